@@ -8,6 +8,7 @@ import (
 
 	"github.com/initializ/forge/forge-core/pipeline"
 	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
 )
 
 // EgressStage resolves egress configuration and generates allowlist artifacts.
@@ -31,34 +32,7 @@ func (s *EgressStage) Execute(ctx context.Context, bc *pipeline.BuildContext) er
 		}
 	}
 
-	// Merge auth + MCP + OTel collector domains into the explicit
-	// allowlist BEFORE resolving. Without this an OIDC issuer, MCP
-	// server URL, or OTLP collector configured in forge.yaml would be
-	// silently blocked at runtime — spans would accumulate in the
-	// BatchSpanProcessor queue and drop on shutdown timeout, leaving
-	// the operator with an inexplicably empty trace backend. Phase 6
-	// of OTel Tracing v1 (#107 / #108) closes the loop: "tracing on in
-	// forge.yaml" implies "tracing reaches the backend."
-	allowed := append([]string{}, cfg.AllowedDomains...)
-	allowed = append(allowed, security.AuthDomains(bc.Config.Auth)...)
-	allowed = append(allowed, security.MCPDomains(bc.Config.MCP)...)
-	allowed = append(allowed, security.OTelDomain(bc.Config.Observability.Tracing)...)
-	// Issue #139 — auto-merge LLM provider base URLs declared on
-	// model.base_url (and on each fallback). Without this an agent
-	// configured against an OpenAI-compatible provider (Together.ai,
-	// OpenRouter, Groq, ...) ships a NetworkPolicy that blocks the
-	// provider's hostname, and the deployed agent's LLM calls 401 /
-	// time out depending on which side notices first.
-	allowed = append(allowed, security.LLMProviderDomains(bc.Config)...)
-
-	// Pass allowed_private_cidrs and allowed_tcp through so `forge build`
-	// validates the strings at build time (same fail-loud posture as
-	// `forge run`). The build artifacts themselves (`GenerateAllowlistJSON`,
-	// `GenerateK8sNetworkPolicy`) don't consume either list — runtime
-	// enforcement re-resolves from raw config — but a typo should fail the
-	// build, not sail through and trip only on first `forge run`.
-	// (#348 review nit 1, extended to allowed_tcp for #337.)
-	resolved, err := security.Resolve(cfg.Profile, cfg.Mode, allowed, toolNames, cfg.Capabilities, cfg.AllowedPrivateCIDRs, cfg.AllowedTCP)
+	resolved, err := ResolveEgress(bc.Config, toolNames)
 	if err != nil {
 		return fmt.Errorf("resolving egress: %w", err)
 	}
@@ -90,3 +64,41 @@ func (s *EgressStage) Execute(ctx context.Context, bc *pipeline.BuildContext) er
 	bc.AddFile("compiled/egress_allowlist.json", outPath)
 	return nil
 }
+
+// ResolveEgress resolves cfg's egress block for an agent with the given
+// tools, as the deployed agent will: the declared allowlist plus the
+// auth, MCP, OTel collector and LLM provider hosts it must reach.
+// `forge deploy k8s` shares it to derive NetworkPolicy rules.
+func ResolveEgress(cfg *types.ForgeConfig, toolNames []string) (*security.EgressConfig, error) {
+	egress := cfg.Egress
+
+	// Merge auth + MCP + OTel collector domains into the explicit
+	// allowlist BEFORE resolving. Without this an OIDC issuer, MCP
+	// server URL, or OTLP collector configured in forge.yaml would be
+	// silently blocked at runtime — spans would accumulate in the
+	// BatchSpanProcessor queue and drop on shutdown timeout, leaving
+	// the operator with an inexplicably empty trace backend. Phase 6
+	// of OTel Tracing v1 (#107 / #108) closes the loop: "tracing on in
+	// forge.yaml" implies "tracing reaches the backend."
+	allowed := append([]string{}, egress.AllowedDomains...)
+	allowed = append(allowed, security.AuthDomains(cfg.Auth)...)
+	allowed = append(allowed, security.MCPDomains(cfg.MCP)...)
+	allowed = append(allowed, security.OTelDomain(cfg.Observability.Tracing)...)
+	allowed = append(allowed, security.LLMTraceDomains(cfg.Observability)...)
+	// Issue #139 — auto-merge LLM provider base URLs declared on
+	// model.base_url (and on each fallback). Without this an agent
+	// configured against an OpenAI-compatible provider (Together.ai,
+	// OpenRouter, Groq, ...) ships a NetworkPolicy that blocks the
+	// provider's hostname, and the deployed agent's LLM calls 401 /
+	// time out depending on which side notices first.
+	allowed = append(allowed, security.LLMProviderDomains(cfg)...)
+
+	// Pass allowed_private_cidrs and allowed_tcp through so `forge build`
+	// validates the strings at build time (same fail-loud posture as
+	// `forge run`). egress_allowlist.json doesn't consume either list —
+	// runtime enforcement re-resolves from raw config — but a typo should
+	// fail the build, not sail through and trip only on first `forge run`.
+	// `GenerateK8sNetworkPolicy` turns both into ipBlock / port rules.
+	// (#348 review nit 1, extended to allowed_tcp for #337.)
+	return security.Resolve(egress.Profile, egress.Mode, allowed, toolNames, egress.Capabilities, egress.AllowedPrivateCIDRs, egress.AllowedTCP)
+}
@@ -26,8 +26,36 @@ func (s *K8sStage) Execute(ctx context.Context, bc *pipeline.BuildContext) error
 	}
 
 	data := compiler.BuildTemplateDataFromContext(bc.Spec, bc)
+	egressCfg, _ := bc.EgressResolved.(*security.EgressConfig)
 
-	manifests := []struct {
+	manifests, err := RenderK8sManifests(data, egressCfg)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range manifests {
+		outPath := filepath.Join(k8sDir, m.Name)
+		if err := os.WriteFile(outPath, m.Data, 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", m.Name, err)
+		}
+		bc.AddFile(filepath.Join("k8s", m.Name), outPath)
+	}
+
+	return nil
+}
+
+// K8sManifest is one rendered Kubernetes manifest file.
+type K8sManifest struct {
+	Name string // file name, e.g. "deployment.yaml"
+	Data []byte
+}
+
+// RenderK8sManifests renders the agent's Deployment, Service,
+// NetworkPolicy and Secret. With egressCfg set the NetworkPolicy mirrors
+// the resolved egress allowlist; otherwise it falls back to the template
+// (deny all egress). `forge build` and `forge deploy k8s` share it.
+func RenderK8sManifests(data *compiler.TemplateSpecData, egressCfg *security.EgressConfig) ([]K8sManifest, error) {
+	files := []struct {
 		tmplFile string
 		outFile  string
 		optional bool
@@ -38,52 +66,41 @@ func (s *K8sStage) Execute(ctx context.Context, bc *pipeline.BuildContext) error
 		{"secrets.yaml.tmpl", "secrets.yaml", true},
 	}
 
-	for _, m := range manifests {
+	var out []K8sManifest
+	for _, f := range files {
 		// Special handling for network-policy: use egress resolver if available
-		if m.outFile == "network-policy.yaml" && bc.EgressResolved != nil {
-			egressCfg, ok := bc.EgressResolved.(*security.EgressConfig)
-			if ok {
-				policyData, err := security.GenerateK8sNetworkPolicy(bc.Spec.AgentID, egressCfg)
-				if err != nil {
-					return fmt.Errorf("generating network policy from egress config: %w", err)
-				}
-				outPath := filepath.Join(k8sDir, m.outFile)
-				if err := os.WriteFile(outPath, policyData, 0644); err != nil {
-					return fmt.Errorf("writing %s: %w", m.outFile, err)
-				}
-				bc.AddFile(filepath.Join("k8s", m.outFile), outPath)
-				continue
+		if f.outFile == "network-policy.yaml" && egressCfg != nil {
+			policyData, err := security.GenerateK8sNetworkPolicy(data.AgentID, egressCfg)
+			if err != nil {
+				return nil, fmt.Errorf("generating network policy from egress config: %w", err)
 			}
+			out = append(out, K8sManifest{Name: f.outFile, Data: policyData})
+			continue
 		}
 
-		tmplData, err := templates.FS.ReadFile(m.tmplFile)
+		tmplData, err := templates.FS.ReadFile(f.tmplFile)
 		if err != nil {
-			if m.optional {
+			if f.optional {
 				continue
 			}
-			return fmt.Errorf("reading template %s: %w", m.tmplFile, err)
+			return nil, fmt.Errorf("reading template %s: %w", f.tmplFile, err)
 		}
 
-		tmpl, err := template.New(m.tmplFile).Parse(string(tmplData))
+		tmpl, err := template.New(f.tmplFile).Parse(string(tmplData))
 		if err != nil {
-			return fmt.Errorf("parsing template %s: %w", m.tmplFile, err)
+			return nil, fmt.Errorf("parsing template %s: %w", f.tmplFile, err)
 		}
 
 		var buf bytes.Buffer
 		if err := tmpl.Execute(&buf, data); err != nil {
-			if m.optional {
+			if f.optional {
 				continue
 			}
-			return fmt.Errorf("rendering %s: %w", m.tmplFile, err)
-		}
-
-		outPath := filepath.Join(k8sDir, m.outFile)
-		if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
-			return fmt.Errorf("writing %s: %w", m.outFile, err)
+			return nil, fmt.Errorf("rendering %s: %w", f.tmplFile, err)
 		}
 
-		bc.AddFile(filepath.Join("k8s", m.outFile), outPath)
+		out = append(out, K8sManifest{Name: f.outFile, Data: buf.Bytes()})
 	}
 
-	return nil
+	return out, nil
 }
@@ -8,7 +8,9 @@ import (
 	"testing"
 
 	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/compiler"
 	"github.com/initializ/forge/forge-core/pipeline"
+	"github.com/initializ/forge/forge-core/security"
 )
 
 func TestK8sStage_Execute(t *testing.T) {
@@ -84,3 +86,80 @@ func TestK8sStage_Execute(t *testing.T) {
 		t.Error("k8s/service.yaml not recorded in GeneratedFiles")
 	}
 }
+
+func TestRenderK8sManifests_DeploySettings(t *testing.T) {
+	spec := &agentspec.AgentSpec{
+		AgentID: "test-agent",
+		Version: "0.1.0",
+		Runtime: &agentspec.RuntimeConfig{
+			Image:      "ubuntu:24.04",
+			Entrypoint: []string{"forge", "run", "--host", "0.0.0.0", "--allow-remote"},
+			Port:       8080,
+		},
+		Requirements: &agentspec.AgentRequirements{
+			EnvRequired: []string{"OPENAI_API_KEY"},
+			EnvOptional: []string{"TAVILY_API_KEY"},
+		},
+	}
+	data := compiler.BuildTemplateDataFromSpec(spec)
+	data.RequiredEnvVars = spec.Requirements.EnvRequired
+	data.OptionalEnvVars = spec.Requirements.EnvOptional
+	data.Image = "ghcr.io/acme/test-agent:0.1.0"
+	data.Replicas = 3
+	data.ShutdownTimeout = "30s"
+	data.TerminationGracePeriodSeconds = 35
+	data.SecretValues = map[string]string{"OPENAI_API_KEY": "sk-\"x\"\n"}
+
+	egressCfg, err := security.Resolve("strict", "allowlist", []string{"api.openai.com"}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifests, err := RenderK8sManifests(data, egressCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, m := range manifests {
+		got[m.Name] = string(m.Data)
+	}
+
+	dep := got["deployment.yaml"]
+	for _, want := range []string{
+		"replicas: 3",
+		"image: ghcr.io/acme/test-agent:0.1.0",
+		`args: ["--shutdown-timeout", "30s"]`,
+		"terminationGracePeriodSeconds: 35",
+		"path: /health",
+		"path: /healthz",
+		"livenessProbe:\n            tcpSocket:",
+	} {
+		if !strings.Contains(dep, want) {
+			t.Errorf("deployment.yaml missing %q:\n%s", want, dep)
+		}
+	}
+	if sec := got["secrets.yaml"]; !strings.Contains(sec, `OPENAI_API_KEY: "sk-\"x\"\n"`) || !strings.Contains(sec, `TAVILY_API_KEY: ""`) {
+		t.Errorf("secrets.yaml values not rendered:\n%s", sec)
+	}
+	if np := got["network-policy.yaml"]; !strings.Contains(np, "api.openai.com") {
+		t.Errorf("network-policy.yaml not generated from egress config:\n%s", np)
+	}
+}
+
+func TestRenderK8sManifests_WrapperProbes(t *testing.T) {
+	data := compiler.BuildTemplateDataFromSpec(&agentspec.AgentSpec{
+		AgentID: "test-agent",
+		Runtime: &agentspec.RuntimeConfig{Entrypoint: []string{"python", "agent.py"}, Port: 8080},
+	})
+	manifests, err := RenderK8sManifests(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dep := string(manifests[0].Data)
+	// Wrappers serve neither /health nor --shutdown-timeout.
+	if strings.Contains(dep, "/health") || strings.Contains(dep, "args:") || strings.Contains(dep, "terminationGracePeriodSeconds") {
+		t.Errorf("wrapper deployment.yaml has forge-run-only settings:\n%s", dep)
+	}
+	if !strings.Contains(dep, "readinessProbe:\n            tcpSocket:") {
+		t.Errorf("wrapper deployment.yaml missing TCP readiness probe:\n%s", dep)
+	}
+}
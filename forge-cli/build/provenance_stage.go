@@ -0,0 +1,210 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	clitools "github.com/initializ/forge/forge-cli/tools"
+	"github.com/initializ/forge/forge-core/pipeline"
+)
+
+// ProvenanceFile is the build-output path of the provenance attestation.
+const ProvenanceFile = "provenance.json"
+
+// ProvenanceBuildType identifies forge builds in SLSA provenance.
+const ProvenanceBuildType = "https://github.com/initializ/forge/build/v1"
+
+// ProvenanceStage writes an in-toto Statement carrying SLSA v1
+// provenance: every build artifact (SBOM and build manifest included)
+// as a subject with its SHA-256, the project sources it was built from
+// as resolved dependencies, and the forge version as the builder. It
+// runs just before SigningStage, so checksums.json — and its signature —
+// cover the attestation too.
+type ProvenanceStage struct {
+	// StartedOn is when the build began; zero omits it.
+	StartedOn time.Time
+}
+
+func (s *ProvenanceStage) Name() string { return "generate-provenance" }
+
+// Statement is an in-toto v1 Statement with a SLSA v1 provenance
+// predicate. The runtime decodes the same shape to verify subjects.
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Provenance           `json:"predicate"`
+}
+
+// ResourceDescriptor names an artifact or input by path and digest.
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// Provenance is the SLSA v1 provenance predicate.
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   map[string]any       `json:"externalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+type RunDetails struct {
+	Builder  ProvenanceBuilder  `json:"builder"`
+	Metadata ProvenanceMetadata `json:"metadata"`
+}
+
+type ProvenanceBuilder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+type ProvenanceMetadata struct {
+	StartedOn  string `json:"startedOn,omitempty"`
+	FinishedOn string `json:"finishedOn"`
+}
+
+func (s *ProvenanceStage) Execute(ctx context.Context, bc *pipeline.BuildContext) error {
+	rels := make([]string, 0, len(bc.GeneratedFiles))
+	for rel := range bc.GeneratedFiles {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	st := Statement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       make([]ResourceDescriptor, 0, len(rels)),
+		PredicateType: "https://slsa.dev/provenance/v1",
+	}
+	for _, rel := range rels {
+		sum, err := fileSHA256(bc.GeneratedFiles[rel])
+		if err != nil {
+			return fmt.Errorf("hashing %s: %w", rel, err)
+		}
+		st.Subject = append(st.Subject, ResourceDescriptor{Name: filepath.ToSlash(rel), Digest: map[string]string{"sha256": sum}})
+	}
+
+	params := map[string]any{
+		"agent_id": bc.Spec.AgentID,
+		"version":  bc.Spec.Version,
+	}
+	if bc.DevMode {
+		params["dev"] = true
+	}
+	if bc.ProdMode {
+		params["prod"] = true
+	}
+	if bc.PreferAlpine {
+		params["alpine"] = true
+	}
+	if bc.PreferSlim {
+		params["slim"] = true
+	}
+
+	deps, err := provenanceSources(bc)
+	if err != nil {
+		return err
+	}
+	if bc.Spec.Runtime != nil && bc.Spec.Runtime.Image != "" {
+		deps = append(deps, ResourceDescriptor{URI: imagePURL(bc.Spec.Runtime.Image)})
+	}
+
+	meta := ProvenanceMetadata{FinishedOn: time.Now().UTC().Format(time.RFC3339)}
+	if !s.StartedOn.IsZero() {
+		meta.StartedOn = s.StartedOn.UTC().Format(time.RFC3339)
+	}
+	st.Predicate = Provenance{
+		BuildDefinition: BuildDefinition{
+			BuildType:            ProvenanceBuildType,
+			ExternalParameters:   params,
+			ResolvedDependencies: deps,
+		},
+		RunDetails: RunDetails{
+			Builder: ProvenanceBuilder{
+				ID:      "https://github.com/initializ/forge/forge-cli",
+				Version: map[string]string{"forge": bc.ForgeCLIVersion},
+			},
+			Metadata: meta,
+		},
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling provenance: %w", err)
+	}
+	outPath := filepath.Join(bc.Opts.OutputDir, ProvenanceFile)
+	if err := os.WriteFile(outPath, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", ProvenanceFile, err)
+	}
+	bc.AddFile(ProvenanceFile, outPath)
+	return nil
+}
+
+// provenanceSources digests the project files the build read: forge.yaml,
+// guardrails.json, channel configs, skills/ and the custom tools.
+func provenanceSources(bc *pipeline.BuildContext) ([]ResourceDescriptor, error) {
+	workDir := bc.Opts.WorkDir
+	var rels []string
+	names := []string{"forge.yaml", "guardrails.json", "SKILL.md"}
+	if bc.Config != nil {
+		if bc.Config.Skills.Path != "" && !filepath.IsAbs(bc.Config.Skills.Path) {
+			names = append(names, bc.Config.Skills.Path)
+		}
+		for _, ch := range bc.Config.Channels {
+			names = append(names, ch+"-config.yaml")
+		}
+	}
+	for _, name := range names {
+		if info, err := os.Stat(filepath.Join(workDir, name)); err == nil && info.Mode().IsRegular() {
+			rels = append(rels, name)
+		}
+	}
+
+	skillsDir := filepath.Join(workDir, "skills")
+	if info, err := os.Stat(skillsDir); err == nil && info.IsDir() {
+		err := filepath.WalkDir(skillsDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(workDir, path)
+			if err == nil {
+				rels = append(rels, rel)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing skills: %w", err)
+		}
+	}
+	for _, dt := range clitools.DiscoverTools(filepath.Join(workDir, "tools")) {
+		rels = append(rels, filepath.Join("tools", dt.Entrypoint))
+	}
+
+	sort.Strings(rels)
+	seen := map[string]bool{}
+	var deps []ResourceDescriptor
+	for _, rel := range rels {
+		rel = filepath.ToSlash(filepath.Clean(rel))
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		sum, err := fileSHA256(filepath.Join(workDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, fmt.Errorf("hashing %s: %w", rel, err)
+		}
+		deps = append(deps, ResourceDescriptor{URI: "file:" + rel, Digest: map[string]string{"sha256": sum}})
+	}
+	return deps, nil
+}
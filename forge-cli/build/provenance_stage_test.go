@@ -0,0 +1,81 @@
+package build
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/pipeline"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestProvenanceStage(t *testing.T) {
+	workDir := t.TempDir()
+	outDir := t.TempDir()
+
+	forgeYAML := []byte("agent_id: prov-agent\n")
+	_ = os.WriteFile(filepath.Join(workDir, "forge.yaml"), forgeYAML, 0644)
+	_ = os.MkdirAll(filepath.Join(workDir, "skills", "github"), 0755)
+	_ = os.WriteFile(filepath.Join(workDir, "skills", "github", "SKILL.md"), []byte("# github"), 0644)
+
+	agentJSON := []byte(`{"agent_id":"prov-agent"}`)
+	_ = os.WriteFile(filepath.Join(outDir, "agent.json"), agentJSON, 0644)
+
+	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{WorkDir: workDir, OutputDir: outDir})
+	bc.Config = &types.ForgeConfig{}
+	bc.Spec = &agentspec.AgentSpec{AgentID: "prov-agent", Version: "0.1.0"}
+	bc.ForgeCLIVersion = "0.18.0"
+	bc.ProdMode = true
+	bc.AddFile("agent.json", filepath.Join(outDir, "agent.json"))
+
+	stage := &ProvenanceStage{StartedOn: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+	if err := stage.Execute(context.Background(), bc); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if bc.GeneratedFiles[ProvenanceFile] == "" {
+		t.Fatal("provenance not registered as a generated file")
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, ProvenanceFile))
+	if err != nil {
+		t.Fatalf("reading provenance: %v", err)
+	}
+	var st Statement
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatalf("parsing provenance: %v", err)
+	}
+	if st.Type != "https://in-toto.io/Statement/v1" || st.PredicateType != "https://slsa.dev/provenance/v1" {
+		t.Errorf("type = %s, predicateType = %s", st.Type, st.PredicateType)
+	}
+
+	sum := func(b []byte) string { h := sha256.Sum256(b); return hex.EncodeToString(h[:]) }
+	if len(st.Subject) != 1 || st.Subject[0].Name != "agent.json" || st.Subject[0].Digest["sha256"] != sum(agentJSON) {
+		t.Errorf("subjects = %+v", st.Subject)
+	}
+
+	deps := map[string]string{}
+	for _, d := range st.Predicate.BuildDefinition.ResolvedDependencies {
+		deps[d.URI] = d.Digest["sha256"]
+	}
+	if deps["file:forge.yaml"] != sum(forgeYAML) {
+		t.Errorf("forge.yaml dependency = %q", deps["file:forge.yaml"])
+	}
+	if _, ok := deps["file:skills/github/SKILL.md"]; !ok {
+		t.Errorf("skill file missing from dependencies: %v", deps)
+	}
+
+	params := st.Predicate.BuildDefinition.ExternalParameters
+	if params["agent_id"] != "prov-agent" || params["prod"] != true {
+		t.Errorf("external parameters = %v", params)
+	}
+	rd := st.Predicate.RunDetails
+	if rd.Builder.Version["forge"] != "0.18.0" || rd.Metadata.StartedOn != "2026-10-01T12:00:00Z" {
+		t.Errorf("run details = %+v", rd)
+	}
+}
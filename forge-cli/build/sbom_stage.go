@@ -0,0 +1,282 @@
+package build
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	clitools "github.com/initializ/forge/forge-cli/tools"
+	"github.com/initializ/forge/forge-core/packaging"
+	"github.com/initializ/forge/forge-core/pipeline"
+	"github.com/initializ/forge/forge-skills/contract"
+)
+
+// SBOMFile is the build-output path of the CycloneDX SBOM.
+const SBOMFile = "sbom.cdx.json"
+
+// SBOMStage writes a CycloneDX 1.5 SBOM for the agent bundle: the base
+// image, the forge runtime, every file under skills/ (SKILL.md and
+// scripts), the custom tools in tools/, and the binaries skills declare.
+// It runs before ManifestStage so the SBOM is listed, checksummed and
+// named as a provenance subject like any other artifact.
+type SBOMStage struct{}
+
+func (s *SBOMStage) Name() string { return "generate-sbom" }
+
+// CycloneDX document subset forge emits.
+type cdxBOM struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type               string        `json:"type"`
+	BOMRef             string        `json:"bom-ref,omitempty"`
+	Name               string        `json:"name"`
+	Version            string        `json:"version,omitempty"`
+	PURL               string        `json:"purl,omitempty"`
+	Hashes             []cdxHash     `json:"hashes,omitempty"`
+	ExternalReferences []cdxExtRef   `json:"externalReferences,omitempty"`
+	Properties         []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxExtRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (s *SBOMStage) Execute(ctx context.Context, bc *pipeline.BuildContext) error {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: cdxTools{Components: []cdxComponent{
+				{Type: "application", Name: "forge", Version: bc.ForgeCLIVersion},
+			}},
+			Component: cdxComponent{
+				Type:    "application",
+				BOMRef:  "agent:" + bc.Spec.AgentID,
+				Name:    bc.Spec.AgentID,
+				Version: bc.Spec.Version,
+			},
+		},
+		Components: []cdxComponent{},
+	}
+
+	if bc.Spec.Runtime != nil && bc.Spec.Runtime.Image != "" {
+		bom.Components = append(bom.Components, cdxComponent{
+			Type:   "container",
+			BOMRef: "image:" + bc.Spec.Runtime.Image,
+			Name:   bc.Spec.Runtime.Image,
+			PURL:   imagePURL(bc.Spec.Runtime.Image),
+		})
+	}
+	if bc.Config != nil && bc.Config.Framework == "forge" {
+		bom.Components = append(bom.Components, cdxComponent{
+			Type:    "application",
+			BOMRef:  "forge-runtime",
+			Name:    "forge",
+			Version: bc.ForgeCLIVersion,
+			PURL:    "pkg:github/initializ/forge@" + bc.ForgeCLIVersion,
+		})
+	}
+
+	skillFiles, err := sbomSkillFiles(bc.Opts.OutputDir)
+	if err != nil {
+		return fmt.Errorf("listing skill files: %w", err)
+	}
+	bom.Components = append(bom.Components, skillFiles...)
+
+	for _, dt := range clitools.DiscoverTools(filepath.Join(bc.Opts.WorkDir, "tools")) {
+		rel := filepath.ToSlash(filepath.Join("tools", dt.Entrypoint))
+		c := cdxComponent{
+			Type:   "file",
+			BOMRef: "file:" + rel,
+			Name:   rel,
+			Properties: []cdxProperty{
+				{Name: "forge:tool", Value: dt.Name},
+				{Name: "forge:language", Value: dt.Language},
+			},
+		}
+		if sum, err := fileSHA256(filepath.Join(bc.Opts.WorkDir, "tools", dt.Entrypoint)); err == nil {
+			c.Hashes = []cdxHash{{Alg: "SHA-256", Content: sum}}
+		}
+		bom.Components = append(bom.Components, c)
+	}
+
+	if manifest, ok := bc.BinManifest.(*packaging.BinManifest); ok && manifest != nil {
+		for _, req := range manifest.Requirements {
+			bom.Components = append(bom.Components, binComponent(bc, manifest, req))
+		}
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling SBOM: %w", err)
+	}
+	outPath := filepath.Join(bc.Opts.OutputDir, SBOMFile)
+	if err := os.WriteFile(outPath, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", SBOMFile, err)
+	}
+	bc.AddFile(SBOMFile, outPath)
+	return nil
+}
+
+// sbomSkillFiles returns a file component for every file under the
+// build output's skills/ directory, tagged with the skill it belongs to.
+func sbomSkillFiles(outputDir string) ([]cdxComponent, error) {
+	root := filepath.Join(outputDir, "skills")
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+	var out []cdxComponent
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return err
+		}
+		c := cdxComponent{
+			Type:   "file",
+			BOMRef: "file:" + rel,
+			Name:   rel,
+			Hashes: []cdxHash{{Alg: "SHA-256", Content: sum}},
+		}
+		// skills/<name>/... belongs to skill <name>.
+		if parts := strings.SplitN(rel, "/", 3); len(parts) == 3 {
+			c.Properties = []cdxProperty{{Name: "forge:skill", Value: parts[1]}}
+		}
+		out = append(out, c)
+		return nil
+	})
+	return out, err
+}
+
+// binComponent describes one binary a skill (or a local override)
+// brings into the image, with a package URL when it comes from apt/apk.
+func binComponent(bc *pipeline.BuildContext, manifest *packaging.BinManifest, req contract.BinRequirement) cdxComponent {
+	name, version := req.Name, req.Version
+	apt, apk, directURL := req.AptPackage, req.ApkPackage, req.DirectURL
+	c := cdxComponent{Type: "application", BOMRef: "bin:" + name, Name: name, Version: version}
+	if bc.Config != nil {
+		if o, ok := bc.Config.Package.BinOverrides[name]; ok {
+			if o.AptPackage != "" {
+				apt = o.AptPackage
+			}
+			if o.ApkPackage != "" {
+				apk = o.ApkPackage
+			}
+			if o.DirectURL != "" {
+				directURL = o.DirectURL
+			}
+		}
+	}
+	source := ""
+	switch {
+	case bc.PreferAlpine && apk != "":
+		source = "apk"
+		c.PURL = "pkg:apk/alpine/" + apk
+	case apt != "":
+		source = "apt"
+		c.PURL = "pkg:deb/debian/" + apt
+	case apk != "":
+		source = "apk"
+		c.PURL = "pkg:apk/alpine/" + apk
+	case directURL != "":
+		source = "url"
+		c.ExternalReferences = []cdxExtRef{{Type: "distribution", URL: directURL}}
+	}
+	if c.PURL != "" && version != "" {
+		c.PURL += "@" + version
+	}
+	if sum, err := fileSHA256(filepath.Join(bc.Opts.OutputDir, ".local-bins", name)); err == nil {
+		source = "local"
+		c.Hashes = []cdxHash{{Alg: "SHA-256", Content: sum}}
+	}
+	if source != "" {
+		c.Properties = append(c.Properties, cdxProperty{Name: "forge:source", Value: source})
+	}
+	if origin := manifest.SkillOrigin[name]; origin != "" {
+		c.Properties = append(c.Properties, cdxProperty{Name: "forge:skill", Value: origin})
+	}
+	sort.Slice(c.Properties, func(i, j int) bool { return c.Properties[i].Name < c.Properties[j].Name })
+	return c
+}
+
+// imagePURL returns the pkg:docker package URL for an image reference.
+func imagePURL(image string) string {
+	name, tag := image, ""
+	if i := strings.LastIndex(image, ":"); i > 0 && !strings.Contains(image[i+1:], "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+	purl := "pkg:docker/" + name
+	if tag != "" {
+		purl += "@" + tag
+	}
+	return purl
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
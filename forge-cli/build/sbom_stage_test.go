@@ -0,0 +1,100 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/packaging"
+	"github.com/initializ/forge/forge-core/pipeline"
+	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-skills/contract"
+)
+
+func TestSBOMStage(t *testing.T) {
+	workDir := t.TempDir()
+	outDir := t.TempDir()
+
+	_ = os.MkdirAll(filepath.Join(outDir, "skills", "github", "scripts"), 0755)
+	_ = os.WriteFile(filepath.Join(outDir, "skills", "github", "SKILL.md"), []byte("# github"), 0644)
+	_ = os.WriteFile(filepath.Join(outDir, "skills", "github", "scripts", "pr.sh"), []byte("#!/bin/sh\n"), 0755)
+	_ = os.MkdirAll(filepath.Join(workDir, "tools"), 0755)
+	_ = os.WriteFile(filepath.Join(workDir, "tools", "tool_lookup.py"), []byte("print(1)\n"), 0644)
+
+	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{WorkDir: workDir, OutputDir: outDir})
+	bc.Config = &types.ForgeConfig{Framework: "forge"}
+	bc.Spec = &agentspec.AgentSpec{
+		AgentID: "sbom-agent",
+		Version: "1.2.0",
+		Runtime: &agentspec.RuntimeConfig{Image: "python:3.12-slim"},
+	}
+	bc.ForgeCLIVersion = "0.18.0"
+	bc.BinManifest = &packaging.BinManifest{
+		Requirements: []contract.BinRequirement{{Name: "gh", AptPackage: "gh", Version: "2.40.0"}},
+		SkillOrigin:  map[string]string{"gh": "github"},
+	}
+
+	if err := (&SBOMStage{}).Execute(context.Background(), bc); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if bc.GeneratedFiles[SBOMFile] == "" {
+		t.Fatal("SBOM not registered as a generated file")
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, SBOMFile))
+	if err != nil {
+		t.Fatalf("reading SBOM: %v", err)
+	}
+	var bom cdxBOM
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatalf("parsing SBOM: %v", err)
+	}
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.5" {
+		t.Errorf("format = %s %s", bom.BOMFormat, bom.SpecVersion)
+	}
+	if bom.Metadata.Component.Name != "sbom-agent" || bom.Metadata.Component.Version != "1.2.0" {
+		t.Errorf("metadata component = %+v", bom.Metadata.Component)
+	}
+
+	byRef := map[string]cdxComponent{}
+	for _, c := range bom.Components {
+		byRef[c.BOMRef] = c
+	}
+	if c := byRef["image:python:3.12-slim"]; c.PURL != "pkg:docker/python@3.12-slim" {
+		t.Errorf("image purl = %q", c.PURL)
+	}
+	if c := byRef["forge-runtime"]; c.Version != "0.18.0" {
+		t.Errorf("forge runtime = %+v", c)
+	}
+	script := byRef["file:skills/github/scripts/pr.sh"]
+	if len(script.Hashes) != 1 || len(script.Properties) != 1 || script.Properties[0].Value != "github" {
+		t.Errorf("skill script = %+v", script)
+	}
+	if _, ok := byRef["file:tools/tool_lookup.py"]; !ok {
+		t.Error("custom tool missing")
+	}
+	gh := byRef["bin:gh"]
+	if gh.PURL != "pkg:deb/debian/gh@2.40.0" {
+		t.Errorf("gh purl = %q", gh.PURL)
+	}
+	if len(gh.Properties) != 2 || gh.Properties[0] != (cdxProperty{Name: "forge:skill", Value: "github"}) {
+		t.Errorf("gh properties = %+v", gh.Properties)
+	}
+}
+
+func TestImagePURL(t *testing.T) {
+	tests := map[string]string{
+		"alpine":                        "pkg:docker/alpine",
+		"python:3.12":                   "pkg:docker/python@3.12",
+		"registry.local:5000/team/app":  "pkg:docker/registry.local:5000/team/app",
+		"registry.local:5000/app:1.0.0": "pkg:docker/registry.local:5000/app@1.0.0",
+	}
+	for in, want := range tests {
+		if got := imagePURL(in); got != want {
+			t.Errorf("imagePURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -96,12 +96,15 @@ func (s *ScheduleManifestStage) Execute(_ context.Context, bc *pipeline.BuildCon
 			Schedule: scheduler.Schedule{
 				ID:            sc.ID,
 				Cron:          sc.Cron,
+				Timezone:      sc.Timezone,
 				Task:          sc.Task,
 				Skill:         sc.Skill,
 				Channel:       sc.Channel,
 				ChannelTarget: sc.ChannelTarget,
 				Source:        scheduler.SourceYAML,
 				Enabled:       true,
+				MaxRuntime:    sc.MaxRuntime,
+				Overlap:       sc.Overlap,
 			},
 		})
 		name := "cronjob-" + safeFileName(sc.ID) + ".yaml"
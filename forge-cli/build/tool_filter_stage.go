@@ -22,6 +22,8 @@ var knownBuiltinTools = map[string]bool{
 	"code_interpreter": true,
 	"text_generation":  true,
 	"cli_execute":      true,
+	"model_switch":     true,
+	"compact_now":      true,
 }
 
 // Known adapter tools.
@@ -0,0 +1,155 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// attachmentScanTimeout bounds one run of the scan command.
+const attachmentScanTimeout = time.Minute
+
+// AttachmentScanner checks a downloaded attachment before the agent sees
+// it. An error rejects the attachment.
+type AttachmentScanner func(ctx context.Context, a channels.Attachment) error
+
+// CommandScanner scans attachments by running argv with the file on
+// stdin, the way clamdscan - and most scanners' stdin modes work. Exit
+// status 0 passes the file; any other status, or a scanner that can't be
+// run, rejects it.
+func CommandScanner(argv []string) AttachmentScanner {
+	return func(ctx context.Context, a channels.Attachment) error {
+		ctx, cancel := context.WithTimeout(ctx, attachmentScanTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		cmd.Stdin = bytes.NewReader(a.Data)
+		out, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &exitErr):
+			return fmt.Errorf("scan rejected the file: %s", strings.TrimSpace(string(out)))
+		default:
+			return fmt.Errorf("running the scanner: %w", err)
+		}
+	}
+}
+
+// ApplyAttachmentConfig installs the forge.yaml attachments middleware
+// (see Attachments), when attachments are enabled. Attachments are
+// fetched through whichever of plugins received them. Call it after
+// ApplyMiddlewareConfig so only admitted messages' files are downloaded.
+func (r *Router) ApplyAttachmentConfig(cfg types.AttachmentsConfig, plugins ...channels.ChannelPlugin) {
+	if !cfg.Enabled {
+		return
+	}
+	fetchers := map[string]channels.AttachmentFetcher{}
+	for _, p := range plugins {
+		if f, ok := p.(channels.AttachmentFetcher); ok {
+			fetchers[p.Name()] = f
+		}
+	}
+	var scan AttachmentScanner
+	if len(cfg.ScanCommand) > 0 {
+		scan = CommandScanner(cfg.ScanCommand)
+	}
+	r.Use(Attachments(cfg, fetchers, scan, r.warn))
+}
+
+// Attachments downloads the files and images of an event through the
+// adapter's fetcher, keeping those cfg admits and scan (when set)
+// passes; the router forwards them to the agent as file parts. Each
+// attachment turned away is dropped and noted in the message text, so
+// the agent can tell the user. Audio is left to the voice middleware.
+func Attachments(cfg types.AttachmentsConfig, fetchers map[string]channels.AttachmentFetcher, scan AttachmentScanner, warn func(string, map[string]any)) channels.Middleware {
+	maxBytes := cfg.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = types.DefaultAttachmentMaxBytes
+	}
+	return func(next channels.EventHandler) channels.EventHandler {
+		return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+			var kept []channels.Attachment
+			var notes []string
+			for _, a := range event.Attachments {
+				if a.IsAudio() || len(a.Data) > 0 {
+					kept = append(kept, a)
+					continue
+				}
+				data, rej, err := fetchAttachment(ctx, a, fetchers[event.Channel], cfg.AllowedTypes, maxBytes, scan)
+				if rej != nil {
+					fields := map[string]any{"channel": event.Channel, "user_id": event.UserID, "name": a.Name, "reason": english(rej.key, rej.args...)}
+					if err != nil {
+						fields["error"] = err.Error()
+					}
+					warn("channel attachment rejected", fields)
+					notes = append(notes, localize(ctx, "channel.attachment.rejected", a.Name, localize(ctx, rej.key, rej.args...)))
+					continue
+				}
+				a.Data = data
+				kept = append(kept, a)
+			}
+			event.Attachments = kept
+			if len(notes) > 0 {
+				event.Message = strings.TrimSpace(event.Message + "\n\n" + strings.Join(notes, "\n"))
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// rejection is why an attachment was turned away: a message key, worded
+// for the user, and its arguments.
+type rejection struct {
+	key  string
+	args []any
+}
+
+// fetchAttachment downloads and checks a. It returns the content, or
+// why the attachment was turned away, with the error behind it for the
+// log.
+func fetchAttachment(ctx context.Context, a channels.Attachment, fetcher channels.AttachmentFetcher, allowed []string, maxBytes int64, scan AttachmentScanner) ([]byte, *rejection, error) {
+	switch {
+	case fetcher == nil:
+		return nil, &rejection{key: "channel.attachment.unsupported"}, nil
+	case !mimeAllowed(a.MimeType, allowed):
+		return nil, &rejection{key: "channel.attachment.type", args: []any{a.MimeType}}, nil
+	case a.Size > maxBytes:
+		return nil, &rejection{key: "channel.attachment.too_large", args: []any{maxBytes}}, nil
+	}
+	data, err := fetcher.FetchAttachment(ctx, a, maxBytes)
+	if err != nil {
+		return nil, &rejection{key: "channel.attachment.download"}, err
+	}
+	if scan != nil {
+		a.Data = data
+		if err := scan(ctx, a); err != nil {
+			return nil, &rejection{key: "channel.attachment.scan"}, err
+		}
+	}
+	return data, nil, nil
+}
+
+// mimeAllowed reports whether mimeType matches an allowed entry, exactly
+// or by a type/* family. An empty list allows every type.
+func mimeAllowed(mimeType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	family, _, _ := strings.Cut(mimeType, "/")
+	for _, a := range allowed {
+		if strings.EqualFold(a, mimeType) || strings.EqualFold(a, family+"/*") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+type fakeFetcher map[string]string // attachment ID → content
+
+func (f fakeFetcher) FetchAttachment(_ context.Context, a channels.Attachment, maxBytes int64) ([]byte, error) {
+	data, ok := f[a.ID]
+	if !ok {
+		return nil, errors.New("no such file")
+	}
+	return []byte(data), nil
+}
+
+func TestAttachmentsMiddleware(t *testing.T) {
+	fetchers := map[string]channels.AttachmentFetcher{"slack": fakeFetcher{"F1": "%PDF", "F2": "MZ", "F3": "png"}}
+	scan := func(_ context.Context, a channels.Attachment) error {
+		if string(a.Data) == "MZ" {
+			return errors.New("Win.Trojan FOUND")
+		}
+		return nil
+	}
+	cfg := types.AttachmentsConfig{Enabled: true, MaxBytes: 100, AllowedTypes: []string{"application/pdf", "image/*"}}
+	var forwarded *channels.ChannelEvent
+	h := channels.Chain(func(_ context.Context, e *channels.ChannelEvent) (*a2a.Message, error) {
+		forwarded = e
+		return &a2a.Message{Role: a2a.MessageRoleAgent}, nil
+	}, Attachments(cfg, fetchers, scan, noWarn))
+
+	_, err := h(context.Background(), &channels.ChannelEvent{Channel: "slack", Message: "see attached", Attachments: []channels.Attachment{
+		{Name: "q3.pdf", MimeType: "application/pdf", ID: "F1"},
+		{Name: "setup.pdf", MimeType: "application/pdf", ID: "F2"},
+		{Name: "chart.png", MimeType: "image/png", ID: "F3", Size: 1000},
+		{Name: "notes.zip", MimeType: "application/zip", ID: "F4"},
+		{Name: "voice.ogg", MimeType: "audio/ogg", Data: []byte("OggS")},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forwarded.Attachments) != 2 || string(forwarded.Attachments[0].Data) != "%PDF" || !forwarded.Attachments[1].IsAudio() {
+		t.Errorf("attachments = %+v, want the PDF and the untouched audio", forwarded.Attachments)
+	}
+	for _, want := range []string{
+		"see attached\n\n",
+		"[attachment setup.pdf not accepted: the file did not pass the virus scan]",
+		"[attachment chart.png not accepted: the file is larger than 100 bytes]",
+		"[attachment notes.zip not accepted: files of type application/zip are not accepted]",
+	} {
+		if !strings.Contains(forwarded.Message, want) {
+			t.Errorf("message = %q, want it to contain %q", forwarded.Message, want)
+		}
+	}
+
+	// An adapter that can't fetch files has its attachments noted too.
+	_, _ = h(context.Background(), &channels.ChannelEvent{Channel: "msteams", Attachments: []channels.Attachment{{Name: "a.pdf", MimeType: "application/pdf"}}})
+	if len(forwarded.Attachments) != 0 || !strings.Contains(forwarded.Message, "can't pass files") {
+		t.Errorf("msteams event = %+v", forwarded)
+	}
+}
+
+func TestCommandScanner(t *testing.T) {
+	a := channels.Attachment{Name: "x", Data: []byte("EICAR")}
+	if err := CommandScanner([]string{"sh", "-c", "cat >/dev/null"})(context.Background(), a); err != nil {
+		t.Errorf("clean file rejected: %v", err)
+	}
+	if err := CommandScanner([]string{"sh", "-c", "grep -q EICAR && echo FOUND && exit 1; exit 0"})(context.Background(), a); err == nil || !strings.Contains(err.Error(), "FOUND") {
+		t.Errorf("infected file: err = %v", err)
+	}
+	if err := CommandScanner([]string{"/nonexistent/scanner"})(context.Background(), a); err == nil {
+		t.Error("a scanner that can't run must reject the file")
+	}
+}
+
+func TestRouter_ForwardsAttachmentsAsFileParts(t *testing.T) {
+	var parts []a2a.Part
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var params a2a.SendTaskParams
+		_ = json.Unmarshal(req.Params, &params)
+		parts = params.Message.Parts
+		task := a2a.Task{ID: params.ID, Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}}
+		_ = json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task))
+	}))
+	defer srv.Close()
+
+	_, err := NewRouter(srv.URL, "").forwardToA2A(context.Background(), &channels.ChannelEvent{
+		Channel: "slack", WorkspaceID: "C1", Message: "what is this?",
+		Attachments: []channels.Attachment{
+			{Name: "chart.png", MimeType: "image/png", Data: []byte("png")},
+			{Name: "voice.ogg", MimeType: "audio/ogg", Data: []byte("OggS")},
+			{Name: "unfetched.pdf", MimeType: "application/pdf"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 || parts[1].Kind != a2a.PartKindFile || parts[1].File.Name != "chart.png" || string(parts[1].File.Bytes) != "png" {
+		t.Errorf("parts = %+v, want the text and the image", parts)
+	}
+}
@@ -0,0 +1,76 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// fallbackText is the message key of what a channel user is told when
+// their message failed, by error code. The cause stays in the agent's
+// log and audit trail; only a guardrail's reason, which operators write
+// for end users, is passed through.
+var fallbackText = map[a2a.ErrorCode]string{
+	a2a.ErrorGuardrailViolation: "channel.error.guardrail",
+	a2a.ErrorToolError:          "channel.error.tool",
+	a2a.ErrorLLMRateLimited:     "channel.error.rate_limited",
+	a2a.ErrorLLMUnavailable:     "channel.error.llm_unavailable",
+	a2a.ErrorBudgetExceeded:     "channel.error.budget",
+	a2a.ErrorLoopDetected:       "channel.error.loop",
+	a2a.ErrorOutputInvalid:      "channel.error.output_invalid",
+	a2a.ErrorAuthFailed:         "channel.error.auth",
+	a2a.ErrorInternal:           "channel.error.internal",
+}
+
+// fallbackMessage builds the channel reply for a failed message: the
+// code's sentence, a retry hint when the failure is retryable, and the
+// correlation ID so a support request can be matched to the audit
+// trail. te rides in the message metadata so the adapter can still tell
+// the reply reports a failure.
+func fallbackMessage(ctx context.Context, te *a2a.TaskError, correlationID string) *a2a.Message {
+	key, ok := fallbackText[te.Code]
+	if !ok {
+		key = fallbackText[a2a.ErrorInternal]
+	}
+	text := localize(ctx, key)
+	if te.Code == a2a.ErrorGuardrailViolation && te.Message != "" {
+		text += " " + te.Message
+	}
+	switch {
+	case te.RetryAfterSeconds > 0:
+		text += " " + localize(ctx, "channel.retry_after", time.Duration(te.RetryAfterSeconds)*time.Second)
+	case te.Retryable:
+		text += " " + localize(ctx, "channel.retry")
+	}
+	if correlationID != "" {
+		text += " " + localize(ctx, "channel.ref", correlationID)
+	}
+	return &a2a.Message{
+		Role:     a2a.MessageRoleAgent,
+		Parts:    []a2a.Part{a2a.NewTextPart(text)},
+		Metadata: map[string]any{a2a.MetadataKeyError: te},
+	}
+}
+
+// decodeErrorBody reads a classified REST error body — the shape the
+// auth, admission and task endpoints write — or returns nil.
+func decodeErrorBody(r io.Reader) *a2a.TaskError {
+	var body struct {
+		Error             string        `json:"error"`
+		Code              a2a.ErrorCode `json:"code"`
+		Retryable         bool          `json:"retryable"`
+		RetryAfterSeconds int           `json:"retry_after_seconds"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r, 64<<10)).Decode(&body); err != nil || body.Code == "" {
+		return nil
+	}
+	return &a2a.TaskError{
+		Code:              body.Code,
+		Message:           body.Error,
+		Retryable:         body.Retryable,
+		RetryAfterSeconds: body.RetryAfterSeconds,
+	}
+}
@@ -0,0 +1,70 @@
+package channels
+
+import (
+	"context"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
+)
+
+// SetLocalizer makes the router write its own replies — failures, policy
+// refusals, attachment notes — from l's catalogs, in the language of the
+// message each answers. Without it they are in English.
+func (r *Router) SetLocalizer(l *i18n.Localizer) {
+	r.localizer = l
+}
+
+type localeKey struct{}
+
+// locale is the language of the event being handled. It is detected on
+// first use, so a voice message is judged by its transcript.
+type locale struct {
+	l     *i18n.Localizer
+	event *channels.ChannelEvent
+	lang  string
+}
+
+func (lc *locale) language() string {
+	if lc.lang == "" {
+		lc.lang = lc.l.Language(lc.event.Message)
+	}
+	return lc.lang
+}
+
+// localized puts the locale of each event on the context next handles
+// it with.
+func (r *Router) localized(next channels.EventHandler) channels.EventHandler {
+	return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+		return next(r.withLocale(ctx, event), event)
+	}
+}
+
+func (r *Router) withLocale(ctx context.Context, event *channels.ChannelEvent) context.Context {
+	return context.WithValue(ctx, localeKey{}, &locale{l: r.localizer, event: event})
+}
+
+// localize renders message key in the language of the event ctx is
+// handling; in English outside one.
+func localize(ctx context.Context, key string, args ...any) string {
+	lc, _ := ctx.Value(localeKey{}).(*locale)
+	if lc == nil {
+		return english(key, args...)
+	}
+	return lc.l.Text(lc.language(), key, args...)
+}
+
+// english renders message key in English, for logs.
+func english(key string, args ...any) string {
+	return (*i18n.Localizer)(nil).Text(i18n.SourceLanguage, key, args...)
+}
+
+// eventLanguage is the language of the event ctx is handling, passed
+// to the agent; "" when the router has no localizer.
+func eventLanguage(ctx context.Context) string {
+	lc, _ := ctx.Value(localeKey{}).(*locale)
+	if lc == nil || lc.l == nil {
+		return ""
+	}
+	return lc.language()
+}
@@ -0,0 +1,231 @@
+package channels
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Channel middleware defaults; see types.ChannelMiddlewareConfig.
+const (
+	DefaultDedupeWindow    = 10 * time.Minute
+	DefaultMaxMessageBytes = 64 << 10
+)
+
+// middlewarePruneInterval is how often the dedupe and rate-limit state
+// drops entries nobody needs any more.
+const middlewarePruneInterval = time.Minute
+
+// MiddlewareFromConfig builds the forge.yaml channel_middleware chain, in
+// the order events meet it: duplicate detection, sender filter,
+// per-sender rate limit, message size cap. warn receives the ops signal
+// for each refused event.
+func MiddlewareFromConfig(cfg types.ChannelMiddlewareConfig, warn func(msg string, fields map[string]any)) []channels.Middleware {
+	window := cfg.DedupeWindow
+	if window == 0 {
+		window = DefaultDedupeWindow
+	}
+	maxBytes := cfg.MaxMessageBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxMessageBytes
+	}
+	mws := []channels.Middleware{Dedupe(window)}
+	if len(cfg.AllowUsers) > 0 || len(cfg.DenyUsers) > 0 {
+		mws = append(mws, SenderFilter(cfg.AllowUsers, cfg.DenyUsers, warn))
+	}
+	if cfg.RateLimit.PerMinute > 0 {
+		mws = append(mws, RateLimit(cfg.RateLimit.PerMinute, cfg.RateLimit.Burst, warn))
+	}
+	return append(mws, MaxMessageBytes(maxBytes, warn))
+}
+
+// eventKey identifies a platform update across redeliveries: the
+// adapter's event ID, else the message ID. Empty when the event has
+// neither.
+func eventKey(event *channels.ChannelEvent) string {
+	switch {
+	case event.EventID != "":
+		return event.Channel + "/event/" + event.EventID
+	case event.MessageID != "":
+		return event.Channel + "/" + event.WorkspaceID + "/" + event.MessageID
+	}
+	return ""
+}
+
+// Dedupe answers a redelivered update — one whose key was seen within
+// window — with channels.ErrDuplicateEvent. Events without a key pass.
+func Dedupe(window time.Duration) channels.Middleware {
+	var (
+		mu        sync.Mutex
+		seen      = map[string]time.Time{}
+		lastPrune time.Time
+	)
+	return func(next channels.EventHandler) channels.EventHandler {
+		return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+			key := eventKey(event)
+			if key == "" {
+				return next(ctx, event)
+			}
+			now := time.Now()
+			mu.Lock()
+			if now.Sub(lastPrune) > middlewarePruneInterval {
+				for k, at := range seen {
+					if now.Sub(at) >= window {
+						delete(seen, k)
+					}
+				}
+				lastPrune = now
+			}
+			at, dup := seen[key]
+			dup = dup && now.Sub(at) < window
+			if !dup {
+				seen[key] = now
+			}
+			mu.Unlock()
+			if dup {
+				return nil, channels.ErrDuplicateEvent
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// senderMatches reports whether an allow_users / deny_users entry names
+// the event's sender.
+func senderMatches(entry string, event *channels.ChannelEvent) bool {
+	adapter, user, ok := strings.Cut(entry, ":")
+	if !ok {
+		return event.UserEmail != "" && strings.EqualFold(entry, event.UserEmail)
+	}
+	return adapter == event.Channel && (user == "*" || user == event.UserID)
+}
+
+// SenderFilter drops events from senders on deny, and — when allow is
+// set — from senders not on it, with channels.ErrEventDropped. Denial
+// wins over an allow entry.
+func SenderFilter(allow, deny []string, warn func(string, map[string]any)) channels.Middleware {
+	listed := func(entries []string, event *channels.ChannelEvent) bool {
+		for _, e := range entries {
+			if senderMatches(e, event) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(next channels.EventHandler) channels.EventHandler {
+		return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+			reason := ""
+			switch {
+			case listed(deny, event):
+				reason = "sender denied"
+			case len(allow) > 0 && !listed(allow, event):
+				reason = "sender not allowed"
+			}
+			if reason != "" {
+				warn("channel message dropped: "+reason, map[string]any{
+					"channel": event.Channel, "user_id": event.UserID,
+				})
+				return nil, channels.ErrEventDropped
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// RateLimit caps each sender at perMinute messages a minute, with bursts
+// of up to burst (default perMinute). A sender over the limit gets a
+// reply saying when to try again; the event does not reach the agent.
+func RateLimit(perMinute, burst int, warn func(string, map[string]any)) channels.Middleware {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	type sender struct {
+		limiter  *rate.Limiter
+		lastSeen time.Time
+	}
+	var (
+		mu        sync.Mutex
+		senders   = map[string]*sender{}
+		lastPrune time.Time
+	)
+	// A limiter left idle this long is full again and can be dropped.
+	idle := time.Duration(float64(burst)/float64(perMinute)*float64(time.Minute)) + time.Minute
+
+	return func(next channels.EventHandler) channels.EventHandler {
+		return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+			now := time.Now()
+			key := event.Channel + "/" + event.UserID
+			mu.Lock()
+			if now.Sub(lastPrune) > middlewarePruneInterval {
+				for k, s := range senders {
+					if now.Sub(s.lastSeen) > idle {
+						delete(senders, k)
+					}
+				}
+				lastPrune = now
+			}
+			s, ok := senders[key]
+			if !ok {
+				s = &sender{limiter: rate.NewLimiter(rate.Limit(float64(perMinute)/60), burst)}
+				senders[key] = s
+			}
+			s.lastSeen = now
+			res := s.limiter.ReserveN(now, 1)
+			wait := res.DelayFrom(now)
+			if wait > 0 {
+				res.CancelAt(now)
+			}
+			mu.Unlock()
+
+			if wait > 0 {
+				retryAfter := int(math.Ceil(wait.Seconds()))
+				warn("channel message rate limited", map[string]any{
+					"channel": event.Channel, "user_id": event.UserID, "retry_after_seconds": retryAfter,
+				})
+				return policyReply(
+					localize(ctx, "channel.rate_limited", time.Duration(retryAfter)*time.Second),
+					&a2a.TaskError{Code: a2a.ErrorBudgetExceeded, Message: "channel rate limit exceeded", Retryable: true, RetryAfterSeconds: retryAfter},
+				), nil
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// MaxMessageBytes refuses messages whose text exceeds limit bytes with a
+// reply asking the sender to shorten it.
+func MaxMessageBytes(limit int, warn func(string, map[string]any)) channels.Middleware {
+	return func(next channels.EventHandler) channels.EventHandler {
+		return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+			if len(event.Message) > limit {
+				warn("channel message over size cap", map[string]any{
+					"channel": event.Channel, "user_id": event.UserID, "bytes": len(event.Message), "limit": limit,
+				})
+				return policyReply(
+					localize(ctx, "channel.too_long", max(limit>>10, 1)),
+					a2a.NewTaskError(a2a.ErrorGuardrailViolation, "channel message exceeds size cap"),
+				), nil
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// policyReply is the reply to an event a policy refused. te rides in
+// the metadata, as on fallback replies, so the adapter can tell the
+// reply reports a failure.
+func policyReply(text string, te *a2a.TaskError) *a2a.Message {
+	return &a2a.Message{
+		Role:     a2a.MessageRoleAgent,
+		Parts:    []a2a.Part{a2a.NewTextPart(text)},
+		Metadata: map[string]any{a2a.MetadataKeyError: te},
+	}
+}
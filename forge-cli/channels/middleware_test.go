@@ -0,0 +1,150 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// countingHandler stands in for the router: it counts the events that
+// get through and answers each with "ok".
+func countingHandler(n *atomic.Int32) channels.EventHandler {
+	return func(context.Context, *channels.ChannelEvent) (*a2a.Message, error) {
+		n.Add(1)
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("ok")}}, nil
+	}
+}
+
+func noWarn(string, map[string]any) {}
+
+func TestDedupeMiddleware(t *testing.T) {
+	var n atomic.Int32
+	h := channels.Chain(countingHandler(&n), Dedupe(time.Minute))
+	ctx := context.Background()
+
+	ev := &channels.ChannelEvent{Channel: "slack", WorkspaceID: "C1", MessageID: "1.0", EventID: "Ev1", Message: "hi"}
+	if _, err := h(ctx, ev); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	redelivery := *ev
+	if _, err := h(ctx, &redelivery); !errors.Is(err, channels.ErrDuplicateEvent) {
+		t.Errorf("redelivery err = %v, want ErrDuplicateEvent", err)
+	}
+	// Same message ID on another adapter, and events without IDs, pass.
+	for _, e := range []*channels.ChannelEvent{
+		{Channel: "telegram", WorkspaceID: "C1", MessageID: "1.0"},
+		{Channel: "slack", Message: "no ids"},
+		{Channel: "slack", Message: "no ids"},
+	} {
+		if _, err := h(ctx, e); err != nil {
+			t.Errorf("%+v: %v", e, err)
+		}
+	}
+	if n.Load() != 4 {
+		t.Errorf("forwarded %d events, want 4", n.Load())
+	}
+}
+
+func TestSenderFilterMiddleware(t *testing.T) {
+	var n atomic.Int32
+	var warned []string
+	h := channels.Chain(countingHandler(&n), SenderFilter(
+		[]string{"slack:U1", "telegram:*", "ops@example.com"},
+		[]string{"telegram:666"},
+		func(msg string, _ map[string]any) { warned = append(warned, msg) },
+	))
+	for _, tc := range []struct {
+		event   channels.ChannelEvent
+		dropped bool
+	}{
+		{channels.ChannelEvent{Channel: "slack", UserID: "U1"}, false},
+		{channels.ChannelEvent{Channel: "slack", UserID: "U2"}, true},
+		{channels.ChannelEvent{Channel: "slack", UserID: "U3", UserEmail: "Ops@Example.com"}, false},
+		{channels.ChannelEvent{Channel: "telegram", UserID: "42"}, false},
+		{channels.ChannelEvent{Channel: "telegram", UserID: "666"}, true},
+		{channels.ChannelEvent{Channel: "msteams", UserID: "U1"}, true},
+	} {
+		_, err := h(context.Background(), &tc.event)
+		if got := errors.Is(err, channels.ErrEventDropped); got != tc.dropped {
+			t.Errorf("%s/%s: dropped = %v, want %v", tc.event.Channel, tc.event.UserID, got, tc.dropped)
+		}
+	}
+	if len(warned) != 3 || warned[1] != "channel message dropped: sender denied" {
+		t.Errorf("warnings = %q", warned)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	var n atomic.Int32
+	h := channels.Chain(countingHandler(&n), RateLimit(6, 2, noWarn))
+	ctx := context.Background()
+	alice := &channels.ChannelEvent{Channel: "slack", UserID: "alice"}
+
+	for i := 0; i < 2; i++ {
+		if resp, _ := h(ctx, alice); a2a.TaskErrorFromMessage(resp) != nil {
+			t.Fatalf("message %d within the burst was limited", i+1)
+		}
+	}
+	resp, err := h(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	te := a2a.TaskErrorFromMessage(resp)
+	if te == nil || !te.Retryable || te.RetryAfterSeconds < 1 || te.RetryAfterSeconds > 10 {
+		t.Fatalf("over-limit reply error = %+v", te)
+	}
+	if !strings.Contains(resp.Parts[0].Text, "try again in") {
+		t.Errorf("reply = %q", resp.Parts[0].Text)
+	}
+	// Limits are per sender.
+	if resp, _ := h(ctx, &channels.ChannelEvent{Channel: "slack", UserID: "bob"}); a2a.TaskErrorFromMessage(resp) != nil {
+		t.Error("another sender was limited")
+	}
+	if n.Load() != 3 {
+		t.Errorf("forwarded %d events, want 3", n.Load())
+	}
+}
+
+func TestMaxMessageBytesMiddleware(t *testing.T) {
+	var n atomic.Int32
+	h := channels.Chain(countingHandler(&n), MaxMessageBytes(2048, noWarn))
+	resp, err := h(context.Background(), &channels.ChannelEvent{Channel: "slack", Message: strings.Repeat("x", 2049)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te := a2a.TaskErrorFromMessage(resp); te == nil || te.Code != a2a.ErrorGuardrailViolation {
+		t.Errorf("oversized reply error = %+v", te)
+	}
+	if !strings.Contains(resp.Parts[0].Text, "limit 2 KiB") {
+		t.Errorf("reply = %q", resp.Parts[0].Text)
+	}
+	if _, err := h(context.Background(), &channels.ChannelEvent{Channel: "slack", Message: strings.Repeat("x", 2048)}); err != nil || n.Load() != 1 {
+		t.Errorf("message at the cap: err = %v, forwarded = %d", err, n.Load())
+	}
+}
+
+func TestMiddlewareFromConfig(t *testing.T) {
+	if got := len(MiddlewareFromConfig(types.ChannelMiddlewareConfig{}, noWarn)); got != 2 {
+		t.Errorf("default chain has %d middlewares, want dedupe and size cap", got)
+	}
+	full := types.ChannelMiddlewareConfig{RateLimit: types.ChannelRateLimitYAML{PerMinute: 1}, DenyUsers: []string{"slack:U9"}}
+	if got := len(MiddlewareFromConfig(full, noWarn)); got != 4 {
+		t.Errorf("configured chain has %d middlewares, want 4", got)
+	}
+}
+
+func TestRouter_UseMiddleware(t *testing.T) {
+	r := NewRouter("http://127.0.0.1:0", "")
+	r.Use(SenderFilter(nil, []string{"slack:U9"}, noWarn))
+	_, err := r.Handler()(context.Background(), &channels.ChannelEvent{Channel: "slack", UserID: "U9", Message: "hi"})
+	if !channels.IsDropped(err) {
+		t.Errorf("err = %v, want the event dropped before forwarding", err)
+	}
+}
@@ -0,0 +1,213 @@
+package channels
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// DefaultQueueMaxAge bounds how long the queue remembers a message. An
+// undelivered message older than this is dropped on replay (a reply
+// that late would confuse more than help), and a delivered message is
+// no longer deduplicated against platform retries.
+const DefaultQueueMaxAge = time.Hour
+
+const (
+	queueStatePending = "pending"
+	queueStateDone    = "done"
+)
+
+// Queue is a small on-disk buffer of inbound channel events. The router
+// records each event before forwarding it and marks it done once the
+// agent has answered, so an event received while the agent is
+// restarting survives the restart and is replayed afterward. The same
+// records deduplicate platform retries (Slack re-delivers unacknowledged
+// events, Telegram re-sends webhooks) against messages already accepted.
+//
+// One JSON file per message keeps writes atomic without a database;
+// the queue only ever holds the backlog of a restart window.
+type Queue struct {
+	dir    string
+	maxAge time.Duration
+
+	mu        sync.Mutex
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// queueEntry is the on-disk record for one message. Done entries drop
+// the event body and remain only as dedupe tombstones.
+type queueEntry struct {
+	Key        string                 `json:"key"`
+	State      string                 `json:"state"`
+	ReceivedAt time.Time              `json:"received_at"`
+	Event      *channels.ChannelEvent `json:"event,omitempty"`
+}
+
+// OpenQueue opens (creating if needed) the queue stored in dir.
+func OpenQueue(dir string) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating channel queue dir: %w", err)
+	}
+	return &Queue{dir: dir, maxAge: DefaultQueueMaxAge, now: time.Now}, nil
+}
+
+// queueKey identifies a platform message. Events without a message ID
+// cannot be told apart from their retries and are not queued.
+func queueKey(event *channels.ChannelEvent) string {
+	if event.MessageID == "" {
+		return ""
+	}
+	return event.Channel + "/" + event.WorkspaceID + "/" + event.MessageID
+}
+
+func (q *Queue) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(q.dir, hex.EncodeToString(sum[:12])+".json")
+}
+
+// Enqueue records event as pending. It returns channels.ErrDuplicateEvent
+// when the same message is already pending or was answered within the
+// queue's max age. Events without a message ID are accepted unrecorded.
+func (q *Queue) Enqueue(event *channels.ChannelEvent) error {
+	key := queueKey(event)
+	if key == "" {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	if now.Sub(q.lastPrune) > time.Minute {
+		q.pruneLocked(now)
+		q.lastPrune = now
+	}
+	if existing, err := q.readLocked(q.path(key)); err == nil && existing.Key == key && now.Sub(existing.ReceivedAt) < q.maxAge {
+		return channels.ErrDuplicateEvent
+	}
+	return q.writeLocked(&queueEntry{Key: key, State: queueStatePending, ReceivedAt: now, Event: event})
+}
+
+// Done marks event answered. The record stays behind as a tombstone so
+// retries of the message keep being recognised until it ages out.
+func (q *Queue) Done(event *channels.ChannelEvent) error {
+	key := queueKey(event)
+	if key == "" {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, err := q.readLocked(q.path(key))
+	if err != nil || entry.Key != key {
+		entry = &queueEntry{Key: key, ReceivedAt: q.now()}
+	}
+	entry.State = queueStateDone
+	entry.Event = nil
+	return q.writeLocked(entry)
+}
+
+// Pending returns channel's undelivered events, oldest first. Events
+// past the queue's max age are discarded rather than returned.
+func (q *Queue) Pending(channel string) ([]*channels.ChannelEvent, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	q.pruneLocked(now)
+	q.lastPrune = now
+
+	entries, err := q.entriesLocked()
+	if err != nil {
+		return nil, err
+	}
+	var pending []*queueEntry
+	for _, e := range entries {
+		if e.State == queueStatePending && e.Event != nil && e.Event.Channel == channel {
+			pending = append(pending, e)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ReceivedAt.Before(pending[j].ReceivedAt) })
+
+	events := make([]*channels.ChannelEvent, len(pending))
+	for i, e := range pending {
+		events[i] = e.Event
+	}
+	return events, nil
+}
+
+// pruneLocked removes every record older than maxAge, pending or not.
+func (q *Queue) pruneLocked(now time.Time) {
+	entries, err := q.entriesLocked()
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if now.Sub(e.ReceivedAt) >= q.maxAge {
+			_ = os.Remove(q.path(e.Key))
+		}
+	}
+}
+
+func (q *Queue) entriesLocked() ([]*queueEntry, error) {
+	files, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading channel queue: %w", err)
+	}
+	var entries []*queueEntry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		e, err := q.readLocked(filepath.Join(q.dir, f.Name()))
+		if err != nil {
+			continue // torn or foreign file; ignore
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (q *Queue) readLocked(path string) (*queueEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var e queueEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if e.Key == "" {
+		return nil, errors.New("queue entry has no key")
+	}
+	return &e, nil
+}
+
+// writeLocked writes e atomically (temp file + rename) so a crash
+// mid-write never leaves a half-written record.
+func (q *Queue) writeLocked(e *queueEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshalling queue entry: %w", err)
+	}
+	path := q.path(e.Key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing queue entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return fmt.Errorf("renaming queue entry: %w", err)
+	}
+	return nil
+}
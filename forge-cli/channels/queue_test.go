@@ -0,0 +1,187 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+func TestQueue_DedupeAndPersist(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenQueue(dir)
+	if err != nil {
+		t.Fatalf("OpenQueue: %v", err)
+	}
+	ev := &channels.ChannelEvent{Channel: "slack", WorkspaceID: "C1", MessageID: "171.1", Message: "hi"}
+
+	if err := q.Enqueue(ev); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := q.Enqueue(ev); !errors.Is(err, channels.ErrDuplicateEvent) {
+		t.Errorf("retry of pending message: err = %v, want ErrDuplicateEvent", err)
+	}
+
+	// A fresh process sees the pending message.
+	q2, _ := OpenQueue(dir)
+	pending, err := q2.Pending("slack")
+	if err != nil || len(pending) != 1 || pending[0].Message != "hi" {
+		t.Fatalf("Pending = %v, %v", pending, err)
+	}
+	if other, _ := q2.Pending("telegram"); len(other) != 0 {
+		t.Errorf("Pending(telegram) = %v", other)
+	}
+
+	// Done keeps a tombstone that still deduplicates.
+	if err := q2.Done(ev); err != nil {
+		t.Fatalf("Done: %v", err)
+	}
+	if pending, _ := q2.Pending("slack"); len(pending) != 0 {
+		t.Errorf("Pending after Done = %v", pending)
+	}
+	if err := q2.Enqueue(ev); !errors.Is(err, channels.ErrDuplicateEvent) {
+		t.Errorf("retry of answered message: err = %v, want ErrDuplicateEvent", err)
+	}
+
+	// Past max age the record is gone and the message is new again.
+	q2.now = func() time.Time { return time.Now().Add(2 * DefaultQueueMaxAge) }
+	if err := q2.Enqueue(ev); err != nil {
+		t.Errorf("Enqueue after expiry: %v", err)
+	}
+
+	// Events without a message ID are never deduplicated.
+	anon := &channels.ChannelEvent{Channel: "slack", WorkspaceID: "C1"}
+	if q.Enqueue(anon) != nil || q.Enqueue(anon) != nil {
+		t.Error("event without message ID should not be deduplicated")
+	}
+}
+
+func TestRouter_QueuedRetriesUntilAgentIsBack(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req a2a.JSONRPCRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		task := a2a.Task{Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: &a2a.Message{
+			Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("back")},
+		}}}
+		_ = json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task))
+	}))
+	defer srv.Close()
+
+	q, _ := OpenQueue(t.TempDir())
+	router := NewRouter(srv.URL, "")
+	router.SetQueue(q)
+	router.SetLogger(nopChannelLogger{})
+	router.retryBackoff = time.Millisecond
+
+	ev := &channels.ChannelEvent{Channel: "telegram", WorkspaceID: "42", MessageID: "7", Message: "hi"}
+	msg, err := router.Handler()(context.Background(), ev)
+	if err != nil || msg.Parts[0].Text != "back" {
+		t.Fatalf("handler = %v, %v", msg, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+	if _, err := router.Handler()(context.Background(), ev); !errors.Is(err, channels.ErrDuplicateEvent) {
+		t.Errorf("redelivery: err = %v, want ErrDuplicateEvent", err)
+	}
+}
+
+func TestRouter_QueuedDoesNotRetryMidResponseReset(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// The agent has the message and is working on it when the
+		// connection drops.
+		_, _ = io.Copy(io.Discard, r.Body)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_ = conn.(*net.TCPConn).SetLinger(0)
+		_ = conn.Close()
+	}))
+	defer srv.Close()
+
+	q, _ := OpenQueue(t.TempDir())
+	router := NewRouter(srv.URL, "")
+	router.SetQueue(q)
+	router.SetLogger(nopChannelLogger{})
+	router.retryBackoff = time.Millisecond
+
+	ev := &channels.ChannelEvent{Channel: "slack", WorkspaceID: "C1", MessageID: "2.0", Message: "run once"}
+	_, err := router.Handler()(context.Background(), ev)
+	if err == nil || isAgentUnavailable(err) {
+		t.Fatalf("handler err = %v, want a delivery failure", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1: a reset after sending must not be retried", calls.Load())
+	}
+	if pending, _ := q.Pending("slack"); len(pending) != 0 {
+		t.Errorf("message left pending for replay: %v", pending)
+	}
+}
+
+func TestRouter_ReplayPending(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	dir := t.TempDir()
+	q, _ := OpenQueue(dir)
+	router := NewRouter(down.URL, "")
+	router.SetQueue(q)
+	router.SetLogger(nopChannelLogger{})
+	router.retryBackoff = time.Millisecond
+
+	// The agent is down and the process stops before it comes back.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	ev := &channels.ChannelEvent{Channel: "slack", WorkspaceID: "C1", MessageID: "1.0", Message: "while restarting"}
+	if _, err := router.Handler()(ctx, ev); err == nil {
+		t.Fatal("expected an error while the agent is down")
+	}
+
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, a2a.Task{Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}}))
+	}))
+	defer up.Close()
+
+	q2, _ := OpenQueue(dir)
+	restarted := NewRouter(up.URL, "")
+	restarted.SetQueue(q2)
+	restarted.SetLogger(nopChannelLogger{})
+	var sent []string
+	err := restarted.Replay(context.Background(), "slack", func(e *channels.ChannelEvent, _ *a2a.Message) error {
+		sent = append(sent, e.Message)
+		return nil
+	})
+	if err != nil || len(sent) != 1 || sent[0] != "while restarting" {
+		t.Fatalf("Replay = %v, sent %v", err, sent)
+	}
+	if pending, _ := q2.Pending("slack"); len(pending) != 0 {
+		t.Errorf("still pending after replay: %v", pending)
+	}
+}
+
+type nopChannelLogger struct{}
+
+func (nopChannelLogger) Info(string, map[string]any)  {}
+func (nopChannelLogger) Warn(string, map[string]any)  {}
+func (nopChannelLogger) Error(string, map[string]any) {}
+func (nopChannelLogger) Debug(string, map[string]any) {}
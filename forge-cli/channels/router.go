@@ -4,16 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"os"
+	"strings"
 	"time"
+	"unicode"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
 )
 
 // SyncRequestTimeout is how long the router holds a channel-initiated
@@ -23,11 +31,26 @@ import (
 // the deferral is abandoned). See #314 for lifting this via async delivery.
 const SyncRequestTimeout = 360 * time.Second
 
+// maxRetryBackoff caps the wait between attempts to reach an agent that
+// is restarting.
+const maxRetryBackoff = 30 * time.Second
+
 // Router forwards channel events to an A2A agent server via JSON-RPC over HTTP.
 type Router struct {
 	agentURL    string
 	bearerToken string
 	client      *http.Client
+
+	// queue, when set, buffers events while the agent is unreachable;
+	// see SetQueue.
+	queue *Queue
+	// sessions, when set, maps conversations to agent sessions; see
+	// SetSessions.
+	sessions     *Sessions
+	middleware   []channels.Middleware
+	logger       channels.Logger
+	localizer    *i18n.Localizer
+	retryBackoff time.Duration
 }
 
 // NewRouter creates a Router that forwards events to the A2A server at agentURL.
@@ -39,26 +62,259 @@ func NewRouter(agentURL, bearerToken string) *Router {
 		client: &http.Client{
 			Timeout: SyncRequestTimeout,
 		},
+		retryBackoff: time.Second,
+	}
+}
+
+// SetQueue makes the router record every event in q before forwarding
+// it. While the agent is unreachable (restarting after a config reload,
+// or down until its supervisor brings it back) the handler keeps the
+// event pending and retries instead of failing it; events still pending
+// when this process exits are picked up by Replay on the next start.
+// Platform retries of an accepted message are answered with
+// channels.ErrDuplicateEvent.
+func (r *Router) SetQueue(q *Queue) {
+	r.queue = q
+}
+
+// SetSessions sends each conversation's messages under the session s
+// maps it to, instead of one fixed task ID per conversation, and enables
+// the /new command: a message that starts with "/new" moves the
+// conversation to a fresh session, so the agent no longer sees its
+// earlier messages, and forwards any text after the command as the first
+// message of the new session.
+func (r *Router) SetSessions(s *Sessions) {
+	r.sessions = s
+}
+
+// ProgressSubscriber wraps s so adapters that subscribe to progress by
+// conversation (channels.SessionTaskID) follow the conversation's
+// current session.
+func (r *Router) ProgressSubscriber(s channels.ProgressSubscriber) channels.ProgressSubscriber {
+	if r.sessions == nil {
+		return s
+	}
+	return func(key string, fn func(channels.ProgressUpdate)) func() {
+		return s(r.sessions.Resolve(key), fn)
+	}
+}
+
+// SetLogger routes the router's operational signals (queued, replayed,
+// dropped events) through l instead of stderr.
+func (r *Router) SetLogger(l channels.Logger) {
+	r.logger = l
+}
+
+// Use adds middleware every inbound event passes before it is queued
+// or forwarded, in the order given. Replayed events, already admitted
+// on their first delivery, skip it.
+func (r *Router) Use(mws ...channels.Middleware) {
+	r.middleware = append(r.middleware, mws...)
+}
+
+// ApplyMiddlewareConfig installs the forge.yaml channel_middleware chain (see
+// MiddlewareFromConfig), logging refused events through the router's logger.
+func (r *Router) ApplyMiddlewareConfig(cfg types.ChannelMiddlewareConfig) {
+	r.Use(MiddlewareFromConfig(cfg, r.warn)...)
+}
+
+// ApplyVoiceConfig installs the forge.yaml voice middleware (see
+// VoiceFromConfig), when an STT provider is configured. Call it after
+// ApplyMiddlewareConfig so only admitted messages are transcribed.
+func (r *Router) ApplyVoiceConfig(cfg types.VoiceConfig) error {
+	mw, err := VoiceFromConfig(cfg, r.warn)
+	if err != nil || mw == nil {
+		return err
 	}
+	r.Use(mw)
+	return nil
 }
 
 // Handler returns an EventHandler suitable for passing to ChannelPlugin.Start().
 func (r *Router) Handler() channels.EventHandler {
-	return r.forwardToA2A
+	next := r.handleQueued
+	if r.queue == nil {
+		next = r.forwardToA2A
+	}
+	if r.sessions != nil {
+		next = r.handleNewSession(next)
+	}
+	return r.localized(channels.Chain(next, r.middleware...))
+}
+
+// handleNewSession implements the /new command in front of next.
+func (r *Router) handleNewSession(next channels.EventHandler) channels.EventHandler {
+	return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+		rest, ok := newSessionCommand(event.Message)
+		if !ok {
+			return next(ctx, event)
+		}
+		key := channels.SessionTaskID(event)
+		r.info("channel conversation reset", map[string]any{
+			"channel": event.Channel, "user_id": event.UserID, "session": r.sessions.Reset(key),
+		})
+		if rest == "" && len(event.Attachments) == 0 {
+			return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(localize(ctx, "channel.new_session"))}}, nil
+		}
+		event.Message = rest
+		return next(ctx, event)
+	}
+}
+
+// newSessionCommand reports whether msg is the /new command — also in
+// Telegram's "/new@botname" group form — and returns the text after it.
+func newSessionCommand(msg string) (rest string, ok bool) {
+	msg = strings.TrimSpace(msg)
+	cmd := msg
+	if i := strings.IndexFunc(msg, unicode.IsSpace); i >= 0 {
+		cmd, rest = msg[:i], msg[i:]
+	}
+	if at := strings.IndexByte(cmd, '@'); at > 0 {
+		cmd = cmd[:at]
+	}
+	if cmd != "/new" {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// sessionID returns the A2A task ID event is sent under.
+func (r *Router) sessionID(event *channels.ChannelEvent) string {
+	key := channels.SessionTaskID(event)
+	if r.sessions == nil {
+		return key
+	}
+	return r.sessions.Resolve(key)
+}
+
+// handleQueued is the Handler used when a queue is configured.
+func (r *Router) handleQueued(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+	if err := r.queue.Enqueue(event); err != nil {
+		if errors.Is(err, channels.ErrDuplicateEvent) {
+			return nil, err
+		}
+		// A disk problem must not cost the message: deliver unbuffered.
+		r.warn("channel queue unavailable, forwarding unbuffered", map[string]any{
+			"channel": event.Channel, "error": err.Error(),
+		})
+		return r.forwardToA2A(ctx, event)
+	}
+	resp, err := r.forwardWithRetry(ctx, event)
+	if err != nil && isAgentUnavailable(err) {
+		// Still pending on disk; Replay delivers it after a restart.
+		return nil, err
+	}
+	if doneErr := r.queue.Done(event); doneErr != nil {
+		r.warn("channel queue: marking event done failed", map[string]any{
+			"channel": event.Channel, "error": doneErr.Error(),
+		})
+	}
+	return resp, err
+}
+
+// Replay forwards channel's events left pending by a previous run, oldest
+// first, and hands each answer to send (usually the adapter's
+// SendResponse). It returns when the backlog is drained, or early when
+// ctx ends, leaving the rest pending.
+func (r *Router) Replay(ctx context.Context, channel string, send func(*channels.ChannelEvent, *a2a.Message) error) error {
+	if r.queue == nil {
+		return nil
+	}
+	events, err := r.queue.Pending(channel)
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		r.info("replaying queued channel messages", map[string]any{"channel": channel, "count": len(events)})
+	}
+	for _, event := range events {
+		resp, err := r.forwardWithRetry(r.withLocale(ctx, event), event)
+		if err != nil && isAgentUnavailable(err) {
+			return err
+		}
+		if err == nil {
+			if sendErr := send(event, resp); sendErr != nil {
+				r.warn("replayed message: send response failed", map[string]any{
+					"channel": channel, "error": sendErr.Error(),
+				})
+			}
+		} else {
+			r.warn("replayed message failed", map[string]any{"channel": channel, "error": err.Error()})
+		}
+		if doneErr := r.queue.Done(event); doneErr != nil {
+			return doneErr
+		}
+	}
+	return nil
+}
+
+// forwardWithRetry forwards event, retrying with capped exponential
+// backoff for as long as the agent is unreachable and ctx is live.
+func (r *Router) forwardWithRetry(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+	backoff := r.retryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := r.forwardToA2A(ctx, event)
+		if err == nil || !isAgentUnavailable(err) {
+			return resp, err
+		}
+		if attempt == 0 {
+			r.warn("agent unreachable, message queued until it is back", map[string]any{
+				"channel": event.Channel, "error": err.Error(),
+			})
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+func (r *Router) info(msg string, fields map[string]any) {
+	if r.logger != nil {
+		r.logger.Info(msg, fields)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "channels: %s %v\n", msg, fields)
+}
+
+func (r *Router) warn(msg string, fields map[string]any) {
+	if r.logger != nil {
+		r.logger.Warn(msg, fields)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "channels: %s %v\n", msg, fields)
+}
+
+// agentUnavailableError marks a failure where the A2A server could not
+// take the request at all, so forwarding it again later is safe.
+type agentUnavailableError struct{ err error }
+
+func (e *agentUnavailableError) Error() string { return e.err.Error() }
+func (e *agentUnavailableError) Unwrap() error { return e.err }
+
+func isAgentUnavailable(err error) bool {
+	var ue *agentUnavailableError
+	return errors.As(err, &ue)
+}
+
+// neverSent reports whether a transport error shows the request never
+// reached the agent: the connection could not be dialed, as when the
+// agent is restarting and refuses it. A timeout or a reset once the
+// request is out may come after the agent started on the message, and
+// forwarding it again would run its task, tools and reply twice.
+func neverSent(err error) bool {
+	var oe *net.OpError
+	return errors.As(err, &oe) && oe.Op == "dial"
 }
 
 // forwardToA2A sends a tasks/send JSON-RPC request to the A2A server and
 // extracts the agent's response message from the returned task.
 func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
-	// Build a stable task ID so all messages in the same conversation share
-	// one session. Use thread ID when available (threaded replies), otherwise
-	// fall back to channel + workspace + user for DM-style conversations.
-	var taskID string
-	if event.ThreadID != "" {
-		taskID = fmt.Sprintf("%s-%s-%s", event.Channel, event.WorkspaceID, event.ThreadID)
-	} else {
-		taskID = fmt.Sprintf("%s-%s-%s", event.Channel, event.WorkspaceID, event.UserID)
-	}
+	// A stable task ID so all messages in the same conversation share one
+	// session.
+	taskID := r.sessionID(event)
 
 	// Inject channel context so the LLM knows where this message originated.
 	// This enables schedule_set to automatically capture channel/target for delivery.
@@ -71,6 +327,18 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 			Parts: []a2a.Part{a2a.NewTextPart(contextPrefix + event.Message)},
 		},
 	}
+	if lang := eventLanguage(ctx); lang != "" {
+		params.Message.Metadata = map[string]any{a2a.MetadataKeyLanguage: lang}
+	}
+	// Files the attachments middleware fetched ride along as file parts;
+	// the agent stores them where its tools can read them.
+	for _, a := range event.Attachments {
+		if len(a.Data) > 0 && !a.IsAudio() {
+			params.Message.Parts = append(params.Message.Parts, a2a.NewFilePart(a2a.FileContent{
+				Name: a.Name, MimeType: a.MimeType, Bytes: a.Data,
+			}))
+		}
+	}
 
 	paramsJSON, err := json.Marshal(params)
 	if err != nil {
@@ -108,9 +376,14 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 	if event.UserEmail != "" {
 		httpReq.Header.Set("X-Forge-Channel-Email", event.UserEmail)
 	}
-	if event.Channel != "" {
-		httpReq.Header.Set("X-Forge-Channel", event.Channel)
-	}
+	// Channel name plus the platform's delivery and message IDs, so the
+	// invocation's audit events can be joined back to the Slack event or
+	// Telegram update that triggered it.
+	coreruntime.ChannelContext{
+		Channel:   event.Channel,
+		EventID:   event.EventID,
+		MessageID: event.MessageID,
+	}.ApplyToHTTPHeaders(httpReq.Header)
 	// Inject the W3C traceparent + baggage from the calling context onto
 	// the outbound HTTP request so the A2A server's a2a.tasks/send span
 	// nests under the calling channel.<adapter>.deliver span instead of
@@ -121,7 +394,11 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 
 	resp, err := r.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("sending request to A2A server: %w", err)
+		err = fmt.Errorf("sending request to A2A server: %w", err)
+		if ctx.Err() == nil && neverSent(err) {
+			err = &agentUnavailableError{err: err}
+		}
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -129,7 +406,16 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 	if resp.StatusCode == http.StatusUnauthorized {
 		return nil, fmt.Errorf("A2A server returned 401 Unauthorized (check auth token)")
 	}
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return nil, &agentUnavailableError{err: fmt.Errorf("A2A server returned HTTP %d", resp.StatusCode)}
+	default:
+		// A refusal with a classified error (e.g. the 402 from
+		// admission) still gets the user a reply.
+		if te := decodeErrorBody(resp.Body); te != nil {
+			return fallbackMessage(ctx, te, resp.Header.Get(coreruntime.HeaderForgeCorrelationID)), nil
+		}
 		return nil, fmt.Errorf("A2A server returned HTTP %d", resp.StatusCode)
 	}
 
@@ -143,6 +429,9 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 		return nil, fmt.Errorf("parsing JSON-RPC response: %w", err)
 	}
 
+	if te := a2a.TaskErrorFromJSONRPC(rpcResp.Error); te != nil {
+		return fallbackMessage(ctx, te, resp.Header.Get(coreruntime.HeaderForgeCorrelationID)), nil
+	}
 	if rpcResp.Error != nil {
 		return nil, fmt.Errorf("A2A error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
 	}
@@ -157,13 +446,38 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 	if err := json.Unmarshal(resultJSON, &task); err != nil {
 		return nil, fmt.Errorf("parsing task from result: %w", err)
 	}
+	corrID, _ := task.Metadata["correlation_id"].(string)
+	if corrID == "" {
+		corrID = resp.Header.Get(coreruntime.HeaderForgeCorrelationID)
+	}
+	// Link the platform delivery to the invocation's correlation ID, the
+	// key its audit events and spans carry. Debug-grade, so only when a
+	// logger is wired rather than on the stderr fallback.
+	if r.logger != nil {
+		r.logger.Info("channel event correlated", map[string]any{
+			"channel":        event.Channel,
+			"event_id":       event.EventID,
+			"task_id":        task.ID,
+			"correlation_id": corrID,
+		})
+	}
 
+	if task.Status.State == a2a.TaskStateFailed {
+		if te := a2a.TaskErrorFromTask(&task); te != nil {
+			return fallbackMessage(ctx, te, corrID), nil
+		}
+	}
 	if task.Status.Message != nil {
+		// Forwarded to the human operators holding the conversation;
+		// they reply, not the agent.
+		if held, _ := task.Status.Message.Metadata[a2a.MetadataKeyHandoff].(bool); held {
+			return nil, channels.ErrEventDropped
+		}
 		return task.Status.Message, nil
 	}
 
 	return &a2a.Message{
 		Role:  a2a.MessageRoleAgent,
-		Parts: []a2a.Part{a2a.NewTextPart("(no response)")},
+		Parts: []a2a.Part{a2a.NewTextPart(localize(ctx, "channel.no_response"))},
 	}, nil
 }
@@ -9,6 +9,8 @@ import (
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
+	"github.com/initializ/forge/forge-core/types"
 )
 
 func TestRouter_ForwardToA2A_Success(t *testing.T) {
@@ -34,6 +36,13 @@ func TestRouter_ForwardToA2A_Success(t *testing.T) {
 		if got := r.Header.Get("X-Forge-Channel"); got != "test" {
 			t.Errorf("X-Forge-Channel = %q, want test", got)
 		}
+		// Delivery + message IDs ride along for audit correlation.
+		if got := r.Header.Get("X-Forge-Channel-Event-ID"); got != "Ev1" {
+			t.Errorf("X-Forge-Channel-Event-ID = %q, want Ev1", got)
+		}
+		if got := r.Header.Get("X-Forge-Channel-Message-ID"); got != "M1" {
+			t.Errorf("X-Forge-Channel-Message-ID = %q, want M1", got)
+		}
 
 		var params a2a.SendTaskParams
 		if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -68,6 +77,8 @@ func TestRouter_ForwardToA2A_Success(t *testing.T) {
 		WorkspaceID: "W123",
 		UserID:      "U456",
 		UserEmail:   "mk@example.com",
+		MessageID:   "M1",
+		EventID:     "Ev1",
 		Message:     "hello agent",
 	}
 
@@ -143,6 +154,110 @@ func TestRouter_ForwardToA2A_NoMessage(t *testing.T) {
 	}
 }
 
+func TestRouter_ForwardToA2A_FailedTaskFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+
+		task := a2a.Task{
+			ID: "t1",
+			Status: a2a.TaskStatus{
+				State: a2a.TaskStateFailed,
+				Message: &a2a.Message{
+					Role:  a2a.MessageRoleAgent,
+					Parts: []a2a.Part{a2a.NewTextPart("openai error (status 429): slow down")},
+				},
+			},
+			Metadata: map[string]any{
+				"correlation_id":     "corr-1",
+				a2a.MetadataKeyError: a2a.NewTaskError(a2a.ErrorLLMRateLimited, "something went wrong"),
+			},
+		}
+		json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	msg, err := NewRouter(srv.URL, "").forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "hi"})
+	if err != nil {
+		t.Fatalf("forwardToA2A() error: %v", err)
+	}
+	want := "I'm receiving too many requests right now. Please try again in a moment. (ref: corr-1)"
+	if got := msg.Parts[0].Text; got != want {
+		t.Errorf("reply = %q, want %q", got, want)
+	}
+}
+
+func TestRouter_ForwardToA2A_AdmissionDeniedFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"error":"admission_denied","code":"budget_exceeded","retryable":true,"retry_after_seconds":120}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	msg, err := NewRouter(srv.URL, "").forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "hi"})
+	if err != nil {
+		t.Fatalf("forwardToA2A() error: %v", err)
+	}
+	if got, want := msg.Parts[0].Text, "I've reached my usage limit. Please try again in 2m0s."; got != want {
+		t.Errorf("reply = %q, want %q", got, want)
+	}
+}
+
+func TestRouter_LocalizedFallback(t *testing.T) {
+	var gotLang any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		var params a2a.SendTaskParams
+		json.Unmarshal(req.Params, &params) //nolint:errcheck
+		gotLang = params.Message.Metadata[a2a.MetadataKeyLanguage]
+		task := a2a.Task{ID: "t1", Status: a2a.TaskStatus{State: a2a.TaskStateFailed}, Metadata: map[string]any{
+			"correlation_id":     "corr-1",
+			a2a.MetadataKeyError: a2a.NewTaskError(a2a.ErrorLLMRateLimited, "something went wrong"),
+		}}
+		json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	l, err := i18n.New(types.LocalizationConfig{Detect: true}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter(srv.URL, "")
+	router.SetLocalizer(l)
+	msg, err := router.Handler()(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "Hola, ¿puedes resumir las facturas pendientes?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Estoy recibiendo demasiadas solicitudes en este momento. Vuelve a intentarlo en un momento. (ref.: corr-1)"
+	if got := msg.Parts[0].Text; got != want {
+		t.Errorf("reply = %q, want %q", got, want)
+	}
+	if gotLang != "es" {
+		t.Errorf("message language = %v, want es", gotLang)
+	}
+}
+
+func TestRouter_ForwardToA2A_HandedOffIsSilent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		task := a2a.Task{ID: "t1", Status: a2a.TaskStatus{
+			State:   a2a.TaskStateCompleted,
+			Message: &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{}, Metadata: map[string]any{a2a.MetadataKeyHandoff: true}},
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	_, err := NewRouter(srv.URL, "").forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "hi"})
+	if !channels.IsDropped(err) {
+		t.Errorf("err = %v, want the reply dropped for the operators to answer", err)
+	}
+}
+
 func TestRouter_Handler(t *testing.T) {
 	router := NewRouter("http://localhost:9999", "")
 	handler := router.Handler()
@@ -0,0 +1,147 @@
+package channels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultSessionTTL is how long a channel conversation may sit idle
+// before its next message starts a new agent session.
+const DefaultSessionTTL = 24 * time.Hour
+
+// sessionTouchInterval bounds how often a conversation's last-seen time
+// is written back to disk; the TTL is coarse enough not to need every
+// message persisted.
+const sessionTouchInterval = time.Minute
+
+// Sessions maps each channel conversation — a Slack thread, a Telegram
+// chat — to the A2A task ID its messages are sent under, so they land
+// in one memory session. A conversation idle longer than the TTL, or
+// reset with the /new command, moves to a fresh session.
+//
+// Conversations are keyed by channels.SessionTaskID. A conversation's
+// first session reuses that key as its task ID, so history from before
+// the store existed carries over.
+type Sessions struct {
+	path string // "" keeps the mapping in memory only
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*sessionEntry
+	now     func() time.Time
+}
+
+type sessionEntry struct {
+	ID       string    `json:"id"`
+	LastSeen time.Time `json:"last_seen"`
+	// saved is when LastSeen was last persisted.
+	saved time.Time
+}
+
+// OpenSessions loads the session map stored at path, creating it on the
+// first write. An empty path keeps the map in memory. ttl <= 0 means
+// DefaultSessionTTL.
+func OpenSessions(path string, ttl time.Duration) (*Sessions, error) {
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	s := &Sessions{path: path, ttl: ttl, entries: map[string]*sessionEntry{}, now: time.Now}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading channel sessions: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("parsing channel sessions %s: %w", path, err)
+	}
+	for _, e := range s.entries {
+		e.saved = e.LastSeen
+	}
+	return s, nil
+}
+
+// Resolve returns the task ID of conversation key's current session,
+// starting a new one when the conversation is new or has been idle
+// longer than the TTL. Each call counts as activity.
+func (s *Sessions) Resolve(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	e, ok := s.entries[key]
+	switch {
+	case !ok:
+		e = &sessionEntry{ID: key}
+		s.entries[key] = e
+	case now.Sub(e.LastSeen) > s.ttl:
+		e.ID = newSessionID(key, now)
+		e.saved = time.Time{}
+	}
+	e.LastSeen = now
+	if now.Sub(e.saved) >= sessionTouchInterval {
+		s.saveLocked(now)
+	}
+	return e.ID
+}
+
+// Reset starts a new session for conversation key and returns its task
+// ID. The agent no longer sees the conversation's earlier messages.
+func (s *Sessions) Reset(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	e := &sessionEntry{ID: newSessionID(key, now), LastSeen: now}
+	s.entries[key] = e
+	s.saveLocked(now)
+	return e.ID
+}
+
+// newSessionID derives a session ID for key that differs from every
+// earlier one.
+func newSessionID(key string, now time.Time) string {
+	return key + "-" + strconv.FormatInt(now.UnixNano(), 36)
+}
+
+// saveLocked drops expired conversations and writes the map atomically.
+// A failed write only costs affinity across a restart, so it is not
+// reported.
+func (s *Sessions) saveLocked(now time.Time) {
+	for k, e := range s.entries {
+		if now.Sub(e.LastSeen) > s.ttl {
+			delete(s.entries, k)
+		}
+	}
+	if s.path == "" {
+		return
+	}
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return
+	}
+	for _, e := range s.entries {
+		e.saved = e.LastSeen
+	}
+}
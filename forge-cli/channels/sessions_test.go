@@ -0,0 +1,140 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+func TestSessions_AffinityTTLAndReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "channel-sessions.json")
+	s, err := OpenSessions(path, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenSessions: %v", err)
+	}
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	// A conversation's first session is its key, so existing history
+	// carries over.
+	if got := s.Resolve("slack-C1-171.1"); got != "slack-C1-171.1" {
+		t.Errorf("first session = %q", got)
+	}
+	reset := s.Reset("slack-C1-171.1")
+	if reset == "slack-C1-171.1" || !strings.HasPrefix(reset, "slack-C1-171.1-") {
+		t.Fatalf("Reset = %q", reset)
+	}
+	if got := s.Resolve("slack-C1-171.1"); got != reset {
+		t.Errorf("after reset Resolve = %q, want %q", got, reset)
+	}
+
+	// The mapping survives a restart.
+	s2, err := OpenSessions(path, time.Hour)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	s2.now = func() time.Time { return now.Add(30 * time.Minute) }
+	if got := s2.Resolve("slack-C1-171.1"); got != reset {
+		t.Errorf("after restart Resolve = %q, want %q", got, reset)
+	}
+
+	// Idle past the TTL, the conversation moves on.
+	s2.now = func() time.Time { return now.Add(3 * time.Hour) }
+	if got := s2.Resolve("slack-C1-171.1"); got == reset {
+		t.Error("expired conversation kept its session")
+	}
+}
+
+func TestRouter_NewSessionCommand(t *testing.T) {
+	var taskIDs, texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		var params a2a.SendTaskParams
+		json.Unmarshal(req.Params, &params) //nolint:errcheck
+		taskIDs = append(taskIDs, params.ID)
+		texts = append(texts, params.Message.Parts[0].Text)
+		task := a2a.Task{
+			ID: params.ID,
+			Status: a2a.TaskStatus{
+				State:   a2a.TaskStateCompleted,
+				Message: &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("ok")}},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	router := NewRouter(srv.URL, "")
+	sessions, _ := OpenSessions("", 0)
+	router.SetSessions(sessions)
+	h := router.Handler()
+	ctx := context.Background()
+	chat := func(text string) *a2a.Message {
+		t.Helper()
+		resp, err := h(ctx, &channels.ChannelEvent{Channel: "telegram", WorkspaceID: "42", UserID: "7", Message: text})
+		if err != nil {
+			t.Fatalf("%q: %v", text, err)
+		}
+		return resp
+	}
+
+	chat("hello")
+	chat("again")
+	if resp := chat("/new@forge_bot"); resp.Parts[0].Text != english("channel.new_session") {
+		t.Errorf("/new reply = %q", resp.Parts[0].Text)
+	}
+	chat("/new what's on today?")
+
+	if len(taskIDs) != 3 {
+		t.Fatalf("forwarded %d messages, want 3 (bare /new is answered locally)", len(taskIDs))
+	}
+	if taskIDs[0] != taskIDs[1] || taskIDs[0] != "telegram-42-7" {
+		t.Errorf("one chat spread over tasks %q", taskIDs[:2])
+	}
+	if taskIDs[2] == taskIDs[1] {
+		t.Error("/new kept the old session")
+	}
+	if !strings.HasSuffix(texts[2], "\nwhat's on today?") {
+		t.Errorf("text after /new = %q", texts[2])
+	}
+
+	// Progress subscriptions by conversation follow the current session.
+	var subscribed string
+	sub := router.ProgressSubscriber(func(taskID string, _ func(channels.ProgressUpdate)) func() {
+		subscribed = taskID
+		return func() {}
+	})
+	sub("telegram-42-7", nil)
+	if subscribed != taskIDs[2] {
+		t.Errorf("progress subscribed to %q, want %q", subscribed, taskIDs[2])
+	}
+}
+
+func TestNewSessionCommand(t *testing.T) {
+	for _, tc := range []struct {
+		msg, rest string
+		ok        bool
+	}{
+		{"/new", "", true},
+		{"  /new  ", "", true},
+		{"/new@forge_bot", "", true},
+		{"/new\nsummarise this", "summarise this", true},
+		{"/news today", "", false},
+		{"start /new", "", false},
+	} {
+		rest, ok := newSessionCommand(tc.msg)
+		if rest != tc.rest || ok != tc.ok {
+			t.Errorf("newSessionCommand(%q) = %q, %v", tc.msg, rest, ok)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// DefaultMaxSpokenChars is the longest reply spoken back by default;
+// see types.VoiceTTSConfig.
+const DefaultMaxSpokenChars = 1000
+
+// VoiceFromConfig builds the forge.yaml voice middleware, reading each
+// provider's API key from its api_key_env (default per provider). It
+// returns nil when no STT provider is configured.
+func VoiceFromConfig(cfg types.VoiceConfig, warn func(msg string, fields map[string]any)) (channels.Middleware, error) {
+	if cfg.STT.Provider == "" {
+		return nil, nil
+	}
+	stt, err := providers.NewTranscriber(cfg.STT.Provider, providers.SpeechConfig{
+		APIKey:   speechAPIKey(cfg.STT.Provider, cfg.STT.APIKeyEnv),
+		BaseURL:  cfg.STT.BaseURL,
+		Model:    cfg.STT.Model,
+		Language: cfg.STT.Language,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("voice.stt: %w", err)
+	}
+	var tts llm.Synthesizer
+	if cfg.TTS.Provider != "" {
+		tts, err = providers.NewSynthesizer(cfg.TTS.Provider, providers.SpeechConfig{
+			APIKey:  speechAPIKey(cfg.TTS.Provider, cfg.TTS.APIKeyEnv),
+			BaseURL: cfg.TTS.BaseURL,
+			Model:   cfg.TTS.Model,
+			Voice:   cfg.TTS.Voice,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("voice.tts: %w", err)
+		}
+	}
+	maxChars := cfg.TTS.MaxChars
+	if maxChars == 0 {
+		maxChars = DefaultMaxSpokenChars
+	}
+	return Voice(stt, tts, maxChars, warn), nil
+}
+
+func speechAPIKey(provider, env string) string {
+	if env == "" {
+		env = providers.SpeechAPIKeyEnv(provider)
+	}
+	if env == "" {
+		return ""
+	}
+	return os.Getenv(env)
+}
+
+// Voice transcribes an event's audio attachments with stt and forwards
+// the transcript as the message text, after any caption. When tts is
+// set, a reply to a voice message of at most maxSpokenChars characters
+// is also spoken: the audio rides along as an Ogg Opus file part,
+// which the adapter sends as a voice note. Events without audio pass
+// untouched.
+func Voice(stt llm.Transcriber, tts llm.Synthesizer, maxSpokenChars int, warn func(string, map[string]any)) channels.Middleware {
+	return func(next channels.EventHandler) channels.EventHandler {
+		return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+			var transcripts []string
+			spoken := false
+			for i := range event.Attachments {
+				a := &event.Attachments[i]
+				if !a.IsAudio() || len(a.Data) == 0 {
+					continue
+				}
+				spoken = true
+				res, err := stt.Transcribe(ctx, &llm.TranscriptionRequest{Audio: a.Data, Filename: a.Name, MimeType: a.MimeType})
+				if err != nil {
+					warn("voice message transcription failed", map[string]any{
+						"channel": event.Channel, "user_id": event.UserID, "error": err.Error(),
+					})
+					return policyReply(
+						localize(ctx, "channel.voice_failed"),
+						a2a.NewTaskError(a2a.ErrorLLMUnavailable, "voice message transcription failed"),
+					), nil
+				}
+				if res.Text != "" {
+					transcripts = append(transcripts, res.Text)
+				}
+				// The transcript replaces the audio; a queued event
+				// need not carry it.
+				a.Data = nil
+			}
+			if !spoken {
+				return next(ctx, event)
+			}
+			if len(transcripts) == 0 {
+				return &a2a.Message{
+					Role:  a2a.MessageRoleAgent,
+					Parts: []a2a.Part{a2a.NewTextPart(localize(ctx, "channel.voice_empty"))},
+				}, nil
+			}
+			text := strings.Join(transcripts, "\n\n")
+			if event.Message != "" {
+				text = event.Message + "\n\n" + text
+			}
+			event.Message = text
+
+			resp, err := next(ctx, event)
+			if err != nil || resp == nil || tts == nil || a2a.TaskErrorFromMessage(resp) != nil {
+				return resp, err
+			}
+			return speakReply(ctx, tts, resp, maxSpokenChars, event, warn), nil
+		}
+	}
+}
+
+// speakReply returns resp with its text spoken as an extra audio file
+// part, or resp unchanged when the text is empty, longer than
+// maxChars, or synthesis fails.
+func speakReply(ctx context.Context, tts llm.Synthesizer, resp *a2a.Message, maxChars int, event *channels.ChannelEvent, warn func(string, map[string]any)) *a2a.Message {
+	var texts []string
+	for _, p := range resp.Parts {
+		if p.Kind == a2a.PartKindText && p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	text := strings.Join(texts, "\n")
+	if text == "" || utf8.RuneCountInString(text) > maxChars {
+		return resp
+	}
+	speech, err := tts.Synthesize(ctx, &llm.SpeechRequest{Text: text, Format: "opus"})
+	if err != nil {
+		warn("voice reply synthesis failed, replying in text", map[string]any{
+			"channel": event.Channel, "error": err.Error(),
+		})
+		return resp
+	}
+	out := *resp
+	out.Parts = append(append([]a2a.Part(nil), resp.Parts...), a2a.NewFilePart(a2a.FileContent{
+		Name:     "reply.ogg",
+		MimeType: speech.MimeType,
+		Bytes:    speech.Audio,
+	}))
+	return &out
+}
@@ -0,0 +1,111 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+type fakeSpeech struct {
+	transcript string
+	err        error
+	spoken     []string
+}
+
+func (f *fakeSpeech) Transcribe(_ context.Context, req *llm.TranscriptionRequest) (*llm.TranscriptionResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &llm.TranscriptionResponse{Text: f.transcript}, nil
+}
+
+func (f *fakeSpeech) Synthesize(_ context.Context, req *llm.SpeechRequest) (*llm.SpeechResponse, error) {
+	f.spoken = append(f.spoken, req.Text)
+	return &llm.SpeechResponse{Audio: []byte("OggS"), MimeType: "audio/ogg"}, nil
+}
+
+func voiceEvent() *channels.ChannelEvent {
+	return &channels.ChannelEvent{Channel: "telegram", UserID: "1", Message: "re: standup", Attachments: []channels.Attachment{
+		{Name: "voice.ogg", MimeType: "audio/ogg", Data: []byte("audio")},
+	}}
+}
+
+func TestVoiceMiddleware(t *testing.T) {
+	speech := &fakeSpeech{transcript: "Move it to ten thirty."}
+	var forwarded string
+	h := channels.Chain(func(_ context.Context, e *channels.ChannelEvent) (*a2a.Message, error) {
+		forwarded = e.Message
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Done.")}}, nil
+	}, Voice(speech, speech, 100, noWarn))
+
+	event := voiceEvent()
+	resp, err := h(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forwarded != "re: standup\n\nMove it to ten thirty." {
+		t.Errorf("forwarded message = %q", forwarded)
+	}
+	if event.Attachments[0].Data != nil {
+		t.Error("audio kept on the event after transcription")
+	}
+	if len(resp.Parts) != 2 || resp.Parts[1].File == nil || resp.Parts[1].File.MimeType != "audio/ogg" {
+		t.Fatalf("reply parts = %+v", resp.Parts)
+	}
+	if strings.Join(speech.spoken, "|") != "Done." {
+		t.Errorf("spoken = %q", speech.spoken)
+	}
+
+	// Typed messages are neither transcribed nor spoken back.
+	resp, _ = h(context.Background(), &channels.ChannelEvent{Channel: "telegram", Message: "typed"})
+	if forwarded != "typed" || len(resp.Parts) != 1 || len(speech.spoken) != 1 {
+		t.Errorf("typed message: forwarded %q, %d parts, %d spoken", forwarded, len(resp.Parts), len(speech.spoken))
+	}
+}
+
+func TestVoiceMiddleware_LongReplyStaysText(t *testing.T) {
+	speech := &fakeSpeech{transcript: "Summarize the incident."}
+	h := channels.Chain(func(context.Context, *channels.ChannelEvent) (*a2a.Message, error) {
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(strings.Repeat("x", 101))}}, nil
+	}, Voice(speech, speech, 100, noWarn))
+	resp, _ := h(context.Background(), voiceEvent())
+	if len(resp.Parts) != 1 || len(speech.spoken) != 0 {
+		t.Errorf("long reply was spoken: %d parts", len(resp.Parts))
+	}
+}
+
+func TestVoiceMiddleware_TranscriptionFails(t *testing.T) {
+	var n atomic.Int32
+	h := channels.Chain(countingHandler(&n), Voice(&fakeSpeech{err: errors.New("503")}, nil, 100, noWarn))
+	resp, err := h(context.Background(), voiceEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te := a2a.TaskErrorFromMessage(resp); te == nil || !te.Retryable {
+		t.Errorf("reply error = %+v", te)
+	}
+	if n.Load() != 0 {
+		t.Error("an untranscribed voice message reached the agent")
+	}
+}
+
+func TestVoiceFromConfig(t *testing.T) {
+	mw, err := VoiceFromConfig(types.VoiceConfig{}, noWarn)
+	if err != nil || mw != nil {
+		t.Errorf("no stt: mw = %v, err = %v", mw != nil, err)
+	}
+	mw, err = VoiceFromConfig(types.VoiceConfig{STT: types.VoiceSTTConfig{Provider: "local"}, TTS: types.VoiceTTSConfig{Provider: "openai"}}, noWarn)
+	if err != nil || mw == nil {
+		t.Errorf("configured: mw = %v, err = %v", mw != nil, err)
+	}
+	if _, err := VoiceFromConfig(types.VoiceConfig{STT: types.VoiceSTTConfig{Provider: "deepgram"}}, noWarn); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
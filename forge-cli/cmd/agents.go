@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/internal/fleet"
+)
+
+var agentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "Manage the agents a forge ui dashboard serves",
+	Long: `Lists, starts and stops agents and reads their logs through the API of a
+'forge ui' dashboard, so a fleet can be managed from a terminal or CI
+without the web UI.
+
+--host defaults to $FORGE_UI_HOST, then http://127.0.0.1:4200. A
+dashboard bound to a non-loopback address requires its API token: pass
+--token or set $FORGE_UI_TOKEN.`,
+	Example: `  forge agents list --host https://ui.internal:4200
+  forge agents start support-bot
+  forge agents logs support-bot --lines 200`,
+}
+
+var agentsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agents and their status",
+	Args:  cobra.NoArgs,
+	RunE:  agentsListRun,
+}
+
+var agentsStartCmd = &cobra.Command{
+	Use:   "start <agent-id>",
+	Short: "Start an agent",
+	Long: `Starts an agent and waits until it is running. An agent with encrypted
+secrets needs a passphrase: pipe it in with --passphrase-stdin, or set
+FORGE_PASSPHRASE where the dashboard runs.`,
+	Args: cobra.ExactArgs(1),
+	RunE: agentsStartRun,
+}
+
+var agentsStopCmd = &cobra.Command{
+	Use:   "stop <agent-id>",
+	Short: "Stop an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE:  agentsStopRun,
+}
+
+var agentsLogsCmd = &cobra.Command{
+	Use:   "logs <agent-id>",
+	Short: "Print the tail of an agent's serve log",
+	Args:  cobra.ExactArgs(1),
+	RunE:  agentsLogsRun,
+}
+
+var (
+	agentsHost            string
+	agentsToken           string
+	agentsListJSON        bool
+	agentsPassphraseStdin bool
+	agentsLogLines        int
+)
+
+func init() {
+	agentsCmd.PersistentFlags().StringVar(&agentsHost, "host", "", "dashboard address (default $FORGE_UI_HOST, then http://127.0.0.1:4200)")
+	agentsCmd.PersistentFlags().StringVar(&agentsToken, "token", "", "dashboard API token (default $FORGE_UI_TOKEN)")
+	agentsListCmd.Flags().BoolVar(&agentsListJSON, "json", false, "print the agents as JSON")
+	agentsStartCmd.Flags().BoolVar(&agentsPassphraseStdin, "passphrase-stdin", false, "read the secrets passphrase from stdin")
+	agentsLogsCmd.Flags().IntVarP(&agentsLogLines, "lines", "n", 100, "number of lines to print (max 5000)")
+	agentsCmd.AddCommand(agentsListCmd, agentsStartCmd, agentsStopCmd, agentsLogsCmd)
+}
+
+func fleetClient() (*fleet.Client, error) {
+	host := agentsHost
+	if host == "" {
+		host = os.Getenv("FORGE_UI_HOST")
+	}
+	if host == "" {
+		host = "http://127.0.0.1:4200"
+	}
+	if !strings.Contains(host, "://") {
+		return nil, fmt.Errorf("--host %q needs a scheme, e.g. https://%s", host, host)
+	}
+	token := agentsToken
+	if token == "" {
+		token = os.Getenv("FORGE_UI_TOKEN")
+	}
+	return &fleet.Client{BaseURL: host, Token: token}, nil
+}
+
+func agentsListRun(cmd *cobra.Command, args []string) error {
+	client, err := fleetClient()
+	if err != nil {
+		return err
+	}
+	agents, err := client.List(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if agentsListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(agents)
+	}
+	if len(agents) == 0 {
+		fmt.Println("No agents in the dashboard's workspace.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "ID\tSTATUS\tPORT\tMODEL\tUPTIME\tDIRECTORY\n")
+	for _, a := range agents {
+		port, uptime := "-", "-"
+		if a.Port != 0 {
+			port = fmt.Sprint(a.Port)
+		}
+		if a.StartedAt != nil && a.Status == "running" {
+			uptime = time.Since(*a.StartedAt).Round(time.Second).String()
+		}
+		status := a.Status
+		if a.Error != "" {
+			status += ": " + a.Error
+		}
+		model := a.Model.Name
+		if a.Model.Provider != "" {
+			model = a.Model.Provider + "/" + model
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, status, port, model, uptime, a.Directory)
+	}
+	return w.Flush()
+}
+
+func agentsStartRun(cmd *cobra.Command, args []string) error {
+	client, err := fleetClient()
+	if err != nil {
+		return err
+	}
+	var passphrase string
+	if agentsPassphraseStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("reading passphrase from stdin: %w", err)
+		}
+		passphrase = strings.TrimRight(line, "\r\n")
+	}
+	if err := client.Start(cmd.Context(), args[0], passphrase); err != nil {
+		return err
+	}
+	a, err := client.Get(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+	if a.Status != "running" {
+		return fmt.Errorf("agent %s is %s: %s", a.ID, a.Status, a.Error)
+	}
+	fmt.Printf("Started %s on port %d\n", a.ID, a.Port)
+	return nil
+}
+
+func agentsStopRun(cmd *cobra.Command, args []string) error {
+	client, err := fleetClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Stop(cmd.Context(), args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("Stopped %s\n", args[0])
+	return nil
+}
+
+func agentsLogsRun(cmd *cobra.Command, args []string) error {
+	if agentsLogLines < 1 {
+		return fmt.Errorf("--lines must be at least 1")
+	}
+	client, err := fleetClient()
+	if err != nil {
+		return err
+	}
+	lines, err := client.Logs(cmd.Context(), args[0], agentsLogLines)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/internal/batch"
+	"github.com/initializ/forge/forge-cli/runtime"
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run files of tasks through the agent",
+}
+
+var batchRunCmd = &cobra.Command{
+	Use:   "run <tasks.jsonl|tasks.csv>",
+	Short: "Run each row of a JSONL or CSV file as a task",
+	Long: `Runs every row of a JSONL or CSV file as a separate task and appends one
+JSON result line per row to the output file: id, status, output or error,
+tokens, estimated cost and duration.
+
+A row's task is its "task" field, or --template rendered over the row's
+fields ({{.text}} inserts the "text" column). Its id is the "id" field, or
+row-N. CSV files name their columns on the first line.
+
+Runs are resumable: rows the output file already records as completed are
+skipped, so rerunning after an interruption or with failed rows retries
+only what is left. Pass --restart to start the output file over.
+
+By default tasks run in-process through the agent in the current
+directory, like forge try. With --url they are sent to a running agent's
+A2A endpoint instead, with --token (default $FORGE_AUTH_TOKEN) as the
+bearer token.`,
+	Example: `  forge batch run tickets.jsonl --concurrency 4 --output results.jsonl
+  forge batch run reviews.csv --template 'Classify the sentiment of: {{.text}}'
+  forge batch run tickets.jsonl --url http://localhost:8080`,
+	Args: cobra.ExactArgs(1),
+	RunE: batchRunRun,
+}
+
+var (
+	batchConcurrency int
+	batchOutput      string
+	batchTemplate    string
+	batchURL         string
+	batchToken       string
+	batchRestart     bool
+	batchEnvFile     string
+)
+
+func init() {
+	batchRunCmd.Flags().IntVar(&batchConcurrency, "concurrency", batch.DefaultConcurrency, "number of tasks run at once")
+	batchRunCmd.Flags().StringVar(&batchOutput, "output", "", "results file (default <input>.results.jsonl)")
+	batchRunCmd.Flags().StringVar(&batchTemplate, "template", "", "Go template rendering each row's fields into its task")
+	batchRunCmd.Flags().StringVar(&batchURL, "url", "", "send tasks to a running agent's A2A endpoint instead of running in-process")
+	batchRunCmd.Flags().StringVar(&batchToken, "token", "", "bearer token for --url (default $FORGE_AUTH_TOKEN)")
+	batchRunCmd.Flags().BoolVar(&batchRestart, "restart", false, "truncate the results file instead of resuming")
+	batchRunCmd.Flags().StringVar(&batchEnvFile, "env", ".env", "path to .env file")
+	batchCmd.AddCommand(batchRunCmd)
+}
+
+func batchRunRun(cmd *cobra.Command, args []string) error {
+	input := args[0]
+	rows, err := batch.ReadRows(input, batchTemplate)
+	if err != nil {
+		return err
+	}
+	output := batchOutput
+	if output == "" {
+		output = strings.TrimSuffix(input, filepath.Ext(input)) + ".results.jsonl"
+	}
+
+	done := map[string]bool{}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if batchRestart {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	} else if done, err = batch.Completed(output); err != nil {
+		return fmt.Errorf("reading %s: %w", output, err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	exec, closeExec, err := taskExecutor(ctx, batchURL, batchToken, batchEnvFile)
+	if err != nil {
+		return err
+	}
+	defer closeExec()
+
+	out, err := os.OpenFile(output, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer out.Close() //nolint:errcheck
+
+	pending := 0
+	for _, row := range rows {
+		if !done[row.ID] {
+			pending++
+		}
+	}
+	if pending < len(rows) {
+		fmt.Fprintf(os.Stderr, "Resuming: %d of %d rows already completed in %s\n", len(rows)-pending, len(rows), output)
+	}
+	finished := 0
+	sum, runErr := batch.Run(ctx, rows, done, exec, out, batch.Options{
+		Concurrency: batchConcurrency,
+		Done: func(res batch.Result) {
+			finished++
+			line := fmt.Sprintf("[%d/%d] %s %s (%.1fs)", finished, pending, res.ID, res.Status, float64(res.DurationMs)/1000)
+			if res.Error != "" {
+				line += ": " + res.Error
+			}
+			fmt.Fprintln(os.Stderr, line)
+		},
+	})
+
+	fmt.Printf("\nRows:      %d total, %d completed, %d failed, %d skipped\n", sum.Total, sum.Completed, sum.Failed, sum.Skipped)
+	fmt.Printf("Tokens:    %d in, %d out\n", sum.InputTokens, sum.OutputTokens)
+	cost := fmt.Sprintf("$%.4f", sum.CostUSD)
+	if sum.UnpricedRows > 0 {
+		cost += fmt.Sprintf(" (%d rows on unpriced models not counted)", sum.UnpricedRows)
+	}
+	fmt.Printf("Est. cost: %s\n", cost)
+	fmt.Printf("Duration:  %s\n", sum.Duration.Round(100*time.Millisecond))
+	fmt.Printf("Results:   %s\n", output)
+
+	if runErr != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("interrupted; rerun the same command to resume")
+		}
+		return runErr
+	}
+	if sum.Failed > 0 {
+		return fmt.Errorf("%d row(s) failed; rerun the same command to retry them", sum.Failed)
+	}
+	return nil
+}
+
+// taskExecutor returns the remote executor for agentURL, or, when it is
+// empty, an in-process session over the agent in the current directory.
+// Shared by `forge batch run` and `forge bench`.
+func taskExecutor(ctx context.Context, agentURL, token, envFile string) (batch.Executor, func(), error) {
+	if agentURL != "" {
+		if token == "" {
+			token = os.Getenv("FORGE_AUTH_TOKEN")
+		}
+		return batch.RemoteExecutor(&http.Client{}, agentURL, token), func() {}, nil
+	}
+
+	cfg, workDir, err := loadAndPrepareConfig(envFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	sess, err := runtime.NewLocalSession(ctx, runtime.LocalSessionOptions{Config: cfg, WorkDir: workDir})
+	if err != nil {
+		return nil, nil, err
+	}
+	exec := func(ctx context.Context, taskID, prompt string) (batch.Outcome, error) {
+		text, snap, err := sess.RunTask(ctx, taskID, prompt)
+		return batch.Outcome{
+			Output:       text,
+			InputTokens:  snap.InputTokens,
+			OutputTokens: snap.OutputTokens,
+			Model:        snap.PrimaryModel,
+			LLMTime:      snap.LLMTimeTotal,
+		}, err
+	}
+	return exec, func() { _ = sess.Close() }, nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/internal/batch"
+	"github.com/initializ/forge/forge-cli/internal/bench"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Load-test the agent and report latency and throughput",
+	Long: `Fires tasks at the agent at a fixed concurrency and reports p50/p95/p99
+latency, throughput, output tokens per second, the time tasks spend outside
+the model (tool calls, guardrails and the runtime), and a breakdown of the
+failures by kind.
+
+By default tasks run in-process through the agent in the current directory.
+With --url they are sent to a running agent's A2A endpoint, with --token
+(default $FORGE_AUTH_TOKEN) as the bearer token; a remote agent does not
+report model time, so the overhead figures are left out.
+
+Prompts come from --prompt (repeatable) or --prompts, a JSONL or CSV file
+read like forge batch run's input, and are sent in turn. The run stops after
+--requests tasks or --duration, whichever comes first; --duration alone runs
+for the whole duration. Every task calls the model, so a benchmark costs
+tokens.`,
+	Example: `  forge bench --prompt "What's the weather in Paris?" --concurrency 8 --requests 100
+  forge bench --prompts tickets.jsonl --duration 2m --url http://localhost:8080
+  forge bench --prompt "ping" --requests 20 --json > bench.json`,
+	Args: cobra.NoArgs,
+	RunE: benchRun,
+}
+
+var (
+	benchPrompts     []string
+	benchPromptsFile string
+	benchConcurrency int
+	benchRequests    int
+	benchDuration    time.Duration
+	benchURL         string
+	benchToken       string
+	benchEnvFile     string
+	benchJSON        bool
+)
+
+func init() {
+	benchCmd.Flags().StringArrayVar(&benchPrompts, "prompt", nil, "prompt to send (repeatable)")
+	benchCmd.Flags().StringVar(&benchPromptsFile, "prompts", "", "JSONL or CSV file of prompts, one task per row")
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", 4, "number of tasks in flight")
+	benchCmd.Flags().IntVar(&benchRequests, "requests", 20, "number of tasks to run (0 = until --duration; ignored when only --duration is set)")
+	benchCmd.Flags().DurationVar(&benchDuration, "duration", 0, "stop starting tasks after this long (e.g. 2m)")
+	benchCmd.Flags().StringVar(&benchURL, "url", "", "benchmark a running agent's A2A endpoint instead of running in-process")
+	benchCmd.Flags().StringVar(&benchToken, "token", "", "bearer token for --url (default $FORGE_AUTH_TOKEN)")
+	benchCmd.Flags().StringVar(&benchEnvFile, "env", ".env", "path to .env file")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "print the report as JSON")
+}
+
+func benchRun(cmd *cobra.Command, _ []string) error {
+	prompts := benchPrompts
+	if benchPromptsFile != "" {
+		rows, err := batch.ReadRows(benchPromptsFile, "")
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			prompts = append(prompts, row.Task)
+		}
+	}
+	if len(prompts) == 0 {
+		return fmt.Errorf("--prompt or --prompts is required")
+	}
+	requests := benchRequests
+	if benchDuration > 0 && !cmd.Flags().Changed("requests") {
+		requests = 0 // --duration alone runs for the whole duration
+	}
+	if requests <= 0 && benchDuration <= 0 {
+		return fmt.Errorf("--requests or --duration is required")
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	exec, closeExec, err := taskExecutor(ctx, benchURL, benchToken, benchEnvFile)
+	if err != nil {
+		return err
+	}
+	defer closeExec()
+
+	if !benchJSON {
+		fmt.Fprintf(os.Stderr, "Benchmarking with %d concurrent tasks...\n", benchConcurrency)
+	}
+	report, err := bench.Run(ctx, exec, bench.Options{
+		Prompts:     prompts,
+		Concurrency: benchConcurrency,
+		Requests:    requests,
+		Duration:    benchDuration,
+	})
+	if err != nil {
+		return err
+	}
+
+	if benchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printBenchReport(os.Stdout, report)
+	return nil
+}
+
+// printBenchReport writes report as a human-readable summary.
+func printBenchReport(w io.Writer, r bench.Report) {
+	_, _ = fmt.Fprintf(w, "\nRequests:    %d (%d succeeded, %d failed) at concurrency %d in %.1fs\n",
+		r.Requests, r.Succeeded, r.Failed, r.Concurrency, r.DurationSec)
+	_, _ = fmt.Fprintf(w, "Throughput:  %.2f tasks/s\n", r.Throughput)
+	if r.Succeeded > 0 {
+		_, _ = fmt.Fprintf(w, "Latency:     p50 %.2fs  p95 %.2fs  p99 %.2fs  max %.2fs\n",
+			r.LatencyP50, r.LatencyP95, r.LatencyP99, r.LatencyMax)
+	}
+	_, _ = fmt.Fprintf(w, "Tokens:      %d in, %d out (%.1f output tokens/s)\n", r.InputTokens, r.OutputTokens, r.OutputTokensPerSec)
+	if r.OverheadShare != nil {
+		_, _ = fmt.Fprintf(w, "Overhead:    p50 %.2fs  p95 %.2fs outside the model (%.0f%% of task time)\n",
+			*r.OverheadP50, *r.OverheadP95, *r.OverheadShare*100)
+	}
+	if len(r.Failures) > 0 {
+		kinds := make([]string, 0, len(r.Failures))
+		for k := range r.Failures {
+			kinds = append(kinds, k)
+		}
+		// Most frequent first.
+		sort.Slice(kinds, func(i, j int) bool {
+			if ni, nj := r.Failures[kinds[i]], r.Failures[kinds[j]]; ni != nj {
+				return ni > nj
+			}
+			return kinds[i] < kinds[j]
+		})
+		_, _ = fmt.Fprintln(w, "Failures:")
+		for _, k := range kinds {
+			_, _ = fmt.Fprintf(w, "  %-20s %d\n", k, r.Failures[k])
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/initializ/forge/forge-cli/build"
 	"github.com/initializ/forge/forge-cli/config"
@@ -43,6 +44,7 @@ func init() {
 }
 
 func runBuild(cmd *cobra.Command, args []string) error {
+	started := time.Now()
 	cfgPath := cfgFile
 	if !filepath.IsAbs(cfgPath) {
 		wd, err := os.Getwd()
@@ -125,7 +127,9 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		&build.K8sStage{},
 		&build.ScheduleManifestStage{},
 		&build.ValidateStage{},
+		&build.SBOMStage{},
 		&build.ManifestStage{},
+		&build.ProvenanceStage{StartedOn: started},
 		&build.SigningStage{},
 	)
 
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -10,11 +12,14 @@ import (
 	"syscall"
 
 	"github.com/initializ/forge/forge-cli/channels"
+	"github.com/initializ/forge/forge-cli/config"
 	"github.com/initializ/forge/forge-cli/templates"
 	"github.com/initializ/forge/forge-core/auth"
 	corechannels "github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
 	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
 	"github.com/initializ/forge/forge-plugins/channels/msteams"
 	"github.com/initializ/forge/forge-plugins/channels/slack"
 	"github.com/initializ/forge/forge-plugins/channels/telegram"
@@ -215,6 +220,37 @@ func runChannelServe(cmd *cobra.Command, args []string) error {
 		channelToken, _ = auth.LoadToken(wd)
 	}
 	router := channels.NewRouter(agentURL, channelToken)
+	// The forge.yaml channel_middleware and voice blocks apply here too
+	// when the adapter runs next to the agent's config; a sidecar without
+	// one keeps the defaults.
+	forgeCfg, err := loadChannelForgeConfig(wd)
+	if err != nil {
+		return err
+	}
+	router.ApplyMiddlewareConfig(forgeCfg.ChannelMiddleware)
+	if err := router.ApplyVoiceConfig(forgeCfg.Voice); err != nil {
+		return fmt.Errorf("configuring channel voice: %w", err)
+	}
+	router.ApplyAttachmentConfig(forgeCfg.Attachments, plugin)
+	localizer, err := i18n.New(forgeCfg.Localization, wd)
+	if err != nil {
+		return fmt.Errorf("localization: %w", err)
+	}
+	router.SetLocalizer(localizer)
+	if lz, ok := plugin.(corechannels.Localized); ok {
+		lz.SetLocalizer(localizer)
+	}
+	if q, err := channels.OpenQueue(filepath.Join(wd, ".forge", "channel-queue")); err == nil {
+		router.SetQueue(q)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: channel queue disabled: %v\n", err)
+	}
+	sessions, err := channels.OpenSessions(filepath.Join(wd, ".forge", "channel-sessions.json"), forgeCfg.ChannelMiddleware.SessionTTL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: channel sessions not persisted: %v\n", err)
+		sessions, _ = channels.OpenSessions("", forgeCfg.ChannelMiddleware.SessionTTL)
+	}
+	router.SetSessions(sessions)
 
 	// Signal handling
 	ctx, cancel := context.WithCancel(context.Background())
@@ -229,6 +265,11 @@ func runChannelServe(cmd *cobra.Command, args []string) error {
 	}()
 
 	fmt.Fprintf(os.Stderr, "Starting %s adapter (agent: %s)\n", adapter, agentURL)
+	go func() {
+		if err := router.Replay(ctx, adapter, plugin.SendResponse); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Replaying queued messages: %v\n", err)
+		}
+	}()
 	return plugin.Start(ctx, router.Handler())
 }
 
@@ -630,3 +671,27 @@ func mutateDeniedChannelsInPolicy(path, name string, add bool) (bool, error) {
 	}
 	return true, nil
 }
+
+// loadChannelForgeConfig reads the forge.yaml in dir for its
+// channel_middleware and voice blocks, validating both. A missing
+// forge.yaml yields an empty config, which keeps the defaults.
+func loadChannelForgeConfig(dir string) (*types.ForgeConfig, error) {
+	path := cfgFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return &types.ForgeConfig{}, nil
+	}
+	cfg, err := config.LoadForgeConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if err := cfg.ChannelMiddleware.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Voice.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/initializ/forge/forge-cli/internal/tryview"
+	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// forge chat — an interactive terminal chat with the agent in the current
+// directory. It runs the same in-process session as `forge try` (no HTTP
+// server, no channels), with the agent's own forge.yaml, tools and skills.
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Chat with the agent in your terminal, in-process",
+	Long: `Starts the agent in the current directory in-process (no HTTP server) and
+opens an interactive chat. Tool calls, egress checks and guardrail blocks
+print inline as the turn runs; the reply prints when the turn finishes.
+
+Slash commands:
+  /tools                 list the agent's tools
+  /memory                show the agent's long-term MEMORY.md
+  /model [provider] [model]  show or switch the model, keeping the conversation
+  /reset                 start a fresh conversation
+  /save [path]           save the transcript as markdown
+  /help                  list these commands
+  /exit, /quit           leave (so do Ctrl-C and Ctrl-D)
+
+The conversation is held in memory only; nothing is written to the agent's
+session store or long-term memory. --transcript saves it on exit.`,
+	Example: `  forge chat
+  forge chat --model gpt-4o-mini --transcript chat.md`,
+	Args:         cobra.NoArgs,
+	RunE:         runChat,
+	SilenceUsage: true,
+}
+
+var (
+	chatEnvFile    string
+	chatModel      string
+	chatProvider   string
+	chatTranscript string
+	chatQuiet      bool
+)
+
+func init() {
+	chatCmd.Flags().StringVar(&chatEnvFile, "env", ".env", "path to .env file")
+	chatCmd.Flags().StringVar(&chatModel, "model", "", "override model name (sets MODEL_NAME env var)")
+	chatCmd.Flags().StringVar(&chatProvider, "provider", "", "LLM provider (openai, anthropic, ollama)")
+	chatCmd.Flags().StringVar(&chatTranscript, "transcript", "", "save the transcript to this markdown file on exit")
+	chatCmd.Flags().BoolVar(&chatQuiet, "quiet", false, "hide the inline tool/egress loop lines")
+}
+
+func runChat(cmd *cobra.Command, _ []string) error {
+	cfg, workDir, err := loadAndPrepareConfig(chatEnvFile)
+	if err != nil {
+		return err
+	}
+	overrides := map[string]string{}
+	if chatModel != "" {
+		overrides["MODEL_NAME"] = chatModel
+	}
+	if chatProvider != "" {
+		overrides["FORGE_MODEL_PROVIDER"] = chatProvider
+	}
+
+	sess, err := runtime.NewLocalSession(cmd.Context(), runtime.LocalSessionOptions{
+		Config:       cfg,
+		WorkDir:      workDir,
+		EnvOverrides: overrides,
+		Verbose:      verbose,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sess.Close() }()
+
+	out := cmd.OutOrStdout()
+	color := term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == ""
+	renderer := tryview.New(out, chatQuiet, false, color)
+	sess.AuditLogger().AddSink(renderer)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	c := &chatSession{sess: sess, cfg: cfg, workDir: workDir, out: out, renderer: renderer, started: time.Now()}
+	_, _ = fmt.Fprintf(out, "Chatting with %s on %s. /help for commands, /exit to leave.\n", cfg.AgentID, sess.Model())
+	err = c.loop(ctx, cmd.InOrStdin())
+
+	if chatTranscript != "" {
+		if saveErr := c.save(chatTranscript); saveErr != nil {
+			return saveErr
+		}
+		_, _ = fmt.Fprintf(out, "Transcript saved to %s\n", chatTranscript)
+	}
+	return err
+}
+
+// chatSession is one `forge chat` run.
+type chatSession struct {
+	sess     *runtime.LocalSession
+	cfg      *types.ForgeConfig
+	workDir  string
+	out      io.Writer
+	renderer *tryview.Renderer
+	started  time.Time
+
+	// transcript is every turn of the run, across /reset and /model.
+	transcript []chatEntry
+}
+
+// chatEntry is one transcript line.
+type chatEntry struct {
+	role string // "you", "agent", or "system" for resets and model switches
+	text string
+}
+
+// errChatExit ends the loop on /exit or /quit.
+var errChatExit = errors.New("exit")
+
+// loop reads one line at a time until /exit, EOF or a cancelled ctx.
+// Lines starting with "/" are slash commands; anything else is a turn.
+func (c *chatSession) loop(ctx context.Context, in io.Reader) error {
+	// Read stdin on a goroutine so Ctrl-C can end the prompt (see tryREPL).
+	lines := make(chan string)
+	eof := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(eof)
+	}()
+
+	for {
+		_, _ = fmt.Fprint(c.out, "\nyou › ")
+		var line string
+		select {
+		case <-ctx.Done():
+			_, _ = fmt.Fprintln(c.out)
+			return nil
+		case <-eof:
+			return nil
+		case line = <-lines:
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "/") {
+			if err := c.command(line); errors.Is(err, errChatExit) {
+				return nil
+			} else if err != nil {
+				_, _ = fmt.Fprintf(c.out, "  error: %v\n", err)
+			}
+			continue
+		}
+
+		reply, err := c.sess.RunTurn(ctx, line, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil // Ctrl-C mid-turn
+			}
+			_, _ = fmt.Fprintf(c.out, "\n  error: %v\n", err)
+			continue
+		}
+		c.transcript = append(c.transcript, chatEntry{"you", line}, chatEntry{"agent", reply})
+		_, _ = fmt.Fprintf(c.out, "\nagent › %s\n", reply)
+		c.renderer.FlushSummary()
+	}
+}
+
+// command runs one slash command.
+func (c *chatSession) command(line string) error {
+	name, args := parseChatCommand(line)
+	switch name {
+	case "exit", "quit":
+		return errChatExit
+	case "help":
+		_, _ = fmt.Fprintln(c.out, "  /tools  /memory  /model [provider] [model]  /reset  /save [path]  /exit")
+	case "tools":
+		tools := c.sess.Tools()
+		if len(tools) == 0 {
+			_, _ = fmt.Fprintln(c.out, "  No tools.")
+		}
+		for _, t := range tools {
+			_, _ = fmt.Fprintf(c.out, "  %s\n", t)
+		}
+	case "memory":
+		return c.showMemory()
+	case "reset":
+		c.sess.Reset()
+		c.transcript = append(c.transcript, chatEntry{"system", "Conversation reset."})
+		_, _ = fmt.Fprintln(c.out, "  Started a fresh conversation.")
+	case "model":
+		var provider, model string
+		switch len(args) {
+		case 0:
+			_, _ = fmt.Fprintf(c.out, "  %s\n", c.sess.Model())
+			return nil
+		case 1:
+			model = args[0]
+		case 2:
+			provider, model = args[0], args[1]
+		default:
+			return fmt.Errorf("usage: /model [provider] [model]")
+		}
+		now, err := c.sess.SwitchModel(provider, model)
+		if err != nil {
+			return err
+		}
+		c.transcript = append(c.transcript, chatEntry{"system", "Switched to " + now + "."})
+		_, _ = fmt.Fprintf(c.out, "  Switched to %s.\n", now)
+	case "save":
+		path := fmt.Sprintf("forge-chat-%s.md", c.started.Format("20060102-150405"))
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if err := c.save(path); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(c.out, "  Saved to %s\n", path)
+	default:
+		return fmt.Errorf("unknown command /%s (try /help)", name)
+	}
+	return nil
+}
+
+// parseChatCommand splits "/model openai gpt-4o" into "model" and its
+// arguments.
+func parseChatCommand(line string) (name string, args []string) {
+	fields := strings.Fields(strings.TrimPrefix(line, "/"))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), fields[1:]
+}
+
+// showMemory prints the agent's curated long-term memory. The chat itself
+// never writes to it.
+func (c *chatSession) showMemory() error {
+	path := filepath.Join(runtime.MemoryDir(c.cfg.Memory, c.workDir), "MEMORY.md")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, _ = fmt.Fprintf(c.out, "  No long-term memory at %s.\n", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.out, "  %s (%d turns in this conversation)\n\n%s\n", path, chatTurns(c.sess.History()), strings.TrimSpace(string(data)))
+	return nil
+}
+
+// save writes the transcript as markdown.
+func (c *chatSession) save(path string) error {
+	if err := os.WriteFile(path, []byte(renderChatTranscript(c.cfg.AgentID, c.started, c.transcript)), 0o600); err != nil {
+		return fmt.Errorf("saving transcript: %w", err)
+	}
+	return nil
+}
+
+// renderChatTranscript formats a chat as markdown: a heading, then one
+// section per message, with resets and model switches as italic notes.
+func renderChatTranscript(agentID string, started time.Time, entries []chatEntry) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# forge chat with %s\n\n_Started %s_\n", agentID, started.Format(time.RFC1123))
+	for _, e := range entries {
+		switch e.role {
+		case "system":
+			fmt.Fprintf(&sb, "\n_%s_\n", e.text)
+		case "you":
+			fmt.Fprintf(&sb, "\n## You\n\n%s\n", e.text)
+		default:
+			fmt.Fprintf(&sb, "\n## Agent\n\n%s\n", e.text)
+		}
+	}
+	return sb.String()
+}
+
+// chatTurns counts the user messages in history.
+func chatTurns(history []a2a.Message) int {
+	n := 0
+	for _, m := range history {
+		if m.Role == a2a.MessageRoleUser {
+			n++
+		}
+	}
+	return n
+}
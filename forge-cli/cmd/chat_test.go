@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseChatCommand(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		name string
+		args []string
+	}{
+		{"/tools", "tools", nil},
+		{"/Model  anthropic   claude-sonnet-4-5", "model", []string{"anthropic", "claude-sonnet-4-5"}},
+		{"/save notes/chat.md", "save", []string{"notes/chat.md"}},
+		{"/", "", nil},
+	} {
+		name, args := parseChatCommand(tc.line)
+		if name != tc.name || strings.Join(args, " ") != strings.Join(tc.args, " ") {
+			t.Errorf("parseChatCommand(%q) = %q %q, want %q %q", tc.line, name, args, tc.name, tc.args)
+		}
+	}
+}
+
+func TestRenderChatTranscript(t *testing.T) {
+	started := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	got := renderChatTranscript("support-bot", started, []chatEntry{
+		{"you", "hi"},
+		{"agent", "Hello! How can I help?"},
+		{"system", "Switched to openai/gpt-4o."},
+		{"you", "bye"},
+	})
+	want := `# forge chat with support-bot
+
+_Started Fri, 16 Oct 2026 09:30:00 UTC_
+
+## You
+
+hi
+
+## Agent
+
+Hello! How can I help?
+
+_Switched to openai/gpt-4o._
+
+## You
+
+bye
+`
+	if got != want {
+		t.Errorf("transcript =\n%s\nwant\n%s", got, want)
+	}
+}
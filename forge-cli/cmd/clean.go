@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/initializ/forge/forge-core/retention"
+)
+
+// cleanBuild is the `forge clean` category for the build output. It has
+// no retention policy: naming it removes the whole build.
+const cleanBuild = "build"
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean [categories...]",
+	Short: "Remove old sessions, created files, scratch directories and captures",
+	Long: `Applies forge.yaml retention to the agent's artifacts under .forge/:
+
+  sessions  session files (default: removed after 7 days)
+  files     file_create output and cli_execute spill files
+            (default: 7 days, 1 GiB)
+  scratch   task scratch directories (default: 1 day, 1 GiB)
+  captures  provider request/response captures
+            (default: 3 days, 256 MiB)
+
+Entries older than max_age are removed, then the oldest entries until
+the category fits max_bytes. With no arguments all four categories are
+collected. Name "build" to also remove the build output in
+.forge-output/. --all removes every entry of the named categories
+regardless of age.
+
+A running agent collects on its own on retention.gc_schedule. Run
+--all while the agent is stopped: it removes live sessions too.`,
+	Example: `  forge clean --dry-run
+  forge clean scratch files
+  forge clean --all sessions`,
+	RunE: cleanRun,
+}
+
+var (
+	cleanDryRun bool
+	cleanAll    bool
+	cleanJSON   bool
+)
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "report what would be removed without removing anything")
+	cleanCmd.Flags().BoolVar(&cleanAll, "all", false, "remove every entry of the named categories, ignoring retention limits")
+	cleanCmd.Flags().BoolVar(&cleanJSON, "json", false, "print the result as JSON")
+}
+
+func cleanRun(cmd *cobra.Command, args []string) error {
+	cfg, workDir, err := loadAndPrepareConfig(".env")
+	if err != nil {
+		return err
+	}
+	categories, err := cleanCategories(runtime.ArtifactCategories(cfg, workDir), workDir, args, cleanAll)
+	if err != nil {
+		return err
+	}
+	res := retention.Collect(categories, time.Now(), cleanDryRun)
+
+	if cleanJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	return printCleanResult(res, workDir)
+}
+
+// cleanCategories picks the named categories (all artifact categories
+// when none are named) and applies --all.
+func cleanCategories(all []retention.Category, workDir string, names []string, removeAll bool) ([]retention.Category, error) {
+	var known []string
+	for _, c := range all {
+		if !slices.Contains(known, c.Name) {
+			known = append(known, c.Name) // tenants' files share the files category
+		}
+	}
+	known = append(known, cleanBuild)
+	for _, n := range names {
+		if !slices.Contains(known, n) {
+			return nil, fmt.Errorf("unknown category %q (want one of %s)", n, strings.Join(known, ", "))
+		}
+	}
+
+	var out []retention.Category
+	for _, c := range all {
+		if len(names) == 0 || slices.Contains(names, c.Name) {
+			c.Policy.All = removeAll
+			out = append(out, c)
+		}
+	}
+	if slices.Contains(names, cleanBuild) {
+		out = append(out, retention.Category{
+			Name:   cleanBuild,
+			Dir:    filepath.Join(workDir, ".forge-output"),
+			Policy: retention.Policy{All: true},
+		})
+	}
+	return out, nil
+}
+
+func printCleanResult(res *retention.Result, workDir string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "CATEGORY\tDIR\tENTRIES\tSIZE\tREMOVED\tFREED\n")
+	for _, c := range res.Categories {
+		dir := c.Dir
+		if rel, err := filepath.Rel(workDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			dir = rel
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%s\n", c.Category, dir, c.Entries, formatBytes(c.Bytes), len(c.Removed), formatBytes(c.RemovedBytes))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if res.DryRun {
+		for _, c := range res.Categories {
+			for _, r := range c.Removed {
+				fmt.Printf("  would remove %s (%s, %s, last modified %s)\n", r.Path, formatBytes(r.Bytes), r.Reason, r.ModTime.Format(time.DateTime))
+			}
+		}
+	}
+	for _, c := range res.Categories {
+		for _, e := range c.Errors {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: %s\n", c.Category, e)
+		}
+	}
+
+	n, bytes := res.Removed()
+	verb := "Removed"
+	if res.DryRun {
+		verb = "Would remove"
+	}
+	fmt.Printf("\n%s %d entries (%s).\n", verb, n, formatBytes(bytes))
+	return nil
+}
+
+// formatBytes renders n in binary units: 512 B, 1.5 KiB, 3.2 GiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
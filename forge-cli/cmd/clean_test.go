@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/retention"
+)
+
+func TestCleanCategories(t *testing.T) {
+	all := []retention.Category{
+		{Name: "sessions", Policy: retention.Policy{MaxAge: 1}},
+		{Name: "files"},
+		{Name: "scratch"},
+	}
+
+	got, err := cleanCategories(all, "/agent", nil, false)
+	if err != nil || len(got) != 3 || got[0].Policy.All {
+		t.Fatalf("no names: %+v, %v", got, err)
+	}
+
+	got, err = cleanCategories(all, "/agent", []string{"scratch", "build"}, true)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("scratch build: %+v, %v", got, err)
+	}
+	if got[0].Name != "scratch" || !got[0].Policy.All {
+		t.Errorf("scratch = %+v, want --all applied", got[0])
+	}
+	if got[1].Name != "build" || got[1].Dir != filepath.Join("/agent", ".forge-output") || !got[1].Policy.All {
+		t.Errorf("build = %+v", got[1])
+	}
+
+	if _, err := cleanCategories(all, "/agent", []string{"memory"}, false); err == nil {
+		t.Error("unknown category accepted")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 1 << 30: "1.0 GiB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/initializ/forge/forge-cli/config"
 	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/initializ/forge/forge-core/secrets"
 	"github.com/initializ/forge/forge-core/types"
 	"github.com/initializ/forge/forge-core/validate"
 	"golang.org/x/term"
@@ -56,7 +57,8 @@ func loadAndPrepareConfig(envFilePath string) (*types.ForgeConfig, string, error
 	}
 
 	// Prompt for passphrase if encrypted secrets are configured but passphrase is missing.
-	if containsProvider(cfg.Secrets.Providers, "encrypted-file") && os.Getenv("FORGE_PASSPHRASE") == "" {
+	// An identity (FORGE_SECRETS_IDENTITY) opens files wrapped for it instead.
+	if containsProvider(cfg.Secrets.Providers, "encrypted-file") && os.Getenv("FORGE_PASSPHRASE") == "" && os.Getenv(secrets.IdentityEnv) == "" {
 		if term.IsTerminal(int(os.Stdin.Fd())) {
 			fmt.Fprint(os.Stderr, "Enter passphrase for encrypted secrets: ")
 			raw, pErr := term.ReadPassword(int(os.Stdin.Fd()))
@@ -65,7 +67,7 @@ func loadAndPrepareConfig(envFilePath string) (*types.ForgeConfig, string, error
 				_ = os.Setenv("FORGE_PASSPHRASE", string(raw))
 			}
 		} else {
-			fmt.Fprintln(os.Stderr, "Warning: secrets.providers includes encrypted-file but FORGE_PASSPHRASE is not set; encrypted secrets will not be loaded")
+			fmt.Fprintln(os.Stderr, "Warning: secrets.providers includes encrypted-file but neither FORGE_PASSPHRASE nor FORGE_SECRETS_IDENTITY is set; encrypted secrets will not be loaded")
 		}
 	}
 
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRunConfigValidate_UnknownKeyIsError(t *testing.T) {
+	orig := `
+agent_id: test-agent
+version: 0.1.0
+framework: forge
+egres:
+  mode: allowlist
+`
+	cfgPath := writeTestForgeYAML(t, t.TempDir(), orig)
+	oldCfg := cfgFile
+	cfgFile = cfgPath
+	defer func() { cfgFile = oldCfg }()
+
+	if err := runConfigValidate(nil, nil); err == nil {
+		t.Fatal("expected an error for the unknown key")
+	}
+	// Validation never rewrites the file, even one that needs migrating.
+	if b, _ := os.ReadFile(cfgPath); string(b) != orig {
+		t.Errorf("forge.yaml modified: %q", b)
+	}
+}
+
+func TestRunConfigValidate_Valid(t *testing.T) {
+	cfgPath := writeTestForgeYAML(t, t.TempDir(), `
+schema_version: 1
+agent_id: test-agent
+version: 0.1.0
+framework: forge
+model:
+  provider: openai
+  name: gpt-4o
+`)
+	oldCfg := cfgFile
+	cfgFile = cfgPath
+	defer func() { cfgFile = oldCfg }()
+
+	if err := runConfigValidate(nil, nil); err != nil {
+		t.Fatalf("runConfigValidate() error: %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/build"
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/compiler"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	deployOut             string
+	deployImage           string
+	deployRegistry        string
+	deployReplicas        int
+	deployShutdownTimeout time.Duration
+	deployEnvFile         string
+	deployNoSecretValues  bool
+	deploySkipBuild       bool
+)
+
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Generate deployment manifests for the agent",
+}
+
+var deployK8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Generate Kubernetes manifests for the agent",
+	Long: `Generate a Deployment, Service, Secret and NetworkPolicy for the agent.
+
+The NetworkPolicy egress rules come from the resolved egress allowlist in
+forge.yaml, the Deployment's readiness probe follows /health (which reports
+503 while the agent drains), and the Secret is filled from the configured
+secret providers — .env, the environment and secrets.providers — exactly as
+'forge run' would resolve them.
+
+Manifests are written to stdout as one multi-document YAML stream, or as
+separate files with --out. Apply them with 'kubectl apply -n <namespace>'.`,
+	Args: cobra.NoArgs,
+	RunE: runDeployK8s,
+}
+
+func init() {
+	deployK8sCmd.Flags().StringVar(&deployOut, "out", "", "write manifests to this directory instead of stdout")
+	addDeployFlags(deployK8sCmd)
+	deployK8sCmd.Flags().BoolVar(&deployNoSecretValues, "no-secret-values", false, "emit empty Secret placeholders instead of resolved values")
+	deployCmd.AddCommand(deployK8sCmd)
+}
+
+// addDeployFlags registers the flags every deploy target shares.
+func addDeployFlags(c *cobra.Command) {
+	c.Flags().StringVar(&deployImage, "image", "", "container image (default: <registry>/<agent_id>:<version>, as tagged by forge package)")
+	c.Flags().StringVar(&deployRegistry, "registry", "", "registry prefix for the default image (default: registry from forge.yaml)")
+	c.Flags().IntVar(&deployReplicas, "replicas", 1, "number of replicas")
+	c.Flags().DurationVar(&deployShutdownTimeout, "shutdown-timeout", 30*time.Second, "how long a terminating pod drains in-flight tasks (forge run agents)")
+	c.Flags().StringVar(&deployEnvFile, "env", ".env", "path to .env file")
+	c.Flags().BoolVar(&deploySkipBuild, "skip-build", false, "skip re-running forge build")
+}
+
+func runDeployK8s(cmd *cobra.Command, args []string) error {
+	// Same config + secret resolution as `forge run`, so the Secret holds
+	// the values the agent would see locally.
+	cfg, workDir, err := loadAndPrepareConfig(deployEnvFile)
+	if err != nil {
+		return err
+	}
+	data, egressCfg, err := prepareDeploy(cfg, workDir)
+	if err != nil {
+		return err
+	}
+
+	if !deployNoSecretValues {
+		data.SecretValues = map[string]string{}
+		for _, key := range data.RequiredEnvVars {
+			if v := os.Getenv(key); v != "" {
+				data.SecretValues[key] = v
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: required secret %s is not set; the Secret holds an empty value\n", key)
+			}
+		}
+		for _, key := range data.OptionalEnvVars {
+			if v := os.Getenv(key); v != "" {
+				data.SecretValues[key] = v
+			}
+		}
+	}
+
+	manifests, err := build.RenderK8sManifests(data, egressCfg)
+	if err != nil {
+		return err
+	}
+
+	if deployOut == "" {
+		return writeManifestStream(cmd.OutOrStdout(), manifests)
+	}
+	if err := os.MkdirAll(deployOut, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", deployOut, err)
+	}
+	for _, m := range manifests {
+		mode := os.FileMode(0644)
+		if m.Name == "secrets.yaml" && data.SecretValues != nil {
+			mode = 0600
+		}
+		path := filepath.Join(deployOut, m.Name)
+		if err := os.WriteFile(path, m.Data, mode); err != nil {
+			return fmt.Errorf("writing %s: %w", m.Name, err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	}
+	return nil
+}
+
+// prepareDeploy validates the shared deploy flags, makes sure the build
+// output is current and returns the template data and resolved egress
+// config every deploy target renders from.
+func prepareDeploy(cfg *types.ForgeConfig, workDir string) (*compiler.TemplateSpecData, *security.EgressConfig, error) {
+	if deployReplicas < 1 {
+		return nil, nil, fmt.Errorf("--replicas must be at least 1")
+	}
+	if deployShutdownTimeout < 0 {
+		return nil, nil, fmt.Errorf("--shutdown-timeout must not be negative")
+	}
+	cfgPath := filepath.Join(workDir, filepath.Base(cfgFile))
+
+	outDir := outputDir
+	if outDir == "." {
+		outDir = filepath.Join(workDir, ".forge-output")
+	}
+	if !deploySkipBuild {
+		if err := ensureBuildOutput(outDir, cfgPath); err != nil {
+			return nil, nil, err
+		}
+	}
+	agentData, err := os.ReadFile(filepath.Join(outDir, "agent.json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading agent.json: %w; run 'forge build' first or remove --skip-build", err)
+	}
+	var spec agentspec.AgentSpec
+	if err := json.Unmarshal(agentData, &spec); err != nil {
+		return nil, nil, fmt.Errorf("parsing agent.json: %w", err)
+	}
+	if spec.Runtime == nil {
+		return nil, nil, fmt.Errorf("agent.json has no runtime section")
+	}
+
+	// Always resolve egress, even without an egress block: the runtime
+	// enforces the default profile then too, and the NetworkPolicy must
+	// match what the agent is allowed to reach.
+	var toolNames []string
+	for _, t := range spec.Tools {
+		toolNames = append(toolNames, t.Name)
+	}
+	egressCfg, err := build.ResolveEgress(cfg, toolNames)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving egress: %w", err)
+	}
+
+	data := compiler.BuildTemplateDataFromSpec(&spec)
+	data.EgressProfile = string(egressCfg.Profile)
+	data.EgressMode = string(egressCfg.Mode)
+	data.RequiredEnvVars, data.OptionalEnvVars = deploySecretKeys(cfg, spec.Requirements)
+
+	data.Image = deployImage
+	if data.Image == "" {
+		reg := deployRegistry
+		if reg == "" {
+			reg = cfg.Registry
+		}
+		data.Image = computeImageTag(cfg.AgentID, cfg.Version, reg)
+	}
+	data.Replicas = deployReplicas
+	if data.Runtime.ForgeRun && deployShutdownTimeout > 0 {
+		data.ShutdownTimeout = deployShutdownTimeout.String()
+		data.TerminationGracePeriodSeconds = int((deployShutdownTimeout + 5*time.Second).Seconds())
+	}
+
+	if deployReplicas > 1 && cfg.Cluster.LockURL == "" {
+		fmt.Fprintln(os.Stderr, "Warning: --replicas > 1 without cluster.lock_url; every replica runs the schedules and a task may run on two replicas at once (see docs/deployment/multi-replica.md)")
+	}
+	return data, egressCfg, nil
+}
+
+// deploySecretKeys returns the env vars the agent's Secret carries:
+// skill and channel requirements from the build, plus the model
+// providers' API keys. The primary provider's key is required; fallback
+// and route providers' keys are optional, since calls fall back to the
+// primary without them.
+func deploySecretKeys(cfg *types.ForgeConfig, reqs *agentspec.AgentRequirements) (required, optional []string) {
+	seen := map[string]bool{}
+	add := func(list *[]string, key string) {
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		*list = append(*list, key)
+	}
+
+	if cfg.Model.Provider != "" {
+		add(&required, coreruntime.ProviderAPIKeyEnv(cfg.Model.Provider))
+	}
+	if reqs != nil {
+		for _, key := range reqs.EnvRequired {
+			add(&required, key)
+		}
+	}
+	for _, fb := range cfg.Model.Fallbacks {
+		if fb.Provider != "" {
+			add(&optional, coreruntime.ProviderAPIKeyEnv(fb.Provider))
+		}
+	}
+	for _, route := range cfg.Model.Routes {
+		if route.Provider != "" {
+			add(&optional, coreruntime.ProviderAPIKeyEnv(route.Provider))
+		}
+	}
+	for _, exp := range cfg.Experiments {
+		for _, v := range exp.Variants {
+			if v.Model != nil && v.Model.Provider != "" {
+				add(&optional, coreruntime.ProviderAPIKeyEnv(v.Model.Provider))
+			}
+		}
+	}
+	if cfg.Observability.Langfuse.Enabled {
+		add(&optional, "LANGFUSE_PUBLIC_KEY")
+		add(&optional, "LANGFUSE_SECRET_KEY")
+	}
+	if cfg.Observability.LangSmith.Enabled {
+		add(&optional, "LANGSMITH_API_KEY")
+	}
+	if reqs != nil {
+		for _, key := range reqs.EnvOptional {
+			add(&optional, key)
+		}
+	}
+	return required, optional
+}
+
+// writeManifestStream writes manifests as one multi-document YAML stream.
+func writeManifestStream(w io.Writer, manifests []build.K8sManifest) error {
+	for i, m := range manifests {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# %s\n", m.Name); err != nil {
+			return err
+		}
+		data := m.Data
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			data = append(data, '\n')
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
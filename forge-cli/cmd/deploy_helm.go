@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	clichannels "github.com/initializ/forge/forge-cli/channels"
+	"github.com/initializ/forge/forge-cli/templates"
+	"github.com/initializ/forge/forge-core/compiler"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/spf13/cobra"
+)
+
+var deployHelmOut string
+
+var deployHelmCmd = &cobra.Command{
+	Use:   "helm",
+	Short: "Generate a Helm chart for the agent",
+	Long: `Generate a Helm chart for the agent in <out>/<agent_id>: Chart.yaml, a
+values.yaml derived from forge.yaml, and templates for the Deployment,
+Service, Secret, NetworkPolicy, HorizontalPodAutoscaler and Ingress.
+
+values.yaml carries the image, replicas, resources, probes, autoscaling and
+ingress settings and the names of the secrets the agent needs — never their
+values — so the chart can be committed to a GitOps repository. Set secret
+values at install time or point secret.existingSecret at a Secret managed
+elsewhere.`,
+	Args: cobra.NoArgs,
+	RunE: runDeployHelm,
+}
+
+func init() {
+	deployHelmCmd.Flags().StringVar(&deployHelmOut, "out", "charts", "directory the chart directory is created in")
+	addDeployFlags(deployHelmCmd)
+	deployCmd.AddCommand(deployHelmCmd)
+}
+
+func runDeployHelm(cmd *cobra.Command, args []string) error {
+	cfg, workDir, err := loadAndPrepareConfig(deployEnvFile)
+	if err != nil {
+		return err
+	}
+	data, egressCfg, err := prepareDeploy(cfg, workDir)
+	if err != nil {
+		return err
+	}
+
+	// Webhook-mode channel listeners bind to loopback, so no Service or
+	// Ingress can reach them; say so instead of emitting a dead route.
+	for _, ch := range cfg.Channels {
+		chCfg, loadErr := clichannels.LoadChannelConfig(filepath.Join(workDir, ch+"-config.yaml"))
+		if loadErr == nil && chCfg.Settings["mode"] == "webhook" {
+			fmt.Fprintf(os.Stderr, "Warning: channel %s uses webhook mode, whose listener binds to 127.0.0.1 and cannot be exposed through the Ingress; use mode: polling in the cluster\n", ch)
+		}
+	}
+
+	files, err := renderHelmChart(data, egressCfg)
+	if err != nil {
+		return err
+	}
+
+	chartDir := filepath.Join(deployHelmOut, data.AgentID)
+	for name, content := range files {
+		p := filepath.Join(chartDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", filepath.Dir(p), err)
+		}
+		if err := os.WriteFile(p, content, 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", p, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Wrote Helm chart to %s\n", chartDir)
+	return nil
+}
+
+// helmValuesData is the data for the chart's Chart.yaml and values.yaml
+// templates.
+type helmValuesData struct {
+	*compiler.TemplateSpecData
+	ImageRepository string
+	ImageTag        string // empty when it equals the agent version
+	Args            []string
+}
+
+// renderHelmChart returns the chart's files keyed by slash-separated
+// path relative to the chart directory. Chart.yaml and values.yaml are
+// rendered from data; the chart templates are copied verbatim, and the
+// NetworkPolicy is generated from egressCfg like `forge deploy k8s`.
+func renderHelmChart(data *compiler.TemplateSpecData, egressCfg *security.EgressConfig) (map[string][]byte, error) {
+	vd := helmValuesData{TemplateSpecData: data}
+	vd.ImageRepository, vd.ImageTag = splitImageRef(data.Image)
+	if vd.ImageTag == data.Version {
+		vd.ImageTag = ""
+	}
+	if data.ShutdownTimeout != "" {
+		vd.Args = []string{"--shutdown-timeout", data.ShutdownTimeout}
+	}
+
+	files := map[string][]byte{}
+	for _, name := range []string{"Chart.yaml", "values.yaml"} {
+		raw, err := templates.FS.ReadFile("helm/" + name + ".tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("reading template %s: %w", name, err)
+		}
+		tmpl, err := template.New(name).Parse(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("parsing template %s: %w", name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, vd); err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", name, err)
+		}
+		files[name] = buf.Bytes()
+	}
+
+	err := fs.WalkDir(templates.FS, "helm/templates", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := templates.FS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files["templates/"+path.Base(p)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading chart templates: %w", err)
+	}
+
+	policy, err := security.GenerateK8sNetworkPolicy(data.AgentID, egressCfg)
+	if err != nil {
+		return nil, fmt.Errorf("generating network policy from egress config: %w", err)
+	}
+	files["templates/networkpolicy.yaml"] = []byte("{{- if .Values.networkPolicy.enabled }}\n" + string(policy) + "\n{{- end }}\n")
+
+	return files, nil
+}
+
+// splitImageRef splits "registry/name:tag" into repository and tag. A
+// colon inside the registry host (a port) is not a tag separator.
+func splitImageRef(ref string) (repository, tag string) {
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.Contains(ref[i+1:], "/") {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/compiler"
+	"github.com/initializ/forge/forge-core/security"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderHelmChart(t *testing.T) {
+	data := compiler.BuildTemplateDataFromSpec(&agentspec.AgentSpec{
+		AgentID: "support-bot",
+		Version: "1.2.0",
+		Runtime: &agentspec.RuntimeConfig{
+			Entrypoint: []string{"forge", "run", "--host", "0.0.0.0", "--allow-remote"},
+			Port:       8080,
+			Env:        map[string]string{"LOG_LEVEL": "info"},
+		},
+	})
+	data.Image = "registry.local:5000/acme/support-bot:1.2.0"
+	data.Replicas = 2
+	data.ShutdownTimeout = "30s"
+	data.TerminationGracePeriodSeconds = 35
+	data.RequiredEnvVars = []string{"OPENAI_API_KEY"}
+	egressCfg, err := security.Resolve("strict", "allowlist", []string{"api.openai.com"}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := renderHelmChart(data, egressCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		"Chart.yaml", "values.yaml", "templates/_helpers.tpl", "templates/deployment.yaml",
+		"templates/service.yaml", "templates/secret.yaml", "templates/hpa.yaml",
+		"templates/ingress.yaml", "templates/networkpolicy.yaml",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("chart missing %s", name)
+		}
+	}
+
+	var chart map[string]any
+	if err := yaml.Unmarshal(files["Chart.yaml"], &chart); err != nil {
+		t.Fatalf("Chart.yaml: %v", err)
+	}
+	if chart["name"] != "support-bot" || chart["appVersion"] != "1.2.0" {
+		t.Errorf("Chart.yaml = %v", chart)
+	}
+
+	var values struct {
+		ReplicaCount int `yaml:"replicaCount"`
+		Image        struct {
+			Repository string `yaml:"repository"`
+			Tag        string `yaml:"tag"`
+		} `yaml:"image"`
+		Args                          []string          `yaml:"args"`
+		TerminationGracePeriodSeconds int               `yaml:"terminationGracePeriodSeconds"`
+		Env                           map[string]string `yaml:"env"`
+		Secret                        struct {
+			Required []string `yaml:"required"`
+			Optional []string `yaml:"optional"`
+		} `yaml:"secret"`
+		ReadinessProbe map[string]any `yaml:"readinessProbe"`
+	}
+	if err := yaml.Unmarshal(files["values.yaml"], &values); err != nil {
+		t.Fatalf("values.yaml: %v\n%s", err, files["values.yaml"])
+	}
+	if values.ReplicaCount != 2 || values.Image.Repository != "registry.local:5000/acme/support-bot" || values.Image.Tag != "" {
+		t.Errorf("values replicas/image = %d %q %q", values.ReplicaCount, values.Image.Repository, values.Image.Tag)
+	}
+	if strings.Join(values.Args, " ") != "--shutdown-timeout 30s" || values.TerminationGracePeriodSeconds != 35 {
+		t.Errorf("values args/grace = %v %d", values.Args, values.TerminationGracePeriodSeconds)
+	}
+	if values.Env["LOG_LEVEL"] != "info" || len(values.Secret.Required) != 1 || len(values.Secret.Optional) != 0 {
+		t.Errorf("values env/secret = %v %+v", values.Env, values.Secret)
+	}
+	if _, ok := values.ReadinessProbe["httpGet"]; !ok {
+		t.Errorf("readinessProbe = %v, want httpGet /health", values.ReadinessProbe)
+	}
+
+	np := string(files["templates/networkpolicy.yaml"])
+	if !strings.HasPrefix(np, "{{- if .Values.networkPolicy.enabled }}") || !strings.Contains(np, "api.openai.com") {
+		t.Errorf("networkpolicy.yaml = %s", np)
+	}
+}
+
+func TestSplitImageRef(t *testing.T) {
+	for _, tc := range []struct{ ref, repo, tag string }{
+		{"agent:0.1.0", "agent", "0.1.0"},
+		{"ghcr.io/acme/agent:v2", "ghcr.io/acme/agent", "v2"},
+		{"localhost:5000/agent", "localhost:5000/agent", ""},
+		{"agent", "agent", ""},
+	} {
+		repo, tag := splitImageRef(tc.ref)
+		if repo != tc.repo || tag != tc.tag {
+			t.Errorf("splitImageRef(%q) = %q, %q; want %q, %q", tc.ref, repo, tag, tc.repo, tc.tag)
+		}
+	}
+}
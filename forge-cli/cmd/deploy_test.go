@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/initializ/forge/forge-cli/build"
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestDeploySecretKeys(t *testing.T) {
+	cfg := &types.ForgeConfig{
+		Model: types.ModelRef{
+			Provider:  "openai",
+			Fallbacks: []types.ModelFallback{{Provider: "anthropic"}, {Provider: "ollama"}},
+			Routes:    []types.ModelRoute{{Provider: "gemini", Name: "gemini-2.5-flash"}, {Name: "gpt-4o-mini"}},
+		},
+	}
+	reqs := &agentspec.AgentRequirements{
+		EnvRequired: []string{"SLACK_BOT_TOKEN", "OPENAI_API_KEY"},
+		EnvOptional: []string{"TAVILY_API_KEY", "ANTHROPIC_API_KEY"},
+	}
+
+	required, optional := deploySecretKeys(cfg, reqs)
+	if want := []string{"OPENAI_API_KEY", "SLACK_BOT_TOKEN"}; !reflect.DeepEqual(required, want) {
+		t.Errorf("required = %v, want %v", required, want)
+	}
+	if want := []string{"ANTHROPIC_API_KEY", "GEMINI_API_KEY", "TAVILY_API_KEY"}; !reflect.DeepEqual(optional, want) {
+		t.Errorf("optional = %v, want %v", optional, want)
+	}
+}
+
+func TestWriteManifestStream(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeManifestStream(&buf, []build.K8sManifest{
+		{Name: "a.yaml", Data: []byte("kind: A\n")},
+		{Name: "b.yaml", Data: []byte("kind: B")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# a.yaml\nkind: A\n---\n# b.yaml\nkind: B\n"
+	if buf.String() != want {
+		t.Errorf("stream = %q, want %q", buf.String(), want)
+	}
+}
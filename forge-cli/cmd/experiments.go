@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/internal/experiments"
+)
+
+var experimentsCmd = &cobra.Command{
+	Use:   "experiments",
+	Short: "Inspect A/B prompt and model experiments",
+}
+
+var experimentsReportCmd = &cobra.Command{
+	Use:   "report <audit.ndjson>",
+	Short: "Compare experiment variants from an audit log",
+	Long: `Reads an NDJSON audit log and compares the variants of the experiments
+declared in forge.yaml. Every audit event of a task in an experiment carries
+its "<experiment>/<variant>", so the report splits by variant:
+
+  - tasks and turns served
+  - completed and failed turns, and the success rate
+  - p50/p95 turn latency
+  - LLM calls, tokens and estimated cost (list prices), per turn
+
+Use "-" as the file path to read from stdin. A running agent's /info usage
+section carries the same per-variant token and cost totals live.`,
+	Example: `  forge experiments report audit.ndjson
+  forge experiments report audit.ndjson --experiment tone --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: experimentsReportRun,
+}
+
+var (
+	experimentsReportID   string
+	experimentsReportJSON bool
+)
+
+func init() {
+	experimentsReportCmd.Flags().StringVar(&experimentsReportID, "experiment", "", "report only this experiment")
+	experimentsReportCmd.Flags().BoolVar(&experimentsReportJSON, "json", false, "print the report as JSON")
+	experimentsCmd.AddCommand(experimentsReportCmd)
+}
+
+func experimentsReportRun(cmd *cobra.Command, args []string) error {
+	var reader io.Reader = os.Stdin
+	if path := args[0]; path != "-" {
+		f, err := os.Open(path) //nolint:gosec // operator-supplied path is the intended surface
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer func() { _ = f.Close() }()
+		reader = f
+	}
+
+	variants, err := experiments.Report(reader, experimentsReportID)
+	if err != nil {
+		return err
+	}
+	if experimentsReportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(variants)
+	}
+	if len(variants) == 0 {
+		fmt.Println("No experiment traffic in the audit log.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "EXPERIMENT\tVARIANT\tTASKS\tTURNS\tSUCCESS\tP50\tP95\tTOKENS/TURN\tCOST/TURN\n")
+	for _, v := range variants {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f%%\t%.2fs\t%.2fs\t%.0f\t$%.4f\n",
+			v.Experiment, v.Variant, v.Tasks, v.Turns, v.SuccessRate*100,
+			v.LatencyP50, v.LatencyP95, v.TokensPerTurn, v.CostPerTurn)
+	}
+	return w.Flush()
+}
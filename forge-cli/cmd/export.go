@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 
 	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/internal/bundle"
 	"github.com/initializ/forge/forge-core/agentspec"
 	"github.com/initializ/forge/forge-core/export"
+	"github.com/initializ/forge/forge-core/types"
 	"github.com/initializ/forge/forge-core/validate"
 	"github.com/spf13/cobra"
 )
@@ -19,13 +21,24 @@ var (
 	exportIncludeSchemas bool
 	exportSimulateImport bool
 	exportDevMode        bool
+	exportBundle         bool
+	exportIncludeMemory  bool
+	exportSigningKey     string
 )
 
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export the agent specification for Command platform import",
-	Long:  "Export produces a standalone AgentSpec JSON file with metadata for importing into the Command platform.",
-	RunE:  runExport,
+	Long: `Export produces a standalone AgentSpec JSON file with metadata for importing into the Command platform.
+
+With --bundle it instead packs the whole agent (forge.yaml, skills, tools,
+prompts, schedules and, with --include-memory, its memory) into a signed
+tarball that 'forge import' verifies and unpacks elsewhere. Secrets and
+.env files are never bundled. The bundle is signed with --signing-key or
+~/.forge/signing-key.pem when it exists.`,
+	Example: `  forge export --pretty
+  forge export --bundle --include-memory --output support-bot.forge.tar.gz`,
+	RunE: runExport,
 }
 
 func init() {
@@ -34,6 +47,9 @@ func init() {
 	exportCmd.Flags().BoolVar(&exportIncludeSchemas, "include-schemas", false, "embed tool schemas inline from build output")
 	exportCmd.Flags().BoolVar(&exportSimulateImport, "simulate-import", false, "print simulated Command import result to stdout")
 	exportCmd.Flags().BoolVar(&exportDevMode, "dev", false, "include dev-category tools in export")
+	exportCmd.Flags().BoolVar(&exportBundle, "bundle", false, "export a signed agent bundle for forge import (default output: {agent_id}-{version}.forge.tar.gz)")
+	exportCmd.Flags().BoolVar(&exportIncludeMemory, "include-memory", false, "with --bundle, include a snapshot of .forge/memory")
+	exportCmd.Flags().StringVar(&exportSigningKey, "signing-key", "", "with --bundle, Ed25519 key to sign with (default: ~/.forge/signing-key.pem if present)")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
@@ -52,6 +68,9 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	if exportBundle {
+		return exportAgentBundle(filepath.Dir(cfgPath), cfg)
+	}
 
 	// 3. Determine output dir and ensure build output exists
 	outDir := outputDir
@@ -179,6 +198,69 @@ func runExport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// defaultSigningKeyPath returns ~/.forge/signing-key.pem, written by
+// `forge key generate`, or "" when it does not exist.
+func defaultSigningKeyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	p := filepath.Join(home, ".forge", "signing-key.pem")
+	if _, err := os.Stat(p); err != nil {
+		return ""
+	}
+	return p
+}
+
+// exportAgentBundle writes the signed bundle forge import reads.
+func exportAgentBundle(agentDir string, cfg *types.ForgeConfig) error {
+	opts := bundle.Options{
+		AgentID:       cfg.AgentID,
+		AgentVersion:  cfg.Version,
+		ForgeVersion:  appVersion,
+		IncludeMemory: exportIncludeMemory,
+	}
+	keyPath := exportSigningKey
+	if keyPath == "" {
+		keyPath = defaultSigningKeyPath()
+	}
+	if keyPath != "" {
+		key, keyID, err := bundle.LoadSigningKey(keyPath)
+		if err != nil {
+			return fmt.Errorf("loading signing key: %w", err)
+		}
+		opts.Key, opts.KeyID = key, keyID
+	}
+
+	outFile := exportOutput
+	if outFile == "" {
+		outFile = fmt.Sprintf("%s-%s.forge.tar.gz", cfg.AgentID, cfg.Version)
+		if cfg.Version == "" {
+			outFile = cfg.AgentID + ".forge.tar.gz"
+		}
+	}
+	f, err := os.Create(outFile)
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	m, err := bundle.Create(f, agentDir, opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(outFile)
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+
+	fmt.Printf("Exported bundle: %s (%d files)\n", outFile, len(m.Files))
+	if m.Signature == "" {
+		fmt.Fprintln(os.Stderr, "WARNING: bundle is unsigned; create a key with 'forge key generate' or import with --allow-unsigned")
+	} else {
+		fmt.Printf("Signed with key: %s\n", m.KeyID)
+	}
+	return nil
+}
+
 // embedToolSchemas reads tool schema files from .forge-output/tools/ and
 // merges them into the spec's tool InputSchema fields.
 func embedToolSchemas(outDir string, spec *agentspec.AgentSpec) error {
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/internal/bundle"
+	"github.com/initializ/forge/forge-cli/templates"
+	"github.com/initializ/forge/forge-core/validate"
+)
+
+var (
+	importDir           string
+	importAllowUnsigned bool
+	importForce         bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Import an agent bundle made by forge export --bundle",
+	Long: `Import verifies an agent bundle and unpacks it into a new agent directory,
+./<agent_id> by default.
+
+The bundle must be signed by a key in ~/.forge/trusted-keys (add one with
+'forge key trust'), and every file must match the bundle's checksums.
+forge.yaml is validated before anything is written to the target.
+
+With --force an existing agent directory is updated in place: bundled
+files overwrite their copies, while files the bundle does not carry,
+such as .env and .forge/secrets.enc, are kept. This is how a bundle is
+promoted from one environment to the next.
+
+Use "-" to read the bundle from stdin.`,
+	Example: `  forge import support-bot-1.2.0.forge.tar.gz
+  forge import support-bot-1.2.0.forge.tar.gz --dir /srv/agents/support-bot --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importDir, "dir", "", "target directory (default: ./<agent_id>)")
+	importCmd.Flags().BoolVar(&importAllowUnsigned, "allow-unsigned", false, "accept a bundle without a signature")
+	importCmd.Flags().BoolVar(&importForce, "force", false, "update an existing directory in place")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	var reader io.Reader = os.Stdin
+	if path := args[0]; path != "-" {
+		f, err := os.Open(path) //nolint:gosec // operator-supplied path is the intended surface
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer func() { _ = f.Close() }()
+		reader = f
+	}
+
+	// Unpack next to the target so the final move is a rename.
+	stageParent := "."
+	if importDir != "" {
+		stageParent = filepath.Dir(filepath.Clean(importDir))
+	}
+	stage, err := os.MkdirTemp(stageParent, ".forge-import-*")
+	if err != nil {
+		return fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(stage) }()
+	if err := os.Chmod(stage, 0o755); err != nil { // MkdirTemp creates it 0700
+		return err
+	}
+
+	m, keyID, err := bundle.Extract(reader, stage, bundle.ExtractOptions{AllowUnsigned: importAllowUnsigned})
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadForgeConfig(filepath.Join(stage, "forge.yaml"))
+	if err != nil {
+		return fmt.Errorf("loading bundled forge.yaml: %w", err)
+	}
+	result := validate.ValidateForgeConfig(cfg)
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", w)
+	}
+	if !result.IsValid() {
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "ERROR: %s\n", e)
+		}
+		return fmt.Errorf("bundled forge.yaml is invalid: %d error(s)", len(result.Errors))
+	}
+
+	target := importDir
+	if target == "" {
+		if !filepath.IsLocal(m.AgentID) || filepath.Base(m.AgentID) != m.AgentID {
+			return fmt.Errorf("bundle agent_id %q is not a directory name; pass --dir", m.AgentID)
+		}
+		target = filepath.Join(".", m.AgentID)
+	}
+	if _, err := os.Stat(target); err == nil {
+		if !importForce {
+			return fmt.Errorf("directory %q already exists (use --force to update it in place)", target)
+		}
+		if err := mergeImport(stage, target, m); err != nil {
+			return err
+		}
+	} else if err := os.Rename(stage, target); err != nil {
+		return fmt.Errorf("moving agent into %s: %w", target, err)
+	}
+
+	if err := rescaffoldImport(target); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %s %s into %s (%d files)\n", m.AgentID, m.AgentVersion, target, len(m.Files))
+	if keyID != "" {
+		fmt.Printf("  Signed by: %s\n", keyID)
+	} else {
+		fmt.Fprintln(os.Stderr, "WARNING: bundle was not signed")
+	}
+	if m.Memory {
+		fmt.Println("  Memory:    snapshot included")
+	}
+	if _, err := os.Stat(filepath.Join(target, ".env")); os.IsNotExist(err) && slices.Contains(m.FileList(), ".env.example") {
+		fmt.Println("\nSecrets are not bundled. Copy .env.example to .env and fill it in, or use 'forge secret set'.")
+	}
+	return nil
+}
+
+// mergeImport moves the bundled files from stage over their copies in
+// target, leaving the rest of target alone.
+func mergeImport(stage, target string, m *bundle.Manifest) error {
+	for _, rel := range m.FileList() {
+		dst := filepath.Join(target, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(stage, filepath.FromSlash(rel)), dst); err != nil {
+			return fmt.Errorf("updating %s: %w", dst, err)
+		}
+	}
+	return nil
+}
+
+// rescaffoldImport recreates what forge init would have and a bundle
+// leaves out: the tools/ and skills/ directories and a .gitignore that
+// keeps secrets and runtime state out of version control.
+func rescaffoldImport(dir string) error {
+	for _, sub := range []string{"tools", "skills"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return fmt.Errorf("creating directory %s: %w", sub, err)
+		}
+	}
+	gitignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(gitignore); os.IsNotExist(err) {
+		content, err := templates.GetInitTemplate("gitignore.tmpl")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(gitignore, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("writing .gitignore: %w", err)
+		}
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
+	"github.com/initializ/forge/forge-cli/internal/templaterepo"
 	"github.com/initializ/forge/forge-cli/internal/tui"
 	"github.com/initializ/forge/forge-cli/internal/tui/steps"
 	"github.com/initializ/forge/forge-cli/skills"
@@ -55,6 +56,8 @@ type initOptions struct {
 	// scaffold() before the "Created …" banner and the auto-run of
 	// `forge run` — the caller drives the agent itself, in-process.
 	Preset bool
+	// Template is the `forge init --template` template applied, if any.
+	Template *initTemplate
 
 	// A2A auth chain (from the wizard's Authentication step or CLI flags).
 	AuthMode        string         // "", "none", "oidc", "http_verifier", "custom"
@@ -93,6 +96,11 @@ type templateData struct {
 	// would otherwise require non-trivial template helpers. Empty when
 	// the user picked "none" or skipped the auth step.
 	AuthBlock string
+
+	// Template preset rendering: the preset's forge.yaml fragment and its
+	// extra guardrails.json customRules, one JSON object each.
+	TemplateBlock  string
+	GuardrailRules []string
 }
 
 // fallbackTmplData holds template data for a fallback provider.
@@ -133,7 +141,7 @@ func init() {
 	initCmd.Flags().StringP("name", "n", "", "agent name")
 	initCmd.Flags().StringP("framework", "f", "", "framework: forge (default), crewai, or langchain")
 	initCmd.Flags().StringP("language", "l", "", "language for crewai/langchain entrypoint (python only)")
-	initCmd.Flags().StringP("model-provider", "m", "", "model provider: openai, anthropic, gemini, ollama, or custom")
+	initCmd.Flags().StringP("model-provider", "m", "", "model provider: openai, anthropic, gemini, mistral, cohere, groq, together, openrouter, xai, deepseek, ollama, or custom")
 	initCmd.Flags().StringSlice("channels", nil, "communication channels (e.g., slack,telegram)")
 	initCmd.Flags().String("from-skills", "", "path to SKILL.md file to parse for tools")
 	initCmd.Flags().Bool("non-interactive", false, "run without interactive prompts (requires all flags)")
@@ -144,6 +152,9 @@ func init() {
 	initCmd.Flags().String("org-id", "", "OpenAI organization ID (enterprise)")
 	initCmd.Flags().StringSlice("fallbacks", nil, "fallback LLM providers (e.g., openai,gemini)")
 	initCmd.Flags().Bool("force", false, "overwrite existing directory")
+	initCmd.Flags().String("template", "", initTemplateHelp())
+	initCmd.Flags().StringArray("template-var", nil, "custom template variable as name=value (repeatable)")
+	initCmd.Flags().Bool("template-allow-unsigned", false, "accept a custom template whose checksums.json is not signed")
 
 	// Auth chain (PR5+). All optional. When --auth is unset or "none", no
 	// auth: block is written and the agent runs anonymously.
@@ -203,6 +214,35 @@ func runInit(cmd *cobra.Command, args []string) error {
 	opts.NonInteractive = nonInteractive
 	opts.Force, _ = cmd.Flags().GetBool("force")
 
+	var preset *initTemplate
+	if name, _ := cmd.Flags().GetString("template"); name != "" && !templaterepo.IsSource(name) {
+		t, err := lookupInitTemplate(name)
+		if err != nil {
+			return err
+		}
+		preset = t
+	} else if name != "" {
+		varFlags, _ := cmd.Flags().GetStringArray("template-var")
+		vars, err := parseTemplateVars(varFlags)
+		if err != nil {
+			return err
+		}
+		allowUnsigned, _ := cmd.Flags().GetBool("template-allow-unsigned")
+		work, err := os.MkdirTemp("", "forge-template-*")
+		if err != nil {
+			return fmt.Errorf("creating template directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(work) }()
+		preset, err = loadCustomTemplate(cmd.Context(), name, work, customTemplateOptions{
+			Vars:          vars,
+			AllowUnsigned: allowUnsigned,
+			Interactive:   !nonInteractive && term.IsTerminal(int(os.Stdin.Fd())),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Auth chain flags.
 	authMode, _ := cmd.Flags().GetString("auth")
 	if authMode != "" {
@@ -229,6 +269,9 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if preset != nil {
+		applyInitTemplate(opts, preset)
+	}
 
 	// Derive agent ID
 	opts.AgentID = util.Slugify(opts.Name)
@@ -311,6 +354,10 @@ func collectInteractive(opts *initOptions) error {
 		return validateWebSearchKey(provider, key)
 	}
 
+	// Ollama: detect the daemon, offer to pull a model, check tool calling
+	providerStep := steps.NewProviderStep(styles, validateKeyFn, oauthFlowFn)
+	providerStep.SetOllama(ollamaWizardFuncs())
+
 	// Build step list.
 	//
 	// Auth comes BEFORE Egress so the operator's auth choice — and the
@@ -321,14 +368,14 @@ func collectInteractive(opts *initOptions) error {
 	// blocked by the very allowlist the wizard just rendered.
 	wizardSteps := []tui.Step{
 		steps.NewNameStep(styles, opts.Name),
-		steps.NewProviderStep(styles, validateKeyFn, oauthFlowFn),
+		providerStep,
 		steps.NewFallbackStep(styles, validateKeyFn),
 		steps.NewChannelStep(styles),
 		steps.NewWebSearchStep(styles, validateWebSearchKeyFn),
 		steps.NewSkillsStep(styles, skillInfos),
 		steps.NewCompressionStep(styles),
 		steps.NewAuthStep(styles),
-		steps.NewEgressStep(styles, deriveEgressFn),
+		steps.NewEgressStep(styles, deriveEgressFn, checkEgressDomains),
 		steps.NewReviewStep(styles), // scaffold is handled by the caller after collectInteractive returns
 	}
 
@@ -466,9 +513,9 @@ func collectNonInteractive(opts *initOptions) error {
 
 	// Validate model provider
 	switch opts.ModelProvider {
-	case "openai", "anthropic", "gemini", "ollama", "custom":
+	case "openai", "anthropic", "gemini", "mistral", "cohere", "groq", "together", "openrouter", "xai", "deepseek", "ollama", "custom":
 	default:
-		return fmt.Errorf("invalid model-provider %q: must be openai, anthropic, gemini, ollama, or custom", opts.ModelProvider)
+		return fmt.Errorf("invalid model-provider %q: must be openai, anthropic, gemini, mistral, cohere, groq, together, openrouter, xai, deepseek, ollama, or custom", opts.ModelProvider)
 	}
 
 	// Validate API key if provided
@@ -532,6 +579,20 @@ func storeProviderEnvVar(opts *initOptions) {
 		opts.EnvVars["ANTHROPIC_API_KEY"] = opts.APIKey
 	case "gemini":
 		opts.EnvVars["GEMINI_API_KEY"] = opts.APIKey
+	case "mistral":
+		opts.EnvVars["MISTRAL_API_KEY"] = opts.APIKey
+	case "cohere":
+		opts.EnvVars["COHERE_API_KEY"] = opts.APIKey
+	case "groq":
+		opts.EnvVars["GROQ_API_KEY"] = opts.APIKey
+	case "together":
+		opts.EnvVars["TOGETHER_API_KEY"] = opts.APIKey
+	case "openrouter":
+		opts.EnvVars["OPENROUTER_API_KEY"] = opts.APIKey
+	case "xai":
+		opts.EnvVars["XAI_API_KEY"] = opts.APIKey
+	case "deepseek":
+		opts.EnvVars["DEEPSEEK_API_KEY"] = opts.APIKey
 	}
 }
 
@@ -723,6 +784,16 @@ func scaffold(opts *initOptions) error {
 	}
 
 	data := buildTemplateData(opts)
+	if t := opts.Template; t != nil {
+		var err error
+		if data.TemplateBlock, err = t.forgeYAML(opts); err != nil {
+			return fmt.Errorf("reading template %s: %w", t.Name, err)
+		}
+		data.TemplateBlock = strings.TrimSpace(data.TemplateBlock)
+		if data.GuardrailRules, err = renderTemplateRules(t); err != nil {
+			return fmt.Errorf("rendering template %s guardrails: %w", t.Name, err)
+		}
+	}
 	manifest := getFileManifest(opts)
 
 	for _, f := range manifest {
@@ -757,6 +828,12 @@ func scaffold(opts *initOptions) error {
 		_ = out.Close()
 	}
 
+	if t := opts.Template; t != nil {
+		if err := t.writeFiles(dir, opts); err != nil {
+			return fmt.Errorf("writing template %s files: %w", t.Name, err)
+		}
+	}
+
 	// Split env vars into secrets and config
 	secretVars, configVars := splitEnvVars(data.EnvVars)
 
@@ -767,7 +844,7 @@ func scaffold(opts *initOptions) error {
 
 	// Write secrets to encrypted file
 	if len(secretVars) > 0 {
-		storedKeys, sErr := writeSecrets(dir, secretVars, opts.NonInteractive)
+		storedKeys, sErr := writeSecrets(dir, opts.AgentID, secretVars, opts.NonInteractive)
 		if sErr != nil {
 			fmt.Printf("  Warning: could not encrypt secrets: %s\n", sErr)
 			fmt.Println("  Secrets will be stored in plaintext .env file instead.")
@@ -834,6 +911,9 @@ func scaffold(opts *initOptions) error {
 	}
 
 	fmt.Printf("\nCreated agent project in ./%s\n", opts.AgentID)
+	if opts.Template != nil {
+		fmt.Printf("  From template %s: edit prompts/system.tmpl and the schedules in forge.yaml to fit.\n", opts.Template.Name)
+	}
 
 	// Show channel-specific reminders
 	for _, ch := range opts.Channels {
@@ -972,10 +1052,10 @@ func resolvePassphraseForInit(nonInteractive bool) (string, error) {
 }
 
 // writeSecrets encrypts the given secret env vars into <dir>/.forge/secrets.enc
-// (agent-local) and ensures the global ~/.forge/secrets.enc exists as a marker
+// (agent-local, bound to the agent's encryption context) and ensures the global ~/.forge/secrets.enc exists as a marker
 // for passphrase validation on subsequent inits.
 // Returns the list of stored key names on success.
-func writeSecrets(dir string, secretVars []envVarEntry, nonInteractive bool) ([]string, error) {
+func writeSecrets(dir, agentID string, secretVars []envVarEntry, nonInteractive bool) ([]string, error) {
 	passphrase, err := resolvePassphraseForInit(nonInteractive)
 	if err != nil {
 		return nil, err
@@ -986,6 +1066,7 @@ func writeSecrets(dir string, secretVars []envVarEntry, nonInteractive bool) ([]
 	// Write secrets to agent-local file.
 	encPath := filepath.Join(dir, ".forge", "secrets.enc")
 	provider := secrets.NewEncryptedFileProvider(encPath, passCb)
+	provider.SetContext(secrets.AgentContext(agentID, ""))
 
 	pairs := make(map[string]string, len(secretVars))
 	keys := make([]string, 0, len(secretVars))
@@ -1224,6 +1305,20 @@ func defaultModelNameForProvider(provider string) string {
 		return "claude-sonnet-4-20250514"
 	case "gemini":
 		return "gemini-2.5-flash"
+	case "mistral":
+		return "mistral-large-latest"
+	case "cohere":
+		return "command-r-plus"
+	case "groq":
+		return "llama-3.3-70b-versatile"
+	case "together":
+		return "meta-llama/Llama-3.3-70B-Instruct-Turbo"
+	case "openrouter":
+		return "openai/gpt-4o"
+	case "xai":
+		return "grok-4"
+	case "deepseek":
+		return "deepseek-chat"
 	case "ollama":
 		return "llama3"
 	default:
@@ -1274,6 +1369,48 @@ func buildEnvVars(opts *initOptions) []envVarEntry {
 			val = "your-api-key-here"
 		}
 		vars = append(vars, envVarEntry{Key: "GEMINI_API_KEY", Value: val, Comment: "Gemini API key"})
+	case "mistral":
+		val := opts.EnvVars["MISTRAL_API_KEY"]
+		if val == "" {
+			val = "your-api-key-here"
+		}
+		vars = append(vars, envVarEntry{Key: "MISTRAL_API_KEY", Value: val, Comment: "Mistral API key"})
+	case "cohere":
+		val := opts.EnvVars["COHERE_API_KEY"]
+		if val == "" {
+			val = "your-api-key-here"
+		}
+		vars = append(vars, envVarEntry{Key: "COHERE_API_KEY", Value: val, Comment: "Cohere API key"})
+	case "groq":
+		val := opts.EnvVars["GROQ_API_KEY"]
+		if val == "" {
+			val = "your-api-key-here"
+		}
+		vars = append(vars, envVarEntry{Key: "GROQ_API_KEY", Value: val, Comment: "Groq API key"})
+	case "together":
+		val := opts.EnvVars["TOGETHER_API_KEY"]
+		if val == "" {
+			val = "your-api-key-here"
+		}
+		vars = append(vars, envVarEntry{Key: "TOGETHER_API_KEY", Value: val, Comment: "Together AI API key"})
+	case "openrouter":
+		val := opts.EnvVars["OPENROUTER_API_KEY"]
+		if val == "" {
+			val = "your-api-key-here"
+		}
+		vars = append(vars, envVarEntry{Key: "OPENROUTER_API_KEY", Value: val, Comment: "OpenRouter API key"})
+	case "xai":
+		val := opts.EnvVars["XAI_API_KEY"]
+		if val == "" {
+			val = "your-api-key-here"
+		}
+		vars = append(vars, envVarEntry{Key: "XAI_API_KEY", Value: val, Comment: "xAI API key"})
+	case "deepseek":
+		val := opts.EnvVars["DEEPSEEK_API_KEY"]
+		if val == "" {
+			val = "your-api-key-here"
+		}
+		vars = append(vars, envVarEntry{Key: "DEEPSEEK_API_KEY", Value: val, Comment: "DeepSeek API key"})
 	case "ollama":
 		vars = append(vars, envVarEntry{Key: "OLLAMA_HOST", Value: "http://localhost:11434", Comment: "Ollama host"})
 	}
@@ -1343,9 +1480,16 @@ func buildEnvVars(opts *initOptions) []envVarEntry {
 
 	// Fallback provider env vars
 	fallbackKeyMap := map[string]string{
-		"openai":    "OPENAI_API_KEY",
-		"anthropic": "ANTHROPIC_API_KEY",
-		"gemini":    "GEMINI_API_KEY",
+		"openai":     "OPENAI_API_KEY",
+		"anthropic":  "ANTHROPIC_API_KEY",
+		"gemini":     "GEMINI_API_KEY",
+		"mistral":    "MISTRAL_API_KEY",
+		"cohere":     "COHERE_API_KEY",
+		"groq":       "GROQ_API_KEY",
+		"together":   "TOGETHER_API_KEY",
+		"openrouter": "OPENROUTER_API_KEY",
+		"xai":        "XAI_API_KEY",
+		"deepseek":   "DEEPSEEK_API_KEY",
 	}
 	for _, fb := range opts.Fallbacks {
 		envKey, ok := fallbackKeyMap[fb.Provider]
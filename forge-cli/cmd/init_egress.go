@@ -1,17 +1,30 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/initializ/forge/forge-cli/internal/tui/components"
 	"github.com/initializ/forge/forge-core/security"
 	"github.com/initializ/forge/forge-skills/contract"
 )
 
 // providerDomains maps model provider names to their API domains.
 var providerDomains = map[string]string{
-	"openai":    "api.openai.com",
-	"anthropic": "api.anthropic.com",
-	"gemini":    "generativelanguage.googleapis.com",
+	"openai":     "api.openai.com",
+	"anthropic":  "api.anthropic.com",
+	"gemini":     "generativelanguage.googleapis.com",
+	"cohere":     "api.cohere.com",
+	"mistral":    "api.mistral.ai",
+	"groq":       "api.groq.com",
+	"together":   "api.together.xyz",
+	"openrouter": "openrouter.ai",
+	"xai":        "api.x.ai",
+	"deepseek":   "api.deepseek.com",
 	// ollama is local, no egress needed
 }
 
@@ -108,3 +121,56 @@ func deriveEgressDomains(opts *initOptions, skills []contract.SkillDescriptor) [
 	sort.Strings(domains)
 	return domains
 }
+
+// egressCheckTimeout bounds the wizard's DNS check and connectivity test,
+// so an offline laptop or a black-holed host never stalls the review.
+const egressCheckTimeout = 5 * time.Second
+
+// checkEgressDomains is the wizard's live check of the derived allowlist:
+// each domain is resolved and flagged if it will not match what the agent
+// calls, and with probe each resolvable host is also dialled over TLS.
+func checkEgressDomains(domains []string, probe bool) map[string]components.EgressCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), egressCheckTimeout)
+	defer cancel()
+
+	out := make(map[string]components.EgressCheck, len(domains))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range security.CheckEgressDomains(ctx, nil, domains) {
+		check := egressCheckFromDNS(c)
+		out[c.Domain] = check
+		if !probe || c.Err != nil || len(c.Addrs) == 0 || strings.HasPrefix(c.Domain, "*.") {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rtt, err := security.ProbeEgress(ctx, nil, c.Domain)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				out[c.Domain] = components.EgressCheck{Status: components.EgressError, Note: "unreachable: " + err.Error()}
+			case check.Status == components.EgressOK:
+				out[c.Domain] = components.EgressCheck{Status: components.EgressOK, Note: fmt.Sprintf("TLS ok · %s", rtt.Round(time.Millisecond))}
+			}
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+// egressCheckFromDNS renders a DNS verdict for the egress review.
+func egressCheckFromDNS(c security.DomainCheck) components.EgressCheck {
+	switch {
+	case c.Err != nil:
+		return components.EgressCheck{Status: components.EgressError, Note: c.Err.Error()}
+	case len(c.Warnings) > 0:
+		return components.EgressCheck{Status: components.EgressWarn, Note: strings.Join(c.Warnings, "; ")}
+	}
+	addrs := c.Addrs
+	if len(addrs) > 3 {
+		addrs = append(addrs[:3:3], "…")
+	}
+	return components.EgressCheck{Status: components.EgressOK, Note: strings.Join(addrs, ", ")}
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/initializ/forge/forge-cli/internal/templaterepo"
+	"github.com/initializ/forge/forge-cli/templates"
+	"github.com/initializ/forge/forge-skills/local"
+)
+
+// initTemplate is a curated agent for `forge init --template`: an
+// opinionated starting point that works out of the box instead of an
+// empty scaffold.
+//
+// Besides the fields below, an embedded template's forge.yaml fragment
+// (appended to the generated forge.yaml) and files/, copied verbatim into
+// the agent directory, live in templates/init/presets/<name>/. A custom
+// template carries them in its own directory; see templaterepo.
+type initTemplate struct {
+	Name         string
+	Description  string
+	Skills       []string // registry skills to vendor
+	BuiltinTools []string
+	// GuardrailRules are appended to guardrails.json's customRules.
+	GuardrailRules []guardrailRule
+
+	// custom is the fetched template for a repository or directory
+	// source, and vars its resolved variables; nil for embedded ones.
+	custom *templaterepo.Template
+	vars   map[string]string
+}
+
+// guardrailRule is a guardrails.json customRules entry.
+type guardrailRule = templaterepo.GuardrailRule
+
+var initTemplates = []initTemplate{
+	{
+		Name:         "sre-triage",
+		Description:  "read-only Kubernetes incident triage with a weekday morning health check",
+		Skills:       []string{"k8s-incident-triage"},
+		BuiltinTools: []string{"datetime_now"},
+		GuardrailRules: []guardrailRule{{
+			ID: "sre_mutating_kubectl", Name: "Mutating kubectl command", Type: "regex", Constraint: "hard",
+			Pattern: `kubectl\s+(delete|drain|cordon|uncordon|scale|patch|apply|replace|edit|exec|rollout\s+(restart|undo))\b`,
+			Action:  "block", Gates: []string{"tool_call"},
+		}},
+	},
+	{
+		Name:         "support-bot",
+		Description:  "customer support answering from a docs/ knowledge base, with a weekly FAQ review",
+		BuiltinTools: []string{"datetime_now"},
+		GuardrailRules: []guardrailRule{{
+			ID: "support_internal_only", Name: "Internal-only content", Type: "phrase", Constraint: "hard",
+			Keywords: []string{"INTERNAL ONLY", "CONFIDENTIAL"},
+			Action:   "block", Gates: []string{"output"},
+		}},
+	},
+	{
+		Name:         "data-analyst",
+		Description:  "analysis of CSV and JSON files under data/, with a weekly metrics report",
+		BuiltinTools: []string{"file_read", "csv_parse", "json_parse", "math_calculate", "datetime_now"},
+	},
+}
+
+// lookupInitTemplate returns the template called name.
+func lookupInitTemplate(name string) (*initTemplate, error) {
+	for i := range initTemplates {
+		if initTemplates[i].Name == name {
+			return &initTemplates[i], nil
+		}
+	}
+	names := make([]string, len(initTemplates))
+	for i, t := range initTemplates {
+		names[i] = t.Name
+	}
+	return nil, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// initTemplateHelp lists the templates for the --template flag.
+func initTemplateHelp() string {
+	var b strings.Builder
+	b.WriteString("agent template:")
+	for _, t := range initTemplates {
+		fmt.Fprintf(&b, "\n  %s: %s", t.Name, t.Description)
+	}
+	b.WriteString("\nor a custom template: host/owner/repo//dir[@ref] or a local directory")
+	return b.String()
+}
+
+// customTemplateOptions configures loadCustomTemplate.
+type customTemplateOptions struct {
+	Vars          map[string]string // --template-var values
+	AllowUnsigned bool
+	Interactive   bool // prompt for variables Vars does not set
+}
+
+// loadCustomTemplate fetches the template at source into work, verifies
+// it and resolves its variables. Templates from a repository must carry
+// checksums.json signed by a trusted key; a local directory is checked
+// only when it has one.
+func loadCustomTemplate(ctx context.Context, source, work string, opts customTemplateOptions) (*initTemplate, error) {
+	src, err := templaterepo.ParseSource(source)
+	if err != nil {
+		return nil, err
+	}
+	fetcher := &templaterepo.Fetcher{GitHubToken: os.Getenv("GITHUB_TOKEN")}
+	dir, err := fetcher.Fetch(ctx, src, work)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := templaterepo.Load(dir, templaterepo.LoadOptions{
+		RequireChecksums: src.Local == "",
+		AllowUnsigned:    opts.AllowUnsigned,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", src, err)
+	}
+
+	if len(ct.Skills) > 0 {
+		reg, err := local.NewEmbeddedRegistry()
+		if err != nil {
+			return nil, fmt.Errorf("loading skill registry: %w", err)
+		}
+		for _, name := range ct.Skills {
+			if reg.Get(name) == nil {
+				return nil, fmt.Errorf("template %s: skill %q not found in registry", src, name)
+			}
+		}
+	}
+
+	var ask func(templaterepo.Variable) (string, error)
+	if opts.Interactive {
+		reader := bufio.NewReader(os.Stdin)
+		ask = func(v templaterepo.Variable) (string, error) {
+			prompt := v.Prompt
+			if prompt == "" {
+				prompt = v.Name
+			}
+			if v.Default != "" {
+				prompt += " [" + v.Default + "]"
+			}
+			fmt.Printf("  %s: ", prompt)
+			val, err := reader.ReadString('\n')
+			if err != nil && val == "" {
+				return "", fmt.Errorf("reading template variable %s: %w", v.Name, err)
+			}
+			return strings.TrimSpace(val), nil
+		}
+	}
+	vars, err := ct.Resolve(opts.Vars, ask)
+	if err != nil {
+		if !opts.Interactive {
+			return nil, fmt.Errorf("%w (set variables with --template-var name=value)", err)
+		}
+		return nil, err
+	}
+
+	switch {
+	case ct.KeyID != "":
+		fmt.Printf("Using template %s from %s (signed by %s)\n", ct.Name, src, ct.KeyID)
+	case ct.Verified:
+		fmt.Fprintf(os.Stderr, "WARNING: template %s from %s is not signed\n", ct.Name, src)
+	default:
+		fmt.Fprintf(os.Stderr, "WARNING: template %s from %s has no %s; its files are not verified\n", ct.Name, src, templaterepo.ChecksumsName)
+	}
+
+	return &initTemplate{
+		Name:           ct.Name,
+		Description:    ct.Description,
+		Skills:         ct.Skills,
+		BuiltinTools:   ct.BuiltinTools,
+		GuardrailRules: ct.GuardrailRules,
+		custom:         ct,
+		vars:           vars,
+	}, nil
+}
+
+// parseTemplateVars parses --template-var name=value flags.
+func parseTemplateVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+	for _, f := range flags {
+		name, value, ok := strings.Cut(f, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --template-var %q: want name=value", f)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// applyInitTemplate merges t's skills and builtin tools into opts, which
+// the flags or the wizard have already filled in.
+func applyInitTemplate(opts *initOptions, t *initTemplate) {
+	opts.Template = t
+	opts.BuiltinTools = mergeBuiltinTools(opts.BuiltinTools, t.BuiltinTools)
+
+	var added []string
+	for _, s := range t.Skills {
+		if !slices.Contains(opts.Skills, s) {
+			added = append(added, s)
+		}
+	}
+	opts.Skills = append(opts.Skills, added...)
+	checkSkillRequirements(&initOptions{Skills: added, EnvVars: opts.EnvVars})
+
+	// The wizard's egress review replaces the derived allowlist; add what
+	// the template's skills and tools need to it.
+	if stored := opts.EnvVars["__egress_domains"]; stored != "" {
+		extra := deriveEgressDomains(&initOptions{ModelProvider: opts.ModelProvider, BuiltinTools: t.BuiltinTools, EnvVars: opts.EnvVars}, lookupSelectedSkills(t.Skills))
+		opts.EnvVars["__egress_domains"] = strings.Join(mergeEgressDomains(strings.Split(stored, ","), extra), ",")
+	}
+}
+
+// renderTemplateRules renders t's guardrail rules as JSON objects for
+// guardrails.json.tmpl.
+func renderTemplateRules(t *initTemplate) ([]string, error) {
+	rules := make([]string, 0, len(t.GuardrailRules))
+	for _, r := range t.GuardrailRules {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(r); err != nil {
+			return nil, err
+		}
+		rules = append(rules, strings.TrimSpace(buf.String()))
+	}
+	return rules, nil
+}
+
+// forgeYAML returns t's forge.yaml fragment.
+func (t *initTemplate) forgeYAML(opts *initOptions) (string, error) {
+	if t.custom != nil {
+		return t.custom.ForgeYAML(t.renderData(opts))
+	}
+	return templates.GetInitTemplate("presets/" + t.Name + "/forge.yaml")
+}
+
+// writeFiles copies t's files/ into dir.
+func (t *initTemplate) writeFiles(dir string, opts *initOptions) error {
+	if t.custom != nil {
+		return t.custom.WriteFiles(dir, t.renderData(opts))
+	}
+	return writeTemplateFiles(dir, t.Name)
+}
+
+func (t *initTemplate) renderData(opts *initOptions) templaterepo.RenderData {
+	return templaterepo.RenderData{Name: opts.Name, AgentID: opts.AgentID, Vars: t.vars}
+}
+
+// writeTemplateFiles copies embedded template name's files/ into dir.
+func writeTemplateFiles(dir, name string) error {
+	root := path.Join("init", "presets", name, "files")
+	return fs.WalkDir(templates.FS, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := templates.FS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		out := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(p, root+"/")))
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(out, data, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", out, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/templates"
+)
+
+func TestLookupInitTemplateUnknown(t *testing.T) {
+	_, err := lookupInitTemplate("nope")
+	if err == nil || !strings.Contains(err.Error(), "sre-triage") {
+		t.Fatalf("expected error listing the templates, got %v", err)
+	}
+}
+
+func TestScaffoldInitTemplates(t *testing.T) {
+	for _, tmpl := range initTemplates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			origDir, _ := os.Getwd()
+			if err := os.Chdir(tmpDir); err != nil {
+				t.Fatalf("chdir: %v", err)
+			}
+			defer func() { _ = os.Chdir(origDir) }()
+
+			opts := &initOptions{
+				Name:           "Template Agent",
+				AgentID:        "template-agent",
+				Framework:      "forge",
+				ModelProvider:  "openai",
+				EnvVars:        map[string]string{},
+				NonInteractive: true,
+			}
+			applyInitTemplate(opts, &tmpl)
+			if err := scaffold(opts); err != nil {
+				t.Fatalf("scaffold error: %v", err)
+			}
+
+			cfg, err := config.LoadForgeConfig(filepath.Join("template-agent", "forge.yaml"))
+			if err != nil {
+				t.Fatalf("LoadForgeConfig error: %v", err)
+			}
+			if len(cfg.Schedules) == 0 {
+				t.Error("expected the template's schedules in forge.yaml")
+			}
+			for _, bt := range tmpl.BuiltinTools {
+				if !slices.Contains(cfg.BuiltinTools, bt) {
+					t.Errorf("expected builtin tool %s in forge.yaml, got %v", bt, cfg.BuiltinTools)
+				}
+			}
+
+			raw, err := os.ReadFile(filepath.Join("template-agent", "guardrails.json"))
+			if err != nil {
+				t.Fatalf("reading guardrails.json: %v", err)
+			}
+			var g struct {
+				CustomRules struct {
+					Rules []guardrailRule `json:"rules"`
+				} `json:"customRules"`
+			}
+			if err := json.Unmarshal(raw, &g); err != nil {
+				t.Fatalf("guardrails.json is not valid JSON: %v", err)
+			}
+			for _, want := range tmpl.GuardrailRules {
+				if !slices.ContainsFunc(g.CustomRules.Rules, func(r guardrailRule) bool { return r.ID == want.ID }) {
+					t.Errorf("expected custom rule %s in guardrails.json", want.ID)
+				}
+			}
+
+			// The prompt template is copied, not rendered.
+			want, err := templates.GetInitTemplate("presets/" + tmpl.Name + "/files/prompts/system.tmpl")
+			if err != nil {
+				t.Fatalf("reading embedded prompt: %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join("template-agent", "prompts", "system.tmpl"))
+			if err != nil {
+				t.Fatalf("reading prompts/system.tmpl: %v", err)
+			}
+			if string(got) != want {
+				t.Error("prompts/system.tmpl differs from the template's")
+			}
+		})
+	}
+}
+
+func TestParseTemplateVars(t *testing.T) {
+	vars, err := parseTemplateVars([]string{"cluster=prod", "query=a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["cluster"] != "prod" || vars["query"] != "a=b" {
+		t.Errorf("vars = %v", vars)
+	}
+	if _, err := parseTemplateVars([]string{"cluster"}); err == nil {
+		t.Error("expected error for a value without =")
+	}
+}
+
+func TestScaffoldCustomTemplate(t *testing.T) {
+	tmplDir := t.TempDir()
+	for rel, content := range map[string]string{
+		"forge-template.yaml": "name: oncall\nbuiltin_tools: [datetime_now]\nvariables:\n  - name: cluster\n    required: true\nrender: [RUNBOOK.md]\n",
+		"forge.yaml":          "schedules:\n  - id: check-{{.Vars.cluster}}\n    cron: \"0 8 * * *\"\n    task: \"Check {{.Vars.cluster}}\"\n",
+		"files/RUNBOOK.md":    "# {{.Vars.cluster}}\n",
+	} {
+		p := filepath.Join(tmplDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmpl, err := loadCustomTemplate(context.Background(), tmplDir, t.TempDir(), customTemplateOptions{Vars: map[string]string{"cluster": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	opts := &initOptions{
+		Name:           "Oncall",
+		AgentID:        "oncall",
+		Framework:      "forge",
+		ModelProvider:  "openai",
+		EnvVars:        map[string]string{},
+		NonInteractive: true,
+	}
+	applyInitTemplate(opts, tmpl)
+	if err := scaffold(opts); err != nil {
+		t.Fatalf("scaffold error: %v", err)
+	}
+
+	cfg, err := config.LoadForgeConfig(filepath.Join("oncall", "forge.yaml"))
+	if err != nil {
+		t.Fatalf("LoadForgeConfig error: %v", err)
+	}
+	if len(cfg.Schedules) != 1 || cfg.Schedules[0].ID != "check-prod" {
+		t.Errorf("schedules = %+v", cfg.Schedules)
+	}
+	runbook, err := os.ReadFile(filepath.Join("oncall", "RUNBOOK.md"))
+	if err != nil || string(runbook) != "# prod\n" {
+		t.Errorf("RUNBOOK.md = %q, %v", runbook, err)
+	}
+}
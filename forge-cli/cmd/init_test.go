@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/internal/tui/components"
+	"github.com/initializ/forge/forge-core/security"
 )
 
 func TestParseSkillsFileHeadings(t *testing.T) {
@@ -983,3 +986,28 @@ func TestMergeBuiltinTools(t *testing.T) {
 		})
 	}
 }
+
+func TestEgressCheckFromDNS(t *testing.T) {
+	tests := []struct {
+		in   security.DomainCheck
+		want components.EgressCheck
+	}{
+		{
+			security.DomainCheck{Domain: "api.openai.com", Addrs: []string{"a", "b", "c", "d"}},
+			components.EgressCheck{Status: components.EgressOK, Note: "a, b, c, …"},
+		},
+		{
+			security.DomainCheck{Domain: "slack.com", Addrs: []string{"a"}, Warnings: []string{"apex only", "private"}},
+			components.EgressCheck{Status: components.EgressWarn, Note: "apex only; private"},
+		},
+		{
+			security.DomainCheck{Domain: "api.opneai.com", Err: errors.New("does not resolve: no such host")},
+			components.EgressCheck{Status: components.EgressError, Note: "does not resolve: no such host"},
+		},
+	}
+	for _, tt := range tests {
+		if got := egressCheckFromDNS(tt.in); got != tt.want {
+			t.Errorf("egressCheckFromDNS(%s) = %+v, want %+v", tt.in.Domain, got, tt.want)
+		}
+	}
+}
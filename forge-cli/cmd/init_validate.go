@@ -7,7 +7,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/initializ/forge/forge-cli/internal/tui/steps"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
 )
 
 // providerValidationURLs maps provider names to their validation endpoints.
@@ -16,6 +21,13 @@ var (
 	openaiValidationURL     = "https://api.openai.com/v1/models"
 	anthropicValidationURL  = "https://api.anthropic.com/v1/messages"
 	geminiValidationURL     = "https://generativelanguage.googleapis.com/v1beta/models"
+	cohereValidationURL     = "https://api.cohere.com/v1/models"
+	mistralValidationURL    = "https://api.mistral.ai/v1/models"
+	groqValidationURL       = "https://api.groq.com/openai/v1/models"
+	togetherValidationURL   = "https://api.together.xyz/v1/models"
+	openrouterValidationURL = "https://openrouter.ai/api/v1/key"
+	xaiValidationURL        = "https://api.x.ai/v1/models"
+	deepseekValidationURL   = "https://api.deepseek.com/models"
 	ollamaValidationURL     = "http://localhost:11434/api/tags"
 	tavilyValidationURL     = "https://api.tavily.com/search"
 	perplexityValidationURL = "https://api.perplexity.ai/chat/completions"
@@ -34,6 +46,20 @@ func validateProviderKey(provider, apiKey string) error {
 		return validateAnthropicKey(ctx, apiKey)
 	case "gemini":
 		return validateGeminiKey(ctx, apiKey)
+	case "cohere":
+		return validateBearerKey(ctx, cohereValidationURL, "Cohere", apiKey)
+	case "mistral":
+		return validateBearerKey(ctx, mistralValidationURL, "Mistral", apiKey)
+	case "groq":
+		return validateBearerKey(ctx, groqValidationURL, "Groq", apiKey)
+	case "together":
+		return validateBearerKey(ctx, togetherValidationURL, "Together AI", apiKey)
+	case "openrouter":
+		return validateBearerKey(ctx, openrouterValidationURL, "OpenRouter", apiKey)
+	case "xai":
+		return validateBearerKey(ctx, xaiValidationURL, "xAI", apiKey)
+	case "deepseek":
+		return validateBearerKey(ctx, deepseekValidationURL, "DeepSeek", apiKey)
 	case "ollama":
 		return validateOllamaConnection(ctx)
 	case "custom":
@@ -122,6 +148,31 @@ func validateGeminiKey(ctx context.Context, apiKey string) error {
 	return nil
 }
 
+// validateBearerKey validates a key against a models-list endpoint that
+// takes Authorization: Bearer (Cohere, Mistral).
+func validateBearerKey(ctx context.Context, url, label, apiKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", label, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("invalid %s API key (401 Unauthorized)", label)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s API returned status %d", strings.ToLower(label), resp.StatusCode)
+	}
+	return nil
+}
+
 func validateOllamaConnection(ctx context.Context) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ollamaValidationURL, nil)
 	if err != nil {
@@ -141,6 +192,29 @@ func validateOllamaConnection(ctx context.Context) error {
 	return nil
 }
 
+// ollamaWizardFuncs backs the wizard's Ollama flow with the daemon at
+// OLLAMA_BASE_URL, or localhost.
+func ollamaWizardFuncs() steps.OllamaFuncs {
+	client := providers.NewOllamaClient(llm.ClientConfig{BaseURL: ollamaBaseURL(nil)})
+	return steps.OllamaFuncs{
+		List: func() ([]string, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return client.ListModels(ctx)
+		},
+		Pull: func(model string, progress func(status string, completed, total int64)) error {
+			return client.Pull(context.Background(), model, func(p providers.OllamaPullProgress) {
+				progress(p.Status, p.Completed, p.Total)
+			})
+		},
+		SupportsTools: func(model string) (bool, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			return client.SupportsTools(ctx, model)
+		},
+	}
+}
+
 // validateWebSearchKey validates a web search API key based on the provider.
 func validateWebSearchKey(provider, apiKey string) error {
 	switch provider {
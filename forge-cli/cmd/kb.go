@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/runtime"
+)
+
+var kbCmd = &cobra.Command{
+	Use:   "kb",
+	Short: "Manage the knowledge base",
+}
+
+var kbSyncCmd = &cobra.Command{
+	Use:   "sync [paths...]",
+	Short: "Index local documents into the knowledge base",
+	Long: `Chunks, embeds and indexes markdown, text, HTML and PDF documents into
+the knowledge base: a read-only partition of long-term memory that
+memory_search returns with source citations.
+
+Paths are files or directories; without any, kb.paths from forge.yaml is
+used. Documents unchanged since the last sync are not re-embedded, and
+documents deleted from a synced directory are removed from the index.
+PDFs need pdftotext (poppler-utils) on PATH.
+
+Embeddings use the same provider as the agent's memory search, so the
+model's API key must be available (.env or secrets).`,
+	Example: `  forge kb sync ./docs
+  forge kb sync handbook.pdf faq.html`,
+	RunE: kbSyncRun,
+}
+
+var kbSyncJSON bool
+
+func init() {
+	kbSyncCmd.Flags().BoolVar(&kbSyncJSON, "json", false, "print the result as JSON")
+	kbCmd.AddCommand(kbSyncCmd)
+}
+
+func kbSyncRun(cmd *cobra.Command, args []string) error {
+	cfg, workDir, err := loadAndPrepareConfig(".env")
+	if err != nil {
+		return err
+	}
+	// Paths on the command line are relative to the current directory.
+	paths := make([]string, len(args))
+	for i, a := range args {
+		if paths[i], err = filepath.Abs(a); err != nil {
+			return err
+		}
+	}
+
+	runner, err := runtime.NewRunner(runtime.RunnerConfig{
+		Config:      cfg,
+		WorkDir:     workDir,
+		EnvFilePath: resolveEnvPath(workDir, ".env"),
+	})
+	if err != nil {
+		return err
+	}
+	report, err := runner.SyncKnowledgeBase(cmd.Context(), paths)
+	if err != nil {
+		return fmt.Errorf("kb sync: %w", err)
+	}
+
+	if kbSyncJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	for _, p := range report.Added {
+		fmt.Printf("  added    %s\n", p)
+	}
+	for _, p := range report.Updated {
+		fmt.Printf("  updated  %s\n", p)
+	}
+	for _, p := range report.Removed {
+		fmt.Printf("  removed  %s\n", p)
+	}
+	for _, s := range report.Skipped {
+		fmt.Printf("  skipped  %s (%s)\n", s.Path, s.Reason)
+	}
+	fmt.Printf("Knowledge base synced: %d added, %d updated, %d removed, %d unchanged (%d chunks indexed).\n",
+		len(report.Added), len(report.Updated), len(report.Removed), report.Unchanged, report.Chunks)
+	return nil
+}
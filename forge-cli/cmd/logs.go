@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/runtime"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show daemon logs or captured provider calls",
+	Long: `Without flags, prints the tail of the agent daemon's log, as
+` + "`forge serve logs`" + ` does.
+
+--captures lists the tasks whose provider calls were captured
+(forge.yaml observability.captures): calls, errors, tokens and models
+per task, most recent first. Add --task to print one task's calls in
+full — the request the executor sent and the response, with secrets and
+PII redacted.`,
+	Example: `  forge logs
+  forge logs --captures
+  forge logs --captures --task 3f9c2a --json`,
+	Args: cobra.NoArgs,
+	RunE: logsRun,
+}
+
+var (
+	logsCaptures bool
+	logsTask     string
+	logsJSON     bool
+)
+
+func init() {
+	logsCmd.Flags().BoolVar(&logsCaptures, "captures", false, "show captured provider requests and responses instead of the daemon log")
+	logsCmd.Flags().StringVar(&logsTask, "task", "", "with --captures, print the captured calls of this task")
+	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "with --captures, print JSON")
+	rootCmd.AddCommand(logsCmd)
+}
+
+func logsRun(cmd *cobra.Command, args []string) error {
+	if !logsCaptures {
+		if logsTask != "" || logsJSON {
+			return fmt.Errorf("--task and --json apply to --captures")
+		}
+		return serveLogsRun(cmd, args)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	dir := runtime.CapturesDir(wd)
+
+	if logsTask != "" {
+		recs, err := coreruntime.LoadProviderCaptures(dir, logsTask)
+		if err != nil {
+			return err
+		}
+		if logsJSON {
+			return printJSON(os.Stdout, recs)
+		}
+		return printCaptures(os.Stdout, recs)
+	}
+
+	sums, err := coreruntime.ListProviderCaptures(dir)
+	if err != nil {
+		return fmt.Errorf("reading captures: %w", err)
+	}
+	if logsJSON {
+		return printJSON(os.Stdout, sums)
+	}
+	if len(sums) == 0 {
+		fmt.Println("No captures. Enable observability.captures in forge.yaml and run some tasks.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "TASK\tCALLS\tERRORS\tTOKENS\tMODELS\tLAST\n")
+	for _, s := range sums {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n", s.TaskID, s.Calls, s.Errors, s.Tokens,
+			strings.Join(s.Models, ","), s.Last.Local().Format(time.DateTime))
+	}
+	return w.Flush()
+}
+
+// printCaptures prints a task's captured calls: a header line per call,
+// then its request messages and response.
+func printCaptures(out io.Writer, recs []coreruntime.ProviderCapture) error {
+	for i, r := range recs {
+		model := r.Provider
+		if r.Model != "" {
+			model += "/" + r.Model
+		}
+		_, _ = fmt.Fprintf(out, "── call %d · %s · %s · %dms", i+1, r.Time.Local().Format(time.DateTime), model, r.DurationMS)
+		if r.Stream {
+			_, _ = fmt.Fprint(out, " · stream")
+		}
+		_, _ = fmt.Fprintln(out)
+		if r.Request != nil {
+			_, _ = fmt.Fprintf(out, "request: %d messages, %d tools\n", len(r.Request.Messages), len(r.Request.Tools))
+			for _, m := range r.Request.Messages {
+				printCapturedMessage(out, m.Role, m.Content, m.Name)
+				for _, tc := range m.ToolCalls {
+					_, _ = fmt.Fprintf(out, "    → %s(%s)\n", tc.Function.Name, tc.Function.Arguments)
+				}
+			}
+		}
+		switch {
+		case r.Error != "":
+			_, _ = fmt.Fprintf(out, "error: %s\n", r.Error)
+		case r.Response != nil:
+			u := r.Response.Usage
+			_, _ = fmt.Fprintf(out, "response: finish=%s, tokens in=%d out=%d\n", r.Response.FinishReason, u.InputTokens, u.OutputTokens)
+			printCapturedMessage(out, r.Response.Message.Role, r.Response.Message.Content, "")
+			for _, tc := range r.Response.Message.ToolCalls {
+				_, _ = fmt.Fprintf(out, "    → %s(%s)\n", tc.Function.Name, tc.Function.Arguments)
+			}
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	return nil
+}
+
+func printCapturedMessage(out io.Writer, role, content, name string) {
+	if name != "" {
+		role += " " + name
+	}
+	_, _ = fmt.Fprintf(out, "  [%s]\n", role)
+	if content == "" {
+		return
+	}
+	for _, line := range strings.Split(content, "\n") {
+		_, _ = fmt.Fprintf(out, "    %s\n", line)
+	}
+}
+
+func printJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func TestPrintCaptures(t *testing.T) {
+	recs := []coreruntime.ProviderCapture{
+		{
+			Time: time.Now(), Provider: "openai", Model: "gpt-4o", DurationMS: 812,
+			Request: &llm.ChatRequest{Messages: []llm.ChatMessage{
+				{Role: llm.RoleUser, Content: "weather?"},
+			}},
+			Response: &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{Function: llm.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}}}},
+				FinishReason: "tool_calls",
+				Usage:        llm.UsageInfo{InputTokens: 20, OutputTokens: 8},
+			},
+		},
+		{Time: time.Now(), Provider: "anthropic", Stream: true, Request: &llm.ChatRequest{}, Error: "overloaded"},
+	}
+	var out bytes.Buffer
+	if err := printCaptures(&out, recs); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"── call 1 ·", "openai/gpt-4o · 812ms\n",
+		"  [user]\n    weather?\n",
+		"response: finish=tool_calls, tokens in=20 out=8\n",
+		`    → weather({"city":"Paris"})`,
+		"· anthropic · 0ms · stream\n", "error: overloaded\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
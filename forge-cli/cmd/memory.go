@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/initializ/forge/forge-core/memory"
+)
+
+var memoryCmd = &cobra.Command{
+	Use:   "memory",
+	Short: "Manage long-term memory",
+}
+
+var memoryGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Archive cold long-term memories",
+	Long: `Applies memory.retention to the agent's long-term memory directory.
+
+Each note and daily log has a retention score that halves every
+half_life_days × 2 × importance of age. Memories scoring under
+archive_below, and the coldest notes of a namespace beyond max_notes,
+are appended to the compressed archive (archive/archive.jsonl.gz under
+memory_dir) and removed from the index. Expired notes are dropped.
+MEMORY.md is never archived.
+
+Run it while the agent is stopped; a running agent collects on its own
+when memory.retention.gc_schedule is set.`,
+	RunE: memoryGCRun,
+}
+
+var (
+	memoryGCDryRun bool
+	memoryGCJSON   bool
+)
+
+func init() {
+	memoryGCCmd.Flags().BoolVar(&memoryGCDryRun, "dry-run", false, "show what would be archived without changing anything")
+	memoryGCCmd.Flags().BoolVar(&memoryGCJSON, "json", false, "print the result as JSON")
+	memoryCmd.AddCommand(memoryGCCmd)
+}
+
+func memoryGCRun(cmd *cobra.Command, args []string) error {
+	cfg, workDir, err := loadAndPrepareConfig(".env")
+	if err != nil {
+		return err
+	}
+	dir := runtime.MemoryDir(cfg.Memory, workDir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Printf("No long-term memory at %s.\n", dir)
+		return nil
+	}
+
+	mgr, err := memory.NewManager(memory.ManagerConfig{
+		MemoryDir: dir,
+		Retention: runtime.MemoryRetentionPolicy(cfg.Memory),
+	})
+	if err != nil {
+		return err
+	}
+	res, gcErr := mgr.GC(context.Background(), memoryGCDryRun)
+	if err := mgr.Close(); err != nil && gcErr == nil {
+		gcErr = err
+	}
+	if gcErr != nil {
+		return fmt.Errorf("memory gc: %w", gcErr)
+	}
+
+	if memoryGCJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	if len(res.Archived) == 0 {
+		fmt.Println("Nothing to archive.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "KIND\tSOURCE\tID\tSCORE\tREASON\n")
+	for _, a := range res.Archived {
+		id := "-"
+		if a.Note != nil {
+			id = a.Note.ID
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%.3f\t%s\n", a.Kind, a.Source, id, a.Score, a.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	verb := "Archived"
+	if res.DryRun {
+		verb = "Would archive"
+	}
+	fmt.Printf("\n%s %d notes and %d daily logs", verb, res.ArchivedNotes, res.ArchivedLogs)
+	if res.Archive != "" {
+		fmt.Printf(" to %s", res.Archive)
+	}
+	fmt.Println(".")
+	return nil
+}
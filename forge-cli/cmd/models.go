@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+var modelsBaseURL string
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Manage local models",
+}
+
+var modelsPullCmd = &cobra.Command{
+	Use:   "pull [model...]",
+	Short: "Download Ollama models onto the local daemon",
+	Long: `Download models onto the Ollama daemon so the agent can start.
+
+With no arguments, pulls every Ollama model forge.yaml refers to: the
+primary model, ollama fallbacks, and the long-term-memory embedding model
+when embeddings run on Ollama.
+
+The daemon address comes from --base-url, then OLLAMA_BASE_URL, then
+model.base_url (when the provider is ollama), then http://localhost:11434.`,
+	SilenceUsage: true,
+	RunE:         modelsPullRun,
+}
+
+func init() {
+	modelsPullCmd.Flags().StringVar(&modelsBaseURL, "base-url", "", "Ollama daemon URL")
+	modelsCmd.AddCommand(modelsPullCmd)
+}
+
+func modelsPullRun(cmd *cobra.Command, args []string) error {
+	var cfg *types.ForgeConfig
+	if loaded, err := config.LoadForgeConfig(modelsConfigPath()); err == nil {
+		cfg = loaded
+	} else if len(args) == 0 {
+		return fmt.Errorf("no model given and forge.yaml could not be loaded: %w", err)
+	}
+
+	models := args
+	if len(models) == 0 {
+		models = ollamaModelsFromConfig(cfg)
+		if len(models) == 0 {
+			return fmt.Errorf("forge.yaml uses no Ollama models; pass a model name, e.g. `forge models pull llama3.2`")
+		}
+	}
+
+	client := providers.NewOllamaClient(llm.ClientConfig{BaseURL: ollamaBaseURL(cfg)})
+	out := cmd.OutOrStdout()
+	for _, model := range models {
+		_, _ = fmt.Fprintf(out, "Pulling %s...\n", model)
+		var lastStatus string
+		var inBar bool // a \r progress line is open
+		err := client.Pull(cmd.Context(), model, func(p providers.OllamaPullProgress) {
+			if p.Total > 0 {
+				_, _ = fmt.Fprintf(out, "\r  %s %3d%%", p.Status, p.Completed*100/p.Total)
+				inBar = true
+				return
+			}
+			if inBar {
+				_, _ = fmt.Fprintln(out)
+				inBar = false
+			}
+			if p.Status != lastStatus {
+				_, _ = fmt.Fprintf(out, "  %s\n", p.Status)
+				lastStatus = p.Status
+			}
+		})
+		if inBar {
+			_, _ = fmt.Fprintln(out)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modelsConfigPath resolves --config against the working directory.
+func modelsConfigPath() string {
+	if filepath.IsAbs(cfgFile) {
+		return cfgFile
+	}
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, cfgFile)
+}
+
+// ollamaModelsFromConfig lists the Ollama models cfg needs, in
+// primary → fallbacks → embeddings order, without duplicates.
+func ollamaModelsFromConfig(cfg *types.ForgeConfig) []string {
+	var models []string
+	seen := map[string]bool{}
+	add := func(name, def string) {
+		if name == "" {
+			name = def
+		}
+		if !seen[name] {
+			seen[name] = true
+			models = append(models, name)
+		}
+	}
+	if cfg.Model.Provider == "ollama" {
+		add(cfg.Model.Name, defaultModelNameForProvider("ollama"))
+	}
+	for _, fb := range cfg.Model.Fallbacks {
+		if fb.Provider == "ollama" {
+			add(fb.Name, defaultModelNameForProvider("ollama"))
+		}
+	}
+	// Embeddings auto-detect from the LLM provider when unset.
+	embed := cfg.Memory.EmbeddingProvider
+	longTerm := cfg.Memory.LongTerm != nil && *cfg.Memory.LongTerm
+	if embed == "ollama" || (embed == "" && longTerm && cfg.Model.Provider == "ollama") {
+		add(cfg.Memory.EmbeddingModel, "nomic-embed-text")
+	}
+	return models
+}
+
+// ollamaBaseURL picks the daemon address (see modelsPullCmd.Long).
+func ollamaBaseURL(cfg *types.ForgeConfig) string {
+	if modelsBaseURL != "" {
+		return modelsBaseURL
+	}
+	if u := os.Getenv("OLLAMA_BASE_URL"); u != "" {
+		return u
+	}
+	if cfg != nil && cfg.Model.Provider == "ollama" {
+		return cfg.Model.BaseURL
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestOllamaModelsFromConfig(t *testing.T) {
+	longTerm := true
+	cfg := &types.ForgeConfig{
+		Model: types.ModelRef{
+			Provider: "ollama",
+			Name:     "qwen2.5:7b",
+			Fallbacks: []types.ModelFallback{
+				{Provider: "openai", Name: "gpt-4o"},
+				{Provider: "ollama"},
+				{Provider: "ollama", Name: "qwen2.5:7b"},
+			},
+		},
+		Memory: types.MemoryConfig{LongTerm: &longTerm},
+	}
+	want := []string{"qwen2.5:7b", "llama3", "nomic-embed-text"}
+	if got := ollamaModelsFromConfig(cfg); !slices.Equal(got, want) {
+		t.Errorf("models = %v, want %v", got, want)
+	}
+
+	cfg = &types.ForgeConfig{Model: types.ModelRef{Provider: "anthropic"}}
+	if got := ollamaModelsFromConfig(cfg); len(got) != 0 {
+		t.Errorf("non-ollama config models = %v", got)
+	}
+}
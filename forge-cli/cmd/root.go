@@ -32,20 +32,35 @@ func init() {
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(tryCmd)
+	rootCmd.AddCommand(chatCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(batchCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(experimentsCmd)
 	rootCmd.AddCommand(toolCmd)
 	rootCmd.AddCommand(compressionCmd)
+	rootCmd.AddCommand(memoryCmd)
+	rootCmd.AddCommand(cleanCmd)
+	rootCmd.AddCommand(kbCmd)
 	rootCmd.AddCommand(packageCmd)
+	rootCmd.AddCommand(deployCmd)
 	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(templateCmd)
+	rootCmd.AddCommand(specCmd)
 	rootCmd.AddCommand(channelCmd)
 	rootCmd.AddCommand(skillsCmd)
 	rootCmd.AddCommand(secretCmd)
 	rootCmd.AddCommand(keyCmd)
 	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(modelsCmd)
 	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(uiCmd)
+	rootCmd.AddCommand(agentsCmd)
 	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(guardrailsCmd)
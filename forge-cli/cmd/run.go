@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -18,9 +19,12 @@ import (
 
 	"github.com/initializ/forge/forge-cli/channels"
 	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/initializ/forge/forge-cli/server"
 	"github.com/initializ/forge/forge-core/a2a"
 	corechannels "github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/scheduler"
 	"github.com/initializ/forge/forge-core/security"
 	"github.com/initializ/forge/forge-core/types"
 	"github.com/spf13/cobra"
@@ -29,10 +33,16 @@ import (
 var (
 	runPort              int
 	runHost              string
+	runSocket            string
+	runAllowRemote       bool
 	runShutdownTimeout   time.Duration
 	runMockTools         bool
+	runDryRun            bool
 	runEnforceGuardrails bool
 	runNoGuardrails      bool
+	runProfile           string
+	runAll               bool
+	runWorkspaceFile     string
 	runModel             string
 	runProvider          string
 	runEnvFile           string
@@ -94,11 +104,17 @@ var runCmd = &cobra.Command{
 
 func init() {
 	runCmd.Flags().IntVar(&runPort, "port", 8080, "port for the A2A dev server")
-	runCmd.Flags().StringVar(&runHost, "host", "", "bind address (e.g. 0.0.0.0 for containers)")
-	runCmd.Flags().DurationVar(&runShutdownTimeout, "shutdown-timeout", 0, "graceful shutdown timeout (e.g. 30s)")
+	runCmd.Flags().StringVar(&runHost, "host", "", "bind address (default 127.0.0.1; a non-loopback address such as 0.0.0.0 needs --allow-remote)")
+	runCmd.Flags().BoolVar(&runAllowRemote, "allow-remote", false, "allow --host to bind a non-loopback address, exposing the agent to the network")
+	runCmd.Flags().StringVar(&runSocket, "socket", "", "serve A2A on this Unix socket path instead of a TCP port (sidecar deployments)")
+	runCmd.Flags().DurationVar(&runShutdownTimeout, "shutdown-timeout", 0, "how long a graceful shutdown waits for in-flight tasks before cancelling them (e.g. 30s; 0 = cancel immediately)")
 	runCmd.Flags().BoolVar(&runMockTools, "mock-tools", false, "use mock runtime instead of subprocess")
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "don't run tools: describe each call instead and attach the plan to the task")
 	runCmd.Flags().BoolVar(&runEnforceGuardrails, "enforce-guardrails", true, "enforce guardrail violations as errors")
 	runCmd.Flags().BoolVar(&runNoGuardrails, "no-guardrails", false, "disable all guardrail enforcement")
+	runCmd.Flags().StringVar(&runProfile, "profile", "", "forge.yaml profile to apply, e.g. prod (sets FORGE_PROFILE)")
+	runCmd.Flags().BoolVar(&runAll, "all", false, "run every agent listed in the workspace file, each on its own port")
+	runCmd.Flags().StringVar(&runWorkspaceFile, "workspace", types.WorkspaceFile, "workspace file for --all")
 	runCmd.Flags().StringVar(&runModel, "model", "", "override model name (sets MODEL_NAME env var)")
 	runCmd.Flags().StringVar(&runProvider, "provider", "", "LLM provider (openai, anthropic, ollama)")
 	runCmd.Flags().BoolVar(&runCompression, "compression", false, "enable reversible context compression; --compression=false forces it off (overrides forge.yaml; sets FORGE_COMPRESSION)")
@@ -144,11 +160,44 @@ func init() {
 }
 
 func runRun(cmd *cobra.Command, args []string) error {
+	// --profile → FORGE_PROFILE, which config loading applies. Set
+	// before loading so the env form and the flag share one path.
+	if runProfile != "" {
+		_ = os.Setenv(types.ProfileEnv, runProfile)
+	}
+	if err := checkBindFlags(runHost, runSocket, runWithChannels, runAllowRemote, runAll); err != nil {
+		return err
+	}
+	if runAll {
+		return runWorkspace(cmd)
+	}
+
 	cfg, workDir, err := loadAndPrepareConfig(runEnvFile)
 	if err != nil {
 		return err
 	}
 
+	// Under systemd socket activation the unit owns the address, so
+	// --host, --port and the loopback check give way to it.
+	listener, err := server.ActivationListener()
+	if err != nil {
+		return err
+	}
+	if listener != nil && runSocket != "" {
+		_ = listener.Close()
+		return fmt.Errorf("--socket cannot be used under systemd socket activation")
+	}
+	// Channel adapters call the agent back over HTTP on localhost.
+	agentPort := runPort
+	if listener != nil {
+		if tcp, ok := listener.Addr().(*net.TCPAddr); ok {
+			agentPort = tcp.Port
+		} else if runWithChannels != "" {
+			_ = listener.Close()
+			return fmt.Errorf("--with needs a TCP listener; the activated socket is %s", listener.Addr())
+		}
+	}
+
 	// --compression / --compression=false → FORGE_COMPRESSION env, which the
 	// runner resolves with highest precedence (flag > env > forge.yaml).
 	// Only when explicitly passed, so absent flag leaves yaml/env behavior
@@ -159,7 +208,12 @@ func runRun(cmd *cobra.Command, args []string) error {
 
 	activeChannels := parseChannels(runWithChannels)
 
+	// The flags win; without them forge.yaml (or its profile) decides,
+	// defaulting to enforce.
 	enforceGuardrails := runEnforceGuardrails
+	if !cmd.Flags().Changed("enforce-guardrails") && cfg.EnforceGuardrails != nil {
+		enforceGuardrails = *cfg.EnforceGuardrails
+	}
 	if runNoGuardrails {
 		enforceGuardrails = false
 	}
@@ -177,6 +231,12 @@ func runRun(cmd *cobra.Command, args []string) error {
 	// values (when non-empty / non-zero) override. Empty after this
 	// merge means "no export sink; stderr only" — pre-FWS-7 behavior.
 	auditExport := coreruntime.AuditExportConfigFromEnv()
+	if auditExport.SocketPath == "" && auditExport.HTTPEndpoint == "" {
+		// No env sink: forge.yaml's audit.export (typically inherited
+		// from the organization base config) is next.
+		auditExport.SocketPath = cfg.Audit.Export.Socket
+		auditExport.HTTPEndpoint = cfg.Audit.Export.HTTPEndpoint
+	}
 	if runAuditSocket != "" {
 		auditExport.SocketPath = runAuditSocket
 	}
@@ -213,8 +273,11 @@ func runRun(cmd *cobra.Command, args []string) error {
 		WorkDir:             workDir,
 		Port:                runPort,
 		Host:                runHost,
+		Socket:              runSocket,
+		Listener:            listener,
 		ShutdownTimeout:     runShutdownTimeout,
 		MockTools:           runMockTools,
+		DryRun:              runDryRun,
 		EnforceGuardrails:   enforceGuardrails,
 		ModelOverride:       runModel,
 		ProviderOverride:    runProvider,
@@ -246,12 +309,34 @@ func runRun(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	sigCh := make(chan os.Signal, 1)
+	// The first SIGINT/SIGTERM starts a graceful drain: new tasks are
+	// refused and in-flight ones get --shutdown-timeout to finish. A
+	// second one exits immediately.
+	sigCh := make(chan os.Signal, 2)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-sigCh
-		fmt.Fprintln(os.Stderr, "\nShutting down...")
+		fmt.Fprintln(os.Stderr, "\nShutting down (waiting for in-flight tasks; press Ctrl+C again to force)...")
 		cancel()
+		<-sigCh
+		fmt.Fprintln(os.Stderr, "Forced shutdown.")
+		os.Exit(1)
+	}()
+
+	// SIGHUP reloads forge.yaml in place (forge serve reload).
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	go func() {
+		for range hupCh {
+			newCfg, _, err := loadAndPrepareConfig(runEnvFile)
+			if err == nil {
+				err = runner.Reload(newCfg)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Config reload failed: %v\n", err)
+			}
+		}
 	}()
 
 	// activeChannelSet is the set of adapters actually running (post policy
@@ -262,8 +347,35 @@ func runRun(cmd *cobra.Command, args []string) error {
 	// Start channel adapters if --with flag is set
 	if runWithChannels != "" {
 		registry := defaultRegistry()
-		agentURL := fmt.Sprintf("http://localhost:%d", runPort)
+		agentURL := fmt.Sprintf("http://localhost:%d", agentPort)
 		router := channels.NewRouter(agentURL, runner.AuthToken())
+		router.SetLogger(runner.SubsystemLogger(coreruntime.LogSubsystemChannels))
+		router.ApplyMiddlewareConfig(cfg.ChannelMiddleware)
+		if err := router.ApplyVoiceConfig(cfg.Voice); err != nil {
+			return fmt.Errorf("configuring channel voice: %w", err)
+		}
+		// The router's own replies and the adapters' schedule notices
+		// come from the localization catalogs.
+		localizer, err := i18n.New(cfg.Localization, workDir)
+		if err != nil {
+			return fmt.Errorf("localization: %w", err)
+		}
+		router.SetLocalizer(localizer)
+		// Buffer inbound messages on disk so ones that arrive while the
+		// agent restarts are answered afterward instead of dropped.
+		if q, qErr := channels.OpenQueue(filepath.Join(workDir, ".forge", "channel-queue")); qErr == nil {
+			router.SetQueue(q)
+		} else {
+			fmt.Fprintf(os.Stderr, "  Warning: channel queue disabled: %v\n", qErr)
+		}
+		// Keep each Slack thread or Telegram chat in one agent session
+		// across restarts, until it idles out or the user sends /new.
+		sessions, sErr := channels.OpenSessions(filepath.Join(workDir, ".forge", "channel-sessions.json"), cfg.ChannelMiddleware.SessionTTL)
+		if sErr != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: channel sessions not persisted: %v\n", sErr)
+			sessions, _ = channels.OpenSessions("", cfg.ChannelMiddleware.SessionTTL)
+		}
+		router.SetSessions(sessions)
 
 		// Collect initialized plugins so the scheduler can deliver results.
 		activePlugins := make(map[string]corechannels.ChannelPlugin)
@@ -291,6 +403,16 @@ func runRun(cmd *cobra.Command, args []string) error {
 			fmt.Fprintf(os.Stderr, "  Channel:    %s skipped (denied by %s policy)\n", s.Channel, s.Layer)
 		}
 
+		// Files shared on a channel are fetched through the adapter that
+		// received them; the middleware goes in before any adapter starts.
+		var fetchers []corechannels.ChannelPlugin
+		for _, name := range effective {
+			if plugin := registry.Get(name); plugin != nil {
+				fetchers = append(fetchers, plugin)
+			}
+		}
+		router.ApplyAttachmentConfig(cfg.Attachments, fetchers...)
+
 		for _, name := range effective {
 			plugin := registry.Get(name)
 			if plugin == nil {
@@ -309,6 +431,10 @@ func runRun(cmd *cobra.Command, args []string) error {
 
 			defer plugin.Stop() //nolint:errcheck
 
+			if lz, ok := plugin.(corechannels.Localized); ok {
+				lz.SetLocalizer(localizer)
+			}
+
 			activePlugins[name] = plugin
 			activeChannelSet[name] = true
 
@@ -317,29 +443,60 @@ func runRun(cmd *cobra.Command, args []string) error {
 					fmt.Fprintf(os.Stderr, "channel %s error: %v\n", plugin.Name(), err)
 				}
 			}()
+			go func() {
+				if err := router.Replay(ctx, name, plugin.SendResponse); err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "channel %s: replaying queued messages: %v\n", name, err)
+				}
+			}()
 
 			fmt.Fprintf(os.Stderr, "  Channel:    %s adapter started\n", name)
 		}
 
 		// Wire up schedule notifier so cron results are delivered to channels.
+		// Adapters with schedule controls (Telegram inline keyboards) also
+		// get the confirmation of each schedule the agent sets.
 		if len(activePlugins) > 0 {
-			runner.SetScheduleNotifier(func(ctx context.Context, channel, target string, response *a2a.Message) error {
-				plugin, ok := activePlugins[channel]
+			runner.SetScheduleNotifier(func(ctx context.Context, sched scheduler.Schedule, response *a2a.Message) error {
+				plugin, ok := activePlugins[sched.Channel]
 				if !ok {
-					return fmt.Errorf("channel adapter %q not active", channel)
+					return fmt.Errorf("channel adapter %q not active", sched.Channel)
+				}
+				if sd, ok := plugin.(corechannels.ScheduleDeliverer); ok {
+					return sd.DeliverSchedule(ctx, corechannels.ScheduleNotice{
+						ScheduleID: sched.ID,
+						Cron:       sched.Cron,
+						Task:       sched.Task,
+						Enabled:    sched.Enabled,
+						Target:     sched.ChannelTarget,
+						Response:   response,
+						Language:   localizer.Language(sched.Task),
+					})
+				}
+				if response == nil {
+					return nil
 				}
 				event := &corechannels.ChannelEvent{
-					Channel:     channel,
-					WorkspaceID: target,
+					Channel:     sched.Channel,
+					WorkspaceID: sched.ChannelTarget,
 				}
 				return plugin.SendResponse(event, response)
 			})
 
+			// Deliver notify tool / POST /notify messages to their target's
+			// adapter.
+			runner.SetNotifySender(func(_ context.Context, channel, target string, msg *a2a.Message) error {
+				plugin, ok := activePlugins[channel]
+				if !ok {
+					return fmt.Errorf("%w: %s", runtime.ErrNotifyUnavailable, channel)
+				}
+				return plugin.SendResponse(&corechannels.ChannelEvent{Channel: channel, WorkspaceID: target}, msg)
+			})
+
 			// Wire up DEFER (R4c) interactive approvals (#310): route a
 			// deferred tool call's `to: channel:<adapter>:<target>` to a
 			// channel adapter that supports interactive approvals, and resolve
 			// the approver's click back to the decisions endpoint.
-			opsLog := coreruntime.NewJSONLogger(os.Stdout, false)
+			opsLog := runner.SubsystemLogger(coreruntime.LogSubsystemChannels)
 			resolver := func(ctx context.Context, d corechannels.ApprovalDecision) error {
 				if err := postDeferDecision(ctx, agentURL, runner.AuthToken(), d); err != nil {
 					opsLog.Warn("defer: recording approval decision failed", map[string]any{
@@ -358,6 +515,14 @@ func runRun(cmd *cobra.Command, args []string) error {
 				if la, ok := plugin.(corechannels.LoggerAware); ok {
 					la.SetLogger(opsLog)
 				}
+				// In-process adapters can follow the tool progress of the
+				// task a message started (Slack's thinking message).
+				if pa, ok := plugin.(corechannels.ProgressAware); ok {
+					pa.SetProgressSubscriber(router.ProgressSubscriber(runner.SubscribeProgress))
+				}
+				if sd, ok := plugin.(corechannels.ScheduleDeliverer); ok {
+					sd.SetScheduleManager(runner.ManageSchedule)
+				}
 			}
 			runner.SetDeferralNotifier(func(ctx context.Context, to, taskID, tool, approverContext string, timeout time.Duration) error {
 				adapter, target, ok := parseDeferTarget(to)
@@ -422,6 +587,13 @@ func runRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	channelNames := make([]string, 0, len(activeChannelSet))
+	for name := range activeChannelSet {
+		channelNames = append(channelNames, name)
+	}
+	sort.Strings(channelNames)
+	runner.SetChannels(channelNames)
+
 	// #311 review / user report: warn (loudly, at startup) if a DEFER tool
 	// routes approvals to a channel adapter that isn't active — otherwise the
 	// approval is silently never delivered (the deferral still holds; an
@@ -715,3 +887,26 @@ func buildTracingFlags(cmd *cobra.Command) runtime.TracingFlags {
 	}
 	return flags
 }
+
+// checkBindFlags enforces loopback-only binding: --host may name a
+// non-loopback address only with --allow-remote. --socket replaces the
+// TCP listener, so it excludes --host, --all (whose agents each need
+// their own port) and --with (whose adapters call the agent over TCP).
+func checkBindFlags(host, socket, with string, allowRemote, all bool) error {
+	if socket != "" {
+		if host != "" {
+			return fmt.Errorf("--socket and --host are mutually exclusive")
+		}
+		if all {
+			return fmt.Errorf("--socket cannot be used with --all")
+		}
+		if with != "" {
+			return fmt.Errorf("--with needs a TCP listener; channel adapters cannot reach the agent over --socket")
+		}
+		return nil
+	}
+	if host != "" && !server.IsLoopbackHost(host) && !allowRemote {
+		return fmt.Errorf("refusing to bind %q: a non-loopback address exposes the agent to the network; pass --allow-remote to confirm", host)
+	}
+	return nil
+}
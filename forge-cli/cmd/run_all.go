@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-core/types"
+	"github.com/spf13/cobra"
+)
+
+// workspaceReadyTimeout bounds how long a workspace agent others depend
+// on may take to answer /healthz.
+const workspaceReadyTimeout = 60 * time.Second
+
+// workspaceStopGrace is how long stopping waits for an agent beyond its
+// --shutdown-timeout before killing it.
+const workspaceStopGrace = 10 * time.Second
+
+// workspaceProc is one running workspace agent.
+type workspaceProc struct {
+	agent types.WorkspaceAgent
+	cmd   *exec.Cmd
+	outs  []*prefixWriter
+	done  chan struct{}
+	err   error
+}
+
+// runWorkspace implements `forge run --all`: it starts every agent in
+// the workspace file as a `forge run` child, dependencies first, and
+// stops them all when one exits or on Ctrl+C. Like forge-ui's process
+// manager, but in the foreground with each agent's output prefixed by
+// its name.
+func runWorkspace(cmd *cobra.Command) error {
+	if cmd.Flags().Changed("port") {
+		return fmt.Errorf("--port cannot be used with --all; set each agent's port in %s", runWorkspaceFile)
+	}
+	ws, err := config.LoadWorkspace(runWorkspaceFile)
+	if err != nil {
+		return err
+	}
+	order, err := ws.StartOrder()
+	if err != nil {
+		return err
+	}
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding forge executable: %w", err)
+	}
+
+	byName := make(map[string]types.WorkspaceAgent, len(ws.Agents))
+	needed := make(map[string]bool)
+	width := 0
+	for _, a := range ws.Agents {
+		byName[a.Name] = a
+		for _, d := range a.DependsOn {
+			needed[d] = true
+		}
+		width = max(width, len(a.Name))
+	}
+
+	// Ctrl+C reaches only this process — the agents run in their own
+	// sessions — and is forwarded once per press, so a second press
+	// forces the agents down just as it does for a single `forge run`.
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	var outMu sync.Mutex
+	exited := make(chan *workspaceProc, len(order))
+	var procs []*workspaceProc
+	stopAll := func() {
+		for i := len(procs) - 1; i >= 0; i-- {
+			select {
+			case <-procs[i].done:
+			default:
+				_ = sendTermSignal(procs[i].cmd.Process)
+			}
+		}
+	}
+	shutdown := func() {
+		stopAll()
+		stopped := make(chan struct{})
+		defer close(stopped)
+		go func() {
+			for {
+				select {
+				case <-sigCh:
+					stopAll()
+				case <-stopped:
+					return
+				}
+			}
+		}()
+		waitWorkspaceStopped(procs)
+	}
+
+	startErr := func() error {
+		for _, a := range order {
+			p := &workspaceProc{agent: a, done: make(chan struct{})}
+			p.cmd = exec.Command(exePath, append([]string{"run", "--port", strconv.Itoa(a.Port)}, workspaceRunArgs(cmd)...)...)
+			p.cmd.Dir = a.Dir
+			p.cmd.Env = workspaceAgentEnv(ws, a, byName)
+			p.cmd.SysProcAttr = daemonSysProcAttr()
+			label := fmt.Sprintf("%-*s | ", width, a.Name)
+			stdout := &prefixWriter{mu: &outMu, out: os.Stdout, prefix: []byte(label)}
+			stderr := &prefixWriter{mu: &outMu, out: os.Stderr, prefix: []byte(label)}
+			p.cmd.Stdout, p.cmd.Stderr = stdout, stderr
+			p.outs = []*prefixWriter{stdout, stderr}
+			if err := p.cmd.Start(); err != nil {
+				return fmt.Errorf("starting agent %q: %w", a.Name, err)
+			}
+			procs = append(procs, p)
+			go func() {
+				p.err = p.cmd.Wait()
+				for _, w := range p.outs {
+					w.Flush()
+				}
+				close(p.done)
+				exited <- p
+			}()
+
+			if needed[a.Name] {
+				if err := waitWorkspaceAgentReady(sigCh, p); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}()
+	if startErr != nil {
+		shutdown()
+		return startErr
+	}
+
+	fmt.Fprintf(os.Stderr, "\nWorkspace running %d agents (Ctrl+C to stop):\n", len(procs))
+	for _, p := range procs {
+		fmt.Fprintf(os.Stderr, "  %-*s  http://localhost:%d  %s\n", width, p.agent.Name, p.agent.Port, p.agent.Dir)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	var result error
+	select {
+	case <-sigCh:
+		fmt.Fprintln(os.Stderr, "\nStopping workspace agents (press Ctrl+C again to force)...")
+	case p := <-exited:
+		result = fmt.Errorf("agent %q exited: %v", p.agent.Name, exitDescription(p.err))
+		fmt.Fprintf(os.Stderr, "\nAgent %q exited; stopping the others...\n", p.agent.Name)
+	}
+	shutdown()
+	return result
+}
+
+// waitWorkspaceAgentReady polls the agent's /healthz until it answers
+// 200, failing when the agent exits, the user interrupts, or
+// workspaceReadyTimeout passes.
+func waitWorkspaceAgentReady(sigCh <-chan os.Signal, p *workspaceProc) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	url := fmt.Sprintf("http://127.0.0.1:%d/healthz", p.agent.Port)
+	deadline := time.After(workspaceReadyTimeout)
+	tick := time.NewTicker(300 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		if resp, err := client.Get(url); err == nil { //nolint:noctx
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		select {
+		case <-p.done:
+			return fmt.Errorf("agent %q exited before becoming ready: %v", p.agent.Name, exitDescription(p.err))
+		case <-sigCh:
+			return fmt.Errorf("interrupted while waiting for agent %q", p.agent.Name)
+		case <-deadline:
+			return fmt.Errorf("agent %q was not ready on port %d after %s", p.agent.Name, p.agent.Port, workspaceReadyTimeout)
+		case <-tick.C:
+		}
+	}
+}
+
+// waitWorkspaceStopped waits for every agent to exit, killing any still
+// running after its shutdown timeout plus workspaceStopGrace.
+func waitWorkspaceStopped(procs []*workspaceProc) {
+	deadline := time.After(runShutdownTimeout + workspaceStopGrace)
+	for _, p := range procs {
+		select {
+		case <-p.done:
+		case <-deadline:
+			for _, q := range procs {
+				select {
+				case <-q.done:
+				default:
+					_ = sendKillSignal(q.cmd.Process)
+				}
+			}
+			for _, q := range procs {
+				<-q.done
+			}
+			return
+		}
+	}
+}
+
+// workspaceRunArgs forwards the `forge run` flags that apply to every
+// agent in the workspace. --profile needs no forwarding: the agents
+// inherit FORGE_PROFILE.
+func workspaceRunArgs(cmd *cobra.Command) []string {
+	var args []string
+	if runHost != "" {
+		args = append(args, "--host", runHost)
+	}
+	if runAllowRemote {
+		args = append(args, "--allow-remote")
+	}
+	if runShutdownTimeout > 0 {
+		args = append(args, "--shutdown-timeout", runShutdownTimeout.String())
+	}
+	if runMockTools {
+		args = append(args, "--mock-tools")
+	}
+	if runDryRun {
+		args = append(args, "--dry-run")
+	}
+	if runNoGuardrails {
+		args = append(args, "--no-guardrails")
+	} else if cmd.Flags().Changed("enforce-guardrails") {
+		args = append(args, "--enforce-guardrails="+strconv.FormatBool(runEnforceGuardrails))
+	}
+	if runModel != "" {
+		args = append(args, "--model", runModel)
+	}
+	if runProvider != "" {
+		args = append(args, "--provider", runProvider)
+	}
+	if cmd.Flags().Changed("compression") {
+		args = append(args, "--compression="+strconv.FormatBool(runCompression))
+	}
+	if runEnvFile != ".env" {
+		args = append(args, "--env", runEnvFile)
+	}
+	if runWithChannels != "" {
+		args = append(args, "--with", runWithChannels)
+	}
+	if runNoAuth {
+		args = append(args, "--no-auth")
+	}
+	if runCORSOrigins != "" {
+		args = append(args, "--cors-origins", runCORSOrigins)
+	}
+	return args
+}
+
+// workspaceAgentEnv builds an agent's environment: this process's, then
+// the workspace env, then the agent's own, plus the URL of each agent it
+// depends on as FORGE_WORKSPACE_<NAME>_URL.
+func workspaceAgentEnv(ws *types.WorkspaceConfig, a types.WorkspaceAgent, byName map[string]types.WorkspaceAgent) []string {
+	env := os.Environ()
+	for k, v := range ws.Env {
+		env = append(env, k+"="+v)
+	}
+	for k, v := range a.Env {
+		env = append(env, k+"="+v)
+	}
+	for _, d := range a.DependsOn {
+		env = append(env, fmt.Sprintf("%s=http://127.0.0.1:%d", workspaceURLEnv(d), byName[d].Port))
+	}
+	return env
+}
+
+// workspaceURLEnv names the env var carrying a workspace agent's URL.
+func workspaceURLEnv(name string) string {
+	return "FORGE_WORKSPACE_" + strings.ToUpper(strings.NewReplacer("-", "_").Replace(name)) + "_URL"
+}
+
+// exitDescription renders a child's Wait error for a log line.
+func exitDescription(err error) string {
+	if err == nil {
+		return "exit status 0"
+	}
+	return err.Error()
+}
+
+// prefixWriter writes each line it is given to out with prefix in front,
+// holding mu per line so agents' lines don't interleave mid-line.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix []byte
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing partial line, if any.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) emit(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, _ = w.out.Write(w.prefix)
+	_, _ = w.out.Write(line)
+}
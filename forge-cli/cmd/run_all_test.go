@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestPrefixWriter_PrefixesWholeLines(t *testing.T) {
+	var out bytes.Buffer
+	w := &prefixWriter{mu: &sync.Mutex{}, out: &out, prefix: []byte("api | ")}
+	_, _ = w.Write([]byte("one\ntw"))
+	_, _ = w.Write([]byte("o\nthr"))
+	w.Flush()
+
+	want := "api | one\napi | two\napi | thr\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWorkspaceAgentEnv_LayersAndDependencyURLs(t *testing.T) {
+	ws := &types.WorkspaceConfig{
+		Env: map[string]string{"LOG_LEVEL": "debug", "MODEL_NAME": "gpt-4o-mini"},
+		Agents: []types.WorkspaceAgent{
+			{Name: "web-search", Port: 8081},
+			{Name: "writer", Port: 8082, Env: map[string]string{"MODEL_NAME": "gpt-4o"}, DependsOn: []string{"web-search"}},
+		},
+	}
+	byName := map[string]types.WorkspaceAgent{"web-search": ws.Agents[0], "writer": ws.Agents[1]}
+
+	env := workspaceAgentEnv(ws, ws.Agents[1], byName)
+	if !slices.Contains(env, "FORGE_WORKSPACE_WEB_SEARCH_URL=http://127.0.0.1:8081") {
+		t.Error("missing dependency URL")
+	}
+	// The agent's own value comes after the workspace's, so it wins.
+	if i, j := slices.Index(env, "MODEL_NAME=gpt-4o-mini"), slices.Index(env, "MODEL_NAME=gpt-4o"); i < 0 || j < i {
+		t.Errorf("agent env should follow workspace env: workspace at %d, agent at %d", i, j)
+	}
+}
@@ -244,3 +244,40 @@ func TestSyncRequestTimeout_IsReferenced(t *testing.T) {
 		t.Errorf("SyncRequestTimeout = %v, want 360s", channels.SyncRequestTimeout)
 	}
 }
+
+func TestCheckBindFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		socket      string
+		with        string
+		allowRemote bool
+		all         bool
+		wantErr     string
+	}{
+		{name: "default loopback"},
+		{name: "explicit loopback", host: "127.0.0.1"},
+		{name: "ipv6 loopback", host: "::1"},
+		{name: "all interfaces refused", host: "0.0.0.0", wantErr: "--allow-remote"},
+		{name: "lan address refused", host: "10.0.0.5", wantErr: "--allow-remote"},
+		{name: "all interfaces opted in", host: "0.0.0.0", allowRemote: true},
+		{name: "socket", socket: "/run/forge/a2a.sock"},
+		{name: "socket and host", socket: "/run/forge/a2a.sock", host: "127.0.0.1", wantErr: "mutually exclusive"},
+		{name: "socket and all", socket: "/run/forge/a2a.sock", all: true, wantErr: "--all"},
+		{name: "socket and channels", socket: "/run/forge/a2a.sock", with: "slack", wantErr: "--with"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBindFlags(tt.host, tt.socket, tt.with, tt.allowRemote, tt.all)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
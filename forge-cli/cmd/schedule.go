@@ -52,7 +52,7 @@ func scheduleListRun(cmd *cobra.Command, args []string) error {
 	for _, sched := range schedules {
 		nextFire := "N/A"
 		if sched.Enabled {
-			parsed, parseErr := scheduler.Parse(sched.Cron)
+			parsed, parseErr := scheduler.ParseIn(sched.Cron, sched.Timezone)
 			if parseErr == nil {
 				ref := sched.LastRun
 				if ref.IsZero() {
@@ -70,8 +70,13 @@ func scheduleListRun(cmd *cobra.Command, args []string) error {
 			task = task[:47] + "..."
 		}
 
+		cron := sched.Cron
+		if sched.Timezone != "" {
+			cron += " (" + sched.Timezone + ")"
+		}
+
 		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\n",
-			sched.ID, sched.Cron, sched.Source, sched.Enabled, nextFire, task)
+			sched.ID, cron, sched.Source, sched.Enabled, nextFire, task)
 	}
 
 	return w.Flush()
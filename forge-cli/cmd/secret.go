@@ -5,18 +5,29 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/initializ/forge/forge-cli/config"
 	"github.com/initializ/forge/forge-core/secrets"
+	"github.com/initializ/forge/forge-core/types"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
-var secretLocal bool
+var (
+	secretLocal        bool
+	secretProfile      string
+	secretIdentity     string
+	rotateRecipients   []string
+	rotateNoPassphrase bool
+	keygenOutput       string
+)
 
 var secretCmd = &cobra.Command{
-	Use:   "secret",
-	Short: "Manage encrypted secrets",
-	Long:  "Store, retrieve, and manage secrets in the encrypted secrets file.",
+	Use:     "secret",
+	Short:   "Manage encrypted secrets",
+	Aliases: []string{"secrets"},
+	Long:    "Store, retrieve, and manage secrets in the encrypted secrets file.",
 }
 
 var secretSetCmd = &cobra.Command{
@@ -124,12 +135,221 @@ var secretDeleteCmd = &cobra.Command{
 	},
 }
 
+var secretRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Re-encrypt the secrets file under a new key",
+	Long: `Re-encrypt the secrets file under a newly generated data key and record
+the new key ID in the file header.
+
+The file is opened with the current passphrase or --identity, then wrapped
+for the new passphrase (FORGE_NEW_PASSPHRASE, or prompted) and for every
+recipient in secrets.recipients and --recipient. All earlier wrappings are
+dropped: the old passphrase and removed recipients' keys stop working.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := buildEncryptedProvider()
+		if err != nil {
+			return err
+		}
+		recipients, err := secretRecipients(rotateRecipients)
+		if err != nil {
+			return err
+		}
+		if _, err := p.List(); err != nil { // open with the current key first
+			return err
+		}
+
+		var pass string
+		if !rotateNoPassphrase {
+			if pass, err = resolveNewPassphrase(); err != nil {
+				return err
+			}
+		}
+		keyID, err := p.Rotate(pass, recipients)
+		if err != nil {
+			return fmt.Errorf("rotating secrets key: %w", err)
+		}
+
+		fmt.Printf("Rotated %s to key %s", secretsPathForDisplay(), keyID)
+		if len(recipients) > 0 {
+			fmt.Printf(" (%d recipient(s))", len(recipients))
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+var secretInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the secrets file's key ID, context and recipients",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := resolveSecretsPath()
+		info, err := secrets.ReadFileInfo(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		fmt.Printf("File:        %s\n", path)
+		fmt.Printf("Format:      v%d\n", info.Version)
+		if info.Version == 1 {
+			fmt.Println("             (passphrase only; upgraded to v2 on the next write)")
+			return nil
+		}
+		fmt.Printf("Key ID:      %s\n", info.KeyID)
+		fmt.Printf("Rotated:     %s\n", info.RotatedAt.Format(time.RFC3339))
+		context := info.Context
+		if context == "" {
+			context = "(unbound)"
+		}
+		fmt.Printf("Context:     %s\n", context)
+		fmt.Printf("Passphrase:  %v\n", info.Passphrase)
+		for i, r := range info.Recipients {
+			label := ""
+			if i == 0 {
+				label = "Recipients:"
+			}
+			fmt.Printf("%-12s %s\n", label, r)
+		}
+		return nil
+	},
+}
+
+var secretKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate an identity for opening secrets files without a passphrase",
+	Long: `Generate an age-compatible X25519 identity, write it to --output (mode 0600)
+and print its recipient. Add the recipient to secrets.recipients (or pass it
+to "forge secret rotate --recipient") and point FORGE_SECRETS_IDENTITY or
+--identity at the identity file. SSH ed25519 and RSA keys work too, with no
+keygen step.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := keygenOutput
+		if out == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("resolving home directory: %w", err)
+			}
+			out = filepath.Join(home, ".forge", "identity.txt")
+		}
+		if _, err := os.Stat(out); err == nil {
+			return fmt.Errorf("%s already exists; refusing to overwrite an identity", out)
+		}
+
+		identity, recipient, err := secrets.GenerateX25519Identity()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(out), 0o700); err != nil {
+			return fmt.Errorf("creating identity directory: %w", err)
+		}
+		content := fmt.Sprintf("# created: %s\n# recipient: %s\n%s\n", time.Now().UTC().Format(time.RFC3339), recipient, identity)
+		if err := os.WriteFile(out, []byte(content), 0o600); err != nil {
+			return fmt.Errorf("writing identity: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Identity written to %s\n", out)
+		fmt.Println(recipient)
+		return nil
+	},
+}
+
 func init() {
 	secretCmd.PersistentFlags().BoolVar(&secretLocal, "local", false, "operate on agent-local secrets (<cwd>/.forge/secrets.enc)")
+	secretCmd.PersistentFlags().StringVar(&secretProfile, "profile", "", "operate on a profile's agent-local secrets (<cwd>/.forge/secrets.<profile>.enc)")
+	secretCmd.PersistentFlags().StringVar(&secretIdentity, "identity", os.Getenv(secrets.IdentityEnv), "identity file (age or SSH private key) tried before the passphrase (env: FORGE_SECRETS_IDENTITY)")
+	secretRotateCmd.Flags().StringArrayVar(&rotateRecipients, "recipient", nil, "additional recipient to wrap the new key for (age1… or SSH public key; repeatable)")
+	secretRotateCmd.Flags().BoolVar(&rotateNoPassphrase, "no-passphrase", false, "wrap the new key for recipients only, with no passphrase")
+	secretKeygenCmd.Flags().StringVarP(&keygenOutput, "output", "o", "", "identity file to write (default ~/.forge/identity.txt)")
 	secretCmd.AddCommand(secretSetCmd)
 	secretCmd.AddCommand(secretGetCmd)
 	secretCmd.AddCommand(secretListCmd)
 	secretCmd.AddCommand(secretDeleteCmd)
+	secretCmd.AddCommand(secretRotateCmd)
+	secretCmd.AddCommand(secretInfoCmd)
+	secretCmd.AddCommand(secretKeygenCmd)
+	secretCmd.AddCommand(secretSyncCmd)
+}
+
+// localSecretsConfig loads forge.yaml from the current directory for the
+// agent-local file's encryption context and recipients. It returns nil
+// when operating on the global file or outside an agent directory.
+func localSecretsConfig() *types.ForgeConfig {
+	if !secretLocal && secretProfile == "" {
+		return nil
+	}
+	return secretsForgeConfig()
+}
+
+// secretsForgeConfig loads forge.yaml from the current directory, or
+// returns nil when there is none or it does not load.
+func secretsForgeConfig() *types.ForgeConfig {
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, _ := os.Getwd()
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+	if _, err := os.Stat(cfgPath); err != nil {
+		return nil
+	}
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// secretRecipients parses secrets.recipients from forge.yaml, for
+// agent-local files, followed by extra.
+func secretRecipients(extra []string) ([]secrets.Recipient, error) {
+	var all []string
+	if cfg := localSecretsConfig(); cfg != nil {
+		all = append(all, cfg.Secrets.Recipients...)
+	}
+	all = append(all, extra...)
+
+	var rs []secrets.Recipient
+	for _, s := range all {
+		r, err := secrets.ParseRecipient(s)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// resolveNewPassphrase returns the passphrase to rotate to from
+// FORGE_NEW_PASSPHRASE or a confirmed terminal prompt.
+func resolveNewPassphrase() (string, error) {
+	if p := os.Getenv("FORGE_NEW_PASSPHRASE"); p != "" {
+		return p, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("FORGE_NEW_PASSPHRASE not set; pass --no-passphrase to rotate to recipients only")
+	}
+
+	fmt.Fprint(os.Stderr, "New passphrase: ")
+	pass1, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase: %w", err)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprint(os.Stderr, "Confirm new passphrase: ")
+	pass2, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase confirmation: %w", err)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if string(pass1) != string(pass2) {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	if len(pass1) == 0 {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	return string(pass1), nil
 }
 
 // localSecretsPath returns the path for agent-local secrets in the current directory.
@@ -153,6 +373,9 @@ func defaultSecretsPath() string {
 // resolveSecretsPath returns the actual secrets file path that will be used,
 // accounting for the --local flag and any secrets.path override in forge.yaml.
 func resolveSecretsPath() string {
+	if secretProfile != "" {
+		return filepath.Join(filepath.Dir(localSecretsPath()), "secrets."+secretProfile+".enc")
+	}
 	if secretLocal {
 		return localSecretsPath()
 	}
@@ -192,8 +415,26 @@ func resolvePassphrase() (string, error) {
 }
 
 // buildEncryptedProvider builds an EncryptedFileProvider using defaults or config.
+// Agent-local files are bound to the agent's encryption context, and new
+// ones are wrapped for secrets.recipients.
 func buildEncryptedProvider() (*secrets.EncryptedFileProvider, error) {
-	return secrets.NewEncryptedFileProvider(resolveSecretsPath(), resolvePassphrase), nil
+	p := secrets.NewEncryptedFileProvider(resolveSecretsPath(), resolvePassphrase)
+	if cfg := localSecretsConfig(); cfg != nil && cfg.AgentID != "" {
+		p.SetContext(secrets.AgentContext(cfg.AgentID, secretProfile))
+	}
+	recipients, err := secretRecipients(nil)
+	if err != nil {
+		return nil, err
+	}
+	p.SetRecipients(recipients...)
+	if secretIdentity != "" {
+		ids, err := secrets.ReadIdentityFile(secretIdentity)
+		if err != nil {
+			return nil, err
+		}
+		p.SetIdentities(ids...)
+	}
+	return p, nil
 }
 
 // parseSecretsPath extracts secrets.path from raw YAML config bytes.
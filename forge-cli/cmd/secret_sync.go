@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/initializ/forge/forge-core/secrets/remote"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncFrom  string
+	syncKeys  []string
+	syncCheck bool
+)
+
+var secretSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Pull keys from Vault, AWS or GCP into the encrypted store",
+	Long: `Pull a defined set of keys from an external secret manager into the
+encrypted secrets file, for offline development.
+
+The source and keys come from secrets.sync in forge.yaml, or --from and
+--key. Sources:
+
+  vault://<mount>/<path>[?kv=1]         VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE
+  aws://<secret-id>[?region=<region>]   AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_REGION
+  gcp://<project>/<secret>[?version=N]  GOOGLE_OAUTH_ACCESS_TOKEN, or the metadata server
+
+Each key is reported as in-sync, missing (not stored locally yet),
+drifted (stored with a different value) or absent (not in the source).
+Missing and drifted keys are written; an absent key fails the sync.
+With --check nothing is written and any key not in sync fails the
+command, for CI.`,
+	Example: `  forge secret sync --local --from vault://secret/billing --key OPENAI_API_KEY --key STRIPE_KEY=stripe_secret
+  forge secret sync --local --check`,
+	Args: cobra.NoArgs,
+	RunE: runSecretSync,
+}
+
+func init() {
+	secretSyncCmd.Flags().StringVar(&syncFrom, "from", "", "source URL (default: secrets.sync.from)")
+	secretSyncCmd.Flags().StringArrayVar(&syncKeys, "key", nil, "key to pull, KEY or KEY=field (repeatable; default: secrets.sync.keys)")
+	secretSyncCmd.Flags().BoolVar(&syncCheck, "check", false, "report drift without writing; exit non-zero unless every key is in sync")
+}
+
+func runSecretSync(cmd *cobra.Command, _ []string) error {
+	from, keys := syncFrom, syncKeys
+	if cfg := secretsForgeConfig(); cfg != nil && cfg.Secrets.Sync != nil {
+		if from == "" {
+			from = cfg.Secrets.Sync.From
+		}
+		if len(keys) == 0 {
+			keys = cfg.Secrets.Sync.Keys
+		}
+	}
+	if from == "" {
+		return fmt.Errorf("no source: set secrets.sync.from in forge.yaml or pass --from")
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no keys to sync: set secrets.sync.keys in forge.yaml or pass --key")
+	}
+	mappings, err := remote.ParseMappings(keys)
+	if err != nil {
+		return err
+	}
+	src, err := remote.Parse(from, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+	fields, err := src.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", src, err)
+	}
+
+	store, err := buildEncryptedProvider()
+	if err != nil {
+		return err
+	}
+	changes, err := remote.Compare(mappings, fields, store)
+	if err != nil {
+		return err
+	}
+
+	outOfSync, absent := 0, 0
+	for _, c := range changes {
+		name := c.Local
+		if c.Remote != c.Local {
+			name += " <- " + c.Remote
+		}
+		fmt.Printf("  %-8s %s\n", c.Status, name)
+		switch c.Status {
+		case remote.StatusAbsent:
+			absent++
+			outOfSync++
+		case remote.StatusMissing, remote.StatusDrifted:
+			outOfSync++
+		}
+	}
+
+	if syncCheck {
+		if outOfSync > 0 {
+			return fmt.Errorf("%d of %d key(s) out of sync with %s", outOfSync, len(changes), src)
+		}
+		fmt.Printf("All %d key(s) in sync with %s\n", len(changes), src)
+		return nil
+	}
+	if absent > 0 {
+		return fmt.Errorf("%d key(s) absent from %s; nothing written", absent, src)
+	}
+	n, err := remote.Apply(changes, store)
+	if err != nil {
+		return fmt.Errorf("writing secrets: %w", err)
+	}
+	fmt.Printf("Synced %d key(s) from %s into %s\n", n, src, secretsPathForDisplay())
+	return nil
+}
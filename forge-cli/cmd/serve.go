@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/initializ/forge/forge-core/types"
 	"github.com/initializ/forge/forge-core/util/process"
 	"github.com/spf13/cobra"
 )
@@ -23,14 +24,19 @@ type daemonState struct {
 	Host        string `json:"host"`
 	AuthEnabled bool   `json:"auth_enabled"`
 	TokenPath   string `json:"token_path,omitempty"`
+	// ShutdownTimeout is the daemon's --shutdown-timeout, so stop
+	// waits out a graceful drain before resorting to SIGKILL.
+	ShutdownTimeout string `json:"shutdown_timeout,omitempty"`
 }
 
 var (
 	servePort              int
 	serveHost              string
+	serveAllowRemote       bool
 	serveShutdownTimeout   time.Duration
 	serveEnforceGuardrails bool
 	serveNoGuardrails      bool
+	serveProfile           string
 	serveModel             string
 	serveProvider          string
 	serveCompression       bool
@@ -69,7 +75,8 @@ When invoked without a subcommand, starts the daemon (equivalent to "forge serve
 
 Subcommands:
   start   - Start the daemon (default)
-  stop    - Stop a running daemon
+  stop    - Stop a running daemon, letting in-flight tasks finish
+  reload  - Reload model, guardrails and egress config without restarting
   status  - Show daemon status
   logs    - Tail daemon logs
 
@@ -77,6 +84,7 @@ Examples:
   forge serve                         # Start daemon on 127.0.0.1:8080
   forge serve start --port 9090       # Start on custom port
   forge serve stop                    # Stop the daemon
+  forge serve reload                  # Re-read forge.yaml in place
   forge serve status                  # Show running status
   forge serve logs                    # View recent logs`,
 	SilenceUsage: true,
@@ -96,6 +104,16 @@ var serveStopCmd = &cobra.Command{
 	RunE:  serveStopRun,
 }
 
+var serveReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload the daemon's model, guardrail and egress config",
+	Long: `Send SIGHUP to the running daemon. It re-reads forge.yaml, .env and
+secrets, and swaps in the model, guardrail policies and egress allowlist
+without dropping connections. Other settings need a restart. The outcome
+is logged and recorded as a config_reloaded audit event.`,
+	RunE: serveReloadRun,
+}
+
 var serveStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show agent daemon status",
@@ -110,10 +128,12 @@ var serveLogsCmd = &cobra.Command{
 
 func registerServeFlags(cmd *cobra.Command) {
 	cmd.Flags().IntVarP(&servePort, "port", "p", 8080, "HTTP server port")
-	cmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "bind address (use 0.0.0.0 for containers)")
-	cmd.Flags().DurationVar(&serveShutdownTimeout, "shutdown-timeout", 30*time.Second, "graceful shutdown timeout")
+	cmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "bind address (a non-loopback address such as 0.0.0.0 needs --allow-remote)")
+	cmd.Flags().BoolVar(&serveAllowRemote, "allow-remote", false, "allow --host to bind a non-loopback address, exposing the agent to the network")
+	cmd.Flags().DurationVar(&serveShutdownTimeout, "shutdown-timeout", 30*time.Second, "how long a graceful shutdown waits for in-flight tasks before cancelling them")
 	cmd.Flags().BoolVar(&serveEnforceGuardrails, "enforce-guardrails", true, "enforce guardrail violations as errors")
 	cmd.Flags().BoolVar(&serveNoGuardrails, "no-guardrails", false, "disable all guardrail enforcement")
+	cmd.Flags().StringVar(&serveProfile, "profile", "", "forge.yaml profile to apply, e.g. prod (forwarded to the daemon)")
 	cmd.Flags().StringVar(&serveModel, "model", "", "override model name (sets MODEL_NAME env var)")
 	cmd.Flags().StringVar(&serveProvider, "provider", "", "LLM provider (openai, anthropic, ollama)")
 	cmd.Flags().BoolVar(&serveCompression, "compression", false, "enable reversible context compression; --compression=false forces it off (forwarded to the daemon)")
@@ -140,6 +160,7 @@ func init() {
 
 	serveCmd.AddCommand(serveStartCmd)
 	serveCmd.AddCommand(serveStopCmd)
+	serveCmd.AddCommand(serveReloadCmd)
 	serveCmd.AddCommand(serveStatusCmd)
 	serveCmd.AddCommand(serveLogsCmd)
 }
@@ -178,6 +199,9 @@ func readDaemonState(path string) (daemonState, bool) {
 
 // serveStartRun starts the daemon by forking "forge run" in the background.
 func serveStartRun(cmd *cobra.Command, args []string) error {
+	if err := checkBindFlags(serveHost, "", "", serveAllowRemote, false); err != nil {
+		return err
+	}
 	statePath := stateFilePath()
 
 	// Check if already running
@@ -186,6 +210,12 @@ func serveStartRun(cmd *cobra.Command, args []string) error {
 			state.PID, state.Host, state.Port)
 	}
 
+	// The profile picks the secrets providers, so apply it in the parent
+	// too; the forked run inherits FORGE_PROFILE.
+	if serveProfile != "" {
+		_ = os.Setenv(types.ProfileEnv, serveProfile)
+	}
+
 	// Call loadAndPrepareConfig in the parent (which has a TTY) so passphrase
 	// prompting works and secrets are overlaid into the environment.
 	if _, _, err := loadAndPrepareConfig(serveEnvFile); err != nil {
@@ -204,10 +234,15 @@ func serveStartRun(cmd *cobra.Command, args []string) error {
 		"--host", serveHost,
 		"--shutdown-timeout", serveShutdownTimeout.String(),
 	}
+	if serveAllowRemote {
+		runArgs = append(runArgs, "--allow-remote")
+	}
 	if serveNoGuardrails {
 		runArgs = append(runArgs, "--no-guardrails")
-	} else if serveEnforceGuardrails {
-		runArgs = append(runArgs, "--enforce-guardrails")
+	} else if cmd.Flags().Changed("enforce-guardrails") {
+		// Forward only when explicitly passed so forge.yaml's
+		// enforce_guardrails (or its profile's) still applies.
+		runArgs = append(runArgs, "--enforce-guardrails="+strconv.FormatBool(serveEnforceGuardrails))
 	}
 	if serveModel != "" {
 		runArgs = append(runArgs, "--model", serveModel)
@@ -298,11 +333,12 @@ func serveStartRun(cmd *cobra.Command, args []string) error {
 		tokenPath = filepath.Join(wd, ".forge", "runtime.token")
 	}
 	state := daemonState{
-		PID:         child.Process.Pid,
-		Port:        servePort,
-		Host:        serveHost,
-		AuthEnabled: authEnabled,
-		TokenPath:   tokenPath,
+		PID:             child.Process.Pid,
+		Port:            servePort,
+		Host:            serveHost,
+		AuthEnabled:     authEnabled,
+		TokenPath:       tokenPath,
+		ShutdownTimeout: serveShutdownTimeout.String(),
 	}
 	stateData, _ := json.Marshal(state)
 	if err := os.WriteFile(statePath, stateData, 0644); err != nil {
@@ -355,8 +391,9 @@ func serveStopRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("sending SIGTERM: %w", err)
 	}
 
-	// Poll for exit (up to 10 seconds)
-	deadline := time.Now().Add(10 * time.Second)
+	// Poll for exit: the drain's shutdown timeout plus 10 seconds to
+	// cancel stragglers and flush sessions and audit sinks.
+	deadline := time.Now().Add(stopWait(state))
 	for time.Now().Before(deadline) {
 		if !process.IsAlive(state.PID) {
 			os.Remove(statePath) //nolint:errcheck
@@ -378,6 +415,33 @@ func serveStopRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// stopWait is how long stop waits for the daemon to exit on its own.
+// State files written before the timeout was recorded get 10 seconds.
+func stopWait(state daemonState) time.Duration {
+	wait := 10 * time.Second
+	if d, err := time.ParseDuration(state.ShutdownTimeout); err == nil && d > 0 {
+		wait += d
+	}
+	return wait
+}
+
+// serveReloadRun signals the daemon to reload its config.
+func serveReloadRun(cmd *cobra.Command, args []string) error {
+	state, running := readDaemonState(stateFilePath())
+	if !running {
+		return fmt.Errorf("no daemon is running")
+	}
+	proc, err := os.FindProcess(state.PID)
+	if err != nil {
+		return fmt.Errorf("finding process %d: %w", state.PID, err)
+	}
+	if err := sendReloadSignal(proc); err != nil {
+		return fmt.Errorf("sending SIGHUP: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Reload signalled (PID %d); see %s for the result.\n", state.PID, logFilePath())
+	return nil
+}
+
 // serveStatusRun displays daemon status.
 func serveStatusRun(cmd *cobra.Command, args []string) error {
 	statePath := stateFilePath()
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestReadDaemonState_NoFile(t *testing.T) {
@@ -42,9 +43,18 @@ func TestServeCmd_HasSubcommands(t *testing.T) {
 		names[sub.Name()] = true
 	}
 
-	for _, want := range []string{"start", "stop", "status", "logs"} {
+	for _, want := range []string{"start", "stop", "reload", "status", "logs"} {
 		if !names[want] {
 			t.Errorf("missing subcommand %q", want)
 		}
 	}
 }
+
+func TestStopWait(t *testing.T) {
+	if got := stopWait(daemonState{}); got != 10*time.Second {
+		t.Errorf("stopWait(legacy state) = %v, want 10s", got)
+	}
+	if got := stopWait(daemonState{ShutdownTimeout: "30s"}); got != 40*time.Second {
+		t.Errorf("stopWait(30s) = %v, want 40s", got)
+	}
+}
@@ -18,3 +18,7 @@ func sendTermSignal(proc *os.Process) error {
 func sendKillSignal(proc *os.Process) error {
 	return proc.Signal(syscall.SIGKILL)
 }
+
+func sendReloadSignal(proc *os.Process) error {
+	return proc.Signal(syscall.SIGHUP)
+}
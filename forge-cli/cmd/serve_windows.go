@@ -3,6 +3,7 @@
 package cmd
 
 import (
+	"errors"
 	"os"
 	"syscall"
 )
@@ -19,3 +20,8 @@ func sendTermSignal(proc *os.Process) error {
 func sendKillSignal(proc *os.Process) error {
 	return proc.Kill()
 }
+
+func sendReloadSignal(proc *os.Process) error {
+	// Windows has no SIGHUP; the daemon must be restarted instead.
+	return errors.New("reload is not supported on Windows; use forge serve stop && forge serve start")
+}
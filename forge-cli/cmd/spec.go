@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+var (
+	specDiffFormat     string
+	specDiffFailOnGain bool
+)
+
+var specCmd = &cobra.Command{
+	Use:   "spec",
+	Short: "Inspect built agent specifications",
+}
+
+var specDiffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare the capability surface of two agent builds",
+	Long: `Compare what two builds of an agent can do: tools and their permissions,
+skills, egress profile, mode and domains, schedules, channels, and auth.
+
+Each argument is a build output directory (containing agent.json), a project
+directory with a .forge-output build, or a path to agent.json. Schedules,
+channels and auth are read from the forge.yaml copied into the build output.
+
+Use --fail-on-gain in CI to fail a pull request check whenever the new build
+gains a capability, so a reviewer signs off on it before deploy.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runSpecDiff,
+}
+
+func init() {
+	specDiffCmd.Flags().StringVar(&specDiffFormat, "format", "text", "output format: text or json")
+	specDiffCmd.Flags().BoolVar(&specDiffFailOnGain, "fail-on-gain", false, "exit non-zero when the new build gains a capability")
+	specCmd.AddCommand(specDiffCmd)
+}
+
+func runSpecDiff(cmd *cobra.Command, args []string) error {
+	if specDiffFormat != "text" && specDiffFormat != "json" {
+		return fmt.Errorf("unknown --format %q (want text or json)", specDiffFormat)
+	}
+	from, err := loadCapabilityCard(args[0])
+	if err != nil {
+		return err
+	}
+	to, err := loadCapabilityCard(args[1])
+	if err != nil {
+		return err
+	}
+	diff := agentspec.DiffCapabilities(from, to)
+
+	out := cmd.OutOrStdout()
+	if specDiffFormat == "json" {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("formatting diff: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	} else {
+		_, _ = fmt.Fprint(out, diff.FormatText())
+	}
+
+	if specDiffFailOnGain && diff.Gained() > 0 {
+		return fmt.Errorf("new build gains %d capability change(s); review before deploy", diff.Gained())
+	}
+	return nil
+}
+
+// loadCapabilityCard reads the capability card of the build at path
+// (see specDiffCmd.Long for the accepted forms).
+func loadCapabilityCard(path string) (*agentspec.CapabilityCard, error) {
+	dir, err := resolveBuildDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "agent.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading agent.json: %w", err)
+	}
+	var spec agentspec.AgentSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(dir, "agent.json"), err)
+	}
+	card := agentspec.NewCapabilityCard(&spec)
+
+	// Absent when forge.yaml has no egress block.
+	if data, err := os.ReadFile(filepath.Join(dir, "compiled", "egress_allowlist.json")); err == nil {
+		var allow struct {
+			AllDomains []string `json:"all_domains"`
+		}
+		if err := json.Unmarshal(data, &allow); err != nil {
+			return nil, fmt.Errorf("parsing egress_allowlist.json in %s: %w", dir, err)
+		}
+		card.EgressDomains = allow.AllDomains
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "forge.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("reading forge.yaml from build output %s: %w", dir, err)
+	}
+	cfg, err := types.ParseForgeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	addConfigCapabilities(card, cfg)
+	return card, nil
+}
+
+// resolveBuildDir maps a spec diff argument to a build output directory.
+func resolveBuildDir(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return filepath.Dir(path), nil
+	}
+	for _, dir := range []string{path, filepath.Join(path, ".forge-output")} {
+		if _, err := os.Stat(filepath.Join(dir, "agent.json")); err == nil {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("%s: no agent.json found; run `forge build` first", path)
+}
+
+// addConfigCapabilities fills the card fields that come from forge.yaml
+// rather than agent.json.
+func addConfigCapabilities(card *agentspec.CapabilityCard, cfg *types.ForgeConfig) {
+	for _, s := range cfg.Schedules {
+		item := fmt.Sprintf("%s %q", s.ID, s.Cron)
+		if s.Skill != "" {
+			item += " skill=" + s.Skill
+		}
+		if s.Channel != "" {
+			item += " channel=" + s.Channel
+		}
+		card.Schedules = append(card.Schedules, item)
+	}
+	card.Channels = append(card.Channels, cfg.Channels...)
+
+	card.AuthRequired = cfg.Auth.Required
+	for _, p := range cfg.Auth.Providers {
+		item := p.Type
+		if p.Name != "" {
+			item += " " + p.Name
+		}
+		// The issuer is who can mint accepted tokens; surface it when
+		// the provider has one.
+		if issuer, ok := p.Settings["issuer"].(string); ok && issuer != "" {
+			item += " issuer=" + issuer
+		}
+		card.Auth = append(card.Auth, item)
+	}
+}
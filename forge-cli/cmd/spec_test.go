@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+)
+
+func writeSpecBuild(t *testing.T, agentJSON, forgeYAML, allowlist string) string {
+	t.Helper()
+	dir := t.TempDir()
+	out := filepath.Join(dir, ".forge-output")
+	if err := os.MkdirAll(filepath.Join(out, "compiled"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"agent.json": agentJSON, "forge.yaml": forgeYAML}
+	if allowlist != "" {
+		files[filepath.Join("compiled", "egress_allowlist.json")] = allowlist
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(out, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSpecDiff(t *testing.T) {
+	oldDir := writeSpecBuild(t,
+		`{"agent_id":"bot","version":"0.1.0","egress_mode":"allowlist","tools":[{"name":"web_search"}]}`,
+		"agent_id: bot\nversion: 0.1.0\nframework: forge\n",
+		`{"all_domains":["api.example.com"]}`)
+	newDir := writeSpecBuild(t,
+		`{"agent_id":"bot","version":"0.2.0","egress_mode":"allowlist","tools":[{"name":"web_search"},{"name":"cli_execute"}]}`,
+		`agent_id: bot
+version: 0.2.0
+framework: forge
+channels: [slack]
+schedules:
+  - id: nightly
+    cron: "0 2 * * *"
+    task: clean up
+auth:
+  providers:
+    - type: oidc
+      settings:
+        issuer: https://login.example.com
+`,
+		`{"all_domains":["api.example.com","hooks.slack.com"]}`)
+
+	var buf bytes.Buffer
+	specDiffCmd.SetOut(&buf)
+	defer specDiffCmd.SetOut(nil)
+
+	specDiffFormat, specDiffFailOnGain = "json", true
+	defer func() { specDiffFormat, specDiffFailOnGain = "text", false }()
+
+	err := runSpecDiff(specDiffCmd, []string{oldDir, newDir})
+	if err == nil || !strings.Contains(err.Error(), "gains") {
+		t.Errorf("--fail-on-gain: err = %v", err)
+	}
+	var d agentspec.CapabilityDiff
+	if err := json.Unmarshal(buf.Bytes(), &d); err != nil {
+		t.Fatalf("json output: %v\n%s", err, buf.String())
+	}
+	added := map[string][]string{}
+	for _, s := range d.Sections {
+		added[s.Section] = s.Added
+	}
+	want := map[string]string{
+		"tools":          "cli_execute",
+		"egress_domains": "hooks.slack.com",
+		"schedules":      `nightly "0 2 * * *"`,
+		"channels":       "slack",
+		"auth":           "oidc issuer=https://login.example.com",
+	}
+	for section, item := range want {
+		if got := added[section]; len(got) != 1 || got[0] != item {
+			t.Errorf("%s added = %v, want [%s]", section, got, item)
+		}
+	}
+
+	// Reversed, nothing is gained and the check passes.
+	buf.Reset()
+	specDiffFormat = "text"
+	if err := runSpecDiff(specDiffCmd, []string{newDir, filepath.Join(oldDir, ".forge-output", "agent.json")}); err != nil {
+		t.Errorf("removal only: %v", err)
+	}
+	if !strings.Contains(buf.String(), "  - cli_execute") {
+		t.Errorf("text output:\n%s", buf.String())
+	}
+}
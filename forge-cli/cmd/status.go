@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/initializ/forge/forge-cli/internal/tui"
+	"github.com/initializ/forge/forge-cli/internal/tui/dashboard"
+	"github.com/initializ/forge/forge-core/auth"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Live dashboard of a running agent",
+	Long: `Shows a running agent's live state in the terminal: the tasks it is
+running and the tool each is on, recent tool calls, today's token spend
+and estimated cost, when each schedule runs next, and recent guardrail
+and egress denials.
+
+The dashboard reads the agent's GET /metrics and GET /events endpoints.
+By default it connects to the daemon 'forge serve' started in this
+directory, or http://127.0.0.1:8080, with the token from --token,
+$FORGE_AUTH_TOKEN or .forge/runtime.token.
+
+With --once, or when stdout is not a terminal, it prints the current
+metrics once instead.`,
+	Example: `  forge status
+  forge status --url https://agent.internal:8443 --token $TOKEN
+  forge status --once`,
+	Args: cobra.NoArgs,
+	RunE: statusRun,
+}
+
+var (
+	statusURL   string
+	statusToken string
+	statusOnce  bool
+)
+
+func init() {
+	statusCmd.Flags().StringVar(&statusURL, "url", "", "agent address (default: the local daemon, else http://127.0.0.1:8080)")
+	statusCmd.Flags().StringVar(&statusToken, "token", "", "bearer token (default $FORGE_AUTH_TOKEN, then .forge/runtime.token)")
+	statusCmd.Flags().BoolVar(&statusOnce, "once", false, "print the current metrics and exit")
+}
+
+func statusRun(cmd *cobra.Command, args []string) error {
+	client := &dashboard.Client{BaseURL: statusURL, Token: statusToken}
+	if client.BaseURL == "" {
+		client.BaseURL = "http://127.0.0.1:8080"
+		if state, ok := readDaemonState(stateFilePath()); ok {
+			client.BaseURL = fmt.Sprintf("http://%s:%d", state.Host, state.Port)
+		}
+	}
+	if client.Token == "" {
+		client.Token = os.Getenv("FORGE_AUTH_TOKEN")
+	}
+	if client.Token == "" {
+		tok, err := auth.LoadToken(agentRootDir())
+		if err != nil {
+			return err
+		}
+		client.Token = tok
+	}
+
+	if statusOnce || !term.IsTerminal(int(os.Stdout.Fd())) {
+		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
+		metrics, err := client.Metrics(ctx)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", client.BaseURL, err)
+		}
+		dashboard.WriteSummary(os.Stdout, client.BaseURL, metrics, time.Now())
+		return nil
+	}
+
+	p := tea.NewProgram(dashboard.New(client, tui.DetectTheme(themeOverride)), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("status dashboard: %w", err)
+	}
+	return nil
+}
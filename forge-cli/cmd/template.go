@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initializ/forge/forge-cli/internal/bundle"
+	"github.com/initializ/forge/forge-cli/internal/templaterepo"
+)
+
+var templateSigningKey string
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Maintain custom agent templates for forge init --template",
+}
+
+var templateSignCmd = &cobra.Command{
+	Use:   "sign <dir>",
+	Short: "Write a template's checksums.json and sign it",
+	Long: `Sign records the SHA-256 of a template's forge-template.yaml, forge.yaml
+and files/ in <dir>/checksums.json, signed with --signing-key or
+~/.forge/signing-key.pem when it exists. Commit checksums.json with the
+template; forge init rejects a template from a repository without one, or
+one whose files changed since it was signed.
+
+Users trust the matching public key with 'forge key trust'.`,
+	Example: `  forge template sign ./forge-templates/sre`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runTemplateSign,
+}
+
+func init() {
+	templateCmd.AddCommand(templateSignCmd)
+	templateSignCmd.Flags().StringVar(&templateSigningKey, "signing-key", "", "Ed25519 key to sign with (default: ~/.forge/signing-key.pem if present)")
+}
+
+func runTemplateSign(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	keyPath := templateSigningKey
+	if keyPath == "" {
+		keyPath = defaultSigningKeyPath()
+	}
+	var (
+		key   ed25519.PrivateKey
+		keyID string
+	)
+	if keyPath != "" {
+		var err error
+		if key, keyID, err = bundle.LoadSigningKey(keyPath); err != nil {
+			return fmt.Errorf("loading signing key: %w", err)
+		}
+	}
+	c, err := templaterepo.Sign(dir, key, keyID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %s (%d files)\n", filepath.Join(dir, templaterepo.ChecksumsName), len(c.Files))
+	if c.KeyID != "" {
+		fmt.Printf("  Signed by: %s\n", c.KeyID)
+	} else {
+		fmt.Fprintln(os.Stderr, "WARNING: no signing key found; checksums.json is unsigned. Create one with 'forge key generate'.")
+	}
+	return nil
+}
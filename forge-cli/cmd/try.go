@@ -432,7 +432,7 @@ func resolveTryProvider(ctx context.Context, flags tryFlags, in io.Reader, out i
 		return tryResolution{
 			Provider: "ollama",
 			Model:    model,
-			Label:    fmt.Sprintf("Using local Ollama (%s). First response may be slow if the model must download.", model),
+			Label:    fmt.Sprintf("Using local Ollama (%s). Run `forge models pull %s` first if it isn't downloaded.", model, model),
 		}, nil
 	}
 
@@ -10,6 +10,8 @@ import (
 	"syscall"
 
 	"github.com/initializ/forge/forge-cli/internal/tui"
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/auth"
 	"github.com/initializ/forge/forge-core/llm"
 	"github.com/initializ/forge/forge-core/llm/providers"
 	"github.com/initializ/forge/forge-core/util"
@@ -18,25 +20,52 @@ import (
 )
 
 var (
-	uiPort   int
-	uiDir    string
-	uiNoOpen bool
+	uiPort        int
+	uiDir         string
+	uiNoOpen      bool
+	uiHost        string
+	uiAllowRemote bool
+	uiAPIToken    string
 )
 
 var uiCmd = &cobra.Command{
 	Use:   "ui",
 	Short: "Launch the local agent dashboard",
-	Long:  "Start a web dashboard for managing, monitoring, and interacting with agents in a workspace.",
-	RunE:  runUI,
+	Long: `Start a web dashboard for managing, monitoring, and interacting with agents in a workspace.
+
+The dashboard listens on 127.0.0.1. To manage a fleet remotely with
+'forge agents', bind another address with --host and --allow-remote;
+the API then requires a token, taken from --api-token or $FORGE_UI_TOKEN
+or generated and printed at startup.`,
+	RunE: runUI,
 }
 
 func init() {
 	uiCmd.Flags().IntVar(&uiPort, "port", 4200, "dashboard server port")
 	uiCmd.Flags().StringVar(&uiDir, "dir", "", "workspace directory (default: current directory)")
 	uiCmd.Flags().BoolVar(&uiNoOpen, "no-open", false, "do not open browser automatically")
+	uiCmd.Flags().StringVar(&uiHost, "host", "", "bind address (default: 127.0.0.1)")
+	uiCmd.Flags().BoolVar(&uiAllowRemote, "allow-remote", false, "allow --host to bind a non-loopback address")
+	uiCmd.Flags().StringVar(&uiAPIToken, "api-token", "", "require this bearer token on the dashboard API (default $FORGE_UI_TOKEN)")
 }
 
 func runUI(cmd *cobra.Command, args []string) error {
+	if uiHost != "" && !server.IsLoopbackHost(uiHost) && !uiAllowRemote {
+		return fmt.Errorf("refusing to bind %q: a non-loopback address exposes the dashboard to the network; pass --allow-remote to confirm", uiHost)
+	}
+	apiToken := uiAPIToken
+	if apiToken == "" {
+		apiToken = os.Getenv("FORGE_UI_TOKEN")
+	}
+	if apiToken == "" && uiHost != "" && !server.IsLoopbackHost(uiHost) {
+		tok, err := auth.GenerateToken()
+		if err != nil {
+			return fmt.Errorf("generating API token: %w", err)
+		}
+		apiToken = tok
+		fmt.Fprintf(os.Stderr, "Dashboard API token (pass to forge agents --token): %s\n", apiToken)
+	}
+
 	workDir := uiDir
 	if workDir == "" {
 		wd, err := os.Getwd()
@@ -213,8 +242,10 @@ func runUI(cmd *cobra.Command, args []string) error {
 	// scripts/-cleanup behavior (issue #193).
 	skillSaveFunc := SaveSkillToDisk
 
-	server := forgeui.NewUIServer(forgeui.UIServerConfig{
+	uiServer := forgeui.NewUIServer(forgeui.UIServerConfig{
 		Port:          uiPort,
+		Host:          uiHost,
+		APIToken:      apiToken,
 		WorkDir:       workDir,
 		ExePath:       exePath,
 		Version:       appVersion,
@@ -238,5 +269,5 @@ func runUI(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	return server.Start(ctx)
+	return uiServer.Start(ctx)
 }
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// maxBaseConfigBytes caps a base config fetched over HTTPS.
+const maxBaseConfigBytes = 1 << 20
+
+// baseConfigFetchTimeout bounds fetching a base config URL.
+const baseConfigFetchTimeout = 15 * time.Second
+
+// baseConfigClient fetches base config URLs; tests swap in a client
+// trusting their TLS server.
+var baseConfigClient = http.DefaultClient
+
+// applyExtends loads the base config cfg.Extends names, relative to the
+// forge.yaml at cfgPath, and merges it in with cfg.Inherit.
+func applyExtends(cfgPath string, cfg *types.ForgeConfig) error {
+	if cfg.Extends == "" {
+		return nil
+	}
+	data, err := readBaseConfig(cfgPath, cfg.Extends, cfg.ExtendsSHA256)
+	if err != nil {
+		return fmt.Errorf("extends %s: %w", cfg.Extends, err)
+	}
+	base, err := types.ParseBaseConfig(data)
+	if err != nil {
+		return fmt.Errorf("extends %s: %w", cfg.Extends, err)
+	}
+	return cfg.Inherit(base)
+}
+
+// readBaseConfig reads a base config from a path or an https:// URL and
+// checks it against the pinned SHA-256. A URL must be pinned; a path may
+// be.
+func readBaseConfig(cfgPath, ref, pin string) ([]byte, error) {
+	var data []byte
+	switch {
+	case strings.HasPrefix(ref, "https://"):
+		if pin == "" {
+			return nil, fmt.Errorf("a URL base config must be pinned with extends_sha256")
+		}
+		var err error
+		if data, err = fetchBaseConfig(ref); err != nil {
+			return nil, err
+		}
+	case strings.Contains(ref, "://"):
+		return nil, fmt.Errorf("only https:// URLs and file paths are supported")
+	default:
+		path := ref
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("resolving ~: %w", err)
+			}
+			path = filepath.Join(home, rest)
+		} else if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(cfgPath), path)
+		}
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if pin != "" {
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, pin) {
+			return nil, fmt.Errorf("sha256 mismatch: file is %s, extends_sha256 pins %s", got, pin)
+		}
+	}
+	return data, nil
+}
+
+// fetchBaseConfig downloads a base config URL.
+func fetchBaseConfig(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), baseConfigFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := baseConfigClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBaseConfigBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching: %w", err)
+	}
+	if len(data) > maxBaseConfigBytes {
+		return nil, fmt.Errorf("base config exceeds %d bytes", maxBaseConfigBytes)
+	}
+	return data, nil
+}
@@ -3,15 +3,115 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/validate"
 )
 
 // LoadForgeConfig reads and parses a forge.yaml file from the given path.
+//
+// A file written for an older schema version is migrated in memory
+// first; the file itself is only rewritten by `forge config migrate`
+// (see MigrateForgeFile). The result is then checked against the
+// forge.yaml JSON Schema: type, enum and range violations fail the load
+// with their line and column, and unknown keys are printed as warnings.
+// Finally the profile named by FORGE_PROFILE, if any, is merged in (see
+// types.ForgeConfig.ApplyProfile) and then the base config named by
+// extends (see types.ForgeConfig.Inherit), so a profile cannot loosen
+// the base either.
 func LoadForgeConfig(path string) (*types.ForgeConfig, error) {
-	data, err := os.ReadFile(path)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading forge config %s: %w", path, err)
+	}
+	data, _, err := types.MigrateForgeConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := validate.CheckForgeConfigSchema(data)
 	if err != nil {
 		return nil, fmt.Errorf("reading forge config %s: %w", path, err)
 	}
-	return types.ParseForgeConfig(data)
+	var errs []string
+	for _, i := range issues {
+		if i.Unknown {
+			fmt.Fprintf(os.Stderr, "WARNING: %s\n", FormatSchemaIssue(path, i))
+			continue
+		}
+		errs = append(errs, FormatSchemaIssue(path, i))
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid forge config:\n  %s", strings.Join(errs, "\n  "))
+	}
+	cfg, err := types.ParseForgeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.ApplyProfile(os.Getenv(types.ProfileEnv)); err != nil {
+		return nil, err
+	}
+	if err := applyExtends(path, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// MigrateForgeFile reads the forge.yaml at path and, when it was written
+// for an older schema version, upgrades it to the current one. The
+// upgraded file replaces the original, which is kept as
+// forge.yaml.v<N>.bak; a current file is not touched. The returned
+// version is the one the file was written for.
+func MigrateForgeFile(path string) ([]byte, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading forge config %s: %w", path, err)
+	}
+	migrated, from, err := types.MigrateForgeConfig(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from >= types.ForgeConfigSchemaVersion {
+		return data, from, nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	if err := saveMigratedForgeFile(path, backup, data, migrated); err != nil {
+		return nil, 0, fmt.Errorf("saving migrated %s: %w", filepath.Base(path), err)
+	}
+	fmt.Fprintf(os.Stderr, "Migrated %s from schema version %d to %d (original saved as %s)\n",
+		filepath.Base(path), from, types.ForgeConfigSchemaVersion, filepath.Base(backup))
+	return migrated, from, nil
+}
+
+// saveMigratedForgeFile writes the backup, then atomically replaces the
+// config with its migrated form, keeping the file mode.
+func saveMigratedForgeFile(path, backup string, original, migrated []byte) error {
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err := os.WriteFile(backup, original, mode); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, migrated, mode); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// FormatSchemaIssue renders a schema issue as "forge.yaml:LINE:COL: msg",
+// the position omitted when unknown.
+func FormatSchemaIssue(path string, i validate.SchemaIssue) string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", filepath.Base(path), i)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", filepath.Base(path), i.Line, i.Column, i)
 }
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadForgeConfig_MigratesInMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forge.yaml")
+	orig := "agent_id: a\nversion: 0.1.0\nframework: forge\n"
+	if err := os.WriteFile(path, []byte(orig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadForgeConfig(path)
+	if err != nil || cfg.SchemaVersion != 1 {
+		t.Fatalf("LoadForgeConfig = %+v, %v", cfg, err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != orig {
+		t.Errorf("LoadForgeConfig rewrote the file: %q", b)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Errorf("LoadForgeConfig wrote a backup: %v", err)
+	}
+}
+
+func TestMigrateForgeFile_WritesBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forge.yaml")
+	orig := "agent_id: a\nversion: 0.1.0\nframework: forge\n"
+	if err := os.WriteFile(path, []byte(orig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, from, err := MigrateForgeFile(path); err != nil || from != 0 {
+		t.Fatalf("MigrateForgeFile = %d, %v", from, err)
+	}
+	if b, _ := os.ReadFile(path + ".v0.bak"); string(b) != orig {
+		t.Errorf("backup = %q", b)
+	}
+	if b, _ := os.ReadFile(path); !strings.HasPrefix(string(b), "schema_version: 1\nagent_id: a\n") {
+		t.Errorf("migrated file = %q", b)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("migrated file mode = %v, %v", fi.Mode(), err)
+	}
+}
+
+func TestLoadForgeConfig_SchemaErrorsHavePositions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forge.yaml")
+	yml := "schema_version: 1\nagent_id: a\nversion: 0.1.0\nframework: forge\negress:\n  mode: open\n"
+	if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadForgeConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "forge.yaml:6:9: egress.mode:") {
+		t.Errorf("LoadForgeConfig error = %v", err)
+	}
+}
+
+func TestLoadForgeConfig_AppliesProfileFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forge.yaml")
+	yml := "schema_version: 1\nagent_id: a\nversion: 0.1.0\nframework: forge\negress:\n  mode: dev-open\nprofiles:\n  prod:\n    egress:\n      mode: allowlist\n"
+	if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("FORGE_PROFILE", "prod")
+	cfg, err := LoadForgeConfig(path)
+	if err != nil || cfg.Egress.Mode != "allowlist" || cfg.ActiveProfile != "prod" {
+		t.Fatalf("LoadForgeConfig = %+v, %v", cfg, err)
+	}
+
+	t.Setenv("FORGE_PROFILE", "staging")
+	if _, err := LoadForgeConfig(path); err == nil || !strings.Contains(err.Error(), `profile "staging" is not defined`) {
+		t.Errorf("unknown profile error = %v", err)
+	}
+}
+
+func TestLoadForgeConfig_Extends(t *testing.T) {
+	dir := t.TempDir()
+	base := "egress:\n  mode: allowlist\n  allowed_domains: [api.openai.com]\nenforce_guardrails: true\n"
+	if err := os.WriteFile(filepath.Join(dir, "org-base.yaml"), []byte(base), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	write := func(extra string) string {
+		path := filepath.Join(dir, "forge.yaml")
+		yml := "schema_version: 1\nagent_id: a\nversion: 0.1.0\nframework: forge\nextends: org-base.yaml\n" + extra
+		if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg, err := LoadForgeConfig(write(""))
+	if err != nil || cfg.Egress.Mode != "allowlist" || cfg.EnforceGuardrails == nil || !*cfg.EnforceGuardrails {
+		t.Fatalf("LoadForgeConfig = %+v, %v", cfg, err)
+	}
+	if _, err := LoadForgeConfig(write("egress:\n  mode: dev-open\n")); err == nil || !strings.Contains(err.Error(), "egress.mode") {
+		t.Errorf("loosening error = %v", err)
+	}
+	if _, err := LoadForgeConfig(write("extends_sha256: " + strings.Repeat("a", 64) + "\n")); err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Errorf("pin mismatch error = %v", err)
+	}
+}
+
+func TestReadBaseConfig_URL(t *testing.T) {
+	body := "egress:\n  mode: deny-all\n"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	}))
+	defer srv.Close()
+	old := baseConfigClient
+	baseConfigClient = srv.Client()
+	defer func() { baseConfigClient = old }()
+
+	sum := sha256.Sum256([]byte(body))
+	data, err := readBaseConfig("forge.yaml", srv.URL+"/base.yaml", hex.EncodeToString(sum[:]))
+	if err != nil || string(data) != body {
+		t.Errorf("readBaseConfig = %q, %v", data, err)
+	}
+	if _, err := readBaseConfig("forge.yaml", srv.URL+"/base.yaml", ""); err == nil {
+		t.Error("unpinned URL accepted")
+	}
+	if _, err := readBaseConfig("forge.yaml", "http://example.com/base.yaml", hex.EncodeToString(sum[:])); err == nil {
+		t.Error("plain http URL accepted")
+	}
+}
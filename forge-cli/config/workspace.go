@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// LoadWorkspace reads a forge-workspace.yaml and resolves each agent's
+// dir against the workspace file's directory. Every agent dir must hold
+// a forge.yaml.
+func LoadWorkspace(path string) (*types.WorkspaceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading workspace config %s: %w", path, err)
+	}
+	ws, err := types.ParseWorkspaceConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("resolving workspace directory: %w", err)
+	}
+	for i := range ws.Agents {
+		a := &ws.Agents[i]
+		if !filepath.IsAbs(a.Dir) {
+			a.Dir = filepath.Join(root, a.Dir)
+		}
+		if _, err := os.Stat(filepath.Join(a.Dir, "forge.yaml")); err != nil {
+			return nil, fmt.Errorf("workspace agent %q: no forge.yaml in %s", a.Name, a.Dir)
+		}
+	}
+	return ws, nil
+}
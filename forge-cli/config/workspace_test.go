@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadWorkspace_ResolvesDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"researcher", "writer"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, d, "forge.yaml"), []byte("agent_id: "+d+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(root, "forge-workspace.yaml")
+	if err := os.WriteFile(path, []byte("agents:\n  - dir: researcher\n  - dir: ./writer\n    depends_on: [researcher]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := LoadWorkspace(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ws.Agents[1].Dir; got != filepath.Join(root, "writer") {
+		t.Errorf("writer dir = %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte("agents:\n  - dir: missing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWorkspace(path); err == nil || !strings.Contains(err.Error(), "no forge.yaml") {
+		t.Errorf("missing agent dir: err = %v", err)
+	}
+}
@@ -0,0 +1,364 @@
+// Package batch runs a file of tasks through an agent: each JSONL or CSV
+// row becomes one task, results are appended to a JSONL file as they
+// finish, and a rerun against the same results file skips the rows that
+// already completed. It backs `forge batch run`.
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// Result statuses.
+const (
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// DefaultConcurrency is the number of rows run at once when Options
+// leaves it unset.
+const DefaultConcurrency = 4
+
+// Row is one task read from the input file.
+type Row struct {
+	ID   string
+	Task string
+}
+
+// Outcome is what an Executor reports for one task.
+type Outcome struct {
+	Output       string
+	InputTokens  int
+	OutputTokens int
+	Model        string
+	// LLMTime is the time spent waiting on the model, when the executor
+	// can tell; the rest of the task's time went to tools and the runtime.
+	LLMTime time.Duration
+}
+
+// Executor runs one task and returns the agent's reply. taskID is
+// unique to the row and the batch run.
+type Executor func(ctx context.Context, taskID, prompt string) (Outcome, error)
+
+// Result is one line of the results file.
+type Result struct {
+	ID           string  `json:"id"`
+	Status       string  `json:"status"`
+	Output       string  `json:"output,omitempty"`
+	Error        string  `json:"error,omitempty"`
+	InputTokens  int     `json:"input_tokens,omitempty"`
+	OutputTokens int     `json:"output_tokens,omitempty"`
+	CostUSD      float64 `json:"cost_usd,omitempty"`
+	Model        string  `json:"model,omitempty"`
+	DurationMs   int64   `json:"duration_ms"`
+}
+
+// Summary totals a batch run. Skipped counts rows that already completed
+// in an earlier run; the other counts cover this run only.
+type Summary struct {
+	Total        int
+	Skipped      int
+	Completed    int
+	Failed       int
+	InputTokens  int
+	OutputTokens int
+	CostUSD      float64
+	// UnpricedRows counts completed rows whose model has no known price,
+	// which CostUSD leaves out.
+	UnpricedRows int
+	Duration     time.Duration
+}
+
+// Options configure Run.
+type Options struct {
+	// Concurrency is the number of rows run at once (default
+	// DefaultConcurrency).
+	Concurrency int
+	// RunID makes task IDs unique to this run (default: the start time).
+	RunID string
+	// Done, when set, is called after each row finishes. Calls are
+	// serialized.
+	Done func(Result)
+}
+
+// ReadRows reads the tasks in path. A .csv file's first line names its
+// columns; any other file is read as JSONL, one object per line. A row's
+// ID is its "id" field, or "row-N" for the Nth row. Its task is the
+// "task" field, or tmpl rendered over the row's fields when tmpl is set
+// ({{.text}} inserts the "text" field).
+func ReadRows(path, tmpl string) ([]Row, error) {
+	var t *template.Template
+	if tmpl != "" {
+		var err error
+		t, err = template.New("task").Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template: %w", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	var records []map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		records, err = readCSV(f)
+	} else {
+		records, err = readJSONL(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	rows := make([]Row, 0, len(records))
+	seen := make(map[string]bool, len(records))
+	for i, rec := range records {
+		id := fieldString(rec["id"])
+		if id == "" {
+			id = fmt.Sprintf("row-%d", i+1)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("row %d: duplicate id %q", i+1, id)
+		}
+		seen[id] = true
+
+		task := fieldString(rec["task"])
+		if t != nil {
+			var buf bytes.Buffer
+			if err := t.Execute(&buf, rec); err != nil {
+				return nil, fmt.Errorf("row %d: %w", i+1, err)
+			}
+			task = buf.String()
+		}
+		if strings.TrimSpace(task) == "" {
+			return nil, fmt.Errorf("row %d: no task (add a \"task\" field or pass a template)", i+1)
+		}
+		rows = append(rows, Row{ID: id, Task: task})
+	}
+	return rows, nil
+}
+
+func readJSONL(r io.Reader) ([]map[string]any, error) {
+	var records []map[string]any
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records, sc.Err()
+}
+
+func readCSV(r io.Reader) ([]map[string]any, error) {
+	all, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	header := all[0]
+	records := make([]map[string]any, 0, len(all)-1)
+	for _, rec := range all[1:] {
+		m := make(map[string]any, len(header))
+		for i, name := range header {
+			if i < len(rec) {
+				m[strings.TrimSpace(name)] = rec[i]
+			}
+		}
+		records = append(records, m)
+	}
+	return records, nil
+}
+
+// fieldString renders a row field as text: strings as-is, numbers and
+// other values as JSON.
+func fieldString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// Completed returns the IDs the results file at path records as
+// completed. A missing file has none.
+func Completed(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var res Result
+		// A torn last line from an interrupted run is skipped; its row
+		// runs again.
+		if json.Unmarshal(sc.Bytes(), &res) != nil {
+			continue
+		}
+		if res.Status == StatusCompleted {
+			done[res.ID] = true
+		} else {
+			delete(done, res.ID)
+		}
+	}
+	return done, sc.Err()
+}
+
+// Run runs every row not in done through exec, writing one Result line
+// to out as each finishes. Rows that fail are recorded and the batch
+// carries on; Run returns an error only when out cannot be written or
+// ctx ends.
+func Run(ctx context.Context, rows []Row, done map[string]bool, exec Executor, out io.Writer, opts Options) (Summary, error) {
+	start := time.Now()
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	runID := opts.RunID
+	if runID == "" {
+		runID = start.UTC().Format("20060102T150405")
+	}
+
+	sum := Summary{Total: len(rows)}
+	var pending []Row
+	for _, row := range rows {
+		if done[row.ID] {
+			sum.Skipped++
+			continue
+		}
+		pending = append(pending, row)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		writeErr error
+		wg       sync.WaitGroup
+	)
+	queue := make(chan Row)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range queue {
+				res := runRow(ctx, exec, "batch-"+runID+"-"+row.ID, row)
+				if ctx.Err() != nil && res.Status == StatusFailed {
+					// Cut short by cancellation: leave the row for the
+					// next run rather than recording it as failed.
+					continue
+				}
+
+				mu.Lock()
+				if writeErr == nil {
+					writeErr = writeResult(out, res)
+					if writeErr != nil {
+						cancel()
+					}
+				}
+				sum.add(res)
+				if opts.Done != nil {
+					opts.Done(res)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, row := range pending {
+		select {
+		case queue <- row:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	sum.Duration = time.Since(start)
+	if writeErr != nil {
+		return sum, fmt.Errorf("writing results: %w", writeErr)
+	}
+	return sum, context.Cause(ctx)
+}
+
+func runRow(ctx context.Context, exec Executor, taskID string, row Row) Result {
+	start := time.Now()
+	o, err := exec(ctx, taskID, row.Task)
+	res := Result{
+		ID:           row.ID,
+		Status:       StatusCompleted,
+		Output:       o.Output,
+		InputTokens:  o.InputTokens,
+		OutputTokens: o.OutputTokens,
+		Model:        o.Model,
+		DurationMs:   time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		res.Status, res.Error = StatusFailed, err.Error()
+	}
+	if price, ok := coreruntime.PriceForModel(o.Model); ok {
+		res.CostUSD = (float64(o.InputTokens)*price.InputPerMTok + float64(o.OutputTokens)*price.OutputPerMTok) / 1e6
+	}
+	return res
+}
+
+func writeResult(out io.Writer, res Result) error {
+	line, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(line, '\n'))
+	return err
+}
+
+func (s *Summary) add(res Result) {
+	if res.Status == StatusCompleted {
+		s.Completed++
+	} else {
+		s.Failed++
+	}
+	s.InputTokens += res.InputTokens
+	s.OutputTokens += res.OutputTokens
+	s.CostUSD += res.CostUSD
+	if res.Status == StatusCompleted && res.CostUSD == 0 && res.InputTokens+res.OutputTokens > 0 {
+		s.UnpricedRows++
+	}
+}
@@ -0,0 +1,157 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadRows(t *testing.T) {
+	jsonl := writeFile(t, "tasks.jsonl", `{"id": "a", "task": "first"}
+
+{"task": "second"}
+{"id": 7, "task": "third"}
+`)
+	rows, err := ReadRows(jsonl, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{{ID: "a", Task: "first"}, {ID: "row-2", Task: "second"}, {ID: "7", Task: "third"}}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+
+	csvPath := writeFile(t, "reviews.csv", "id,text\nr1,great product\nr2,\"broke, twice\"\n")
+	rows, err = ReadRows(csvPath, "Classify: {{.text}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1] != (Row{ID: "r2", Task: "Classify: broke, twice"}) {
+		t.Errorf("rows = %+v", rows)
+	}
+
+	for name, tc := range map[string]struct{ content, tmpl, want string }{
+		"no task":      {`{"text": "x"}`, "", "no task"},
+		"missing key":  {`{"text": "x"}`, "{{.body}}", "body"},
+		"duplicate id": {"{\"id\":\"a\",\"task\":\"x\"}\n{\"id\":\"a\",\"task\":\"y\"}", "", "duplicate id"},
+		"bad json":     {`{"task":`, "", "line 1"},
+	} {
+		_, err := ReadRows(writeFile(t, "t.jsonl", tc.content), tc.tmpl)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", name, err, tc.want)
+		}
+	}
+}
+
+func TestRunResumes(t *testing.T) {
+	rows := []Row{{ID: "a", Task: "ok"}, {ID: "b", Task: "fail"}, {ID: "c", Task: "ok"}}
+	var mu sync.Mutex
+	var ran []string
+	exec := func(_ context.Context, taskID, prompt string) (Outcome, error) {
+		mu.Lock()
+		ran = append(ran, taskID)
+		mu.Unlock()
+		if prompt == "fail" {
+			return Outcome{}, errors.New("boom")
+		}
+		return Outcome{Output: "done " + prompt, InputTokens: 1000, OutputTokens: 100, Model: "gpt-4o"}, nil
+	}
+
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := Run(context.Background(), rows, nil, exec, f, Options{Concurrency: 2, RunID: "r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Completed != 2 || sum.Failed != 1 || sum.InputTokens != 2000 || sum.CostUSD <= 0 {
+		t.Errorf("summary = %+v", sum)
+	}
+	if len(ran) != 3 || !strings.HasPrefix(ran[0], "batch-r1-") {
+		t.Errorf("ran = %v", ran)
+	}
+	// A torn line from an interrupted write is ignored.
+	_, _ = f.WriteString(`{"id":"b","sta`)
+	_ = f.Close()
+
+	done, err := Completed(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done["a"] || done["b"] || !done["c"] {
+		t.Fatalf("done = %v", done)
+	}
+
+	ran = nil
+	sum, err = Run(context.Background(), rows, done, exec, &bytes.Buffer{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Skipped != 2 || sum.Failed != 1 || len(ran) != 1 {
+		t.Errorf("summary = %+v, ran = %v", sum, ran)
+	}
+}
+
+func TestRemoteExecutor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var rpc a2a.JSONRPCRequest
+		var params a2a.SendTaskParams
+		if json.NewDecoder(req.Body).Decode(&rpc) != nil || json.Unmarshal(rpc.Params, &params) != nil || rpc.Method != "tasks/send" {
+			t.Errorf("bad request")
+		}
+		state, reply := a2a.TaskStateCompleted, "positive"
+		if params.Message.Parts[0].Text == "fail" {
+			state, reply = a2a.TaskStateFailed, "guardrail blocked"
+		}
+		w.Header().Set(headerTokensIn, "120")
+		w.Header().Set(headerTokensOut, "8")
+		w.Header().Set(headerModel, "gpt-4o")
+		_ = json.NewEncoder(w).Encode(a2a.NewResponse(rpc.ID, &a2a.Task{ID: params.ID, Status: a2a.TaskStatus{
+			State:   state,
+			Message: &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(reply)}},
+		}}))
+	}))
+	defer srv.Close()
+
+	exec := RemoteExecutor(srv.Client(), srv.URL, "secret")
+	o, err := exec(context.Background(), "batch-1-a", "great product")
+	if err != nil || o != (Outcome{Output: "positive", InputTokens: 120, OutputTokens: 8, Model: "gpt-4o"}) {
+		t.Errorf("outcome = %+v, err = %v", o, err)
+	}
+	if _, err := exec(context.Background(), "batch-1-b", "fail"); err == nil || err.Error() != "guardrail blocked" {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := RemoteExecutor(srv.Client(), srv.URL, "")(context.Background(), "batch-1-c", "x"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// Usage response headers set by the agent server on tasks/send.
+const (
+	headerTokensIn  = "X-Forge-Tokens-In"
+	headerTokensOut = "X-Forge-Tokens-Out"
+	headerModel     = "X-Forge-Model"
+)
+
+// HTTPError is a non-200 answer from the A2A server.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("A2A server returned HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// RemoteExecutor returns an Executor that sends each task to the A2A
+// server at agentURL as a tasks/send JSON-RPC request, with token as the
+// bearer when set. Token usage comes from the server's X-Forge-*
+// response headers. Task failures carry the server's A2A error code
+// (see coreruntime.ClassifyError).
+func RemoteExecutor(client *http.Client, agentURL, token string) Executor {
+	return func(ctx context.Context, taskID, prompt string) (Outcome, error) {
+		params, err := json.Marshal(a2a.SendTaskParams{
+			ID: taskID,
+			Message: a2a.Message{
+				Role:  a2a.MessageRoleUser,
+				Parts: []a2a.Part{a2a.NewTextPart(prompt)},
+			},
+		})
+		if err != nil {
+			return Outcome{}, err
+		}
+		body, err := json.Marshal(a2a.JSONRPCRequest{JSONRPC: "2.0", ID: taskID, Method: "tasks/send", Params: params})
+		if err != nil {
+			return Outcome{}, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, agentURL, bytes.NewReader(body))
+		if err != nil {
+			return Outcome{}, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return Outcome{}, fmt.Errorf("sending request to A2A server: %w", err)
+		}
+		defer resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return Outcome{}, &HTTPError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+		}
+
+		var rpcResp struct {
+			Result *a2a.Task         `json:"result"`
+			Error  *a2a.JSONRPCError `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+			return Outcome{}, fmt.Errorf("parsing JSON-RPC response: %w", err)
+		}
+		o := Outcome{Model: resp.Header.Get(headerModel)}
+		o.InputTokens, _ = strconv.Atoi(resp.Header.Get(headerTokensIn))
+		o.OutputTokens, _ = strconv.Atoi(resp.Header.Get(headerTokensOut))
+		if rpcResp.Error != nil {
+			err := fmt.Errorf("A2A error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+			if te := a2a.TaskErrorFromJSONRPC(rpcResp.Error); te != nil {
+				err = coreruntime.WithErrorCode(te.Code, err)
+			}
+			return o, err
+		}
+		task := rpcResp.Result
+		if task == nil {
+			return o, fmt.Errorf("A2A response has no task")
+		}
+		o.Output = messageText(task.Status.Message)
+		if task.Status.State == a2a.TaskStateFailed {
+			if o.Output == "" {
+				o.Output = "task failed"
+			}
+			err := errors.New(o.Output)
+			if te := a2a.TaskErrorFromTask(task); te != nil {
+				err = coreruntime.WithErrorCode(te.Code, err)
+			}
+			return o, err
+		}
+		return o, nil
+	}
+}
+
+func messageText(m *a2a.Message) string {
+	if m == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range m.Parts {
+		if p.Kind == a2a.PartKindText {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
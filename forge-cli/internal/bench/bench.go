@@ -0,0 +1,229 @@
+// Package bench load-tests an agent: it fires tasks at a fixed
+// concurrency through a batch.Executor — in-process or against a running
+// agent — and reports latency percentiles, throughput, token rates, the
+// time spent outside the model, and a breakdown of the failures. It
+// backs `forge bench`.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/internal/batch"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// Options configure Run.
+type Options struct {
+	// Prompts are sent in turn, cycling when there are more requests
+	// than prompts. At least one is required.
+	Prompts []string
+	// Concurrency is the number of tasks in flight (default 1).
+	Concurrency int
+	// Requests stops the run after this many tasks. With Duration also
+	// set, whichever comes first ends it.
+	Requests int
+	// Duration stops starting new tasks after this long.
+	Duration time.Duration
+	// RunID makes task IDs unique to this run (default: the start time).
+	RunID string
+}
+
+// Report is the outcome of a run. Latencies cover successful tasks only.
+type Report struct {
+	Requests    int     `json:"requests"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	Concurrency int     `json:"concurrency"`
+	DurationSec float64 `json:"duration_seconds"`
+	// Throughput is completed tasks per second of wall time.
+	Throughput float64 `json:"throughput_per_sec"`
+
+	LatencyP50 float64 `json:"latency_p50_seconds"`
+	LatencyP95 float64 `json:"latency_p95_seconds"`
+	LatencyP99 float64 `json:"latency_p99_seconds"`
+	LatencyMax float64 `json:"latency_max_seconds"`
+
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	// OutputTokensPerSec is output tokens per second of wall time, across
+	// all tasks in flight.
+	OutputTokensPerSec float64 `json:"output_tokens_per_sec"`
+
+	// OverheadP50 and OverheadP95 are the per-task time spent outside the
+	// model: tool calls, guardrails and the runtime. Nil when the executor
+	// does not report model time (a remote agent).
+	OverheadP50 *float64 `json:"overhead_p50_seconds,omitempty"`
+	OverheadP95 *float64 `json:"overhead_p95_seconds,omitempty"`
+	// OverheadShare is the fraction of successful tasks' total time spent
+	// outside the model.
+	OverheadShare *float64 `json:"overhead_share,omitempty"`
+
+	// Failures counts failed tasks by kind: an A2A error code
+	// ("llm_rate_limited", "tool_error", ...), "http_<status>",
+	// "timeout" or "connection".
+	Failures map[string]int `json:"failures,omitempty"`
+}
+
+// sample is one finished task.
+type sample struct {
+	latency time.Duration
+	llmTime time.Duration
+	out     batch.Outcome
+	err     error
+}
+
+// Run fires tasks through exec until Requests tasks have run or Duration
+// has passed, then reports on them. A task still running when Duration
+// ends is waited for and counted. Ending ctx stops the run early; the
+// report covers what finished.
+func Run(ctx context.Context, exec batch.Executor, opts Options) (Report, error) {
+	if len(opts.Prompts) == 0 {
+		return Report{}, errors.New("at least one prompt is required")
+	}
+	if opts.Requests <= 0 && opts.Duration <= 0 {
+		return Report{}, errors.New("a request count or a duration is required")
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	start := time.Now()
+	runID := opts.RunID
+	if runID == "" {
+		runID = start.UTC().Format("20060102T150405")
+	}
+
+	feedCtx := ctx
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		feedCtx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	var (
+		mu      sync.Mutex
+		samples []sample
+		wg      sync.WaitGroup
+	)
+	queue := make(chan int)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range queue {
+				t0 := time.Now()
+				out, err := exec(ctx, "bench-"+runID+"-"+strconv.Itoa(n), opts.Prompts[n%len(opts.Prompts)])
+				s := sample{latency: time.Since(t0), llmTime: out.LLMTime, out: out, err: err}
+				if err != nil && ctx.Err() != nil {
+					continue // interrupted, not failed
+				}
+				mu.Lock()
+				samples = append(samples, s)
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for n := 0; opts.Requests <= 0 || n < opts.Requests; n++ {
+		select {
+		case queue <- n:
+		case <-feedCtx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	r := report(samples, time.Since(start))
+	r.Concurrency = concurrency
+	return r, nil
+}
+
+// report computes the Report for samples taken over wall.
+func report(samples []sample, wall time.Duration) Report {
+	r := Report{Requests: len(samples), DurationSec: wall.Seconds()}
+	var latencies, overheads []time.Duration
+	var total, outside time.Duration
+	for _, s := range samples {
+		r.InputTokens += s.out.InputTokens
+		r.OutputTokens += s.out.OutputTokens
+		if s.err != nil {
+			r.Failed++
+			if r.Failures == nil {
+				r.Failures = map[string]int{}
+			}
+			r.Failures[failureKind(s.err)]++
+			continue
+		}
+		r.Succeeded++
+		latencies = append(latencies, s.latency)
+		if s.llmTime > 0 {
+			o := max(s.latency-s.llmTime, 0)
+			overheads = append(overheads, o)
+			total += s.latency
+			outside += o
+		}
+	}
+	if wall > 0 {
+		r.Throughput = float64(r.Succeeded) / wall.Seconds()
+		r.OutputTokensPerSec = float64(r.OutputTokens) / wall.Seconds()
+	}
+	if len(latencies) > 0 {
+		sortDurations(latencies)
+		r.LatencyP50 = percentile(latencies, 0.50).Seconds()
+		r.LatencyP95 = percentile(latencies, 0.95).Seconds()
+		r.LatencyP99 = percentile(latencies, 0.99).Seconds()
+		r.LatencyMax = latencies[len(latencies)-1].Seconds()
+	}
+	if len(overheads) > 0 {
+		sortDurations(overheads)
+		p50 := percentile(overheads, 0.50).Seconds()
+		p95 := percentile(overheads, 0.95).Seconds()
+		share := float64(outside) / float64(total)
+		r.OverheadP50, r.OverheadP95, r.OverheadShare = &p50, &p95, &share
+	}
+	return r
+}
+
+func sortDurations(d []time.Duration) {
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+}
+
+// percentile returns the nearest-rank q percentile of sorted d.
+func percentile(d []time.Duration, q float64) time.Duration {
+	idx := int(math.Ceil(q*float64(len(d)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return d[idx]
+}
+
+// failureKind names the kind of a task failure for the report's
+// breakdown.
+func failureKind(err error) string {
+	var httpErr *batch.HTTPError
+	if errors.As(err, &httpErr) {
+		return fmt.Sprintf("http_%d", httpErr.StatusCode)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "connection"
+	}
+	// A2A error codes, from the agent or the in-process executor.
+	return string(coreruntime.ClassifyError(err).Code)
+}
@@ -0,0 +1,87 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/internal/batch"
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func TestRun(t *testing.T) {
+	var n atomic.Int32
+	exec := func(_ context.Context, taskID, prompt string) (batch.Outcome, error) {
+		if !strings.HasPrefix(taskID, "bench-r1-") {
+			t.Errorf("task id %q", taskID)
+		}
+		switch i := n.Add(1); {
+		case i%5 == 0:
+			return batch.Outcome{}, coreruntime.WithErrorCode(a2a.ErrorLLMRateLimited, errors.New("slow down"))
+		case prompt == "b":
+			return batch.Outcome{}, &batch.HTTPError{StatusCode: 503}
+		}
+		time.Sleep(time.Millisecond)
+		return batch.Outcome{InputTokens: 100, OutputTokens: 10, LLMTime: time.Microsecond}, nil
+	}
+
+	r, err := Run(context.Background(), exec, Options{Prompts: []string{"a", "a", "a", "b"}, Concurrency: 3, Requests: 20, RunID: "r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Requests != 20 || r.Succeeded+r.Failed != 20 || r.Concurrency != 3 {
+		t.Errorf("report = %+v", r)
+	}
+	if r.Failures["http_503"] == 0 || r.Failures["llm_rate_limited"] == 0 {
+		t.Errorf("failures = %v", r.Failures)
+	}
+	if r.LatencyP50 <= 0 || r.LatencyP95 < r.LatencyP50 || r.LatencyMax < r.LatencyP99 {
+		t.Errorf("latencies = %v %v %v %v", r.LatencyP50, r.LatencyP95, r.LatencyP99, r.LatencyMax)
+	}
+	if r.OverheadShare == nil || *r.OverheadShare <= 0.5 || r.OutputTokensPerSec <= 0 {
+		t.Errorf("overhead = %v, tokens/s = %v", r.OverheadShare, r.OutputTokensPerSec)
+	}
+
+	if _, err := Run(context.Background(), exec, Options{Prompts: []string{"a"}}); err == nil {
+		t.Error("ran without a request count or duration")
+	}
+}
+
+func TestRunDuration(t *testing.T) {
+	exec := func(ctx context.Context, _, _ string) (batch.Outcome, error) {
+		time.Sleep(5 * time.Millisecond)
+		return batch.Outcome{}, nil
+	}
+	r, err := Run(context.Background(), exec, Options{Prompts: []string{"a"}, Concurrency: 2, Duration: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Requests == 0 || r.Failed != 0 || r.OverheadP50 != nil {
+		t.Errorf("report = %+v", r)
+	}
+}
+
+func TestFailureKind(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+	_, connErr := batch.RemoteExecutor(http.DefaultClient, url, "")(context.Background(), "t", "x")
+
+	for err, want := range map[error]string{
+		&batch.HTTPError{StatusCode: 429}:                              "http_429",
+		coreruntime.WithErrorCode(a2a.ErrorToolError, errors.New("x")): "tool_error",
+		context.DeadlineExceeded:                                       "timeout",
+		connErr:                                                        "connection",
+		errors.New("boom"):                                             "internal_error",
+	} {
+		if got := failureKind(err); got != want {
+			t.Errorf("failureKind(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// Package bundle packs an agent directory into a portable, signed
+// tarball (`forge export --bundle`) and unpacks one (`forge import`).
+//
+// A bundle is a gzipped tar whose first entry is bundle.json: the agent's
+// identity and the SHA-256 of every other file in the archive. The
+// manifest is signed with the same Ed25519 keys `forge key` manages for
+// build checksums, and verified against the trusted keyring.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-skills/trust"
+)
+
+// ManifestName is the bundle's first entry.
+const ManifestName = "bundle.json"
+
+// FormatVersion is the bundle.json version this package writes and reads.
+const FormatVersion = "1"
+
+// maxBundleBytes caps the unpacked size of a bundle.
+const maxBundleBytes = 1 << 30
+
+// schedulesFile holds the schedules an agent created at runtime; it is
+// always bundled, while the rest of the memory directory is opt-in.
+const schedulesFile = ".forge/memory/SCHEDULES.md"
+
+// Manifest is bundle.json.
+type Manifest struct {
+	Version      string            `json:"version"`
+	AgentID      string            `json:"agent_id"`
+	AgentVersion string            `json:"agent_version,omitempty"`
+	ForgeVersion string            `json:"forge_version,omitempty"`
+	Created      string            `json:"created"`
+	Memory       bool              `json:"memory"`
+	Files        map[string]string `json:"files"`               // slash path -> sha256 hex
+	Signature    string            `json:"signature,omitempty"` // base64 Ed25519 over the manifest without signature and key_id
+	KeyID        string            `json:"key_id,omitempty"`
+}
+
+// FileList returns the bundled files as sorted slash paths.
+func (m *Manifest) FileList() []string {
+	return slices.Sorted(maps.Keys(m.Files))
+}
+
+// signedBytes is what the signature covers.
+func (m Manifest) signedBytes() ([]byte, error) {
+	m.Signature, m.KeyID = "", ""
+	return json.Marshal(m)
+}
+
+// Options configures Create.
+type Options struct {
+	AgentID      string
+	AgentVersion string
+	ForgeVersion string
+	// IncludeMemory adds the agent's .forge/memory snapshot. Runtime
+	// schedules are bundled either way.
+	IncludeMemory bool
+	// Key signs the bundle when set.
+	Key   ed25519.PrivateKey
+	KeyID string
+}
+
+// Files lists the files of the agent in dir that a bundle carries, as
+// sorted slash paths. Secrets, .env files, build output, VCS and
+// dependency directories and runtime state under .forge are left out.
+func Files(dir string, includeMemory bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			if skipDir(rel, includeMemory) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() && keepFile(rel, includeMemory) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func skipDir(rel string, includeMemory bool) bool {
+	switch path.Base(rel) {
+	case ".git", ".forge-output", "node_modules", "__pycache__", ".venv", "venv":
+		return true
+	}
+	if rel == ".forge" || rel == ".forge/memory" {
+		return false
+	}
+	if strings.HasPrefix(rel, ".forge/memory/") {
+		return !includeMemory
+	}
+	return strings.HasPrefix(rel, ".forge/")
+}
+
+func keepFile(rel string, includeMemory bool) bool {
+	base := path.Base(rel)
+	if (base == ".env" || strings.HasPrefix(base, ".env.")) && base != ".env.example" {
+		return false
+	}
+	if rel == schedulesFile {
+		return true
+	}
+	if strings.HasPrefix(rel, ".forge/memory/") {
+		return includeMemory
+	}
+	return !strings.HasPrefix(rel, ".forge/")
+}
+
+// Create writes a bundle of the agent in dir to w.
+func Create(w io.Writer, dir string, opts Options) (*Manifest, error) {
+	files, err := Files(dir, opts.IncludeMemory)
+	if err != nil {
+		return nil, fmt.Errorf("listing agent files: %w", err)
+	}
+	if !slices.Contains(files, "forge.yaml") {
+		return nil, fmt.Errorf("%s has no forge.yaml", dir)
+	}
+
+	m := &Manifest{
+		Version:      FormatVersion,
+		AgentID:      opts.AgentID,
+		AgentVersion: opts.AgentVersion,
+		ForgeVersion: opts.ForgeVersion,
+		Created:      time.Now().UTC().Format(time.RFC3339),
+		Memory:       opts.IncludeMemory,
+		Files:        make(map[string]string, len(files)),
+	}
+	for _, rel := range files {
+		sum, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		m.Files[rel] = sum
+	}
+	if opts.Key != nil {
+		payload, err := m.signedBytes()
+		if err != nil {
+			return nil, err
+		}
+		m.Signature = base64.StdEncoding.EncodeToString(trust.Sign(payload, opts.Key))
+		m.KeyID = opts.KeyID
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: ManifestName, Mode: 0o644, Size: int64(len(manifest)), ModTime: time.Now()}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return nil, err
+	}
+	for _, rel := range files {
+		if err := addFile(tw, dir, rel); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func addFile(tw *tar.Writer, dir, rel string) error {
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	mode := int64(0o644)
+	if st.Mode()&0o111 != 0 {
+		mode = 0o755
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: rel, Mode: mode, Size: st.Size(), ModTime: st.ModTime()}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("bundling %s: %w", rel, err)
+	}
+	return nil
+}
+
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ExtractOptions configures Extract.
+type ExtractOptions struct {
+	// Keyring verifies the signature; trust.DefaultKeyring() when nil.
+	Keyring *trust.Keyring
+	// AllowUnsigned accepts a bundle without a signature. A signature
+	// that does not verify is rejected regardless.
+	AllowUnsigned bool
+}
+
+// Extract verifies the bundle read from r and unpacks it into dest, which
+// should be a fresh directory: files are written as they are read, so a
+// failed extraction leaves a partial tree behind. It returns the
+// manifest and the ID of the key that signed it ("" when unsigned).
+//
+// Every file must be listed in the manifest with a matching checksum and
+// every listed file must be present.
+func Extract(r io.Reader, dest string, opts ExtractOptions) (*Manifest, string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("not a forge bundle: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != ManifestName {
+		return nil, "", fmt.Errorf("not a forge bundle: %s is not the first entry", ManifestName)
+	}
+	var m Manifest
+	if err := json.NewDecoder(io.LimitReader(tr, 16<<20)).Decode(&m); err != nil {
+		return nil, "", fmt.Errorf("parsing %s: %w", ManifestName, err)
+	}
+	if m.Version != FormatVersion {
+		return nil, "", fmt.Errorf("unsupported bundle version %q (this forge reads %q)", m.Version, FormatVersion)
+	}
+	keyID, err := verifySignature(m, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	seen := make(map[string]bool, len(m.Files))
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("reading bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, "", fmt.Errorf("bundle entry %q is not a regular file", hdr.Name)
+		}
+		rel := hdr.Name
+		want, ok := m.Files[rel]
+		if !ok {
+			return nil, "", fmt.Errorf("bundle entry %q is not in the manifest", rel)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) || seen[rel] {
+			return nil, "", fmt.Errorf("bundle entry %q is not allowed", rel)
+		}
+		seen[rel] = true
+		total += hdr.Size
+		if total > maxBundleBytes {
+			return nil, "", fmt.Errorf("bundle unpacks to more than %d bytes", maxBundleBytes)
+		}
+		if err := extractFile(tr, dest, rel, hdr.FileInfo().Mode().Perm(), want); err != nil {
+			return nil, "", err
+		}
+	}
+	for rel := range m.Files {
+		if !seen[rel] {
+			return nil, "", fmt.Errorf("bundle is missing %s", rel)
+		}
+	}
+	return &m, keyID, nil
+}
+
+func verifySignature(m Manifest, opts ExtractOptions) (string, error) {
+	if m.Signature == "" {
+		if !opts.AllowUnsigned {
+			return "", fmt.Errorf("bundle is not signed (pass --allow-unsigned to import it anyway)")
+		}
+		return "", nil
+	}
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return "", fmt.Errorf("decoding bundle signature: %w", err)
+	}
+	payload, err := m.signedBytes()
+	if err != nil {
+		return "", err
+	}
+	kr := opts.Keyring
+	if kr == nil {
+		kr = trust.DefaultKeyring()
+	}
+	keyID, ok := kr.Verify(payload, sig)
+	if !ok {
+		return "", fmt.Errorf("bundle signature verification failed: no trusted key matched (key_id: %s); trust the signer's key with 'forge key trust'", m.KeyID)
+	}
+	return keyID, nil
+}
+
+func extractFile(r io.Reader, dest, rel string, perm fs.FileMode, want string) error {
+	target := filepath.Join(dest, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm&0o755|0o600)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("extracting %s: %w", rel, err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %s", rel)
+	}
+	return nil
+}
+
+// LoadSigningKey reads a base64-encoded Ed25519 private key written by
+// `forge key generate`. The key ID is the file name without extension.
+func LoadSigningKey(p string) (ed25519.PrivateKey, string, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading key file: %w", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, "", fmt.Errorf("decoding key: %w", err)
+	}
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, "", fmt.Errorf("invalid private key size: %d (expected %d)", len(raw), ed25519.PrivateKeySize)
+	}
+	return ed25519.PrivateKey(raw), strings.TrimSuffix(filepath.Base(p), filepath.Ext(p)), nil
+}
@@ -0,0 +1,174 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-skills/trust"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testAgent(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"forge.yaml":                  "agent_id: demo\nversion: 1.2.0\n",
+		"skills/report/SKILL.md":      "# report\n",
+		"tools/example_tool.py":       "print('hi')\n",
+		"prompts/system.md":           "Be brief.\n",
+		".env":                        "OPENAI_API_KEY=sk-secret\n",
+		".env.production":             "X=1\n",
+		".env.example":                "OPENAI_API_KEY=\n",
+		".forge/secrets.enc":          "ciphertext",
+		".forge/serve.log":            "log",
+		".forge/memory/SCHEDULES.md":  "- daily report\n",
+		".forge/memory/MEMORY.md":     "remember this\n",
+		".forge-output/agent.json":    "{}",
+		".git/HEAD":                   "ref: refs/heads/main\n",
+		"node_modules/pkg/index.js":   "",
+		"tools/__pycache__/x.cpython": "",
+	})
+	return dir
+}
+
+func TestFiles(t *testing.T) {
+	dir := testAgent(t)
+	got, err := Files(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".env.example", ".forge/memory/SCHEDULES.md", "forge.yaml", "prompts/system.md", "skills/report/SKILL.md", "tools/example_tool.py"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Files = %q, want %q", got, want)
+	}
+
+	got, err = Files(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(got, ".forge/memory/MEMORY.md") || slices.Contains(got, ".forge/secrets.enc") {
+		t.Errorf("Files with memory = %q", got)
+	}
+}
+
+func TestCreateExtract_Signed(t *testing.T) {
+	pub, priv, err := trust.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kr := trust.NewKeyring()
+	kr.Add("release", pub)
+
+	var buf bytes.Buffer
+	m, err := Create(&buf, testAgent(t), Options{AgentID: "demo", AgentVersion: "1.2.0", IncludeMemory: true, Key: priv, KeyID: "release"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Signature == "" || m.KeyID != "release" || len(m.Files) != 7 {
+		t.Fatalf("manifest = %+v", m)
+	}
+
+	dest := t.TempDir()
+	got, keyID, err := Extract(bytes.NewReader(buf.Bytes()), dest, ExtractOptions{Keyring: kr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "release" || got.AgentID != "demo" || !got.Memory {
+		t.Errorf("extract = %+v, key %q", got, keyID)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "skills", "report", "SKILL.md"))
+	if err != nil || string(data) != "# report\n" {
+		t.Errorf("SKILL.md = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".env")); !os.IsNotExist(err) {
+		t.Errorf(".env was bundled: %v", err)
+	}
+
+	// An untrusted signer is rejected even when unsigned bundles are allowed.
+	_, _, err = Extract(bytes.NewReader(buf.Bytes()), t.TempDir(), ExtractOptions{Keyring: trust.NewKeyring(), AllowUnsigned: true})
+	if err == nil || !strings.Contains(err.Error(), "no trusted key matched") {
+		t.Errorf("untrusted signer: err = %v", err)
+	}
+}
+
+func TestExtract_Unsigned(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Create(&buf, testAgent(t), Options{AgentID: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := Extract(bytes.NewReader(buf.Bytes()), t.TempDir(), ExtractOptions{Keyring: trust.NewKeyring()})
+	if err == nil || !strings.Contains(err.Error(), "--allow-unsigned") {
+		t.Errorf("unsigned: err = %v", err)
+	}
+	if _, keyID, err := Extract(bytes.NewReader(buf.Bytes()), t.TempDir(), ExtractOptions{Keyring: trust.NewKeyring(), AllowUnsigned: true}); err != nil || keyID != "" {
+		t.Errorf("allow unsigned: key %q, err %v", keyID, err)
+	}
+}
+
+// rewrite copies a bundle, passing each file entry's content through edit.
+func rewrite(t *testing.T, in []byte, edit func(name string, content []byte) []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(tr)
+		content = edit(hdr.Name, content)
+		hdr.Size = int64(len(content))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = tw.Write(content)
+	}
+	_ = tw.Close()
+	_ = gw.Close()
+	return out.Bytes()
+}
+
+func TestExtract_Tampered(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Create(&buf, testAgent(t), Options{AgentID: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	tampered := rewrite(t, buf.Bytes(), func(name string, content []byte) []byte {
+		if name == "forge.yaml" {
+			return append(content, "egress:\n  allowed_domains: [\"*\"]\n"...)
+		}
+		return content
+	})
+	_, _, err := Extract(bytes.NewReader(tampered), t.TempDir(), ExtractOptions{AllowUnsigned: true})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch for forge.yaml") {
+		t.Errorf("tampered: err = %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+// Package experiments compares the variants of forge.yaml experiments
+// from an agent's audit log: every event of a task in an experiment
+// carries its "<experiment>/<variant>", so turns, outcomes, latency and
+// token spend can be split by variant. It backs `forge experiments
+// report`.
+package experiments
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// Variant is one experiment variant's share of an audit log.
+type Variant struct {
+	Experiment string `json:"experiment"`
+	Variant    string `json:"variant"`
+	// Tasks counts distinct tasks, Turns the messages they handled.
+	Tasks     int `json:"tasks"`
+	Turns     int `json:"turns"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	// SuccessRate is Completed over the turns that finished.
+	SuccessRate float64 `json:"success_rate"`
+
+	LatencyP50 float64 `json:"latency_p50_seconds"`
+	LatencyP95 float64 `json:"latency_p95_seconds"`
+
+	// Usage is the variant's LLM calls, tokens and estimated cost.
+	Usage coreruntime.UsageLedger `json:"usage"`
+	// CostPerTurn and TokensPerTurn average Usage over Turns.
+	CostPerTurn   float64 `json:"cost_per_turn_usd"`
+	TokensPerTurn float64 `json:"tokens_per_turn"`
+
+	tasks     map[string]bool
+	latencies []float64
+}
+
+// Report reads an NDJSON audit log and returns the variants of
+// experiment, or of every experiment when it is "", sorted by
+// experiment and variant. Lines that are not audit events are skipped.
+func Report(r io.Reader, experiment string) ([]*Variant, error) {
+	byKey := map[string]*Variant{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var ev coreruntime.AuditEvent
+		if json.Unmarshal(sc.Bytes(), &ev) != nil || ev.Experiment == "" {
+			continue
+		}
+		exp, variant, ok := strings.Cut(ev.Experiment, "/")
+		if !ok || (experiment != "" && exp != experiment) {
+			continue
+		}
+		v := byKey[ev.Experiment]
+		if v == nil {
+			v = &Variant{Experiment: exp, Variant: variant, tasks: map[string]bool{}}
+			byKey[ev.Experiment] = v
+		}
+		v.add(&ev)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	variants := make([]*Variant, 0, len(byKey))
+	for _, v := range byKey {
+		v.finish()
+		variants = append(variants, v)
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		if variants[i].Experiment != variants[j].Experiment {
+			return variants[i].Experiment < variants[j].Experiment
+		}
+		return variants[i].Variant < variants[j].Variant
+	})
+	return variants, nil
+}
+
+func (v *Variant) add(ev *coreruntime.AuditEvent) {
+	switch ev.Event {
+	case coreruntime.AuditSessionStart:
+		v.Turns++
+		v.tasks[ev.TaskID] = true
+	case coreruntime.AuditSessionEnd:
+		switch ev.Fields["state"] {
+		case string(a2a.TaskStateCompleted):
+			v.Completed++
+		case string(a2a.TaskStateFailed):
+			v.Failed++
+		}
+	case coreruntime.AuditInvocationComplete:
+		if ev.DurationMs != nil {
+			v.latencies = append(v.latencies, float64(*ev.DurationMs)/1000)
+		}
+	case coreruntime.AuditLLMCall:
+		var u llm.UsageInfo
+		if ev.InputTokens != nil {
+			u.InputTokens = *ev.InputTokens
+		}
+		if ev.OutputTokens != nil {
+			u.OutputTokens = *ev.OutputTokens
+		}
+		if ev.ReasoningTokens != nil {
+			u.ReasoningTokens = *ev.ReasoningTokens
+		}
+		v.Usage.Add(ev.Provider, ev.Model, u)
+	}
+}
+
+func (v *Variant) finish() {
+	v.Tasks = len(v.tasks)
+	if n := v.Completed + v.Failed; n > 0 {
+		v.SuccessRate = float64(v.Completed) / float64(n)
+	}
+	if len(v.latencies) > 0 {
+		sort.Float64s(v.latencies)
+		v.LatencyP50 = percentile(v.latencies, 0.50)
+		v.LatencyP95 = percentile(v.latencies, 0.95)
+	}
+	if v.Turns > 0 {
+		v.CostPerTurn = v.Usage.EstimatedCostUSD / float64(v.Turns)
+		v.TokensPerTurn = float64(v.Usage.TotalTokens) / float64(v.Turns)
+	}
+}
+
+// percentile returns the nearest-rank q percentile of sorted d.
+func percentile(d []float64, q float64) float64 {
+	idx := int(math.Ceil(q*float64(len(d)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return d[idx]
+}
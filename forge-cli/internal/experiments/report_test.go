@@ -0,0 +1,50 @@
+package experiments
+
+import (
+	"strings"
+	"testing"
+)
+
+const auditLog = `{"event":"session_start","task_id":"t1","experiment":"tone/control"}
+{"event":"llm_call","task_id":"t1","experiment":"tone/control","provider":"openai","model":"gpt-4o","input_tokens":1000,"output_tokens":200}
+{"event":"session_end","task_id":"t1","experiment":"tone/control","fields":{"state":"completed"}}
+{"event":"invocation_complete","task_id":"t1","experiment":"tone/control","duration_ms":2000}
+{"event":"session_start","task_id":"t1","experiment":"tone/control"}
+{"event":"session_end","task_id":"t1","experiment":"tone/control","fields":{"state":"failed"}}
+{"event":"invocation_complete","task_id":"t1","experiment":"tone/control","duration_ms":4000}
+{"event":"session_start","task_id":"t2","experiment":"tone/terse"}
+{"event":"llm_call","task_id":"t2","experiment":"tone/terse","provider":"openai","model":"gpt-4o-mini","input_tokens":500,"output_tokens":50}
+{"event":"session_end","task_id":"t2","experiment":"tone/terse","fields":{"state":"completed"}}
+{"event":"session_start","task_id":"t3","experiment":"model/opus"}
+{"event":"session_start","task_id":"t4"}
+not json
+`
+
+func TestReport(t *testing.T) {
+	variants, err := Report(strings.NewReader(auditLog), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != 3 || variants[0].Experiment != "model" || variants[1].Variant != "control" || variants[2].Variant != "terse" {
+		t.Fatalf("variants = %+v", variants)
+	}
+
+	c := variants[1]
+	if c.Tasks != 1 || c.Turns != 2 || c.Completed != 1 || c.Failed != 1 || c.SuccessRate != 0.5 {
+		t.Errorf("control = %+v", c)
+	}
+	if c.LatencyP50 != 2 || c.LatencyP95 != 4 {
+		t.Errorf("control latency p50 %v p95 %v", c.LatencyP50, c.LatencyP95)
+	}
+	if c.Usage.LLMCalls != 1 || c.Usage.TotalTokens != 1200 || c.TokensPerTurn != 600 || c.Usage.EstimatedCostUSD == 0 {
+		t.Errorf("control usage = %+v, %v tokens/turn", c.Usage, c.TokensPerTurn)
+	}
+
+	variants, err = Report(strings.NewReader(auditLog), "tone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != 2 {
+		t.Errorf("filtered report has %d variants, want 2", len(variants))
+	}
+}
@@ -0,0 +1,146 @@
+// Package fleet is the client `forge agents` uses to manage the agents a
+// remote `forge ui` dashboard serves.
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client talks to a forge ui dashboard's API.
+type Client struct {
+	// BaseURL is the dashboard's address, e.g. https://ui.internal:4200.
+	BaseURL string
+	// Token is sent as a bearer token when set.
+	Token string
+	// HTTP defaults to a client with a 60s timeout; starting an agent
+	// waits for it to come up.
+	HTTP *http.Client
+}
+
+// Agent is one agent as the dashboard reports it.
+type Agent struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	Model   struct {
+		Provider string `json:"provider"`
+		Name     string `json:"name"`
+	} `json:"model"`
+	Status          string     `json:"status"`
+	Port            int        `json:"port,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	Directory       string     `json:"directory"`
+	NeedsPassphrase bool       `json:"needs_passphrase,omitempty"`
+}
+
+var defaultHTTP = &http.Client{Timeout: 60 * time.Second}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.BaseURL, "/")+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = defaultHTTP
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		msg := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			msg = apiErr.Error
+		}
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("%s %s: %s: %s (pass --token or set FORGE_UI_TOKEN)", method, path, resp.Status, msg)
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	// The dashboard answers unknown paths with its single-page app.
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return fmt.Errorf("%s %s: not a JSON response (is %s a forge ui dashboard of this version?)", method, path, c.BaseURL)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
+
+// List returns every agent in the dashboard's workspace, sorted by ID.
+func (c *Client) List(ctx context.Context) ([]Agent, error) {
+	var agents []Agent
+	if err := c.do(ctx, http.MethodGet, "/api/agents", nil, &agents); err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// Get returns one agent.
+func (c *Client) Get(ctx context.Context, id string) (*Agent, error) {
+	var a Agent
+	if err := c.do(ctx, http.MethodGet, "/api/agents/"+url.PathEscape(id), nil, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Start starts an agent. passphrase unlocks encrypted secrets; when
+// empty the dashboard falls back to its own FORGE_PASSPHRASE.
+func (c *Client) Start(ctx context.Context, id, passphrase string) error {
+	var body any
+	if passphrase != "" {
+		body = map[string]string{"passphrase": passphrase}
+	}
+	return c.do(ctx, http.MethodPost, "/api/agents/"+url.PathEscape(id)+"/start", body, nil)
+}
+
+// Stop stops an agent.
+func (c *Client) Stop(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/api/agents/"+url.PathEscape(id)+"/stop", nil, nil)
+}
+
+// Logs returns up to the last lines lines of an agent's serve log.
+func (c *Client) Logs(ctx context.Context, id string, lines int) ([]string, error) {
+	var resp struct {
+		Lines []string `json:"lines"`
+	}
+	path := "/api/agents/" + url.PathEscape(id) + "/logs?lines=" + strconv.Itoa(lines)
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Lines, nil
+}
@@ -0,0 +1,89 @@
+package fleet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/agents", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"alpha","model":{"provider":"openai","name":"gpt-4o"},"status":"running","port":9100,"directory":"/w/alpha"}]`))
+	})
+	mux.HandleFunc("POST /api/agents/{id}/start", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Passphrase string `json:"passphrase"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Passphrase != "pw" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"passphrase required for encrypted secrets"}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("GET /api/agents/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"agent_id":"alpha","lines":["a","b"]}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"missing or invalid API token"}`))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestClient(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+	c := &Client{BaseURL: srv.URL + "/", Token: "tok"}
+
+	agents, err := c.List(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agents) != 1 || agents[0].ID != "alpha" || agents[0].Status != "running" || agents[0].Model.Name != "gpt-4o" {
+		t.Errorf("agents = %+v", agents)
+	}
+
+	if err := c.Start(t.Context(), "alpha", "pw"); err != nil {
+		t.Errorf("start: %v", err)
+	}
+	if err := c.Start(t.Context(), "alpha", ""); err == nil || !strings.Contains(err.Error(), "passphrase required") {
+		t.Errorf("start without passphrase: err = %v", err)
+	}
+
+	lines, err := c.Logs(t.Context(), "alpha", 50)
+	if err != nil || !slices.Equal(lines, []string{"a", "b"}) {
+		t.Errorf("logs = %q, %v", lines, err)
+	}
+
+	// An unknown route gets the dashboard's HTML, not a decode error.
+	if _, err := c.Get(t.Context(), "alpha"); err == nil || !strings.Contains(err.Error(), "forge ui dashboard") {
+		t.Errorf("get on old dashboard: err = %v", err)
+	}
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+	_, err := (&Client{BaseURL: srv.URL}).List(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "missing or invalid API token") || !strings.Contains(err.Error(), "--token") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package templaterepo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Source is where a template lives: a directory on disk, or a directory
+// in a git repository written host/owner/repo//dir@ref, as in
+// github.com/acme/forge-templates//sre@v1.2.0. The //dir and @ref parts
+// are optional; ref defaults to the repository's default branch.
+type Source struct {
+	Local string // directory on disk; the fields below are unset
+
+	Host  string
+	Owner string
+	Repo  string
+	Dir   string // slash path inside the repository
+	Ref   string
+}
+
+// String returns the source as written.
+func (s Source) String() string {
+	if s.Local != "" {
+		return s.Local
+	}
+	out := s.Host + "/" + s.Owner + "/" + s.Repo
+	if s.Dir != "" {
+		out += "//" + s.Dir
+	}
+	if s.Ref != "" {
+		out += "@" + s.Ref
+	}
+	return out
+}
+
+// IsSource reports whether a --template value names a custom template
+// rather than an embedded one: embedded names contain no slash.
+func IsSource(s string) bool {
+	return strings.ContainsAny(s, `/\`) || strings.HasPrefix(s, ".")
+}
+
+// ParseSource parses a --template value that IsSource accepts.
+func ParseSource(s string) (Source, error) {
+	if strings.HasPrefix(s, ".") || filepath.IsAbs(s) {
+		return Source{Local: s}, nil
+	}
+	if info, err := os.Stat(s); err == nil && info.IsDir() {
+		return Source{Local: s}, nil
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "git::")
+	var src Source
+	if i := strings.LastIndex(rest, "@"); i > 0 {
+		rest, src.Ref = rest[:i], rest[i+1:]
+	}
+	repo, dir, _ := strings.Cut(rest, "//")
+	parts := strings.Split(strings.TrimSuffix(repo, ".git"), "/")
+	if len(parts) != 3 || !strings.Contains(parts[0], ".") || parts[1] == "" || parts[2] == "" {
+		return Source{}, fmt.Errorf("invalid template source %q: want host/owner/repo//dir[@ref], e.g. github.com/acme/forge-templates//sre", s)
+	}
+	src.Host, src.Owner, src.Repo = parts[0], parts[1], parts[2]
+	if dir != "" {
+		src.Dir = path.Clean(dir)
+		if !filepath.IsLocal(filepath.FromSlash(src.Dir)) {
+			return Source{}, fmt.Errorf("invalid template source %q: directory %q leaves the repository", s, dir)
+		}
+	}
+	return src, nil
+}
+
+// Fetcher downloads templates from repositories.
+type Fetcher struct {
+	// GitHubAPI is the GitHub API base URL; https://api.github.com when
+	// empty. github.com sources are fetched as a tarball from it, so
+	// they need no git binary; other hosts are cloned with git.
+	GitHubAPI string
+	// GitHubToken authenticates tarball downloads, for private template
+	// repositories. `forge init` passes $GITHUB_TOKEN.
+	GitHubToken string
+	HTTP        *http.Client
+}
+
+// Fetch downloads src's template into work, a fresh directory, and
+// returns the template's directory. A Local source is returned as is.
+func (f *Fetcher) Fetch(ctx context.Context, src Source, work string) (string, error) {
+	if src.Local != "" {
+		return src.Local, nil
+	}
+	if src.Host == "github.com" {
+		if err := f.fetchGitHub(ctx, src, work); err != nil {
+			return "", err
+		}
+	} else if err := gitClone(ctx, src, work); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(work, filepath.FromSlash(src.Dir))
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("template %s: no directory %q in the repository", src, src.Dir)
+	}
+	return dir, nil
+}
+
+func (f *Fetcher) fetchGitHub(ctx context.Context, src Source, work string) error {
+	api := f.GitHubAPI
+	if api == "" {
+		api = "https://api.github.com"
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/tarball", strings.TrimSuffix(api, "/"), src.Owner, src.Repo)
+	if src.Ref != "" {
+		url += "/" + src.Ref
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if f.GitHubToken != "" {
+		req.Header.Set("Authorization", "Bearer "+f.GitHubToken)
+	}
+	client := f.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 2 * time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching template %s: %w", src, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		hint := ""
+		if resp.StatusCode == http.StatusNotFound && f.GitHubToken == "" {
+			hint = " (set GITHUB_TOKEN for a private repository)"
+		}
+		return fmt.Errorf("fetching template %s: GitHub returned %s%s", src, resp.Status, hint)
+	}
+	return extractTarball(resp.Body, work, src.Dir)
+}
+
+// extractTarball unpacks the entries under dir of a GitHub tarball,
+// whose entries all sit below one owner-repo-sha/ directory, into work.
+func extractTarball(r io.Reader, work, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("reading repository tarball: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading repository tarball: %w", err)
+		}
+		_, rel, ok := strings.Cut(hdr.Name, "/")
+		if !ok || hdr.Typeflag != tar.TypeReg || !strings.HasPrefix(rel, prefix) {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return fmt.Errorf("repository tarball entry %q is not allowed", hdr.Name)
+		}
+		if total += hdr.Size; total > maxTemplateBytes {
+			return fmt.Errorf("template is larger than %d bytes", maxTemplateBytes)
+		}
+		target := filepath.Join(work, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, io.LimitReader(tr, hdr.Size))
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("extracting %s: %w", rel, err)
+		}
+	}
+}
+
+func gitClone(ctx context.Context, src Source, work string) error {
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if src.Ref != "" {
+		args = append(args, "--branch", src.Ref)
+	}
+	args = append(args, fmt.Sprintf("https://%s/%s/%s.git", src.Host, src.Owner, src.Repo), work)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cloning template %s: %w: %s", src, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
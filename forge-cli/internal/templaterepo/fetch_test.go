@@ -0,0 +1,106 @@
+package templaterepo
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Source
+	}{
+		{"github.com/acme/forge-templates//sre", Source{Host: "github.com", Owner: "acme", Repo: "forge-templates", Dir: "sre"}},
+		{"github.com/acme/forge-templates//agents/sre@v1.2.0", Source{Host: "github.com", Owner: "acme", Repo: "forge-templates", Dir: "agents/sre", Ref: "v1.2.0"}},
+		{"https://gitlab.example.com/acme/templates.git@main", Source{Host: "gitlab.example.com", Owner: "acme", Repo: "templates", Ref: "main"}},
+		{"./my-template", Source{Local: "./my-template"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSource(tt.in)
+		if err != nil {
+			t.Errorf("ParseSource(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSource(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"acme/templates", "github.com/acme", "github.com/acme/templates//../etc"} {
+		if _, err := ParseSource(in); err == nil {
+			t.Errorf("ParseSource(%q): expected error", in)
+		}
+	}
+
+	if IsSource("sre-triage") || !IsSource("github.com/acme/t//sre") || !IsSource("./sre") {
+		t.Error("IsSource misclassified a value")
+	}
+}
+
+func TestFetchGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"acme-templates-abc123/README.md":                   "index",
+		"acme-templates-abc123/sre/forge-template.yaml":     "name: sre\n",
+		"acme-templates-abc123/sre/files/prompts/x.md":      "hi",
+		"acme-templates-abc123/support/forge-template.yaml": "name: support\n",
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		if r.URL.Path != "/repos/acme/templates/tarball/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{GitHubAPI: srv.URL, GitHubToken: "ghp_test"}
+	src, _ := ParseSource("github.com/acme/templates//sre@v1")
+	work := t.TempDir()
+	dir, err := f.Fetch(context.Background(), src, work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/repos/acme/templates/tarball/v1" || gotAuth != "Bearer ghp_test" {
+		t.Errorf("request path = %q, auth = %q", gotPath, gotAuth)
+	}
+	if dir != filepath.Join(work, "sre") {
+		t.Errorf("dir = %q", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "files", "prompts", "x.md")); err != nil {
+		t.Errorf("template file not extracted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(work, "support")); !os.IsNotExist(err) {
+		t.Error("extracted a directory outside the template")
+	}
+
+	src, _ = ParseSource("github.com/acme/templates//missing@v1")
+	if _, err := f.Fetch(context.Background(), src, t.TempDir()); err == nil {
+		t.Error("expected error for a directory not in the repository")
+	}
+	src, _ = ParseSource("github.com/acme/other//sre")
+	if _, err := f.Fetch(context.Background(), src, t.TempDir()); err == nil {
+		t.Error("expected error for a 404")
+	}
+}
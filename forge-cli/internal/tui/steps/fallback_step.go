@@ -150,6 +150,13 @@ func (s *FallbackStep) buildMultiSelect() {
 		{"OpenAI", "openai", "GPT-4o, GPT-4o-mini", "🔷"},
 		{"Anthropic", "anthropic", "Claude Sonnet, Haiku, Opus", "🟠"},
 		{"Google Gemini", "gemini", "Gemini 2.5 Flash, Pro", "🔵"},
+		{"Mistral", "mistral", "Mistral Large, Medium, Small", "🟧"},
+		{"Cohere", "cohere", "Command R+, Command R", "🟣"},
+		{"Groq", "groq", "Llama 3.3 70B, Llama 3.1 8B", "⚡"},
+		{"Together AI", "together", "Llama 3.3 70B, Qwen 2.5, DeepSeek V3", "🟦"},
+		{"OpenRouter", "openrouter", "Hundreds of models behind one key", "🔀"},
+		{"xAI", "xai", "Grok 4, Grok 3 Mini", "⚫"},
+		{"DeepSeek", "deepseek", "DeepSeek V3 chat, R1 reasoner", "🐋"},
 		{"Ollama (local)", "ollama", "Run models locally, no API key needed", "🦙"},
 	}
 
@@ -370,6 +377,20 @@ func (s *FallbackStep) Apply(ctx *tui.WizardContext) {
 			ctx.EnvVars["ANTHROPIC_API_KEY"] = fb.APIKey
 		case "gemini":
 			ctx.EnvVars["GEMINI_API_KEY"] = fb.APIKey
+		case "mistral":
+			ctx.EnvVars["MISTRAL_API_KEY"] = fb.APIKey
+		case "cohere":
+			ctx.EnvVars["COHERE_API_KEY"] = fb.APIKey
+		case "groq":
+			ctx.EnvVars["GROQ_API_KEY"] = fb.APIKey
+		case "together":
+			ctx.EnvVars["TOGETHER_API_KEY"] = fb.APIKey
+		case "openrouter":
+			ctx.EnvVars["OPENROUTER_API_KEY"] = fb.APIKey
+		case "xai":
+			ctx.EnvVars["XAI_API_KEY"] = fb.APIKey
+		case "deepseek":
+			ctx.EnvVars["DEEPSEEK_API_KEY"] = fb.APIKey
 		}
 	}
 }
@@ -0,0 +1,292 @@
+package steps
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/initializ/forge/forge-cli/internal/tui"
+	"github.com/initializ/forge/forge-cli/internal/tui/components"
+)
+
+// OllamaFuncs lets the provider step talk to the local Ollama daemon.
+// Without them, picking Ollama only pings the daemon and scaffolds the
+// default model.
+type OllamaFuncs struct {
+	// List returns the models pulled on the daemon; an error means the
+	// daemon is not reachable.
+	List func() ([]string, error)
+	// Pull downloads model, reporting progress as Ollama streams it.
+	Pull func(model string, progress func(status string, completed, total int64)) error
+	// SupportsTools reports whether model can call tools.
+	SupportsTools func(model string) (bool, error)
+}
+
+// Messages of the Ollama sub-flow.
+type (
+	ollamaModelsMsg struct {
+		models []string
+		err    error
+	}
+	ollamaPullMsg struct {
+		status           string
+		completed, total int64
+		done             bool
+		err              error
+	}
+	ollamaToolsMsg struct {
+		ok  bool
+		err error
+	}
+)
+
+// SetOllama enables daemon detection, model pulls and the tool-calling
+// check when Ollama is picked.
+func (s *ProviderStep) SetOllama(fns OllamaFuncs) {
+	s.ollama = fns
+}
+
+// startOllama detects the daemon and lists its models.
+func (s *ProviderStep) startOllama() tea.Cmd {
+	s.phase = providerOllamaDetectPhase
+	list := s.ollama.List
+	return func() tea.Msg {
+		models, err := list()
+		return ollamaModelsMsg{models: models, err: err}
+	}
+}
+
+func (s *ProviderStep) updateOllamaDetectPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
+	m, ok := msg.(ollamaModelsMsg)
+	if !ok {
+		return s, nil
+	}
+	if m.err != nil {
+		s.modelID = ollamaRecommendedModels()[0].ModelID
+		return s, s.showOllamaChoice(
+			fmt.Sprintf("Ollama is not reachable: %s", m.err),
+			components.SingleSelectItem{Label: "Retry", Value: "retry", Description: "After starting `ollama serve`", Icon: "🔄"},
+			components.SingleSelectItem{Label: "Continue anyway", Value: "anyway", Description: fmt.Sprintf("Scaffold %s and pull it later with `forge models pull`", s.modelID), Icon: "⏭"},
+		)
+	}
+	s.ollamaModels = m.models
+	return s, s.showOllamaModels()
+}
+
+// showOllamaModels lists the pulled models first, then the recommended
+// ones not pulled yet.
+func (s *ProviderStep) showOllamaModels() tea.Cmd {
+	var items []components.SingleSelectItem
+	for _, m := range s.ollamaModels {
+		items = append(items, components.SingleSelectItem{Label: m, Value: m, Description: "pulled", Icon: "✓"})
+	}
+	for _, m := range ollamaRecommendedModels() {
+		if !ollamaHasModel(s.ollamaModels, m.ModelID) {
+			items = append(items, components.SingleSelectItem{
+				Label: m.DisplayName, Value: m.ModelID,
+				Description: fmt.Sprintf("pull %s · recommended, supports tools", m.ModelID), Icon: "⬇",
+			})
+		}
+	}
+	s.modelSelector = components.NewSingleSelect(
+		items,
+		s.styles.Theme.Accent,
+		s.styles.Theme.Primary,
+		s.styles.Theme.Secondary,
+		s.styles.Theme.Dim,
+		s.styles.Theme.Border,
+		s.styles.Theme.ActiveBorder,
+		s.styles.Theme.ActiveBg,
+		s.styles.KbdKey,
+		s.styles.KbdDesc,
+	)
+	s.phase = providerOllamaModelPhase
+	return s.modelSelector.Init()
+}
+
+func (s *ProviderStep) updateOllamaModelPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "backspace" {
+		s.phase = providerSelectPhase
+		s.provider = ""
+		s.modelID = ""
+		s.selector.Reset()
+		return s, s.selector.Init()
+	}
+
+	updated, cmd := s.modelSelector.Update(msg)
+	s.modelSelector = updated
+
+	if s.modelSelector.Done() {
+		_, val := s.modelSelector.Selected()
+		s.modelID = val
+		if ollamaHasModel(s.ollamaModels, val) {
+			return s, s.checkOllamaTools()
+		}
+		return s, s.startOllamaPull()
+	}
+	return s, cmd
+}
+
+// startOllamaPull runs the pull in the background; progress arrives as
+// ollamaPullMsg, one per waitOllamaPull.
+func (s *ProviderStep) startOllamaPull() tea.Cmd {
+	s.phase = providerOllamaPullPhase
+	s.pull = ollamaPullMsg{status: "starting"}
+	ch := make(chan ollamaPullMsg, 16)
+	s.pullCh = ch
+	pull, model := s.ollama.Pull, s.modelID
+	go func() {
+		err := pull(model, func(status string, completed, total int64) {
+			select {
+			case ch <- ollamaPullMsg{status: status, completed: completed, total: total}:
+			default: // the view only needs the latest update
+			}
+		})
+		ch <- ollamaPullMsg{done: true, err: err}
+		close(ch)
+	}()
+	return waitOllamaPull(ch)
+}
+
+func waitOllamaPull(ch <-chan ollamaPullMsg) tea.Cmd {
+	return func() tea.Msg { return <-ch }
+}
+
+func (s *ProviderStep) updateOllamaPullPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
+	m, ok := msg.(ollamaPullMsg)
+	if !ok {
+		return s, nil
+	}
+	if !m.done {
+		s.pull = m
+		return s, waitOllamaPull(s.pullCh)
+	}
+	s.pullCh = nil
+	if m.err != nil {
+		return s, s.showOllamaChoice(
+			fmt.Sprintf("Pulling %s failed: %s", s.modelID, m.err),
+			components.SingleSelectItem{Label: "Pick another model", Value: "models", Icon: "↩"},
+			components.SingleSelectItem{Label: "Retry", Value: "pull", Icon: "🔄"},
+		)
+	}
+	s.ollamaModels = append(s.ollamaModels, s.modelID)
+	return s, s.checkOllamaTools()
+}
+
+// checkOllamaTools asks the daemon whether the picked model can call
+// tools; an agent on a model that cannot fails its first tool call.
+func (s *ProviderStep) checkOllamaTools() tea.Cmd {
+	s.phase = providerOllamaToolsPhase
+	check, model := s.ollama.SupportsTools, s.modelID
+	return func() tea.Msg {
+		ok, err := check(model)
+		return ollamaToolsMsg{ok: ok, err: err}
+	}
+}
+
+func (s *ProviderStep) updateOllamaToolsPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
+	m, ok := msg.(ollamaToolsMsg)
+	if !ok {
+		return s, nil
+	}
+	if m.err == nil && m.ok {
+		s.complete = true
+		return s, func() tea.Msg { return tui.StepCompleteMsg{} }
+	}
+	reason := fmt.Sprintf("%s does not support tool calling, so the agent cannot use its tools or skills", s.modelID)
+	if m.err != nil {
+		reason = fmt.Sprintf("Could not check %s for tool calling: %s", s.modelID, m.err)
+	}
+	return s, s.showOllamaChoice(reason,
+		components.SingleSelectItem{Label: "Pick another model", Value: "models", Icon: "↩"},
+		components.SingleSelectItem{Label: "Use it anyway", Value: "anyway", Description: "Fine for chat-only agents", Icon: "⏭"},
+	)
+}
+
+// showOllamaChoice explains a problem and offers ways on.
+func (s *ProviderStep) showOllamaChoice(problem string, items ...components.SingleSelectItem) tea.Cmd {
+	s.ollamaProblem = problem
+	s.authMethodSelector = components.NewSingleSelect(
+		items,
+		s.styles.Theme.Accent,
+		s.styles.Theme.Primary,
+		s.styles.Theme.Secondary,
+		s.styles.Theme.Dim,
+		s.styles.Theme.Border,
+		s.styles.Theme.ActiveBorder,
+		s.styles.Theme.ActiveBg,
+		s.styles.KbdKey,
+		s.styles.KbdDesc,
+	)
+	s.phase = providerOllamaChoicePhase
+	return s.authMethodSelector.Init()
+}
+
+func (s *ProviderStep) updateOllamaChoicePhase(msg tea.Msg) (tui.Step, tea.Cmd) {
+	updated, cmd := s.authMethodSelector.Update(msg)
+	s.authMethodSelector = updated
+
+	if s.authMethodSelector.Done() {
+		_, val := s.authMethodSelector.Selected()
+		s.ollamaProblem = ""
+		switch val {
+		case "retry":
+			return s, s.startOllama()
+		case "models":
+			return s, s.showOllamaModels()
+		case "pull":
+			return s, s.startOllamaPull()
+		case "anyway":
+			s.complete = true
+			return s, func() tea.Msg { return tui.StepCompleteMsg{} }
+		}
+	}
+	return s, cmd
+}
+
+// viewOllama renders the Ollama phases.
+func (s *ProviderStep) viewOllama(width int) string {
+	switch s.phase {
+	case providerOllamaDetectPhase:
+		return "  " + s.styles.AccentTxt.Render("⣾ Looking for Ollama...") + "\n"
+	case providerOllamaModelPhase:
+		return s.modelSelector.View(width)
+	case providerOllamaPullPhase:
+		line := "  " + s.styles.AccentTxt.Render("⣾ Pulling "+s.modelID) + "\n"
+		if s.pull.total > 0 {
+			line += "  " + progressBar(s.pull.completed, s.pull.total, 30) + " " +
+				s.styles.DimTxt.Render(fmt.Sprintf("%3d%% · %s", s.pull.completed*100/s.pull.total, s.pull.status)) + "\n"
+		} else {
+			line += "  " + s.styles.DimTxt.Render(s.pull.status) + "\n"
+		}
+		return line
+	case providerOllamaToolsPhase:
+		return "  " + s.styles.AccentTxt.Render(fmt.Sprintf("⣾ Checking %s supports tool calling...", s.modelID)) + "\n"
+	case providerOllamaChoicePhase:
+		return "  " + s.styles.WarningTxt.Render(s.ollamaProblem) + "\n\n" + s.authMethodSelector.View(width)
+	}
+	return ""
+}
+
+// progressBar renders completed/total as a bar width cells wide.
+func progressBar(completed, total int64, width int) string {
+	filled := int(completed * int64(width) / total)
+	filled = max(0, min(filled, width))
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// ollamaRecommendedModels are the catalog's tool-capable Ollama models.
+func ollamaRecommendedModels() []modelOption {
+	return catalogModelOptions("ollama")
+}
+
+// ollamaHasModel matches name against pulled models, treating an
+// untagged name as :latest the way the Ollama CLI does.
+func ollamaHasModel(models []string, name string) bool {
+	if !strings.Contains(name, ":") {
+		name += ":latest"
+	}
+	return slices.Contains(models, name)
+}
@@ -0,0 +1,110 @@
+package steps
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/initializ/forge/forge-cli/internal/tui"
+)
+
+var (
+	keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+	keyDown  = tea.KeyMsg{Type: tea.KeyDown}
+)
+
+// drive feeds msg to the step and then the messages its commands
+// produce, until a command yields nothing or the step completes.
+func drive(s *ProviderStep, msg tea.Msg) {
+	for msg != nil && !s.complete {
+		_, cmd := s.Update(msg)
+		if cmd == nil {
+			return
+		}
+		msg = cmd()
+		if _, ok := msg.(tui.StepCompleteMsg); ok {
+			return
+		}
+	}
+}
+
+func newOllamaStep(fns OllamaFuncs) *ProviderStep {
+	s := NewProviderStep(tui.NewStyleSet(tui.DarkTheme), nil)
+	s.SetOllama(fns)
+	s.provider = "ollama"
+	return s
+}
+
+func TestProviderStep_OllamaPullsRecommendedModel(t *testing.T) {
+	var pulled string
+	s := newOllamaStep(OllamaFuncs{
+		List: func() ([]string, error) { return []string{"llama3:latest"}, nil },
+		Pull: func(model string, progress func(string, int64, int64)) error {
+			pulled = model
+			progress("pulling abc", 50, 100)
+			return nil
+		},
+		SupportsTools: func(model string) (bool, error) { return model == "qwen2.5:7b", nil },
+	})
+
+	drive(s, s.startOllama()())
+	if s.phase != providerOllamaModelPhase {
+		t.Fatalf("phase = %d, want the model list", s.phase)
+	}
+	view := s.View(80)
+	if !strings.Contains(view, "llama3:latest") || !strings.Contains(view, "Qwen 2.5 7B") {
+		t.Errorf("model list should show the pulled and recommended models:\n%s", view)
+	}
+
+	// llama3:latest is first; the first recommended model is next.
+	drive(s, keyDown)
+	drive(s, keyEnter)
+	if pulled != "qwen2.5:7b" || !s.complete {
+		t.Fatalf("pulled = %q, complete = %v", pulled, s.complete)
+	}
+	ctx := tui.NewWizardContext()
+	s.Apply(ctx)
+	if ctx.Provider != "ollama" || ctx.ModelName != "qwen2.5:7b" {
+		t.Errorf("Apply: provider = %q, model = %q", ctx.Provider, ctx.ModelName)
+	}
+}
+
+func TestProviderStep_OllamaModelWithoutTools(t *testing.T) {
+	s := newOllamaStep(OllamaFuncs{
+		List:          func() ([]string, error) { return []string{"llama3:latest"}, nil },
+		SupportsTools: func(string) (bool, error) { return false, nil },
+	})
+	drive(s, s.startOllama()())
+	drive(s, keyEnter) // llama3:latest
+	if s.phase != providerOllamaChoicePhase || !strings.Contains(s.View(80), "does not support tool calling") {
+		t.Fatalf("expected the tool-calling warning, phase = %d:\n%s", s.phase, s.View(80))
+	}
+
+	drive(s, keyEnter) // pick another model
+	if s.phase != providerOllamaModelPhase {
+		t.Fatalf("phase = %d, want the model list again", s.phase)
+	}
+	drive(s, keyEnter)
+	drive(s, keyDown)
+	drive(s, keyEnter) // use it anyway
+	if !s.complete || s.modelID != "llama3:latest" {
+		t.Errorf("complete = %v, model = %q", s.complete, s.modelID)
+	}
+}
+
+func TestProviderStep_OllamaDaemonDown(t *testing.T) {
+	s := newOllamaStep(OllamaFuncs{
+		List: func() ([]string, error) { return nil, errors.New("connection refused") },
+	})
+	drive(s, s.startOllama()())
+	if !strings.Contains(s.View(80), "Ollama is not reachable: connection refused") {
+		t.Fatalf("view:\n%s", s.View(80))
+	}
+	drive(s, keyDown)
+	drive(s, keyEnter) // continue anyway
+	if !s.complete || s.modelID != ollamaRecommendedModels()[0].ModelID {
+		t.Errorf("complete = %v, model = %q", s.complete, s.modelID)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/initializ/forge/forge-cli/internal/tui/components"
 
 	"github.com/initializ/forge/forge-core/catalog"
+	"github.com/initializ/forge/forge-core/llm/providers"
 )
 
 type providerPhase int
@@ -31,6 +32,13 @@ const (
 	providerCustomShapePhase
 	providerCustomModelPhase
 	providerCustomAuthPhase
+	// The Ollama phases detect the daemon, pick or pull a model and
+	// check it can call tools; see provider_ollama.go.
+	providerOllamaDetectPhase
+	providerOllamaModelPhase
+	providerOllamaPullPhase
+	providerOllamaToolsPhase
+	providerOllamaChoicePhase
 	providerDonePhase
 )
 
@@ -55,7 +63,15 @@ var (
 
 // openAIModelOptions projects the catalog's OpenAI models into modelOption.
 func openAIModelOptions() []modelOption {
-	p, _ := catalog.ProviderByID("openai")
+	return catalogModelOptions("openai")
+}
+
+// catalogModelOptions projects a catalog provider's curated models into
+// modelOption. Used for OpenAI and the OpenAI-compatible presets (Groq,
+// Together, OpenRouter, xAI, DeepSeek), whose catalogs are too broad to
+// default blindly.
+func catalogModelOptions(providerID string) []modelOption {
+	p, _ := catalog.ProviderByID(providerID)
 	out := make([]modelOption, 0, len(p.Models))
 	for _, m := range p.Models {
 		out = append(out, modelOption{DisplayName: m.Label, ModelID: m.ModelID})
@@ -106,6 +122,11 @@ type ProviderStep struct {
 	validating        bool
 	valErr            error
 	oauthRunning      bool
+	ollama            OllamaFuncs
+	ollamaModels      []string // models pulled on the daemon
+	ollamaProblem     string   // shown above the choice in providerOllamaChoicePhase
+	pull              ollamaPullMsg
+	pullCh            <-chan ollamaPullMsg
 }
 
 // NewProviderStep creates a new provider selection step.
@@ -167,7 +188,7 @@ func (s *ProviderStep) Update(msg tea.Msg) (tui.Step, tea.Cmd) {
 			updated, cmd := s.authMethodSelector.Update(wsm)
 			s.authMethodSelector = updated
 			return s, cmd
-		case providerModelPhase:
+		case providerModelPhase, providerOllamaModelPhase:
 			updated, cmd := s.modelSelector.Update(wsm)
 			s.modelSelector = updated
 			return s, cmd
@@ -198,6 +219,16 @@ func (s *ProviderStep) Update(msg tea.Msg) (tui.Step, tea.Cmd) {
 		return s.updateCustomModelPhase(msg)
 	case providerCustomAuthPhase:
 		return s.updateCustomAuthPhase(msg)
+	case providerOllamaDetectPhase:
+		return s.updateOllamaDetectPhase(msg)
+	case providerOllamaModelPhase:
+		return s.updateOllamaModelPhase(msg)
+	case providerOllamaPullPhase:
+		return s.updateOllamaPullPhase(msg)
+	case providerOllamaToolsPhase:
+		return s.updateOllamaToolsPhase(msg)
+	case providerOllamaChoicePhase:
+		return s.updateOllamaChoicePhase(msg)
 	}
 
 	return s, nil
@@ -213,6 +244,9 @@ func (s *ProviderStep) updateSelectPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
 
 		switch val {
 		case "ollama":
+			if s.ollama.List != nil {
+				return s, s.startOllama()
+			}
 			// Skip key, go to validation
 			s.phase = providerValidatingPhase
 			s.validating = true
@@ -258,7 +292,7 @@ func (s *ProviderStep) updateSelectPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
 			s.authMethod = "apikey"
 			fallthrough
 		default:
-			// openai, anthropic, gemini → ask for key
+			// openai, anthropic, gemini, mistral, cohere, presets → ask for key
 			s.phase = providerKeyPhase
 			label := fmt.Sprintf("%s API Key", providerDisplayName(val))
 			s.keyInput = components.NewSecretInput(
@@ -421,8 +455,9 @@ func (s *ProviderStep) updateValidatingPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
 			s.complete = true
 			return s, func() tea.Msg { return tui.StepCompleteMsg{} }
 		}
-		// Validation passed — show model selection for OpenAI
-		if s.provider == "openai" {
+		// Validation passed — show model selection for OpenAI and
+		// the OpenAI-compatible presets.
+		if s.provider == "openai" || isPresetProvider(s.provider) {
 			return s, s.showModelSelector()
 		}
 		s.complete = true
@@ -432,12 +467,16 @@ func (s *ProviderStep) updateValidatingPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
 	return s, nil
 }
 
-// showModelSelector sets up the model selection phase for OpenAI.
+// showModelSelector sets up the model selection phase for OpenAI and
+// the OpenAI-compatible presets.
 func (s *ProviderStep) showModelSelector() tea.Cmd {
 	var models []modelOption
-	if s.authMethod == "oauth" {
+	switch {
+	case s.provider != "openai":
+		models = catalogModelOptions(s.provider)
+	case s.authMethod == "oauth":
 		models = openAIOAuthModels
-	} else {
+	default:
 		models = openAIAPIKeyModels
 	}
 
@@ -472,8 +511,8 @@ func (s *ProviderStep) updateModelPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
 	if s.modelSelector.Done() {
 		_, val := s.modelSelector.Selected()
 		s.modelID = val
-		// Show org ID prompt for API key auth
-		if s.authMethod == "apikey" {
+		// Show org ID prompt for OpenAI API key auth
+		if s.provider == "openai" && s.authMethod == "apikey" {
 			return s, s.showOrgIDPrompt()
 		}
 		s.complete = true
@@ -674,6 +713,9 @@ func (s *ProviderStep) View(width int) string {
 		return s.customShapeSelect.View(width)
 	case providerCustomAuthPhase:
 		return s.keyInput.View(width)
+	case providerOllamaDetectPhase, providerOllamaModelPhase, providerOllamaPullPhase,
+		providerOllamaToolsPhase, providerOllamaChoicePhase:
+		return s.viewOllama(width)
 	}
 	return ""
 }
@@ -694,6 +736,10 @@ func (s *ProviderStep) Summary() string {
 		return name + " · Claude Sonnet 4"
 	case "gemini":
 		return name + " · Gemini 2.5 Flash"
+	case "mistral":
+		return name + " · Mistral Large"
+	case "cohere":
+		return name + " · Command R+"
 	case "ollama":
 		return name + " · llama3"
 	case "custom":
@@ -726,6 +772,14 @@ func (s *ProviderStep) Apply(ctx *tui.WizardContext) {
 			ctx.EnvVars["ANTHROPIC_API_KEY"] = s.apiKey
 		case "gemini":
 			ctx.EnvVars["GEMINI_API_KEY"] = s.apiKey
+		case "mistral":
+			ctx.EnvVars["MISTRAL_API_KEY"] = s.apiKey
+		case "cohere":
+			ctx.EnvVars["COHERE_API_KEY"] = s.apiKey
+		default:
+			if p, ok := catalog.ProviderByID(s.provider); ok && p.APIKeyEnvVar != "" {
+				ctx.EnvVars[p.APIKeyEnvVar] = s.apiKey
+			}
 		}
 	}
 	if s.orgID != "" {
@@ -746,9 +800,23 @@ func modelDisplayName(modelID string) string {
 			return m.DisplayName
 		}
 	}
+	for _, p := range catalog.AllProviders() {
+		for _, m := range p.Models {
+			if m.ModelID == modelID {
+				return m.Label
+			}
+		}
+	}
 	return modelID
 }
 
+// isPresetProvider reports whether provider is one of the
+// OpenAI-compatible presets (Groq, Together, OpenRouter, xAI, DeepSeek).
+func isPresetProvider(provider string) bool {
+	_, ok := providers.LookupPreset(provider)
+	return ok
+}
+
 func providerDisplayName(provider string) string {
 	switch provider {
 	case "openai":
@@ -757,10 +825,17 @@ func providerDisplayName(provider string) string {
 		return "Anthropic"
 	case "gemini":
 		return "Google Gemini"
+	case "mistral":
+		return "Mistral"
+	case "cohere":
+		return "Cohere"
 	case "ollama":
 		return "Ollama"
 	case "custom":
 		return "Custom"
 	}
+	if p, ok := catalog.ProviderByID(provider); ok {
+		return p.Label
+	}
 	return provider
 }
@@ -0,0 +1,356 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Alerts: forge.yaml `alerts:` rules put thresholds on the agent's
+// token spend, task failure rate and task latency. A background monitor
+// collects those from the event bus and, on each tick, notifies a
+// notify target or webhook when a rule is breached, again after its
+// cooldown while it stays breached, and once more when it resolves.
+
+// Alert states.
+const (
+	alertFiring   = "firing"
+	alertResolved = "resolved"
+)
+
+// alertWebhookTimeout bounds one webhook delivery.
+const alertWebhookTimeout = 10 * time.Second
+
+// alertPayload is the JSON body POSTed to an alert webhook.
+type alertPayload struct {
+	Rule      string    `json:"rule"`
+	Metric    string    `json:"metric"`
+	State     string    `json:"state"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Agent     string    `json:"agent"`
+	Time      time.Time `json:"time"`
+}
+
+// taskSample is one finished task, for the windowed task metrics.
+type taskSample struct {
+	at       time.Time
+	failed   bool
+	duration time.Duration // 0 when unknown
+}
+
+// alertState is what the monitor remembers about one rule.
+type alertState struct {
+	firing   bool
+	lastSent time.Time
+}
+
+// alertMonitor evaluates alert rules over the usage and task outcomes
+// it collects from the event bus.
+type alertMonitor struct {
+	rules    []types.AlertRule // defaults applied
+	interval time.Duration
+	agentID  string
+	notify   builtins.Notifier // nil without notify targets
+	audit    *coreruntime.AuditLogger
+	logger   coreruntime.Logger
+	client   *http.Client
+	now      func() time.Time
+
+	mu        sync.Mutex
+	day       string // UTC date the daily counters cover
+	dayTokens int
+	dayCost   float64
+	tasks     []taskSample
+	state     map[string]*alertState
+}
+
+// newAlertMonitor builds the monitor for cfg, or returns nil when no
+// rules are declared.
+func newAlertMonitor(cfg types.AlertsConfig, agentID string, notify builtins.Notifier, audit *coreruntime.AuditLogger, logger coreruntime.Logger) *alertMonitor {
+	if len(cfg.Rules) == 0 {
+		return nil
+	}
+	m := &alertMonitor{
+		interval: cfg.Interval,
+		agentID:  agentID,
+		notify:   notify,
+		audit:    audit,
+		logger:   logger,
+		client:   &http.Client{Timeout: alertWebhookTimeout},
+		now:      time.Now,
+		state:    map[string]*alertState{},
+	}
+	if m.interval == 0 {
+		m.interval = types.DefaultAlertInterval
+	}
+	for _, rule := range cfg.Rules {
+		if rule.Window == 0 {
+			rule.Window = types.DefaultAlertWindow
+		}
+		if rule.MinTasks == 0 {
+			rule.MinTasks = types.DefaultAlertMinTasks
+		}
+		if rule.Cooldown == 0 {
+			rule.Cooldown = types.DefaultAlertCooldown
+		}
+		m.rules = append(m.rules, rule)
+		m.state[rule.ID] = &alertState{}
+	}
+	return m
+}
+
+// startAlertMonitor subscribes the monitor to the event bus and
+// evaluates its rules until ctx ends. No-op without alert rules.
+func (r *Runner) startAlertMonitor(ctx context.Context, auditLogger *coreruntime.AuditLogger, agentID string) {
+	var notify builtins.Notifier
+	if r.notify != nil {
+		notify = r.notify
+	}
+	m := newAlertMonitor(r.cfg.Config.Alerts, agentID, notify, auditLogger, r.logger)
+	if m == nil {
+		return
+	}
+	r.events.Subscribe(m.observe, coreruntime.TopicLLMCall, coreruntime.TopicTaskFinished, coreruntime.TopicScheduleCompleted)
+	go m.run(ctx)
+	r.logger.Info("alert monitor started", map[string]any{"rules": len(m.rules), "interval": m.interval.String()})
+}
+
+// observe folds an event into the monitor's counters.
+func (m *alertMonitor) observe(_ context.Context, e coreruntime.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch e.Topic {
+	case coreruntime.TopicLLMCall:
+		if e.Hook == nil || e.Hook.Response == nil {
+			return
+		}
+		model, provider := e.Hook.Model, e.Hook.Provider
+		if rt := e.Hook.Response.Route; rt != nil {
+			model, provider = rt.Model, rt.Provider
+		}
+		var call coreruntime.UsageLedger
+		call.Add(provider, model, e.Hook.Response.Usage)
+		m.rollDay(e.Time)
+		m.dayTokens += call.TotalTokens
+		m.dayCost += call.EstimatedCostUSD
+	case coreruntime.TopicTaskFinished:
+		state, _ := e.Fields["state"].(string)
+		ms, _ := e.Fields["duration_ms"].(int64)
+		m.tasks = append(m.tasks, taskSample{at: e.Time, failed: state == string(a2a.TaskStateFailed), duration: time.Duration(ms) * time.Millisecond})
+	case coreruntime.TopicScheduleCompleted:
+		ok, _ := e.Fields["success"].(bool)
+		ms, _ := e.Fields["duration_ms"].(int64)
+		m.tasks = append(m.tasks, taskSample{at: e.Time, failed: !ok, duration: time.Duration(ms) * time.Millisecond})
+	}
+}
+
+// rollDay resets the daily counters at midnight UTC. The caller holds mu.
+func (m *alertMonitor) rollDay(t time.Time) {
+	if day := t.UTC().Format(time.DateOnly); day != m.day {
+		m.day, m.dayTokens, m.dayCost = day, 0, 0
+	}
+}
+
+func (m *alertMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.evaluate(ctx)
+		}
+	}
+}
+
+// evaluate checks every rule once and sends what changed.
+func (m *alertMonitor) evaluate(ctx context.Context) {
+	now := m.now()
+	type send struct {
+		rule  types.AlertRule
+		state string
+		value float64
+	}
+	var sends []send
+
+	m.mu.Lock()
+	m.pruneTasks(now)
+	for _, rule := range m.rules {
+		value, ok := m.metric(rule, now)
+		if !ok {
+			// Too little data to judge: keep the rule's state.
+			continue
+		}
+		st := m.state[rule.ID]
+		switch breached := value > rule.Threshold; {
+		case breached && (!st.firing || now.Sub(st.lastSent) >= rule.Cooldown):
+			st.firing, st.lastSent = true, now
+			sends = append(sends, send{rule, alertFiring, value})
+		case !breached && st.firing:
+			st.firing = false
+			sends = append(sends, send{rule, alertResolved, value})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, s := range sends {
+		m.send(ctx, s.rule, s.state, s.value, now)
+	}
+}
+
+// metric computes rule's metric at now. ok is false when a task
+// metric's window has fewer than MinTasks tasks. The caller holds mu.
+func (m *alertMonitor) metric(rule types.AlertRule, now time.Time) (value float64, ok bool) {
+	switch rule.Metric {
+	case types.AlertMetricDailyTokens:
+		m.rollDay(now)
+		return float64(m.dayTokens), true
+	case types.AlertMetricDailyCost:
+		m.rollDay(now)
+		return m.dayCost, true
+	}
+
+	var total, failed int
+	var durations []time.Duration
+	for _, t := range m.tasks {
+		if now.Sub(t.at) > rule.Window {
+			continue
+		}
+		total++
+		if t.failed {
+			failed++
+		}
+		if t.duration > 0 {
+			durations = append(durations, t.duration)
+		}
+	}
+	switch rule.Metric {
+	case types.AlertMetricTaskFailureRate:
+		if total < rule.MinTasks {
+			return 0, false
+		}
+		return float64(failed) / float64(total), true
+	case types.AlertMetricP95Latency:
+		if len(durations) < rule.MinTasks {
+			return 0, false
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		idx := int(math.Ceil(0.95*float64(len(durations)))) - 1
+		return durations[idx].Seconds(), true
+	}
+	return 0, false
+}
+
+// pruneTasks drops samples older than every rule's window. The caller
+// holds mu.
+func (m *alertMonitor) pruneTasks(now time.Time) {
+	var longest time.Duration
+	for _, rule := range m.rules {
+		if rule.Window > longest {
+			longest = rule.Window
+		}
+	}
+	keep := m.tasks[:0]
+	for _, t := range m.tasks {
+		if now.Sub(t.at) <= longest {
+			keep = append(keep, t)
+		}
+	}
+	m.tasks = keep
+}
+
+// send delivers one alert to the rule's notify target and webhook and
+// audits the outcome.
+func (m *alertMonitor) send(ctx context.Context, rule types.AlertRule, state string, value float64, now time.Time) {
+	fields := map[string]any{
+		"rule":      rule.ID,
+		"metric":    rule.Metric,
+		"state":     state,
+		"value":     value,
+		"threshold": rule.Threshold,
+	}
+	if rule.Notify != "" {
+		if err := m.sendNotify(ctx, rule, state, value); err != nil {
+			fields["notify_error"] = err.Error()
+		}
+	}
+	if rule.WebhookEnv != "" {
+		if err := m.sendWebhook(ctx, rule, state, value, now); err != nil {
+			fields["webhook_error"] = err.Error()
+		}
+	}
+	m.logger.Warn("alert "+state, fields)
+	if m.audit != nil {
+		m.audit.EmitFromContext(ctx, coreruntime.AuditEvent{Event: coreruntime.AuditAlert, Fields: fields})
+	}
+}
+
+func (m *alertMonitor) sendNotify(ctx context.Context, rule types.AlertRule, state string, value float64) error {
+	if m.notify == nil {
+		return ErrNotifyUnavailable
+	}
+	severity, title := "warning", fmt.Sprintf("Alert %s: %s", rule.ID, rule.Metric)
+	if state == alertResolved {
+		severity, title = "info", fmt.Sprintf("Resolved %s: %s", rule.ID, rule.Metric)
+	}
+	return m.notify.Notify(ctx, builtins.NotifyRequest{
+		Target:   rule.Notify,
+		Title:    title,
+		Message:  fmt.Sprintf("%s is %s (threshold %s)", rule.Metric, formatAlertValue(value), formatAlertValue(rule.Threshold)),
+		Severity: severity,
+		Fields:   map[string]string{"agent": m.agentID, "state": state},
+	}, "alert")
+}
+
+func (m *alertMonitor) sendWebhook(ctx context.Context, rule types.AlertRule, state string, value float64, now time.Time) error {
+	url := os.Getenv(rule.WebhookEnv)
+	if url == "" {
+		return fmt.Errorf("%s is unset", rule.WebhookEnv)
+	}
+	body, err := json.Marshal(alertPayload{
+		Rule:      rule.ID,
+		Metric:    rule.Metric,
+		State:     state,
+		Value:     value,
+		Threshold: rule.Threshold,
+		Agent:     m.agentID,
+		Time:      now.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("alert webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alert webhook: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// formatAlertValue prints a metric value without float noise.
+func formatAlertValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
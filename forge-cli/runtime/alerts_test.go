@@ -0,0 +1,121 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestAlertMonitor_DailyTokens(t *testing.T) {
+	n, sent, _ := newTestNotifier(t, types.NotifyConfig{Targets: map[string]types.NotifyTarget{
+		"oncall": {Channel: "slack", Target: "C0123"},
+	}})
+	var audit bytes.Buffer
+	m := newAlertMonitor(types.AlertsConfig{Rules: []types.AlertRule{
+		{ID: "spend", Metric: types.AlertMetricDailyTokens, Threshold: 1000, Notify: "oncall", Cooldown: time.Hour},
+	}}, "support-bot", n, coreruntime.NewAuditLogger(&audit), &captureLogger{})
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	call := func(tokens int) {
+		m.observe(ctx, coreruntime.Event{Topic: coreruntime.TopicLLMCall, Time: now, Hook: &coreruntime.HookContext{
+			Response: &llm.ChatResponse{Usage: llm.UsageInfo{TotalTokens: tokens}},
+		}})
+	}
+	call(600)
+	m.evaluate(ctx)
+	if len(*sent) != 0 {
+		t.Fatalf("fired below the threshold: %+v", *sent)
+	}
+
+	call(600)
+	m.evaluate(ctx)
+	m.evaluate(ctx) // still breached, inside the cooldown: no repeat
+	if len(*sent) != 1 || !strings.Contains((*sent)[0].text, "daily_tokens is 1200 (threshold 1000)") {
+		t.Fatalf("sent = %+v", *sent)
+	}
+
+	now = now.Add(2 * time.Hour)
+	m.evaluate(ctx)
+	if len(*sent) != 2 {
+		t.Fatalf("no repeat after the cooldown: %+v", *sent)
+	}
+
+	// Midnight UTC resets the count, which resolves the alert.
+	now = time.Date(2026, 10, 17, 0, 1, 0, 0, time.UTC)
+	m.evaluate(ctx)
+	if len(*sent) != 3 || !strings.Contains((*sent)[2].text, "Resolved spend") {
+		t.Fatalf("sent = %+v", *sent)
+	}
+	if got := strings.Count(audit.String(), `"event":"alert"`); got != 3 {
+		t.Errorf("audited %d alert events, want 3:\n%s", got, audit.String())
+	}
+}
+
+func TestAlertMonitor_TaskMetricsWebhook(t *testing.T) {
+	var posted []alertPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		var p alertPayload
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		posted = append(posted, p)
+	}))
+	defer srv.Close()
+	t.Setenv("ALERT_WEBHOOK_URL", srv.URL)
+
+	m := newAlertMonitor(types.AlertsConfig{Rules: []types.AlertRule{
+		{ID: "failures", Metric: types.AlertMetricTaskFailureRate, Threshold: 0.5, MinTasks: 4, WebhookEnv: "ALERT_WEBHOOK_URL"},
+		{ID: "slow", Metric: types.AlertMetricP95Latency, Threshold: 30, MinTasks: 4, WebhookEnv: "ALERT_WEBHOOK_URL"},
+	}}, "support-bot", nil, nil, &captureLogger{})
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	finish := func(state string, d time.Duration) {
+		m.observe(ctx, coreruntime.Event{Topic: coreruntime.TopicTaskFinished, Time: now, Fields: map[string]any{
+			"state": state, "duration_ms": d.Milliseconds(),
+		}})
+	}
+	finish("failed", 40*time.Second)
+	finish("failed", time.Second)
+	finish("failed", time.Second)
+	m.evaluate(ctx)
+	if len(posted) != 0 {
+		t.Fatalf("fired on fewer than min_tasks tasks: %+v", posted)
+	}
+
+	finish("completed", 2*time.Second)
+	m.evaluate(ctx)
+	if len(posted) != 2 {
+		t.Fatalf("posted = %+v, want the failure rate (0.75) and p95 (40s) alerts", posted)
+	}
+	for _, p := range posted {
+		if p.State != alertFiring || p.Agent != "support-bot" {
+			t.Errorf("payload = %+v", p)
+		}
+		if p.Rule == "failures" && p.Value != 0.75 || p.Rule == "slow" && p.Value != 40 {
+			t.Errorf("payload = %+v", p)
+		}
+	}
+
+	// Outside the window the samples age out; too little data keeps
+	// the rules' state rather than resolving them.
+	now = now.Add(2 * time.Hour)
+	m.evaluate(ctx)
+	if len(posted) != 2 || len(m.tasks) != 0 {
+		t.Errorf("posted = %+v, tasks = %d", posted, len(m.tasks))
+	}
+}
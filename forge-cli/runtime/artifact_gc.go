@@ -0,0 +1,142 @@
+package runtime
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/initializ/forge/forge-core/retention"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/scheduler"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Artifact categories collected under forge.yaml `retention:`.
+const (
+	ArtifactSessions = "sessions"
+	ArtifactFiles    = "files"
+	ArtifactScratch  = "scratch"
+	ArtifactCaptures = "captures"
+)
+
+// SessionsDir resolves the file session store's directory:
+// memory.sessions_dir, or .forge/sessions under workDir.
+func SessionsDir(cfg types.MemoryConfig, workDir string) string {
+	if cfg.SessionsDir != "" {
+		return cfg.SessionsDir
+	}
+	return filepath.Join(workDir, ".forge", "sessions")
+}
+
+// ArtifactCategories resolves forge.yaml's retention settings into the
+// categories artifact GC collects, defaults applied.
+func ArtifactCategories(cfg *types.ForgeConfig, workDir string) []retention.Category {
+	rc := cfg.Retention
+	categories := []retention.Category{
+		{Name: ArtifactSessions, Dir: SessionsDir(cfg.Memory, workDir), Policy: retentionPolicy(rc.Sessions, types.DefaultSessionMaxAge, 0)},
+		{Name: ArtifactFiles, Dir: filepath.Join(workDir, ".forge", "files"), Policy: retentionPolicy(rc.Files, types.DefaultFilesMaxAge, types.DefaultFilesMaxBytes)},
+		{Name: ArtifactScratch, Dir: filepath.Join(workDir, ".forge", "scratch"), Policy: retentionPolicy(rc.Scratch, types.DefaultScratchMaxAge, types.DefaultScratchMaxBytes)},
+		{Name: ArtifactCaptures, Dir: CapturesDir(workDir), Policy: retentionPolicy(rc.Captures, types.DefaultCapturesMaxAge, types.DefaultCapturesMaxBytes)},
+	}
+	// Each tenant's files are collected on their own, so one tenant's
+	// output can't push another's out under max_bytes.
+	for _, t := range cfg.Tenants {
+		categories = append(categories, retention.Category{
+			Name:   ArtifactFiles,
+			Dir:    filepath.Join(TenantsDir(workDir), t.ID, "files"),
+			Policy: retentionPolicy(rc.Files, types.DefaultFilesMaxAge, types.DefaultFilesMaxBytes),
+		})
+	}
+	return categories
+}
+
+// TenantsDir is the parent of the per-tenant artifact directories under
+// workDir.
+func TenantsDir(workDir string) string {
+	return filepath.Join(workDir, ".forge", "tenants")
+}
+
+// retentionPolicy applies a category's defaults to p: zero takes the
+// default, negative means no limit.
+func retentionPolicy(p types.RetentionPolicy, maxAge time.Duration, maxBytes int64) retention.Policy {
+	if p.MaxAge == 0 {
+		p.MaxAge = maxAge
+	}
+	if p.MaxBytes == 0 {
+		p.MaxBytes = maxBytes
+	}
+	if p.MaxAge < 0 {
+		p.MaxAge = 0
+	}
+	if p.MaxBytes < 0 {
+		p.MaxBytes = 0
+	}
+	return retention.Policy{MaxAge: p.MaxAge, MaxBytes: p.MaxBytes}
+}
+
+// startArtifactGC collects the agent's artifacts once at startup and
+// then on retention.gc_schedule until ctx ends. Like memory GC it runs on every replica: each keeps its own
+// .forge directory.
+func (r *Runner) startArtifactGC(ctx context.Context, auditLogger *coreruntime.AuditLogger) {
+	if r.cfg.WorkDir == "" {
+		return
+	}
+	expr := r.cfg.Config.Retention.GCSchedule
+	if expr == "" {
+		expr = types.DefaultRetentionGCSchedule
+	}
+	sched, err := scheduler.Parse(expr)
+	if err != nil {
+		r.logger.Warn("invalid retention.gc_schedule, background artifact gc disabled", map[string]any{
+			"gc_schedule": expr, "error": err.Error(),
+		})
+		sched = nil
+	}
+	categories := ArtifactCategories(r.cfg.Config, r.cfg.WorkDir)
+
+	r.collectArtifacts(categories, auditLogger)
+	if sched == nil {
+		return
+	}
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(sched.Next(time.Now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			r.collectArtifacts(categories, auditLogger)
+		}
+	}()
+	r.logger.Info("artifact gc enabled", map[string]any{"gc_schedule": expr})
+}
+
+// collectArtifacts runs one GC pass and audits what it removed.
+func (r *Runner) collectArtifacts(categories []retention.Category, auditLogger *coreruntime.AuditLogger) {
+	res := retention.Collect(categories, time.Now(), false)
+	for _, c := range res.Categories {
+		for _, e := range c.Errors {
+			r.logger.Warn("artifact gc", map[string]any{"category": c.Category, "error": e})
+		}
+	}
+	n, bytes := res.Removed()
+	if n == 0 {
+		return
+	}
+	removed := map[string]int{}
+	for _, c := range res.Categories {
+		if len(c.Removed) > 0 {
+			removed[c.Category] = len(c.Removed)
+		}
+	}
+	r.logger.Info("artifact gc", map[string]any{"removed": removed, "removed_bytes": bytes})
+	auditLogger.Emit(coreruntime.AuditEvent{
+		Event: coreruntime.AuditArtifactGC,
+		Fields: map[string]any{
+			"removed":       removed,
+			"removed_bytes": bytes,
+		},
+	})
+}
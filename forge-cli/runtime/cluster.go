@@ -0,0 +1,189 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/cluster"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// EnvClusterLockURL overrides cluster.lock_url.
+const EnvClusterLockURL = "FORGE_CLUSTER_LOCK_URL"
+
+// errTaskElsewhere is returned when another replica holds a task's lock.
+var errTaskElsewhere = errors.New("task is running on another replica; retry when it completes")
+
+// clusterState is the runner's share of a multi-replica deploy: the
+// lock backend, the scheduler leader election, and the task locks this
+// replica holds. Nil when cluster.lock_url is unset.
+type clusterState struct {
+	locker  cluster.Locker
+	elector *cluster.Elector
+	prefix  string // "forge:<agent_id>:"
+	ttl     time.Duration
+	logger  coreruntime.Logger
+
+	mu    sync.Mutex
+	tasks map[string]*heldTask
+}
+
+// heldTask is a task lock this replica holds, shared by every request
+// on the task here so the last one to finish releases it.
+type heldTask struct {
+	refs int
+	stop context.CancelFunc
+}
+
+// startCluster opens the lock backend and starts campaigning for
+// scheduler leadership. The state is nil when no lock URL is configured.
+// The returned stop function steps down, releases the leader lock and
+// closes the backend.
+func (r *Runner) startCluster(ctx context.Context) (*clusterState, func(), error) {
+	lockURL := r.cfg.Config.Cluster.LockURL
+	if v := os.Getenv(EnvClusterLockURL); v != "" {
+		lockURL = v
+	}
+	if lockURL == "" {
+		return nil, func() {}, nil
+	}
+	ttl := cluster.DefaultTTL
+	if s := r.cfg.Config.Cluster.LockTTL; s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cluster.lock_ttl: %w", err)
+		}
+		ttl = d
+	}
+	owner := cluster.OwnerID()
+	locker, err := cluster.Open(lockURL, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger := r.logger
+	prefix := "forge:" + r.cfg.Config.AgentID + ":"
+	cs := &clusterState{
+		locker:  locker,
+		elector: cluster.NewElector(locker, prefix+"scheduler", ttl, logger),
+		prefix:  prefix,
+		ttl:     ttl,
+		logger:  logger,
+		tasks:   make(map[string]*heldTask),
+	}
+	electCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cs.elector.Run(electCtx)
+	}()
+	logger.Info("cluster coordination enabled", map[string]any{"owner": owner, "lock_ttl": ttl.String()})
+
+	return cs, func() {
+		cancel()
+		<-done
+		_ = locker.Close()
+	}, nil
+}
+
+// lockTask takes taskID's lock for the duration of one request, so a
+// task runs — and writes its session — on one replica at a time. It
+// returns errTaskElsewhere when another replica holds the lock. The
+// lock is renewed every ttl/3 until the returned release is called.
+// A nil clusterState (single replica) always succeeds.
+func (cs *clusterState) lockTask(ctx context.Context, taskID string) (func(), error) {
+	if cs == nil {
+		return func() {}, nil
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if h, ok := cs.tasks[taskID]; ok {
+		h.refs++
+		return func() { cs.releaseTask(taskID) }, nil
+	}
+
+	key := cs.prefix + "task:" + taskID
+	held, err := cs.locker.TryLock(ctx, key, cs.ttl)
+	if err != nil {
+		// Fail closed: running unlocked is what the lock backend exists
+		// to prevent.
+		return nil, fmt.Errorf("cluster lock backend unavailable: %w", err)
+	}
+	if !held {
+		return nil, errTaskElsewhere
+	}
+
+	renewCtx, stop := context.WithCancel(context.Background())
+	cs.tasks[taskID] = &heldTask{refs: 1, stop: stop}
+	go cs.renewTask(renewCtx, taskID, key)
+	return func() { cs.releaseTask(taskID) }, nil
+}
+
+// taskLockStatus is the HTTP status for a REST task request that
+// failed with err: 409 when another replica holds the task, 500
+// otherwise.
+func taskLockStatus(err error) int {
+	if errors.Is(err, errTaskElsewhere) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// taskLockError classifies a lockTask failure. A task held by another
+// replica is worth retrying once it completes.
+func taskLockError(err error) *a2a.TaskError {
+	te := coreruntime.ClassifyError(err)
+	if errors.Is(err, errTaskElsewhere) {
+		te.Retryable = true
+	}
+	return te
+}
+
+// releaseTask drops one reference and unlocks after the last. The
+// unlock runs under mu so a request arriving meanwhile cannot re-take
+// the lock only to have it deleted.
+func (cs *clusterState) releaseTask(taskID string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	h := cs.tasks[taskID]
+	if h.refs--; h.refs > 0 {
+		return
+	}
+	delete(cs.tasks, taskID)
+
+	h.stop()
+	ctx, cancel := context.WithTimeout(context.Background(), cs.ttl/3)
+	defer cancel()
+	if err := cs.locker.Unlock(ctx, cs.prefix+"task:"+taskID); err != nil {
+		cs.logger.Warn("task lock release failed; it expires on its own", map[string]any{"task_id": taskID, "error": err.Error()})
+	}
+}
+
+func (cs *clusterState) renewTask(ctx context.Context, taskID, key string) {
+	ticker := time.NewTicker(cs.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := cs.locker.TryLock(ctx, key, cs.ttl)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil || !held {
+				fields := map[string]any{"task_id": taskID}
+				if err != nil {
+					fields["error"] = err.Error()
+				}
+				cs.logger.Warn("task lock renewal failed; another replica may take the task over", fields)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/cluster"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func newTestClusterState(table *cluster.MemoryLocks, owner string) *clusterState {
+	return &clusterState{
+		locker: table.Locker(owner),
+		prefix: "forge:test-agent:",
+		ttl:    time.Minute,
+		logger: coreruntime.NewJSONLogger(io.Discard, false),
+		tasks:  make(map[string]*heldTask),
+	}
+}
+
+func TestClusterState_TaskRunsOnOneReplica(t *testing.T) {
+	ctx := context.Background()
+	table := cluster.NewMemoryLocks()
+	a, b := newTestClusterState(table, "a"), newTestClusterState(table, "b")
+
+	releaseA, err := a.lockTask(ctx, "t1")
+	if err != nil {
+		t.Fatalf("a.lockTask: %v", err)
+	}
+	if _, err := b.lockTask(ctx, "t1"); !errors.Is(err, errTaskElsewhere) {
+		t.Fatalf("b.lockTask while a holds t1 = %v, want errTaskElsewhere", err)
+	}
+
+	// A second request for t1 on replica a shares the lock; the lock is
+	// only released when both are done.
+	releaseA2, err := a.lockTask(ctx, "t1")
+	if err != nil {
+		t.Fatalf("second a.lockTask: %v", err)
+	}
+	releaseA()
+	if _, err := b.lockTask(ctx, "t1"); !errors.Is(err, errTaskElsewhere) {
+		t.Fatal("t1 released while a request on a still holds it")
+	}
+	releaseA2()
+
+	releaseB, err := b.lockTask(ctx, "t1")
+	if err != nil {
+		t.Fatalf("b.lockTask after a released t1: %v", err)
+	}
+	releaseB()
+}
+
+func TestClusterState_NilIsSingleReplica(t *testing.T) {
+	var cs *clusterState
+	release, err := cs.lockTask(context.Background(), "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/initializ/forge/forge-cli/server"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// errCompactUnsupported is returned by CompactSession when the agent
+// does not run the LLM loop (mock tools, a subprocess framework).
+var errCompactUnsupported = errors.New("this agent's executor keeps no conversation to compact")
+
+// CompactSession forces the Compactor to run on sessionID's conversation
+// and returns the summary. It is the runtime side of POST
+// /sessions/{id}/compact and the compact_now tool. A running task is
+// compacted in place; a finished one has its persisted session compacted
+// and saved. Each compaction emits a session_compacted audit event.
+func (r *Runner) CompactSession(ctx context.Context, sessionID, source string) (*coreruntime.CompactResult, error) {
+	r.reloadMu.RLock()
+	rt := r.reload
+	r.reloadMu.RUnlock()
+	if rt == nil {
+		return nil, ErrNotServing
+	}
+	if rt.executor == nil {
+		return nil, errCompactUnsupported
+	}
+
+	res, err := rt.executor.CompactSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{
+		"source":           source,
+		"compacted":        res.Compacted,
+		"removed_messages": res.Removed,
+		"chars_before":     res.CharsBefore,
+		"chars_after":      res.CharsAfter,
+	}
+	if source == "api" {
+		fields["actor"] = delegatedSubject(ctx)
+	}
+	rt.auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
+		Event:         coreruntime.AuditSessionCompacted,
+		CorrelationID: coreruntime.CorrelationIDFromContext(ctx),
+		TaskID:        sessionID,
+		Fields:        fields,
+	})
+	r.logger.Info("session compacted on demand", map[string]any{
+		"task_id": sessionID, "source": source, "removed_messages": res.Removed,
+	})
+	return res, nil
+}
+
+// registerCompactNowTool registers the compact_now admin tool when
+// forge.yaml lists it under tools.
+func (r *Runner) registerCompactNowTool(reg *tools.Registry) {
+	if !slices.ContainsFunc(r.cfg.Config.Tools, func(t types.ToolRef) bool { return t.Name == "compact_now" }) {
+		return
+	}
+	if err := reg.Register(builtins.NewCompactNowTool(r)); err != nil {
+		r.logger.Warn("failed to register compact_now tool", map[string]any{"error": err.Error()})
+	}
+}
+
+// registerCompactEndpoint wires POST /sessions/{id}/compact. It sits
+// behind the server's auth middleware like every other non-public route.
+func (r *Runner) registerCompactEndpoint(srv *server.Server) {
+	srv.RegisterHTTPHandler("POST /sessions/{id}/compact", makeCompactHandler(r, r.cluster))
+}
+
+// makeCompactHandler is extracted so tests can exercise the handler
+// without a full server. 404 when the task has no session, 409 when
+// there is nothing to compact with (persistence off, no LLM loop) or
+// another replica holds the task.
+func makeCompactHandler(c builtins.SessionCompactor, cluster *clusterState) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := req.PathValue("id")
+		unlock, err := cluster.lockTask(req.Context(), id)
+		if err != nil {
+			writeJSON(w, taskLockStatus(err), map[string]string{"error": err.Error()})
+			return
+		}
+		defer unlock()
+
+		res, err := c.CompactSession(req.Context(), id, "api")
+		switch {
+		case err == nil:
+			writeJSON(w, http.StatusOK, res)
+		case errors.Is(err, coreruntime.ErrNoSession):
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no session for task " + id})
+		case errors.Is(err, coreruntime.ErrNoCompactor), errors.Is(err, errCompactUnsupported), errors.Is(err, ErrNotServing):
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		}
+	}
+}
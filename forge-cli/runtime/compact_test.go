@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+type fakeSessionCompactor struct {
+	err    error
+	called string
+}
+
+func (f *fakeSessionCompactor) CompactSession(_ context.Context, sessionID, source string) (*coreruntime.CompactResult, error) {
+	f.called = sessionID + "/" + source
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &coreruntime.CompactResult{SessionID: sessionID, Compacted: true, Summary: "## State\nhalfway", Removed: 12}, nil
+}
+
+func TestCompactHandler(t *testing.T) {
+	post := func(c *fakeSessionCompactor) *httptest.ResponseRecorder {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /sessions/{id}/compact", makeCompactHandler(c, nil))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions/task-7/compact", nil))
+		return w
+	}
+
+	ok := &fakeSessionCompactor{}
+	if w := post(ok); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"summary":"## State\nhalfway"`) || ok.called != "task-7/api" {
+		t.Errorf("compact: %d %s (called %q)", w.Code, w.Body.String(), ok.called)
+	}
+	for err, want := range map[error]int{
+		coreruntime.ErrNoSession:   http.StatusNotFound,
+		coreruntime.ErrNoCompactor: http.StatusConflict,
+		errCompactUnsupported:      http.StatusConflict,
+	} {
+		if w := post(&fakeSessionCompactor{err: err}); w.Code != want {
+			t.Errorf("%v: status %d, want %d", err, w.Code, want)
+		}
+	}
+}
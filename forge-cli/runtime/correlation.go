@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/auth"
+	"github.com/initializ/forge/forge-core/observability"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// restRequestContext is the REST twin of the JSON-RPC dispatcher's
+// request setup (server.handleJSONRPC): it extracts the inbound W3C
+// traceparent + baggage so the invocation nests under the caller's
+// trace, installs the workflow, tenancy and channel contexts and the
+// task origin from the request headers, and opens the a2a.<method>
+// server span. Callers end the span when the handler returns.
+func restRequestContext(req *http.Request, method string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	ctx = coreruntime.WithWorkflowContext(ctx, coreruntime.WorkflowContextFromHTTPHeaders(req.Header))
+	ctx = coreruntime.WithTenancyContext(ctx, coreruntime.TenancyContextFromHTTPHeaders(req.Header))
+	ctx = coreruntime.WithChannelContext(ctx, coreruntime.ChannelContextFromHTTPHeaders(req.Header))
+	ctx = tools.WithOrigin(ctx, coreruntime.RequestOrigin(req.Header, tools.OriginREST, auth.FromRuntime(ctx)))
+	return coreruntime.Tracer().Start(ctx, "a2a."+method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String(observability.AttrForgeA2AMethod, method)),
+	)
+}
+
+// stampTaskCorrelation records the invocation's correlation ID — and
+// trace ID, when tracing is on — in the task metadata, so whoever
+// holds the task can find the audit events and spans of the run that
+// produced it, along with the task origin and experiment variant. The
+// error a previous turn recorded is cleared.
+func stampTaskCorrelation(ctx context.Context, task *a2a.Task) {
+	if task.Metadata == nil {
+		task.Metadata = map[string]any{}
+	}
+	delete(task.Metadata, a2a.MetadataKeyError)
+	if id := coreruntime.CorrelationIDFromContext(ctx); id != "" {
+		task.Metadata["correlation_id"] = id
+	}
+	if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		task.Metadata["trace_id"] = sc.TraceID().String()
+	} else {
+		delete(task.Metadata, "trace_id")
+	}
+	if origin := tools.OriginFromContext(ctx); origin != "" {
+		task.Metadata["origin"] = origin
+	}
+	if a := coreruntime.ExperimentFromContext(ctx); a != nil {
+		task.Metadata[a2a.MetadataKeyExperiment] = *a
+	} else {
+		delete(task.Metadata, a2a.MetadataKeyExperiment)
+	}
+}
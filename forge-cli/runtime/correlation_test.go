@@ -0,0 +1,93 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/auth"
+	"github.com/initializ/forge/forge-core/auth/providers/statictoken"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// originRequest sends req through the auth middleware, with the
+// runtime's loopback token "internal" and an operator token "user",
+// and returns the origin restRequestContext tags the task with.
+func originRequest(t *testing.T, req *http.Request) (string, coreruntime.ChannelContext) {
+	t.Helper()
+	loopback, err := statictoken.New(statictoken.Config{
+		Token:    "internal",
+		Identity: auth.MarkRuntimeInternal(auth.Identity{UserID: "forge-internal", Source: "internal"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := statictoken.New(statictoken.Config{Token: "user", Identity: auth.Identity{UserID: "alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var origin string
+	var channel coreruntime.ChannelContext
+	h := auth.Middleware(auth.MiddlewareOptions{Chain: auth.NewChainProvider(loopback, user)})(
+		http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			ctx, span := restRequestContext(req, "tasks/send")
+			defer span.End()
+			origin = tools.OriginFromContext(ctx)
+			channel = coreruntime.ChannelContextFromContext(ctx)
+		}))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return origin, channel
+}
+
+func TestRestRequestContext_ChannelHeaders(t *testing.T) {
+	req := httptest.NewRequest("POST", "/tasks/send", nil)
+	req.Header.Set("Authorization", "Bearer internal")
+	req.Header.Set(coreruntime.HeaderForgeChannel, "slack")
+	req.Header.Set(coreruntime.HeaderForgeChannelEventID, "Ev1")
+	origin, channel := originRequest(t, req)
+
+	if channel.Channel != "slack" || channel.EventID != "Ev1" {
+		t.Errorf("channel context = %+v", channel)
+	}
+	if origin != tools.OriginChannel {
+		t.Errorf("origin = %q, want channel", origin)
+	}
+}
+
+func TestRestRequestContext_IgnoresSpoofedOrigin(t *testing.T) {
+	for _, hdr := range []string{coreruntime.HeaderForgeOrigin, coreruntime.HeaderForgeChannel} {
+		req := httptest.NewRequest("POST", "/tasks/send", nil)
+		req.Header.Set("Authorization", "Bearer user")
+		req.Header.Set(hdr, tools.OriginUI)
+		if origin, _ := originRequest(t, req); origin != tools.OriginREST {
+			t.Errorf("%s from an operator token: origin = %q, want rest", hdr, origin)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/tasks/send", nil)
+	req.Header.Set("Authorization", "Bearer internal")
+	req.Header.Set(coreruntime.HeaderForgeOrigin, tools.OriginUI)
+	if origin, _ := originRequest(t, req); origin != tools.OriginUI {
+		t.Errorf("X-Forge-Origin from the runtime token: origin = %q, want ui", origin)
+	}
+}
+
+func TestStampTaskCorrelation(t *testing.T) {
+	ctx := coreruntime.WithCorrelationID(context.Background(), "corr-1")
+	ctx = tools.WithOrigin(ctx, tools.OriginChannel)
+	task := &a2a.Task{ID: "t1", Metadata: map[string]any{"trace_id": "stale"}}
+	stampTaskCorrelation(ctx, task)
+
+	if task.Metadata["correlation_id"] != "corr-1" {
+		t.Errorf("correlation_id = %v", task.Metadata["correlation_id"])
+	}
+	if _, ok := task.Metadata["trace_id"]; ok {
+		t.Error("trace_id should be dropped when the context carries no span")
+	}
+	if task.Metadata["origin"] != "channel" {
+		t.Errorf("origin = %v", task.Metadata["origin"])
+	}
+}
@@ -163,7 +163,7 @@ func (r *Runner) registerDeferHook(hooks *coreruntime.HookRegistry, store TaskSt
 					"wait_ms":        waitMs,
 				},
 			})
-			return fmt.Errorf("defer: rejected by %s: %s", res.Approver, res.Note)
+			return coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("defer: rejected by %s: %s", res.Approver, res.Note))
 		case deferengine.DecisionTimeout:
 			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 				Event:         coreruntime.AuditTaskDeferredTimeout,
@@ -175,7 +175,7 @@ func (r *Runner) registerDeferHook(hooks *coreruntime.HookRegistry, store TaskSt
 					"wait_ms":    waitMs,
 				},
 			})
-			return fmt.Errorf("defer: no decision within %s (auto-deny)", spec.Timeout)
+			return coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("defer: no decision within %s (auto-deny)", spec.Timeout))
 		default:
 			return fmt.Errorf("defer: unknown decision %q", res.Decision)
 		}
@@ -290,6 +290,9 @@ func makeDecisionsHandler(engine *deferengine.Engine, auditLogger *coreruntime.A
 			Note          string `json:"note"`
 		}
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
 			return
 		}
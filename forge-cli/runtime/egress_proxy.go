@@ -0,0 +1,36 @@
+package runtime
+
+import (
+	"net/http"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// applyEgressProxyConfig applies forge.yaml egress.proxy — the SNI
+// policy, per-task quotas and sidecar socket — to the subprocess egress
+// proxy.
+func applyEgressProxyConfig(p *security.EgressProxy, cfg *types.EgressProxyRef) {
+	if cfg == nil {
+		return
+	}
+	p.SetSNIPolicy(security.SNIPolicy(cfg.SNI))
+	p.SetTaskQuota(security.EgressTaskQuota{MaxBytes: cfg.TaskMaxBytes, MaxRequests: cfg.TaskMaxRequests})
+	if cfg.Socket != "" {
+		p.SetUnixSocket(cfg.Socket)
+	}
+}
+
+// registerEgressProxyEndpoint wires GET /admin/egress-proxy, which
+// reports the subprocess proxy's per-domain request, block and byte
+// counters and the quota usage of recent tasks. No-op wire when the
+// proxy is not running.
+func (r *Runner) registerEgressProxyEndpoint(srv *server.Server) {
+	if r.egressProxy == nil {
+		return
+	}
+	srv.RegisterHTTPHandler("GET /admin/egress-proxy", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, r.egressProxy.Stats())
+	})
+}
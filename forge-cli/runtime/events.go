@@ -0,0 +1,114 @@
+package runtime
+
+import (
+	"context"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// subscribeEvents attaches the runner's own consumers to the event bus:
+// the ops log of tool and LLM activity, the usage totals behind /info,
+// and the schedule audit events. Channel adapters subscribe per task
+// through SubscribeProgress.
+func (r *Runner) subscribeEvents(auditLogger *coreruntime.AuditLogger) {
+	r.subscribeEventLog()
+	r.events.Subscribe(func(ctx context.Context, e coreruntime.Event) {
+		h := e.Hook
+		if h == nil || h.Response == nil {
+			return
+		}
+		// A model.routes rule answered: attribute the tokens to the
+		// routed model, not the configured primary.
+		model, provider := h.Model, h.Provider
+		if h.Response.Route != nil {
+			model, provider = h.Response.Route.Model, h.Response.Route.Provider
+		}
+		r.recordUsage(ctx, provider, model, h.Response.Usage)
+	}, coreruntime.TopicLLMCall)
+
+	// In line: audit events must not be dropped.
+	r.events.SubscribeSync(func(_ context.Context, e coreruntime.Event) {
+		event := coreruntime.AuditScheduleFire
+		if e.Topic == coreruntime.TopicScheduleCompleted {
+			event = coreruntime.AuditScheduleComplete
+		}
+		auditLogger.Emit(coreruntime.AuditEvent{
+			Event:         event,
+			CorrelationID: e.CorrelationID,
+			TaskID:        e.TaskID,
+			Fields:        e.Fields,
+		})
+	}, coreruntime.TopicScheduleFired, coreruntime.TopicScheduleCompleted)
+}
+
+// subscribeEventLog writes the agent loop's LLM responses, tool calls
+// and errors to the ops log.
+func (r *Runner) subscribeEventLog() {
+	execLog := coreruntime.SubsystemLogger(r.logger, coreruntime.LogSubsystemExecutor)
+	toolLog := coreruntime.SubsystemLogger(r.logger, coreruntime.LogSubsystemTools)
+
+	r.events.Subscribe(func(_ context.Context, e coreruntime.Event) {
+		h := e.Hook
+		if h == nil {
+			return
+		}
+		switch e.Topic {
+		case coreruntime.TopicLLMCall:
+			if h.Response == nil {
+				return
+			}
+			fields := map[string]any{
+				"finish_reason": h.Response.FinishReason,
+			}
+			if h.Response.Usage.TotalTokens > 0 {
+				fields["tokens"] = h.Response.Usage.TotalTokens
+			}
+			if len(h.Response.Message.ToolCalls) > 0 {
+				names := make([]string, len(h.Response.Message.ToolCalls))
+				for i, tc := range h.Response.Message.ToolCalls {
+					names[i] = tc.Function.Name
+				}
+				fields["tool_calls"] = names
+			}
+			if h.Response.Message.Content != "" {
+				content := h.Response.Message.Content
+				if len(content) > 200 {
+					content = content[:200] + "..."
+				}
+				fields["response"] = content
+			}
+			execLog.Info("llm response", fields)
+
+		case coreruntime.TopicToolStart:
+			fields := map[string]any{"tool": h.ToolName}
+			if h.ToolInput != "" {
+				input := h.ToolInput
+				if len(input) > 300 {
+					input = input[:300] + "..."
+				}
+				fields["input"] = input
+			}
+			toolLog.Info("tool call", fields)
+
+		case coreruntime.TopicToolEnd:
+			fields := map[string]any{"tool": h.ToolName}
+			if h.Error != nil {
+				fields["error"] = h.Error.Error()
+				toolLog.Error("tool error", fields)
+				return
+			}
+			output := h.ToolOutput
+			if len(output) > 500 {
+				output = output[:500] + "..."
+			}
+			fields["output_length"] = len(h.ToolOutput)
+			fields["output"] = output
+			toolLog.Info("tool result", fields)
+
+		case coreruntime.TopicLoopError:
+			if h.Error != nil {
+				execLog.Error("agent loop error", map[string]any{"error": h.Error.Error()})
+			}
+		}
+	}, coreruntime.TopicLLMCall, coreruntime.TopicToolStart, coreruntime.TopicToolEnd, coreruntime.TopicLoopError)
+}
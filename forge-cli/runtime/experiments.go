@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// enrollExperiment puts task taskID in its forge.yaml experiments
+// variant: the one an earlier turn recorded in prev's metadata while
+// the config still has it, else the one AssignExperiment picks for the
+// request metadata md. The returned context carries the assignment
+// (audit events, usage, the task's metadata), pins the variant's model
+// and overrides the system prompt with the variant's.
+func (r *Runner) enrollExperiment(ctx context.Context, taskID string, prev *a2a.Task, md map[string]any) context.Context {
+	if r.cfg.Config == nil || len(r.cfg.Config.Experiments) == 0 {
+		return ctx
+	}
+	exps := r.cfg.Config.Experiments
+	var exp *types.ExperimentConfig
+	var v *types.ExperimentVariant
+	if a, ok := taskExperiment(prev); ok {
+		exp, v = coreruntime.FindExperimentVariant(exps, a)
+	}
+	if v == nil {
+		exp, v = coreruntime.AssignExperiment(exps, taskID, md)
+	}
+	if v == nil {
+		return ctx
+	}
+	ctx = coreruntime.WithExperiment(ctx, &coreruntime.ExperimentAssignment{Experiment: exp.ID, Variant: v.ID})
+	if v.Model != nil {
+		ctx = llm.WithRoute(ctx, coreruntime.ExperimentRouteID(exp.ID, v.ID))
+	}
+	if v.Prompt != "" {
+		prompt, err := r.variantSystemPrompt(v.Prompt)
+		if err != nil {
+			// loadPrompts checked the template; keep the task running
+			// on the default prompt rather than fail it.
+			r.logger.Warn("experiment prompt not rendered", map[string]any{
+				"experiment": exp.ID, "variant": v.ID, "error": err.Error(),
+			})
+			return ctx
+		}
+		ctx = coreruntime.WithSystemPrompt(ctx, prompt)
+	}
+	return ctx
+}
+
+// variantSystemPrompt renders an experiment variant's system prompt
+// from template name, with the directives executorSystemPrompt adds.
+func (r *Runner) variantSystemPrompt(name string) (string, error) {
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	prompt, err := r.renderPrompt(r.prompts, name)
+	if err != nil {
+		return "", err
+	}
+	return r.withPromptDirectives(prompt), nil
+}
+
+// taskExperiment reads the assignment recorded in a task's metadata: the
+// value stampTaskCorrelation set, or its JSON form once the task went
+// through a store.
+func taskExperiment(task *a2a.Task) (coreruntime.ExperimentAssignment, bool) {
+	if task == nil {
+		return coreruntime.ExperimentAssignment{}, false
+	}
+	switch v := task.Metadata[a2a.MetadataKeyExperiment].(type) {
+	case coreruntime.ExperimentAssignment:
+		return v, true
+	case map[string]any:
+		id, _ := v["id"].(string)
+		variant, _ := v["variant"].(string)
+		return coreruntime.ExperimentAssignment{Experiment: id, Variant: variant}, id != "" && variant != ""
+	}
+	return coreruntime.ExperimentAssignment{}, false
+}
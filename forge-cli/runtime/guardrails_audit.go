@@ -131,7 +131,25 @@ func (e *LibraryGuardrailEngine) emitGuardrailEvent(
 	decision string,
 	res *guardrails.Result,
 ) {
-	if e.auditLogger == nil || res == nil {
+	if res == nil {
+		return
+	}
+	if e.events != nil {
+		hit := map[string]any{"gate": string(res.Gate), "decision": decision}
+		if len(res.Violations) > 0 {
+			hit["guardrail"] = res.Violations[0].Type
+		}
+		if tool != "" {
+			hit["tool"] = tool
+		}
+		e.events.Publish(ctx, coreruntime.Event{
+			Topic:         coreruntime.TopicGuardrailHit,
+			TaskID:        coreruntime.TaskIDFromContext(ctx),
+			CorrelationID: coreruntime.CorrelationIDFromContext(ctx),
+			Fields:        hit,
+		})
+	}
+	if e.auditLogger == nil {
 		return
 	}
 	fields := map[string]any{
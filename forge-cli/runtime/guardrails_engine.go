@@ -47,6 +47,7 @@ type LibraryGuardrailEngine struct {
 	logger        coreruntime.Logger
 	auditLogger   *coreruntime.AuditLogger
 	auditCfg      GuardrailAuditConfig
+	events        *coreruntime.EventBus // guardrail.hit subscribers; nil when none are wired
 	// tracingCfg controls the OTel guardrail.<gate> span instrumentation
 	// added in #161. CaptureContent + Redact + MaxBytes follow the
 	// same posture as the #130 LLM-call content capture. Default zero
@@ -163,7 +164,7 @@ func (e *LibraryGuardrailEngine) CheckInbound(ctx context.Context, msg *a2a.Mess
 			e.emitGuardrailEvent(ctx, "", text, guardrailResultBlocked, result)
 			evidenceContent = text
 			decisionString = guardrailResultBlocked
-			return coreruntime.Deny(desc), fmt.Errorf("input blocked: %s", desc)
+			return coreruntime.Deny(desc), coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("input blocked: %s", desc))
 		}
 		e.logger.Warn("guardrail input violation (warn mode)", map[string]any{"detail": desc})
 		e.emitGuardrailEvent(ctx, "", text, guardrailResultWarned, result)
@@ -254,7 +255,7 @@ func (e *LibraryGuardrailEngine) checkOneOutboundPart(ctx context.Context, msg *
 			e.emitGuardrailEvent(ctx, "", original, guardrailResultBlocked, result)
 			evidenceContent = original
 			decisionString = guardrailResultBlocked
-			return coreruntime.Deny(desc), fmt.Errorf("output blocked: %s", desc)
+			return coreruntime.Deny(desc), coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("output blocked: %s", desc))
 		}
 		e.logger.Warn("guardrail output violation (warn mode)", map[string]any{"detail": desc})
 		e.emitGuardrailEvent(ctx, "", original, guardrailResultWarned, result)
@@ -314,7 +315,7 @@ func (e *LibraryGuardrailEngine) CheckToolCall(ctx context.Context, toolName, ar
 			e.emitGuardrailEvent(ctx, toolName, args, guardrailResultBlocked, result)
 			evidenceContent = args
 			decisionString = guardrailResultBlocked
-			return "", fmt.Errorf("tool_call blocked: %s", desc)
+			return "", coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("tool_call blocked: %s", desc))
 		}
 		e.logger.Warn("guardrail tool_call violation (warn mode)", map[string]any{
 			"tool":   toolName,
@@ -380,7 +381,7 @@ func (e *LibraryGuardrailEngine) CheckToolOutput(ctx context.Context, toolName,
 			e.emitGuardrailEvent(ctx, toolName, text, guardrailResultBlocked, result)
 			evidenceContent = text
 			decisionString = guardrailResultBlocked
-			return "", fmt.Errorf("tool output blocked: %s", desc)
+			return "", coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("tool output blocked: %s", desc))
 		}
 		e.logger.Warn("guardrail tool output violation (warn mode)", map[string]any{
 			"tool":   toolName,
@@ -441,7 +442,7 @@ func (e *LibraryGuardrailEngine) CheckContext(ctx context.Context, content strin
 			e.emitGuardrailEvent(ctx, "", content, guardrailResultBlocked, result)
 			evidenceContent = content
 			decisionString = guardrailResultBlocked
-			return "", fmt.Errorf("context blocked: %s", desc)
+			return "", coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("context blocked: %s", desc))
 		}
 		e.logger.Warn("guardrail context violation (warn mode)", map[string]any{"detail": desc})
 		e.emitGuardrailEvent(ctx, "", content, guardrailResultWarned, result)
@@ -453,11 +454,10 @@ func (e *LibraryGuardrailEngine) CheckContext(ctx context.Context, content strin
 }
 
 // CheckStream validates a single chunk from a streaming LLM call via
-// StreamGate. Returns the (possibly masked) chunk. Not auto-wired
-// because Forge's current Execute loop does not call provider streaming
-// (ExecuteStream buffers a single non-streaming response). Exposed for
-// callers that consume llm.Client.ChatStream directly and for future
-// loop work that adds a real per-chunk seam.
+// StreamGate. Returns the (possibly masked) chunk. Called once per
+// buffered window by coreruntime.StreamGuard, which callers consuming
+// llm.Client.ChatStream wrap their stream in (GuardStream); the
+// Execute loop itself does not stream yet.
 func (e *LibraryGuardrailEngine) CheckStream(ctx context.Context, chunk string) (string, error) {
 	if chunk == "" {
 		return chunk, nil
@@ -501,7 +501,7 @@ func (e *LibraryGuardrailEngine) CheckStream(ctx context.Context, chunk string)
 			e.emitGuardrailEvent(ctx, "", chunk, guardrailResultBlocked, result)
 			evidenceContent = chunk
 			decisionString = guardrailResultBlocked
-			return "", fmt.Errorf("stream blocked: %s", desc)
+			return "", coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("stream blocked: %s", desc))
 		}
 		e.logger.Warn("guardrail stream violation (warn mode)", map[string]any{"detail": desc})
 		e.emitGuardrailEvent(ctx, "", chunk, guardrailResultWarned, result)
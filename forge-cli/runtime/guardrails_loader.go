@@ -51,7 +51,8 @@ func DefaultPolicyScaffold() *agentspec.PolicyScaffold {
 // CaptureContent is on, evidence is stamped on the span via the same
 // redact-then-truncate pipeline the LLM-call content capture uses.
 // When auditLogger is nil the engine is silent on the audit pipeline
-// (used by tests).
+// (used by tests). events, when non-nil, receives a guardrail.hit event
+// for each of those decisions.
 //
 // A file-engine construction error logs and returns a
 // NoopGuardrailChecker — rare, and the recovery path is well-understood.
@@ -62,12 +63,14 @@ func BuildGuardrailChecker(
 	logger coreruntime.Logger,
 	auditLogger *coreruntime.AuditLogger,
 	auditCfg GuardrailAuditConfig,
+	events *coreruntime.EventBus,
 	tracingCfg observability.TracingConfig,
 ) (coreruntime.GuardrailChecker, error) {
 	attach := func(e *LibraryGuardrailEngine) coreruntime.GuardrailChecker {
 		if auditLogger != nil {
 			e.WithAuditLogger(auditLogger, auditCfg)
 		}
+		e.events = events
 		e.WithTracing(tracingCfg)
 		return e
 	}
@@ -89,6 +92,28 @@ func BuildGuardrailChecker(
 		return nil, fmt.Errorf("platform guardrails overlay: %w", err)
 	}
 
+	// The organization base config (forge.yaml extends) may carry a
+	// guardrails block too; it tightens the same never-loosen way.
+	if cfg != nil && len(cfg.BaseGuardrails) > 0 {
+		overlay, err := overlayFromRawYAML(cfg.BaseGuardrails)
+		if err != nil {
+			return nil, fmt.Errorf("base config %s guardrails: %w", cfg.Extends, err)
+		}
+		var tightenings []GuardrailTightening
+		sg, tightenings = MergeGuardrails(sg, overlay)
+		if len(tightenings) > 0 {
+			sortTightenings(tightenings)
+			changes := make([]string, 0, len(tightenings))
+			for _, t := range tightenings {
+				changes = append(changes, t.Field+": "+t.Change)
+			}
+			logger.Info("guardrails: base config tightened agent guardrails", map[string]any{
+				"extends": cfg.Extends,
+				"changes": changes,
+			})
+		}
+	}
+
 	engine, err := NewFileGuardrailEngine(sg, enforce, logger)
 	if err != nil {
 		logger.Warn("failed to create file guardrail engine, using noop", map[string]any{
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/initializ/forge/forge-core/observability"
+	"github.com/initializ/forge/forge-core/types"
 )
 
 // captureLogger records everything emitted through the ops logger so tests
@@ -53,7 +54,7 @@ guardrails:
 
 	logger := &captureLogger{}
 	checker, err := BuildGuardrailChecker(nil, dir, false, logger, nil,
-		GuardrailAuditConfig{}, observability.TracingConfig{})
+		GuardrailAuditConfig{}, nil, observability.TracingConfig{})
 	if err != nil {
 		t.Fatalf("overlay build errored: %v", err)
 	}
@@ -65,6 +66,37 @@ guardrails:
 	}
 }
 
+// TestBuildGuardrailChecker_AppliesBaseConfigGuardrails confirms the
+// guardrails block inherited from a forge.yaml extends base config
+// tightens the agent guardrails like the platform overlay does.
+func TestBuildGuardrailChecker_AppliesBaseConfigGuardrails(t *testing.T) {
+	dir := isolateLayers(t)
+	if err := os.WriteFile(filepath.Join(dir, "guardrails.json"), []byte(`{
+		"gateConfig": {"outputGate": false}
+	}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &types.ForgeConfig{
+		Extends: "org-base.yaml",
+		BaseGuardrails: map[string]any{
+			"gateConfig": map[string]any{"outputGate": true},
+		},
+	}
+
+	logger := &captureLogger{}
+	checker, err := BuildGuardrailChecker(cfg, dir, false, logger, nil,
+		GuardrailAuditConfig{}, nil, observability.TracingConfig{})
+	if err != nil {
+		t.Fatalf("base config build errored: %v", err)
+	}
+	if checker == nil {
+		t.Fatal("expected a non-nil checker")
+	}
+	if !anyContains(logger.infos, "base config tightened") {
+		t.Errorf("expected a tightening log line; got infos=%v", logger.infos)
+	}
+}
+
 // TestBuildGuardrailChecker_MalformedOverlay_FailsClosed pins finding #1
 // from the #285 review: a typo'd `guardrails:` block (rejected by strict
 // decode) must ABORT startup, not silently drop the operator's mandate.
@@ -82,7 +114,7 @@ guardrails:
 
 	logger := &captureLogger{}
 	checker, err := BuildGuardrailChecker(nil, dir, false, logger, nil,
-		GuardrailAuditConfig{}, observability.TracingConfig{})
+		GuardrailAuditConfig{}, nil, observability.TracingConfig{})
 	if err == nil {
 		t.Fatalf("expected a startup error on malformed overlay; got checker=%v", checker)
 	}
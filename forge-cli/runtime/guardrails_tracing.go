@@ -28,7 +28,7 @@ import (
 //   guardrail.context      (ContextGate, CheckContext)
 //   guardrail.tool_call    (ToolCallGate, CheckToolCall)
 //   guardrail.output       (OutputGate, CheckOutbound + CheckToolOutput)
-//   guardrail.stream       (StreamGate, CheckStream — not auto-wired)
+//   guardrail.stream       (StreamGate, CheckStream — once per StreamGuard window)
 //
 // The span parent is whatever's active when the engine method is
 // called (the A2A handler span for CheckInbound, agent.execute for
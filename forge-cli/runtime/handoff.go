@@ -0,0 +1,325 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Handoff: the handoff_to_human tool hands a conversation to the
+// operator channel in forge.yaml's handoff block. Until someone sends
+// /resume-bot, the conversation's messages are forwarded to the
+// operators instead of reaching the LLM, and the agent stays silent.
+
+// resumeBotCommand is the chat command that hands a conversation back
+// to the agent.
+const resumeBotCommand = "/resume-bot"
+
+// resumeBotReply answers /resume-bot.
+const resumeBotReply = "You're talking to the agent again."
+
+// handoffUndeliveredReply answers a message that could not be passed
+// to the operators.
+const handoffUndeliveredReply = "I couldn't pass your message to an operator. Please try again in a moment."
+
+var errNotHandedOff = errors.New("conversation is not with an operator")
+
+// handoffState is one conversation held by the operators.
+type handoffState struct {
+	Since  time.Time `json:"since"`
+	Reason string    `json:"reason"`
+}
+
+// handoffs implements builtins.HandoffController for the runner. The
+// set of held conversations is persisted so a restart does not hand
+// them back to the agent.
+type handoffs struct {
+	runner *Runner
+	cfg    types.HandoffConfig
+	audit  *coreruntime.AuditLogger
+	path   string // "" keeps the set in memory only
+
+	mu     sync.Mutex
+	active map[string]*handoffState
+}
+
+// newHandoffs loads the held conversations stored at path.
+func newHandoffs(r *Runner, cfg types.HandoffConfig, audit *coreruntime.AuditLogger, path string) (*handoffs, error) {
+	h := &handoffs{runner: r, cfg: cfg, audit: audit, path: path, active: map[string]*handoffState{}}
+	if path == "" {
+		return h, nil
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return h, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading handoffs: %w", err)
+	}
+	if err := json.Unmarshal(data, &h.active); err != nil {
+		return nil, fmt.Errorf("parsing handoffs %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// HandOff holds the conversation of the task in ctx for the operators,
+// once they have been told about it.
+func (h *handoffs) HandOff(ctx context.Context, req builtins.HandoffRequest) error {
+	taskID := coreruntime.TaskIDFromContext(ctx)
+	if taskID == "" {
+		return fmt.Errorf("handoff_to_human needs a conversation to hand off")
+	}
+	if h.isActive(taskID) {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "🙋 **Handoff requested** for conversation `%s`\nReason: %s", taskID, req.Reason)
+	if req.Summary != "" {
+		fmt.Fprintf(&b, "\nSummary: %s", req.Summary)
+	}
+	fmt.Fprintf(&b, "\nThe agent has stopped answering and the user's messages will appear here. "+
+		"Send `%s` in the conversation, or POST /handoffs/%s/resume, to hand it back.", resumeBotCommand, taskID)
+	if err := h.send(ctx, b.String()); err != nil {
+		h.emit(ctx, taskID, "start", "tool", map[string]any{"reason": req.Reason, "error": err.Error()})
+		return fmt.Errorf("reaching the operators: %w", err)
+	}
+
+	h.mu.Lock()
+	h.active[taskID] = &handoffState{Since: time.Now().UTC(), Reason: req.Reason}
+	h.saveLocked()
+	h.mu.Unlock()
+	h.emit(ctx, taskID, "start", "tool", map[string]any{"reason": req.Reason})
+	return nil
+}
+
+func (h *handoffs) isActive(taskID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.active[taskID]
+	return ok
+}
+
+// forward passes a user message of a held conversation to the
+// operators.
+func (h *handoffs) forward(ctx context.Context, taskID, text string) error {
+	from := ""
+	if subject := delegatedSubject(ctx); subject != "" {
+		from = " from " + subject
+	}
+	err := h.send(ctx, fmt.Sprintf("💬 `%s`%s:\n%s", taskID, from, text))
+	fields := map[string]any{}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	h.emit(ctx, taskID, "forward", "chat", fields)
+	return err
+}
+
+// resume hands taskID back to the agent and tells the operators.
+func (h *handoffs) resume(ctx context.Context, taskID, source string) error {
+	h.mu.Lock()
+	_, ok := h.active[taskID]
+	delete(h.active, taskID)
+	if ok {
+		h.saveLocked()
+	}
+	h.mu.Unlock()
+	if !ok {
+		return errNotHandedOff
+	}
+	fields := map[string]any{}
+	if source == "api" {
+		fields["actor"] = delegatedSubject(ctx)
+	}
+	h.emit(ctx, taskID, "resume", source, fields)
+	// Best effort: the conversation is back either way.
+	_ = h.send(ctx, fmt.Sprintf("✅ Conversation `%s` is back with the agent.", taskID))
+	return nil
+}
+
+// handoffInfo is one held conversation, as listed by GET /handoffs.
+type handoffInfo struct {
+	TaskID string    `json:"task_id"`
+	Since  time.Time `json:"since"`
+	Reason string    `json:"reason"`
+}
+
+func (h *handoffs) list() []handoffInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]handoffInfo, 0, len(h.active))
+	for id, s := range h.active {
+		out = append(out, handoffInfo{TaskID: id, Since: s.Since, Reason: s.Reason})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Since.Before(out[j].Since) })
+	return out
+}
+
+// send delivers text to the operator channel.
+func (h *handoffs) send(ctx context.Context, text string) error {
+	send := h.runner.notifySender
+	if send == nil {
+		return ErrNotifyUnavailable
+	}
+	return send(ctx, h.cfg.Channel, h.cfg.Target, &a2a.Message{
+		Role:  a2a.MessageRoleAgent,
+		Parts: []a2a.Part{a2a.NewTextPart(text)},
+	})
+}
+
+// saveLocked writes the held set atomically. A failed write only costs
+// the handoff across a restart, so it is logged rather than returned.
+func (h *handoffs) saveLocked() {
+	if h.path == "" {
+		return
+	}
+	err := func() error {
+		data, err := json.Marshal(h.active)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+			return err
+		}
+		tmp := h.path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o600); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, h.path); err != nil {
+			os.Remove(tmp) //nolint:errcheck
+			return err
+		}
+		return nil
+	}()
+	if err != nil && h.runner.logger != nil {
+		h.runner.logger.Warn("failed to persist handoffs", map[string]any{"error": err.Error()})
+	}
+}
+
+func (h *handoffs) emit(ctx context.Context, taskID, action, source string, fields map[string]any) {
+	if h.audit == nil {
+		return
+	}
+	fields["action"] = action
+	fields["source"] = source
+	h.audit.EmitFromContext(ctx, coreruntime.AuditEvent{Event: coreruntime.AuditHandoff, TaskID: taskID, Fields: fields})
+}
+
+// chatText returns the text of msg without the channel context line
+// the channel router prefixes ("[channel:<name> channel_target:<id>]").
+func chatText(msg a2a.Message) string {
+	var text []string
+	for _, p := range msg.Parts {
+		if p.Kind == a2a.PartKindText {
+			text = append(text, p.Text)
+		}
+	}
+	s := strings.TrimSpace(strings.Join(text, "\n"))
+	if strings.HasPrefix(s, "[channel:") {
+		if _, rest, ok := strings.Cut(s, "\n"); ok {
+			s = strings.TrimSpace(rest)
+		}
+	}
+	return s
+}
+
+// handoffIntercept handles a message for a conversation held by the
+// operators, instead of the agent: /resume-bot hands it back, anything
+// else is forwarded to the operators and answered with a status message
+// marked a2a.MetadataKeyHandoff, which channel adapters do not post. It
+// reports false for conversations with the agent.
+func (r *Runner) handoffIntercept(ctx context.Context, store *a2a.TaskStore, params a2a.SendTaskParams) (*a2a.Task, bool) {
+	if r.handoff == nil || params.Message.Role != a2a.MessageRoleUser || !r.handoff.isActive(params.ID) {
+		return nil, false
+	}
+	unlock, err := r.cluster.lockTask(ctx, params.ID)
+	if err != nil {
+		return nil, false
+	}
+	defer unlock()
+
+	task := store.Get(params.ID)
+	if task == nil {
+		task = &a2a.Task{ID: params.ID}
+	}
+	text := chatText(params.Message)
+	if text == resumeBotCommand {
+		if err := r.handoff.resume(ctx, params.ID, "chat"); err == nil {
+			task.Status = a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: &a2a.Message{
+				Role:  a2a.MessageRoleAgent,
+				Parts: []a2a.Part{a2a.NewTextPart(resumeBotReply)},
+			}}
+			store.Put(task)
+			return task, true
+		}
+		return nil, false
+	}
+
+	task.History = append(task.History, params.Message)
+	if err := r.handoff.forward(ctx, params.ID, text); err != nil {
+		task.Status = a2a.TaskStatus{State: a2a.TaskStateFailed, Message: &a2a.Message{
+			Role:  a2a.MessageRoleAgent,
+			Parts: []a2a.Part{a2a.NewTextPart(handoffUndeliveredReply)},
+		}}
+	} else {
+		task.Status = a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: &a2a.Message{
+			Role:     a2a.MessageRoleAgent,
+			Parts:    []a2a.Part{},
+			Metadata: map[string]any{a2a.MetadataKeyHandoff: true},
+		}}
+	}
+	store.Put(task)
+	return task, true
+}
+
+// registerHandoffTool registers handoff_to_human when an operator
+// channel is configured.
+func (r *Runner) registerHandoffTool(reg *tools.Registry) {
+	if r.handoff == nil {
+		return
+	}
+	if err := reg.Register(builtins.NewHandoffTool(r.handoff)); err != nil {
+		r.logger.Warn("failed to register handoff_to_human tool", map[string]any{"error": err.Error()})
+	}
+}
+
+// registerHandoffEndpoints wires GET /handoffs and POST
+// /handoffs/{id}/resume when an operator channel is configured.
+func (r *Runner) registerHandoffEndpoints(srv *server.Server) {
+	if r.handoff == nil {
+		return
+	}
+	h := r.handoff
+	srv.RegisterHTTPHandler("GET /handoffs", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"handoffs": h.list()})
+	})
+	srv.RegisterHTTPHandler("POST /handoffs/{id}/resume", makeHandoffResumeHandler(h))
+}
+
+// makeHandoffResumeHandler is extracted so tests can exercise it
+// without a full server. 404 when the conversation is not held.
+func makeHandoffResumeHandler(h *handoffs) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := req.PathValue("id")
+		if err := h.resume(req.Context(), id, "api"); err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "resumed", "task_id": id})
+	}
+}
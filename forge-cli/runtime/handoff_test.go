@@ -0,0 +1,121 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func newTestHandoffs(t *testing.T, path string) (*Runner, *[]sentNotification, *bytes.Buffer) {
+	t.Helper()
+	var sent []sentNotification
+	r := &Runner{}
+	r.SetNotifySender(func(_ context.Context, channel, target string, msg *a2a.Message) error {
+		sent = append(sent, sentNotification{channel, target, msg.Parts[0].Text})
+		return nil
+	})
+	var audit bytes.Buffer
+	h, err := newHandoffs(r, types.HandoffConfig{Channel: "slack", Target: "C0SUPPORT"}, coreruntime.NewAuditLogger(&audit), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.handoff = h
+	return r, &sent, &audit
+}
+
+func userMessage(text string) a2a.Message {
+	return a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(text)}}
+}
+
+func TestHandoff_HoldForwardResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handoffs.json")
+	r, sent, audit := newTestHandoffs(t, path)
+	store := a2a.NewTaskStore()
+	const taskID = "telegram-42-7"
+	ctx := coreruntime.WithTaskID(context.Background(), taskID)
+
+	// With the agent, messages pass through.
+	if _, ok := r.handoffIntercept(ctx, store, a2a.SendTaskParams{ID: taskID, Message: userMessage("hi")}); ok {
+		t.Fatal("message intercepted before any handoff")
+	}
+
+	tool := builtins.NewHandoffTool(r.handoff)
+	if _, err := tool.Execute(ctx, []byte(`{"reason":"refund over limit","summary":"wants $900 back"}`)); err != nil {
+		t.Fatalf("handoff_to_human: %v", err)
+	}
+	if len(*sent) != 1 || (*sent)[0].channel != "slack" || (*sent)[0].target != "C0SUPPORT" ||
+		!strings.Contains((*sent)[0].text, "refund over limit") || !strings.Contains((*sent)[0].text, "wants $900 back") {
+		t.Fatalf("operator notice = %+v", *sent)
+	}
+
+	// The hold survives a restart.
+	r2, sent2, _ := newTestHandoffs(t, path)
+	task, ok := r2.handoffIntercept(ctx, store, a2a.SendTaskParams{
+		ID: taskID, Message: userMessage("[channel:telegram channel_target:42]\nare you there?"),
+	})
+	if !ok {
+		t.Fatal("held conversation reached the agent")
+	}
+	if held, _ := task.Status.Message.Metadata[a2a.MetadataKeyHandoff].(bool); !held || len(task.Status.Message.Parts) != 0 {
+		t.Errorf("forwarded status message = %+v", task.Status.Message)
+	}
+	if len(*sent2) != 1 || !strings.HasSuffix((*sent2)[0].text, "\nare you there?") {
+		t.Errorf("forwarded = %+v", *sent2)
+	}
+
+	task, ok = r2.handoffIntercept(ctx, store, a2a.SendTaskParams{ID: taskID, Message: userMessage("/resume-bot")})
+	if !ok || task.Status.Message.Parts[0].Text != resumeBotReply {
+		t.Fatalf("/resume-bot = %+v, %v", task, ok)
+	}
+	if _, ok := r2.handoffIntercept(ctx, store, a2a.SendTaskParams{ID: taskID, Message: userMessage("thanks")}); ok {
+		t.Error("resumed conversation still held")
+	}
+	if got := strings.Count(audit.String(), `"event":"handoff"`); got != 1 {
+		t.Errorf("audited %d handoff events, want 1:\n%s", got, audit.String())
+	}
+}
+
+func TestHandoff_ResumeEndpoint(t *testing.T) {
+	r, _, audit := newTestHandoffs(t, "")
+	ctx := coreruntime.WithTaskID(context.Background(), "slack-C1-171.1")
+	if err := r.handoff.HandOff(ctx, builtins.HandoffRequest{Reason: "asked for a person"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.handoff.list(); len(got) != 1 || got[0].TaskID != "slack-C1-171.1" {
+		t.Errorf("list = %+v", got)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /handoffs/{id}/resume", makeHandoffResumeHandler(r.handoff))
+	for _, want := range []int{http.StatusOK, http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/handoffs/slack-C1-171.1/resume", nil))
+		if rec.Code != want {
+			t.Errorf("status = %d, want %d: %s", rec.Code, want, rec.Body.String())
+		}
+	}
+	if !strings.Contains(audit.String(), `"action":"resume"`) || !strings.Contains(audit.String(), `"source":"api"`) {
+		t.Errorf("audit = %s", audit.String())
+	}
+}
+
+func TestHandoff_OperatorsUnreachable(t *testing.T) {
+	r := &Runner{}
+	h, _ := newHandoffs(r, types.HandoffConfig{Channel: "slack", Target: "C0SUPPORT"}, nil, "")
+	ctx := coreruntime.WithTaskID(context.Background(), "slack-C1-171.1")
+	if err := h.HandOff(ctx, builtins.HandoffRequest{Reason: "x"}); err == nil {
+		t.Fatal("handoff without a sender succeeded")
+	}
+	if h.isActive("slack-C1-171.1") {
+		t.Error("conversation held although the operators were never told")
+	}
+}
@@ -27,6 +27,11 @@ import (
 // JSON-RPC / REST handler entry, downstream of auth; the auth callback's
 // audit emits used plain Emit() and lost seq + trace_id + workflow tags.
 //
+// The correlation id is also echoed on the response as
+// X-Forge-Correlation-Id, so a caller — the channel router, an
+// orchestrator, a curl user — can pivot straight to the invocation's
+// audit events without parsing the body.
+//
 // Cost: ~24 bytes + a UUID per request. The wrapper runs even on auth-skipped
 // paths (/.well-known/agent-card.json, /healthz); those don't emit
 // per-request audit events, so the values are unused — allocating
@@ -40,6 +45,7 @@ func installIngressContextMiddleware(authMW func(http.Handler) http.Handler) fun
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := coreruntime.EnsureSequenceCounter(r.Context())
 			ctx = coreruntime.EnsureCorrelationID(ctx)
+			w.Header().Set(coreruntime.HeaderForgeCorrelationID, coreruntime.CorrelationIDFromContext(ctx))
 			composed.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
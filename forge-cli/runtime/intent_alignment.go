@@ -51,14 +51,9 @@ func (r *Runner) buildIntentEngine() (*intent.Engine, error) {
 	apiKey := ""
 	if cfg.APIKeyEnv != "" {
 		apiKey = os.Getenv(cfg.APIKeyEnv)
-	} else {
+	} else if env := providers.EmbeddingAPIKeyEnv(cfg.Provider); env != "" {
 		// Provider defaults; providers themselves fall back further.
-		switch cfg.Provider {
-		case "openai":
-			apiKey = os.Getenv("OPENAI_API_KEY")
-		case "gemini":
-			apiKey = os.Getenv("GEMINI_API_KEY")
-		}
+		apiKey = os.Getenv(env)
 	}
 	embedder, err := providers.NewEmbedder(cfg.Provider, providers.OpenAIEmbedderConfig{
 		Model:   cfg.Model,
@@ -180,7 +175,7 @@ func (r *Runner) registerIntentAlignmentHook(hooks *coreruntime.HookRegistry, re
 		}
 
 		if res.Decision == intent.DecisionDeny {
-			return fmt.Errorf("intent_alignment: %s", res.Reason)
+			return coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("intent_alignment: %s", res.Reason))
 		}
 		return nil
 	})
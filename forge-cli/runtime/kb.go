@@ -0,0 +1,219 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/memory"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+)
+
+// maxKBFileBytes skips documents too large to be worth indexing whole.
+const maxKBFileBytes = 20 << 20
+
+// kbExtensions are the document types the knowledge base ingests.
+var kbExtensions = map[string]bool{
+	".md": true, ".markdown": true, ".txt": true,
+	".html": true, ".htm": true, ".pdf": true,
+}
+
+// KBSkip is a document LoadKBDocuments passed over, and why.
+type KBSkip struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// KBSyncReport is the outcome of SyncKnowledgeBase.
+type KBSyncReport struct {
+	*memory.KBSyncResult
+	Skipped []KBSkip `json:"skipped,omitempty"`
+}
+
+// LoadKBDocuments walks paths (files or directories, relative to
+// workDir) and extracts the text of every markdown, text, HTML and PDF
+// document not matched by exclude. It returns the documents with the
+// cleaned roots they were found under. Hidden directories are not
+// entered. PDFs need pdftotext (poppler-utils) on PATH and are skipped
+// without it.
+func LoadKBDocuments(workDir string, paths, exclude []string) ([]memory.KBDocument, []string, []KBSkip, error) {
+	var docs []memory.KBDocument
+	var roots []string
+	var skipped []KBSkip
+	for _, p := range paths {
+		abs := p
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(workDir, p)
+		}
+		root := kbRelPath(workDir, abs)
+		roots = append(roots, root)
+
+		err := filepath.WalkDir(abs, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel := kbRelPath(workDir, file)
+			if d.IsDir() {
+				if file != abs && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !kbExtensions[strings.ToLower(filepath.Ext(file))] || kbExcluded(rel, exclude) {
+				return nil
+			}
+			doc, reason := extractKBDocument(file, rel)
+			if reason != "" {
+				skipped = append(skipped, KBSkip{Path: rel, Reason: reason})
+				return nil
+			}
+			docs = append(docs, doc)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("reading knowledge base path %s: %w", p, err)
+		}
+	}
+	return docs, roots, skipped, nil
+}
+
+// extractKBDocument reads file and returns its text, or a reason it was
+// skipped.
+func extractKBDocument(file, rel string) (memory.KBDocument, string) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return memory.KBDocument{}, err.Error()
+	}
+	if info.Size() > maxKBFileBytes {
+		return memory.KBDocument{}, fmt.Sprintf("larger than %d MB", maxKBFileBytes>>20)
+	}
+
+	var text string
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".pdf":
+		bin, err := exec.LookPath("pdftotext")
+		if err != nil {
+			return memory.KBDocument{}, "pdftotext not found; install poppler-utils to ingest PDFs"
+		}
+		var stderr bytes.Buffer
+		cmd := exec.Command(bin, "-enc", "UTF-8", file, "-")
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return memory.KBDocument{}, "pdftotext: " + strings.TrimSpace(stderr.String()+" "+err.Error())
+		}
+		// pdftotext separates pages with form feeds.
+		text = strings.ReplaceAll(string(out), "\f", "\n\n")
+	case ".html", ".htm":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return memory.KBDocument{}, err.Error()
+		}
+		text = builtins.ExtractReadableText(string(data))
+	default:
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return memory.KBDocument{}, err.Error()
+		}
+		text = string(data)
+	}
+	if !utf8.ValidString(text) {
+		return memory.KBDocument{}, "not UTF-8 text"
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return memory.KBDocument{}, "no text"
+	}
+	return memory.KBDocument{Path: rel, Title: kbTitle(text, file), Text: text}, ""
+}
+
+// kbTitle is the document's first markdown heading, or its file name.
+func kbTitle(text, file string) string {
+	for _, line := range strings.SplitN(text, "\n", 20) {
+		if h, ok := strings.CutPrefix(strings.TrimSpace(line), "# "); ok && strings.TrimSpace(h) != "" {
+			return strings.TrimSpace(h)
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+// kbRelPath is file relative to workDir with forward slashes, or the
+// absolute path for files outside it.
+func kbRelPath(workDir, file string) string {
+	rel, err := filepath.Rel(workDir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filepath.Clean(file))
+	}
+	return filepath.ToSlash(rel)
+}
+
+// kbExcluded reports whether an exclude glob matches rel or its base name.
+func kbExcluded(rel string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncKnowledgeBase indexes the documents under paths (kb.paths when
+// empty) into the knowledge base partition of long-term memory, using
+// the same embedder the agent searches with. It backs `forge kb sync`.
+func (r *Runner) SyncKnowledgeBase(ctx context.Context, paths []string) (*KBSyncReport, error) {
+	if len(paths) == 0 {
+		paths = r.cfg.Config.KB.Paths
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no documents to sync: pass paths or set kb.paths in forge.yaml")
+	}
+	envVars, err := r.loadEnvVars()
+	if err != nil {
+		return nil, err
+	}
+	mc := coreruntime.ResolveModelConfig(r.cfg.Config, envVars, r.cfg.ProviderOverride)
+	if mc == nil {
+		mc = &coreruntime.ModelConfig{}
+	}
+	mgr, err := memory.NewManager(memory.ManagerConfig{
+		MemoryDir: MemoryDir(r.cfg.Config.Memory, r.cfg.WorkDir),
+		Embedder:  r.resolveEmbedder(mc),
+		Logger:    r.logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	report, syncErr := r.syncKB(ctx, mgr, paths)
+	if err := mgr.Close(); err != nil && syncErr == nil {
+		syncErr = err
+	}
+	return report, syncErr
+}
+
+// syncKB loads the documents under paths and syncs them into mgr.
+func (r *Runner) syncKB(ctx context.Context, mgr *memory.Manager, paths []string) (*KBSyncReport, error) {
+	docs, roots, skipped, err := LoadKBDocuments(r.cfg.WorkDir, paths, r.cfg.Config.KB.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range skipped {
+		r.logger.Warn("knowledge base document skipped", map[string]any{"path": s.Path, "reason": s.Reason})
+	}
+	res, err := mgr.SyncKB(ctx, roots, docs)
+	if err != nil {
+		return nil, err
+	}
+	return &KBSyncReport{KBSyncResult: res, Skipped: skipped}, nil
+}
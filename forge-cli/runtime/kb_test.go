@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKBDocuments(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"docs/setup.md":          "# Setup Guide\n\nRun forge init.",
+		"docs/page.html":         "<html><head><title>Pricing</title><script>x()</script></head><body><p>Plans start at $10.</p></body></html>",
+		"docs/drafts/wip.md":     "draft",
+		"docs/.hidden/secret.md": "hidden",
+		"docs/logo.png":          "\x89PNG",
+		"docs/empty.txt":         "   ",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs, roots, skipped, err := LoadKBDocuments(dir, []string{"./docs"}, []string{"docs/drafts/*"})
+	if err != nil {
+		t.Fatalf("LoadKBDocuments: %v", err)
+	}
+	if len(roots) != 1 || roots[0] != "docs" {
+		t.Errorf("roots = %v", roots)
+	}
+	got := map[string]string{}
+	for _, d := range docs {
+		got[d.Path] = d.Title
+	}
+	if len(got) != 2 || got["docs/setup.md"] != "Setup Guide" || got["docs/page.html"] != "Pricing" {
+		t.Errorf("documents = %+v", docs)
+	}
+	for _, d := range docs {
+		if d.Path == "docs/page.html" && d.Text != "# Pricing\n\nPlans start at $10." {
+			t.Errorf("html text = %q", d.Text)
+		}
+	}
+	if len(skipped) != 1 || skipped[0].Path != "docs/empty.txt" {
+		t.Errorf("skipped = %+v", skipped)
+	}
+}
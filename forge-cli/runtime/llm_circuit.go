@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// registerCircuitAudit reports the fallback chain's circuit-breaker
+// transitions as llm_circuit audit events and ops-log lines, so a
+// degrading provider is visible rather than silently failed over.
+// No-op without fallbacks (a single candidate has no breaker).
+func (r *Runner) registerCircuitAudit(auditLogger *coreruntime.AuditLogger) {
+	if r.fallbackChain == nil {
+		return
+	}
+	r.fallbackChain.SetTransitionHandler(func(t llm.CircuitTransition) {
+		fields := map[string]any{
+			"from":   string(t.From),
+			"to":     string(t.To),
+			"reason": t.Reason,
+		}
+		if t.To == llm.CircuitOpen {
+			if t.FailureRate > 0 {
+				fields["failure_rate"] = t.FailureRate
+			}
+			fields["open_seconds"] = int(t.OpenFor.Seconds())
+		}
+		if auditLogger != nil {
+			auditLogger.Emit(coreruntime.AuditEvent{
+				Event:    coreruntime.AuditLLMCircuit,
+				Model:    t.Model,
+				Provider: t.Provider,
+				Fields:   fields,
+			})
+		}
+		logFields := map[string]any{"provider": t.Provider, "model": t.Model}
+		for k, v := range fields {
+			logFields[k] = v
+		}
+		if t.To == llm.CircuitOpen {
+			r.logger.Warn("LLM provider circuit opened", logFields)
+		} else {
+			r.logger.Info("LLM provider circuit "+string(t.To), logFields)
+		}
+	})
+}
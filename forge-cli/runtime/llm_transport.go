@@ -0,0 +1,43 @@
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/llm/providers"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// initLLMTransport builds the HTTP transport every provider client
+// shares, from forge.yaml model.http. It is built once and outlives
+// client rebuilds so the pool stays warm. Unlike the response cache, a
+// bad setting fails the build: a proxy or CA the operator asked for
+// must not be silently skipped.
+func (r *Runner) initLLMTransport(mc *coreruntime.ModelConfig) error {
+	if r.llmTransport != nil {
+		return nil
+	}
+	cfg := mc.HTTP
+	if cfg.CAFile != "" && !filepath.IsAbs(cfg.CAFile) {
+		cfg.CAFile = filepath.Join(r.cfg.WorkDir, cfg.CAFile)
+	}
+	t, err := providers.NewTransport(cfg)
+	if err != nil {
+		return fmt.Errorf("model.http: %w", err)
+	}
+	r.llmTransport = t
+	return nil
+}
+
+// registerLLMTransportEndpoint wires GET /admin/llm-transport, which
+// reports the shared transport's request and connection-reuse counters.
+func (r *Runner) registerLLMTransportEndpoint(srv *server.Server) {
+	if r.llmTransport == nil {
+		return
+	}
+	srv.RegisterHTTPHandler("GET /admin/llm-transport", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, r.llmTransport.Stats())
+	})
+}
@@ -0,0 +1,215 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/observability/llmtrace"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// llmTraceCloseTimeout bounds the export of the traces still queued at
+// shutdown.
+const llmTraceCloseTimeout = 5 * time.Second
+
+// startLLMTraceExport exports task traces to the LLM observability
+// platforms forge.yaml `observability.langfuse` / `langsmith` enable,
+// fed from the event bus. The returned func flushes the traces still
+// open or queued; call it after the event bus has drained. A platform
+// whose keys are missing is skipped with a warning, like an
+// unreachable OTLP collector: tracing never stops the agent.
+func (r *Runner) startLLMTraceExport(envVars map[string]string, agentID string) func() {
+	if r.cfg.Config == nil {
+		return func() {}
+	}
+	obs := r.cfg.Config.Observability
+	lookup := func(key string) string {
+		if v := envVars[key]; v != "" {
+			return v
+		}
+		return osEnvMap()[key]
+	}
+
+	var exporters []llmtrace.Exporter
+	capture := map[string]bool{}
+	if obs.Langfuse.Enabled {
+		endpoint := obs.Langfuse.Endpoint
+		if endpoint == "" {
+			endpoint = lookup("LANGFUSE_HOST")
+		}
+		lf, err := llmtrace.NewLangfuse(endpoint, lookup("LANGFUSE_PUBLIC_KEY"), lookup("LANGFUSE_SECRET_KEY"), nil)
+		if err != nil {
+			r.logger.Warn("langfuse trace export disabled", map[string]any{"error": err.Error()})
+		} else {
+			exporters = append(exporters, lf)
+			capture[lf.Name()] = obs.Langfuse.CaptureContent
+		}
+	}
+	if obs.LangSmith.Enabled {
+		endpoint, project := obs.LangSmith.Endpoint, obs.LangSmith.Project
+		if endpoint == "" {
+			endpoint = lookup("LANGSMITH_ENDPOINT")
+		}
+		if project == "" {
+			project = lookup("LANGSMITH_PROJECT")
+		}
+		if project == "" {
+			project = agentID
+		}
+		ls, err := llmtrace.NewLangSmith(endpoint, lookup("LANGSMITH_API_KEY"), project, nil)
+		if err != nil {
+			r.logger.Warn("langsmith trace export disabled", map[string]any{"error": err.Error()})
+		} else {
+			exporters = append(exporters, ls)
+			capture[ls.Name()] = obs.LangSmith.CaptureContent
+		}
+	}
+	if len(exporters) == 0 {
+		return func() {}
+	}
+
+	// Content capture is per platform; a platform without it gets the
+	// trace stripped of content.
+	for i, e := range exporters {
+		if !capture[e.Name()] {
+			exporters[i] = contentless{e}
+		}
+	}
+	withContent := false
+	for _, on := range capture {
+		withContent = withContent || on
+	}
+
+	rec := llmtrace.NewRecorder(func(exporter string, err error) {
+		r.logger.Warn("llm trace export failed", map[string]any{"exporter": exporter, "error": err.Error()})
+	}, exporters...)
+	t := &llmTracer{rec: rec, agentID: agentID, capture: withContent}
+	r.events.Subscribe(t.observe,
+		coreruntime.TopicTaskStarted, coreruntime.TopicLLMCall,
+		coreruntime.TopicToolEnd, coreruntime.TopicTaskFinished)
+
+	names := make([]string, 0, len(exporters))
+	for _, e := range exporters {
+		names = append(names, e.Name())
+	}
+	r.logger.Info("llm trace export enabled", map[string]any{"exporters": names, "capture_content": withContent})
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), llmTraceCloseTimeout)
+		defer cancel()
+		if err := rec.Close(ctx); err != nil {
+			r.logger.Warn("llm trace export: traces left unexported at shutdown", map[string]any{"error": err.Error()})
+		}
+	}
+}
+
+// llmTracer turns the event bus's task, LLM and tool events into
+// llmtrace traces, one per correlation ID.
+type llmTracer struct {
+	rec     *llmtrace.Recorder
+	agentID string
+	capture bool
+}
+
+func (t *llmTracer) observe(ctx context.Context, e coreruntime.Event) {
+	switch e.Topic {
+	case coreruntime.TopicTaskStarted:
+		md := map[string]any{"task_id": e.TaskID}
+		if tenant := coreruntime.TenantFromContext(ctx); tenant != "" {
+			md["tenant"] = tenant
+		}
+		if exp := coreruntime.ExperimentFromContext(ctx); exp != nil {
+			md["experiment"] = exp.String()
+		}
+		t.rec.Start(llmtrace.Trace{ID: e.CorrelationID, Name: t.agentID, SessionID: e.TaskID, Start: e.Time, Metadata: md})
+
+	case coreruntime.TopicLLMCall:
+		h := e.Hook
+		if h == nil || h.Response == nil {
+			return
+		}
+		model, provider := h.Model, h.Provider
+		if h.Response.Route != nil {
+			model, provider = h.Response.Route.Model, h.Response.Route.Provider
+		}
+		o := llmtrace.Observation{
+			Kind:         llmtrace.KindGeneration,
+			Name:         model,
+			Start:        e.Time.Add(-h.LLMCallDuration),
+			End:          e.Time,
+			Provider:     provider,
+			Model:        model,
+			InputTokens:  h.Response.Usage.InputTokens,
+			OutputTokens: h.Response.Usage.OutputTokens,
+		}
+		if t.capture {
+			if raw, err := json.Marshal(h.Messages); err == nil {
+				o.Input = captureTraceContent(string(raw))
+			}
+			o.Output = captureTraceContent(h.Response.Message.Content)
+			// The trace's input is the message that started the turn.
+			if in := lastUserMessage(h.Messages); in != "" {
+				t.rec.Start(llmtrace.Trace{ID: e.CorrelationID, Input: captureTraceContent(in)})
+			}
+		}
+		t.rec.Observe(e.CorrelationID, o)
+
+	case coreruntime.TopicToolEnd:
+		h := e.Hook
+		if h == nil {
+			return
+		}
+		o := llmtrace.Observation{
+			ID:    h.ToolCallID,
+			Kind:  llmtrace.KindTool,
+			Name:  h.ToolName,
+			Start: e.Time.Add(-h.ToolExecDuration),
+			End:   e.Time,
+		}
+		if h.Error != nil {
+			o.Error = h.Error.Error()
+		}
+		if t.capture {
+			o.Input = captureTraceContent(h.ToolInput)
+			o.Output = captureTraceContent(h.ToolOutput)
+		}
+		t.rec.Observe(e.CorrelationID, o)
+
+	case coreruntime.TopicTaskFinished:
+		state, _ := e.Fields["state"].(string)
+		t.rec.End(e.CorrelationID, e.Time, state, "")
+	}
+}
+
+// captureTraceContent redacts secrets from s and caps its size, as for
+// captured span and audit content.
+func captureTraceContent(s string) string {
+	return coreruntime.PrepareCapturedContent(s, true, coreruntime.CapOrDefault(0))
+}
+
+// lastUserMessage is the content of the last user message of msgs.
+func lastUserMessage(msgs []llm.ChatMessage) string {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == llm.RoleUser {
+			return msgs[i].Content
+		}
+	}
+	return ""
+}
+
+// contentless strips the content from the traces it exports, for a
+// platform configured without capture_content when another has it.
+type contentless struct{ llmtrace.Exporter }
+
+func (c contentless) Export(ctx context.Context, t *llmtrace.Trace) error {
+	stripped := *t
+	stripped.Input, stripped.Output = "", ""
+	stripped.Observations = make([]llmtrace.Observation, len(t.Observations))
+	for i, o := range t.Observations {
+		o.Input, o.Output = "", ""
+		stripped.Observations[i] = o
+	}
+	return c.Exporter.Export(ctx, &stripped)
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/initializ/forge/forge-core/a2a"
@@ -18,19 +21,23 @@ import (
 	"github.com/initializ/forge/forge-core/types"
 )
 
-// LocalSession is an in-process agent runtime for `forge try` (issue #350). It
-// assembles the SAME coreruntime.LLMExecutor that `forge run` uses — the
-// built-in tool registry, egress enforcement (in-process client + subprocess
+// LocalSession is an in-process agent runtime for `forge try` (issue #350),
+// `forge chat` and `forge batch run`. It assembles the SAME
+// coreruntime.LLMExecutor that `forge run` uses — the built-in tool registry, egress enforcement (in-process client + subprocess
 // proxy), audit + progress hooks, and provider client — but WITHOUT an HTTP
 // server, scheduler, MCP, admission, auth, or long-term memory. There is no
 // second executor: this is a trimmed bootstrap around the shared sub-builders.
 //
-// Turns run one at a time via RunTurn. Conversation history is kept in memory
+// Turns run one at a time via RunTurn; RunTask runs independent tasks, which
+// may overlap. Conversation history is kept in memory
 // and never persisted (the executor Store is nil), so nothing touches disk for
 // the ephemeral run.
 type LocalSession struct {
 	runner       *Runner
-	executor     coreruntime.AgentExecutor
+	executor     *coreruntime.LLMExecutor
+	client       *reloadableClient // swapped by SwitchModel
+	tools        *tools.Registry
+	envVars      map[string]string
 	audit        *coreruntime.AuditLogger
 	egressClient *http.Client
 	proxyStop    func()
@@ -46,6 +53,9 @@ type LocalSession struct {
 // synchronous (one turn at a time), so no locking is needed.
 type errBox struct{ err error }
 
+// errBoxKey carries a per-call errBox for RunTask, whose calls may overlap.
+type errBoxKey struct{}
+
 // LocalSessionOptions configure an in-process `forge try` session.
 type LocalSessionOptions struct {
 	Config       *types.ForgeConfig
@@ -106,7 +116,7 @@ func NewLocalSession(ctx context.Context, opts LocalSessionOptions) (*LocalSessi
 	// Resolve the model first — registerSkillTools reads r.modelConfig.
 	mc := coreruntime.ResolveModelConfig(opts.Config, envVars, r.cfg.ProviderOverride)
 	if mc == nil {
-		return nil, fmt.Errorf("no model provider could be resolved for the demo agent")
+		return nil, fmt.Errorf("no model provider could be resolved for the agent")
 	}
 	r.modelConfig = mc
 
@@ -123,6 +133,7 @@ func NewLocalSession(ctx context.Context, opts LocalSessionOptions) (*LocalSessi
 	// socksURL is empty: the keyless demo has no raw-TCP allowlist, so the
 	// SOCKS5 listener is never started (see buildTryEgress).
 	r.registerSkillTools(reg, proxyURL, "")
+	r.applyReadOnlyMode(reg)
 
 	llmClient, err := r.buildLLMClient(mc)
 	if err != nil {
@@ -138,26 +149,46 @@ func NewLocalSession(ctx context.Context, opts LocalSessionOptions) (*LocalSessi
 	// then returns a canned "something went wrong" string, so without this the
 	// actual provider failure (e.g. an OAuth/gateway 4xx) is invisible.
 	eb := &errBox{}
-	hooks.Register(coreruntime.OnError, func(_ context.Context, hctx *coreruntime.HookContext) error {
-		if hctx.Error != nil {
+	hooks.Register(coreruntime.OnError, func(ctx context.Context, hctx *coreruntime.HookContext) error {
+		if hctx.Error == nil {
+			return nil
+		}
+		if box, ok := ctx.Value(errBoxKey{}).(*errBox); ok {
+			box.err = hctx.Error
+		} else {
 			eb.err = hctx.Error
 		}
 		return nil
 	})
 
-	executor := coreruntime.NewLLMExecutor(coreruntime.LLMExecutorConfig{
-		Client:       llmClient,
+	if err := r.loadPrompts(); err != nil {
+		proxyStop()
+		return nil, err
+	}
+
+	client := newReloadableClient(llmClient)
+	execCfg := coreruntime.LLMExecutorConfig{
+		Client:       client,
 		Tools:        reg,
 		Hooks:        hooks,
 		SystemPrompt: r.buildSystemPrompt(),
 		ModelName:    mc.Client.Model,
 		Provider:     mc.Provider,
 		// Store is nil: history rides in task.History, nothing persists.
-	})
+	}
+	r.applySandbox(reg, &execCfg)
+	r.applyLoopLimits(&execCfg)
+	r.applyReflection(&execCfg, mc, nil)
+	r.applyOutputContracts(&execCfg)
+	r.applyExecutorMode(&execCfg)
+	executor := coreruntime.NewLLMExecutor(execCfg)
 
 	return &LocalSession{
 		runner:       r,
 		executor:     executor,
+		client:       client,
+		tools:        reg,
+		envVars:      envVars,
 		audit:        audit,
 		egressClient: egressClient,
 		proxyStop:    proxyStop,
@@ -195,6 +226,81 @@ func (s *LocalSession) RunTurn(ctx context.Context, prompt string, progress core
 	return messageText(resp), nil
 }
 
+// RunTask runs prompt as a fresh task with no history, for `forge batch run`.
+// Unlike RunTurn it is safe to call concurrently. It returns the agent's text
+// reply and the task's LLM usage.
+func (s *LocalSession) RunTask(ctx context.Context, taskID, prompt string) (string, coreruntime.LLMUsageSnapshot, error) {
+	ctx = security.WithEgressClient(ctx, s.egressClient)
+	acc := coreruntime.NewLLMUsageAccumulator()
+	ctx = coreruntime.WithLLMUsageAccumulator(ctx, acc)
+	eb := &errBox{}
+	ctx = context.WithValue(ctx, errBoxKey{}, eb)
+
+	task := &a2a.Task{ID: taskID}
+	userMsg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(prompt)}}
+	resp, err := s.executor.Execute(ctx, task, userMsg)
+	if err != nil {
+		if eb.err != nil {
+			err = eb.err
+		}
+		return "", acc.Snapshot(), err
+	}
+	return messageText(resp), acc.Snapshot(), nil
+}
+
+// Tools returns the names of the tools the session's agent can call, sorted.
+func (s *LocalSession) Tools() []string {
+	names := s.tools.List()
+	sort.Strings(names)
+	return names
+}
+
+// History returns the conversation so far, oldest first.
+func (s *LocalSession) History() []a2a.Message { return slices.Clone(s.history) }
+
+// Reset forgets the conversation; the next turn starts a fresh one.
+func (s *LocalSession) Reset() { s.history = nil }
+
+// Model returns the "provider/model" the session is talking to.
+func (s *LocalSession) Model() string {
+	mc := s.runner.modelConfig
+	return mc.Provider + "/" + mc.Client.Model
+}
+
+// SwitchModel points the session at another provider and/or model, as
+// POST /admin/model does for a running agent: an empty provider keeps the
+// current one, an empty model takes the provider's default. History is kept.
+// It returns the "provider/model" now serving.
+func (s *LocalSession) SwitchModel(provider, model string) (string, error) {
+	r := s.runner
+	cfg, err := switchedModelConfig(r.cfg.Config, r.modelConfig, builtins.ModelSwitchRequest{Provider: provider, Model: model})
+	if err != nil {
+		return "", err
+	}
+	// The request names the model outright; the env overrides that pick it
+	// at startup must not win over it.
+	envVars := maps.Clone(s.envVars)
+	delete(envVars, "MODEL_NAME")
+	delete(envVars, "FORGE_MODEL_PROVIDER")
+	mc := coreruntime.ResolveModelConfig(cfg, envVars, "")
+	if mc == nil {
+		return "", fmt.Errorf("no model provider could be resolved for %s", provider)
+	}
+
+	prevChain := r.fallbackChain
+	r.fallbackChain = nil
+	client, err := r.buildLLMClient(mc)
+	if err != nil {
+		r.fallbackChain = prevChain
+		return "", fmt.Errorf("building model client: %w", err)
+	}
+	s.client.set(client)
+	s.executor.SetModel(mc.Provider, mc.Client.Model)
+	r.modelConfig = mc
+	r.cfg.Config = cfg
+	return s.Model(), nil
+}
+
 // AuditLogger exposes the session's audit logger so the visible-loop renderer
 // (Phase 4) can attach itself as an additional sink.
 func (s *LocalSession) AuditLogger() *coreruntime.AuditLogger { return s.audit }
@@ -264,7 +370,16 @@ func (r *Runner) buildTryEgress(ctx context.Context, envVars map[string]string,
 		allowedPrivateCIDRs = nil
 	}
 
+	constraints, err := security.NewEgressConstraints(r.cfg.Config.Egress.AllowedSchemes, r.cfg.Config.Egress.MaxResponseBytes, r.cfg.Config.Egress.Rules)
+	if err != nil {
+		// Fail closed, as above.
+		r.logger.Warn("egress constraints invalid; denying all egress", map[string]any{"error": err.Error()})
+		denyAll := security.NewEgressEnforcer(nil, security.ModeAllowlist, nil, allowPrivateIPs, nil)
+		return &http.Client{Transport: observability.WrapHTTPTransport(denyAll)}, "", noop
+	}
+
 	enforcer := security.NewEgressEnforcer(nil, egressCfg.Mode, egressCfg.AllDomains, allowPrivateIPs, allowedPrivateCIDRs)
+	enforcer.SetConstraints(constraints)
 	enforcer.OnAttempt = func(ctx context.Context, domain string, allowed bool) {
 		audit.EmitFromContext(ctx, coreruntime.AuditEvent{
 			Event:         egressEvent(allowed),
@@ -273,6 +388,14 @@ func (r *Runner) buildTryEgress(ctx context.Context, envVars map[string]string,
 			Fields:        map[string]any{"domain": domain, "mode": string(egressCfg.Mode)},
 		})
 	}
+	enforcer.OnViolation = func(ctx context.Context, a security.EgressAttempt) {
+		audit.EmitFromContext(ctx, coreruntime.AuditEvent{
+			Event:         coreruntime.AuditEgressBlocked,
+			CorrelationID: coreruntime.CorrelationIDFromContext(ctx),
+			TaskID:        coreruntime.TaskIDFromContext(ctx),
+			Fields:        egressAttemptFields(a, map[string]any{"domain": a.Domain, "mode": string(egressCfg.Mode)}),
+		})
+	}
 	egressClient := &http.Client{Transport: observability.WrapHTTPTransport(enforcer)}
 
 	// Subprocess proxy for skill scripts (e.g. the weather skill's curl).
@@ -281,12 +404,14 @@ func (r *Runner) buildTryEgress(ctx context.Context, envVars map[string]string,
 	}
 	matcher := security.NewDomainMatcher(egressCfg.Mode, egressCfg.AllDomains)
 	proxy := security.NewEgressProxy(matcher, allowPrivateIPs, allowedPrivateCIDRs)
+	proxy.SetConstraints(constraints)
+	applyEgressProxyConfig(proxy, r.cfg.Config.Egress.Proxy)
 	proxy.OnAttempt = func(a security.EgressAttempt) {
 		audit.Emit(coreruntime.AuditEvent{
 			Event:         egressEvent(a.Allowed),
 			TaskID:        a.TaskID,
 			CorrelationID: a.CorrelationID,
-			Fields:        map[string]any{"domain": a.Domain, "mode": string(egressCfg.Mode), "source": "proxy"},
+			Fields:        egressAttemptFields(a, map[string]any{"domain": a.Domain, "mode": string(egressCfg.Mode), "source": "proxy"}),
 		})
 	}
 	proxyURL, perr := proxy.Start(ctx)
@@ -304,6 +429,26 @@ func egressEvent(allowed bool) string {
 	return coreruntime.AuditEgressBlocked
 }
 
+// egressAttemptFields adds the scheme, port, method and constraint
+// reason of an egress attempt to audit fields, skipping those the path
+// that saw it could not tell. The enforcer and the proxy share it so
+// their events carry the same keys.
+func egressAttemptFields(a security.EgressAttempt, fields map[string]any) map[string]any {
+	if a.Scheme != "" {
+		fields["scheme"] = a.Scheme
+	}
+	if a.Port != 0 {
+		fields["port"] = a.Port
+	}
+	if a.Method != "" {
+		fields["method"] = a.Method
+	}
+	if a.Reason != "" {
+		fields["reason"] = a.Reason
+	}
+	return fields
+}
+
 // messageText concatenates the text parts of an a2a message.
 func messageText(m *a2a.Message) string {
 	if m == nil {
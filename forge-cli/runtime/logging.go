@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/initializ/forge/forge-cli/server"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// SubsystemLogger returns the runner's ops logger scoped to one of the
+// coreruntime.LogSubsystem* names, so components wired outside the
+// runner (channel adapters in `forge run --with`) honor the same
+// runtime level overrides as everything inside it.
+func (r *Runner) SubsystemLogger(subsystem string) coreruntime.Logger {
+	return coreruntime.SubsystemLogger(r.logger, subsystem)
+}
+
+// logEgressAttempt writes the ops-log line for one egress decision.
+// Allowed connections are debug-level and carry the samplable
+// egress_allowed event — every outbound request produces one, so they
+// are the first thing to sample when debugging egress in production.
+// Blocked connections are rare and actionable, so they log at info.
+// The audit stream records both regardless of these settings.
+func logEgressAttempt(logger coreruntime.Logger, domain string, allowed bool, source string) {
+	if allowed {
+		logger.Debug("egress allowed", map[string]any{
+			"event":  coreruntime.LogEventEgressAllowed,
+			"domain": domain,
+			"source": source,
+		})
+		return
+	}
+	logger.Info("egress blocked", map[string]any{
+		"event":  coreruntime.AuditEgressBlocked,
+		"domain": domain,
+		"source": source,
+	})
+}
+
+// registerLoggingEndpoint wires the runtime ops-log controls:
+//
+//	GET  /admin/logging — current level, subsystem overrides, sampling
+//	POST /admin/logging — apply a coreruntime.LogSettingsUpdate
+//
+// Both sit behind the server's auth middleware like every other
+// non-public route. No-op when the ops logger is not a JSONLogger
+// (nothing to control).
+func (r *Runner) registerLoggingEndpoint(srv *server.Server, auditLogger *coreruntime.AuditLogger) {
+	jl, ok := r.logger.(*coreruntime.JSONLogger)
+	if !ok {
+		return
+	}
+	get, post := makeLoggingHandlers(jl.Controls(), auditLogger)
+	srv.RegisterHTTPHandler("GET /admin/logging", get)
+	srv.RegisterHTTPHandler("POST /admin/logging", post)
+}
+
+// makeLoggingHandlers is extracted so tests can exercise the handlers
+// without a full server. The POST handler validates the whole update
+// before applying any of it (400 on a bad level, unknown subsystem, or
+// non-samplable event), emits log_settings_changed with before/after
+// snapshots, and returns the new settings.
+func makeLoggingHandlers(controls *coreruntime.LogControls, auditLogger *coreruntime.AuditLogger) (get, post http.HandlerFunc) {
+	get = func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, loggingResponse(controls.Settings()))
+	}
+	post = func(w http.ResponseWriter, req *http.Request) {
+		var update coreruntime.LogSettingsUpdate
+		dec := json.NewDecoder(req.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&update); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+			return
+		}
+		before := controls.Settings()
+		if err := controls.Apply(update); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		after := controls.Settings()
+		if auditLogger != nil {
+			auditLogger.EmitFromContext(req.Context(), coreruntime.AuditEvent{
+				Event: coreruntime.AuditLogSettingsChanged,
+				Fields: map[string]any{
+					"before": before,
+					"after":  after,
+					"actor":  delegatedSubject(req.Context()),
+				},
+			})
+		}
+		writeJSON(w, http.StatusOK, loggingResponse(after))
+	}
+	return get, post
+}
+
+// loggingResponse adds the accepted subsystem names to a settings
+// snapshot so clients can discover them.
+func loggingResponse(s coreruntime.LogSettings) map[string]any {
+	return map[string]any{
+		"level":      s.Level,
+		"subsystems": s.Subsystems,
+		"sampling":   s.Sampling,
+		"available":  coreruntime.LogSubsystems(),
+	}
+}
@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func TestLoggingHandlers_ApplyAndAudit(t *testing.T) {
+	controls := coreruntime.NewLogControls(false)
+	var audit bytes.Buffer
+	get, post := makeLoggingHandlers(controls, coreruntime.NewAuditLogger(&audit))
+
+	body := `{"subsystems":{"egress":"debug"},"sampling":{"egress_allowed":100}}`
+	rec := httptest.NewRecorder()
+	post(rec, httptest.NewRequest(http.MethodPost, "/admin/logging", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if !controls.Enabled(coreruntime.LogSubsystemEgress, coreruntime.LogLevelDebug) {
+		t.Error("egress debug not enabled after POST")
+	}
+
+	events := undoAuditEvents(t, &audit, coreruntime.AuditLogSettingsChanged)
+	if len(events) != 1 {
+		t.Fatalf("got %d log_settings_changed events, want 1", len(events))
+	}
+	after, _ := events[0].Fields["after"].(map[string]any)
+	if subs, _ := after["subsystems"].(map[string]any); subs["egress"] != "debug" {
+		t.Errorf("audit after = %v", after)
+	}
+
+	rec = httptest.NewRecorder()
+	get(rec, httptest.NewRequest(http.MethodGet, "/admin/logging", nil))
+	var got struct {
+		Level     string         `json:"level"`
+		Sampling  map[string]int `json:"sampling"`
+		Available []string       `json:"available"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Level != "info" || got.Sampling["egress_allowed"] != 100 || len(got.Available) != 5 {
+		t.Errorf("GET = %+v", got)
+	}
+}
+
+func TestLoggingHandlers_RejectsBadUpdate(t *testing.T) {
+	controls := coreruntime.NewLogControls(false)
+	var audit bytes.Buffer
+	_, post := makeLoggingHandlers(controls, coreruntime.NewAuditLogger(&audit))
+
+	for _, body := range []string{
+		`{"level":"loud"}`,
+		`{"subsystems":{"kernel":"debug"}}`,
+		`{"sampling":{"tool_exec":10}}`,
+		`{"levels":{"egress":"debug"}}`, // unknown field
+	} {
+		rec := httptest.NewRecorder()
+		post(rec, httptest.NewRequest(http.MethodPost, "/admin/logging", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s status = %d, want 400", body, rec.Code)
+		}
+	}
+	if audit.Len() != 0 {
+		t.Errorf("rejected updates were audited: %s", audit.String())
+	}
+	if s := controls.Settings(); s.Level != "info" || len(s.Subsystems) != 0 {
+		t.Errorf("settings changed by rejected update: %+v", s)
+	}
+}
+
+func TestLogEgressAttempt(t *testing.T) {
+	var buf bytes.Buffer
+	l := coreruntime.NewJSONLogger(&buf, false)
+	egress := l.Subsystem(coreruntime.LogSubsystemEgress)
+
+	logEgressAttempt(egress, "api.example.com", true, "client")
+	if buf.Len() != 0 {
+		t.Fatalf("allowed egress logged at info level: %s", buf.String())
+	}
+	logEgressAttempt(egress, "evil.example.com", false, "proxy")
+	if !strings.Contains(buf.String(), `"msg":"egress blocked"`) || !strings.Contains(buf.String(), `"subsystem":"egress"`) {
+		t.Errorf("blocked line = %s", buf.String())
+	}
+}
@@ -198,6 +198,9 @@ func makeMCPConsentHandler(engine *authgate.Engine, auditLogger *coreruntime.Aud
 			Granted *bool `json:"granted"`
 		}
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
 			return
 		}
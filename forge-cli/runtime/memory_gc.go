@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/initializ/forge/forge-core/memory"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/scheduler"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// MemoryDir resolves the long-term memory directory: memory.memory_dir,
+// or .forge/memory under workDir.
+func MemoryDir(cfg types.MemoryConfig, workDir string) string {
+	if cfg.MemoryDir != "" {
+		return cfg.MemoryDir
+	}
+	return filepath.Join(workDir, ".forge", "memory")
+}
+
+// MemoryRetentionPolicy converts memory.retention to the policy the
+// memory Manager applies during GC.
+func MemoryRetentionPolicy(cfg types.MemoryConfig) memory.RetentionPolicy {
+	return memory.RetentionPolicy{
+		HalfLife:     time.Duration(cfg.Retention.HalfLifeDays) * 24 * time.Hour,
+		ArchiveBelow: cfg.Retention.ArchiveBelow,
+		MaxNotes:     cfg.Retention.MaxNotes,
+	}
+}
+
+// startMemoryGC runs memory GC on memory.retention.gc_schedule until ctx
+// ends, over the agent's memory and each tenant's. Each replica keeps
+// its own memory directory, so every replica collects its own; there is
+// no leader gate.
+func (r *Runner) startMemoryGC(ctx context.Context, mgr *memory.Manager, auditLogger *coreruntime.AuditLogger) {
+	expr := r.cfg.Config.Memory.Retention.GCSchedule
+	if expr == "" {
+		return
+	}
+	sched, err := scheduler.Parse(expr)
+	if err != nil {
+		r.logger.Warn("invalid memory.retention.gc_schedule, scheduled memory gc disabled", map[string]any{
+			"gc_schedule": expr, "error": err.Error(),
+		})
+		return
+	}
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(sched.Next(time.Now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			r.collectMemory(ctx, mgr, "", auditLogger)
+			for _, t := range r.cfg.Config.Tenants {
+				tm, err := mgr.ForTenant(t.ID)
+				if err != nil {
+					r.logger.Warn("scheduled memory gc failed", map[string]any{"tenant": t.ID, "error": err.Error()})
+					continue
+				}
+				r.collectMemory(ctx, tm, t.ID, auditLogger)
+			}
+		}
+	}()
+	r.logger.Info("scheduled memory gc enabled", map[string]any{"gc_schedule": expr})
+}
+
+// collectMemory runs one memory GC pass over mgr, the memory of tenant
+// ("" for the agent's own), and audits what it archived.
+func (r *Runner) collectMemory(ctx context.Context, mgr *memory.Manager, tenant string, auditLogger *coreruntime.AuditLogger) {
+	res, err := mgr.GC(ctx, false)
+	if err != nil {
+		r.logger.Warn("scheduled memory gc failed", map[string]any{"tenant": tenant, "error": err.Error()})
+		return
+	}
+	if len(res.Archived) == 0 {
+		return
+	}
+	auditLogger.EmitFromContext(coreruntime.WithTenant(ctx, tenant), coreruntime.AuditEvent{
+		Event: coreruntime.AuditMemoryGC,
+		Fields: map[string]any{
+			"archived_notes": res.ArchivedNotes,
+			"archived_logs":  res.ArchivedLogs,
+			"archive":        res.Archive,
+		},
+	})
+}
@@ -0,0 +1,231 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/llm/providers"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// errModelSwitchUnsupported is returned by SwitchModel when the agent
+// does not run the LLM loop (mock tools, a subprocess framework).
+var errModelSwitchUnsupported = errors.New("this agent's executor has no LLM client to switch")
+
+// errModelSwitchInvalid wraps a model switch request refused before
+// anything was built, so the endpoint can answer 400 rather than 502.
+var errModelSwitchInvalid = errors.New("invalid model switch")
+
+// SwitchModel swaps the executor's LLM client for the provider, model
+// and fallbacks req names, without restarting. It is the runtime side
+// of POST /admin/model and the model_switch tool.
+//
+// The switched config must pass the platform policy, as at startup, and
+// the new client must build; otherwise the previous model keeps
+// serving. The egress allowlist is re-resolved against the switched
+// config, as Reload does. A running task finishes its current LLM call
+// on the old model. The switch lasts until the next restart or config
+// reload, and every attempt emits a model_switched audit event.
+func (r *Runner) SwitchModel(ctx context.Context, req builtins.ModelSwitchRequest, source string) (string, error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	rt := r.reload
+	if rt == nil {
+		return "", ErrNotServing
+	}
+	if rt.client == nil || r.modelConfig == nil {
+		return "", errModelSwitchUnsupported
+	}
+	before := r.modelConfig.Provider + "/" + r.modelConfig.Client.Model
+	fields := map[string]any{
+		"source":  source,
+		"before":  before,
+		"request": req,
+	}
+	if req.Reason != "" {
+		fields["reason"] = req.Reason
+	}
+	if source == "api" {
+		fields["actor"] = delegatedSubject(ctx)
+	}
+
+	after, err := r.switchModel(rt, req)
+	if err != nil {
+		fields["outcome"] = "rejected"
+		fields["error"] = err.Error()
+		rt.auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{Event: coreruntime.AuditModelSwitched, Fields: fields})
+		r.logger.Warn("model switch rejected", fields)
+		return "", err
+	}
+	fields["outcome"] = "switched"
+	fields["after"] = after
+	rt.auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{Event: coreruntime.AuditModelSwitched, Fields: fields})
+	r.logger.Info("model switched", fields)
+	return after, nil
+}
+
+// switchModel does SwitchModel's work under reloadMu.
+func (r *Runner) switchModel(rt *reloadTargets, req builtins.ModelSwitchRequest) (string, error) {
+	cfg, err := switchedModelConfig(rt.cfg, r.modelConfig, req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errModelSwitchInvalid, err)
+	}
+	envVars, err := r.loadEnvVars()
+	if err != nil {
+		return "", err
+	}
+	// The request names the model outright, so the env and --model /
+	// --provider overrides that pick it at startup must not win over it.
+	delete(envVars, "MODEL_NAME")
+	delete(envVars, "FORGE_MODEL_PROVIDER")
+	if req.Fallbacks != nil {
+		delete(envVars, "FORGE_MODEL_FALLBACKS")
+	}
+
+	mc := coreruntime.ResolveModelConfig(cfg, envVars, "")
+	if mc == nil {
+		return "", fmt.Errorf("%w: no model provider configured", errModelSwitchInvalid)
+	}
+	// Name the provider's default model, if that is what resolved, so
+	// the policy's forbidden_models sees it.
+	cfg.Model.Name = mc.Client.Model
+
+	platformLayers, err := security.LoadAllPolicyLayers()
+	if err != nil {
+		return "", fmt.Errorf("loading platform policy layers: %w", err)
+	}
+	if violations := security.EnforcePolicy(cfg, platformLayers); len(violations) > 0 {
+		return "", fmt.Errorf("%w: %s", errModelSwitchInvalid, security.FormatViolations(violations))
+	}
+
+	// Resolve egress before swapping so a failure leaves both as they
+	// were; apply it only once the client has built.
+	var egressCfg *security.EgressConfig
+	if len(rt.matchers) > 0 {
+		if egressCfg, err = r.resolveEgress(cfg, envVars, platformLayers); err != nil {
+			return "", fmt.Errorf("resolving egress for the new model: %w", err)
+		}
+	}
+	if err := r.swapModel(rt, mc); err != nil {
+		return "", err
+	}
+	if egressCfg != nil {
+		for _, m := range rt.matchers {
+			m.Update(egressCfg.Mode, egressCfg.AllDomains)
+		}
+	}
+	rt.cfg = cfg
+	return mc.Provider + "/" + mc.Client.Model, nil
+}
+
+// switchedModelConfig returns a copy of cfg serving the model req asks
+// for. Fields req leaves empty keep the model serving now (cur), which
+// may differ from cfg when env vars or flags picked it. A provider
+// change drops the old provider's endpoint settings (base URL, auth
+// scheme, region, header name) and, without a model, takes the new
+// provider's default model.
+func switchedModelConfig(cfg *types.ForgeConfig, cur *coreruntime.ModelConfig, req builtins.ModelSwitchRequest) (*types.ForgeConfig, error) {
+	if req.Provider == "" && req.Model == "" && req.Fallbacks == nil {
+		return nil, errors.New("provider, model or fallbacks is required")
+	}
+	out := *cfg
+	m := cfg.Model
+	m.Provider = cur.Provider
+	m.Name = cur.Client.Model
+	if req.Provider != "" && !providers.IsSupported(req.Provider) {
+		return nil, fmt.Errorf("unknown LLM provider %q", req.Provider)
+	}
+	if req.Provider != "" && req.Provider != cur.Provider {
+		m.Provider = req.Provider
+		m.Name = ""
+		m.BaseURL = ""
+		m.AuthScheme = ""
+		m.AWSRegion = ""
+		m.AuthHeaderName = ""
+	}
+	if req.Model != "" {
+		m.Name = req.Model
+	}
+	if req.Fallbacks != nil {
+		m.Fallbacks = make([]types.ModelFallback, 0, len(req.Fallbacks))
+		for i, fb := range req.Fallbacks {
+			if !providers.IsSupported(fb.Provider) {
+				return nil, fmt.Errorf("fallbacks[%d]: unknown LLM provider %q", i, fb.Provider)
+			}
+			m.Fallbacks = append(m.Fallbacks, types.ModelFallback{Provider: fb.Provider, Name: fb.Model})
+		}
+	} else {
+		m.Fallbacks = slices.Clone(m.Fallbacks)
+	}
+	out.Model = m
+	return &out, nil
+}
+
+// CurrentModel returns the provider/model serving now, or "" before Run
+// has resolved one.
+func (r *Runner) CurrentModel() string {
+	mc, _ := r.currentModel()
+	if mc == nil {
+		return ""
+	}
+	return mc.Provider + "/" + mc.Client.Model
+}
+
+// registerModelSwitchTool registers the model_switch admin tool when
+// forge.yaml lists it under tools. It is never on by default: an agent
+// that can change its own model should be one an operator chose to
+// give that power.
+func (r *Runner) registerModelSwitchTool(reg *tools.Registry) {
+	if !slices.ContainsFunc(r.cfg.Config.Tools, func(t types.ToolRef) bool { return t.Name == "model_switch" }) {
+		return
+	}
+	if err := reg.Register(builtins.NewModelSwitchTool(r)); err != nil {
+		r.logger.Warn("failed to register model_switch tool", map[string]any{"error": err.Error()})
+	}
+}
+
+// registerModelSwitchEndpoint wires POST /admin/model. It sits behind
+// the server's auth middleware like every other non-public route.
+func (r *Runner) registerModelSwitchEndpoint(srv *server.Server) {
+	srv.RegisterHTTPHandler("POST /admin/model", makeModelSwitchHandler(r))
+}
+
+// makeModelSwitchHandler is extracted so tests can exercise the handler
+// without a full server. 400 for a request that fails validation or
+// policy, 409 when the executor has no LLM client, 502 when the new
+// client cannot be built.
+func makeModelSwitchHandler(s builtins.ModelSwitcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var body builtins.ModelSwitchRequest
+		dec := json.NewDecoder(req.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&body); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+			return
+		}
+		before := s.CurrentModel()
+		after, err := s.SwitchModel(req.Context(), body, "api")
+		switch {
+		case err == nil:
+			writeJSON(w, http.StatusOK, map[string]string{"status": "switched", "before": before, "model": after})
+		case errors.Is(err, errModelSwitchInvalid):
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, errModelSwitchUnsupported), errors.Is(err, ErrNotServing):
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		}
+	}
+}
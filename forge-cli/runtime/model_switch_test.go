@@ -0,0 +1,150 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// newModelSwitchRunner extends newReloadRunner with an LLM loop serving
+// openai/gpt-4o-mini.
+func newModelSwitchRunner(t *testing.T) (*Runner, *reloadTargets, *bytes.Buffer) {
+	t.Helper()
+	r, rt, buf := newReloadRunner(t)
+	// SwitchModel re-resolves credentials from the env file.
+	r.cfg.EnvFilePath = filepath.Join(r.cfg.WorkDir, ".env")
+	if err := os.WriteFile(r.cfg.EnvFilePath, []byte("OPENAI_API_KEY=sk-test\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rt.cfg = &types.ForgeConfig{
+		AgentID: "test-agent",
+		Model: types.ModelRef{
+			Provider: "openai",
+			Name:     "gpt-4o-mini",
+			BaseURL:  "https://gateway.example.com/v1",
+		},
+		Egress: types.EgressRef{Profile: "standard", Mode: "allowlist"},
+	}
+	mc := coreruntime.ResolveModelConfig(rt.cfg, map[string]string{"OPENAI_API_KEY": "sk-test"}, "")
+	client, err := r.buildLLMClient(mc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.client = newReloadableClient(client)
+	rt.executor = coreruntime.NewLLMExecutor(coreruntime.LLMExecutorConfig{Client: rt.client})
+	r.modelConfig = mc
+	return r, rt, buf
+}
+
+func TestSwitchModel_SwapsClientAndAudits(t *testing.T) {
+	r, rt, buf := newModelSwitchRunner(t)
+
+	got, err := r.SwitchModel(context.Background(), builtins.ModelSwitchRequest{
+		Provider: "anthropic",
+		Reason:   "openai incident",
+	}, "api")
+	if err != nil {
+		t.Fatalf("SwitchModel: %v", err)
+	}
+	if !strings.HasPrefix(got, "anthropic/") || r.CurrentModel() != got {
+		t.Errorf("switched to %q, CurrentModel = %q", got, r.CurrentModel())
+	}
+	// The gateway URL belonged to the old provider.
+	if rt.cfg.Model.BaseURL != "" || rt.cfg.Model.Provider != "anthropic" {
+		t.Errorf("switched model config = %+v", rt.cfg.Model)
+	}
+
+	events := undoAuditEvents(t, buf, coreruntime.AuditModelSwitched)
+	if len(events) != 1 {
+		t.Fatalf("got %d model_switched events, want 1", len(events))
+	}
+	f := events[0].Fields
+	if f["outcome"] != "switched" || f["before"] != "openai/gpt-4o-mini" || f["after"] != got || f["reason"] != "openai incident" {
+		t.Errorf("model_switched fields = %+v", f)
+	}
+}
+
+func TestSwitchModel_PolicyViolationKeepsModel(t *testing.T) {
+	r, _, buf := newModelSwitchRunner(t)
+	policy := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(policy, []byte("forbidden_models:\n  - provider: anthropic\n    name: claude-opus-4\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FORGE_PLATFORM_POLICY", policy)
+
+	if _, err := r.SwitchModel(context.Background(), builtins.ModelSwitchRequest{Provider: "anthropic", Model: "claude-opus-4"}, "api"); err == nil {
+		t.Fatal("SwitchModel accepted a model the platform policy denies")
+	}
+	if got := r.CurrentModel(); got != "openai/gpt-4o-mini" {
+		t.Errorf("CurrentModel = %q after a rejected switch", got)
+	}
+	if events := undoAuditEvents(t, buf, coreruntime.AuditModelSwitched); len(events) != 1 || events[0].Fields["outcome"] != "rejected" {
+		t.Errorf("model_switched events = %+v", events)
+	}
+}
+
+func TestSwitchedModelConfig(t *testing.T) {
+	cfg := &types.ForgeConfig{Model: types.ModelRef{
+		Provider:  "openai",
+		Name:      "gpt-4o",
+		Fallbacks: []types.ModelFallback{{Provider: "anthropic"}},
+	}}
+	// MODEL_NAME picked a different model at startup; an empty request
+	// field keeps what is serving, not what forge.yaml says.
+	cur := &coreruntime.ModelConfig{Provider: "openai", Client: llm.ClientConfig{Model: "gpt-4o-mini"}}
+
+	out, err := switchedModelConfig(cfg, cur, builtins.ModelSwitchRequest{Fallbacks: []builtins.ModelSwitchFallback{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Model.Name != "gpt-4o-mini" || len(out.Model.Fallbacks) != 0 || len(cfg.Model.Fallbacks) != 1 {
+		t.Errorf("switched = %+v, original = %+v", out.Model, cfg.Model)
+	}
+
+	for _, req := range []builtins.ModelSwitchRequest{
+		{},
+		{Provider: "nope"},
+		{Fallbacks: []builtins.ModelSwitchFallback{{Model: "x"}}},
+	} {
+		if _, err := switchedModelConfig(cfg, cur, req); err == nil {
+			t.Errorf("request %+v: expected an error", req)
+		}
+	}
+}
+
+func TestModelSwitchHandler(t *testing.T) {
+	r, _, _ := newModelSwitchRunner(t)
+	h := makeModelSwitchHandler(r)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodPost, "/admin/model", strings.NewReader(body)))
+		return w
+	}
+
+	if w := post(`{"model":"gpt-4o"}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"model":"openai/gpt-4o"`) {
+		t.Errorf("switch: %d %s", w.Code, w.Body.String())
+	}
+	if w := post(`{"provider":"nope"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown provider: %d", w.Code)
+	}
+	if w := post(`{"modle":"gpt-4o"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown field: %d", w.Code)
+	}
+
+	idle, _, _ := newReloadRunner(t)
+	w := httptest.NewRecorder()
+	makeModelSwitchHandler(idle)(w, httptest.NewRequest(http.MethodPost, "/admin/model", strings.NewReader(`{"model":"gpt-4o"}`)))
+	if w.Code != http.StatusConflict {
+		t.Errorf("no LLM loop: %d", w.Code)
+	}
+}
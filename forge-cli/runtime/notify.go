@@ -0,0 +1,262 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Notifications: the notify tool and POST /notify let the agent, or a
+// monitoring system calling it, message a channel target nobody asked
+// from. Targets are declared in forge.yaml so neither can reach an
+// arbitrary chat.
+
+// NotifySender delivers a proactive message to a channel adapter's
+// chat. `forge run --with` wires it to the adapter's SendResponse.
+type NotifySender func(ctx context.Context, channel, target string, msg *a2a.Message) error
+
+// SetNotifySender sets the callback notifications are delivered
+// through. Must be called before Run(); without it notifications fail
+// as unavailable.
+func (r *Runner) SetNotifySender(fn NotifySender) {
+	r.notifySender = fn
+}
+
+// Default notify rate limit, per target.
+const defaultNotifyPerMinute = 10
+
+// defaultNotifyTemplate renders a notification without a target template.
+const defaultNotifyTemplate = `{{if eq .Severity "critical"}}🚨 {{else if eq .Severity "warning"}}⚠️ {{end}}` +
+	`{{if .Title}}**{{.Title}}**
+{{end}}{{.Message}}{{range $k, $v := .Fields}}
+• {{$k}}: {{$v}}{{end}}`
+
+var errNotifyUnknownTarget = errors.New("unknown notify target")
+
+// ErrNotifyUnavailable reports that a notify target's channel adapter
+// is not running, so the message could not be sent. NotifySenders wrap
+// it for a channel they do not serve.
+var ErrNotifyUnavailable = errors.New("notify target's channel adapter is not running")
+
+// notifyRateLimitedError refuses a notification over its target's rate
+// limit.
+type notifyRateLimitedError struct {
+	target     string
+	retryAfter time.Duration
+}
+
+func (e *notifyRateLimitedError) Error() string {
+	return fmt.Sprintf("notify target %q is rate limited; retry in %s", e.target, e.retryAfter)
+}
+
+// notifyTarget is a configured target with its parsed template.
+type notifyTarget struct {
+	types.NotifyTarget
+	tmpl    *template.Template
+	limiter *rate.Limiter
+}
+
+// notifier implements builtins.Notifier for the runner.
+type notifier struct {
+	runner  *Runner
+	agentID string
+	audit   *coreruntime.AuditLogger
+	mu      sync.Mutex // guards limiter reservations
+	targets map[string]*notifyTarget
+}
+
+// newNotifier builds the notifier for cfg, or returns nil when no
+// targets are declared.
+func newNotifier(r *Runner, cfg types.NotifyConfig, agentID string, audit *coreruntime.AuditLogger) (*notifier, error) {
+	if len(cfg.Targets) == 0 {
+		return nil, nil
+	}
+	perMinute, burst := cfg.RateLimit.PerMinute, cfg.RateLimit.Burst
+	if perMinute == 0 {
+		perMinute = defaultNotifyPerMinute
+	}
+	if burst == 0 {
+		burst = perMinute
+	}
+	n := &notifier{runner: r, agentID: agentID, audit: audit, targets: map[string]*notifyTarget{}}
+	for name, t := range cfg.Targets {
+		src := t.Template
+		if src == "" {
+			src = defaultNotifyTemplate
+		}
+		tmpl, err := template.New(name).Option("missingkey=zero").Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("notify.targets.%s.template: %w", name, err)
+		}
+		n.targets[name] = &notifyTarget{
+			NotifyTarget: t,
+			tmpl:         tmpl,
+			limiter:      rate.NewLimiter(rate.Limit(float64(perMinute)/60), burst),
+		}
+	}
+	return n, nil
+}
+
+// Targets returns the configured target names.
+func (n *notifier) Targets() []string {
+	names := make([]string, 0, len(n.targets))
+	for name := range n.targets {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Notify renders req with its target's template and sends it, within
+// the target's rate limit. Every attempt that names a known target is
+// audited.
+func (n *notifier) Notify(ctx context.Context, req builtins.NotifyRequest, source string) error {
+	t, ok := n.targets[req.Target]
+	if !ok {
+		return fmt.Errorf("%w %q", errNotifyUnknownTarget, req.Target)
+	}
+	text, err := n.render(t, req)
+	if err != nil {
+		n.emit(ctx, req.Target, t.Channel, source, "failed", err)
+		return err
+	}
+
+	now := time.Now()
+	n.mu.Lock()
+	res := t.limiter.ReserveN(now, 1)
+	wait := res.DelayFrom(now)
+	if wait > 0 {
+		res.CancelAt(now)
+	}
+	n.mu.Unlock()
+	if wait > 0 {
+		n.emit(ctx, req.Target, t.Channel, source, "rate_limited", nil)
+		return &notifyRateLimitedError{target: req.Target, retryAfter: time.Duration(math.Ceil(wait.Seconds())) * time.Second}
+	}
+
+	err = ErrNotifyUnavailable
+	if send := n.runner.notifySender; send != nil {
+		err = send(ctx, t.Channel, t.Target, &a2a.Message{
+			Role:  a2a.MessageRoleAgent,
+			Parts: []a2a.Part{a2a.NewTextPart(text)},
+		})
+	}
+	if err != nil {
+		n.emit(ctx, req.Target, t.Channel, source, "failed", err)
+		return fmt.Errorf("sending notification to %q: %w", req.Target, err)
+	}
+	n.emit(ctx, req.Target, t.Channel, source, "sent", nil)
+	return nil
+}
+
+func (n *notifier) render(t *notifyTarget, req builtins.NotifyRequest) (string, error) {
+	var buf bytes.Buffer
+	err := t.tmpl.Execute(&buf, map[string]any{
+		"Title":    req.Title,
+		"Message":  req.Message,
+		"Severity": req.Severity,
+		"Fields":   req.Fields,
+		"Target":   req.Target,
+		"Agent":    n.agentID,
+		"Time":     time.Now().UTC(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering notify template for %q: %w", req.Target, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func (n *notifier) emit(ctx context.Context, target, channel, source, outcome string, err error) {
+	if n.audit == nil {
+		return
+	}
+	fields := map[string]any{
+		"target":  target,
+		"channel": channel,
+		"source":  source,
+		"outcome": outcome,
+	}
+	if source == "api" {
+		fields["actor"] = delegatedSubject(ctx)
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	n.audit.EmitFromContext(ctx, coreruntime.AuditEvent{Event: coreruntime.AuditNotify, Fields: fields})
+}
+
+// registerNotifyTool registers the notify tool when targets are
+// declared.
+func (r *Runner) registerNotifyTool(reg *tools.Registry) {
+	if r.notify == nil {
+		return
+	}
+	if err := reg.Register(builtins.NewNotifyTool(r.notify)); err != nil {
+		r.logger.Warn("failed to register notify tool", map[string]any{"error": err.Error()})
+	}
+}
+
+// registerNotifyEndpoint wires POST /notify when targets are declared.
+// It sits behind the server's auth middleware like every other
+// non-public route.
+func (r *Runner) registerNotifyEndpoint(srv *server.Server) {
+	if r.notify == nil {
+		return
+	}
+	srv.RegisterHTTPHandler("POST /notify", makeNotifyHandler(r.notify))
+}
+
+// makeNotifyHandler is extracted so tests can exercise the handler
+// without a full server. 404 for an unknown target, 429 with
+// Retry-After over the rate limit, 503 when the target's adapter is
+// not running.
+func makeNotifyHandler(n builtins.Notifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var body builtins.NotifyRequest
+		dec := json.NewDecoder(req.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&body); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+			return
+		}
+		if body.Target == "" || body.Message == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "target and message are required"})
+			return
+		}
+		err := n.Notify(req.Context(), body, "api")
+		var limited *notifyRateLimitedError
+		switch {
+		case err == nil:
+			writeJSON(w, http.StatusOK, map[string]string{"status": "sent", "target": body.Target})
+		case errors.Is(err, errNotifyUnknownTarget):
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.As(err, &limited):
+			w.Header().Set("Retry-After", strconv.Itoa(int(limited.retryAfter.Seconds())))
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrNotifyUnavailable):
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		default:
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		}
+	}
+}
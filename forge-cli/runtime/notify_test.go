@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+type sentNotification struct {
+	channel, target, text string
+}
+
+func newTestNotifier(t *testing.T, cfg types.NotifyConfig) (*notifier, *[]sentNotification, *bytes.Buffer) {
+	t.Helper()
+	var sent []sentNotification
+	r := &Runner{}
+	r.SetNotifySender(func(_ context.Context, channel, target string, msg *a2a.Message) error {
+		sent = append(sent, sentNotification{channel, target, msg.Parts[0].Text})
+		return nil
+	})
+	var audit bytes.Buffer
+	n, err := newNotifier(r, cfg, "monitor", coreruntime.NewAuditLogger(&audit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n, &sent, &audit
+}
+
+func TestNotify(t *testing.T) {
+	n, sent, audit := newTestNotifier(t, types.NotifyConfig{Targets: map[string]types.NotifyTarget{
+		"oncall": {Channel: "slack", Target: "C0123"},
+		"ops":    {Channel: "telegram", Target: "42", Template: "[{{.Agent}}] {{.Message}} ({{index .Fields \"host\"}})"},
+	}})
+	ctx := context.Background()
+
+	if err := n.Notify(ctx, builtins.NotifyRequest{Target: "oncall", Title: "Disk full", Message: "/var at 98%", Severity: "critical"}, "tool"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := n.Notify(ctx, builtins.NotifyRequest{Target: "ops", Message: "restarted", Fields: map[string]string{"host": "db-1"}}, "api"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	want := []sentNotification{
+		{"slack", "C0123", "🚨 **Disk full**\n/var at 98%"},
+		{"telegram", "42", "[monitor] restarted (db-1)"},
+	}
+	if len(*sent) != 2 || (*sent)[0] != want[0] || (*sent)[1] != want[1] {
+		t.Errorf("sent = %+v", *sent)
+	}
+	if err := n.Notify(ctx, builtins.NotifyRequest{Target: "nobody", Message: "x"}, "tool"); err == nil {
+		t.Error("expected an error for an unknown target")
+	}
+	if got := strings.Count(audit.String(), `"event":"notify"`); got != 2 {
+		t.Errorf("audited %d notify events, want 2:\n%s", got, audit.String())
+	}
+	if !strings.Contains(audit.String(), `"source":"api"`) || !strings.Contains(audit.String(), `"outcome":"sent"`) {
+		t.Errorf("audit = %s", audit.String())
+	}
+}
+
+func TestNotify_RateLimit(t *testing.T) {
+	n, sent, audit := newTestNotifier(t, types.NotifyConfig{
+		Targets:   map[string]types.NotifyTarget{"oncall": {Channel: "slack", Target: "C0123"}},
+		RateLimit: types.ChannelRateLimitYAML{PerMinute: 1},
+	})
+	ctx := context.Background()
+	if err := n.Notify(ctx, builtins.NotifyRequest{Target: "oncall", Message: "first"}, "tool"); err != nil {
+		t.Fatal(err)
+	}
+	err := n.Notify(ctx, builtins.NotifyRequest{Target: "oncall", Message: "second"}, "tool")
+	limited, ok := err.(*notifyRateLimitedError)
+	if !ok || limited.retryAfter <= 0 {
+		t.Fatalf("err = %v, want a rate limit", err)
+	}
+	if len(*sent) != 1 || !strings.Contains(audit.String(), `"outcome":"rate_limited"`) {
+		t.Errorf("sent %d, audit = %s", len(*sent), audit.String())
+	}
+}
+
+func TestNotifyHandler(t *testing.T) {
+	n, _, _ := newTestNotifier(t, types.NotifyConfig{
+		Targets:   map[string]types.NotifyTarget{"oncall": {Channel: "slack", Target: "C0123"}},
+		RateLimit: types.ChannelRateLimitYAML{PerMinute: 1},
+	})
+	h := makeNotifyHandler(n)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(body)))
+		return w
+	}
+
+	if w := post(`{"target":"oncall","message":"deploy finished"}`); w.Code != http.StatusOK {
+		t.Errorf("send: %d %s", w.Code, w.Body.String())
+	}
+	if w := post(`{"target":"oncall","message":"again"}`); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("over limit: %d Retry-After=%q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := post(`{"target":"nobody","message":"x"}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown target: %d", w.Code)
+	}
+	if w := post(`{"target":"oncall"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("no message: %d", w.Code)
+	}
+
+	// Without a running adapter the message cannot go anywhere.
+	idle, err := newNotifier(&Runner{}, types.NotifyConfig{Targets: map[string]types.NotifyTarget{"oncall": {Channel: "slack", Target: "C0123"}}}, "monitor", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	makeNotifyHandler(idle)(w, httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(`{"target":"oncall","message":"x"}`)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("no adapter: %d", w.Code)
+	}
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// ollamaHealthTimeout bounds the startup probe. /api/tags answers
+// immediately when the daemon is up, so this only matters when the
+// host is unreachable.
+const ollamaHealthTimeout = 5 * time.Second
+
+// checkOllama probes every ollama client in mc before the executor is
+// built. The primary model failing the check is fatal: an agent whose
+// only model cannot answer would otherwise start and fail every request
+// with a connection error. An ollama fallback or route failing is
+// logged and left in place (the daemon may come up later).
+func (r *Runner) checkOllama(ctx context.Context, mc *coreruntime.ModelConfig) error {
+	probe := func(cfg llm.ClientConfig) error {
+		pctx, cancel := context.WithTimeout(ctx, ollamaHealthTimeout)
+		defer cancel()
+		return providers.NewOllamaClient(cfg).CheckHealth(pctx)
+	}
+	if mc.Provider == "ollama" {
+		if err := probe(mc.Client); err != nil {
+			return err
+		}
+	}
+	for _, fb := range mc.Fallbacks {
+		if fb.Provider != "ollama" {
+			continue
+		}
+		if err := probe(fb.Client); err != nil {
+			r.logger.Warn("ollama fallback unavailable", map[string]any{
+				"model": fb.Client.Model, "error": err.Error(),
+			})
+		}
+	}
+	for _, rt := range mc.Routes {
+		if rt.Provider != "ollama" {
+			continue
+		}
+		if err := probe(rt.Client); err != nil {
+			r.logger.Warn("ollama route unavailable", map[string]any{
+				"route": rt.ID, "model": rt.Client.Model, "error": err.Error(),
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func TestCheckOllama(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"models":[{"name":"llama3.2:latest"}]}`))
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	r := &Runner{logger: nopLogger{}}
+	ctx := context.Background()
+
+	ok := &coreruntime.ModelConfig{Provider: "ollama", Client: llm.ClientConfig{BaseURL: up.URL, Model: "llama3.2"}}
+	if err := r.checkOllama(ctx, ok); err != nil {
+		t.Errorf("healthy daemon: %v", err)
+	}
+
+	notPulled := &coreruntime.ModelConfig{Provider: "ollama", Client: llm.ClientConfig{BaseURL: up.URL, Model: "qwen3"}}
+	if err := r.checkOllama(ctx, notPulled); err == nil || !strings.Contains(err.Error(), "forge models pull qwen3") {
+		t.Errorf("missing model: %v", err)
+	}
+
+	primaryDown := &coreruntime.ModelConfig{Provider: "ollama", Client: llm.ClientConfig{BaseURL: down.URL, Model: "llama3.2"}}
+	if err := r.checkOllama(ctx, primaryDown); err == nil || !strings.Contains(err.Error(), "ollama serve") {
+		t.Errorf("daemon down: %v", err)
+	}
+
+	// A dead ollama fallback does not block startup.
+	fallbackDown := &coreruntime.ModelConfig{
+		Provider:  "openai",
+		Fallbacks: []coreruntime.FallbackModelConfig{{Provider: "ollama", Client: llm.ClientConfig{BaseURL: down.URL, Model: "llama3.2"}}},
+	}
+	if err := r.checkOllama(ctx, fallbackDown); err != nil {
+		t.Errorf("fallback down should only warn: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/auth"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/security/opa"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// EnvOPAURL overrides security.opa.url.
+const EnvOPAURL = "FORGE_OPA_URL"
+
+// opaState is the runner's OPA decision point. Nil when security.opa
+// configures no policy source; every method is nil-safe.
+type opaState struct {
+	client   *opa.Client
+	points   map[string]bool
+	failOpen bool
+	agentID  string
+	audit    *coreruntime.AuditLogger
+	logger   coreruntime.Logger
+}
+
+// startOPA builds the decision point from security.opa. With
+// policy_path it first starts a local `opa run --server` on a loopback
+// port loaded with the agent's Rego; the returned stop function shuts
+// it down.
+func (r *Runner) startOPA(ctx context.Context, auditLogger *coreruntime.AuditLogger) (*opaState, func(), error) {
+	cfg := r.cfg.Config.Security.OPA
+	if v := os.Getenv(EnvOPAURL); v != "" {
+		cfg.URL, cfg.PolicyPath = v, ""
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, func() {}, nil
+	}
+
+	baseURL, stop := cfg.URL, func() {}
+	if cfg.PolicyPath != "" {
+		policyPath := cfg.PolicyPath
+		if !filepath.IsAbs(policyPath) {
+			policyPath = filepath.Join(r.cfg.WorkDir, policyPath)
+		}
+		var err error
+		baseURL, stop, err = startLocalOPA(ctx, policyPath, r.logger)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	points := map[string]bool{}
+	for _, p := range cfg.Points {
+		points[p] = true
+	}
+	if len(points) == 0 {
+		points = map[string]bool{types.OPAPointTool: true, types.OPAPointEgress: true, types.OPAPointSchedule: true}
+	}
+	o := &opaState{
+		client:   opa.NewClient(baseURL, cfg.Decision, cfg.Timeout),
+		points:   points,
+		failOpen: cfg.FailOpen,
+		agentID:  r.cfg.Config.AgentID,
+		audit:    auditLogger,
+		logger:   r.logger,
+	}
+	r.logger.Info("opa decision point enabled", map[string]any{
+		"endpoint":  o.client.Endpoint(),
+		"local":     cfg.PolicyPath != "",
+		"fail_open": cfg.FailOpen,
+	})
+	return o, stop, nil
+}
+
+func (o *opaState) enabled(point string) bool {
+	return o != nil && o.points[point]
+}
+
+// decide evaluates in and returns a *opa.DeniedError when the policy
+// denies it — or when evaluation fails and fail_open is off. Denials
+// and failures are audited as opa_decision events.
+func (o *opaState) decide(ctx context.Context, in opa.Input, subject string) error {
+	in.AgentID = o.agentID
+	if in.Identity == nil {
+		in.Identity = opa.IdentityFrom(auth.IdentityFromContext(ctx))
+	}
+	d, err := o.client.Evaluate(ctx, in)
+	if err == nil && d.Allow {
+		return nil
+	}
+
+	subjectField := map[string]string{opa.KindTool: "tool", opa.KindEgress: "domain", opa.KindSchedule: "schedule_id"}[in.Kind]
+	fields := map[string]any{"kind": in.Kind, subjectField: subject}
+	allow := false
+	if err != nil {
+		allow = o.failOpen
+		fields["error"] = err.Error()
+		d.Reason = "policy evaluation failed"
+		o.logger.Warn("opa evaluation failed", map[string]any{"kind": in.Kind, "subject": subject, "fail_open": o.failOpen, "error": err.Error()})
+	} else if d.Reason != "" {
+		fields["reason"] = d.Reason
+	}
+	fields["allow"] = allow
+	o.audit.EmitFromContext(ctx, coreruntime.AuditEvent{
+		Event:         coreruntime.AuditOPADecision,
+		CorrelationID: in.CorrelationID,
+		TaskID:        in.TaskID,
+		Fields:        fields,
+	})
+	if allow {
+		return nil
+	}
+	return &opa.DeniedError{Kind: in.Kind, Subject: subject, Reason: d.Reason}
+}
+
+// registerOPAHook puts every tool call — and, as a schedule decision,
+// every schedule_set call — to OPA on BeforeToolExec. No-op when OPA
+// is off or neither point is enabled.
+func (r *Runner) registerOPAHook(hooks *coreruntime.HookRegistry) {
+	o := r.opa
+	if !o.enabled(types.OPAPointTool) && !o.enabled(types.OPAPointSchedule) {
+		return
+	}
+	hooks.Register(coreruntime.BeforeToolExec, func(ctx context.Context, hctx *coreruntime.HookContext) error {
+		in := opa.Input{TaskID: hctx.TaskID, CorrelationID: hctx.CorrelationID}
+		if hctx.ToolName == "schedule_set" && o.enabled(types.OPAPointSchedule) {
+			var s opa.ScheduleInput
+			_ = json.Unmarshal([]byte(hctx.ToolInput), &s)
+			in.Kind, in.Schedule = opa.KindSchedule, &s
+			return o.decide(ctx, in, s.ID)
+		}
+		if !o.enabled(types.OPAPointTool) {
+			return nil
+		}
+		in.Kind = opa.KindTool
+		in.Tool = &opa.ToolInput{Name: hctx.ToolName, Args: opa.SummarizeArgs(hctx.ToolInput)}
+		return o.decide(ctx, in, hctx.ToolName)
+	})
+}
+
+// authorizeEgress is the EgressEnforcer.Authorize hook for in-process
+// HTTP clients.
+func (o *opaState) authorizeEgress(ctx context.Context, domain string) error {
+	return o.decide(ctx, opa.Input{
+		Kind:          opa.KindEgress,
+		Domain:        domain,
+		TaskID:        coreruntime.TaskIDFromContext(ctx),
+		CorrelationID: coreruntime.CorrelationIDFromContext(ctx),
+	}, domain)
+}
+
+// authorizeProxyEgress is the EgressProxy.Authorize hook for skill
+// subprocesses. The proxy knows the task only from the credentials the
+// subprocess replays, and never the caller's identity.
+func (o *opaState) authorizeProxyEgress(ctx context.Context, a security.EgressAttempt) error {
+	return o.decide(ctx, opa.Input{
+		Kind:          opa.KindEgress,
+		Domain:        a.Domain,
+		TaskID:        a.TaskID,
+		CorrelationID: a.CorrelationID,
+	}, a.Domain)
+}
+
+// startLocalOPA runs `opa run --server` on a free loopback port with
+// the Rego under policyPath and waits until it reports healthy.
+func startLocalOPA(ctx context.Context, policyPath string, logger coreruntime.Logger) (string, func(), error) {
+	bin, err := exec.LookPath("opa")
+	if err != nil {
+		return "", nil, fmt.Errorf("security.opa.policy_path requires the opa binary on PATH: %w", err)
+	}
+	port, err := findFreePort()
+	if err != nil {
+		return "", nil, fmt.Errorf("finding free port for opa: %w", err)
+	}
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	cmd := exec.Command(bin, "run", "--server", "--addr", addr, "--log-level", "error", policyPath)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", nil, fmt.Errorf("opa stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return "", nil, fmt.Errorf("starting opa: %w", err)
+	}
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			logger.Warn("opa", map[string]any{"stderr": scanner.Text()})
+		}
+	}()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			_ = cmd.Process.Kill()
+			<-exited
+		})
+	}
+
+	baseURL := "http://" + addr
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		select {
+		case err := <-exited:
+			return "", nil, fmt.Errorf("opa exited during startup (check the Rego under %s): %v", policyPath, err)
+		case <-ctx.Done():
+			stop()
+			return "", nil, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+		resp, err := http.Get(baseURL + "/health")
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return baseURL, stop, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return "", nil, fmt.Errorf("timeout waiting for opa on %s", addr)
+		}
+	}
+}
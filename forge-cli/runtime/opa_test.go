@@ -0,0 +1,149 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/security/opa"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// fakeOPA serves the Data API: it records each input and denies
+// cli_execute, schedules firing more often than hourly and
+// blocked.example.com.
+func fakeOPA(t *testing.T, inputs *[]opa.Input) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input opa.Input `json:"input"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*inputs = append(*inputs, body.Input)
+		in := body.Input
+		allow := !(in.Tool != nil && in.Tool.Name == "cli_execute") &&
+			!(in.Schedule != nil && strings.HasPrefix(in.Schedule.Cron, "*/")) &&
+			in.Domain != "blocked.example.com"
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"allow": allow, "reason": "org policy"}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func buildOPARunner(t *testing.T, cfg types.OPAConfig, audit *bytes.Buffer) *Runner {
+	t.Helper()
+	r := &Runner{
+		logger: coreruntime.NewJSONLogger(discardWriter{}, false),
+		cfg: RunnerConfig{Config: &types.ForgeConfig{
+			AgentID:  "opa-agent",
+			Security: types.SecurityConfig{OPA: cfg},
+		}},
+	}
+	o, stop, err := r.startOPA(context.Background(), coreruntime.NewAuditLogger(audit))
+	if err != nil {
+		t.Fatalf("startOPA: %v", err)
+	}
+	t.Cleanup(stop)
+	r.opa = o
+	return r
+}
+
+func TestOPAHook_ToolAndSchedule(t *testing.T) {
+	var inputs []opa.Input
+	srv := fakeOPA(t, &inputs)
+	var audit bytes.Buffer
+	r := buildOPARunner(t, types.OPAConfig{URL: srv.URL}, &audit)
+	hooks := coreruntime.NewHookRegistry()
+	r.registerOPAHook(hooks)
+
+	err := hooks.Fire(context.Background(), coreruntime.BeforeToolExec, &coreruntime.HookContext{
+		ToolName: "cli_execute", ToolInput: `{"binary":"kubectl"}`, TaskID: "t1",
+	})
+	var denied *opa.DeniedError
+	if !errors.As(err, &denied) || denied.Reason != "org policy" {
+		t.Fatalf("cli_execute: expected DeniedError, got %v", err)
+	}
+	if err := hooks.Fire(context.Background(), coreruntime.BeforeToolExec, &coreruntime.HookContext{ToolName: "web_search", ToolInput: `{"query":"forge"}`}); err != nil {
+		t.Fatalf("web_search: %v", err)
+	}
+	err = hooks.Fire(context.Background(), coreruntime.BeforeToolExec, &coreruntime.HookContext{
+		ToolName: "schedule_set", ToolInput: `{"id":"poll","cron":"*/1 * * * *","task":"poll"}`,
+	})
+	if !errors.As(err, &denied) || denied.Kind != opa.KindSchedule || denied.Subject != "poll" {
+		t.Fatalf("schedule_set: expected schedule denial, got %v", err)
+	}
+
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 evaluations, got %d", len(inputs))
+	}
+	if in := inputs[0]; in.Kind != opa.KindTool || in.AgentID != "opa-agent" || in.TaskID != "t1" || in.Tool.Args["binary"] != "kubectl" {
+		t.Errorf("tool input = %+v", in)
+	}
+	if in := inputs[2]; in.Kind != opa.KindSchedule || in.Tool != nil || in.Schedule.Cron != "*/1 * * * *" {
+		t.Errorf("schedule input = %+v", in)
+	}
+	if n := strings.Count(audit.String(), `"event":"opa_decision"`); n != 2 {
+		t.Errorf("expected 2 opa_decision events (the denials), got %d:\n%s", n, audit.String())
+	}
+}
+
+func TestOPA_EgressAuthorize(t *testing.T) {
+	var inputs []opa.Input
+	srv := fakeOPA(t, &inputs)
+	r := buildOPARunner(t, types.OPAConfig{URL: srv.URL, Points: []string{types.OPAPointEgress}}, &bytes.Buffer{})
+
+	hooks := coreruntime.NewHookRegistry()
+	r.registerOPAHook(hooks)
+	if err := hooks.Fire(context.Background(), coreruntime.BeforeToolExec, &coreruntime.HookContext{ToolName: "cli_execute"}); err != nil {
+		t.Fatalf("tool point disabled, yet hook denied: %v", err)
+	}
+
+	if err := r.opa.authorizeEgress(context.Background(), "api.example.com"); err != nil {
+		t.Errorf("api.example.com: %v", err)
+	}
+	if err := r.opa.authorizeProxyEgress(context.Background(), security.EgressAttempt{Domain: "blocked.example.com", TaskID: "t2"}); err == nil {
+		t.Error("blocked.example.com allowed")
+	}
+	if len(inputs) != 2 || inputs[1].Kind != opa.KindEgress || inputs[1].TaskID != "t2" {
+		t.Errorf("inputs = %+v", inputs)
+	}
+}
+
+func TestOPA_FailOpenAndClosed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	var audit bytes.Buffer
+	closed := buildOPARunner(t, types.OPAConfig{URL: srv.URL}, &audit)
+	if err := closed.opa.authorizeEgress(context.Background(), "api.example.com"); err == nil {
+		t.Error("fail-closed: evaluation error allowed the request")
+	}
+	if !strings.Contains(audit.String(), `"allow":false`) || !strings.Contains(audit.String(), "opa returned 500") {
+		t.Errorf("fail-closed audit = %s", audit.String())
+	}
+
+	open := buildOPARunner(t, types.OPAConfig{URL: srv.URL, FailOpen: true}, &bytes.Buffer{})
+	if err := open.opa.authorizeEgress(context.Background(), "api.example.com"); err != nil {
+		t.Errorf("fail-open: %v", err)
+	}
+}
+
+func TestStartOPA_EnvOverridesPolicyPath(t *testing.T) {
+	var inputs []opa.Input
+	srv := fakeOPA(t, &inputs)
+	t.Setenv(EnvOPAURL, srv.URL)
+	// policy_path would need the opa binary; the env URL replaces it.
+	r := buildOPARunner(t, types.OPAConfig{PolicyPath: "policies"}, &bytes.Buffer{})
+	if r.opa == nil || !strings.HasPrefix(r.opa.client.Endpoint(), srv.URL) {
+		t.Fatalf("expected the env URL to be used, got %+v", r.opa)
+	}
+}
@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// SubscribeProgress is the channels.ProgressSubscriber handed to
+// in-process channel adapters — the ones `forge run --with` starts,
+// which have no SSE stream to read progress from. fn receives every
+// tool progress update of taskID, from the event bus, until the
+// returned cancel is called.
+func (r *Runner) SubscribeProgress(taskID string, fn func(channels.ProgressUpdate)) func() {
+	return r.events.Subscribe(func(_ context.Context, e coreruntime.Event) {
+		if e.TaskID != taskID || e.Hook == nil {
+			return
+		}
+		fn(progressUpdate(e))
+	}, coreruntime.TopicToolStart, coreruntime.TopicToolEnd)
+}
+
+// progressUpdate renders a tool event the way registerProgressHooks
+// renders it for SSE clients.
+func progressUpdate(e coreruntime.Event) channels.ProgressUpdate {
+	tool := e.Hook.ToolName
+	if e.Topic == coreruntime.TopicToolStart {
+		return channels.ProgressUpdate{Phase: string(coreruntime.ProgressToolStart), Tool: tool, Message: fmt.Sprintf("Executing %s...", tool)}
+	}
+	msg := fmt.Sprintf("Completed %s", tool)
+	if e.Hook.Error != nil {
+		msg = fmt.Sprintf("Failed %s: %s", tool, e.Hook.Error.Error())
+	}
+	return channels.ProgressUpdate{Phase: string(coreruntime.ProgressToolEnd), Tool: tool, Message: msg}
+}
+
+// progressTask renders a progress event as the working-state task SSE
+// clients receive: the message as a text part, the structured fields as
+// progress_* metadata. Percent and the iteration counters are omitted
+// when the event does not carry them; plan events add the plan.
+func progressTask(taskID string, event coreruntime.ProgressEvent) *a2a.Task {
+	meta := map[string]any{
+		"progress_phase": string(event.Phase),
+		"progress_tool":  event.Tool,
+	}
+	if event.Percent != nil {
+		meta["progress_percent"] = *event.Percent
+	}
+	if event.Iteration > 0 {
+		meta["progress_iteration"] = event.Iteration
+		meta["progress_max_iterations"] = event.MaxIterations
+	}
+	if event.Context != nil {
+		meta["context"] = event.Context
+	}
+	if event.Plan != nil {
+		meta[coreruntime.MetadataKeyPlan] = event.Plan
+	}
+	return &a2a.Task{
+		ID: taskID,
+		Status: a2a.TaskStatus{
+			State: a2a.TaskStateWorking,
+			Message: &a2a.Message{
+				Role:  a2a.MessageRoleAgent,
+				Parts: []a2a.Part{a2a.NewTextPart(event.Message)},
+			},
+		},
+		Metadata: meta,
+	}
+}
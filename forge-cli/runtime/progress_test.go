@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/channels"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func TestSubscribeProgress(t *testing.T) {
+	r := Runner{events: coreruntime.NewEventBus()}
+	got := make(chan channels.ProgressUpdate, 10)
+	cancel := r.SubscribeProgress("task-1", func(u channels.ProgressUpdate) { got <- u })
+
+	ctx := context.Background()
+	r.events.Publish(ctx, coreruntime.Event{Topic: coreruntime.TopicToolStart, TaskID: "task-1", Hook: &coreruntime.HookContext{ToolName: "web_search"}})
+	r.events.Publish(ctx, coreruntime.Event{Topic: coreruntime.TopicToolStart, TaskID: "task-2", Hook: &coreruntime.HookContext{ToolName: "other"}})
+	r.events.Publish(ctx, coreruntime.Event{Topic: coreruntime.TopicToolEnd, TaskID: "task-1", Hook: &coreruntime.HookContext{ToolName: "web_search", Error: errors.New("timeout")}})
+
+	want := []channels.ProgressUpdate{
+		{Phase: "tool_start", Tool: "web_search", Message: "Executing web_search..."},
+		{Phase: "tool_end", Tool: "web_search", Message: "Failed web_search: timeout"},
+	}
+	for i := range want {
+		select {
+		case u := <-got:
+			if u != want[i] {
+				t.Errorf("update %d = %+v, want %+v", i, u, want[i])
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("update %d not delivered", i)
+		}
+	}
+
+	cancel()
+	r.events.Publish(ctx, coreruntime.Event{Topic: coreruntime.TopicToolEnd, TaskID: "task-1", Hook: &coreruntime.HookContext{ToolName: "web_search"}})
+	r.events.Close()
+	if len(got) != 0 {
+		t.Errorf("update delivered after cancel: %+v", <-got)
+	}
+}
@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/initializ/forge/forge-core/prompts"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// loadPrompts parses the agent's prompts/ templates and checks that the
+// system prompt renders with them. Without the directory the built-in
+// template is used.
+func (r *Runner) loadPrompts() error {
+	set, err := prompts.Load(filepath.Join(r.cfg.WorkDir, prompts.DefaultDir))
+	if err != nil {
+		return fmt.Errorf("prompt templates: %w", err)
+	}
+	if err := r.checkPrompts(set); err != nil {
+		return fmt.Errorf("prompt templates: %w", err)
+	}
+	r.prompts = set
+	if set.Version() != prompts.BuiltinTemplate {
+		r.logger.Info("prompt templates loaded", map[string]any{
+			"version": set.Version(), "templates": len(set.Templates()),
+		})
+	}
+	return nil
+}
+
+// checkPrompts checks that the system prompt, and the prompt of every
+// experiment variant, renders with set.
+func (r *Runner) checkPrompts(set *prompts.Set) error {
+	if _, err := r.renderSystemPrompt(set); err != nil {
+		return err
+	}
+	for _, exp := range r.cfg.Config.Experiments {
+		for _, v := range exp.Variants {
+			if v.Prompt == "" {
+				continue
+			}
+			if _, err := r.renderPrompt(set, v.Prompt); err != nil {
+				return fmt.Errorf("experiment %s variant %s: %w", exp.ID, v.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderSystemPrompt renders the system prompt from set.
+func (r *Runner) renderSystemPrompt(set *prompts.Set) (string, error) {
+	return r.renderPrompt(set, "")
+}
+
+// renderPrompt renders the system prompt from set's template name, or
+// from its system template when name is "".
+func (r *Runner) renderPrompt(set *prompts.Set, name string) (string, error) {
+	d := prompts.Data{
+		AgentID:       r.cfg.Config.AgentID,
+		Version:       r.cfg.Config.Version,
+		SkillCatalog:  r.buildSkillCatalog(set),
+		Scheduler:     r.buildSchedulerPrompt(),
+		ChannelFormat: r.buildChannelFormatPrompt(),
+	}
+	if name == "" {
+		return set.Render(d)
+	}
+	return set.RenderTemplate(name, d)
+}
+
+// reloadPrompts re-reads the prompt templates after a file change and
+// gives tasks started from now on the new system prompt. A set that
+// fails to parse or render is logged and the previous one kept.
+func (r *Runner) reloadPrompts(auditLogger *coreruntime.AuditLogger) {
+	set, err := prompts.Load(filepath.Join(r.cfg.WorkDir, prompts.DefaultDir))
+	if err == nil {
+		err = r.checkPrompts(set)
+	}
+	if err != nil {
+		r.logger.Error("prompt templates not reloaded", map[string]any{"error": err.Error()})
+		return
+	}
+
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	if set.Digest() == r.prompts.Digest() {
+		return
+	}
+	r.prompts = set
+	if r.reload != nil && r.reload.executor != nil {
+		r.reload.executor.SetSystemPrompt(r.executorSystemPrompt())
+	}
+	r.logger.Info("prompt templates reloaded", map[string]any{"version": set.Version()})
+	r.emitPromptLoaded(auditLogger)
+}
+
+// emitPromptLoaded writes a prompt_loaded audit event identifying the
+// templates the system prompt is rendered from. Callers hold reloadMu
+// once serving.
+func (r *Runner) emitPromptLoaded(auditLogger *coreruntime.AuditLogger) {
+	if auditLogger == nil {
+		return
+	}
+	fields := r.promptFields()
+	fields["templates"] = r.prompts.Templates()
+	auditLogger.Emit(coreruntime.AuditEvent{
+		Event:  coreruntime.EventPromptLoaded,
+		Fields: fields,
+	})
+}
+
+// promptFields identifies the current system prompt on audit events:
+// its version tag, when it has one, and the templates' digest.
+func (r *Runner) promptFields() map[string]any {
+	fields := map[string]any{"prompt_sha256": r.prompts.Digest()}
+	if v := r.prompts.Version(); v != "" {
+		fields["prompt_version"] = v
+	}
+	return fields
+}
+
+// taskPromptFields is promptFields for a task starting while serving.
+func (r *Runner) taskPromptFields() map[string]any {
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	return r.promptFields()
+}
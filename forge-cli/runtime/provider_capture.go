@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"path/filepath"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// CapturesDir is where provider captures are written under workDir.
+func CapturesDir(workDir string) string {
+	return filepath.Join(workDir, ".forge", "captures")
+}
+
+// initProviderCapture starts capturing provider calls when forge.yaml
+// `observability.captures` enables it. Like the response cache it is
+// set up once and outlives client rebuilds; a capture dir that cannot
+// be created disables capture with a warning.
+func (r *Runner) initProviderCapture() {
+	if r.providerCapture != nil || r.cfg.Config == nil || r.cfg.WorkDir == "" {
+		return
+	}
+	c := r.cfg.Config.Observability.Captures
+	if !c.Enabled {
+		return
+	}
+	percent := c.SamplePercent
+	if percent == 0 {
+		percent = 100
+	}
+	dir := CapturesDir(r.cfg.WorkDir)
+	pc, err := coreruntime.NewProviderCapturer(coreruntime.ProviderCaptureConfig{
+		Dir:           dir,
+		SamplePercent: percent,
+		MaxBytes:      c.MaxBytes,
+	}, func(err error) {
+		r.logger.Warn("provider capture failed", map[string]any{"error": err.Error()})
+	})
+	if err != nil {
+		r.logger.Warn("provider capture disabled", map[string]any{"error": err.Error()})
+		return
+	}
+	r.providerCapture = pc
+	r.logger.Info("provider capture enabled", map[string]any{"dir": dir, "sample_percent": percent})
+}
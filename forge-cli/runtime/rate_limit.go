@@ -58,7 +58,8 @@ func ResolveRateLimit(cfg *types.ForgeConfig, override *RateLimitOverride) *serv
 		override = nil
 	}
 
-	if override == nil && envLayer == nil && yamlLayer == nil {
+	tenants := tenantRateLimits(cfg)
+	if override == nil && envLayer == nil && yamlLayer == nil && tenants == nil {
 		return nil // no overrides at all → server installs its defaults
 	}
 
@@ -77,6 +78,7 @@ func ResolveRateLimit(cfg *types.ForgeConfig, override *RateLimitOverride) *serv
 	applyLayer(out, yamlLayer)
 	applyLayer(out, envLayer)
 	applyLayer(out, override)
+	out.Tenants = tenants
 	return out
 }
 
@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// editMessage redacts or deletes one message of taskID's conversation
+// (tasks/redactMessage and tasks/deleteMessage share this path). It
+// edits the A2A task history, the persisted session and its compaction
+// summary, scrubs a redacted text from the task's status message and
+// artifacts, and emits a message_redacted tombstone. The returned task
+// is the edited task; its status is otherwise unchanged.
+func (r *Runner) editMessage(ctx context.Context, store *a2a.TaskStore, params a2a.EditMessageParams, del bool, auditLogger *coreruntime.AuditLogger) (*a2a.Task, error) {
+	unlock, err := r.cluster.lockTask(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ctx = coreruntime.EnsureCorrelationID(ctx)
+	correlationID := coreruntime.CorrelationIDFromContext(ctx)
+	ctx = coreruntime.WithTaskID(ctx, params.ID)
+	ctx = coreruntime.EnsureSequenceCounter(ctx)
+
+	text := params.Text
+	if del {
+		text = ""
+	}
+
+	task := store.Get(params.ID)
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %s", params.ID)
+	}
+	if task.Status.State == a2a.TaskStateWorking || task.Status.State == a2a.TaskStateSubmitted {
+		return nil, fmt.Errorf("task %s is still running; cancel it before editing its history", params.ID)
+	}
+
+	edited, edit, err := coreruntime.EditTaskHistory(task.History, params.Index, text, del)
+	if err != nil {
+		return nil, err
+	}
+	role := task.History[params.Index].Role
+
+	sessionMessages, scrubbed, summary := -1, 0, coreruntime.SummaryUnchanged
+	res, err := coreruntime.EditSessionMessage(r.sessionStore, params.ID, edit)
+	switch {
+	case errors.Is(err, coreruntime.ErrNoSession):
+		// Persistence disabled (or the session expired): the task
+		// history is the only record, so editing it is the whole job.
+	case err != nil:
+		return nil, err
+	default:
+		sessionMessages, scrubbed, summary = res.Matched, res.Scrubbed, res.Summary
+	}
+
+	task.History = edited
+	if text != "" {
+		if task.Status.Message != nil {
+			msg := *task.Status.Message
+			msg.Parts = coreruntime.ScrubParts(msg.Parts, text)
+			task.Status.Message = &msg
+		}
+		artifacts := make([]a2a.Artifact, len(task.Artifacts))
+		for i, a := range task.Artifacts {
+			a.Parts = coreruntime.ScrubParts(a.Parts, text)
+			artifacts[i] = a
+		}
+		task.Artifacts = artifacts
+	}
+	store.Put(task)
+
+	action, scope := "redact", "message"
+	if del {
+		action = "delete"
+	} else if text != "" {
+		scope = "text"
+	}
+	auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
+		Event:         coreruntime.AuditMessageRedacted,
+		CorrelationID: correlationID,
+		TaskID:        params.ID,
+		Fields: map[string]any{
+			"action":           action,
+			"scope":            scope,
+			"message_index":    params.Index,
+			"role":             string(role),
+			"session_messages": sessionMessages,
+			"scrubbed":         scrubbed,
+			"summary":          summary,
+		},
+	})
+	r.logger.Info("task message "+action, map[string]any{
+		"task_id":          params.ID,
+		"message_index":    params.Index,
+		"session_messages": sessionMessages,
+		"summary":          summary,
+	})
+	return task, nil
+}
@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func TestEditMessage_RedactText(t *testing.T) {
+	r, _, store := newUndoRunner(t)
+	task := store.Get("t1")
+	task.Status.Message = &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Opened #1.")}}
+	store.Put(task)
+	var buf bytes.Buffer
+
+	task, err := r.editMessage(context.Background(), store, a2a.EditMessageParams{ID: "t1", Index: 1, Text: "#1"}, false, coreruntime.NewAuditLogger(&buf))
+	if err != nil {
+		t.Fatalf("editMessage: %v", err)
+	}
+	if got := task.History[1].Parts[0].Text; got != "Opened [redacted]." {
+		t.Errorf("history[1] = %q", got)
+	}
+	if got := task.Status.Message.Parts[0].Text; got != "Opened [redacted]." {
+		t.Errorf("status message = %q", got)
+	}
+
+	saved, err := r.sessionStore.Load("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range saved.Messages {
+		if strings.Contains(msg.Content, "#1") {
+			t.Errorf("session still holds the text: %+v", msg)
+		}
+	}
+
+	events := undoAuditEvents(t, &buf, coreruntime.AuditMessageRedacted)
+	if len(events) != 1 {
+		t.Fatalf("got %d message_redacted events, want 1", len(events))
+	}
+	f := events[0].Fields
+	if f["action"] != "redact" || f["scope"] != "text" || f["role"] != "agent" || f["session_messages"] != float64(1) {
+		t.Errorf("message_redacted fields = %+v", f)
+	}
+	if strings.Contains(buf.String(), "#1") {
+		t.Error("audit stream carries the redacted text")
+	}
+}
+
+func TestEditMessage_Delete(t *testing.T) {
+	r, _, store := newUndoRunner(t)
+	var buf bytes.Buffer
+
+	task, err := r.editMessage(context.Background(), store, a2a.EditMessageParams{ID: "t1", Index: 0}, true, coreruntime.NewAuditLogger(&buf))
+	if err != nil {
+		t.Fatalf("editMessage: %v", err)
+	}
+	if len(task.History) != 1 || task.History[0].Role != a2a.MessageRoleAgent {
+		t.Errorf("history = %+v", task.History)
+	}
+	saved, err := r.sessionStore.Load("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Messages[0].Content == "open an issue" {
+		t.Error("user message still in the session")
+	}
+	if events := undoAuditEvents(t, &buf, coreruntime.AuditMessageRedacted); len(events) != 1 || events[0].Fields["action"] != "delete" {
+		t.Errorf("message_redacted = %+v", events)
+	}
+}
+
+func TestEditMessage_RefusesRunningTask(t *testing.T) {
+	r, _, store := newUndoRunner(t)
+	task := store.Get("t1")
+	task.Status.State = a2a.TaskStateWorking
+	store.Put(task)
+	if _, err := r.editMessage(context.Background(), store, a2a.EditMessageParams{ID: "t1"}, true, coreruntime.NewAuditLogger(&bytes.Buffer{})); err == nil {
+		t.Error("edit of a running task accepted")
+	}
+}
@@ -0,0 +1,262 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/observability"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// ErrNotServing is returned by Reload before Run has started serving.
+var ErrNotServing = errors.New("runner is not serving")
+
+// reloadTargets is what Reload can swap in a running server. Run fills
+// it while wiring the executor and publishes it just before serving.
+type reloadTargets struct {
+	cfg         *types.ForgeConfig        // the forge.yaml last applied; SwitchModel edits a copy
+	client      *reloadableClient         // nil when the executor is not the LLM loop (mock, stub, subprocess)
+	summary     *reloadableClient         // summarizes tool results past memory.high_water_mark; nil when off
+	executor    *coreruntime.LLMExecutor  // attributes calls to the reloaded model
+	guardrails  *reloadableGuardrails     // wraps the checker every hook and handler holds
+	matchers    []*security.DomainMatcher // egress enforcer and proxy allowlists
+	auditLogger *coreruntime.AuditLogger
+	tracingCfg  observability.TracingConfig
+}
+
+// Reload re-applies the model, guardrail policies and egress allowlist
+// from cfg (a freshly loaded forge.yaml) without restarting the server.
+// Open connections are kept: a task already running finishes its
+// current LLM call and tool call as configured before, and makes its
+// next ones under the reloaded configuration.
+//
+// The platform policy layers are re-read and cfg must pass them, as at
+// startup; otherwise nothing changes. Each component is swapped
+// independently, so one that fails to build keeps its previous
+// configuration and is reported in the returned error. Everything else
+// in cfg (skills, tools, channels, auth, memory) still needs a restart,
+// as does an egress mode change that would start or stop the
+// subprocess proxy.
+func (r *Runner) Reload(cfg *types.ForgeConfig) error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	rt := r.reload
+	if rt == nil {
+		return ErrNotServing
+	}
+
+	envVars, err := r.loadEnvVars()
+	if err != nil {
+		return r.reloadFailed(rt, "env", err)
+	}
+	platformLayers, err := security.LoadAllPolicyLayers()
+	if err != nil {
+		return r.reloadFailed(rt, "policy", fmt.Errorf("loading platform policy layers: %w", err))
+	}
+	if violations := security.EnforcePolicy(cfg, platformLayers); len(violations) > 0 {
+		return r.reloadFailed(rt, "policy", fmt.Errorf("%s", security.FormatViolations(violations)))
+	}
+
+	rt.cfg = cfg
+
+	var applied []string
+	failed := map[string]string{}
+	fields := map[string]any{}
+
+	if rt.client != nil {
+		if mc, err := r.reloadModel(rt, cfg, envVars); err != nil {
+			failed["model"] = err.Error()
+		} else {
+			applied = append(applied, "model")
+			fields["model"] = mc.Provider + "/" + mc.Client.Model
+		}
+	}
+
+	guardrails, err := BuildGuardrailChecker(cfg, r.cfg.WorkDir, r.cfg.EnforceGuardrails, r.logger, rt.auditLogger, GuardrailAuditConfigFromEnv(), r.events, rt.tracingCfg)
+	if _, noop := guardrails.(*coreruntime.NoopGuardrailChecker); noop {
+		// Startup serves without guardrails rather than not at all;
+		// a reload must not quietly drop the policies in force.
+		err = errors.New("guardrail engine could not be built; keeping the previous policies")
+	}
+	if err != nil {
+		failed["guardrails"] = err.Error()
+	} else {
+		rt.guardrails.set(guardrails)
+		applied = append(applied, "guardrails")
+	}
+
+	if len(rt.matchers) > 0 {
+		egressCfg, err := r.resolveEgress(cfg, envVars, platformLayers)
+		if err != nil {
+			failed["egress"] = err.Error()
+		} else {
+			for _, m := range rt.matchers {
+				m.Update(egressCfg.Mode, egressCfg.AllDomains)
+			}
+			applied = append(applied, "egress")
+			fields["egress_mode"] = string(egressCfg.Mode)
+			fields["egress_domains"] = len(egressCfg.AllDomains)
+		}
+	}
+
+	fields["applied"] = applied
+	if len(failed) > 0 {
+		fields["failed"] = failed
+	}
+	rt.auditLogger.Emit(coreruntime.AuditEvent{Event: coreruntime.AuditConfigReloaded, Fields: fields})
+	if len(failed) == 0 {
+		r.logger.Info("config reloaded", fields)
+		return nil
+	}
+	r.logger.Warn("config reload incomplete", fields)
+	components := make([]string, 0, len(failed))
+	for c := range failed {
+		components = append(components, c)
+	}
+	sort.Strings(components)
+	errs := make([]error, 0, len(components))
+	for _, c := range components {
+		errs = append(errs, fmt.Errorf("%s: %s", c, failed[c]))
+	}
+	return errors.Join(errs...)
+}
+
+// reloadModel builds a client for cfg's model and swaps it in. The
+// previous client, fallback chain and model config stay in place when
+// the new one cannot be built.
+func (r *Runner) reloadModel(rt *reloadTargets, cfg *types.ForgeConfig, envVars map[string]string) (*coreruntime.ModelConfig, error) {
+	mc := coreruntime.ResolveModelConfig(cfg, envVars, r.cfg.ProviderOverride)
+	if mc == nil {
+		return nil, errors.New("no model provider configured")
+	}
+	if err := r.swapModel(rt, mc); err != nil {
+		return nil, err
+	}
+	return mc, nil
+}
+
+// swapModel builds a client for mc and swaps it in, keeping the previous
+// client, fallback chain and model config when it cannot be built.
+// Callers hold reloadMu.
+func (r *Runner) swapModel(rt *reloadTargets, mc *coreruntime.ModelConfig) error {
+	prevChain := r.fallbackChain
+	r.fallbackChain = nil
+	client, err := r.buildLLMClient(mc)
+	if err != nil {
+		r.fallbackChain = prevChain
+		return err
+	}
+	rt.client.set(client)
+	if rt.summary != nil {
+		rt.summary.set(r.summaryClient(mc, rt.client))
+	}
+	rt.executor.SetModel(mc.Provider, mc.Client.Model)
+	r.modelConfig = mc
+	r.registerCircuitAudit(rt.auditLogger)
+	return nil
+}
+
+// reloadFailed reports a reload that changed nothing.
+func (r *Runner) reloadFailed(rt *reloadTargets, component string, err error) error {
+	fields := map[string]any{"applied": []string{}, "failed": map[string]string{component: err.Error()}}
+	rt.auditLogger.Emit(coreruntime.AuditEvent{Event: coreruntime.AuditConfigReloaded, Fields: fields})
+	r.logger.Error("config reload rejected", fields)
+	return err
+}
+
+// currentModel returns the model config and fallback chain in use;
+// Reload replaces both while the server runs.
+func (r *Runner) currentModel() (*coreruntime.ModelConfig, *llm.FallbackChain) {
+	r.reloadMu.RLock()
+	defer r.reloadMu.RUnlock()
+	return r.modelConfig, r.fallbackChain
+}
+
+// reloadableClient is an llm.Client whose underlying client Reload can
+// replace. Each call goes to the client current when it starts.
+type reloadableClient struct {
+	mu     sync.RWMutex
+	client llm.Client
+}
+
+func newReloadableClient(client llm.Client) *reloadableClient {
+	return &reloadableClient{client: client}
+}
+
+func (c *reloadableClient) get() llm.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+func (c *reloadableClient) set(client llm.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.client = client
+}
+
+func (c *reloadableClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return c.get().Chat(ctx, req)
+}
+
+func (c *reloadableClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	return c.get().ChatStream(ctx, req)
+}
+
+func (c *reloadableClient) ModelID() string {
+	return c.get().ModelID()
+}
+
+// reloadableGuardrails is a GuardrailChecker whose underlying checker
+// Reload can replace.
+type reloadableGuardrails struct {
+	mu      sync.RWMutex
+	checker coreruntime.GuardrailChecker
+}
+
+func newReloadableGuardrails(checker coreruntime.GuardrailChecker) *reloadableGuardrails {
+	return &reloadableGuardrails{checker: checker}
+}
+
+func (g *reloadableGuardrails) get() coreruntime.GuardrailChecker {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.checker
+}
+
+func (g *reloadableGuardrails) set(checker coreruntime.GuardrailChecker) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.checker = checker
+}
+
+func (g *reloadableGuardrails) CheckInbound(ctx context.Context, msg *a2a.Message) (coreruntime.PolicyResult, error) {
+	return g.get().CheckInbound(ctx, msg)
+}
+
+func (g *reloadableGuardrails) CheckOutbound(ctx context.Context, msg *a2a.Message) (coreruntime.PolicyResult, error) {
+	return g.get().CheckOutbound(ctx, msg)
+}
+
+func (g *reloadableGuardrails) CheckToolCall(ctx context.Context, toolName, args string) (string, error) {
+	return g.get().CheckToolCall(ctx, toolName, args)
+}
+
+func (g *reloadableGuardrails) CheckToolOutput(ctx context.Context, toolName, text string) (string, error) {
+	return g.get().CheckToolOutput(ctx, toolName, text)
+}
+
+func (g *reloadableGuardrails) CheckContext(ctx context.Context, content string) (string, error) {
+	return g.get().CheckContext(ctx, content)
+}
+
+func (g *reloadableGuardrails) CheckStream(ctx context.Context, chunk string) (string, error) {
+	return g.get().CheckStream(ctx, chunk)
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// newReloadRunner returns a Runner as Run leaves it once serving, with
+// an egress matcher allowing api.openai.com and a no-op guardrail
+// checker. The platform policy layers are isolated from the host.
+func newReloadRunner(t *testing.T) (*Runner, *reloadTargets, *bytes.Buffer) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("FORGE_SYSTEM_POLICY", filepath.Join(dir, "no-system.yaml"))
+	t.Setenv("FORGE_PLATFORM_POLICY", "")
+
+	r, err := NewRunner(RunnerConfig{Config: &types.ForgeConfig{AgentID: "test-agent"}, WorkDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	r.reload = &reloadTargets{
+		guardrails:  newReloadableGuardrails(&coreruntime.NoopGuardrailChecker{}),
+		matchers:    []*security.DomainMatcher{security.NewDomainMatcher(security.ModeAllowlist, []string{"api.openai.com"})},
+		auditLogger: coreruntime.NewAuditLogger(&buf),
+	}
+	return r, r.reload, &buf
+}
+
+func reloadConfig(domains ...string) *types.ForgeConfig {
+	return &types.ForgeConfig{
+		AgentID: "test-agent",
+		Egress:  types.EgressRef{Profile: "standard", Mode: "allowlist", AllowedDomains: domains},
+	}
+}
+
+func TestReload_SwapsGuardrailsAndEgress(t *testing.T) {
+	r, rt, buf := newReloadRunner(t)
+
+	if err := r.Reload(reloadConfig("api.github.com")); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	m := rt.matchers[0]
+	if !m.IsAllowed("api.github.com") || m.IsAllowed("api.openai.com") {
+		t.Error("egress allowlist not replaced")
+	}
+	if _, noop := rt.guardrails.get().(*coreruntime.NoopGuardrailChecker); noop {
+		t.Error("guardrail checker not replaced")
+	}
+
+	events := undoAuditEvents(t, buf, coreruntime.AuditConfigReloaded)
+	if len(events) != 1 {
+		t.Fatalf("got %d config_reloaded events, want 1", len(events))
+	}
+	f := events[0].Fields
+	if applied, _ := f["applied"].([]any); len(applied) != 2 || f["egress_mode"] != "allowlist" || f["failed"] != nil {
+		t.Errorf("config_reloaded fields = %+v", f)
+	}
+}
+
+func TestReload_PolicyViolationChangesNothing(t *testing.T) {
+	r, rt, buf := newReloadRunner(t)
+	policy := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(policy, []byte("denied_egress_domains:\n  - api.github.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FORGE_PLATFORM_POLICY", policy)
+
+	if err := r.Reload(reloadConfig("api.github.com")); err == nil {
+		t.Fatal("Reload accepted a config the platform policy denies")
+	}
+	if m := rt.matchers[0]; m.IsAllowed("api.github.com") || !m.IsAllowed("api.openai.com") {
+		t.Error("egress allowlist changed by a rejected reload")
+	}
+	events := undoAuditEvents(t, buf, coreruntime.AuditConfigReloaded)
+	if len(events) != 1 || events[0].Fields["failed"] == nil {
+		t.Errorf("config_reloaded = %+v, want one failed event", events)
+	}
+}
+
+func TestReload_NotServing(t *testing.T) {
+	r, err := NewRunner(RunnerConfig{Config: &types.ForgeConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(reloadConfig()); !errors.Is(err, ErrNotServing) {
+		t.Errorf("Reload before Run = %v, want ErrNotServing", err)
+	}
+}
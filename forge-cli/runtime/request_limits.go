@@ -0,0 +1,39 @@
+package runtime
+
+import (
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// ResolveRequestLimits overlays cfg.Server.Limits from forge.yaml onto
+// server.DefaultRequestLimits. Zero fields keep the default; endpoint
+// entries add to (or replace) the built-in per-route caps.
+func ResolveRequestLimits(cfg *types.ForgeConfig) *server.RequestLimits {
+	out := server.DefaultRequestLimits()
+	if cfg == nil {
+		return out
+	}
+	y := cfg.Server.Limits
+	if y.MaxBodyBytes > 0 {
+		out.MaxBodyBytes = y.MaxBodyBytes
+	}
+	for pattern, n := range y.Endpoints {
+		out.EndpointBodyBytes[pattern] = n
+	}
+	if y.MaxMessageParts > 0 {
+		out.MaxMessageParts = y.MaxMessageParts
+	}
+	if y.MaxHistoryMessages > 0 {
+		out.MaxHistoryMessages = y.MaxHistoryMessages
+	}
+	if y.MaxSSEEventBytes > 0 {
+		out.MaxSSEEventBytes = y.MaxSSEEventBytes
+	}
+	if y.MaxBatchRequests > 0 {
+		out.MaxBatchRequests = y.MaxBatchRequests
+	}
+	if y.BatchConcurrency > 0 {
+		out.BatchConcurrency = y.BatchConcurrency
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestResolveRequestLimits(t *testing.T) {
+	def := ResolveRequestLimits(nil)
+	if def.MaxBodyBytes != 2<<20 || def.EndpointBodyBytes["POST /tasks/{id}/decisions"] != 64<<10 {
+		t.Fatalf("defaults = %+v", def)
+	}
+
+	cfg := &types.ForgeConfig{Server: types.ServerConfig{Limits: types.RequestLimitsYAML{
+		MaxBodyBytes:    8 << 20,
+		Endpoints:       map[string]int64{"POST /tasks/send": 16 << 20, "POST /admin/logging": 1 << 10},
+		MaxMessageParts: 8,
+	}}}
+	got := ResolveRequestLimits(cfg)
+	if got.MaxBodyBytes != 8<<20 || got.MaxMessageParts != 8 {
+		t.Errorf("scalar overrides not applied: %+v", got)
+	}
+	if got.EndpointBodyBytes["POST /tasks/send"] != 16<<20 || got.EndpointBodyBytes["POST /admin/logging"] != 1<<10 {
+		t.Errorf("endpoint overrides not applied: %v", got.EndpointBodyBytes)
+	}
+	if got.EndpointBodyBytes["POST /mcp/consent"] != 64<<10 {
+		t.Error("built-in endpoint cap dropped by an unrelated override")
+	}
+	if got.MaxHistoryMessages != def.MaxHistoryMessages || got.MaxSSEEventBytes != def.MaxSSEEventBytes {
+		t.Errorf("zero fields should keep defaults: %+v", got)
+	}
+}
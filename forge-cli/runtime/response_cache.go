@@ -0,0 +1,65 @@
+package runtime
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// initResponseCache opens the LLM response cache on first use. The
+// cache outlives client rebuilds so its entries and counters are shared
+// by every client the runner builds. A cache dir that cannot be created
+// disables the cache with a warning — it is an optimization, never a
+// reason not to start.
+func (r *Runner) initResponseCache(mc *coreruntime.ModelConfig) {
+	if r.responseCache != nil || mc.ResponseCache == nil {
+		return
+	}
+	cfg := *mc.ResponseCache
+	if cfg.Dir != "" && !filepath.IsAbs(cfg.Dir) {
+		cfg.Dir = filepath.Join(r.cfg.WorkDir, cfg.Dir)
+	}
+	cache, err := llm.NewResponseCache(cfg)
+	if err != nil {
+		r.logger.Warn("LLM response cache disabled", map[string]any{"error": err.Error()})
+		return
+	}
+	r.responseCache = cache
+}
+
+// cachedProviderClient is createProviderClient behind the response
+// cache, when enabled, on the shared HTTP transport. Provider capture
+// sits between the two, so it records the calls that reach the
+// provider and not cache hits.
+func (r *Runner) cachedProviderClient(provider string, cfg llm.ClientConfig) (llm.Client, error) {
+	if r.llmTransport != nil {
+		cfg.Transport = r.llmTransport
+	}
+	client, err := r.createProviderClient(provider, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if r.providerCapture != nil {
+		client = r.providerCapture.Wrap(provider, client)
+	}
+	if r.responseCache == nil {
+		return client, nil
+	}
+	return r.responseCache.Wrap(provider, client), nil
+}
+
+// registerResponseCacheEndpoint wires GET /admin/llm-cache, which
+// reports the cache's hit/miss/bypass/store/eviction counters. It sits
+// behind the server's auth middleware like every other non-public
+// route.
+func (r *Runner) registerResponseCacheEndpoint(srv *server.Server) {
+	if r.responseCache == nil {
+		return
+	}
+	srv.RegisterHTTPHandler("GET /admin/llm-cache", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, r.responseCache.Stats())
+	})
+}
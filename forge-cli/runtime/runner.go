@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/initializ/forge/forge-cli/server"
@@ -30,16 +34,19 @@ import (
 	"github.com/initializ/forge/forge-core/auth/providers/httpverifier"
 	_ "github.com/initializ/forge/forge-core/auth/providers/oidc"
 	"github.com/initializ/forge/forge-core/auth/providers/statictoken"
+	"github.com/initializ/forge/forge-core/channels"
 	"github.com/initializ/forge/forge-core/compress"
 	"github.com/initializ/forge/forge-core/credentials"
 	_ "github.com/initializ/forge/forge-core/credentials/static" //nolint:revive // registers static provider via init()
 	_ "github.com/initializ/forge/forge-core/credentials/sts"    //nolint:revive // registers sts_assume_role provider via init()
+	"github.com/initializ/forge/forge-core/i18n"
 	"github.com/initializ/forge/forge-core/llm"
 	"github.com/initializ/forge/forge-core/llm/oauth"
 	"github.com/initializ/forge/forge-core/llm/providers"
 	"github.com/initializ/forge/forge-core/mcp"
 	"github.com/initializ/forge/forge-core/memory"
 	"github.com/initializ/forge/forge-core/observability"
+	"github.com/initializ/forge/forge-core/prompts"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
 	"github.com/initializ/forge/forge-core/scheduler"
 	"github.com/initializ/forge/forge-core/secrets"
@@ -52,6 +59,7 @@ import (
 	"github.com/initializ/forge/forge-core/tools/adapters"
 	"github.com/initializ/forge/forge-core/tools/builtins"
 	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/workflow"
 	"github.com/initializ/forge/forge-skills/contract"
 	skillsparser "github.com/initializ/forge/forge-skills/parser"
 	"github.com/initializ/forge/forge-skills/requirements"
@@ -63,7 +71,9 @@ type RunnerConfig struct {
 	Config            *types.ForgeConfig
 	WorkDir           string
 	Port              int
-	Host              string        // bind host (e.g. "127.0.0.1" for serve, "" for run)
+	Host              string        // bind host ("" = 127.0.0.1)
+	Socket            string        // Unix socket path; replaces Port/Host when set
+	Listener          net.Listener  // socket-activated listener; replaces Socket/Port/Host
 	ShutdownTimeout   time.Duration // graceful shutdown timeout (0 = immediate)
 	MockTools         bool
 	EnforceGuardrails bool
@@ -78,6 +88,12 @@ type RunnerConfig struct {
 	AuthOrgID         string   // org_id sent to external auth provider
 	CORSOrigins       []string // CORS allowed origins (from --cors-origins flag)
 
+	// DryRun intercepts every tool call: the agent plans against
+	// descriptions of what would run, and each task carries a "plan"
+	// artifact instead of side effects. A single request opts in with
+	// metadata.dry_run. See coreruntime.WithDryRun.
+	DryRun bool
+
 	// AuditExport configures the FWS-7 audit export sinks (Unix socket
 	// or localhost HTTP fallback). Zero value = pre-FWS-7 behavior
 	// (stderr only). See issue #95.
@@ -116,8 +132,10 @@ type RunnerConfig struct {
 }
 
 // ScheduleNotifier is called after a scheduled task completes to deliver the
-// result to the appropriate channel (e.g. Slack, Telegram).
-type ScheduleNotifier func(ctx context.Context, channel, target string, response *a2a.Message) error
+// result to the schedule's channel (e.g. Slack, Telegram). It is also called
+// with a nil response to confirm a schedule the agent just set; notifiers
+// with no way to present a confirmation ignore it.
+type ScheduleNotifier func(ctx context.Context, sched scheduler.Schedule, response *a2a.Message) error
 
 // DeferralNotifier is called when a tool call is deferred for human approval
 // (R4c #211) to deliver an interactive approval request to a channel (#310).
@@ -161,14 +179,27 @@ type Runner struct {
 	logger                 coreruntime.Logger
 	cliExecTool            *clitools.CLIExecuteTool
 	modelConfig            *coreruntime.ModelConfig          // resolved model config (for banner)
+	responseCache          *llm.ResponseCache                // nil unless model.response_cache is enabled; shared by every provider client
+	providerCapture        *coreruntime.ProviderCapturer     // nil unless observability.captures is enabled; shared by every provider client
+	llmTransport           *providers.Transport              // HTTP connection pool shared by every provider client (model.http)
+	egressProxy            *security.EgressProxy             // subprocess egress proxy, nil when not running (for /admin/egress-proxy)
+	fallbackChain          *llm.FallbackChain                // primary chain when fallbacks are configured (circuit health for /health)
+	usageMu                sync.Mutex                        // guards usageTotals
+	usageTotals            coreruntime.UsageLedger           // every LLM call since start, for the /info usage section
 	derivedCLIConfig       *contract.DerivedCLIConfig        // auto-derived from skill requirements
 	derivedBrowserConfig   *contract.DerivedBrowserConfig    // non-nil when a skill declares requires.capabilities: [browser] (#94)
 	browserManager         *browser.Manager                  // lazy Chromium owner; nil unless browser tools registered
 	skillGuardrails        *agentspec.SkillGuardrailRules    // runtime-parsed skill guardrails (fallback when no build artifact)
 	schedBackend           scheduler.Backend                 // schedule backend (nil until started); FileBackend in non-cluster deploys, KubernetesBackend (#162 part 2b) when running in-cluster with scheduler.backend=auto|kubernetes
+	schedAudit             scheduler.AuditFunc               // audit emitter for schedule changes made from channels; nil without an audit logger
 	startTime              time.Time                         // server start time (for /health uptime)
 	scheduleNotifier       ScheduleNotifier                  // optional: delivers cron results to channels
 	deferralNotifier       DeferralNotifier                  // optional: delivers DEFER approval requests to channels (#310)
+	notifySender           NotifySender                      // optional: delivers notify tool / POST /notify messages to channels
+	notify                 *notifier                         // notify targets from forge.yaml; nil when none are declared
+	handoff                *handoffs                         // conversations held by human operators; nil without a handoff channel
+	workflows              *workflow.Engine                  // forge.yaml workflows; nil when none are declared
+	events                 *coreruntime.EventBus             // internal pub/sub: task, tool, schedule and guardrail events for logs, metrics, channels and audit
 	authToken              string                            // resolved auth token (empty if --no-auth)
 	cancelRegistry         *coreruntime.CancellationRegistry // per-Runner in-flight cancellation registry (issue #88 / FWS-4)
 	auditSigningKey        *coreruntime.LoadedKey            // loaded once at startup; nil when signing is off (#213). Served on JWKS endpoint.
@@ -184,6 +215,22 @@ type Runner struct {
 	standaloneSubjectStore mcp.SubjectTokenStore             // #332 shared per-subject token cache: standalone resolver reads, callback writes; nil unless a standalone type:user server exists
 	taskStore              *a2a.TaskStore                    // shared task store, populated once srv is built; read by defer hook when it fires
 	platformCommandGuard   *coreruntime.PlatformCommandGuard // #238 (ASI02) operator-authored command deny, applied to every tool call; empty when no layer declares denied_command_patterns
+	sessionStore           coreruntime.SessionStore          // persisted session backend read by tasks/undo; nil when persistence is off or the framework is not forge
+	toolRegistry           *tools.Registry                   // forge-framework tool registry; tasks/undo resolves read-only / compensation hooks through it
+	reloadMu               sync.RWMutex                      // serializes Reload; guards modelConfig and fallbackChain once serving
+	reload                 *reloadTargets                    // what a SIGHUP reload swaps; nil until Run starts serving
+	cluster                *clusterState                     // lock backend, scheduler election and task locks; nil unless cluster.lock_url is set
+	opa                    *opaState                         // OPA decision point for tool calls, egress and schedules; nil unless security.opa is set
+	localizer              *i18n.Localizer                   // catalogs for guardrail notices; set when Run starts
+	channels               []string                          // running channel adapters, listed in the runtime context (see SetChannels)
+	prompts                *prompts.Set                      // prompts/ templates the system prompt renders from; swapped by the file watcher under reloadMu
+
+	// tenantUsage is each tenant's share of usageTotals, by tenant;
+	// guarded by usageMu.
+	tenantUsage map[string]*coreruntime.UsageLedger
+	// experimentUsage is each experiment variant's share, by
+	// "<experiment>/<variant>"; guarded by usageMu.
+	experimentUsage map[string]*coreruntime.UsageLedger
 }
 
 // NewRunner creates a Runner from the given config.
@@ -205,6 +252,7 @@ func NewRunner(cfg RunnerConfig) (*Runner, error) {
 		cfg:            cfg,
 		logger:         logger,
 		cancelRegistry: coreruntime.NewCancellationRegistry(),
+		events:         coreruntime.NewEventBus(),
 	}, nil
 }
 
@@ -214,6 +262,12 @@ func (r *Runner) SetScheduleNotifier(fn ScheduleNotifier) {
 	r.scheduleNotifier = fn
 }
 
+// SetChannels records the channel adapters the agent runs with, for the
+// runtime context block. Must be called before Run().
+func (r *Runner) SetChannels(names []string) {
+	r.channels = names
+}
+
 // SetDeferralNotifier sets the callback used to deliver DEFER (R4c) approval
 // requests to channel adapters (#310). Must be called before Run().
 func (r *Runner) SetDeferralNotifier(fn DeferralNotifier) {
@@ -270,7 +324,7 @@ func (r *Runner) ResolveAuth() error {
 		r.authToken = token
 		return nil
 	}
-	local := isLocalhost(r.cfg.Host)
+	local := r.bindsLocally()
 	if r.cfg.NoAuth && !local {
 		return fmt.Errorf("--no-auth is only allowed when binding to localhost (current host: %s)", r.cfg.Host)
 	}
@@ -297,6 +351,10 @@ func (r *Runner) AuthToken() string {
 
 // Run starts the development server. It blocks until ctx is cancelled.
 func (r *Runner) Run(ctx context.Context) error {
+	if p := r.cfg.Config.ActiveProfile; p != "" {
+		r.logger.Info("forge.yaml profile applied", map[string]any{"profile": p})
+	}
+
 	// 0. Materialize inline KUBECONFIG content to a file.
 	if materialized, err := materializeKubeconfig(r.cfg.WorkDir); err != nil {
 		r.logger.Warn("failed to materialize KUBECONFIG", map[string]any{"error": err.Error()})
@@ -306,7 +364,8 @@ func (r *Runner) Run(ctx context.Context) error {
 		})
 	}
 
-	// 0b. Verify build output integrity if checksums.json exists.
+	// 0b. Verify build output integrity if checksums.json exists
+	// (always, with security.build_verification: enforce).
 	// Inside a Forge container, .forge-output/ is flattened into
 	// WorkDir (typically /app) — the .dockerignore drops the dir
 	// while keeping checksums.json at /app/checksums.json. On the
@@ -320,37 +379,40 @@ func (r *Runner) Run(ctx context.Context) error {
 			outputDir = r.cfg.WorkDir
 		}
 	}
-	if err := VerifyBuildOutput(outputDir); err != nil {
+	verifyMode := r.cfg.Config.Security.BuildVerification
+	if v := os.Getenv(EnvBuildVerification); v != "" {
+		verifyMode = v
+	}
+	if verifyMode != "" && verifyMode != "warn" && verifyMode != "enforce" {
+		return fmt.Errorf("build verification mode %q must be one of: warn, enforce", verifyMode)
+	}
+	if err := VerifyBuildOutput(outputDir, verifyMode == "enforce"); err != nil {
+		if verifyMode == "enforce" {
+			return fmt.Errorf("build output verification failed: %w", err)
+		}
 		r.logger.Warn("build output verification failed", map[string]any{"error": err.Error()})
 	}
 
-	// 1. Load .env file
-	envVars, err := LoadEnvFile(r.cfg.EnvFilePath)
+	// 1. Load .env file, secrets and model-config env
+	envVars, err := r.loadEnvVars()
 	if err != nil {
-		return fmt.Errorf("loading env file: %w", err)
+		return err
 	}
 
-	// Overlay secrets from configured providers
-	if err := r.overlaySecrets(envVars); err != nil {
-		return fmt.Errorf("secret validation failed: %w", err)
+	// 1b. Validate skill requirements
+	if err := r.validateSkillRequirements(envVars); err != nil {
+		return err
 	}
 
-	// Merge non-secret model-config env keys (base URLs, provider/model
-	// overrides, region) from the process environment. LoadEnvFile only reads
-	// the .env file and overlaySecrets only fetches secret keys, so keys a
-	// deployment injects into the pod env — e.g. ANTHROPIC_BASE_URL pointing at
-	// a gateway — would otherwise never reach ResolveModelConfig, silently
-	// falling back to the provider's public host. .env / secrets already loaded
-	// above win; this only fills what's missing.
-	mergeModelConfigEnv(envVars)
-
-	// Apply model override
-	if r.cfg.ModelOverride != "" {
-		envVars["MODEL_NAME"] = r.cfg.ModelOverride
+	// 1c. Catalogs for the notices the runtime writes itself.
+	localizer, err := i18n.New(r.cfg.Config.Localization, r.cfg.WorkDir)
+	if err != nil {
+		return fmt.Errorf("localization: %w", err)
 	}
+	r.localizer = localizer
 
-	// 1b. Validate skill requirements
-	if err := r.validateSkillRequirements(envVars); err != nil {
+	// 1d. Prompt templates; the system prompt must render with them.
+	if err := r.loadPrompts(); err != nil {
 		return err
 	}
 
@@ -404,6 +466,43 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 	auditLogger.WithEntity("agent", agentID)
 
+	// Internal event bus: subsystems publish what the agent does, the
+	// ops log, usage totals and schedule audit subscribe. Close drains
+	// queued events on shutdown.
+	// The LLM trace exporters flush after the bus has drained into them:
+	// defers run last-in first-out.
+	stopLLMTrace := r.startLLMTraceExport(envVars, agentID)
+	defer stopLLMTrace()
+	r.subscribeEvents(auditLogger)
+	defer r.events.Close()
+
+	// forge.yaml notify targets, served by the notify tool and POST /notify.
+	if r.cfg.Config != nil {
+		n, err := newNotifier(r, r.cfg.Config.Notify, agentID, auditLogger)
+		if err != nil {
+			return err
+		}
+		r.notify = n
+	}
+	// forge.yaml handoff channel, served by handoff_to_human and /handoffs.
+	if r.cfg.Config != nil && r.cfg.Config.Handoff.Enabled() {
+		statePath := ""
+		if r.cfg.WorkDir != "" {
+			statePath = filepath.Join(r.cfg.WorkDir, ".forge", "handoffs.json")
+		}
+		h, err := newHandoffs(r, r.cfg.Config.Handoff, auditLogger, statePath)
+		if err != nil {
+			return err
+		}
+		r.handoff = h
+	}
+	// forge.yaml alert rules, evaluated in the background over the
+	// usage and task outcomes the event bus carries.
+	if r.cfg.Config != nil {
+		r.startAlertMonitor(ctx, auditLogger, agentID)
+		r.startArtifactGC(ctx, auditLogger)
+	}
+
 	// Ed25519 event signing (#213). Signing is opt-in via env:
 	// FORGE_AUDIT_SIGNING_KEY_B64 (PKCS#8 DER base64, or PEM inline)
 	// plus optional FORGE_AUDIT_SIGNING_KID. When unset, the loader
@@ -491,6 +590,16 @@ func (r *Runner) Run(ctx context.Context) error {
 		})
 	}
 
+	// Policy-as-code decision point (security.opa). Started before the
+	// egress enforcer below so it can hook egress attempts; with
+	// policy_path this also starts the local OPA server.
+	opaPoint, stopOPA, err := r.startOPA(ctx, auditLogger)
+	if err != nil {
+		return fmt.Errorf("opa: %w", err)
+	}
+	defer stopOPA()
+	r.opa = opaPoint
+
 	// Resolve TracingConfig early so we can thread it into the
 	// guardrail engine before the tracer provider itself is installed
 	// further down. ResolveTracingConfig is a pure config-resolution
@@ -515,7 +624,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	// evidence stamping (#161); the spans themselves are opened
 	// unconditionally — when tracing is disabled, the noop tracer
 	// short-circuits.
-	guardrails, err := BuildGuardrailChecker(r.cfg.Config, r.cfg.WorkDir, r.cfg.EnforceGuardrails, r.logger, auditLogger, GuardrailAuditConfigFromEnv(), tracingCfgEarly)
+	guardrails, err := BuildGuardrailChecker(r.cfg.Config, r.cfg.WorkDir, r.cfg.EnforceGuardrails, r.logger, auditLogger, GuardrailAuditConfigFromEnv(), r.events, tracingCfgEarly)
 	if err != nil {
 		// Only the fail-loud DB-required path produces a non-nil
 		// error here. The runner refuses to serve so the agent
@@ -523,6 +632,11 @@ func (r *Runner) Run(ctx context.Context) error {
 		// surface the failure to operators. Issue #166.
 		return err
 	}
+	// Every hook and handler holds the checker through this wrapper so
+	// a SIGHUP reload (Reload) can swap the policies in place.
+	reloadGuardrails := newReloadableGuardrails(guardrails)
+	guardrails = reloadGuardrails
+	reload := &reloadTargets{cfg: r.cfg.Config, guardrails: reloadGuardrails, auditLogger: auditLogger, tracingCfg: tracingCfgEarly}
 	// Periodic audit_export_status — one event every 60s with per-sink
 	// health counters. Operators tail the audit stream to answer
 	// "is my sidecar healthy?". The stop func blocks until the
@@ -604,77 +718,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	var egressProxy *security.EgressProxy
 	var proxyURL string
 	var socksURL string
-	egressToolNames := make([]string, len(r.cfg.Config.Tools))
-	for i, t := range r.cfg.Config.Tools {
-		egressToolNames[i] = t.Name
-	}
-	// Merge skill-derived egress domains with explicitly configured domains.
-	// Both sources may contain $VAR or ${VAR} references which are
-	// expanded from .env and OS environment (e.g. "$K8S_API_DOMAIN").
-	//
-	// Platform-policy intersection (issue #89 / FWS-5): the developer's
-	// forge.yaml allow list is filtered through the policy deny list
-	// BEFORE expansion. The EnforcePolicy check above already aborted
-	// startup on a declared-but-denied entry; this filter is the
-	// belt-and-suspenders defence-in-depth pass — any new code path
-	// that injects egress entries can call it independently.
-	declaredAllowed := security.EffectiveEgressAllowlist(r.cfg.Config, platformLayers)
-	var egressDomains []string
-	for _, d := range declaredAllowed {
-		egressDomains = append(egressDomains, expandEgressDomains(d, envVars)...)
-	}
-	if r.derivedCLIConfig != nil && len(r.derivedCLIConfig.EgressDomains) > 0 {
-		for _, d := range r.derivedCLIConfig.EgressDomains {
-			egressDomains = append(egressDomains, expandEgressDomains(d, envVars)...)
-		}
-	}
-	// Auto-merge auth-provider issuer/verifier hosts. Without this, an
-	// OIDC issuer or http_verifier URL configured in forge.yaml would be
-	// silently blocked at runtime by the egress enforcer.
-	egressDomains = append(egressDomains, security.AuthDomains(r.cfg.Config.Auth)...)
-	// Same for MCP servers — without this, every HTTPS MCP call would
-	// be silently blocked. Mirror the AuthDomains pattern.
-	egressDomains = append(egressDomains, security.MCPDomains(r.cfg.Config.MCP)...)
-	// #316: with OAuth discovery the authorization-server host is not in
-	// forge.yaml to pre-seed the allowlist — it is learned at login time
-	// and persisted in the registration record. mcpRegisteredOAuthHosts
-	// applies the store-path override and reads those hosts back.
-	egressDomains = append(egressDomains, mcpRegisteredOAuthHosts(r.cfg.Config.MCP)...)
-	// §19: the platform token resolver must be reachable for
-	// auth.type=platform servers — merge its host (env-expanded; the
-	// endpoint may be materialized as ${VAR}).
-	egressDomains = append(egressDomains, platformResolverHost(r.cfg.Config.Platform)...)
-	// Phase 6 (#107 / #108) — same for the OTel collector. Without
-	// this, dev runs with `observability.tracing.enabled: true` and
-	// `egress.mode: allowlist` would silently drop spans on shutdown.
-	// Matches the build pipeline's egress_stage so `forge run` and
-	// `forge package`-then-deploy behave identically on the
-	// allowlist surface.
-	egressDomains = append(egressDomains, security.OTelDomain(r.cfg.Config.Observability.Tracing)...)
-	// Issue #139 — auto-merge LLM provider base URLs. Two sources:
-	//   1. The new ModelRef.BaseURL field (the durable signal that
-	//      also flows through `forge package` to the deployed
-	//      NetworkPolicy). This is the canonical path going forward.
-	//   2. The standard SDK base-URL env vars (OPENAI_BASE_URL /
-	//      ANTHROPIC_BASE_URL / OLLAMA_BASE_URL / GEMINI_BASE_URL).
-	//      Safety-net for deployments that haven't migrated to the
-	//      schema field yet — `envVars` already carries the resolved
-	//      .env + .forge/secrets.enc state at this point.
-	// Both are deduped via the helper. Without these merges, an agent
-	// using a custom OpenAI-compatible / Anthropic-compatible /
-	// remote-Ollama endpoint would be silently blocked by the egress
-	// enforcer at runtime.
-	egressDomains = append(egressDomains, security.LLMProviderDomains(r.cfg.Config)...)
-	egressDomains = append(egressDomains, security.LLMProviderEnvDomains(envVars)...)
-	egressCfg, egressErr := security.Resolve(
-		r.cfg.Config.Egress.Profile,
-		r.cfg.Config.Egress.Mode,
-		egressDomains,
-		egressToolNames,
-		r.cfg.Config.Egress.Capabilities,
-		r.cfg.Config.Egress.AllowedPrivateCIDRs,
-		r.cfg.Config.Egress.AllowedTCP,
-	)
+	egressCfg, egressErr := r.resolveEgress(r.cfg.Config, envVars, platformLayers)
 	if egressErr != nil {
 		r.logger.Warn("failed to resolve egress config, using default", map[string]any{"error": egressErr.Error()})
 		egressClient = http.DefaultClient
@@ -696,7 +740,22 @@ func (r *Runner) Run(ctx context.Context) error {
 			allowedPrivateCIDRs = nil
 		}
 
+		// Scheme, port, method and response-size constraints. Validation
+		// already rejected bad entries, so an error here fails the start
+		// rather than running with egress looser than configured.
+		egressRef := r.cfg.Config.Egress
+		constraints, consErr := security.NewEgressConstraints(egressRef.AllowedSchemes, egressRef.MaxResponseBytes, egressRef.Rules)
+		if consErr != nil {
+			return fmt.Errorf("egress: %w", consErr)
+		}
+
+		egressLog := coreruntime.SubsystemLogger(r.logger, coreruntime.LogSubsystemEgress)
 		enforcer := security.NewEgressEnforcer(nil, egressCfg.Mode, egressCfg.AllDomains, allowPrivateIPs, allowedPrivateCIDRs)
+		enforcer.SetConstraints(constraints)
+		reload.matchers = append(reload.matchers, enforcer.Matcher())
+		if r.opa.enabled(types.OPAPointEgress) {
+			enforcer.Authorize = r.opa.authorizeEgress
+		}
 		enforcer.OnAttempt = func(ctx context.Context, domain string, allowed bool) {
 			event := coreruntime.AuditEgressAllowed
 			if !allowed {
@@ -706,8 +765,22 @@ func (r *Runner) Run(ctx context.Context) error {
 				Event:         event,
 				CorrelationID: coreruntime.CorrelationIDFromContext(ctx),
 				TaskID:        coreruntime.TaskIDFromContext(ctx),
-				Fields:        map[string]any{"domain": domain, "mode": string(egressCfg.Mode)},
+				Fields:        map[string]any{"domain": domain, "mode": string(enforcer.Matcher().Mode())},
 			})
+			if !allowed {
+				r.publishEgressBlocked(ctx, "", "", domain, string(enforcer.Matcher().Mode()), "client")
+			}
+			logEgressAttempt(egressLog, domain, allowed, "client")
+		}
+		enforcer.OnViolation = func(ctx context.Context, a security.EgressAttempt) {
+			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
+				Event:         coreruntime.AuditEgressBlocked,
+				CorrelationID: coreruntime.CorrelationIDFromContext(ctx),
+				TaskID:        coreruntime.TaskIDFromContext(ctx),
+				Fields:        egressAttemptFields(a, map[string]any{"domain": a.Domain, "mode": string(enforcer.Matcher().Mode())}),
+			})
+			r.publishEgressBlocked(ctx, "", "", a.Domain, string(enforcer.Matcher().Mode()), "client")
+			logEgressAttempt(egressLog, a.Domain, false, "client")
 		}
 		// Phase 3 (#104) — wrap the egress-enforced transport with
 		// otelhttp instrumentation so every outbound HTTP request the
@@ -740,11 +813,19 @@ func (r *Runner) Run(ctx context.Context) error {
 		// capability force-starts it even in-container / dev-open: browser
 		// tools never run unproxied ("no direct-network escape hatch", #94).
 		// In dev-open mode the matcher allows all domains, so the proxy is a
-		// pass-through with audit logging.
+		// pass-through with audit logging. A configured egress.proxy.socket
+		// force-starts it too: sidecars reach egress only through it.
 		browserActive := r.derivedBrowserConfig != nil
-		if (!security.InContainer() && egressCfg.Mode != security.ModeDevOpen) || browserActive {
+		sidecarSocket := r.cfg.Config.Egress.Proxy != nil && r.cfg.Config.Egress.Proxy.Socket != ""
+		if (!security.InContainer() && egressCfg.Mode != security.ModeDevOpen) || browserActive || sidecarSocket {
 			matcher := security.NewDomainMatcher(egressCfg.Mode, egressCfg.AllDomains)
 			egressProxy = security.NewEgressProxy(matcher, allowPrivateIPs, allowedPrivateCIDRs)
+			egressProxy.SetConstraints(constraints)
+			applyEgressProxyConfig(egressProxy, r.cfg.Config.Egress.Proxy)
+			reload.matchers = append(reload.matchers, matcher)
+			if r.opa.enabled(types.OPAPointEgress) {
+				egressProxy.Authorize = r.opa.authorizeProxyEgress
+			}
 
 			// #337 — install the port-aware raw-TCP matcher. Empty list =>
 			// SOCKS5 listener stays disabled (no extra port bound). The
@@ -770,8 +851,12 @@ func (r *Runner) Run(ctx context.Context) error {
 					Event:         event,
 					TaskID:        a.TaskID,
 					CorrelationID: a.CorrelationID,
-					Fields:        map[string]any{"domain": a.Domain, "mode": string(egressCfg.Mode), "source": "proxy"},
+					Fields:        egressAttemptFields(a, map[string]any{"domain": a.Domain, "mode": string(matcher.Mode()), "source": "proxy"}),
 				})
+				if !a.Allowed {
+					r.publishEgressBlocked(ctx, a.TaskID, a.CorrelationID, a.Domain, string(matcher.Mode()), "proxy")
+				}
+				logEgressAttempt(egressLog, a.Domain, a.Allowed, "proxy")
 			}
 			var pErr error
 			proxyURL, pErr = egressProxy.Start(ctx)
@@ -788,10 +873,14 @@ func (r *Runner) Run(ctx context.Context) error {
 				// #337 — capture the SOCKS5 URL for env injection into skill
 				// subprocesses. Empty when raw-TCP egress wasn't configured.
 				socksURL = egressProxy.SOCKSURL()
+				r.egressProxy = egressProxy
 				fields := map[string]any{"http_url": proxyURL}
 				if socksURL != "" {
 					fields["socks_url"] = socksURL
 				}
+				if sock := egressProxy.SocketPath(); sock != "" {
+					fields["socket"] = sock
+				}
 				r.logger.Info("egress proxy started", fields)
 			}
 		}
@@ -854,6 +943,17 @@ func (r *Runner) Run(ctx context.Context) error {
 		}()
 	}
 
+	// 4d. Cluster coordination (cluster.lock_url): elect the replica
+	// that runs the file-backend scheduler and lock tasks so one runs on
+	// one replica at a time. Stopped after the server drains (LIFO), so
+	// in-flight tasks still release their locks.
+	cs, stopCluster, err := r.startCluster(ctx)
+	if err != nil {
+		return fmt.Errorf("cluster: %w", err)
+	}
+	defer stopCluster()
+	r.cluster = cs
+
 	// 5. Choose executor and optional lifecycle runtime
 	var executor coreruntime.AgentExecutor
 	var lifecycle coreruntime.AgentRuntime // optional, for subprocess lifecycle management
@@ -870,6 +970,7 @@ func (r *Runner) Run(ctx context.Context) error {
 		default:
 			// Forge framework — build tool registry and use built-in LLM executor
 			reg := tools.NewRegistry()
+			r.toolRegistry = reg
 			// R9: wire the JIT credential injector into http_request
 			// alongside cli_execute (further down). Nil injector →
 			// no-op inside the tool, so unsigned-cred deployments
@@ -1170,6 +1271,9 @@ func (r *Runner) Run(ctx context.Context) error {
 			mc := coreruntime.ResolveModelConfig(r.cfg.Config, envVars, r.cfg.ProviderOverride)
 			if mc != nil {
 				r.modelConfig = mc
+				if err := r.checkOllama(ctx, mc); err != nil {
+					return err
+				}
 				// Export org ID for skill scripts
 				if mc.Client.OrgID != "" {
 					_ = os.Setenv("OPENAI_ORG_ID", mc.Client.OrgID)
@@ -1179,11 +1283,20 @@ func (r *Runner) Run(ctx context.Context) error {
 					r.logger.Warn("failed to create LLM client, using stub", map[string]any{"error": llmErr.Error()})
 					executor = NewStubExecutor(r.cfg.Config.Framework)
 				} else {
-					// Build logging and audit hooks for agent loop observability
+					// Reload (SIGHUP) swaps the model behind this
+					// wrapper; compression and the compactor wrap it.
+					reload.client = newReloadableClient(llmClient)
+					llmClient = reload.client
+
+					// Publish the loop's tool and LLM events to the bus
+					// (ops log, usage totals, channel progress) and
+					// audit them in line.
 					hooks := coreruntime.NewHookRegistry()
-					r.registerLoggingHooks(hooks)
+					r.events.PublishHooks(hooks)
 					r.registerAuditHooks(hooks, auditLogger)
+					r.registerCircuitAudit(auditLogger)
 					r.registerProgressHooks(hooks)
+					r.registerScheduleHooks(hooks)
 					r.registerGuardrailHooks(hooks, guardrails)
 
 					// R3 (#208) — intent-alignment check on every
@@ -1198,6 +1311,11 @@ func (r *Runner) Run(ctx context.Context) error {
 					// No-op when the engine is disabled.
 					r.registerStepUpHook(hooks, auditLogger)
 
+					// Policy-as-code (security.opa) — tool calls and
+					// schedule_set go to OPA before any deferral, so a
+					// human is never asked to approve what policy denies.
+					r.registerOPAHook(hooks)
+
 					// R4c (#211) — defer hook. Pauses the executor
 					// when a listed tool is invoked, until a decision
 					// arrives (or the timeout auto-denies). The hook
@@ -1244,30 +1362,17 @@ func (r *Runner) Run(ctx context.Context) error {
 						charBudget = coreruntime.ContextBudgetForModel(mc.Client.Model)
 					}
 
-					// Build system prompt; append code-agent tool directives if those tools are registered.
-					sysPrompt := r.buildSystemPrompt()
-					if r.hasSkill("code-agent") {
-						sysPrompt += "\n\n" + codeAgentDirective
-					}
-					// Compression marker-awareness is a runtime concern, not a
-					// per-skill one: whenever compression is on, every skill's
-					// agent learns what <<ctxzip:...>> markers are and when to
-					// call context_expand — skill authors need do nothing.
-					if r.compression != nil {
-						sysPrompt += "\n\n" + compress.SystemDirective
-					}
-
 					execCfg := coreruntime.LLMExecutorConfig{
-						Client:        llmClient,
-						Tools:         reg,
-						Hooks:         hooks,
-						SystemPrompt:  sysPrompt,
-						Logger:        r.logger,
-						ModelName:     mc.Client.Model,
-						Provider:      mc.Provider,
-						MaxIterations: 100,
-						CharBudget:    charBudget,
-						FilesDir:      filepath.Join(r.cfg.WorkDir, ".forge", "files"),
+						Client:       llmClient,
+						Tools:        reg,
+						Hooks:        hooks,
+						SystemPrompt: r.executorSystemPrompt(),
+						Logger:       coreruntime.SubsystemLogger(r.logger, coreruntime.LogSubsystemExecutor),
+						ModelName:    mc.Client.Model,
+						Provider:     mc.Provider,
+						CharBudget:   charBudget,
+						FilesDir:     filepath.Join(r.cfg.WorkDir, ".forge", "files"),
+						TenantsDir:   TenantsDir(r.cfg.WorkDir),
 						// With compression on, tool results are capped AFTER
 						// the compression hook (behind a 16x/4MB safety
 						// ceiling) — pre-hook truncation destroys data and
@@ -1286,6 +1391,14 @@ func (r *Runner) Run(ctx context.Context) error {
 					if r.derivedCLIConfig != nil {
 						execCfg.WorkflowPhases = r.derivedCLIConfig.WorkflowPhases
 					}
+					// Past memory.high_water_mark the executor summarizes
+					// the largest tool results; the summarizer follows the
+					// model through reloads.
+					if hw := r.cfg.Config.Memory.HighWaterMark; hw > 0 {
+						reload.summary = newReloadableClient(r.summaryClient(mc, reload.client))
+						execCfg.ContextHighWater = hw
+						execCfg.SummaryClient = reload.summary
+					}
 
 					// Initialize memory persistence (enabled by default).
 					// Disable via FORGE_MEMORY_PERSISTENCE=false or memory.persistence: false in forge.yaml.
@@ -1315,21 +1428,15 @@ func (r *Runner) Run(ctx context.Context) error {
 							sessionStore = remote
 							storeDesc = map[string]any{"backend": "remote"}
 						} else {
-							sessDir := r.cfg.Config.Memory.SessionsDir
-							if sessDir == "" {
-								sessDir = filepath.Join(r.cfg.WorkDir, ".forge", "sessions")
-							}
+							// Old sessions are removed by artifact GC
+							// (retention.sessions, 7 days by default).
+							sessDir := SessionsDir(r.cfg.Config.Memory, r.cfg.WorkDir)
 							memStore, storeErr := coreruntime.NewMemoryStore(sessDir)
 							if storeErr != nil {
 								r.logger.Warn("failed to create memory store, persistence disabled", map[string]any{
 									"error": storeErr.Error(),
 								})
 							} else {
-								// Clean up old sessions on startup (7-day TTL).
-								deleted, _ := memStore.Cleanup(7 * 24 * time.Hour)
-								if deleted > 0 {
-									r.logger.Info("cleaned up old sessions", map[string]any{"deleted": deleted})
-								}
 								sessionStore = memStore
 								storeDesc = map[string]any{"backend": "file", "sessions_dir": sessDir}
 							}
@@ -1339,13 +1446,14 @@ func (r *Runner) Run(ctx context.Context) error {
 							compactor := coreruntime.NewCompactor(coreruntime.CompactorConfig{
 								Client:       llmClient,
 								Store:        sessionStore,
-								Logger:       r.logger,
+								Logger:       coreruntime.SubsystemLogger(r.logger, coreruntime.LogSubsystemExecutor),
 								CharBudget:   charBudget,
 								TriggerRatio: r.cfg.Config.Memory.TriggerRatio,
 							})
 
 							execCfg.Store = sessionStore
 							execCfg.Compactor = compactor
+							r.sessionStore = sessionStore
 
 							// Session max age: stale sessions are discarded to prevent
 							// poisoned error context from blocking tool retries.
@@ -1364,19 +1472,36 @@ func (r *Runner) Run(ctx context.Context) error {
 					}
 
 					// Initialize long-term memory if enabled.
-					memMgr := r.initLongTermMemory(ctx, mc, reg, execCfg.Compactor)
+					memMgr := r.initLongTermMemory(ctx, mc, llmClient, reg, execCfg.Compactor)
 					if memMgr != nil {
 						defer memMgr.Close() //nolint:errcheck
+						r.startMemoryGC(ctx, memMgr, auditLogger)
 					}
 
 					// Initialize scheduler store and register schedule tools.
 					schedStore := r.initScheduler(reg)
-
-					executor = coreruntime.NewLLMExecutor(execCfg)
+					r.registerNotifyTool(reg)
+					r.registerHandoffTool(reg)
+					r.registerModelSwitchTool(reg)
+					r.registerCompactNowTool(reg)
+					r.registerWorkflows(reg, hooks, llmClient, auditLogger)
+					// Last, once every tool is registered.
+					r.applyReadOnlyMode(reg)
+					r.applyToolPolicies(reg)
+					r.applySandbox(reg, &execCfg)
+					r.applyLoopLimits(&execCfg)
+					r.applyReflection(&execCfg, mc, reload)
+					r.applyOutputContracts(&execCfg)
+					r.applyExecutorMode(&execCfg)
+					r.applyRuntimeContext(&execCfg)
+
+					llmExecutor := coreruntime.NewLLMExecutor(execCfg)
+					reload.executor = llmExecutor
+					executor = llmExecutor
 
 					// Start cron scheduler after executor is ready.
 					if schedStore != nil {
-						dispatch := r.makeScheduleDispatcher(executor, egressClient, auditLogger)
+						dispatch := r.makeScheduleDispatcher(executor, egressClient)
 						var auditFn scheduler.AuditFunc
 						if auditLogger != nil {
 							auditFn = func(event, scheduleID string, fields map[string]any) {
@@ -1405,6 +1530,7 @@ func (r *Runner) Run(ctx context.Context) error {
 							return berr
 						}
 						r.schedBackend = backend
+						r.schedAudit = auditFn
 						if syncErr := r.schedBackend.Sync(ctx, r.declaredSchedules()); syncErr != nil {
 							r.logger.Warn("schedule backend sync failed", map[string]any{"error": syncErr.Error()})
 						}
@@ -1475,7 +1601,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	//
 	// The pipeline shape is:
 	//
-	//   seq counter → auth → admission → handlers
+	//   seq counter → auth → tenant → admission → handlers
 	//
 	// auth runs first so the platform call never burns on
 	// unauthenticated traffic; admission runs before the dispatcher
@@ -1484,19 +1610,24 @@ func (r *Runner) Run(ctx context.Context) error {
 	admissionChecker := BuildAdmissionChecker(r.cfg.Config.AgentID, r.logger)
 	admissionMW := server.AdmissionMiddleware(admissionChecker, auditLogger)
 	authThenAdmission := func(next http.Handler) http.Handler {
-		return auth.Middleware(authCfg)(admissionMW(next))
+		return auth.Middleware(authCfg)(tenantMiddleware(admissionMW(next)))
 	}
 
 	r.startTime = time.Now()
 	srv := server.NewServer(server.ServerConfig{
 		Port:            r.cfg.Port,
-		Host:            r.cfg.Host,
+		Host:            defaultStr(r.cfg.Host, "127.0.0.1"),
+		Socket:          r.cfg.Socket,
+		Listener:        r.cfg.Listener,
 		ShutdownTimeout: r.cfg.ShutdownTimeout,
+		OnDrainTimeout:  r.cancelInFlight,
 		AgentCard:       card,
 		AuthMiddleware:  installIngressContextMiddleware(authThenAdmission),
 		AllowedOrigins:  corsOrigins,
 		RateLimit:       rateLimit,
+		Limits:          ResolveRequestLimits(r.cfg.Config),
 	})
+	r.advertiseAgentCardCapabilities(card, srv.RateLimit())
 	// R4c: the task store is created inside NewServer; expose it
 	// on the runner so the defer hook (which registered earlier,
 	// before srv existed) can resolve it at fire time.
@@ -1508,6 +1639,13 @@ func (r *Runner) Run(ctx context.Context) error {
 	// 7b. Register REST-style HTTP handlers
 	r.registerRESTHandlers(srv, executor, guardrails, egressClient, auditLogger)
 
+	// 7c. Webhook triggers declared in forge.yaml
+	r.registerTriggerEndpoints(srv, executor, guardrails, egressClient, auditLogger)
+
+	// 7d. Live status for `forge status` and scrapers: GET /metrics
+	// and GET /events, fed by the event bus.
+	r.startStatusMonitor(ctx, srv)
+
 	// 9. Start file watcher
 	watchCtx, watchCancel := context.WithCancel(ctx)
 	defer watchCancel()
@@ -1520,6 +1658,7 @@ func (r *Runner) Run(ctx context.Context) error {
 		} else {
 			coreruntime.PopulateSecuritySchemes(newCard, r.cfg.Config)
 			r.enrichAgentCardWithSkills(newCard)
+			r.advertiseAgentCardCapabilities(newCard, srv.RateLimit())
 			srv.UpdateAgentCard(newCard)
 			r.logger.Info("agent card reloaded", nil)
 			// Re-emit agent_card_published so audit consumers see the
@@ -1527,6 +1666,9 @@ func (r *Runner) Run(ctx context.Context) error {
 			r.emitAgentCardPublished(auditLogger, newCard)
 		}
 
+		// Prompt templates apply to the next task; no restart needed.
+		r.reloadPrompts(auditLogger)
+
 		// Restart subprocess lifecycle (no-op if lifecycle is nil)
 		if lifecycle != nil {
 			if err := lifecycle.Restart(ctx); err != nil {
@@ -1544,11 +1686,141 @@ func (r *Runner) Run(ctx context.Context) error {
 	// encoded card so consumers can detect config drift. Hot-reload
 	// re-emits via the file watcher above (UpdateAgentCard path).
 	r.emitAgentCardPublished(auditLogger, card)
+	r.emitPromptLoaded(auditLogger)
+
+	// 10c. Publish the reload targets: from here on SIGHUP reloads
+	// the model, guardrails and egress allowlist in place.
+	r.reloadMu.Lock()
+	r.reload = reload
+	r.reloadMu.Unlock()
 
 	// 11. Start server (blocks)
 	return srv.Start(ctx)
 }
 
+// cancelInFlight is the server's drain-timeout hook: tasks still running
+// when a graceful shutdown has waited ShutdownTimeout are cancelled with
+// reason "shutdown", which persists their sessions so they resume after
+// the restart.
+func (r *Runner) cancelInFlight(inFlight int) {
+	cancelled := r.cancelRegistry.CancelAll(coreruntime.CancelReasonShutdown)
+	r.logger.Warn("shutdown timeout reached, cancelling in-flight tasks", map[string]any{
+		"in_flight": inFlight,
+		"cancelled": cancelled,
+	})
+}
+
+// loadEnvVars reads the .env file, overlays secrets from the configured
+// providers and model-config keys from the process environment, and
+// applies --model. Run calls it at startup and Reload on every reload,
+// so a rotated key or an edited MODEL_NAME takes effect on SIGHUP.
+func (r *Runner) loadEnvVars() (map[string]string, error) {
+	envVars, err := LoadEnvFile(r.cfg.EnvFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("loading env file: %w", err)
+	}
+
+	// Overlay secrets from configured providers
+	if err := r.overlaySecrets(envVars); err != nil {
+		return nil, fmt.Errorf("secret validation failed: %w", err)
+	}
+
+	// Merge non-secret model-config env keys (base URLs, provider/model
+	// overrides, region) from the process environment. LoadEnvFile only reads
+	// the .env file and overlaySecrets only fetches secret keys, so keys a
+	// deployment injects into the pod env — e.g. ANTHROPIC_BASE_URL pointing at
+	// a gateway — would otherwise never reach ResolveModelConfig, silently
+	// falling back to the provider's public host. .env / secrets already loaded
+	// above win; this only fills what's missing.
+	mergeModelConfigEnv(envVars)
+
+	// Apply model override
+	if r.cfg.ModelOverride != "" {
+		envVars["MODEL_NAME"] = r.cfg.ModelOverride
+	}
+	return envVars, nil
+}
+
+// resolveEgress computes the egress configuration for cfg: its declared
+// domains (filtered through platform policy) plus the skill-derived
+// domains and the auth, MCP, OTel and LLM hosts the runtime itself
+// must reach. Run calls it at startup and Reload on SIGHUP.
+func (r *Runner) resolveEgress(cfg *types.ForgeConfig, envVars map[string]string, platformLayers []security.PolicyLayer) (*security.EgressConfig, error) {
+	egressToolNames := make([]string, len(cfg.Tools))
+	for i, t := range cfg.Tools {
+		egressToolNames[i] = t.Name
+	}
+	// Merge skill-derived egress domains with explicitly configured domains.
+	// Both sources may contain $VAR or ${VAR} references which are
+	// expanded from .env and OS environment (e.g. "$K8S_API_DOMAIN").
+	//
+	// Platform-policy intersection (issue #89 / FWS-5): the developer's
+	// forge.yaml allow list is filtered through the policy deny list
+	// BEFORE expansion. The EnforcePolicy check above already aborted
+	// startup on a declared-but-denied entry; this filter is the
+	// belt-and-suspenders defence-in-depth pass — any new code path
+	// that injects egress entries can call it independently.
+	declaredAllowed := security.EffectiveEgressAllowlist(cfg, platformLayers)
+	var egressDomains []string
+	for _, d := range declaredAllowed {
+		egressDomains = append(egressDomains, expandEgressDomains(d, envVars)...)
+	}
+	if r.derivedCLIConfig != nil && len(r.derivedCLIConfig.EgressDomains) > 0 {
+		for _, d := range r.derivedCLIConfig.EgressDomains {
+			egressDomains = append(egressDomains, expandEgressDomains(d, envVars)...)
+		}
+	}
+	// Auto-merge auth-provider issuer/verifier hosts. Without this, an
+	// OIDC issuer or http_verifier URL configured in forge.yaml would be
+	// silently blocked at runtime by the egress enforcer.
+	egressDomains = append(egressDomains, security.AuthDomains(cfg.Auth)...)
+	// Same for MCP servers — without this, every HTTPS MCP call would
+	// be silently blocked. Mirror the AuthDomains pattern.
+	egressDomains = append(egressDomains, security.MCPDomains(cfg.MCP)...)
+	// #316: with OAuth discovery the authorization-server host is not in
+	// forge.yaml to pre-seed the allowlist — it is learned at login time
+	// and persisted in the registration record. mcpRegisteredOAuthHosts
+	// applies the store-path override and reads those hosts back.
+	egressDomains = append(egressDomains, mcpRegisteredOAuthHosts(cfg.MCP)...)
+	// §19: the platform token resolver must be reachable for
+	// auth.type=platform servers — merge its host (env-expanded; the
+	// endpoint may be materialized as ${VAR}).
+	egressDomains = append(egressDomains, platformResolverHost(cfg.Platform)...)
+	// Phase 6 (#107 / #108) — same for the OTel collector. Without
+	// this, dev runs with `observability.tracing.enabled: true` and
+	// `egress.mode: allowlist` would silently drop spans on shutdown.
+	// Matches the build pipeline's egress_stage so `forge run` and
+	// `forge package`-then-deploy behave identically on the
+	// allowlist surface.
+	egressDomains = append(egressDomains, security.OTelDomain(cfg.Observability.Tracing)...)
+	// Same for the Langfuse / LangSmith trace exporters.
+	egressDomains = append(egressDomains, security.LLMTraceDomains(cfg.Observability)...)
+	// Issue #139 — auto-merge LLM provider base URLs. Two sources:
+	//   1. The new ModelRef.BaseURL field (the durable signal that
+	//      also flows through `forge package` to the deployed
+	//      NetworkPolicy). This is the canonical path going forward.
+	//   2. The standard SDK base-URL env vars (OPENAI_BASE_URL /
+	//      ANTHROPIC_BASE_URL / OLLAMA_BASE_URL / GEMINI_BASE_URL).
+	//      Safety-net for deployments that haven't migrated to the
+	//      schema field yet — `envVars` already carries the resolved
+	//      .env + .forge/secrets.enc state at this point.
+	// Both are deduped via the helper. Without these merges, an agent
+	// using a custom OpenAI-compatible / Anthropic-compatible /
+	// remote-Ollama endpoint would be silently blocked by the egress
+	// enforcer at runtime.
+	egressDomains = append(egressDomains, security.LLMProviderDomains(cfg)...)
+	egressDomains = append(egressDomains, security.LLMProviderEnvDomains(envVars)...)
+	return security.Resolve(
+		cfg.Egress.Profile,
+		cfg.Egress.Mode,
+		egressDomains,
+		egressToolNames,
+		cfg.Egress.Capabilities,
+		cfg.Egress.AllowedPrivateCIDRs,
+		cfg.Egress.AllowedTCP,
+	)
+}
+
 func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.AgentExecutor, guardrails coreruntime.GuardrailChecker, egressClient *http.Client, auditLogger *coreruntime.AuditLogger) {
 	store := srv.TaskStore()
 
@@ -1560,6 +1832,11 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		if err := json.Unmarshal(rawParams, &params); err != nil {
 			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
 		}
+		scoped, err := r.scopeTaskID(ctx, params.ID)
+		if err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+		}
+		params.ID = scoped
 		// Validate the message shape per A2A 0.3.0 (issue #119). The
 		// most common failure is a client sending `"type": "text"`
 		// instead of `"kind": "text"` — encoding/json silently drops
@@ -1583,7 +1860,7 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		// when present.
 		task, snap, err := r.executeTask(ctx, params, store, executor, guardrails, egressClient, auditLogger)
 		if err != nil {
-			return a2a.NewErrorResponse(id, a2a.ErrCodeInternal, err.Error())
+			return a2a.NewTaskErrorResponse(id, a2a.ErrCodeInternal, coreruntime.ClassifyError(err))
 		}
 		// FWS-3 X-Forge-* response headers. The REST path at
 		// POST /tasks/send stamps directly on w.Header() because the
@@ -1606,6 +1883,12 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 			server.WriteSSEEvent(w, flusher, "error", a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())) //nolint:errcheck
 			return
 		}
+		scoped, err := r.scopeTaskID(ctx, params.ID)
+		if err != nil {
+			server.WriteSSEEvent(w, flusher, "error", a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())) //nolint:errcheck
+			return
+		}
+		params.ID = scoped
 		// A2A 0.3.0 message-shape validation (issue #119). Same
 		// rationale as the JSON-RPC tasks/send path: reject malformed
 		// requests at the entry point with a clear diagnostic instead
@@ -1622,6 +1905,20 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		}
 
 		r.logger.Info("tasks/sendSubscribe", map[string]any{"task_id": params.ID})
+		if compensate, ok := parseUndoCommand(params.Message); ok {
+			server.WriteSSEEvent(w, flusher, "result", r.undoFromChat(ctx, store, params.ID, compensate, auditLogger)) //nolint:errcheck
+			return
+		}
+		if task, ok := r.handoffIntercept(ctx, store, params); ok {
+			server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
+			return
+		}
+		unlock, err := r.cluster.lockTask(ctx, params.ID)
+		if err != nil {
+			server.WriteSSEEvent(w, flusher, "error", a2a.NewTaskErrorResponse(id, a2a.ErrCodeInternal, taskLockError(err))) //nolint:errcheck
+			return
+		}
+		defer unlock()
 
 		// Inject egress client, correlation/task IDs, and per-invocation
 		// usage accumulator (issue #87 / FWS-3) into context. The
@@ -1635,6 +1932,11 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		correlationID := coreruntime.CorrelationIDFromContext(ctx)
 		ctx = security.WithEgressClient(ctx, egressClient)
 		ctx = coreruntime.WithTaskID(ctx, params.ID)
+		ctx = llm.WithRequestMetadata(ctx, params.Metadata) // model.routes conditions
+		plan := r.dryRunPlan(params.Metadata)
+		if plan != nil {
+			ctx = coreruntime.WithDryRun(ctx, plan)
+		}
 		// FWS-8: per-invocation sequence counter so every audit event
 		// emitted on behalf of this request carries a monotonically
 		// increasing `seq` field — consumers detect gaps + ordering
@@ -1646,8 +1948,18 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		ctx = coreruntime.EnsureSequenceCounter(ctx)
 		sseAcc := coreruntime.NewLLMUsageAccumulator()
 		ctx = coreruntime.WithLLMUsageAccumulator(ctx, sseAcc)
+		var finalState a2a.TaskState
 		defer func() {
 			snap := sseAcc.Snapshot()
+			r.events.Publish(ctx, coreruntime.Event{
+				Topic:         coreruntime.TopicTaskFinished,
+				TaskID:        params.ID,
+				CorrelationID: correlationID,
+				Fields: map[string]any{
+					"state":       string(finalState),
+					"duration_ms": snap.InvocationDuration.Milliseconds(),
+				},
+			})
 			fields := map[string]any{}
 			if snap.LLMCallCount > 0 {
 				fields["input_tokens_total"] = snap.InputTokens
@@ -1666,10 +1978,18 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 			auditLogger.EmitInvocationComplete(ctx, snap.InvocationDuration, fields)
 		}()
 
+		ctx = r.enrollExperiment(ctx, params.ID, store.Get(params.ID), params.Metadata)
+
 		auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 			Event:         coreruntime.AuditSessionStart,
 			CorrelationID: correlationID,
 			TaskID:        params.ID,
+			Fields:        r.taskPromptFields(),
+		})
+		r.events.Publish(ctx, coreruntime.Event{
+			Topic:         coreruntime.TopicTaskStarted,
+			TaskID:        params.ID,
+			CorrelationID: correlationID,
 		})
 
 		// Load existing task to preserve conversation history, or create new.
@@ -1677,21 +1997,17 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		if task == nil {
 			task = &a2a.Task{ID: params.ID}
 		}
+		stampTaskCorrelation(ctx, task)
 		task.Status = a2a.TaskStatus{State: a2a.TaskStateSubmitted}
 		store.Put(task)
 		server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
 
 		// Guardrail check inbound
 		if _, err := guardrails.CheckInbound(ctx, &params.Message); err != nil {
-			task.Status = a2a.TaskStatus{
-				State: a2a.TaskStateFailed,
-				Message: &a2a.Message{
-					Role:  a2a.MessageRoleAgent,
-					Parts: []a2a.Part{a2a.NewTextPart("Guardrail violation: " + err.Error())},
-				},
-			}
+			failTask(task, a2a.NewTaskError(a2a.ErrorGuardrailViolation, r.localizer.Text(r.messageLanguage(&params.Message), "guardrail.inbound", err.Error())))
 			store.Put(task)
 			server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
+			finalState = a2a.TaskStateFailed
 			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 				Event:         coreruntime.AuditSessionEnd,
 				CorrelationID: correlationID,
@@ -1704,6 +2020,7 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		// R3 (#208): capture stated intent for the intent-alignment
 		// engine. No-op when the engine is disabled.
 		r.CaptureStatedIntent(ctx, params.ID, &params.Message)
+		r.storeUploads(ctx, params.ID, &params.Message)
 
 		// Append inbound user message to task history.
 		task.History = append(task.History, params.Message)
@@ -1715,35 +2032,16 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 
 		// Inject progress emitter for SSE clients
 		ctx = coreruntime.WithProgressEmitter(ctx, func(event coreruntime.ProgressEvent) {
-			progressTask := &a2a.Task{
-				ID: params.ID,
-				Status: a2a.TaskStatus{
-					State: a2a.TaskStateWorking,
-					Message: &a2a.Message{
-						Role:  a2a.MessageRoleAgent,
-						Parts: []a2a.Part{a2a.NewTextPart(event.Message)},
-					},
-				},
-				Metadata: map[string]any{
-					"progress_phase": event.Phase,
-					"progress_tool":  event.Tool,
-				},
-			}
-			server.WriteSSEEvent(w, flusher, "progress", progressTask) //nolint:errcheck
+			server.WriteSSEEvent(w, flusher, "progress", progressTask(params.ID, event)) //nolint:errcheck
 		})
 
 		// Stream from executor
 		ch, err := executor.ExecuteStream(ctx, task, &params.Message)
 		if err != nil {
-			task.Status = a2a.TaskStatus{
-				State: a2a.TaskStateFailed,
-				Message: &a2a.Message{
-					Role:  a2a.MessageRoleAgent,
-					Parts: []a2a.Part{a2a.NewTextPart(err.Error())},
-				},
-			}
+			failTask(task, coreruntime.ClassifyError(err))
 			store.Put(task)
 			server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
+			finalState = a2a.TaskStateFailed
 			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 				Event:         coreruntime.AuditSessionEnd,
 				CorrelationID: correlationID,
@@ -1753,17 +2051,19 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 			return
 		}
 
-		var finalState a2a.TaskState
 		for respMsg := range ch {
+			// A streaming executor reports failure as a final message
+			// carrying the classified error.
+			if te := a2a.TaskErrorFromMessage(respMsg); te != nil {
+				failTask(task, te)
+				store.Put(task)
+				server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
+				finalState = a2a.TaskStateFailed
+				break
+			}
 			// Guardrail check outbound
 			if _, grErr := guardrails.CheckOutbound(ctx, respMsg); grErr != nil {
-				task.Status = a2a.TaskStatus{
-					State: a2a.TaskStateFailed,
-					Message: &a2a.Message{
-						Role:  a2a.MessageRoleAgent,
-						Parts: []a2a.Part{a2a.NewTextPart("Outbound guardrail violation: " + grErr.Error())},
-					},
-				}
+				failTask(task, a2a.NewTaskError(a2a.ErrorGuardrailViolation, r.localizer.Text(r.messageLanguage(&params.Message), "guardrail.outbound", grErr.Error())))
 				store.Put(task)
 				server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
 				finalState = a2a.TaskStateFailed
@@ -1784,6 +2084,9 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 					Parts: respMsg.Parts,
 				},
 			}
+			if plan != nil {
+				task.Artifacts = append(task.Artifacts, plan.Artifact())
+			}
 			store.Put(task)
 			server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
 			finalState = a2a.TaskStateCompleted
@@ -1803,6 +2106,11 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		if err := json.Unmarshal(rawParams, &params); err != nil {
 			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
 		}
+		scoped, err := r.scopeTaskID(ctx, params.ID)
+		if err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+		}
+		params.ID = scoped
 
 		task := store.Get(params.ID)
 		if task == nil {
@@ -1818,11 +2126,16 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 	// that already completed (or was never started) returns the stored
 	// task without an error so the orchestrator can issue cancels
 	// optimistically. See issue #88 / FWS-4.
-	srv.RegisterHandler("tasks/cancel", func(_ context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
+	srv.RegisterHandler("tasks/cancel", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
 		var params a2a.CancelTaskParams
 		if err := json.Unmarshal(rawParams, &params); err != nil {
 			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
 		}
+		scoped, err := r.scopeTaskID(ctx, params.ID)
+		if err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+		}
+		params.ID = scoped
 
 		task := store.Get(params.ID)
 		if task == nil {
@@ -1846,6 +2159,66 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		// store has so the orchestrator reads the actual outcome.
 		return a2a.NewResponse(id, task)
 	})
+
+	// tasks/undo — roll the conversation back one exchange. Trims the
+	// task history and persisted session, marks side-effecting tool
+	// calls from that exchange as disavowed in the audit trail, and
+	// (with compensate=true) runs tool compensation hooks. The chat
+	// equivalent is the `/undo` command handled in executeTask. See
+	// undo.go.
+	srv.RegisterHandler("tasks/undo", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
+		var params a2a.UndoTaskParams
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
+		}
+		scoped, err := r.scopeTaskID(ctx, params.ID)
+		if err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+		}
+		params.ID = scoped
+		r.logger.Info("tasks/undo", map[string]any{"task_id": params.ID, "compensate": params.Compensate})
+
+		task, err := r.undoTask(ctx, store, params.ID, params.Compensate, auditLogger)
+		if err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+		}
+		return a2a.NewResponse(id, task)
+	})
+
+	// tasks/redactMessage and tasks/deleteMessage — remove one message
+	// (or a pasted secret within it) from the conversation. Edits the
+	// task history, the persisted session and its compaction summary,
+	// and records a message_redacted tombstone. See redact.go.
+	for method, del := range map[string]bool{"tasks/redactMessage": false, "tasks/deleteMessage": true} {
+		srv.RegisterHandler(method, func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
+			var params a2a.EditMessageParams
+			if err := json.Unmarshal(rawParams, &params); err != nil {
+				return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
+			}
+			scoped, err := r.scopeTaskID(ctx, params.ID)
+			if err != nil {
+				return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+			}
+			params.ID = scoped
+			r.logger.Info(method, map[string]any{"task_id": params.ID, "message_index": params.Index})
+
+			task, err := r.editMessage(ctx, store, params, del, auditLogger)
+			if err != nil {
+				return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+			}
+			return a2a.NewResponse(id, task)
+		})
+	}
+}
+
+// dryRunPlan returns the plan a task's tool calls are recorded in when it
+// is a dry run — `forge run --dry-run`, or "dry_run": true in the
+// request metadata — and nil when its tools run for real.
+func (r *Runner) dryRunPlan(metadata map[string]any) *coreruntime.Plan {
+	if requested, _ := metadata["dry_run"].(bool); r.cfg.DryRun || requested {
+		return coreruntime.NewPlan()
+	}
+	return nil
 }
 
 // executeTask is the shared task execution pipeline used by both JSON-RPC and REST handlers.
@@ -1858,12 +2231,36 @@ func (r *Runner) executeTask(
 	egressClient *http.Client,
 	auditLogger *coreruntime.AuditLogger,
 ) (*a2a.Task, coreruntime.LLMUsageSnapshot, error) {
+	// `/undo` chat command: roll back the previous exchange instead of
+	// running the agent. No LLM call, so the usage snapshot is empty.
+	if compensate, ok := parseUndoCommand(params.Message); ok {
+		return r.undoFromChat(ctx, store, params.ID, compensate, auditLogger), coreruntime.LLMUsageSnapshot{}, nil
+	}
+	// A conversation handed to human operators: forward the message to
+	// them, or hand back on /resume-bot, without running the agent.
+	if task, ok := r.handoffIntercept(ctx, store, params); ok {
+		return task, coreruntime.LLMUsageSnapshot{}, nil
+	}
+
+	// One replica at a time per task (cluster.lock_url): a second
+	// replica appending to the same session would lose one side's turn.
+	unlock, err := r.cluster.lockTask(ctx, params.ID)
+	if err != nil {
+		return nil, coreruntime.LLMUsageSnapshot{}, err
+	}
+	defer unlock()
+
 	// Adopt the ingress-minted correlation ID so task events share the
 	// invocation id auth_verify already carries (#278); generate if absent.
 	ctx = coreruntime.EnsureCorrelationID(ctx)
 	correlationID := coreruntime.CorrelationIDFromContext(ctx)
 	ctx = security.WithEgressClient(ctx, egressClient)
 	ctx = coreruntime.WithTaskID(ctx, params.ID)
+	ctx = llm.WithRequestMetadata(ctx, params.Metadata) // model.routes conditions
+	plan := r.dryRunPlan(params.Metadata)
+	if plan != nil {
+		ctx = coreruntime.WithDryRun(ctx, plan)
+	}
 	// FWS-8: per-invocation sequence counter (see issue #91 / FWS-8).
 	// EnsureSequenceCounter reuses the counter the auth middleware
 	// wrapper installed pre-auth so auth_verify lands seq=1 and
@@ -1886,16 +2283,25 @@ func (r *Runner) executeTask(
 	defer release()
 	defer cancelInvocation(nil) // nil cause = clean completion; no-op when already cancelled
 
+	ctx = r.enrollExperiment(ctx, params.ID, store.Get(params.ID), params.Metadata)
+
 	auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 		Event:         coreruntime.AuditSessionStart,
 		CorrelationID: correlationID,
 		TaskID:        params.ID,
+		Fields:        r.taskPromptFields(),
+	})
+	r.events.Publish(ctx, coreruntime.Event{
+		Topic:         coreruntime.TopicTaskStarted,
+		TaskID:        params.ID,
+		CorrelationID: correlationID,
 	})
 
 	task := store.Get(params.ID)
 	if task == nil {
 		task = &a2a.Task{ID: params.ID}
 	}
+	stampTaskCorrelation(ctx, task)
 	task.Status = a2a.TaskStatus{State: a2a.TaskStateSubmitted}
 	store.Put(task)
 
@@ -1922,6 +2328,14 @@ func (r *Runner) executeTask(
 		}
 		// Per-invocation compression savings — see appendCompressionFields.
 		r.appendCompressionFields(ctx, fields)
+		finished := maps.Clone(fields)
+		finished["duration_ms"] = snap.InvocationDuration.Milliseconds()
+		r.events.Publish(ctx, coreruntime.Event{
+			Topic:         coreruntime.TopicTaskFinished,
+			TaskID:        params.ID,
+			CorrelationID: correlationID,
+			Fields:        finished,
+		})
 		if task.Status.State == a2a.TaskStateCanceled {
 			auditLogger.EmitInvocationCancelled(ctx,
 				coreruntime.CancellationReasonFromCause(ctx),
@@ -1932,13 +2346,7 @@ func (r *Runner) executeTask(
 	}
 
 	if _, err := guardrails.CheckInbound(ctx, &params.Message); err != nil {
-		task.Status = a2a.TaskStatus{
-			State: a2a.TaskStateFailed,
-			Message: &a2a.Message{
-				Role:  a2a.MessageRoleAgent,
-				Parts: []a2a.Part{a2a.NewTextPart("Guardrail violation: " + err.Error())},
-			},
-		}
+		failTask(task, a2a.NewTaskError(a2a.ErrorGuardrailViolation, r.localizer.Text(r.messageLanguage(&params.Message), "guardrail.inbound", err.Error())))
 		store.Put(task)
 		auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 			Event:         coreruntime.AuditSessionEnd,
@@ -1953,6 +2361,7 @@ func (r *Runner) executeTask(
 	// R3 (#208): capture stated intent for the intent-alignment
 	// engine. No-op when the engine is disabled.
 	r.CaptureStatedIntent(ctx, params.ID, &params.Message)
+	r.storeUploads(ctx, params.ID, &params.Message)
 
 	task.History = append(task.History, params.Message)
 	task.Status = a2a.TaskStatus{State: a2a.TaskStateWorking}
@@ -1987,13 +2396,7 @@ func (r *Runner) executeTask(
 			return task, acc.Snapshot(), nil
 		}
 		r.logger.Error("execute failed", map[string]any{"task_id": params.ID, "error": err.Error()})
-		task.Status = a2a.TaskStatus{
-			State: a2a.TaskStateFailed,
-			Message: &a2a.Message{
-				Role:  a2a.MessageRoleAgent,
-				Parts: []a2a.Part{a2a.NewTextPart(err.Error())},
-			},
-		}
+		failTask(task, coreruntime.ClassifyError(err))
 		store.Put(task)
 		auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 			Event:         coreruntime.AuditSessionEnd,
@@ -2018,13 +2421,7 @@ func (r *Runner) executeTask(
 
 	if respMsg != nil {
 		if _, err := guardrails.CheckOutbound(ctx, respMsg); err != nil {
-			task.Status = a2a.TaskStatus{
-				State: a2a.TaskStateFailed,
-				Message: &a2a.Message{
-					Role:  a2a.MessageRoleAgent,
-					Parts: []a2a.Part{a2a.NewTextPart("Outbound guardrail violation: " + err.Error())},
-				},
-			}
+			failTask(task, a2a.NewTaskError(a2a.ErrorGuardrailViolation, r.localizer.Text(r.messageLanguage(&params.Message), "guardrail.outbound", err.Error())))
 			store.Put(task)
 			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
 				Event:         coreruntime.AuditSessionEnd,
@@ -2052,6 +2449,9 @@ func (r *Runner) executeTask(
 				Parts: respMsg.Parts,
 			},
 		}
+		if plan != nil {
+			task.Artifacts = append(task.Artifacts, plan.Artifact())
+		}
 	}
 	store.Put(task)
 	auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
@@ -2068,8 +2468,9 @@ func (r *Runner) executeTask(
 // restTaskRequest is the simplified JSON body for REST task endpoints.
 type restTaskRequest struct {
 	Task struct {
-		ID      string      `json:"id"`
-		Message a2a.Message `json:"message"`
+		ID       string         `json:"id"`
+		Message  a2a.Message    `json:"message"`
+		Metadata map[string]any `json:"metadata,omitempty"`
 	} `json:"task"`
 }
 
@@ -2081,16 +2482,29 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 	srv.RegisterHTTPHandler("POST /tasks/send", func(w http.ResponseWriter, req *http.Request) {
 		var body restTaskRequest
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
 			return
 		}
 		if body.Task.ID == "" {
 			body.Task.ID = coreruntime.GenerateID()
 		}
+		scoped, err := r.scopeTaskID(req.Context(), body.Task.ID)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		body.Task.ID = scoped
 
 		params := a2a.SendTaskParams{
-			ID:      body.Task.ID,
-			Message: body.Task.Message,
+			ID:       body.Task.ID,
+			Message:  body.Task.Message,
+			Metadata: body.Task.Metadata,
+		}
+		if server.WriteLimitExceededOnError(w, srv.CheckTaskLimits(params)) {
+			return
 		}
 		// A2A 0.3.0 message-shape validation (issue #119). Catches the
 		// pre-0.3.0 `type` vs `kind` discriminator mismatch + missing
@@ -2106,15 +2520,13 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 			return
 		}
 
-		// Pull workflow correlation headers (issue #86 / FWS-2) so audit
-		// events tagged via EmitFromContext carry the orchestrator's
-		// workflow/stage/step identifiers. Absent headers → IsZero
-		// WorkflowContext → fields omitted (backward compat).
-		ctx := coreruntime.WithWorkflowContext(req.Context(),
-			coreruntime.WorkflowContextFromHTTPHeaders(req.Header))
-		// Same for tenancy override headers (#157).
-		ctx = coreruntime.WithTenancyContext(ctx,
-			coreruntime.TenancyContextFromHTTPHeaders(req.Header))
+		// Pull the traceparent and the workflow (issue #86 / FWS-2),
+		// tenancy (#157) and channel correlation headers so the run
+		// nests under the caller's trace and audit events tagged via
+		// EmitFromContext carry the caller's identifiers. Absent
+		// headers → fields omitted (backward compat).
+		ctx, span := restRequestContext(req, "tasks/send")
+		defer span.End()
 		task, snap, err := r.executeTask(ctx, params, store, executor, guardrails, egressClient, auditLogger)
 		if err != nil {
 			// R4b: a step-up-required error takes priority — we
@@ -2123,7 +2535,7 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 			if WriteStepUpChallengeOnError(w, err) {
 				return
 			}
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			writeTaskError(w, taskLockStatus(err), taskLockError(err))
 			return
 		}
 		applyForgeUsageHeaders(w.Header(), snap)
@@ -2140,12 +2552,21 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 
 		var body restTaskRequest
 		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body: " + err.Error()})
 			return
 		}
 		if body.Task.ID == "" {
 			body.Task.ID = coreruntime.GenerateID()
 		}
+		scoped, err := r.scopeTaskID(req.Context(), body.Task.ID)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		body.Task.ID = scoped
 		// A2A 0.3.0 message-shape validation (issue #119). Reject before
 		// we commit SSE response headers — once Content-Type is set
 		// to text/event-stream the client expects a stream, not a 400.
@@ -2158,22 +2579,50 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid message: " + err.Error()})
 			return
 		}
+		if server.WriteLimitExceededOnError(w, srv.CheckTaskLimits(a2a.SendTaskParams{ID: body.Task.ID, Message: body.Task.Message})) {
+			return
+		}
+		// Take the task lock before committing to a stream so a task
+		// held by another replica gets a plain 409 the client can retry.
+		unlock, err := r.cluster.lockTask(req.Context(), body.Task.ID)
+		if err != nil {
+			writeTaskError(w, taskLockStatus(err), taskLockError(err))
+			return
+		}
+		defer unlock()
 
 		w.Header().Set("Content-Type", "text/event-stream")
 		w.Header().Set("Cache-Control", "no-cache")
 		w.Header().Set("Connection", "keep-alive")
 
 		params := a2a.SendTaskParams{
-			ID:      body.Task.ID,
-			Message: body.Task.Message,
+			ID:       body.Task.ID,
+			Message:  body.Task.Message,
+			Metadata: body.Task.Metadata,
+		}
+		// Traceparent + workflow / tenancy / channel headers; see
+		// POST /tasks/send above.
+		ctx, span := restRequestContext(req, "tasks/sendSubscribe")
+		defer span.End()
+		if compensate, ok := parseUndoCommand(params.Message); ok {
+			server.WriteSSEEvent(w, flusher, "result", r.undoFromChat(ctx, store, params.ID, compensate, auditLogger)) //nolint:errcheck
+			return
+		}
+		if task, ok := r.handoffIntercept(ctx, store, params); ok {
+			server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
+			return
 		}
 
 		// Adopt the ingress-minted correlation ID so task events share the
 		// invocation id auth_verify already carries (#278); generate if absent.
-		ctx := coreruntime.EnsureCorrelationID(req.Context())
+		ctx = coreruntime.EnsureCorrelationID(ctx)
 		correlationID := coreruntime.CorrelationIDFromContext(ctx)
 		ctx = security.WithEgressClient(ctx, egressClient)
 		ctx = coreruntime.WithTaskID(ctx, params.ID)
+		plan := r.dryRunPlan(params.Metadata)
+		if plan != nil {
+			ctx = coreruntime.WithDryRun(ctx, plan)
+		}
 		// FWS-8: per-invocation sequence counter so every audit event
 		// emitted on behalf of this request carries a monotonically
 		// increasing `seq` field — consumers detect gaps + ordering
@@ -2181,14 +2630,6 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 		// installSequenceCounterMiddleware put on ctx before auth ran
 		// (#174); install fresh on the --no-auth path.
 		ctx = coreruntime.EnsureSequenceCounter(ctx)
-		// Pull workflow correlation headers (issue #86 / FWS-2) before
-		// the accumulator setup so invocation_complete inherits workflow
-		// tagging via EmitFromContext.
-		ctx = coreruntime.WithWorkflowContext(ctx,
-			coreruntime.WorkflowContextFromHTTPHeaders(req.Header))
-		// Same for tenancy override headers (#157).
-		ctx = coreruntime.WithTenancyContext(ctx,
-			coreruntime.TenancyContextFromHTTPHeaders(req.Header))
 		// Per-invocation usage accumulator + invocation_complete on exit.
 		// See issue #87 / FWS-3.
 		restSSEAcc := coreruntime.NewLLMUsageAccumulator()
@@ -2217,24 +2658,20 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 			Event:         coreruntime.AuditSessionStart,
 			CorrelationID: correlationID,
 			TaskID:        params.ID,
+			Fields:        r.taskPromptFields(),
 		})
 
 		task := store.Get(params.ID)
 		if task == nil {
 			task = &a2a.Task{ID: params.ID}
 		}
+		stampTaskCorrelation(ctx, task)
 		task.Status = a2a.TaskStatus{State: a2a.TaskStateSubmitted}
 		store.Put(task)
 		server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
 
 		if _, err := guardrails.CheckInbound(ctx, &params.Message); err != nil {
-			task.Status = a2a.TaskStatus{
-				State: a2a.TaskStateFailed,
-				Message: &a2a.Message{
-					Role:  a2a.MessageRoleAgent,
-					Parts: []a2a.Part{a2a.NewTextPart("Guardrail violation: " + err.Error())},
-				},
-			}
+			failTask(task, a2a.NewTaskError(a2a.ErrorGuardrailViolation, r.localizer.Text(r.messageLanguage(&params.Message), "guardrail.inbound", err.Error())))
 			store.Put(task)
 			server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
 			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
@@ -2249,6 +2686,7 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 		// R3 (#208): capture stated intent for the intent-alignment
 		// engine. No-op when the engine is disabled.
 		r.CaptureStatedIntent(ctx, params.ID, &params.Message)
+		r.storeUploads(ctx, params.ID, &params.Message)
 
 		task.History = append(task.History, params.Message)
 		task.Status = a2a.TaskStatus{State: a2a.TaskStateWorking}
@@ -2256,32 +2694,12 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 		server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
 
 		ctx = coreruntime.WithProgressEmitter(ctx, func(event coreruntime.ProgressEvent) {
-			progressTask := &a2a.Task{
-				ID: params.ID,
-				Status: a2a.TaskStatus{
-					State: a2a.TaskStateWorking,
-					Message: &a2a.Message{
-						Role:  a2a.MessageRoleAgent,
-						Parts: []a2a.Part{a2a.NewTextPart(event.Message)},
-					},
-				},
-				Metadata: map[string]any{
-					"progress_phase": event.Phase,
-					"progress_tool":  event.Tool,
-				},
-			}
-			server.WriteSSEEvent(w, flusher, "progress", progressTask) //nolint:errcheck
+			server.WriteSSEEvent(w, flusher, "progress", progressTask(params.ID, event)) //nolint:errcheck
 		})
 
 		ch, err := executor.ExecuteStream(ctx, task, &params.Message)
 		if err != nil {
-			task.Status = a2a.TaskStatus{
-				State: a2a.TaskStateFailed,
-				Message: &a2a.Message{
-					Role:  a2a.MessageRoleAgent,
-					Parts: []a2a.Part{a2a.NewTextPart(err.Error())},
-				},
-			}
+			failTask(task, coreruntime.ClassifyError(err))
 			store.Put(task)
 			server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
 			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
@@ -2295,14 +2713,17 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 
 		var finalState a2a.TaskState
 		for respMsg := range ch {
+			// A streaming executor reports failure as a final message
+			// carrying the classified error.
+			if te := a2a.TaskErrorFromMessage(respMsg); te != nil {
+				failTask(task, te)
+				store.Put(task)
+				server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
+				finalState = a2a.TaskStateFailed
+				break
+			}
 			if _, grErr := guardrails.CheckOutbound(ctx, respMsg); grErr != nil {
-				task.Status = a2a.TaskStatus{
-					State: a2a.TaskStateFailed,
-					Message: &a2a.Message{
-						Role:  a2a.MessageRoleAgent,
-						Parts: []a2a.Part{a2a.NewTextPart("Outbound guardrail violation: " + grErr.Error())},
-					},
-				}
+				failTask(task, a2a.NewTaskError(a2a.ErrorGuardrailViolation, r.localizer.Text(r.messageLanguage(&params.Message), "guardrail.outbound", grErr.Error())))
 				store.Put(task)
 				server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
 				finalState = a2a.TaskStateFailed
@@ -2320,6 +2741,9 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 					Parts: respMsg.Parts,
 				},
 			}
+			if plan != nil {
+				task.Artifacts = append(task.Artifacts, plan.Artifact())
+			}
 			store.Put(task)
 			server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
 			finalState = a2a.TaskStateCompleted
@@ -2333,13 +2757,24 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 		})
 	})
 
-	// GET /health — health check with uptime
+	// GET /health — health check with uptime. Reports 503 while the
+	// server drains so a Kubernetes readiness probe on it takes the pod
+	// out of the Service before in-flight tasks finish.
 	srv.RegisterHTTPHandler("GET /health", func(w http.ResponseWriter, req *http.Request) {
 		uptime := time.Since(r.startTime).Seconds()
-		writeJSON(w, http.StatusOK, map[string]any{
+		health := map[string]any{
 			"status":         "ok",
 			"uptime_seconds": int(uptime),
-		})
+		}
+		if _, chain := r.currentModel(); chain != nil {
+			health["llm_candidates"] = chain.Health()
+		}
+		status := http.StatusOK
+		if srv.Draining() {
+			health["status"] = "draining"
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, health)
 	})
 
 	// GET /info — agent metadata
@@ -2348,8 +2783,8 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 			"agent_id": r.cfg.Config.AgentID,
 			"version":  r.cfg.Config.Version,
 		}
-		if r.modelConfig != nil {
-			info["model"] = r.modelConfig.Provider + "/" + r.modelConfig.Client.Model
+		if mc, _ := r.currentModel(); mc != nil {
+			info["model"] = mc.Provider + "/" + mc.Client.Model
 		}
 
 		// Skills
@@ -2384,6 +2819,8 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 			info["channels"] = r.cfg.Channels
 		}
 
+		info["usage"] = r.usageSnapshot()
+
 		writeJSON(w, http.StatusOK, info)
 	})
 
@@ -2403,10 +2840,38 @@ func (r *Runner) registerRESTHandlers(srv *server.Server, executor coreruntime.A
 	// auth-required gate. No-op wire when no type=user MCP server is active.
 	r.registerMCPConsentEndpoint(srv, auditLogger)
 
-	// R10 (#330) — standalone loopback OAuth callback. Registered only when
-	// a CallbackCompleter is set (standalone interactive mode); managed
-	// deployments host their own callback and skip this.
-	r.registerMCPCallbackEndpoint(srv, auditLogger)
+	// R10 (#330) — standalone loopback OAuth callback. Registered only when
+	// a CallbackCompleter is set (standalone interactive mode); managed
+	// deployments host their own callback and skip this.
+	r.registerMCPCallbackEndpoint(srv, auditLogger)
+
+	// Runtime ops-log controls: per-subsystem levels and sampling.
+	r.registerLoggingEndpoint(srv, auditLogger)
+
+	// Live model switching for provider incidents.
+	r.registerModelSwitchEndpoint(srv)
+	r.registerCompactEndpoint(srv)
+
+	// Natural-language schedule parsing for UIs and channels, ahead
+	// of committing a schedule.
+	r.registerScheduleParseEndpoint(srv)
+
+	// Proactive messages to forge.yaml notify targets. No-op wire when
+	// none are declared.
+	r.registerNotifyEndpoint(srv)
+
+	// Conversations handed to human operators. No-op wire without a
+	// handoff channel.
+	r.registerHandoffEndpoints(srv)
+
+	// LLM response cache counters. No-op wire when the cache is off.
+	r.registerResponseCacheEndpoint(srv)
+
+	// Provider connection-pool counters.
+	r.registerLLMTransportEndpoint(srv)
+
+	// Subprocess egress proxy counters. No-op wire without the proxy.
+	r.registerEgressProxyEndpoint(srv)
 }
 
 // serveJWKS is the handler for /.well-known/forge-audit-keys. Split
@@ -2435,74 +2900,6 @@ func (r *Runner) loadToolSpecs() []agentspec.ToolSpec {
 	return toolSpecs
 }
 
-// registerLoggingHooks adds observability hooks to the LLM executor's agent loop.
-func (r *Runner) registerLoggingHooks(hooks *coreruntime.HookRegistry) {
-	hooks.Register(coreruntime.AfterLLMCall, func(_ context.Context, hctx *coreruntime.HookContext) error {
-		if hctx.Response == nil {
-			return nil
-		}
-		fields := map[string]any{
-			"finish_reason": hctx.Response.FinishReason,
-		}
-		if hctx.Response.Usage.TotalTokens > 0 {
-			fields["tokens"] = hctx.Response.Usage.TotalTokens
-		}
-		if len(hctx.Response.Message.ToolCalls) > 0 {
-			names := make([]string, len(hctx.Response.Message.ToolCalls))
-			for i, tc := range hctx.Response.Message.ToolCalls {
-				names[i] = tc.Function.Name
-			}
-			fields["tool_calls"] = names
-		}
-		if hctx.Response.Message.Content != "" {
-			content := hctx.Response.Message.Content
-			if len(content) > 200 {
-				content = content[:200] + "..."
-			}
-			fields["response"] = content
-		}
-		r.logger.Info("llm response", fields)
-		return nil
-	})
-
-	hooks.Register(coreruntime.BeforeToolExec, func(_ context.Context, hctx *coreruntime.HookContext) error {
-		fields := map[string]any{"tool": hctx.ToolName}
-		if hctx.ToolInput != "" {
-			input := hctx.ToolInput
-			if len(input) > 300 {
-				input = input[:300] + "..."
-			}
-			fields["input"] = input
-		}
-		r.logger.Info("tool call", fields)
-		return nil
-	})
-
-	hooks.Register(coreruntime.AfterToolExec, func(_ context.Context, hctx *coreruntime.HookContext) error {
-		fields := map[string]any{"tool": hctx.ToolName}
-		if hctx.Error != nil {
-			fields["error"] = hctx.Error.Error()
-			r.logger.Error("tool error", fields)
-		} else {
-			output := hctx.ToolOutput
-			if len(output) > 500 {
-				output = output[:500] + "..."
-			}
-			fields["output_length"] = len(hctx.ToolOutput)
-			fields["output"] = output
-			r.logger.Info("tool result", fields)
-		}
-		return nil
-	})
-
-	hooks.Register(coreruntime.OnError, func(_ context.Context, hctx *coreruntime.HookContext) error {
-		if hctx.Error != nil {
-			r.logger.Error("agent loop error", map[string]any{"error": hctx.Error.Error()})
-		}
-		return nil
-	})
-}
-
 // registerAuditHooks adds structured audit event hooks to the LLM executor's agent loop.
 // The default audit posture is metadata-only — token counts, sizes,
 // durations, tool names, no raw bytes. r.cfg.AuditPayloadCapture
@@ -2515,6 +2912,10 @@ func (r *Runner) registerAuditHooks(hooks *coreruntime.HookRegistry, auditLogger
 
 	hooks.Register(coreruntime.BeforeToolExec, func(ctxStart context.Context, hctx *coreruntime.HookContext) error {
 		fields := map[string]any{"tool": hctx.ToolName, "phase": "start"}
+		if hctx.ToolCallID != "" {
+			// Join key for tool_disavowed events emitted by tasks/undo.
+			fields["tool_call_id"] = hctx.ToolCallID
+		}
 		// FWS-8: opt-in raw tool args. We only emit them here at the
 		// start hook (the end hook has them too — duplicating would
 		// double the audit footprint). args_size always lands; args
@@ -2542,6 +2943,9 @@ func (r *Runner) registerAuditHooks(hooks *coreruntime.HookRegistry, auditLogger
 
 	hooks.Register(coreruntime.AfterToolExec, func(ctxEnd context.Context, hctx *coreruntime.HookContext) error {
 		fields := map[string]any{"tool": hctx.ToolName, "phase": "end"}
+		if hctx.ToolCallID != "" {
+			fields["tool_call_id"] = hctx.ToolCallID
+		}
 		if hctx.Error != nil {
 			fields["error"] = hctx.Error.Error()
 		}
@@ -2564,6 +2968,59 @@ func (r *Runner) registerAuditHooks(hooks *coreruntime.HookRegistry, auditLogger
 			DurationMs:    &ms,
 			Fields:        fields,
 		})
+		// A path outside a tool's sandbox gets its own event, so
+		// traversal attempts can be alerted on without parsing errors.
+		var pv *tools.PathViolation
+		if errors.As(hctx.Error, &pv) {
+			auditLogger.EmitFromContext(ctxEnd, coreruntime.AuditEvent{
+				Event:         coreruntime.AuditSandboxViolation,
+				CorrelationID: hctx.CorrelationID,
+				TaskID:        hctx.TaskID,
+				Fields: map[string]any{
+					"tool":  hctx.ToolName,
+					"path":  pv.Path,
+					"root":  pv.Root,
+					"scope": pv.Scope,
+				},
+			})
+		}
+		return nil
+	})
+
+	// A task stopped as stuck is audited with what it kept repeating.
+	hooks.Register(coreruntime.OnError, func(ctx context.Context, hctx *coreruntime.HookContext) error {
+		var loop *coreruntime.LoopDetectedError
+		if errors.As(hctx.Error, &loop) {
+			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
+				Event:         coreruntime.AuditLoopDetected,
+				CorrelationID: hctx.CorrelationID,
+				TaskID:        hctx.TaskID,
+				Fields:        loop.ErrorDetails(),
+			})
+		}
+		return nil
+	})
+
+	// An answer a skill's output contract rejected is a blocked output,
+	// audited like any other guardrail decision.
+	hooks.Register(coreruntime.OnError, func(ctx context.Context, hctx *coreruntime.HookContext) error {
+		var oc *coreruntime.OutputContractError
+		if errors.As(hctx.Error, &oc) {
+			auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
+				Event:         coreruntime.AuditGuardrail,
+				CorrelationID: hctx.CorrelationID,
+				TaskID:        hctx.TaskID,
+				Fields: map[string]any{
+					"gate":            "output",
+					"decision":        "blocked",
+					"source":          "skill",
+					"guardrail":       "output_contract",
+					"tools":           oc.Tools,
+					"violations":      oc.Violations,
+					"violation_count": len(oc.Violations),
+				},
+			})
+		}
 		return nil
 	})
 
@@ -2592,6 +3049,7 @@ func (r *Runner) registerAuditHooks(hooks *coreruntime.HookRegistry, auditLogger
 			usage.InputTokens = hctx.Response.Usage.InputTokens
 			usage.OutputTokens = hctx.Response.Usage.OutputTokens
 			usage.TotalTokens = hctx.Response.Usage.TotalTokens
+			usage.ReasoningTokens = hctx.Response.Usage.ReasoningTokens
 			requestID = hctx.Response.ID
 		}
 		// FWS-8 payload-capture surfaces. Fields stays nil in the
@@ -2605,6 +3063,22 @@ func (r *Runner) registerAuditHooks(hooks *coreruntime.HookRegistry, auditLogger
 		if hctx.Response != nil && hctx.Response.Endpoint != "" {
 			fields = map[string]any{"url": hctx.Response.Endpoint}
 		}
+		// A model.routes rule answered: attribute the call (and its
+		// tokens) to the routed model, not the configured primary.
+		model, provider := hctx.Model, hctx.Provider
+		if hctx.Response != nil && hctx.Response.Route != nil {
+			model, provider = hctx.Response.Route.Model, hctx.Response.Route.Provider
+			if fields == nil {
+				fields = map[string]any{}
+			}
+			fields["route"] = hctx.Response.Route.ID
+		}
+		if hctx.Response != nil && hctx.Response.Cached {
+			if fields == nil {
+				fields = map[string]any{}
+			}
+			fields["cache"] = "hit"
+		}
 		if capture.LLMMessages && len(hctx.Messages) > 0 {
 			if fields == nil {
 				fields = map[string]any{}
@@ -2622,8 +3096,8 @@ func (r *Runner) registerAuditHooks(hooks *coreruntime.HookRegistry, auditLogger
 				capture.Redact, coreruntime.CapOrDefault(capture.CapLLMResponseBytes))
 		}
 		auditLogger.EmitLLMCall(ctx, coreruntime.LLMCallAuditArgs{
-			Model:     hctx.Model,
-			Provider:  hctx.Provider,
+			Model:     model,
+			Provider:  provider,
 			RequestID: requestID,
 			Usage:     usage,
 			Duration:  hctx.LLMCallDuration,
@@ -2633,7 +3107,7 @@ func (r *Runner) registerAuditHooks(hooks *coreruntime.HookRegistry, auditLogger
 		// can populate X-Forge-Tokens-In/Out + X-Forge-Duration-Ms +
 		// X-Forge-Model + X-Forge-Provider headers. See issue #87 / FWS-3.
 		if acc := coreruntime.LLMUsageAccumulatorFromContext(ctx); acc != nil {
-			acc.AddLLMCall(hctx.Model, hctx.Provider, usage, hctx.LLMCallDuration)
+			acc.AddLLMCall(model, provider, usage, hctx.LLMCallDuration)
 		}
 		return nil
 	})
@@ -2646,9 +3120,10 @@ func (r *Runner) registerProgressHooks(hooks *coreruntime.HookRegistry) {
 	hooks.Register(coreruntime.BeforeToolExec, func(ctx context.Context, hctx *coreruntime.HookContext) error {
 		if emitter := coreruntime.ProgressEmitterFromContext(ctx); emitter != nil {
 			emitter(coreruntime.ProgressEvent{
-				Phase:   "tool_start",
+				Phase:   coreruntime.ProgressToolStart,
 				Tool:    hctx.ToolName,
 				Message: fmt.Sprintf("Executing %s...", hctx.ToolName),
+				Context: hctx.ContextUsage,
 			})
 		}
 		return nil
@@ -2661,9 +3136,10 @@ func (r *Runner) registerProgressHooks(hooks *coreruntime.HookRegistry) {
 				msg = fmt.Sprintf("Failed %s: %s", hctx.ToolName, hctx.Error.Error())
 			}
 			emitter(coreruntime.ProgressEvent{
-				Phase:   "tool_end",
+				Phase:   coreruntime.ProgressToolEnd,
 				Tool:    hctx.ToolName,
 				Message: msg,
+				Context: hctx.ContextUsage,
 			})
 		}
 		return nil
@@ -2689,9 +3165,10 @@ func (r *Runner) registerProgressHooks(hooks *coreruntime.HookRegistry) {
 // outer A2A envelope.
 //
 // StreamGate has no auto-wire point — Forge's ExecuteStream is a
-// buffered wrapper around non-streaming Execute. The CheckStream
-// method is exposed for callers that consume llm.Client.ChatStream
-// directly. See issue #159.
+// buffered wrapper around non-streaming Execute. Callers that consume
+// llm.Client.ChatStream directly wrap the stream in
+// coreruntime.GuardStream, which checks it window by window. See
+// issue #159.
 func (r *Runner) registerGuardrailHooks(hooks *coreruntime.HookRegistry, guardrails coreruntime.GuardrailChecker) {
 	// ContextGate over system-role messages. Re-scans on every
 	// iteration — acceptable because system messages are small and
@@ -2797,6 +3274,180 @@ func toPlatformCommandSpecs(patterns []security.DeniedCommandPattern) []corerunt
 	return specs
 }
 
+// applyReadOnlyMode strips reg down for `mode: read-only` (see
+// tools.ApplyReadOnly). No-op for any other mode.
+func (r *Runner) applyReadOnlyMode(reg *tools.Registry) {
+	if !r.cfg.Config.IsReadOnly() {
+		return
+	}
+	removed := tools.ApplyReadOnly(reg, r.cfg.Config.ReadOnly.SafeCommands)
+	r.logger.Info("read-only mode", map[string]any{
+		"removed_tools": removed,
+		"safe_commands": r.cfg.Config.ReadOnly.SafeCommands,
+	})
+}
+
+// applyToolPolicies installs forge.yaml's tool_policies on reg, so each
+// task origin is offered and may call only its tools.
+func (r *Runner) applyToolPolicies(reg *tools.Registry) {
+	if len(r.cfg.Config.ToolPolicies) == 0 {
+		return
+	}
+	policies := make(map[string]tools.OriginPolicy, len(r.cfg.Config.ToolPolicies))
+	for origin, p := range r.cfg.Config.ToolPolicies {
+		policies[origin] = tools.OriginPolicy{Allow: p.Allow, Deny: p.Deny}
+	}
+	reg.SetOriginPolicies(policies)
+}
+
+// applySandbox confines forge.yaml's sandbox.tools to per-task scratch
+// directories under .forge/scratch/. No-op when no tool is sandboxed.
+func (r *Runner) applySandbox(reg *tools.Registry, execCfg *coreruntime.LLMExecutorConfig) {
+	sb := r.cfg.Config.Sandbox
+	if len(sb.Tools) == 0 {
+		return
+	}
+	reg.SetSandbox(sb.Tools)
+	execCfg.ScratchDir = filepath.Join(r.cfg.WorkDir, ".forge", "scratch")
+	execCfg.KeepScratch = sb.KeepScratch
+	r.logger.Info("tool sandbox", map[string]any{
+		"tools":        sb.Tools,
+		"keep_scratch": sb.KeepScratch,
+	})
+}
+
+// applyLoopLimits sets forge.yaml's loop limits on the executor,
+// defaults applied.
+func (r *Runner) applyLoopLimits(execCfg *coreruntime.LLMExecutorConfig) {
+	lc := r.cfg.Config.Loop
+	execCfg.MaxIterations = lc.MaxIterations
+	if execCfg.MaxIterations == 0 {
+		execCfg.MaxIterations = types.DefaultLoopMaxIterations
+	}
+	execCfg.LoopRepeatLimit = lc.RepeatLimit
+	if execCfg.LoopRepeatLimit == 0 {
+		execCfg.LoopRepeatLimit = types.DefaultLoopRepeatLimit
+	}
+	execCfg.LoopOscillationLimit = lc.OscillationLimit
+	if execCfg.LoopOscillationLimit == 0 {
+		execCfg.LoopOscillationLimit = types.DefaultLoopOscillationLimit
+	}
+}
+
+// applyExecutorMode sets up planner-executor mode when forge.yaml's
+// executor.mode is plan.
+func (r *Runner) applyExecutorMode(execCfg *coreruntime.LLMExecutorConfig) {
+	ec := r.cfg.Config.Executor
+	if ec.Mode != types.ExecutorModePlan {
+		return
+	}
+	execCfg.Planning = &coreruntime.PlanConfig{MaxSteps: ec.MaxSteps, MaxReplans: ec.MaxReplans}
+	r.logger.Info("executor mode: plan", map[string]any{"max_steps": ec.MaxSteps, "max_replans": ec.MaxReplans})
+}
+
+// applyRuntimeContext appends the runtime context block — current time,
+// agent, channels and forge.yaml facts — to the system prompt of every
+// task when runtime_context.enabled is set.
+func (r *Runner) applyRuntimeContext(execCfg *coreruntime.LLMExecutorConfig) {
+	rc := r.cfg.Config.RuntimeContext
+	if !rc.Enabled {
+		return
+	}
+	loc := time.Local
+	if rc.Timezone != "" {
+		l, err := time.LoadLocation(rc.Timezone)
+		if err != nil {
+			r.logger.Warn("runtime context: using the local timezone", map[string]any{
+				"timezone": rc.Timezone, "error": err.Error(),
+			})
+		} else {
+			loc = l
+		}
+	}
+	execCfg.RuntimeContext = &coreruntime.RuntimeContext{
+		Location: loc,
+		AgentID:  r.cfg.Config.AgentID,
+		Version:  r.cfg.Config.Version,
+		Channels: r.channels,
+		Facts:    rc.Facts,
+	}
+	r.logger.Info("runtime context enabled", map[string]any{"timezone": loc.String(), "facts": len(rc.Facts)})
+}
+
+// applyReflection turns on the reflection pass when forge.yaml enables it
+// or a skill declares `reflection: true`. The reviewer is
+// reflection.model on the primary provider, else the summary model, which
+// follows the model through reloads when reload is non-nil.
+func (r *Runner) applyReflection(execCfg *coreruntime.LLMExecutorConfig, mc *coreruntime.ModelConfig, reload *reloadTargets) {
+	rc := r.cfg.Config.Reflection
+	var skillTools []string
+	if r.derivedCLIConfig != nil {
+		skillTools = r.derivedCLIConfig.ReflectionTools
+	}
+	if !rc.Enabled && len(skillTools) == 0 {
+		return
+	}
+
+	var reviewer llm.Client
+	if rc.Model != "" {
+		cc := mc.Client
+		cc.Model = rc.Model
+		client, err := r.cachedProviderClient(mc.Provider, cc)
+		if err != nil {
+			r.logger.Warn("reviewing answers with the summary model", map[string]any{
+				"model": rc.Model, "error": err.Error(),
+			})
+		} else {
+			reviewer = client
+		}
+	}
+	if reviewer == nil {
+		switch {
+		case reload == nil:
+			reviewer = r.summaryClient(mc, execCfg.Client)
+		case reload.summary == nil:
+			reload.summary = newReloadableClient(r.summaryClient(mc, reload.client))
+			fallthrough
+		default:
+			reviewer = reload.summary
+		}
+	}
+	execCfg.Reflection = &coreruntime.ReflectionConfig{Client: reviewer, Always: rc.Enabled, Tools: skillTools}
+	r.logger.Info("reflection enabled", map[string]any{"all_tasks": rc.Enabled, "skill_tools": skillTools})
+}
+
+// applyOutputContracts holds the final answer of a task that called a
+// skill's tool to the output schema and max_length the skill declares.
+// A contract whose schema doesn't compile is skipped with a warning.
+func (r *Runner) applyOutputContracts(execCfg *coreruntime.LLMExecutorConfig) {
+	if r.derivedCLIConfig == nil || len(r.derivedCLIConfig.OutputContracts) == 0 {
+		return
+	}
+	contracts := make(map[string]*coreruntime.OutputContract, len(r.derivedCLIConfig.OutputContracts))
+	for tool, oc := range r.derivedCLIConfig.OutputContracts {
+		var schema json.RawMessage
+		if len(oc.Schema) > 0 {
+			b, err := json.Marshal(oc.Schema)
+			if err != nil {
+				r.logger.Warn("skipping skill output contract", map[string]any{"tool": tool, "error": err.Error()})
+				continue
+			}
+			schema = b
+		}
+		c, err := coreruntime.NewOutputContract(schema, oc.MaxLength)
+		if err != nil {
+			r.logger.Warn("skipping skill output contract", map[string]any{"tool": tool, "error": err.Error()})
+			continue
+		}
+		contracts[tool] = c
+	}
+	if len(contracts) == 0 {
+		return
+	}
+	execCfg.OutputContracts = contracts
+	r.logger.Info("skill output contracts enabled", map[string]any{"tools": slices.Sorted(maps.Keys(contracts))})
+}
+
 // registerPlatformCommandGuardHook wires the operator-authored command
 // denylist (#238) onto BeforeToolExec. It fires for EVERY tool call
 // regardless of the active skill. A match blocks the call AND emits a
@@ -2817,6 +3468,17 @@ func (r *Runner) registerPlatformCommandGuardHook(hooks *coreruntime.HookRegistr
 		if msg == "" {
 			msg = "command blocked by platform policy"
 		}
+		r.events.Publish(ctx, coreruntime.Event{
+			Topic:         coreruntime.TopicGuardrailHit,
+			TaskID:        hctx.TaskID,
+			CorrelationID: hctx.CorrelationID,
+			Fields: map[string]any{
+				"gate":      "tool_call",
+				"decision":  "blocked",
+				"guardrail": "platform_command_deny",
+				"tool":      hctx.ToolName,
+			},
+		})
 		if auditLogger != nil {
 			fields := map[string]any{
 				"gate":      "tool_call",
@@ -2838,14 +3500,72 @@ func (r *Runner) registerPlatformCommandGuardHook(hooks *coreruntime.HookRegistr
 				Fields: fields,
 			})
 		}
-		return fmt.Errorf("platform policy: %s", msg)
+		return coreruntime.WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("platform policy: %s", msg))
 	})
 }
 
 // buildLLMClient creates the LLM client from the resolved model config.
-// If fallback providers are configured, wraps them in a FallbackChain.
+// If fallback providers are configured, wraps them in a FallbackChain;
+// model.routes then wrap that chain in a RoutingClient. Each provider
+// client sits behind the response cache and provider capture when
+// they are enabled, and all of them share one HTTP transport.
 func (r *Runner) buildLLMClient(mc *coreruntime.ModelConfig) (llm.Client, error) {
-	primaryClient, err := r.createProviderClient(mc.Provider, mc.Client)
+	if err := r.initLLMTransport(mc); err != nil {
+		return nil, err
+	}
+	r.initResponseCache(mc)
+	r.initProviderCapture()
+	client, err := r.buildFallbackChain(mc)
+	if err != nil || len(mc.Routes) == 0 {
+		return client, err
+	}
+
+	var routes []llm.Route
+	for _, rt := range mc.Routes {
+		rtClient, rtErr := r.cachedProviderClient(rt.Provider, rt.Client)
+		if rtErr != nil {
+			r.logger.Warn("skipping model route", map[string]any{
+				"route": rt.ID, "provider": rt.Provider, "error": rtErr.Error(),
+			})
+			continue
+		}
+		routes = append(routes, llm.Route{
+			ID:       rt.ID,
+			When:     rt.When,
+			Provider: rt.Provider,
+			Model:    rt.Client.Model,
+			Client:   rtClient,
+			Pinned:   rt.Pinned,
+		})
+	}
+	if len(routes) == 0 {
+		return client, nil
+	}
+	return llm.NewRoutingClient(client, routes), nil
+}
+
+// summaryClient returns the client that summarizes tool results past
+// memory.high_water_mark: the first fallback model, usually the cheaper
+// one, or primary when there is none or it cannot be built.
+func (r *Runner) summaryClient(mc *coreruntime.ModelConfig, primary llm.Client) llm.Client {
+	if len(mc.Fallbacks) == 0 {
+		return primary
+	}
+	fb := mc.Fallbacks[0]
+	client, err := r.cachedProviderClient(fb.Provider, fb.Client)
+	if err != nil {
+		r.logger.Warn("summarizing tool results with the primary model", map[string]any{
+			"provider": fb.Provider, "error": err.Error(),
+		})
+		return primary
+	}
+	return client
+}
+
+// buildFallbackChain creates the primary client, wrapped in a
+// FallbackChain when fallback providers are configured.
+func (r *Runner) buildFallbackChain(mc *coreruntime.ModelConfig) (llm.Client, error) {
+	primaryClient, err := r.cachedProviderClient(mc.Provider, mc.Client)
 	if err != nil {
 		return nil, err
 	}
@@ -2860,7 +3580,7 @@ func (r *Runner) buildLLMClient(mc *coreruntime.ModelConfig) (llm.Client, error)
 		{Provider: mc.Provider, Model: mc.Client.Model, Client: primaryClient},
 	}
 	for _, fb := range mc.Fallbacks {
-		fbClient, fbErr := r.createProviderClient(fb.Provider, fb.Client)
+		fbClient, fbErr := r.cachedProviderClient(fb.Provider, fb.Client)
 		if fbErr != nil {
 			r.logger.Warn("skipping fallback provider", map[string]any{
 				"provider": fb.Provider, "error": fbErr.Error(),
@@ -2874,7 +3594,9 @@ func (r *Runner) buildLLMClient(mc *coreruntime.ModelConfig) (llm.Client, error)
 		})
 	}
 
-	return llm.NewFallbackChain(candidates), nil
+	chain := llm.NewFallbackChain(candidates)
+	r.fallbackChain = chain
+	return chain, nil
 }
 
 // createProviderClient creates an LLM client for a provider, using OAuth
@@ -2960,7 +3682,18 @@ func (r *Runner) printBanner(proxyURL string) {
 	if r.cfg.Host != "" {
 		title = "Forge Server"
 	}
-	host := defaultStr(r.cfg.Host, "0.0.0.0")
+	listen := fmt.Sprintf("%s:%d", defaultStr(r.cfg.Host, "127.0.0.1"), r.cfg.Port)
+	port, socket := r.cfg.Port, r.cfg.Socket
+	if ln := r.cfg.Listener; ln != nil {
+		listen = ln.Addr().String() + " (systemd socket activation)"
+		if tcp, ok := ln.Addr().(*net.TCPAddr); ok {
+			port = tcp.Port
+		} else {
+			socket = ln.Addr().String()
+		}
+	} else if socket != "" {
+		listen = "unix:" + socket
+	}
 
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  %s\n", title)
@@ -2968,7 +3701,10 @@ func (r *Runner) printBanner(proxyURL string) {
 	fmt.Fprintf(os.Stderr, "  Agent:      %s (v%s)\n", r.cfg.Config.AgentID, r.cfg.Config.Version)
 	fmt.Fprintf(os.Stderr, "  Forge:      %s\n", r.forgeVersionString())
 	fmt.Fprintf(os.Stderr, "  Framework:  %s\n", r.cfg.Config.Framework)
-	fmt.Fprintf(os.Stderr, "  Listen:     %s:%d\n", host, r.cfg.Port)
+	fmt.Fprintf(os.Stderr, "  Listen:     %s\n", listen)
+	if r.cfg.DryRun {
+		fmt.Fprintf(os.Stderr, "  Dry run:    tool calls are described, not executed\n")
+	}
 	if r.cfg.MockTools {
 		fmt.Fprintf(os.Stderr, "  Mode:       mock (no subprocess)\n")
 	} else if r.cfg.Config.Entrypoint != "" {
@@ -2984,6 +3720,20 @@ func (r *Runner) printBanner(proxyURL string) {
 			}
 			fmt.Fprintf(os.Stderr, "  Fallbacks:  %s\n", strings.Join(fbNames, ", "))
 		}
+		if len(r.modelConfig.Routes) > 0 {
+			var rtNames []string
+			for _, rt := range r.modelConfig.Routes {
+				rtNames = append(rtNames, rt.ID+" ("+rt.Provider+"/"+rt.Client.Model+")")
+			}
+			fmt.Fprintf(os.Stderr, "  Routes:     %s\n", strings.Join(rtNames, ", "))
+		}
+		if rc := r.modelConfig.ResponseCache; rc != nil {
+			where := "memory"
+			if rc.Dir != "" {
+				where = rc.Dir
+			}
+			fmt.Fprintf(os.Stderr, "  LLM cache:  on (%s)\n", where)
+		}
 	}
 	// Tools
 	if len(r.cfg.Config.Tools) > 0 {
@@ -3025,7 +3775,7 @@ func (r *Runner) printBanner(proxyURL string) {
 		fmt.Fprintf(os.Stderr, "  Auth:       enabled (token in .forge/runtime.token)\n")
 	}
 	// LAN exposure warning
-	if !isLocalhost(r.cfg.Host) && !r.cfg.NoAuth {
+	if !r.bindsLocally() && !r.cfg.NoAuth {
 		fmt.Fprintf(os.Stderr, "  WARNING:    binding to non-localhost; ensure firewall rules are in place\n")
 	}
 	// Egress proxy
@@ -3033,10 +3783,14 @@ func (r *Runner) printBanner(proxyURL string) {
 		fmt.Fprintf(os.Stderr, "  Proxy:      %s\n", proxyURL)
 	}
 	fmt.Fprintf(os.Stderr, "  ────────────────────────────────────────\n")
-	fmt.Fprintf(os.Stderr, "  Agent Card: http://localhost:%d/.well-known/agent-card.json\n", r.cfg.Port)
-	fmt.Fprintf(os.Stderr, "  Health:     http://localhost:%d/healthz\n", r.cfg.Port)
-	fmt.Fprintf(os.Stderr, "  REST:       http://localhost:%d/tasks/send\n", r.cfg.Port)
-	fmt.Fprintf(os.Stderr, "  JSON-RPC:   POST http://localhost:%d/\n", r.cfg.Port)
+	if socket != "" {
+		fmt.Fprintf(os.Stderr, "  Health:     curl --unix-socket %s http://localhost/healthz\n", socket)
+	} else {
+		fmt.Fprintf(os.Stderr, "  Agent Card: http://localhost:%d/.well-known/agent-card.json\n", port)
+		fmt.Fprintf(os.Stderr, "  Health:     http://localhost:%d/healthz\n", port)
+		fmt.Fprintf(os.Stderr, "  REST:       http://localhost:%d/tasks/send\n", port)
+		fmt.Fprintf(os.Stderr, "  JSON-RPC:   POST http://localhost:%d/\n", port)
+	}
 	fmt.Fprintf(os.Stderr, "  ────────────────────────────────────────\n")
 	fmt.Fprintf(os.Stderr, "  Press Ctrl+C to stop\n\n")
 }
@@ -3077,6 +3831,11 @@ func (r *Runner) resolveAuth(auditLogger *coreruntime.AuditLogger) (auth.Middlew
 		// operator declared a required-auth deployment.
 		if r.cfg.Config != nil {
 			authCfg := r.cfg.Config.Auth
+			if len(r.cfg.Config.Tenants) > 0 {
+				return auth.MiddlewareOptions{}, fmt.Errorf(
+					"--no-auth conflicts with forge.yaml 'tenants:' — tenants are told apart by their API keys, " +
+						"so a multi-tenant agent can't accept anonymous traffic")
+			}
 			if authCfg.Required {
 				return auth.MiddlewareOptions{}, fmt.Errorf(
 					"--no-auth conflicts with forge.yaml 'auth.required: true' — " +
@@ -3096,7 +3855,7 @@ func (r *Runner) resolveAuth(auditLogger *coreruntime.AuditLogger) (auth.Middlew
 		// makes that choice visible at the middleware boundary (review #3).
 		return auth.MiddlewareOptions{
 			AllowAnonymous: true,
-			SkipPaths:      auth.DefaultSkipPaths(),
+			SkipPaths:      r.authSkipPaths(),
 		}, nil
 	}
 
@@ -3117,6 +3876,17 @@ func (r *Runner) resolveAuth(auditLogger *coreruntime.AuditLogger) (auth.Middlew
 	// pinned by TestResolveAuth_InvariantMintsTokenInNonNoAuthPath
 	// (review #10).
 	chain := userChain
+	var tenants []types.TenantConfig
+	if r.cfg.Config != nil {
+		tenants = r.cfg.Config.Tenants
+	}
+	tenantKeys, err := newTenantAPIKeyProvider(tenants)
+	if err != nil {
+		return auth.MiddlewareOptions{}, err
+	}
+	if tenantKeys != nil {
+		chain = auth.PrependChain(chain, tenantKeys)
+	}
 	if r.authToken != "" {
 		loopback, err := statictoken.New(statictoken.Config{
 			Token: r.authToken,
@@ -3128,7 +3898,7 @@ func (r *Runner) resolveAuth(auditLogger *coreruntime.AuditLogger) (auth.Middlew
 		if err != nil {
 			return auth.MiddlewareOptions{}, fmt.Errorf("loopback static_token: %w", err)
 		}
-		chain = auth.PrependChain(userChain, loopback)
+		chain = auth.PrependChain(chain, loopback)
 	}
 
 	// No user chain AND no loopback token → legacy "no auth config, no
@@ -3138,14 +3908,14 @@ func (r *Runner) resolveAuth(auditLogger *coreruntime.AuditLogger) (auth.Middlew
 	if chain == nil {
 		return auth.MiddlewareOptions{
 			AllowAnonymous: true,
-			SkipPaths:      auth.DefaultSkipPaths(),
+			SkipPaths:      r.authSkipPaths(),
 			OnAuth:         makeAuthAuditCallback(auditLogger),
 		}, nil
 	}
 
 	return auth.MiddlewareOptions{
 		Chain:     chain,
-		SkipPaths: auth.DefaultSkipPaths(),
+		SkipPaths: r.authSkipPaths(),
 		OnAuth:    makeAuthAuditCallback(auditLogger),
 	}, nil
 }
@@ -3212,6 +3982,7 @@ func makeAuthAuditCallback(auditLogger *coreruntime.AuditLogger) func(*http.Requ
 				InvocationCaller:    wc.InvocationCaller,
 				OrgID:               tc.OrgID,
 				WorkspaceID:         tc.WorkspaceID,
+				TenantID:            id.TenantID,
 				Fields:              fields,
 			})
 			return
@@ -3603,21 +4374,38 @@ func (r *Runner) registerSkillTools(reg *tools.Registry, proxyURL string, socksU
 	}
 }
 
-// buildSystemPrompt constructs the system prompt with an optional skill catalog.
+// buildSystemPrompt renders the system prompt from the prompt templates
+// (see loadPrompts), falling back to the built-in template if they fail.
 func (r *Runner) buildSystemPrompt() string {
-	base := fmt.Sprintf("You are %s, an AI agent.", r.cfg.Config.AgentID)
-	catalog := r.buildSkillCatalog()
-	if catalog != "" {
-		base += "\n\n" + catalog
+	prompt, err := r.renderSystemPrompt(r.prompts)
+	if err != nil {
+		r.logger.Warn("rendering the built-in system prompt", map[string]any{"error": err.Error()})
+		prompt, _ = r.renderSystemPrompt(nil)
 	}
+	return prompt
+}
 
-	// Add scheduler awareness if schedules are configured or tools are available.
-	schedSection := r.buildSchedulerPrompt()
-	if schedSection != "" {
-		base += "\n\n" + schedSection
-	}
+// executorSystemPrompt is the LLM executor's system prompt: the rendered
+// templates, then the directives of runtime features that are on.
+func (r *Runner) executorSystemPrompt() string {
+	return r.withPromptDirectives(r.buildSystemPrompt())
+}
 
-	return base
+// withPromptDirectives appends the directives of runtime features that
+// are on to a rendered system prompt.
+func (r *Runner) withPromptDirectives(sysPrompt string) string {
+	// Append code-agent tool directives if those tools are registered.
+	if r.hasSkill("code-agent") {
+		sysPrompt += "\n\n" + codeAgentDirective
+	}
+	// Compression marker-awareness is a runtime concern, not a
+	// per-skill one: whenever compression is on, every skill's
+	// agent learns what <<ctxzip:...>> markers are and when to
+	// call context_expand — skill authors need do nothing.
+	if r.compression != nil {
+		sysPrompt += "\n\n" + compress.SystemDirective
+	}
+	return sysPrompt
 }
 
 // buildSkillCatalog generates a lightweight catalog of binary-backed skills
@@ -3653,7 +4441,7 @@ func (r *Runner) skillEntryHasScript(skillDir, toolName string) bool {
 	return false
 }
 
-func (r *Runner) buildSkillCatalog() string {
+func (r *Runner) buildSkillCatalog(set *prompts.Set) string {
 	matches := r.discoverSkillFiles()
 	if len(matches) == 0 {
 		return ""
@@ -3760,6 +4548,14 @@ func (r *Runner) buildSkillCatalog() string {
 		if usesCLI {
 			line += " (uses cli_execute)"
 		}
+		// A prompts/skills/<name>.tmpl template rewrites the line.
+		custom, ok, err := set.RenderSkill(prompts.Skill{Name: loadName, Description: desc, Provides: provides, UsesCLI: usesCLI})
+		switch {
+		case err != nil:
+			r.logger.Warn("skill prompt template failed", map[string]any{"skill": loadName, "error": err.Error()})
+		case ok:
+			line = custom
+		}
 		catalogEntries = append(catalogEntries, line)
 	}
 
@@ -3840,10 +4636,10 @@ func (r *Runner) validateSkillRequirements(envVars map[string]string) error {
 
 	if len(reqs.Bins) == 0 && len(reqs.EnvRequired) == 0 && len(reqs.EnvOneOf) == 0 && len(reqs.EnvOptional) == 0 {
 		// Skills carrying only egress_domains / denied_tools / capabilities /
-		// workflow phases still need their derived config stored: the egress
-		// resolver (proxy allowlist) and denied-tools removal read it.
+		// workflow phases / reflection / output contracts still need their derived config
+		// stored: the egress resolver (proxy allowlist) and denied-tools removal read it.
 		// Previously these were silently dropped for bins/env-less skills.
-		if len(reqs.EgressDomains) > 0 || len(reqs.DeniedTools) > 0 || len(reqs.Capabilities) > 0 || len(reqs.WorkflowPhases) > 0 {
+		if len(reqs.EgressDomains) > 0 || len(reqs.DeniedTools) > 0 || len(reqs.Capabilities) > 0 || len(reqs.WorkflowPhases) > 0 || len(reqs.ReflectionTools) > 0 || len(reqs.OutputContracts) > 0 {
 			r.derivedCLIConfig = requirements.DeriveCLIConfig(reqs)
 		}
 		return nil
@@ -3983,7 +4779,7 @@ func expandEgressDomains(domain string, envVars map[string]string) []string {
 // initLongTermMemory sets up the long-term memory system if enabled.
 // It resolves the embedder, creates a memory.Manager, registers memory tools,
 // and starts background indexing. Returns the Manager (caller must Close) or nil.
-func (r *Runner) initLongTermMemory(ctx context.Context, mc *coreruntime.ModelConfig, reg *tools.Registry, compactor *coreruntime.Compactor) *memory.Manager {
+func (r *Runner) initLongTermMemory(ctx context.Context, mc *coreruntime.ModelConfig, llmClient llm.Client, reg *tools.Registry, compactor *coreruntime.Compactor) *memory.Manager {
 	// Check if long-term memory is enabled.
 	enabled := false
 	if r.cfg.Config.Memory.LongTerm != nil {
@@ -3992,14 +4788,15 @@ func (r *Runner) initLongTermMemory(ctx context.Context, mc *coreruntime.ModelCo
 	if os.Getenv("FORGE_MEMORY_LONG_TERM") == "true" {
 		enabled = true
 	}
+	// A knowledge base lives in long-term memory.
+	if len(r.cfg.Config.KB.Paths) > 0 {
+		enabled = true
+	}
 	if !enabled {
 		return nil
 	}
 
-	memDir := r.cfg.Config.Memory.MemoryDir
-	if memDir == "" {
-		memDir = filepath.Join(r.cfg.WorkDir, ".forge", "memory")
-	}
+	memDir := MemoryDir(r.cfg.Config.Memory, r.cfg.WorkDir)
 
 	// Resolve embedder.
 	embedder := r.resolveEmbedder(mc)
@@ -4015,12 +4812,37 @@ func (r *Runner) initLongTermMemory(ctx context.Context, mc *coreruntime.ModelCo
 	if r.cfg.Config.Memory.DecayHalfLifeDays > 0 {
 		searchCfg.DecayHalfLife = time.Duration(r.cfg.Config.Memory.DecayHalfLifeDays) * 24 * time.Hour
 	}
+	if r.cfg.Config.Memory.RerankBudget > 0 {
+		searchCfg.RerankBudget = r.cfg.Config.Memory.RerankBudget
+	}
+	if r.cfg.Config.Memory.RewriteBudget > 0 {
+		searchCfg.RewriteBudget = r.cfg.Config.Memory.RewriteBudget
+	}
+
+	// Query rewriting and LLM reranking use the summarizer's model: the
+	// first fallback, usually the cheaper one.
+	var searchLLM llm.Client
+	if r.cfg.Config.Memory.QueryRewrite || r.cfg.Config.Memory.RerankProvider == "llm" {
+		searchLLM = r.summaryClient(mc, llmClient)
+	}
+	if r.cfg.Config.Memory.QueryRewrite {
+		if embedder == nil {
+			r.logger.Info("memory.query_rewrite needs vector search; ignored with keyword-only search", nil)
+		} else {
+			searchCfg.QueryRewrite = true
+		}
+	}
 
 	mgr, err := memory.NewManager(memory.ManagerConfig{
 		MemoryDir:    memDir,
 		Embedder:     embedder,
+		Reranker:     r.resolveReranker(searchLLM),
+		Rewriter:     searchLLM,
 		Logger:       r.logger,
 		SearchConfig: searchCfg,
+		WriteQuota:   r.cfg.Config.Memory.WriteQuota,
+		WriteQuotas:  r.cfg.Config.Memory.WriteQuotas,
+		Retention:    MemoryRetentionPolicy(r.cfg.Config.Memory),
 	})
 	if err != nil {
 		r.logger.Warn("failed to create memory manager, long-term memory disabled", map[string]any{
@@ -4036,17 +4858,30 @@ func (r *Runner) initLongTermMemory(ctx context.Context, mc *coreruntime.ModelCo
 	if regErr := reg.Register(builtins.NewMemoryGetTool(mgr)); regErr != nil {
 		r.logger.Warn("failed to register memory_get tool", map[string]any{"error": regErr.Error()})
 	}
+	if regErr := reg.Register(builtins.NewMemoryWriteTool(mgr)); regErr != nil {
+		r.logger.Warn("failed to register memory_write tool", map[string]any{"error": regErr.Error()})
+	}
 
 	// Wire memory flusher into compactor (if compactor exists).
 	if compactor != nil {
-		compactor.SetMemoryFlusher(mgr)
+		if len(r.cfg.Config.Tenants) > 0 {
+			compactor.SetMemoryFlusher(&tenantMemoryFlusher{mgr: mgr, tenants: r.tenantIDs()})
+		} else {
+			compactor.SetMemoryFlusher(mgr)
+		}
 	}
 
-	// Index memory files at startup in background.
+	// Index memory files and re-sync the knowledge base at startup in
+	// background. Unchanged documents are not re-embedded.
 	go func() {
 		if idxErr := mgr.IndexAll(ctx); idxErr != nil {
 			r.logger.Warn("background memory indexing failed", map[string]any{"error": idxErr.Error()})
 		}
+		if paths := r.cfg.Config.KB.Paths; len(paths) > 0 {
+			if _, kbErr := r.syncKB(ctx, mgr, paths); kbErr != nil {
+				r.logger.Warn("knowledge base sync failed", map[string]any{"error": kbErr.Error()})
+			}
+		}
 	}()
 
 	mode := "keyword-only"
@@ -4191,8 +5026,9 @@ func (r *Runner) initCompression(reg *tools.Registry, auditLogger *coreruntime.A
 // resolveEmbedder creates an embedder from config or auto-detection.
 // Returns nil if no embedder can be created (keyword-only mode).
 func (r *Runner) resolveEmbedder(mc *coreruntime.ModelConfig) llm.Embedder {
+	memCfg := r.cfg.Config.Memory
 	// Resolution order: config override → env → primary LLM provider.
-	embProvider := r.cfg.Config.Memory.EmbeddingProvider
+	embProvider := memCfg.EmbeddingProvider
 	if embProvider == "" {
 		embProvider = os.Getenv("FORGE_EMBEDDING_PROVIDER")
 	}
@@ -4210,28 +5046,48 @@ func (r *Runner) resolveEmbedder(mc *coreruntime.ModelConfig) llm.Embedder {
 				break
 			}
 		}
+		// Voyage AI is Anthropic's recommended embedding provider.
+		if embProvider == "anthropic" && os.Getenv("VOYAGE_API_KEY") != "" {
+			embProvider = "voyage"
+		}
+		// An embedding endpoint with no cloud provider to own it is a
+		// local embedding server.
+		if embProvider == "anthropic" && memCfg.EmbeddingBaseURL != "" {
+			embProvider = "local"
+		}
 		if embProvider == "anthropic" {
-			r.logger.Info("no embedding-capable provider found, using keyword-only search", nil)
+			r.logger.Info("no embedding-capable provider found, using keyword-only search (set memory.embedding_provider to voyage or local for vector search)", nil)
 			return nil
 		}
 	}
 
 	cfg := providers.OpenAIEmbedderConfig{
-		APIKey: mc.Client.APIKey,
-		OrgID:  mc.Client.OrgID,
-		Model:  r.cfg.Config.Memory.EmbeddingModel,
+		Model:   memCfg.EmbeddingModel,
+		BaseURL: memCfg.EmbeddingBaseURL,
+		Dims:    memCfg.EmbeddingDims,
 	}
 
-	// Use the correct API key for the embedding provider if it differs from primary.
-	if embProvider != mc.Provider {
+	// Use the embedding provider's own credentials: the primary's, a
+	// matching fallback's, or its API-key env var.
+	if embProvider == mc.Provider {
+		cfg.APIKey = mc.Client.APIKey
+		cfg.OrgID = mc.Client.OrgID
+	} else {
+		found := false
 		for _, fb := range mc.Fallbacks {
 			if fb.Provider == embProvider {
 				cfg.APIKey = fb.Client.APIKey
-				cfg.BaseURL = fb.Client.BaseURL
 				cfg.OrgID = fb.Client.OrgID
+				if cfg.BaseURL == "" {
+					cfg.BaseURL = fb.Client.BaseURL
+				}
+				found = true
 				break
 			}
 		}
+		if env := providers.EmbeddingAPIKeyEnv(embProvider); !found && env != "" {
+			cfg.APIKey = os.Getenv(env)
+		}
 	}
 
 	embedder, err := providers.NewEmbedder(embProvider, cfg)
@@ -4246,6 +5102,39 @@ func (r *Runner) resolveEmbedder(mc *coreruntime.ModelConfig) llm.Embedder {
 	return embedder
 }
 
+// resolveReranker creates the memory search reranker named by
+// memory.rerank_provider, or returns nil (hybrid order) when none is
+// configured or it cannot be built. The "llm" provider grades
+// candidates with client.
+func (r *Runner) resolveReranker(client llm.Client) llm.Reranker {
+	memCfg := r.cfg.Config.Memory
+	switch memCfg.RerankProvider {
+	case "":
+		return nil
+	case "llm":
+		if client == nil {
+			return nil
+		}
+		return memory.NewLLMReranker(client)
+	}
+	cfg := providers.RerankerConfig{
+		Model:   memCfg.RerankModel,
+		BaseURL: memCfg.RerankBaseURL,
+	}
+	if env := providers.EmbeddingAPIKeyEnv(memCfg.RerankProvider); env != "" {
+		cfg.APIKey = os.Getenv(env)
+	}
+	reranker, err := providers.NewReranker(memCfg.RerankProvider, cfg)
+	if err != nil {
+		r.logger.Warn("failed to create reranker, using hybrid search order", map[string]any{
+			"provider": memCfg.RerankProvider,
+			"error":    err.Error(),
+		})
+		return nil
+	}
+	return reranker
+}
+
 // modelConfigEnvKeys are the NON-secret env vars ResolveModelConfig /
 // resolveFallbacks read (base URLs, provider/model overrides, org, region,
 // fallback list). They aren't in builtinSecretKeys (those are credentials) and
@@ -4256,11 +5145,19 @@ var modelConfigEnvKeys = []string{
 	"OPENAI_BASE_URL",
 	"ANTHROPIC_BASE_URL",
 	"OLLAMA_BASE_URL",
+	"COHERE_BASE_URL",
+	"MISTRAL_BASE_URL",
+	"GROQ_BASE_URL",
+	"TOGETHER_BASE_URL",
+	"OPENROUTER_BASE_URL",
+	"XAI_BASE_URL",
+	"DEEPSEEK_BASE_URL",
 	"FORGE_MODEL_PROVIDER",
 	"MODEL_NAME",
 	"OPENAI_ORG_ID",
 	"AWS_REGION",
 	"FORGE_MODEL_FALLBACKS",
+	"OLLAMA_KEEP_ALIVE",
 }
 
 // mergeModelConfigEnv fills any model-config key absent from envVars from the
@@ -4287,6 +5184,14 @@ var builtinSecretKeys = []string{
 	"OPENAI_API_KEY",
 	"ANTHROPIC_API_KEY",
 	"GEMINI_API_KEY",
+	"COHERE_API_KEY",
+	"MISTRAL_API_KEY",
+	"GROQ_API_KEY",
+	"TOGETHER_API_KEY",
+	"OPENROUTER_API_KEY",
+	"XAI_API_KEY",
+	"DEEPSEEK_API_KEY",
+	"VOYAGE_API_KEY",
 	"LLM_API_KEY",
 	"MODEL_API_KEY",
 	"TAVILY_API_KEY",
@@ -4388,7 +5293,7 @@ func (r *Runner) overlaySecrets(envVars map[string]string) error {
 // secretCategory returns the purpose category for a known secret key.
 func secretCategory(key string) string {
 	switch key {
-	case "OPENAI_API_KEY", "ANTHROPIC_API_KEY", "GEMINI_API_KEY", "LLM_API_KEY", "MODEL_API_KEY":
+	case "OPENAI_API_KEY", "ANTHROPIC_API_KEY", "GEMINI_API_KEY", "COHERE_API_KEY", "MISTRAL_API_KEY", "GROQ_API_KEY", "TOGETHER_API_KEY", "OPENROUTER_API_KEY", "XAI_API_KEY", "DEEPSEEK_API_KEY", "VOYAGE_API_KEY", "LLM_API_KEY", "MODEL_API_KEY":
 		return "llm"
 	case "TAVILY_API_KEY", "PERPLEXITY_API_KEY":
 		return "search"
@@ -4430,7 +5335,7 @@ func (r *Runner) buildSecretProvider() secrets.Provider {
 		case "env":
 			providers = append(providers, secrets.NewEnvProvider(""))
 		case "encrypted-file":
-			providers = append(providers, viableEncryptedFileProviders(r.cfg.WorkDir, passCb, r.logger.Warn)...)
+			providers = append(providers, viableEncryptedFileProviders(r.cfg.Config, r.cfg.WorkDir, passCb, r.logger.Warn)...)
 		default:
 			r.logger.Warn("unknown secret provider, skipping", map[string]any{"provider": name})
 		}
@@ -4445,26 +5350,48 @@ func (r *Runner) buildSecretProvider() secrets.Provider {
 	return secrets.NewChainProvider(providers...)
 }
 
-// viableEncryptedFileProviders returns the agent-local and global
-// encrypted-file providers that pass an eager-load check. Files that don't
+// viableEncryptedFileProviders returns the profile-scoped, agent-local and
+// global encrypted-file providers that pass an eager-load check. Files that don't
 // exist are silently skipped (the common case: the operator never ran
 // `forge secret set --global`). Files that fail to decrypt (wrong passphrase,
-// corruption) emit a warning via warnFn and are dropped from the chain — so a
+// corruption, an encryption context belonging to another agent) emit a
+// warning via warnFn and are dropped from the chain — so a
 // stale global file with a different passphrase cannot poison subsequent
 // ChainProvider.Get/List calls once admitted to the chain.
 //
+// The profile-scoped file, .forge/secrets.<profile>.enc, is only
+// consulted when a profile is active and comes first, so a profile's
+// secrets override the agent's. Identities from the file named by
+// FORGE_SECRETS_IDENTITY are tried before the passphrase.
+//
 // The returned providers retain their decrypted cache (EncryptedFileProvider
 // flags `loaded = true` after a successful List()), so subsequent reads — by
 // secretOverlayKeys, by Get on individual keys — reuse the work and don't
 // trigger another Argon2id derivation.
 //
 // warnFn may be nil; in that case decryption failures are silently skipped.
-func viableEncryptedFileProviders(workDir string, passCb func() (string, error), warnFn func(msg string, fields map[string]any)) []secrets.Provider {
-	candidates := []struct{ path, label string }{
-		{filepath.Join(workDir, ".forge", "secrets.enc"), "agent-local"},
+func viableEncryptedFileProviders(cfg *types.ForgeConfig, workDir string, passCb func() (string, error), warnFn func(msg string, fields map[string]any)) []secrets.Provider {
+	type candidate struct{ path, label, context string }
+	var candidates []candidate
+	if cfg.ActiveProfile != "" {
+		candidates = append(candidates, candidate{
+			filepath.Join(workDir, ".forge", "secrets."+cfg.ActiveProfile+".enc"), "profile",
+			secrets.AgentContext(cfg.AgentID, cfg.ActiveProfile),
+		})
 	}
+	candidates = append(candidates, candidate{
+		filepath.Join(workDir, ".forge", "secrets.enc"), "agent-local", secrets.AgentContext(cfg.AgentID, ""),
+	})
 	if home, err := os.UserHomeDir(); err == nil {
-		candidates = append(candidates, struct{ path, label string }{filepath.Join(home, ".forge", "secrets.enc"), "global"})
+		candidates = append(candidates, candidate{filepath.Join(home, ".forge", "secrets.enc"), "global", ""})
+	}
+
+	var ids []secrets.Identity
+	if path := os.Getenv(secrets.IdentityEnv); path != "" {
+		var err error
+		if ids, err = secrets.ReadIdentityFile(path); err != nil && warnFn != nil {
+			warnFn("ignoring secrets identity", map[string]any{"error": err.Error()})
+		}
 	}
 
 	var viable []secrets.Provider
@@ -4473,6 +5400,8 @@ func viableEncryptedFileProviders(workDir string, passCb func() (string, error),
 			continue
 		}
 		provider := secrets.NewEncryptedFileProvider(c.path, passCb)
+		provider.SetContext(c.context)
+		provider.SetIdentities(ids...)
 		// Eagerly validate the file can be decrypted. List() runs
 		// ensureLoaded which performs the decrypt and caches the cleartext
 		// for later calls.
@@ -4511,7 +5440,7 @@ func OverlaySecretsToEnv(cfg *types.ForgeConfig, workDir string) {
 	for _, name := range providerNames {
 		switch name {
 		case "encrypted-file":
-			chain = append(chain, viableEncryptedFileProviders(workDir, passCb, stderrWarn)...)
+			chain = append(chain, viableEncryptedFileProviders(cfg, workDir, passCb, stderrWarn)...)
 		case "env":
 			// env provider uses os.Getenv — already available, skip
 		}
@@ -4568,9 +5497,16 @@ func defaultStr(s, def string) string {
 	return def
 }
 
-// isLocalhost returns true if the host string refers to a localhost address.
-func isLocalhost(host string) bool {
-	return host == "" || host == "127.0.0.1" || host == "localhost" || host == "::1"
+// bindsLocally reports whether the A2A server is reachable only from
+// this host: over a loopback address or a Unix socket.
+func (r *Runner) bindsLocally() bool {
+	if ln := r.cfg.Listener; ln != nil {
+		if tcp, ok := ln.Addr().(*net.TCPAddr); ok {
+			return tcp.IP.IsLoopback()
+		}
+		return ln.Addr().Network() == "unix"
+	}
+	return r.cfg.Socket != "" || r.cfg.Host == "" || server.IsLoopbackHost(r.cfg.Host)
 }
 
 // materializeKubeconfig checks whether the KUBECONFIG env var contains inline
@@ -4650,9 +5586,12 @@ func (l *lazyScheduleReloader) Reload(ctx context.Context) {
 
 // makeScheduleDispatcher creates a TaskDispatcher that executes scheduled tasks
 // via the LLM executor.
-func (r *Runner) makeScheduleDispatcher(executor coreruntime.AgentExecutor, egressClient *http.Client, auditLogger *coreruntime.AuditLogger) scheduler.TaskDispatcher {
+func (r *Runner) makeScheduleDispatcher(executor coreruntime.AgentExecutor, egressClient *http.Client) scheduler.TaskDispatcher {
 	return func(ctx context.Context, sched scheduler.Schedule) error {
-		taskID := fmt.Sprintf("sched-%s-%d", sched.ID, time.Now().Unix())
+		// A tenant's schedule runs as the tenant's task, with the tenant
+		// prefix moved from the schedule ID to the front of the task ID.
+		localID := strings.TrimPrefix(sched.ID, sched.Tenant+coreruntime.TenantSeparator)
+		taskID := coreruntime.TenantScopedID(sched.Tenant, fmt.Sprintf("sched-%s-%d", localID, time.Now().Unix()))
 		// A schedule fire is a background invocation with no HTTP ingress and
 		// no auth, so it mints its own correlation id (nothing upstream to
 		// adopt — unlike the request-driven paths, see #278).
@@ -4662,49 +5601,66 @@ func (r *Runner) makeScheduleDispatcher(executor coreruntime.AgentExecutor, egre
 		ctx = security.WithEgressClient(ctx, egressClient)
 		ctx = coreruntime.WithCorrelationID(ctx, correlationID)
 		ctx = coreruntime.WithTaskID(ctx, taskID)
+		ctx = coreruntime.WithTenant(ctx, sched.Tenant)
+		ctx = tools.WithOrigin(ctx, tools.OriginSchedule)
 		// FWS-8: scheduled invocations also need a per-invocation
 		// sequence counter so their audit stream is gap-detectable.
 		ctx = coreruntime.WithSequenceCounter(ctx, new(coreruntime.SequenceCounter))
 
-		auditLogger.Emit(coreruntime.AuditEvent{
-			Event:         coreruntime.AuditScheduleFire,
+		r.events.Publish(ctx, coreruntime.Event{
+			Topic:         coreruntime.TopicScheduleFired,
 			CorrelationID: correlationID,
 			TaskID:        taskID,
 			Fields:        map[string]any{"schedule_id": sched.ID},
 		})
 
-		// Build the task message.
-		msgText := fmt.Sprintf("[Scheduled Task: %s]\n\n%s", sched.ID, sched.Task)
-		if sched.Skill != "" {
-			msgText = fmt.Sprintf("[Scheduled Task: %s] [Skill: %s]\n\n%s", sched.ID, sched.Skill, sched.Task)
-		}
+		started := time.Now()
+		var respMsg *a2a.Message
+		var err error
+		if sched.Workflow != "" {
+			// A workflow schedule runs its steps, not the agent; the
+			// run ID is the task ID.
+			respMsg, err = r.runWorkflow(ctx, sched.Workflow, workflow.RunOptions{ID: taskID, Trigger: "schedule:" + sched.ID})
+		} else {
+			// Build the task message.
+			msgText := fmt.Sprintf("[Scheduled Task: %s]\n\n%s", sched.ID, sched.Task)
+			if sched.Skill != "" {
+				msgText = fmt.Sprintf("[Scheduled Task: %s] [Skill: %s]\n\n%s", sched.ID, sched.Skill, sched.Task)
+			}
 
-		task := &a2a.Task{
-			ID:     taskID,
-			Status: a2a.TaskStatus{State: a2a.TaskStateWorking},
-		}
+			task := &a2a.Task{
+				ID:     taskID,
+				Status: a2a.TaskStatus{State: a2a.TaskStateWorking},
+			}
 
-		msg := &a2a.Message{
-			Role:  a2a.MessageRoleUser,
-			Parts: []a2a.Part{a2a.NewTextPart(msgText)},
-		}
+			msg := &a2a.Message{
+				Role:  a2a.MessageRoleUser,
+				Parts: []a2a.Part{a2a.NewTextPart(msgText)},
+			}
 
-		respMsg, err := executor.Execute(ctx, task, msg)
+			// Under --dry-run a schedule firing must not touch anything
+			// either; its plan is not kept.
+			if r.cfg.DryRun {
+				ctx = coreruntime.WithDryRun(ctx, coreruntime.NewPlan())
+			}
+			respMsg, err = executor.Execute(ctx, task, msg)
+		}
 
-		auditLogger.Emit(coreruntime.AuditEvent{
-			Event:         coreruntime.AuditScheduleComplete,
+		r.events.Publish(ctx, coreruntime.Event{
+			Topic:         coreruntime.TopicScheduleCompleted,
 			CorrelationID: correlationID,
 			TaskID:        taskID,
 			Fields: map[string]any{
 				"schedule_id": sched.ID,
 				"success":     err == nil,
+				"duration_ms": time.Since(started).Milliseconds(),
 			},
 		})
 
 		// Deliver result to channel if configured.
 		if err == nil && respMsg != nil && sched.Channel != "" && sched.ChannelTarget != "" {
 			if r.scheduleNotifier != nil {
-				if notifyErr := r.scheduleNotifier(ctx, sched.Channel, sched.ChannelTarget, respMsg); notifyErr != nil {
+				if notifyErr := r.scheduleNotifier(ctx, sched, respMsg); notifyErr != nil {
 					r.logger.Warn("failed to notify channel for scheduled task", map[string]any{
 						"schedule_id": sched.ID,
 						"channel":     sched.Channel,
@@ -4757,7 +5713,11 @@ func (r *Runner) selectScheduleBackend(
 		return nil, fmt.Errorf("scheduler.backend = %q: must be one of auto / file / kubernetes", mode)
 	}
 	if !useK8s {
-		sched := scheduler.New(store, dispatch, r.logger, auditFn)
+		sched := scheduler.New(store, dispatch, coreruntime.SubsystemLogger(r.logger, coreruntime.LogSubsystemScheduler), auditFn)
+		if r.cluster != nil {
+			// Every replica ticks; only the elected leader fires.
+			sched.SetLeader(r.cluster.elector.IsLeader)
+		}
 		return scheduler.NewFileBackend(store, sched), nil
 	}
 	k8sCfg := r.cfg.Config.Scheduler.Kubernetes
@@ -4771,7 +5731,7 @@ func (r *Runner) selectScheduleBackend(
 			TriggerImage:   k8sCfg.TriggerImage,
 			AllowDynamic:   k8sCfg.AllowDynamic,
 		},
-		r.logger,
+		coreruntime.SubsystemLogger(r.logger, coreruntime.LogSubsystemScheduler),
 	)
 	if err != nil {
 		return nil, err
@@ -4795,6 +5755,7 @@ func (r *Runner) declaredSchedules() []scheduler.Schedule {
 		out = append(out, scheduler.Schedule{
 			ID:            sc.ID,
 			Cron:          sc.Cron,
+			Timezone:      sc.Timezone,
 			Task:          sc.Task,
 			Skill:         sc.Skill,
 			Channel:       sc.Channel,
@@ -4802,6 +5763,9 @@ func (r *Runner) declaredSchedules() []scheduler.Schedule {
 			Source:        scheduler.SourceYAML,
 			Enabled:       true,
 			Created:       now,
+			MaxRuntime:    sc.MaxRuntime,
+			Overlap:       sc.Overlap,
+			Workflow:      sc.Workflow,
 		})
 	}
 	return out
@@ -4819,6 +5783,7 @@ You have access to a built-in cron scheduler for recurring tasks. Use these tool
 - **schedule_history**: View execution history for scheduled tasks
 
 Cron expressions support: standard 5-field (min hour dom mon dow), aliases (@hourly, @daily, @weekly, @monthly), and intervals (@every 5m, @every 1h).
+Cron times are UTC unless you pass a timezone (IANA name, e.g. America/New_York); when the user gives a local time, pass their zone.
 
 ### Channel delivery
 Messages from channels include a context line: ` + "`" + `[channel:<name> channel_target:<id>]` + "`" + `
@@ -4827,3 +5792,46 @@ When creating a schedule from a channel conversation, **always** extract these v
 - **channel_target**: the destination ID from the context line (Slack channel ID, Telegram chat ID)
 Without these, scheduled task results will execute but not be sent to any channel.`
 }
+
+// buildChannelFormatPrompt tells the model how each active channel
+// renders its replies, and maps the output formats skills declare
+// ("**Output format:**") onto the channels that cannot show them as
+// declared. Empty when no channel adapter is active.
+func (r *Runner) buildChannelFormatPrompt() string {
+	var profiles []string
+	var active []string
+	for _, name := range r.cfg.Channels {
+		if p, ok := channels.FormatProfileFor(name); ok {
+			profiles = append(profiles, fmt.Sprintf("- **%s**: %s", name, p.Guidance))
+			active = append(active, name)
+		}
+	}
+	if len(profiles) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Channel formatting\n\n")
+	b.WriteString("Replies to messages with a `[channel:<name> ...]` context line are converted for that channel:\n")
+	b.WriteString(strings.Join(profiles, "\n"))
+
+	var hints []string
+	for _, match := range r.discoverSkillFiles() {
+		entries, _, err := cliskills.ParseFileWithMetadata(match)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			for _, name := range active {
+				if hint := channels.OutputFormatHint(name, e.OutputFormat); hint != "" {
+					hints = append(hints, fmt.Sprintf("- %s (%s) on %s: %s", e.Name, e.OutputFormat, name, hint))
+				}
+			}
+		}
+	}
+	if len(hints) > 0 {
+		b.WriteString("\n\nWhen a skill's declared output format does not render in a channel, adapt it:\n")
+		b.WriteString(strings.Join(hints, "\n"))
+	}
+	return b.String()
+}
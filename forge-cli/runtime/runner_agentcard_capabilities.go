@@ -0,0 +1,42 @@
+package runtime
+
+import (
+	"encoding/json"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// advertiseAgentCardCapabilities adds what only the assembled runtime
+// knows to the card: the runtime bearer token when no auth chain is
+// advertised, input schemas of tool skills from the tool registry, and
+// the rate limits the server enforces, and read-only mode. Called once the server exists,
+// at startup and on hot-reload, so remote agents can introspect how to
+// call the agent before they do.
+func (r *Runner) advertiseAgentCardCapabilities(card *a2a.AgentCard, rl server.RateLimitConfig) {
+	if card == nil {
+		return
+	}
+	if !r.cfg.NoAuth {
+		coreruntime.PopulateRuntimeTokenScheme(card)
+	}
+	if reg := r.toolRegistry; reg != nil {
+		coreruntime.PopulateToolSchemas(card, func(name string) json.RawMessage {
+			if t := reg.Get(name); t != nil {
+				return t.InputSchema()
+			}
+			return nil
+		})
+	}
+	coreruntime.AdvertiseRateLimit(card, coreruntime.RateLimitPolicy{
+		ReadRPS:      rl.ReadRPS,
+		ReadBurst:    rl.ReadBurst,
+		WriteRPS:     rl.WriteRPS,
+		WriteBurst:   rl.WriteBurst,
+		CancelExempt: rl.CancelExempt,
+	})
+	if r.cfg.Config.IsReadOnly() {
+		coreruntime.AdvertiseReadOnly(card, r.cfg.Config.ReadOnly.SafeCommands)
+	}
+}
@@ -257,7 +257,7 @@ func TestViableEncryptedFileProviders_Categorization(t *testing.T) {
 	}
 
 	var warnings []string
-	got := viableEncryptedFileProviders(workDir, func() (string, error) {
+	got := viableEncryptedFileProviders(&types.ForgeConfig{AgentID: "test-agent"}, workDir, func() (string, error) {
 		return projectPass, nil
 	}, func(msg string, fields map[string]any) {
 		warnings = append(warnings, fields["label"].(string))
@@ -287,7 +287,7 @@ func TestViableEncryptedFileProviders_MissingGlobalIsSilent(t *testing.T) {
 	}
 
 	var warnings []string
-	got := viableEncryptedFileProviders(workDir, func() (string, error) {
+	got := viableEncryptedFileProviders(&types.ForgeConfig{AgentID: "test-agent"}, workDir, func() (string, error) {
 		return projectPass, nil
 	}, func(msg string, fields map[string]any) {
 		warnings = append(warnings, fields["label"].(string))
@@ -300,3 +300,41 @@ func TestViableEncryptedFileProviders_MissingGlobalIsSilent(t *testing.T) {
 		t.Errorf("warnings = %v, want none (missing global should be silent)", warnings)
 	}
 }
+
+// TestViableEncryptedFileProviders_Contexts verifies that the active
+// profile's file is consulted first and that a file bound to another
+// agent is dropped with a warning.
+func TestViableEncryptedFileProviders_Contexts(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	pass := func() (string, error) { return "p", nil }
+
+	seed := func(name, context string, pairs map[string]string) {
+		t.Helper()
+		p := secrets.NewEncryptedFileProvider(filepath.Join(workDir, ".forge", name), pass)
+		p.SetContext(context)
+		if err := p.SetBatch(pairs); err != nil {
+			t.Fatalf("seeding %s: %v", name, err)
+		}
+	}
+	seed("secrets.enc", secrets.AgentContext("billing", ""), map[string]string{"K": "agent"})
+	seed("secrets.prod.enc", secrets.AgentContext("billing", "prod"), map[string]string{"K": "prod"})
+
+	cfg := &types.ForgeConfig{AgentID: "billing", ActiveProfile: "prod"}
+	got := viableEncryptedFileProviders(cfg, workDir, pass, nil)
+	if len(got) != 2 {
+		t.Fatalf("got %d providers, want 2", len(got))
+	}
+	if v, err := secrets.NewChainProvider(got...).Get("K"); err != nil || v != "prod" {
+		t.Errorf("Get(K) = %q, %v; want the profile's value", v, err)
+	}
+
+	var warnings []string
+	other := &types.ForgeConfig{AgentID: "support"}
+	got = viableEncryptedFileProviders(other, workDir, pass, func(msg string, fields map[string]any) {
+		warnings = append(warnings, fields["label"].(string))
+	})
+	if len(got) != 0 || len(warnings) != 1 || warnings[0] != "agent-local" {
+		t.Errorf("another agent: got %d providers, warnings %v", len(got), warnings)
+	}
+}
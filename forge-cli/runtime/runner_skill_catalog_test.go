@@ -1,13 +1,16 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
 	"github.com/initializ/forge/forge-core/tools/builtins"
 	"github.com/initializ/forge/forge-core/types"
 )
@@ -36,7 +39,7 @@ func TestSkillCatalog_AdvertisesLoadableName(t *testing.T) {
 	writeCatalogSkill(t, root, "k8s-incident-triage", "k8s-incident-triage", "k8s_triage")
 
 	r := &Runner{cfg: RunnerConfig{WorkDir: root, Config: &types.ForgeConfig{AgentID: "test"}}}
-	cat := r.buildSkillCatalog()
+	cat := r.buildSkillCatalog(nil)
 
 	// The read_skill key (leading identifier) must be the loadable name.
 	if !strings.Contains(cat, "- k8s-incident-triage:") {
@@ -63,7 +66,7 @@ func TestSkillCatalog_NameIsReadSkillResolvable(t *testing.T) {
 	writeCatalogSkill(t, root, "kube", "cluster-inspector", "inspect")
 
 	r := &Runner{cfg: RunnerConfig{WorkDir: root, Config: &types.ForgeConfig{AgentID: "test"}}}
-	cat := r.buildSkillCatalog()
+	cat := r.buildSkillCatalog(nil)
 
 	readTool := builtins.NewReadSkillTool(root)
 	var checked int
@@ -102,7 +105,7 @@ func TestSkillCatalog_HasRoutingDirective(t *testing.T) {
 	writeCatalogSkill(t, root, "german-brisbane-time", "german-brisbane-time", "brisbane_time")
 
 	r := &Runner{cfg: RunnerConfig{WorkDir: root, Config: &types.ForgeConfig{AgentID: "test"}}}
-	cat := r.buildSkillCatalog()
+	cat := r.buildSkillCatalog(nil)
 
 	for _, want := range []string{
 		"Before answering",                                         // routing happens first
@@ -119,3 +122,80 @@ func TestSkillCatalog_HasRoutingDirective(t *testing.T) {
 		t.Errorf("catalog should still explain how to load a skill; got:\n%s", cat)
 	}
 }
+
+// TestChannelFormatPrompt maps a skill's declared output format onto the
+// active channels that cannot render it, and stays silent without channels.
+func TestChannelFormatPrompt(t *testing.T) {
+	root := t.TempDir()
+	d := filepath.Join(root, "skills", "pod-report")
+	if err := os.MkdirAll(d, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: pod-report\ndescription: Pod status report.\n---\n" +
+		"## Tool: pod_report\n\nReports pods.\n\n**Output format:** Markdown table of pods\n"
+	if err := os.WriteFile(filepath.Join(d, "SKILL.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Runner{cfg: RunnerConfig{WorkDir: root, Config: &types.ForgeConfig{AgentID: "test"}}}
+	if got := r.buildChannelFormatPrompt(); got != "" {
+		t.Errorf("no channels should produce no section; got:\n%s", got)
+	}
+
+	r.cfg.Channels = []string{"slack", "msteams"}
+	got := r.buildChannelFormatPrompt()
+	if !strings.Contains(got, "## Channel formatting") || !strings.Contains(got, "- **slack**:") {
+		t.Errorf("section should list the active channels; got:\n%s", got)
+	}
+	if !strings.Contains(got, "- pod_report (Markdown table of pods) on slack:") {
+		t.Errorf("table output should be mapped for slack; got:\n%s", got)
+	}
+	if !strings.Contains(got, "on msteams:") {
+		t.Errorf("msteams does not render tables either; got:\n%s", got)
+	}
+}
+
+// TestPromptTemplates renders the system prompt from prompts/ templates,
+// including a per-skill catalog line, and reloads it after an edit.
+func TestPromptTemplates(t *testing.T) {
+	root := t.TempDir()
+	writeCatalogSkill(t, root, "k8s-incident-triage", "k8s-incident-triage", "k8s_triage")
+	write := func(name, body string) {
+		path := filepath.Join(root, "prompts", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("skills/k8s-incident-triage.tmpl", "- {{.Name}}: {{.Description}} Use it for every cluster incident.\n")
+
+	var audit bytes.Buffer
+	r := &Runner{
+		cfg:    RunnerConfig{WorkDir: root, Config: &types.ForgeConfig{AgentID: "test"}},
+		logger: coreruntime.NewJSONLogger(io.Discard, false),
+	}
+	if err := r.loadPrompts(); err != nil {
+		t.Fatal(err)
+	}
+	got := r.buildSystemPrompt()
+	if !strings.HasPrefix(got, "You are test, an AI agent.") || !strings.Contains(got, "- k8s-incident-triage: Read-only kubectl triage. Use it for every cluster incident.\n") {
+		t.Errorf("system prompt:\n%s", got)
+	}
+
+	write("system.tmpl", "{{/* version: v2 */}}Agent {{.AgentID}}.\n")
+	r.reloadPrompts(coreruntime.NewAuditLogger(&audit))
+	if got := r.buildSystemPrompt(); got != "Agent test." {
+		t.Errorf("reloaded system prompt = %q", got)
+	}
+	if !strings.Contains(audit.String(), `"event":"prompt_loaded"`) || !strings.Contains(audit.String(), `"prompt_version":"v2"`) {
+		t.Errorf("audit = %s", audit.String())
+	}
+
+	write("system.tmpl", "{{if}}")
+	r.reloadPrompts(nil)
+	if got := r.buildSystemPrompt(); got != "Agent test." {
+		t.Errorf("a broken template replaced the prompt: %q", got)
+	}
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/initializ/forge/forge-core/channels"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// Schedule controls: adapters that implement channels.ScheduleDeliverer
+// (Telegram inline keyboards) show a confirmation when the agent sets a
+// schedule that reports to their channel, attach controls to its run
+// results, and route the user's choice back to ManageSchedule.
+
+// scheduleSetResultRe matches a successful schedule_set result and
+// captures the schedule ID.
+var scheduleSetResultRe = regexp.MustCompile(`^(?:Created|Updated) schedule "([^"]+)"`)
+
+// registerScheduleHooks confirms each schedule the agent sets to the
+// schedule's channel.
+func (r *Runner) registerScheduleHooks(hooks *coreruntime.HookRegistry) {
+	hooks.Register(coreruntime.AfterToolExec, func(ctx context.Context, hctx *coreruntime.HookContext) error {
+		if hctx.ToolName != "schedule_set" || hctx.Error != nil {
+			return nil
+		}
+		m := scheduleSetResultRe.FindStringSubmatch(hctx.ToolOutput)
+		if m == nil {
+			return nil
+		}
+		r.confirmSchedule(ctx, m[1])
+		return nil
+	})
+}
+
+// confirmSchedule sends a schedule's confirmation through the schedule
+// notifier. No-op when the schedule has no channel or no adapters run.
+func (r *Runner) confirmSchedule(ctx context.Context, id string) {
+	if r.scheduleNotifier == nil || r.schedBackend == nil {
+		return
+	}
+	sched, err := r.schedBackend.Get(ctx, id)
+	if err != nil || sched == nil || sched.Channel == "" || sched.ChannelTarget == "" {
+		return
+	}
+	if err := r.scheduleNotifier(ctx, *sched, nil); err != nil {
+		r.logger.Warn("failed to confirm schedule to channel", map[string]any{
+			"schedule_id": id,
+			"channel":     sched.Channel,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// ManageSchedule applies a schedule action taken from a channel: pause,
+// resume or delete. Schedules declared in forge.yaml are refused, like
+// the schedule tools refuse them — the next sync would undo the change.
+// Wired into adapters as their channels.ScheduleManager.
+func (r *Runner) ManageSchedule(ctx context.Context, a channels.ScheduleAction) error {
+	if r.schedBackend == nil {
+		return fmt.Errorf("scheduler is not running")
+	}
+	sched, err := r.schedBackend.Get(ctx, a.ScheduleID)
+	if err != nil {
+		return fmt.Errorf("looking up schedule: %w", err)
+	}
+	if sched == nil {
+		return fmt.Errorf("schedule %q not found", a.ScheduleID)
+	}
+	if sched.Source == "yaml" {
+		return fmt.Errorf("schedule %q is defined in forge.yaml; change it there", a.ScheduleID)
+	}
+
+	switch a.Action {
+	case "pause", "resume":
+		sched.Enabled = a.Action == "resume"
+		err = r.schedBackend.Set(ctx, *sched)
+	case "delete":
+		err = r.schedBackend.Delete(ctx, a.ScheduleID)
+	default:
+		return fmt.Errorf("unknown schedule action %q", a.Action)
+	}
+	if err != nil {
+		return fmt.Errorf("%s schedule %q: %w", a.Action, a.ScheduleID, err)
+	}
+	r.schedBackend.Reload(ctx)
+
+	fields := map[string]any{"action": a.Action, "actor": a.Actor, "channel": sched.Channel}
+	if r.schedAudit != nil {
+		r.schedAudit(coreruntime.AuditScheduleModify, a.ScheduleID, fields)
+	}
+	r.logger.Info("schedule changed from channel", map[string]any{
+		"schedule_id": a.ScheduleID, "action": a.Action, "actor": a.Actor, "channel": sched.Channel,
+	})
+	return nil
+}
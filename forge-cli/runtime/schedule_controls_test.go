@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/scheduler"
+)
+
+func newScheduleControlsRunner(t *testing.T) *Runner {
+	t.Helper()
+	store := NewMemoryScheduleStore(filepath.Join(t.TempDir(), "SCHEDULES.md"))
+	sched := scheduler.New(store, func(context.Context, scheduler.Schedule) error { return nil }, coreruntime.NewJSONLogger(io.Discard, false), nil)
+	r := &Runner{logger: coreruntime.NewJSONLogger(io.Discard, false), schedBackend: scheduler.NewFileBackend(store, sched)}
+	ctx := context.Background()
+	for _, s := range []scheduler.Schedule{
+		{ID: "daily-report", Cron: "@daily", Task: "report", Channel: "telegram", ChannelTarget: "42", Source: "llm", Enabled: true},
+		{ID: "declared", Cron: "@hourly", Task: "check", Source: "yaml", Enabled: true},
+	} {
+		if err := r.schedBackend.Set(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func TestManageSchedule(t *testing.T) {
+	ctx := context.Background()
+	r := newScheduleControlsRunner(t)
+	var audited []string
+	r.schedAudit = func(event, id string, fields map[string]any) {
+		audited = append(audited, event+":"+id+":"+fields["action"].(string)+":"+fields["actor"].(string))
+	}
+
+	if err := r.ManageSchedule(ctx, channels.ScheduleAction{ScheduleID: "daily-report", Action: "pause", Actor: "@ops"}); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if s, _ := r.schedBackend.Get(ctx, "daily-report"); s == nil || s.Enabled {
+		t.Fatalf("after pause: %+v", s)
+	}
+	if err := r.ManageSchedule(ctx, channels.ScheduleAction{ScheduleID: "daily-report", Action: "resume", Actor: "@ops"}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if s, _ := r.schedBackend.Get(ctx, "daily-report"); s == nil || !s.Enabled {
+		t.Fatalf("after resume: %+v", s)
+	}
+	if err := r.ManageSchedule(ctx, channels.ScheduleAction{ScheduleID: "daily-report", Action: "delete", Actor: "@ops"}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if s, _ := r.schedBackend.Get(ctx, "daily-report"); s != nil {
+		t.Fatalf("after delete: %+v", s)
+	}
+	want := "schedule_modify:daily-report:pause:@ops,schedule_modify:daily-report:resume:@ops,schedule_modify:daily-report:delete:@ops"
+	if got := strings.Join(audited, ","); got != want {
+		t.Errorf("audit = %s, want %s", got, want)
+	}
+
+	for _, tc := range []struct {
+		action channels.ScheduleAction
+		want   string
+	}{
+		{channels.ScheduleAction{ScheduleID: "declared", Action: "pause"}, "forge.yaml"},
+		{channels.ScheduleAction{ScheduleID: "missing", Action: "pause"}, "not found"},
+		{channels.ScheduleAction{ScheduleID: "declared", Action: "run"}, "forge.yaml"},
+	} {
+		err := r.ManageSchedule(ctx, tc.action)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: err = %v, want %q", tc.action, err, tc.want)
+		}
+	}
+	if s, _ := r.schedBackend.Get(ctx, "declared"); s == nil || !s.Enabled {
+		t.Errorf("yaml schedule changed: %+v", s)
+	}
+}
+
+func TestScheduleSetConfirmation(t *testing.T) {
+	r := newScheduleControlsRunner(t)
+	var confirmed []string
+	r.SetScheduleNotifier(func(_ context.Context, sched scheduler.Schedule, resp *a2a.Message) error {
+		if resp != nil {
+			t.Errorf("confirmation carried a response")
+		}
+		confirmed = append(confirmed, sched.ID+"@"+sched.ChannelTarget)
+		return nil
+	})
+	hooks := coreruntime.NewHookRegistry()
+	r.registerScheduleHooks(hooks)
+
+	for _, hctx := range []*coreruntime.HookContext{
+		{ToolName: "schedule_set", ToolOutput: "Created schedule \"daily-report\".\nCron: @daily"},
+		{ToolName: "schedule_set", ToolOutput: "Updated schedule \"declared\".\nCron: @hourly"}, // no channel
+		{ToolName: "schedule_list", ToolOutput: "Created schedule \"daily-report\"."},
+	} {
+		if err := hooks.Fire(context.Background(), coreruntime.AfterToolExec, hctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := strings.Join(confirmed, ","); got != "daily-report@42" {
+		t.Errorf("confirmed = %q, want daily-report@42", got)
+	}
+}
@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/scheduler"
+)
+
+// scheduleParsePreviewRuns is how many upcoming fire times POST
+// /schedules/parse returns, so callers can show the user what they are
+// about to commit.
+const scheduleParsePreviewRuns = 3
+
+// scheduleParseRequest is the POST /schedules/parse body. Timezone is
+// the caller's zone, used when the text names none.
+type scheduleParseRequest struct {
+	Text     string `json:"text"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// scheduleParseResponse is a validated schedule plus a preview of its
+// next fire times in its zone.
+type scheduleParseResponse struct {
+	*scheduler.NaturalSchedule
+	NextRuns []string `json:"next_runs"`
+}
+
+// registerScheduleParseEndpoint wires POST /schedules/parse. Parsing is
+// stateless, so it is served whether or not a scheduler is running.
+func (r *Runner) registerScheduleParseEndpoint(srv *server.Server) {
+	srv.RegisterHTTPHandler("POST /schedules/parse", makeScheduleParseHandler(time.Now))
+}
+
+// makeScheduleParseHandler is extracted so tests can exercise the
+// handler with a fixed clock. 400 when the text does not resolve to a
+// valid schedule; ambiguities are reported as warnings with a 200.
+func makeScheduleParseHandler(now func() time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var body scheduleParseRequest
+		dec := json.NewDecoder(req.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&body); err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+			return
+		}
+		if body.Text == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "text is required"})
+			return
+		}
+		ns, err := scheduler.ParseNatural(body.Text, body.Timezone)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		parsed, err := scheduler.ParseIn(ns.Cron, ns.Timezone)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		loc := time.UTC
+		if ns.Timezone != "" {
+			if l, err := scheduler.LoadTimezone(ns.Timezone); err == nil {
+				loc = l
+			}
+		}
+		resp := scheduleParseResponse{NaturalSchedule: ns, NextRuns: []string{}}
+		t := now()
+		for range scheduleParsePreviewRuns {
+			t = parsed.Next(t)
+			if t.IsZero() {
+				break
+			}
+			resp.NextRuns = append(resp.NextRuns, t.In(loc).Format(time.RFC3339))
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleParseHandler(t *testing.T) {
+	// Friday 2026-03-06 15:00 UTC; US daylight saving starts that Sunday.
+	now := func() time.Time { return time.Date(2026, 3, 6, 15, 0, 0, 0, time.UTC) }
+	h := makeScheduleParseHandler(now)
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/schedules/parse",
+		strings.NewReader(`{"text":"every weekday at 9am Eastern"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Cron     string   `json:"cron"`
+		Timezone string   `json:"timezone"`
+		Warnings []string `json:"warnings"`
+		NextRuns []string `json:"next_runs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Cron != "0 9 * * 1-5" || resp.Timezone != "America/New_York" {
+		t.Errorf("got %q in %q", resp.Cron, resp.Timezone)
+	}
+	want := []string{"2026-03-09T09:00:00-04:00", "2026-03-10T09:00:00-04:00", "2026-03-11T09:00:00-04:00"}
+	if strings.Join(resp.NextRuns, ",") != strings.Join(want, ",") {
+		t.Errorf("next_runs = %v, want %v", resp.NextRuns, want)
+	}
+
+	// The caller's zone applies when the text names none.
+	rec = httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/schedules/parse",
+		strings.NewReader(`{"text":"every day at 9","timezone":"Europe/Paris"}`)))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"timezone":"Europe/Paris"`) ||
+		!strings.Contains(rec.Body.String(), "am/pm") {
+		t.Errorf("default zone: %d %s", rec.Code, rec.Body)
+	}
+
+	for _, body := range []string{`{"text":""}`, `{"text":"whenever you like"}`, `{"text":"daily","tz":"UTC"}`} {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodPost, "/schedules/parse", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -38,6 +40,7 @@ const (
 	annotationChannelTarget = "forge.schedule.channel_target"
 	annotationRunCount      = "forge.schedule.run_count"
 	annotationLastStatus    = "forge.schedule.last_status"
+	annotationOverlap       = "forge.schedule.overlap"
 )
 
 // KubernetesBackend implements scheduler.Backend by delegating
@@ -190,7 +193,19 @@ func (b *KubernetesBackend) Reload(_ context.Context) {}
 //     via Set / Delete.
 //
 // Mirrors the FileBackend.Sync rule from forge-core/scheduler/backend.go.
+// Workflow schedules are skipped with a warning: a CronJob trigger pod
+// can only POST a task, and workflows run in-process.
 func (b *KubernetesBackend) Sync(ctx context.Context, declared []scheduler.Schedule) error {
+	declared = slices.DeleteFunc(slices.Clone(declared), func(s scheduler.Schedule) bool {
+		if s.Workflow == "" {
+			return false
+		}
+		b.logger.Warn("workflow schedules are not supported on the kubernetes scheduler backend; skipping", map[string]any{
+			"schedule_id": s.ID,
+			"workflow":    s.Workflow,
+		})
+		return true
+	})
 	declaredByID := make(map[string]scheduler.Schedule, len(declared))
 	for _, s := range declared {
 		if s.Source == "" {
@@ -295,6 +310,10 @@ func (b *KubernetesBackend) Set(ctx context.Context, s scheduler.Schedule) error
 	if s.Source == scheduler.SourceLLM && !b.cfg.AllowDynamic {
 		return fmt.Errorf("dynamic schedule creation is disabled (scheduler.kubernetes.allow_dynamic=false); declare the schedule in forge.yaml or enable allow_dynamic")
 	}
+	// A CronJob's trigger pod calls the agent outside any tenant.
+	if s.Tenant != "" {
+		return fmt.Errorf("tenant schedules need the file scheduler backend; the kubernetes backend runs schedules outside any tenant")
+	}
 	want := b.cronJobFromSchedule(s)
 	cur, err := b.client.BatchV1().CronJobs(b.namespace).Get(ctx, want.Name, metav1.GetOptions{})
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -366,7 +385,7 @@ func (b *KubernetesBackend) cronJobFromSchedule(s scheduler.Schedule) *batchv1.C
 		enabled = true
 	}
 	suspend := !enabled
-	concurrencyForbid := batchv1.ForbidConcurrent
+	concurrency := batchv1.ConcurrencyPolicy(scheduler.ConcurrencyPolicy(s.Overlap))
 	successHistory := int32(3)
 	failHistory := int32(3)
 	cj := &batchv1.CronJob{
@@ -384,13 +403,20 @@ func (b *KubernetesBackend) cronJobFromSchedule(s scheduler.Schedule) *batchv1.C
 		},
 		Spec: batchv1.CronJobSpec{
 			Schedule:                   s.Cron,
-			ConcurrencyPolicy:          concurrencyForbid,
+			ConcurrencyPolicy:          concurrency,
 			SuccessfulJobsHistoryLimit: &successHistory,
 			FailedJobsHistoryLimit:     &failHistory,
 			Suspend:                    &suspend,
 			JobTemplate:                batchv1.JobTemplateSpec{Spec: b.jobSpec(s)},
 		},
 	}
+	if s.Timezone != "" {
+		tz := s.Timezone
+		cj.Spec.TimeZone = &tz
+	}
+	if s.Overlap != "" {
+		cj.Annotations[annotationOverlap] = s.Overlap
+	}
 	if s.Skill != "" {
 		cj.Annotations[annotationSkill] = s.Skill
 	}
@@ -419,7 +445,7 @@ func (b *KubernetesBackend) jobSpec(s scheduler.Schedule) batchv1.JobSpec {
 		`curl -sfX POST %s -H "Authorization: Bearer $FORGE_AUTH_TOKEN" -H "X-Forge-Schedule-Id: %s" -H "Content-Type: application/json" --data '{"jsonrpc":"2.0","id":"1","method":"tasks/send","params":{"id":"sched-%s-'"$(date +%%s)"'","message":{"role":"user","parts":[{"type":"text","text":"%s"}]}}}'`,
 		b.cfg.ServiceURL, s.ID, s.ID, shellEscapeSingleQuoted(s.Task),
 	)
-	return batchv1.JobSpec{
+	spec := batchv1.JobSpec{
 		Template: corev1.PodTemplateSpec{
 			Spec: corev1.PodSpec{
 				RestartPolicy: restartNever,
@@ -445,6 +471,10 @@ func (b *KubernetesBackend) jobSpec(s scheduler.Schedule) batchv1.JobSpec {
 			},
 		},
 	}
+	if secs := scheduler.JobDeadlineSeconds(s.MaxRuntime); secs > 0 {
+		spec.ActiveDeadlineSeconds = &secs
+	}
+	return spec
 }
 
 // scheduleFromCronJob materializes a scheduler.Schedule from the
@@ -466,18 +496,42 @@ func scheduleFromCronJob(cj *batchv1.CronJob) (scheduler.Schedule, bool) {
 		Enabled:       cj.Spec.Suspend == nil || !*cj.Spec.Suspend,
 		Created:       cj.CreationTimestamp.Time,
 		LastStatus:    cj.Annotations[annotationLastStatus],
+		Overlap:       cj.Annotations[annotationOverlap],
+	}
+	if d := cj.Spec.JobTemplate.Spec.ActiveDeadlineSeconds; d != nil {
+		s.MaxRuntime = time.Duration(*d) * time.Second
 	}
 	if v := cj.Annotations[annotationRunCount]; v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
 			s.RunCount = n
 		}
 	}
+	if cj.Spec.TimeZone != nil {
+		s.Timezone = *cj.Spec.TimeZone
+	}
 	if cj.Status.LastScheduleTime != nil {
 		s.LastRun = cj.Status.LastScheduleTime.Time
 	}
 	return s, true
 }
 
+// cronJobTimeZone returns the spec's time zone, "" when unset (UTC).
+func cronJobTimeZone(spec batchv1.CronJobSpec) string {
+	if spec.TimeZone == nil {
+		return ""
+	}
+	return *spec.TimeZone
+}
+
+// jobDeadline returns the trigger Job's activeDeadlineSeconds, 0 when
+// unset.
+func jobDeadline(spec batchv1.CronJobSpec) int64 {
+	if spec.JobTemplate.Spec.ActiveDeadlineSeconds == nil {
+		return 0
+	}
+	return *spec.JobTemplate.Spec.ActiveDeadlineSeconds
+}
+
 // cronJobNeedsUpdate compares the spec-relevant fields of two CronJob
 // objects. Returns true when the runtime should issue an Update.
 // Status fields and resource versions are ignored — those are the
@@ -486,6 +540,18 @@ func cronJobNeedsUpdate(cur batchv1.CronJob, want *batchv1.CronJob) bool {
 	if cur.Spec.Schedule != want.Spec.Schedule {
 		return true
 	}
+	if cronJobTimeZone(cur.Spec) != cronJobTimeZone(want.Spec) {
+		return true
+	}
+	if cur.Spec.ConcurrencyPolicy != want.Spec.ConcurrencyPolicy {
+		return true
+	}
+	if jobDeadline(cur.Spec) != jobDeadline(want.Spec) {
+		return true
+	}
+	if cur.Annotations[annotationOverlap] != want.Annotations[annotationOverlap] {
+		return true
+	}
 	if (cur.Spec.Suspend == nil) != (want.Spec.Suspend == nil) {
 		return true
 	}
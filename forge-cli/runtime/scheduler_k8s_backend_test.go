@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -64,10 +65,65 @@ func TestKubernetesBackend_SyncCreatesCronJobsForDeclared(t *testing.T) {
 	}
 }
 
+// TestKubernetesBackend_OverlapAndMaxRuntime verifies the run policies
+// map onto the CronJob (Replace, activeDeadlineSeconds) and read back.
+func TestKubernetesBackend_OverlapAndMaxRuntime(t *testing.T) {
+	b, cs := newTestK8sBackend(t, K8sBackendConfig{})
+	ctx := context.Background()
+
+	declared := []scheduler.Schedule{{
+		ID: "report", Cron: "@hourly", Task: "t", Source: scheduler.SourceYAML, Enabled: true,
+		MaxRuntime: 90 * time.Second, Overlap: scheduler.OverlapCancelPrevious,
+	}}
+	if err := b.Sync(ctx, declared); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	cj, err := cs.BatchV1().CronJobs("default").Get(ctx, scheduler.CronJobName("test-agent", "report"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get cronjob: %v", err)
+	}
+	if cj.Spec.ConcurrencyPolicy != batchv1.ReplaceConcurrent {
+		t.Errorf("concurrency = %q, want Replace", cj.Spec.ConcurrencyPolicy)
+	}
+	if d := cj.Spec.JobTemplate.Spec.ActiveDeadlineSeconds; d == nil || *d != 90 {
+		t.Errorf("activeDeadlineSeconds = %v, want 90", d)
+	}
+
+	got, err := b.Get(ctx, "report")
+	if err != nil || got == nil {
+		t.Fatalf("Get: %v %v", got, err)
+	}
+	if got.MaxRuntime != 90*time.Second || got.Overlap != scheduler.OverlapCancelPrevious {
+		t.Errorf("round trip = %s / %q", got.MaxRuntime, got.Overlap)
+	}
+}
+
 // TestKubernetesBackend_SyncIdempotent verifies that re-running Sync
 // with the same declared set does not churn CronJobs (no spurious
 // Updates). Reconciliation is measured by counting Update actions on
 // the fake clientset's action recorder.
+// TestKubernetesBackend_SyncSkipsWorkflowSchedules verifies workflow
+// schedules get no CronJob: trigger pods can only POST a task.
+func TestKubernetesBackend_SyncSkipsWorkflowSchedules(t *testing.T) {
+	b, cs := newTestK8sBackend(t, K8sBackendConfig{})
+	ctx := context.Background()
+
+	declared := []scheduler.Schedule{
+		{ID: "digest", Cron: "@daily", Task: "t", Source: scheduler.SourceYAML, Enabled: true},
+		{ID: "triage", Cron: "@hourly", Workflow: "triage", Source: scheduler.SourceYAML, Enabled: true},
+	}
+	if err := b.Sync(ctx, declared); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	list, err := cs.BatchV1().CronJobs("default").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Labels[labelScheduleID] != "digest" {
+		t.Errorf("cronjobs = %d, want only digest", len(list.Items))
+	}
+}
+
 func TestKubernetesBackend_SyncIdempotent(t *testing.T) {
 	b, cs := newTestK8sBackend(t, K8sBackendConfig{})
 	ctx := context.Background()
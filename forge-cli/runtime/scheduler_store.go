@@ -261,6 +261,10 @@ func parseScheduleField(sched *scheduler.Schedule, line string) {
 		sched.Cron = value
 	case "Task":
 		sched.Task = value
+	case "Workflow":
+		sched.Workflow = value
+	case "Tenant":
+		sched.Tenant = value
 	case "Skill":
 		sched.Skill = value
 	case "Channel":
@@ -340,6 +344,12 @@ func renderSchedulesMD(schedules []scheduler.Schedule, history []scheduler.Histo
 		fmt.Fprintf(&b, "- **ID:** %s\n", sched.ID)
 		fmt.Fprintf(&b, "- **Cron:** %s\n", sched.Cron)
 		fmt.Fprintf(&b, "- **Task:** %s\n", sched.Task)
+		if sched.Workflow != "" {
+			fmt.Fprintf(&b, "- **Workflow:** %s\n", sched.Workflow)
+		}
+		if sched.Tenant != "" {
+			fmt.Fprintf(&b, "- **Tenant:** %s\n", sched.Tenant)
+		}
 		if sched.Skill != "" {
 			fmt.Fprintf(&b, "- **Skill:** %s\n", sched.Skill)
 		}
@@ -232,6 +232,40 @@ func TestMemoryScheduleStore_ChannelFieldsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestMemoryScheduleStore_WorkflowRoundTrip(t *testing.T) {
+	store, _ := testStore(t)
+	ctx := context.Background()
+
+	sched := scheduler.Schedule{ID: "triage", Cron: "@hourly", Workflow: "triage", Source: "yaml", Enabled: true}
+	if err := store.Set(ctx, sched); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(ctx, "triage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Workflow != "triage" {
+		t.Fatalf("schedule = %+v, want workflow triage", got)
+	}
+}
+
+func TestMemoryScheduleStore_TenantRoundTrip(t *testing.T) {
+	store, _ := testStore(t)
+	ctx := context.Background()
+
+	sched := scheduler.Schedule{ID: "acme.digest", Cron: "@daily", Task: "digest", Source: "llm", Enabled: true, Tenant: "acme"}
+	if err := store.Set(ctx, sched); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(ctx, "acme.digest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Tenant != "acme" {
+		t.Fatalf("schedule = %+v, want tenant acme", got)
+	}
+}
+
 func TestMemoryScheduleStore_MissingFileCreation(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "deep", "nested", "dir", "SCHEDULES.md")
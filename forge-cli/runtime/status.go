@@ -0,0 +1,357 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/scheduler"
+)
+
+// Live status: a monitor folds the event bus into the counters behind
+// GET /metrics (Prometheus text format) and keeps the recent events
+// GET /events streams as server-sent events. `forge status` renders
+// both for terminal users; any Prometheus scraper can read the former.
+
+const (
+	// statusRecentEvents is how many events a new /events client is
+	// replayed before the live stream.
+	statusRecentEvents = 100
+	// statusWatchQueue is how far an /events client may fall behind
+	// before events are dropped for it.
+	statusWatchQueue = 64
+	// statusKeepAlive is the SSE comment interval that keeps idle
+	// proxies from closing an /events stream.
+	statusKeepAlive = 15 * time.Second
+	// statusErrorLen caps the error text an event carries.
+	statusErrorLen = 200
+)
+
+// statusTopics are the bus topics the monitor observes.
+var statusTopics = []coreruntime.EventTopic{
+	coreruntime.TopicTaskStarted, coreruntime.TopicTaskFinished,
+	coreruntime.TopicToolStart, coreruntime.TopicToolEnd,
+	coreruntime.TopicLLMCall, coreruntime.TopicLoopError,
+	coreruntime.TopicScheduleFired, coreruntime.TopicScheduleCompleted,
+	coreruntime.TopicGuardrailHit, coreruntime.TopicEgressBlocked,
+}
+
+// statusEvent is a bus event as GET /events sends it. It carries what a
+// dashboard shows and never tool input, tool output or model text.
+type statusEvent struct {
+	Topic      string         `json:"topic"`
+	Time       time.Time      `json:"time"`
+	TaskID     string         `json:"task_id,omitempty"`
+	Tool       string         `json:"tool,omitempty"`
+	Model      string         `json:"model,omitempty"`
+	Tokens     int            `json:"tokens,omitempty"`
+	DurationMs int64          `json:"duration_ms,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Fields     map[string]any `json:"fields,omitempty"`
+}
+
+// runningTask is a task that has started and not finished.
+type runningTask struct {
+	TaskID  string    `json:"task_id"`
+	Started time.Time `json:"started"`
+	// Tool is the tool the task is running, or ran last.
+	Tool      string `json:"tool,omitempty"`
+	ToolCalls int    `json:"tool_calls"`
+}
+
+// statusSnapshot is the first event of every /events stream: the state
+// the replayed events may no longer cover.
+type statusSnapshot struct {
+	Running []runningTask `json:"running"`
+}
+
+// statusMonitor is the state behind /metrics and /events.
+type statusMonitor struct {
+	mu            sync.Mutex
+	running       map[string]*runningTask
+	tasksTotal    int
+	tasksFailed   int
+	toolCalls     map[string]int
+	toolErrors    map[string]int
+	day           string // UTC date the daily counters cover
+	dayTokens     int
+	dayCost       float64
+	guardrailHits map[string]int // by decision
+	egressBlocked int
+	recent        []statusEvent // oldest first
+	watchers      map[chan statusEvent]struct{}
+}
+
+func newStatusMonitor() *statusMonitor {
+	return &statusMonitor{
+		running:       map[string]*runningTask{},
+		toolCalls:     map[string]int{},
+		toolErrors:    map[string]int{},
+		guardrailHits: map[string]int{},
+		watchers:      map[chan statusEvent]struct{}{},
+	}
+}
+
+// startStatusMonitor subscribes the monitor to the event bus and
+// registers GET /metrics and GET /events. Streams end with ctx.
+func (r *Runner) startStatusMonitor(ctx context.Context, srv *server.Server) {
+	m := newStatusMonitor()
+	r.events.Subscribe(m.observe, statusTopics...)
+
+	srv.RegisterHTTPHandler("GET /metrics", func(w http.ResponseWriter, req *http.Request) {
+		var schedules []scheduler.Schedule
+		if r.schedBackend != nil {
+			list, err := r.schedBackend.List(req.Context())
+			if err != nil {
+				r.logger.Warn("metrics: listing schedules failed", map[string]any{"error": err.Error()})
+			}
+			schedules = list
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.writeMetrics(w, time.Now(), time.Since(r.startTime), schedules)
+	})
+	srv.RegisterHTTPHandler("GET /events", func(w http.ResponseWriter, req *http.Request) {
+		m.serveEvents(ctx, w, req)
+	})
+}
+
+// observe folds an event into the counters and forwards it to the
+// /events clients.
+func (m *statusMonitor) observe(_ context.Context, e coreruntime.Event) {
+	se := statusEvent{Topic: string(e.Topic), Time: e.Time, TaskID: e.TaskID}
+	if len(e.Fields) > 0 {
+		se.Fields = maps.Clone(e.Fields)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch e.Topic {
+	case coreruntime.TopicTaskStarted:
+		m.running[e.TaskID] = &runningTask{TaskID: e.TaskID, Started: e.Time}
+	case coreruntime.TopicTaskFinished:
+		delete(m.running, e.TaskID)
+		m.tasksTotal++
+		if state, _ := e.Fields["state"].(string); state == string(a2a.TaskStateFailed) {
+			m.tasksFailed++
+		}
+		se.DurationMs, _ = e.Fields["duration_ms"].(int64)
+	case coreruntime.TopicToolStart:
+		if e.Hook == nil {
+			return
+		}
+		se.Tool = e.Hook.ToolName
+		if t := m.running[e.TaskID]; t != nil {
+			t.Tool = e.Hook.ToolName
+			t.ToolCalls++
+		}
+	case coreruntime.TopicToolEnd:
+		if e.Hook == nil {
+			return
+		}
+		se.Tool = e.Hook.ToolName
+		se.DurationMs = e.Hook.ToolExecDuration.Milliseconds()
+		m.toolCalls[e.Hook.ToolName]++
+		if e.Hook.Error != nil {
+			m.toolErrors[e.Hook.ToolName]++
+			se.Error = truncateStatus(e.Hook.Error.Error())
+		}
+	case coreruntime.TopicLLMCall:
+		if e.Hook == nil || e.Hook.Response == nil {
+			return
+		}
+		model, provider := e.Hook.Model, e.Hook.Provider
+		if rt := e.Hook.Response.Route; rt != nil {
+			model, provider = rt.Model, rt.Provider
+		}
+		var call coreruntime.UsageLedger
+		call.Add(provider, model, e.Hook.Response.Usage)
+		m.rollDay(e.Time)
+		m.dayTokens += call.TotalTokens
+		m.dayCost += call.EstimatedCostUSD
+		se.Model = model
+		se.Tokens = call.TotalTokens
+		se.DurationMs = e.Hook.LLMCallDuration.Milliseconds()
+	case coreruntime.TopicLoopError:
+		if e.Hook != nil && e.Hook.Error != nil {
+			se.Error = truncateStatus(e.Hook.Error.Error())
+		}
+	case coreruntime.TopicGuardrailHit:
+		decision, _ := e.Fields["decision"].(string)
+		m.guardrailHits[decision]++
+	case coreruntime.TopicEgressBlocked:
+		m.egressBlocked++
+	}
+
+	m.recent = append(m.recent, se)
+	if over := len(m.recent) - statusRecentEvents; over > 0 {
+		m.recent = slices.Delete(m.recent, 0, over)
+	}
+	for ch := range m.watchers {
+		select {
+		case ch <- se:
+		default: // a slow client misses events rather than stalling others
+		}
+	}
+}
+
+// rollDay resets the daily counters at midnight UTC. The caller holds mu.
+func (m *statusMonitor) rollDay(t time.Time) {
+	if day := t.UTC().Format(time.DateOnly); day != m.day {
+		m.day, m.dayTokens, m.dayCost = day, 0, 0
+	}
+}
+
+// watch registers an /events client and returns its channel, the
+// current snapshot and the events to replay. stop unregisters it.
+func (m *statusMonitor) watch() (ch chan statusEvent, snap statusSnapshot, recent []statusEvent, stop func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch = make(chan statusEvent, statusWatchQueue)
+	m.watchers[ch] = struct{}{}
+	snap.Running = make([]runningTask, 0, len(m.running))
+	for _, t := range m.running {
+		snap.Running = append(snap.Running, *t)
+	}
+	slices.SortFunc(snap.Running, func(a, b runningTask) int { return a.Started.Compare(b.Started) })
+	return ch, snap, slices.Clone(m.recent), func() {
+		m.mu.Lock()
+		delete(m.watchers, ch)
+		m.mu.Unlock()
+	}
+}
+
+// serveEvents streams a "snapshot" event, the recent events, and then
+// live events as "event" until the client goes away or ctx ends.
+func (m *statusMonitor) serveEvents(ctx context.Context, w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming not supported"})
+		return
+	}
+	ch, snap, recent, stop := m.watch()
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	if err := server.WriteSSEEvent(w, flusher, "snapshot", snap); err != nil {
+		return
+	}
+	for _, e := range recent {
+		if err := server.WriteSSEEvent(w, flusher, "event", e); err != nil {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(statusKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-req.Context().Done():
+			return
+		case e := <-ch:
+			if err := server.WriteSSEEvent(w, flusher, "event", e); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeMetrics renders the counters in the Prometheus text format.
+// schedules supplies the next-run gauges; disabled ones are skipped.
+func (m *statusMonitor) writeMetrics(w io.Writer, now time.Time, uptime time.Duration, schedules []scheduler.Schedule) {
+	m.mu.Lock()
+	m.rollDay(now)
+	running, total, failed := len(m.running), m.tasksTotal, m.tasksFailed
+	toolCalls, toolErrors := maps.Clone(m.toolCalls), maps.Clone(m.toolErrors)
+	tokens, cost := m.dayTokens, m.dayCost
+	hits, egress := maps.Clone(m.guardrailHits), m.egressBlocked
+	m.mu.Unlock()
+
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	metric("forge_uptime_seconds", "gauge", "Seconds since the agent started.")
+	fmt.Fprintf(w, "forge_uptime_seconds %d\n", int64(uptime.Seconds()))
+	metric("forge_tasks_running", "gauge", "Tasks currently running.")
+	fmt.Fprintf(w, "forge_tasks_running %d\n", running)
+	metric("forge_tasks_total", "counter", "Tasks finished since the agent started.")
+	fmt.Fprintf(w, "forge_tasks_total %d\n", total)
+	metric("forge_tasks_failed_total", "counter", "Tasks that finished in the failed state.")
+	fmt.Fprintf(w, "forge_tasks_failed_total %d\n", failed)
+	metric("forge_tool_calls_total", "counter", "Tool calls by tool.")
+	for _, tool := range slices.Sorted(maps.Keys(toolCalls)) {
+		fmt.Fprintf(w, "forge_tool_calls_total{tool=%s} %d\n", promLabel(tool), toolCalls[tool])
+	}
+	metric("forge_tool_errors_total", "counter", "Tool calls that returned an error, by tool.")
+	for _, tool := range slices.Sorted(maps.Keys(toolErrors)) {
+		fmt.Fprintf(w, "forge_tool_errors_total{tool=%s} %d\n", promLabel(tool), toolErrors[tool])
+	}
+	metric("forge_tokens_today", "gauge", "LLM tokens used since midnight UTC.")
+	fmt.Fprintf(w, "forge_tokens_today %d\n", tokens)
+	metric("forge_cost_today_usd", "gauge", "Estimated LLM cost since midnight UTC, in US dollars.")
+	fmt.Fprintf(w, "forge_cost_today_usd %s\n", strconv.FormatFloat(cost, 'f', -1, 64))
+	metric("forge_guardrail_hits_total", "counter", "Guardrail hits by decision.")
+	for _, decision := range slices.Sorted(maps.Keys(hits)) {
+		fmt.Fprintf(w, "forge_guardrail_hits_total{decision=%s} %d\n", promLabel(decision), hits[decision])
+	}
+	metric("forge_egress_blocked_total", "counter", "Outbound connections refused by the egress allowlist.")
+	fmt.Fprintf(w, "forge_egress_blocked_total %d\n", egress)
+	metric("forge_schedule_next_run_timestamp_seconds", "gauge", "Unix time of each enabled schedule's next run.")
+	for _, s := range schedules {
+		if !s.Enabled {
+			continue
+		}
+		ps, err := scheduler.ParseIn(s.Cron, s.Timezone)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(w, "forge_schedule_next_run_timestamp_seconds{schedule=%s} %d\n", promLabel(s.ID), ps.Next(now).Unix())
+	}
+}
+
+// promLabel quotes a label value for the Prometheus text format.
+func promLabel(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+// truncateStatus caps an event's error text at statusErrorLen runes.
+func truncateStatus(s string) string {
+	r := []rune(s)
+	if len(r) <= statusErrorLen {
+		return s
+	}
+	return string(r[:statusErrorLen]) + "…"
+}
+
+// publishEgressBlocked puts a refused outbound connection on the event
+// bus. taskID and correlationID default to ctx's.
+func (r *Runner) publishEgressBlocked(ctx context.Context, taskID, correlationID, domain, mode, source string) {
+	if taskID == "" {
+		taskID = coreruntime.TaskIDFromContext(ctx)
+	}
+	if correlationID == "" {
+		correlationID = coreruntime.CorrelationIDFromContext(ctx)
+	}
+	r.events.Publish(ctx, coreruntime.Event{
+		Topic:         coreruntime.TopicEgressBlocked,
+		TaskID:        taskID,
+		CorrelationID: correlationID,
+		Fields:        map[string]any{"domain": domain, "mode": mode, "source": source},
+	})
+}
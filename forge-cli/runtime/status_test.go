@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/scheduler"
+)
+
+func TestStatusMonitor_Metrics(t *testing.T) {
+	m := newStatusMonitor()
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for _, e := range []coreruntime.Event{
+		{Topic: coreruntime.TopicTaskStarted, TaskID: "t1", Time: now},
+		{Topic: coreruntime.TopicTaskStarted, TaskID: "t2", Time: now},
+		{Topic: coreruntime.TopicToolEnd, TaskID: "t1", Time: now, Hook: &coreruntime.HookContext{ToolName: "http_request"}},
+		{Topic: coreruntime.TopicToolEnd, TaskID: "t1", Time: now, Hook: &coreruntime.HookContext{ToolName: "http_request", Error: errors.New("timeout")}},
+		{Topic: coreruntime.TopicLLMCall, Time: now, Hook: &coreruntime.HookContext{
+			Response: &llm.ChatResponse{Usage: llm.UsageInfo{TotalTokens: 1500}},
+		}},
+		{Topic: coreruntime.TopicTaskFinished, TaskID: "t2", Time: now, Fields: map[string]any{"state": "failed"}},
+		{Topic: coreruntime.TopicGuardrailHit, Time: now, Fields: map[string]any{"decision": "blocked"}},
+		{Topic: coreruntime.TopicEgressBlocked, Time: now, Fields: map[string]any{"domain": "evil.example"}},
+	} {
+		m.observe(ctx, e)
+	}
+
+	var out strings.Builder
+	m.writeMetrics(&out, now, 90*time.Second, []scheduler.Schedule{
+		{ID: "daily", Cron: "0 9 * * *", Enabled: true},
+		{ID: "paused", Cron: "0 9 * * *"},
+	})
+	for _, want := range []string{
+		"forge_uptime_seconds 90\n",
+		"forge_tasks_running 1\n",
+		"forge_tasks_total 1\n",
+		"forge_tasks_failed_total 1\n",
+		`forge_tool_calls_total{tool="http_request"} 2` + "\n",
+		`forge_tool_errors_total{tool="http_request"} 1` + "\n",
+		"forge_tokens_today 1500\n",
+		`forge_guardrail_hits_total{decision="blocked"} 1` + "\n",
+		"forge_egress_blocked_total 1\n",
+		`forge_schedule_next_run_timestamp_seconds{schedule="daily"} 1792227600` + "\n",
+		"# TYPE forge_tasks_total counter\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "paused") {
+		t.Errorf("disabled schedule exported:\n%s", out.String())
+	}
+
+	// Midnight UTC resets the daily counters.
+	out.Reset()
+	m.writeMetrics(&out, now.Add(24*time.Hour), 0, nil)
+	if !strings.Contains(out.String(), "forge_tokens_today 0\n") {
+		t.Errorf("daily tokens not reset:\n%s", out.String())
+	}
+}
+
+func TestStatusMonitor_Events(t *testing.T) {
+	m := newStatusMonitor()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.observe(ctx, coreruntime.Event{Topic: coreruntime.TopicTaskStarted, TaskID: "t1", Time: time.Now()})
+	m.observe(ctx, coreruntime.Event{Topic: coreruntime.TopicToolStart, TaskID: "t1", Time: time.Now(), Hook: &coreruntime.HookContext{
+		ToolName: "web_search", ToolInput: `{"query":"secret plans"}`,
+	}})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		m.serveEvents(ctx, w, req)
+	}))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	lines := make(chan string, 64)
+	go func() {
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			if line := sc.Text(); line != "" {
+				lines <- line
+			}
+		}
+		close(lines)
+	}()
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an event")
+			return ""
+		}
+	}
+
+	if l := next(); l != "event: snapshot" {
+		t.Fatalf("first line = %q", l)
+	}
+	if l := next(); !strings.Contains(l, `"task_id":"t1"`) || !strings.Contains(l, `"tool":"web_search"`) {
+		t.Fatalf("snapshot = %q", l)
+	}
+	next() // event: event
+	if l := next(); !strings.Contains(l, `"topic":"task.started"`) {
+		t.Fatalf("replayed = %q", l)
+	}
+	next()
+	if l := next(); !strings.Contains(l, `"topic":"tool.start"`) || strings.Contains(l, "secret plans") {
+		t.Fatalf("replayed = %q", l)
+	}
+
+	m.observe(ctx, coreruntime.Event{Topic: coreruntime.TopicEgressBlocked, TaskID: "t1", Time: time.Now(), Fields: map[string]any{"domain": "evil.example"}})
+	next()
+	if l := next(); !strings.Contains(l, `"topic":"egress.blocked"`) || !strings.Contains(l, "evil.example") {
+		t.Fatalf("live = %q", l)
+	}
+}
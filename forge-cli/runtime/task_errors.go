@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"net/http"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// failTask marks task failed. The status message is te's message, the
+// text clients have always read; te itself is recorded in the task
+// metadata so every transport carries the same error code.
+func failTask(task *a2a.Task, te *a2a.TaskError) {
+	task.Status = a2a.TaskStatus{
+		State: a2a.TaskStateFailed,
+		Message: &a2a.Message{
+			Role:  a2a.MessageRoleAgent,
+			Parts: []a2a.Part{a2a.NewTextPart(te.Message)},
+		},
+	}
+	if task.Metadata == nil {
+		task.Metadata = map[string]any{}
+	}
+	task.Metadata[a2a.MetadataKeyError] = te
+}
+
+// messageLanguage is the language the runtime writes its notices about
+// msg in: the one the channel router detected on it, otherwise the one
+// the localizer picks for its text.
+func (r *Runner) messageLanguage(msg *a2a.Message) string {
+	if lang, _ := msg.Metadata[a2a.MetadataKeyLanguage].(string); lang != "" {
+		return lang
+	}
+	return r.localizer.Language(coreruntime.ExtractText(msg))
+}
+
+// restErrorBody is the REST error response for a classified failure.
+// Error keeps the message field REST clients already read.
+type restErrorBody struct {
+	Error             string        `json:"error"`
+	Code              a2a.ErrorCode `json:"code"`
+	Retryable         bool          `json:"retryable"`
+	RetryAfterSeconds int           `json:"retry_after_seconds,omitempty"`
+}
+
+// writeTaskError writes te as a REST error response.
+func writeTaskError(w http.ResponseWriter, status int, te *a2a.TaskError) {
+	writeJSON(w, status, restErrorBody{
+		Error:             te.Message,
+		Code:              te.Code,
+		Retryable:         te.Retryable,
+		RetryAfterSeconds: te.RetryAfterSeconds,
+	})
+}
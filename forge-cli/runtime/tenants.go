@@ -0,0 +1,170 @@
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/auth"
+	"github.com/initializ/forge/forge-core/memory"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Tenants: forge.yaml `tenants:` lets one deployment back several
+// customers. A tenant's callers authenticate with its API keys; the
+// tenant then rides the request context, and its task IDs, memory,
+// files, schedules, rate limit and usage are kept apart from everyone
+// else's. Operators and channel adapters act outside any tenant.
+
+// tenantAPIKeyProvider is the auth provider for tenant API keys. It sits
+// after the runtime loopback and before the forge.yaml providers, and
+// yields tokens that are no tenant's key to them.
+type tenantAPIKeyProvider struct {
+	keys map[[sha256.Size]byte]string // API key digest → tenant
+}
+
+// newTenantAPIKeyProvider reads the tenants' API keys from the
+// environment. A tenant none of whose key variables is set is an error:
+// it could never be reached. Returns nil without tenants.
+func newTenantAPIKeyProvider(tenants []types.TenantConfig) (*tenantAPIKeyProvider, error) {
+	if len(tenants) == 0 {
+		return nil, nil
+	}
+	p := &tenantAPIKeyProvider{keys: map[[sha256.Size]byte]string{}}
+	for _, t := range tenants {
+		n := 0
+		for _, env := range t.APIKeysEnv {
+			if key := os.Getenv(env); key != "" {
+				p.keys[sha256.Sum256([]byte(key))] = t.ID
+				n++
+			}
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("tenant %q: none of its api_keys_env (%s) is set", t.ID, strings.Join(t.APIKeysEnv, ", "))
+		}
+	}
+	return p, nil
+}
+
+// Name implements auth.Provider.
+func (p *tenantAPIKeyProvider) Name() string { return "tenant_api_key" }
+
+// Verify implements auth.Provider. Like static_token it compares SHA-256
+// digests, and every key is compared so the time taken doesn't say which
+// one matched.
+func (p *tenantAPIKeyProvider) Verify(_ context.Context, token string, _ auth.Headers) (*auth.Identity, error) {
+	presented := sha256.Sum256([]byte(token))
+	tenant := ""
+	for digest, t := range p.keys {
+		if subtle.ConstantTimeCompare(presented[:], digest[:]) == 1 {
+			tenant = t
+		}
+	}
+	if tenant == "" {
+		return nil, auth.ErrTokenNotForMe
+	}
+	return &auth.Identity{UserID: "tenant:" + tenant, TenantID: tenant, Source: p.Name()}, nil
+}
+
+// tenantRoutes are the paths a tenant's callers may reach: the A2A
+// endpoints and the public probes. The rest (/admin, /info, /handoffs,
+// /notify, decisions, ...) is the operator's.
+var tenantRoutes = []string{
+	"/", "/tasks/send", "/tasks/sendSubscribe",
+	"/.well-known/agent-card.json", "/.well-known/agent.json", "/health", "/healthz",
+}
+
+// tenantMiddleware puts the tenant of an authenticated tenant caller on
+// the request context, where the rate limiter, the task handlers and
+// everything downstream find it, and keeps the caller to tenantRoutes.
+func tenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := auth.IdentityFromContext(req.Context())
+		if id == nil || id.TenantID == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+		allowed := false
+		for _, p := range tenantRoutes {
+			allowed = allowed || req.URL.Path == p
+		}
+		if !allowed {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "not available to tenants"})
+			return
+		}
+		next.ServeHTTP(w, req.WithContext(coreruntime.WithTenant(req.Context(), id.TenantID)))
+	})
+}
+
+// tenantIDs returns the IDs of the declared tenants.
+func (r *Runner) tenantIDs() []string {
+	if r.cfg.Config == nil {
+		return nil
+	}
+	ids := make([]string, len(r.cfg.Config.Tenants))
+	for i, t := range r.cfg.Config.Tenants {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// scopeTaskID maps the task ID a caller sent to the one the agent keeps
+// the task under: a tenant's IDs are prefixed with the tenant. A caller
+// outside any tenant can't name a tenant's task; it is reported as not
+// found.
+func (r *Runner) scopeTaskID(ctx context.Context, id string) (string, error) {
+	if id == "" {
+		return id, nil
+	}
+	if tenant := coreruntime.TenantFromContext(ctx); tenant != "" {
+		return coreruntime.TenantScopedID(tenant, id), nil
+	}
+	if coreruntime.TenantFromScopedID(id, r.tenantIDs()) != "" {
+		return "", fmt.Errorf("task not found: %s", id)
+	}
+	return id, nil
+}
+
+// tenantRateLimits is the per-tenant section of the server rate limit,
+// nil when no tenant overrides it.
+func tenantRateLimits(cfg *types.ForgeConfig) map[string]server.TenantRateLimit {
+	if cfg == nil {
+		return nil
+	}
+	var out map[string]server.TenantRateLimit
+	for _, t := range cfg.Tenants {
+		if t.RateLimit == (types.TenantRateLimit{}) {
+			continue
+		}
+		if out == nil {
+			out = map[string]server.TenantRateLimit{}
+		}
+		out[t.ID] = server.TenantRateLimit{RPS: t.RateLimit.RPS, Burst: t.RateLimit.Burst}
+	}
+	return out
+}
+
+// tenantMemoryFlusher sends the observations the compactor flushes for a
+// tenant's task to the tenant's memory, and the rest to the agent's.
+type tenantMemoryFlusher struct {
+	mgr     *memory.Manager
+	tenants []string
+}
+
+func (f *tenantMemoryFlusher) AppendDailyLog(ctx context.Context, observation string) error {
+	tenant := coreruntime.TenantFromScopedID(coreruntime.TaskIDFromContext(ctx), f.tenants)
+	if tenant == "" {
+		return f.mgr.AppendDailyLog(ctx, observation)
+	}
+	m, err := f.mgr.ForTenant(tenant)
+	if err != nil {
+		return err
+	}
+	return m.AppendDailyLog(ctx, observation)
+}
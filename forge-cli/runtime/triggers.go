@@ -0,0 +1,274 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/auth"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Webhook triggers: forge.yaml `triggers:` registers POST routes under
+// /hooks/ that start a task when an external system (GitHub, a
+// monitoring stack, a CI server) posts an event. Requests bypass bearer
+// auth and are authenticated by an HMAC-SHA256 signature over the raw
+// body instead, the scheme those senders already implement.
+
+// defaultTriggerTemplate renders a task for a trigger without a task
+// template: the raw body, for the agent to interpret.
+const defaultTriggerTemplate = "Webhook {{.Trigger}} received:\n\n{{.Body}}"
+
+var errTriggerSignature = errors.New("missing or invalid webhook signature")
+
+// webhookTrigger is a configured trigger with its secret and parsed
+// task template.
+type webhookTrigger struct {
+	types.TriggerConfig
+	secret []byte
+	tmpl   *template.Template
+}
+
+// triggerData is what a trigger's task template renders against.
+type triggerData struct {
+	Trigger string
+	Payload any               // the decoded JSON body; nil when the body is not JSON
+	Body    string            // the raw body
+	Headers map[string]string // first value of each header, canonical keys (X-Github-Event)
+}
+
+// newWebhookTrigger resolves a trigger's secret from its environment
+// variable and parses its template. A trigger without a secret is an
+// error: its route would accept anyone's events.
+func newWebhookTrigger(cfg types.TriggerConfig) (*webhookTrigger, error) {
+	secret := os.Getenv(cfg.SecretEnv)
+	if cfg.SecretEnv == "" || secret == "" {
+		return nil, fmt.Errorf("triggers.%s: secret_env %q is unset; refusing to serve an unsigned webhook", cfg.ID, cfg.SecretEnv)
+	}
+	src := cfg.Task
+	if src == "" {
+		src = defaultTriggerTemplate
+	}
+	tmpl, err := template.New(cfg.ID).Option("missingkey=zero").Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("triggers.%s.task: %w", cfg.ID, err)
+	}
+	if cfg.SignatureHeader == "" {
+		cfg.SignatureHeader = types.DefaultTriggerSignatureHeader
+	}
+	return &webhookTrigger{TriggerConfig: cfg, secret: []byte(secret), tmpl: tmpl}, nil
+}
+
+// verify checks the request's signature header against the body.
+func (t *webhookTrigger) verify(header string, body []byte) error {
+	sig := strings.TrimPrefix(strings.TrimSpace(header), "sha256=")
+	got, err := hex.DecodeString(sig)
+	if err != nil || len(got) == 0 {
+		return errTriggerSignature
+	}
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errTriggerSignature
+	}
+	return nil
+}
+
+// data collects what a verified request gives the task template, or
+// the workflow as its inputs.
+func (t *webhookTrigger) data(req *http.Request, body []byte) triggerData {
+	data := triggerData{Trigger: t.ID, Body: string(body), Headers: map[string]string{}}
+	for k, v := range req.Header {
+		if len(v) > 0 {
+			data.Headers[k] = v[0]
+		}
+	}
+	var payload any
+	if json.Unmarshal(body, &payload) == nil {
+		data.Payload = payload
+	}
+	return data
+}
+
+// render builds the task message for a verified request.
+func (t *webhookTrigger) render(data triggerData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering task for trigger %q: %w", t.ID, err)
+	}
+	text := strings.TrimSpace(buf.String())
+	if text == "" {
+		return "", fmt.Errorf("trigger %q rendered an empty task", t.ID)
+	}
+	return text, nil
+}
+
+// triggerRoutes returns the "POST <path>" keys of the declared
+// triggers, which bypass bearer auth.
+func triggerRoutes(triggers []types.TriggerConfig) []string {
+	routes := make([]string, 0, len(triggers))
+	for _, t := range triggers {
+		routes = append(routes, "POST "+t.Route())
+	}
+	return routes
+}
+
+// authSkipPaths returns the public routes: the defaults plus the
+// webhook trigger routes, which authenticate by signature.
+func (r *Runner) authSkipPaths() map[string]bool {
+	skip := auth.DefaultSkipPaths()
+	if r.cfg.Config != nil {
+		for _, route := range triggerRoutes(r.cfg.Config.Triggers) {
+			skip[route] = true
+		}
+	}
+	return skip
+}
+
+// registerTriggerEndpoints wires a POST route per declared trigger. A
+// trigger whose secret is unset is not served.
+func (r *Runner) registerTriggerEndpoints(srv *server.Server, executor coreruntime.AgentExecutor, guardrails coreruntime.GuardrailChecker, egressClient *http.Client, auditLogger *coreruntime.AuditLogger) {
+	store := srv.TaskStore()
+	for _, cfg := range r.cfg.Config.Triggers {
+		t, err := newWebhookTrigger(cfg)
+		if err != nil {
+			r.logger.Error("webhook trigger disabled", map[string]any{"trigger": cfg.ID, "error": err.Error()})
+			continue
+		}
+		start := func(ctx context.Context, t *webhookTrigger, data triggerData, text string) string {
+			if t.Workflow != "" {
+				return r.startTriggerWorkflow(ctx, t, data)
+			}
+			return r.startTriggerTask(ctx, t, text, store, executor, guardrails, egressClient, auditLogger)
+		}
+		srv.RegisterHTTPHandler("POST "+t.Route(), makeTriggerHandler(t, auditLogger, start))
+		r.logger.Info("webhook trigger registered", map[string]any{"trigger": t.ID, "path": t.Route()})
+	}
+}
+
+// makeTriggerHandler is extracted so tests can exercise the handler
+// without a full server. start runs the task, or the trigger's
+// workflow, in the background and returns its ID: webhook senders time
+// out in seconds, so the request is answered 202 as soon as the task is
+// accepted. 401 for a bad signature, 422 when the task template cannot
+// render.
+func makeTriggerHandler(t *webhookTrigger, audit *coreruntime.AuditLogger, start func(context.Context, *webhookTrigger, triggerData, string) string) http.HandlerFunc {
+	emit := func(ctx context.Context, taskID, outcome string, err error) {
+		if audit == nil {
+			return
+		}
+		fields := map[string]any{"trigger": t.ID, "outcome": outcome}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		audit.EmitFromContext(ctx, coreruntime.AuditEvent{Event: coreruntime.AuditWebhookTrigger, TaskID: taskID, Fields: fields})
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			if server.WriteLimitExceededOnError(w, err) {
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "reading body: " + err.Error()})
+			return
+		}
+		if err := t.verify(req.Header.Get(t.SignatureHeader), body); err != nil {
+			emit(req.Context(), "", "rejected", err)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+			return
+		}
+		data := t.data(req, body)
+		var text string
+		if t.Workflow == "" {
+			if text, err = t.render(data); err != nil {
+				emit(req.Context(), "", "invalid", err)
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		// The route skips the A2A request setup, so tag the origin
+		// here for tool_policies.
+		ctx := tools.WithOrigin(req.Context(), tools.OriginWebhook)
+		taskID := start(ctx, t, data, text)
+		emit(ctx, taskID, "accepted", nil)
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted", "task_id": taskID})
+	}
+}
+
+// startTriggerTask runs a trigger's task in the background through the
+// same path as POST /tasks/send, so it is stored, guarded and audited
+// like any task, and delivers the result to the trigger's channel.
+func (r *Runner) startTriggerTask(
+	reqCtx context.Context,
+	t *webhookTrigger,
+	text string,
+	store *a2a.TaskStore,
+	executor coreruntime.AgentExecutor,
+	guardrails coreruntime.GuardrailChecker,
+	egressClient *http.Client,
+	auditLogger *coreruntime.AuditLogger,
+) string {
+	taskID := fmt.Sprintf("hook-%s-%s", t.ID, coreruntime.GenerateID())
+	msgText := fmt.Sprintf("[Webhook Trigger: %s]\n\n%s", t.ID, text)
+	if t.Skill != "" {
+		msgText = fmt.Sprintf("[Webhook Trigger: %s] [Skill: %s]\n\n%s", t.ID, t.Skill, text)
+	}
+	params := a2a.SendTaskParams{
+		ID: taskID,
+		Message: a2a.Message{
+			Role:  a2a.MessageRoleUser,
+			Parts: []a2a.Part{a2a.NewTextPart(msgText)},
+		},
+	}
+
+	// The run outlives the webhook request; keep its correlation and
+	// trace context but not its cancellation.
+	ctx := context.WithoutCancel(reqCtx)
+	go func() {
+		task, _, err := r.executeTask(ctx, params, store, executor, guardrails, egressClient, auditLogger)
+		if err != nil {
+			r.logger.Error("webhook trigger task failed", map[string]any{"trigger": t.ID, "task_id": taskID, "error": err.Error()})
+			return
+		}
+		if task != nil {
+			r.deliverTriggerResult(ctx, t, task.Status.Message)
+		}
+	}()
+	return taskID
+}
+
+// deliverTriggerResult sends a trigger's result to its channel, if it
+// has one.
+func (r *Runner) deliverTriggerResult(ctx context.Context, t *webhookTrigger, msg *a2a.Message) {
+	if t.Channel == "" || msg == nil {
+		return
+	}
+	if r.notifySender == nil {
+		r.logger.Warn("trigger has channel configured but no channel adapters are active; use --with flag", map[string]any{
+			"trigger": t.ID,
+			"channel": t.Channel,
+		})
+		return
+	}
+	if err := r.notifySender(ctx, t.Channel, t.ChannelTarget, msg); err != nil {
+		r.logger.Warn("failed to deliver webhook trigger result", map[string]any{
+			"trigger": t.ID,
+			"channel": t.Channel,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func signTrigger(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestTriggerHandler(t *testing.T) {
+	t.Setenv("TEST_HOOK_SECRET", "s3cret")
+	trig, err := newWebhookTrigger(types.TriggerConfig{
+		ID:        "github-push",
+		SecretEnv: "TEST_HOOK_SECRET",
+		Task:      `Review push {{.Payload.after}} to {{.Payload.repository.full_name}} ({{index .Headers "X-Github-Event"}})`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var started []string
+	start := func(_ context.Context, _ *webhookTrigger, _ triggerData, text string) string {
+		started = append(started, text)
+		return "hook-github-push-1"
+	}
+	var audit bytes.Buffer
+	h := makeTriggerHandler(trig, coreruntime.NewAuditLogger(&audit), start)
+
+	body := `{"after":"abc123","repository":{"full_name":"acme/api"}}`
+	post := func(sig string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/hooks/github-push", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "push")
+		if sig != "" {
+			req.Header.Set("X-Hub-Signature-256", sig)
+		}
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec
+	}
+
+	if rec := post(signTrigger("s3cret", body)); rec.Code != http.StatusAccepted || !strings.Contains(rec.Body.String(), "hook-github-push-1") {
+		t.Fatalf("signed request: %d %s", rec.Code, rec.Body)
+	}
+	if len(started) != 1 || started[0] != "Review push abc123 to acme/api (push)" {
+		t.Errorf("started = %q", started)
+	}
+
+	for _, sig := range []string{"", signTrigger("wrong", body), "sha256=zz"} {
+		if rec := post(sig); rec.Code != http.StatusUnauthorized {
+			t.Errorf("signature %q: status = %d, want 401", sig, rec.Code)
+		}
+	}
+	if len(started) != 1 {
+		t.Errorf("unsigned requests started tasks: %q", started)
+	}
+	if got := strings.Count(audit.String(), `"outcome":"rejected"`); got != 3 {
+		t.Errorf("rejected audit events = %d, want 3", got)
+	}
+}
+
+func TestTriggerHandler_WebhookOriginPolicy(t *testing.T) {
+	t.Setenv("TEST_HOOK_SECRET", "s3cret")
+	trig, err := newWebhookTrigger(types.TriggerConfig{ID: "alert", SecretEnv: "TEST_HOOK_SECRET", Task: "triage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := tools.NewRegistry()
+	if err := reg.Register(workflowStepTool{}); err != nil {
+		t.Fatal(err)
+	}
+	reg.SetOriginPolicies(map[string]tools.OriginPolicy{tools.OriginWebhook: {Deny: []string{"lookup"}}})
+
+	var toolErr error
+	start := func(ctx context.Context, _ *webhookTrigger, _ triggerData, _ string) string {
+		_, toolErr = reg.Execute(ctx, "lookup", json.RawMessage(`{}`))
+		return "hook-alert-1"
+	}
+	body := `{}`
+	req := httptest.NewRequest(http.MethodPost, "/hooks/alert", strings.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", signTrigger("s3cret", body))
+	rec := httptest.NewRecorder()
+	makeTriggerHandler(trig, nil, start)(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d %s", rec.Code, rec.Body)
+	}
+	if toolErr == nil || !strings.Contains(toolErr.Error(), `origin "webhook"`) {
+		t.Errorf("denied tool on a webhook task: err = %v", toolErr)
+	}
+}
+
+func TestNewWebhookTrigger(t *testing.T) {
+	if _, err := newWebhookTrigger(types.TriggerConfig{ID: "x", SecretEnv: "TEST_HOOK_UNSET"}); err == nil {
+		t.Error("a trigger without a secret should be refused")
+	}
+	t.Setenv("TEST_HOOK_SECRET", "s3cret")
+	trig, err := newWebhookTrigger(types.TriggerConfig{ID: "ci", Path: "/hooks/ci/done", SecretEnv: "TEST_HOOK_SECRET"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trig.SignatureHeader != types.DefaultTriggerSignatureHeader || trig.Route() != "/hooks/ci/done" {
+		t.Errorf("defaults: header %q route %q", trig.SignatureHeader, trig.Route())
+	}
+	req := httptest.NewRequest(http.MethodPost, "/hooks/ci/done", nil)
+	if text, err := trig.render(trig.data(req, []byte("build 42 passed"))); err != nil || text != "Webhook ci received:\n\nbuild 42 passed" {
+		t.Errorf("default template = %q, %v", text, err)
+	}
+	routes := triggerRoutes([]types.TriggerConfig{{ID: "a"}, {ID: "b", Path: "/hooks/custom"}})
+	if strings.Join(routes, ",") != "POST /hooks/a,POST /hooks/custom" {
+		t.Errorf("routes = %v", routes)
+	}
+}
@@ -0,0 +1,188 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// undoCommand is the chat command that reverts the previous exchange.
+// "/undo compensate" additionally runs tool compensation hooks.
+const undoCommand = "/undo"
+
+// parseUndoCommand reports whether msg is the `/undo` chat command and
+// whether the caller asked for compensation. Only a message whose
+// entire text is the command matches, so a user asking the agent
+// "how do I /undo a commit?" still reaches the LLM.
+func parseUndoCommand(msg a2a.Message) (compensate, ok bool) {
+	if msg.Role != a2a.MessageRoleUser {
+		return false, false
+	}
+	var text []string
+	for _, p := range msg.Parts {
+		if p.Kind == a2a.PartKindText {
+			text = append(text, p.Text)
+		}
+	}
+	fields := strings.Fields(strings.Join(text, " "))
+	switch {
+	case len(fields) == 1 && fields[0] == undoCommand:
+		return false, true
+	case len(fields) == 2 && fields[0] == undoCommand && fields[1] == "compensate":
+		return true, true
+	}
+	return false, false
+}
+
+// undoTask rolls taskID back by one exchange (tasks/undo and the
+// `/undo` chat command share this path). It trims the A2A task
+// history and the persisted session, emits a tool_disavowed audit
+// event for every side-effecting tool call the exchange made, and —
+// when compensate is set — asks tools implementing
+// tools.Compensator to reverse their effect. The returned task is
+// the trimmed task with a completed status whose message describes
+// what was undone; that message is NOT appended to history, so the
+// next turn sees the conversation exactly as it stood before the
+// reverted exchange.
+func (r *Runner) undoTask(ctx context.Context, store *a2a.TaskStore, taskID string, compensate bool, auditLogger *coreruntime.AuditLogger) (*a2a.Task, error) {
+	unlock, err := r.cluster.lockTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ctx = coreruntime.EnsureCorrelationID(ctx)
+	correlationID := coreruntime.CorrelationIDFromContext(ctx)
+	ctx = coreruntime.WithTaskID(ctx, taskID)
+	ctx = coreruntime.EnsureSequenceCounter(ctx)
+
+	task := store.Get(taskID)
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+	if task.Status.State == a2a.TaskStateWorking || task.Status.State == a2a.TaskStateSubmitted {
+		return nil, fmt.Errorf("task %s is still running; cancel it before undoing", taskID)
+	}
+
+	trimmed, userContent, hasHistory := coreruntime.UndoTaskHistory(task.History)
+	res, err := coreruntime.UndoLastExchange(r.sessionStore, taskID, userContent)
+	switch {
+	case errors.Is(err, coreruntime.ErrNothingToUndo) && hasHistory:
+		// Persistence disabled (or the session expired): the task
+		// history is the only record, so trimming it is the whole undo.
+		// Tool calls are not recorded there and cannot be disavowed.
+		res = &coreruntime.UndoResult{TaskID: taskID, UserMessage: userContent}
+	case errors.Is(err, coreruntime.ErrNothingToUndo):
+		return nil, fmt.Errorf("task %s has no exchange to undo", taskID)
+	case err != nil:
+		return nil, err
+	}
+
+	disavowed := 0
+	var compensated []string
+	for _, tc := range res.ToolCalls {
+		var t tools.Tool
+		if r.toolRegistry != nil {
+			t = r.toolRegistry.Get(tc.Name)
+		}
+		if t != nil && tools.IsReadOnly(t) {
+			continue
+		}
+		fields := map[string]any{
+			"tool":         tc.Name,
+			"tool_call_id": tc.ID,
+			"compensation": "skipped",
+		}
+		if compensate {
+			c, ok := t.(tools.Compensator)
+			if !ok {
+				fields["compensation"] = "unsupported"
+			} else if detail, cErr := c.Compensate(ctx, json.RawMessage(tc.Arguments), tc.Result); cErr != nil {
+				fields["compensation"] = "failed"
+				fields["error"] = cErr.Error()
+				r.logger.Warn("undo compensation failed", map[string]any{
+					"task_id": taskID, "tool": tc.Name, "error": cErr.Error(),
+				})
+			} else if detail == "" {
+				fields["compensation"] = "none"
+			} else {
+				fields["compensation"] = "applied"
+				fields["detail"] = detail
+				compensated = append(compensated, detail)
+			}
+		}
+		auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
+			Event:         coreruntime.AuditToolDisavowed,
+			CorrelationID: correlationID,
+			TaskID:        taskID,
+			Fields:        fields,
+		})
+		disavowed++
+	}
+
+	auditLogger.EmitFromContext(ctx, coreruntime.AuditEvent{
+		Event:         coreruntime.AuditSessionUndo,
+		CorrelationID: correlationID,
+		TaskID:        taskID,
+		Fields: map[string]any{
+			"removed_messages": res.RemovedMessages,
+			"tool_calls":       len(res.ToolCalls),
+			"disavowed":        disavowed,
+			"compensate":       compensate,
+		},
+	})
+	r.logger.Info("task undo", map[string]any{
+		"task_id":          taskID,
+		"removed_messages": res.RemovedMessages,
+		"disavowed":        disavowed,
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Undid the last exchange (%q).", res.UserMessage)
+	if disavowed > 0 {
+		fmt.Fprintf(&sb, " %d side-effecting tool call(s) marked as disavowed.", disavowed)
+	}
+	for _, c := range compensated {
+		sb.WriteString("\n- " + c)
+	}
+	msg := &a2a.Message{
+		Role:  a2a.MessageRoleAgent,
+		Parts: []a2a.Part{a2a.NewTextPart(sb.String())},
+	}
+
+	task.History = trimmed
+	task.Status = a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: msg}
+	task.Artifacts = []a2a.Artifact{{Name: "response", Parts: msg.Parts}}
+	store.Put(task)
+	return task, nil
+}
+
+// undoFromChat runs the `/undo` chat command for the tasks/send and
+// tasks/sendSubscribe paths. Failures (nothing to undo, task still
+// running) are rendered as a failed task carrying the reason rather
+// than a transport error, so chat surfaces show them inline like any
+// other agent reply.
+func (r *Runner) undoFromChat(ctx context.Context, store *a2a.TaskStore, taskID string, compensate bool, auditLogger *coreruntime.AuditLogger) *a2a.Task {
+	task, err := r.undoTask(ctx, store, taskID, compensate, auditLogger)
+	if err == nil {
+		return task
+	}
+	task = store.Get(taskID)
+	if task == nil {
+		task = &a2a.Task{ID: taskID}
+	}
+	task.Status = a2a.TaskStatus{
+		State: a2a.TaskStateFailed,
+		Message: &a2a.Message{
+			Role:  a2a.MessageRoleAgent,
+			Parts: []a2a.Part{a2a.NewTextPart("Undo failed: " + err.Error())},
+		},
+	}
+	return task
+}
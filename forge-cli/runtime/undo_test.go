@@ -0,0 +1,190 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// undoStubTool is a side-effecting tool with a compensation hook.
+type undoStubTool struct {
+	compensated []string
+}
+
+func (t *undoStubTool) Name() string                 { return "issue_create" }
+func (t *undoStubTool) Description() string          { return "create an issue" }
+func (t *undoStubTool) Category() tools.Category     { return tools.CategoryCustom }
+func (t *undoStubTool) InputSchema() json.RawMessage { return json.RawMessage(`{}`) }
+func (t *undoStubTool) Execute(context.Context, json.RawMessage) (string, error) {
+	return "created #1", nil
+}
+func (t *undoStubTool) Compensate(_ context.Context, args json.RawMessage, result string) (string, error) {
+	t.compensated = append(t.compensated, result)
+	return "Closed issue #1.", nil
+}
+
+// undoReadTool is a read-only tool; undo must not disavow its calls.
+type undoReadTool struct{ undoStubTool }
+
+func (t *undoReadTool) Name() string   { return "issue_get" }
+func (t *undoReadTool) ReadOnly() bool { return true }
+
+func newUndoRunner(t *testing.T) (*Runner, *undoStubTool, *a2a.TaskStore) {
+	t.Helper()
+	sessions, err := coreruntime.NewMemoryStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := &undoStubTool{}
+	reg := tools.NewRegistry()
+	if err := reg.Register(stub); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Register(&undoReadTool{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sessions.Save(&coreruntime.SessionData{TaskID: "t1", Messages: []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "open an issue"},
+		{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+			{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "issue_get", Arguments: `{}`}},
+			{ID: "c2", Type: "function", Function: llm.FunctionCall{Name: "issue_create", Arguments: `{}`}},
+		}},
+		{Role: llm.RoleTool, ToolCallID: "c1", Content: "none"},
+		{Role: llm.RoleTool, ToolCallID: "c2", Content: "created #1"},
+		{Role: llm.RoleAssistant, Content: "Opened #1."},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	store := a2a.NewTaskStore()
+	store.Put(&a2a.Task{
+		ID:     "t1",
+		Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+		History: []a2a.Message{
+			{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("open an issue")}},
+			{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Opened #1.")}},
+		},
+	})
+
+	r := &Runner{
+		logger:       coreruntime.NewJSONLogger(io.Discard, false),
+		sessionStore: sessions,
+		toolRegistry: reg,
+	}
+	return r, stub, store
+}
+
+func undoAuditEvents(t *testing.T, buf *bytes.Buffer, event string) []coreruntime.AuditEvent {
+	t.Helper()
+	var out []coreruntime.AuditEvent
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var ev coreruntime.AuditEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		if ev.Event == event {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func TestUndoTask_DisavowsSideEffectingCalls(t *testing.T) {
+	r, stub, store := newUndoRunner(t)
+	var buf bytes.Buffer
+	al := coreruntime.NewAuditLogger(&buf)
+
+	task, err := r.undoTask(context.Background(), store, "t1", false, al)
+	if err != nil {
+		t.Fatalf("undoTask: %v", err)
+	}
+	if len(task.History) != 0 {
+		t.Errorf("history = %+v, want empty", task.History)
+	}
+	if len(stub.compensated) != 0 {
+		t.Error("compensation ran without compensate=true")
+	}
+
+	disavowed := undoAuditEvents(t, &buf, coreruntime.AuditToolDisavowed)
+	if len(disavowed) != 1 {
+		t.Fatalf("got %d tool_disavowed events, want 1 (read-only call skipped)", len(disavowed))
+	}
+	if f := disavowed[0].Fields; f["tool"] != "issue_create" || f["tool_call_id"] != "c2" || f["compensation"] != "skipped" {
+		t.Errorf("tool_disavowed fields = %+v", f)
+	}
+	if n := len(undoAuditEvents(t, &buf, coreruntime.AuditSessionUndo)); n != 1 {
+		t.Errorf("got %d session_undo events, want 1", n)
+	}
+
+	saved, err := r.sessionStore.Load("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Messages) != 0 {
+		t.Errorf("session messages = %+v, want empty", saved.Messages)
+	}
+}
+
+func TestUndoTask_Compensate(t *testing.T) {
+	r, stub, store := newUndoRunner(t)
+	var buf bytes.Buffer
+
+	task, err := r.undoTask(context.Background(), store, "t1", true, coreruntime.NewAuditLogger(&buf))
+	if err != nil {
+		t.Fatalf("undoTask: %v", err)
+	}
+	if len(stub.compensated) != 1 || stub.compensated[0] != "created #1" {
+		t.Errorf("compensated = %v", stub.compensated)
+	}
+	if !strings.Contains(task.Status.Message.Parts[0].Text, "Closed issue #1.") {
+		t.Errorf("status message = %q", task.Status.Message.Parts[0].Text)
+	}
+	disavowed := undoAuditEvents(t, &buf, coreruntime.AuditToolDisavowed)
+	if len(disavowed) != 1 || disavowed[0].Fields["compensation"] != "applied" {
+		t.Errorf("tool_disavowed = %+v", disavowed)
+	}
+}
+
+func TestUndoFromChat_NothingToUndo(t *testing.T) {
+	r, _, store := newUndoRunner(t)
+	al := coreruntime.NewAuditLogger(io.Discard)
+	if _, err := r.undoTask(context.Background(), store, "t1", false, al); err != nil {
+		t.Fatal(err)
+	}
+	task := r.undoFromChat(context.Background(), store, "t1", false, al)
+	if task.Status.State != a2a.TaskStateFailed {
+		t.Errorf("state = %s, want failed", task.Status.State)
+	}
+}
+
+func TestParseUndoCommand(t *testing.T) {
+	cases := []struct {
+		text           string
+		ok, compensate bool
+	}{
+		{"/undo", true, false},
+		{"  /undo  ", true, false},
+		{"/undo compensate", true, true},
+		{"/undo please", false, false},
+		{"how do I /undo a commit?", false, false},
+	}
+	for _, tc := range cases {
+		msg := a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(tc.text)}}
+		compensate, ok := parseUndoCommand(msg)
+		if ok != tc.ok || compensate != tc.compensate {
+			t.Errorf("parseUndoCommand(%q) = (%v, %v), want (%v, %v)", tc.text, compensate, ok, tc.compensate, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// uploadsDirPrefix starts the name of the directory, in the files
+// directory, that a task's uploaded files are kept in. One directory per
+// task makes each task's uploads one entry for artifact GC.
+const uploadsDirPrefix = "uploads-"
+
+// storeUploads saves the file parts of an inbound message that carry
+// their content, such as the files and images shared on a channel, in
+// the task's uploads directory. Each part's content is swapped for the
+// saved file's path relative to the agent directory: the model sees the
+// path and reads the file with its tools, and the task history and
+// session don't hold the bytes. A part that can't be saved keeps its
+// content.
+func (r *Runner) storeUploads(ctx context.Context, taskID string, msg *a2a.Message) {
+	filesDir := filepath.Join(r.cfg.WorkDir, ".forge", "files")
+	if tenant := coreruntime.TenantFromContext(ctx); tenant != "" {
+		filesDir = filepath.Join(TenantsDir(r.cfg.WorkDir), tenant, "files")
+	}
+	dir := filepath.Join(filesDir, uploadsDirPrefix+tools.ScratchDirName(taskID))
+	for i := range msg.Parts {
+		f := msg.Parts[i].File
+		if msg.Parts[i].Kind != a2a.PartKindFile || f == nil || len(f.Bytes) == 0 {
+			continue
+		}
+		path, err := writeUpload(dir, f.Name, f.Bytes)
+		if err != nil {
+			r.logger.Warn("storing uploaded file failed", map[string]any{"task_id": taskID, "name": f.Name, "error": err.Error()})
+			continue
+		}
+		if rel, err := filepath.Rel(r.cfg.WorkDir, path); err == nil {
+			path = rel
+		}
+		r.logger.Info("stored uploaded file", map[string]any{"task_id": taskID, "path": path, "bytes": len(f.Bytes)})
+		f.URI = filepath.ToSlash(path)
+		f.Bytes = nil
+	}
+}
+
+// writeUpload writes data to a new file in dir named after name, never
+// replacing an earlier upload: a name already taken gets a numeric
+// prefix.
+func writeUpload(dir, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating uploads directory: %w", err)
+	}
+	name = uploadFileName(name)
+	for n := 1; n < 1000; n++ {
+		candidate := name
+		if n > 1 {
+			candidate = fmt.Sprintf("%d-%s", n, name)
+		}
+		path := filepath.Join(dir, candidate)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return path, err
+	}
+	return "", fmt.Errorf("too many uploads named %s", name)
+}
+
+// uploadFileName reduces a caller-supplied file name to a safe base
+// name.
+func uploadFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, filepath.Base(filepath.FromSlash(name)))
+	if strings.Trim(name, ".") == "" {
+		return "file"
+	}
+	return name
+}
@@ -0,0 +1,41 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+func TestStoreUploads(t *testing.T) {
+	dir := t.TempDir()
+	r := &Runner{cfg: RunnerConfig{WorkDir: dir}, logger: nopLogger{}}
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{
+		a2a.NewTextPart("what is this?"),
+		a2a.NewFilePart(a2a.FileContent{Name: "../../chart.png", MimeType: "image/png", Bytes: []byte("png")}),
+		a2a.NewFilePart(a2a.FileContent{Name: "chart.png", MimeType: "image/png", Bytes: []byte("png2")}),
+		a2a.NewFilePart(a2a.FileContent{Name: "linked.pdf", URI: "https://example.com/linked.pdf"}),
+	}}
+	r.storeUploads(coreruntime.WithTenant(context.Background(), "acme"), "acme.t1", msg)
+
+	first, second := msg.Parts[1].File, msg.Parts[2].File
+	if first.Bytes != nil || second.Bytes != nil {
+		t.Error("stored content kept on the message")
+	}
+	if filepath.Dir(first.URI) != filepath.Dir(second.URI) || filepath.Base(first.URI) != "chart.png" || filepath.Base(second.URI) != "2-chart.png" {
+		t.Errorf("URIs = %q, %q; want both in the task's uploads directory, the second renamed", first.URI, second.URI)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, first.URI)); err != nil || string(data) != "png" {
+		t.Errorf("stored file = %q, %v", data, err)
+	}
+	if dir := filepath.Dir(first.URI); filepath.Dir(dir) != filepath.Join(".forge", "tenants", "acme", "files") || !strings.HasPrefix(filepath.Base(dir), uploadsDirPrefix) {
+		t.Errorf("URI %q is outside the tenant's uploads", first.URI)
+	}
+	if msg.Parts[3].File.URI != "https://example.com/linked.pdf" {
+		t.Error("a file part without content was changed")
+	}
+}
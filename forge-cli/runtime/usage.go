@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// agentUsage is the /info usage section: every LLM call the agent has
+// made since it started, across all tasks, and each tenant's and each
+// experiment variant's share of them. Per-task ledgers live in
+// tasks/get metadata and the session store.
+type agentUsage struct {
+	Since time.Time `json:"since"`
+	*coreruntime.UsageLedger
+	Tenants map[string]*coreruntime.UsageLedger `json:"tenants,omitempty"`
+	// Experiments is keyed by "<experiment>/<variant>".
+	Experiments map[string]*coreruntime.UsageLedger `json:"experiments,omitempty"`
+}
+
+// recordUsage adds one LLM call to the agent-wide usage totals and, for
+// a tenant's task or one in an experiment, to the tenant's or the
+// variant's.
+func (r *Runner) recordUsage(ctx context.Context, provider, model string, u llm.UsageInfo) {
+	r.usageMu.Lock()
+	defer r.usageMu.Unlock()
+	r.usageTotals.Add(provider, model, u)
+	if tenant := coreruntime.TenantFromContext(ctx); tenant != "" {
+		addUsage(&r.tenantUsage, tenant, provider, model, u)
+	}
+	if a := coreruntime.ExperimentFromContext(ctx); a != nil {
+		addUsage(&r.experimentUsage, a.String(), provider, model, u)
+	}
+}
+
+// addUsage adds one LLM call to (*ledgers)[key], creating both as needed.
+func addUsage(ledgers *map[string]*coreruntime.UsageLedger, key, provider, model string, u llm.UsageInfo) {
+	if *ledgers == nil {
+		*ledgers = map[string]*coreruntime.UsageLedger{}
+	}
+	l := (*ledgers)[key]
+	if l == nil {
+		l = &coreruntime.UsageLedger{}
+		(*ledgers)[key] = l
+	}
+	l.Add(provider, model, u)
+}
+
+func (r *Runner) usageSnapshot() agentUsage {
+	r.usageMu.Lock()
+	defer r.usageMu.Unlock()
+	snap := agentUsage{Since: r.startTime, UsageLedger: r.usageTotals.Clone()}
+	for tenant, l := range r.tenantUsage {
+		if snap.Tenants == nil {
+			snap.Tenants = map[string]*coreruntime.UsageLedger{}
+		}
+		snap.Tenants[tenant] = l.Clone()
+	}
+	for variant, l := range r.experimentUsage {
+		if snap.Experiments == nil {
+			snap.Experiments = map[string]*coreruntime.UsageLedger{}
+		}
+		snap.Experiments[variant] = l.Clone()
+	}
+	return snap
+}
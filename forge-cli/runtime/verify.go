@@ -8,10 +8,38 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/initializ/forge/forge-skills/trust"
 )
 
+// EnvBuildVerification overrides security.build_verification.
+const EnvBuildVerification = "FORGE_BUILD_VERIFICATION"
+
+// Build-output files the verifier reads besides checksums.json. Keep in
+// sync with build.SBOMFile and build.ProvenanceFile.
+const (
+	sbomFile       = "sbom.cdx.json"
+	provenanceFile = "provenance.json"
+)
+
+// operatorOnlyPaths are build-output paths the generated .dockerignore
+// keeps out of the image (issue #147). They are listed in checksums.json
+// and provenance.json but legitimately absent inside a Forge container,
+// so a missing one is skipped; a present one is still verified. Keep in
+// sync with DockerfileStage's .dockerignore.
+var operatorOnlyPaths = []string{"k8s/", "compiled/", ".local-bins/", "build-manifest.json", "Dockerfile", ".dockerignore"}
+
+func isOperatorOnly(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, p := range operatorOnlyPaths {
+		if rel == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(rel, p)) {
+			return true
+		}
+	}
+	return false
+}
+
 // ChecksumsFile mirrors the JSON structure written by the signing stage.
 type ChecksumsFile struct {
 	Version   string            `json:"version"`
@@ -21,14 +49,46 @@ type ChecksumsFile struct {
 	KeyID     string            `json:"key_id,omitempty"`
 }
 
-// VerifyBuildOutput verifies the integrity of build output files against checksums.json.
-// Returns nil if checksums.json is not found (verification is optional).
-// Returns an error if any file's checksum doesn't match or if the signature is invalid.
-func VerifyBuildOutput(outputDir string) error {
+// provenanceStatement is the part of the in-toto provenance statement
+// written by the provenance stage that the verifier checks.
+type provenanceStatement struct {
+	Type    string `json:"_type"`
+	Subject []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// sbomDocument is the part of the CycloneDX SBOM the verifier checks.
+type sbomDocument struct {
+	BOMFormat  string `json:"bomFormat"`
+	Components []struct {
+		Type   string `json:"type"`
+		Name   string `json:"name"`
+		Hashes []struct {
+			Alg     string `json:"alg"`
+			Content string `json:"content"`
+		} `json:"hashes"`
+	} `json:"components"`
+}
+
+// VerifyBuildOutput verifies the integrity of build output files against
+// checksums.json, the provenance attestation's subjects and the SBOM's
+// skill-file hashes. Any file whose content doesn't match, or an invalid
+// signature, is an error.
+//
+// Without enforce, every piece is optional: a missing checksums.json,
+// SBOM or provenance is skipped. With enforce, checksums.json must exist
+// and be signed by a trusted key, and the SBOM and provenance must exist
+// and be covered by it.
+func VerifyBuildOutput(outputDir string, enforce bool) error {
 	checksumPath := filepath.Join(outputDir, "checksums.json")
 
 	data, err := os.ReadFile(checksumPath)
 	if os.IsNotExist(err) {
+		if enforce {
+			return fmt.Errorf("checksums.json not found in %s", outputDir)
+		}
 		return nil // checksums.json is optional
 	}
 	if err != nil {
@@ -42,16 +102,19 @@ func VerifyBuildOutput(outputDir string) error {
 
 	// Verify each file's checksum.
 	for rel, expectedHash := range cf.Checksums {
-		absPath := filepath.Join(outputDir, rel)
-		fileData, err := os.ReadFile(absPath)
-		if err != nil {
-			return fmt.Errorf("reading %s: %w", rel, err)
+		if err := verifyFileDigest(outputDir, rel, expectedHash); err != nil {
+			return err
 		}
+	}
 
-		h := sha256.Sum256(fileData)
-		actual := hex.EncodeToString(h[:])
-		if actual != expectedHash {
-			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", rel, expectedHash, actual)
+	if enforce {
+		if cf.Signature == "" {
+			return fmt.Errorf("checksums.json is not signed")
+		}
+		for _, f := range []string{sbomFile, provenanceFile} {
+			if _, ok := cf.Checksums[f]; !ok {
+				return fmt.Errorf("%s is missing from checksums.json", f)
+			}
 		}
 	}
 
@@ -75,5 +138,89 @@ func VerifyBuildOutput(outputDir string) error {
 		_ = keyID // signature is valid
 	}
 
+	if err := verifyProvenance(outputDir); err != nil {
+		return err
+	}
+	return verifySBOM(outputDir)
+}
+
+// verifyFileDigest checks rel's SHA-256 against want. A missing
+// operator-only file is not an error.
+func verifyFileDigest(outputDir, rel, want string) error {
+	fileData, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(rel)))
+	if os.IsNotExist(err) && isOperatorOnly(rel) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", rel, err)
+	}
+	h := sha256.Sum256(fileData)
+	actual := hex.EncodeToString(h[:])
+	if actual != want {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", rel, want, actual)
+	}
+	return nil
+}
+
+// verifyProvenance checks every subject of provenance.json, if present,
+// against the file it names.
+func verifyProvenance(outputDir string) error {
+	data, err := os.ReadFile(filepath.Join(outputDir, provenanceFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", provenanceFile, err)
+	}
+	var st provenanceStatement
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("parsing %s: %w", provenanceFile, err)
+	}
+	if st.Type != "https://in-toto.io/Statement/v1" {
+		return fmt.Errorf("%s: unsupported statement type %q", provenanceFile, st.Type)
+	}
+	for _, s := range st.Subject {
+		want := s.Digest["sha256"]
+		if want == "" {
+			return fmt.Errorf("%s: subject %s has no sha256 digest", provenanceFile, s.Name)
+		}
+		if err := verifyFileDigest(outputDir, s.Name, want); err != nil {
+			return fmt.Errorf("provenance: %w", err)
+		}
+	}
+	return nil
+}
+
+// verifySBOM checks the SHA-256 of every skill file the SBOM, if
+// present, lists — the skills/ tree is copied into the output without
+// being listed in checksums.json, so this is what covers its scripts.
+func verifySBOM(outputDir string) error {
+	data, err := os.ReadFile(filepath.Join(outputDir, sbomFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", sbomFile, err)
+	}
+	var bom sbomDocument
+	if err := json.Unmarshal(data, &bom); err != nil {
+		return fmt.Errorf("parsing %s: %w", sbomFile, err)
+	}
+	if bom.BOMFormat != "CycloneDX" {
+		return fmt.Errorf("%s: unsupported bomFormat %q", sbomFile, bom.BOMFormat)
+	}
+	for _, c := range bom.Components {
+		if c.Type != "file" || !strings.HasPrefix(c.Name, "skills/") {
+			continue
+		}
+		for _, h := range c.Hashes {
+			if h.Alg != "SHA-256" {
+				continue
+			}
+			if err := verifyFileDigest(outputDir, c.Name, h.Content); err != nil {
+				return fmt.Errorf("sbom: %w", err)
+			}
+		}
+	}
 	return nil
 }
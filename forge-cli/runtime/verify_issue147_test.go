@@ -96,7 +96,7 @@ func TestVerifyBuildOutput_ContainerLayoutWorksEndToEnd(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := VerifyBuildOutput(resolveChecksumsDir(workDir)); err != nil {
+	if err := VerifyBuildOutput(resolveChecksumsDir(workDir), false); err != nil {
 		t.Fatalf("container-layout verification must succeed; got: %v", err)
 	}
 }
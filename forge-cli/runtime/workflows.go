@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/workflow"
+)
+
+// Workflows: forge.yaml `workflows:` declares step pipelines that run
+// the same way every time. Schedules and webhook triggers start them by
+// name, and the agent through the run_workflow tool.
+
+// registerWorkflows builds the workflow engine and registers the
+// run_workflow tool when forge.yaml declares workflows.
+func (r *Runner) registerWorkflows(reg *tools.Registry, hooks *coreruntime.HookRegistry, client llm.Client, auditLogger *coreruntime.AuditLogger) {
+	if len(r.cfg.Config.Workflows) == 0 {
+		return
+	}
+	r.workflows = workflow.New(workflow.Config{
+		Workflows: r.cfg.Config.Workflows,
+		Tools:     workflowToolRunner(reg, hooks),
+		Client:    client,
+		Audit:     auditLogger,
+	})
+	if err := reg.Register(builtins.NewRunWorkflowTool(r.workflows)); err != nil {
+		r.logger.Warn("failed to register run_workflow tool", map[string]any{"error": err.Error()})
+	}
+}
+
+// workflowToolRunner calls tools for workflow steps through the
+// registry, so origin policies, read-only mode and the sandbox apply,
+// and fires the tool hooks around each call, so guardrails and the
+// tool_exec audit see steps like any call the agent makes.
+func workflowToolRunner(reg *tools.Registry, hooks *coreruntime.HookRegistry) workflow.ToolRunner {
+	return func(ctx context.Context, name string, args json.RawMessage) (string, error) {
+		callID := "wf-call-" + coreruntime.GenerateID()
+		if err := hooks.Fire(ctx, coreruntime.BeforeToolExec, &coreruntime.HookContext{
+			ToolName:      name,
+			ToolCallID:    callID,
+			ToolInput:     string(args),
+			TaskID:        coreruntime.TaskIDFromContext(ctx),
+			CorrelationID: coreruntime.CorrelationIDFromContext(ctx),
+		}); err != nil {
+			return "", fmt.Errorf("before tool exec hook: %w", err)
+		}
+		start := time.Now()
+		out, err := reg.Execute(ctx, name, args)
+		after := &coreruntime.HookContext{
+			ToolName:         name,
+			ToolCallID:       callID,
+			ToolInput:        string(args),
+			ToolOutput:       out,
+			Error:            err,
+			TaskID:           coreruntime.TaskIDFromContext(ctx),
+			CorrelationID:    coreruntime.CorrelationIDFromContext(ctx),
+			ToolExecDuration: time.Since(start),
+		}
+		if herr := hooks.Fire(ctx, coreruntime.AfterToolExec, after); herr != nil {
+			return "", fmt.Errorf("after tool exec hook: %w", herr)
+		}
+		return after.ToolOutput, err
+	}
+}
+
+// runWorkflow runs a workflow for a schedule or trigger and returns its
+// output as the message delivered to the channel. Under --dry-run
+// nothing runs: a workflow's tool calls are not planned like the agent's.
+func (r *Runner) runWorkflow(ctx context.Context, id string, opts workflow.RunOptions) (*a2a.Message, error) {
+	if r.workflows == nil {
+		return nil, errors.New("workflows are not available without an LLM executor")
+	}
+	if r.cfg.DryRun {
+		r.logger.Info("dry run: workflow not run", map[string]any{"workflow": id, "trigger": opts.Trigger})
+		return nil, nil
+	}
+	run, err := r.workflows.Run(ctx, id, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &a2a.Message{
+		Role:  a2a.MessageRoleAgent,
+		Parts: []a2a.Part{a2a.NewTextPart(run.Output)},
+	}, nil
+}
+
+// startTriggerWorkflow runs a trigger's workflow in the background with
+// the request as its inputs and delivers the output to the trigger's
+// channel. It returns the run ID.
+func (r *Runner) startTriggerWorkflow(reqCtx context.Context, t *webhookTrigger, data triggerData) string {
+	runID := workflow.NewRunID(t.Workflow)
+	inputs := map[string]any{
+		"trigger": data.Trigger,
+		"payload": data.Payload,
+		"body":    data.Body,
+		"headers": data.Headers,
+	}
+	// The run outlives the webhook request; keep its correlation and
+	// trace context but not its cancellation.
+	ctx := context.WithoutCancel(reqCtx)
+	go func() {
+		msg, err := r.runWorkflow(ctx, t.Workflow, workflow.RunOptions{ID: runID, Inputs: inputs, Trigger: "webhook:" + t.ID})
+		if err != nil {
+			r.logger.Error("webhook trigger workflow failed", map[string]any{"trigger": t.ID, "run_id": runID, "error": err.Error()})
+			return
+		}
+		r.deliverTriggerResult(ctx, t, msg)
+	}()
+	return runID
+}
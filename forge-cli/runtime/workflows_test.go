@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+type workflowStepTool struct{}
+
+func (workflowStepTool) Name() string                 { return "lookup" }
+func (workflowStepTool) Description() string          { return "test tool" }
+func (workflowStepTool) Category() tools.Category     { return tools.CategoryBuiltin }
+func (workflowStepTool) InputSchema() json.RawMessage { return json.RawMessage(`{"type":"object"}`) }
+func (workflowStepTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	return "secret-token found", nil
+}
+
+func TestWorkflowToolRunnerFiresHooks(t *testing.T) {
+	reg := tools.NewRegistry()
+	if err := reg.Register(workflowStepTool{}); err != nil {
+		t.Fatal(err)
+	}
+	hooks := coreruntime.NewHookRegistry()
+	var before []string
+	hooks.Register(coreruntime.BeforeToolExec, func(ctx context.Context, hc *coreruntime.HookContext) error {
+		before = append(before, hc.ToolName+" "+hc.TaskID)
+		if hc.ToolInput == `{"blocked":true}` {
+			return errors.New("denied")
+		}
+		return nil
+	})
+	hooks.Register(coreruntime.AfterToolExec, func(ctx context.Context, hc *coreruntime.HookContext) error {
+		hc.ToolOutput = "[REDACTED] found"
+		return nil
+	})
+	run := workflowToolRunner(reg, hooks)
+
+	ctx := coreruntime.WithTaskID(context.Background(), "wf-triage-1")
+	out, err := run(ctx, "lookup", json.RawMessage(`{}`))
+	if err != nil || out != "[REDACTED] found" {
+		t.Errorf("run = %q, %v; want the redacted output", out, err)
+	}
+	if _, err := run(ctx, "lookup", json.RawMessage(`{"blocked":true}`)); err == nil {
+		t.Error("a call a BeforeToolExec hook refuses should fail")
+	}
+	if len(before) != 2 || before[0] != "lookup wf-triage-1" {
+		t.Errorf("hook saw %v", before)
+	}
+}
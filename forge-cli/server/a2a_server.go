@@ -14,12 +14,15 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/auth"
 	"github.com/initializ/forge/forge-core/observability"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -59,17 +62,35 @@ type RateLimitConfig struct {
 	// DoS via cancel-spam is naturally bounded by the registry's
 	// O(1) unknown-task lookup. See issue #110 / FWS-10.
 	CancelExempt bool
+	// Tenants overrides the limits for a tenant's requests, by tenant.
+	// A tenant's requests share one budget across all its callers'
+	// addresses; a tenant without an entry gets the limits above.
+	Tenants map[string]TenantRateLimit
+}
+
+// TenantRateLimit is one tenant's request budget, reads and writes
+// alike. A zero field keeps the corresponding RateLimitConfig value.
+type TenantRateLimit struct {
+	RPS   float64
+	Burst int
 }
 
 // ServerConfig configures the A2A HTTP server.
 type ServerConfig struct {
 	Port            int
 	Host            string        // bind address (default "" = all interfaces)
+	Socket          string        // Unix socket path; replaces Port/Host when set
+	Listener        net.Listener  // pre-opened listener (socket activation); replaces Socket/Port/Host
 	ShutdownTimeout time.Duration // graceful shutdown timeout (0 = immediate)
 	AgentCard       *a2a.AgentCard
 	AuthMiddleware  func(http.Handler) http.Handler // optional auth middleware
 	AllowedOrigins  []string                        // CORS allowed origins
 	RateLimit       *RateLimitConfig                // optional rate limit config
+	Limits          *RequestLimits                  // optional; DefaultRequestLimits when nil
+	// OnDrainTimeout is called when ShutdownTimeout elapses during a
+	// graceful shutdown with inFlight tasks still running; the runner
+	// cancels them. Optional.
+	OnDrainTimeout func(inFlight int)
 }
 
 type httpRoute struct {
@@ -81,6 +102,9 @@ type httpRoute struct {
 type Server struct {
 	port            int
 	host            string
+	socket          string
+	listener        net.Listener
+	addr            string // where Start listens: "host:port" or "unix:/path"
 	shutdownTimeout time.Duration
 	card            *a2a.AgentCard
 	cardMu          sync.RWMutex
@@ -91,7 +115,14 @@ type Server struct {
 	authMiddleware  func(http.Handler) http.Handler
 	allowedOrigins  []string
 	rateLimit       *RateLimitConfig
+	limits          *RequestLimits
 	srv             *http.Server
+
+	drainMu        sync.Mutex
+	draining       bool
+	tasks          sync.WaitGroup // in-flight tasks; see beginTask
+	inFlight       atomic.Int64
+	onDrainTimeout func(inFlight int)
 }
 
 // NewServer creates a new A2A server.
@@ -103,6 +134,8 @@ func NewServer(cfg ServerConfig) *Server {
 	s := &Server{
 		port:            cfg.Port,
 		host:            cfg.Host,
+		socket:          cfg.Socket,
+		listener:        cfg.Listener,
 		shutdownTimeout: cfg.ShutdownTimeout,
 		card:            cfg.AgentCard,
 		store:           a2a.NewTaskStore(),
@@ -111,10 +144,15 @@ func NewServer(cfg ServerConfig) *Server {
 		authMiddleware:  cfg.AuthMiddleware,
 		allowedOrigins:  allowedOrigins,
 		rateLimit:       cfg.RateLimit,
+		limits:          cfg.Limits,
+		onDrainTimeout:  cfg.OnDrainTimeout,
 	}
 	if s.rateLimit == nil {
 		s.rateLimit = defaultRateLimitConfig()
 	}
+	if s.limits == nil {
+		s.limits = DefaultRequestLimits()
+	}
 	return s
 }
 
@@ -146,12 +184,25 @@ func (s *Server) TaskStore() *a2a.TaskStore {
 	return s.store
 }
 
+// RateLimit returns the rate limits the server enforces, defaults
+// included.
+func (s *Server) RateLimit() RateLimitConfig {
+	return *s.rateLimit
+}
+
 // Port returns the port the server is configured to listen on (or the actual
 // port after Start resolves port conflicts).
 func (s *Server) Port() int {
 	return s.port
 }
 
+// Addr returns the address the server listens on once Start has bound
+// it: "host:port" for TCP, "unix:/path" for a Unix socket. Empty before
+// Start.
+func (s *Server) Addr() string {
+	return s.addr
+}
+
 func (s *Server) agentCard() *a2a.AgentCard {
 	s.cardMu.RLock()
 	defer s.cardMu.RUnlock()
@@ -165,7 +216,11 @@ func (s *Server) Start(ctx context.Context) error {
 
 	// Register REST-style HTTP handlers first (more specific patterns)
 	for _, route := range s.httpHandlers {
-		mux.HandleFunc(route.pattern, route.handler)
+		h := s.limitBody(route.pattern, route.handler)
+		if isTaskRoute(route.pattern) {
+			h = s.trackTask(h)
+		}
+		mux.HandleFunc(route.pattern, h)
 	}
 
 	// Register core A2A handlers. The canonical Agent Card path is
@@ -196,35 +251,18 @@ func (s *Server) Start(ctx context.Context) error {
 		MaxHeaderBytes: 1 << 20, // 1 MiB max header size
 	}
 
-	// Try specified port, then auto-increment up to 10 times on conflict.
-	var ln net.Listener
-	var listenErr error
-	actualPort := s.port
-	for range 10 {
-		addr := fmt.Sprintf("%s:%d", s.host, actualPort)
-		ln, listenErr = net.Listen("tcp", addr)
-		if listenErr == nil {
-			break
-		}
-		if !isAddrInUse(listenErr) {
-			return fmt.Errorf("listen on %s: %w", addr, listenErr)
-		}
-		actualPort++
-	}
-	if listenErr != nil {
-		return fmt.Errorf("all ports %d-%d in use: %w", s.port, actualPort, listenErr)
+	ln, err := s.listen()
+	if err != nil {
+		return err
 	}
-	s.port = actualPort // update so banner/info reflect actual port
-	s.srv.Addr = fmt.Sprintf("%s:%d", s.host, actualPort)
 
+	// Graceful shutdown: drain tasks first (see drain), then close
+	// the listener and idle connections.
 	go func() {
 		<-ctx.Done()
-		shutdownCtx := context.Background()
-		if s.shutdownTimeout > 0 {
-			var cancel context.CancelFunc
-			shutdownCtx, cancel = context.WithTimeout(shutdownCtx, s.shutdownTimeout)
-			defer cancel()
-		}
+		s.drain()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), drainGrace)
+		defer cancel()
 		s.srv.Shutdown(shutdownCtx) //nolint:errcheck
 	}()
 
@@ -258,31 +296,117 @@ func (s *Server) handleAgentCardLegacy(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(s.agentCard()) //nolint:errcheck
 }
 
+// handleHealthz reports 503 "draining" once a graceful shutdown has
+// begun, so load balancers stop routing new tasks here.
 func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+	if s.Draining() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"draining"}`)) //nolint:errcheck
+		return
+	}
 	w.Write([]byte(`{"status":"ok"}`)) //nolint:errcheck
 }
 
 func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
-	r.Body = http.MaxBytesReader(w, r.Body, 2<<20) // 2 MiB
+	maxBody := s.limits.bodyLimit("POST /")
+	if maxBody > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+	}
 
-	var req a2a.JSONRPCRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		var maxBytesErr *http.MaxBytesError
-		if errors.As(err, &maxBytesErr) || isMaxBytesError(err) {
-			writeJSON(w, http.StatusRequestEntityTooLarge,
-				a2a.NewErrorResponse(nil, a2a.ErrCodeParseError, "request body too large"))
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		if le, ok := asLimitError(err, maxBody); ok {
+			writeJSONRPCLimitError(w, nil, a2a.ErrCodeParseError, le)
 			return
 		}
 		writeJSON(w, http.StatusOK, a2a.NewErrorResponse(nil, a2a.ErrCodeParseError, "parse error: "+err.Error()))
 		return
 	}
+	if isJSONRPCBatch(raw) {
+		s.handleJSONRPCBatch(w, r, raw)
+		return
+	}
+
+	var req a2a.JSONRPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		writeJSON(w, http.StatusOK, a2a.NewErrorResponse(nil, a2a.ErrCodeParseError, "parse error: "+err.Error()))
+		return
+	}
 
 	if req.JSONRPC != "2.0" {
 		writeJSON(w, http.StatusOK, a2a.NewErrorResponse(req.ID, a2a.ErrCodeInvalidRequest, "jsonrpc must be \"2.0\""))
 		return
 	}
+	if isTaskMethod(req.Method) {
+		if err := s.checkSendParams(req.Params); err != nil {
+			writeJSONRPCLimitError(w, req.ID, a2a.ErrCodeInvalidParams, err.(*LimitError))
+			return
+		}
+	}
+
+	ctx, span := startDispatchSpan(dispatchContext(r), req.Method)
+	defer span.End()
+
+	if isTaskMethod(req.Method) {
+		if !s.beginTask() {
+			span.SetStatus(codes.Error, "draining")
+			writeDraining(w, req.ID)
+			return
+		}
+		defer s.endTask()
+	}
+
+	// Check SSE handlers first (for streaming methods)
+	if h, ok := s.sseHandlers[req.Method]; ok {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeJSON(w, http.StatusOK, a2a.NewErrorResponse(req.ID, a2a.ErrCodeInternal, "streaming not supported"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		sw := s.sseWriter(w)
+		if f, ok := sw.(http.Flusher); ok {
+			flusher = f
+		}
+		h(ctx, req.ID, req.Params, sw, flusher)
+		return
+	}
 
+	// Check regular handlers
+	if h, ok := s.handlers[req.Method]; ok {
+		// Attach a per-request response-header stage so the handler
+		// can publish FWS-3 X-Forge-* invocation-usage headers (or
+		// future per-method headers) without needing access to the
+		// http.ResponseWriter, which the JSON-RPC Handler signature
+		// deliberately omits. The dispatcher drains the stage onto
+		// the writer's Header() before writeJSON emits the body.
+		ctx = WithResponseHeaderStage(ctx)
+		resp := h(ctx, req.ID, req.Params)
+		DrainResponseHeaderStage(ctx, w.Header())
+		if resp != nil && resp.Error != nil {
+			// JSON-RPC errors surface as Error/Ok on the span (the
+			// HTTP response itself is still 200 — JSON-RPC semantics).
+			// Numeric code stays attribute-only; descriptive text goes
+			// on the status so trace browsers display it inline.
+			span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", resp.Error.Code))
+			span.SetStatus(codes.Error, resp.Error.Message)
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	// Method not found also surfaces as a span error so an operator
+	// scanning traces sees the misroute without having to grep the body.
+	span.SetStatus(codes.Error, "method not found: "+req.Method)
+	writeJSON(w, http.StatusOK, a2a.NewErrorResponse(req.ID, a2a.ErrCodeMethodNotFound, "method not found: "+req.Method))
+}
+
+// dispatchContext builds the context a JSON-RPC request is dispatched
+// under from the inbound HTTP request's headers.
+func dispatchContext(r *http.Request) context.Context {
 	// Phase 5 (#106) — extract the inbound W3C tracecontext + baggage
 	// BEFORE wrapping with workflow context so the dispatcher span
 	// becomes a CHILD of the upstream caller's span when a
@@ -293,7 +417,7 @@ func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 	// The propagator is the composite TraceContext + Baggage installed
 	// on the OTel global by Phase 0's SetTracerProvider. When the
 	// inbound request has NO traceparent header the propagator returns
-	// the ctx unchanged — and Tracer().Start below opens a new root,
+	// the ctx unchanged — and startDispatchSpan opens a new root,
 	// matching the pre-Phase-5 behavior verbatim.
 	ctx := otel.GetTextMapPropagator().Extract(
 		r.Context(),
@@ -316,7 +440,18 @@ func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 	// stamp wins, or fields omit when no stamp is installed either.
 	ctx = coreruntime.WithTenancyContext(ctx,
 		coreruntime.TenancyContextFromHTTPHeaders(r.Header))
+	// Same for the X-Forge-Channel* headers the channel router sets,
+	// so every event of a channel-triggered invocation carries the
+	// platform's event and message IDs.
+	ctx = coreruntime.WithChannelContext(ctx,
+		coreruntime.ChannelContextFromHTTPHeaders(r.Header))
+	// Task origin, for tool_policies.
+	ctx = tools.WithOrigin(ctx, coreruntime.RequestOrigin(r.Header, tools.OriginA2A, auth.FromRuntime(ctx)))
+	return ctx
+}
 
+// startDispatchSpan opens the inbound dispatch span for method.
+func startDispatchSpan(ctx context.Context, method string) (context.Context, trace.Span) {
 	// Phase 3 (#104) — open the inbound dispatch span. Span name
 	// mirrors the JSON-RPC method ("a2a.tasks/send", "a2a.tasks/get",
 	// "a2a.tasks/cancel") so backend dashboards key by the same
@@ -329,12 +464,11 @@ func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 	// Per-iteration LLM and tool spans live in the executor (Phase 3
 	// continues in forge-core/runtime/loop.go); this is the root for
 	// every inbound A2A request — OR a child of the upstream span
-	// when Phase 5's propagator extracted one above.
-	ctx, span := coreruntime.Tracer().Start(ctx, "a2a."+req.Method,
+	// when Phase 5's propagator extracted one in dispatchContext.
+	ctx, span := coreruntime.Tracer().Start(ctx, "a2a."+method,
 		trace.WithSpanKind(trace.SpanKindServer),
-		trace.WithAttributes(attribute.String(observability.AttrForgeA2AMethod, req.Method)),
+		trace.WithAttributes(attribute.String(observability.AttrForgeA2AMethod, method)),
 	)
-	defer span.End()
 	if wf := coreruntime.WorkflowContextFromContext(ctx); !wf.IsZero() {
 		// FWS-2 orchestrator correlation surfaces on the span so a
 		// trace browser can pivot from a workflow run to every Forge
@@ -354,48 +488,7 @@ func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 		}
 		span.SetAttributes(attrs...)
 	}
-
-	// Check SSE handlers first (for streaming methods)
-	if h, ok := s.sseHandlers[req.Method]; ok {
-		flusher, ok := w.(http.Flusher)
-		if !ok {
-			writeJSON(w, http.StatusOK, a2a.NewErrorResponse(req.ID, a2a.ErrCodeInternal, "streaming not supported"))
-			return
-		}
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
-		h(ctx, req.ID, req.Params, w, flusher)
-		return
-	}
-
-	// Check regular handlers
-	if h, ok := s.handlers[req.Method]; ok {
-		// Attach a per-request response-header stage so the handler
-		// can publish FWS-3 X-Forge-* invocation-usage headers (or
-		// future per-method headers) without needing access to the
-		// http.ResponseWriter, which the JSON-RPC Handler signature
-		// deliberately omits. The dispatcher drains the stage onto
-		// the writer's Header() before writeJSON emits the body.
-		ctx = WithResponseHeaderStage(ctx)
-		resp := h(ctx, req.ID, req.Params)
-		DrainResponseHeaderStage(ctx, w.Header())
-		if resp != nil && resp.Error != nil {
-			// JSON-RPC errors surface as Error/Ok on the span (the
-			// HTTP response itself is still 200 — JSON-RPC semantics).
-			// Numeric code stays attribute-only; descriptive text goes
-			// on the status so trace browsers display it inline.
-			span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", resp.Error.Code))
-			span.SetStatus(codes.Error, resp.Error.Message)
-		}
-		writeJSON(w, http.StatusOK, resp)
-		return
-	}
-
-	// Method not found also surfaces as a span error so an operator
-	// scanning traces sees the misroute without having to grep the body.
-	span.SetStatus(codes.Error, "method not found: "+req.Method)
-	writeJSON(w, http.StatusOK, a2a.NewErrorResponse(req.ID, a2a.ErrCodeMethodNotFound, "method not found: "+req.Method))
+	return ctx, span
 }
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
@@ -487,14 +580,26 @@ func securityHeadersMiddleware(next http.Handler) http.Handler {
 }
 
 // WriteSSEEvent writes a single SSE event to the response writer.
+//
+// On a writer handed out by the server, an event over
+// RequestLimits.MaxSSEEventBytes is shrunk to fit — a task loses its
+// history — or replaced by an "error" event, in which case the
+// *LimitError is returned after the error event is written.
 func WriteSSEEvent(w http.ResponseWriter, flusher http.Flusher, event string, data any) error {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
+	var limitErr error
+	if lw, ok := w.(*sseLimitWriter); ok && len(jsonData) > lw.max {
+		event, jsonData, limitErr = shrinkSSEEvent(event, data, lw.max)
+		if jsonData == nil {
+			return limitErr
+		}
+	}
 	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, jsonData)
 	flusher.Flush()
-	return nil
+	return limitErr
 }
 
 // isMaxBytesError checks if the error chain contains an http.MaxBytesError
@@ -544,7 +649,8 @@ type visitor struct {
 	lastSeen     time.Time
 }
 
-// rateLimitMiddleware returns middleware that enforces per-IP rate limits.
+// rateLimitMiddleware returns middleware that enforces per-IP rate limits,
+// or per-tenant ones for requests the auth layer attributed to a tenant.
 // GET/HEAD/OPTIONS use the read limiter; POST/PUT/DELETE use the write limiter.
 // Returns 429 with Retry-After header when the limit is exceeded.
 func rateLimitMiddleware(cfg *RateLimitConfig) func(http.Handler) http.Handler {
@@ -566,16 +672,25 @@ func rateLimitMiddleware(cfg *RateLimitConfig) func(http.Handler) http.Handler {
 		}
 	}()
 
-	getVisitor := func(ip string) *visitor {
+	getVisitor := func(key, tenant string) *visitor {
 		mu.Lock()
 		defer mu.Unlock()
-		v, ok := visitors[ip]
+		v, ok := visitors[key]
 		if !ok {
+			readRPS, readBurst, writeRPS, writeBurst := cfg.ReadRPS, cfg.ReadBurst, cfg.WriteRPS, cfg.WriteBurst
+			if t, ok := cfg.Tenants[tenant]; ok {
+				if t.RPS > 0 {
+					readRPS, writeRPS = t.RPS, t.RPS
+				}
+				if t.Burst > 0 {
+					readBurst, writeBurst = t.Burst, t.Burst
+				}
+			}
 			v = &visitor{
-				readLimiter:  rate.NewLimiter(rate.Limit(cfg.ReadRPS), cfg.ReadBurst),
-				writeLimiter: rate.NewLimiter(rate.Limit(cfg.WriteRPS), cfg.WriteBurst),
+				readLimiter:  rate.NewLimiter(rate.Limit(readRPS), readBurst),
+				writeLimiter: rate.NewLimiter(rate.Limit(writeRPS), writeBurst),
 			}
-			visitors[ip] = v
+			visitors[key] = v
 		}
 		v.lastSeen = time.Now()
 		return v
@@ -603,7 +718,13 @@ func rateLimitMiddleware(cfg *RateLimitConfig) func(http.Handler) http.Handler {
 				}
 			}
 
-			v := getVisitor(ip)
+			// Tenant keys can't collide with addresses: tenant IDs
+			// are kebab-case and a key of one carries a prefix.
+			key, tenant := ip, coreruntime.TenantFromContext(r.Context())
+			if tenant != "" {
+				key = "tenant/" + tenant
+			}
+			v := getVisitor(key, tenant)
 			var limiter *rate.Limiter
 			switch r.Method {
 			case http.MethodGet, http.MethodHead, http.MethodOptions:
@@ -619,6 +740,9 @@ func rateLimitMiddleware(cfg *RateLimitConfig) func(http.Handler) http.Handler {
 					a2a.NewErrorResponse(nil, a2a.ErrCodeInternal, "rate limit exceeded"))
 				return
 			}
+			if limiter == v.writeLimiter {
+				r = r.WithContext(withWriteLimiter(r.Context(), limiter))
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -632,9 +756,10 @@ func rateLimitMiddleware(cfg *RateLimitConfig) func(http.Handler) http.Handler {
 const jsonRPCPeekCap = 4 << 10
 
 // isTasksCancel returns true when the request body's JSON-RPC `method`
-// field is "tasks/cancel". The body is read up to jsonRPCPeekCap bytes
-// and then restored via io.NopCloser so downstream handlers see the
-// full payload. Any error (read failure, unparseable JSON, method
+// field is "tasks/cancel". Only the first jsonRPCPeekCap bytes are read;
+// they are stitched back in front of the unread remainder so
+// downstream handlers see the full payload through the original body
+// (and its MaxBytesReader cap). Any error (read failure, unparseable JSON, method
 // missing) returns false — the caller falls back to standard write
 // classification.
 func isTasksCancel(r *http.Request) bool {
@@ -644,11 +769,10 @@ func isTasksCancel(r *http.Request) bool {
 	buf := make([]byte, jsonRPCPeekCap)
 	n, _ := io.ReadFull(r.Body, buf)
 	body := buf[:n]
-	// Drain any tail past the cap so r.Body.Close() doesn't leak the
-	// connection — and stitch the cap'd prefix back into a new reader.
-	rest, _ := io.ReadAll(r.Body)
-	_ = r.Body.Close()
-	r.Body = io.NopCloser(bytes.NewReader(append(body, rest...)))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
 
 	var env struct {
 		Method string `json:"method"`
@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
 )
 
 func TestSecurityHeadersPresent(t *testing.T) {
@@ -280,3 +282,36 @@ func TestRateLimitMiddleware_PerIPIsolation(t *testing.T) {
 		t.Errorf("IP2 first request: status = %d, want %d", rec.Code, http.StatusOK)
 	}
 }
+
+func TestRateLimitMiddleware_PerTenant(t *testing.T) {
+	cfg := &RateLimitConfig{
+		ReadRPS: 0.001, ReadBurst: 1, WriteRPS: 0.001, WriteBurst: 1,
+		Tenants: map[string]TenantRateLimit{"acme": {Burst: 2}},
+	}
+	handler := rateLimitMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(tenant, addr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		req = req.WithContext(coreruntime.WithTenant(req.Context(), tenant))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// acme's budget of two is shared across its callers' addresses.
+	if send("acme", "10.0.0.2:1") != http.StatusOK || send("acme", "10.0.0.3:1") != http.StatusOK {
+		t.Fatal("acme's burst of 2 not allowed")
+	}
+	if got := send("acme", "10.0.0.4:1"); got != http.StatusTooManyRequests {
+		t.Errorf("acme's third request: status = %d, want 429", got)
+	}
+	// Another tenant, and untenanted callers, have budgets of their own.
+	if got := send("globex", "10.0.0.2:1"); got != http.StatusOK {
+		t.Errorf("globex: status = %d, want 200", got)
+	}
+	if got := send("", "10.0.0.2:1"); got != http.StatusOK {
+		t.Errorf("untenanted caller: status = %d, want 200", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
 )
 
@@ -14,12 +15,18 @@ import (
 // Payment Required response when the admission middleware denies an
 // inbound invocation. The shape mirrors the platform's admission
 // response so a caller library can deserialize them with one struct.
+// Code, Retryable and RetryAfterSeconds are the a2a error taxonomy
+// fields every Forge error body carries; a denial is retryable once
+// the platform names a reset time.
 type admissionResponseBody struct {
-	Error   string `json:"error"`
-	Reason  string `json:"reason,omitempty"`
-	Scope   string `json:"scope,omitempty"`
-	Window  string `json:"window,omitempty"`
-	ResetAt string `json:"reset_at,omitempty"`
+	Error             string        `json:"error"`
+	Reason            string        `json:"reason,omitempty"`
+	Scope             string        `json:"scope,omitempty"`
+	Window            string        `json:"window,omitempty"`
+	ResetAt           string        `json:"reset_at,omitempty"`
+	Code              a2a.ErrorCode `json:"code"`
+	Retryable         bool          `json:"retryable"`
+	RetryAfterSeconds int           `json:"retry_after_seconds,omitempty"`
 }
 
 // AdmissionMiddleware gates inbound A2A requests on the platform's
@@ -69,23 +76,24 @@ func AdmissionMiddleware(
 // negative so a stale ResetAt doesn't produce a negative header.
 func writeAdmissionDenied(w http.ResponseWriter, d coreruntime.Decision) {
 	w.Header().Set("Content-Type", "application/json")
-	if !d.ResetAt.IsZero() {
-		secs := time.Until(d.ResetAt).Seconds()
-		if secs < 0 {
-			secs = 0
-		}
-		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(secs))))
-	}
-	w.WriteHeader(http.StatusPaymentRequired)
 	body := admissionResponseBody{
 		Error:  "admission_denied",
 		Reason: d.Reason,
 		Scope:  d.Scope,
 		Window: d.Window,
+		Code:   a2a.ErrorBudgetExceeded,
 	}
 	if !d.ResetAt.IsZero() {
+		secs := time.Until(d.ResetAt).Seconds()
+		if secs < 0 {
+			secs = 0
+		}
+		body.RetryAfterSeconds = int(math.Ceil(secs))
+		body.Retryable = true
 		body.ResetAt = d.ResetAt.UTC().Format(time.RFC3339)
+		w.Header().Set("Retry-After", strconv.Itoa(body.RetryAfterSeconds))
 	}
+	w.WriteHeader(http.StatusPaymentRequired)
 	_ = json.NewEncoder(w).Encode(body)
 }
 
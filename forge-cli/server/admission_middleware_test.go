@@ -98,6 +98,9 @@ func TestAdmissionMiddleware_DenyReturns402WithStructuredBody(t *testing.T) {
 	if _, ok := body["reset_at"]; !ok {
 		t.Errorf("body missing reset_at")
 	}
+	if body["code"] != "budget_exceeded" || body["retryable"] != true {
+		t.Errorf("taxonomy fields = code %v retryable %v, want budget_exceeded true", body["code"], body["retryable"])
+	}
 }
 
 // TestAdmissionMiddleware_DenyClampsNegativeRetryAfter pins the
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/time/rate"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// JSON-RPC 2.0 batches: a POST / body that is an array of requests is
+// answered with an array of responses in the same order. Entries run
+// concurrently, at most RequestLimits.BatchConcurrency at a time, so a
+// client submitting many small tasks (bulk classification, say) needs
+// one round-trip instead of hundreds. Streaming methods can't share a
+// batch response and are refused per entry.
+//
+// Each task entry costs one write rate-limit token, as it would sent on
+// its own: rateLimitMiddleware takes one for the request and the batch
+// handler the rest, refusing the whole batch with 429 when the caller
+// lacks them. Notifications (entries without an id) run but get no
+// response entry.
+
+// writeLimiterKey carries the caller's write limiter from
+// rateLimitMiddleware to handleJSONRPCBatch.
+type writeLimiterKey struct{}
+
+func withWriteLimiter(ctx context.Context, l *rate.Limiter) context.Context {
+	return context.WithValue(ctx, writeLimiterKey{}, l)
+}
+
+func writeLimiterFromContext(ctx context.Context) *rate.Limiter {
+	l, _ := ctx.Value(writeLimiterKey{}).(*rate.Limiter)
+	return l
+}
+
+// batchEntryHead is the part of a batch entry read before dispatch.
+type batchEntryHead struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// isJSONRPCBatch reports whether a JSON-RPC body is a batch array.
+func isJSONRPCBatch(raw json.RawMessage) bool {
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+func (s *Server) handleJSONRPCBatch(w http.ResponseWriter, r *http.Request, raw json.RawMessage) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		writeJSON(w, http.StatusOK, a2a.NewErrorResponse(nil, a2a.ErrCodeParseError, "parse error: "+err.Error()))
+		return
+	}
+	if len(items) == 0 {
+		writeJSON(w, http.StatusOK, a2a.NewErrorResponse(nil, a2a.ErrCodeInvalidRequest, "empty batch"))
+		return
+	}
+	if max := s.limits.MaxBatchRequests; max > 0 && len(items) > max {
+		writeJSONRPCLimitError(w, nil, a2a.ErrCodeInvalidRequest, &LimitError{Limit: "max_batch_requests", Max: int64(max)})
+		return
+	}
+
+	tasks := 0
+	notification := make([]bool, len(items))
+	for i, item := range items {
+		var head batchEntryHead
+		if json.Unmarshal(item, &head) != nil {
+			continue // dispatchBatchEntry reports it
+		}
+		notification[i] = head.ID == nil && head.Method != ""
+		if _, streams := s.sseHandlers[head.Method]; isTaskMethod(head.Method) && !streams {
+			tasks++
+		}
+	}
+	// rateLimitMiddleware already took one token for the request.
+	if l := writeLimiterFromContext(r.Context()); l != nil && tasks > 1 && !l.AllowN(time.Now(), tasks-1) {
+		msg := "rate limit exceeded"
+		if tasks > l.Burst() {
+			msg = fmt.Sprintf("rate limit exceeded: a batch of %d tasks is more than the write burst of %d; split it", tasks, l.Burst())
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(float64(tasks-1)/float64(l.Limit())))))
+		writeJSON(w, http.StatusTooManyRequests, a2a.NewErrorResponse(nil, a2a.ErrCodeInternal, msg))
+		return
+	}
+
+	ctx := dispatchContext(r)
+	concurrency := s.limits.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	out := make([]*a2a.JSONRPCResponse, len(items))
+	var wg sync.WaitGroup
+	for i, item := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			out[i] = s.dispatchBatchEntry(ctx, item)
+		}()
+	}
+	wg.Wait()
+
+	resps := make([]*a2a.JSONRPCResponse, 0, len(out))
+	for i, resp := range out {
+		if !notification[i] {
+			resps = append(resps, resp)
+		}
+	}
+	if len(resps) == 0 {
+		// JSON-RPC 2.0: an all-notification batch gets no body at all.
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, resps)
+}
+
+// dispatchBatchEntry runs one request of a batch and returns its
+// response. Failures a lone request reports with an HTTP status — a
+// limit, a drain — become error responses, since the batch shares one.
+func (s *Server) dispatchBatchEntry(ctx context.Context, raw json.RawMessage) *a2a.JSONRPCResponse {
+	var req a2a.JSONRPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return a2a.NewErrorResponse(nil, a2a.ErrCodeInvalidRequest, "invalid request: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" {
+		return a2a.NewErrorResponse(req.ID, a2a.ErrCodeInvalidRequest, "jsonrpc must be \"2.0\"")
+	}
+	if _, ok := s.sseHandlers[req.Method]; ok {
+		return a2a.NewErrorResponse(req.ID, a2a.ErrCodeInvalidRequest, req.Method+" streams its response and can't be batched; send it on its own")
+	}
+	if isTaskMethod(req.Method) {
+		if err := s.checkSendParams(req.Params); err != nil {
+			resp := a2a.NewErrorResponse(req.ID, a2a.ErrCodeInvalidParams, err.Error())
+			resp.Error.Data = err.(*LimitError).data()
+			return resp
+		}
+	}
+
+	ctx, span := startDispatchSpan(ctx, req.Method)
+	defer span.End()
+
+	if isTaskMethod(req.Method) {
+		if !s.beginTask() {
+			span.SetStatus(codes.Error, "draining")
+			return a2a.NewErrorResponse(req.ID, a2a.ErrCodeInternal, "server is shutting down; retry the task")
+		}
+		defer s.endTask()
+	}
+
+	h, ok := s.handlers[req.Method]
+	if !ok {
+		span.SetStatus(codes.Error, "method not found: "+req.Method)
+		return a2a.NewErrorResponse(req.ID, a2a.ErrCodeMethodNotFound, "method not found: "+req.Method)
+	}
+	resp := h(ctx, req.ID, req.Params)
+	if resp != nil && resp.Error != nil {
+		span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", resp.Error.Code))
+		span.SetStatus(codes.Error, resp.Error.Message)
+	}
+	return resp
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func postBatch(s *Server, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.handleJSONRPC(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	return rec
+}
+
+func TestBatch_OrderedResponsesWithinConcurrency(t *testing.T) {
+	limits := DefaultRequestLimits()
+	limits.BatchConcurrency = 3
+	s := NewServer(ServerConfig{Limits: limits})
+	var running, peak atomic.Int32
+	s.RegisterHandler("tasks/send", func(_ context.Context, id any, raw json.RawMessage) *a2a.JSONRPCResponse {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		var p a2a.SendTaskParams
+		_ = json.Unmarshal(raw, &p)
+		return a2a.NewResponse(id, a2a.Task{ID: p.ID})
+	})
+	s.RegisterSSEHandler("tasks/sendSubscribe", func(context.Context, any, json.RawMessage, http.ResponseWriter, http.Flusher) {
+		t.Error("streaming method ran inside a batch")
+	})
+
+	var entries []string
+	for i := range 10 {
+		entries = append(entries, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tasks/send","params":{"id":"t%d","message":{"role":"user","parts":[{"kind":"text","text":"x"}]}}}`, i, i))
+	}
+	entries = append(entries,
+		`{"jsonrpc":"2.0","id":10,"method":"tasks/sendSubscribe","params":{"id":"s"}}`,
+		`{"jsonrpc":"2.0","id":11,"method":"tasks/nope"}`,
+		`{"jsonrpc":"1.0","id":12,"method":"tasks/send"}`,
+	)
+	rec := postBatch(s, " ["+strings.Join(entries, ",")+"]")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var out []struct {
+		ID     float64           `json:"id"`
+		Result *a2a.Task         `json:"result"`
+		Error  *a2a.JSONRPCError `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil || len(out) != 13 {
+		t.Fatalf("responses = %s (%v)", rec.Body.String(), err)
+	}
+	for i := range 10 {
+		if out[i].ID != float64(i) || out[i].Result == nil || out[i].Result.ID != fmt.Sprintf("t%d", i) {
+			t.Errorf("response %d = %+v", i, out[i])
+		}
+	}
+	for i, code := range map[int]int{10: a2a.ErrCodeInvalidRequest, 11: a2a.ErrCodeMethodNotFound, 12: a2a.ErrCodeInvalidRequest} {
+		if out[i].Error == nil || out[i].Error.Code != code {
+			t.Errorf("response %d = %+v, want error %d", i, out[i], code)
+		}
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("peak concurrency = %d, want 2..3", p)
+	}
+}
+
+func TestBatch_Limits(t *testing.T) {
+	limits := DefaultRequestLimits()
+	limits.MaxBatchRequests = 2
+	limits.MaxMessageParts = 1
+	s := NewServer(ServerConfig{Limits: limits})
+	s.RegisterHandler("tasks/send", func(_ context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+		return a2a.NewResponse(id, "ok")
+	})
+
+	rec := postBatch(s, `[{"jsonrpc":"2.0","id":1,"method":"tasks/send"},{"jsonrpc":"2.0","id":2,"method":"tasks/send"},{"jsonrpc":"2.0","id":3,"method":"tasks/send"}]`)
+	if rec.Code != http.StatusRequestEntityTooLarge || !strings.Contains(rec.Body.String(), "max_batch_requests") {
+		t.Errorf("oversized batch: %d %s", rec.Code, rec.Body.String())
+	}
+
+	// A limit hit by one entry fails that entry only.
+	rec = postBatch(s, `[{"jsonrpc":"2.0","id":1,"method":"tasks/send","params":{"message":{"parts":[{},{}]}}},{"jsonrpc":"2.0","id":2,"method":"tasks/send"}]`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"limit":"max_message_parts"`) || !strings.Contains(rec.Body.String(), `"result":"ok"`) {
+		t.Errorf("entry over a limit: %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := postBatch(s, `[]`); !strings.Contains(rec.Body.String(), "empty batch") {
+		t.Errorf("empty batch: %s", rec.Body.String())
+	}
+}
+
+func TestBatch_ChargesWriteLimiterPerTask(t *testing.T) {
+	s := NewServer(ServerConfig{Limits: DefaultRequestLimits()})
+	var ran atomic.Int32
+	s.RegisterHandler("tasks/send", func(_ context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+		ran.Add(1)
+		return a2a.NewResponse(id, "ok")
+	})
+	s.RegisterHandler("tasks/get", func(_ context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+		return a2a.NewResponse(id, "ok")
+	})
+	h := rateLimitMiddleware(&RateLimitConfig{ReadRPS: 1, ReadBurst: 10, WriteRPS: 0.001, WriteBurst: 5})(http.HandlerFunc(s.handleJSONRPC))
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		return rec
+	}
+	send := func(id int) string {
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tasks/send"}`, id)
+	}
+
+	// Three tasks and a read take three of the five tokens.
+	if rec := post("[" + send(1) + "," + send(2) + "," + send(3) + `,{"jsonrpc":"2.0","id":4,"method":"tasks/get"}]`); rec.Code != http.StatusOK {
+		t.Fatalf("first batch: %d %s", rec.Code, rec.Body.String())
+	}
+	// Three more need three tokens and only two are left.
+	rec := post("[" + send(5) + "," + send(6) + "," + send(7) + "]")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("second batch: %d %s", rec.Code, rec.Body.String())
+	}
+	if n := ran.Load(); n != 3 {
+		t.Errorf("tasks run = %d, want 3", n)
+	}
+
+	// A batch bigger than the burst can never fit.
+	var entries []string
+	for i := range 6 {
+		entries = append(entries, send(i))
+	}
+	if rec := post("[" + strings.Join(entries, ",") + "]"); rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), "split it") {
+		t.Errorf("batch over the burst: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestBatch_NotificationsGetNoResponse(t *testing.T) {
+	s := NewServer(ServerConfig{Limits: DefaultRequestLimits()})
+	var ran atomic.Int32
+	s.RegisterHandler("tasks/send", func(_ context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+		ran.Add(1)
+		return a2a.NewResponse(id, "ok")
+	})
+
+	rec := postBatch(s, `[{"jsonrpc":"2.0","method":"tasks/send"},{"jsonrpc":"2.0","id":2,"method":"tasks/send"},"junk"]`)
+	var out []struct {
+		ID    any               `json:"id"`
+		Error *a2a.JSONRPCError `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil || len(out) != 2 {
+		t.Fatalf("responses = %s (%v)", rec.Body.String(), err)
+	}
+	if out[0].ID != float64(2) || out[1].Error == nil {
+		t.Errorf("responses = %s", rec.Body.String())
+	}
+
+	rec = postBatch(s, `[{"jsonrpc":"2.0","method":"tasks/send"},{"jsonrpc":"2.0","method":"tasks/send"}]`)
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("all-notification batch: %d %q", rec.Code, rec.Body.String())
+	}
+	if n := ran.Load(); n != 4 {
+		t.Errorf("tasks run = %d, want 4", n)
+	}
+}
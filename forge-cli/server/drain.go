@@ -0,0 +1,126 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// drainGrace is how long tasks cancelled at the end of a drain get to
+// write their final response, and how long the HTTP server then waits
+// for the remaining (non-task) requests before closing connections.
+const drainGrace = 5 * time.Second
+
+// drainRetryAfter is the Retry-After hint on tasks rejected while
+// draining: long enough for a load balancer to route the retry to
+// another replica.
+const drainRetryAfter = 5
+
+// isTaskMethod reports whether a JSON-RPC method starts a task. Only
+// these are refused while draining; reads, cancels and undo keep
+// working so callers can follow their in-flight tasks to the end.
+func isTaskMethod(method string) bool {
+	return method == "tasks/send" || method == "tasks/sendSubscribe"
+}
+
+// isTaskRoute is isTaskMethod for REST-style routes.
+func isTaskRoute(pattern string) bool {
+	return pattern == "POST /tasks/send" || pattern == "POST /tasks/sendSubscribe"
+}
+
+// beginTask admits a task unless the server is draining. Every
+// admitted task must call endTask when its handler returns.
+func (s *Server) beginTask() bool {
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
+	if s.draining {
+		return false
+	}
+	s.tasks.Add(1)
+	s.inFlight.Add(1)
+	return true
+}
+
+func (s *Server) endTask() {
+	s.inFlight.Add(-1)
+	s.tasks.Done()
+}
+
+// Draining reports whether the server has stopped admitting tasks.
+func (s *Server) Draining() bool {
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
+	return s.draining
+}
+
+// InFlight returns the number of tasks currently running.
+func (s *Server) InFlight() int {
+	return int(s.inFlight.Load())
+}
+
+// drain stops admitting tasks and waits up to the shutdown timeout for
+// in-flight ones to finish. Tasks still running then are handed to
+// the OnDrainTimeout hook (the runner cancels them) and get drainGrace
+// to write their final response. The listener stays open throughout,
+// so callers can still poll tasks/get, cancel, and see /healthz
+// report "draining".
+func (s *Server) drain() {
+	s.drainMu.Lock()
+	s.draining = true
+	s.drainMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.tasks.Wait()
+		close(done)
+	}()
+	if waitDone(done, s.shutdownTimeout) {
+		return
+	}
+	if s.onDrainTimeout != nil {
+		s.onDrainTimeout(s.InFlight())
+	}
+	waitDone(done, drainGrace)
+}
+
+// waitDone waits up to d for done to close and reports whether it did.
+func waitDone(done <-chan struct{}, d time.Duration) bool {
+	if d <= 0 {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-done:
+		return true
+	case <-t.C:
+		return false
+	}
+}
+
+// writeDraining rejects a task with 503 and a Retry-After hint.
+func writeDraining(w http.ResponseWriter, id any) {
+	w.Header().Set("Retry-After", strconv.Itoa(drainRetryAfter))
+	writeJSON(w, http.StatusServiceUnavailable,
+		a2a.NewErrorResponse(id, a2a.ErrCodeInternal, "server is shutting down; retry the task"))
+}
+
+// trackTask wraps a REST task route so it is counted while draining
+// and refused once a drain has begun.
+func (s *Server) trackTask(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.beginTask() {
+			writeDraining(w, nil)
+			return
+		}
+		defer s.endTask()
+		h(w, r)
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func startDrainServer(t *testing.T, cfg ServerConfig, handler Handler) (addr string, srv *Server, stop context.CancelFunc, stopped <-chan struct{}) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := lis.Addr().(*net.TCPAddr).Port
+	_ = lis.Close()
+
+	cfg.Port, cfg.Host = port, "127.0.0.1"
+	cfg.AgentCard = &a2a.AgentCard{Name: "test", URL: "http://x", Version: "0.1.0", ProtocolVersion: "0.3.0"}
+	srv = NewServer(cfg)
+	srv.RegisterHandler("tasks/send", handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = srv.Start(ctx)
+		close(done)
+	}()
+	t.Cleanup(cancel)
+
+	addr = "127.0.0.1:" + itoaShim(port)
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if c, err := net.DialTimeout("tcp", addr, 50*time.Millisecond); err == nil {
+			_ = c.Close()
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return addr, srv, cancel, done
+}
+
+// postTask sends tasks/send and returns the HTTP status and
+// Retry-After header, or status 0 when the request failed.
+func postTask(addr string) (status int, retryAfter string) {
+	body, _ := json.Marshal(a2a.JSONRPCRequest{JSONRPC: "2.0", Method: "tasks/send", Params: json.RawMessage(`{}`), ID: "1"})
+	resp, err := http.Post("http://"+addr+"/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, ""
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("Retry-After")
+}
+
+func TestDrain_FinishesInFlightAndRefusesNewTasks(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	addr, srv, stop, stopped := startDrainServer(t, ServerConfig{ShutdownTimeout: 5 * time.Second},
+		func(ctx context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+			close(started)
+			<-release
+			return a2a.NewResponse(id, "done")
+		})
+
+	inFlight := make(chan int, 1)
+	go func() {
+		code, _ := postTask(addr)
+		inFlight <- code
+	}()
+	<-started
+	stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !srv.Draining() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if code, retryAfter := postTask(addr); code != http.StatusServiceUnavailable || retryAfter == "" {
+		t.Errorf("new task while draining: status %d, Retry-After %q", code, retryAfter)
+	}
+	resp, err := http.Get("http://" + addr + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/healthz while draining = %d, want 503", resp.StatusCode)
+	}
+
+	close(release)
+	if code := <-inFlight; code != http.StatusOK {
+		t.Errorf("in-flight task status = %d, want 200", code)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after the drain")
+	}
+}
+
+func TestDrain_TimeoutCallsHook(t *testing.T) {
+	var cancelled atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	addr, _, stop, stopped := startDrainServer(t, ServerConfig{
+		ShutdownTimeout: 50 * time.Millisecond,
+		OnDrainTimeout: func(n int) {
+			cancelled.Store(int32(n))
+			close(release)
+		},
+	}, func(ctx context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+		close(started)
+		<-release
+		return a2a.NewResponse(id, "cancelled")
+	})
+
+	go postTask(addr)
+	<-started
+	stop()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after the drain timeout")
+	}
+	if cancelled.Load() != 1 {
+		t.Errorf("OnDrainTimeout inFlight = %d, want 1", cancelled.Load())
+	}
+}
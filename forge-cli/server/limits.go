@@ -0,0 +1,229 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// RequestLimits bounds what a caller can make the server buffer and
+// what the server streams back. Every inbound body is read through
+// http.MaxBytesReader, so a handler decoding JSON off the wire never
+// holds more than its endpoint's cap in memory.
+type RequestLimits struct {
+	// MaxBodyBytes caps request bodies on every route without an
+	// entry in EndpointBodyBytes.
+	MaxBodyBytes int64
+	// EndpointBodyBytes caps individual routes, keyed by the pattern
+	// they were registered with ("POST /tasks/send"). The JSON-RPC
+	// dispatcher is "POST /".
+	EndpointBodyBytes map[string]int64
+	// MaxMessageParts caps the parts in an inbound tasks/send message.
+	MaxMessageParts int
+	// MaxHistoryMessages caps a task's stored history. A send to a
+	// task already at the cap is refused; the caller starts a new task.
+	MaxHistoryMessages int
+	// MaxSSEEventBytes caps a single SSE event's data line. See
+	// WriteSSEEvent for what happens to an event over the cap.
+	MaxSSEEventBytes int
+	// MaxBatchRequests caps the requests in one JSON-RPC batch.
+	MaxBatchRequests int
+	// BatchConcurrency caps how many requests of one batch run at
+	// once.
+	BatchConcurrency int
+}
+
+// DefaultRequestLimits returns the limits installed when ServerConfig
+// carries none. The small-JSON control endpoints get 64 KiB; task
+// endpoints share the 2 MiB default that predates the limits block.
+func DefaultRequestLimits() *RequestLimits {
+	return &RequestLimits{
+		MaxBodyBytes: 2 << 20,
+		EndpointBodyBytes: map[string]int64{
+			"POST /tasks/{id}/decisions": 64 << 10,
+			"POST /mcp/consent":          64 << 10,
+			"POST /admin/logging":        64 << 10,
+			"POST /admin/model":          64 << 10,
+			"POST /notify":               64 << 10,
+			"POST /handoffs/{id}/resume": 64 << 10,
+			"POST /schedules/parse":      64 << 10,
+		},
+		MaxMessageParts:    64,
+		MaxHistoryMessages: 1000,
+		MaxSSEEventBytes:   4 << 20,
+		MaxBatchRequests:   100,
+		BatchConcurrency:   8,
+	}
+}
+
+// bodyLimit returns the body cap for the route registered as pattern.
+func (l *RequestLimits) bodyLimit(pattern string) int64 {
+	if n, ok := l.EndpointBodyBytes[pattern]; ok && n > 0 {
+		return n
+	}
+	return l.MaxBodyBytes
+}
+
+// LimitError reports an inbound request over one of the RequestLimits.
+// Limit names the knob in forge.yaml's server.limits block.
+type LimitError struct {
+	Limit string
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case "max_body_bytes":
+		return fmt.Sprintf("request body too large (limit %d bytes)", e.Max)
+	case "max_message_parts":
+		return fmt.Sprintf("message has too many parts (limit %d)", e.Max)
+	case "max_history_messages":
+		return fmt.Sprintf("task history is full (limit %d messages); start a new task", e.Max)
+	case "max_sse_event_bytes":
+		return fmt.Sprintf("event too large to stream (limit %d bytes); fetch it with tasks/get", e.Max)
+	case "max_batch_requests":
+		return fmt.Sprintf("batch has too many requests (limit %d)", e.Max)
+	}
+	return fmt.Sprintf("request exceeds %s (%d)", e.Limit, e.Max)
+}
+
+// data is the structured detail carried in the 413 body.
+func (e *LimitError) data() map[string]any {
+	return map[string]any{"limit": e.Limit, "max": e.Max}
+}
+
+// asLimitError converts a body-read error into a *LimitError. The
+// MaxBytesError is sometimes wrapped by json.Decoder beyond errors.As,
+// so the canonical message is matched too.
+func asLimitError(err error, max int64) (*LimitError, bool) {
+	var le *LimitError
+	if errors.As(err, &le) {
+		return le, true
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &LimitError{Limit: "max_body_bytes", Max: maxBytesErr.Limit}, true
+	}
+	if isMaxBytesError(err) {
+		return &LimitError{Limit: "max_body_bytes", Max: max}, true
+	}
+	return nil, false
+}
+
+// WriteLimitExceededOnError writes a structured 413 and returns true
+// when err is a *LimitError or came from reading a body over its cap.
+// REST handlers call it first on a decode or CheckTaskLimits error:
+//
+//	{"error": "request body too large (limit 2097152 bytes)", "limit": "max_body_bytes", "max": 2097152}
+func WriteLimitExceededOnError(w http.ResponseWriter, err error) bool {
+	le, ok := asLimitError(err, 0)
+	if !ok {
+		return false
+	}
+	body := le.data()
+	body["error"] = le.Error()
+	writeJSON(w, http.StatusRequestEntityTooLarge, body)
+	return true
+}
+
+// writeJSONRPCLimitError is the JSON-RPC form of the 413: the error
+// object carries the same limit/max detail in data.
+func writeJSONRPCLimitError(w http.ResponseWriter, id any, code int, le *LimitError) {
+	resp := a2a.NewErrorResponse(id, code, le.Error())
+	resp.Error.Data = le.data()
+	writeJSON(w, http.StatusRequestEntityTooLarge, resp)
+}
+
+// CheckTaskLimits checks an inbound tasks/send message against
+// MaxMessageParts and the target task's stored history against
+// MaxHistoryMessages. The JSON-RPC dispatcher runs it for tasks/send
+// and tasks/sendSubscribe; REST handlers call it after decoding.
+func (s *Server) CheckTaskLimits(params a2a.SendTaskParams) error {
+	return s.checkTaskLimits(params.ID, len(params.Message.Parts))
+}
+
+func (s *Server) checkTaskLimits(taskID string, parts int) error {
+	if max := s.limits.MaxMessageParts; max > 0 && parts > max {
+		return &LimitError{Limit: "max_message_parts", Max: int64(max)}
+	}
+	if max := s.limits.MaxHistoryMessages; max > 0 && taskID != "" {
+		if t := s.store.Get(taskID); t != nil && len(t.History) >= max {
+			return &LimitError{Limit: "max_history_messages", Max: int64(max)}
+		}
+	}
+	return nil
+}
+
+// checkSendParams is CheckTaskLimits for the dispatcher, which holds
+// the params undecoded: only the task ID and the part count are read.
+func (s *Server) checkSendParams(raw json.RawMessage) error {
+	var p struct {
+		ID      string `json:"id"`
+		Message struct {
+			Parts []json.RawMessage `json:"parts"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil // the handler reports malformed params
+	}
+	return s.checkTaskLimits(p.ID, len(p.Message.Parts))
+}
+
+// limitBody caps the body of a REST route and hands SSE writers the
+// event cap.
+func (s *Server) limitBody(pattern string, h http.HandlerFunc) http.HandlerFunc {
+	max := s.limits.bodyLimit(pattern)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if max > 0 && r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, max)
+		}
+		h(s.sseWriter(w), r)
+	}
+}
+
+// sseLimitWriter carries MaxSSEEventBytes to WriteSSEEvent, whose
+// signature predates the limit.
+type sseLimitWriter struct {
+	http.ResponseWriter
+	flusher http.Flusher
+	max     int
+}
+
+func (w *sseLimitWriter) Flush() { w.flusher.Flush() }
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *sseLimitWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// sseWriter wraps w when it can stream and an event cap is set.
+func (s *Server) sseWriter(w http.ResponseWriter) http.ResponseWriter {
+	f, ok := w.(http.Flusher)
+	if !ok || s.limits.MaxSSEEventBytes <= 0 {
+		return w
+	}
+	return &sseLimitWriter{ResponseWriter: w, flusher: f, max: s.limits.MaxSSEEventBytes}
+}
+
+// shrinkSSEEvent makes an over-cap event fit: a task is re-sent
+// without its history (clients hold the turns they sent and can fetch
+// the rest with tasks/get). Anything still over the cap is replaced by
+// an error event carrying the structured limit detail.
+func shrinkSSEEvent(event string, data any, max int) (string, []byte, error) {
+	if t, ok := data.(*a2a.Task); ok && len(t.History) > 0 {
+		slim := *t
+		slim.History = nil
+		if b, err := json.Marshal(&slim); err == nil && len(b) <= max {
+			return event, b, nil
+		}
+	}
+	le := &LimitError{Limit: "max_sse_event_bytes", Max: int64(max)}
+	resp := a2a.NewErrorResponse(nil, a2a.ErrCodeInternal, le.Error())
+	resp.Error.Data = map[string]any{"limit": le.Limit, "max": le.Max, "event": event}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return "", nil, err
+	}
+	return "error", b, le
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func TestLimitBody_PerEndpoint(t *testing.T) {
+	s := NewServer(ServerConfig{Limits: &RequestLimits{
+		MaxBodyBytes:      1 << 10,
+		EndpointBodyBytes: map[string]int64{"POST /big": 4 << 10},
+	}})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			if WriteLimitExceededOnError(w, err) {
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	body := `{"data":"` + strings.Repeat("x", 2<<10) + `"}`
+
+	for _, tc := range []struct {
+		pattern string
+		want    int
+	}{
+		{"POST /small", http.StatusRequestEntityTooLarge},
+		{"POST /big", http.StatusOK},
+	} {
+		rec := httptest.NewRecorder()
+		s.limitBody(tc.pattern, handler)(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		if rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.pattern, rec.Code, tc.want)
+		}
+		if tc.want == http.StatusRequestEntityTooLarge {
+			var got map[string]any
+			_ = json.Unmarshal(rec.Body.Bytes(), &got)
+			if got["limit"] != "max_body_bytes" || got["max"] != float64(1<<10) {
+				t.Errorf("413 body = %s", rec.Body.String())
+			}
+		}
+	}
+}
+
+func TestHandleJSONRPC_TaskLimits(t *testing.T) {
+	s := NewServer(ServerConfig{Limits: &RequestLimits{MaxBodyBytes: 1 << 20, MaxMessageParts: 2, MaxHistoryMessages: 2}})
+	s.RegisterHandler("tasks/send", func(_ context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+		return a2a.NewResponse(id, "ok")
+	})
+	s.store.Put(&a2a.Task{ID: "full", History: []a2a.Message{{}, {}}})
+
+	send := func(params string) (int, *a2a.JSONRPCError) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"tasks/send","params":` + params + `}`
+		rec := httptest.NewRecorder()
+		s.handleJSONRPC(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		var resp a2a.JSONRPCResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp.Error
+	}
+
+	if code, rpcErr := send(`{"id":"t1","message":{"role":"user","parts":[{"kind":"text","text":"a"}]}}`); code != http.StatusOK || rpcErr != nil {
+		t.Fatalf("within limits: %d %+v", code, rpcErr)
+	}
+	code, rpcErr := send(`{"id":"t1","message":{"role":"user","parts":[{},{},{}]}}`)
+	if code != http.StatusRequestEntityTooLarge || rpcErr == nil || rpcErr.Code != a2a.ErrCodeInvalidParams {
+		t.Fatalf("too many parts: %d %+v", code, rpcErr)
+	}
+	if data, _ := rpcErr.Data.(map[string]any); data["limit"] != "max_message_parts" {
+		t.Errorf("error data = %v", rpcErr.Data)
+	}
+	code, rpcErr = send(`{"id":"full","message":{"role":"user","parts":[{"kind":"text","text":"a"}]}}`)
+	if code != http.StatusRequestEntityTooLarge || !strings.Contains(rpcErr.Message, "history is full") {
+		t.Errorf("full history: %d %+v", code, rpcErr)
+	}
+}
+
+func TestWriteSSEEvent_Limit(t *testing.T) {
+	s := NewServer(ServerConfig{Limits: &RequestLimits{MaxSSEEventBytes: 512}})
+	rec := httptest.NewRecorder()
+	w := s.sseWriter(rec)
+	flusher := w.(http.Flusher)
+
+	task := &a2a.Task{ID: "t1", History: []a2a.Message{{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(strings.Repeat("h", 1024))}}}}
+	if err := WriteSSEEvent(w, flusher, "status", task); err != nil {
+		t.Fatalf("task without history fits; got %v", err)
+	}
+	out, _ := io.ReadAll(rec.Body)
+	if !strings.HasPrefix(string(out), "event: status\n") || strings.Contains(string(out), "hhhh") {
+		t.Errorf("expected a status event without history, got %q", out)
+	}
+
+	err := WriteSSEEvent(w, flusher, "result", map[string]string{"text": strings.Repeat("r", 1024)})
+	if le, ok := err.(*LimitError); !ok || le.Limit != "max_sse_event_bytes" {
+		t.Fatalf("expected *LimitError, got %v", err)
+	}
+	out, _ = io.ReadAll(rec.Body)
+	if !strings.HasPrefix(string(out), "event: error\n") || !strings.Contains(string(out), `"event":"result"`) {
+		t.Errorf("expected an error event naming the dropped event, got %q", out)
+	}
+
+	// A writer the server did not hand out is never capped.
+	plain := httptest.NewRecorder()
+	if err := WriteSSEEvent(plain, plain, "result", map[string]string{"text": strings.Repeat("r", 1024)}); err != nil {
+		t.Errorf("uncapped writer: %v", err)
+	}
+}
+
+func TestIsTasksCancel_PreservesBody(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"tasks/cancel","params":{"pad":"` + strings.Repeat("p", 2*jsonRPCPeekCap) + `"}}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if isTasksCancel(r) {
+		t.Error("a body cut at the peek cap is not valid JSON and should classify as a write")
+	}
+	got, _ := io.ReadAll(r.Body)
+	if string(got) != body {
+		t.Errorf("body not restored: %d of %d bytes", len(got), len(body))
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/initializ/forge/forge-core/security"
+)
+
+// listenFDsStart is the first file descriptor systemd passes to a
+// socket-activated service (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// ActivationListener returns the socket systemd passed to this process
+// under socket activation (LISTEN_PID / LISTEN_FDS), or nil when the
+// process was not socket-activated. The activation variables are
+// cleared so subprocesses do not mistake the socket for their own.
+// Exactly one socket is supported: the A2A server has one listener.
+func ActivationListener() (net.Listener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if pid == "" || fds == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("socket activation: invalid LISTEN_FDS %q", fds)
+	}
+	if n > 1 {
+		return nil, fmt.Errorf("socket activation: got %d sockets, want 1", n)
+	}
+	f := os.NewFile(uintptr(listenFDsStart), "LISTEN_FD_3")
+	defer f.Close() //nolint:errcheck
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("socket activation: %w", err)
+	}
+	return ln, nil
+}
+
+// IsLoopbackHost reports whether host is a loopback address or
+// "localhost". The empty host binds every interface, so it is not.
+func IsLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// listen opens the server's listener: the one passed in ServerConfig,
+// the Unix socket, or the TCP port — auto-incremented up to 10 times on
+// conflict — in that order of preference.
+func (s *Server) listen() (net.Listener, error) {
+	if s.listener != nil {
+		s.addr = s.listener.Addr().String()
+		if s.listener.Addr().Network() == "unix" {
+			s.addr = "unix:" + s.addr
+		}
+		return s.listener, nil
+	}
+	if s.socket != "" {
+		ln, err := security.ListenUnix(s.socket)
+		if err != nil {
+			return nil, fmt.Errorf("listen on %s: %w", s.socket, err)
+		}
+		s.addr = "unix:" + s.socket
+		return ln, nil
+	}
+
+	var ln net.Listener
+	var listenErr error
+	actualPort := s.port
+	for range 10 {
+		addr := fmt.Sprintf("%s:%d", s.host, actualPort)
+		ln, listenErr = net.Listen("tcp", addr)
+		if listenErr == nil {
+			break
+		}
+		if !isAddrInUse(listenErr) {
+			return nil, fmt.Errorf("listen on %s: %w", addr, listenErr)
+		}
+		actualPort++
+	}
+	if listenErr != nil {
+		return nil, fmt.Errorf("all ports %d-%d in use: %w", s.port, actualPort, listenErr)
+	}
+	s.port = actualPort // update so banner/info reflect actual port
+	s.addr = fmt.Sprintf("%s:%d", s.host, actualPort)
+	s.srv.Addr = s.addr
+	return ln, nil
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func TestIsLoopbackHost(t *testing.T) {
+	for host, want := range map[string]bool{
+		"127.0.0.1": true,
+		"127.0.0.2": true,
+		"::1":       true,
+		"localhost": true,
+		"":          false,
+		"0.0.0.0":   false,
+		"::":        false,
+		"10.0.0.5":  false,
+		"agent.lan": false,
+	} {
+		if got := IsLoopbackHost(host); got != want {
+			t.Errorf("IsLoopbackHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestActivationListener_NotActivated(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "1")
+	t.Setenv("LISTEN_PID", "1") // another process's sockets
+	ln, err := ActivationListener()
+	if ln != nil || err != nil {
+		t.Fatalf("ActivationListener = %v, %v; want nil, nil", ln, err)
+	}
+	if os.Getenv("LISTEN_FDS") != "1" {
+		t.Error("variables meant for another process were cleared")
+	}
+}
+
+// getHealthz polls /healthz through dial until the server answers.
+func getHealthz(t *testing.T, dial func(ctx context.Context, network, addr string) (net.Conn, error)) string {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{DialContext: dial}}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := client.Get("http://agent/healthz")
+		if err == nil {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return string(body)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET /healthz: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestServer_UnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "fsrv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+	path := filepath.Join(dir, "a2a.sock")
+
+	srv := NewServer(ServerConfig{Socket: path, AgentCard: &a2a.AgentCard{Name: "test"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Start(ctx) }()
+
+	body := getHealthz(t, func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	})
+	if body != `{"status":"ok"}` {
+		t.Errorf("healthz = %q", body)
+	}
+	if srv.Addr() != "unix:"+path {
+		t.Errorf("Addr = %q, want unix:%s", srv.Addr(), path)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o660 {
+		t.Errorf("socket stat = %v, %v; want mode 660", fi, err)
+	}
+}
+
+func TestServer_Listener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	// Port and Host are ignored once a listener is passed in.
+	srv := NewServer(ServerConfig{Listener: ln, Port: 1, Host: "0.0.0.0", AgentCard: &a2a.AgentCard{Name: "test"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Start(ctx) }()
+
+	getHealthz(t, func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", addr)
+	})
+	if srv.Addr() != addr {
+		t.Errorf("Addr = %q, want %q", srv.Addr(), addr)
+	}
+}
@@ -3,7 +3,7 @@ package templates
 
 import "embed"
 
-//go:embed Dockerfile.tmpl deployment.yaml.tmpl service.yaml.tmpl network-policy.yaml.tmpl secrets.yaml.tmpl docker-compose.yaml.tmpl init wrapper
+//go:embed Dockerfile.tmpl deployment.yaml.tmpl service.yaml.tmpl network-policy.yaml.tmpl secrets.yaml.tmpl docker-compose.yaml.tmpl init wrapper helm helm/templates/_helpers.tpl
 var FS embed.FS
 
 // GetInitTemplate reads a template file from the init directory.
@@ -0,0 +1,15 @@
+{{/*
+Labels on every resource. "app" is the selector the generated
+NetworkPolicy matches, so it is the chart name, not the release name.
+*/}}
+{{- define "agent.labels" -}}
+app: {{ .Chart.Name }}
+app.kubernetes.io/name: {{ .Chart.Name }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end }}
+
+{{- define "agent.secretName" -}}
+{{- .Values.secret.existingSecret | default (printf "%s-secrets" .Chart.Name) }}
+{{- end }}
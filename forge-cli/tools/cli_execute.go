@@ -1,9 +1,9 @@
 package tools
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -22,6 +22,17 @@ type CLIExecuteConfig struct {
 	TimeoutSeconds  int    // default 120
 	MaxOutputBytes  int    // default 1MB
 	WorkDir         string // confine path arguments to this directory
+
+	// OutputTailBytes is how much of MaxOutputBytes is kept from the end
+	// of a stream that outgrows it; the rest is kept from the start.
+	// Default 0: only the head is kept.
+	OutputTailBytes int
+	// SpillMaxBytes caps the file a truncated stream is written to in
+	// full (default 16MB). Negative disables spilling.
+	SpillMaxBytes int
+	// StreamOutput sends output chunks to the client as "tool_stream"
+	// progress events while the command runs.
+	StreamOutput bool
 }
 
 // CLIExecuteTool is a Category-A builtin tool that executes only pre-approved
@@ -56,6 +67,10 @@ type cliExecuteResult struct {
 	Stderr    string `json:"stderr"`
 	ExitCode  int    `json:"exit_code"`
 	Truncated bool   `json:"truncated"`
+	// StdoutFile and StderrFile hold the full stream when it was
+	// truncated, for the model to read or pass to another command.
+	StdoutFile string `json:"stdout_file,omitempty"`
+	StderrFile string `json:"stderr_file,omitempty"`
 }
 
 // NewCLIExecuteTool creates a CLIExecuteTool from the given config.
@@ -67,6 +82,9 @@ func NewCLIExecuteTool(config CLIExecuteConfig) *CLIExecuteTool {
 	if config.MaxOutputBytes <= 0 {
 		config.MaxOutputBytes = 1048576 // 1MB
 	}
+	if config.SpillMaxBytes == 0 {
+		config.SpillMaxBytes = defaultSpillMaxBytes
+	}
 
 	// Resolve workDir and homeDir for path confinement.
 	workDir := config.WorkDir
@@ -75,14 +93,14 @@ func NewCLIExecuteTool(config CLIExecuteConfig) *CLIExecuteTool {
 			workDir = abs
 		}
 	}
-	homeDir := os.Getenv("HOME")
+	homeDir := userHome()
 
 	// Filter denied shells from the allowed list before constructing the
 	// tool. Execute() blocks them at runtime, but including them in the
 	// schema/description causes the LLM to hallucinate they are available.
 	filtered := make([]string, 0, len(config.AllowedBinaries))
 	for _, bin := range config.AllowedBinaries {
-		if !deniedShells[bin] {
+		if !isDeniedShell(bin) {
 			filtered = append(filtered, bin)
 		}
 	}
@@ -97,12 +115,12 @@ func NewCLIExecuteTool(config CLIExecuteConfig) *CLIExecuteTool {
 	}
 
 	for _, bin := range config.AllowedBinaries {
-		t.allowedSet[bin] = true
+		t.allowedSet[canonicalBinary(bin)] = true
 		absPath, err := exec.LookPath(bin)
 		if err != nil {
 			t.missing = append(t.missing, bin)
 		} else {
-			t.binaryPaths[bin] = absPath
+			t.binaryPaths[canonicalBinary(bin)] = absPath
 			t.available = append(t.available, bin)
 		}
 	}
@@ -177,17 +195,17 @@ func (t *CLIExecuteTool) Execute(ctx context.Context, args json.RawMessage) (str
 
 	// Security check 1a: Block shell interpreters — these defeat the no-shell
 	// exec.Command design and bypass all path argument validation.
-	if deniedShells[input.Binary] {
+	if isDeniedShell(input.Binary) {
 		return "", fmt.Errorf("cli_execute: binary %q is a shell interpreter and cannot be used", input.Binary)
 	}
 
 	// Security check 1b: Binary allowlist
-	if !t.allowedSet[input.Binary] {
+	if !t.allowedSet[canonicalBinary(input.Binary)] {
 		return "", fmt.Errorf("cli_execute: binary %q is not in the allowed list", input.Binary)
 	}
 
 	// Security check 2: Binary availability
-	absPath, ok := t.binaryPaths[input.Binary]
+	absPath, ok := t.binaryPaths[canonicalBinary(input.Binary)]
 	if !ok {
 		return "", fmt.Errorf("cli_execute: binary %q was not found on this system", input.Binary)
 	}
@@ -200,10 +218,12 @@ func (t *CLIExecuteTool) Execute(ctx context.Context, args json.RawMessage) (str
 	}
 
 	// Security check 3b: Path confinement — block path args that escape workDir
-	// into $HOME (e.g., ~/Library/Keychains/, ../../../.ssh/id_rsa)
-	if t.workDir != "" {
+	// into $HOME (e.g., ~/Library/Keychains/, ../../../.ssh/id_rsa). A
+	// sandboxed call may not name any path outside its task sandbox.
+	sandbox := coretools.SandboxRootFromContext(ctx)
+	if t.workDir != "" || sandbox != "" {
 		for i, arg := range input.Args {
-			if err := t.validatePathArg(arg); err != nil {
+			if err := t.validatePathArg(arg, sandbox); err != nil {
 				return "", fmt.Errorf("cli_execute: argument %d: %w", i, err)
 			}
 		}
@@ -218,7 +238,9 @@ func (t *CLIExecuteTool) Execute(ctx context.Context, args json.RawMessage) (str
 	cmd := exec.CommandContext(cmdCtx, absPath, input.Args...)
 
 	// Defense-in-depth: set working directory so relative paths resolve within workDir
-	if t.workDir != "" {
+	if sandbox != "" {
+		cmd.Dir = sandbox
+	} else if t.workDir != "" {
 		cmd.Dir = t.workDir
 	}
 
@@ -261,15 +283,29 @@ func (t *CLIExecuteTool) Execute(ctx context.Context, args json.RawMessage) (str
 		cmd.Stdin = strings.NewReader(input.Stdin)
 	}
 
-	// Security check 7: Output limit
-	stdoutWriter := newLimitedWriter(t.config.MaxOutputBytes)
-	stderrWriter := newLimitedWriter(t.config.MaxOutputBytes)
+	// Security check 7: Output limit. Past the limit a stream keeps its
+	// head (and tail), and spills in full to the files directory.
+	stdoutWriter := newOutputCapture(t.config.MaxOutputBytes, t.config.OutputTailBytes)
+	stderrWriter := newOutputCapture(t.config.MaxOutputBytes, t.config.OutputTailBytes)
+	if t.config.SpillMaxBytes > 0 {
+		stdoutWriter.spillPath = spillFilePath(ctx, input.Binary, "stdout")
+		stderrWriter.spillPath = spillFilePath(ctx, input.Binary, "stderr")
+		stdoutWriter.spillMax = int64(t.config.SpillMaxBytes)
+		stderrWriter.spillMax = int64(t.config.SpillMaxBytes)
+	}
+	if t.config.StreamOutput {
+		streamer := newOutputStreamer(ctx, t.Name())
+		stdoutWriter.stream = streamer
+		stderrWriter.stream = streamer
+	}
 	cmd.Stdout = stdoutWriter
 	cmd.Stderr = stderrWriter
 
 	// Run the command
 	exitCode := 0
 	err := cmd.Run()
+	stdoutWriter.stream.flush()
+	stdoutFile, stderrFile := stdoutWriter.close(), stderrWriter.close()
 	if err != nil {
 		if cmdCtx.Err() == context.DeadlineExceeded {
 			return "", fmt.Errorf("cli_execute: command timed out after %ds", t.config.TimeoutSeconds)
@@ -284,10 +320,12 @@ func (t *CLIExecuteTool) Execute(ctx context.Context, args json.RawMessage) (str
 
 	// Build result
 	result := cliExecuteResult{
-		Stdout:    stdoutWriter.String(),
-		Stderr:    stderrWriter.String(),
-		ExitCode:  exitCode,
-		Truncated: stdoutWriter.overflow || stderrWriter.overflow,
+		Stdout:     stdoutWriter.String(),
+		Stderr:     stderrWriter.String(),
+		ExitCode:   exitCode,
+		Truncated:  stdoutWriter.truncated() || stderrWriter.truncated(),
+		StdoutFile: stdoutFile,
+		StderrFile: stderrFile,
 	}
 
 	resultJSON, err := json.Marshal(result)
@@ -330,14 +368,17 @@ func stripEnvKeys(env []string, override map[string]string) []string {
 
 // buildEnv constructs an isolated environment with only PATH, HOME, LANG
 // and explicitly configured passthrough variables. When workDir is set,
-// HOME is overridden to workDir so subprocess ~ expansion stays confined.
+// HOME is overridden to workDir so subprocess ~ expansion stays confined;
+// a sandboxed call gets its task sandbox as HOME instead.
 // The binary parameter scopes credential env vars to only the binaries that need them.
 // ctx carries the task/invocation identity that is stamped into the egress
 // proxy URL so proxied subprocess egress can be attributed in the audit log (#338).
 func (t *CLIExecuteTool) buildEnv(ctx context.Context, binary string) []string {
-	realHome := os.Getenv("HOME")
+	realHome := userHome()
 	homeVal := realHome
-	if t.workDir != "" {
+	if sandbox := coretools.SandboxRootFromContext(ctx); sandbox != "" {
+		homeVal = sandbox
+	} else if t.workDir != "" {
 		homeVal = t.workDir
 	}
 	env := []string{
@@ -345,9 +386,10 @@ func (t *CLIExecuteTool) buildEnv(ctx context.Context, binary string) []string {
 		"HOME=" + homeVal,
 		"LANG=" + os.Getenv("LANG"),
 	}
+	env = append(env, platformEnv(homeVal)...)
 
 	// Per-binary credential scoping: only the binary that needs credentials gets them.
-	if t.workDir != "" && realHome != "" {
+	if homeVal != realHome && realHome != "" {
 		switch binary {
 		case "gh":
 			// Preserve GH_CONFIG_DIR so gh CLI finds auth at real ~/.config/gh.
@@ -380,12 +422,7 @@ func (t *CLIExecuteTool) buildEnv(ctx context.Context, binary string) []string {
 		// the egress proxy can attribute this subprocess's egress to its task
 		// in the audit log (same mechanism as SkillCommandExecutor).
 		proxyURL := proxyURLWithIdentity(ctx, t.proxyURL)
-		env = append(env,
-			"HTTP_PROXY="+proxyURL,
-			"HTTPS_PROXY="+proxyURL,
-			"http_proxy="+proxyURL,
-			"https_proxy="+proxyURL,
-		)
+		env = appendProxyEnv(env, proxyURL, "HTTP_PROXY", "HTTPS_PROXY")
 	}
 
 	// kubectl/helm manage their own TLS (mTLS client certs, bearer tokens).
@@ -394,10 +431,7 @@ func (t *CLIExecuteTool) buildEnv(ctx context.Context, binary string) []string {
 	if t.proxyURL != "" && (binary == "kubectl" || binary == "helm") {
 		noProxy := buildK8sNoProxy(env)
 		if noProxy != "" {
-			env = append(env,
-				"NO_PROXY="+noProxy,
-				"no_proxy="+noProxy,
-			)
+			env = appendProxyEnv(env, noProxy, "NO_PROXY")
 		}
 	}
 
@@ -471,6 +505,14 @@ func extractK8sAPIHost(kubeconfigPath string) string {
 var deniedShells = map[string]bool{
 	"bash": true, "sh": true, "zsh": true, "dash": true,
 	"ksh": true, "csh": true, "tcsh": true, "fish": true,
+	"cmd": true, "powershell": true, "pwsh": true,
+}
+
+// isDeniedShell reports whether bin names a shell interpreter, by path
+// or bare name, with or without .exe.
+func isDeniedShell(bin string) bool {
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(bin)), ".exe")
+	return deniedShells[name]
 }
 
 // validateArg rejects arguments containing shell injection patterns.
@@ -496,17 +538,25 @@ func validateArg(arg string) error {
 // validatePathArg checks whether an argument looks like a filesystem path and,
 // if so, ensures it doesn't resolve to a location inside $HOME but outside
 // workDir. System paths (outside $HOME) and non-path arguments pass through.
-func (t *CLIExecuteTool) validatePathArg(arg string) error {
+// With a sandbox, every path must stay inside it, symlinks followed, and ~
+// is the sandbox (it is the subprocess HOME).
+func (t *CLIExecuteTool) validatePathArg(arg, sandbox string) error {
 	if !looksLikePath(arg) {
 		return nil
 	}
+	if sandbox != "" {
+		_, err := coretools.ConfinePath(sandbox, resolveArgPath(arg, sandbox, sandbox), "task sandbox")
+		var pv *coretools.PathViolation
+		if errors.As(err, &pv) {
+			pv.Path = arg
+		}
+		return err
+	}
 	resolved := resolveArgPath(arg, t.workDir, t.homeDir)
 
 	// If the resolved path is inside $HOME (or is $HOME itself) but outside workDir → blocked.
-	inHome := resolved == t.homeDir || strings.HasPrefix(resolved, t.homeDir+"/")
-	inWorkDir := resolved == t.workDir || strings.HasPrefix(resolved, t.workDir+"/")
-	if t.homeDir != "" && inHome && !inWorkDir {
-		return fmt.Errorf("path %q resolves outside the agent working directory", arg)
+	if t.homeDir != "" && pathWithin(resolved, t.homeDir) && !pathWithin(resolved, t.workDir) {
+		return &coretools.PathViolation{Path: arg, Root: t.workDir, Scope: "agent working directory"}
 	}
 	return nil
 }
@@ -515,17 +565,25 @@ func (t *CLIExecuteTool) validatePathArg(arg string) error {
 // Only bare path prefixes are matched; flag arguments (--foo=/bar) are not
 // detected so that flags like --kubeconfig=~/.kube/config pass through.
 func looksLikePath(arg string) bool {
-	return strings.HasPrefix(arg, "/") ||
+	return looksLikePlatformPath(arg) ||
+		strings.HasPrefix(arg, "/") ||
 		strings.HasPrefix(arg, "~/") ||
 		strings.HasPrefix(arg, "./") ||
 		strings.HasPrefix(arg, "../") ||
 		arg == "~" || arg == "." || arg == ".."
 }
 
+// pathWithin reports whether p is dir or lies under it. filepath.Rel
+// compares case-insensitively on Windows, where paths are.
+func pathWithin(p, dir string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // resolveArgPath expands ~ and resolves relative paths against workDir,
 // then cleans the result to eliminate .. components.
 func resolveArgPath(arg, workDir, homeDir string) string {
-	if strings.HasPrefix(arg, "~/") {
+	if strings.HasPrefix(arg, "~/") || strings.HasPrefix(arg, "~"+string(filepath.Separator)) {
 		arg = filepath.Join(homeDir, arg[2:])
 	} else if arg == "~" {
 		arg = homeDir
@@ -535,6 +593,13 @@ func resolveArgPath(arg, workDir, homeDir string) string {
 	return filepath.Clean(arg)
 }
 
+// userHome returns the user's home directory: $HOME on Unix, %USERPROFILE%
+// on Windows, or "" when unset.
+func userHome() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
+
 // ParseCLIExecuteConfig extracts typed config from the map[string]any that
 // YAML produces. Handles both int and float64 for numeric fields.
 func ParseCLIExecuteConfig(raw map[string]any) CLIExecuteConfig {
@@ -568,6 +633,18 @@ func ParseCLIExecuteConfig(raw map[string]any) CLIExecuteConfig {
 		cfg.MaxOutputBytes = toInt(maxOutput)
 	}
 
+	if tail, ok := raw["output_tail_bytes"]; ok {
+		cfg.OutputTailBytes = toInt(tail)
+	}
+
+	if spill, ok := raw["spill_max_bytes"]; ok {
+		cfg.SpillMaxBytes = toInt(spill)
+	}
+
+	if stream, ok := raw["stream_output"].(bool); ok {
+		cfg.StreamOutput = stream
+	}
+
 	return cfg
 }
 
@@ -584,34 +661,3 @@ func toInt(v any) int {
 		return 0
 	}
 }
-
-// limitedWriter wraps a bytes.Buffer and silently drops bytes after the limit.
-// It always returns len(p) to avoid broken pipe errors from subprocesses.
-type limitedWriter struct {
-	buf      bytes.Buffer
-	limit    int
-	overflow bool
-}
-
-func newLimitedWriter(limit int) *limitedWriter {
-	return &limitedWriter{limit: limit}
-}
-
-func (w *limitedWriter) Write(p []byte) (int, error) {
-	remaining := w.limit - w.buf.Len()
-	if remaining <= 0 {
-		w.overflow = true
-		return len(p), nil
-	}
-	if len(p) > remaining {
-		w.buf.Write(p[:remaining])
-		w.overflow = true
-		return len(p), nil
-	}
-	w.buf.Write(p)
-	return len(p), nil
-}
-
-func (w *limitedWriter) String() string {
-	return w.buf.String()
-}
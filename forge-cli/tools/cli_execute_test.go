@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -11,6 +12,7 @@ import (
 	"testing"
 
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	coretools "github.com/initializ/forge/forge-core/tools"
 )
 
 func TestCLIExecute_Name(t *testing.T) {
@@ -474,7 +476,7 @@ func TestValidatePathArg_BlocksHomeTraversal(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tool.validatePathArg(tt.arg)
+			err := tool.validatePathArg(tt.arg, "")
 			if tt.wantErr && err == nil {
 				t.Errorf("validatePathArg(%q) = nil, want error", tt.arg)
 			}
@@ -520,7 +522,7 @@ func TestCLIExecute_PathTraversalBlocked(t *testing.T) {
 }
 
 func TestCLIExecute_ShellInterpreterBlocked(t *testing.T) {
-	shells := []string{"bash", "sh", "zsh", "dash", "ksh", "csh", "tcsh", "fish"}
+	shells := []string{"bash", "sh", "zsh", "dash", "ksh", "csh", "tcsh", "fish", "cmd", "powershell", "pwsh", "PowerShell.exe"}
 	for _, shell := range shells {
 		t.Run(shell, func(t *testing.T) {
 			tool := NewCLIExecuteTool(CLIExecuteConfig{
@@ -576,9 +578,58 @@ func TestCLIExecute_HomeOverriddenToWorkDir(t *testing.T) {
 	}
 }
 
+func TestCLIExecute_Sandboxed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses Unix binaries")
+	}
+
+	workDir := t.TempDir()
+	sandbox := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sandbox, "in.txt"), []byte("scratch"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(workDir, filepath.Join(sandbox, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewCLIExecuteTool(CLIExecuteConfig{
+		AllowedBinaries: []string{"cat", "pwd"},
+		WorkDir:         workDir,
+	})
+	ctx := coretools.WithSandboxRoot(context.Background(), sandbox)
+
+	run := func(binary string, args ...string) (cliExecuteResult, error) {
+		raw, _ := json.Marshal(cliExecuteArgs{Binary: binary, Args: args})
+		out, err := tool.Execute(ctx, raw)
+		var res cliExecuteResult
+		if err == nil {
+			_ = json.Unmarshal([]byte(out), &res)
+		}
+		return res, err
+	}
+
+	res, err := run("pwd")
+	if err != nil {
+		t.Fatalf("pwd: %v", err)
+	}
+	if real, _ := filepath.EvalSymlinks(sandbox); strings.TrimSpace(res.Stdout) != real {
+		t.Errorf("pwd = %q, want the sandbox %q", res.Stdout, real)
+	}
+	if res, err := run("cat", "./in.txt"); err != nil || res.Stdout != "scratch" {
+		t.Errorf("cat ./in.txt = %q, %v", res.Stdout, err)
+	}
+
+	for _, arg := range []string{"/etc/hosts", "../x", "./escape/secret", workDir} {
+		_, err := run("cat", arg)
+		var pv *coretools.PathViolation
+		if !errors.As(err, &pv) || pv.Path != arg || pv.Scope != "task sandbox" {
+			t.Errorf("cat %s: err = %v, want a task sandbox PathViolation", arg, err)
+		}
+	}
+}
+
 func TestBuildEnv_GHConfigDirScopedToGh(t *testing.T) {
 	tmpDir := t.TempDir()
-	t.Setenv("HOME", "/Users/testuser")
+	setHome(t, "/Users/testuser")
 
 	tool := NewCLIExecuteTool(CLIExecuteConfig{
 		AllowedBinaries: []string{"env", "gh", "curl"},
@@ -610,7 +661,7 @@ func TestBuildEnv_GHConfigDirScopedToGh(t *testing.T) {
 func TestBuildEnv_KubeconfigScopedToKubectl(t *testing.T) {
 	tmpDir := t.TempDir()
 	realHome := t.TempDir()
-	t.Setenv("HOME", realHome)
+	setHome(t, realHome)
 
 	// Create a fake kubeconfig
 	kubeDir := filepath.Join(realHome, ".kube")
@@ -654,7 +705,7 @@ func TestBuildEnv_KubeconfigScopedToKubectl(t *testing.T) {
 func TestBuildEnv_KubectlNoProxy(t *testing.T) {
 	tmpDir := t.TempDir()
 	realHome := t.TempDir()
-	t.Setenv("HOME", realHome)
+	setHome(t, realHome)
 
 	// Create kubeconfig with a server address
 	kubeDir := filepath.Join(realHome, ".kube")
@@ -730,3 +781,11 @@ func TestBuildEnv_ProxyIdentityStamped(t *testing.T) {
 		t.Errorf("expected identity-stamped %q in cli_execute env; got:\n%s", want, strings.Join(env, "\n"))
 	}
 }
+
+// setHome points the user's home directory at dir for the test: $HOME on
+// Unix, %USERPROFILE% on Windows.
+func setHome(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("HOME", dir)
+	t.Setenv("USERPROFILE", dir)
+}
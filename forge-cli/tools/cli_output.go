@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// Output capture for cli_execute: what the model sees is capped at
+// MaxOutputBytes per stream (head, plus an optional tail), the full stream
+// can spill to a file under the agent files directory, and chunks can be
+// streamed to the client as progress events while the command runs.
+
+const (
+	// defaultSpillMaxBytes caps a spill file when spill_max_bytes is unset.
+	defaultSpillMaxBytes = 16 << 20 // 16MB
+
+	// streamInterval is the minimum gap between streamed chunks, and
+	// streamChunkBytes the most one chunk carries. Output beyond that
+	// between two chunks is not streamed (it is still captured).
+	streamInterval   = 250 * time.Millisecond
+	streamChunkBytes = 4096
+)
+
+// spillSeq keeps spill file names unique within a process.
+var spillSeq atomic.Int64
+
+// outputCapture records one stream of a command's output. It keeps the
+// first head bytes and the last tail bytes, counts everything, and once the
+// stream outgrows head+tail writes the whole stream (up to spillMax bytes)
+// to spillPath. It always returns len(p) so the subprocess never sees a
+// broken pipe.
+type outputCapture struct {
+	head      bytes.Buffer
+	headLimit int
+	tail      []byte
+	tailLimit int
+	total     int64
+
+	spillPath string // "" disables spilling
+	spillMax  int64
+	spill     *os.File
+	spilled   int64
+	spillErr  error
+
+	stream *outputStreamer // nil when not streaming
+}
+
+// newOutputCapture returns a capture keeping maxBytes in total, tailBytes
+// of them from the end of the stream.
+func newOutputCapture(maxBytes, tailBytes int) *outputCapture {
+	if tailBytes < 0 || tailBytes >= maxBytes {
+		tailBytes = 0
+	}
+	return &outputCapture{headLimit: maxBytes - tailBytes, tailLimit: tailBytes}
+}
+
+func (c *outputCapture) Write(p []byte) (int, error) {
+	n := len(p)
+	if c.stream != nil {
+		c.stream.write(p)
+	}
+	c.total += int64(n)
+
+	if room := c.headLimit - c.head.Len(); room > 0 {
+		if len(p) <= room {
+			c.head.Write(p)
+			return n, nil
+		}
+		c.head.Write(p[:room])
+		c.startSpill() // copies the head, p[:room] included
+		p = p[room:]
+	} else if c.spill == nil && c.spillErr == nil {
+		c.startSpill()
+	}
+	c.writeSpill(p)
+
+	if c.tailLimit > 0 {
+		c.tail = append(c.tail, p...)
+		// Trim lazily so steady output doesn't copy on every write.
+		if len(c.tail) > 2*c.tailLimit {
+			c.tail = append(c.tail[:0], c.tail[len(c.tail)-c.tailLimit:]...)
+		}
+	}
+	return n, nil
+}
+
+// startSpill opens the spill file and copies the head into it, so the file
+// holds the stream from its first byte.
+func (c *outputCapture) startSpill() {
+	if c.spillPath == "" || c.spill != nil || c.spillErr != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.spillPath), 0o755); err != nil {
+		c.spillErr = err
+		return
+	}
+	f, err := os.OpenFile(c.spillPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		c.spillErr = err
+		return
+	}
+	c.spill = f
+	c.writeSpill(c.head.Bytes())
+}
+
+func (c *outputCapture) writeSpill(p []byte) {
+	if c.spill == nil || len(p) == 0 {
+		return
+	}
+	if room := c.spillMax - c.spilled; int64(len(p)) > room {
+		p = p[:room]
+	}
+	if len(p) == 0 {
+		return
+	}
+	n, err := c.spill.Write(p)
+	c.spilled += int64(n)
+	if err != nil {
+		c.spillErr = err
+	}
+}
+
+// close finishes the spill file. It returns the file's path, or "" when
+// the output was not truncated, nothing was spilled, or the file could not
+// be written (the truncated output is still returned; only the reference
+// is lost).
+func (c *outputCapture) close() string {
+	if c.spill == nil {
+		return ""
+	}
+	err := c.spill.Close()
+	c.spill = nil
+	if err != nil || c.spillErr != nil || !c.truncated() {
+		_ = os.Remove(c.spillPath)
+		return ""
+	}
+	return c.spillPath
+}
+
+// truncated reports whether the stream outgrew what String keeps.
+func (c *outputCapture) truncated() bool {
+	return c.total > int64(c.headLimit+c.tailLimit)
+}
+
+// String returns the retained output: the head, and when the stream was
+// cut, a marker naming the omitted byte count followed by the tail.
+func (c *outputCapture) String() string {
+	if !c.truncated() {
+		return c.head.String() + string(c.tail)
+	}
+	tail := c.tail
+	if len(tail) > c.tailLimit {
+		tail = tail[len(tail)-c.tailLimit:]
+	}
+	omitted := c.total - int64(c.head.Len()) - int64(len(tail))
+	var sb strings.Builder
+	sb.Grow(c.head.Len() + len(tail) + 64)
+	sb.Write(c.head.Bytes())
+	fmt.Fprintf(&sb, "\n[... %d bytes omitted ...]\n", omitted)
+	sb.Write(tail)
+	return sb.String()
+}
+
+// spillFilePath returns where a stream of a cli_execute run spills: the
+// agent files directory from ctx, or $TMPDIR/forge-files outside the full
+// runtime (the same fallback file_create uses).
+func spillFilePath(ctx context.Context, binary, stream string) string {
+	dir := coreruntime.FilesDirFromContext(ctx)
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "forge-files")
+	}
+	name := fmt.Sprintf("cli-%s-%d-%d.%s.log", filepath.Base(binary), time.Now().Unix(), spillSeq.Add(1), stream)
+	return filepath.Join(dir, name)
+}
+
+// outputStreamer forwards a command's output to the client as progress
+// events while it runs. stdout and stderr share one streamer, and os/exec
+// copies them on separate goroutines, so writes are serialized here.
+// Chunks are cut at line boundaries and sent at most every streamInterval.
+type outputStreamer struct {
+	mu      sync.Mutex
+	emit    coreruntime.ProgressEmitter
+	tool    string
+	pending []byte
+	dropped int
+	last    time.Time
+}
+
+// newOutputStreamer returns a streamer for ctx's progress emitter, or nil
+// when the request has no client to stream to.
+func newOutputStreamer(ctx context.Context, tool string) *outputStreamer {
+	emit := coreruntime.ProgressEmitterFromContext(ctx)
+	if emit == nil {
+		return nil
+	}
+	return &outputStreamer{emit: emit, tool: tool, last: time.Now()}
+}
+
+func (s *outputStreamer) write(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room := streamChunkBytes - len(s.pending)
+	if room > len(p) {
+		room = len(p)
+	}
+	if room > 0 {
+		s.pending = append(s.pending, p[:room]...)
+	}
+	s.dropped += len(p) - room
+	if time.Since(s.last) >= streamInterval {
+		s.flushLocked(false)
+	}
+}
+
+// flush sends whatever is pending. Call it once the command has exited.
+func (s *outputStreamer) flush() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushLocked(true)
+}
+
+// flushLocked sends the pending output up to its last newline; a full
+// buffer or a final flush sends it all. Callers hold s.mu.
+func (s *outputStreamer) flushLocked(final bool) {
+	cut := len(s.pending)
+	if !final && cut < streamChunkBytes {
+		i := bytes.LastIndexByte(s.pending, '\n')
+		if i < 0 {
+			return
+		}
+		cut = i + 1
+	}
+	if cut == 0 && s.dropped == 0 {
+		return
+	}
+	msg := string(s.pending[:cut])
+	if s.dropped > 0 {
+		msg += fmt.Sprintf("[... %d bytes not streamed ...]\n", s.dropped)
+		s.dropped = 0
+	}
+	s.pending = append(s.pending[:0], s.pending[cut:]...)
+	s.last = time.Now()
+	s.emit(coreruntime.ProgressEvent{Phase: coreruntime.ProgressToolStream, Tool: s.tool, Message: msg})
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// seqOutput is what `seq 1 n` prints.
+func seqOutput(n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		sb.WriteString(strconv.Itoa(i) + "\n")
+	}
+	return sb.String()
+}
+
+func TestCLIExecute_HeadTailAndSpill(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("seq not available on Windows")
+	}
+	filesDir := t.TempDir()
+	tool := NewCLIExecuteTool(CLIExecuteConfig{
+		AllowedBinaries: []string{"seq"},
+		MaxOutputBytes:  100,
+		OutputTailBytes: 40,
+	})
+	args, _ := json.Marshal(cliExecuteArgs{Binary: "seq", Args: []string{"1", "1000"}})
+
+	result, err := tool.Execute(coreruntime.WithFilesDir(context.Background(), filesDir), args)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var res cliExecuteResult
+	if err := json.Unmarshal([]byte(result), &res); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+
+	full := seqOutput(1000)
+	if !res.Truncated {
+		t.Error("expected truncated = true")
+	}
+	if !strings.HasPrefix(res.Stdout, full[:60]) || !strings.HasSuffix(res.Stdout, full[len(full)-40:]) {
+		t.Errorf("stdout = %q, want the first 60 and last 40 bytes", res.Stdout)
+	}
+	omitted := strconv.Itoa(len(full)-100) + " bytes omitted"
+	if !strings.Contains(res.Stdout, omitted) {
+		t.Errorf("stdout = %q, want a %q marker", res.Stdout, omitted)
+	}
+
+	if filepath.Dir(res.StdoutFile) != filesDir {
+		t.Fatalf("stdout_file = %q, want a file in %s", res.StdoutFile, filesDir)
+	}
+	spilled, err := os.ReadFile(res.StdoutFile)
+	if err != nil {
+		t.Fatalf("reading spill file: %v", err)
+	}
+	if string(spilled) != full {
+		t.Errorf("spill file holds %d bytes, want the full %d", len(spilled), len(full))
+	}
+	if res.StderrFile != "" {
+		t.Errorf("stderr_file = %q, want none", res.StderrFile)
+	}
+}
+
+func TestCLIExecute_NoSpillWhenTailCoversOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("seq not available on Windows")
+	}
+	filesDir := t.TempDir()
+	tool := NewCLIExecuteTool(CLIExecuteConfig{
+		AllowedBinaries: []string{"seq"},
+		MaxOutputBytes:  100,
+		OutputTailBytes: 60,
+	})
+	args, _ := json.Marshal(cliExecuteArgs{Binary: "seq", Args: []string{"1", "30"}}) // 81 bytes
+
+	result, err := tool.Execute(coreruntime.WithFilesDir(context.Background(), filesDir), args)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var res cliExecuteResult
+	if err := json.Unmarshal([]byte(result), &res); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if res.Truncated || res.Stdout != seqOutput(30) || res.StdoutFile != "" {
+		t.Errorf("result = %+v, want the whole output and no spill", res)
+	}
+	if entries, _ := os.ReadDir(filesDir); len(entries) != 0 {
+		t.Errorf("files dir has %d entries, want the spill file removed", len(entries))
+	}
+}
+
+func TestCLIExecute_StreamOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("seq not available on Windows")
+	}
+	tool := NewCLIExecuteTool(CLIExecuteConfig{
+		AllowedBinaries: []string{"seq"},
+		StreamOutput:    true,
+	})
+	var streamed strings.Builder
+	ctx := coreruntime.WithProgressEmitter(context.Background(), func(ev coreruntime.ProgressEvent) {
+		if ev.Phase != coreruntime.ProgressToolStream || ev.Tool != "cli_execute" {
+			t.Errorf("event = %+v, want a cli_execute tool_stream event", ev)
+		}
+		streamed.WriteString(ev.Message)
+	})
+	args, _ := json.Marshal(cliExecuteArgs{Binary: "seq", Args: []string{"1", "200"}})
+
+	if _, err := tool.Execute(ctx, args); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := streamed.String(); got != seqOutput(200) {
+		t.Errorf("streamed %q, want the command's output", got)
+	}
+}
+
+func TestOutputStreamerDropsPastChunkLimit(t *testing.T) {
+	var msgs []string
+	s := &outputStreamer{
+		emit: func(ev coreruntime.ProgressEvent) { msgs = append(msgs, ev.Message) },
+		tool: "cli_execute",
+	}
+	s.write([]byte(strings.Repeat("x", streamChunkBytes+10)))
+	s.flush()
+
+	if len(msgs) != 1 {
+		t.Fatalf("emitted %d chunks, want 1", len(msgs))
+	}
+	if !strings.HasSuffix(msgs[0], "[... 10 bytes not streamed ...]\n") {
+		t.Errorf("chunk ends %q, want a not-streamed marker", msgs[0][len(msgs[0])-40:])
+	}
+}
+
+func TestParseCLIExecuteConfig_OutputControls(t *testing.T) {
+	cfg := ParseCLIExecuteConfig(map[string]any{
+		"output_tail_bytes": 4096,
+		"spill_max_bytes":   float64(-1),
+		"stream_output":     true,
+	})
+	if cfg.OutputTailBytes != 4096 || cfg.SpillMaxBytes != -1 || !cfg.StreamOutput {
+		t.Errorf("config = %+v", cfg)
+	}
+}
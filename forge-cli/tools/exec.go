@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/initializ/forge/forge-core/llm/oauth"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	coretools "github.com/initializ/forge/forge-core/tools"
 )
 
 // otelEnvPassthroughPrefixes lists the OTel SDK env var prefixes / names
@@ -49,6 +51,27 @@ var otelEnvPassthroughPrefixes = []string{
 	"OTEL_SDK_DISABLED",
 }
 
+// sandboxArgs rewrites args for a subprocess started in a task sandbox
+// rather than base ("" for the process directory): relative arguments
+// naming a file under base — the script being run — become absolute so
+// they still resolve. Other arguments are left to the subprocess.
+func sandboxArgs(base string, args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = a
+		if a == "" || filepath.IsAbs(a) {
+			continue
+		}
+		p := filepath.Join(base, a)
+		if fi, err := os.Stat(p); err == nil && fi.Mode().IsRegular() {
+			if abs, err := filepath.Abs(p); err == nil {
+				out[i] = abs
+			}
+		}
+	}
+	return out
+}
+
 // OSCommandExecutor implements tools.CommandExecutor using os/exec.
 type OSCommandExecutor struct{}
 
@@ -56,8 +79,13 @@ func (e *OSCommandExecutor) Run(ctx context.Context, command string, args []stri
 	cmdCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	sandbox := coretools.SandboxRootFromContext(ctx)
+	if sandbox != "" {
+		args = sandboxArgs("", args)
+	}
 	cmd := exec.CommandContext(cmdCtx, command, args...)
 	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Dir = sandbox
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -92,16 +120,34 @@ func (e *SkillCommandExecutor) Run(ctx context.Context, command string, args []s
 	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	// A sandboxed tool starts in its task sandbox, which is also its HOME
+	// and temp directory.
+	sandbox := coretools.SandboxRootFromContext(ctx)
+	if sandbox != "" {
+		args = sandboxArgs(e.WorkDir, args)
+	}
 	cmd := exec.CommandContext(cmdCtx, command, args...)
 	cmd.Stdin = bytes.NewReader(stdin)
-	if e.WorkDir != "" {
+	if sandbox != "" {
+		cmd.Dir = sandbox
+	} else if e.WorkDir != "" {
 		cmd.Dir = e.WorkDir
 	}
 
 	// Build minimal environment with only explicitly allowed variables.
+	home := userHome()
+	if sandbox != "" {
+		home = sandbox
+	}
 	env := []string{
 		"PATH=" + os.Getenv("PATH"),
-		"HOME=" + os.Getenv("HOME"),
+		"HOME=" + home,
+	}
+	env = append(env, platformEnv(home)...)
+	if sandbox != "" {
+		for _, name := range []string{"TMPDIR", "TEMP", "TMP", "FORGE_SCRATCH_DIR"} {
+			env = append(env, name+"="+sandbox)
+		}
 	}
 	for _, name := range e.EnvVars {
 		if val := os.Getenv(name); val != "" {
@@ -156,12 +202,7 @@ func (e *SkillCommandExecutor) Run(ctx context.Context, command string, args []s
 		// decodes it (identityFromRequest) and tags egress_allowed/blocked
 		// events. When ctx carries neither ID the base URL is used unchanged.
 		proxyURL := proxyURLWithIdentity(ctx, e.ProxyURL)
-		env = append(env,
-			"HTTP_PROXY="+proxyURL,
-			"HTTPS_PROXY="+proxyURL,
-			"http_proxy="+proxyURL,
-			"https_proxy="+proxyURL,
-		)
+		env = appendProxyEnv(env, proxyURL, "HTTP_PROXY", "HTTPS_PROXY")
 	}
 	if e.SOCKSURL != "" {
 		// #337 — raw-TCP egress for databases / message brokers via SOCKS5.
@@ -173,11 +214,7 @@ func (e *SkillCommandExecutor) Run(ctx context.Context, command string, args []s
 		// No identity injection here: SOCKS5v5 (no-auth) has no channel for
 		// per-request credentials — task/correlation attribution is HTTP-only.
 		// This is a known limitation documented in the egress-control doc.
-		env = append(env,
-			"ALL_PROXY="+e.SOCKSURL,
-			"all_proxy="+e.SOCKSURL,
-			"SOCKS_PROXY="+e.SOCKSURL,
-		)
+		env = appendProxyEnv(env, e.SOCKSURL, "ALL_PROXY", "SOCKS_PROXY")
 	}
 	// Issue #182 — propagate W3C trace context + curated OTel SDK env
 	// vars so the subprocess's spans nest under the parent agent's
@@ -211,11 +248,14 @@ func (e *SkillCommandExecutor) Run(ctx context.Context, command string, args []s
 	}
 	cmd.Env = env
 
+	// Scripts report progress on stderr (see progressPrefix).
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd.Stderr = newProgressWriter(ctx, &stderr)
 
-	if err := cmd.Run(); err != nil {
+	err := cmd.Run()
+	flushProgress(cmd.Stderr)
+	if err != nil {
 		if stderr.Len() > 0 {
 			return "", fmt.Errorf("skill command error: %s", stderr.String())
 		}
@@ -254,6 +294,17 @@ func proxyURLWithIdentity(ctx context.Context, base string) string {
 	return u.String()
 }
 
+// appendProxyEnv sets each named proxy variable to value, under the
+// names the OS expects (see proxyEnvNames).
+func appendProxyEnv(env []string, value string, names ...string) []string {
+	for _, name := range names {
+		for _, n := range proxyEnvNames(name) {
+			env = append(env, n+"="+value)
+		}
+	}
+	return env
+}
+
 // resolveOAuthToken loads and refreshes the OpenAI OAuth token.
 // Returns nil if no valid token is available.
 func resolveOAuthToken() *oauth.Token {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/base64"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -14,6 +16,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	coretools "github.com/initializ/forge/forge-core/tools"
 )
 
 func TestSkillCommandExecutor_OrgIDInjection(t *testing.T) {
@@ -33,6 +36,63 @@ func TestSkillCommandExecutor_OrgIDInjection(t *testing.T) {
 	}
 }
 
+func TestSkillCommandExecutor_Sandboxed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses a shell script")
+	}
+	workDir := t.TempDir()
+	sandbox := t.TempDir()
+	script := filepath.Join("scripts", "where.sh")
+	if err := os.MkdirAll(filepath.Join(workDir, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, script), []byte("pwd\necho \"$HOME $TMPDIR $FORGE_SCRATCH_DIR\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &SkillCommandExecutor{WorkDir: workDir}
+	ctx := coretools.WithSandboxRoot(context.Background(), sandbox)
+	out, err := e.Run(ctx, "bash", []string{script, "{}"}, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	real, _ := filepath.EvalSymlinks(sandbox)
+	want := real + "\n" + strings.Repeat(sandbox+" ", 2) + sandbox + "\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestSkillCommandExecutor_Progress(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses a shell script")
+	}
+	var events []coreruntime.ProgressEvent
+	ctx := coreruntime.WithProgressEmitter(context.Background(), func(ev coreruntime.ProgressEvent) {
+		events = append(events, ev)
+	})
+	script := `echo "::progress 40 Fetched 4 of 10" >&2; echo "::progress 250 done" >&2; echo "::progress soon" >&2; echo out; exit 1`
+
+	e := &SkillCommandExecutor{}
+	_, err := e.Run(ctx, "bash", []string{"-c", script}, nil)
+	if err == nil {
+		t.Fatal("expected error from failing script")
+	}
+	// Progress lines are stripped from stderr; a malformed one is kept.
+	if want := "skill command error: ::progress soon\n"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+	if ev := events[0]; ev.Phase != coreruntime.ProgressToolStream || *ev.Percent != 40 || ev.Message != "Fetched 4 of 10" {
+		t.Errorf("events[0] = %+v (percent %d)", ev, *ev.Percent)
+	}
+	if p := *events[1].Percent; p != 100 {
+		t.Errorf("events[1] percent = %d, want clamped to 100", p)
+	}
+}
+
 func TestSkillCommandExecutor_NoOrgIDWhenUnset(t *testing.T) {
 	// Ensure the env var is NOT set
 	os.Unsetenv("OPENAI_ORG_ID") //nolint:errcheck
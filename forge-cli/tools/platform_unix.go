@@ -0,0 +1,31 @@
+//go:build !windows
+
+package tools
+
+import "strings"
+
+// proxyEnvNames returns the env var names a proxy setting is exported
+// under. Tools disagree on case (curl reads http_proxy, Go reads either),
+// so both are set.
+func proxyEnvNames(name string) []string {
+	return []string{name, strings.ToLower(name)}
+}
+
+// canonicalBinary returns the name cli_execute matches binaries by.
+func canonicalBinary(name string) string { return name }
+
+// platformEnv returns the extra variables a subprocess needs on this OS
+// besides PATH, HOME and LANG. Unix needs none.
+func platformEnv(string) []string { return nil }
+
+// platformScriptInterpreters maps script extensions that only run on this
+// OS to their interpreter command line. Unix has none beyond the portable
+// ones in interpreterForScript.
+var platformScriptInterpreters = map[string][]string{}
+
+// pythonCommand is the Python 3 interpreter.
+func pythonCommand() string { return "python3" }
+
+// looksLikePlatformPath reports OS-specific path forms looksLikePath
+// doesn't cover. Unix has none.
+func looksLikePlatformPath(string) bool { return false }
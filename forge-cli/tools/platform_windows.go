@@ -0,0 +1,72 @@
+//go:build windows
+
+package tools
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// proxyEnvNames returns the env var names a proxy setting is exported
+// under. Windows env names are case-insensitive, so HTTP_PROXY and
+// http_proxy are one variable and only the upper-case name is set.
+func proxyEnvNames(name string) []string { return []string{name} }
+
+// canonicalBinary returns the name cli_execute matches binaries by:
+// lower-case, without .exe, so "kubectl", "kubectl.exe" and "Kubectl.EXE"
+// all name the same allow-listed binary. exec.LookPath adds the extension
+// back from PATHEXT.
+func canonicalBinary(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
+
+// windowsEnvPassthrough are the variables Windows programs expect to find.
+// Without SYSTEMROOT even Go binaries can't open sockets; without
+// PATHEXT, cmd and PowerShell can't resolve commands.
+var windowsEnvPassthrough = []string{
+	"SYSTEMROOT", "WINDIR", "SYSTEMDRIVE", "PATHEXT", "COMSPEC",
+	"TEMP", "TMP", "APPDATA", "LOCALAPPDATA", "PROGRAMDATA",
+	"PROGRAMFILES", "PROGRAMFILES(X86)", "NUMBER_OF_PROCESSORS", "PROCESSOR_ARCHITECTURE",
+}
+
+// platformEnv returns the extra variables a subprocess needs on Windows
+// besides PATH, HOME and LANG. USERPROFILE is Windows' HOME and follows
+// the same override.
+func platformEnv(home string) []string {
+	env := []string{"USERPROFILE=" + home}
+	for _, name := range windowsEnvPassthrough {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+// platformScriptInterpreters maps Windows-only script extensions to their
+// interpreter command line; the script path and JSON argument follow.
+var platformScriptInterpreters = map[string][]string{
+	".ps1": {"powershell", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File"},
+	".cmd": {"cmd", "/d", "/c"},
+	".bat": {"cmd", "/d", "/c"},
+}
+
+// pythonCommand is the Python 3 interpreter. The python.org installer
+// ships python.exe; python3.exe is only the Microsoft Store alias.
+func pythonCommand() string {
+	if _, err := exec.LookPath("python3"); err == nil {
+		return "python3"
+	}
+	return "python"
+}
+
+// looksLikePlatformPath reports Windows path forms: drive-letter and UNC
+// paths, and .\ ..\ ~\ relative paths.
+func looksLikePlatformPath(arg string) bool {
+	return filepath.IsAbs(arg) || filepath.VolumeName(arg) != "" ||
+		strings.HasPrefix(arg, `\`) ||
+		strings.HasPrefix(arg, `.\`) ||
+		strings.HasPrefix(arg, `..\`) ||
+		strings.HasPrefix(arg, `~\`)
+}
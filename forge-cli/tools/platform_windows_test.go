@@ -0,0 +1,88 @@
+//go:build windows
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalBinary_Windows(t *testing.T) {
+	for _, name := range []string{"kubectl", "kubectl.exe", "Kubectl.EXE"} {
+		if got := canonicalBinary(name); got != "kubectl" {
+			t.Errorf("canonicalBinary(%q) = %q, want kubectl", name, got)
+		}
+	}
+}
+
+func TestLooksLikePath_Windows(t *testing.T) {
+	for _, arg := range []string{`C:\Users\me\.ssh\id_rsa`, `C:/Users/me`, `\\server\share`, `.\data.txt`, `..\up`, `~\Documents`} {
+		if !looksLikePath(arg) {
+			t.Errorf("looksLikePath(%q) = false, want true", arg)
+		}
+	}
+	if looksLikePath("get") {
+		t.Error(`looksLikePath("get") = true, want false`)
+	}
+}
+
+func TestBuildEnv_Windows(t *testing.T) {
+	t.Setenv("SYSTEMROOT", `C:\Windows`)
+	tool := NewCLIExecuteTool(CLIExecuteConfig{WorkDir: t.TempDir()})
+	tool.proxyURL = "http://127.0.0.1:54321"
+
+	env := strings.Join(tool.buildEnv(context.Background(), "curl"), "\n")
+	for _, want := range []string{`SYSTEMROOT=C:\Windows`, "USERPROFILE=" + tool.workDir, "HTTP_PROXY=http://127.0.0.1:54321"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("env missing %q:\n%s", want, env)
+		}
+	}
+	// One spelling per variable: Windows env names are case-insensitive.
+	if strings.Contains(env, "http_proxy=") {
+		t.Errorf("env sets http_proxy alongside HTTP_PROXY:\n%s", env)
+	}
+}
+
+func TestCLIExecute_ExeSuffix_Windows(t *testing.T) {
+	if _, err := exec.LookPath("whoami"); err != nil {
+		t.Skip("whoami not available")
+	}
+	tool := NewCLIExecuteTool(CLIExecuteConfig{AllowedBinaries: []string{"whoami"}})
+	args, _ := json.Marshal(cliExecuteArgs{Binary: "whoami.exe"})
+	out, err := tool.Execute(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Execute(whoami.exe) with whoami allowed: %v", err)
+	}
+	var res cliExecuteResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil || res.ExitCode != 0 || res.Stdout == "" {
+		t.Errorf("result = %s (%v)", out, err)
+	}
+}
+
+func TestRunSkillScript_Batch_Windows(t *testing.T) {
+	root := t.TempDir()
+	writeSkillScript(t, root, "owl", "SKILL.md", "---\nname: owl\ndescription: d\n---\n")
+	writeSkillScript(t, root, "owl", "scripts/hello.cmd", "@echo off\r\necho hello from cmd\r\n")
+
+	out := runScript(t, root, "owl", "scripts/hello.cmd", nil)
+	if !strings.Contains(out, "hello from cmd") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunSkillScript_PowerShell_Windows(t *testing.T) {
+	if _, err := exec.LookPath("powershell"); err != nil {
+		t.Skip("powershell not available")
+	}
+	root := t.TempDir()
+	writeSkillScript(t, root, "owl", "SKILL.md", "---\nname: owl\ndescription: d\n---\n")
+	writeSkillScript(t, root, "owl", "scripts/hello.ps1", "param($in)\r\n$a = $in | ConvertFrom-Json\r\nWrite-Output \"n=$($a.n)\"\r\n")
+
+	out := runScript(t, root, "owl", "scripts/hello.ps1", map[string]any{"n": 7})
+	if !strings.Contains(out, "n=7") {
+		t.Errorf("output = %q", out)
+	}
+}
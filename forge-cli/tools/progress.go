@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// progressPrefix starts a progress line a skill script writes to stderr:
+//
+//	::progress 40 Fetched 4 of 10 pages
+//
+// The line reaches the client as a tool_stream progress event carrying
+// the percentage, and is dropped from the stderr the tool returns.
+const progressPrefix = "::progress "
+
+// progressWriter passes stderr through to w, turning progress lines into
+// progress events. Partial lines are held until their newline; call
+// flush once the command has exited.
+type progressWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	pending []byte
+}
+
+// newProgressWriter returns w itself when ctx has no client to report
+// progress to: progress lines are then ordinary stderr.
+func newProgressWriter(ctx context.Context, w io.Writer) io.Writer {
+	if coreruntime.ProgressEmitterFromContext(ctx) == nil {
+		return w
+	}
+	return &progressWriter{ctx: ctx, w: w}
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.pending = append(p.pending, b...)
+	for {
+		i := bytes.IndexByte(p.pending, '\n')
+		if i < 0 {
+			break
+		}
+		p.line(p.pending[:i+1])
+		p.pending = p.pending[i+1:]
+	}
+	// A line this long is not a progress report; stop holding it.
+	if len(p.pending) > streamChunkBytes {
+		_, _ = p.w.Write(p.pending)
+		p.pending = nil
+	}
+	return len(b), nil
+}
+
+func (p *progressWriter) line(l []byte) {
+	rest, ok := strings.CutPrefix(strings.TrimRight(string(l), "\r\n"), progressPrefix)
+	if ok {
+		pct, msg, _ := strings.Cut(strings.TrimSpace(rest), " ")
+		if n, err := strconv.Atoi(strings.TrimSuffix(pct, "%")); err == nil {
+			coreruntime.ReportProgress(p.ctx, n, strings.TrimSpace(msg))
+			return
+		}
+	}
+	_, _ = p.w.Write(l)
+}
+
+// flushProgress writes out a trailing partial line held by w, when w is
+// a progressWriter.
+func flushProgress(w io.Writer) {
+	if p, ok := w.(*progressWriter); ok && len(p.pending) > 0 {
+		p.line(p.pending)
+		p.pending = nil
+	}
+}
@@ -1,11 +1,14 @@
 package tools
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,8 +21,9 @@ import (
 // ("run owl/scripts/check.py"). Unlike a `## Tool:` entry (registered as a
 // first-class callable tool via registerSkillTools, `.sh`-only), this tool
 // resolves an arbitrary script path relative to the skill directory, picks
-// the interpreter from the extension (shell / python / javascript), and
-// runs it with the skill directory as the working directory so the
+// the interpreter from the extension (shell / python / javascript, plus
+// PowerShell and batch on Windows) or else the script's #! line, and runs
+// it with the skill directory as the working directory so the
 // script's own relative references resolve. See issue #251.
 //
 // Path resolution is confined to the skill directory (no `..` / absolute
@@ -50,7 +54,8 @@ func (t *RunSkillScriptTool) Name() string                 { return "run_skill_s
 func (t *RunSkillScriptTool) Category() coretools.Category { return coretools.CategoryBuiltin }
 
 func (t *RunSkillScriptTool) Description() string {
-	return "Execute a helper script bundled inside a skill's directory (shell .sh, python .py, or javascript .js). " +
+	return "Execute a helper script bundled inside a skill's directory (shell .sh, python .py, javascript .js, " +
+		"PowerShell .ps1 or batch .cmd/.bat on Windows, or any script with a #! line). " +
 		"The path is resolved relative to the skill and the script runs with the skill's directory as its working " +
 		"directory, so the script's own relative references resolve. Use for skill instructions like " +
 		"'run owl/scripts/check.py'. JSON in 'args' is passed to the script as its first positional argument ($1)."
@@ -105,7 +110,9 @@ func (t *RunSkillScriptTool) Execute(ctx context.Context, args json.RawMessage)
 
 	interp, ierr := interpreterForScript(input.Path)
 	if ierr != nil {
-		return jsonError(ierr.Error()), nil
+		if interp = shebangInterpreter(full); interp == nil {
+			return jsonError(ierr.Error()), nil
+		}
 	}
 
 	// CWD = the skill dir so `input.Path` (relative) and the script's own
@@ -118,7 +125,8 @@ func (t *RunSkillScriptTool) Execute(ctx context.Context, args json.RawMessage)
 		ProxyURL: t.proxyURL,
 		SOCKSURL: t.socksURL,
 	}
-	out, runErr := exec.Run(ctx, interp, []string{input.Path, jsonArgs}, nil)
+	argv := append(append([]string(nil), interp[1:]...), input.Path, jsonArgs)
+	out, runErr := exec.Run(ctx, interp[0], argv, nil)
 	if runErr != nil {
 		// Build via json.Marshal, not fmt %q: script output can carry raw
 		// bytes / invalid UTF-8 that %q would emit as \xNN escapes, which
@@ -142,20 +150,65 @@ func jsonObj(m map[string]any) string {
 // jsonError builds a well-formed JSON error object for the given message.
 func jsonError(msg string) string { return jsonObj(map[string]any{"error": msg}) }
 
-// interpreterForScript picks the interpreter for a script by extension.
-// TypeScript is intentionally unsupported — `node` can't run raw `.ts`;
-// ship a compiled `.js` instead.
-func interpreterForScript(path string) (string, error) {
-	switch strings.ToLower(filepath.Ext(path)) {
+// interpreterForScript picks the interpreter command line for a script by
+// extension; the script path and JSON argument follow it. TypeScript is
+// intentionally unsupported — `node` can't run raw `.ts`; ship a compiled
+// `.js` instead. On Windows, `.sh` needs bash on PATH (Git for Windows
+// ships one).
+func interpreterForScript(path string) ([]string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
 	case ".sh", ".bash":
-		return "bash", nil
+		return []string{"bash"}, nil
 	case ".py":
-		return "python3", nil
+		return []string{pythonCommand()}, nil
 	case ".js", ".cjs", ".mjs":
-		return "node", nil
-	default:
-		return "", fmt.Errorf("unsupported script type %q (supported: .sh, .py, .js)", filepath.Ext(path))
+		return []string{"node"}, nil
+	}
+	if interp, ok := platformScriptInterpreters[ext]; ok {
+		return interp, nil
+	}
+	supported := []string{".sh", ".py", ".js"}
+	for e := range platformScriptInterpreters {
+		supported = append(supported, e)
+	}
+	sort.Strings(supported[3:])
+	return nil, fmt.Errorf("unsupported script type %q (supported: %s, or a #! line)", filepath.Ext(path), strings.Join(supported, ", "))
+}
+
+// shebangInterpreter returns the interpreter command line from a script's
+// #! line, or nil when it has none. The interpreter is looked up by name
+// on PATH rather than run from the path in the line, so
+// "#!/usr/bin/env python3" and "#!/usr/bin/python3" both work on Windows,
+// which has no shebang support of its own.
+func shebangInterpreter(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return nil
+	}
+	if !strings.HasPrefix(line, "#!") {
+		return nil
+	}
+	fields := strings.Fields(line[2:])
+	if len(fields) > 0 && path.Base(fields[0]) == "env" {
+		fields = fields[1:]
+		if len(fields) > 0 && fields[0] == "-S" {
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	fields[0] = path.Base(fields[0])
+	if fields[0] == "python3" {
+		fields[0] = pythonCommand()
 	}
+	return fields
 }
 
 func truncate(s string, max int) string {
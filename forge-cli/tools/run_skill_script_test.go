@@ -159,10 +159,10 @@ func TestRunSkillScript_ArgsMustBeObject(t *testing.T) {
 }
 
 func TestInterpreterForScript(t *testing.T) {
-	ok := map[string]string{"a.sh": "bash", "a.bash": "bash", "a.py": "python3", "a.js": "node", "a.mjs": "node"}
+	ok := map[string]string{"a.sh": "bash", "a.bash": "bash", "a.py": pythonCommand(), "a.js": "node", "a.mjs": "node"}
 	for path, want := range ok {
 		got, err := interpreterForScript(path)
-		if err != nil || got != want {
+		if err != nil || len(got) != 1 || got[0] != want {
 			t.Errorf("interpreterForScript(%q) = %q,%v want %q", path, got, err, want)
 		}
 	}
@@ -170,3 +170,39 @@ func TestInterpreterForScript(t *testing.T) {
 		t.Error("expected error for .rb")
 	}
 }
+
+func TestShebangInterpreter(t *testing.T) {
+	dir := t.TempDir()
+	for line, want := range map[string][]string{
+		"#!/usr/bin/env node\n":                  {"node"},
+		"#!/usr/bin/env -S node --no-warnings\n": {"node", "--no-warnings"},
+		"#!/bin/bash -e\n":                       {"bash", "-e"},
+		"#!/usr/bin/python3\n":                   {pythonCommand()},
+		"echo no shebang\n":                      nil,
+		"#!\n":                                   nil,
+	} {
+		file := filepath.Join(dir, "script")
+		if err := os.WriteFile(file, []byte(line+"body\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if got := shebangInterpreter(file); strings.Join(got, " ") != strings.Join(want, " ") || (got == nil) != (want == nil) {
+			t.Errorf("shebangInterpreter(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+// TestRunSkillScript_Shebang runs an extension-less script through the
+// interpreter its #! line names.
+func TestRunSkillScript_Shebang(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not installed")
+	}
+	root := t.TempDir()
+	writeSkillScript(t, root, "owl", "SKILL.md", "---\nname: owl\ndescription: d\n---\n")
+	writeSkillScript(t, root, "owl", "scripts/check", "#!/usr/bin/env bash\nprintf '{\"got\": %s}\\n' \"$1\"\n")
+
+	out := runScript(t, root, "owl", "scripts/check", map[string]any{"n": 1})
+	if strings.TrimSpace(out) != `{"got": {"n":1}}` {
+		t.Errorf("output = %q", out)
+	}
+}
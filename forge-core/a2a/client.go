@@ -0,0 +1,460 @@
+package a2a
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client defaults.
+const (
+	DefaultClientMaxRetries   = 2
+	DefaultClientRetryBackoff = 500 * time.Millisecond
+	DefaultClientCardTTL      = 5 * time.Minute
+
+	// maxClientRetryWait caps how long a Retry-After header can make
+	// the client wait between attempts.
+	maxClientRetryWait = 30 * time.Second
+
+	// maxErrorBodyBytes bounds how much of an error response is read.
+	maxErrorBodyBytes = 64 << 10
+)
+
+// ClientConfig configures a Client.
+type ClientConfig struct {
+	// BaseURL is the agent's A2A endpoint, e.g. "http://localhost:8080".
+	// JSON-RPC requests are POSTed to it; the Agent Card is fetched from
+	// its /.well-known/agent-card.json.
+	BaseURL string
+
+	// Token, when set, is sent as "Authorization: Bearer <Token>".
+	Token string
+
+	// Header is added to every request (e.g. on-behalf-of or tenant
+	// headers). Authorization here is overridden by Token.
+	Header http.Header
+
+	// HTTPClient sends the requests. nil uses a client without a
+	// timeout — streams can run as long as the task does; bound calls
+	// with the context instead.
+	HTTPClient *http.Client
+
+	// MaxRetries caps how many times a failed request is retried. 0
+	// means DefaultClientMaxRetries; negative disables retries.
+	MaxRetries int
+
+	// RetryBackoff is the wait before the first retry, doubled for each
+	// later one. 0 means DefaultClientRetryBackoff. A Retry-After
+	// header from the agent takes precedence.
+	RetryBackoff time.Duration
+
+	// CardTTL is how long a fetched Agent Card is reused. 0 means
+	// DefaultClientCardTTL; negative disables caching.
+	CardTTL time.Duration
+}
+
+// Client calls a remote agent over the A2A JSON-RPC binding: tasks/send,
+// tasks/sendSubscribe (Server-Sent Events), tasks/get and tasks/cancel,
+// plus Agent Card discovery. It is safe for concurrent use.
+//
+// Requests that fail before the agent could act on them — refused
+// connections, 429 and 503 responses — are retried with backoff.
+// tasks/get and tasks/cancel, being idempotent, are also retried after
+// timeouts and 502/504 responses; a task send never is, since the agent
+// may already be working on it.
+//
+// Failures are returned as *HTTPError (non-200 response) or *RPCError
+// (JSON-RPC error); both carry the agent's TaskError when it sent one.
+type Client struct {
+	cfg      ClientConfig
+	endpoint string
+	http     *http.Client
+	nextID   atomic.Int64
+
+	cardMu      sync.Mutex
+	card        *AgentCard
+	cardFetched time.Time
+}
+
+// NewClient returns a Client for the agent at cfg.BaseURL.
+func NewClient(cfg ClientConfig) *Client {
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultClientMaxRetries
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = DefaultClientRetryBackoff
+	}
+	if cfg.CardTTL == 0 {
+		cfg.CardTTL = DefaultClientCardTTL
+	}
+	hc := cfg.HTTPClient
+	if hc == nil {
+		hc = &http.Client{}
+	}
+	return &Client{
+		cfg:      cfg,
+		endpoint: strings.TrimRight(cfg.BaseURL, "/") + "/",
+		http:     hc,
+	}
+}
+
+// HTTPError is a non-200 response from the agent.
+type HTTPError struct {
+	StatusCode int
+	// Message is the agent's error message, or the start of the body.
+	Message string
+	// TaskError is the classified error, when the agent sent one.
+	TaskError *TaskError
+	// RetryAfter is the agent's Retry-After, when set.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("a2a: HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("a2a: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// RPCError is a JSON-RPC error returned by the agent, in a response or
+// as a stream's error event.
+type RPCError struct {
+	Code    int
+	Message string
+	// TaskError is the classified error, when the agent sent one.
+	TaskError *TaskError
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("a2a: JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+func newRPCError(e *JSONRPCError) *RPCError {
+	return &RPCError{Code: e.Code, Message: e.Message, TaskError: TaskErrorFromJSONRPC(e)}
+}
+
+// Send sends a message with tasks/send and returns the resulting task.
+func (c *Client) Send(ctx context.Context, params SendTaskParams) (*Task, error) {
+	var task Task
+	if err := c.call(ctx, "tasks/send", params, false, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Get returns the task id with tasks/get.
+func (c *Client) Get(ctx context.Context, id string) (*Task, error) {
+	var task Task
+	if err := c.call(ctx, "tasks/get", GetTaskParams{ID: id}, true, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Cancel cancels a task with tasks/cancel and returns it.
+func (c *Client) Cancel(ctx context.Context, params CancelTaskParams) (*Task, error) {
+	var task Task
+	if err := c.call(ctx, "tasks/cancel", params, true, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SendSubscribe sends a message with tasks/sendSubscribe and returns
+// the event stream. The caller must Close it.
+func (c *Client) SendSubscribe(ctx context.Context, params SendTaskParams) (*Stream, error) {
+	body, err := c.request("tasks/sendSubscribe", params)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, http.MethodPost, c.endpoint, body, false)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		// Errors raised before streaming starts come back as a plain
+		// JSON-RPC response.
+		defer func() { _ = resp.Body.Close() }()
+		var rpcResp JSONRPCResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodyBytes)).Decode(&rpcResp); err == nil && rpcResp.Error != nil {
+			return nil, newRPCError(rpcResp.Error)
+		}
+		return nil, fmt.Errorf("a2a: tasks/sendSubscribe: response is %q, not an event stream", resp.Header.Get("Content-Type"))
+	}
+	return &Stream{body: resp.Body, r: bufio.NewReader(resp.Body)}, nil
+}
+
+// AgentCard returns the agent's card from /.well-known/agent-card.json,
+// reusing a fetched card for CardTTL.
+func (c *Client) AgentCard(ctx context.Context) (*AgentCard, error) {
+	c.cardMu.Lock()
+	defer c.cardMu.Unlock()
+	if c.card != nil && c.cfg.CardTTL > 0 && time.Since(c.cardFetched) < c.cfg.CardTTL {
+		return c.card, nil
+	}
+	resp, err := c.do(ctx, http.MethodGet, c.endpoint+".well-known/agent-card.json", nil, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var card AgentCard
+	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
+		return nil, fmt.Errorf("a2a: parsing agent card: %w", err)
+	}
+	c.card, c.cardFetched = &card, time.Now()
+	return c.card, nil
+}
+
+// call makes one JSON-RPC call and decodes its result into out.
+func (c *Client) call(ctx context.Context, method string, params any, idempotent bool, out any) error {
+	body, err := c.request(method, params)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, c.endpoint, body, idempotent)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *JSONRPCError   `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("a2a: %s: parsing response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return newRPCError(rpcResp.Error)
+	}
+	if err := json.Unmarshal(rpcResp.Result, out); err != nil {
+		return fmt.Errorf("a2a: %s: parsing result: %w", method, err)
+	}
+	return nil
+}
+
+// request encodes a JSON-RPC 2.0 request.
+func (c *Client) request(method string, params any) ([]byte, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("a2a: %s: encoding params: %w", method, err)
+	}
+	return json.Marshal(JSONRPCRequest{JSONRPC: "2.0", ID: c.nextID.Add(1), Method: method, Params: raw})
+}
+
+// do sends one request, retrying as described on Client, and returns
+// the 200 response. Any other status is returned as an *HTTPError.
+func (c *Client) do(ctx context.Context, method, url string, body []byte, idempotent bool) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, url, body)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		if err == nil {
+			err = httpErrorFrom(resp)
+			_ = resp.Body.Close()
+		}
+		if attempt >= c.cfg.MaxRetries || ctx.Err() != nil || !retryable(err, idempotent) {
+			return nil, err
+		}
+		wait := c.cfg.RetryBackoff << attempt
+		var he *HTTPError
+		if errors.As(err, &he) && he.RetryAfter > 0 {
+			wait = min(he.RetryAfter, maxClientRetryWait)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
+	if err != nil {
+		return nil, fmt.Errorf("a2a: creating request: %w", err)
+	}
+	for k, vs := range c.cfg.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("a2a: %w", err)
+	}
+	return resp, nil
+}
+
+// retryable reports whether a request that failed with err may be sent
+// again: always when the agent refused it before acting on it, and for
+// idempotent calls also when the outcome is unknown.
+func retryable(err error, idempotent bool) bool {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		switch he.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		case http.StatusBadGateway, http.StatusGatewayTimeout:
+			return idempotent
+		}
+		return false
+	}
+	var oe *net.OpError
+	if errors.As(err, &oe) && oe.Op == "dial" {
+		return true
+	}
+	return idempotent
+}
+
+// httpErrorFrom builds the HTTPError for a non-200 response. The body
+// may be a JSON-RPC response (rate limiter, auth), a REST error body
+// ({"error": "...", "code": ...}) or plain text.
+func httpErrorFrom(resp *http.Response) *HTTPError {
+	he := &HTTPError{StatusCode: resp.StatusCode}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		he.RetryAfter = time.Duration(secs) * time.Second
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	var body struct {
+		Error             json.RawMessage `json:"error"`
+		Code              ErrorCode       `json:"code"`
+		Retryable         bool            `json:"retryable"`
+		RetryAfterSeconds int             `json:"retry_after_seconds"`
+	}
+	if json.Unmarshal(raw, &body) != nil || len(body.Error) == 0 {
+		he.Message = strings.TrimSpace(string(raw))
+		if len(he.Message) > 200 {
+			he.Message = he.Message[:200]
+		}
+		return he
+	}
+	var rpcErr JSONRPCError
+	if json.Unmarshal(body.Error, &he.Message) != nil && json.Unmarshal(body.Error, &rpcErr) == nil {
+		he.Message = rpcErr.Message
+		he.TaskError = TaskErrorFromJSONRPC(&rpcErr)
+	}
+	if body.Code != "" {
+		he.TaskError = &TaskError{Code: body.Code, Message: he.Message, Retryable: body.Retryable, RetryAfterSeconds: body.RetryAfterSeconds}
+	}
+	return he
+}
+
+// StreamEvent is one Server-Sent Event of a tasks/sendSubscribe stream.
+type StreamEvent struct {
+	// Event is the SSE event type: "status", "progress", "result" or
+	// "error".
+	Event string
+	// Data is the event's raw JSON payload.
+	Data json.RawMessage
+	// Task is the decoded payload of status, progress and result
+	// events.
+	Task *Task
+	// Err is the decoded payload of an error event.
+	Err *RPCError
+}
+
+// Stream reads the events of a tasks/sendSubscribe response.
+type Stream struct {
+	body io.ReadCloser
+	r    *bufio.Reader
+}
+
+// Next returns the next event, or io.EOF once the agent has closed the
+// stream.
+func (s *Stream) Next() (*StreamEvent, error) {
+	var event string
+	var data []string
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil && line == "" {
+			if errors.Is(err, io.EOF) && event != "" && len(data) > 0 {
+				return decodeStreamEvent(event, data)
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			if event != "" && len(data) > 0 {
+				return decodeStreamEvent(event, data)
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// SSE comment (keep-alive).
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
+
+func decodeStreamEvent(event string, data []string) (*StreamEvent, error) {
+	ev := &StreamEvent{Event: event, Data: json.RawMessage(strings.Join(data, "\n"))}
+	if event == "error" {
+		var resp JSONRPCResponse
+		if err := json.Unmarshal(ev.Data, &resp); err != nil || resp.Error == nil {
+			ev.Err = &RPCError{Code: ErrCodeInternal, Message: string(ev.Data)}
+		} else {
+			ev.Err = newRPCError(resp.Error)
+		}
+		return ev, nil
+	}
+	var task Task
+	if err := json.Unmarshal(ev.Data, &task); err == nil && task.ID != "" {
+		ev.Task = &task
+	}
+	return ev, nil
+}
+
+// Result reads the stream to its end and returns the last task it
+// carried, or the error of an error event.
+func (s *Stream) Result() (*Task, error) {
+	var last *Task
+	for {
+		ev, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			if last == nil {
+				return nil, errors.New("a2a: stream ended without a task")
+			}
+			return last, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ev.Err != nil {
+			return nil, ev.Err
+		}
+		if ev.Task != nil && ev.Event != "progress" {
+			last = ev.Task
+		}
+	}
+}
+
+// Close closes the stream.
+func (s *Stream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,193 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rpcServer answers JSON-RPC calls with handle's result or error.
+func rpcServer(t *testing.T, handle func(req JSONRPCRequest) *JSONRPCResponse) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(handle(req))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient_SendGetCancel(t *testing.T) {
+	srv := rpcServer(t, func(req JSONRPCRequest) *JSONRPCResponse {
+		switch req.Method {
+		case "tasks/send":
+			var p SendTaskParams
+			_ = json.Unmarshal(req.Params, &p)
+			return NewResponse(req.ID, Task{ID: p.ID, Status: TaskStatus{State: TaskStateCompleted, Message: &Message{
+				Role: MessageRoleAgent, Parts: []Part{NewTextPart("echo: " + p.Message.Parts[0].Text)},
+			}}})
+		case "tasks/get":
+			return NewErrorResponse(req.ID, ErrCodeInvalidParams, "task not found: t2")
+		case "tasks/cancel":
+			return NewTaskErrorResponse(req.ID, ErrCodeInternal, NewTaskError(ErrorBudgetExceeded, "budget spent"))
+		}
+		return NewErrorResponse(req.ID, ErrCodeMethodNotFound, req.Method)
+	})
+	c := NewClient(ClientConfig{BaseURL: srv.URL, Token: "secret"})
+	ctx := context.Background()
+
+	task, err := c.Send(ctx, SendTaskParams{ID: "t1", Message: Message{Role: MessageRoleUser, Parts: []Part{NewTextPart("hi")}}})
+	if err != nil || task.ID != "t1" || task.Status.Message.Parts[0].Text != "echo: hi" {
+		t.Fatalf("Send = %+v, %v", task, err)
+	}
+
+	var rpcErr *RPCError
+	if _, err := c.Get(ctx, "t2"); !errors.As(err, &rpcErr) || rpcErr.Code != ErrCodeInvalidParams {
+		t.Errorf("Get error = %v", err)
+	}
+	if _, err := c.Cancel(ctx, CancelTaskParams{ID: "t1"}); !errors.As(err, &rpcErr) || rpcErr.TaskError == nil || rpcErr.TaskError.Code != ErrorBudgetExceeded {
+		t.Errorf("Cancel error = %v", err)
+	}
+
+	var httpErr *HTTPError
+	anon := NewClient(ClientConfig{BaseURL: srv.URL})
+	if _, err := anon.Get(ctx, "t1"); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated Get error = %v", err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	var calls atomic.Int32
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(status)
+			_, _ = io.WriteString(w, `{"error":"draining","code":"internal_error"}`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(NewResponse(1, Task{ID: "t1"}))
+	}))
+	defer srv.Close()
+	c := NewClient(ClientConfig{BaseURL: srv.URL, RetryBackoff: time.Millisecond})
+	ctx := context.Background()
+	send := SendTaskParams{ID: "t1", Message: Message{Role: MessageRoleUser, Parts: []Part{NewTextPart("hi")}}}
+
+	// 503: the agent did not take the request, so even a send retries.
+	if _, err := c.Send(ctx, send); err != nil || calls.Load() != 3 {
+		t.Fatalf("Send after 503s = %v (%d calls)", err, calls.Load())
+	}
+
+	// 502: the outcome is unknown, so a send is not retried but a get is.
+	status = http.StatusBadGateway
+	calls.Store(0)
+	var httpErr *HTTPError
+	if _, err := c.Send(ctx, send); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway ||
+		httpErr.TaskError == nil || httpErr.TaskError.Code != ErrorInternal || calls.Load() != 1 {
+		t.Errorf("Send after 502 = %v (%d calls)", err, calls.Load())
+	}
+	calls.Store(0)
+	if _, err := c.Get(ctx, "t1"); err != nil || calls.Load() != 3 {
+		t.Errorf("Get after 502s = %v (%d calls)", err, calls.Load())
+	}
+
+	// Negative MaxRetries disables retries.
+	calls.Store(0)
+	c = NewClient(ClientConfig{BaseURL: srv.URL, MaxRetries: -1})
+	if _, err := c.Get(ctx, "t1"); err == nil || calls.Load() != 1 {
+		t.Errorf("Get without retries = %v (%d calls)", err, calls.Load())
+	}
+}
+
+func TestClient_SendSubscribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		task := func(state TaskState) string {
+			b, _ := json.Marshal(Task{ID: "t1", Status: TaskStatus{State: state}})
+			return string(b)
+		}
+		_, _ = fmt.Fprintf(w, "event: status\ndata: %s\n\n: keep-alive\n\n", task(TaskStateWorking))
+		_, _ = fmt.Fprintf(w, "event: progress\ndata: %s\n\n", task(TaskStateWorking))
+		_, _ = fmt.Fprintf(w, "event: result\ndata: %s\n\n", task(TaskStateCompleted))
+	}))
+	defer srv.Close()
+
+	stream, err := NewClient(ClientConfig{BaseURL: srv.URL}).SendSubscribe(context.Background(), SendTaskParams{ID: "t1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stream.Close() }()
+	ev, err := stream.Next()
+	if err != nil || ev.Event != "status" || ev.Task == nil || ev.Task.Status.State != TaskStateWorking {
+		t.Fatalf("first event = %+v, %v", ev, err)
+	}
+	task, err := stream.Result()
+	if err != nil || task.Status.State != TaskStateCompleted {
+		t.Errorf("Result = %+v, %v", task, err)
+	}
+}
+
+func TestClient_SendSubscribeErrorEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		b, _ := json.Marshal(NewTaskErrorResponse(1, ErrCodeInternal, NewTaskError(ErrorLLMRateLimited, "slow down")))
+		_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", b)
+	}))
+	defer srv.Close()
+
+	stream, err := NewClient(ClientConfig{BaseURL: srv.URL}).SendSubscribe(context.Background(), SendTaskParams{ID: "t1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stream.Close() }()
+	var rpcErr *RPCError
+	if _, err := stream.Result(); !errors.As(err, &rpcErr) || rpcErr.TaskError == nil || !rpcErr.TaskError.Retryable {
+		t.Errorf("Result error = %v", err)
+	}
+}
+
+func TestClient_AgentCardCached(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/agent-card.json" {
+			http.NotFound(w, r)
+			return
+		}
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(AgentCard{Name: "weather", URL: "http://" + r.Host, PreferredTransport: TransportJSONRPC})
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := NewClient(ClientConfig{BaseURL: srv.URL + "/"})
+	for range 2 {
+		card, err := c.AgentCard(ctx)
+		if err != nil || card.Name != "weather" || card.PreferredTransport != TransportJSONRPC {
+			t.Fatalf("AgentCard = %+v, %v", card, err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("fetched %d times, want 1", fetches.Load())
+	}
+
+	uncached := NewClient(ClientConfig{BaseURL: srv.URL, CardTTL: -1})
+	_, _ = uncached.AgentCard(ctx)
+	_, _ = uncached.AgentCard(ctx)
+	if fetches.Load() != 3 {
+		t.Errorf("fetched %d times, want 3", fetches.Load())
+	}
+}
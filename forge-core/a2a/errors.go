@@ -0,0 +1,127 @@
+package a2a
+
+// ErrorCode classifies why a task failed or a request was refused. The
+// same code is surfaced on every transport — failed-task metadata,
+// JSON-RPC error data, REST error bodies, SSE error events and channel
+// fallback replies — so a client branches on one vocabulary instead of
+// parsing free-form messages.
+type ErrorCode string
+
+const (
+	// ErrorGuardrailViolation: a guardrail, skill guardrail, policy
+	// layer or approver blocked the request or the agent's reply.
+	ErrorGuardrailViolation ErrorCode = "guardrail_violation"
+	// ErrorToolError: a tool call failed in a way that ended the task.
+	ErrorToolError ErrorCode = "tool_error"
+	// ErrorLLMRateLimited: the model provider throttled the agent.
+	ErrorLLMRateLimited ErrorCode = "llm_rate_limited"
+	// ErrorLLMUnavailable: the model provider timed out, was
+	// overloaded or failed for an unclassified reason.
+	ErrorLLMUnavailable ErrorCode = "llm_unavailable"
+	// ErrorBudgetExceeded: a quota, billing limit or the agent loop's
+	// iteration budget ran out.
+	ErrorBudgetExceeded ErrorCode = "budget_exceeded"
+	// ErrorLoopDetected: the agent loop was stopped because it kept
+	// repeating the same tool calls without making progress.
+	ErrorLoopDetected ErrorCode = "loop_detected"
+	// ErrorOutputInvalid: the agent's answer broke a skill's output
+	// schema or length limit, and still did after one repair attempt.
+	ErrorOutputInvalid ErrorCode = "output_invalid"
+	// ErrorAuthFailed: the caller (or the agent's provider
+	// credentials) failed authentication, or a step-up is required.
+	ErrorAuthFailed ErrorCode = "auth_failed"
+	// ErrorInternal: anything not covered above.
+	ErrorInternal ErrorCode = "internal_error"
+)
+
+// Retryable reports whether a request that failed with c may succeed
+// if sent again unchanged. A TaskError can override it for a single
+// failure — a quota denial with a known reset time, for instance.
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrorLLMRateLimited, ErrorLLMUnavailable:
+		return true
+	}
+	return false
+}
+
+// MetadataKeyError is the task metadata key a failed task's TaskError
+// is recorded under.
+const MetadataKeyError = "error"
+
+// TaskError is the structured form of a failure. Message is safe to
+// show the caller; the full cause stays in the server log and audit.
+type TaskError struct {
+	Code      ErrorCode `json:"code"`
+	Message   string    `json:"message"`
+	Retryable bool      `json:"retryable"`
+	// RetryAfterSeconds, when set, is how long the caller should wait
+	// before retrying.
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
+	// Details is diagnostic context for the failure, when the error
+	// carries any — what a loop_detected task kept repeating, say.
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// NewTaskError returns a TaskError with the code's default
+// retryability.
+func NewTaskError(code ErrorCode, message string) *TaskError {
+	return &TaskError{Code: code, Message: message, Retryable: code.Retryable()}
+}
+
+// TaskErrorFromTask returns the TaskError recorded on a failed task, or
+// nil. It reads both the in-process value and the map a decoded task
+// carries.
+func TaskErrorFromTask(t *Task) *TaskError {
+	if t == nil || t.Metadata == nil {
+		return nil
+	}
+	return taskErrorFromValue(t.Metadata[MetadataKeyError])
+}
+
+// TaskErrorFromMessage returns the TaskError a streaming executor
+// recorded on its final message, or nil.
+func TaskErrorFromMessage(m *Message) *TaskError {
+	if m == nil || m.Metadata == nil {
+		return nil
+	}
+	return taskErrorFromValue(m.Metadata[MetadataKeyError])
+}
+
+// TaskErrorFromJSONRPC returns the TaskError carried in a JSON-RPC
+// error's data, or nil.
+func TaskErrorFromJSONRPC(e *JSONRPCError) *TaskError {
+	if e == nil {
+		return nil
+	}
+	return taskErrorFromValue(e.Data)
+}
+
+func taskErrorFromValue(v any) *TaskError {
+	switch te := v.(type) {
+	case *TaskError:
+		return te
+	case map[string]any:
+		code, _ := te["code"].(string)
+		if code == "" {
+			return nil
+		}
+		out := &TaskError{Code: ErrorCode(code)}
+		out.Message, _ = te["message"].(string)
+		out.Retryable, _ = te["retryable"].(bool)
+		if secs, ok := te["retry_after_seconds"].(float64); ok {
+			out.RetryAfterSeconds = int(secs)
+		}
+		out.Details, _ = te["details"].(map[string]any)
+		return out
+	}
+	return nil
+}
+
+// NewTaskErrorResponse creates a JSON-RPC error response whose data is
+// te, so JSON-RPC callers see the same code as REST and SSE callers.
+func NewTaskErrorResponse(id any, code int, te *TaskError) *JSONRPCResponse {
+	resp := NewErrorResponse(id, code, te.Message)
+	resp.Error.Data = te
+	return resp
+}
@@ -0,0 +1,58 @@
+package a2a
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTaskErrorFromTask_RoundTrip(t *testing.T) {
+	task := &Task{ID: "t1", Metadata: map[string]any{
+		MetadataKeyError: &TaskError{Code: ErrorBudgetExceeded, Message: "quota", Retryable: true, RetryAfterSeconds: 30},
+	}}
+	if te := TaskErrorFromTask(task); te == nil || te.Code != ErrorBudgetExceeded {
+		t.Fatalf("in-process: %+v", te)
+	}
+
+	b, err := json.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Task
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	te := TaskErrorFromTask(&decoded)
+	if te == nil || !reflect.DeepEqual(*te, TaskError{Code: ErrorBudgetExceeded, Message: "quota", Retryable: true, RetryAfterSeconds: 30}) {
+		t.Errorf("decoded: %+v", te)
+	}
+
+	// Details survive the round trip.
+	task.Metadata[MetadataKeyError] = &TaskError{Code: ErrorLoopDetected, Message: "stuck", Details: map[string]any{"kind": "repeated_call"}}
+	b, _ = json.Marshal(task)
+	decoded = Task{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if te := TaskErrorFromTask(&decoded); te == nil || te.Details["kind"] != "repeated_call" {
+		t.Errorf("decoded details: %+v", te)
+	}
+
+	if TaskErrorFromTask(&Task{Metadata: map[string]any{"correlation_id": "c"}}) != nil {
+		t.Error("task without an error should yield nil")
+	}
+}
+
+func TestNewTaskError_DefaultRetryable(t *testing.T) {
+	for code, want := range map[ErrorCode]bool{
+		ErrorLLMRateLimited:     true,
+		ErrorLLMUnavailable:     true,
+		ErrorGuardrailViolation: false,
+		ErrorAuthFailed:         false,
+		ErrorInternal:           false,
+	} {
+		if got := NewTaskError(code, "x").Retryable; got != want {
+			t.Errorf("%s: retryable = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -38,6 +38,9 @@ type JSONRPCError struct {
 type SendTaskParams struct {
 	ID      string  `json:"id"`
 	Message Message `json:"message"`
+	// Metadata is caller-supplied request metadata (e.g. "tags"). The
+	// runtime matches it against model.routes conditions.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // GetTaskParams are the parameters for tasks/get.
@@ -59,6 +62,32 @@ type CancelTaskParams struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// UndoTaskParams are the parameters for tasks/undo.
+//
+// Compensate is optional. When true, every side-effecting tool call in
+// the reverted exchange whose tool implements a compensation hook is
+// asked to reverse its effect (e.g. delete the schedule it just
+// created). When false the exchange is only removed from the
+// conversation and the calls are marked disavowed in the audit trail.
+type UndoTaskParams struct {
+	ID         string `json:"id"`
+	Compensate bool   `json:"compensate,omitempty"`
+}
+
+// EditMessageParams are the parameters for tasks/redactMessage and
+// tasks/deleteMessage.
+//
+// Index is the message's position in the task history. For
+// tasks/redactMessage, Text is optional: when set, only that text is
+// redacted — from the message and from anywhere else the conversation
+// repeats it; when empty, the whole message is. tasks/deleteMessage
+// ignores Text.
+type EditMessageParams struct {
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+	Text  string `json:"text,omitempty"`
+}
+
 // NewResponse creates a successful JSON-RPC 2.0 response.
 func NewResponse(id any, result any) *JSONRPCResponse {
 	return &JSONRPCResponse{
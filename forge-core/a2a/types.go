@@ -1,6 +1,8 @@
 // Package a2a provides shared types for the Agent-to-Agent (A2A) protocol.
 package a2a
 
+import "encoding/json"
+
 // TaskState represents the possible states of an A2A task.
 type TaskState string
 
@@ -54,12 +56,47 @@ const (
 // response. Channel adapters prefer it over head-truncating the verbose body
 // when an inline-friendly message is needed. Empty for short responses where
 // the full text already fits inline.
+//
+// Citations lists the sources the response references with [n] markers,
+// in order of first reference. Channel adapters render them as footnotes.
 type Message struct {
-	Role    MessageRole `json:"role"`
-	Parts   []Part      `json:"parts"`
-	Summary string      `json:"summary,omitempty"`
+	Role      MessageRole `json:"role"`
+	Parts     []Part      `json:"parts"`
+	Summary   string      `json:"summary,omitempty"`
+	Citations []Citation  `json:"citations,omitempty"`
+	// Metadata carries per-message annotations. A streaming executor
+	// records a failure's TaskError under MetadataKeyError, since the
+	// stream has no error channel.
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// Citation is a source a response draws on: a web page, a knowledge base
+// document or a memory file a retrieval tool returned. ID is the number
+// the response cites it by ("[2]").
+type Citation struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Location string `json:"location,omitempty"`
+	Tool     string `json:"tool,omitempty"`
+}
+
+// MetadataKeyHandoff is set (true) in the metadata of the status message
+// the runtime returns for a message it forwarded to human operators
+// instead of the agent (the handoff_to_human tool). No reply is due, so
+// channel adapters post nothing.
+const MetadataKeyHandoff = "handoff"
+
+// MetadataKeyLanguage carries, in a user message's metadata, the ISO
+// 639-1 language the sender wrote in, when the channel router detected
+// it. The runtime writes its own notices for the task in that language.
+const MetadataKeyLanguage = "language"
+
+// MetadataKeyExperiment carries, in a task's metadata, the experiment
+// variant the task ran with: {"id": <experiment>, "variant": <variant>}.
+const MetadataKeyExperiment = "experiment"
+
 // PartKind discriminates the content type of a Part.
 type PartKind string
 
@@ -120,6 +157,14 @@ type AgentCard struct {
 	// the JSON-RPC and REST handlers live). Required.
 	URL string `json:"url"`
 
+	// PreferredTransport names the protocol binding served at URL.
+	// Forge serves "JSONRPC".
+	PreferredTransport string `json:"preferredTransport,omitempty"`
+
+	// AdditionalInterfaces lists every URL + transport pair the agent
+	// serves, including the preferred one, per A2A 0.3.0 §5.6.
+	AdditionalInterfaces []AgentInterface `json:"additionalInterfaces,omitempty"`
+
 	// Version is the agent's semantic version string (e.g. "0.1.0").
 	// Required by A2A 0.3.0. Forge sources this from forge.yaml's
 	// version field (or the build-time agent.json's version).
@@ -175,11 +220,35 @@ type AgentProvider struct {
 	URL          string `json:"url,omitempty"`
 }
 
+// A2A transport protocol names, as used in AgentCard.PreferredTransport
+// and AgentInterface.Transport.
+const (
+	TransportJSONRPC  = "JSONRPC"
+	TransportGRPC     = "GRPC"
+	TransportHTTPJSON = "HTTP+JSON"
+)
+
+// AgentInterface is one URL + transport pair an agent serves.
+type AgentInterface struct {
+	URL       string `json:"url"`
+	Transport string `json:"transport"`
+}
+
 // AgentCapabilities declares optional A2A features an agent supports.
 type AgentCapabilities struct {
-	Streaming              bool `json:"streaming,omitempty"`
-	PushNotifications      bool `json:"pushNotifications,omitempty"`
-	StateTransitionHistory bool `json:"stateTransitionHistory,omitempty"`
+	Streaming              bool             `json:"streaming,omitempty"`
+	PushNotifications      bool             `json:"pushNotifications,omitempty"`
+	StateTransitionHistory bool             `json:"stateTransitionHistory,omitempty"`
+	Extensions             []AgentExtension `json:"extensions,omitempty"`
+}
+
+// AgentExtension declares a protocol extension the agent supports,
+// identified by URI. Params carries extension-specific configuration.
+type AgentExtension struct {
+	URI         string         `json:"uri"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Params      map[string]any `json:"params,omitempty"`
 }
 
 // Skill describes a discrete capability an agent exposes — the A2A
@@ -211,6 +280,11 @@ type Skill struct {
 	// OutputModes overrides AgentCard.DefaultOutputModes for this skill
 	// only. Optional.
 	OutputModes []string `json:"outputModes,omitempty"`
+
+	// InputSchema is the JSON Schema of the skill's arguments, for
+	// skills backed by a tool. Not part of A2A 0.3.0; clients that
+	// don't know the field ignore it.
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
 }
 
 // SecurityScheme describes one authentication mechanism advertised in
@@ -0,0 +1,182 @@
+package agentspec
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CapabilityCard is the security-relevant surface of one agent build:
+// what it can call, where it can connect, when it acts on its own, and
+// who may invoke it. Two cards are compared with DiffCapabilities so a
+// reviewer sees exactly what a change grants before it ships.
+//
+// List fields hold one display string per capability and are compared
+// as sets; a capability whose details change (a tool gaining a
+// permission, a schedule moving to a new cron) shows up as one item
+// removed and one added.
+type CapabilityCard struct {
+	AgentID string `json:"agent_id"`
+	Version string `json:"version,omitempty"`
+
+	Tools  []string `json:"tools"`
+	Skills []string `json:"skills"`
+
+	EgressProfile string   `json:"egress_profile,omitempty"`
+	EgressMode    string   `json:"egress_mode,omitempty"`
+	EgressDomains []string `json:"egress_domains"`
+
+	Schedules []string `json:"schedules"`
+	Channels  []string `json:"channels"`
+
+	AuthRequired bool     `json:"auth_required"`
+	Auth         []string `json:"auth"`
+	Scopes       []string `json:"scopes"`
+}
+
+// NewCapabilityCard fills the card fields derived from spec: tools
+// (with their declared permissions), A2A skills and identity scopes.
+// Egress, schedules, channels and auth live outside agent.json and are
+// set by the caller.
+func NewCapabilityCard(spec *AgentSpec) *CapabilityCard {
+	c := &CapabilityCard{
+		AgentID:       spec.AgentID,
+		Version:       spec.Version,
+		EgressProfile: spec.EgressProfile,
+		EgressMode:    spec.EgressMode,
+	}
+	for _, t := range spec.Tools {
+		item := t.Name
+		if len(t.Permissions) > 0 {
+			perms := slices.Clone(t.Permissions)
+			slices.Sort(perms)
+			item += " [" + strings.Join(perms, ", ") + "]"
+		}
+		c.Tools = append(c.Tools, item)
+	}
+	if spec.A2A != nil {
+		for _, s := range spec.A2A.Skills {
+			c.Skills = append(c.Skills, s.ID)
+		}
+	}
+	if spec.Identity != nil {
+		c.Scopes = append(c.Scopes, spec.Identity.Scopes...)
+	}
+	return c
+}
+
+// CapabilityChange is a scalar card field whose value differs.
+type CapabilityChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// CapabilitySectionDiff lists what one list field gained and lost.
+type CapabilitySectionDiff struct {
+	Section string   `json:"section"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// CapabilityDiff is the difference between two capability cards.
+type CapabilityDiff struct {
+	OldVersion string                  `json:"old_version,omitempty"`
+	NewVersion string                  `json:"new_version,omitempty"`
+	Changed    []CapabilityChange      `json:"changed,omitempty"`
+	Sections   []CapabilitySectionDiff `json:"sections,omitempty"`
+}
+
+// Empty reports whether the two cards describe the same surface.
+func (d *CapabilityDiff) Empty() bool {
+	return len(d.Changed) == 0 && len(d.Sections) == 0
+}
+
+// Gained counts the capabilities the new build has that the old one
+// did not. Scalar changes count as gains because they may widen access
+// (a permissive egress mode, auth no longer required).
+func (d *CapabilityDiff) Gained() int {
+	n := len(d.Changed)
+	for _, s := range d.Sections {
+		n += len(s.Added)
+	}
+	return n
+}
+
+// DiffCapabilities compares from (typically the deployed build) with
+// to. Sections and changes appear in card field order; items within a
+// section are sorted.
+func DiffCapabilities(from, to *CapabilityCard) *CapabilityDiff {
+	d := &CapabilityDiff{OldVersion: from.Version, NewVersion: to.Version}
+
+	scalar := func(field, o, n string) {
+		if o != n {
+			d.Changed = append(d.Changed, CapabilityChange{Field: field, Old: o, New: n})
+		}
+	}
+	scalar("agent_id", from.AgentID, to.AgentID)
+	scalar("egress_profile", from.EgressProfile, to.EgressProfile)
+	scalar("egress_mode", from.EgressMode, to.EgressMode)
+	scalar("auth_required", fmt.Sprint(from.AuthRequired), fmt.Sprint(to.AuthRequired))
+
+	section := func(name string, o, n []string) {
+		s := CapabilitySectionDiff{Section: name, Added: setMinus(n, o), Removed: setMinus(o, n)}
+		if len(s.Added) > 0 || len(s.Removed) > 0 {
+			d.Sections = append(d.Sections, s)
+		}
+	}
+	section("tools", from.Tools, to.Tools)
+	section("skills", from.Skills, to.Skills)
+	section("egress_domains", from.EgressDomains, to.EgressDomains)
+	section("schedules", from.Schedules, to.Schedules)
+	section("channels", from.Channels, to.Channels)
+	section("auth", from.Auth, to.Auth)
+	section("scopes", from.Scopes, to.Scopes)
+	return d
+}
+
+// setMinus returns the sorted, de-duplicated items of a not in b.
+func setMinus(a, b []string) []string {
+	var out []string
+	for _, v := range a {
+		if !slices.Contains(b, v) && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// FormatText renders d for a terminal or PR comment: "+" marks a
+// gained capability, "-" a dropped one, "~" a changed setting.
+func (d *CapabilityDiff) FormatText() string {
+	if d.Empty() {
+		return "No capability changes.\n"
+	}
+	var b strings.Builder
+	if d.OldVersion != "" || d.NewVersion != "" {
+		fmt.Fprintf(&b, "Capability changes %s -> %s\n", orNone(d.OldVersion), orNone(d.NewVersion))
+	} else {
+		b.WriteString("Capability changes\n")
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "\n%s\n  ~ %s -> %s\n", c.Field, orNone(c.Old), orNone(c.New))
+	}
+	for _, s := range d.Sections {
+		fmt.Fprintf(&b, "\n%s\n", s.Section)
+		for _, v := range s.Added {
+			fmt.Fprintf(&b, "  + %s\n", v)
+		}
+		for _, v := range s.Removed {
+			fmt.Fprintf(&b, "  - %s\n", v)
+		}
+	}
+	return b.String()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
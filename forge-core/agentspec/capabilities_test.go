@@ -0,0 +1,61 @@
+package agentspec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffCapabilities(t *testing.T) {
+	old := NewCapabilityCard(&AgentSpec{
+		AgentID:    "bot",
+		Version:    "1.0.0",
+		EgressMode: "allowlist",
+		Tools:      []ToolSpec{{Name: "web_search"}, {Name: "http_request", Permissions: []string{"net"}}},
+		A2A:        &A2AConfig{Skills: []A2ASkill{{ID: "summarize"}}},
+	})
+	old.EgressDomains = []string{"api.example.com"}
+
+	next := NewCapabilityCard(&AgentSpec{
+		AgentID:    "bot",
+		Version:    "1.1.0",
+		EgressMode: "dev-open",
+		Tools:      []ToolSpec{{Name: "web_search"}, {Name: "http_request", Permissions: []string{"net", "fs"}}},
+		A2A:        &A2AConfig{Skills: []A2ASkill{{ID: "summarize"}, {ID: "deploy"}}},
+	})
+	next.EgressDomains = []string{"api.example.com", "evil.example.net"}
+
+	d := DiffCapabilities(old, next)
+	if d.Empty() {
+		t.Fatal("expected a diff")
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Field != "egress_mode" || d.Changed[0].New != "dev-open" {
+		t.Errorf("Changed = %+v", d.Changed)
+	}
+	got := map[string]CapabilitySectionDiff{}
+	for _, s := range d.Sections {
+		got[s.Section] = s
+	}
+	if s := got["tools"]; len(s.Added) != 1 || s.Added[0] != "http_request [fs, net]" || len(s.Removed) != 1 || s.Removed[0] != "http_request [net]" {
+		t.Errorf("tools = %+v", s)
+	}
+	if s := got["skills"]; len(s.Added) != 1 || s.Added[0] != "deploy" || len(s.Removed) != 0 {
+		t.Errorf("skills = %+v", s)
+	}
+	if s := got["egress_domains"]; len(s.Added) != 1 || s.Added[0] != "evil.example.net" {
+		t.Errorf("egress_domains = %+v", s)
+	}
+	if d.Gained() != 4 {
+		t.Errorf("Gained = %d, want 4", d.Gained())
+	}
+
+	text := d.FormatText()
+	for _, want := range []string{"1.0.0 -> 1.1.0", "  + deploy", "  - http_request [net]", "  ~ allowlist -> dev-open"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatText missing %q:\n%s", want, text)
+		}
+	}
+
+	if same := DiffCapabilities(old, old); !same.Empty() || same.FormatText() != "No capability changes.\n" {
+		t.Errorf("self diff = %+v", same)
+	}
+}
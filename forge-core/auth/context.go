@@ -24,3 +24,23 @@ func IdentityFromContext(ctx context.Context) *Identity {
 	id, _ := ctx.Value(identityKey{}).(*Identity)
 	return id
 }
+
+// runtimeKey marks a request authenticated with the runtime's own
+// loopback token.
+type runtimeKey struct{}
+
+// withFromRuntime marks ctx as carrying a request the runtime's own
+// loopback identity authenticated (see MarkRuntimeInternal).
+func withFromRuntime(ctx context.Context) context.Context {
+	return context.WithValue(ctx, runtimeKey{}, true)
+}
+
+// FromRuntime reports whether the request under ctx authenticated with
+// the runtime's own loopback token, as its channel router and forge ui
+// do. It stays true after the channel on-behalf-of graft has replaced
+// the identity with the channel sender's, so headers those front ends
+// set can be trusted without trusting every caller.
+func FromRuntime(ctx context.Context) bool {
+	ok, _ := ctx.Value(runtimeKey{}).(bool)
+	return ok
+}
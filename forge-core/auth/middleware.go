@@ -9,14 +9,17 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/observability"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
 )
 
 // errorResponse is the JSON body returned for auth failures.
 type errorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
+	Error     string        `json:"error"`
+	Message   string        `json:"message"`
+	Code      a2a.ErrorCode `json:"code"`
+	Retryable bool          `json:"retryable"`
 }
 
 // DefaultSkipPaths returns the default set of public endpoints
@@ -172,7 +175,7 @@ func Middleware(opts MiddlewareOptions) func(http.Handler) http.Handler {
 				span.SetStatus(codes.Error, "missing bearer token")
 				span.End()
 				notifyAuth(opts.OnAuth, r, nil, ErrMissingBearer, kind)
-				writeAuthError(w, "valid bearer token required")
+				writeAuthError(w, "valid bearer token required", ErrMissingBearer)
 				return
 			}
 
@@ -196,7 +199,7 @@ func Middleware(opts MiddlewareOptions) func(http.Handler) http.Handler {
 				span.SetStatus(codes.Error, classifyAuthFailure(err))
 				span.End()
 				notifyAuth(opts.OnAuth, r, nil, err, kind)
-				writeAuthError(w, classifyAuthFailure(err))
+				writeAuthError(w, classifyAuthFailure(err), err)
 				return
 			}
 
@@ -228,9 +231,13 @@ func Middleware(opts MiddlewareOptions) func(http.Handler) http.Handler {
 			// Graft AFTER the auth span/notify (which record the transport
 			// credential truthfully) but BEFORE the handler ctx, so the task
 			// executes as the asserted human.
+			fromRuntime := identity.IsRuntimeInternal()
 			identity = applyChannelOnBehalfOf(identity, r)
 
 			ctx := WithIdentity(r.Context(), identity)
+			if fromRuntime {
+				ctx = withFromRuntime(ctx)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -382,13 +389,16 @@ func classifyAuthFailure(err error) string {
 
 // writeAuthError sends a 401 JSON response. The OnAuth callback is fired
 // separately (via notifyAuth) so the audit-emission path can run with the
-// full Identity / error context, not just a bool.
-func writeAuthError(w http.ResponseWriter, msg string) {
+// full Identity / error context, not just a bool. Only an unreachable
+// provider is worth retrying with the same token.
+func writeAuthError(w http.ResponseWriter, msg string, err error) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusUnauthorized)
 	_ = json.NewEncoder(w).Encode(errorResponse{
-		Error:   "unauthorized",
-		Message: msg,
+		Error:     "unauthorized",
+		Message:   msg,
+		Code:      a2a.ErrorAuthFailed,
+		Retryable: errors.Is(err, ErrProviderUnavailable),
 	})
 }
 
@@ -302,8 +302,10 @@ func TestMiddleware_ChannelOnBehalfOf(t *testing.T) {
 			SkipPaths: DefaultSkipPaths(),
 		}
 		var gotID *Identity
+		var fromRuntime bool
 		handler := Middleware(opts)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
 			gotID = IdentityFromContext(r.Context())
+			fromRuntime = FromRuntime(r.Context())
 		}))
 		req := httptest.NewRequest("POST", "/", nil)
 		req.Header.Set("Authorization", "Bearer "+token)
@@ -314,6 +316,11 @@ func TestMiddleware_ChannelOnBehalfOf(t *testing.T) {
 		if gotID == nil {
 			t.Fatal("identity not attached")
 		}
+		// Only the runtime's token may vouch for its front ends' headers,
+		// and the graft must not drop that.
+		if fromRuntime != wantGraft {
+			t.Errorf("FromRuntime = %v, want %v", fromRuntime, wantGraft)
+		}
 		if wantGraft {
 			if gotID.Email != "mk@example.com" || gotID.UserID != "U0456" || gotID.Source != "channel:slack" {
 				t.Errorf("graft not applied: %+v", gotID)
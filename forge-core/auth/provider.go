@@ -49,6 +49,10 @@ type Identity struct {
 	OrgID       string   `json:"org_id,omitempty"`
 	WorkspaceID string   `json:"workspace_id,omitempty"`
 	Groups      []string `json:"groups,omitempty"`
+	// TenantID is the forge.yaml tenant the caller acts as, set when
+	// they authenticated with a tenant API key. Empty for callers
+	// outside any tenant (operators, channel adapters).
+	TenantID string `json:"tenant_id,omitempty"`
 
 	// runtimeInternal marks an identity minted IN-PROCESS by the runtime's own
 	// loopback provider (the channel-adapter token). It is the trust anchor
@@ -38,12 +38,119 @@ var providers = []Provider{
 		APIKeyEnvVar: "GEMINI_API_KEY",
 		DefaultModel: "gemini-2.5-flash",
 	},
+	{
+		ID:           "mistral",
+		Label:        "Mistral",
+		Description:  "Mistral Large, Medium, Small",
+		Icon:         "🟧",
+		NeedsAPIKey:  true,
+		APIKeyEnvVar: "MISTRAL_API_KEY",
+		DefaultModel: "mistral-large-latest",
+		Models: []Model{
+			{Label: "Mistral Large", ModelID: "mistral-large-latest"},
+			{Label: "Mistral Medium", ModelID: "mistral-medium-latest"},
+			{Label: "Mistral Small", ModelID: "mistral-small-latest"},
+		},
+	},
+	{
+		ID:           "cohere",
+		Label:        "Cohere",
+		Description:  "Command R+, Command R",
+		Icon:         "🟣",
+		NeedsAPIKey:  true,
+		APIKeyEnvVar: "COHERE_API_KEY",
+		DefaultModel: "command-r-plus",
+		Models: []Model{
+			{Label: "Command R+", ModelID: "command-r-plus"},
+			{Label: "Command R", ModelID: "command-r"},
+		},
+	},
+	{
+		ID:           "groq",
+		Label:        "Groq",
+		Description:  "Llama, Qwen, GPT-OSS on LPU inference",
+		Icon:         "⚡",
+		NeedsAPIKey:  true,
+		APIKeyEnvVar: "GROQ_API_KEY",
+		DefaultModel: "llama-3.3-70b-versatile",
+		Models: []Model{
+			{Label: "Llama 3.3 70B Versatile", ModelID: "llama-3.3-70b-versatile"},
+			{Label: "Llama 3.1 8B Instant", ModelID: "llama-3.1-8b-instant"},
+			{Label: "GPT-OSS 120B", ModelID: "openai/gpt-oss-120b"},
+			{Label: "Qwen3 32B", ModelID: "qwen/qwen3-32b"},
+		},
+	},
+	{
+		ID:           "together",
+		Label:        "Together AI",
+		Description:  "Open-weight Llama, Qwen, DeepSeek models",
+		Icon:         "🟦",
+		NeedsAPIKey:  true,
+		APIKeyEnvVar: "TOGETHER_API_KEY",
+		DefaultModel: "meta-llama/Llama-3.3-70B-Instruct-Turbo",
+		Models: []Model{
+			{Label: "Llama 3.3 70B Instruct Turbo", ModelID: "meta-llama/Llama-3.3-70B-Instruct-Turbo"},
+			{Label: "Qwen 2.5 72B Instruct Turbo", ModelID: "Qwen/Qwen2.5-72B-Instruct-Turbo"},
+			{Label: "DeepSeek V3", ModelID: "deepseek-ai/DeepSeek-V3"},
+			{Label: "Kimi K2 Instruct", ModelID: "moonshotai/Kimi-K2-Instruct"},
+		},
+	},
+	{
+		ID:           "openrouter",
+		Label:        "OpenRouter",
+		Description:  "One key for hundreds of hosted models",
+		Icon:         "🔀",
+		NeedsAPIKey:  true,
+		APIKeyEnvVar: "OPENROUTER_API_KEY",
+		DefaultModel: "openai/gpt-4o",
+		Models: []Model{
+			{Label: "GPT-4o", ModelID: "openai/gpt-4o"},
+			{Label: "Claude Sonnet 4", ModelID: "anthropic/claude-sonnet-4"},
+			{Label: "Gemini 2.5 Flash", ModelID: "google/gemini-2.5-flash"},
+			{Label: "Llama 3.3 70B Instruct", ModelID: "meta-llama/llama-3.3-70b-instruct"},
+		},
+	},
+	{
+		ID:           "xai",
+		Label:        "xAI",
+		Description:  "Grok models with configurable reasoning",
+		Icon:         "⚫",
+		NeedsAPIKey:  true,
+		APIKeyEnvVar: "XAI_API_KEY",
+		DefaultModel: "grok-4",
+		Models: []Model{
+			{Label: "Grok 4", ModelID: "grok-4"},
+			{Label: "Grok 3", ModelID: "grok-3"},
+			{Label: "Grok 3 Mini (reasoning effort)", ModelID: "grok-3-mini"},
+		},
+	},
+	{
+		ID:           "deepseek",
+		Label:        "DeepSeek",
+		Description:  "DeepSeek V3 chat and R1 reasoner",
+		Icon:         "🐋",
+		NeedsAPIKey:  true,
+		APIKeyEnvVar: "DEEPSEEK_API_KEY",
+		DefaultModel: "deepseek-chat",
+		Models: []Model{
+			{Label: "DeepSeek Chat (V3)", ModelID: "deepseek-chat"},
+			{Label: "DeepSeek Reasoner (R1)", ModelID: "deepseek-reasoner"},
+		},
+	},
 	{
 		ID:           "ollama",
 		Label:        "Ollama (local)",
 		Description:  "Run models locally, no API key needed",
 		Icon:         "🦙",
 		DefaultModel: "llama3",
+		// Recommended for agents: each supports tool calling and runs on
+		// a laptop. The wizard offers to pull them and lists what the
+		// local daemon already has alongside.
+		Models: []Model{
+			{Label: "Qwen 2.5 7B", ModelID: "qwen2.5:7b"},
+			{Label: "Llama 3.1 8B", ModelID: "llama3.1:8b"},
+			{Label: "Mistral Nemo 12B", ModelID: "mistral-nemo:12b"},
+		},
 	},
 	{
 		ID:          "custom",
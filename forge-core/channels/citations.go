@@ -0,0 +1,39 @@
+package channels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// CitationFootnotes renders a response's citations as a markdown source
+// list to append to its text, one "[n] title" line per source with web
+// sources linked. It returns "" when there are none.
+func CitationFootnotes(cits []a2a.Citation) string {
+	if len(cits) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("*Sources:*")
+	for _, c := range cits {
+		title := c.Title
+		if title == "" {
+			title = c.URL
+		}
+		if title == "" {
+			title = c.Source
+		}
+		var line string
+		switch {
+		case c.URL != "":
+			line = fmt.Sprintf("[%s](%s)", title, c.URL)
+		case c.Location != "":
+			line = fmt.Sprintf("%s (%s, %s)", title, c.Source, c.Location)
+		default:
+			line = title
+		}
+		fmt.Fprintf(&b, "\n[%d] %s", c.ID, line)
+	}
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package channels
+
+import (
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func TestCitationFootnotes(t *testing.T) {
+	if got := CitationFootnotes(nil); got != "" {
+		t.Errorf("no citations = %q", got)
+	}
+	got := CitationFootnotes([]a2a.Citation{
+		{ID: 1, Title: "Pricing", URL: "https://example.com/pricing"},
+		{ID: 3, Title: "Setup Guide", Source: "kb:docs/setup.md", Location: "lines 1-12"},
+		{ID: 4, URL: "https://example.com/faq"},
+	})
+	want := "*Sources:*\n" +
+		"[1] [Pricing](https://example.com/pricing)\n" +
+		"[3] Setup Guide (kb:docs/setup.md, lines 1-12)\n" +
+		"[4] [https://example.com/faq](https://example.com/faq)"
+	if got != want {
+		t.Errorf("CitationFootnotes =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package channels
+
+import "strings"
+
+// FormatProfile describes which markdown a channel renders once its
+// adapter has converted the agent's reply, so the agent can be steered
+// toward output that survives the conversion.
+type FormatProfile struct {
+	// Guidance is the channel's line in the system prompt.
+	Guidance string
+	// Tables reports whether markdown tables render as tables. Adapters
+	// without them send tables as aligned monospace blocks.
+	Tables bool
+	// Headings reports whether headings render as headings rather than
+	// bold lines.
+	Headings bool
+}
+
+var formatProfiles = map[string]FormatProfile{
+	"slack": {
+		Guidance: "Slack renders bold, italics, strikethrough, links, bullet lists, block quotes and code blocks. Headings arrive as bold lines and tables as monospaced blocks; nested lists are flattened.",
+	},
+	"telegram": {
+		Guidance: "Telegram renders bold, italics, strikethrough, links, block quotes and code blocks. Headings arrive as bold lines and tables as monospaced blocks; messages over 4096 characters are split or sent as a file, so keep replies concise.",
+	},
+	"msteams": {
+		Guidance: "Teams renders headings, bold, italics, links, lists, block quotes and code blocks. Tables arrive as plain text.",
+		Headings: true,
+	},
+}
+
+// FormatProfileFor returns the format profile of a channel adapter. ok
+// is false for adapters without one.
+func FormatProfileFor(channel string) (FormatProfile, bool) {
+	p, ok := formatProfiles[channel]
+	return p, ok
+}
+
+// OutputFormatHint maps a skill's declared output format (its
+// "**Output format:**" line) onto a channel. It returns guidance for
+// the parts of that format the channel cannot show as declared, or ""
+// when the format renders as is or the channel has no profile.
+func OutputFormatHint(channel, outputFormat string) string {
+	p, ok := formatProfiles[channel]
+	if !ok || outputFormat == "" {
+		return ""
+	}
+	f := strings.ToLower(outputFormat)
+	var hints []string
+	if !p.Tables && strings.Contains(f, "table") {
+		hints = append(hints, "Tables do not render as tables: keep them to a few narrow columns, or use a bulleted list when rows are wide.")
+	}
+	if !p.Headings && (strings.Contains(f, "heading") || strings.Contains(f, "section")) {
+		hints = append(hints, "Headings are sent as bold lines: keep section titles short.")
+	}
+	for _, kw := range []string{"json", "yaml", "csv", "code"} {
+		if strings.Contains(f, kw) {
+			hints = append(hints, "Wrap structured output in a fenced code block so it is sent verbatim.")
+			break
+		}
+	}
+	return strings.Join(hints, " ")
+}
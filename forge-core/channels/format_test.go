@@ -0,0 +1,43 @@
+package channels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatProfileFor(t *testing.T) {
+	for _, name := range []string{"slack", "telegram", "msteams"} {
+		if p, ok := FormatProfileFor(name); !ok || p.Guidance == "" {
+			t.Errorf("FormatProfileFor(%q) = %+v, %v; want a profile", name, p, ok)
+		}
+	}
+	if _, ok := FormatProfileFor("irc"); ok {
+		t.Error("unknown adapter should have no profile")
+	}
+}
+
+func TestOutputFormatHint(t *testing.T) {
+	tests := []struct {
+		channel, format, want string
+	}{
+		{"slack", "Markdown table of pods", "narrow columns"},
+		{"telegram", "Report with sections", "bold lines"},
+		{"msteams", "Report with sections", ""},
+		{"slack", "JSON object", "fenced code block"},
+		{"slack", "one short sentence", ""},
+		{"irc", "table", ""},
+		{"slack", "", ""},
+	}
+	for _, tt := range tests {
+		got := OutputFormatHint(tt.channel, tt.format)
+		if tt.want == "" {
+			if got != "" {
+				t.Errorf("OutputFormatHint(%q, %q) = %q, want none", tt.channel, tt.format, got)
+			}
+			continue
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("OutputFormatHint(%q, %q) = %q, want it to mention %q", tt.channel, tt.format, got, tt.want)
+		}
+	}
+}
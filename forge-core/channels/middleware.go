@@ -0,0 +1,30 @@
+package channels
+
+import "errors"
+
+// Middleware wraps an EventHandler with a policy applied to every
+// inbound event before it reaches the agent — rate limits, duplicate
+// detection, sender filters. A middleware either passes the event on,
+// answers it itself, or refuses it with ErrEventDropped.
+type Middleware func(next EventHandler) EventHandler
+
+// Chain wraps h in mws. The first middleware sees each event first.
+func Chain(h EventHandler, mws ...Middleware) EventHandler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// ErrEventDropped is returned by an EventHandler for an event a policy
+// refused without a reply, such as a message from a denied sender, or
+// one passed to human operators who answer it themselves. Adapters drop
+// the event silently.
+var ErrEventDropped = errors.New("channel event dropped by policy")
+
+// IsDropped reports whether err means the event gets no reply: a
+// duplicate (ErrDuplicateEvent) or an event a policy dropped
+// (ErrEventDropped).
+func IsDropped(err error) bool {
+	return errors.Is(err, ErrDuplicateEvent) || errors.Is(err, ErrEventDropped)
+}
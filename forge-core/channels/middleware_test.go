@@ -0,0 +1,48 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next EventHandler) EventHandler {
+			return func(ctx context.Context, event *ChannelEvent) (*a2a.Message, error) {
+				order = append(order, name)
+				return next(ctx, event)
+			}
+		}
+	}
+	h := Chain(func(context.Context, *ChannelEvent) (*a2a.Message, error) {
+		order = append(order, "handler")
+		return nil, nil
+	}, mw("first"), mw("second"))
+	if _, err := h(context.Background(), &ChannelEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "first,second,handler" {
+		t.Errorf("order = %s", got)
+	}
+}
+
+func TestIsDropped(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{ErrDuplicateEvent, true},
+		{fmt.Errorf("queue: %w", ErrEventDropped), true},
+		{fmt.Errorf("agent unreachable"), false},
+		{nil, false},
+	} {
+		if got := IsDropped(tc.err); got != tc.want {
+			t.Errorf("IsDropped(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
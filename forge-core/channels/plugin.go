@@ -5,9 +5,13 @@ package channels
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/i18n"
 )
 
 // ChannelPlugin is the interface every channel adapter must implement.
@@ -32,6 +36,12 @@ type ChannelPlugin interface {
 // to the A2A server and returns the agent's response.
 type EventHandler func(ctx context.Context, event *ChannelEvent) (*a2a.Message, error)
 
+// ErrDuplicateEvent is returned by an EventHandler for a message it has
+// already accepted — typically a platform retry of a delivery the agent
+// is still working on or has answered. Adapters drop the event silently:
+// the original delivery owns the reply.
+var ErrDuplicateEvent = errors.New("duplicate channel event")
+
 // ChannelConfig holds per-adapter configuration loaded from YAML.
 type ChannelConfig struct {
 	Adapter     string            `yaml:"adapter"`
@@ -52,19 +62,62 @@ type ChannelEvent struct {
 	// tools key consent + per-user tokens on the HUMAN who sent the message,
 	// not the runtime's loopback identity (field-hit 2026-07-21: a consent DM
 	// addressed to "forge-internal"). Best-effort — empty when unresolvable.
-	UserEmail   string          `json:"user_email,omitempty"`
-	ThreadID    string          `json:"thread_id,omitempty"`
-	MessageID   string          `json:"message_id,omitempty"` // per-message ID for reply targeting
+	UserEmail string `json:"user_email,omitempty"`
+	ThreadID  string `json:"thread_id,omitempty"`
+	MessageID string `json:"message_id,omitempty"` // per-message ID for reply targeting
+	// EventID is the platform's delivery ID (Slack event_id, Telegram
+	// update_id), when the platform has one distinct from MessageID. It is forwarded to the A2A server
+	// so audit events of the resulting task can be correlated with it.
+	EventID     string          `json:"event_id,omitempty"`
 	Message     string          `json:"message"`
 	Attachments []Attachment    `json:"attachments,omitempty"`
 	Raw         json.RawMessage `json:"raw,omitempty"`
 }
 
+// SessionTaskID returns the conversation key of a channel message, which
+// is the A2A task ID it is sent under and so keys the conversation
+// history the agent sees. Messages in one thread (Slack thread_ts,
+// Telegram reply chain) share a session; messages outside a thread share
+// one per user and chat. A router with a session store maps the key to
+// the conversation's current session instead.
+func SessionTaskID(event *ChannelEvent) string {
+	if event.ThreadID != "" {
+		return fmt.Sprintf("%s-%s-%s", event.Channel, event.WorkspaceID, event.ThreadID)
+	}
+	return fmt.Sprintf("%s-%s-%s", event.Channel, event.WorkspaceID, event.UserID)
+}
+
 // Attachment represents a file or media item attached to a channel message.
 type Attachment struct {
 	Name     string `json:"name,omitempty"`
 	MimeType string `json:"mime_type,omitempty"`
 	URL      string `json:"url,omitempty"`
+	// ID is the platform's file ID, for adapters that download by ID
+	// rather than URL (Telegram's file_id).
+	ID   string `json:"id,omitempty"`
+	Size int64  `json:"size,omitempty"` // as the platform reports it; 0 when unknown
+	// Data is the content, when the adapter downloaded it — as it does
+	// for voice messages (audio/* MIME types), which the router
+	// transcribes. An adapter that hands audio in must also accept an
+	// audio file part in the reply.
+	Data []byte `json:"data,omitempty"`
+}
+
+// IsAudio reports whether the attachment is recorded speech or other
+// audio.
+func (a Attachment) IsAudio() bool {
+	return strings.HasPrefix(a.MimeType, "audio/")
+}
+
+// AttachmentFetcher is an OPTIONAL capability. An adapter whose messages
+// carry files it must download with its own credentials implements it
+// (Slack's bot token, Telegram's getFile). The router fetches the
+// attachments forge.yaml `attachments:` admits; adapters that don't
+// implement it have their attachments dropped.
+type AttachmentFetcher interface {
+	// FetchAttachment downloads a, failing when it is larger than
+	// maxBytes. It downloads only from the platform's own file hosts.
+	FetchAttachment(ctx context.Context, a Attachment, maxBytes int64) ([]byte, error)
 }
 
 // --- Interactive human-approval (DEFER / R4c, #211) delivery -----------------
@@ -118,6 +171,14 @@ type LoggerAware interface {
 	SetLogger(Logger)
 }
 
+// Localized is an OPTIONAL capability: an adapter that writes messages of
+// its own (schedule confirmations, button labels) implements it to write
+// them from the agent's catalogs. The runtime wires it at startup;
+// adapters without it, or not yet wired, write English.
+type Localized interface {
+	SetLocalizer(l *i18n.Localizer)
+}
+
 // ApprovalDeliverer is an OPTIONAL capability. A channel adapter that can post
 // an interactive approval request AND receive the approver's response
 // implements it (Slack via Block Kit over Socket Mode, #310). Adapters that
@@ -178,3 +239,65 @@ type ConsentDeliverer interface {
 	// cancels. The runtime sets this once at startup; may be a no-op.
 	SetConsentCanceler(c ConsentCanceler)
 }
+
+// --- Task progress ------------------------------------------------------------
+
+// ProgressUpdate is a progress update of a running task — a tool call
+// starting or finishing.
+type ProgressUpdate struct {
+	Phase   string // "tool_start" | "tool_end"
+	Tool    string
+	Message string
+}
+
+// ProgressSubscriber calls fn with every progress update of taskID until
+// cancel is called. Wired via ProgressAware.SetProgressSubscriber.
+type ProgressSubscriber func(taskID string, fn func(ProgressUpdate)) (cancel func())
+
+// ProgressAware is an OPTIONAL capability. An adapter that shows the
+// agent's progress while it handles a message (Slack's thinking message)
+// implements it. The runtime wires it when the adapter runs in-process
+// with the agent (`forge run --with`); adapters started with
+// `forge channel serve` run without progress.
+type ProgressAware interface {
+	SetProgressSubscriber(s ProgressSubscriber)
+}
+
+// --- Schedule management -----------------------------------------------------
+
+// ScheduleNotice is a schedule event delivered to the schedule's channel
+// together with controls to manage the schedule: the confirmation of a
+// schedule the agent just set, or the result of a run.
+type ScheduleNotice struct {
+	ScheduleID string
+	Cron       string
+	Task       string
+	Enabled    bool
+	Target     string       // the schedule's channel_target
+	Response   *a2a.Message // the run's result; nil for a confirmation
+	Language   string       // language to write the notice in; see Localized
+}
+
+// ScheduleAction is a management action a user took on a delivered
+// ScheduleNotice.
+type ScheduleAction struct {
+	ScheduleID string
+	Action     string // "pause" | "resume" | "delete"
+	Actor      string // who acted (platform user id / name), for audit
+}
+
+// ScheduleManager applies a ScheduleAction to the agent's schedules.
+// Wired via ScheduleDeliverer.SetScheduleManager at startup.
+type ScheduleManager func(ctx context.Context, a ScheduleAction) error
+
+// ScheduleDeliverer is an OPTIONAL capability. An adapter that can attach
+// schedule controls to a message AND receive the user's choice implements
+// it (Telegram inline keyboards). Adapters that don't implement it get a
+// schedule's run results through SendResponse and no confirmations.
+type ScheduleDeliverer interface {
+	// DeliverSchedule posts the notice, with its controls, to n.Target.
+	DeliverSchedule(ctx context.Context, n ScheduleNotice) error
+	// SetScheduleManager wires the callback the adapter invokes when a
+	// user acts on a control. The runtime sets this once at startup.
+	SetScheduleManager(m ScheduleManager)
+}
@@ -0,0 +1,14 @@
+package channels
+
+import "testing"
+
+func TestSessionTaskID(t *testing.T) {
+	threaded := &ChannelEvent{Channel: "slack", WorkspaceID: "C1", UserID: "U1", ThreadID: "1700.1"}
+	if got := SessionTaskID(threaded); got != "slack-C1-1700.1" {
+		t.Errorf("threaded = %q", got)
+	}
+	dm := &ChannelEvent{Channel: "telegram", WorkspaceID: "42", UserID: "7"}
+	if got := SessionTaskID(dm); got != "telegram-42-7" {
+		t.Errorf("unthreaded = %q", got)
+	}
+}
@@ -0,0 +1,90 @@
+package cluster
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Logger is the minimal logging interface used by the elector.
+type Logger interface {
+	Info(msg string, fields map[string]any)
+	Warn(msg string, fields map[string]any)
+	Error(msg string, fields map[string]any)
+}
+
+// Elector elects one leader among the replicas sharing a lock backend:
+// whichever holds key. The leader renews the lock every ttl/3; a
+// follower retries at the same pace and takes over within ttl of the
+// leader stopping or dying.
+type Elector struct {
+	locker Locker
+	key    string
+	ttl    time.Duration
+	logger Logger
+
+	leader atomic.Bool
+}
+
+// NewElector returns an Elector campaigning for key. A ttl of zero
+// means DefaultTTL.
+func NewElector(locker Locker, key string, ttl time.Duration, logger Logger) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Elector{locker: locker, key: key, ttl: ttl, logger: logger}
+}
+
+// IsLeader reports whether this replica currently holds leadership.
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run campaigns until ctx is cancelled, then steps down and releases
+// the lock so another replica can take over without waiting for it to
+// expire.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	for {
+		e.campaign(ctx)
+		select {
+		case <-ctx.Done():
+			e.setLeader(false, nil)
+			unlockCtx, cancel := context.WithTimeout(context.Background(), e.ttl/3)
+			_ = e.locker.Unlock(unlockCtx, e.key)
+			cancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign acquires or renews the lock. A backend error steps down: the
+// lock may expire before the backend is reachable again, and two
+// leaders are worse than none for a few seconds.
+func (e *Elector) campaign(ctx context.Context) {
+	callCtx, cancel := context.WithTimeout(ctx, e.ttl/3)
+	held, err := e.locker.TryLock(callCtx, e.key, e.ttl)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+	e.setLeader(held && err == nil, err)
+}
+
+func (e *Elector) setLeader(leader bool, err error) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+	fields := map[string]any{"key": e.key}
+	switch {
+	case leader:
+		e.logger.Info("acquired leadership", fields)
+	case err != nil:
+		fields["error"] = err.Error()
+		e.logger.Error("lost leadership: lock backend unavailable", fields)
+	default:
+		e.logger.Warn("lost leadership", fields)
+	}
+}
@@ -0,0 +1,51 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, map[string]any)  {}
+func (nopLogger) Warn(string, map[string]any)  {}
+func (nopLogger) Error(string, map[string]any) {}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestElector_OneLeaderAndFailover(t *testing.T) {
+	table := NewMemoryLocks()
+	ttl := 60 * time.Millisecond
+	a := NewElector(table.Locker("a"), "forge:agent:scheduler", ttl, nopLogger{})
+	b := NewElector(table.Locker("b"), "forge:agent:scheduler", ttl, nopLogger{})
+
+	ctxA, stopA := context.WithCancel(context.Background())
+	doneA := make(chan struct{})
+	go func() { a.Run(ctxA); close(doneA) }()
+	waitFor(t, "a to lead", a.IsLeader)
+
+	ctxB, stopB := context.WithCancel(context.Background())
+	defer stopB()
+	go b.Run(ctxB)
+	time.Sleep(3 * ttl)
+	if b.IsLeader() {
+		t.Fatal("two leaders at once")
+	}
+
+	stopA()
+	<-doneA
+	if a.IsLeader() {
+		t.Error("a still leader after Run returned")
+	}
+	waitFor(t, "b to take over", b.IsLeader)
+}
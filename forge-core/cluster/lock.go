@@ -0,0 +1,134 @@
+// Package cluster coordinates the replicas of one agent: expiring
+// distributed locks, and a leader election built on them. With it, a
+// multi-replica deploy fires each schedule once and never runs the same
+// task (and writes its session) on two replicas at once.
+//
+// A single-replica deploy needs none of this; the runner leaves the
+// Locker nil and every caller treats nil as "no coordination".
+package cluster
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the lock lifetime when cluster.lock_ttl is unset. A
+// crashed replica's locks free up after at most this long.
+const DefaultTTL = 15 * time.Second
+
+// Locker grants exclusive, expiring locks shared by every replica that
+// opens the same backend. A lock belongs to the Locker that acquired it
+// (its owner ID); the TTL bounds how long a replica that died can keep
+// holding it.
+type Locker interface {
+	// TryLock acquires key for ttl, or extends it when this Locker
+	// already holds it. It reports false, with a nil error, when
+	// another owner holds the key.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// Unlock releases key if this Locker holds it. Releasing a lock that
+	// expired, or passed to another owner after expiring, is a no-op.
+	Unlock(ctx context.Context, key string) error
+
+	// Close releases the backend connection. Locks still held expire
+	// after their TTL.
+	Close() error
+}
+
+// Open returns the Locker for rawURL:
+//
+//   - ""                               → nil: one replica, no coordination
+//   - "memory://"                      → in-process locks (tests, dev)
+//   - "redis://[:pass@]host:port[/db]" → Redis (SET NX PX + Lua)
+//   - "rediss://…"                     → Redis over TLS
+//
+// owner identifies this replica; OwnerID is the usual choice.
+func Open(rawURL, owner string) (Locker, error) {
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("cluster lock url: %w", err)
+	}
+	switch u.Scheme {
+	case "memory":
+		return NewMemoryLocks().Locker(owner), nil
+	case "redis", "rediss":
+		return NewRedisLocker(u, owner)
+	default:
+		return nil, fmt.Errorf("cluster lock url: unsupported scheme %q (want redis, rediss or memory)", u.Scheme)
+	}
+}
+
+// OwnerID returns an identifier unique to this process: the hostname
+// (the pod name in Kubernetes), the PID and a random suffix, so a
+// restarted replica never inherits its predecessor's locks.
+func OwnerID() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "forge"
+	}
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+// MemoryLocks is an in-process lock table. The Lockers it hands out
+// share it the way replicas share one Redis, so tests can stand up
+// several "replicas" in one process.
+type MemoryLocks struct {
+	mu    sync.Mutex
+	locks map[string]memoryLock
+	now   func() time.Time
+}
+
+type memoryLock struct {
+	owner   string
+	expires time.Time
+}
+
+// NewMemoryLocks returns an empty lock table.
+func NewMemoryLocks() *MemoryLocks {
+	return &MemoryLocks{locks: make(map[string]memoryLock), now: time.Now}
+}
+
+// Locker returns a Locker on this table that acquires locks as owner.
+func (m *MemoryLocks) Locker(owner string) Locker {
+	return &memoryLocker{table: m, owner: owner}
+}
+
+type memoryLocker struct {
+	table *MemoryLocks
+	owner string
+}
+
+func (l *memoryLocker) TryLock(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	m := l.table
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if cur, ok := m.locks[key]; ok && cur.owner != l.owner && now.Before(cur.expires) {
+		return false, nil
+	}
+	m.locks[key] = memoryLock{owner: l.owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (l *memoryLocker) Unlock(_ context.Context, key string) error {
+	m := l.table
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cur, ok := m.locks[key]; ok && cur.owner == l.owner {
+		delete(m.locks, key)
+	}
+	return nil
+}
+
+func (l *memoryLocker) Close() error { return nil }
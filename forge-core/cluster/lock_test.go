@@ -0,0 +1,190 @@
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryLocker_ExclusiveUntilExpiry(t *testing.T) {
+	ctx := context.Background()
+	table := NewMemoryLocks()
+	now := time.Unix(1000, 0)
+	table.now = func() time.Time { return now }
+	a, b := table.Locker("a"), table.Locker("b")
+
+	if ok, _ := a.TryLock(ctx, "k", time.Second); !ok {
+		t.Fatal("a could not take a free lock")
+	}
+	if ok, _ := b.TryLock(ctx, "k", time.Second); ok {
+		t.Fatal("b took a lock a holds")
+	}
+	if ok, _ := a.TryLock(ctx, "k", time.Second); !ok {
+		t.Fatal("a could not extend its own lock")
+	}
+
+	now = now.Add(2 * time.Second)
+	if ok, _ := b.TryLock(ctx, "k", time.Second); !ok {
+		t.Fatal("b could not take an expired lock")
+	}
+	_ = a.Unlock(ctx, "k")
+	if ok, _ := a.TryLock(ctx, "k", time.Second); ok {
+		t.Fatal("a released b's lock")
+	}
+	_ = b.Unlock(ctx, "k")
+	if ok, _ := a.TryLock(ctx, "k", time.Second); !ok {
+		t.Fatal("lock not free after b released it")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if l, err := Open("", "x"); l != nil || err != nil {
+		t.Errorf("Open(\"\") = %v, %v; want nil, nil", l, err)
+	}
+	if _, err := Open("memory://", "x"); err != nil {
+		t.Errorf("Open(memory://): %v", err)
+	}
+	if _, err := Open("postgres://db/forge", "x"); err == nil {
+		t.Error("Open(postgres://) accepted an unsupported scheme")
+	}
+	if _, err := Open("redis://localhost/abc", "x"); err == nil {
+		t.Error("Open accepted a non-numeric redis database")
+	}
+}
+
+// fakeRedis answers the two lock scripts, AUTH and SELECT over RESP,
+// enough to exercise RedisLocker without a server.
+type fakeRedis struct {
+	mu       sync.Mutex
+	locks    map[string]string
+	commands []string
+}
+
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	f := &fakeRedis{locks: map[string]string{}}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, lis.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	for {
+		reply, err := readReply(rd)
+		if err != nil {
+			return
+		}
+		args := reply.([]any)
+		cmd := args[0].(string)
+		f.mu.Lock()
+		f.commands = append(f.commands, cmd)
+		var out string
+		switch cmd {
+		case "AUTH":
+			if args[len(args)-1] == "secret" {
+				out = "+OK\r\n"
+			} else {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case "SELECT":
+			out = "+OK\r\n"
+		case "EVAL":
+			key, owner := args[3].(string), args[4].(string)
+			cur, held := f.locks[key]
+			switch {
+			case args[1] == tryLockScript && (!held || cur == owner):
+				f.locks[key] = owner
+				out = ":1\r\n"
+			case args[1] == unlockScript && cur == owner:
+				delete(f.locks, key)
+				out = ":1\r\n"
+			default:
+				out = ":0\r\n"
+			}
+		default:
+			out = "-ERR unknown command '" + cmd + "'\r\n"
+		}
+		f.mu.Unlock()
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func TestRedisLocker(t *testing.T) {
+	f, addr := startFakeRedis(t)
+	ctx := context.Background()
+	open := func(owner string) Locker {
+		l, err := Open("redis://:secret@"+addr+"/2", owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = l.Close() })
+		return l
+	}
+	a, b := open("a"), open("b")
+
+	if ok, err := a.TryLock(ctx, "forge:agent:scheduler", time.Second); !ok || err != nil {
+		t.Fatalf("a.TryLock = %v, %v", ok, err)
+	}
+	if ok, err := b.TryLock(ctx, "forge:agent:scheduler", time.Second); ok || err != nil {
+		t.Fatalf("b.TryLock on a held lock = %v, %v", ok, err)
+	}
+	if err := a.Unlock(ctx, "forge:agent:scheduler"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := b.TryLock(ctx, "forge:agent:scheduler", time.Second); !ok {
+		t.Fatal("b could not take the released lock")
+	}
+
+	f.mu.Lock()
+	got := strings.Join(f.commands[:2], " ")
+	f.mu.Unlock()
+	if got != "AUTH SELECT" {
+		t.Errorf("connection setup = %q, want AUTH SELECT", got)
+	}
+}
+
+func TestRedisLocker_AuthFailure(t *testing.T) {
+	_, addr := startFakeRedis(t)
+	u, _ := url.Parse("redis://:wrong@" + addr)
+	l, err := NewRedisLocker(u, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if _, err := l.TryLock(context.Background(), "k", time.Second); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("TryLock with a bad password = %v, want WRONGPASS", err)
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	in := "*3\r\n$5\r\nhello\r\n:42\r\n$-1\r\n"
+	reply, err := readReply(bufio.NewReader(strings.NewReader(in)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr := reply.([]any)
+	if arr[0] != "hello" || arr[1] != int64(42) || arr[2] != nil {
+		t.Errorf("readReply = %#v", arr)
+	}
+}
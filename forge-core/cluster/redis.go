@@ -0,0 +1,232 @@
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redisTimeout bounds one command round trip when ctx has no deadline.
+const redisTimeout = 5 * time.Second
+
+// tryLockScript acquires KEYS[1] for owner ARGV[1] for ARGV[2] ms, or
+// extends it when the owner already holds it. Returns 1 when held.
+const tryLockScript = `local v = redis.call('GET', KEYS[1])
+if v == false then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+  return 1
+end
+if v == ARGV[1] then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+  return 1
+end
+return 0`
+
+// unlockScript deletes KEYS[1] only while owner ARGV[1] holds it, so a
+// replica whose lock expired cannot release its successor's.
+const unlockScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+// RedisLocker is a Locker backed by one Redis instance. Each lock is a
+// key holding the owner ID with a PX expiry; acquire-or-extend and
+// owner-checked release run as Lua scripts so they are atomic. It
+// speaks RESP over a single connection, redialled after any error, and
+// needs no client library.
+type RedisLocker struct {
+	addr     string
+	tls      bool
+	username string
+	password string
+	db       int
+	owner    string
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// NewRedisLocker returns a RedisLocker for a redis:// or rediss:// URL.
+// It connects lazily, on the first lock operation.
+func NewRedisLocker(u *url.URL, owner string) (*RedisLocker, error) {
+	host := u.Host
+	if host == "" {
+		return nil, fmt.Errorf("cluster lock url: missing host")
+	}
+	if u.Port() == "" {
+		host = net.JoinHostPort(host, "6379")
+	}
+	l := &RedisLocker{addr: host, tls: u.Scheme == "rediss", owner: owner}
+	if u.User != nil {
+		l.username = u.User.Username()
+		l.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("cluster lock url: invalid database %q", db)
+		}
+		l.db = n
+	}
+	return l, nil
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	reply, err := l.do(ctx, "EVAL", tryLockScript, "1", key, l.owner, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+	return reply == int64(1), nil
+}
+
+func (l *RedisLocker) Unlock(ctx context.Context, key string) error {
+	_, err := l.do(ctx, "EVAL", unlockScript, "1", key, l.owner)
+	return err
+}
+
+func (l *RedisLocker) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close()
+	l.conn, l.rd = nil, nil
+	return err
+}
+
+// do sends one command and returns its reply: a string, an int64, nil,
+// or []any. A Redis error reply is returned as an error and keeps the
+// connection; an I/O or protocol error drops it.
+func (l *RedisLocker) do(ctx context.Context, args ...string) (any, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		if err := l.dial(ctx); err != nil {
+			return nil, fmt.Errorf("redis %s: %w", l.addr, err)
+		}
+	}
+	reply, err := l.roundTrip(ctx, args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		_ = l.conn.Close()
+		l.conn, l.rd = nil, nil
+		return nil, fmt.Errorf("redis %s: %w", l.addr, err)
+	}
+	return reply, err
+}
+
+// dial connects, authenticates and selects the database. Callers hold mu.
+func (l *RedisLocker) dial(ctx context.Context) error {
+	d := &net.Dialer{Timeout: redisTimeout}
+	var conn net.Conn
+	var err error
+	if l.tls {
+		host, _, _ := net.SplitHostPort(l.addr)
+		td := &tls.Dialer{NetDialer: d, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = td.DialContext(ctx, "tcp", l.addr)
+	} else {
+		conn, err = d.DialContext(ctx, "tcp", l.addr)
+	}
+	if err != nil {
+		return err
+	}
+	l.conn, l.rd = conn, bufio.NewReader(conn)
+
+	var setup [][]string
+	switch {
+	case l.username != "" && l.password != "":
+		setup = append(setup, []string{"AUTH", l.username, l.password})
+	case l.password != "":
+		setup = append(setup, []string{"AUTH", l.password})
+	}
+	if l.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(l.db)})
+	}
+	for _, cmd := range setup {
+		if _, err := l.roundTrip(ctx, cmd); err != nil {
+			_ = conn.Close()
+			l.conn, l.rd = nil, nil
+			return fmt.Errorf("%s: %w", cmd[0], err)
+		}
+	}
+	return nil
+}
+
+func (l *RedisLocker) roundTrip(ctx context.Context, args []string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	_ = l.conn.SetDeadline(deadline)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(l.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(l.rd)
+}
+
+// redisError is an error reply ("-ERR ..."): the command failed but
+// the connection is still in sync.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// readReply parses one RESP2 reply.
+func readReply(rd *bufio.Reader) (any, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		out := make([]any, n)
+		for i := range out {
+			if out[i], err = readReply(rd); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply %q", line)
+	}
+}
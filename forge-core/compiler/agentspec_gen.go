@@ -23,7 +23,7 @@ func ConfigToAgentSpec(cfg *types.ForgeConfig) *agentspec.AgentSpec {
 	fields := strings.Fields(cfg.Entrypoint)
 	// Default entrypoint for forge framework agents
 	if cfg.Framework == "forge" && len(fields) == 0 {
-		fields = []string{"forge", "run", "--host", "0.0.0.0"}
+		fields = []string{"forge", "run", "--host", "0.0.0.0", "--allow-remote"}
 		if len(cfg.Channels) > 0 {
 			fields = append(fields, "--with", strings.Join(cfg.Channels, ","))
 		}
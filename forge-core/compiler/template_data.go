@@ -50,6 +50,15 @@ type TemplateSpecData struct {
 	// legitimately requires curl at runtime, that purge would clobber it, so
 	// the Dockerfile template skips the purge in that case.
 	KeepRuntimeCurl bool
+
+	// Kubernetes deploy settings set by `forge deploy k8s`. Zero values
+	// render the `forge build` defaults: Runtime.Image, one replica, the
+	// cluster's default grace period and empty secret placeholders.
+	Image                         string
+	Replicas                      int
+	ShutdownTimeout               string            // --shutdown-timeout passed to `forge run`, e.g. "30s"
+	TerminationGracePeriodSeconds int               // pod grace period; must exceed ShutdownTimeout
+	SecretValues                  map[string]string // env var → value for the Secret
 }
 
 // TemplateRuntimeData holds runtime-specific template data.
@@ -63,6 +72,7 @@ type TemplateRuntimeData struct {
 	HealthCheck    string
 	User           string
 	ModelEnv       map[string]string
+	ForgeRun       bool // entrypoint is `forge run`: serves /health and /healthz, drains on SIGTERM
 }
 
 // NetworkPolicyData holds network policy template data.
@@ -112,6 +122,7 @@ func BuildTemplateDataFromSpec(spec *agentspec.AgentSpec) *TemplateSpecData {
 			HealthCheck:    spec.Runtime.HealthCheck,
 			User:           spec.Runtime.User,
 			ModelEnv:       modelEnv,
+			ForgeRun:       len(spec.Runtime.Entrypoint) >= 2 && spec.Runtime.Entrypoint[0] == "forge" && spec.Runtime.Entrypoint[1] == "run",
 		}
 	}
 	return d
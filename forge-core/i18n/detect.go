@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scripts maps writing systems used by one language (or one language
+// in practice) to its code. Checked in order; Japanese before Chinese,
+// since Japanese text mixes kana with Han.
+var scripts = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Arabic, "ar"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+	{unicode.Cyrillic, "ru"},
+}
+
+// stopwords are frequent function words of the Latin-script languages
+// Detect tells apart. A word several languages share counts for each.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "you", "to", "of", "what", "with", "this", "that", "it", "for", "can", "please", "my", "how", "i", "do", "have", "not", "me", "your", "be"},
+	"es": {"el", "la", "los", "las", "que", "de", "y", "es", "por", "para", "con", "una", "un", "está", "qué", "cómo", "mi", "no", "hola", "gracias", "puedes", "del", "se", "me", "lo", "favor"},
+	"fr": {"le", "la", "les", "des", "est", "et", "que", "pour", "avec", "une", "un", "je", "vous", "pas", "ce", "mon", "bonjour", "merci", "comment", "sur", "du", "au", "il", "peux", "moi"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "sie", "mit", "ein", "eine", "für", "was", "wie", "bitte", "danke", "mein", "zu", "auf", "den", "du", "mir", "kannst", "es"},
+	"pt": {"o", "os", "as", "que", "de", "é", "não", "para", "com", "uma", "um", "você", "meu", "obrigado", "obrigada", "olá", "como", "está", "por", "do", "da", "em", "me", "pode"},
+	"it": {"il", "la", "che", "di", "è", "non", "per", "con", "una", "un", "sono", "ciao", "grazie", "mio", "come", "della", "del", "questo", "mi", "puoi", "gli"},
+	"nl": {"de", "het", "een", "en", "is", "niet", "ik", "je", "met", "van", "voor", "dat", "wat", "hoe", "mijn", "dank", "graag", "zijn", "op", "kun", "alsjeblieft"},
+}
+
+// stopwordLangs inverts stopwords.
+var stopwordLangs = func() map[string][]string {
+	out := map[string][]string{}
+	for lang, words := range stopwords {
+		for _, w := range words {
+			out[w] = append(out[w], lang)
+		}
+	}
+	return out
+}()
+
+// Detect returns the ISO 639-1 code of the language text is most likely
+// written in, or "" when it can't tell: text too short to judge, a tie,
+// or a language it doesn't know. Text in a script of its own is told by
+// the script; Latin-script text by its function words.
+func Detect(text string) string {
+	counts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// Kana anywhere makes Han text Japanese.
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+	for _, s := range scripts {
+		if n := counts[s.lang]; n*2 > letters {
+			if s.lang == "ru" && strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+				return "uk"
+			}
+			return s.lang
+		}
+	}
+	return detectLatin(text)
+}
+
+// detectLatin scores text's words against each language's stopwords. The
+// winner needs two hits and a clear lead.
+func detectLatin(text string) string {
+	scores := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, w := range words {
+		for _, lang := range stopwordLangs[w] {
+			scores[lang]++
+		}
+	}
+	best, bestScore, second := "", 0, 0
+	for lang, n := range scores {
+		switch {
+		case n > bestScore:
+			best, bestScore, second = lang, n, bestScore
+		case n > second:
+			second = n
+		}
+	}
+	if bestScore < 2 || bestScore == second {
+		return ""
+	}
+	return best
+}
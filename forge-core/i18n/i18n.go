@@ -0,0 +1,192 @@
+// Package i18n localizes the messages the agent writes itself rather
+// than its model — channel error replies, guardrail notices, schedule
+// confirmations — so a deployment answering in Spanish doesn't fail in
+// English.
+//
+// Strings live in catalogs keyed by message ID, one per language. The
+// English catalog is the source: other catalogs, built-in or loaded from
+// forge.yaml localization.catalog_dir, may only use its keys and must
+// keep its %-verbs.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// SourceLanguage is the language of the source catalog, and the last
+// resort when a string is missing.
+const SourceLanguage = "en"
+
+//go:embed catalogs/*.yaml
+var builtinCatalogs embed.FS
+
+// Catalog maps message IDs to format strings in one language.
+type Catalog map[string]string
+
+// Localizer renders messages in a language. A nil *Localizer renders
+// the built-in catalogs with English as the default, so callers need no
+// nil checks.
+type Localizer struct {
+	defaultLang string
+	detect      bool
+	catalogs    map[string]Catalog
+}
+
+// builtin holds the embedded catalogs, parsed once.
+var builtin = mustLoadBuiltin()
+
+func mustLoadBuiltin() map[string]Catalog {
+	entries, err := builtinCatalogs.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+	out := map[string]Catalog{}
+	for _, e := range entries {
+		data, err := builtinCatalogs.ReadFile("catalogs/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		var c Catalog
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("i18n: built-in catalog %s: %v", e.Name(), err))
+		}
+		out[strings.TrimSuffix(e.Name(), ".yaml")] = c
+	}
+	return out
+}
+
+// New builds the Localizer forge.yaml localization describes. catalog_dir
+// is resolved against baseDir, the directory holding forge.yaml.
+func New(cfg types.LocalizationConfig, baseDir string) (*Localizer, error) {
+	l := &Localizer{defaultLang: cfg.DefaultLanguage, detect: cfg.Detect, catalogs: map[string]Catalog{}}
+	if l.defaultLang == "" {
+		l.defaultLang = SourceLanguage
+	}
+	for lang, c := range builtin {
+		l.catalogs[lang] = c
+	}
+	if cfg.CatalogDir != "" {
+		dir := cfg.CatalogDir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		if err := l.loadDir(dir); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// loadDir merges the <lang>.yaml catalogs in dir over the built-in ones,
+// key by key.
+func (l *Localizer) loadDir(dir string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("localization.catalog_dir: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		lang := strings.TrimSuffix(filepath.Base(p), ".yaml")
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		var c Catalog
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("catalog %s: %w", p, err)
+		}
+		if err := CheckCatalog(c); err != nil {
+			return fmt.Errorf("catalog %s: %w", p, err)
+		}
+		merged := Catalog{}
+		for k, v := range l.catalogs[lang] {
+			merged[k] = v
+		}
+		for k, v := range c {
+			merged[k] = v
+		}
+		l.catalogs[lang] = merged
+	}
+	return nil
+}
+
+// CheckCatalog reports the first entry of c whose key the source catalog
+// doesn't have, or whose %-verbs differ from the source string's.
+func CheckCatalog(c Catalog) error {
+	src := builtin[SourceLanguage]
+	for k, v := range c {
+		s, ok := src[k]
+		if !ok {
+			return fmt.Errorf("unknown message %q", k)
+		}
+		if got, want := verbs(v), verbs(s); got != want {
+			return fmt.Errorf("message %q has verbs %q; want %q, as in %q", k, got, want, s)
+		}
+	}
+	return nil
+}
+
+// verbs returns the %-verbs of a format string, in order.
+func verbs(format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if format[i] != '%' {
+			b.WriteString("%" + string(format[i]))
+		}
+	}
+	return b.String()
+}
+
+// Language returns the language to answer text in: the one detected on
+// it when detection is on and sure, otherwise the default.
+func (l *Localizer) Language(text string) string {
+	if l == nil {
+		return SourceLanguage
+	}
+	if l.detect {
+		if lang := Detect(text); lang != "" {
+			return lang
+		}
+	}
+	return l.defaultLang
+}
+
+// Text renders message key in lang with args. A language without the
+// message falls back to its base language (pt for pt-BR), then the
+// default language, then English.
+func (l *Localizer) Text(lang, key string, args ...any) string {
+	format := l.lookup(lang, key)
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (l *Localizer) lookup(lang, key string) string {
+	catalogs, def := builtin, SourceLanguage
+	if l != nil {
+		catalogs, def = l.catalogs, l.defaultLang
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	defBase, _, _ := strings.Cut(def, "-")
+	for _, candidate := range []string{lang, base, def, defBase, SourceLanguage} {
+		if s, ok := catalogs[candidate][key]; ok {
+			return s
+		}
+	}
+	return key
+}
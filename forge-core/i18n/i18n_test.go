@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestDetect(t *testing.T) {
+	for text, want := range map[string]string{
+		"Can you summarize the open invoices for me, please?": "en",
+		"Hola, ¿puedes resumir las facturas pendientes?":      "es",
+		"Bonjour, peux-tu me résumer les factures ouvertes ?": "fr",
+		"Kannst du mir bitte die offenen Rechnungen zeigen?":  "de",
+		"Olá, você pode resumir as faturas em aberto?":        "pt",
+		"Привет, покажи открытые счета":                       "ru",
+		"Привіт, покажи відкриті рахунки":                     "uk",
+		"未払いの請求書をまとめてください":                                    "ja",
+		"请总结一下未付的发票":                                          "zh",
+		"미결제 송장을 요약해 주세요":                                     "ko",
+		"ok": "",
+		"👍":  "",
+	} {
+		if got := Detect(text); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestBuiltinCatalogs(t *testing.T) {
+	for lang, c := range builtin {
+		if err := CheckCatalog(c); err != nil {
+			t.Errorf("%s: %v", lang, err)
+		}
+		if len(c) != len(builtin[SourceLanguage]) {
+			t.Errorf("%s has %d messages, en has %d", lang, len(c), len(builtin[SourceLanguage]))
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("es.yaml", "channel.no_response: \"(nada)\"\n")
+	write("it.yaml", "channel.retry_after: \"Riprova tra %s.\"\n")
+
+	l, err := New(types.LocalizationConfig{DefaultLanguage: "es", Detect: true, CatalogDir: "."}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		lang, key string
+		args      []any
+		want      string
+	}{
+		{"es", "channel.no_response", nil, "(nada)"},                        // overridden
+		{"es", "channel.retry", nil, "Vuelve a intentarlo en un momento."},  // built-in kept
+		{"it", "channel.retry_after", []any{"5s"}, "Riprova tra 5s."},       // added language
+		{"it", "channel.retry", nil, "Vuelve a intentarlo en un momento."},  // missing: default language
+		{"pt-BR", "channel.error.budget", nil, "Atingi meu limite de uso."}, // base language
+		{"xx", "no.such.key", nil, "no.such.key"},                           // unknown key
+	} {
+		if got := l.Text(c.lang, c.key, c.args...); got != c.want {
+			t.Errorf("Text(%q, %q) = %q, want %q", c.lang, c.key, got, c.want)
+		}
+	}
+
+	if got := l.Language("Kannst du mir bitte helfen?"); got != "de" {
+		t.Errorf("Language = %q, want de", got)
+	}
+	if got := l.Language("ok"); got != "es" {
+		t.Errorf("Language of undetectable text = %q, want the default", got)
+	}
+	var nilL *Localizer
+	if got := nilL.Text(nilL.Language("hola, ¿qué tal? gracias"), "channel.no_response"); got != "(no response)" {
+		t.Errorf("nil Localizer = %q, want English", got)
+	}
+}
+
+func TestLoadCatalogErrors(t *testing.T) {
+	for body, want := range map[string]string{
+		"channel.no_such: x\n":              "unknown message",
+		"channel.retry_after: \"Pronto\"\n": "verbs",
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "es.yaml"), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := New(types.LocalizationConfig{CatalogDir: dir}, ""); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("catalog %q: err = %v, want %q", body, err, want)
+		}
+	}
+	if _, err := New(types.LocalizationConfig{CatalogDir: "missing"}, t.TempDir()); err == nil {
+		t.Error("missing catalog_dir accepted")
+	}
+}
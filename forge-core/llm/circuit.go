@@ -0,0 +1,287 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CircuitState is a fallback candidate's circuit-breaker state.
+type CircuitState string
+
+const (
+	// CircuitClosed candidates serve calls normally.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen candidates are skipped until their open period ends.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen candidates are being probed; the probe's outcome
+	// closes or re-opens the circuit.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// Circuit breaker defaults. A candidate's circuit opens when, within
+// the last CircuitWindow, at least CircuitMinRequests calls were made
+// and CircuitFailureRate or more of them failed with a retriable
+// error. On a quiet agent one failure is enough (as with the old
+// cooldown); on a busy one an occasional 429 among many successes is
+// not.
+const (
+	CircuitWindow      = time.Minute
+	CircuitFailureRate = 0.5
+	CircuitMinRequests = 1
+
+	// circuitProbeTimeout bounds a half-open probe.
+	circuitProbeTimeout = 10 * time.Second
+)
+
+// CandidateHealth is a snapshot of one fallback candidate's circuit.
+type CandidateHealth struct {
+	Provider    string       `json:"provider"`
+	Model       string       `json:"model"`
+	State       CircuitState `json:"state"`
+	Requests    int          `json:"window_requests"`
+	Failures    int          `json:"window_failures"`
+	LastError   string       `json:"last_error,omitempty"`
+	LastFailure *time.Time   `json:"last_failure,omitempty"`
+	OpenUntil   *time.Time   `json:"open_until,omitempty"`
+}
+
+// CircuitTransition describes a candidate's circuit changing state.
+// Reason is the failover reason that opened it, "open_period_elapsed"
+// when a probe starts, or "probe_ok" / "probe_failed" for the probe's
+// outcome.
+type CircuitTransition struct {
+	Provider    string
+	Model       string
+	From        CircuitState
+	To          CircuitState
+	Reason      string
+	FailureRate float64
+	OpenFor     time.Duration
+}
+
+// circuitBreaker tracks one candidate. Its window is a list of call
+// outcomes, trimmed to CircuitWindow on every update — candidates see
+// at most a few calls a second, so a list beats a bucketed counter.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     CircuitState
+	outcomes  []circuitOutcome
+	opens     int // consecutive opens without a close; scales the open period
+	reason    FailoverReason
+	lastErr   string
+	lastFail  time.Time
+	openUntil time.Time
+	probing   bool
+}
+
+type circuitOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{state: CircuitClosed}
+}
+
+func (b *circuitBreaker) trimLocked(now time.Time) {
+	cut := 0
+	for cut < len(b.outcomes) && now.Sub(b.outcomes[cut].at) > CircuitWindow {
+		cut++
+	}
+	b.outcomes = b.outcomes[cut:]
+}
+
+func (b *circuitBreaker) rateLocked() (requests, failures int, rate float64) {
+	for _, o := range b.outcomes {
+		if o.failed {
+			failures++
+		}
+	}
+	requests = len(b.outcomes)
+	if requests > 0 {
+		rate = float64(failures) / float64(requests)
+	}
+	return requests, failures, rate
+}
+
+// recordSuccess notes a successful call.
+func (b *circuitBreaker) recordSuccess(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trimLocked(now)
+	b.outcomes = append(b.outcomes, circuitOutcome{at: now})
+}
+
+// recordFailure notes a retriable failure and reports whether it
+// opened the circuit.
+func (b *circuitBreaker) recordFailure(now time.Time, fe *FailoverError) (opened bool, rate float64, openFor time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trimLocked(now)
+	b.outcomes = append(b.outcomes, circuitOutcome{at: now, failed: true})
+	b.reason, b.lastErr, b.lastFail = fe.Reason, fe.Error(), now
+
+	requests, _, rate := b.rateLocked()
+	if b.state != CircuitClosed || requests < CircuitMinRequests || rate < CircuitFailureRate {
+		return false, rate, 0
+	}
+	openFor = b.openLocked(now)
+	return true, rate, openFor
+}
+
+func (b *circuitBreaker) openLocked(now time.Time) time.Duration {
+	b.opens++
+	d := cooldownDuration(b.reason, b.opens)
+	b.state = CircuitOpen
+	b.openUntil = now.Add(d)
+	return d
+}
+
+// acquire reports whether the candidate may serve a call now, and
+// whether the caller must first probe it (the open period is over and
+// no other caller is probing).
+func (b *circuitBreaker) acquire(now time.Time) (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitClosed:
+		return true, false
+	case CircuitOpen:
+		if now.Before(b.openUntil) {
+			return false, false
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return true, true
+	default: // half-open: another caller's probe is in flight
+		return false, false
+	}
+}
+
+// finishProbe settles a half-open probe: success closes the circuit
+// and clears its window; failure re-opens it for longer.
+func (b *circuitBreaker) finishProbe(now time.Time, fe *FailoverError) (openFor time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if fe == nil {
+		b.state = CircuitClosed
+		b.opens = 0
+		b.outcomes = nil
+		return 0
+	}
+	b.reason, b.lastErr, b.lastFail = fe.Reason, fe.Error(), now
+	return b.openLocked(now)
+}
+
+func (b *circuitBreaker) health(now time.Time, provider, model string) CandidateHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trimLocked(now)
+	requests, failures, _ := b.rateLocked()
+	h := CandidateHealth{
+		Provider:  provider,
+		Model:     model,
+		State:     b.state,
+		Requests:  requests,
+		Failures:  failures,
+		LastError: b.lastErr,
+	}
+	if !b.lastFail.IsZero() {
+		t := b.lastFail
+		h.LastFailure = &t
+	}
+	if b.state == CircuitOpen {
+		t := b.openUntil
+		h.OpenUntil = &t
+	}
+	return h
+}
+
+// probeRequest is the lightweight call a half-open probe sends: one
+// token, so a recovering provider costs next to nothing to check.
+func probeRequest() *ChatRequest {
+	return &ChatRequest{
+		Messages:  []ChatMessage{{Role: RoleUser, Content: "ping"}},
+		MaxTokens: 1,
+	}
+}
+
+// probeFailure runs the probe against c and returns nil when the
+// provider answered. A format error (the provider rejected the tiny
+// request) still proves it is reachable, so it counts as healthy. The
+// probe keeps ctx's values but not its cancellation: a caller that
+// gives up mid-request says nothing about the provider's health.
+func probeFailure(ctx context.Context, c FallbackCandidate) *FailoverError {
+	ctx, cancel := context.WithTimeout(withoutResponseCache(context.WithoutCancel(ctx)), circuitProbeTimeout)
+	defer cancel()
+	if _, err := c.Client.Chat(ctx, probeRequest()); err != nil {
+		if fe := ClassifyError(err, c.Provider, c.Model); fe.Reason != FailoverFormat {
+			return fe
+		}
+	}
+	return nil
+}
+
+type noResponseCacheKey struct{}
+
+// withoutResponseCache marks ctx so a CachingClient passes the call
+// straight through — a cached answer says nothing about health.
+func withoutResponseCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noResponseCacheKey{}, true)
+}
+
+func responseCacheDisabled(ctx context.Context) bool {
+	v, _ := ctx.Value(noResponseCacheKey{}).(bool)
+	return v
+}
+
+// cooldownDuration returns how long a circuit stays open, based on the
+// reason it opened and count, its consecutive opens without a close.
+//
+// Standard errors (rate_limit, overloaded, timeout, unknown):
+//
+//	count 1: 1 min, count 2: 5 min, count 3: 25 min, count 4+: 1 hour (cap)
+//
+// Billing errors:
+//
+//	count 1: 5 hours, count 2: 10 hours, count 3: 20 hours, count 4+: 24 hours (cap)
+//
+// Auth errors:
+//
+//	Always 24 hours (credentials won't fix themselves mid-session)
+func cooldownDuration(reason FailoverReason, count int) time.Duration {
+	if count <= 0 {
+		return 0
+	}
+
+	switch reason {
+	case FailoverAuth:
+		return 24 * time.Hour
+
+	case FailoverBilling:
+		// 5h * 2^(count-1), capped at 24h
+		base := 5 * time.Hour
+		d := base
+		for i := 1; i < count; i++ {
+			d *= 2
+		}
+		if d > 24*time.Hour {
+			d = 24 * time.Hour
+		}
+		return d
+
+	default:
+		// Standard: 1min * 5^(count-1), capped at 1h
+		base := time.Minute
+		d := base
+		for i := 1; i < count; i++ {
+			d *= 5
+		}
+		if d > time.Hour {
+			d = time.Hour
+		}
+		return d
+	}
+}
@@ -0,0 +1,169 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFallbackChain_CircuitBreaker(t *testing.T) {
+	var calls, probes int
+	failing := true
+	primary := &mockClient{
+		modelID: "gpt-4o",
+		chatFunc: func(_ context.Context, req *ChatRequest) (*ChatResponse, error) {
+			if req.MaxTokens == 1 {
+				probes++
+			} else {
+				calls++
+			}
+			if failing {
+				return nil, fmt.Errorf("openai error (status 503): overloaded")
+			}
+			return &ChatResponse{Message: ChatMessage{Content: "primary"}}, nil
+		},
+	}
+	fc := NewFallbackChain([]FallbackCandidate{
+		{Provider: "openai", Model: "gpt-4o", Client: primary},
+		{Provider: "anthropic", Model: "claude", Client: okClient("claude")},
+	})
+	now := time.Now()
+	fc.now = func() time.Time { return now }
+	var transitions []string
+	fc.SetTransitionHandler(func(tr CircuitTransition) {
+		transitions = append(transitions, fmt.Sprintf("%s:%s->%s(%s)", tr.Provider, tr.From, tr.To, tr.Reason))
+	})
+	ctx := context.Background()
+
+	// A busy, healthy primary absorbs an occasional failure.
+	failing = false
+	for range 3 {
+		_, _ = fc.Chat(ctx, &ChatRequest{})
+	}
+	failing = true
+	if resp, _ := fc.Chat(ctx, &ChatRequest{}); resp.Message.Content != "ok from claude" {
+		t.Fatalf("failover = %q", resp.Message.Content)
+	}
+	if h := fc.Health()[0]; h.State != CircuitClosed || h.Requests != 4 || h.Failures != 1 {
+		t.Errorf("after 1/4 failures: %+v", h)
+	}
+
+	// Reaching the failure rate opens the circuit; the primary is skipped.
+	_, _ = fc.Chat(ctx, &ChatRequest{})
+	_, _ = fc.Chat(ctx, &ChatRequest{})
+	if h := fc.Health()[0]; h.State != CircuitOpen || h.OpenUntil == nil || h.LastError == "" {
+		t.Fatalf("after 3/6 failures: %+v", h)
+	}
+	before := calls
+	_, _ = fc.Chat(ctx, &ChatRequest{})
+	if calls != before {
+		t.Errorf("open circuit was called")
+	}
+
+	// After the open period a failing probe re-opens it, for longer.
+	now = now.Add(2 * time.Minute)
+	_, _ = fc.Chat(ctx, &ChatRequest{})
+	if probes != 1 || calls != before {
+		t.Errorf("probes = %d, calls = %d (want 1, %d)", probes, calls, before)
+	}
+	if h := fc.Health()[0]; h.State != CircuitOpen || h.OpenUntil.Sub(now) != 5*time.Minute {
+		t.Errorf("after failed probe: %+v", h)
+	}
+
+	// A successful probe closes it and the call goes to the primary.
+	failing = false
+	now = now.Add(6 * time.Minute)
+	if resp, _ := fc.Chat(ctx, &ChatRequest{}); resp.Message.Content != "primary" {
+		t.Errorf("after recovery = %q", resp.Message.Content)
+	}
+	if h := fc.Health()[0]; h.State != CircuitClosed || h.Requests != 1 {
+		t.Errorf("after recovery: %+v", h)
+	}
+
+	want := []string{
+		"openai:closed->open(overloaded)",
+		"openai:open->half_open(open_period_elapsed)",
+		"openai:half_open->open(probe_failed)",
+		"openai:open->half_open(open_period_elapsed)",
+		"openai:half_open->closed(probe_ok)",
+	}
+	if fmt.Sprint(transitions) != fmt.Sprint(want) {
+		t.Errorf("transitions:\n got %v\nwant %v", transitions, want)
+	}
+}
+
+func TestFallbackChain_ProbeBypassesResponseCache(t *testing.T) {
+	cache, _ := NewResponseCache(ResponseCacheConfig{})
+	var probes int
+	up := &mockClient{modelID: "m", chatFunc: func(context.Context, *ChatRequest) (*ChatResponse, error) {
+		probes++
+		return &ChatResponse{}, nil
+	}}
+	client := cache.Wrap("openai", up)
+	for range 2 {
+		if fe := probeFailure(context.Background(), FallbackCandidate{Provider: "openai", Model: "m", Client: client}); fe != nil {
+			t.Fatal(fe)
+		}
+	}
+	// The probe skips the cache outright rather than being counted as
+	// a sampling request.
+	if s := cache.Stats(); probes != 2 || s.Hits != 0 || s.Bypassed != 0 {
+		t.Errorf("probes = %d, stats = %+v", probes, s)
+	}
+}
+
+func TestProbeFailure_IgnoresCallerCancellation(t *testing.T) {
+	up := &mockClient{modelID: "m", chatFunc: func(ctx context.Context, _ *ChatRequest) (*ChatResponse, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &ChatResponse{}, nil
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if fe := probeFailure(ctx, FallbackCandidate{Provider: "openai", Model: "m", Client: up}); fe != nil {
+		t.Errorf("a cancelled caller failed the probe of a healthy provider: %v", fe)
+	}
+}
+
+func TestCooldownDuration(t *testing.T) {
+	tests := []struct {
+		reason FailoverReason
+		count  int
+		want   time.Duration
+	}{
+		// Standard
+		{FailoverRateLimit, 1, time.Minute},
+		{FailoverRateLimit, 2, 5 * time.Minute},
+		{FailoverRateLimit, 3, 25 * time.Minute},
+		{FailoverRateLimit, 4, time.Hour},
+		{FailoverRateLimit, 10, time.Hour}, // capped
+		{FailoverOverloaded, 1, time.Minute},
+		{FailoverTimeout, 1, time.Minute},
+		{FailoverUnknown, 1, time.Minute},
+
+		// Billing
+		{FailoverBilling, 1, 5 * time.Hour},
+		{FailoverBilling, 2, 10 * time.Hour},
+		{FailoverBilling, 3, 20 * time.Hour},
+		{FailoverBilling, 4, 24 * time.Hour},
+		{FailoverBilling, 10, 24 * time.Hour}, // capped
+
+		// Auth
+		{FailoverAuth, 1, 24 * time.Hour},
+		{FailoverAuth, 5, 24 * time.Hour},
+
+		// Zero count
+		{FailoverRateLimit, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			got := cooldownDuration(tt.reason, tt.count)
+			if got != tt.want {
+				t.Errorf("cooldownDuration(%s, %d) = %v, want %v", tt.reason, tt.count, got, tt.want)
+			}
+		})
+	}
+}
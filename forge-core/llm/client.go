@@ -1,6 +1,9 @@
 package llm
 
-import "context"
+import (
+	"context"
+	"net/http"
+)
 
 // Outbound LLM auth schemes (ClientConfig.AuthScheme / ModelRef.auth_scheme).
 const (
@@ -100,4 +103,39 @@ type ClientConfig struct {
 	// pre-compression contract unless the operator opts in
 	// (compression.cache_hints / compression.enabled in forge.yaml).
 	PromptCaching bool
+
+	// ReasoningEffort asks a reasoning model to think less or more
+	// before answering ("minimal", "low", "medium", "high"). Sent as
+	// `reasoning_effort` by the OpenAI-compatible clients whose provider
+	// accepts the value (see providers.ReasoningEfforts); dropped
+	// everywhere else. Empty leaves the provider default.
+	ReasoningEffort string
+
+	// ThinkingBudget turns on Anthropic extended thinking with up to
+	// this many tokens of thinking per call (at least 1024). The
+	// request's max_tokens is raised above the budget when needed.
+	// Zero leaves thinking off; ignored by every other provider.
+	ThinkingBudget int
+
+	// GoogleSearch grounds Gemini answers in Google Search results,
+	// returned as the message's Citations. Ignored by every other
+	// provider.
+	GoogleSearch bool
+
+	// SafetySettings sets Gemini's block threshold per harm category,
+	// both in forge.yaml's short form (harassment: block_only_high; see
+	// providers.ValidateGeminiSafetySettings). Ignored by every other
+	// provider.
+	SafetySettings map[string]string
+
+	// Transport carries the client's HTTP requests. The runtime shares
+	// one providers.Transport — a tuned connection pool — across every
+	// client; nil uses http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// KeepAlive is how long Ollama keeps the model loaded after a call:
+	// a Go duration ("10m") or seconds ("-1" = indefinitely, "0" =
+	// unload immediately). Empty leaves the daemon default (5m).
+	// Ignored by every other provider.
+	KeepAlive string
 }
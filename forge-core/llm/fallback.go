@@ -3,6 +3,8 @@ package llm
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 )
 
 // FallbackCandidate pairs a provider/model label with its LLM client.
@@ -17,19 +19,90 @@ type FallbackCandidate struct {
 // (429, 503, timeouts), the chain moves to the next candidate. Non-retriable
 // errors (400 bad request, 401 auth) abort immediately.
 //
+// Each candidate has a circuit breaker. A candidate whose retriable
+// failure rate over the last CircuitWindow reaches CircuitFailureRate is
+// opened and skipped for a period that grows with each consecutive open
+// (see cooldownDuration). When the period ends, the next call first sends
+// the candidate a one-token probe: success closes the circuit, failure
+// re-opens it. Health reports each circuit, and the transition handler
+// hears every state change.
+//
 // When there is only one candidate, FallbackChain delegates directly without
 // error classification to preserve exact current behavior.
 type FallbackChain struct {
 	candidates []FallbackCandidate
-	cooldown   *CooldownTracker
+	breakers   []*circuitBreaker
+	now        func() time.Time
+
+	mu           sync.RWMutex
+	onTransition func(CircuitTransition)
 }
 
 // NewFallbackChain creates a new fallback chain from the given candidates.
 // At least one candidate is required.
 func NewFallbackChain(candidates []FallbackCandidate) *FallbackChain {
+	breakers := make([]*circuitBreaker, len(candidates))
+	for i := range breakers {
+		breakers[i] = newCircuitBreaker()
+	}
 	return &FallbackChain{
 		candidates: candidates,
-		cooldown:   NewCooldownTracker(),
+		breakers:   breakers,
+		now:        time.Now,
+	}
+}
+
+// SetTransitionHandler registers fn to hear every circuit state change
+// (for audit events). fn runs synchronously on the calling goroutine.
+func (fc *FallbackChain) SetTransitionHandler(fn func(CircuitTransition)) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.onTransition = fn
+}
+
+// Health returns each candidate's circuit state, in chain order.
+func (fc *FallbackChain) Health() []CandidateHealth {
+	now := fc.now()
+	out := make([]CandidateHealth, len(fc.candidates))
+	for i, c := range fc.candidates {
+		out[i] = fc.breakers[i].health(now, c.Provider, c.Model)
+	}
+	return out
+}
+
+func (fc *FallbackChain) emit(t CircuitTransition) {
+	fc.mu.RLock()
+	fn := fc.onTransition
+	fc.mu.RUnlock()
+	if fn != nil {
+		fn(t)
+	}
+}
+
+// available reports whether candidate i may serve a call, probing it
+// first when its open period has just ended.
+func (fc *FallbackChain) available(ctx context.Context, i int) bool {
+	c, b := fc.candidates[i], fc.breakers[i]
+	ok, probe := b.acquire(fc.now())
+	if !ok || !probe {
+		return ok
+	}
+	fc.emit(CircuitTransition{Provider: c.Provider, Model: c.Model, From: CircuitOpen, To: CircuitHalfOpen, Reason: "open_period_elapsed"})
+	fe := probeFailure(ctx, c)
+	openFor := b.finishProbe(fc.now(), fe)
+	if fe != nil {
+		fc.emit(CircuitTransition{Provider: c.Provider, Model: c.Model, From: CircuitHalfOpen, To: CircuitOpen, Reason: "probe_failed", OpenFor: openFor})
+		return false
+	}
+	fc.emit(CircuitTransition{Provider: c.Provider, Model: c.Model, From: CircuitHalfOpen, To: CircuitClosed, Reason: "probe_ok"})
+	return true
+}
+
+// recordFailure feeds a retriable failure to candidate i's breaker.
+func (fc *FallbackChain) recordFailure(i int, fe *FailoverError) {
+	c := fc.candidates[i]
+	if opened, rate, openFor := fc.breakers[i].recordFailure(fc.now(), fe); opened {
+		fc.emit(CircuitTransition{Provider: c.Provider, Model: c.Model, From: CircuitClosed, To: CircuitOpen, Reason: string(fe.Reason), FailureRate: rate, OpenFor: openFor})
 	}
 }
 
@@ -42,20 +115,20 @@ func (fc *FallbackChain) Chat(ctx context.Context, req *ChatRequest) (*ChatRespo
 
 	var errors []*FailoverError
 
-	for _, c := range fc.candidates {
+	for i, c := range fc.candidates {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
 
-		// Skip providers in cooldown
-		if !fc.cooldown.IsAvailable(c.Provider) {
+		// Skip candidates whose circuit is open
+		if !fc.available(ctx, i) {
 			continue
 		}
 
 		resp, err := c.Client.Chat(ctx, req)
 		if err == nil {
-			fc.cooldown.MarkSuccess(c.Provider)
+			fc.breakers[i].recordSuccess(fc.now())
 			return resp, nil
 		}
 
@@ -67,12 +140,12 @@ func (fc *FallbackChain) Chat(ctx context.Context, req *ChatRequest) (*ChatRespo
 			return nil, fe
 		}
 
-		// Retriable — mark failure and try next
-		fc.cooldown.MarkFailure(c.Provider, fe.Reason)
+		// Retriable — record the failure and try next
+		fc.recordFailure(i, fe)
 	}
 
 	if len(errors) == 0 {
-		return nil, fmt.Errorf("all fallback candidates in cooldown")
+		return nil, fmt.Errorf("all fallback candidates unavailable (circuit open)")
 	}
 	return nil, &FallbackExhaustedError{Errors: errors}
 }
@@ -86,20 +159,20 @@ func (fc *FallbackChain) ChatStream(ctx context.Context, req *ChatRequest) (<-ch
 
 	var errors []*FailoverError
 
-	for _, c := range fc.candidates {
+	for i, c := range fc.candidates {
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
 
-		// Skip providers in cooldown
-		if !fc.cooldown.IsAvailable(c.Provider) {
+		// Skip candidates whose circuit is open
+		if !fc.available(ctx, i) {
 			continue
 		}
 
 		ch, err := c.Client.ChatStream(ctx, req)
 		if err == nil {
-			fc.cooldown.MarkSuccess(c.Provider)
+			fc.breakers[i].recordSuccess(fc.now())
 			return ch, nil
 		}
 
@@ -111,12 +184,12 @@ func (fc *FallbackChain) ChatStream(ctx context.Context, req *ChatRequest) (<-ch
 			return nil, fe
 		}
 
-		// Retriable — mark failure and try next
-		fc.cooldown.MarkFailure(c.Provider, fe.Reason)
+		// Retriable — record the failure and try next
+		fc.recordFailure(i, fe)
 	}
 
 	if len(errors) == 0 {
-		return nil, fmt.Errorf("all fallback candidates in cooldown")
+		return nil, fmt.Errorf("all fallback candidates unavailable (circuit open)")
 	}
 	return nil, &FallbackExhaustedError{Errors: errors}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 )
 
 // mockClient is a test double for llm.Client.
@@ -388,8 +389,8 @@ func TestFallbackChain_SuccessResetsCooldown(t *testing.T) {
 		t.Errorf("first: expected fallback, got %s", resp.Message.Content)
 	}
 
-	// Manually reset cooldown for primary (simulates time passing)
-	fc.cooldown.MarkSuccess("openai")
+	// Let the open period pass; the next call probes and closes the circuit
+	fc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
 
 	// Second: primary succeeds now
 	resp, err = fc.Chat(context.Background(), &ChatRequest{})
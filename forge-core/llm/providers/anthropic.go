@@ -22,6 +22,7 @@ type AnthropicClient struct {
 	authScheme     string
 	authHeaderName string
 	promptCaching  bool
+	thinkingBudget int
 	client         *http.Client
 }
 
@@ -44,13 +45,9 @@ func NewAnthropicClient(cfg llm.ClientConfig) *AnthropicClient {
 	if baseURL == "" {
 		baseURL = "https://api.anthropic.com"
 	}
-	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
-	if timeout == 0 {
-		timeout = 120 * time.Second
-	}
-	httpClient := &http.Client{Timeout: timeout}
+	httpClient := newHTTPClient(cfg, 120*time.Second)
 	if cfg.AuthScheme == llm.AuthSchemeAWSSigV4 {
-		httpClient.Transport = newBedrockSigningTransport(cfg.AWSRegion, http.DefaultTransport)
+		httpClient.Transport = newBedrockSigningTransport(cfg.AWSRegion, cfg.Transport)
 	}
 	return &AnthropicClient{
 		apiKey:         cfg.APIKey,
@@ -59,6 +56,7 @@ func NewAnthropicClient(cfg llm.ClientConfig) *AnthropicClient {
 		authScheme:     cfg.AuthScheme,
 		authHeaderName: cfg.AuthHeaderName,
 		promptCaching:  cfg.PromptCaching,
+		thinkingBudget: cfg.ThinkingBudget,
 		client:         httpClient,
 	}
 }
@@ -188,9 +186,19 @@ type anthropicRequest struct {
 	System    any                `json:"system,omitempty"`
 	MaxTokens int                `json:"max_tokens"`
 	Tools     []anthropicTool    `json:"tools,omitempty"`
+	Thinking  *anthropicThinking `json:"thinking,omitempty"`
 	Stream    bool               `json:"stream,omitempty"`
 }
 
+// anthropicThinking enables extended thinking with a token budget.
+type anthropicThinking struct {
+	Type         string `json:"type"` // "enabled"
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+// minThinkingBudget is the smallest budget_tokens the API accepts.
+const minThinkingBudget = 1024
+
 // anthropicCacheControl marks a prompt-cache breakpoint. Everything up to and
 // including the marked block (in Anthropic's tools → system → messages cache
 // order) is cached for ~5 minutes and re-billed at ~10% on hit.
@@ -219,6 +227,10 @@ type anthropicContentBlock struct {
 	Input     json.RawMessage `json:"input,omitempty"`
 	ToolUseID string          `json:"tool_use_id,omitempty"`
 	Content   string          `json:"content,omitempty"`
+	// thinking / redacted_thinking blocks.
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
 }
 
 type anthropicTool struct {
@@ -245,25 +257,58 @@ func (c *AnthropicClient) toAnthropicRequest(req *llm.ChatRequest, stream bool)
 		Stream:    stream,
 	}
 
-	// Extract system message and convert remaining messages
+	// Extended thinking counts against max_tokens, which must exceed
+	// the budget.
+	if c.thinkingBudget > 0 {
+		budget := max(c.thinkingBudget, minThinkingBudget)
+		r.Thinking = &anthropicThinking{Type: "enabled", BudgetTokens: budget}
+		if r.MaxTokens <= budget {
+			r.MaxTokens = budget + 4096
+		}
+	}
+
+	// Extract system message and convert remaining messages. The
+	// results of one assistant turn's tool calls go back together in a
+	// single user message, as the Messages API expects.
 	var system string
+	var results []anthropicContentBlock
+	flushResults := func() {
+		if len(results) > 0 {
+			data, _ := json.Marshal(results)
+			r.Messages = append(r.Messages, anthropicMessage{Role: "user", Content: data})
+			results = nil
+		}
+	}
 	for _, m := range req.Messages {
-		if m.Role == llm.RoleSystem {
+		switch m.Role {
+		case llm.RoleSystem:
 			system = m.Content
-			continue
+		case llm.RoleTool:
+			results = append(results, anthropicContentBlock{
+				Type:      "tool_result",
+				ToolUseID: m.ToolCallID,
+				Content:   m.Content,
+			})
+		default:
+			flushResults()
+			r.Messages = append(r.Messages, c.convertMessage(m))
 		}
-		r.Messages = append(r.Messages, c.convertMessage(m))
 	}
+	flushResults()
 	if system != "" {
 		r.System = system
 	}
 
 	// Convert tools
 	for _, t := range req.Tools {
+		schema := t.Function.Parameters
+		if len(schema) == 0 || string(schema) == "null" {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
 		r.Tools = append(r.Tools, anthropicTool{
 			Name:        t.Function.Name,
 			Description: t.Function.Description,
-			InputSchema: t.Function.Parameters,
+			InputSchema: schema,
 		})
 	}
 
@@ -294,22 +339,17 @@ func (c *AnthropicClient) convertMessage(m llm.ChatMessage) anthropicMessage {
 		role = "assistant"
 	}
 
-	// Tool result message
-	if m.Role == llm.RoleTool {
-		blocks := []anthropicContentBlock{
-			{
-				Type:      "tool_result",
-				ToolUseID: m.ToolCallID,
-				Content:   m.Content,
-			},
-		}
-		data, _ := json.Marshal(blocks)
-		return anthropicMessage{Role: "user", Content: data}
-	}
-
-	// Assistant message with tool calls
-	if m.Role == llm.RoleAssistant && len(m.ToolCalls) > 0 {
+	// Assistant message with tool calls or thinking: content blocks,
+	// thinking first, as the model produced them.
+	if m.Role == llm.RoleAssistant && (len(m.ToolCalls) > 0 || len(m.Thinking) > 0) {
 		var blocks []anthropicContentBlock
+		for _, th := range m.Thinking {
+			if th.Data != "" {
+				blocks = append(blocks, anthropicContentBlock{Type: "redacted_thinking", Data: th.Data})
+			} else {
+				blocks = append(blocks, anthropicContentBlock{Type: "thinking", Thinking: th.Text, Signature: th.Signature})
+			}
+		}
 		if m.Content != "" {
 			blocks = append(blocks, anthropicContentBlock{Type: "text", Text: m.Content})
 		}
@@ -318,7 +358,7 @@ func (c *AnthropicClient) convertMessage(m llm.ChatMessage) anthropicMessage {
 				Type:  "tool_use",
 				ID:    tc.ID,
 				Name:  tc.Function.Name,
-				Input: json.RawMessage(tc.Function.Arguments),
+				Input: toolUseInput(tc.Function.Arguments),
 			})
 		}
 		data, _ := json.Marshal(blocks)
@@ -330,6 +370,30 @@ func (c *AnthropicClient) convertMessage(m llm.ChatMessage) anthropicMessage {
 	return anthropicMessage{Role: role, Content: data}
 }
 
+// toolUseInput is a tool call's arguments as a tool_use input, which
+// must be a JSON object: a call without arguments (or with arguments
+// that are not an object) sends {}.
+func toolUseInput(args string) json.RawMessage {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal([]byte(args), &obj) != nil || obj == nil {
+		return json.RawMessage(`{}`)
+	}
+	return json.RawMessage(args)
+}
+
+// anthropicFinishReason maps a stop_reason to the OpenAI-style finish
+// reason the runtime uses; reasons without an equivalent pass through.
+func anthropicFinishReason(stopReason string) string {
+	switch stopReason {
+	case "tool_use":
+		return "tool_calls"
+	case "end_turn", "":
+		return "stop"
+	default:
+		return stopReason
+	}
+}
+
 // Anthropic-specific response types.
 type anthropicResponse struct {
 	ID         string                  `json:"id"`
@@ -358,20 +422,16 @@ func (c *AnthropicClient) parseAnthropicResponse(body io.Reader) (*llm.ChatRespo
 				Type: "function",
 				Function: llm.FunctionCall{
 					Name:      block.Name,
-					Arguments: string(block.Input),
+					Arguments: string(toolUseInput(string(block.Input))),
 				},
 			})
+		case "thinking":
+			msg.Thinking = append(msg.Thinking, llm.ThinkingBlock{Text: block.Thinking, Signature: block.Signature})
+		case "redacted_thinking":
+			msg.Thinking = append(msg.Thinking, llm.ThinkingBlock{Data: block.Data})
 		}
 	}
-
-	finishReason := "stop"
-	if resp.StopReason == "tool_use" {
-		finishReason = "tool_calls"
-	} else if resp.StopReason == "end_turn" {
-		finishReason = "stop"
-	} else if resp.StopReason != "" {
-		finishReason = resp.StopReason
-	}
+	finishReason := anthropicFinishReason(resp.StopReason)
 
 	return &llm.ChatResponse{
 		ID:      resp.ID,
@@ -386,6 +446,14 @@ func (c *AnthropicClient) parseAnthropicResponse(body io.Reader) (*llm.ChatRespo
 }
 
 // Anthropic streaming event types.
+type anthropicMessageStart struct {
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
 type anthropicContentBlockStart struct {
 	Index        int                   `json:"index"`
 	ContentBlock anthropicContentBlock `json:"content_block"`
@@ -397,9 +465,15 @@ type anthropicContentBlockDelta struct {
 		Type        string `json:"type"`
 		Text        string `json:"text,omitempty"`
 		PartialJSON string `json:"partial_json,omitempty"`
+		Thinking    string `json:"thinking,omitempty"`
+		Signature   string `json:"signature,omitempty"`
 	} `json:"delta"`
 }
 
+type anthropicContentBlockStop struct {
+	Index int `json:"index"`
+}
+
 type anthropicMessageDelta struct {
 	Delta struct {
 		StopReason string `json:"stop_reason"`
@@ -409,9 +483,16 @@ type anthropicMessageDelta struct {
 	} `json:"usage"`
 }
 
+// readAnthropicStream turns the SSE events of a streamed message into
+// deltas. Text streams as it arrives; a tool_use block is sent whole
+// once its input_json deltas are complete, and a thinking block once
+// its signature has arrived. Blocks are tracked by index, since
+// thinking, text and tool_use blocks interleave.
 func (c *AnthropicClient) readAnthropicStream(r io.Reader, ch chan<- llm.StreamDelta) {
 	scanner := bufio.NewScanner(r)
-	var currentToolCall *llm.ToolCall
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	blocks := map[int]*anthropicContentBlock{}
+	var inputTokens int
 	var eventType string
 
 	for scanner.Scan() {
@@ -428,41 +509,68 @@ func (c *AnthropicClient) readAnthropicStream(r io.Reader, ch chan<- llm.StreamD
 		}
 
 		switch eventType {
+		case "message_start":
+			var ev anthropicMessageStart
+			if json.Unmarshal([]byte(after), &ev) == nil {
+				inputTokens = ev.Message.Usage.InputTokens
+			}
+
 		case "content_block_start":
 			var ev anthropicContentBlockStart
 			if json.Unmarshal([]byte(after), &ev) != nil {
 				continue
 			}
-			if ev.ContentBlock.Type == "tool_use" {
-				currentToolCall = &llm.ToolCall{
-					ID:   ev.ContentBlock.ID,
-					Type: "function",
-					Function: llm.FunctionCall{
-						Name: ev.ContentBlock.Name,
-					},
-				}
-			}
+			block := ev.ContentBlock
+			block.Input = nil // streamed as input_json deltas
+			blocks[ev.Index] = &block
 
 		case "content_block_delta":
 			var ev anthropicContentBlockDelta
 			if json.Unmarshal([]byte(after), &ev) != nil {
 				continue
 			}
+			block := blocks[ev.Index]
 			switch ev.Delta.Type {
 			case "text_delta":
 				ch <- llm.StreamDelta{Content: ev.Delta.Text}
 			case "input_json_delta":
-				if currentToolCall != nil {
-					currentToolCall.Function.Arguments += ev.Delta.PartialJSON
+				if block != nil {
+					block.Input = append(block.Input, ev.Delta.PartialJSON...)
+				}
+			case "thinking_delta":
+				if block != nil {
+					block.Thinking += ev.Delta.Thinking
+				}
+			case "signature_delta":
+				if block != nil {
+					block.Signature += ev.Delta.Signature
 				}
 			}
 
 		case "content_block_stop":
-			if currentToolCall != nil {
-				ch <- llm.StreamDelta{
-					ToolCalls: []llm.ToolCall{*currentToolCall},
-				}
-				currentToolCall = nil
+			var ev anthropicContentBlockStop
+			if json.Unmarshal([]byte(after), &ev) != nil {
+				continue
+			}
+			block := blocks[ev.Index]
+			delete(blocks, ev.Index)
+			if block == nil {
+				continue
+			}
+			switch block.Type {
+			case "tool_use":
+				ch <- llm.StreamDelta{ToolCalls: []llm.ToolCall{{
+					ID:   block.ID,
+					Type: "function",
+					Function: llm.FunctionCall{
+						Name:      block.Name,
+						Arguments: string(toolUseInput(string(block.Input))),
+					},
+				}}}
+			case "thinking":
+				ch <- llm.StreamDelta{Thinking: []llm.ThinkingBlock{{Text: block.Thinking, Signature: block.Signature}}}
+			case "redacted_thinking":
+				ch <- llm.StreamDelta{Thinking: []llm.ThinkingBlock{{Data: block.Data}}}
 			}
 
 		case "message_delta":
@@ -470,14 +578,12 @@ func (c *AnthropicClient) readAnthropicStream(r io.Reader, ch chan<- llm.StreamD
 			if json.Unmarshal([]byte(after), &ev) != nil {
 				continue
 			}
-			finishReason := "stop"
-			if ev.Delta.StopReason == "tool_use" {
-				finishReason = "tool_calls"
-			}
 			ch <- llm.StreamDelta{
-				FinishReason: finishReason,
+				FinishReason: anthropicFinishReason(ev.Delta.StopReason),
 				Usage: &llm.UsageInfo{
+					InputTokens:  inputTokens,
 					OutputTokens: ev.Usage.OutputTokens,
+					TotalTokens:  inputTokens + ev.Usage.OutputTokens,
 				},
 			}
 
@@ -0,0 +1,134 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestAnthropic_ToolUseRoundTrip(t *testing.T) {
+	c := NewAnthropicClient(llm.ClientConfig{Model: "claude-sonnet-4-6"})
+	body := c.toAnthropicRequest(&llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleUser, Content: "weather in Paris and Rome?"},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+				{ID: "tu_1", Type: "function", Function: llm.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+				{ID: "tu_2", Type: "function", Function: llm.FunctionCall{Name: "now"}},
+			}},
+			{Role: llm.RoleTool, ToolCallID: "tu_1", Content: "sunny"},
+			{Role: llm.RoleTool, ToolCallID: "tu_2", Content: "12:00"},
+		},
+		Tools: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "now"}}},
+	}, false)
+
+	if len(body.Messages) != 3 {
+		t.Fatalf("messages = %d, want the tool results merged into one user turn", len(body.Messages))
+	}
+	var calls []anthropicContentBlock
+	_ = json.Unmarshal(body.Messages[1].Content, &calls)
+	if len(calls) != 2 || calls[1].Type != "tool_use" || string(calls[1].Input) != "{}" {
+		t.Errorf("tool_use blocks = %+v, want input {} for a call without arguments", calls)
+	}
+	var results []anthropicContentBlock
+	_ = json.Unmarshal(body.Messages[2].Content, &results)
+	if body.Messages[2].Role != "user" || len(results) != 2 || results[1].ToolUseID != "tu_2" {
+		t.Errorf("tool results = %s %+v", body.Messages[2].Role, results)
+	}
+	if string(body.Tools[0].InputSchema) != `{"type":"object","properties":{}}` {
+		t.Errorf("input_schema = %s, want an empty object schema", body.Tools[0].InputSchema)
+	}
+}
+
+func TestAnthropic_Thinking(t *testing.T) {
+	var sent anthropicRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		_, _ = io.WriteString(w, `{"id":"msg_1","stop_reason":"tool_use","usage":{"input_tokens":10,"output_tokens":20},"content":[
+			{"type":"thinking","thinking":"look it up","signature":"sig"},
+			{"type":"redacted_thinking","data":"enc"},
+			{"type":"tool_use","id":"tu_1","name":"weather","input":{"city":"Paris"}}]}`)
+	}))
+	defer srv.Close()
+
+	c := NewAnthropicClient(llm.ClientConfig{APIKey: "x", BaseURL: srv.URL, Model: "claude-sonnet-4-6", ThinkingBudget: 8000})
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.Thinking == nil || sent.Thinking.BudgetTokens != 8000 || sent.MaxTokens <= 8000 {
+		t.Errorf("request thinking = %+v, max_tokens = %d", sent.Thinking, sent.MaxTokens)
+	}
+	want := []llm.ThinkingBlock{{Text: "look it up", Signature: "sig"}, {Data: "enc"}}
+	if len(resp.Message.Thinking) != 2 || resp.Message.Thinking[0] != want[0] || resp.Message.Thinking[1] != want[1] {
+		t.Errorf("thinking = %+v", resp.Message.Thinking)
+	}
+	if resp.FinishReason != "tool_calls" || resp.Message.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("response = %+v", resp)
+	}
+
+	// The blocks go back, first and verbatim, with the tool calls.
+	body := c.toAnthropicRequest(&llm.ChatRequest{Messages: []llm.ChatMessage{resp.Message}}, false)
+	raw := string(body.Messages[0].Content)
+	if !strings.HasPrefix(raw, `[{"type":"thinking","thinking":"look it up","signature":"sig"},{"type":"redacted_thinking","data":"enc"},{"type":"tool_use"`) {
+		t.Errorf("assistant content = %s", raw)
+	}
+
+	off := NewAnthropicClient(llm.ClientConfig{Model: "claude-sonnet-4-6"})
+	if body := off.toAnthropicRequest(&llm.ChatRequest{}, false); body.Thinking != nil || body.MaxTokens != 4096 {
+		t.Errorf("thinking off: %+v", body)
+	}
+}
+
+func TestAnthropic_Stream(t *testing.T) {
+	stream := strings.Join([]string{
+		"event: message_start", `data: {"type":"message_start","message":{"usage":{"input_tokens":25}}}`,
+		"event: content_block_start", `data: {"index":0,"content_block":{"type":"thinking","thinking":""}}`,
+		"event: content_block_delta", `data: {"index":0,"delta":{"type":"thinking_delta","thinking":"need "}}`,
+		"event: content_block_delta", `data: {"index":0,"delta":{"type":"thinking_delta","thinking":"weather"}}`,
+		"event: content_block_delta", `data: {"index":0,"delta":{"type":"signature_delta","signature":"sig"}}`,
+		"event: content_block_stop", `data: {"index":0}`,
+		"event: content_block_start", `data: {"index":1,"content_block":{"type":"text","text":""}}`,
+		"event: content_block_delta", `data: {"index":1,"delta":{"type":"text_delta","text":"Checking."}}`,
+		"event: content_block_stop", `data: {"index":1}`,
+		"event: content_block_start", `data: {"index":2,"content_block":{"type":"tool_use","id":"tu_1","name":"weather","input":{}}}`,
+		"event: content_block_delta", `data: {"index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+		"event: content_block_delta", `data: {"index":2,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+		"event: content_block_stop", `data: {"index":2}`,
+		"event: content_block_start", `data: {"index":3,"content_block":{"type":"tool_use","id":"tu_2","name":"now","input":{}}}`,
+		"event: content_block_stop", `data: {"index":3}`,
+		"event: message_delta", `data: {"delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":40}}`,
+		"event: message_stop", `data: {}`,
+	}, "\n")
+	c := NewAnthropicClient(llm.ClientConfig{Model: "claude-sonnet-4-6"})
+	ch := make(chan llm.StreamDelta, 32)
+	c.readAnthropicStream(strings.NewReader(stream), ch)
+	close(ch)
+
+	var text string
+	var calls []llm.ToolCall
+	var thinking []llm.ThinkingBlock
+	var last llm.StreamDelta
+	for d := range ch {
+		text += d.Content
+		calls = append(calls, d.ToolCalls...)
+		thinking = append(thinking, d.Thinking...)
+		if d.Usage != nil {
+			last = d
+		}
+	}
+	if text != "Checking." || len(thinking) != 1 || thinking[0] != (llm.ThinkingBlock{Text: "need weather", Signature: "sig"}) {
+		t.Errorf("text = %q, thinking = %+v", text, thinking)
+	}
+	if len(calls) != 2 || calls[0].Function.Arguments != `{"city":"Paris"}` || calls[1].Function.Arguments != "{}" {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if last.FinishReason != "tool_calls" || last.Usage.InputTokens != 25 || last.Usage.TotalTokens != 65 {
+		t.Errorf("final delta = %+v %+v", last, last.Usage)
+	}
+}
@@ -0,0 +1,337 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// CohereClient implements llm.Client for the Cohere v2 Chat API
+// (Command R / Command A family).
+type CohereClient struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewCohereClient creates a new Cohere client.
+func NewCohereClient(cfg llm.ClientConfig) *CohereClient {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.cohere.com"
+	}
+	return &CohereClient{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   cfg.Model,
+		client:  newHTTPClient(cfg, 120*time.Second),
+	}
+}
+
+func (c *CohereClient) ModelID() string { return c.model }
+
+// Chat sends a non-streaming chat request.
+func (c *CohereClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	body := c.toCohereRequest(req, false)
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/v2/chat"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(httpReq)
+
+	safeEndpoint := sanitizeEndpoint(endpoint)
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("cohere request to %s: %w", safeEndpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("cohere error (status %d) calling %s: %s", resp.StatusCode, safeEndpoint, string(respBody))
+	}
+
+	result, err := c.parseCohereResponse(resp.Body)
+	if result != nil {
+		result.Endpoint = safeEndpoint
+	}
+	return result, err
+}
+
+// ChatStream sends a streaming chat request.
+func (c *CohereClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	body := c.toCohereRequest(req, true)
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	endpoint := c.baseURL + "/v2/chat"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(httpReq)
+
+	safeEndpoint := sanitizeEndpoint(endpoint)
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("cohere stream request to %s: %w", safeEndpoint, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("cohere stream error (status %d) calling %s: %s", resp.StatusCode, safeEndpoint, string(respBody))
+	}
+
+	ch := make(chan llm.StreamDelta, 32)
+	go func() {
+		defer func() { _ = resp.Body.Close() }()
+		defer close(ch)
+		c.readCohereStream(resp.Body, ch)
+	}()
+
+	return ch, nil
+}
+
+func (c *CohereClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+}
+
+// Cohere-specific request types.
+type cohereRequest struct {
+	Model       string               `json:"model"`
+	Messages    []cohereMessage      `json:"messages"`
+	Tools       []llm.ToolDefinition `json:"tools,omitempty"`
+	Temperature *float64             `json:"temperature,omitempty"`
+	MaxTokens   int                  `json:"max_tokens,omitempty"`
+	Stream      bool                 `json:"stream,omitempty"`
+}
+
+// cohereMessage is a v2 chat message. Assistant turns that call tools carry
+// their reasoning in tool_plan rather than content; Cohere rejects an
+// assistant message with both tool_calls and content.
+type cohereMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content,omitempty"`
+	ToolPlan   string         `json:"tool_plan,omitempty"`
+	ToolCalls  []llm.ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+func (c *CohereClient) toCohereRequest(req *llm.ChatRequest, stream bool) cohereRequest {
+	model := req.Model
+	if model == "" {
+		model = c.model
+	}
+
+	msgs := make([]cohereMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		msg := cohereMessage{
+			Role:       m.Role,
+			ToolCallID: m.ToolCallID,
+		}
+		if m.Role == llm.RoleAssistant && len(m.ToolCalls) > 0 {
+			msg.ToolPlan = m.Content
+			msg.ToolCalls = m.ToolCalls
+		} else {
+			msg.Content = m.Content
+		}
+		msgs[i] = msg
+	}
+
+	return cohereRequest{
+		Model:       model,
+		Messages:    msgs,
+		Tools:       req.Tools,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      stream,
+	}
+}
+
+// Cohere-specific response types.
+type cohereResponse struct {
+	ID           string `json:"id"`
+	FinishReason string `json:"finish_reason"`
+	Message      struct {
+		Role    string `json:"role"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		ToolPlan  string         `json:"tool_plan"`
+		ToolCalls []llm.ToolCall `json:"tool_calls"`
+	} `json:"message"`
+	Usage cohereUsage `json:"usage"`
+}
+
+// cohereUsage reports both the tokens the model saw (tokens) and the
+// tokens billed (billed_units). Forge records the former when present.
+type cohereUsage struct {
+	BilledUnits cohereTokenCounts `json:"billed_units"`
+	Tokens      cohereTokenCounts `json:"tokens"`
+}
+
+type cohereTokenCounts struct {
+	InputTokens  float64 `json:"input_tokens"`
+	OutputTokens float64 `json:"output_tokens"`
+}
+
+func (u cohereUsage) toUsageInfo() llm.UsageInfo {
+	counts := u.Tokens
+	if counts.InputTokens == 0 && counts.OutputTokens == 0 {
+		counts = u.BilledUnits
+	}
+	in, out := int(counts.InputTokens), int(counts.OutputTokens)
+	return llm.UsageInfo{InputTokens: in, OutputTokens: out, TotalTokens: in + out}
+}
+
+// cohereFinishReason maps Cohere's upper-case finish reasons onto the
+// OpenAI-style values the executor checks.
+func cohereFinishReason(reason string) string {
+	switch reason {
+	case "COMPLETE", "STOP_SEQUENCE", "":
+		return "stop"
+	case "TOOL_CALL":
+		return "tool_calls"
+	case "MAX_TOKENS":
+		return "length"
+	default:
+		return strings.ToLower(reason)
+	}
+}
+
+func (c *CohereClient) parseCohereResponse(body io.Reader) (*llm.ChatResponse, error) {
+	var resp cohereResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decoding cohere response: %w", err)
+	}
+
+	msg := llm.ChatMessage{Role: llm.RoleAssistant}
+	for _, block := range resp.Message.Content {
+		if block.Type == "text" {
+			msg.Content += block.Text
+		}
+	}
+	if msg.Content == "" {
+		msg.Content = resp.Message.ToolPlan
+	}
+	for _, tc := range resp.Message.ToolCalls {
+		if tc.Type == "" {
+			tc.Type = "function"
+		}
+		msg.ToolCalls = append(msg.ToolCalls, tc)
+	}
+
+	return &llm.ChatResponse{
+		ID:           resp.ID,
+		Message:      msg,
+		Usage:        resp.Usage.toUsageInfo(),
+		FinishReason: cohereFinishReason(resp.FinishReason),
+	}, nil
+}
+
+// cohereStreamEvent is a v2 streaming event. The event type is repeated in
+// the data payload, so the SSE "event:" lines can be ignored. Within a
+// delta, tool_calls is a single object, not an array.
+type cohereStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Message struct {
+			Content struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			ToolPlan  string `json:"tool_plan"`
+			ToolCalls struct {
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string       `json:"finish_reason"`
+		Usage        *cohereUsage `json:"usage"`
+	} `json:"delta"`
+}
+
+func (c *CohereClient) readCohereStream(r io.Reader, ch chan<- llm.StreamDelta) {
+	scanner := bufio.NewScanner(r)
+	var currentToolCall *llm.ToolCall
+
+	for scanner.Scan() {
+		after, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+
+		var ev cohereStreamEvent
+		if json.Unmarshal([]byte(after), &ev) != nil {
+			continue
+		}
+
+		switch ev.Type {
+		case "content-delta":
+			ch <- llm.StreamDelta{Content: ev.Delta.Message.Content.Text}
+
+		case "tool-plan-delta":
+			ch <- llm.StreamDelta{Content: ev.Delta.Message.ToolPlan}
+
+		case "tool-call-start":
+			tc := ev.Delta.Message.ToolCalls
+			currentToolCall = &llm.ToolCall{
+				ID:   tc.ID,
+				Type: "function",
+				Function: llm.FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			}
+
+		case "tool-call-delta":
+			if currentToolCall != nil {
+				currentToolCall.Function.Arguments += ev.Delta.Message.ToolCalls.Function.Arguments
+			}
+
+		case "tool-call-end":
+			if currentToolCall != nil {
+				ch <- llm.StreamDelta{
+					ToolCalls: []llm.ToolCall{*currentToolCall},
+				}
+				currentToolCall = nil
+			}
+
+		case "message-end":
+			delta := llm.StreamDelta{
+				FinishReason: cohereFinishReason(ev.Delta.FinishReason),
+				Done:         true,
+			}
+			if ev.Delta.Usage != nil {
+				usage := ev.Delta.Usage.toUsageInfo()
+				delta.Usage = &usage
+			}
+			ch <- delta
+			return
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+const (
+	cohereDefaultEmbeddingModel = "embed-english-v3.0"
+	cohereDefaultEmbeddingDims  = 1024
+)
+
+// CohereEmbedder implements llm.Embedder using the Cohere v2 Embed API.
+type CohereEmbedder struct {
+	apiKey  string
+	baseURL string
+	model   string
+	dims    int
+	client  *http.Client
+}
+
+// NewCohereEmbedder creates a Cohere embedder. It reuses
+// OpenAIEmbedderConfig so the embedder factory can treat every provider
+// alike; OrgID is ignored.
+func NewCohereEmbedder(cfg OpenAIEmbedderConfig) *CohereEmbedder {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.cohere.com"
+	}
+	model := cfg.Model
+	if model == "" {
+		model = cohereDefaultEmbeddingModel
+	}
+	dims := cfg.Dims
+	if dims <= 0 {
+		dims = cohereDefaultEmbeddingDims
+	}
+	return &CohereEmbedder{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   model,
+		dims:    dims,
+		client:  &http.Client{Timeout: embeddingTimeout},
+	}
+}
+
+func (e *CohereEmbedder) Dimensions() int { return e.dims }
+
+// Embed produces embeddings for the given texts using POST /v2/embed.
+// Texts are embedded as search documents; Cohere's v3 models also accept
+// "search_query", but memory stores and queries through the same call, and
+// document embeddings retrieve well against themselves.
+func (e *CohereEmbedder) Embed(ctx context.Context, req *llm.EmbeddingRequest) (*llm.EmbeddingResponse, error) {
+	if len(req.Texts) == 0 {
+		return &llm.EmbeddingResponse{Model: e.model}, nil
+	}
+
+	model := req.Model
+	if model == "" {
+		model = e.model
+	}
+
+	body := cohereEmbedRequest{
+		Model:          model,
+		Texts:          req.Texts,
+		InputType:      "search_document",
+		EmbeddingTypes: []string{"float"},
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling embedding request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/v2/embed", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embedding error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var embResp cohereEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("decoding embedding response: %w", err)
+	}
+
+	in := int(embResp.Meta.BilledUnits.InputTokens)
+	return &llm.EmbeddingResponse{
+		Embeddings: embResp.Embeddings.Float,
+		Model:      model,
+		Usage: llm.UsageInfo{
+			InputTokens: in,
+			TotalTokens: in,
+		},
+	}, nil
+}
+
+// cohereEmbedRequest is the Cohere v2 embed API request format.
+type cohereEmbedRequest struct {
+	Model          string   `json:"model"`
+	Texts          []string `json:"texts"`
+	InputType      string   `json:"input_type"`
+	EmbeddingTypes []string `json:"embedding_types"`
+}
+
+// cohereEmbedResponse is the Cohere v2 embed API response format.
+type cohereEmbedResponse struct {
+	Embeddings struct {
+		Float [][]float32 `json:"float"`
+	} `json:"embeddings"`
+	Meta struct {
+		BilledUnits cohereTokenCounts `json:"billed_units"`
+	} `json:"meta"`
+}
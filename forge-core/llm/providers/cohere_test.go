@@ -0,0 +1,165 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestCohereClient_ChatToolCalls(t *testing.T) {
+	var gotBody cohereRequest
+	var gotAuth, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "c-1",
+			"finish_reason": "TOOL_CALL",
+			"message": {
+				"role": "assistant",
+				"tool_plan": "I will look up the weather.",
+				"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "weather", "arguments": "{\"city\":\"Paris\"}"}}]
+			},
+			"usage": {"billed_units": {"input_tokens": 5, "output_tokens": 3}, "tokens": {"input_tokens": 40, "output_tokens": 12}}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := NewCohereClient(llm.ClientConfig{APIKey: "co-test", Model: "command-r-plus", BaseURL: srv.URL})
+	resp, err := client.Chat(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: "be brief"},
+			{Role: llm.RoleUser, Content: "weather?"},
+			{Role: llm.RoleAssistant, Content: "Checking.", ToolCalls: []llm.ToolCall{{ID: "call_0", Type: "function", Function: llm.FunctionCall{Name: "weather", Arguments: "{}"}}}},
+			{Role: llm.RoleTool, ToolCallID: "call_0", Content: "sunny"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+
+	if gotPath != "/v2/chat" {
+		t.Errorf("path = %q, want /v2/chat", gotPath)
+	}
+	if gotAuth != "Bearer co-test" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if len(gotBody.Messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(gotBody.Messages))
+	}
+	asst := gotBody.Messages[2]
+	if asst.Content != "" || asst.ToolPlan != "Checking." || len(asst.ToolCalls) != 1 {
+		t.Errorf("assistant tool-call turn not mapped to tool_plan: %+v", asst)
+	}
+	if gotBody.Messages[3].ToolCallID != "call_0" {
+		t.Errorf("tool result lost tool_call_id: %+v", gotBody.Messages[3])
+	}
+
+	if resp.FinishReason != "tool_calls" {
+		t.Errorf("FinishReason = %q, want tool_calls", resp.FinishReason)
+	}
+	if resp.Message.Content != "I will look up the weather." {
+		t.Errorf("Content = %q, want tool plan", resp.Message.Content)
+	}
+	if len(resp.Message.ToolCalls) != 1 || resp.Message.ToolCalls[0].Function.Name != "weather" {
+		t.Errorf("ToolCalls = %+v", resp.Message.ToolCalls)
+	}
+	if resp.Usage.InputTokens != 40 || resp.Usage.OutputTokens != 12 || resp.Usage.TotalTokens != 52 {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+	if resp.Endpoint != srv.URL+"/v2/chat" {
+		t.Errorf("Endpoint = %q", resp.Endpoint)
+	}
+}
+
+func TestCohereClient_ChatStream(t *testing.T) {
+	events := []string{
+		`{"type":"message-start","id":"c-2"}`,
+		`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"Hel"}}}}`,
+		`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"lo"}}}}`,
+		`{"type":"tool-call-start","index":0,"delta":{"message":{"tool_calls":{"id":"call_1","type":"function","function":{"name":"weather","arguments":""}}}}}`,
+		`{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"{\"city\":"}}}}}`,
+		`{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"\"Paris\"}"}}}}}`,
+		`{"type":"tool-call-end","index":0}`,
+		`{"type":"message-end","delta":{"finish_reason":"TOOL_CALL","usage":{"billed_units":{"input_tokens":7,"output_tokens":4}}}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, ev := range events {
+			var typ struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal([]byte(ev), &typ)
+			_, _ = w.Write([]byte("event: " + typ.Type + "\ndata: " + ev + "\n\n"))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewCohereClient(llm.ClientConfig{APIKey: "co-test", BaseURL: srv.URL})
+	ch, err := client.ChatStream(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+
+	var content strings.Builder
+	var calls []llm.ToolCall
+	var last llm.StreamDelta
+	for d := range ch {
+		content.WriteString(d.Content)
+		calls = append(calls, d.ToolCalls...)
+		last = d
+	}
+
+	if content.String() != "Hello" {
+		t.Errorf("content = %q, want Hello", content.String())
+	}
+	if len(calls) != 1 || calls[0].ID != "call_1" || calls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if !last.Done || last.FinishReason != "tool_calls" {
+		t.Errorf("final delta = %+v", last)
+	}
+	if last.Usage == nil || last.Usage.InputTokens != 7 || last.Usage.OutputTokens != 4 {
+		t.Errorf("usage = %+v", last.Usage)
+	}
+}
+
+func TestCohereEmbedder_Embed(t *testing.T) {
+	var gotBody cohereEmbedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/embed" {
+			t.Errorf("path = %q, want /v2/embed", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"embeddings":{"float":[[0.1,0.2],[0.3,0.4]]},"meta":{"billed_units":{"input_tokens":6}}}`))
+	}))
+	defer srv.Close()
+
+	emb := NewCohereEmbedder(OpenAIEmbedderConfig{APIKey: "co-test", BaseURL: srv.URL})
+	if emb.Dimensions() != cohereDefaultEmbeddingDims {
+		t.Errorf("Dimensions = %d, want %d", emb.Dimensions(), cohereDefaultEmbeddingDims)
+	}
+	resp, err := emb.Embed(context.Background(), &llm.EmbeddingRequest{Texts: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if gotBody.Model != cohereDefaultEmbeddingModel || gotBody.InputType != "search_document" {
+		t.Errorf("request = %+v", gotBody)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1][1] != 0.4 {
+		t.Errorf("Embeddings = %v", resp.Embeddings)
+	}
+	if resp.Usage.InputTokens != 6 {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+}
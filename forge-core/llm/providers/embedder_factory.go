@@ -7,22 +7,52 @@ import (
 )
 
 // NewEmbedder creates an Embedder for the specified provider.
-// Supported providers: "openai", "gemini", "ollama".
-// Returns an error for "anthropic" (no embedding API).
+// Supported providers: "openai", "gemini", "voyage", "ollama", "local",
+// "cohere", "mistral", "together". Returns an error for "anthropic",
+// "groq" and "openrouter" (no embedding API).
 func NewEmbedder(provider string, cfg OpenAIEmbedderConfig) (llm.Embedder, error) {
 	switch provider {
 	case "openai":
 		return NewOpenAIEmbedder(cfg), nil
 	case "gemini":
-		if cfg.BaseURL == "" {
-			cfg.BaseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
-		}
-		return NewOpenAIEmbedder(cfg), nil
+		return NewGeminiEmbedder(cfg), nil
+	case "voyage":
+		return NewVoyageEmbedder(cfg), nil
+	case "local":
+		return NewLocalEmbedder(cfg), nil
 	case "ollama":
 		return NewOllamaEmbedder(cfg), nil
+	case "cohere":
+		return NewCohereEmbedder(cfg), nil
+	case "mistral":
+		return NewMistralEmbedder(cfg), nil
 	case "anthropic":
-		return nil, fmt.Errorf("anthropic does not provide an embedding API; configure an alternative embedding provider")
+		return nil, fmt.Errorf("anthropic does not provide an embedding API; use voyage, local, or another embedding provider")
 	default:
+		if p, ok := LookupPreset(provider); ok {
+			return newPresetEmbedder(provider, p, cfg)
+		}
 		return nil, fmt.Errorf("unknown embedding provider: %q", provider)
 	}
 }
+
+// EmbeddingAPIKeyEnv returns the env var an embedding provider's API key
+// is read from, or "" for providers that need none (ollama, local).
+func EmbeddingAPIKeyEnv(provider string) string {
+	switch provider {
+	case "openai":
+		return "OPENAI_API_KEY"
+	case "gemini":
+		return "GEMINI_API_KEY"
+	case "voyage":
+		return "VOYAGE_API_KEY"
+	case "cohere":
+		return "COHERE_API_KEY"
+	case "mistral":
+		return "MISTRAL_API_KEY"
+	}
+	if p, ok := LookupPreset(provider); ok {
+		return p.APIKeyEnvVar
+	}
+	return ""
+}
@@ -12,7 +12,14 @@ func TestNewEmbedder(t *testing.T) {
 	}{
 		{"openai", "openai", false},
 		{"gemini", "gemini", false},
+		{"voyage", "voyage", false},
+		{"local", "local", false},
 		{"ollama", "ollama", false},
+		{"cohere", "cohere", false},
+		{"mistral", "mistral", false},
+		{"together", "together", false},
+		{"groq", "groq", true},
+		{"openrouter", "openrouter", true},
 		{"anthropic", "anthropic", true},
 		{"unknown", "unknown", true},
 	}
@@ -7,7 +7,9 @@ import (
 )
 
 // NewClient creates an LLM client for the specified provider.
-// Supported providers: "openai", "anthropic", "ollama".
+// Supported providers: "openai", "anthropic", "gemini", "ollama",
+// "cohere", "mistral", plus the OpenAI-compatible presets (see
+// PresetNames).
 func NewClient(provider string, cfg llm.ClientConfig) (llm.Client, error) {
 	switch provider {
 	case "openai":
@@ -15,13 +17,28 @@ func NewClient(provider string, cfg llm.ClientConfig) (llm.Client, error) {
 	case "anthropic":
 		return NewAnthropicClient(cfg), nil
 	case "gemini":
-		if cfg.BaseURL == "" {
-			cfg.BaseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
-		}
-		return NewOpenAIClient(cfg), nil
+		return NewGeminiClient(cfg), nil
 	case "ollama":
 		return NewOllamaClient(cfg), nil
+	case "cohere":
+		return NewCohereClient(cfg), nil
+	case "mistral":
+		return NewMistralClient(cfg), nil
 	default:
+		if p, ok := LookupPreset(provider); ok {
+			return newPresetClient(p, cfg), nil
+		}
 		return nil, fmt.Errorf("unknown LLM provider: %q", provider)
 	}
 }
+
+// IsSupported reports whether NewClient can build a client for
+// provider.
+func IsSupported(provider string) bool {
+	switch provider {
+	case "openai", "anthropic", "gemini", "ollama", "cohere", "mistral":
+		return true
+	}
+	_, ok := LookupPreset(provider)
+	return ok
+}
@@ -0,0 +1,543 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// GeminiClient implements llm.Client for the native Gemini API
+// (generateContent): function calling through functionDeclarations and
+// functionCall / functionResponse parts, optional Google Search
+// grounding and per-category safety settings. cfg.BaseURL may name the
+// API root or the OpenAI-compatible path earlier releases used.
+type GeminiClient struct {
+	apiKey          string
+	baseURL         string
+	model           string
+	authScheme      string
+	authHeaderName  string
+	reasoningEffort string
+	googleSearch    bool
+	safety          []geminiSafetySetting
+	client          *http.Client
+}
+
+// NewGeminiClient creates a new Gemini client.
+func NewGeminiClient(cfg llm.ClientConfig) *GeminiClient {
+	baseURL := strings.TrimSuffix(strings.TrimRight(cfg.BaseURL, "/"), "/openai")
+	if baseURL == "" {
+		baseURL = geminiDefaultBaseURL
+	}
+	return &GeminiClient{
+		apiKey:          cfg.APIKey,
+		baseURL:         baseURL,
+		model:           strings.TrimPrefix(cfg.Model, "models/"),
+		authScheme:      cfg.AuthScheme,
+		authHeaderName:  cfg.AuthHeaderName,
+		reasoningEffort: cfg.ReasoningEffort,
+		googleSearch:    cfg.GoogleSearch,
+		safety:          geminiSafety(cfg.SafetySettings),
+		client:          newHTTPClient(cfg, 120*time.Second),
+	}
+}
+
+func (c *GeminiClient) ModelID() string { return c.model }
+
+// Chat sends a non-streaming generateContent request.
+func (c *GeminiClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	data, err := json.Marshal(c.toGeminiRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	endpoint := c.endpoint(req, "generateContent")
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(httpReq)
+
+	safeEndpoint := sanitizeEndpoint(endpoint)
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini request to %s: %w", safeEndpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("gemini error (status %d) calling %s: %s", resp.StatusCode, safeEndpoint, string(respBody))
+	}
+
+	var gr geminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return nil, fmt.Errorf("decoding gemini response: %w", err)
+	}
+	result, err := gr.toChatResponse()
+	if result != nil {
+		result.Endpoint = safeEndpoint
+	}
+	return result, err
+}
+
+// ChatStream sends a streamGenerateContent request.
+func (c *GeminiClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	data, err := json.Marshal(c.toGeminiRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	endpoint := c.endpoint(req, "streamGenerateContent") + "?alt=sse"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(httpReq)
+
+	safeEndpoint := sanitizeEndpoint(endpoint)
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini stream request to %s: %w", safeEndpoint, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("gemini stream error (status %d) calling %s: %s", resp.StatusCode, safeEndpoint, string(respBody))
+	}
+
+	ch := make(chan llm.StreamDelta, 32)
+	go func() {
+		defer func() { _ = resp.Body.Close() }()
+		defer close(ch)
+		readGeminiStream(resp.Body, ch)
+	}()
+
+	return ch, nil
+}
+
+func (c *GeminiClient) endpoint(req *llm.ChatRequest, method string) string {
+	model := strings.TrimPrefix(req.Model, "models/")
+	if model == "" {
+		model = c.model
+	}
+	return c.baseURL + "/models/" + model + ":" + method
+}
+
+func (c *GeminiClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if c.authScheme != llm.AuthSchemeAPIKeyHeaderOnly && c.apiKey != "" {
+		req.Header.Set("x-goog-api-key", c.apiKey)
+	}
+	setGatewayAPIKeyHeader(req, c.authScheme, c.authHeaderName, c.apiKey)
+}
+
+// Gemini-specific request types.
+type geminiRequest struct {
+	Contents          []geminiContent         `json:"contents"`
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Tools             []geminiTool            `json:"tools,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+	SafetySettings    []geminiSafetySetting   `json:"safetySettings,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	Thought          bool                    `json:"thought,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+	// ThoughtSignature lets a thinking model resume its reasoning when
+	// its function calls come back; it must be returned on the part
+	// that carried it.
+	ThoughtSignature string `json:"thoughtSignature,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations,omitempty"`
+	GoogleSearch         *struct{}                   `json:"googleSearch,omitempty"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// ParametersJSONSchema takes the tool's JSON Schema as is, where
+	// the older `parameters` field accepts only an OpenAPI subset.
+	ParametersJSONSchema json.RawMessage `json:"parametersJsonSchema,omitempty"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature     *float64              `json:"temperature,omitempty"`
+	MaxOutputTokens int                   `json:"maxOutputTokens,omitempty"`
+	ThinkingConfig  *geminiThinkingConfig `json:"thinkingConfig,omitempty"`
+}
+
+type geminiThinkingConfig struct {
+	ThinkingBudget int `json:"thinkingBudget"`
+}
+
+type geminiSafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// geminiThinkingBudgets maps reasoning_effort onto thinking token
+// budgets, as Gemini's OpenAI-compatible endpoint does.
+var geminiThinkingBudgets = map[string]int{"low": 1024, "medium": 8192, "high": 24576}
+
+func (c *GeminiClient) toGeminiRequest(req *llm.ChatRequest) geminiRequest {
+	var r geminiRequest
+	var system []string
+	toolNames := map[string]string{} // tool call ID → function name
+	add := func(role string, parts ...geminiPart) {
+		// Consecutive turns of one role become one content, as the
+		// results of one turn's function calls must.
+		if n := len(r.Contents); n > 0 && r.Contents[n-1].Role == role {
+			r.Contents[n-1].Parts = append(r.Contents[n-1].Parts, parts...)
+			return
+		}
+		r.Contents = append(r.Contents, geminiContent{Role: role, Parts: parts})
+	}
+
+	for _, m := range req.Messages {
+		switch m.Role {
+		case llm.RoleSystem:
+			system = append(system, m.Content)
+		case llm.RoleTool:
+			name := m.Name
+			if name == "" {
+				name = toolNames[m.ToolCallID]
+			}
+			add("user", geminiPart{FunctionResponse: &geminiFunctionResponse{
+				Name:     name,
+				Response: map[string]any{"result": m.Content},
+			}})
+		case llm.RoleAssistant:
+			var parts []geminiPart
+			if m.Content != "" {
+				parts = append(parts, geminiPart{Text: m.Content})
+			}
+			signature := geminiSignature(m.Thinking)
+			for i, tc := range m.ToolCalls {
+				toolNames[tc.ID] = tc.Function.Name
+				part := geminiPart{FunctionCall: &geminiFunctionCall{
+					Name: tc.Function.Name,
+					Args: toolUseInput(tc.Function.Arguments),
+				}}
+				if i == 0 {
+					part.ThoughtSignature = signature
+				}
+				parts = append(parts, part)
+			}
+			if len(parts) == 0 {
+				parts = []geminiPart{{Text: ""}}
+			}
+			add("model", parts...)
+		default:
+			add("user", geminiPart{Text: m.Content})
+		}
+	}
+	if len(system) > 0 {
+		r.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: strings.Join(system, "\n\n")}}}
+	}
+
+	if len(req.Tools) > 0 {
+		decls := make([]geminiFunctionDeclaration, 0, len(req.Tools))
+		for _, t := range req.Tools {
+			d := geminiFunctionDeclaration{Name: t.Function.Name, Description: t.Function.Description}
+			if len(t.Function.Parameters) > 0 && string(t.Function.Parameters) != "null" {
+				d.ParametersJSONSchema = t.Function.Parameters
+			}
+			decls = append(decls, d)
+		}
+		r.Tools = append(r.Tools, geminiTool{FunctionDeclarations: decls})
+	}
+	if c.googleSearch {
+		r.Tools = append(r.Tools, geminiTool{GoogleSearch: &struct{}{}})
+	}
+
+	gc := geminiGenerationConfig{Temperature: req.Temperature, MaxOutputTokens: req.MaxTokens}
+	if budget, ok := geminiThinkingBudgets[c.reasoningEffort]; ok {
+		gc.ThinkingConfig = &geminiThinkingConfig{ThinkingBudget: budget}
+	}
+	if gc != (geminiGenerationConfig{}) {
+		r.GenerationConfig = &gc
+	}
+	r.SafetySettings = c.safety
+	return r
+}
+
+// geminiSignature is the thought signature recorded on an assistant
+// message: a thinking block with a signature and nothing else.
+func geminiSignature(blocks []llm.ThinkingBlock) string {
+	for _, b := range blocks {
+		if b.Text == "" && b.Data == "" && b.Signature != "" {
+			return b.Signature
+		}
+	}
+	return ""
+}
+
+// Gemini-specific response types.
+type geminiResponse struct {
+	ResponseID string `json:"responseId"`
+	Candidates []struct {
+		Content           geminiContent            `json:"content"`
+		FinishReason      string                   `json:"finishReason"`
+		GroundingMetadata *geminiGroundingMetadata `json:"groundingMetadata"`
+	} `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata *geminiUsage `json:"usageMetadata"`
+}
+
+type geminiGroundingMetadata struct {
+	GroundingChunks []struct {
+		Web *struct {
+			URI   string `json:"uri"`
+			Title string `json:"title"`
+		} `json:"web"`
+	} `json:"groundingChunks"`
+	GroundingSupports []struct {
+		Segment struct {
+			EndIndex int `json:"endIndex"`
+		} `json:"segment"`
+		GroundingChunkIndices []int `json:"groundingChunkIndices"`
+	} `json:"groundingSupports"`
+}
+
+// citations lists, per grounding support, the web sources backing it.
+func (g *geminiGroundingMetadata) citations() []llm.Citation {
+	if g == nil {
+		return nil
+	}
+	var out []llm.Citation
+	for _, s := range g.GroundingSupports {
+		for _, i := range s.GroundingChunkIndices {
+			if i < 0 || i >= len(g.GroundingChunks) || g.GroundingChunks[i].Web == nil {
+				continue
+			}
+			web := g.GroundingChunks[i].Web
+			out = append(out, llm.Citation{Title: web.Title, URL: web.URI, End: s.Segment.EndIndex})
+		}
+	}
+	return out
+}
+
+type geminiUsage struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	ThoughtsTokenCount   int `json:"thoughtsTokenCount"`
+}
+
+// toUsageInfo counts thinking tokens, which Gemini reports outside
+// candidatesTokenCount, in OutputTokens: the billed completion total.
+func (u *geminiUsage) toUsageInfo() llm.UsageInfo {
+	if u == nil {
+		return llm.UsageInfo{}
+	}
+	out := u.CandidatesTokenCount + u.ThoughtsTokenCount
+	return llm.UsageInfo{
+		InputTokens:     u.PromptTokenCount,
+		OutputTokens:    out,
+		TotalTokens:     u.PromptTokenCount + out,
+		ReasoningTokens: u.ThoughtsTokenCount,
+	}
+}
+
+// geminiFinishReason maps Gemini's finish reasons onto the OpenAI-style
+// values the executor checks.
+func geminiFinishReason(reason string, toolCalls bool) string {
+	switch reason {
+	case "STOP", "":
+		if toolCalls {
+			return "tool_calls"
+		}
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return "content_filter"
+	default:
+		return strings.ToLower(reason)
+	}
+}
+
+// message assembles the first candidate's parts: text, function calls
+// (named call_<response>_<n> when Gemini gives them no ID) and the
+// thought signature.
+func (gr *geminiResponse) message() (llm.ChatMessage, string, *geminiGroundingMetadata) {
+	msg := llm.ChatMessage{Role: llm.RoleAssistant}
+	if len(gr.Candidates) == 0 {
+		return msg, "", nil
+	}
+	cand := gr.Candidates[0]
+	for _, p := range cand.Content.Parts {
+		if p.ThoughtSignature != "" && geminiSignature(msg.Thinking) == "" {
+			msg.Thinking = append(msg.Thinking, llm.ThinkingBlock{Signature: p.ThoughtSignature})
+		}
+		switch {
+		case p.Thought:
+			// Thought summaries are not part of the answer.
+		case p.FunctionCall != nil:
+			id := p.FunctionCall.ID
+			if id == "" {
+				id = fmt.Sprintf("call_%s_%d", gr.ResponseID, len(msg.ToolCalls))
+			}
+			msg.ToolCalls = append(msg.ToolCalls, llm.ToolCall{
+				ID:   id,
+				Type: "function",
+				Function: llm.FunctionCall{
+					Name:      p.FunctionCall.Name,
+					Arguments: string(toolUseInput(string(p.FunctionCall.Args))),
+				},
+			})
+		default:
+			msg.Content += p.Text
+		}
+	}
+	return msg, cand.FinishReason, cand.GroundingMetadata
+}
+
+func (gr *geminiResponse) toChatResponse() (*llm.ChatResponse, error) {
+	if len(gr.Candidates) == 0 && gr.PromptFeedback != nil && gr.PromptFeedback.BlockReason != "" {
+		return nil, fmt.Errorf("gemini blocked the prompt: %s", gr.PromptFeedback.BlockReason)
+	}
+	msg, finish, grounding := gr.message()
+	msg.Citations = grounding.citations()
+	return &llm.ChatResponse{
+		ID:           gr.ResponseID,
+		Message:      msg,
+		Usage:        gr.UsageMetadata.toUsageInfo(),
+		FinishReason: geminiFinishReason(finish, len(msg.ToolCalls) > 0),
+	}, nil
+}
+
+// readGeminiStream turns streamGenerateContent's SSE chunks into
+// deltas. Each chunk carries whole parts: text fragments, complete
+// function calls. Usage, grounding and the finish reason arrive with
+// the last chunk.
+func readGeminiStream(r io.Reader, ch chan<- llm.StreamDelta) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var calls int
+	var signed bool
+
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var gr geminiResponse
+		if json.Unmarshal([]byte(data), &gr) != nil {
+			continue
+		}
+		msg, finish, grounding := gr.message()
+		delta := llm.StreamDelta{Content: msg.Content}
+		for _, tc := range msg.ToolCalls {
+			if strings.HasPrefix(tc.ID, "call_"+gr.ResponseID+"_") {
+				tc.ID = fmt.Sprintf("call_%s_%d", gr.ResponseID, calls)
+			}
+			calls++
+			delta.ToolCalls = append(delta.ToolCalls, tc)
+		}
+		if len(msg.Thinking) > 0 && !signed {
+			delta.Thinking, signed = msg.Thinking, true
+		}
+		delta.Citations = grounding.citations()
+		if finish != "" {
+			delta.FinishReason = geminiFinishReason(finish, calls > 0)
+			if gr.UsageMetadata != nil {
+				usage := gr.UsageMetadata.toUsageInfo()
+				delta.Usage = &usage
+			}
+		}
+		if delta.Content != "" || len(delta.ToolCalls) > 0 || len(delta.Thinking) > 0 || len(delta.Citations) > 0 || delta.FinishReason != "" {
+			ch <- delta
+		}
+	}
+	ch <- llm.StreamDelta{Done: true}
+}
+
+// geminiSafetyCategories maps forge.yaml's safety_settings keys onto
+// Gemini harm categories.
+var geminiSafetyCategories = map[string]string{
+	"harassment":        "HARM_CATEGORY_HARASSMENT",
+	"hate_speech":       "HARM_CATEGORY_HATE_SPEECH",
+	"sexually_explicit": "HARM_CATEGORY_SEXUALLY_EXPLICIT",
+	"dangerous_content": "HARM_CATEGORY_DANGEROUS_CONTENT",
+	"civic_integrity":   "HARM_CATEGORY_CIVIC_INTEGRITY",
+}
+
+// geminiSafetyThresholds maps forge.yaml's threshold values onto
+// Gemini block thresholds.
+var geminiSafetyThresholds = map[string]string{
+	"block_none":             "BLOCK_NONE",
+	"block_only_high":        "BLOCK_ONLY_HIGH",
+	"block_medium_and_above": "BLOCK_MEDIUM_AND_ABOVE",
+	"block_low_and_above":    "BLOCK_LOW_AND_ABOVE",
+	"off":                    "OFF",
+}
+
+// ValidateGeminiSafetySettings checks model.gemini.safety_settings:
+// known harm categories mapped to known thresholds.
+func ValidateGeminiSafetySettings(settings map[string]string) error {
+	for _, category := range sortedKeys(settings) {
+		if _, ok := geminiSafetyCategories[category]; !ok {
+			return fmt.Errorf("unknown harm category %q (want one of: %s)", category, strings.Join(sortedKeys(geminiSafetyCategories), ", "))
+		}
+		if _, ok := geminiSafetyThresholds[settings[category]]; !ok {
+			return fmt.Errorf("%s: unknown threshold %q (want one of: %s)", category, settings[category], strings.Join(sortedKeys(geminiSafetyThresholds), ", "))
+		}
+	}
+	return nil
+}
+
+// geminiSafety converts validated safety settings to the API's form,
+// in a stable order; unknown entries are dropped.
+func geminiSafety(settings map[string]string) []geminiSafetySetting {
+	var out []geminiSafetySetting
+	for _, category := range sortedKeys(settings) {
+		cat, okCat := geminiSafetyCategories[category]
+		threshold, okThreshold := geminiSafetyThresholds[settings[category]]
+		if okCat && okThreshold {
+			out = append(out, geminiSafetySetting{Category: cat, Threshold: threshold})
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
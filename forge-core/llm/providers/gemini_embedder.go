@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+const (
+	geminiDefaultEmbeddingModel = "gemini-embedding-001"
+	geminiDefaultEmbeddingDims  = 768
+	geminiDefaultBaseURL        = "https://generativelanguage.googleapis.com/v1beta"
+)
+
+// GeminiEmbedder implements llm.Embedder using the native Gemini
+// batchEmbedContents API. Gemini's OpenAI-compatible endpoint serves
+// chat well but not embeddings (it ignores dimensions and task types),
+// so embeddings go through the native API.
+type GeminiEmbedder struct {
+	apiKey  string
+	baseURL string
+	model   string
+	dims    int
+	client  *http.Client
+}
+
+// NewGeminiEmbedder creates a Gemini embedder. cfg.BaseURL may name the
+// API root or the OpenAI-compatible path the chat client uses; OrgID is
+// ignored. Dims is sent as outputDimensionality, which the
+// gemini-embedding models truncate to.
+func NewGeminiEmbedder(cfg OpenAIEmbedderConfig) *GeminiEmbedder {
+	baseURL := strings.TrimSuffix(strings.TrimRight(cfg.BaseURL, "/"), "/openai")
+	if baseURL == "" {
+		baseURL = geminiDefaultBaseURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = geminiDefaultEmbeddingModel
+	}
+	dims := cfg.Dims
+	if dims <= 0 {
+		dims = geminiDefaultEmbeddingDims
+	}
+	return &GeminiEmbedder{
+		apiKey:  cfg.APIKey,
+		baseURL: baseURL,
+		model:   strings.TrimPrefix(model, "models/"),
+		dims:    dims,
+		client:  &http.Client{Timeout: embeddingTimeout},
+	}
+}
+
+func (e *GeminiEmbedder) Dimensions() int { return e.dims }
+
+// Embed produces embeddings for the given texts using
+// POST /models/{model}:batchEmbedContents. Texts are embedded as
+// RETRIEVAL_DOCUMENT for the same reason as CohereEmbedder.Embed.
+func (e *GeminiEmbedder) Embed(ctx context.Context, req *llm.EmbeddingRequest) (*llm.EmbeddingResponse, error) {
+	if len(req.Texts) == 0 {
+		return &llm.EmbeddingResponse{Model: e.model}, nil
+	}
+
+	model := strings.TrimPrefix(req.Model, "models/")
+	if model == "" {
+		model = e.model
+	}
+
+	body := geminiBatchEmbedRequest{Requests: make([]geminiEmbedRequest, len(req.Texts))}
+	for i, text := range req.Texts {
+		r := geminiEmbedRequest{
+			Model:                "models/" + model,
+			TaskType:             "RETRIEVAL_DOCUMENT",
+			OutputDimensionality: e.dims,
+		}
+		r.Content.Parts = []geminiEmbedPart{{Text: text}}
+		body.Requests[i] = r
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling embedding request: %w", err)
+	}
+
+	url := e.baseURL + "/models/" + model + ":batchEmbedContents"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		httpReq.Header.Set("x-goog-api-key", e.apiKey)
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embedding error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var embResp geminiBatchEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("decoding embedding response: %w", err)
+	}
+
+	embeddings := make([][]float32, len(embResp.Embeddings))
+	for i, emb := range embResp.Embeddings {
+		embeddings[i] = emb.Values
+	}
+	// The embed API reports no token usage.
+	return &llm.EmbeddingResponse{Embeddings: embeddings, Model: model}, nil
+}
+
+// geminiBatchEmbedRequest is the Gemini batchEmbedContents request format.
+type geminiBatchEmbedRequest struct {
+	Requests []geminiEmbedRequest `json:"requests"`
+}
+
+type geminiEmbedRequest struct {
+	Model   string `json:"model"`
+	Content struct {
+		Parts []geminiEmbedPart `json:"parts"`
+	} `json:"content"`
+	TaskType             string `json:"taskType,omitempty"`
+	OutputDimensionality int    `json:"outputDimensionality,omitempty"`
+}
+
+type geminiEmbedPart struct {
+	Text string `json:"text"`
+}
+
+// geminiBatchEmbedResponse is the Gemini batchEmbedContents response format.
+type geminiBatchEmbedResponse struct {
+	Embeddings []struct {
+		Values []float32 `json:"values"`
+	} `json:"embeddings"`
+}
@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestGeminiEmbedder_Embed(t *testing.T) {
+	var gotPath, gotKey string
+	var gotBody geminiBatchEmbedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("x-goog-api-key")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"embeddings":[{"values":[0.1,0.2]},{"values":[0.3,0.4]}]}`))
+	}))
+	defer srv.Close()
+
+	// The chat client's OpenAI-compatible base URL is accepted.
+	emb := NewGeminiEmbedder(OpenAIEmbedderConfig{APIKey: "g-key", BaseURL: srv.URL + "/openai/"})
+	if emb.Dimensions() != geminiDefaultEmbeddingDims {
+		t.Errorf("Dimensions = %d, want %d", emb.Dimensions(), geminiDefaultEmbeddingDims)
+	}
+	resp, err := emb.Embed(context.Background(), &llm.EmbeddingRequest{Texts: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if want := "/models/" + geminiDefaultEmbeddingModel + ":batchEmbedContents"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	if gotKey != "g-key" {
+		t.Errorf("x-goog-api-key = %q", gotKey)
+	}
+	if len(gotBody.Requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(gotBody.Requests))
+	}
+	r0 := gotBody.Requests[0]
+	if r0.Model != "models/"+geminiDefaultEmbeddingModel || r0.TaskType != "RETRIEVAL_DOCUMENT" ||
+		r0.OutputDimensionality != geminiDefaultEmbeddingDims || r0.Content.Parts[0].Text != "a" {
+		t.Errorf("request[0] = %+v", r0)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[1][1] != 0.4 {
+		t.Errorf("embeddings = %v", resp.Embeddings)
+	}
+}
@@ -0,0 +1,157 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestGemini_Request(t *testing.T) {
+	c := NewGeminiClient(llm.ClientConfig{
+		Model:           "gemini-2.5-flash",
+		ReasoningEffort: "medium",
+		GoogleSearch:    true,
+		SafetySettings:  map[string]string{"harassment": "block_only_high", "dangerous_content": "off"},
+	})
+	body := c.toGeminiRequest(&llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: "be brief"},
+			{Role: llm.RoleUser, Content: "weather in Paris and Rome?"},
+			{Role: llm.RoleAssistant, Thinking: []llm.ThinkingBlock{{Signature: "sig"}}, ToolCalls: []llm.ToolCall{
+				{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}},
+				{ID: "c2", Type: "function", Function: llm.FunctionCall{Name: "weather", Arguments: `{"city":"Rome"}`}},
+			}},
+			{Role: llm.RoleTool, ToolCallID: "c1", Content: "sunny"},
+			{Role: llm.RoleTool, ToolCallID: "c2", Name: "weather", Content: "cloudy"},
+		},
+		Tools: []llm.ToolDefinition{
+			{Type: "function", Function: llm.FunctionSchema{Name: "weather", Parameters: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`)}},
+			{Type: "function", Function: llm.FunctionSchema{Name: "now"}},
+		},
+	})
+
+	if body.SystemInstruction == nil || body.SystemInstruction.Parts[0].Text != "be brief" {
+		t.Errorf("systemInstruction = %+v", body.SystemInstruction)
+	}
+	if len(body.Contents) != 3 || body.Contents[1].Role != "model" || body.Contents[2].Role != "user" {
+		t.Fatalf("contents = %+v, want the function responses merged into one user turn", body.Contents)
+	}
+	calls := body.Contents[1].Parts
+	if len(calls) != 2 || calls[0].ThoughtSignature != "sig" || calls[1].ThoughtSignature != "" || string(calls[1].FunctionCall.Args) != `{"city":"Rome"}` {
+		t.Errorf("function calls = %+v", calls)
+	}
+	results := body.Contents[2].Parts
+	if len(results) != 2 || results[0].FunctionResponse.Name != "weather" || results[1].FunctionResponse.Response["result"] != "cloudy" {
+		t.Errorf("function responses = %+v", results)
+	}
+	decls := body.Tools[0].FunctionDeclarations
+	if len(decls) != 2 || decls[1].ParametersJSONSchema != nil || len(body.Tools) != 2 || body.Tools[1].GoogleSearch == nil {
+		t.Errorf("tools = %+v", body.Tools)
+	}
+	if body.GenerationConfig == nil || body.GenerationConfig.ThinkingConfig.ThinkingBudget != 8192 {
+		t.Errorf("generationConfig = %+v", body.GenerationConfig)
+	}
+	want := []geminiSafetySetting{{"HARM_CATEGORY_DANGEROUS_CONTENT", "OFF"}, {"HARM_CATEGORY_HARASSMENT", "BLOCK_ONLY_HIGH"}}
+	if len(body.SafetySettings) != 2 || body.SafetySettings[0] != want[0] || body.SafetySettings[1] != want[1] {
+		t.Errorf("safetySettings = %+v", body.SafetySettings)
+	}
+}
+
+func TestGemini_Chat(t *testing.T) {
+	var path, key string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, key = r.URL.Path, r.Header.Get("x-goog-api-key")
+		_, _ = io.WriteString(w, `{"responseId":"r1","candidates":[{"finishReason":"STOP","content":{"role":"model","parts":[
+			{"text":"planning","thought":true},
+			{"functionCall":{"name":"weather","args":{"city":"Paris"}},"thoughtSignature":"sig"},
+			{"functionCall":{"name":"now"}}]}}],
+			"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":5,"thoughtsTokenCount":20}}`)
+	}))
+	defer srv.Close()
+
+	c := NewGeminiClient(llm.ClientConfig{APIKey: "k", BaseURL: srv.URL + "/openai", Model: "gemini-2.5-flash"})
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/models/gemini-2.5-flash:generateContent" || key != "k" {
+		t.Errorf("path = %q, key = %q", path, key)
+	}
+	msg := resp.Message
+	if msg.Content != "" || len(msg.ToolCalls) != 2 || msg.ToolCalls[0].ID != "call_r1_0" || msg.ToolCalls[1].Function.Arguments != "{}" {
+		t.Errorf("message = %+v", msg)
+	}
+	if len(msg.Thinking) != 1 || msg.Thinking[0].Signature != "sig" {
+		t.Errorf("thinking = %+v", msg.Thinking)
+	}
+	if resp.FinishReason != "tool_calls" || resp.Usage.OutputTokens != 25 || resp.Usage.ReasoningTokens != 20 || resp.Usage.TotalTokens != 35 {
+		t.Errorf("finish = %q, usage = %+v", resp.FinishReason, resp.Usage)
+	}
+}
+
+func TestGemini_BlockedPrompt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"promptFeedback":{"blockReason":"SAFETY"}}`)
+	}))
+	defer srv.Close()
+
+	c := NewGeminiClient(llm.ClientConfig{BaseURL: srv.URL, Model: "gemini-2.5-flash"})
+	_, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err == nil || !strings.Contains(err.Error(), "SAFETY") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestGemini_StreamGrounding(t *testing.T) {
+	stream := strings.Join([]string{
+		`data: {"responseId":"r2","candidates":[{"content":{"role":"model","parts":[{"text":"Paris is "}]}}]}`,
+		"",
+		`data: {"responseId":"r2","candidates":[{"content":{"role":"model","parts":[{"text":"sunny."}]},"finishReason":"STOP",
+			"groundingMetadata":{"groundingChunks":[{"web":{"uri":"https://a.test","title":"a.test"}},{"web":{"uri":"https://b.test","title":"b.test"}}],
+			"groundingSupports":[{"segment":{"endIndex":15},"groundingChunkIndices":[1,0,7]}]}}],
+			"usageMetadata":{"promptTokenCount":4,"candidatesTokenCount":3}}`,
+	}, "\n")
+	stream = strings.ReplaceAll(stream, "\n\t\t\t", "")
+	ch := make(chan llm.StreamDelta, 8)
+	readGeminiStream(strings.NewReader(stream), ch)
+	close(ch)
+
+	var text string
+	var cites []llm.Citation
+	var last llm.StreamDelta
+	for d := range ch {
+		text += d.Content
+		cites = append(cites, d.Citations...)
+		if d.Usage != nil {
+			last = d
+		}
+	}
+	if text != "Paris is sunny." {
+		t.Errorf("text = %q", text)
+	}
+	want := []llm.Citation{{Title: "b.test", URL: "https://b.test", End: 15}, {Title: "a.test", URL: "https://a.test", End: 15}}
+	if len(cites) != 2 || cites[0] != want[0] || cites[1] != want[1] {
+		t.Errorf("citations = %+v", cites)
+	}
+	if last.FinishReason != "stop" || last.Usage.TotalTokens != 7 {
+		t.Errorf("final delta = %+v", last)
+	}
+}
+
+func TestValidateGeminiSafetySettings(t *testing.T) {
+	if err := ValidateGeminiSafetySettings(map[string]string{"hate_speech": "block_none"}); err != nil {
+		t.Errorf("valid settings: %v", err)
+	}
+	if err := ValidateGeminiSafetySettings(map[string]string{"violence": "off"}); err == nil || !strings.Contains(err.Error(), `unknown harm category "violence"`) {
+		t.Errorf("unknown category: %v", err)
+	}
+	if err := ValidateGeminiSafetySettings(map[string]string{"harassment": "BLOCK_NONE"}); err == nil || !strings.Contains(err.Error(), "harassment: unknown threshold") {
+		t.Errorf("unknown threshold: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package providers
+
+// LocalEmbedder wraps OpenAIEmbedder for a self-hosted embedding server
+// speaking the OpenAI /v1/embeddings protocol: llama.cpp's llama-server
+// with a GGUF model (`llama-server --embeddings -m model.gguf`), an
+// ONNX runtime server such as Hugging Face text-embeddings-inference,
+// or LocalAI. Nothing leaves the host and no API key is needed, which
+// gives Anthropic-only deployments vector memory.
+type LocalEmbedder struct {
+	*OpenAIEmbedder
+}
+
+const (
+	localDefaultEmbeddingBaseURL = "http://localhost:8080/v1"
+	localDefaultEmbeddingDims    = 768
+)
+
+// NewLocalEmbedder creates an embedder for a local embedding server.
+// The server decides which model runs, so an empty Model is sent as
+// "default"; set Dims to the served model's dimensionality when it is
+// not 768 (nomic-embed-text, bge-base).
+func NewLocalEmbedder(cfg OpenAIEmbedderConfig) *LocalEmbedder {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = localDefaultEmbeddingBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = "default"
+	}
+	if cfg.Dims <= 0 {
+		cfg.Dims = localDefaultEmbeddingDims
+	}
+	return &LocalEmbedder{
+		OpenAIEmbedder: NewOpenAIEmbedder(cfg),
+	}
+}
@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestLocalEmbedder_Embed(t *testing.T) {
+	var gotAuth string
+	var gotBody embeddingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"data":[{"embedding":[0.1,0.2],"index":0},{"embedding":[0.3,0.4],"index":1}],"model":"nomic-embed-text","usage":{"prompt_tokens":4,"total_tokens":4}}`))
+	}))
+	defer srv.Close()
+
+	emb := NewLocalEmbedder(OpenAIEmbedderConfig{BaseURL: srv.URL})
+	if emb.Dimensions() != localDefaultEmbeddingDims {
+		t.Errorf("Dimensions = %d, want %d", emb.Dimensions(), localDefaultEmbeddingDims)
+	}
+	resp, err := emb.Embed(context.Background(), &llm.EmbeddingRequest{Texts: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization = %q, want none without an API key", gotAuth)
+	}
+	if gotBody.Model != "default" || len(gotBody.Input) != 2 || gotBody.Input[1] != "b" {
+		t.Errorf("request = %+v", gotBody)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[0][0] != 0.1 || resp.Embeddings[1][0] != 0.3 {
+		t.Errorf("embeddings = %v", resp.Embeddings)
+	}
+	if resp.Model != "nomic-embed-text" || resp.Usage.TotalTokens != 4 {
+		t.Errorf("model = %q, usage = %+v", resp.Model, resp.Usage)
+	}
+}
+
+func TestLocalEmbedder_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	emb := NewLocalEmbedder(OpenAIEmbedderConfig{BaseURL: srv.URL, Dims: 384})
+	if emb.Dimensions() != 384 {
+		t.Errorf("Dimensions = %d, want 384", emb.Dimensions())
+	}
+	_, err := emb.Embed(context.Background(), &llm.EmbeddingRequest{Texts: []string{"a"}})
+	if err == nil || !strings.Contains(err.Error(), "status 503") || !strings.Contains(err.Error(), "model not loaded") {
+		t.Errorf("err = %v", err)
+	}
+}
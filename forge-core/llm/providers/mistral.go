@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// MistralClient wraps OpenAIClient with Mistral La Plateforme defaults.
+// Mistral's chat completions API is OpenAI-shaped but stricter: it rejects
+// unknown request fields and requires tool call IDs to be exactly nine
+// alphanumeric characters.
+type MistralClient struct {
+	*OpenAIClient
+}
+
+// NewMistralClient creates a client for the Mistral API.
+func NewMistralClient(cfg llm.ClientConfig) *MistralClient {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.mistral.ai/v1"
+	}
+	c := NewOpenAIClient(cfg)
+	c.adaptRequest = adaptMistralRequest
+	return &MistralClient{OpenAIClient: c}
+}
+
+// adaptMistralRequest strips the OpenAI-only fields Mistral rejects and
+// rewrites tool call IDs into Mistral's format. Mistral streams usage in
+// the final chunk without being asked, so dropping stream_options loses
+// nothing.
+func adaptMistralRequest(r *openaiRequest) {
+	r.StreamOptions = nil
+	r.PromptCacheKey = ""
+	r.ReasoningEffort = ""
+	for i := range r.Messages {
+		m := &r.Messages[i]
+		if m.ToolCallID != "" {
+			m.ToolCallID = mistralToolCallID(m.ToolCallID)
+		}
+		if len(m.ToolCalls) == 0 {
+			continue
+		}
+		calls := make([]llm.ToolCall, len(m.ToolCalls))
+		for j, tc := range m.ToolCalls {
+			tc.ID = mistralToolCallID(tc.ID)
+			calls[j] = tc
+		}
+		m.ToolCalls = calls
+	}
+}
+
+// mistralToolCallID maps a tool call ID onto Mistral's nine-character
+// [a-zA-Z0-9] format. IDs Mistral issued itself pass through unchanged;
+// anything else (e.g. history produced by another provider before a
+// fallback) is hashed, so a call and its result keep matching IDs.
+func mistralToolCallID(id string) string {
+	if len(id) == 9 && isAlphanumeric(id) {
+		return id
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:9]
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
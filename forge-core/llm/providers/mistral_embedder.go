@@ -0,0 +1,28 @@
+package providers
+
+// MistralEmbedder wraps OpenAIEmbedder with Mistral defaults.
+// Mistral exposes an OpenAI-compatible /v1/embeddings endpoint.
+type MistralEmbedder struct {
+	*OpenAIEmbedder
+}
+
+const (
+	mistralDefaultEmbeddingModel = "mistral-embed"
+	mistralDefaultEmbeddingDims  = 1024
+)
+
+// NewMistralEmbedder creates an embedder for the Mistral API.
+func NewMistralEmbedder(cfg OpenAIEmbedderConfig) *MistralEmbedder {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.mistral.ai/v1"
+	}
+	if cfg.Model == "" {
+		cfg.Model = mistralDefaultEmbeddingModel
+	}
+	if cfg.Dims <= 0 {
+		cfg.Dims = mistralDefaultEmbeddingDims
+	}
+	return &MistralEmbedder{
+		OpenAIEmbedder: NewOpenAIEmbedder(cfg),
+	}
+}
@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestMistralClient_AdaptsRequest(t *testing.T) {
+	var raw map[string]json.RawMessage
+	var body openaiRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&buf)
+		_ = json.Unmarshal(buf, &raw)
+		_ = json.Unmarshal(buf, &body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"ok\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	client := NewMistralClient(llm.ClientConfig{APIKey: "m-test", Model: "mistral-large-latest", BaseURL: srv.URL, PromptCaching: true})
+	ch, err := client.ChatStream(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleUser, Content: "hi"},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+				{ID: "toolu_01ABCdef", Type: "function", Function: llm.FunctionCall{Name: "a", Arguments: "{}"}},
+				{ID: "Ab3dE6gH9", Type: "function", Function: llm.FunctionCall{Name: "b", Arguments: "{}"}},
+			}},
+			{Role: llm.RoleTool, ToolCallID: "toolu_01ABCdef", Content: "done"},
+			{Role: llm.RoleTool, ToolCallID: "Ab3dE6gH9", Content: "done"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	for range ch {
+	}
+
+	for _, field := range []string{"stream_options", "prompt_cache_key"} {
+		if _, ok := raw[field]; ok {
+			t.Errorf("request must not carry %s", field)
+		}
+	}
+	calls := body.Messages[1].ToolCalls
+	if len(calls[0].ID) != 9 || !isAlphanumeric(calls[0].ID) {
+		t.Errorf("foreign tool call id not normalized: %q", calls[0].ID)
+	}
+	if calls[1].ID != "Ab3dE6gH9" {
+		t.Errorf("native tool call id rewritten: %q", calls[1].ID)
+	}
+	if body.Messages[2].ToolCallID != calls[0].ID {
+		t.Errorf("tool result id %q does not match call id %q", body.Messages[2].ToolCallID, calls[0].ID)
+	}
+}
+
+func TestMistralEmbedderDefaults(t *testing.T) {
+	emb := NewMistralEmbedder(OpenAIEmbedderConfig{})
+	if emb.Dimensions() != mistralDefaultEmbeddingDims {
+		t.Errorf("expected %d dims, got %d", mistralDefaultEmbeddingDims, emb.Dimensions())
+	}
+	if emb.model != mistralDefaultEmbeddingModel {
+		t.Errorf("expected model %q, got %q", mistralDefaultEmbeddingModel, emb.model)
+	}
+	if emb.baseURL != "https://api.mistral.ai/v1" {
+		t.Errorf("expected mistral base URL, got %q", emb.baseURL)
+	}
+}
@@ -1,22 +1,502 @@
 package providers
 
-import "github.com/initializ/forge/forge-core/llm"
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
-// OllamaClient wraps OpenAIClient with Ollama-specific defaults.
-// Ollama provides an OpenAI-compatible API at localhost:11434/v1.
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// OllamaDefaultBaseURL is the address a local `ollama serve` listens on.
+const OllamaDefaultBaseURL = "http://localhost:11434"
+
+// OllamaClient implements llm.Client against Ollama's native /api/chat.
+// The OpenAI-compatible /v1 shim it replaces ignores keep_alive and
+// reports a missing model or a stopped daemon as an opaque HTTP error;
+// the native API lets Forge keep models resident and say exactly what
+// to run.
 type OllamaClient struct {
-	*OpenAIClient
+	baseURL   string
+	model     string
+	keepAlive any
+	client    *http.Client
 }
 
-// NewOllamaClient creates a client that talks to a local Ollama server.
+// NewOllamaClient creates a client that talks to an Ollama daemon. The
+// API key is ignored; Ollama is unauthenticated.
 func NewOllamaClient(cfg llm.ClientConfig) *OllamaClient {
-	if cfg.BaseURL == "" {
-		cfg.BaseURL = "http://localhost:11434/v1"
+	return &OllamaClient{
+		baseURL:   OllamaRoot(cfg.BaseURL),
+		model:     cfg.Model,
+		keepAlive: ollamaKeepAlive(cfg.KeepAlive),
+		// Generous default: the first call after a cold start includes
+		// loading the model into memory.
+		client: newHTTPClient(cfg, 300*time.Second),
 	}
-	if cfg.APIKey == "" {
-		cfg.APIKey = "ollama" // Ollama requires a non-empty key
+}
+
+// OllamaRoot normalizes an Ollama base URL to the daemon root. Empty
+// means the local default; a trailing /v1 (configs written for the
+// OpenAI-compatible endpoint) is stripped.
+func OllamaRoot(baseURL string) string {
+	if baseURL == "" {
+		return OllamaDefaultBaseURL
 	}
-	return &OllamaClient{
-		OpenAIClient: NewOpenAIClient(cfg),
+	baseURL = strings.TrimRight(baseURL, "/")
+	return strings.TrimSuffix(baseURL, "/v1")
+}
+
+// ValidateOllamaKeepAlive reports whether s is a keep_alive value Ollama
+// accepts: a Go duration ("10m", "1h30m") or whole seconds ("300"; "-1"
+// keeps the model loaded indefinitely, "0" unloads it after each call).
+func ValidateOllamaKeepAlive(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := strconv.Atoi(s); err == nil {
+		return nil
+	}
+	if _, err := time.ParseDuration(s); err != nil {
+		return fmt.Errorf("keep_alive %q must be a duration (e.g. 10m) or a number of seconds (-1 = forever)", s)
+	}
+	return nil
+}
+
+// ollamaKeepAlive encodes s for the request body. Ollama reads a JSON
+// number as seconds and a string as a Go duration, so "-1" must go out
+// as a number — as a string it fails to parse.
+func ollamaKeepAlive(s string) any {
+	if s == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return s
+}
+
+func (c *OllamaClient) ModelID() string { return c.model }
+
+// Chat sends a non-streaming chat request.
+func (c *OllamaClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	body := c.toOllamaRequest(req, false)
+	resp, endpoint, err := c.post(ctx, "/api/chat", body.Model, body, c.client)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var out ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding ollama response: %w", err)
+	}
+	msg := llm.ChatMessage{
+		Role:      llm.RoleAssistant,
+		Content:   out.Message.Content,
+		ToolCalls: fromOllamaToolCalls(out.Message.ToolCalls),
+	}
+	return &llm.ChatResponse{
+		Message:      msg,
+		Usage:        out.usage(),
+		FinishReason: ollamaFinishReason(out.DoneReason, len(msg.ToolCalls) > 0),
+		Endpoint:     endpoint,
+	}, nil
+}
+
+// ChatStream sends a streaming chat request. Ollama streams
+// newline-delimited JSON objects, not SSE; tool calls arrive whole in
+// a single chunk, and the final chunk (done=true) carries the counts.
+func (c *OllamaClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	body := c.toOllamaRequest(req, true)
+	resp, _, err := c.post(ctx, "/api/chat", body.Model, body, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan llm.StreamDelta, 32)
+	go func() {
+		defer func() { _ = resp.Body.Close() }()
+		defer close(ch)
+		readOllamaStream(resp.Body, ch)
+	}()
+	return ch, nil
+}
+
+// CheckHealth verifies that the daemon answers and that the client's
+// model has been pulled, returning an error that names the command to
+// run when either is not the case.
+func (c *OllamaClient) CheckHealth(ctx context.Context) error {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	if c.model == "" || ollamaHasModel(models, c.model) {
+		return nil
+	}
+	return fmt.Errorf("ollama model %q is not pulled on %s; run `forge models pull %s` (or `ollama pull %s`)", c.model, c.baseURL, c.model, c.model)
+}
+
+// ListModels returns the names of the models pulled on the daemon.
+func (c *OllamaClient) ListModels(ctx context.Context) ([]string, error) {
+	endpoint := c.baseURL + "/api/tags"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, c.transportError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.statusError(resp, "")
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("decoding ollama model list: %w", err)
+	}
+	names := make([]string, len(tags.Models))
+	for i, m := range tags.Models {
+		names[i] = m.Name
+	}
+	return names, nil
+}
+
+// OllamaPullProgress is one progress update from Pull.
+type OllamaPullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+}
+
+// Pull downloads model onto the daemon, calling progress (if non-nil)
+// for every update Ollama streams. Pulls can take many minutes, so the
+// client timeout does not apply; cancel ctx to abort.
+func (c *OllamaClient) Pull(ctx context.Context, model string, progress func(OllamaPullProgress)) error {
+	body := map[string]any{"model": model, "stream": true}
+	resp, _, err := c.post(ctx, "/api/pull", "", body, &http.Client{Transport: c.client.Transport})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	scanner := bufio.NewScanner(resp.Body)
+	var last string
+	for scanner.Scan() {
+		var p struct {
+			OllamaPullProgress
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &p) != nil {
+			continue
+		}
+		if p.Error != "" {
+			return fmt.Errorf("pulling %s: %s", model, p.Error)
+		}
+		last = p.Status
+		if progress != nil {
+			progress(p.OllamaPullProgress)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("pulling %s: %w", model, err)
+	}
+	if last != "success" {
+		return fmt.Errorf("pulling %s: stream ended before completion", model)
+	}
+	return nil
+}
+
+// SupportsTools reports whether model can call tools, which an agent
+// needs. Daemons since 0.6 list capabilities in /api/show; for older
+// ones, a model supports tools when its prompt template renders them.
+func (c *OllamaClient) SupportsTools(ctx context.Context, model string) (bool, error) {
+	resp, _, err := c.post(ctx, "/api/show", model, map[string]any{"model": model}, c.client)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var show struct {
+		Capabilities []string `json:"capabilities"`
+		Template     string   `json:"template"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&show); err != nil {
+		return false, fmt.Errorf("decoding ollama model info: %w", err)
+	}
+	if show.Capabilities != nil {
+		for _, name := range show.Capabilities {
+			if name == "tools" {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return strings.Contains(show.Template, ".Tools"), nil
+}
+
+// post sends body as JSON to path and returns the response once its
+// status is 200. Transport and status failures for model come back as
+// the actionable errors from transportError / statusError.
+func (c *OllamaClient) post(ctx context.Context, path, model string, body any, hc *http.Client) (*http.Response, string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshalling request: %w", err)
+	}
+	endpoint := c.baseURL + path
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	safeEndpoint := sanitizeEndpoint(endpoint)
+	resp, err := hc.Do(httpReq)
+	if err != nil {
+		return nil, safeEndpoint, c.transportError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, safeEndpoint, c.statusError(resp, model)
+	}
+	return resp, safeEndpoint, nil
+}
+
+// transportError turns a refused or unresolvable connection into an
+// instruction to start the daemon.
+func (c *OllamaClient) transportError(err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return fmt.Errorf("ollama is not running at %s; start it with `ollama serve` or set OLLAMA_BASE_URL: %w", sanitizeEndpoint(c.baseURL), err)
+	}
+	return fmt.Errorf("ollama request to %s: %w", sanitizeEndpoint(c.baseURL), err)
+}
+
+// statusError reads Ollama's {"error": "..."} body. A 404 naming a
+// model means it has not been pulled.
+func (c *OllamaClient) statusError(resp *http.Response, model string) error {
+	raw, _ := io.ReadAll(resp.Body)
+	var e struct {
+		Error string `json:"error"`
+	}
+	msg := strings.TrimSpace(string(raw))
+	if json.Unmarshal(raw, &e) == nil && e.Error != "" {
+		msg = e.Error
+	}
+	if resp.StatusCode == http.StatusNotFound && model != "" {
+		return fmt.Errorf("ollama model %q is not pulled; run `forge models pull %s`: %s", model, model, msg)
+	}
+	return fmt.Errorf("ollama error (status %d): %s", resp.StatusCode, msg)
+}
+
+// ollamaHasModel matches name against the daemon's tags, treating an
+// untagged name as :latest the way the Ollama CLI does.
+func ollamaHasModel(models []string, name string) bool {
+	if !strings.Contains(name, ":") {
+		name += ":latest"
+	}
+	for _, m := range models {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Ollama-specific request types.
+type ollamaRequest struct {
+	Model     string               `json:"model"`
+	Messages  []ollamaMessage      `json:"messages"`
+	Tools     []llm.ToolDefinition `json:"tools,omitempty"`
+	Stream    bool                 `json:"stream"` // Ollama streams unless told otherwise
+	Options   *ollamaOptions       `json:"options,omitempty"`
+	KeepAlive any                  `json:"keep_alive,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+}
+
+// ollamaMessage is a native chat message. Tool results are matched to
+// their call by tool_name; Ollama has no tool call IDs on input.
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+// ollamaToolCall carries arguments as a JSON object, not a string.
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+func (c *OllamaClient) toOllamaRequest(req *llm.ChatRequest, stream bool) ollamaRequest {
+	model := req.Model
+	if model == "" {
+		model = c.model
+	}
+
+	toolNames := map[string]string{}
+	msgs := make([]ollamaMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		msg := ollamaMessage{Role: m.Role, Content: m.Content}
+		for _, tc := range m.ToolCalls {
+			toolNames[tc.ID] = tc.Function.Name
+			var otc ollamaToolCall
+			otc.Function.Name = tc.Function.Name
+			otc.Function.Arguments = json.RawMessage(tc.Function.Arguments)
+			if !json.Valid(otc.Function.Arguments) {
+				otc.Function.Arguments = json.RawMessage("{}")
+			}
+			msg.ToolCalls = append(msg.ToolCalls, otc)
+		}
+		if m.Role == llm.RoleTool {
+			msg.ToolName = m.Name
+			if msg.ToolName == "" {
+				msg.ToolName = toolNames[m.ToolCallID]
+			}
+		}
+		msgs[i] = msg
+	}
+
+	r := ollamaRequest{
+		Model:     model,
+		Messages:  msgs,
+		Tools:     req.Tools,
+		Stream:    stream,
+		KeepAlive: c.keepAlive,
+	}
+	if req.Temperature != nil || req.MaxTokens > 0 {
+		r.Options = &ollamaOptions{Temperature: req.Temperature, NumPredict: req.MaxTokens}
+	}
+	return r
+}
+
+// Ollama-specific response types. Streaming chunks share the shape.
+type ollamaChatResponse struct {
+	Message struct {
+		Role      string           `json:"role"`
+		Content   string           `json:"content"`
+		ToolCalls []ollamaToolCall `json:"tool_calls"`
+	} `json:"message"`
+	Done            bool   `json:"done"`
+	DoneReason      string `json:"done_reason"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error"`
+}
+
+// usage maps Ollama's eval counts. Both are absent when the prompt was
+// served entirely from cache or the model reports none; zeros are left
+// as-is so the audit layer flags tokens_unavailable.
+func (r ollamaChatResponse) usage() llm.UsageInfo {
+	return llm.UsageInfo{
+		InputTokens:  r.PromptEvalCount,
+		OutputTokens: r.EvalCount,
+		TotalTokens:  r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// ollamaFinishReason maps done_reason onto the OpenAI-style values the
+// executor checks. Ollama reports "stop" even when the turn ends in
+// tool calls.
+func ollamaFinishReason(reason string, hasToolCalls bool) string {
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	switch reason {
+	case "", "stop", "unload":
+		return "stop"
+	default:
+		return reason // "length"
+	}
+}
+
+// fromOllamaToolCalls assigns the IDs Ollama does not provide; the
+// executor pairs tool results with calls by ID.
+func fromOllamaToolCalls(calls []ollamaToolCall) []llm.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]llm.ToolCall, len(calls))
+	for i, tc := range calls {
+		args := string(tc.Function.Arguments)
+		if args == "" || args == "null" {
+			args = "{}"
+		}
+		out[i] = llm.ToolCall{
+			ID:   newOllamaToolCallID(),
+			Type: "function",
+			Function: llm.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: args,
+			},
+		}
+	}
+	return out
+}
+
+func newOllamaToolCallID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return "call_" + hex.EncodeToString(b[:])
+}
+
+func readOllamaStream(r io.Reader, ch chan<- llm.StreamDelta) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var sawToolCalls bool
+
+	for scanner.Scan() {
+		var chunk ollamaChatResponse
+		if json.Unmarshal(scanner.Bytes(), &chunk) != nil {
+			continue
+		}
+		if chunk.Error != "" {
+			ch <- llm.StreamDelta{FinishReason: "error", Done: true}
+			return
+		}
+
+		delta := llm.StreamDelta{
+			Content:   chunk.Message.Content,
+			ToolCalls: fromOllamaToolCalls(chunk.Message.ToolCalls),
+		}
+		if len(delta.ToolCalls) > 0 {
+			sawToolCalls = true
+		}
+		if chunk.Done {
+			delta.Done = true
+			delta.FinishReason = ollamaFinishReason(chunk.DoneReason, sawToolCalls)
+			usage := chunk.usage()
+			delta.Usage = &usage
+			ch <- delta
+			return
+		}
+		if delta.Content != "" || len(delta.ToolCalls) > 0 {
+			ch <- delta
+		}
 	}
 }
@@ -1,7 +1,9 @@
 package providers
 
 // OllamaEmbedder wraps OpenAIEmbedder with Ollama-specific defaults.
-// Ollama provides an OpenAI-compatible /v1/embeddings endpoint.
+// Ollama provides an OpenAI-compatible /v1/embeddings endpoint. The
+// base URL may name the daemon root (as the chat client expects) or
+// the /v1 path.
 type OllamaEmbedder struct {
 	*OpenAIEmbedder
 }
@@ -13,9 +15,7 @@ const (
 
 // NewOllamaEmbedder creates an embedder that talks to a local Ollama server.
 func NewOllamaEmbedder(cfg OpenAIEmbedderConfig) *OllamaEmbedder {
-	if cfg.BaseURL == "" {
-		cfg.BaseURL = "http://localhost:11434/v1"
-	}
+	cfg.BaseURL = OllamaRoot(cfg.BaseURL) + "/v1"
 	if cfg.APIKey == "" {
 		cfg.APIKey = "ollama" // Ollama requires a non-empty key
 	}
@@ -0,0 +1,219 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestOllamaRoot(t *testing.T) {
+	tests := map[string]string{
+		"":                            "http://localhost:11434",
+		"http://gpu-box:11434":        "http://gpu-box:11434",
+		"http://gpu-box:11434/":       "http://gpu-box:11434",
+		"http://localhost:11434/v1":   "http://localhost:11434",
+		"https://ollama.internal/v1/": "https://ollama.internal",
+	}
+	for in, want := range tests {
+		if got := OllamaRoot(in); got != want {
+			t.Errorf("OllamaRoot(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOllamaClient_ChatNativeWithTools(t *testing.T) {
+	var got map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %s, want /api/chat", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"model":"llama3.2","message":{"role":"assistant","content":"",
+			"tool_calls":[{"function":{"name":"web_search","arguments":{"query":"forge"}}}]},
+			"done":true,"done_reason":"stop","prompt_eval_count":20,"eval_count":7}`))
+	}))
+	defer srv.Close()
+
+	temp := 0.2
+	c := NewOllamaClient(llm.ClientConfig{BaseURL: srv.URL + "/v1", Model: "llama3.2", KeepAlive: "-1"})
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleUser, Content: "search"},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "web_search", Arguments: `{"query":"x"}`}}}},
+			{Role: llm.RoleTool, ToolCallID: "call_1", Content: "results"},
+		},
+		Tools:       []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "web_search"}}},
+		Temperature: &temp,
+		MaxTokens:   64,
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+
+	if string(got["stream"]) != "false" {
+		t.Errorf("stream = %s, want explicit false", got["stream"])
+	}
+	if string(got["keep_alive"]) != "-1" {
+		t.Errorf("keep_alive = %s, want numeric -1", got["keep_alive"])
+	}
+	if !strings.Contains(string(got["options"]), `"num_predict":64`) {
+		t.Errorf("options = %s", got["options"])
+	}
+	var msgs []ollamaMessage
+	_ = json.Unmarshal(got["messages"], &msgs)
+	if len(msgs) != 3 || string(msgs[1].ToolCalls[0].Function.Arguments) != `{"query":"x"}` || msgs[2].ToolName != "web_search" {
+		t.Errorf("messages = %+v", msgs)
+	}
+
+	if resp.FinishReason != "tool_calls" || len(resp.Message.ToolCalls) != 1 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	tc := resp.Message.ToolCalls[0]
+	if tc.ID == "" || tc.Function.Name != "web_search" || tc.Function.Arguments != `{"query":"forge"}` {
+		t.Errorf("tool call = %+v", tc)
+	}
+	if resp.Usage.InputTokens != 20 || resp.Usage.OutputTokens != 7 || resp.Usage.TotalTokens != 27 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestOllamaClient_ChatStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, line := range []string{
+			`{"message":{"role":"assistant","content":"Hel"},"done":false}`,
+			`{"message":{"role":"assistant","content":"lo"},"done":false}`,
+			`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":3,"eval_count":2}`,
+		} {
+			_, _ = w.Write([]byte(line + "\n"))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewOllamaClient(llm.ClientConfig{BaseURL: srv.URL, Model: "llama3.2"})
+	ch, err := c.ChatStream(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	var content string
+	var last llm.StreamDelta
+	for d := range ch {
+		content += d.Content
+		last = d
+	}
+	if content != "Hello" {
+		t.Errorf("content = %q", content)
+	}
+	if !last.Done || last.FinishReason != "stop" || last.Usage == nil || last.Usage.TotalTokens != 5 {
+		t.Errorf("final delta = %+v", last)
+	}
+}
+
+func TestOllamaClient_ActionableErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_, _ = w.Write([]byte(`{"models":[{"name":"llama3:latest"},{"name":"qwen2.5:7b"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"model \"mistral\" not found, try pulling it first"}`))
+		}
+	}))
+	defer srv.Close()
+
+	for model, wantErr := range map[string]bool{"llama3": false, "qwen2.5:7b": false, "mistral": true} {
+		err := NewOllamaClient(llm.ClientConfig{BaseURL: srv.URL, Model: model}).CheckHealth(context.Background())
+		if (err != nil) != wantErr {
+			t.Errorf("CheckHealth(%s) = %v, wantErr %v", model, err, wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "forge models pull mistral") {
+			t.Errorf("missing-model error not actionable: %v", err)
+		}
+	}
+
+	_, err := NewOllamaClient(llm.ClientConfig{BaseURL: srv.URL, Model: "mistral"}).Chat(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "forge models pull mistral") {
+		t.Errorf("chat 404 error = %v", err)
+	}
+
+	// A closed port reads as "daemon not running".
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	err = NewOllamaClient(llm.ClientConfig{BaseURL: down.URL, Model: "llama3"}).CheckHealth(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ollama serve") {
+		t.Errorf("daemon-down error = %v", err)
+	}
+}
+
+func TestOllamaClient_Pull(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, line := range []string{
+			`{"status":"pulling manifest"}`,
+			`{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":50}`,
+			`{"status":"success"}`,
+		} {
+			_, _ = w.Write([]byte(line + "\n"))
+		}
+	}))
+	defer srv.Close()
+
+	var updates []OllamaPullProgress
+	c := NewOllamaClient(llm.ClientConfig{BaseURL: srv.URL})
+	if err := c.Pull(context.Background(), "llama3.2", func(p OllamaPullProgress) { updates = append(updates, p) }); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if body["model"] != "llama3.2" || len(updates) != 3 || updates[1].Completed != 50 {
+		t.Errorf("body = %v, updates = %+v", body, updates)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"pulling manifest"}` + "\n" + `{"error":"pull model manifest: file does not exist"}` + "\n"))
+	}))
+	defer failing.Close()
+	err := NewOllamaClient(llm.ClientConfig{BaseURL: failing.URL}).Pull(context.Background(), "nope", nil)
+	if err == nil || !strings.Contains(err.Error(), "file does not exist") {
+		t.Errorf("Pull error = %v", err)
+	}
+}
+
+func TestOllamaClient_SupportsTools(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.Model {
+		case "qwen2.5:7b":
+			_, _ = w.Write([]byte(`{"capabilities":["completion","tools"]}`))
+		case "llama3":
+			_, _ = w.Write([]byte(`{"capabilities":["completion"]}`))
+		case "old-tools":
+			_, _ = w.Write([]byte(`{"template":"{{ if .Tools }}[TOOLS]{{ end }}"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"model '` + req.Model + `' not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewOllamaClient(llm.ClientConfig{BaseURL: srv.URL})
+	for model, want := range map[string]bool{"qwen2.5:7b": true, "llama3": false, "old-tools": true} {
+		got, err := c.SupportsTools(context.Background(), model)
+		if err != nil || got != want {
+			t.Errorf("SupportsTools(%q) = %v, %v; want %v", model, got, err, want)
+		}
+	}
+	if _, err := c.SupportsTools(context.Background(), "missing"); err == nil || !strings.Contains(err.Error(), "forge models pull missing") {
+		t.Errorf("missing model error = %v", err)
+	}
+}
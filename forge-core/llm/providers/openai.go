@@ -20,14 +20,26 @@ import (
 // OpenAIClient implements llm.Client for the OpenAI Chat Completions API.
 // Also works with Azure OpenAI and any OpenAI-compatible endpoint.
 type OpenAIClient struct {
-	apiKey         string
-	baseURL        string
-	model          string
-	orgID          string
-	authScheme     string
-	authHeaderName string
-	promptCaching  bool
-	client         *http.Client
+	apiKey          string
+	baseURL         string
+	model           string
+	orgID           string
+	authScheme      string
+	authHeaderName  string
+	promptCaching   bool
+	reasoningEffort string
+	client          *http.Client
+	// adaptRequest, when set, rewrites the outgoing request body for
+	// OpenAI-compatible providers whose wire format deviates from
+	// OpenAI's (see MistralClient).
+	adaptRequest func(*openaiRequest)
+	// extraHeaders are set on every request after auth headers (see
+	// Preset.Headers).
+	extraHeaders map[string]string
+	// reasoningOutsideCompletion marks providers whose completion_tokens
+	// excludes reasoning tokens (xAI); usage normalization adds them back
+	// so UsageInfo.OutputTokens is always the billed total.
+	reasoningOutsideCompletion bool
 }
 
 // NewOpenAIClient creates a new OpenAI client.
@@ -46,23 +58,20 @@ func NewOpenAIClient(cfg llm.ClientConfig) *OpenAIClient {
 	if baseURL == "" {
 		baseURL = "https://api.openai.com/v1"
 	}
-	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
-	if timeout == 0 {
-		timeout = 120 * time.Second
-	}
-	httpClient := &http.Client{Timeout: timeout}
+	httpClient := newHTTPClient(cfg, 120*time.Second)
 	if cfg.AuthScheme == llm.AuthSchemeAWSSigV4 {
-		httpClient.Transport = newBedrockSigningTransport(cfg.AWSRegion, http.DefaultTransport)
+		httpClient.Transport = newBedrockSigningTransport(cfg.AWSRegion, cfg.Transport)
 	}
 	return &OpenAIClient{
-		apiKey:         cfg.APIKey,
-		baseURL:        strings.TrimRight(baseURL, "/"),
-		model:          cfg.Model,
-		orgID:          cfg.OrgID,
-		authScheme:     cfg.AuthScheme,
-		authHeaderName: cfg.AuthHeaderName,
-		promptCaching:  cfg.PromptCaching,
-		client:         httpClient,
+		apiKey:          cfg.APIKey,
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		model:           cfg.Model,
+		orgID:           cfg.OrgID,
+		authScheme:      cfg.AuthScheme,
+		authHeaderName:  cfg.AuthHeaderName,
+		promptCaching:   cfg.PromptCaching,
+		reasoningEffort: cfg.ReasoningEffort,
+		client:          httpClient,
 	}
 }
 
@@ -157,6 +166,9 @@ func (c *OpenAIClient) setHeaders(req *http.Request) {
 		req.Header.Set("OpenAI-Organization", c.orgID)
 	}
 	setGatewayAPIKeyHeader(req, c.authScheme, c.authHeaderName, c.apiKey)
+	for k, v := range c.extraHeaders {
+		req.Header.Set(k, v)
+	}
 }
 
 // openaiRequest is the OpenAI-specific request format.
@@ -173,6 +185,9 @@ type openaiRequest struct {
 	// automatic (≥1024 tokens); the key improves hit locality. Only set
 	// when ClientConfig.PromptCaching is on.
 	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
+	// ReasoningEffort is ClientConfig.ReasoningEffort. Providers that
+	// reject the field clear it in adaptRequest.
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
 }
 
 type streamOptions struct {
@@ -219,6 +234,8 @@ func (c *OpenAIClient) toOpenAIRequest(req *llm.ChatRequest, stream bool) openai
 		Temperature: req.Temperature,
 		MaxTokens:   req.MaxTokens,
 		Stream:      stream,
+
+		ReasoningEffort: c.reasoningEffort,
 	}
 
 	if stream {
@@ -229,6 +246,10 @@ func (c *OpenAIClient) toOpenAIRequest(req *llm.ChatRequest, stream bool) openai
 		r.PromptCacheKey = derivePromptCacheKey(model, req)
 	}
 
+	if c.adaptRequest != nil {
+		c.adaptRequest(&r)
+	}
+
 	return r
 }
 
@@ -259,17 +280,48 @@ type openaiResponse struct {
 	ID      string `json:"id"`
 	Choices []struct {
 		Message struct {
-			Role      string         `json:"role"`
-			Content   string         `json:"content"`
-			ToolCalls []llm.ToolCall `json:"tool_calls,omitempty"`
+			Role    string `json:"role"`
+			Content string `json:"content"`
+			// ReasoningContent is the visible chain of thought DeepSeek
+			// and xAI return alongside the answer. It is decoded only
+			// so it is deliberately dropped: the final message carries
+			// the answer alone, and DeepSeek rejects requests that echo
+			// reasoning_content back in history.
+			ReasoningContent string         `json:"reasoning_content,omitempty"`
+			ToolCalls        []llm.ToolCall `json:"tool_calls,omitempty"`
 		} `json:"message"`
 		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
+	Usage openaiUsage `json:"usage"`
+}
+
+// openaiUsage is the Chat Completions usage block, shared by full and
+// streamed responses.
+type openaiUsage struct {
+	PromptTokens            int `json:"prompt_tokens"`
+	CompletionTokens        int `json:"completion_tokens"`
+	TotalTokens             int `json:"total_tokens"`
+	CompletionTokensDetails *struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details,omitempty"`
+}
+
+// usageInfo normalizes u. OpenAI and DeepSeek count reasoning inside
+// completion_tokens; xAI reports it beside them, so for xAI it is added
+// back to make OutputTokens the billed completion total everywhere.
+func (c *OpenAIClient) usageInfo(u openaiUsage) llm.UsageInfo {
+	info := llm.UsageInfo{
+		InputTokens:  u.PromptTokens,
+		OutputTokens: u.CompletionTokens,
+		TotalTokens:  u.TotalTokens,
+	}
+	if u.CompletionTokensDetails != nil {
+		info.ReasoningTokens = u.CompletionTokensDetails.ReasoningTokens
+	}
+	if c.reasoningOutsideCompletion {
+		info.OutputTokens += info.ReasoningTokens
+	}
+	return info
 }
 
 func (c *OpenAIClient) parseOpenAIResponse(body io.Reader) (*llm.ChatResponse, error) {
@@ -290,11 +342,7 @@ func (c *OpenAIClient) parseOpenAIResponse(body io.Reader) (*llm.ChatResponse, e
 			Content:   choice.Message.Content,
 			ToolCalls: choice.Message.ToolCalls,
 		},
-		Usage: llm.UsageInfo{
-			InputTokens:  resp.Usage.PromptTokens,
-			OutputTokens: resp.Usage.CompletionTokens,
-			TotalTokens:  resp.Usage.TotalTokens,
-		},
+		Usage:        c.usageInfo(resp.Usage),
 		FinishReason: choice.FinishReason,
 	}, nil
 }
@@ -303,16 +351,14 @@ func (c *OpenAIClient) parseOpenAIResponse(body io.Reader) (*llm.ChatResponse, e
 type openaiStreamChunk struct {
 	Choices []struct {
 		Delta struct {
-			Content   string         `json:"content"`
-			ToolCalls []llm.ToolCall `json:"tool_calls,omitempty"`
+			Content string `json:"content"`
+			// ReasoningContent deltas are dropped, as in openaiResponse.
+			ReasoningContent string         `json:"reasoning_content,omitempty"`
+			ToolCalls        []llm.ToolCall `json:"tool_calls,omitempty"`
 		} `json:"delta"`
 		FinishReason *string `json:"finish_reason"`
 	} `json:"choices"`
-	Usage *struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage,omitempty"`
+	Usage *openaiUsage `json:"usage,omitempty"`
 }
 
 func (c *OpenAIClient) readSSEStream(r io.Reader, ch chan<- llm.StreamDelta) {
@@ -343,11 +389,8 @@ func (c *OpenAIClient) readSSEStream(r io.Reader, ch chan<- llm.StreamDelta) {
 			}
 		}
 		if chunk.Usage != nil {
-			delta.Usage = &llm.UsageInfo{
-				InputTokens:  chunk.Usage.PromptTokens,
-				OutputTokens: chunk.Usage.CompletionTokens,
-				TotalTokens:  chunk.Usage.TotalTokens,
-			}
+			usage := c.usageInfo(*chunk.Usage)
+			delta.Usage = &usage
 		}
 		ch <- delta
 	}
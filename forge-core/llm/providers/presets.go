@@ -0,0 +1,156 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Preset describes a hosted provider that speaks OpenAI's Chat
+// Completions protocol unchanged and differs only in endpoint, API-key
+// env var, and optional attribution headers. Presets let forge.yaml say
+// `provider: groq` instead of `provider: openai` plus a hand-copied
+// base_url.
+type Preset struct {
+	// BaseURL is the provider's OpenAI-compatible API root.
+	BaseURL string
+	// APIKeyEnvVar is the env var the provider's key is read from.
+	APIKeyEnvVar string
+	// BaseURLEnvVar overrides BaseURL when set (e.g. a regional or
+	// proxied endpoint).
+	BaseURLEnvVar string
+	// DefaultModel is used when forge.yaml names no model.
+	DefaultModel string
+	// Headers are sent on every request in addition to auth.
+	Headers map[string]string
+	// EmbeddingModel / EmbeddingDims configure the provider's
+	// OpenAI-compatible /embeddings endpoint. Empty EmbeddingModel
+	// means the provider has no embedding API.
+	EmbeddingModel string
+	EmbeddingDims  int
+	// ReasoningEfforts lists the reasoning_effort values the provider
+	// accepts. Nil means the field is not supported and is dropped.
+	ReasoningEfforts []string
+	// ReasoningOutsideCompletion is set when the provider reports
+	// reasoning tokens beside completion_tokens rather than within them.
+	ReasoningOutsideCompletion bool
+}
+
+// presets is keyed by the `model.provider` value.
+var presets = map[string]Preset{
+	"groq": {
+		BaseURL:       "https://api.groq.com/openai/v1",
+		APIKeyEnvVar:  "GROQ_API_KEY",
+		BaseURLEnvVar: "GROQ_BASE_URL",
+		DefaultModel:  "llama-3.3-70b-versatile",
+	},
+	"together": {
+		BaseURL:        "https://api.together.xyz/v1",
+		APIKeyEnvVar:   "TOGETHER_API_KEY",
+		BaseURLEnvVar:  "TOGETHER_BASE_URL",
+		DefaultModel:   "meta-llama/Llama-3.3-70B-Instruct-Turbo",
+		EmbeddingModel: "BAAI/bge-large-en-v1.5",
+		EmbeddingDims:  1024,
+	},
+	"openrouter": {
+		BaseURL:       "https://openrouter.ai/api/v1",
+		APIKeyEnvVar:  "OPENROUTER_API_KEY",
+		BaseURLEnvVar: "OPENROUTER_BASE_URL",
+		DefaultModel:  "openai/gpt-4o",
+		// OpenRouter attributes traffic to the calling app via these
+		// optional headers; without them requests show up as "Unknown".
+		Headers: map[string]string{
+			"HTTP-Referer": "https://github.com/initializ/forge",
+			"X-Title":      "Forge",
+		},
+	},
+	"xai": {
+		BaseURL:       "https://api.x.ai/v1",
+		APIKeyEnvVar:  "XAI_API_KEY",
+		BaseURLEnvVar: "XAI_BASE_URL",
+		DefaultModel:  "grok-4",
+		// Only the grok-3-mini family takes an effort; grok-4 always
+		// reasons and rejects the field, so leave it unset there.
+		ReasoningEfforts:           []string{"low", "high"},
+		ReasoningOutsideCompletion: true,
+	},
+	"deepseek": {
+		BaseURL:       "https://api.deepseek.com/v1",
+		APIKeyEnvVar:  "DEEPSEEK_API_KEY",
+		BaseURLEnvVar: "DEEPSEEK_BASE_URL",
+		// deepseek-reasoner is the thinking model; its effort is fixed.
+		DefaultModel: "deepseek-chat",
+	},
+}
+
+// openaiReasoningEfforts are the reasoning_effort values OpenAI accepts.
+var openaiReasoningEfforts = []string{"minimal", "low", "medium", "high"}
+
+// ReasoningEfforts returns the reasoning_effort values provider accepts,
+// or nil when it does not support configurable reasoning effort (the
+// setting is then dropped from requests).
+func ReasoningEfforts(provider string) []string {
+	switch provider {
+	case "openai":
+		return openaiReasoningEfforts
+	case "gemini":
+		return []string{"low", "medium", "high"}
+	}
+	if p, ok := presets[provider]; ok {
+		return p.ReasoningEfforts
+	}
+	return nil
+}
+
+// LookupPreset returns the preset registered for provider, and whether
+// one exists.
+func LookupPreset(provider string) (Preset, bool) {
+	p, ok := presets[provider]
+	return p, ok
+}
+
+// PresetNames returns the registered preset provider names, sorted.
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newPresetClient builds an OpenAIClient configured from p. The
+// OpenAI-only prompt_cache_key routing hint is dropped: these
+// providers either reject unknown fields or ignore it. So is a
+// reasoning_effort the provider does not accept.
+func newPresetClient(p Preset, cfg llm.ClientConfig) *OpenAIClient {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = p.BaseURL
+	}
+	if !slices.Contains(p.ReasoningEfforts, cfg.ReasoningEffort) {
+		cfg.ReasoningEffort = ""
+	}
+	c := NewOpenAIClient(cfg)
+	c.extraHeaders = p.Headers
+	c.reasoningOutsideCompletion = p.ReasoningOutsideCompletion
+	c.adaptRequest = func(r *openaiRequest) { r.PromptCacheKey = "" }
+	return c
+}
+
+// newPresetEmbedder builds an OpenAIEmbedder against p's embeddings
+// endpoint, or returns an error when the provider has none.
+func newPresetEmbedder(name string, p Preset, cfg OpenAIEmbedderConfig) (llm.Embedder, error) {
+	if p.EmbeddingModel == "" {
+		return nil, fmt.Errorf("%s does not provide an embedding API; configure an alternative embedding provider", name)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = p.BaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = p.EmbeddingModel
+		cfg.Dims = p.EmbeddingDims
+	}
+	return NewOpenAIEmbedder(cfg), nil
+}
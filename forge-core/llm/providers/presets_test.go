@@ -0,0 +1,191 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestNewClient_Presets(t *testing.T) {
+	for _, name := range PresetNames() {
+		client, err := NewClient(name, llm.ClientConfig{APIKey: "k", Model: "m"})
+		if err != nil {
+			t.Fatalf("NewClient(%q): %v", name, err)
+		}
+		oc, ok := client.(*OpenAIClient)
+		if !ok {
+			t.Fatalf("NewClient(%q) = %T, want *OpenAIClient", name, client)
+		}
+		p, _ := LookupPreset(name)
+		if oc.baseURL != p.BaseURL {
+			t.Errorf("%s baseURL = %q, want %q", name, oc.baseURL, p.BaseURL)
+		}
+	}
+	want := []string{"deepseek", "groq", "openrouter", "together", "xai"}
+	if got := PresetNames(); !slices.Equal(got, want) {
+		t.Errorf("PresetNames() = %v, want %v", got, want)
+	}
+}
+
+func TestPresetClient_HeadersAndRequest(t *testing.T) {
+	var gotHeader http.Header
+	var raw map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		_ = json.NewDecoder(r.Body).Decode(&raw)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("openrouter", llm.ClientConfig{APIKey: "or-key", Model: "openai/gpt-4o", BaseURL: srv.URL, PromptCaching: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Chat(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "ok" {
+		t.Errorf("content = %q", resp.Message.Content)
+	}
+	if got := gotHeader.Get("Authorization"); got != "Bearer or-key" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := gotHeader.Get("X-Title"); got != "Forge" {
+		t.Errorf("X-Title = %q", got)
+	}
+	if _, ok := raw["prompt_cache_key"]; ok {
+		t.Error("prompt_cache_key sent to OpenAI-compatible preset")
+	}
+}
+
+// reasoningServer answers one chat call with body and records the
+// decoded request.
+func reasoningServer(t *testing.T, body string, raw *map[string]json.RawMessage) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(raw)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestXAIClient_ReasoningUsageAndEffort(t *testing.T) {
+	var raw map[string]json.RawMessage
+	srv := reasoningServer(t, `{"choices":[{"message":{"role":"assistant","content":"42","reasoning_content":"let me think"},"finish_reason":"stop"}],
+		"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":112,"completion_tokens_details":{"reasoning_tokens":100}}}`, &raw)
+
+	client, err := NewClient("xai", llm.ClientConfig{APIKey: "k", Model: "grok-3-mini", BaseURL: srv.URL, ReasoningEffort: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Chat(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "q"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "42" {
+		t.Errorf("content = %q, want reasoning excluded", resp.Message.Content)
+	}
+	// xAI reports reasoning beside completion_tokens; OutputTokens is
+	// normalized to include it.
+	if resp.Usage.OutputTokens != 102 || resp.Usage.ReasoningTokens != 100 || resp.Usage.TotalTokens != 112 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+	if got := string(raw["reasoning_effort"]); got != `"high"` {
+		t.Errorf("reasoning_effort = %s", got)
+	}
+}
+
+func TestDeepSeekClient_ReasoningExcludedAndEffortDropped(t *testing.T) {
+	var raw map[string]json.RawMessage
+	srv := reasoningServer(t, `{"choices":[{"message":{"role":"assistant","content":"done","reasoning_content":"step 1..."},"finish_reason":"stop"}],
+		"usage":{"prompt_tokens":5,"completion_tokens":30,"total_tokens":35,"completion_tokens_details":{"reasoning_tokens":25}}}`, &raw)
+
+	client, err := NewClient("deepseek", llm.ClientConfig{APIKey: "k", Model: "deepseek-reasoner", BaseURL: srv.URL, ReasoningEffort: "high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Chat(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "q"}},
+	})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if strings.Contains(resp.Message.Content, "step 1") {
+		t.Errorf("reasoning leaked into content: %q", resp.Message.Content)
+	}
+	// DeepSeek already counts reasoning inside completion_tokens.
+	if resp.Usage.OutputTokens != 30 || resp.Usage.ReasoningTokens != 25 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+	if _, ok := raw["reasoning_effort"]; ok {
+		t.Error("reasoning_effort sent to a provider that does not accept it")
+	}
+}
+
+func TestDeepSeekClient_StreamDropsReasoning(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"delta":{"reasoning_content":"hmm"}}]}`,
+			`{"choices":[{"delta":{"content":"answer"}}]}`,
+			`{"choices":[{"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":9,"total_tokens":10,"completion_tokens_details":{"reasoning_tokens":8}}}`,
+		} {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("deepseek", llm.ClientConfig{APIKey: "k", Model: "deepseek-reasoner", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, err := client.ChatStream(context.Background(), &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "q"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	var content string
+	var usage *llm.UsageInfo
+	for d := range ch {
+		content += d.Content
+		if d.Usage != nil {
+			usage = d.Usage
+		}
+	}
+	if content != "answer" {
+		t.Errorf("streamed content = %q", content)
+	}
+	if usage == nil || usage.ReasoningTokens != 8 || usage.OutputTokens != 9 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestReasoningEfforts(t *testing.T) {
+	if !slices.Contains(ReasoningEfforts("openai"), "minimal") {
+		t.Error("openai should accept minimal")
+	}
+	if got := ReasoningEfforts("xai"); !slices.Equal(got, []string{"low", "high"}) {
+		t.Errorf("xai efforts = %v", got)
+	}
+	for _, p := range []string{"deepseek", "anthropic", "mistral", "bogus"} {
+		if got := ReasoningEfforts(p); got != nil {
+			t.Errorf("ReasoningEfforts(%q) = %v, want nil", p, got)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// RerankerConfig configures a reranker.
+type RerankerConfig struct {
+	APIKey  string
+	BaseURL string
+	Model   string
+}
+
+// rerankDefaults holds each reranking provider's endpoint and model.
+var rerankDefaults = map[string]struct{ url, model string }{
+	// llama.cpp (`llama-server --reranking -m bge-reranker.gguf`) and
+	// Jina-compatible servers such as LocalAI serve /v1/rerank.
+	"local":  {"http://localhost:8080/v1/rerank", "default"},
+	"cohere": {"https://api.cohere.com/v2/rerank", "rerank-v3.5"},
+	"voyage": {"https://api.voyageai.com/v1/rerank", "rerank-2.5-lite"},
+}
+
+// NewReranker creates a Reranker for provider: "local", "cohere" or
+// "voyage". cfg.BaseURL, when set, is the full rerank endpoint URL.
+func NewReranker(provider string, cfg RerankerConfig) (llm.Reranker, error) {
+	d, ok := rerankDefaults[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown rerank provider: %q (want local, cohere or voyage)", provider)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = d.url
+	}
+	if cfg.Model == "" {
+		cfg.Model = d.model
+	}
+	return &HTTPReranker{
+		apiKey: cfg.APIKey,
+		url:    strings.TrimRight(cfg.BaseURL, "/"),
+		model:  cfg.Model,
+		client: &http.Client{Timeout: embeddingTimeout},
+	}, nil
+}
+
+// HTTPReranker implements llm.Reranker over the rerank API shared by
+// Cohere, Voyage AI, Jina and llama.cpp: POST {query, documents, model}
+// and read back one relevance_score per document index.
+type HTTPReranker struct {
+	apiKey string
+	url    string
+	model  string
+	client *http.Client
+}
+
+// Rerank scores req.Documents against req.Query.
+func (r *HTTPReranker) Rerank(ctx context.Context, req *llm.RerankRequest) (*llm.RerankResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = r.model
+	}
+	if len(req.Documents) == 0 {
+		return &llm.RerankResponse{Model: model}, nil
+	}
+
+	data, err := json.Marshal(rerankRequest{Model: model, Query: req.Query, Documents: req.Documents})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling rerank request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("rerank error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var rr rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return nil, fmt.Errorf("decoding rerank response: %w", err)
+	}
+	// Cohere, Jina and llama.cpp answer in "results"; Voyage in "data".
+	results := rr.Results
+	if len(results) == 0 {
+		results = rr.Data
+	}
+	if len(results) != len(req.Documents) {
+		return nil, fmt.Errorf("rerank returned %d scores for %d documents", len(results), len(req.Documents))
+	}
+	scores := make([]float64, len(req.Documents))
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= len(scores) {
+			return nil, fmt.Errorf("rerank returned out-of-range index %d", res.Index)
+		}
+		scores[res.Index] = res.RelevanceScore
+	}
+	return &llm.RerankResponse{Scores: scores, Model: model}, nil
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type rerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+type rerankResponse struct {
+	Results []rerankResult `json:"results"`
+	Data    []rerankResult `json:"data"`
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestHTTPReranker_Rerank(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		body     string
+	}{
+		{"results shape", "cohere", `{"results":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.2}]}`},
+		{"voyage data shape", "voyage", `{"data":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.2}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody rerankRequest
+			var gotAuth string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAuth = r.Header.Get("Authorization")
+				_ = json.NewDecoder(r.Body).Decode(&gotBody)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			rr, err := NewReranker(tt.provider, RerankerConfig{APIKey: "k", BaseURL: srv.URL})
+			if err != nil {
+				t.Fatalf("NewReranker: %v", err)
+			}
+			resp, err := rr.Rerank(context.Background(), &llm.RerankRequest{Query: "q", Documents: []string{"a", "b"}})
+			if err != nil {
+				t.Fatalf("Rerank: %v", err)
+			}
+			if len(resp.Scores) != 2 || resp.Scores[0] != 0.2 || resp.Scores[1] != 0.9 {
+				t.Errorf("Scores = %v, want [0.2 0.9]", resp.Scores)
+			}
+			if gotAuth != "Bearer k" || gotBody.Query != "q" || gotBody.Model != rerankDefaults[tt.provider].model {
+				t.Errorf("request auth=%q body=%+v", gotAuth, gotBody)
+			}
+		})
+	}
+}
+
+func TestNewReranker_UnknownProvider(t *testing.T) {
+	if _, err := NewReranker("openai", RerankerConfig{}); err == nil {
+		t.Error("expected error for a provider without a rerank API")
+	}
+}
@@ -32,16 +32,12 @@ func NewResponsesClient(cfg llm.ClientConfig) *ResponsesClient {
 	if baseURL == "" {
 		baseURL = "https://api.openai.com/v1"
 	}
-	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
-	if timeout == 0 {
-		timeout = 120 * time.Second
-	}
 	return &ResponsesClient{
 		apiKey:  cfg.APIKey,
 		orgID:   cfg.OrgID,
 		baseURL: strings.TrimRight(baseURL, "/"),
 		model:   cfg.Model,
-		client:  &http.Client{Timeout: timeout},
+		client:  newHTTPClient(cfg, 120*time.Second),
 	}
 }
 
@@ -0,0 +1,233 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// speechTimeout bounds one transcription or synthesis call; audio
+// uploads and downloads run longer than embedding calls.
+const speechTimeout = 2 * time.Minute
+
+// SpeechConfig configures a transcriber or synthesizer.
+type SpeechConfig struct {
+	APIKey   string
+	BaseURL  string
+	Model    string
+	Language string // transcriber only
+	Voice    string // synthesizer only
+}
+
+// speechDefaults holds each speech provider's endpoint, models and voice.
+var speechDefaults = map[string]struct{ url, sttModel, ttsModel, voice string }{
+	"openai": {"https://api.openai.com/v1", "whisper-1", "tts-1", "alloy"},
+	// Self-hosted servers speaking the OpenAI audio API — speaches
+	// (faster-whisper, Kokoro, Piper) or LocalAI. Set the model to the
+	// name the server serves it under.
+	"local": {"http://localhost:8000/v1", "whisper-1", "tts-1", "alloy"},
+}
+
+// speechFormats maps the formats the OpenAI speech API produces to
+// their MIME types. "opus" is Ogg Opus, what Telegram plays as a voice
+// note.
+var speechFormats = map[string]string{
+	"opus": "audio/ogg",
+	"mp3":  "audio/mpeg",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"wav":  "audio/wav",
+	"pcm":  "audio/pcm",
+}
+
+// NewTranscriber creates a Transcriber for provider: "openai" (the
+// Whisper API) or "local".
+func NewTranscriber(provider string, cfg SpeechConfig) (llm.Transcriber, error) {
+	d, ok := speechDefaults[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown speech provider: %q (want openai or local)", provider)
+	}
+	if cfg.Model == "" {
+		cfg.Model = d.sttModel
+	}
+	return &OpenAITranscriber{speechClient: newSpeechClient(cfg, d.url), model: cfg.Model, language: cfg.Language}, nil
+}
+
+// NewSynthesizer creates a Synthesizer for provider: "openai" or "local".
+func NewSynthesizer(provider string, cfg SpeechConfig) (llm.Synthesizer, error) {
+	d, ok := speechDefaults[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown speech provider: %q (want openai or local)", provider)
+	}
+	if cfg.Model == "" {
+		cfg.Model = d.ttsModel
+	}
+	if cfg.Voice == "" {
+		cfg.Voice = d.voice
+	}
+	return &OpenAISynthesizer{speechClient: newSpeechClient(cfg, d.url), model: cfg.Model, voice: cfg.Voice}, nil
+}
+
+// SpeechAPIKeyEnv returns the env var a speech provider's API key is
+// read from, or "" for providers that need none (local).
+func SpeechAPIKeyEnv(provider string) string {
+	if provider == "openai" {
+		return "OPENAI_API_KEY"
+	}
+	return ""
+}
+
+// speechClient is the HTTP plumbing shared by the OpenAI audio endpoints.
+type speechClient struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func newSpeechClient(cfg SpeechConfig, defaultURL string) speechClient {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	return speechClient{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: speechTimeout},
+	}
+}
+
+// post sends body to path and returns the response, which the caller
+// closes. A non-200 status is an error carrying the response body.
+func (c speechClient) post(ctx context.Context, path, contentType string, body io.Reader) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return resp, nil
+}
+
+// OpenAITranscriber implements llm.Transcriber over POST
+// /audio/transcriptions, the Whisper API.
+type OpenAITranscriber struct {
+	speechClient
+	model    string
+	language string
+}
+
+// Transcribe uploads req.Audio and returns the recognized text.
+func (t *OpenAITranscriber) Transcribe(ctx context.Context, req *llm.TranscriptionRequest) (*llm.TranscriptionResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = t.model
+	}
+	language := req.Language
+	if language == "" {
+		language = t.language
+	}
+	filename := req.Filename
+	if filename == "" {
+		filename = "audio.ogg"
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fields := map[string]string{"model": model, "response_format": "json"}
+	if language != "" {
+		fields["language"] = language
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return nil, fmt.Errorf("writing transcription request: %w", err)
+		}
+	}
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("writing transcription request: %w", err)
+	}
+	if _, err := part.Write(req.Audio); err != nil {
+		return nil, fmt.Errorf("writing transcription request: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("writing transcription request: %w", err)
+	}
+
+	resp, err := t.post(ctx, "/audio/transcriptions", w.FormDataContentType(), &buf)
+	if err != nil {
+		return nil, fmt.Errorf("transcription request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var out struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding transcription response: %w", err)
+	}
+	return &llm.TranscriptionResponse{Text: strings.TrimSpace(out.Text), Model: model}, nil
+}
+
+// OpenAISynthesizer implements llm.Synthesizer over POST /audio/speech.
+type OpenAISynthesizer struct {
+	speechClient
+	model string
+	voice string
+}
+
+// Synthesize returns req.Text spoken in req.Format (default opus).
+func (s *OpenAISynthesizer) Synthesize(ctx context.Context, req *llm.SpeechRequest) (*llm.SpeechResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = s.model
+	}
+	voice := req.Voice
+	if voice == "" {
+		voice = s.voice
+	}
+	format := req.Format
+	if format == "" {
+		format = "opus"
+	}
+	mimeType, ok := speechFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported speech format %q", format)
+	}
+
+	data, err := json.Marshal(map[string]string{
+		"model": model, "input": req.Text, "voice": voice, "response_format": format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling speech request: %w", err)
+	}
+	resp, err := s.post(ctx, "/audio/speech", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("speech request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading speech response: %w", err)
+	}
+	return &llm.SpeechResponse{Audio: audio, MimeType: mimeType}, nil
+}
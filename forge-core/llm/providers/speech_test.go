@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestOpenAITranscriber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" || r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("request %s auth=%q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		file, hdr, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("file field: %v", err)
+		}
+		audio, _ := io.ReadAll(file)
+		if hdr.Filename != "voice.ogg" || string(audio) != "OggS..." {
+			t.Errorf("file = %s %q", hdr.Filename, audio)
+		}
+		if r.FormValue("model") != "whisper-1" || r.FormValue("language") != "de" {
+			t.Errorf("model=%q language=%q", r.FormValue("model"), r.FormValue("language"))
+		}
+		_, _ = w.Write([]byte(`{"text":" Wie spät ist es? "}`))
+	}))
+	defer srv.Close()
+
+	tr, err := NewTranscriber("openai", SpeechConfig{APIKey: "sk-test", BaseURL: srv.URL + "/v1/", Language: "de"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tr.Transcribe(context.Background(), &llm.TranscriptionRequest{Audio: []byte("OggS..."), Filename: "voice.ogg"})
+	if err != nil {
+		t.Fatalf("Transcribe: %v", err)
+	}
+	if resp.Text != "Wie spät ist es?" {
+		t.Errorf("text = %q", resp.Text)
+	}
+}
+
+func TestOpenAISynthesizer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/audio/speech" || body["voice"] != "nova" || body["response_format"] != "opus" || body["input"] != "Hello" {
+			t.Errorf("request %s %v", r.URL.Path, body)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("local provider sent an Authorization header without a key")
+		}
+		_, _ = w.Write([]byte("OggS-audio"))
+	}))
+	defer srv.Close()
+
+	s, err := NewSynthesizer("local", SpeechConfig{BaseURL: srv.URL, Voice: "nova"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.Synthesize(context.Background(), &llm.SpeechRequest{Text: "Hello"})
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if string(resp.Audio) != "OggS-audio" || resp.MimeType != "audio/ogg" {
+		t.Errorf("response = %q %s", resp.Audio, resp.MimeType)
+	}
+	if _, err := s.Synthesize(context.Background(), &llm.SpeechRequest{Text: "Hello", Format: "midi"}); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestSpeechProviderErrors(t *testing.T) {
+	if _, err := NewTranscriber("deepgram", SpeechConfig{}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid file format"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	tr, _ := NewTranscriber("local", SpeechConfig{BaseURL: srv.URL})
+	if _, err := tr.Transcribe(context.Background(), &llm.TranscriptionRequest{Audio: []byte("x")}); err == nil {
+		t.Error("expected an error for a 400 response")
+	}
+}
@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Connection pool defaults, used when TransportConfig leaves a field
+// zero. Every provider client shares one pool, so the per-host idle
+// limit is what keeps a busy agent's calls on warm connections —
+// http.DefaultTransport keeps only two per host.
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 32
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// TransportConfig configures the HTTP transport shared by the provider
+// clients (see ClientConfig.Transport).
+type TransportConfig struct {
+	// MaxIdleConns bounds idle connections across all hosts, and
+	// MaxIdleConnsPerHost those kept per provider host. Zero takes the
+	// defaults above.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps connections per host, idle or not; calls
+	// past it wait for one to free up. Zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer. Zero takes
+	// DefaultIdleConnTimeout.
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 keeps every connection on HTTP/1.1. HTTP/2 is
+	// negotiated by default, multiplexing concurrent calls to a
+	// provider over one connection.
+	DisableHTTP2 bool
+	// ProxyURL sends provider traffic through an HTTP(S) proxy. Empty
+	// honors HTTPS_PROXY / NO_PROXY from the environment.
+	ProxyURL string
+	// CAFile is a PEM bundle trusted in addition to the system roots,
+	// for gateways behind a private CA.
+	CAFile string
+	// TLSMinVersion is "1.2" (default) or "1.3".
+	TLSMinVersion string
+	// InsecureSkipVerify disables certificate verification. For local
+	// gateways with self-signed certificates only.
+	InsecureSkipVerify bool
+}
+
+// TransportStats is a snapshot of a Transport's counters. A high
+// ReusedConns share means calls skip the TCP and TLS handshakes.
+type TransportStats struct {
+	Requests      int64 `json:"requests"`
+	NewConns      int64 `json:"new_conns"`
+	ReusedConns   int64 `json:"reused_conns"`
+	HTTP2Requests int64 `json:"http2_requests"`
+	Errors        int64 `json:"errors"`
+}
+
+// Transport is the http.RoundTripper shared by provider clients: one
+// tuned connection pool, with connection-reuse counters.
+type Transport struct {
+	base *http.Transport
+
+	requests, newConns, reusedConns, http2, errors atomic.Int64
+}
+
+// NewTransport builds a Transport from cfg.
+func NewTransport(cfg TransportConfig) (*Transport, error) {
+	tlsCfg, err := transportTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.ProxyURL)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	base := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsCfg,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+	}
+	if base.MaxIdleConns <= 0 {
+		base.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if base.MaxIdleConnsPerHost <= 0 {
+		base.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if base.IdleConnTimeout <= 0 {
+		base.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	// A custom TLS config turns off Go's automatic HTTP/2, so the
+	// protocols are always set explicitly.
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(!cfg.DisableHTTP2)
+	base.Protocols = protocols
+
+	return &Transport{base: base}, nil
+}
+
+func transportTLSConfig(cfg TransportConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // explicit operator opt-in
+	}
+	switch cfg.TLSMinVersion {
+	case "", "1.2":
+	case "1.3":
+		tlsCfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS min version %q (want 1.2 or 1.3)", cfg.TLSMinVersion)
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %s holds no PEM certificates", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return tlsCfg, nil
+}
+
+// RoundTrip sends req on the shared pool, counting whether it went out
+// on a new or a reused connection.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.reusedConns.Add(1)
+			} else {
+				t.newConns.Add(1)
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.errors.Add(1)
+		return nil, err
+	}
+	if resp.ProtoMajor == 2 {
+		t.http2.Add(1)
+	}
+	return resp, nil
+}
+
+// Stats returns the transport's counters. Safe for concurrent use.
+func (t *Transport) Stats() TransportStats {
+	return TransportStats{
+		Requests:      t.requests.Load(),
+		NewConns:      t.newConns.Load(),
+		ReusedConns:   t.reusedConns.Load(),
+		HTTP2Requests: t.http2.Load(),
+		Errors:        t.errors.Load(),
+	}
+}
+
+// CloseIdleConnections closes the pool's idle connections.
+func (t *Transport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// newHTTPClient is the http.Client of a provider client: cfg's
+// timeout, or defaultTimeout when unset, on cfg's transport (nil, for
+// http.DefaultTransport, when none is configured).
+func newHTTPClient(cfg llm.ClientConfig, defaultTimeout time.Duration) *http.Client {
+	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &http.Client{Timeout: timeout, Transport: cfg.Transport}
+}
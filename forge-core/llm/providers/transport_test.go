@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestTransport_ReusesConnections(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`)
+	}))
+	defer srv.Close()
+
+	tr, err := NewTransport(TransportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewOpenAIClient(llm.ClientConfig{APIKey: "k", BaseURL: srv.URL, Model: "gpt-4o", Transport: tr})
+	for range 3 {
+		if _, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s := tr.Stats(); s.Requests != 3 || s.NewConns != 1 || s.ReusedConns != 2 || s.Errors != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestTransport_HTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(ca, pemCert(srv.Certificate().Raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, disable := range []bool{false, true} {
+		tr, err := NewTransport(TransportConfig{CAFile: ca, DisableHTTP2: disable})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		want := "HTTP/2.0"
+		if disable {
+			want = "HTTP/1.1"
+		}
+		if string(body) != want {
+			t.Errorf("DisableHTTP2=%v: proto = %s, want %s", disable, body, want)
+		}
+	}
+}
+
+func TestNewTransport_InvalidConfig(t *testing.T) {
+	for _, tc := range []struct {
+		cfg  TransportConfig
+		want string
+	}{
+		{TransportConfig{ProxyURL: "::"}, "invalid proxy URL"},
+		{TransportConfig{TLSMinVersion: "1.1"}, "unsupported TLS min version"},
+		{TransportConfig{CAFile: "/nonexistent/ca.pem"}, "reading CA file"},
+	} {
+		if _, err := NewTransport(tc.cfg); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: err = %v, want %q", tc.cfg, err, tc.want)
+		}
+	}
+}
+
+func pemCert(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
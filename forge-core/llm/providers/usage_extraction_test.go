@@ -15,7 +15,7 @@ import (
 // populate the normalized UsageInfo.InputTokens / OutputTokens /
 // TotalTokens from its native response shape so the audit layer can
 // emit accurate llm_call events regardless of which provider served
-// the call. The OpenAI-compatible path also serves Ollama.
+// the call.
 
 func TestAnthropic_PopulatesUsageWithOTelAlignedNames(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -99,15 +99,11 @@ func TestOllama_NoUsage_LeavesZerosForAuditUnavailableFlag(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, _ = io.Copy(io.Discard, r.Body)
 		_ = json.NewEncoder(w).Encode(map[string]any{
-			"id": "ollama-1",
-			"choices": []map[string]any{
-				{
-					"index":         0,
-					"message":       map[string]any{"role": "assistant", "content": "ok"},
-					"finish_reason": "stop",
-				},
-			},
-			// usage field deliberately absent
+			"model":       "llama3",
+			"message":     map[string]any{"role": "assistant", "content": "ok"},
+			"done":        true,
+			"done_reason": "stop",
+			// prompt_eval_count / eval_count deliberately absent
 		})
 	}))
 	defer srv.Close()
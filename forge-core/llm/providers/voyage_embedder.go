@@ -0,0 +1,142 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+const (
+	voyageDefaultEmbeddingModel = "voyage-3.5"
+	voyageDefaultEmbeddingDims  = 1024
+	voyageDefaultBaseURL        = "https://api.voyageai.com/v1"
+)
+
+// VoyageEmbedder implements llm.Embedder using the Voyage AI embeddings
+// API — the embedding provider Anthropic recommends, so Claude users
+// get vector memory without an OpenAI or Gemini account.
+type VoyageEmbedder struct {
+	apiKey  string
+	baseURL string
+	model   string
+	dims    int
+	client  *http.Client
+}
+
+// NewVoyageEmbedder creates a Voyage AI embedder. OrgID is ignored. Dims
+// is sent as output_dimension, which voyage-3.5 and newer accept (256,
+// 512, 1024 or 2048).
+func NewVoyageEmbedder(cfg OpenAIEmbedderConfig) *VoyageEmbedder {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = voyageDefaultBaseURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = voyageDefaultEmbeddingModel
+	}
+	dims := cfg.Dims
+	if dims <= 0 {
+		dims = voyageDefaultEmbeddingDims
+	}
+	return &VoyageEmbedder{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   model,
+		dims:    dims,
+		client:  &http.Client{Timeout: embeddingTimeout},
+	}
+}
+
+func (e *VoyageEmbedder) Dimensions() int { return e.dims }
+
+// Embed produces embeddings for the given texts using POST /embeddings.
+// Texts are embedded as documents for the same reason as
+// CohereEmbedder.Embed.
+func (e *VoyageEmbedder) Embed(ctx context.Context, req *llm.EmbeddingRequest) (*llm.EmbeddingResponse, error) {
+	if len(req.Texts) == 0 {
+		return &llm.EmbeddingResponse{Model: e.model}, nil
+	}
+
+	model := req.Model
+	if model == "" {
+		model = e.model
+	}
+
+	body := voyageEmbedRequest{
+		Model:           model,
+		Input:           req.Texts,
+		InputType:       "document",
+		OutputDimension: e.dims,
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling embedding request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embedding error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var embResp voyageEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("decoding embedding response: %w", err)
+	}
+
+	embeddings := make([][]float32, len(embResp.Data))
+	for _, d := range embResp.Data {
+		if d.Index >= 0 && d.Index < len(embeddings) {
+			embeddings[d.Index] = d.Embedding
+		}
+	}
+
+	return &llm.EmbeddingResponse{
+		Embeddings: embeddings,
+		Model:      model,
+		Usage: llm.UsageInfo{
+			InputTokens: embResp.Usage.TotalTokens,
+			TotalTokens: embResp.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// voyageEmbedRequest is the Voyage AI embeddings request format.
+type voyageEmbedRequest struct {
+	Model           string   `json:"model"`
+	Input           []string `json:"input"`
+	InputType       string   `json:"input_type,omitempty"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+}
+
+// voyageEmbedResponse is the Voyage AI embeddings response format.
+type voyageEmbedResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
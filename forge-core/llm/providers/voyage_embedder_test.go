@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestVoyageEmbedder_Embed(t *testing.T) {
+	var gotAuth string
+	var gotBody voyageEmbedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		// Out of order: results are placed by index.
+		_, _ = w.Write([]byte(`{"data":[{"embedding":[0.3,0.4],"index":1},{"embedding":[0.1,0.2],"index":0}],"usage":{"total_tokens":5}}`))
+	}))
+	defer srv.Close()
+
+	emb := NewVoyageEmbedder(OpenAIEmbedderConfig{APIKey: "pa-key", BaseURL: srv.URL, Dims: 512})
+	if emb.Dimensions() != 512 {
+		t.Errorf("Dimensions = %d, want 512", emb.Dimensions())
+	}
+	resp, err := emb.Embed(context.Background(), &llm.EmbeddingRequest{Texts: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if gotAuth != "Bearer pa-key" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotBody.Model != voyageDefaultEmbeddingModel || gotBody.InputType != "document" || gotBody.OutputDimension != 512 {
+		t.Errorf("request = %+v", gotBody)
+	}
+	if len(resp.Embeddings) != 2 || resp.Embeddings[0][0] != 0.1 || resp.Embeddings[1][0] != 0.3 {
+		t.Errorf("embeddings = %v", resp.Embeddings)
+	}
+	if resp.Usage.TotalTokens != 5 {
+		t.Errorf("TotalTokens = %d, want 5", resp.Usage.TotalTokens)
+	}
+}
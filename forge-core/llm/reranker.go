@@ -0,0 +1,24 @@
+package llm
+
+import "context"
+
+// RerankRequest asks a reranker to score documents against a query.
+type RerankRequest struct {
+	Query     string
+	Documents []string
+	Model     string // optional model override
+}
+
+// RerankResponse holds one relevance score per request document, in
+// request order. Higher is more relevant; the scale is the provider's.
+type RerankResponse struct {
+	Scores []float64
+	Model  string
+}
+
+// Reranker scores query/document pairs with a cross-encoder, which
+// reads the query and each document together and so judges relevance
+// more precisely than comparing separately computed embeddings.
+type Reranker interface {
+	Rerank(ctx context.Context, req *RerankRequest) (*RerankResponse, error)
+}
@@ -0,0 +1,315 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Response cache defaults, used when ResponseCacheConfig leaves a field
+// zero.
+const (
+	DefaultResponseCacheTTL        = time.Hour
+	DefaultResponseCacheMaxEntries = 1000
+)
+
+// ResponseCacheConfig configures a ResponseCache.
+type ResponseCacheConfig struct {
+	// TTL is how long a stored response is served. Zero means
+	// DefaultResponseCacheTTL.
+	TTL time.Duration
+	// MaxEntries bounds the in-memory cache; the least recently used
+	// response is evicted first. Zero means DefaultResponseCacheMaxEntries.
+	MaxEntries int
+	// Dir, when set, also persists responses there (one JSON file per
+	// key) so eval runs and CI jobs replay them across processes.
+	Dir string
+}
+
+// ResponseCacheStats is a snapshot of a ResponseCache's counters.
+type ResponseCacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Bypassed  int64 `json:"bypassed"`
+	Stores    int64 `json:"stores"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+}
+
+// ResponseCache stores LLM responses for idempotent requests — those
+// that set temperature to 0 — keyed by provider, model, messages, tools
+// and max tokens. A request without a temperature samples at the
+// provider's default, which is not 0, so it is not cached. It is
+// meant for eval runs, replays and CI, where the same prompt is sent
+// again and a stored answer is as good as a fresh one; it is off by
+// default because live agents rarely repeat a conversation exactly.
+//
+// One cache is shared by every provider client; Wrap adds it to one.
+type ResponseCache struct {
+	ttl time.Duration
+	max int
+	dir string
+	now func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+
+	hits, misses, bypassed, stores, evictions atomic.Int64
+}
+
+type cacheEntry struct {
+	Key      string        `json:"key"`
+	StoredAt time.Time     `json:"stored_at"`
+	Response *ChatResponse `json:"response"`
+}
+
+// NewResponseCache returns a cache configured by cfg, creating cfg.Dir
+// when set.
+func NewResponseCache(cfg ResponseCacheConfig) (*ResponseCache, error) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultResponseCacheTTL
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultResponseCacheMaxEntries
+	}
+	if cfg.Dir != "" {
+		if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+			return nil, fmt.Errorf("creating response cache dir: %w", err)
+		}
+	}
+	return &ResponseCache{
+		ttl:     cfg.TTL,
+		max:     cfg.MaxEntries,
+		dir:     cfg.Dir,
+		now:     time.Now,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}, nil
+}
+
+// Stats returns the cache's counters. Safe for concurrent use.
+func (c *ResponseCache) Stats() ResponseCacheStats {
+	c.mu.Lock()
+	n := c.lru.Len()
+	c.mu.Unlock()
+	return ResponseCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Bypassed:  c.bypassed.Load(),
+		Stores:    c.stores.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   n,
+	}
+}
+
+// Wrap returns client with the cache in front of it. provider is part
+// of the key, so the same model name on two providers never collides.
+func (c *ResponseCache) Wrap(provider string, client Client) Client {
+	return &CachingClient{cache: c, provider: provider, client: client}
+}
+
+// CachingClient implements Client by answering repeated idempotent
+// requests from a ResponseCache. A hit returns the stored response with
+// Cached set and zero usage, since no tokens were spent.
+type CachingClient struct {
+	cache    *ResponseCache
+	provider string
+	client   Client
+}
+
+// Chat answers req from the cache, or calls the wrapped client and
+// stores a successful response.
+func (cc *CachingClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if responseCacheDisabled(ctx) {
+		return cc.client.Chat(ctx, req)
+	}
+	key, ok := cc.key(req)
+	if !ok {
+		cc.cache.bypassed.Add(1)
+		return cc.client.Chat(ctx, req)
+	}
+	if resp := cc.cache.get(key); resp != nil {
+		cc.cache.hits.Add(1)
+		return resp, nil
+	}
+	cc.cache.misses.Add(1)
+	resp, err := cc.client.Chat(ctx, req)
+	if err == nil {
+		cc.cache.put(key, resp)
+	}
+	return resp, err
+}
+
+// ChatStream replays a stored response as a single delta on a hit.
+// Misses stream from the wrapped client and are not stored: partial
+// tool-call deltas would have to be reassembled to make a cacheable
+// response.
+func (cc *CachingClient) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamDelta, error) {
+	if responseCacheDisabled(ctx) {
+		return cc.client.ChatStream(ctx, req)
+	}
+	key, ok := cc.key(req)
+	if !ok {
+		cc.cache.bypassed.Add(1)
+		return cc.client.ChatStream(ctx, req)
+	}
+	resp := cc.cache.get(key)
+	if resp == nil {
+		cc.cache.misses.Add(1)
+		return cc.client.ChatStream(ctx, req)
+	}
+	cc.cache.hits.Add(1)
+	ch := make(chan StreamDelta, 2)
+	ch <- StreamDelta{
+		Content:      resp.Message.Content,
+		ToolCalls:    resp.Message.ToolCalls,
+		Thinking:     resp.Message.Thinking,
+		Citations:    resp.Message.Citations,
+		FinishReason: resp.FinishReason,
+		Usage:        &resp.Usage,
+	}
+	ch <- StreamDelta{Done: true}
+	close(ch)
+	return ch, nil
+}
+
+// ModelID returns the wrapped client's model identifier.
+func (cc *CachingClient) ModelID() string {
+	return cc.client.ModelID()
+}
+
+// key hashes the parts of req that determine the answer. It reports
+// false for requests that sample — any without an explicit temperature
+// of 0 — which are never cached.
+func (cc *CachingClient) key(req *ChatRequest) (string, bool) {
+	if req.Temperature == nil || *req.Temperature != 0 {
+		return "", false
+	}
+	model := req.Model
+	if model == "" {
+		model = cc.client.ModelID()
+	}
+	data, err := json.Marshal(struct {
+		Provider  string           `json:"provider"`
+		Model     string           `json:"model"`
+		Messages  []ChatMessage    `json:"messages"`
+		Tools     []ToolDefinition `json:"tools,omitempty"`
+		MaxTokens int              `json:"max_tokens,omitempty"`
+	}{cc.provider, model, req.Messages, req.Tools, req.MaxTokens})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
+
+// get returns a copy of the live response stored under key, checking
+// the disk store when the key is not in memory.
+func (c *ResponseCache) get(key string) *ChatResponse {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var e *cacheEntry
+	if el, ok := c.entries[key]; ok {
+		e = el.Value.(*cacheEntry)
+		c.lru.MoveToFront(el)
+	} else if e = c.readLocked(key); e != nil {
+		c.addLocked(e)
+	}
+	if e == nil {
+		return nil
+	}
+	if now.Sub(e.StoredAt) >= c.ttl {
+		c.removeLocked(key)
+		return nil
+	}
+	resp := *e.Response
+	resp.Message.ToolCalls = append([]ToolCall(nil), e.Response.Message.ToolCalls...)
+	resp.Usage = UsageInfo{}
+	resp.Cached = true
+	return &resp
+}
+
+func (c *ResponseCache) put(key string, resp *ChatResponse) {
+	stored := *resp
+	stored.Route = nil
+	e := &cacheEntry{Key: key, StoredAt: c.now(), Response: &stored}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(key)
+	c.addLocked(e)
+	c.stores.Add(1)
+	c.writeLocked(e)
+}
+
+func (c *ResponseCache) addLocked(e *cacheEntry) {
+	c.entries[e.Key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.max {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).Key)
+		c.evictions.Add(1)
+	}
+}
+
+// removeLocked drops key from memory and disk.
+func (c *ResponseCache) removeLocked(key string) {
+	if el, ok := c.entries[key]; ok {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+	if c.dir != "" {
+		_ = os.Remove(c.path(key))
+	}
+}
+
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// readLocked loads key from the disk store, or returns nil.
+func (c *ResponseCache) readLocked(key string) *cacheEntry {
+	if c.dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key || e.Response == nil {
+		return nil
+	}
+	return &e
+}
+
+// writeLocked persists e atomically (temp file + rename). A failed
+// write only costs a future miss, so errors are dropped.
+func (c *ResponseCache) writeLocked(e *cacheEntry) {
+	if c.dir == "" {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	path := c.path(e.Key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
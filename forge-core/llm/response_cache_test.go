@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func countingClient(model string, calls *int) *mockClient {
+	return &mockClient{
+		modelID: model,
+		chatFunc: func(_ context.Context, req *ChatRequest) (*ChatResponse, error) {
+			*calls++
+			return &ChatResponse{
+				Message: ChatMessage{Role: RoleAssistant, Content: "answer to " + req.Messages[0].Content},
+				Usage:   UsageInfo{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+			}, nil
+		},
+	}
+}
+
+func TestResponseCache_Chat(t *testing.T) {
+	cache, err := NewResponseCache(ResponseCacheConfig{MaxEntries: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int
+	client := cache.Wrap("openai", countingClient("gpt-4o", &calls))
+	ctx := context.Background()
+	ask := func(s string) *ChatRequest {
+		return &ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: s}}, Temperature: new(float64)}
+	}
+
+	first, _ := client.Chat(ctx, ask("a"))
+	again, _ := client.Chat(ctx, ask("a"))
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+	if first.Cached || !again.Cached || again.Message.Content != "answer to a" || again.Usage.TotalTokens != 0 {
+		t.Errorf("first = %+v, again = %+v", first, again)
+	}
+
+	// Sampling requests bypass the cache, including those at the
+	// provider's default temperature.
+	warm := 0.7
+	_, _ = client.Chat(ctx, &ChatRequest{Messages: ask("a").Messages, Temperature: &warm})
+	_, _ = client.Chat(ctx, &ChatRequest{Messages: ask("a").Messages})
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+
+	// The same prompt on another provider is a different key.
+	var otherCalls int
+	_, _ = cache.Wrap("groq", countingClient("gpt-4o", &otherCalls)).Chat(ctx, ask("a"))
+	if otherCalls != 1 {
+		t.Errorf("other provider calls = %d, want 1", otherCalls)
+	}
+	_, _ = client.Chat(ctx, ask("b"))
+
+	s := cache.Stats()
+	if s.Hits != 1 || s.Misses != 3 || s.Bypassed != 2 || s.Stores != 3 || s.Evictions != 1 || s.Entries != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	// Expired entries are not served.
+	cache.now = func() time.Time { return time.Now().Add(2 * DefaultResponseCacheTTL) }
+	_, _ = cache.Wrap("groq", countingClient("gpt-4o", &otherCalls)).Chat(ctx, ask("a"))
+	if otherCalls != 2 {
+		t.Errorf("expired entry served: calls = %d", otherCalls)
+	}
+}
+
+func TestResponseCache_DefaultTemperatureNotCached(t *testing.T) {
+	cache, err := NewResponseCache(ResponseCacheConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int
+	client := cache.Wrap("openai", countingClient("gpt-4o", &calls))
+	req := &ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: "q"}}}
+	for i := 0; i < 2; i++ {
+		if resp, _ := client.Chat(context.Background(), req); resp.Cached {
+			t.Error("a request without a temperature was answered from the cache")
+		}
+	}
+	if s := cache.Stats(); calls != 2 || s.Stores != 0 || s.Bypassed != 2 {
+		t.Errorf("calls = %d, stats = %+v", calls, s)
+	}
+}
+
+func TestResponseCache_DiskReplay(t *testing.T) {
+	dir := t.TempDir()
+	req := &ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: "q"}}, Temperature: new(float64)}
+
+	var calls int
+	first, _ := NewResponseCache(ResponseCacheConfig{Dir: dir})
+	_, _ = first.Wrap("openai", countingClient("gpt-4o", &calls)).Chat(context.Background(), req)
+
+	// A new process replays the stored answer, including over a stream.
+	second, _ := NewResponseCache(ResponseCacheConfig{Dir: dir})
+	client := second.Wrap("openai", countingClient("gpt-4o", &calls))
+	resp, err := client.Chat(context.Background(), req)
+	if err != nil || !resp.Cached || calls != 1 {
+		t.Fatalf("replay: resp = %+v, err = %v, calls = %d", resp, err, calls)
+	}
+	ch, err := client.ChatStream(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var content string
+	for d := range ch {
+		content += d.Content
+	}
+	if content != "answer to q" || calls != 1 {
+		t.Errorf("stream replay = %q, calls = %d", content, calls)
+	}
+}
@@ -0,0 +1,237 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RouteCondition selects the LLM calls a Route applies to. Every set
+// field must match; a zero RouteCondition matches every call.
+type RouteCondition struct {
+	// Metadata entries must equal the task's request metadata (see
+	// WithRequestMetadata), compared as strings.
+	Metadata map[string]string
+	// Tags must all appear in the task's "tags" metadata, given either
+	// as a list or a comma-separated string.
+	Tags []string
+	// MinInputTokens / MaxInputTokens bound the estimated prompt size
+	// (about four characters per token). Zero means unbounded.
+	MinInputTokens int
+	MaxInputTokens int
+	// Tools, when set, requires the request to offer tools (true) or
+	// none (false).
+	Tools *bool
+	// ToolResults, when set, requires the turn to follow tool results
+	// (true) or a user message (false). Tool-heavy loops spend most of
+	// their calls on the former.
+	ToolResults *bool
+}
+
+// Route sends the calls its condition matches to Client instead of the
+// default model. A pinned route ignores its condition and serves only
+// the calls of tasks pinned to it with WithRoute.
+type Route struct {
+	ID       string
+	When     RouteCondition
+	Provider string
+	Model    string
+	Client   Client
+	Pinned   bool
+}
+
+// RouteDecision records which route served a call. RoutingClient sets
+// it on ChatResponse.Route so audit events carry the model that
+// actually answered.
+type RouteDecision struct {
+	ID       string
+	Provider string
+	Model    string
+}
+
+// RoutingClient implements Client by picking a model per call: the
+// first route whose condition matches serves the call, and calls no
+// route matches go to the default client (typically the primary model's
+// FallbackChain). A routed call that fails with a retriable error is
+// retried on the default client, so a route never makes the agent less
+// available than it was without one.
+type RoutingClient struct {
+	routes []Route
+	def    Client
+}
+
+// NewRoutingClient wraps def with routes, checked in order.
+func NewRoutingClient(def Client, routes []Route) *RoutingClient {
+	return &RoutingClient{routes: routes, def: def}
+}
+
+// Chat sends req to the matching route, or the default client.
+func (rc *RoutingClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	route := rc.match(ctx, req)
+	if route == nil {
+		return rc.def.Chat(ctx, req)
+	}
+	resp, err := route.Client.Chat(ctx, req)
+	if err == nil {
+		resp.Route = &RouteDecision{ID: route.ID, Provider: route.Provider, Model: route.Model}
+		return resp, nil
+	}
+	if ctx.Err() != nil || !ClassifyError(err, route.Provider, route.Model).IsRetriable() {
+		return nil, fmt.Errorf("model route %s: %w", route.ID, err)
+	}
+	return rc.def.Chat(ctx, req)
+}
+
+// ChatStream streams from the matching route, or the default client.
+func (rc *RoutingClient) ChatStream(ctx context.Context, req *ChatRequest) (<-chan StreamDelta, error) {
+	route := rc.match(ctx, req)
+	if route == nil {
+		return rc.def.ChatStream(ctx, req)
+	}
+	ch, err := route.Client.ChatStream(ctx, req)
+	if err == nil {
+		return ch, nil
+	}
+	if ctx.Err() != nil || !ClassifyError(err, route.Provider, route.Model).IsRetriable() {
+		return nil, fmt.Errorf("model route %s: %w", route.ID, err)
+	}
+	return rc.def.ChatStream(ctx, req)
+}
+
+// ModelID returns the default client's model identifier.
+func (rc *RoutingClient) ModelID() string {
+	return rc.def.ModelID()
+}
+
+func (rc *RoutingClient) match(ctx context.Context, req *ChatRequest) *Route {
+	if id, _ := ctx.Value(pinnedRouteKey{}).(string); id != "" {
+		for i := range rc.routes {
+			if rc.routes[i].Pinned && rc.routes[i].ID == id {
+				return &rc.routes[i]
+			}
+		}
+	}
+	md := RequestMetadataFromContext(ctx)
+	tokens := -1 // estimated lazily; most routes never ask
+	for i := range rc.routes {
+		if !rc.routes[i].Pinned && rc.routes[i].When.matches(md, req, &tokens) {
+			return &rc.routes[i]
+		}
+	}
+	return nil
+}
+
+// MatchesMetadata reports whether request metadata md satisfies the
+// condition's Metadata and Tags, the parts that hold for a whole task.
+func (c *RouteCondition) MatchesMetadata(md map[string]any) bool {
+	for k, want := range c.Metadata {
+		v, ok := md[k]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	if len(c.Tags) > 0 {
+		tags := metadataTags(md["tags"])
+		for _, t := range c.Tags {
+			if !slices.Contains(tags, t) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *RouteCondition) matches(md map[string]any, req *ChatRequest, tokens *int) bool {
+	if !c.MatchesMetadata(md) {
+		return false
+	}
+	if c.Tools != nil && *c.Tools != (len(req.Tools) > 0) {
+		return false
+	}
+	if c.ToolResults != nil {
+		last := len(req.Messages) > 0 && req.Messages[len(req.Messages)-1].Role == RoleTool
+		if *c.ToolResults != last {
+			return false
+		}
+	}
+	if c.MinInputTokens > 0 || c.MaxInputTokens > 0 {
+		if *tokens < 0 {
+			*tokens = EstimateInputTokens(req)
+		}
+		if c.MinInputTokens > 0 && *tokens < c.MinInputTokens {
+			return false
+		}
+		if c.MaxInputTokens > 0 && *tokens > c.MaxInputTokens {
+			return false
+		}
+	}
+	return true
+}
+
+// metadataTags reads a "tags" metadata value sent as a JSON list or a
+// comma-separated string.
+func metadataTags(v any) []string {
+	var tags []string
+	switch t := v.(type) {
+	case string:
+		for _, s := range strings.Split(t, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				tags = append(tags, s)
+			}
+		}
+	case []string:
+		tags = t
+	case []any:
+		for _, s := range t {
+			tags = append(tags, fmt.Sprint(s))
+		}
+	}
+	return tags
+}
+
+// EstimateInputTokens approximates the prompt size of req at four
+// characters per token — the same heuristic the runtime's context
+// budget uses. Good enough to tell a one-line question from a
+// long-document task; not a billing figure.
+func EstimateInputTokens(req *ChatRequest) int {
+	chars := 0
+	for _, m := range req.Messages {
+		chars += len(m.Content)
+		for _, tc := range m.ToolCalls {
+			chars += len(tc.Function.Name) + len(tc.Function.Arguments)
+		}
+	}
+	for _, t := range req.Tools {
+		chars += len(t.Function.Name) + len(t.Function.Description) + len(t.Function.Parameters)
+	}
+	return chars / 4
+}
+
+type requestMetadataKey struct{}
+
+type pinnedRouteKey struct{}
+
+// WithRoute pins the LLM calls made with ctx to the pinned route with
+// id, such as an experiment variant's model. Calls fall back to the
+// default client as for any route; without a RoutingClient, or a
+// pinned route of that id, the pin is ignored.
+func WithRoute(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, pinnedRouteKey{}, id)
+}
+
+// WithRequestMetadata attaches the task's request metadata (A2A
+// tasks/send params.metadata) to ctx for RoutingClient conditions.
+func WithRequestMetadata(ctx context.Context, md map[string]any) context.Context {
+	if len(md) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, requestMetadataKey{}, md)
+}
+
+// RequestMetadataFromContext returns the metadata set by
+// WithRequestMetadata, or nil.
+func RequestMetadataFromContext(ctx context.Context) map[string]any {
+	md, _ := ctx.Value(requestMetadataKey{}).(map[string]any)
+	return md
+}
@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRoutingClient_Match(t *testing.T) {
+	yes, no := true, false
+	rc := NewRoutingClient(okClient("big"), []Route{
+		{ID: "cheap", When: RouteCondition{Tags: []string{"cheap"}}, Provider: "openai", Model: "small", Client: okClient("small")},
+		{ID: "tenant", When: RouteCondition{Metadata: map[string]string{"tenant": "acme"}}, Provider: "openai", Model: "acme", Client: okClient("acme")},
+		{ID: "tool-turns", When: RouteCondition{ToolResults: &yes}, Provider: "groq", Model: "fast", Client: okClient("fast")},
+		{ID: "short", When: RouteCondition{MaxInputTokens: 10, Tools: &no}, Provider: "openai", Model: "mini", Client: okClient("mini")},
+		{ID: "long", When: RouteCondition{MinInputTokens: 1000}, Provider: "gemini", Model: "pro", Client: okClient("pro")},
+	})
+
+	user := func(s string) *ChatRequest {
+		return &ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: s}}}
+	}
+	withTools := func(req *ChatRequest) *ChatRequest {
+		req.Tools = []ToolDefinition{{Type: "function", Function: FunctionSchema{Name: "t"}}}
+		return req
+	}
+	medium := strings.Repeat("x", 400)
+
+	tests := []struct {
+		name  string
+		md    map[string]any
+		req   *ChatRequest
+		route string // "" = default client
+	}{
+		{"tag list", map[string]any{"tags": []any{"cheap", "batch"}}, withTools(user(medium)), "cheap"},
+		{"tag string", map[string]any{"tags": "batch, cheap"}, withTools(user(medium)), "cheap"},
+		{"metadata", map[string]any{"tenant": "acme"}, withTools(user(medium)), "tenant"},
+		{"metadata mismatch", map[string]any{"tenant": "other"}, withTools(user(medium)), ""},
+		{"after tool result", nil, withTools(&ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: medium}, {Role: RoleTool, Content: "r"}}}), "tool-turns"},
+		{"short without tools", nil, user("hi"), "short"},
+		{"short with tools", nil, withTools(user("hi")), ""},
+		{"long context", nil, withTools(user(strings.Repeat("x", 5000))), "long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := rc.Chat(WithRequestMetadata(context.Background(), tt.md), tt.req)
+			if err != nil {
+				t.Fatalf("Chat: %v", err)
+			}
+			got := ""
+			if resp.Route != nil {
+				got = resp.Route.ID
+			}
+			if got != tt.route {
+				t.Errorf("route = %q, want %q (%s)", got, tt.route, resp.Message.Content)
+			}
+		})
+	}
+}
+
+func TestRoutingClient_Pinned(t *testing.T) {
+	rc := NewRoutingClient(okClient("big"), []Route{
+		{ID: "experiment:x/b", Provider: "openai", Model: "variant", Client: okClient("variant"), Pinned: true},
+		{ID: "all", Provider: "openai", Model: "small", Client: okClient("small")},
+	})
+	route := func(ctx context.Context) string {
+		resp, err := rc.Chat(ctx, &ChatRequest{})
+		if err != nil {
+			t.Fatalf("Chat: %v", err)
+		}
+		if resp.Route == nil {
+			return ""
+		}
+		return resp.Route.ID
+	}
+	if got := route(WithRoute(context.Background(), "experiment:x/b")); got != "experiment:x/b" {
+		t.Errorf("pinned call routed to %q", got)
+	}
+	// A pinned route's empty condition must not catch unpinned calls.
+	if got := route(context.Background()); got != "all" {
+		t.Errorf("unpinned call routed to %q, want all", got)
+	}
+	if got := route(WithRoute(context.Background(), "experiment:x/gone")); got != "all" {
+		t.Errorf("unknown pin routed to %q, want all", got)
+	}
+}
+
+func TestRoutingClient_Failure(t *testing.T) {
+	always := RouteCondition{}
+
+	// Retriable route failure falls through to the default chain.
+	rc := NewRoutingClient(okClient("big"), []Route{
+		{ID: "r", When: always, Provider: "openai", Model: "small", Client: errorClient("small", errors.New("openai error (status 503): overloaded"))},
+	})
+	resp, err := rc.Chat(context.Background(), &ChatRequest{})
+	if err != nil || resp.Message.Content != "ok from big" || resp.Route != nil {
+		t.Errorf("retriable: resp = %+v, err = %v", resp, err)
+	}
+
+	// A request the routed model rejects is not retried elsewhere.
+	rc = NewRoutingClient(okClient("big"), []Route{
+		{ID: "r", When: always, Provider: "openai", Model: "small", Client: errorClient("small", errors.New("openai error (status 400): bad request"))},
+	})
+	if _, err := rc.Chat(context.Background(), &ChatRequest{}); err == nil || !strings.Contains(err.Error(), "model route r") {
+		t.Errorf("non-retriable: err = %v", err)
+	}
+}
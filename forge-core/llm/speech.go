@@ -0,0 +1,42 @@
+package llm
+
+import "context"
+
+// TranscriptionRequest is a provider-agnostic speech-to-text request.
+type TranscriptionRequest struct {
+	Audio    []byte
+	Filename string // with an extension the provider recognizes, e.g. "voice.ogg"
+	MimeType string
+	Language string // optional ISO-639-1 hint
+	Model    string // optional model override
+}
+
+// TranscriptionResponse is the text spoken in the audio.
+type TranscriptionResponse struct {
+	Text  string
+	Model string
+}
+
+// Transcriber turns recorded speech into text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, req *TranscriptionRequest) (*TranscriptionResponse, error)
+}
+
+// SpeechRequest is a provider-agnostic text-to-speech request.
+type SpeechRequest struct {
+	Text   string
+	Voice  string // optional voice override
+	Format string // audio container/codec, e.g. "opus", "mp3"; provider default when empty
+	Model  string // optional model override
+}
+
+// SpeechResponse is synthesized audio.
+type SpeechResponse struct {
+	Audio    []byte
+	MimeType string
+}
+
+// Synthesizer turns text into spoken audio.
+type Synthesizer interface {
+	Synthesize(ctx context.Context, req *SpeechRequest) (*SpeechResponse, error)
+}
@@ -20,6 +20,33 @@ type ChatMessage struct {
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string     `json:"tool_call_id,omitempty"`
 	Name       string     `json:"name,omitempty"`
+	// Thinking holds the extended-thinking blocks an Anthropic model
+	// produced before this assistant message. They are sent back
+	// verbatim with the message's tool calls, which the API requires
+	// while thinking is on; other providers ignore them.
+	Thinking []ThinkingBlock `json:"thinking,omitempty"`
+	// Citations are the web sources a provider grounded this assistant
+	// message in (Gemini's Google Search grounding). The executor turns
+	// them into the response's numbered citations.
+	Citations []Citation `json:"citations,omitempty"`
+}
+
+// Citation is a web source supporting part of a message's content. End
+// is the byte offset in Content where the supported text ends: where a
+// reference to the source belongs.
+type Citation struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url"`
+	End   int    `json:"end"`
+}
+
+// ThinkingBlock is one block of a model's extended thinking. Signature
+// lets the provider verify the block when it is sent back; a redacted
+// block carries only its encrypted Data.
+type ThinkingBlock struct {
+	Text      string `json:"text,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
 }
 
 // ToolCall represents an LLM request to invoke a tool.
@@ -68,15 +95,30 @@ type ChatResponse struct {
 	// Set by the provider client so the llm_call audit event can record the
 	// invoked path even when payload capture is off. Internal only (json:"-").
 	Endpoint string `json:"-"`
+	// Route is set when a RoutingClient rule, rather than the default
+	// model, served the call. Internal only (json:"-").
+	Route *RouteDecision `json:"-"`
+	// Cached is set when a ResponseCache answered the call without
+	// reaching the provider. Internal only (json:"-").
+	Cached bool `json:"-"`
 }
 
 // StreamDelta represents a single chunk in a streaming response.
 type StreamDelta struct {
-	Content      string     `json:"content,omitempty"`
-	ToolCalls    []ToolCall `json:"tool_calls,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// Thinking carries each completed extended-thinking block.
+	Thinking []ThinkingBlock `json:"thinking,omitempty"`
+	// Citations carries grounding citations, with the last chunk; their
+	// End offsets index the whole streamed content.
+	Citations    []Citation `json:"citations,omitempty"`
 	FinishReason string     `json:"finish_reason,omitempty"`
 	Done         bool       `json:"done,omitempty"`
 	Usage        *UsageInfo `json:"usage,omitempty"`
+	// Replace tells the consumer to discard the content streamed so far
+	// and show Content in its place: a guardrail stopped the stream
+	// (see runtime.GuardStream).
+	Replace bool `json:"replace,omitempty"`
 }
 
 // UsageInfo contains token usage information.
@@ -89,4 +131,10 @@ type UsageInfo struct {
 	InputTokens  int `json:"input_tokens"`
 	OutputTokens int `json:"output_tokens"`
 	TotalTokens  int `json:"total_tokens"`
+	// ReasoningTokens is the share of OutputTokens the model spent on
+	// hidden reasoning (OpenAI o-series / gpt-5, xAI Grok mini, DeepSeek
+	// reasoner). Already included in OutputTokens — providers that
+	// report it separately are normalized so OutputTokens is always the
+	// billed completion total. Zero when the provider reports none.
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
 }
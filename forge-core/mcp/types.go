@@ -109,7 +109,9 @@ type MCPToolDescriptor struct {
 	// Annotations are the MCP spec's optional tool behavior hints
 	// (readOnlyHint / destructiveHint / idempotentHint). Advisory metadata
 	// from the server — surfaced so platform-side discovery can seed
-	// side-effect classifications; the runtime does not act on them.
+	// side-effect classifications. The runtime consults readOnlyHint only
+	// to decide whether an undone call is marked "disavowed" in the audit
+	// trail; it never gates execution on these hints.
 	Annotations *MCPToolAnnotations `json:"annotations,omitempty"`
 }
 
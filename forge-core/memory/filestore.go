@@ -42,8 +42,7 @@ func (fs *FileStore) ReadFile(relPath string) (string, error) {
 
 // AppendDaily appends an entry to today's daily log (YYYY-MM-DD.md).
 func (fs *FileStore) AppendDaily(entry string) error {
-	filename := time.Now().UTC().Format("2006-01-02") + ".md"
-	path := filepath.Join(fs.dir, filename)
+	path := filepath.Join(fs.dir, dailyLogName(time.Now()))
 
 	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 	if err != nil {
@@ -57,6 +56,11 @@ func (fs *FileStore) AppendDaily(entry string) error {
 	return err
 }
 
+// dailyLogName is the daily log file for t's UTC date.
+func dailyLogName(t time.Time) string {
+	return t.UTC().Format("2006-01-02") + ".md"
+}
+
 // ListFiles returns all .md files in the memory directory (relative paths).
 func (fs *FileStore) ListFiles() ([]string, error) {
 	entries, err := os.ReadDir(fs.dir)
@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The knowledge base is a read-only partition of memory: documents
+// synced from local files, indexed under "kb:<path>" sources. The agent
+// searches it with memory_search but cannot write to it, and GC never
+// archives it. The extracted text of each document is kept under kb/ so
+// memory_get can open a cited document.
+const (
+	// KBSourcePrefix marks the sources of knowledge base chunks.
+	KBSourcePrefix = "kb:"
+
+	kbDir      = "kb"
+	kbManifest = "manifest.json"
+	kbTextDir  = "text"
+)
+
+// KBDocument is a document's extracted text, ready to index.
+type KBDocument struct {
+	// Path identifies the document in citations, e.g. "docs/setup.md".
+	Path  string
+	Title string
+	Text  string
+}
+
+// KBEntry is a synced document in the knowledge base manifest.
+type KBEntry struct {
+	Title    string    `json:"title,omitempty"`
+	Hash     string    `json:"hash"`
+	Chunks   int       `json:"chunks"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// KBSyncResult reports what SyncKB changed.
+type KBSyncResult struct {
+	Added     []string `json:"added,omitempty"`
+	Updated   []string `json:"updated,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Unchanged int      `json:"unchanged"`
+	Chunks    int      `json:"chunks"` // chunks indexed by this sync
+}
+
+// SyncKB indexes docs into the knowledge base. Documents whose text is
+// unchanged since the last sync are skipped, so re-syncing is cheap.
+// Documents previously synced under one of roots that are not in docs
+// are removed; documents under other roots are left alone.
+func (m *Manager) SyncKB(ctx context.Context, roots []string, docs []KBDocument) (*KBSyncResult, error) {
+	m.kbMu.Lock()
+	defer m.kbMu.Unlock()
+
+	manifest, err := m.loadKBManifest()
+	if err != nil {
+		return nil, err
+	}
+	textDir := filepath.Join(m.fileStore.Dir(), kbDir, kbTextDir)
+	if err := os.MkdirAll(textDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating knowledge base dir: %w", err)
+	}
+
+	res := &KBSyncResult{}
+	seen := make(map[string]bool, len(docs))
+	for _, d := range docs {
+		seen[d.Path] = true
+		sum := sha256.Sum256([]byte(d.Title + "\x00" + d.Text))
+		hash := hex.EncodeToString(sum[:])
+		prev, ok := manifest[d.Path]
+		if ok && prev.Hash == hash {
+			res.Unchanged++
+			continue
+		}
+		if err := writeFileAtomic(filepath.Join(textDir, kbTextName(d.Path)), []byte(d.Text)); err != nil {
+			return nil, fmt.Errorf("writing text of %s: %w", d.Path, err)
+		}
+		n, err := m.indexContent(ctx, KBSourcePrefix+d.Path, d.Text)
+		if err != nil {
+			return nil, err
+		}
+		manifest[d.Path] = KBEntry{Title: d.Title, Hash: hash, Chunks: n, SyncedAt: time.Now().UTC()}
+		res.Chunks += n
+		if ok {
+			res.Updated = append(res.Updated, d.Path)
+		} else {
+			res.Added = append(res.Added, d.Path)
+		}
+	}
+
+	for path := range manifest {
+		if seen[path] || !slices.ContainsFunc(roots, func(root string) bool { return underKBRoot(path, root) }) {
+			continue
+		}
+		if err := m.vecStore.DeleteBySource(ctx, KBSourcePrefix+path); err != nil {
+			return nil, fmt.Errorf("deleting chunks for %s: %w", path, err)
+		}
+		_ = os.Remove(filepath.Join(textDir, kbTextName(path)))
+		delete(manifest, path)
+		res.Removed = append(res.Removed, path)
+	}
+	slices.Sort(res.Removed)
+
+	if err := m.saveKBManifest(manifest); err != nil {
+		return nil, err
+	}
+	m.logger.Info("knowledge base synced", map[string]any{
+		"added": len(res.Added), "updated": len(res.Updated), "removed": len(res.Removed),
+		"unchanged": res.Unchanged, "chunks": res.Chunks,
+	})
+	return res, nil
+}
+
+// KBDocuments returns the knowledge base manifest, keyed by document path.
+func (m *Manager) KBDocuments() (map[string]KBEntry, error) {
+	m.kbMu.Lock()
+	defer m.kbMu.Unlock()
+	return m.loadKBManifest()
+}
+
+// Citation renders where a knowledge base chunk came from, e.g.
+// "Setup Guide (docs/setup.md, lines 12-30)". It returns "" for chunks
+// from the agent's own memory files.
+func (m *Manager) Citation(c Chunk) string {
+	path, ok := strings.CutPrefix(c.Source, KBSourcePrefix)
+	if !ok {
+		return ""
+	}
+	cite := fmt.Sprintf("%s, lines %d-%d", path, c.LineStart+1, c.LineEnd+1)
+	title := m.KBTitle(c.Source)
+	if title == "" {
+		return cite
+	}
+	return title + " (" + cite + ")"
+}
+
+// KBTitle returns the title of the knowledge base document a chunk source
+// ("kb:docs/setup.md") refers to, or "" when it is not a synced document.
+func (m *Manager) KBTitle(source string) string {
+	path, ok := strings.CutPrefix(source, KBSourcePrefix)
+	if !ok {
+		return ""
+	}
+	m.kbMu.Lock()
+	defer m.kbMu.Unlock()
+	return m.kbTitles[path]
+}
+
+// readKBText returns a synced document's extracted text.
+func (m *Manager) readKBText(path string) (string, error) {
+	m.kbMu.Lock()
+	manifest, err := m.loadKBManifest()
+	m.kbMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := manifest[path]; !ok {
+		return "", fmt.Errorf("%s%s is not in the knowledge base", KBSourcePrefix, path)
+	}
+	data, err := os.ReadFile(filepath.Join(m.fileStore.Dir(), kbDir, kbTextDir, kbTextName(path)))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// loadKBManifest reads the manifest and refreshes the title cache
+// Citation uses. The caller holds kbMu.
+func (m *Manager) loadKBManifest() (map[string]KBEntry, error) {
+	manifest := map[string]KBEntry{}
+	data, err := os.ReadFile(filepath.Join(m.fileStore.Dir(), kbDir, kbManifest))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading knowledge base manifest: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("parsing knowledge base manifest: %w", err)
+		}
+	}
+	m.cacheKBTitles(manifest)
+	return manifest, nil
+}
+
+// saveKBManifest writes the manifest. The caller holds kbMu.
+func (m *Manager) saveKBManifest(manifest map[string]KBEntry) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(m.fileStore.Dir(), kbDir, kbManifest), data); err != nil {
+		return fmt.Errorf("writing knowledge base manifest: %w", err)
+	}
+	m.cacheKBTitles(manifest)
+	return nil
+}
+
+func (m *Manager) cacheKBTitles(manifest map[string]KBEntry) {
+	m.kbTitles = make(map[string]string, len(manifest))
+	for path, e := range manifest {
+		m.kbTitles[path] = e.Title
+	}
+}
+
+// kbTextName names the file holding a document's text. Paths may be
+// absolute or nested, so the name is a hash rather than the path.
+func kbTextName(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:8]) + ".txt"
+}
+
+// underKBRoot reports whether a document path lies under a sync root.
+func underKBRoot(path, root string) bool {
+	return root == "." || path == root || strings.HasPrefix(path, strings.TrimSuffix(root, "/")+"/")
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSyncKB(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{})
+	ctx := context.Background()
+
+	docs := []KBDocument{
+		{Path: "docs/setup.md", Title: "Setup Guide", Text: "# Setup Guide\n\nInstall the agent with brew install forge.\n\nThen run forge init."},
+		{Path: "docs/faq.md", Title: "FAQ", Text: "Refunds are processed within five business days."},
+		{Path: "other/notes.md", Title: "Notes", Text: "Unrelated root."},
+	}
+	res, err := mgr.SyncKB(ctx, []string{"docs", "other"}, docs)
+	if err != nil {
+		t.Fatalf("SyncKB: %v", err)
+	}
+	if len(res.Added) != 3 || res.Chunks == 0 {
+		t.Fatalf("first sync = %+v", res)
+	}
+
+	results, err := mgr.Search(ctx, "refunds processed business days")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 || results[0].Chunk.Source != "kb:docs/faq.md" {
+		t.Fatalf("search results = %+v", results)
+	}
+	if got := mgr.Citation(results[0].Chunk); got != "FAQ (docs/faq.md, lines 1-1)" {
+		t.Errorf("citation = %q", got)
+	}
+	if text, err := mgr.GetFile("kb:docs/setup.md"); err != nil || !strings.Contains(text, "brew install") {
+		t.Errorf("GetFile(kb:docs/setup.md) = %q, %v", text, err)
+	}
+
+	// Re-sync docs/ only: setup.md changed, faq.md was deleted, and the
+	// other root is left alone.
+	docs[0].Text += "\n\nUpgrade with brew upgrade forge."
+	res, err = mgr.SyncKB(ctx, []string{"docs"}, docs[:1])
+	if err != nil {
+		t.Fatalf("re-sync: %v", err)
+	}
+	if len(res.Updated) != 1 || len(res.Removed) != 1 || res.Removed[0] != "docs/faq.md" {
+		t.Errorf("re-sync = %+v", res)
+	}
+	manifest, _ := mgr.KBDocuments()
+	if _, ok := manifest["other/notes.md"]; !ok || len(manifest) != 2 {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	results, _ = mgr.Search(ctx, "refunds processed business days")
+	for _, r := range results {
+		if r.Chunk.Source == "kb:docs/faq.md" {
+			t.Error("removed document is still indexed")
+		}
+	}
+
+	// Unchanged documents are not re-indexed.
+	res, err = mgr.SyncKB(ctx, []string{"docs"}, docs[:1])
+	if err != nil || res.Unchanged != 1 || res.Chunks != 0 {
+		t.Errorf("unchanged sync = %+v, %v", res, err)
+	}
+
+	if _, err := mgr.GetFile("kb:docs/faq.md"); err == nil {
+		t.Error("GetFile of a removed document should fail")
+	}
+	if got := mgr.Citation(Chunk{Source: "MEMORY.md"}); got != "" {
+		t.Errorf("citation for a memory file = %q", got)
+	}
+}
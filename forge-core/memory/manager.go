@@ -2,8 +2,13 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/initializ/forge/forge-core/llm"
 )
@@ -20,8 +25,17 @@ type Logger interface {
 type ManagerConfig struct {
 	MemoryDir    string       // root directory for memory files
 	Embedder     llm.Embedder // nil = keyword-only mode
+	Reranker     llm.Reranker // nil = no cross-encoder pass over search candidates
+	Rewriter     llm.Client   // writes SearchConfig.QueryRewrite passages; nil = no rewriting
 	Logger       Logger
 	SearchConfig SearchConfig
+	// WriteQuota caps the live notes in each namespace memory_write
+	// writes to (0 = DefaultWriteQuota); WriteQuotas overrides it per
+	// namespace.
+	WriteQuota  int
+	WriteQuotas map[string]int
+	// Retention decides which memories GC archives.
+	Retention RetentionPolicy
 }
 
 // Manager orchestrates long-term memory: file storage, indexing, and search.
@@ -31,6 +45,19 @@ type Manager struct {
 	searcher  *HybridSearcher
 	embedder  llm.Embedder
 	logger    Logger
+
+	writeQuota  int
+	writeQuotas map[string]int
+	retention   RetentionPolicy
+	notesMu     sync.Mutex // serializes note writes; guards nextExpiry
+	nextExpiry  time.Time  // earliest note expiry; zero when none
+
+	kbMu     sync.Mutex        // serializes knowledge base syncs; guards kbTitles
+	kbTitles map[string]string // knowledge base document titles, by path
+
+	cfg       ManagerConfig       // the config tenant managers are built from
+	tenantsMu sync.Mutex          // guards tenants
+	tenants   map[string]*Manager // tenant memories opened so far, by tenant
 }
 
 // NewManager creates a new memory Manager.
@@ -66,23 +93,39 @@ func NewManager(cfg ManagerConfig) (*Manager, error) {
 	}
 
 	searcher := NewHybridSearcher(vecStore, cfg.Embedder, searchCfg)
-
-	return &Manager{
-		fileStore: fileStore,
-		vecStore:  vecStore,
-		searcher:  searcher,
-		embedder:  cfg.Embedder,
-		logger:    logger,
-	}, nil
+	searcher.reranker = cfg.Reranker
+	searcher.rewriter = cfg.Rewriter
+
+	m := &Manager{
+		fileStore:   fileStore,
+		vecStore:    vecStore,
+		searcher:    searcher,
+		embedder:    cfg.Embedder,
+		logger:      logger,
+		writeQuota:  cfg.WriteQuota,
+		writeQuotas: cfg.WriteQuotas,
+		retention:   cfg.Retention,
+		cfg:         cfg,
+	}
+	if _, err := m.loadKBManifest(); err != nil {
+		logger.Warn("failed to load knowledge base manifest", map[string]any{"error": err.Error()})
+	}
+	return m, nil
 }
 
-// Search queries long-term memory with hybrid search.
+// Search queries long-term memory with hybrid search. Notes past their
+// expiry are dropped first.
 func (m *Manager) Search(ctx context.Context, query string) ([]SearchResult, error) {
+	m.expireNotesIfDue(ctx)
 	return m.searcher.Search(ctx, query)
 }
 
-// GetFile retrieves a memory file by relative path.
+// GetFile retrieves a memory file by relative path, or the text of a
+// knowledge base document by its "kb:" source.
 func (m *Manager) GetFile(path string) (string, error) {
+	if doc, ok := strings.CutPrefix(path, KBSourcePrefix); ok {
+		return m.readKBText(doc)
+	}
 	return m.fileStore.ReadFile(path)
 }
 
@@ -101,8 +144,12 @@ func (m *Manager) AppendDailyLog(ctx context.Context, observation string) error
 	return nil
 }
 
-// IndexAll indexes all memory files (MEMORY.md + daily logs).
+// IndexAll indexes all memory files (MEMORY.md, daily logs and notes),
+// after dropping expired notes.
 func (m *Manager) IndexAll(ctx context.Context) error {
+	if err := m.ExpireNotes(ctx); err != nil {
+		m.logger.Warn("failed to expire memory notes", map[string]any{"error": err.Error()})
+	}
 	files, err := m.fileStore.ListFiles()
 	if err != nil {
 		return fmt.Errorf("listing memory files: %w", err)
@@ -127,16 +174,22 @@ func (m *Manager) IndexFile(ctx context.Context, path string) error {
 	if err != nil {
 		return fmt.Errorf("reading %s: %w", path, err)
 	}
+	_, err = m.indexContent(ctx, path, content)
+	return err
+}
 
+// indexContent replaces source's chunks in the index with content's and
+// returns how many it indexed.
+func (m *Manager) indexContent(ctx context.Context, path, content string) (int, error) {
 	// Remove old chunks for this file.
 	if err := m.vecStore.DeleteBySource(ctx, path); err != nil {
-		return fmt.Errorf("deleting old chunks for %s: %w", path, err)
+		return 0, fmt.Errorf("deleting old chunks for %s: %w", path, err)
 	}
 
 	// Chunk the content.
 	chunks := ChunkText(content, path, 0, 0)
 	if len(chunks) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	// Generate embeddings if embedder available.
@@ -172,7 +225,7 @@ func (m *Manager) IndexFile(ctx context.Context, path string) error {
 	}
 
 	if err := m.vecStore.Index(ctx, indexed); err != nil {
-		return fmt.Errorf("indexing chunks for %s: %w", path, err)
+		return 0, fmt.Errorf("indexing chunks for %s: %w", path, err)
 	}
 
 	m.logger.Debug("indexed file", map[string]any{
@@ -180,28 +233,52 @@ func (m *Manager) IndexFile(ctx context.Context, path string) error {
 		"chunks": len(indexed),
 	})
 
-	return nil
+	return len(indexed), nil
 }
 
 // indexDailyLog re-indexes today's daily log file.
 func (m *Manager) indexDailyLog(ctx context.Context) error {
-	files, err := m.fileStore.ListFiles()
+	return m.IndexFile(ctx, dailyLogName(time.Now()))
+}
+
+// ForTenant returns the memory of tenant: a Manager of its own under
+// MemoryDir/tenants/<tenant>, opened on first use, so one tenant's
+// searches never see another's notes or logs. The knowledge base stays
+// with the agent's own memory.
+func (m *Manager) ForTenant(tenant string) (*Manager, error) {
+	m.tenantsMu.Lock()
+	defer m.tenantsMu.Unlock()
+	if t, ok := m.tenants[tenant]; ok {
+		return t, nil
+	}
+	cfg := m.cfg
+	cfg.MemoryDir = filepath.Join(m.cfg.MemoryDir, "tenants", tenant)
+	t, err := NewManager(cfg)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("opening memory of tenant %q: %w", tenant, err)
 	}
-
-	// Find the most recent daily log (last .md that isn't MEMORY.md).
-	for i := len(files) - 1; i >= 0; i-- {
-		if files[i] != "MEMORY.md" {
-			return m.IndexFile(ctx, files[i])
-		}
+	if m.tenants == nil {
+		m.tenants = map[string]*Manager{}
 	}
-	return nil
+	m.tenants[tenant] = t
+	return t, nil
 }
 
-// Close flushes the vector store to disk.
+// Tenants returns the tenant memories opened so far, by tenant.
+func (m *Manager) Tenants() map[string]*Manager {
+	m.tenantsMu.Lock()
+	defer m.tenantsMu.Unlock()
+	return maps.Clone(m.tenants)
+}
+
+// Close flushes the vector store, and those of the tenant memories, to
+// disk.
 func (m *Manager) Close() error {
-	return m.vecStore.Close()
+	var errs []error
+	for _, t := range m.Tenants() {
+		errs = append(errs, t.Close())
+	}
+	return errors.Join(append(errs, m.vecStore.Close())...)
 }
 
 // nopLogger is a no-op Logger.
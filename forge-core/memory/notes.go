@@ -0,0 +1,352 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Notes are facts the agent saves deliberately with memory_write, as
+// opposed to the observations compaction flushes into daily logs. Each
+// namespace is kept as JSON under notes/ (the source of truth) and
+// rendered to notes-<namespace>.md, which the index picks up like any
+// other memory file. Notes are evergreen until they expire.
+const (
+	// DefaultNamespace is the namespace of a note written without one.
+	DefaultNamespace = "default"
+	// DefaultWriteQuota is how many live notes a namespace may hold.
+	DefaultWriteQuota = 100
+	// MaxNoteChars bounds a note's content.
+	MaxNoteChars = 2_000
+
+	notesFilePrefix = "notes-"
+	notesDir        = "notes"
+)
+
+// Note write outcomes reported in WriteResult.Status.
+const (
+	NoteSaved     = "saved"
+	NoteUpdated   = "updated"   // same content already saved; provenance refreshed
+	NoteDuplicate = "duplicate" // already in another memory file; nothing saved
+)
+
+// ErrQuotaExceeded is returned by Write when the namespace is full.
+var ErrQuotaExceeded = errors.New("memory namespace write quota exceeded")
+
+var namespacePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Note is one fact saved with memory_write, with its provenance.
+type Note struct {
+	ID         string     `json:"id"`
+	Content    string     `json:"content"`
+	Tags       []string   `json:"tags,omitempty"`
+	SourceTask string     `json:"source_task,omitempty"`
+	Confidence float64    `json:"confidence,omitempty"`
+	Importance float64    `json:"importance,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// WriteRequest is a note to save.
+type WriteRequest struct {
+	Namespace  string
+	Content    string
+	Tags       []string
+	SourceTask string
+	Confidence float64    // 0 = unspecified; otherwise in (0, 1]
+	Importance float64    // 0 = DefaultImportance; otherwise in (0, 1]
+	ExpiresAt  *time.Time // nil = never
+}
+
+// WriteResult reports what Write did.
+type WriteResult struct {
+	ID        string `json:"id,omitempty"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status"`
+	// Source is the memory file holding the note, or the existing
+	// memory it duplicates.
+	Source string `json:"source"`
+}
+
+// Write saves a note to its namespace and re-indexes the namespace. A
+// note whose content is already saved in the namespace refreshes that
+// note's tags, provenance and expiry instead; one already found in
+// another memory file (MEMORY.md, a daily log, another namespace) is
+// not saved again. Expired notes are dropped first, and a namespace
+// holding its quota of live notes refuses new ones.
+func (m *Manager) Write(ctx context.Context, req WriteRequest) (*WriteResult, error) {
+	ns := req.Namespace
+	if ns == "" {
+		ns = DefaultNamespace
+	}
+	if !namespacePattern.MatchString(ns) {
+		return nil, fmt.Errorf("invalid namespace %q: use lowercase letters, digits, '-' and '_'", ns)
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+	if len(content) > MaxNoteChars {
+		return nil, fmt.Errorf("content is %d chars; a note holds at most %d", len(content), MaxNoteChars)
+	}
+	if req.Confidence < 0 || req.Confidence > 1 {
+		return nil, fmt.Errorf("confidence must be between 0 and 1")
+	}
+	if req.Importance < 0 || req.Importance > 1 {
+		return nil, fmt.Errorf("importance must be between 0 and 1")
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("expires_at is in the past")
+	}
+
+	m.notesMu.Lock()
+	defer m.notesMu.Unlock()
+
+	notes, err := m.loadNotes(ns)
+	if err != nil {
+		return nil, err
+	}
+	notes = liveNotes(notes, now)
+	key := normalizeNote(content)
+	res := &WriteResult{Namespace: ns, Source: notesFile(ns)}
+
+	if i := slices.IndexFunc(notes, func(n Note) bool { return normalizeNote(n.Content) == key }); i >= 0 {
+		n := &notes[i]
+		for _, t := range req.Tags {
+			if !slices.Contains(n.Tags, t) {
+				n.Tags = append(n.Tags, t)
+			}
+		}
+		if req.SourceTask != "" {
+			n.SourceTask = req.SourceTask
+		}
+		n.Confidence = max(n.Confidence, req.Confidence)
+		n.Importance = max(n.Importance, req.Importance)
+		n.ExpiresAt = laterExpiry(n.ExpiresAt, req.ExpiresAt)
+		n.UpdatedAt = now
+		res.ID, res.Status = n.ID, NoteUpdated
+	} else {
+		if src := m.findExisting(ctx, content, key, ns); src != "" {
+			res.Status, res.Source = NoteDuplicate, src
+			return res, nil
+		}
+		if quota := m.quotaFor(ns); len(notes) >= quota {
+			return nil, fmt.Errorf("%w: namespace %q holds %d notes (quota %d)", ErrQuotaExceeded, ns, len(notes), quota)
+		}
+		sum := sha256.Sum256([]byte(ns + "\x00" + key))
+		notes = append(notes, Note{
+			ID:         "mem-" + hex.EncodeToString(sum[:6]),
+			Content:    content,
+			Tags:       req.Tags,
+			SourceTask: req.SourceTask,
+			Confidence: req.Confidence,
+			Importance: req.Importance,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			ExpiresAt:  req.ExpiresAt,
+		})
+		res.ID, res.Status = notes[len(notes)-1].ID, NoteSaved
+	}
+
+	if err := m.saveNotes(ctx, ns, notes); err != nil {
+		return nil, err
+	}
+	m.logger.Info("memory note written", map[string]any{
+		"namespace": ns, "id": res.ID, "status": res.Status, "source_task": req.SourceTask,
+	})
+	return res, nil
+}
+
+// ExpireNotes drops expired notes from every namespace and re-indexes
+// the namespaces that changed. Search calls it once the earliest expiry
+// has passed.
+func (m *Manager) ExpireNotes(ctx context.Context) error {
+	m.notesMu.Lock()
+	defer m.notesMu.Unlock()
+
+	namespaces, err := m.noteNamespaces()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	m.nextExpiry = time.Time{}
+	for _, ns := range namespaces {
+		notes, err := m.loadNotes(ns)
+		if err != nil {
+			return err
+		}
+		live := liveNotes(notes, now)
+		if len(live) != len(notes) {
+			if err := m.saveNotes(ctx, ns, live); err != nil {
+				return err
+			}
+			m.logger.Info("expired memory notes removed", map[string]any{"namespace": ns, "removed": len(notes) - len(live)})
+			continue
+		}
+		m.trackExpiry(live)
+	}
+	return nil
+}
+
+// expireNotesIfDue runs ExpireNotes when a note's expiry has passed.
+func (m *Manager) expireNotesIfDue(ctx context.Context) {
+	m.notesMu.Lock()
+	due := !m.nextExpiry.IsZero() && time.Now().After(m.nextExpiry)
+	m.notesMu.Unlock()
+	if !due {
+		return
+	}
+	if err := m.ExpireNotes(ctx); err != nil {
+		m.logger.Warn("failed to expire memory notes", map[string]any{"error": err.Error()})
+	}
+}
+
+// findExisting returns the memory file that already holds content, or
+// "" — a search hit outside ns's own notes file whose text contains it.
+func (m *Manager) findExisting(ctx context.Context, content, key, ns string) string {
+	results, err := m.searcher.search(ctx, content, false)
+	if err != nil {
+		return ""
+	}
+	for _, r := range results {
+		if r.Chunk.Source != notesFile(ns) && strings.Contains(normalizeNote(r.Chunk.Content), key) {
+			return r.Chunk.Source
+		}
+	}
+	return ""
+}
+
+func (m *Manager) quotaFor(ns string) int {
+	if q, ok := m.writeQuotas[ns]; ok && q > 0 {
+		return q
+	}
+	if m.writeQuota > 0 {
+		return m.writeQuota
+	}
+	return DefaultWriteQuota
+}
+
+func (m *Manager) loadNotes(ns string) ([]Note, error) {
+	data, err := os.ReadFile(filepath.Join(m.fileStore.Dir(), notesDir, ns+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading notes for %s: %w", ns, err)
+	}
+	var notes []Note
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("parsing notes for %s: %w", ns, err)
+	}
+	return notes, nil
+}
+
+// saveNotes writes ns's notes and their markdown rendering, then
+// re-indexes the rendering. The caller holds notesMu.
+func (m *Manager) saveNotes(ctx context.Context, ns string, notes []Note) error {
+	dir := filepath.Join(m.fileStore.Dir(), notesDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating notes dir: %w", err)
+	}
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, ns+".json"), data); err != nil {
+		return fmt.Errorf("writing notes for %s: %w", ns, err)
+	}
+	if err := writeFileAtomic(filepath.Join(m.fileStore.Dir(), notesFile(ns)), []byte(renderNotes(ns, notes))); err != nil {
+		return fmt.Errorf("writing %s: %w", notesFile(ns), err)
+	}
+	m.trackExpiry(notes)
+	if err := m.IndexFile(ctx, notesFile(ns)); err != nil {
+		m.logger.Warn("failed to re-index memory notes", map[string]any{"namespace": ns, "error": err.Error()})
+	}
+	return nil
+}
+
+// trackExpiry lowers nextExpiry to the earliest expiry among notes.
+func (m *Manager) trackExpiry(notes []Note) {
+	for _, n := range notes {
+		if n.ExpiresAt != nil && (m.nextExpiry.IsZero() || n.ExpiresAt.Before(m.nextExpiry)) {
+			m.nextExpiry = *n.ExpiresAt
+		}
+	}
+}
+
+// renderNotes renders a namespace's notes as markdown, one section per
+// note with its provenance, so search results carry it.
+func renderNotes(ns string, notes []Note) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Memory notes: %s\n\nWritten by the agent with memory_write. Edit notes/%s.json, not this file.\n", ns, ns)
+	for _, n := range notes {
+		fmt.Fprintf(&sb, "\n## %s\n%s\n\n", n.ID, n.Content)
+		meta := []string{"saved " + n.UpdatedAt.Format("2006-01-02")}
+		if len(n.Tags) > 0 {
+			meta = append(meta, "tags: "+strings.Join(n.Tags, ", "))
+		}
+		if n.SourceTask != "" {
+			meta = append(meta, "source task: "+n.SourceTask)
+		}
+		if n.Confidence > 0 {
+			meta = append(meta, fmt.Sprintf("confidence: %.2f", n.Confidence))
+		}
+		if n.Importance > 0 {
+			meta = append(meta, fmt.Sprintf("importance: %.2f", n.Importance))
+		}
+		if n.ExpiresAt != nil {
+			meta = append(meta, "expires: "+n.ExpiresAt.Format(time.RFC3339))
+		}
+		sb.WriteString("_" + strings.Join(meta, " · ") + "_\n")
+	}
+	return sb.String()
+}
+
+// liveNotes returns the notes that have not expired by now.
+func liveNotes(notes []Note, now time.Time) []Note {
+	return slices.DeleteFunc(notes, func(n Note) bool { return n.ExpiresAt != nil && !n.ExpiresAt.After(now) })
+}
+
+// laterExpiry returns the later of two expiries, where nil means never.
+func laterExpiry(a, b *time.Time) *time.Time {
+	if a == nil || b == nil {
+		return nil
+	}
+	if b.After(*a) {
+		return b
+	}
+	return a
+}
+
+// normalizeNote folds case and whitespace so trivially different
+// restatements of a note compare equal.
+func normalizeNote(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func notesFile(ns string) string { return notesFilePrefix + ns + ".md" }
+
+// isNotesFile reports whether source is a rendered notes file.
+func isNotesFile(source string) bool {
+	return strings.HasPrefix(source, notesFilePrefix) && strings.HasSuffix(source, ".md")
+}
+
+// writeFileAtomic writes data to path through a temp file and rename.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
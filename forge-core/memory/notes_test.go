@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newNotesManager(t *testing.T, cfg ManagerConfig) *Manager {
+	t.Helper()
+	cfg.MemoryDir = filepath.Join(t.TempDir(), "memory")
+	mgr, err := NewManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mgr.Close() }) //nolint:errcheck
+	return mgr
+}
+
+func TestWrite_SaveAndUpdate(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{})
+	ctx := context.Background()
+
+	res, err := mgr.Write(ctx, WriteRequest{
+		Content:    "The staging cluster runs in eu-west-1.",
+		Tags:       []string{"infra"},
+		SourceTask: "task-1",
+		Confidence: 0.8,
+	})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if res.Status != NoteSaved || res.Namespace != DefaultNamespace || !strings.HasPrefix(res.ID, "mem-") || res.Source != "notes-default.md" {
+		t.Fatalf("result = %+v", res)
+	}
+
+	// A restatement differing only in case and spacing updates the note.
+	again, err := mgr.Write(ctx, WriteRequest{
+		Content:    "the staging  cluster runs in EU-WEST-1.",
+		Tags:       []string{"aws"},
+		SourceTask: "task-2",
+	})
+	if err != nil {
+		t.Fatalf("Write again: %v", err)
+	}
+	if again.Status != NoteUpdated || again.ID != res.ID {
+		t.Errorf("second write = %+v, want update of %s", again, res.ID)
+	}
+
+	notes, err := mgr.loadNotes(DefaultNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || notes[0].SourceTask != "task-2" || notes[0].Confidence != 0.8 || len(notes[0].Tags) != 2 {
+		t.Errorf("notes = %+v", notes)
+	}
+
+	results, err := mgr.Search(ctx, "staging cluster region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 || results[0].Chunk.Source != "notes-default.md" || !strings.Contains(results[0].Chunk.Content, "source task: task-2") {
+		t.Errorf("search results = %+v", results)
+	}
+}
+
+func TestWrite_DuplicateOfExistingMemory(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{})
+	ctx := context.Background()
+
+	memPath := filepath.Join(mgr.fileStore.Dir(), "MEMORY.md")
+	if err := os.WriteFile(memPath, []byte("# Agent Memory\n\nDeploys go out on Tuesdays only.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.IndexAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := mgr.Write(ctx, WriteRequest{Namespace: "ops", Content: "deploys go out on tuesdays only."})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if res.Status != NoteDuplicate || res.Source != "MEMORY.md" || res.ID != "" {
+		t.Errorf("result = %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(mgr.fileStore.Dir(), "notes-ops.md")); !os.IsNotExist(err) {
+		t.Error("a duplicate should not create the namespace")
+	}
+}
+
+func TestWrite_Quota(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{WriteQuota: 5, WriteQuotas: map[string]int{"prefs": 1}})
+	ctx := context.Background()
+
+	if _, err := mgr.Write(ctx, WriteRequest{Namespace: "prefs", Content: "Prefers metric units."}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := mgr.Write(ctx, WriteRequest{Namespace: "prefs", Content: "Likes terse answers."})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("err = %v, want ErrQuotaExceeded", err)
+	}
+	// Other namespaces fall back to write_quota.
+	if _, err := mgr.Write(ctx, WriteRequest{Namespace: "infra", Content: "Builds run on arm64 runners."}); err != nil {
+		t.Errorf("infra write: %v", err)
+	}
+	if got := mgr.quotaFor("infra"); got != 5 {
+		t.Errorf("quotaFor(infra) = %d, want 5", got)
+	}
+}
+
+func TestWrite_Expiry(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{})
+	ctx := context.Background()
+
+	if _, err := mgr.Write(ctx, WriteRequest{Content: "The freeze window ends Friday."}); err != nil {
+		t.Fatal(err)
+	}
+	// Age the note past its expiry on disk.
+	past := time.Now().Add(-time.Hour).UTC()
+	notes, _ := mgr.loadNotes(DefaultNamespace)
+	notes[0].ExpiresAt = &past
+	data, _ := json.Marshal(notes)
+	if err := os.WriteFile(filepath.Join(mgr.fileStore.Dir(), notesDir, "default.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mgr.notesMu.Lock()
+	mgr.nextExpiry = past
+	mgr.notesMu.Unlock()
+
+	results, err := mgr.Search(ctx, "freeze window")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Chunk.Source == "notes-default.md" {
+			t.Errorf("expired note still searchable: %+v", r)
+		}
+	}
+	if notes, _ := mgr.loadNotes(DefaultNamespace); len(notes) != 0 {
+		t.Errorf("notes after expiry = %+v", notes)
+	}
+
+	stale := time.Now().Add(-time.Minute)
+	if _, err := mgr.Write(ctx, WriteRequest{Content: "x", ExpiresAt: &stale}); err == nil {
+		t.Error("expected error for an expiry in the past")
+	}
+}
+
+func TestWrite_Validation(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{})
+	ctx := context.Background()
+
+	for _, req := range []WriteRequest{
+		{Content: "   "},
+		{Content: strings.Repeat("a", MaxNoteChars+1)},
+		{Namespace: "../etc", Content: "x"},
+		{Namespace: "Upper", Content: "x"},
+		{Content: "x", Confidence: 1.5},
+	} {
+		if _, err := mgr.Write(ctx, req); err == nil {
+			t.Errorf("Write(%+v) should fail", req)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package memory
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Retention defaults.
+const (
+	// DefaultImportance is the importance of a daily log, or of a note
+	// written without one.
+	DefaultImportance = 0.5
+	// DefaultRetentionHalfLife is how long a memory of DefaultImportance
+	// takes to lose half its retention score.
+	DefaultRetentionHalfLife = 30 * 24 * time.Hour
+	// DefaultArchiveBelow is the retention score under which a memory is
+	// cold and GC archives it.
+	DefaultArchiveBelow = 0.1
+
+	archiveDir  = "archive"
+	archiveFile = "archive.jsonl.gz"
+)
+
+// RetentionPolicy decides which memories GC archives. MEMORY.md is
+// curated by hand and is never archived.
+type RetentionPolicy struct {
+	// HalfLife scales the decay of a memory's retention score; an
+	// importance of 1 doubles it, 0.25 halves it (0 = DefaultRetentionHalfLife).
+	HalfLife time.Duration
+	// ArchiveBelow is the score under which a memory is archived
+	// (0 = DefaultArchiveBelow).
+	ArchiveBelow float64
+	// MaxNotes caps the notes kept per namespace; GC archives the
+	// coldest beyond it (0 = no cap).
+	MaxNotes int
+}
+
+// retentionScore is 1 for a fresh memory and halves every
+// halfLife × 2 × importance of age.
+func (p RetentionPolicy) retentionScore(age time.Duration, importance float64) float64 {
+	halfLife := p.HalfLife
+	if halfLife <= 0 {
+		halfLife = DefaultRetentionHalfLife
+	}
+	if importance <= 0 {
+		importance = DefaultImportance
+	}
+	if age <= 0 {
+		return 1
+	}
+	return math.Exp2(-age.Seconds() / (halfLife.Seconds() * 2 * importance))
+}
+
+func (p RetentionPolicy) archiveBelow() float64 {
+	if p.ArchiveBelow > 0 {
+		return p.ArchiveBelow
+	}
+	return DefaultArchiveBelow
+}
+
+// ArchivedMemory is one record of the archive: a note or a whole daily
+// log removed from the index by GC.
+type ArchivedMemory struct {
+	Kind       string    `json:"kind"` // "note" or "daily_log"
+	Source     string    `json:"source"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Note       *Note     `json:"note,omitempty"`
+	Content    string    `json:"content,omitempty"` // daily logs
+	Score      float64   `json:"score"`
+	Reason     string    `json:"reason"` // "cold" or "max_notes"
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// GCResult reports what a GC pass archived, or would archive on a dry run.
+type GCResult struct {
+	DryRun        bool             `json:"dry_run,omitempty"`
+	ArchivedNotes int              `json:"archived_notes"`
+	ArchivedLogs  int              `json:"archived_logs"`
+	Archived      []ArchivedMemory `json:"archived,omitempty"`
+	// Archive is the archive file, relative to the memory directory.
+	Archive string `json:"archive,omitempty"`
+}
+
+// GC applies the retention policy: expired notes are dropped, cold
+// notes and daily logs are moved to the gzip-compressed archive under
+// archive/, and namespaces over MaxNotes lose their coldest notes. The
+// archive is written before anything is removed. A dry run reports the
+// same records without changing anything.
+func (m *Manager) GC(ctx context.Context, dryRun bool) (*GCResult, error) {
+	if !dryRun {
+		if err := m.ExpireNotes(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	m.notesMu.Lock()
+	defer m.notesMu.Unlock()
+
+	now := time.Now().UTC()
+	res := &GCResult{DryRun: dryRun}
+	kept := map[string][]Note{}
+
+	namespaces, err := m.noteNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		notes, err := m.loadNotes(ns)
+		if err != nil {
+			return nil, err
+		}
+		notes = liveNotes(notes, now)
+		archived := len(res.Archived)
+		keep := make([]Note, 0, len(notes))
+		scores := make(map[string]float64, len(notes))
+		for _, n := range notes {
+			score := m.retention.retentionScore(now.Sub(n.UpdatedAt), n.Importance)
+			scores[n.ID] = score
+			if score < m.retention.archiveBelow() {
+				res.Archived = append(res.Archived, archivedNote(ns, n, score, "cold", now))
+				continue
+			}
+			keep = append(keep, n)
+		}
+		if m.retention.MaxNotes > 0 && len(keep) > m.retention.MaxNotes {
+			slices.SortStableFunc(keep, func(a, b Note) int {
+				switch {
+				case scores[a.ID] > scores[b.ID]:
+					return -1
+				case scores[a.ID] < scores[b.ID]:
+					return 1
+				}
+				return 0
+			})
+			for _, n := range keep[m.retention.MaxNotes:] {
+				res.Archived = append(res.Archived, archivedNote(ns, n, scores[n.ID], "max_notes", now))
+			}
+			keep = keep[:m.retention.MaxNotes]
+			slices.SortStableFunc(keep, func(a, b Note) int { return a.CreatedAt.Compare(b.CreatedAt) })
+		}
+		if len(res.Archived) > archived {
+			res.ArchivedNotes += len(res.Archived) - archived
+			kept[ns] = keep
+		}
+	}
+
+	files, err := m.fileStore.ListFiles()
+	if err != nil {
+		return nil, fmt.Errorf("listing memory files: %w", err)
+	}
+	today := dailyLogName(now)
+	var logs []string
+	for _, f := range files {
+		day, err := time.Parse("2006-01-02.md", f)
+		if err != nil || f == today {
+			continue
+		}
+		score := m.retention.retentionScore(now.Sub(day), DefaultImportance)
+		if score >= m.retention.archiveBelow() {
+			continue
+		}
+		content, err := m.fileStore.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", f, err)
+		}
+		res.Archived = append(res.Archived, ArchivedMemory{
+			Kind: "daily_log", Source: f, Content: content, Score: score, Reason: "cold", ArchivedAt: now,
+		})
+		res.ArchivedLogs++
+		logs = append(logs, f)
+	}
+
+	if dryRun || len(res.Archived) == 0 {
+		return res, nil
+	}
+	if err := m.appendArchive(res.Archived); err != nil {
+		return nil, err
+	}
+	res.Archive = filepath.Join(archiveDir, archiveFile)
+
+	for ns, notes := range kept {
+		if err := m.saveNotes(ctx, ns, notes); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range logs {
+		if err := os.Remove(filepath.Join(m.fileStore.Dir(), f)); err != nil {
+			return nil, fmt.Errorf("removing %s: %w", f, err)
+		}
+		if err := m.vecStore.DeleteBySource(ctx, f); err != nil {
+			return nil, fmt.Errorf("deleting chunks for %s: %w", f, err)
+		}
+	}
+	m.logger.Info("memory gc archived cold memories", map[string]any{
+		"archived_notes": res.ArchivedNotes, "archived_logs": res.ArchivedLogs,
+	})
+	return res, nil
+}
+
+// ReadArchive returns every record GC has archived, oldest first.
+func (m *Manager) ReadArchive() ([]ArchivedMemory, error) {
+	f, err := os.Open(filepath.Join(m.fileStore.Dir(), archiveDir, archiveFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	// Each GC pass appends a gzip member; the reader reads them all.
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	var out []ArchivedMemory
+	dec := json.NewDecoder(zr)
+	for dec.More() {
+		var a ArchivedMemory
+		if err := dec.Decode(&a); err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// appendArchive appends records to the archive as one gzip member of
+// JSON lines.
+func (m *Manager) appendArchive(records []ArchivedMemory) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(m.fileStore.Dir(), archiveDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating archive dir: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, archiveFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening archive: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing archive: %w", err)
+	}
+	return f.Close()
+}
+
+// noteNamespaces lists the namespaces that have notes on disk.
+func (m *Manager) noteNamespaces() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.fileStore.Dir(), notesDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		if ns, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			out = append(out, ns)
+		}
+	}
+	return out, nil
+}
+
+func archivedNote(ns string, n Note, score float64, reason string, now time.Time) ArchivedMemory {
+	return ArchivedMemory{
+		Kind: "note", Source: notesFile(ns), Namespace: ns, Note: &n, Score: score, Reason: reason, ArchivedAt: now,
+	}
+}
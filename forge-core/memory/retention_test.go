@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRetentionScore(t *testing.T) {
+	p := RetentionPolicy{HalfLife: 10 * 24 * time.Hour}
+	day := 24 * time.Hour
+	for _, tc := range []struct {
+		age        time.Duration
+		importance float64
+		want       float64
+	}{
+		{0, 0.5, 1},
+		{10 * day, 0.5, 0.5},
+		{10 * day, 0, 0.5}, // unset importance counts as DefaultImportance
+		{20 * day, 1, 0.5},
+		{10 * day, 0.25, 0.25},
+	} {
+		if got := p.retentionScore(tc.age, tc.importance); got < tc.want-1e-9 || got > tc.want+1e-9 {
+			t.Errorf("retentionScore(%v, %v) = %v, want %v", tc.age, tc.importance, got, tc.want)
+		}
+	}
+}
+
+// ageNotes rewrites ns's notes with UpdatedAt moved back by the given ages.
+func ageNotes(t *testing.T, mgr *Manager, ns string, ages map[string]time.Duration) {
+	t.Helper()
+	notes, err := mgr.loadNotes(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range notes {
+		notes[i].UpdatedAt = notes[i].UpdatedAt.Add(-ages[notes[i].Content])
+	}
+	data, _ := json.Marshal(notes)
+	if err := os.WriteFile(filepath.Join(mgr.fileStore.Dir(), notesDir, ns+".json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGC_ArchivesColdMemories(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{Retention: RetentionPolicy{HalfLife: 10 * 24 * time.Hour}})
+	ctx := context.Background()
+	day := 24 * time.Hour
+
+	for _, req := range []WriteRequest{
+		{Content: "Fresh fact."},
+		{Content: "Old trivia."},
+		{Content: "Old but vital.", Importance: 1},
+	} {
+		if _, err := mgr.Write(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 40 days is four half-lives (0.0625) at importance 0.5 and two
+	// (0.25) at importance 1.
+	ageNotes(t, mgr, DefaultNamespace, map[string]time.Duration{"Old trivia.": 40 * day, "Old but vital.": 40 * day})
+
+	oldLog := dailyLogName(time.Now().Add(-60 * day))
+	recentLog := dailyLogName(time.Now().Add(-2 * day))
+	for _, f := range []string{oldLog, recentLog} {
+		if err := os.WriteFile(filepath.Join(mgr.fileStore.Dir(), f), []byte("## 10:00:00\nobservation\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mgr.IndexAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := mgr.GC(ctx, true)
+	if err != nil {
+		t.Fatalf("GC dry run: %v", err)
+	}
+	if dry.ArchivedNotes != 1 || dry.ArchivedLogs != 1 || dry.Archive != "" {
+		t.Fatalf("dry run = %+v", dry)
+	}
+	if _, err := os.Stat(filepath.Join(mgr.fileStore.Dir(), oldLog)); err != nil {
+		t.Fatal("dry run removed a daily log")
+	}
+
+	res, err := mgr.GC(ctx, false)
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if res.ArchivedNotes != 1 || res.ArchivedLogs != 1 || res.Archive == "" {
+		t.Fatalf("result = %+v", res)
+	}
+	notes, _ := mgr.loadNotes(DefaultNamespace)
+	if len(notes) != 2 || notes[0].Content != "Fresh fact." || notes[1].Content != "Old but vital." {
+		t.Errorf("kept notes = %+v", notes)
+	}
+	if _, err := os.Stat(filepath.Join(mgr.fileStore.Dir(), oldLog)); !os.IsNotExist(err) {
+		t.Error("cold daily log should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(mgr.fileStore.Dir(), recentLog)); err != nil {
+		t.Error("recent daily log should be kept")
+	}
+	results, _ := mgr.Search(ctx, "observation")
+	for _, r := range results {
+		if r.Chunk.Source == oldLog {
+			t.Error("archived daily log is still indexed")
+		}
+	}
+
+	archived, err := mgr.ReadArchive()
+	if err != nil {
+		t.Fatalf("ReadArchive: %v", err)
+	}
+	if len(archived) != 2 || archived[0].Note == nil || archived[0].Note.Content != "Old trivia." || archived[1].Source != oldLog {
+		t.Errorf("archive = %+v", archived)
+	}
+}
+
+func TestGC_MaxNotes(t *testing.T) {
+	mgr := newNotesManager(t, ManagerConfig{Retention: RetentionPolicy{MaxNotes: 2}})
+	ctx := context.Background()
+
+	for _, c := range []string{"First.", "Second.", "Third."} {
+		if _, err := mgr.Write(ctx, WriteRequest{Namespace: "ops", Content: c}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ageNotes(t, mgr, "ops", map[string]time.Duration{"Second.": 24 * time.Hour})
+
+	res, err := mgr.GC(ctx, false)
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if res.ArchivedNotes != 1 || res.Archived[0].Note.Content != "Second." || res.Archived[0].Reason != "max_notes" {
+		t.Errorf("result = %+v", res)
+	}
+
+	// A second pass appends another gzip member to the same archive.
+	if _, err := mgr.Write(ctx, WriteRequest{Namespace: "ops", Content: "Fourth."}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.GC(ctx, false); err != nil {
+		t.Fatal(err)
+	}
+	archived, err := mgr.ReadArchive()
+	if err != nil || len(archived) != 2 {
+		t.Errorf("archive = %+v, err = %v", archived, err)
+	}
+}
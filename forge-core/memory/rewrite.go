@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// hydePrompt asks for the passage HyDE embeds in place of the bare query.
+const hydePrompt = `Write a short passage (2-4 sentences) that would answer the search query below, as it might appear in the user's notes or documents. State plausible specifics; do not hedge or mention that the passage is hypothetical. Reply with the passage only.`
+
+// rewriteQuery returns the text to embed for query: the query followed
+// by a hypothetical answer passage, or the query itself when rewriting
+// is off, fails, or exceeds RewriteBudget.
+func (h *HybridSearcher) rewriteQuery(ctx context.Context, query string) string {
+	if !h.config.QueryRewrite || h.rewriter == nil {
+		return query
+	}
+	ctx, cancel := context.WithTimeout(ctx, h.config.RewriteBudget)
+	defer cancel()
+	resp, err := h.rewriter.Chat(ctx, &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: hydePrompt},
+			{Role: llm.RoleUser, Content: query},
+		},
+		MaxTokens: 200,
+	})
+	if err != nil {
+		return query
+	}
+	passage := strings.TrimSpace(resp.Message.Content)
+	if passage == "" {
+		return query
+	}
+	return query + "\n\n" + passage
+}
+
+// llmRerankPrompt asks a chat model to grade every candidate at once.
+const llmRerankPrompt = `You grade search results. For each numbered document, rate how well it answers the query from 0 (irrelevant) to 10 (directly answers it). Reply with only a JSON array of numbers, one per document, in document order.`
+
+// llmRerankDocChars caps each document in the grading prompt.
+const llmRerankDocChars = 1000
+
+// LLMReranker implements llm.Reranker with a chat model that grades all
+// candidates in one call. It is slower than a cross-encoder but needs no
+// rerank endpoint, only the agent's own LLM.
+type LLMReranker struct {
+	client llm.Client
+}
+
+// NewLLMReranker creates a reranker that grades candidates with client.
+func NewLLMReranker(client llm.Client) *LLMReranker {
+	return &LLMReranker{client: client}
+}
+
+// Rerank grades each document 0-10 against the query and returns the
+// grades scaled to 0-1.
+func (r *LLMReranker) Rerank(ctx context.Context, req *llm.RerankRequest) (*llm.RerankResponse, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Query: %s\n", req.Query)
+	for i, doc := range req.Documents {
+		if runes := []rune(doc); len(runes) > llmRerankDocChars {
+			doc = string(runes[:llmRerankDocChars]) + "…"
+		}
+		fmt.Fprintf(&b, "\n[%d]\n%s\n", i+1, doc)
+	}
+	resp, err := r.client.Chat(ctx, &llm.ChatRequest{
+		Model: req.Model,
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: llmRerankPrompt},
+			{Role: llm.RoleUser, Content: b.String()},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("llm rerank: %w", err)
+	}
+
+	// Tolerate prose or a code fence around the array.
+	content := resp.Message.Content
+	start, end := strings.Index(content, "["), strings.LastIndex(content, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("llm rerank: no score array in reply")
+	}
+	var grades []float64
+	if err := json.Unmarshal([]byte(content[start:end+1]), &grades); err != nil {
+		return nil, fmt.Errorf("llm rerank: parsing scores: %w", err)
+	}
+	if len(grades) != len(req.Documents) {
+		return nil, fmt.Errorf("llm rerank: got %d scores for %d documents", len(grades), len(req.Documents))
+	}
+	scores := make([]float64, len(grades))
+	for i, g := range grades {
+		scores[i] = min(max(g, 0), 10) / 10
+	}
+	return &llm.RerankResponse{Scores: scores, Model: req.Model}, nil
+}
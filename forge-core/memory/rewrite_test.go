@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// mockChatClient answers every chat request with reply, after delay.
+type mockChatClient struct {
+	reply string
+	delay time.Duration
+	calls int
+}
+
+func (m *mockChatClient) Chat(ctx context.Context, _ *llm.ChatRequest) (*llm.ChatResponse, error) {
+	m.calls++
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: m.reply}}, nil
+}
+
+func (m *mockChatClient) ChatStream(context.Context, *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockChatClient) ModelID() string { return "mock" }
+
+func TestHybridSearcher_RewriteQuery(t *testing.T) {
+	cfg := DefaultSearchConfig()
+	cfg.QueryRewrite = true
+	cfg.RewriteBudget = 50 * time.Millisecond
+	searcher := NewHybridSearcher(nil, nil, cfg)
+
+	// No rewriter: the query is embedded as is.
+	if got := searcher.rewriteQuery(context.Background(), "deploy target?"); got != "deploy target?" {
+		t.Errorf("without rewriter = %q", got)
+	}
+
+	searcher.rewriter = &mockChatClient{reply: " We deploy to the staging cluster in eu-west-1. "}
+	if got := searcher.rewriteQuery(context.Background(), "deploy target?"); got != "deploy target?\n\nWe deploy to the staging cluster in eu-west-1." {
+		t.Errorf("rewritten = %q", got)
+	}
+
+	// A rewriter slower than the budget is skipped.
+	searcher.rewriter = &mockChatClient{reply: "late", delay: time.Second}
+	start := time.Now()
+	if got := searcher.rewriteQuery(context.Background(), "deploy target?"); got != "deploy target?" {
+		t.Errorf("over budget = %q", got)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("rewrite took %v despite a 50ms budget", elapsed)
+	}
+}
+
+func TestHybridSearcher_RerankBudget(t *testing.T) {
+	store, err := NewFileVectorStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close() //nolint:errcheck
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	chunks := []IndexedChunk{
+		{Chunk: Chunk{ID: "1", Source: "MEMORY.md", Content: "dark mode dark mode everywhere", CreatedAt: now}},
+		{Chunk: Chunk{ID: "2", Source: "MEMORY.md", Content: "user said dark mode hurts", CreatedAt: now}},
+	}
+	if err := store.Index(ctx, chunks); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultSearchConfig()
+	cfg.RerankBudget = 50 * time.Millisecond
+	searcher := NewHybridSearcher(store, nil, cfg)
+	slow := &mockChatClient{reply: "[1, 9]", delay: time.Second}
+	searcher.reranker = NewLLMReranker(slow)
+
+	results, err := searcher.Search(ctx, "dark mode")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 || results[0].Score != 1 {
+		t.Errorf("over-budget rerank should keep the hybrid order, got %+v", results)
+	}
+
+	// Exact-content lookups skip the reranker altogether.
+	slow.calls = 0
+	if _, err := searcher.search(ctx, "dark mode", false); err != nil || slow.calls != 0 {
+		t.Errorf("unrefined search called the reranker %d times (err %v)", slow.calls, err)
+	}
+}
+
+func TestLLMReranker(t *testing.T) {
+	docs := []string{"refund policy", "shipping times", "office address"}
+	r := NewLLMReranker(&mockChatClient{reply: "Scores:\n```json\n[9, 2.5, 14]\n```"})
+	resp, err := r.Rerank(context.Background(), &llm.RerankRequest{Query: "refunds", Documents: docs})
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	want := []float64{0.9, 0.25, 1}
+	for i := range want {
+		if resp.Scores[i] != want[i] {
+			t.Errorf("scores = %v, want %v", resp.Scores, want)
+			break
+		}
+	}
+
+	for _, reply := range []string{"[1, 2]", "no idea"} {
+		r := NewLLMReranker(&mockChatClient{reply: reply})
+		if _, err := r.Rerank(context.Background(), &llm.RerankRequest{Query: "refunds", Documents: docs}); err == nil {
+			t.Errorf("reply %q: expected an error", reply)
+		}
+	}
+}
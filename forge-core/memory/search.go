@@ -17,6 +17,13 @@ type SearchConfig struct {
 	DecayHalfLife time.Duration // temporal decay half-life (default: 7 days)
 	DecayEnabled  bool          // whether to apply temporal decay (default: true)
 	TopK          int           // max results to return (default: 10)
+
+	// QueryRewrite embeds the query together with a hypothetical answer
+	// passage the searcher's LLM writes for it (HyDE), which lands nearer
+	// to stored answers than a terse question does. Needs an embedder.
+	QueryRewrite  bool
+	RewriteBudget time.Duration // max time for the rewrite (default: 2s)
+	RerankBudget  time.Duration // max time for the reranker (default: 3s)
 }
 
 // DefaultSearchConfig returns a SearchConfig with sensible defaults.
@@ -27,14 +34,25 @@ func DefaultSearchConfig() SearchConfig {
 		DecayHalfLife: 7 * 24 * time.Hour,
 		DecayEnabled:  true,
 		TopK:          10,
+		RewriteBudget: DefaultRewriteBudget,
+		RerankBudget:  DefaultRerankBudget,
 	}
 }
 
+// Default latency budgets for the optional search stages. A stage that
+// runs out of time is skipped, never failing the search.
+const (
+	DefaultRewriteBudget = 2 * time.Second
+	DefaultRerankBudget  = 3 * time.Second
+)
+
 // HybridSearcher combines vector similarity, keyword overlap, and temporal
 // decay for memory retrieval.
 type HybridSearcher struct {
 	store    VectorStore
 	embedder llm.Embedder // nil = keyword-only mode
+	reranker llm.Reranker // nil = hybrid score order
+	rewriter llm.Client   // nil = no query rewriting
 	config   SearchConfig
 }
 
@@ -52,6 +70,12 @@ func NewHybridSearcher(store VectorStore, embedder llm.Embedder, config SearchCo
 	if config.DecayHalfLife <= 0 {
 		config.DecayHalfLife = 7 * 24 * time.Hour
 	}
+	if config.RewriteBudget <= 0 {
+		config.RewriteBudget = DefaultRewriteBudget
+	}
+	if config.RerankBudget <= 0 {
+		config.RerankBudget = DefaultRerankBudget
+	}
 	return &HybridSearcher{
 		store:    store,
 		embedder: embedder,
@@ -59,22 +83,33 @@ func NewHybridSearcher(store VectorStore, embedder llm.Embedder, config SearchCo
 	}
 }
 
-// Search performs hybrid search: vector + keyword + temporal decay.
+// Search performs hybrid search: vector + keyword + temporal decay,
+// refined by the configured query rewrite and reranker.
 // If no embedder is available, falls back to keyword-only search over all chunks.
 func (h *HybridSearcher) Search(ctx context.Context, query string) ([]SearchResult, error) {
+	return h.search(ctx, query, true)
+}
+
+// search runs the hybrid search; refine enables the LLM query rewrite
+// and the reranker, which lookups for exact content don't need.
+func (h *HybridSearcher) search(ctx context.Context, query string, refine bool) ([]SearchResult, error) {
 	candidateK := h.config.TopK * 3 // fetch more candidates for re-ranking
 
 	var candidates []SearchResult
 
 	if h.embedder != nil {
 		// Vector search path
-		resp, err := h.embedder.Embed(ctx, &llm.EmbeddingRequest{Texts: []string{query}})
+		vectorQuery := query
+		if refine {
+			vectorQuery = h.rewriteQuery(ctx, query)
+		}
+		resp, err := h.embedder.Embed(ctx, &llm.EmbeddingRequest{Texts: []string{vectorQuery}})
 		if err != nil {
 			// Fall back to keyword-only on embedding failure.
-			return h.keywordOnlySearch(ctx, query)
+			return h.keywordOnlySearch(ctx, query, refine)
 		}
 		if len(resp.Embeddings) == 0 {
-			return h.keywordOnlySearch(ctx, query)
+			return h.keywordOnlySearch(ctx, query, refine)
 		}
 
 		candidates, err = h.store.Search(ctx, resp.Embeddings[0], candidateK)
@@ -103,9 +138,9 @@ func (h *HybridSearcher) Search(ctx context.Context, query string) ([]SearchResu
 		vectorScore := c.Score
 		keywordScore := keywordOverlap(queryTerms, c.Chunk.Content)
 
-		// Temporal decay: MEMORY.md is evergreen (decay = 1.0).
+		// Temporal decay: MEMORY.md and notes are evergreen (decay = 1.0).
 		decay := 1.0
-		if h.config.DecayEnabled && c.Chunk.Source != "MEMORY.md" {
+		if h.config.DecayEnabled && !evergreen(c.Chunk.Source) {
 			age := now.Sub(c.Chunk.CreatedAt)
 			decay = math.Exp(-math.Ln2 / h.config.DecayHalfLife.Seconds() * age.Seconds())
 		}
@@ -132,17 +167,15 @@ func (h *HybridSearcher) Search(ctx context.Context, query string) ([]SearchResu
 		return scored[i].finalScore > scored[j].finalScore
 	})
 
-	topK := min(h.config.TopK, len(scored))
-
-	results := make([]SearchResult, topK)
-	for i := range topK {
+	results := make([]SearchResult, len(scored))
+	for i := range scored {
 		results[i] = scored[i].result
 	}
-	return results, nil
+	return h.rerankTopK(ctx, query, results, refine), nil
 }
 
 // keywordOnlySearch loads all chunks and ranks by keyword overlap + decay.
-func (h *HybridSearcher) keywordOnlySearch(ctx context.Context, query string) ([]SearchResult, error) {
+func (h *HybridSearcher) keywordOnlySearch(ctx context.Context, query string, refine bool) ([]SearchResult, error) {
 	// Use a nil vector with k=0 to get all chunks (store should return everything).
 	all, err := h.store.Search(ctx, nil, 0)
 	if err != nil {
@@ -165,7 +198,7 @@ func (h *HybridSearcher) keywordOnlySearch(ctx context.Context, query string) ([
 		}
 
 		decay := 1.0
-		if h.config.DecayEnabled && c.Chunk.Source != "MEMORY.md" {
+		if h.config.DecayEnabled && !evergreen(c.Chunk.Source) {
 			age := now.Sub(c.Chunk.CreatedAt)
 			decay = math.Exp(-math.Ln2 / h.config.DecayHalfLife.Seconds() * age.Seconds())
 		}
@@ -181,13 +214,39 @@ func (h *HybridSearcher) keywordOnlySearch(ctx context.Context, query string) ([
 		return scored[i].finalScore > scored[j].finalScore
 	})
 
-	topK := min(h.config.TopK, len(scored))
-
-	results := make([]SearchResult, topK)
-	for i := range topK {
+	results := make([]SearchResult, len(scored))
+	for i := range scored {
 		results[i] = scored[i].result
 	}
-	return results, nil
+	return h.rerankTopK(ctx, query, results, refine), nil
+}
+
+// rerankTopK returns the TopK best of results (sorted by hybrid score).
+// With a reranker and refine set, the leading 3×TopK candidates are first
+// re-scored by the reranker and reordered; each reranked result's Score
+// is then the reranker's relevance score. A reranker error or a reranker
+// slower than RerankBudget keeps the hybrid order, as an embedding error
+// falls back to keyword search.
+func (h *HybridSearcher) rerankTopK(ctx context.Context, query string, results []SearchResult, refine bool) []SearchResult {
+	if refine && h.reranker != nil && len(results) > 1 {
+		pool := results[:min(h.config.TopK*3, len(results))]
+		docs := make([]string, len(pool))
+		for i, r := range pool {
+			docs[i] = r.Chunk.Content
+		}
+		rctx, cancel := context.WithTimeout(ctx, h.config.RerankBudget)
+		resp, err := h.reranker.Rerank(rctx, &llm.RerankRequest{Query: query, Documents: docs})
+		cancel()
+		if err == nil && len(resp.Scores) == len(pool) {
+			for i := range pool {
+				pool[i].Score = resp.Scores[i]
+			}
+			sort.SliceStable(pool, func(i, j int) bool {
+				return pool[i].Score > pool[j].Score
+			})
+		}
+	}
+	return results[:min(h.config.TopK, len(results))]
 }
 
 // tokenize splits text into lowercase terms for keyword matching.
@@ -224,3 +283,10 @@ func keywordOverlap(queryTerms []string, content string) float64 {
 	}
 	return float64(matched) / float64(len(queryTerms))
 }
+
+// evergreen reports whether chunks from source skip temporal decay:
+// curated MEMORY.md, notes saved with memory_write, which expire
+// explicitly instead, and knowledge base documents.
+func evergreen(source string) bool {
+	return source == "MEMORY.md" || isNotesFile(source) || strings.HasPrefix(source, KBSourcePrefix)
+}
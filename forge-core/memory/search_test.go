@@ -124,6 +124,70 @@ func TestHybridSearcher_KeywordOnly(t *testing.T) {
 	}
 }
 
+// mockReranker scores documents by a fixed per-content table.
+type mockReranker struct {
+	scores map[string]float64
+	err    error
+}
+
+func (m *mockReranker) Rerank(_ context.Context, req *llm.RerankRequest) (*llm.RerankResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	scores := make([]float64, len(req.Documents))
+	for i, d := range req.Documents {
+		scores[i] = m.scores[d]
+	}
+	return &llm.RerankResponse{Scores: scores}, nil
+}
+
+func TestHybridSearcher_Rerank(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileVectorStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close() //nolint:errcheck
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	chunks := []IndexedChunk{
+		{Chunk: Chunk{ID: "1", Source: "MEMORY.md", Content: "dark mode dark mode everywhere", CreatedAt: now}},
+		{Chunk: Chunk{ID: "2", Source: "MEMORY.md", Content: "user said dark mode hurts in daylight", CreatedAt: now}},
+		{Chunk: Chunk{ID: "3", Source: "MEMORY.md", Content: "mode of transport: bike", CreatedAt: now}},
+	}
+	if err := store.Index(ctx, chunks); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultSearchConfig()
+	cfg.TopK = 2
+	searcher := NewHybridSearcher(store, nil, cfg)
+	searcher.reranker = &mockReranker{scores: map[string]float64{
+		chunks[0].Chunk.Content: 0.2,
+		chunks[1].Chunk.Content: 0.9,
+		chunks[2].Chunk.Content: 0.1,
+	}}
+
+	results, err := searcher.Search(ctx, "dark mode")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 || results[0].Chunk.ID != "2" || results[0].Score != 0.9 {
+		t.Fatalf("reranked results = %+v, want chunk 2 first with score 0.9", results)
+	}
+
+	// A failing reranker keeps the hybrid order.
+	searcher.reranker = &mockReranker{err: context.DeadlineExceeded}
+	results, err = searcher.Search(ctx, "dark mode")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 || results[0].Score != 1 {
+		t.Errorf("fallback results = %+v, want hybrid order", results)
+	}
+}
+
 func TestHybridSearcher_TemporalDecay(t *testing.T) {
 	dir := t.TempDir()
 	store, err := NewFileVectorStore(dir)
@@ -0,0 +1,171 @@
+package llmtrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultLangfuseEndpoint is Langfuse Cloud.
+const DefaultLangfuseEndpoint = "https://cloud.langfuse.com"
+
+// Langfuse exports traces through Langfuse's ingestion API: a
+// trace-create event keyed by the correlation ID, with the task ID as
+// its session, then a generation-create per LLM call and a span-create
+// per tool call.
+type Langfuse struct {
+	endpoint  string
+	publicKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewLangfuse returns a Langfuse exporter for the project the key pair
+// belongs to. An empty endpoint means Langfuse Cloud; a nil client
+// means http.DefaultClient.
+func NewLangfuse(endpoint, publicKey, secretKey string, client *http.Client) (*Langfuse, error) {
+	if publicKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("langfuse: %w (LANGFUSE_PUBLIC_KEY and LANGFUSE_SECRET_KEY)", ErrNoCredentials)
+	}
+	if endpoint == "" {
+		endpoint = DefaultLangfuseEndpoint
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Langfuse{endpoint: strings.TrimRight(endpoint, "/"), publicKey: publicKey, secretKey: secretKey, client: client}, nil
+}
+
+// Name implements Exporter.
+func (l *Langfuse) Name() string { return "langfuse" }
+
+type langfuseEvent struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
+	Timestamp string         `json:"timestamp"`
+	Body      map[string]any `json:"body"`
+}
+
+// Export implements Exporter.
+func (l *Langfuse) Export(ctx context.Context, t *Trace) error {
+	batch := []langfuseEvent{{
+		ID:        NewID(),
+		Type:      "trace-create",
+		Timestamp: langfuseTime(t.Start),
+		Body: omitEmpty(map[string]any{
+			"id":        t.ID,
+			"name":      t.Name,
+			"timestamp": langfuseTime(t.Start),
+			"sessionId": t.SessionID,
+			"input":     t.Input,
+			"output":    t.Output,
+			"metadata":  traceMetadata(t),
+			"tags":      []string{"forge"},
+		}),
+	}}
+	for _, o := range t.Observations {
+		body := omitEmpty(map[string]any{
+			"id":        o.ID,
+			"traceId":   t.ID,
+			"name":      o.Name,
+			"startTime": langfuseTime(o.Start),
+			"endTime":   langfuseTime(o.End),
+			"input":     o.Input,
+			"output":    o.Output,
+		})
+		if o.Error != "" {
+			body["level"] = "ERROR"
+			body["statusMessage"] = o.Error
+		}
+		typ := "span-create"
+		if o.Kind == KindGeneration {
+			typ = "generation-create"
+			body["model"] = o.Model
+			body["usage"] = map[string]any{
+				"input":  o.InputTokens,
+				"output": o.OutputTokens,
+				"total":  o.InputTokens + o.OutputTokens,
+				"unit":   "TOKENS",
+			}
+			if o.Provider != "" {
+				body["metadata"] = map[string]any{"provider": o.Provider}
+			}
+		}
+		batch = append(batch, langfuseEvent{ID: NewID(), Type: typ, Timestamp: langfuseTime(o.Start), Body: body})
+	}
+
+	raw, err := json.Marshal(map[string]any{"batch": batch})
+	if err != nil {
+		return fmt.Errorf("langfuse: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.endpoint+"/api/public/ingestion", bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("langfuse: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(l.publicKey, l.secretKey)
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("langfuse: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		return errStatus("langfuse", resp.StatusCode, body)
+	}
+	// 207 Multi-Status lists the events Langfuse rejected.
+	var result struct {
+		Errors []struct {
+			ID      string `json:"id"`
+			Status  int    `json:"status"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &result) == nil && len(result.Errors) > 0 {
+		e := result.Errors[0]
+		return fmt.Errorf("langfuse: %d of %d events rejected, first: HTTP %d %s", len(result.Errors), len(batch), e.Status, e.Message)
+	}
+	return nil
+}
+
+func langfuseTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// traceMetadata is t's metadata plus its final state.
+func traceMetadata(t *Trace) map[string]any {
+	md := make(map[string]any, len(t.Metadata)+1)
+	for k, v := range t.Metadata {
+		md[k] = v
+	}
+	if t.Status != "" {
+		md["state"] = t.Status
+	}
+	return md
+}
+
+// omitEmpty drops the empty strings and maps of body, which the
+// platforms would otherwise store as empty values.
+func omitEmpty(body map[string]any) map[string]any {
+	for k, v := range body {
+		switch v := v.(type) {
+		case string:
+			if v == "" {
+				delete(body, k)
+			}
+		case map[string]any:
+			if len(v) == 0 {
+				delete(body, k)
+			}
+		}
+	}
+	return body
+}
@@ -0,0 +1,145 @@
+package llmtrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultLangSmithEndpoint is LangSmith's hosted API.
+const DefaultLangSmithEndpoint = "https://api.smith.langchain.com"
+
+// LangSmith exports traces through LangSmith's batch runs API: a root
+// "chain" run per trace, whose UUID is derived from the correlation ID
+// (kept in its metadata), with an "llm" run per LLM call and a "tool"
+// run per tool call under it. The task ID is set as the run's
+// session_id metadata, which LangSmith groups into threads.
+type LangSmith struct {
+	endpoint string
+	apiKey   string
+	project  string
+	client   *http.Client
+}
+
+// NewLangSmith returns a LangSmith exporter filing runs under project.
+// An empty endpoint means LangSmith's hosted API; a nil client means
+// http.DefaultClient.
+func NewLangSmith(endpoint, apiKey, project string, client *http.Client) (*LangSmith, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("langsmith: %w (LANGSMITH_API_KEY)", ErrNoCredentials)
+	}
+	if endpoint == "" {
+		endpoint = DefaultLangSmithEndpoint
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &LangSmith{endpoint: strings.TrimRight(endpoint, "/"), apiKey: apiKey, project: project, client: client}, nil
+}
+
+// Name implements Exporter.
+func (l *LangSmith) Name() string { return "langsmith" }
+
+// Export implements Exporter.
+func (l *LangSmith) Export(ctx context.Context, t *Trace) error {
+	rootID := UUIDFor(t.ID)
+	rootOrder := dottedOrder(t.Start, rootID)
+	md := traceMetadata(t)
+	md["correlation_id"] = t.ID
+	if t.SessionID != "" {
+		md["session_id"] = t.SessionID
+	}
+	root := map[string]any{
+		"id":           rootID,
+		"trace_id":     rootID,
+		"dotted_order": rootOrder,
+		"name":         t.Name,
+		"run_type":     "chain",
+		"start_time":   langsmithTime(t.Start),
+		"end_time":     langsmithTime(t.End),
+		"inputs":       map[string]any{"input": t.Input},
+		"outputs":      map[string]any{"output": t.Output},
+		"extra":        map[string]any{"metadata": md},
+		"tags":         []string{"forge"},
+		"session_name": l.project,
+	}
+	if t.Status == "failed" {
+		root["error"] = "task failed"
+	}
+	runs := []map[string]any{root}
+	for _, o := range t.Observations {
+		id := o.ID
+		if len(id) != 36 {
+			id = UUIDFor(id)
+		}
+		run := map[string]any{
+			"id":            id,
+			"trace_id":      rootID,
+			"parent_run_id": rootID,
+			"dotted_order":  rootOrder + "." + dottedOrder(o.Start, id),
+			"name":          o.Name,
+			"run_type":      "tool",
+			"start_time":    langsmithTime(o.Start),
+			"end_time":      langsmithTime(o.End),
+			"inputs":        map[string]any{"input": o.Input},
+			"outputs":       map[string]any{"output": o.Output},
+			"session_name":  l.project,
+		}
+		if o.Error != "" {
+			run["error"] = o.Error
+		}
+		if o.Kind == KindGeneration {
+			run["run_type"] = "llm"
+			run["outputs"] = map[string]any{
+				"output": o.Output,
+				"usage_metadata": map[string]any{
+					"input_tokens":  o.InputTokens,
+					"output_tokens": o.OutputTokens,
+					"total_tokens":  o.InputTokens + o.OutputTokens,
+				},
+			}
+			run["extra"] = map[string]any{"metadata": map[string]any{
+				"ls_provider":   o.Provider,
+				"ls_model_name": o.Model,
+			}}
+		}
+		runs = append(runs, run)
+	}
+
+	raw, err := json.Marshal(map[string]any{"post": runs})
+	if err != nil {
+		return fmt.Errorf("langsmith: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.endpoint+"/runs/batch", bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("langsmith: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", l.apiKey)
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("langsmith: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return errStatus("langsmith", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func langsmithTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000Z")
+}
+
+// dottedOrder is a run's segment of LangSmith's dotted_order: its start
+// time to the microsecond, then its ID.
+func dottedOrder(start time.Time, id string) string {
+	start = start.UTC()
+	return fmt.Sprintf("%s%06dZ%s", start.Format("20060102T150405"), start.Nanosecond()/1000, id)
+}
@@ -0,0 +1,311 @@
+// Package llmtrace exports the agent's task traces to LLM observability
+// platforms (Langfuse, LangSmith) in their own trace models: one trace
+// per invocation, keyed by its correlation ID, holding the LLM calls
+// ("generations") and tool calls the invocation made, their token
+// usage, and the task's input and final output.
+//
+// Like the rest of observability, the package is pure library: the cli
+// resolves forge.yaml and env into exporters, feeds a Recorder from the
+// runtime event bus, and closes it on shutdown.
+package llmtrace
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Kind discriminates the observations of a trace.
+type Kind string
+
+const (
+	// KindGeneration is one LLM call.
+	KindGeneration Kind = "generation"
+	// KindTool is one tool call.
+	KindTool Kind = "tool"
+)
+
+// Trace is one invocation of the agent: a task turn.
+type Trace struct {
+	// ID is the invocation's correlation ID.
+	ID string
+	// Name is the agent ID.
+	Name string
+	// SessionID groups the traces of one conversation: the task ID.
+	SessionID string
+	Start     time.Time
+	End       time.Time
+	// Input and Output are the user's message and the final answer;
+	// empty unless content capture is on.
+	Input  string
+	Output string
+	// Status is the task's final A2A state.
+	Status       string
+	Metadata     map[string]any
+	Observations []Observation
+}
+
+// Observation is one LLM or tool call within a trace.
+type Observation struct {
+	ID    string
+	Kind  Kind
+	Name  string
+	Start time.Time
+	End   time.Time
+	// Provider and Model identify a generation's model.
+	Provider     string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	// Input and Output are empty unless content capture is on.
+	Input  string
+	Output string
+	// Error is the call's error, when it failed.
+	Error string
+}
+
+// Exporter pushes finished traces to one platform.
+type Exporter interface {
+	// Name identifies the exporter in logs ("langfuse", "langsmith").
+	Name() string
+	Export(ctx context.Context, t *Trace) error
+}
+
+// Defaults bounding what a Recorder holds.
+const (
+	// MaxObservations caps the observations kept per trace; later ones
+	// are dropped and counted in the "dropped_observations" metadata.
+	MaxObservations = 500
+	// QueueSize is how many finished traces may wait for export before
+	// further ones are dropped.
+	QueueSize = 256
+	// ExportTimeout bounds one trace's export to one platform.
+	ExportTimeout = 10 * time.Second
+)
+
+// Recorder assembles traces from the runtime's events and hands each
+// finished trace to every exporter on a background goroutine, so a slow
+// platform never holds up a task. Methods are safe for concurrent use;
+// a nil *Recorder ignores every call.
+type Recorder struct {
+	exporters []Exporter
+	onError   func(exporter string, err error)
+
+	mu      sync.Mutex
+	open    map[string]*Trace
+	dropped map[string]int
+	closed  bool
+	queue   chan *Trace
+	wg      sync.WaitGroup
+}
+
+// NewRecorder starts a Recorder exporting to exporters. onError, when
+// set, is told about every failed export.
+func NewRecorder(onError func(exporter string, err error), exporters ...Exporter) *Recorder {
+	r := &Recorder{
+		exporters: exporters,
+		onError:   onError,
+		open:      map[string]*Trace{},
+		dropped:   map[string]int{},
+		queue:     make(chan *Trace, QueueSize),
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r
+}
+
+// Start opens trace t. A trace that already has events keeps them and
+// takes the fields t sets: its name, session, start, input and
+// metadata.
+func (r *Recorder) Start(t Trace) {
+	if r == nil || t.ID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	cur := r.trace(t.ID, t.Start)
+	if t.Name != "" {
+		cur.Name = t.Name
+	}
+	if t.SessionID != "" {
+		cur.SessionID = t.SessionID
+	}
+	if t.Input != "" {
+		cur.Input = t.Input
+	}
+	if !t.Start.IsZero() {
+		cur.Start = t.Start
+	}
+	for k, v := range t.Metadata {
+		cur.Metadata[k] = v
+	}
+}
+
+// Observe adds o to trace traceID, opening the trace when no Start
+// came first. Observations without an ID get a random one.
+func (r *Recorder) Observe(traceID string, o Observation) {
+	if r == nil || traceID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	t := r.trace(traceID, o.Start)
+	if len(t.Observations) >= MaxObservations {
+		r.dropped[traceID]++
+		return
+	}
+	if o.ID == "" {
+		o.ID = NewID()
+	}
+	t.Observations = append(t.Observations, o)
+}
+
+// End closes trace traceID and queues it for export. Output, when
+// empty, defaults to the last generation's output.
+func (r *Recorder) End(traceID string, end time.Time, status, output string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.open[traceID]
+	if t == nil || r.closed {
+		return
+	}
+	t.End, t.Status, t.Output = end, status, output
+	if t.Output == "" {
+		for i := len(t.Observations) - 1; i >= 0; i-- {
+			if t.Observations[i].Kind == KindGeneration {
+				t.Output = t.Observations[i].Output
+				break
+			}
+		}
+	}
+	r.finishLocked(traceID)
+}
+
+// Close exports the traces still open, as they stand, and waits for
+// the queue to drain or ctx to end.
+func (r *Recorder) Close(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	if !r.closed {
+		for id, t := range r.open {
+			if t.End.IsZero() {
+				t.End = time.Now()
+			}
+			r.finishLocked(id)
+		}
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// trace returns the open trace id, creating it. Callers hold mu.
+func (r *Recorder) trace(id string, start time.Time) *Trace {
+	t := r.open[id]
+	if t == nil {
+		if start.IsZero() {
+			start = time.Now()
+		}
+		t = &Trace{ID: id, Start: start, Metadata: map[string]any{}}
+		r.open[id] = t
+	}
+	return t
+}
+
+// finishLocked moves trace id to the export queue, dropping it when the
+// queue is full. Callers hold mu.
+func (r *Recorder) finishLocked(id string) {
+	t := r.open[id]
+	delete(r.open, id)
+	if n := r.dropped[id]; n > 0 {
+		t.Metadata["dropped_observations"] = n
+		delete(r.dropped, id)
+	}
+	select {
+	case r.queue <- t:
+	default:
+		r.report("", fmt.Errorf("export queue full, trace %s dropped", id))
+	}
+}
+
+func (r *Recorder) run() {
+	defer r.wg.Done()
+	for t := range r.queue {
+		for _, e := range r.exporters {
+			ctx, cancel := context.WithTimeout(context.Background(), ExportTimeout)
+			if err := e.Export(ctx, t); err != nil {
+				r.report(e.Name(), err)
+			}
+			cancel()
+		}
+	}
+}
+
+func (r *Recorder) report(exporter string, err error) {
+	if r.onError != nil {
+		r.onError(exporter, err)
+	}
+}
+
+// NewID returns a random UUID (version 4).
+func NewID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+// UUIDFor derives a stable UUID from an ID that is not one, such as a
+// correlation ID, for platforms that only accept UUIDs.
+func UUIDFor(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	var b [16]byte
+	copy(b[:], sum[:16])
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+func formatUUID(b [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// errStatus turns a platform's non-2xx reply into an error.
+func errStatus(platform string, status int, body []byte) error {
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return fmt.Errorf("%s: HTTP %d: %s", platform, status, body)
+}
+
+// ErrNoCredentials is returned by the exporter constructors when the
+// platform's API key is missing.
+var ErrNoCredentials = errors.New("missing API key")
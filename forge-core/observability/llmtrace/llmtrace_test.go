@@ -0,0 +1,180 @@
+package llmtrace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeExporter struct {
+	mu     sync.Mutex
+	traces []*Trace
+}
+
+func (f *fakeExporter) Name() string { return "fake" }
+
+func (f *fakeExporter) Export(_ context.Context, t *Trace) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.traces = append(f.traces, t)
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	exp := &fakeExporter{}
+	r := NewRecorder(nil, exp)
+	t0 := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// An observation before Start opens the trace.
+	r.Observe("corr-1", Observation{Kind: KindTool, Name: "web_search", Start: t0, End: t0.Add(time.Second)})
+	r.Start(Trace{ID: "corr-1", Name: "bot", SessionID: "task-1", Start: t0, Metadata: map[string]any{"experiment": "tone/terse"}})
+	r.Observe("corr-1", Observation{Kind: KindGeneration, Name: "gpt-4o", Model: "gpt-4o", Output: "Paris", Start: t0, End: t0.Add(2 * time.Second)})
+	r.Start(Trace{ID: "corr-1", Input: "capital of France?"}) // only adds the input
+	r.End("corr-1", t0.Add(3*time.Second), "completed", "")
+	r.Start(Trace{ID: "corr-2", Start: t0})
+	r.End("unknown", t0, "completed", "")
+
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(exp.traces) != 2 {
+		t.Fatalf("exported %d traces, want the ended one and the one open at Close", len(exp.traces))
+	}
+	tr := exp.traces[0]
+	if tr.SessionID != "task-1" || tr.Name != "bot" || tr.Input != "capital of France?" || tr.Status != "completed" || tr.Output != "Paris" || len(tr.Observations) != 2 || tr.Metadata["experiment"] != "tone/terse" {
+		t.Errorf("trace = %+v", tr)
+	}
+	if tr.Observations[0].ID == "" {
+		t.Error("observation has no ID")
+	}
+	r.Observe("corr-3", Observation{}) // after Close: ignored
+	var nilRecorder *Recorder
+	nilRecorder.Observe("x", Observation{})
+}
+
+func testTrace() *Trace {
+	t0 := time.Date(2026, 10, 16, 12, 0, 0, 123456000, time.UTC)
+	return &Trace{
+		ID: "corr-1", Name: "bot", SessionID: "task-1", Start: t0, End: t0.Add(3 * time.Second),
+		Input: "capital of France?", Output: "Paris", Status: "completed",
+		Observations: []Observation{
+			{ID: NewID(), Kind: KindGeneration, Name: "gpt-4o", Provider: "openai", Model: "gpt-4o", InputTokens: 100, OutputTokens: 5, Start: t0, End: t0.Add(time.Second)},
+			{ID: NewID(), Kind: KindTool, Name: "web_search", Error: "timeout", Start: t0.Add(time.Second), End: t0.Add(2 * time.Second)},
+		},
+	}
+}
+
+func capture(t *testing.T, status int, reply string) (*httptest.Server, *http.Request, *map[string]any) {
+	t.Helper()
+	var req http.Request
+	body := map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = *r
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &req, &body
+}
+
+func TestLangfuse(t *testing.T) {
+	srv, req, body := capture(t, http.StatusMultiStatus, `{"successes":[],"errors":[]}`)
+	lf, err := NewLangfuse(srv.URL+"/", "pk", "sk", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lf.Export(context.Background(), testTrace()); err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Path != "/api/public/ingestion" {
+		t.Errorf("path = %s", req.URL.Path)
+	}
+	if u, p, _ := req.BasicAuth(); u != "pk" || p != "sk" {
+		t.Errorf("auth = %s:%s", u, p)
+	}
+	batch := (*body)["batch"].([]any)
+	if len(batch) != 3 {
+		t.Fatalf("batch = %v", batch)
+	}
+	trace := batch[0].(map[string]any)["body"].(map[string]any)
+	if batch[0].(map[string]any)["type"] != "trace-create" || trace["id"] != "corr-1" || trace["sessionId"] != "task-1" || trace["output"] != "Paris" {
+		t.Errorf("trace event = %v", batch[0])
+	}
+	gen := batch[1].(map[string]any)
+	usage := gen["body"].(map[string]any)["usage"].(map[string]any)
+	if gen["type"] != "generation-create" || usage["input"] != 100.0 || usage["total"] != 105.0 {
+		t.Errorf("generation event = %v", gen)
+	}
+	if span := batch[2].(map[string]any); span["type"] != "span-create" || span["body"].(map[string]any)["level"] != "ERROR" {
+		t.Errorf("span event = %v", span)
+	}
+
+	srv, _, _ = capture(t, http.StatusMultiStatus, `{"errors":[{"id":"x","status":400,"message":"bad usage"}]}`)
+	lf, _ = NewLangfuse(srv.URL, "pk", "sk", nil)
+	if err := lf.Export(context.Background(), testTrace()); err == nil || !strings.Contains(err.Error(), "bad usage") {
+		t.Errorf("rejected events: err = %v", err)
+	}
+	if _, err := NewLangfuse("", "pk", "", nil); err == nil {
+		t.Error("NewLangfuse accepted a missing secret key")
+	}
+}
+
+func TestLangSmith(t *testing.T) {
+	srv, req, body := capture(t, http.StatusAccepted, `{}`)
+	ls, err := NewLangSmith(srv.URL, "key", "support", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ls.Export(context.Background(), testTrace()); err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Path != "/runs/batch" || req.Header.Get("x-api-key") != "key" {
+		t.Errorf("request = %s %v", req.URL.Path, req.Header)
+	}
+	runs := (*body)["post"].([]any)
+	if len(runs) != 3 {
+		t.Fatalf("runs = %v", runs)
+	}
+	root := runs[0].(map[string]any)
+	rootID := UUIDFor("corr-1")
+	if root["id"] != rootID || root["run_type"] != "chain" || root["session_name"] != "support" {
+		t.Errorf("root run = %v", root)
+	}
+	if md := root["extra"].(map[string]any)["metadata"].(map[string]any); md["correlation_id"] != "corr-1" || md["session_id"] != "task-1" {
+		t.Errorf("root metadata = %v", md)
+	}
+	if got := root["dotted_order"]; got != "20261016T120000123456Z"+rootID {
+		t.Errorf("dotted_order = %v", got)
+	}
+	llmRun := runs[1].(map[string]any)
+	if llmRun["run_type"] != "llm" || llmRun["parent_run_id"] != rootID || !strings.HasPrefix(llmRun["dotted_order"].(string), root["dotted_order"].(string)+".") {
+		t.Errorf("llm run = %v", llmRun)
+	}
+	if tool := runs[2].(map[string]any); tool["run_type"] != "tool" || tool["error"] != "timeout" {
+		t.Errorf("tool run = %v", tool)
+	}
+
+	srv, _, _ = capture(t, http.StatusUnauthorized, `{"detail":"invalid key"}`)
+	ls, _ = NewLangSmith(srv.URL, "key", "", nil)
+	if err := ls.Export(context.Background(), testTrace()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("401: err = %v", err)
+	}
+}
+
+func TestUUIDFor(t *testing.T) {
+	a, b := UUIDFor("corr-1"), UUIDFor("corr-1")
+	if a != b || len(a) != 36 || a[14] != '5' || UUIDFor("corr-2") == a {
+		t.Errorf("UUIDFor = %s, %s", a, b)
+	}
+	if id := NewID(); len(id) != 36 || id[14] != '4' {
+		t.Errorf("NewID = %s", id)
+	}
+}
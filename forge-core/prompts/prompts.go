@@ -0,0 +1,229 @@
+// Package prompts renders the agent's system prompt from Go templates, so
+// prompt engineers can change it without rebuilding Forge.
+//
+// Templates are *.tmpl files under an agent's prompts/ directory, named by
+// their path without the extension: prompts/system.tmpl is "system",
+// prompts/partials/tone.tmpl is "partials/tone". They share one namespace,
+// so any template can include another with {{template "partials/tone" .}}.
+//
+//   - "system" renders the system prompt against Data. Without one the
+//     built-in template is used; an own one can still include it as
+//     "builtin".
+//   - "skills/<name>" replaces skill <name>'s line in the skill catalog.
+//     It renders against Skill.
+//
+// A template may begin with a version tag, {{/* version: 2026-10-01 */}}.
+// The system template's tag, with a digest of every template's source,
+// identifies the prompt in audit events.
+package prompts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// DefaultDir is the templates directory, relative to forge.yaml.
+const DefaultDir = "prompts"
+
+// RootTemplate renders the system prompt.
+const RootTemplate = "system"
+
+// BuiltinTemplate is the built-in system template, available to every set.
+const BuiltinTemplate = "builtin"
+
+// skillPrefix starts the names of per-skill catalog templates.
+const skillPrefix = "skills/"
+
+//go:embed system.tmpl
+var builtinSource string
+
+// Data is what the system template renders against. The sections are
+// the runtime's own, rendered already; empty ones are omitted by the
+// built-in template.
+type Data struct {
+	AgentID       string
+	Version       string
+	SkillCatalog  string // "## Available Skills" section
+	Scheduler     string // scheduling instructions
+	ChannelFormat string // reply formatting for the channels the agent serves
+}
+
+// Skill is what a skills/<name> template renders against.
+type Skill struct {
+	Name        string
+	Description string
+	Provides    []string // capabilities documented in the skill
+	UsesCLI     bool     // the skill runs binaries through cli_execute
+}
+
+// Set is a parsed templates directory. A nil *Set renders the built-in
+// template, so callers need no nil checks.
+type Set struct {
+	tmpl     *template.Template
+	versions map[string]string // template name → version tag ("" untagged)
+	digest   string
+}
+
+// versionTag matches a leading {{/* version: <tag> */}} comment, with or
+// without trim markers.
+var versionTag = regexp.MustCompile(`^\s*\{\{-?\s*/\*\s*version:\s*([^\s*]+)\s*\*/\s*-?\}\}`)
+
+var funcs = template.FuncMap{
+	"join": strings.Join,
+}
+
+var builtinSet = mustBuiltin()
+
+func mustBuiltin() *Set {
+	s, err := parse(nil)
+	if err != nil {
+		panic(fmt.Sprintf("prompts: built-in template: %v", err))
+	}
+	return s
+}
+
+// Load parses the templates under dir. A missing dir gives the built-in
+// set; a template that does not parse is an error naming its file.
+func Load(dir string) (*Set, error) {
+	sources := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".tmpl" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sources[filepath.ToSlash(strings.TrimSuffix(rel, ".tmpl"))] = string(data)
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return builtinSet, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sources[BuiltinTemplate]; ok {
+		return nil, fmt.Errorf("%s.tmpl: %q is reserved for the built-in template", BuiltinTemplate, BuiltinTemplate)
+	}
+	return parse(sources)
+}
+
+// parse builds a set from the built-in template and sources, by name.
+func parse(sources map[string]string) (*Set, error) {
+	s := &Set{
+		tmpl:     template.New(BuiltinTemplate).Funcs(funcs).Option("missingkey=error"),
+		versions: map[string]string{},
+	}
+	all := map[string]string{BuiltinTemplate: builtinSource}
+	for name, src := range sources {
+		all[name] = src
+	}
+	if _, ok := all[RootTemplate]; !ok {
+		all[RootTemplate] = `{{template "builtin" .}}`
+		s.versions[RootTemplate] = BuiltinTemplate
+	}
+
+	h := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(all)) {
+		src := all[name]
+		fmt.Fprintf(h, "%s\x00%s\x00", name, src)
+		if m := versionTag.FindStringSubmatch(src); m != nil {
+			s.versions[name] = m[1]
+		} else if _, ok := s.versions[name]; !ok {
+			s.versions[name] = ""
+		}
+		// Editors end files with a newline; the prompt shouldn't.
+		if _, err := s.tmpl.New(name).Parse(strings.TrimSuffix(src, "\n")); err != nil {
+			return nil, fmt.Errorf("%s.tmpl: %w", name, err)
+		}
+	}
+	s.digest = hex.EncodeToString(h.Sum(nil))
+	return s, nil
+}
+
+func (s *Set) set() *Set {
+	if s == nil {
+		return builtinSet
+	}
+	return s
+}
+
+// Render renders the system prompt.
+func (s *Set) Render(d Data) (string, error) {
+	return s.set().execute(RootTemplate, d)
+}
+
+// RenderTemplate renders the system prompt from template name instead
+// of "system", e.g. an experiment variant's prompt. It is an error when
+// the set has no such template.
+func (s *Set) RenderTemplate(name string, d Data) (string, error) {
+	s = s.set()
+	if s.tmpl.Lookup(name) == nil {
+		return "", fmt.Errorf("prompt template %q not found", name)
+	}
+	return s.execute(name, d)
+}
+
+// RenderSkill renders skill sk's catalog line from its skills/<name>
+// template. ok is false when the skill has none.
+func (s *Set) RenderSkill(sk Skill) (line string, ok bool, err error) {
+	s = s.set()
+	name := skillPrefix + sk.Name
+	if s.tmpl.Lookup(name) == nil {
+		return "", false, nil
+	}
+	line, err = s.execute(name, sk)
+	return line, true, err
+}
+
+func (s *Set) execute(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := s.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Version is the system template's version tag: "builtin" for the
+// built-in template, "" when the operator's is untagged.
+func (s *Set) Version() string {
+	return s.set().versions[RootTemplate]
+}
+
+// Digest is the hex SHA-256 of every template's name and source. It
+// changes whenever any template does, tagged or not.
+func (s *Set) Digest() string {
+	return s.set().digest
+}
+
+// Templates maps each template's name to its version tag, "" when
+// untagged. The built-in template is left out.
+func (s *Set) Templates() map[string]string {
+	out := map[string]string{}
+	for name, v := range s.set().versions {
+		if name != BuiltinTemplate {
+			out[name] = v
+		}
+	}
+	return out
+}
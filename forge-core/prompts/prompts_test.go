@@ -0,0 +1,133 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBuiltin(t *testing.T) {
+	set, err := Load(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		data Data
+		want string
+	}{
+		{Data{AgentID: "bot"}, "You are bot, an AI agent."},
+		{
+			Data{AgentID: "bot", SkillCatalog: "## Available Skills\n\n- a: b\n", ChannelFormat: "## Channel formatting"},
+			"You are bot, an AI agent.\n\n## Available Skills\n\n- a: b\n\n\n## Channel formatting",
+		},
+	} {
+		got, err := set.Render(c.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("Render = %q, want %q", got, c.want)
+		}
+	}
+	if set.Version() != "builtin" {
+		t.Errorf("Version = %q, want builtin", set.Version())
+	}
+	var nilSet *Set
+	if got, _ := nilSet.Render(Data{AgentID: "bot"}); got != "You are bot, an AI agent." {
+		t.Errorf("nil Set rendered %q", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"system.tmpl":         "{{/* version: 2026-10-01 */ -}}\n{{template \"builtin\" .}}\n\n{{template \"partials/tone\" .}}\n",
+		"partials/tone.tmpl":  "{{- /* version: 3 */ -}}\nBe brief, {{.AgentID}}.\n",
+		"skills/triage.tmpl":  "- {{.Name}}: {{.Description}} [{{join .Provides \"; \"}}]\n",
+		"notes.txt":           "not a template",
+		"skills/unused.tmpl":  "{{.Name}}",
+		"partials/empty.tmpl": "",
+	})
+	set, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := set.Render(Data{AgentID: "bot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "You are bot, an AI agent.\n\nBe brief, bot."; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+	line, ok, err := set.RenderSkill(Skill{Name: "triage", Description: "Triage pods.", Provides: []string{"logs", "events"}})
+	if err != nil || !ok || line != "- triage: Triage pods. [logs; events]" {
+		t.Errorf("RenderSkill = %q, %v, %v", line, ok, err)
+	}
+	if _, ok, _ := set.RenderSkill(Skill{Name: "other"}); ok {
+		t.Error("skill without a template was overridden")
+	}
+
+	if got, err := set.RenderTemplate("partials/tone", Data{AgentID: "bot"}); err != nil || got != "Be brief, bot." {
+		t.Errorf("RenderTemplate = %q, %v", got, err)
+	}
+	if _, err := set.RenderTemplate("variants/missing", Data{}); err == nil {
+		t.Error("RenderTemplate accepted a missing template")
+	}
+
+	if set.Version() != "2026-10-01" {
+		t.Errorf("Version = %q", set.Version())
+	}
+	tmpls := set.Templates()
+	if tmpls["partials/tone"] != "3" || tmpls["skills/triage"] != "" || len(tmpls) != 5 {
+		t.Errorf("Templates = %v", tmpls)
+	}
+
+	before := set.Digest()
+	if err := os.WriteFile(filepath.Join(dir, "partials", "tone.tmpl"), []byte("{{- /* version: 3 */ -}}\nBe terse.\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	edited, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Digest() == before {
+		t.Error("digest unchanged by an edit to an included template")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for name, files := range map[string]map[string]string{
+		"parse":    {"system.tmpl": "{{if .AgentID}}"},
+		"reserved": {"builtin.tmpl": "mine"},
+	} {
+		if _, err := Load(writeTemplates(t, files)); err == nil {
+			t.Errorf("%s: Load accepted %v", name, files)
+		}
+	}
+
+	set, err := Load(writeTemplates(t, map[string]string{"system.tmpl": "{{.AgentName}}"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := set.Render(Data{}); err == nil || !strings.Contains(err.Error(), "AgentName") {
+		t.Errorf("Render with an unknown field: err = %v", err)
+	}
+	if set.Version() != "" {
+		t.Errorf("untagged system template has version %q", set.Version())
+	}
+}
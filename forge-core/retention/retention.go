@@ -0,0 +1,177 @@
+// Package retention garbage-collects the artifacts an agent accumulates
+// on disk — session files, files tools create, task scratch directories —
+// under a per-category age limit and size quota.
+//
+// A category is one directory. Its top-level entries are the unit of
+// collection: a file, or a directory counted as the sum of its files and
+// as old as its newest one, so a scratch directory a task is still
+// writing to is never collected by age.
+package retention
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Removal reasons.
+const (
+	ReasonMaxAge   = "max_age"
+	ReasonMaxBytes = "max_bytes"
+	ReasonAll      = "all"
+)
+
+// Policy bounds one category.
+type Policy struct {
+	// MaxAge removes entries last modified longer ago. Zero keeps
+	// entries of any age.
+	MaxAge time.Duration
+	// MaxBytes removes the oldest entries until the category fits.
+	// Zero means no quota.
+	MaxBytes int64
+	// All removes every entry regardless of age or size, as
+	// `forge clean --all` does.
+	All bool
+}
+
+// Category is one kind of artifact and the directory holding it.
+type Category struct {
+	Name   string
+	Dir    string
+	Policy Policy
+}
+
+// Removal is one entry GC removed, or would remove in a dry run.
+type Removal struct {
+	Path    string    `json:"path"`
+	Bytes   int64     `json:"bytes"`
+	ModTime time.Time `json:"mod_time"`
+	Reason  string    `json:"reason"`
+}
+
+// CategoryResult is what GC found and removed in one category.
+type CategoryResult struct {
+	Category     string    `json:"category"`
+	Dir          string    `json:"dir"`
+	Entries      int       `json:"entries"` // before collection
+	Bytes        int64     `json:"bytes"`   // before collection
+	Removed      []Removal `json:"removed,omitempty"`
+	RemovedBytes int64     `json:"removed_bytes"`
+	Errors       []string  `json:"errors,omitempty"`
+}
+
+// Result reports a GC pass.
+type Result struct {
+	DryRun     bool             `json:"dry_run,omitempty"`
+	Categories []CategoryResult `json:"categories"`
+}
+
+// Removed returns how many entries the pass removed, and their size.
+func (r *Result) Removed() (entries int, bytes int64) {
+	for _, c := range r.Categories {
+		entries += len(c.Removed)
+		bytes += c.RemovedBytes
+	}
+	return entries, bytes
+}
+
+// Collect applies each category's policy as of now. Categories whose
+// directory does not exist are reported empty. A dry run reports the same
+// removals without deleting anything. Failures to read or remove an entry
+// are recorded in the category's Errors; they never stop the pass.
+func Collect(categories []Category, now time.Time, dryRun bool) *Result {
+	res := &Result{DryRun: dryRun}
+	for _, c := range categories {
+		res.Categories = append(res.Categories, collect(c, now, dryRun))
+	}
+	return res
+}
+
+type entry struct {
+	path    string
+	bytes   int64
+	modTime time.Time
+}
+
+func collect(c Category, now time.Time, dryRun bool) CategoryResult {
+	cr := CategoryResult{Category: c.Name, Dir: c.Dir}
+	entries, err := scan(c.Dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			cr.Errors = append(cr.Errors, err.Error())
+		}
+		return cr
+	}
+	for _, e := range entries {
+		cr.Entries++
+		cr.Bytes += e.bytes
+	}
+
+	// Oldest first, so the quota evicts from the front.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	kept := cr.Bytes
+	for _, e := range entries {
+		reason := ""
+		switch {
+		case c.Policy.All:
+			reason = ReasonAll
+		case c.Policy.MaxAge > 0 && now.Sub(e.modTime) > c.Policy.MaxAge:
+			reason = ReasonMaxAge
+		case c.Policy.MaxBytes > 0 && kept > c.Policy.MaxBytes:
+			reason = ReasonMaxBytes
+		default:
+			continue
+		}
+		if !dryRun {
+			if err := os.RemoveAll(e.path); err != nil {
+				cr.Errors = append(cr.Errors, err.Error())
+				continue
+			}
+		}
+		kept -= e.bytes
+		cr.Removed = append(cr.Removed, Removal{Path: e.path, Bytes: e.bytes, ModTime: e.modTime, Reason: reason})
+		cr.RemovedBytes += e.bytes
+	}
+	return cr
+}
+
+// scan lists dir's top-level entries with their size and newest
+// modification time.
+func scan(dir string) ([]entry, error) {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]entry, 0, len(des))
+	for _, de := range des {
+		p := filepath.Join(dir, de.Name())
+		info, err := de.Info()
+		if err != nil {
+			continue // removed since ReadDir
+		}
+		e := entry{path: p, bytes: info.Size(), modTime: info.ModTime()}
+		if de.IsDir() {
+			e.bytes = 0
+			_ = filepath.WalkDir(p, func(_ string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return nil // skip what vanished or can't be read
+				}
+				fi, err := d.Info()
+				if err != nil {
+					return nil
+				}
+				if fi.Mode().IsRegular() {
+					e.bytes += fi.Size()
+				}
+				if fi.ModTime().After(e.modTime) {
+					e.modTime = fi.ModTime()
+				}
+				return nil
+			})
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
@@ -0,0 +1,85 @@
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// touch writes size bytes to dir/name and dates it age before now.
+func touch(t *testing.T, dir, name string, size int, now time.Time, age time.Duration) {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, make([]byte, size), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mt := now.Add(-age)
+	if err := os.Chtimes(p, mt, mt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func removedPaths(cr CategoryResult) map[string]string {
+	out := map[string]string{}
+	for _, r := range cr.Removed {
+		out[filepath.Base(r.Path)] = r.Reason
+	}
+	return out
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	touch(t, dir, "old.json", 10, now, 10*24*time.Hour)
+	touch(t, dir, "mid.json", 40, now, 3*time.Hour)
+	touch(t, dir, "new.json", 40, now, time.Minute)
+	// A directory is as old as its newest file.
+	touch(t, dir, "task-a/stale.txt", 5, now, 30*24*time.Hour)
+	touch(t, dir, "task-a/fresh.txt", 5, now, time.Second)
+
+	cat := Category{Name: "sessions", Dir: dir, Policy: Policy{MaxAge: 7 * 24 * time.Hour, MaxBytes: 60}}
+
+	dry := Collect([]Category{cat}, now, true)
+	cr := dry.Categories[0]
+	if cr.Entries != 4 || cr.Bytes != 100 {
+		t.Fatalf("entries=%d bytes=%d, want 4 and 100", cr.Entries, cr.Bytes)
+	}
+	want := map[string]string{"old.json": ReasonMaxAge, "mid.json": ReasonMaxBytes}
+	if got := removedPaths(cr); len(got) != len(want) || got["old.json"] != want["old.json"] || got["mid.json"] != want["mid.json"] {
+		t.Fatalf("removed = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.json")); err != nil {
+		t.Fatalf("dry run removed a file: %v", err)
+	}
+
+	res := Collect([]Category{cat}, now, false)
+	if n, b := res.Removed(); n != 2 || b != 50 {
+		t.Fatalf("Removed() = %d, %d; want 2, 50", n, b)
+	}
+	left, _ := os.ReadDir(dir)
+	if len(left) != 2 {
+		t.Errorf("%d entries left, want new.json and task-a", len(left))
+	}
+}
+
+func TestCollectAllAndMissing(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	touch(t, dir, "a", 1, now, 0)
+	touch(t, dir, "b/c", 1, now, 0)
+
+	res := Collect([]Category{
+		{Name: "files", Dir: dir, Policy: Policy{All: true}},
+		{Name: "scratch", Dir: filepath.Join(dir, "missing")},
+	}, now, false)
+	if n, _ := res.Removed(); n != 2 {
+		t.Errorf("removed %d entries, want 2", n)
+	}
+	if miss := res.Categories[1]; miss.Entries != 0 || len(miss.Errors) != 0 {
+		t.Errorf("missing dir = %+v, want an empty result", miss)
+	}
+}
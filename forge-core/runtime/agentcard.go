@@ -49,6 +49,7 @@ func AgentCardFromSpec(spec *agentspec.AgentSpec, baseURL string) *a2a.AgentCard
 			Name:        t.Name,
 			Description: t.Description,
 			Tags:        []string{"tool"},
+			InputSchema: t.InputSchema,
 		})
 	}
 
@@ -78,6 +79,7 @@ func AgentCardFromSpec(spec *agentspec.AgentSpec, baseURL string) *a2a.AgentCard
 		}
 	}
 
+	populateTransports(card)
 	return card
 }
 
@@ -105,6 +107,7 @@ func AgentCardFromConfig(cfg *types.ForgeConfig, baseURL string) *a2a.AgentCard
 		})
 	}
 
+	populateTransports(card)
 	return card
 }
 
@@ -0,0 +1,123 @@
+package runtime
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// RateLimitExtensionURI identifies the Agent Card extension that
+// advertises the A2A server's request rate limits. A2A 0.3.0 has no
+// rate-limit field, so Forge publishes them as a capability extension
+// clients can read before they start calling.
+const RateLimitExtensionURI = "https://github.com/initializ/forge/blob/main/docs/reference/a2a-agent-card.md#rate-limits"
+
+// ReadOnlyExtensionURI identifies the Agent Card extension that marks
+// an agent running in `mode: read-only`.
+const ReadOnlyExtensionURI = "https://github.com/initializ/forge/blob/main/docs/reference/a2a-agent-card.md#read-only-mode"
+
+// RateLimitPolicy is the rate-limit shape advertised on the card. It
+// mirrors the server's RateLimitConfig without importing it.
+type RateLimitPolicy struct {
+	ReadRPS      float64
+	ReadBurst    int
+	WriteRPS     float64
+	WriteBurst   int
+	CancelExempt bool
+}
+
+// populateTransports advertises the protocol bindings every Forge agent
+// serves at its base URL: JSON-RPC 2.0, with Server-Sent Events
+// streaming through tasks/sendSubscribe. Forge's REST routes
+// (/tasks/send, /tasks/sendSubscribe) predate the A2A HTTP+JSON binding
+// and don't follow its paths, so they are not advertised as that
+// transport; there is no gRPC or WebSocket binding.
+func populateTransports(card *a2a.AgentCard) {
+	card.PreferredTransport = a2a.TransportJSONRPC
+	card.AdditionalInterfaces = []a2a.AgentInterface{
+		{URL: card.URL, Transport: a2a.TransportJSONRPC},
+	}
+	if card.Capabilities == nil {
+		card.Capabilities = &a2a.AgentCapabilities{}
+	}
+	card.Capabilities.Streaming = true
+}
+
+// PopulateToolSchemas sets the input schema of every tool-tagged skill
+// that doesn't carry one yet, using schemaFor (typically a lookup in
+// the runtime tool registry). Skills schemaFor returns nothing for are
+// left alone.
+func PopulateToolSchemas(card *a2a.AgentCard, schemaFor func(name string) json.RawMessage) {
+	if card == nil || schemaFor == nil {
+		return
+	}
+	for i := range card.Skills {
+		s := &card.Skills[i]
+		if len(s.InputSchema) > 0 || !slices.Contains(s.Tags, "tool") {
+			continue
+		}
+		if schema := schemaFor(s.ID); len(schema) > 0 && json.Valid(schema) {
+			s.InputSchema = schema
+		}
+	}
+}
+
+// AdvertiseRateLimit records p on the card as the rate-limit capability
+// extension, replacing any previous advertisement. Limits apply per
+// client IP; reads are GET/HEAD/OPTIONS, writes everything else.
+func AdvertiseRateLimit(card *a2a.AgentCard, p RateLimitPolicy) {
+	if card == nil {
+		return
+	}
+	ext := a2a.AgentExtension{
+		URI:         RateLimitExtensionURI,
+		Description: "Per-client-IP token-bucket request limits. Over-limit requests get HTTP 429.",
+		Params: map[string]any{
+			"scope":         "client_ip",
+			"read_rps":      p.ReadRPS,
+			"read_burst":    p.ReadBurst,
+			"write_rps":     p.WriteRPS,
+			"write_burst":   p.WriteBurst,
+			"cancel_exempt": p.CancelExempt,
+		},
+	}
+	setExtension(card, ext)
+}
+
+// AdvertiseReadOnly records on the card that the agent is read-only:
+// its builtin tools have no side effects, http_request may only GET,
+// and cli_execute runs only safeCommands, so a client that needs to
+// change something can tell before it calls.
+func AdvertiseReadOnly(card *a2a.AgentCard, safeCommands []string) {
+	if card == nil {
+		return
+	}
+	if safeCommands == nil {
+		safeCommands = []string{}
+	}
+	setExtension(card, a2a.AgentExtension{
+		URI:         ReadOnlyExtensionURI,
+		Description: "Read-only agent: tools that change anything are disabled.",
+		Params: map[string]any{
+			"mode":          "read-only",
+			"http_methods":  []string{"GET"},
+			"safe_commands": safeCommands,
+		},
+	})
+}
+
+// setExtension adds ext to the card's capabilities, replacing any
+// extension with the same URI.
+func setExtension(card *a2a.AgentCard, ext a2a.AgentExtension) {
+	if card.Capabilities == nil {
+		card.Capabilities = &a2a.AgentCapabilities{}
+	}
+	exts := card.Capabilities.Extensions[:0:0]
+	for _, e := range card.Capabilities.Extensions {
+		if e.URI != ext.URI {
+			exts = append(exts, e)
+		}
+	}
+	card.Capabilities.Extensions = append(exts, ext)
+}
@@ -130,3 +130,26 @@ func trimTrailingSlash(s string) string {
 	}
 	return s
 }
+
+// RuntimeTokenSchemeName is the card's scheme name for the bearer token
+// `forge run` mints when no auth chain is configured.
+const RuntimeTokenSchemeName = "forge_runtime_token"
+
+// PopulateRuntimeTokenScheme advertises the runtime's own bearer token
+// on a card that carries no other scheme. Without an auth: chain the
+// server still requires the token `forge run` mints at startup (unless
+// started with --no-auth), so an empty security list would wrongly
+// tell callers no credentials are needed.
+func PopulateRuntimeTokenScheme(card *a2a.AgentCard) {
+	if card == nil || len(card.SecuritySchemes) > 0 {
+		return
+	}
+	card.SecuritySchemes = map[string]*a2a.SecurityScheme{
+		RuntimeTokenSchemeName: {
+			Type:        "http",
+			Scheme:      "bearer",
+			Description: "Bearer token given to forge run with --auth-token, or minted at startup and stored in the agent's .forge directory.",
+		},
+	}
+	card.Security = append(card.Security, map[string][]string{RuntimeTokenSchemeName: {}})
+}
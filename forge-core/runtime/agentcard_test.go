@@ -196,9 +196,10 @@ func TestAgentCardJSON_OmitsNilFields(t *testing.T) {
 	}
 	js := string(raw)
 
-	// Should NOT contain capabilities (nil pointer) or securitySchemes
-	// (nil map). Confirms omitempty is wired right.
-	for _, forbidden := range []string{`"capabilities"`, `"securitySchemes"`, `"provider"`, `"documentationUrl"`, `"iconUrl"`} {
+	// Should NOT contain securitySchemes (nil map) or the other unset
+	// optional fields. Confirms omitempty is wired right. Capabilities
+	// are always present: every card advertises streaming.
+	for _, forbidden := range []string{`"securitySchemes"`, `"provider"`, `"documentationUrl"`, `"iconUrl"`} {
 		if containsField(js, forbidden) {
 			t.Errorf("nil/empty optional field %s should be omitted, got:\n%s", forbidden, js)
 		}
@@ -231,3 +232,80 @@ func jsonHas(js, key string) bool {
 	}
 	return false
 }
+
+func TestAgentCard_AdvertisesTransportAndStreaming(t *testing.T) {
+	card := AgentCardFromSpec(&agentspec.AgentSpec{AgentID: "a"}, "http://localhost:8080")
+	if card.PreferredTransport != a2a.TransportJSONRPC {
+		t.Errorf("PreferredTransport = %q", card.PreferredTransport)
+	}
+	if len(card.AdditionalInterfaces) != 1 || card.AdditionalInterfaces[0] != (a2a.AgentInterface{URL: card.URL, Transport: a2a.TransportJSONRPC}) {
+		t.Errorf("AdditionalInterfaces = %+v", card.AdditionalInterfaces)
+	}
+	if card.Capabilities == nil || !card.Capabilities.Streaming {
+		t.Errorf("Capabilities = %+v, want streaming", card.Capabilities)
+	}
+}
+
+func TestAgentCard_ToolSkillSchemas(t *testing.T) {
+	schema := json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`)
+	spec := &agentspec.AgentSpec{AgentID: "a", Tools: []agentspec.ToolSpec{{Name: "weather", InputSchema: schema}}}
+	card := AgentCardFromSpec(spec, "http://localhost:8080")
+	if string(card.Skills[0].InputSchema) != string(schema) {
+		t.Errorf("spec tool schema = %s", card.Skills[0].InputSchema)
+	}
+
+	card = AgentCardFromConfig(&types.ForgeConfig{AgentID: "a", Tools: []types.ToolRef{{Name: "web_search"}}}, "http://localhost:8080")
+	card.Skills = append(card.Skills, a2a.Skill{ID: "summarize", Name: "summarize", Tags: []string{"skill"}})
+	PopulateToolSchemas(card, func(name string) json.RawMessage {
+		return json.RawMessage(`{"type":"object","title":"` + name + `"}`)
+	})
+	if got := string(card.Skills[0].InputSchema); got != `{"type":"object","title":"web_search"}` {
+		t.Errorf("registry tool schema = %s", got)
+	}
+	if card.Skills[1].InputSchema != nil {
+		t.Errorf("non-tool skill got a schema: %s", card.Skills[1].InputSchema)
+	}
+}
+
+func TestAdvertiseRateLimit_ReplacesPrevious(t *testing.T) {
+	card := AgentCardFromConfig(&types.ForgeConfig{AgentID: "a"}, "http://localhost:8080")
+	AdvertiseRateLimit(card, RateLimitPolicy{ReadRPS: 1, ReadBurst: 10, WriteRPS: 1, WriteBurst: 20, CancelExempt: true})
+	AdvertiseRateLimit(card, RateLimitPolicy{ReadRPS: 5, ReadBurst: 50, WriteRPS: 2, WriteBurst: 4})
+	exts := card.Capabilities.Extensions
+	if len(exts) != 1 || exts[0].URI != RateLimitExtensionURI {
+		t.Fatalf("extensions = %+v", exts)
+	}
+	if exts[0].Params["write_burst"] != 4 || exts[0].Params["cancel_exempt"] != false {
+		t.Errorf("params = %+v", exts[0].Params)
+	}
+}
+
+func TestAdvertiseReadOnly(t *testing.T) {
+	card := AgentCardFromConfig(&types.ForgeConfig{AgentID: "a"}, "http://localhost:8080")
+	AdvertiseRateLimit(card, RateLimitPolicy{ReadRPS: 1})
+	AdvertiseReadOnly(card, []string{"kubectl get"})
+	exts := card.Capabilities.Extensions
+	if len(exts) != 2 || exts[1].URI != ReadOnlyExtensionURI {
+		t.Fatalf("extensions = %+v", exts)
+	}
+	if cmds, _ := exts[1].Params["safe_commands"].([]string); len(cmds) != 1 || cmds[0] != "kubectl get" {
+		t.Errorf("params = %+v", exts[1].Params)
+	}
+}
+
+func TestPopulateRuntimeTokenScheme(t *testing.T) {
+	card := AgentCardFromConfig(&types.ForgeConfig{AgentID: "a"}, "http://localhost:8080")
+	PopulateRuntimeTokenScheme(card)
+	if s := card.SecuritySchemes[RuntimeTokenSchemeName]; s == nil || s.Scheme != "bearer" || len(card.Security) != 1 {
+		t.Fatalf("schemes = %+v, security = %+v", card.SecuritySchemes, card.Security)
+	}
+
+	// A configured chain is advertised instead.
+	cfg := &types.ForgeConfig{AgentID: "a", Auth: types.AuthConfig{Providers: []types.AuthProvider{{Type: "static_token"}}}}
+	card = AgentCardFromConfig(cfg, "http://localhost:8080")
+	PopulateSecuritySchemes(card, cfg)
+	PopulateRuntimeTokenScheme(card)
+	if _, ok := card.SecuritySchemes[RuntimeTokenSchemeName]; ok || len(card.Security) != 1 {
+		t.Errorf("schemes = %+v", card.SecuritySchemes)
+	}
+}
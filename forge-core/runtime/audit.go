@@ -91,6 +91,12 @@ const (
 	// and the A2A 0.3.0 spec.
 	EventAgentCardPublished = "agent_card_published"
 
+	// EventPromptLoaded is emitted at startup and whenever the prompt
+	// templates change, with the system template's prompt_version tag,
+	// a prompt_sha256 digest of every template and the templates' own
+	// tags. session_start carries prompt_version and prompt_sha256 too.
+	EventPromptLoaded = "prompt_loaded"
+
 	// Lifecycle events emitted at A2A invocation boundaries.
 	// AuditInvocationComplete carries total wall-clock duration_ms for
 	// the full invocation (auth → dispatch → engine.Execute → response).
@@ -231,6 +237,221 @@ const (
 	// 429). See docs/security/admission.md.
 	AuditTaskAdmissionDenied = "task_admission_denied"
 
+	// AuditSessionUndo is emitted when tasks/undo (or the `/undo` chat
+	// command) rolls a task's conversation back by one exchange.
+	// Carries Fields["removed_messages"] (messages dropped from the
+	// session) and Fields["tool_calls"] (tool calls the reverted
+	// exchange made, side-effecting or not). One per undo; each
+	// side-effecting call additionally gets its own tool_disavowed
+	// event.
+	AuditSessionUndo = "session_undo"
+
+	// AuditSessionCompacted is emitted when POST /sessions/{id}/compact
+	// or the compact_now tool forces a compaction. Fields:
+	//   - source           : "api" or "tool"
+	//   - compacted        : false when the conversation was too short
+	//   - removed_messages : messages folded into the summary
+	//   - chars_before / chars_after : context size around it
+	//   - actor            : caller email or user ID for API calls
+	AuditSessionCompacted = "session_compacted"
+
+	// AuditMemoryGC is emitted when a scheduled memory GC pass archives
+	// long-term memories. Fields:
+	//   - archived_notes / archived_logs : memories moved to the archive
+	//   - archive                        : archive file under memory_dir
+	AuditMemoryGC = "memory_gc"
+
+	// AuditArtifactGC is emitted when artifact GC (forge.yaml
+	// retention) removes session files, created files or task scratch
+	// directories. Fields:
+	//   - removed       : entries removed, by category
+	//   - removed_bytes : their total size
+	AuditArtifactGC = "artifact_gc"
+
+	// AuditToolDisavowed marks a side-effecting tool call from an
+	// undone exchange. The original tool_exec events are immutable
+	// (hash-chained), so this event is the retraction record: consumers
+	// join it to the call on (task_id, fields.tool_call_id). Read-only
+	// tools (tools.ReadOnly) are never disavowed. Fields:
+	//
+	//   - tool         : tool name
+	//   - tool_call_id : the LLM-assigned call ID
+	//   - compensation : "applied" (the tool's compensation hook
+	//                    reversed the effect), "none" (hook ran, nothing
+	//                    to reverse), "failed" (hook errored; see
+	//                    fields.error), "unsupported" (the tool has no
+	//                    hook), or "skipped" (caller did not ask for
+	//                    compensation)
+	//   - detail       : the hook's description of what it reversed
+	AuditToolDisavowed = "tool_disavowed"
+
+	// AuditMessageRedacted is the tombstone for a message removed from
+	// a conversation by tasks/redactMessage or tasks/deleteMessage. It
+	// never carries the removed content. Fields:
+	//
+	//   - action           : "redact" or "delete"
+	//   - scope            : "message" (whole message) or "text" (a
+	//                        substring, also scrubbed wherever the
+	//                        conversation repeated it)
+	//   - message_index    : position in the task history
+	//   - role             : "user" or "agent"
+	//   - session_messages : persisted session messages edited, or -1
+	//                        when the task has no persisted session
+	//   - scrubbed         : other session messages the text was
+	//                        removed from
+	//   - summary          : compaction summary outcome — "unchanged",
+	//                        "scrubbed", or "dropped"
+	AuditMessageRedacted = "message_redacted"
+
+	// AuditConfigReloaded is emitted when a running server re-reads
+	// forge.yaml on SIGHUP (`forge serve reload`). Fields:
+	//
+	//   - applied        : components swapped in — "model",
+	//                      "guardrails", "egress"
+	//   - failed         : component → error for each component that
+	//                      kept its previous configuration
+	//   - model          : provider/model now serving, when applied
+	//   - egress_mode    : egress mode now enforced, when applied
+	//   - egress_domains : allowlist size now enforced, when applied
+	//
+	// A reload the platform policy rejects changes nothing and carries
+	// only failed["policy"].
+	AuditConfigReloaded = "config_reloaded"
+
+	// AuditModelSwitched is emitted when POST /admin/model or the
+	// model_switch tool changes the serving model, or is refused.
+	// Fields:
+	//
+	//   - source  : "api" or "tool"
+	//   - outcome : "switched" or "rejected"
+	//   - before  : provider/model serving before
+	//   - after   : provider/model serving now, when switched
+	//   - request : the requested provider, model and fallbacks
+	//   - reason  : the caller's stated reason, when given
+	//   - actor   : caller email or user ID for API calls
+	//   - error   : why a rejected switch was refused
+	AuditModelSwitched = "model_switched"
+
+	// AuditLogSettingsChanged is emitted when PUT /admin/logging changes
+	// ops-log levels or sampling at runtime. Lowering verbosity is how a
+	// noisy subsystem gets quieted — and also how evidence could be
+	// suppressed — so every change is recorded. Fields["before"] and
+	// Fields["after"] carry the full LogSettings snapshots.
+	AuditLogSettingsChanged = "log_settings_changed"
+
+	// AuditLLMCircuit is emitted when a fallback candidate's circuit
+	// breaker changes state (closed → open on a failure-rate trip,
+	// open → half_open when a probe starts, half_open → closed/open on
+	// the probe's outcome). Model and Provider name the candidate;
+	// Fields carry from, to, reason, and — when the circuit opens —
+	// failure_rate and open_seconds.
+	AuditLLMCircuit = "llm_circuit"
+
+	// AuditOPADecision is emitted when the OPA decision point
+	// (security.opa) denies an action or fails to evaluate it. Allowed
+	// actions emit nothing here; their tool_exec / egress_allowed
+	// events already record them. Fields:
+	//
+	//   - kind      : "tool", "egress" or "schedule"
+	//   - tool / domain / schedule_id : the action's subject
+	//   - allow     : the outcome actually applied
+	//   - reason    : the policy's reason, when it gave one
+	//   - error     : evaluation failure, when OPA could not decide;
+	//                 allow then reflects security.opa.fail_open
+	AuditOPADecision = "opa_decision"
+
+	// AuditNotify is emitted for each proactive message sent, or
+	// refused, through the notify tool or POST /notify. Fields:
+	//
+	//   - target  : the notify target name
+	//   - channel : the target's adapter
+	//   - source  : "tool", "api" or "alert"
+	//   - outcome : "sent", "rate_limited" or "failed"
+	//   - actor   : the API caller, for source "api"
+	//   - error   : the delivery failure, for outcome "failed"
+	AuditNotify = "notify"
+
+	// AuditHandoff is emitted when a conversation is handed to a human
+	// operator, for each message forwarded to the operators while it is,
+	// and when it is handed back. The task_id is the conversation's
+	// session. Fields:
+	//
+	//   - action  : "start", "forward" or "resume"
+	//   - source  : "tool", "chat" or "api"
+	//   - reason  : the agent's reason, for "start"
+	//   - actor   : the API caller, for source "api"
+	//   - error   : the delivery failure, when the operators could not
+	//               be reached
+	AuditHandoff = "handoff"
+
+	// AuditWebhookTrigger is emitted for each request to a forge.yaml
+	// webhook trigger. The task_id is the task an accepted request
+	// started. Fields:
+	//
+	//   - trigger : the trigger ID
+	//   - outcome : "accepted", "rejected" (bad signature) or "invalid"
+	//               (the task template could not render)
+	//   - error   : why the request was refused
+	AuditWebhookTrigger = "webhook_trigger"
+
+	// AuditAlert is emitted when a forge.yaml alert rule fires, repeats
+	// after its cooldown, or resolves. Fields:
+	//
+	//   - rule          : the rule ID
+	//   - metric        : the metric the rule watches
+	//   - state         : "firing" or "resolved"
+	//   - value         : the metric's value at evaluation
+	//   - threshold     : the rule's threshold
+	//   - notify_error  : why the notify target could not be messaged
+	//   - webhook_error : why the webhook delivery failed
+	AuditAlert = "alert"
+
+	// AuditSandboxViolation is emitted when a tool refuses a path that
+	// resolves, directly or through a symlink, outside the directory the
+	// tool is confined to: the agent working directory, or the task
+	// scratch directory for a sandboxed tool. Fields:
+	//
+	//   - tool  : the tool that refused the path
+	//   - path  : the path as the model gave it
+	//   - root  : the directory it had to stay in
+	//   - scope : "working directory", "agent working directory" or
+	//             "task sandbox"
+	AuditSandboxViolation = "sandbox_violation"
+
+	// AuditLoopDetected is emitted when forge.yaml loop limits stop a
+	// task whose agent loop kept repeating itself. Fields carry the
+	// failure's details:
+	//   - kind      : "repeated_call" or "oscillation"
+	//   - tools     : the repeated tool, or the two alternating ones
+	//   - args      : the repeated call's arguments (repeated_call only)
+	//   - count     : repeats, or completed cycles
+	//   - iteration : loop iteration it was detected in
+	AuditLoopDetected = "loop_detected"
+
+	// AuditWorkflowStep is emitted for each step a forge.yaml workflow
+	// run reaches. The task_id is the run ID. Fields:
+	//
+	//   - workflow    : the workflow ID
+	//   - step        : the step ID; a fanned-out step is "<parent>.<id>"
+	//   - kind        : "tool", "prompt" or "parallel"
+	//   - tool        : the tool called, for kind "tool"
+	//   - status      : "ok", "skipped" or "failed"
+	//   - duration_ms : how long the step took
+	//   - error       : why the step failed
+	AuditWorkflowStep = "workflow_step"
+
+	// AuditWorkflowRun is emitted when a workflow run finishes. The
+	// task_id is the run ID. Fields:
+	//
+	//   - workflow    : the workflow ID
+	//   - trigger     : what started it: "schedule:<id>", "webhook:<id>"
+	//                   or "tool"
+	//   - status      : "completed" or "failed"
+	//   - steps       : steps reached
+	//   - duration_ms : how long the run took
+	//   - error       : why the run failed
+	AuditWorkflowRun = "workflow_run"
+
 	// Deprecated: use EventAuthVerify. Kept as a string alias so any
 	// audit-log consumer that grep'd for "auth_success" can be migrated.
 	// Scheduled for removal in v0.11.0.
@@ -385,6 +606,17 @@ type AuditEvent struct {
 	// or upstream agent in an agent-to-agent flow).
 	InvocationCaller string `json:"invocation_caller,omitempty"`
 
+	// Channel, ChannelEventID and ChannelMessageID identify the
+	// channel message that triggered this invocation — the adapter
+	// name, the platform's delivery ID (Slack event_id, Telegram
+	// update_id) and the message ID (Slack ts). Sourced from the
+	// X-Forge-Channel* headers the channel router sets; absent for
+	// invocations that did not come through a channel. A SIEM joins on
+	// them to go from a platform delivery to every event it caused.
+	Channel          string `json:"channel,omitempty"`
+	ChannelEventID   string `json:"channel_event_id,omitempty"`
+	ChannelMessageID string `json:"channel_message_id,omitempty"`
+
 	// OrgID + WorkspaceID stamp the tenancy this agent run belongs
 	// to. Sourced from one of three layers (highest precedence first):
 	//
@@ -407,6 +639,13 @@ type AuditEvent struct {
 	// trusted because the deployment / orchestrator set it.
 	OrgID       string `json:"org_id,omitempty"`
 	WorkspaceID string `json:"workspace_id,omitempty"`
+	// TenantID is the forge.yaml tenant the event's task ran for,
+	// stamped from the context (WithTenant). Omitted outside a tenant.
+	TenantID string `json:"tenant_id,omitempty"`
+	// Experiment is the "<experiment>/<variant>" the event's task ran
+	// with, stamped from the context (WithExperiment). Omitted outside
+	// an experiment.
+	Experiment string `json:"experiment,omitempty"`
 
 	// EntityID + EntityType identify which entity emitted this event.
 	// Sourced from two layers (highest precedence first):
@@ -445,6 +684,9 @@ type AuditEvent struct {
 	InputTokens       *int `json:"input_tokens,omitempty"`
 	OutputTokens      *int `json:"output_tokens,omitempty"`
 	TokensUnavailable bool `json:"tokens_unavailable,omitempty"`
+	// ReasoningTokens is the part of OutputTokens a reasoning model spent
+	// thinking. Nil unless the provider reported a non-zero count.
+	ReasoningTokens *int `json:"reasoning_tokens,omitempty"`
 
 	// DurationMs is the wall-clock duration in milliseconds. Populated on
 	// llm_call, tool_exec, and invocation_complete events.
@@ -898,6 +1140,10 @@ func (a *AuditLogger) EmitFromContext(ctx context.Context, event AuditEvent) {
 			event.InvocationCaller = wc.InvocationCaller
 		}
 	}
+	if event.Channel == "" && event.ChannelEventID == "" && event.ChannelMessageID == "" {
+		cc := ChannelContextFromContext(ctx)
+		event.Channel, event.ChannelEventID, event.ChannelMessageID = cc.Channel, cc.EventID, cc.MessageID
+	}
 	// Per-invocation sequence number: pulled from the counter the A2A
 	// handler put on the context at request entry. NextSequence is
 	// atomic + returns 0 when no counter is present (startup banner
@@ -949,6 +1195,12 @@ func (a *AuditLogger) EmitFromContext(ctx context.Context, event AuditEvent) {
 			}
 		}
 	}
+	if event.TenantID == "" {
+		event.TenantID = TenantFromContext(ctx)
+	}
+	if a := ExperimentFromContext(ctx); a != nil && event.Experiment == "" {
+		event.Experiment = a.String()
+	}
 	a.Emit(event)
 }
 
@@ -1001,6 +1253,9 @@ type LLMUsage struct {
 	InputTokens  int
 	OutputTokens int
 	TotalTokens  int
+	// ReasoningTokens is included in OutputTokens (see
+	// llm.UsageInfo.ReasoningTokens).
+	ReasoningTokens int
 }
 
 // EmitLLMCall builds and emits an llm_call (or llm_call_cancelled)
@@ -1035,6 +1290,9 @@ func (a *AuditLogger) EmitLLMCall(ctx context.Context, args LLMCallAuditArgs) {
 	if in == 0 && out == 0 {
 		evt.TokensUnavailable = true
 	}
+	if r := args.Usage.ReasoningTokens; r > 0 {
+		evt.ReasoningTokens = &r
+	}
 	d := args.Duration.Milliseconds()
 	evt.DurationMs = &d
 	if len(args.Fields) > 0 {
@@ -55,6 +55,31 @@ func TestEmitLLMCall_FullUsage(t *testing.T) {
 	if evt.DurationMs == nil || *evt.DurationMs != 120 {
 		t.Errorf("DurationMs want 120, got %v", evt.DurationMs)
 	}
+	if evt.ReasoningTokens != nil {
+		t.Errorf("ReasoningTokens should be omitted when zero, got %v", *evt.ReasoningTokens)
+	}
+}
+
+func TestEmitLLMCall_ReasoningTokens(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLogger(&buf)
+
+	audit.EmitLLMCall(context.Background(), LLMCallAuditArgs{
+		Model:    "grok-3-mini",
+		Provider: "xai",
+		Usage:    LLMUsage{InputTokens: 10, OutputTokens: 102, TotalTokens: 112, ReasoningTokens: 100},
+	})
+
+	if !strings.Contains(buf.String(), `"reasoning_tokens":100`) {
+		t.Fatalf("reasoning_tokens missing: %s", buf.String())
+	}
+	var evt AuditEvent
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &evt); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if evt.OutputTokens == nil || *evt.OutputTokens != 102 {
+		t.Errorf("OutputTokens want 102 (reasoning included), got %v", evt.OutputTokens)
+	}
 }
 
 func TestEmitLLMCall_TokensUnavailable_OllamaMissingUsage(t *testing.T) {
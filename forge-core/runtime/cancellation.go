@@ -38,6 +38,11 @@ const (
 	// cancel, debugging stop, anything else not covered by the more
 	// specific reasons.
 	CancelReasonExternalSignal CancellationReason = "external_signal"
+
+	// CancelReasonShutdown is set by the runtime itself when a
+	// graceful shutdown's drain period ends with the invocation still
+	// running. Internal only: tasks/cancel callers cannot supply it.
+	CancelReasonShutdown CancellationReason = "shutdown"
 )
 
 // IsValid reports whether r is one of the documented reason values.
@@ -167,6 +172,22 @@ func (r *CancellationRegistry) Cancel(taskID string, reason CancellationReason)
 	return true
 }
 
+// CancelAll signals every in-flight invocation with reason and returns
+// how many were signalled. Used when a graceful shutdown's drain
+// period runs out.
+func (r *CancellationRegistry) CancelAll(reason CancellationReason) int {
+	r.mu.Lock()
+	entries := make([]*registryEntry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	r.mu.Unlock()
+	for _, e := range entries {
+		e.cancel(&cancelledByOrchestrator{Reason: reason})
+	}
+	return len(entries)
+}
+
 // Len returns the number of in-flight registrations. Exposed for
 // tests and operational observability — there is no per-task lookup
 // API by design (the handler only needs Cancel; the executeTask
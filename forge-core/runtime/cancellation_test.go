@@ -49,6 +49,26 @@ func TestCancellationRegistry_CancelUnknownTaskIsIdempotent(t *testing.T) {
 	}
 }
 
+func TestCancellationRegistry_CancelAll(t *testing.T) {
+	reg := NewCancellationRegistry()
+	ctx1, cancel1 := context.WithCancelCause(context.Background())
+	ctx2, cancel2 := context.WithCancelCause(context.Background())
+	defer reg.Register("a", cancel1)()
+	defer reg.Register("b", cancel2)()
+
+	if n := reg.CancelAll(CancelReasonShutdown); n != 2 {
+		t.Errorf("CancelAll = %d, want 2", n)
+	}
+	for _, ctx := range []context.Context{ctx1, ctx2} {
+		if got := CancellationReasonFromCause(ctx); got != CancelReasonShutdown {
+			t.Errorf("reason = %q, want shutdown", got)
+		}
+	}
+	if CancelReasonShutdown.IsValid() {
+		t.Error("shutdown must not be accepted from tasks/cancel callers")
+	}
+}
+
 func TestCancellationReasonFromCause_RoundTripWithCancelCause(t *testing.T) {
 	// Reason flows from handler → registry → CancelCauseFunc →
 	// context.Cause → CancellationReasonFromCause. The test simulates
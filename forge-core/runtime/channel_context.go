@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// Channel header names. The channel router sets them on the internal
+// tasks/send it issues for every inbound Slack / Telegram / Teams
+// message, so the audit events of that invocation can be joined back
+// to the platform's own delivery record. X-Forge-Channel doubles as
+// the on-behalf-of adapter name the auth middleware reads.
+const (
+	HeaderForgeChannel          = "X-Forge-Channel"
+	HeaderForgeChannelEventID   = "X-Forge-Channel-Event-ID"
+	HeaderForgeChannelMessageID = "X-Forge-Channel-Message-ID"
+)
+
+// HeaderForgeCorrelationID carries the invocation's correlation ID on
+// A2A responses, so a caller can find the audit events and spans of
+// the run it just triggered.
+const HeaderForgeCorrelationID = "X-Forge-Correlation-Id"
+
+// ChannelContext identifies the channel message that triggered an
+// invocation. EventID is the platform's delivery ID (Slack event_id,
+// Telegram update_id); MessageID is the message
+// itself (Slack ts, Telegram message_id). Like WorkflowContext these
+// are correlation metadata, not identity: they tag audit events and
+// are never used for an authorization decision.
+type ChannelContext struct {
+	Channel   string
+	EventID   string
+	MessageID string
+}
+
+// IsZero reports whether the ChannelContext carries nothing — the
+// invocation did not come through a channel adapter.
+func (c ChannelContext) IsZero() bool {
+	return c.Channel == "" && c.EventID == "" && c.MessageID == ""
+}
+
+// ChannelContextFromHTTPHeaders extracts the X-Forge-Channel* headers
+// from an inbound request. Mirrors WorkflowContextFromHTTPHeaders.
+func ChannelContextFromHTTPHeaders(h http.Header) ChannelContext {
+	return ChannelContext{
+		Channel:   h.Get(HeaderForgeChannel),
+		EventID:   h.Get(HeaderForgeChannelEventID),
+		MessageID: h.Get(HeaderForgeChannelMessageID),
+	}
+}
+
+// ApplyToHTTPHeaders writes any non-empty ChannelContext fields onto
+// outbound request headers. Used by the channel router.
+func (c ChannelContext) ApplyToHTTPHeaders(h http.Header) {
+	if c.Channel != "" {
+		h.Set(HeaderForgeChannel, c.Channel)
+	}
+	if c.EventID != "" {
+		h.Set(HeaderForgeChannelEventID, c.EventID)
+	}
+	if c.MessageID != "" {
+		h.Set(HeaderForgeChannelMessageID, c.MessageID)
+	}
+}
+
+type channelContextKey struct{}
+
+// WithChannelContext stores a ChannelContext in the request context.
+// Installed at the A2A request boundary next to the workflow and
+// tenancy contexts.
+func WithChannelContext(ctx context.Context, c ChannelContext) context.Context {
+	return context.WithValue(ctx, channelContextKey{}, c)
+}
+
+// ChannelContextFromContext retrieves the ChannelContext from the
+// context. Returns the zero value when none was set.
+func ChannelContextFromContext(ctx context.Context) ChannelContext {
+	if c, ok := ctx.Value(channelContextKey{}).(ChannelContext); ok {
+		return c
+	}
+	return ChannelContext{}
+}
+
+// HeaderForgeOrigin lets a trusted front end name the origin of the
+// tasks it submits: forge ui sends "ui" for the dashboard chat and
+// "channel" for the public web chat widget.
+const HeaderForgeOrigin = "X-Forge-Origin"
+
+// RequestOrigin resolves the task origin (tools.Origin*) of an inbound
+// A2A request arriving over transport (tools.OriginREST or
+// tools.OriginA2A). The origin headers are honored only when trusted,
+// that is when the request authenticated with the runtime's own token
+// (auth.FromRuntime): any other caller could claim a more permissive
+// origin's tool policy. A channel header wins, since the channel router
+// sets it; then a known X-Forge-Origin; then the transport.
+func RequestOrigin(h http.Header, transport string, trusted bool) string {
+	if !trusted {
+		return transport
+	}
+	if h.Get(HeaderForgeChannel) != "" {
+		return tools.OriginChannel
+	}
+	switch o := h.Get(HeaderForgeOrigin); o {
+	case tools.OriginChannel, tools.OriginUI:
+		return o
+	}
+	return transport
+}
@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestChannelContextHeadersRoundTrip(t *testing.T) {
+	want := ChannelContext{Channel: "slack", EventID: "Ev0123ABC", MessageID: "1718000000.000100"}
+	h := http.Header{}
+	want.ApplyToHTTPHeaders(h)
+	if got := ChannelContextFromHTTPHeaders(h); got != want {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+	if !ChannelContextFromHTTPHeaders(http.Header{}).IsZero() {
+		t.Error("no headers should yield a zero ChannelContext")
+	}
+}
+
+func TestEmitFromContext_TagsChannelFields(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLogger(&buf)
+
+	ctx := WithChannelContext(context.Background(), ChannelContext{Channel: "telegram", EventID: "812345", MessageID: "42"})
+	audit.EmitFromContext(ctx, AuditEvent{Event: "session_start", CorrelationID: "c1"})
+	audit.EmitFromContext(context.Background(), AuditEvent{Event: "session_start"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, want := range []string{`"channel":"telegram"`, `"channel_event_id":"812345"`, `"channel_message_id":"42"`, `"correlation_id":"c1"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("channel event missing %s: %s", want, lines[0])
+		}
+	}
+	if strings.Contains(lines[1], `"channel`) {
+		t.Errorf("non-channel invocation carries channel fields: %s", lines[1])
+	}
+}
+
+func TestRequestOrigin(t *testing.T) {
+	for _, tc := range []struct {
+		channel, origin, transport, want string
+		trusted                          bool
+	}{
+		{"", "", "rest", "rest", true},
+		{"slack", "ui", "a2a", "channel", true},
+		{"", "ui", "a2a", "ui", true},
+		{"", "schedule", "rest", "rest", true}, // only front-end origins may be claimed
+		// Spoofed by a caller without the runtime's token.
+		{"", "ui", "rest", "rest", false},
+		{"slack", "", "a2a", "a2a", false},
+	} {
+		h := http.Header{}
+		if tc.channel != "" {
+			h.Set(HeaderForgeChannel, tc.channel)
+		}
+		if tc.origin != "" {
+			h.Set(HeaderForgeOrigin, tc.origin)
+		}
+		if got := RequestOrigin(h, tc.transport, tc.trusted); got != tc.want {
+			t.Errorf("RequestOrigin(channel=%q, origin=%q, %s, trusted=%v) = %q, want %q", tc.channel, tc.origin, tc.transport, tc.trusted, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// CitationSource is implemented by tool executors that can name the
+// sources a tool call returned. tools.Registry satisfies it by delegating
+// to tools implementing tools.Citer.
+type CitationSource interface {
+	Citations(name string, arguments json.RawMessage, result string) []a2a.Citation
+}
+
+// citationFooterHeader opens the source list appended to a tool result.
+// It is also how earlier turns' numbering is recovered from a session.
+const citationFooterHeader = "Sources (cite inline as [n] where used):"
+
+// citationMarker matches an inline reference like "[3]" in a response.
+var citationMarker = regexp.MustCompile(`\[(\d+)\]`)
+
+// citationTracker numbers the sources tool calls return across a
+// conversation, so the same page or document keeps one number and the
+// final response's [n] markers resolve to structured citations.
+type citationTracker struct {
+	byID map[int]a2a.Citation
+	ids  map[string]int
+	next int
+}
+
+// newCitationTracker returns a tracker that continues the numbering of
+// earlier turns: citations attached to prior agent messages, and the
+// source lists on tool results still in the conversation.
+func newCitationTracker(history []a2a.Message, msgs []llm.ChatMessage) *citationTracker {
+	t := &citationTracker{byID: map[int]a2a.Citation{}, ids: map[string]int{}, next: 1}
+	for _, m := range history {
+		for _, c := range m.Citations {
+			t.seed(c)
+		}
+	}
+	for _, m := range msgs {
+		if m.Role != llm.RoleTool {
+			continue
+		}
+		for _, c := range parseCitationFooter(m.Content) {
+			t.seed(c)
+		}
+	}
+	return t
+}
+
+// citationKey identifies a source: its URL, or its source and location.
+func citationKey(c a2a.Citation) string {
+	if c.URL != "" {
+		return c.URL
+	}
+	return c.Source + "#" + c.Location
+}
+
+// seed records a citation numbered in an earlier turn.
+func (t *citationTracker) seed(c a2a.Citation) {
+	key := citationKey(c)
+	if c.ID <= 0 || key == "#" {
+		return
+	}
+	if _, taken := t.byID[c.ID]; taken {
+		return
+	}
+	if _, known := t.ids[key]; known {
+		return
+	}
+	t.byID[c.ID] = c
+	t.ids[key] = c.ID
+	if c.ID >= t.next {
+		t.next = c.ID + 1
+	}
+}
+
+// add numbers cits, reusing the number of any source seen before, and
+// returns them with IDs set. Duplicates within cits are dropped.
+func (t *citationTracker) add(cits []a2a.Citation) []a2a.Citation {
+	var out []a2a.Citation
+	for _, c := range cits {
+		key := citationKey(c)
+		if key == "#" {
+			continue
+		}
+		if id, ok := t.ids[key]; ok {
+			if !slices.ContainsFunc(out, func(o a2a.Citation) bool { return o.ID == id }) {
+				out = append(out, t.byID[id])
+			}
+			continue
+		}
+		c.ID = t.next
+		t.next++
+		t.byID[c.ID] = c
+		t.ids[key] = c.ID
+		out = append(out, c)
+	}
+	return out
+}
+
+// resolve returns the known citations text references, in order of
+// first reference. Markers that match no source are ignored.
+func (t *citationTracker) resolve(text string) []a2a.Citation {
+	var out []a2a.Citation
+	seen := map[int]bool{}
+	for _, m := range citationMarker.FindAllStringSubmatch(text, -1) {
+		id, err := strconv.Atoi(m[1])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if c, ok := t.byID[id]; ok {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ground numbers the sources a provider grounded msg in (Gemini's
+// Google Search grounding) and marks them inline as [n] where the text
+// they support ends, so they resolve like tool-call citations. The
+// returned message carries no Citations.
+func (t *citationTracker) ground(msg llm.ChatMessage) llm.ChatMessage {
+	if len(msg.Citations) == 0 {
+		return msg
+	}
+	byEnd := map[int][]a2a.Citation{}
+	var ends []int
+	for _, c := range msg.Citations {
+		end := min(max(c.End, 0), len(msg.Content))
+		for end < len(msg.Content) && !utf8.RuneStart(msg.Content[end]) {
+			end--
+		}
+		if _, ok := byEnd[end]; !ok {
+			ends = append(ends, end)
+		}
+		byEnd[end] = append(byEnd[end], a2a.Citation{Title: c.Title, URL: c.URL})
+	}
+	// Number in reading order, then insert from the end so earlier
+	// offsets stay valid.
+	sort.Ints(ends)
+	markers := make(map[int]string, len(ends))
+	for _, end := range ends {
+		var b strings.Builder
+		for _, c := range t.add(byEnd[end]) {
+			fmt.Fprintf(&b, "[%d]", c.ID)
+		}
+		markers[end] = b.String()
+	}
+	content := msg.Content
+	for i := len(ends) - 1; i >= 0; i-- {
+		end := ends[i]
+		content = content[:end] + markers[end] + content[end:]
+	}
+	msg.Content = content
+	msg.Citations = nil
+	return msg
+}
+
+// citationFooter renders the numbered source list appended to a tool
+// result, one "[n] Title — location" line per citation.
+func citationFooter(cits []a2a.Citation) string {
+	var b strings.Builder
+	b.WriteString(citationFooterHeader)
+	for _, c := range cits {
+		fmt.Fprintf(&b, "\n[%d] %s", c.ID, citationLabel(c))
+	}
+	return b.String()
+}
+
+// citationLabel is a citation's footer text: "Title — URL" or
+// "Title — source (location)".
+func citationLabel(c a2a.Citation) string {
+	where := c.URL
+	if where == "" {
+		where = c.Source
+		if c.Location != "" {
+			where += " (" + c.Location + ")"
+		}
+	}
+	if c.Title == "" {
+		return where
+	}
+	return c.Title + " — " + where
+}
+
+// parseCitationFooter recovers the citations a tool result's source list
+// numbered. The tool that produced them is not recorded in the footer.
+func parseCitationFooter(content string) []a2a.Citation {
+	_, list, ok := strings.Cut(content, citationFooterHeader)
+	if !ok {
+		return nil
+	}
+	var cits []a2a.Citation
+	for _, line := range strings.Split(list, "\n") {
+		rest, ok := strings.CutPrefix(line, "[")
+		if !ok {
+			continue
+		}
+		num, label, ok := strings.Cut(rest, "] ")
+		id, err := strconv.Atoi(num)
+		if !ok || err != nil {
+			continue
+		}
+		c := a2a.Citation{ID: id}
+		where := label
+		if i := strings.LastIndex(label, " — "); i >= 0 {
+			c.Title, where = label[:i], label[i+len(" — "):]
+		}
+		switch {
+		case strings.HasPrefix(where, "http://"), strings.HasPrefix(where, "https://"):
+			c.URL = where
+		case strings.HasSuffix(where, ")") && strings.Contains(where, " ("):
+			i := strings.LastIndex(where, " (")
+			c.Source, c.Location = where[:i], where[i+2:len(where)-1]
+		default:
+			c.Source = where
+		}
+		cits = append(cits, c)
+	}
+	return cits
+}
@@ -0,0 +1,128 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// citingToolExecutor cites every result line that looks like a URL.
+type citingToolExecutor struct {
+	mockToolExecutor
+}
+
+func (c *citingToolExecutor) Citations(name string, _ json.RawMessage, result string) []a2a.Citation {
+	var cits []a2a.Citation
+	for _, line := range strings.Split(result, "\n") {
+		if strings.HasPrefix(line, "https://") {
+			cits = append(cits, a2a.Citation{Title: "Page " + line[len(line)-1:], URL: line, Tool: name})
+		}
+	}
+	return cits
+}
+
+func TestExecuteAttachesCitations(t *testing.T) {
+	results := []string{
+		"https://example.com/a\nhttps://example.com/b",
+		"https://example.com/b\nhttps://example.com/c",
+	}
+	turn := 0
+	var toolMsg string
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			last := req.Messages[len(req.Messages)-1]
+			if last.Role == llm.RoleUser {
+				return &llm.ChatResponse{Message: llm.ChatMessage{
+					Role: llm.RoleAssistant,
+					ToolCalls: []llm.ToolCall{{ID: "call_1", Type: "function",
+						Function: llm.FunctionCall{Name: "web_search", Arguments: `{}`}}},
+				}, FinishReason: "tool_calls"}, nil
+			}
+			toolMsg = last.Content
+			content := "B says so [2], A agrees [1][2]; [9] is unknown."
+			if turn == 1 {
+				content = "C is new [3] and B still holds [2]."
+			}
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: content}, FinishReason: "stop"}, nil
+		},
+	}
+	tools := &citingToolExecutor{mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			return results[turn], nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "web_search"}}},
+	}}
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools})
+
+	task := &a2a.Task{ID: "cite-1"}
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("search")}}
+	resp, err := executor.Execute(context.Background(), task, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(toolMsg, citationFooterHeader+"\n[1] Page a — https://example.com/a\n[2] Page b — https://example.com/b") {
+		t.Errorf("tool result footer missing:\n%s", toolMsg)
+	}
+	if len(resp.Citations) != 2 || resp.Citations[0].ID != 2 || resp.Citations[1].ID != 1 || resp.Citations[0].Tool != "web_search" {
+		t.Fatalf("citations = %+v", resp.Citations)
+	}
+
+	// The next turn keeps the numbering: b is still [2], c is new.
+	turn = 1
+	task.History = append(task.History, *msg, *resp)
+	resp, err = executor.Execute(context.Background(), task, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(toolMsg, "[2] Page b — https://example.com/b\n[3] Page c — https://example.com/c") {
+		t.Errorf("second footer:\n%s", toolMsg)
+	}
+	if len(resp.Citations) != 2 || resp.Citations[0].URL != "https://example.com/c" || resp.Citations[1].ID != 2 {
+		t.Errorf("second citations = %+v", resp.Citations)
+	}
+}
+
+func TestParseCitationFooter(t *testing.T) {
+	cits := []a2a.Citation{
+		{ID: 1, Title: "Pricing — Acme", URL: "https://acme.test/pricing"},
+		{ID: 2, Title: "Setup Guide", Source: "kb:docs/setup.md", Location: "lines 1-12"},
+		{ID: 4, URL: "https://acme.test/faq"},
+	}
+	got := parseCitationFooter("tool output\n\n" + citationFooter(cits))
+	if len(got) != len(cits) {
+		t.Fatalf("parsed %+v", got)
+	}
+	for i := range cits {
+		if got[i] != cits[i] {
+			t.Errorf("citation %d = %+v, want %+v", i, got[i], cits[i])
+		}
+	}
+	if parseCitationFooter("no sources here [1] x") != nil {
+		t.Error("parsed citations from a result without a footer")
+	}
+}
+
+func TestCitationTrackerGround(t *testing.T) {
+	tr := newCitationTracker(nil, nil)
+	tr.add([]a2a.Citation{{URL: "https://example.com/a"}})
+	content := "Paris is sunny. Rome is cloudy."
+	msg := tr.ground(llm.ChatMessage{Role: llm.RoleAssistant, Content: content, Citations: []llm.Citation{
+		{Title: "b.com", URL: "https://example.com/b", End: 31},
+		{Title: "a.com", URL: "https://example.com/a", End: 15},
+		{Title: "c.com", URL: "https://example.com/c", End: 15},
+		{URL: "https://example.com/b", End: 15},
+	}})
+	if want := "Paris is sunny.[1][2][3] Rome is cloudy.[3]"; msg.Content != want {
+		t.Errorf("content = %q, want %q", msg.Content, want)
+	}
+	if msg.Citations != nil {
+		t.Error("grounding citations left on the message")
+	}
+	if got := tr.resolve(msg.Content); len(got) != 3 || got[1].Title != "c.com" || got[2].URL != "https://example.com/b" {
+		t.Errorf("resolved = %+v", got)
+	}
+}
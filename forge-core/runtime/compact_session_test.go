@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestCompactSession_PersistedSession(t *testing.T) {
+	store, err := NewMemoryStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := []llm.ChatMessage{{Role: llm.RoleUser, Content: "the task"}}
+	for i := range 6 {
+		msgs = append(msgs, llm.ChatMessage{Role: llm.RoleAssistant, Content: fmt.Sprintf("finding %d", i)})
+	}
+	if err := store.Save(&SessionData{TaskID: "t1", Messages: msgs}); err != nil {
+		t.Fatal(err)
+	}
+	// Far below the trigger ratio: only a forced compaction runs.
+	e := NewLLMExecutor(LLMExecutorConfig{
+		Store:     store,
+		Compactor: NewCompactor(CompactorConfig{Store: store, CharBudget: 1_000_000}),
+	})
+
+	res, err := e.CompactSession("t1")
+	if err != nil {
+		t.Fatalf("CompactSession: %v", err)
+	}
+	if !res.Compacted || res.Removed != 3 || res.Remaining != 4 || res.Summary == "" {
+		t.Errorf("result = %+v", res)
+	}
+	saved, _ := store.Load("t1")
+	if saved.Summary != res.Summary || len(saved.Messages) != 4 || saved.Messages[0].Content != "the task" {
+		t.Errorf("saved session = %+v", saved)
+	}
+
+	if _, err := e.CompactSession("missing"); !errors.Is(err, ErrNoSession) {
+		t.Errorf("missing session: err = %v", err)
+	}
+	if _, err := NewLLMExecutor(LLMExecutorConfig{}).CompactSession("t1"); !errors.Is(err, ErrNoCompactor) {
+		t.Errorf("no compactor: err = %v", err)
+	}
+}
+
+func TestCompactSession_LiveTask(t *testing.T) {
+	var e *LLMExecutor
+	var res *CompactResult
+	calls := 0
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		calls++
+		if calls <= 4 {
+			return &llm.ChatResponse{Message: llm.ChatMessage{
+				Role:      llm.RoleAssistant,
+				ToolCalls: []llm.ToolCall{{ID: fmt.Sprint("call_", calls), Type: "function", Function: llm.FunctionCall{Name: "step", Arguments: `{}`}}},
+			}, FinishReason: "tool_calls"}, nil
+		}
+		if req.Messages[0].Role != llm.RoleSystem {
+			t.Error("the call after compaction should carry the summary in the system message")
+		}
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "Done"}, FinishReason: "stop"}, nil
+	}}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			if calls == 4 {
+				// The fourth step asks for compaction from inside the task.
+				var err error
+				if res, err = e.CompactSession("live-1"); err != nil {
+					return "", err
+				}
+			}
+			return "ok", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "step"}}},
+	}
+	e = NewLLMExecutor(LLMExecutorConfig{
+		Client:    client,
+		Tools:     tools,
+		Compactor: NewCompactor(CompactorConfig{CharBudget: 1_000_000}),
+	})
+
+	if _, err := e.Execute(context.Background(), &a2a.Task{ID: "live-1"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("work")},
+	}); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if res == nil || !res.Compacted || res.Removed == 0 {
+		t.Errorf("live compaction result = %+v", res)
+	}
+	if len(e.live) != 0 {
+		t.Error("finished task is still tracked as live")
+	}
+}
@@ -1,9 +1,13 @@
 package runtime
 
 import (
+	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
 	"github.com/initializ/forge/forge-core/types"
 )
 
@@ -12,6 +16,27 @@ type ModelConfig struct {
 	Provider  string
 	Client    llm.ClientConfig
 	Fallbacks []FallbackModelConfig
+	Routes    []RouteModelConfig
+	// ResponseCache is nil unless the response cache is enabled.
+	ResponseCache *llm.ResponseCacheConfig
+	// HTTP configures the transport every provider client shares.
+	HTTP providers.TransportConfig
+}
+
+// RouteModelConfig holds a resolved model.routes rule, or an
+// experiment variant's model as a pinned route (see ExperimentRouteID).
+type RouteModelConfig struct {
+	ID       string
+	When     llm.RouteCondition
+	Provider string
+	Client   llm.ClientConfig
+	Pinned   bool
+}
+
+// ExperimentRouteID is the ID of the pinned route serving an experiment
+// variant's model, as it appears in llm_call audit events.
+func ExperimentRouteID(experiment, variant string) string {
+	return "experiment:" + experiment + "/" + variant
 }
 
 // FallbackModelConfig holds a resolved fallback provider's configuration.
@@ -73,6 +98,21 @@ func ResolveModelConfig(cfg *types.ForgeConfig, envVars map[string]string, provi
 		} else if envVars["GEMINI_API_KEY"] != "" {
 			mc.Provider = "gemini"
 			mc.Client.APIKey = envVars["GEMINI_API_KEY"]
+		} else if envVars["MISTRAL_API_KEY"] != "" {
+			mc.Provider = "mistral"
+			mc.Client.APIKey = envVars["MISTRAL_API_KEY"]
+		} else if envVars["COHERE_API_KEY"] != "" {
+			mc.Provider = "cohere"
+			mc.Client.APIKey = envVars["COHERE_API_KEY"]
+		} else {
+			for _, name := range providers.PresetNames() {
+				p, _ := providers.LookupPreset(name)
+				if k := envVars[p.APIKeyEnvVar]; k != "" {
+					mc.Provider = name
+					mc.Client.APIKey = k
+					break
+				}
+			}
 		}
 	}
 
@@ -96,6 +136,15 @@ func ResolveModelConfig(cfg *types.ForgeConfig, envVars map[string]string, provi
 	if u := envVars["OLLAMA_BASE_URL"]; u != "" && mc.Provider == "ollama" {
 		mc.Client.BaseURL = u
 	}
+	if u := envVars["COHERE_BASE_URL"]; u != "" && mc.Provider == "cohere" {
+		mc.Client.BaseURL = u
+	}
+	if u := envVars["MISTRAL_BASE_URL"]; u != "" && mc.Provider == "mistral" {
+		mc.Client.BaseURL = u
+	}
+	if p, ok := providers.LookupPreset(mc.Provider); ok && envVars[p.BaseURLEnvVar] != "" {
+		mc.Client.BaseURL = envVars[p.BaseURLEnvVar]
+	}
 
 	// Issue #202 Phase 2 — forge.yaml auth_scheme + aws_region carry
 	// onto the client config when the operator points at AWS Bedrock
@@ -114,6 +163,16 @@ func ResolveModelConfig(cfg *types.ForgeConfig, envVars map[string]string, provi
 	if cfg.Model.AuthHeaderName != "" {
 		mc.Client.AuthHeaderName = cfg.Model.AuthHeaderName
 	}
+	// Providers that do not accept the value drop it client-side, so
+	// it is carried regardless of a FORGE_MODEL_PROVIDER override.
+	mc.Client.ReasoningEffort = cfg.Model.ReasoningEffort
+	mc.Client.ThinkingBudget = cfg.Model.ThinkingBudget
+	if mc.Provider == "ollama" {
+		mc.Client.KeepAlive = ollamaKeepAlive(cfg, envVars)
+	}
+	if mc.Provider == "gemini" {
+		applyGeminiOptions(cfg, &mc.Client)
+	}
 	// AWS_REGION env safety-net for the SigV4 path. Mirrors the
 	// OPENAI_BASE_URL / ANTHROPIC_BASE_URL env pattern above — lets
 	// an operator override the region per-deploy without touching
@@ -134,10 +193,84 @@ func ResolveModelConfig(cfg *types.ForgeConfig, envVars map[string]string, provi
 
 	// Resolve fallback providers
 	mc.Fallbacks = resolveFallbacks(cfg, envVars, mc.Provider)
+	mc.Routes = resolveRoutes(cfg, envVars, mc)
+	mc.ResponseCache = resolveResponseCache(cfg, envVars)
+	resolveHTTP(cfg, mc)
 
 	return mc
 }
 
+// resolveRoutes resolves model.routes, then the models of experiment
+// variants as pinned routes. A route on the primary's provider inherits
+// the primary client config (key, base URL, auth scheme) with the model
+// swapped; one on another provider resolves its key and base URL the
+// way a fallback does.
+func resolveRoutes(cfg *types.ForgeConfig, envVars map[string]string, mc *ModelConfig) []RouteModelConfig {
+	var routes []RouteModelConfig
+	for i, rt := range cfg.Model.Routes {
+		rc := RouteModelConfig{
+			ID: rt.ID,
+			When: llm.RouteCondition{
+				Metadata:       rt.When.Metadata,
+				Tags:           rt.When.Tags,
+				MinInputTokens: rt.When.MinInputTokens,
+				MaxInputTokens: rt.When.MaxInputTokens,
+				Tools:          rt.When.Tools,
+				ToolResults:    rt.When.ToolResults,
+			},
+		}
+		if rc.ID == "" {
+			rc.ID = fmt.Sprintf("routes[%d]", i)
+		}
+		rc.Provider, rc.Client = resolveRouteClient(cfg, envVars, mc, rt.Provider, rt.Name, rt.ReasoningEffort)
+		routes = append(routes, rc)
+	}
+	for _, exp := range cfg.Experiments {
+		for _, v := range exp.Variants {
+			if v.Model == nil {
+				continue
+			}
+			rc := RouteModelConfig{ID: ExperimentRouteID(exp.ID, v.ID), Pinned: true}
+			rc.Provider, rc.Client = resolveRouteClient(cfg, envVars, mc, v.Model.Provider, v.Model.Name, v.Model.ReasoningEffort)
+			routes = append(routes, rc)
+		}
+	}
+	return routes
+}
+
+// resolveRouteClient resolves the provider and client config of a route
+// to model name on provider ("" = the primary's).
+func resolveRouteClient(cfg *types.ForgeConfig, envVars map[string]string, mc *ModelConfig, provider, name, reasoningEffort string) (string, llm.ClientConfig) {
+	if provider == "" {
+		provider = mc.Provider
+	}
+	var client llm.ClientConfig
+	if provider == mc.Provider {
+		client = mc.Client
+	} else {
+		client = llm.ClientConfig{
+			APIKey:  resolveFallbackAPIKey(provider, envVars),
+			BaseURL: resolveFallbackBaseURL(provider, envVars),
+		}
+		if provider == "openai" {
+			client.OrgID = envVars["OPENAI_ORG_ID"]
+		}
+		if provider == "ollama" {
+			client.KeepAlive = ollamaKeepAlive(cfg, envVars)
+		}
+		if provider == "gemini" {
+			applyGeminiOptions(cfg, &client)
+		}
+	}
+	client.Model = name
+	if client.Model == "" {
+		client.Model = defaultModelForProvider(provider)
+	}
+	client.ReasoningEffort = reasoningEffort
+	client.ThinkingBudget = 0 // the primary model's only
+	return provider, client
+}
+
 // defaultModelForProvider returns the default model name for a given provider.
 func defaultModelForProvider(provider string) string {
 	switch provider {
@@ -149,7 +282,14 @@ func defaultModelForProvider(provider string) string {
 		return "gemini-2.5-flash"
 	case "ollama":
 		return "llama3"
+	case "cohere":
+		return "command-r-plus"
+	case "mistral":
+		return "mistral-large-latest"
 	default:
+		if p, ok := providers.LookupPreset(provider); ok {
+			return p.DefaultModel
+		}
 		return ""
 	}
 }
@@ -162,7 +302,7 @@ func resolveFallbacks(cfg *types.ForgeConfig, envVars map[string]string, primary
 	seen := map[string]bool{primaryProvider: true}
 	var fallbacks []FallbackModelConfig
 
-	addFallback := func(provider, model, orgID string) {
+	addFallback := func(provider, model, orgID, effort string) {
 		if seen[provider] {
 			return
 		}
@@ -177,12 +317,19 @@ func resolveFallbacks(cfg *types.ForgeConfig, envVars map[string]string, primary
 		fc := FallbackModelConfig{
 			Provider: provider,
 			Client: llm.ClientConfig{
-				APIKey: apiKey,
-				Model:  model,
+				APIKey:          apiKey,
+				Model:           model,
+				ReasoningEffort: effort,
 			},
 		}
-		if provider == "ollama" && apiKey == "" {
-			fc.Client.APIKey = "ollama"
+		if provider == "ollama" {
+			if apiKey == "" {
+				fc.Client.APIKey = "ollama"
+			}
+			fc.Client.KeepAlive = ollamaKeepAlive(cfg, envVars)
+		}
+		if provider == "gemini" {
+			applyGeminiOptions(cfg, &fc.Client)
 		}
 		// Apply base URL overrides
 		fc.Client.BaseURL = resolveFallbackBaseURL(provider, envVars)
@@ -202,7 +349,7 @@ func resolveFallbacks(cfg *types.ForgeConfig, envVars map[string]string, primary
 
 	// Source 1: forge.yaml model.fallbacks
 	for _, fb := range cfg.Model.Fallbacks {
-		addFallback(fb.Provider, fb.Name, fb.OrganizationID)
+		addFallback(fb.Provider, fb.Name, fb.OrganizationID, fb.ReasoningEffort)
 	}
 
 	// Source 2: FORGE_MODEL_FALLBACKS env var
@@ -213,7 +360,7 @@ func resolveFallbacks(cfg *types.ForgeConfig, envVars map[string]string, primary
 				continue
 			}
 			provider, model, _ := strings.Cut(entry, ":")
-			addFallback(provider, model, "")
+			addFallback(provider, model, "", "")
 		}
 	}
 
@@ -222,29 +369,138 @@ func resolveFallbacks(cfg *types.ForgeConfig, envVars map[string]string, primary
 		"openai":    "OPENAI_API_KEY",
 		"anthropic": "ANTHROPIC_API_KEY",
 		"gemini":    "GEMINI_API_KEY",
+		"cohere":    "COHERE_API_KEY",
+		"mistral":   "MISTRAL_API_KEY",
+	}
+	for _, name := range providers.PresetNames() {
+		p, _ := providers.LookupPreset(name)
+		providerKeys[name] = p.APIKeyEnvVar
 	}
 	for provider, keyName := range providerKeys {
 		if envVars[keyName] != "" {
-			addFallback(provider, "", "")
+			addFallback(provider, "", "", "")
 		}
 	}
 
 	return fallbacks
 }
 
+// resolveResponseCache applies the FORGE_LLM_CACHE (on/off),
+// FORGE_LLM_CACHE_DIR and FORGE_LLM_CACHE_TTL env overrides to
+// forge.yaml model.response_cache, so a CI or eval environment can turn
+// the cache on without editing forge.yaml. Returns nil when disabled.
+func resolveResponseCache(cfg *types.ForgeConfig, envVars map[string]string) *llm.ResponseCacheConfig {
+	var rc types.ResponseCacheConfig
+	if cfg.Model.ResponseCache != nil {
+		rc = *cfg.Model.ResponseCache
+	}
+	switch strings.ToLower(envVars["FORGE_LLM_CACHE"]) {
+	case "1", "true", "on":
+		rc.Enabled = true
+	case "0", "false", "off":
+		rc.Enabled = false
+	}
+	if !rc.Enabled {
+		return nil
+	}
+	if d := envVars["FORGE_LLM_CACHE_DIR"]; d != "" {
+		rc.Dir = d
+	}
+	if v := envVars["FORGE_LLM_CACHE_TTL"]; v != "" {
+		if ttl, err := time.ParseDuration(v); err == nil {
+			rc.TTL = ttl
+		}
+	}
+	return &llm.ResponseCacheConfig{TTL: rc.TTL, MaxEntries: rc.MaxEntries, Dir: rc.Dir}
+}
+
+// resolveHTTP carries forge.yaml model.http onto the shared transport
+// config, and sets every client's timeout: its provider's entry in
+// model.http.timeouts, else model.http.timeout, else the client's own
+// default.
+func resolveHTTP(cfg *types.ForgeConfig, mc *ModelConfig) {
+	h := cfg.Model.HTTP
+	if h == nil {
+		return
+	}
+	mc.HTTP = providers.TransportConfig{
+		MaxIdleConns:        h.MaxIdleConns,
+		MaxIdleConnsPerHost: h.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     h.MaxConnsPerHost,
+		IdleConnTimeout:     h.IdleConnTimeout,
+		DisableHTTP2:        h.HTTP2 != nil && !*h.HTTP2,
+		ProxyURL:            h.Proxy,
+	}
+	if t := h.TLS; t != nil {
+		mc.HTTP.CAFile = t.CAFile
+		mc.HTTP.TLSMinVersion = t.MinVersion
+		mc.HTTP.InsecureSkipVerify = t.InsecureSkipVerify
+	}
+
+	timeoutSecs := func(provider string) int {
+		d := h.Timeout
+		if pd, ok := h.Timeouts[provider]; ok {
+			d = pd
+		}
+		return int(math.Ceil(d.Seconds()))
+	}
+	mc.Client.TimeoutSecs = timeoutSecs(mc.Provider)
+	for i := range mc.Fallbacks {
+		mc.Fallbacks[i].Client.TimeoutSecs = timeoutSecs(mc.Fallbacks[i].Provider)
+	}
+	for i := range mc.Routes {
+		mc.Routes[i].Client.TimeoutSecs = timeoutSecs(mc.Routes[i].Provider)
+	}
+}
+
+// ollamaKeepAlive resolves keep_alive for an ollama client: the
+// OLLAMA_KEEP_ALIVE env var (the daemon's own setting, honored
+// per-request too) wins over forge.yaml model.keep_alive.
+func ollamaKeepAlive(cfg *types.ForgeConfig, envVars map[string]string) string {
+	if v := envVars["OLLAMA_KEEP_ALIVE"]; v != "" {
+		return v
+	}
+	return cfg.Model.KeepAlive
+}
+
+// applyGeminiOptions carries forge.yaml model.gemini onto a gemini
+// client.
+func applyGeminiOptions(cfg *types.ForgeConfig, client *llm.ClientConfig) {
+	if g := cfg.Model.Gemini; g != nil {
+		client.GoogleSearch = g.Grounding
+		client.SafetySettings = g.SafetySettings
+	}
+}
+
 // resolveFallbackAPIKey resolves the API key for a fallback provider.
 func resolveFallbackAPIKey(provider string, envVars map[string]string) string {
+	if provider == "ollama" {
+		return "ollama"
+	}
+	return envVars[ProviderAPIKeyEnv(provider)]
+}
+
+// ProviderAPIKeyEnv returns the environment variable holding provider's
+// API key, or "" for ollama, which needs none.
+func ProviderAPIKeyEnv(provider string) string {
 	switch provider {
 	case "openai":
-		return envVars["OPENAI_API_KEY"]
+		return "OPENAI_API_KEY"
 	case "anthropic":
-		return envVars["ANTHROPIC_API_KEY"]
+		return "ANTHROPIC_API_KEY"
 	case "gemini":
-		return envVars["GEMINI_API_KEY"]
+		return "GEMINI_API_KEY"
+	case "cohere":
+		return "COHERE_API_KEY"
+	case "mistral":
+		return "MISTRAL_API_KEY"
 	case "ollama":
-		return "ollama"
+		return ""
 	default:
-		return envVars["LLM_API_KEY"]
+		if p, ok := providers.LookupPreset(provider); ok {
+			return p.APIKeyEnvVar
+		}
+		return "LLM_API_KEY"
 	}
 }
 
@@ -257,7 +513,14 @@ func resolveFallbackBaseURL(provider string, envVars map[string]string) string {
 		return envVars["ANTHROPIC_BASE_URL"]
 	case "ollama":
 		return envVars["OLLAMA_BASE_URL"]
+	case "cohere":
+		return envVars["COHERE_BASE_URL"]
+	case "mistral":
+		return envVars["MISTRAL_BASE_URL"]
 	default:
+		if p, ok := providers.LookupPreset(provider); ok {
+			return envVars[p.BaseURLEnvVar]
+		}
 		return ""
 	}
 }
@@ -282,8 +545,28 @@ func resolveAPIKey(mc *ModelConfig, envVars map[string]string) {
 		} else if k := envVars["LLM_API_KEY"]; k != "" {
 			mc.Client.APIKey = k
 		}
+	case "cohere":
+		if k := envVars["COHERE_API_KEY"]; k != "" {
+			mc.Client.APIKey = k
+		} else if k := envVars["LLM_API_KEY"]; k != "" {
+			mc.Client.APIKey = k
+		}
+	case "mistral":
+		if k := envVars["MISTRAL_API_KEY"]; k != "" {
+			mc.Client.APIKey = k
+		} else if k := envVars["LLM_API_KEY"]; k != "" {
+			mc.Client.APIKey = k
+		}
 	case "ollama":
 		// Ollama doesn't need an API key
 		mc.Client.APIKey = "ollama"
+	default:
+		if p, ok := providers.LookupPreset(mc.Provider); ok {
+			if k := envVars[p.APIKeyEnvVar]; k != "" {
+				mc.Client.APIKey = k
+			} else if k := envVars["LLM_API_KEY"]; k != "" {
+				mc.Client.APIKey = k
+			}
+		}
 	}
 }
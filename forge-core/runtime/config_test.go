@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"testing"
+	"time"
 
 	"github.com/initializ/forge/forge-core/types"
 )
@@ -269,6 +270,35 @@ func TestResolveModelConfig_EnvBaseURLOverridesYAML(t *testing.T) {
 	}
 }
 
+// TestResolveModelConfig_PresetProvider covers the OpenAI-compatible
+// presets: key and base-URL env vars come from the preset, and a key
+// alone is enough to auto-detect the provider.
+func TestResolveModelConfig_PresetProvider(t *testing.T) {
+	cfg := &types.ForgeConfig{Model: types.ModelRef{Provider: "together"}}
+	envVars := map[string]string{
+		"TOGETHER_API_KEY":  "tg-test",
+		"TOGETHER_BASE_URL": "https://proxy.internal/together/v1",
+	}
+	mc := ResolveModelConfig(cfg, envVars, "")
+	if mc == nil {
+		t.Fatal("expected non-nil ModelConfig")
+	}
+	if mc.Client.APIKey != "tg-test" {
+		t.Errorf("APIKey = %q, want TOGETHER_API_KEY", mc.Client.APIKey)
+	}
+	if mc.Client.BaseURL != "https://proxy.internal/together/v1" {
+		t.Errorf("BaseURL = %q, want TOGETHER_BASE_URL", mc.Client.BaseURL)
+	}
+	if mc.Client.Model != "meta-llama/Llama-3.3-70B-Instruct-Turbo" {
+		t.Errorf("Model = %q, want the preset default", mc.Client.Model)
+	}
+
+	mc = ResolveModelConfig(&types.ForgeConfig{}, map[string]string{"GROQ_API_KEY": "gq-test"}, "")
+	if mc == nil || mc.Provider != "groq" || mc.Client.APIKey != "gq-test" {
+		t.Errorf("auto-detect = %+v, want provider groq", mc)
+	}
+}
+
 func TestResolveModelConfig_OrgIDEnvOverridesYAML(t *testing.T) {
 	cfg := &types.ForgeConfig{
 		Model: types.ModelRef{
@@ -370,6 +400,193 @@ func TestResolveModelConfig_FallbackOrgIDOverride(t *testing.T) {
 	}
 }
 
+func TestResolveModelConfig_ReasoningEffort(t *testing.T) {
+	cfg := &types.ForgeConfig{
+		Model: types.ModelRef{
+			Provider:        "xai",
+			Name:            "grok-3-mini",
+			ReasoningEffort: "high",
+			Fallbacks: []types.ModelFallback{
+				{Provider: "deepseek", Name: "deepseek-reasoner"},
+				{Provider: "openai", Name: "o4-mini", ReasoningEffort: "low"},
+			},
+		},
+	}
+	envVars := map[string]string{
+		"XAI_API_KEY":      "xai-test",
+		"DEEPSEEK_API_KEY": "ds-test",
+		"OPENAI_API_KEY":   "sk-test",
+	}
+	mc := ResolveModelConfig(cfg, envVars, "")
+	if mc == nil {
+		t.Fatal("expected non-nil ModelConfig")
+	}
+	if mc.Client.ReasoningEffort != "high" {
+		t.Errorf("primary ReasoningEffort = %q, want high", mc.Client.ReasoningEffort)
+	}
+	if len(mc.Fallbacks) != 2 {
+		t.Fatalf("got %d fallbacks, want 2", len(mc.Fallbacks))
+	}
+	if mc.Fallbacks[0].Provider != "deepseek" || mc.Fallbacks[0].Client.ReasoningEffort != "" {
+		t.Errorf("fallback[0] = %+v, want deepseek without effort", mc.Fallbacks[0])
+	}
+	if mc.Fallbacks[1].Client.ReasoningEffort != "low" {
+		t.Errorf("fallback[1] ReasoningEffort = %q, want low", mc.Fallbacks[1].Client.ReasoningEffort)
+	}
+}
+
+func TestResolveModelConfig_OllamaKeepAlive(t *testing.T) {
+	cfg := &types.ForgeConfig{
+		Model: types.ModelRef{
+			Provider:  "anthropic",
+			KeepAlive: "30m",
+			Fallbacks: []types.ModelFallback{{Provider: "ollama", Name: "llama3.2"}},
+		},
+	}
+	mc := ResolveModelConfig(cfg, map[string]string{"ANTHROPIC_API_KEY": "sk-ant"}, "")
+	if mc == nil || len(mc.Fallbacks) != 1 {
+		t.Fatalf("mc = %+v, want one ollama fallback", mc)
+	}
+	if mc.Client.KeepAlive != "" {
+		t.Errorf("anthropic primary KeepAlive = %q, want empty", mc.Client.KeepAlive)
+	}
+	if mc.Fallbacks[0].Client.KeepAlive != "30m" {
+		t.Errorf("ollama fallback KeepAlive = %q, want 30m", mc.Fallbacks[0].Client.KeepAlive)
+	}
+
+	cfg.Model.Provider = "ollama"
+	mc = ResolveModelConfig(cfg, map[string]string{"OLLAMA_KEEP_ALIVE": "-1"}, "")
+	if mc.Client.KeepAlive != "-1" {
+		t.Errorf("env override KeepAlive = %q, want -1", mc.Client.KeepAlive)
+	}
+}
+
+func TestResolveModelConfig_ThinkingBudget(t *testing.T) {
+	cfg := &types.ForgeConfig{Model: types.ModelRef{
+		Provider:       "anthropic",
+		Name:           "claude-sonnet-4-5",
+		ThinkingBudget: 8000,
+		Routes:         []types.ModelRoute{{ID: "cheap", When: types.RouteCondition{Tags: []string{"cheap"}}, Name: "claude-haiku-4-5"}},
+	}}
+	mc := ResolveModelConfig(cfg, map[string]string{"ANTHROPIC_API_KEY": "sk-ant"}, "")
+	if mc.Client.ThinkingBudget != 8000 {
+		t.Errorf("primary ThinkingBudget = %d, want 8000", mc.Client.ThinkingBudget)
+	}
+	if mc.Routes[0].Client.ThinkingBudget != 0 {
+		t.Errorf("route inherited ThinkingBudget %d", mc.Routes[0].Client.ThinkingBudget)
+	}
+}
+
+func TestResolveModelConfig_GeminiOptions(t *testing.T) {
+	cfg := &types.ForgeConfig{Model: types.ModelRef{
+		Provider:  "openai",
+		Name:      "gpt-4o",
+		Gemini:    &types.GeminiOptions{Grounding: true, SafetySettings: map[string]string{"harassment": "block_none"}},
+		Fallbacks: []types.ModelFallback{{Provider: "gemini"}},
+		Routes:    []types.ModelRoute{{ID: "cheap", When: types.RouteCondition{Tags: []string{"cheap"}}, Name: "gpt-4o-mini"}},
+	}}
+	mc := ResolveModelConfig(cfg, map[string]string{"OPENAI_API_KEY": "sk", "GEMINI_API_KEY": "g"}, "")
+	if mc.Client.GoogleSearch || mc.Routes[0].Client.GoogleSearch {
+		t.Error("gemini options applied to an openai client")
+	}
+	if len(mc.Fallbacks) != 1 || !mc.Fallbacks[0].Client.GoogleSearch || mc.Fallbacks[0].Client.SafetySettings["harassment"] != "block_none" {
+		t.Errorf("gemini fallback = %+v", mc.Fallbacks)
+	}
+}
+
+func TestResolveModelConfig_HTTP(t *testing.T) {
+	http2 := false
+	cfg := &types.ForgeConfig{Model: types.ModelRef{
+		Provider:  "openai",
+		Name:      "gpt-4o",
+		Fallbacks: []types.ModelFallback{{Provider: "ollama", Name: "llama3"}},
+		Routes:    []types.ModelRoute{{When: types.RouteCondition{Tags: []string{"long"}}, Provider: "anthropic", Name: "claude-sonnet-4"}},
+		HTTP: &types.ModelHTTPConfig{
+			MaxIdleConnsPerHost: 8,
+			HTTP2:               &http2,
+			Proxy:               "http://proxy:3128",
+			TLS:                 &types.ModelTLSConfig{MinVersion: "1.3"},
+			Timeout:             90 * time.Second,
+			Timeouts:            map[string]time.Duration{"ollama": 10 * time.Minute, "anthropic": 1500 * time.Millisecond},
+		},
+	}}
+	mc := ResolveModelConfig(cfg, map[string]string{"OPENAI_API_KEY": "sk", "ANTHROPIC_API_KEY": "ak"}, "")
+	if h := mc.HTTP; h.MaxIdleConnsPerHost != 8 || !h.DisableHTTP2 || h.ProxyURL != "http://proxy:3128" || h.TLSMinVersion != "1.3" {
+		t.Errorf("transport = %+v", h)
+	}
+	if mc.Client.TimeoutSecs != 90 || mc.Fallbacks[0].Client.TimeoutSecs != 600 || mc.Routes[0].Client.TimeoutSecs != 2 {
+		t.Errorf("timeouts = %d, %d, %d", mc.Client.TimeoutSecs, mc.Fallbacks[0].Client.TimeoutSecs, mc.Routes[0].Client.TimeoutSecs)
+	}
+}
+
+func TestResolveModelConfig_Routes(t *testing.T) {
+	cfg := &types.ForgeConfig{
+		Model: types.ModelRef{
+			Provider:   "openai",
+			Name:       "gpt-4o",
+			AuthScheme: "apikey_header",
+			Routes: []types.ModelRoute{
+				{ID: "cheap", When: types.RouteCondition{Tags: []string{"cheap"}}, Name: "gpt-4o-mini"},
+				{When: types.RouteCondition{MinInputTokens: 100000}, Provider: "gemini", Name: "gemini-2.5-pro"},
+			},
+		},
+	}
+	mc := ResolveModelConfig(cfg, map[string]string{"OPENAI_API_KEY": "sk-oa", "GEMINI_API_KEY": "gk"}, "")
+	if mc == nil || len(mc.Routes) != 2 {
+		t.Fatalf("mc = %+v, want two routes", mc)
+	}
+
+	cheap := mc.Routes[0]
+	if cheap.ID != "cheap" || cheap.Provider != "openai" || cheap.Client.Model != "gpt-4o-mini" {
+		t.Errorf("cheap route = %+v", cheap)
+	}
+	// Same-provider routes inherit the primary's key and auth settings.
+	if cheap.Client.APIKey != "sk-oa" || cheap.Client.AuthScheme != "apikey_header" || len(cheap.When.Tags) != 1 {
+		t.Errorf("cheap route client = %+v", cheap.Client)
+	}
+
+	long := mc.Routes[1]
+	if long.ID != "routes[1]" || long.Provider != "gemini" || long.Client.APIKey != "gk" || long.Client.AuthScheme != "" || long.When.MinInputTokens != 100000 {
+		t.Errorf("long route = %+v", long)
+	}
+
+	// Experiment variants with a model become pinned routes after them.
+	cfg.Experiments = []types.ExperimentConfig{{ID: "tone", Variants: []types.ExperimentVariant{
+		{ID: "control"},
+		{ID: "claude", Model: &types.ExperimentModel{Provider: "anthropic", Name: "claude-sonnet-4-5"}},
+	}}}
+	mc = ResolveModelConfig(cfg, map[string]string{"OPENAI_API_KEY": "sk-oa", "ANTHROPIC_API_KEY": "sk-ant"}, "")
+	if len(mc.Routes) != 3 {
+		t.Fatalf("routes = %+v, want the variant's appended", mc.Routes)
+	}
+	if v := mc.Routes[2]; !v.Pinned || v.ID != ExperimentRouteID("tone", "claude") || v.Client.APIKey != "sk-ant" || v.Client.Model != "claude-sonnet-4-5" {
+		t.Errorf("variant route = %+v", v)
+	}
+}
+
+func TestResolveModelConfig_ResponseCache(t *testing.T) {
+	env := map[string]string{"OPENAI_API_KEY": "sk"}
+	cfg := &types.ForgeConfig{Model: types.ModelRef{Provider: "openai", Name: "gpt-4o"}}
+	if mc := ResolveModelConfig(cfg, env, ""); mc.ResponseCache != nil {
+		t.Errorf("cache should be off by default: %+v", mc.ResponseCache)
+	}
+
+	// A CI environment turns it on without touching forge.yaml.
+	ci := map[string]string{"OPENAI_API_KEY": "sk", "FORGE_LLM_CACHE": "on", "FORGE_LLM_CACHE_DIR": "/tmp/cache", "FORGE_LLM_CACHE_TTL": "24h"}
+	mc := ResolveModelConfig(cfg, ci, "")
+	if rc := mc.ResponseCache; rc == nil || rc.Dir != "/tmp/cache" || rc.TTL != 24*time.Hour {
+		t.Errorf("env-enabled cache = %+v", rc)
+	}
+
+	cfg.Model.ResponseCache = &types.ResponseCacheConfig{Enabled: true, MaxEntries: 50}
+	if rc := ResolveModelConfig(cfg, env, "").ResponseCache; rc == nil || rc.MaxEntries != 50 {
+		t.Errorf("forge.yaml cache = %+v", rc)
+	}
+	if rc := ResolveModelConfig(cfg, map[string]string{"OPENAI_API_KEY": "sk", "FORGE_LLM_CACHE": "off"}, "").ResponseCache; rc != nil {
+		t.Errorf("FORGE_LLM_CACHE=off should win: %+v", rc)
+	}
+}
+
 func TestDefaultModelForProvider(t *testing.T) {
 	tests := []struct {
 		provider string
@@ -379,6 +596,9 @@ func TestDefaultModelForProvider(t *testing.T) {
 		{"anthropic", "claude-sonnet-4-20250514"},
 		{"gemini", "gemini-2.5-flash"},
 		{"ollama", "llama3"},
+		{"groq", "llama-3.3-70b-versatile"},
+		{"xai", "grok-4"},
+		{"deepseek", "deepseek-chat"},
 		{"unknown", ""},
 	}
 	for _, tt := range tests {
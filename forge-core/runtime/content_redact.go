@@ -172,3 +172,27 @@ func PrepareSpanContent(s string, redact bool, maxBytes int) string {
 	}
 	return PrepareCapturedContent(s, redact, maxBytes)
 }
+
+// piiPatterns covers the personal-data shapes the default guardrails
+// mask (email, phone number, SSN, credit card). Card and SSN come
+// before phone so a card number is not half-matched as one.
+var piiPatterns = []redactPattern{
+	{name: "email", re: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{name: "credit_card", re: regexp.MustCompile(`\b(?:\d{4}[ \-]?){3}\d{1,4}\b`)},
+	{name: "ssn", re: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{name: "phone", re: regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?\(?\b\d{3}\)?[ .\-]?\d{3}[ .\-]\d{4}\b`)},
+}
+
+// RedactPII returns s with email addresses, phone, social security and
+// credit card numbers replaced by RedactionMarker. Unlike secrets, PII
+// is left in audit and span content; it is scrubbed from artifacts
+// that hold whole conversations, such as provider captures.
+func RedactPII(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, p := range piiPatterns {
+		s = p.re.ReplaceAllString(s, RedactionMarker)
+	}
+	return s
+}
@@ -139,3 +139,11 @@ func TestPrepareSpanContent_TruncationMarkerMatchesAuditPipeline(t *testing.T) {
 		t.Errorf("expected truncation marker in span output; got %q", spanOut)
 	}
 }
+
+func TestRedactPII(t *testing.T) {
+	in := "Reach jane.doe+ops@example.co.uk or (415) 555-0100; SSN 123-45-6789, card 4111 1111 1111 1111. Order 12345 ships 2026-10-16."
+	want := "Reach [REDACTED] or [REDACTED]; SSN [REDACTED], card [REDACTED]. Order 12345 ships 2026-10-16."
+	if got := RedactPII(in); got != want {
+		t.Errorf("RedactPII =\n%q\nwant\n%q", got, want)
+	}
+}
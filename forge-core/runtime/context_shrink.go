@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Adaptive context shrinking settings.
+const (
+	// minShrinkChars is the smallest tool result worth a summary call.
+	minShrinkChars = 2_000
+	// maxShrinksPerPass bounds the summary calls made before one LLM call.
+	maxShrinksPerPass = 4
+	// maxShrinkInputChars bounds what is sent to the summary model.
+	maxShrinkInputChars = 100_000
+)
+
+// shrinkContext summarizes the largest tool results in mem, one at a time,
+// until utilization is back under the high-water mark. A failed or
+// unhelpful summary ends the pass; trimming still prunes the oldest tool
+// results if the budget is exceeded.
+func (e *LLMExecutor) shrinkContext(ctx context.Context, mem *Memory) {
+	for range maxShrinksPerPass {
+		usage := mem.ContextUsage()
+		if usage.Utilization <= e.highWater {
+			return
+		}
+		idx, msg, ok := mem.largestToolResult(minShrinkChars)
+		if !ok {
+			return
+		}
+		summary, err := e.summarizeToolResult(ctx, msg)
+		if err != nil {
+			e.logger.Warn("tool result summary failed", map[string]any{
+				"task_id": TaskIDFromContext(ctx), "tool": msg.Name, "error": err.Error(),
+			})
+			return
+		}
+		content := fmt.Sprintf(toolResultPlaceholderPrefix+"%s — %d chars, summarized for context space]\n%s",
+			msg.Name, len(msg.Content), summary)
+		if len(content) >= len(msg.Content) || !mem.replaceToolResult(idx, msg.Content, content) {
+			return
+		}
+		e.logger.Info("tool result summarized", map[string]any{
+			"task_id":      TaskIDFromContext(ctx),
+			"tool":         msg.Name,
+			"before_chars": len(msg.Content),
+			"after_chars":  len(content),
+			"utilization":  usage.Utilization,
+		})
+		if emit := ProgressEmitterFromContext(ctx); emit != nil {
+			emit(ProgressEvent{Phase: ProgressCompaction, Tool: msg.Name, Message: "Summarized " + msg.Name + " result to free context"})
+		}
+	}
+}
+
+// summarizeToolResult asks the summary model for a compact version of a
+// tool result that keeps what the agent needs to act on.
+func (e *LLMExecutor) summarizeToolResult(ctx context.Context, msg llm.ChatMessage) (string, error) {
+	client := e.summaryClient
+	if client == nil {
+		client = e.client
+	}
+	ctx, cancel := context.WithTimeout(ctx, summaryTimeout)
+	defer cancel()
+
+	resp, err := client.Chat(ctx, &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: "You condense tool output for an AI agent that is running low on context space. " +
+				"The agent will see only your summary, not the original output. " +
+				"Keep specific identifiers (file paths, resource names, URLs, IDs), numbers, " +
+				"error messages and anything the agent would need to act on; drop repetition and boilerplate. " +
+				"Keep under 300 words."},
+			{Role: llm.RoleUser, Content: "Output of tool " + msg.Name + ":\n\n" + truncateForPrompt(msg.Content, maxShrinkInputChars)},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	summary := strings.TrimSpace(resp.Message.Content)
+	if summary == "" {
+		return "", errors.New("empty summary")
+	}
+	return summary, nil
+}
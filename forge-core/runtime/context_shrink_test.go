@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// runShrinkTask runs one tool call returning a 20K-char result against a
+// 100K budget (40% utilization, tool results weigh 2x) with a 30%
+// high-water mark, and returns the tool message the second LLM call saw.
+func runShrinkTask(t *testing.T, summary *mockLLMClient) (*a2a.Task, llm.ChatMessage) {
+	t.Helper()
+	calls := 0
+	var toolMsg llm.ChatMessage
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		calls++
+		if calls == 1 {
+			return &llm.ChatResponse{Message: llm.ChatMessage{
+				Role:      llm.RoleAssistant,
+				ToolCalls: []llm.ToolCall{{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "kubectl", Arguments: `{}`}}},
+			}, FinishReason: "tool_calls"}, nil
+		}
+		for _, m := range req.Messages {
+			if m.Role == llm.RoleTool {
+				toolMsg = m
+			}
+		}
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "Done"}, FinishReason: "stop"}, nil
+	}}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			return strings.Repeat("pod/web-1 Running\n", 1_200), nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "kubectl"}}},
+	}
+	executor := NewLLMExecutor(LLMExecutorConfig{
+		Client:           client,
+		Tools:            tools,
+		CharBudget:       100_000,
+		ContextHighWater: 0.3,
+		SummaryClient:    summary,
+	})
+	task := &a2a.Task{ID: "shrink-1"}
+	if _, err := executor.Execute(context.Background(), task, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("list pods")},
+	}); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return task, toolMsg
+}
+
+func TestShrinkContext_SummarizesAboveHighWater(t *testing.T) {
+	var summarized string
+	summary := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		summarized = req.Messages[len(req.Messages)-1].Content
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "1200 pods, all Running (web-1)"}}, nil
+	}}
+	task, toolMsg := runShrinkTask(t, summary)
+
+	if !strings.Contains(summarized, "Output of tool kubectl") {
+		t.Errorf("summary model was not asked about the tool output: %q", summarized)
+	}
+	if !strings.HasPrefix(toolMsg.Content, "[Tool result from kubectl — 21600 chars, summarized for context space]") ||
+		!strings.Contains(toolMsg.Content, "all Running") {
+		t.Errorf("tool message = %q", toolMsg.Content)
+	}
+	usage, ok := task.Metadata["context"].(*ContextUsage)
+	if !ok {
+		t.Fatalf("task metadata context = %T", task.Metadata["context"])
+	}
+	if usage.ShrunkToolResults != 1 || usage.Utilization >= 0.3 {
+		t.Errorf("context usage = %+v", usage)
+	}
+}
+
+func TestShrinkContext_SummaryFailureKeepsResult(t *testing.T) {
+	summary := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		return nil, errors.New("rate limited")
+	}}
+	task, toolMsg := runShrinkTask(t, summary)
+
+	if len(toolMsg.Content) != 21_600 {
+		t.Errorf("tool message changed after a failed summary: %d chars", len(toolMsg.Content))
+	}
+	if usage := task.Metadata["context"].(*ContextUsage); usage.ShrunkToolResults != 0 || usage.Utilization < 0.3 {
+		t.Errorf("context usage = %+v", usage)
+	}
+}
+
+func TestMemoryContextUsage(t *testing.T) {
+	mem := NewMemory("", 1_000, "")
+	mem.Append(llm.ChatMessage{Role: llm.RoleUser, Content: strings.Repeat("a", 196)})
+
+	u := mem.ContextUsage()
+	if u.Chars != 200 || u.Tokens != 50 || u.BudgetTokens != 250 || u.Utilization != 0.2 {
+		t.Errorf("usage = %+v", u)
+	}
+}
@@ -0,0 +1,163 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// PlanArtifactName is the name of the task artifact that carries a dry
+// run's plan.
+const PlanArtifactName = "plan"
+
+// PlanStep is one tool call the agent would have made.
+type PlanStep struct {
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	// Description says what would run: a command line, "METHOD url", or
+	// the tool name with its arguments.
+	Description string `json:"description"`
+}
+
+// Plan records the tool calls intercepted during a dry run. It is safe
+// for concurrent use.
+type Plan struct {
+	mu    sync.Mutex
+	steps []PlanStep
+}
+
+// NewPlan returns an empty plan.
+func NewPlan() *Plan { return &Plan{} }
+
+// Record adds a step for tool called with args and returns its
+// description.
+func (p *Plan) Record(tool string, args json.RawMessage) string {
+	desc := DescribeToolCall(tool, args)
+	step := PlanStep{Tool: tool, Description: desc}
+	if json.Valid(args) {
+		step.Arguments = append(json.RawMessage(nil), args...)
+	}
+	p.mu.Lock()
+	p.steps = append(p.steps, step)
+	p.mu.Unlock()
+	return desc
+}
+
+// Steps returns a copy of the recorded steps in call order.
+func (p *Plan) Steps() []PlanStep {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlanStep(nil), p.steps...)
+}
+
+// Artifact returns the plan as a task artifact: a data part listing the
+// steps, and a text part with one numbered line per step.
+func (p *Plan) Artifact() a2a.Artifact {
+	steps := p.Steps()
+	var sb strings.Builder
+	if len(steps) == 0 {
+		sb.WriteString("No tool calls.")
+	}
+	for i, s := range steps {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(strconv.Itoa(i+1) + ". " + s.Description)
+	}
+	return a2a.Artifact{
+		Name:        PlanArtifactName,
+		Description: "Dry run: tool calls the agent would have made",
+		Parts: []a2a.Part{
+			a2a.NewTextPart(sb.String()),
+			a2a.NewDataPart(map[string]any{"steps": steps}),
+		},
+	}
+}
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context under which the LLM executor records tool
+// calls in plan instead of running them. The model is still called, and
+// each intercepted call returns a "not executed" result describing what
+// would have run, so the model can carry on planning.
+func WithDryRun(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, plan)
+}
+
+// DryRunFromContext returns the plan installed by WithDryRun, or nil when
+// tools run for real.
+func DryRunFromContext(ctx context.Context) *Plan {
+	p, _ := ctx.Value(dryRunKey{}).(*Plan)
+	return p
+}
+
+// dryRunResult is the tool result the model sees for an intercepted call.
+func dryRunResult(desc string) string {
+	return "[dry run] Tool not executed. Would run: " + desc
+}
+
+// DescribeToolCall says what a tool call would do, from the shape of its
+// arguments: a shell-quoted command line for binary/command + args
+// (cli_execute and friends), "METHOD url" for HTTP-shaped arguments, and
+// otherwise the tool name followed by its arguments.
+func DescribeToolCall(tool string, args json.RawMessage) string {
+	var fields map[string]any
+	if err := json.Unmarshal(args, &fields); err != nil || len(fields) == 0 {
+		if s := strings.TrimSpace(string(args)); s != "" && s != "{}" {
+			return tool + " " + s
+		}
+		return tool
+	}
+
+	// A "command" string may already be a full shell line; a "binary"
+	// is a single word.
+	words := []string(nil)
+	if bin, _ := fields["binary"].(string); bin != "" {
+		words = append(words, shellQuote(bin))
+	} else if cmd, _ := fields["command"].(string); cmd != "" {
+		words = append(words, cmd)
+	}
+	if words != nil {
+		if list, ok := fields["args"].([]any); ok {
+			for _, a := range list {
+				if s, ok := a.(string); ok {
+					words = append(words, shellQuote(s))
+				} else {
+					b, _ := json.Marshal(a)
+					words = append(words, shellQuote(string(b)))
+				}
+			}
+		}
+		return strings.Join(words, " ")
+	}
+
+	if u, ok := fields["url"].(string); ok && u != "" {
+		method, _ := fields["method"].(string)
+		if method == "" {
+			method = "GET"
+		}
+		return strings.ToUpper(method) + " " + u
+	}
+
+	compact, err := json.Marshal(fields)
+	if err != nil {
+		return tool
+	}
+	return tool + " " + string(compact)
+}
+
+// shellQuote single-quotes s when it holds anything a shell would
+// interpret.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(s, " \t\n'\"\\$`;&|<>()*?[]{}~#!") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
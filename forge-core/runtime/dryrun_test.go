@@ -0,0 +1,94 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestDescribeToolCall(t *testing.T) {
+	for _, tc := range []struct {
+		tool, args, want string
+	}{
+		{"cli_execute", `{"binary":"kubectl","args":["delete","pod","web 1"]}`, `kubectl delete pod 'web 1'`},
+		{"shell", `{"command":"ls -la | wc -l"}`, `ls -la | wc -l`},
+		{"http_request", `{"method":"post","url":"https://api.example.com/v1/items"}`, `POST https://api.example.com/v1/items`},
+		{"web_fetch", `{"url":"https://example.com"}`, `GET https://example.com`},
+		{"memory_write", `{"text":"note"}`, `memory_write {"text":"note"}`},
+		{"datetime_now", `{}`, `datetime_now`},
+	} {
+		if got := DescribeToolCall(tc.tool, json.RawMessage(tc.args)); got != tc.want {
+			t.Errorf("DescribeToolCall(%s, %s) = %q, want %q", tc.tool, tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestDryRunInterceptsTools(t *testing.T) {
+	var toolResult string
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				return &llm.ChatResponse{
+					Message: llm.ChatMessage{
+						Role: llm.RoleAssistant,
+						ToolCalls: []llm.ToolCall{{
+							ID:   "call_1",
+							Type: "function",
+							Function: llm.FunctionCall{
+								Name:      "cli_execute",
+								Arguments: `{"binary":"kubectl","args":["scale","deploy/web","--replicas=0"]}`,
+							},
+						}},
+					},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			for _, m := range req.Messages {
+				if m.Role == llm.RoleTool {
+					toolResult = m.Content
+				}
+			}
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "Planned."},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			t.Fatalf("tool %s executed during a dry run", name)
+			return "", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "cli_execute"}}},
+	}
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools})
+
+	plan := NewPlan()
+	ctx := WithDryRun(context.Background(), plan)
+	_, err := executor.Execute(ctx, &a2a.Task{ID: "dry-1"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("scale web to zero")},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	want := "kubectl scale deploy/web --replicas=0"
+	if !strings.Contains(toolResult, "[dry run]") || !strings.Contains(toolResult, want) {
+		t.Errorf("tool result = %q, want a dry-run note naming %q", toolResult, want)
+	}
+	steps := plan.Steps()
+	if len(steps) != 1 || steps[0].Tool != "cli_execute" || steps[0].Description != want {
+		t.Fatalf("plan steps = %+v", steps)
+	}
+	art := plan.Artifact()
+	if art.Name != PlanArtifactName || art.Parts[0].Text != "1. "+want {
+		t.Errorf("artifact = %+v", art)
+	}
+}
@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"errors"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// CodedError tags an error with its a2a.ErrorCode. The message is the
+// wrapped error's, so tagging never changes what callers already see.
+type CodedError struct {
+	Code a2a.ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string { return e.Err.Error() }
+func (e *CodedError) Unwrap() error { return e.Err }
+
+// ErrorCode implements the interface ClassifyError looks for.
+func (e *CodedError) ErrorCode() a2a.ErrorCode { return e.Code }
+
+// WithErrorCode tags err with code. Returns nil for a nil err.
+func WithErrorCode(code a2a.ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// ToolError marks an error raised around a tool call — by a hook or the
+// tool itself — that ended the invocation. The tool name rides along
+// for logs; the message is the wrapped error's.
+type ToolError struct {
+	Tool string
+	Err  error
+}
+
+func (e *ToolError) Error() string { return e.Err.Error() }
+func (e *ToolError) Unwrap() error { return e.Err }
+
+// ClassifyError maps an invocation error onto the a2a error taxonomy.
+// An explicit code anywhere in the chain wins — a CodedError, or any
+// error with an ErrorCode() a2a.ErrorCode method, which is how packages
+// this one cannot import (step-up, OPA) tag their errors. Then model
+// provider errors, then ToolError; everything else is internal_error.
+// An error in the chain with an ErrorDetails() map[string]any method
+// supplies the TaskError's Details.
+func ClassifyError(err error) *a2a.TaskError {
+	if err == nil {
+		return nil
+	}
+	te := classifyError(err)
+	var detailed interface{ ErrorDetails() map[string]any }
+	if errors.As(err, &detailed) {
+		te.Details = detailed.ErrorDetails()
+	}
+	return te
+}
+
+func classifyError(err error) *a2a.TaskError {
+	msg := err.Error()
+	var coded interface{ ErrorCode() a2a.ErrorCode }
+	if errors.As(err, &coded) {
+		return a2a.NewTaskError(coded.ErrorCode(), msg)
+	}
+	if reason, ok := failoverReason(err); ok {
+		return a2a.NewTaskError(llmErrorCode(reason), msg)
+	}
+	var te *ToolError
+	if errors.As(err, &te) {
+		return a2a.NewTaskError(a2a.ErrorToolError, msg)
+	}
+	return a2a.NewTaskError(a2a.ErrorInternal, msg)
+}
+
+// LLMErrorCode classifies an error returned by a model client. Errors
+// the failover client already classified keep their reason; raw
+// provider errors are classified from their status and message.
+func LLMErrorCode(err error, provider, model string) a2a.ErrorCode {
+	if reason, ok := failoverReason(err); ok {
+		return llmErrorCode(reason)
+	}
+	return llmErrorCode(llm.ClassifyError(err, provider, model).Reason)
+}
+
+// failoverReason returns the reason of the failover error in err's
+// chain. For an exhausted fallback chain the last candidate's reason
+// is the one the caller hit.
+func failoverReason(err error) (llm.FailoverReason, bool) {
+	var exhausted *llm.FallbackExhaustedError
+	if errors.As(err, &exhausted) && len(exhausted.Errors) > 0 {
+		return exhausted.Errors[len(exhausted.Errors)-1].Reason, true
+	}
+	var fe *llm.FailoverError
+	if errors.As(err, &fe) {
+		return fe.Reason, true
+	}
+	return "", false
+}
+
+// llmErrorCode maps a provider failover reason onto the taxonomy.
+func llmErrorCode(reason llm.FailoverReason) a2a.ErrorCode {
+	switch reason {
+	case llm.FailoverRateLimit:
+		return a2a.ErrorLLMRateLimited
+	case llm.FailoverAuth:
+		return a2a.ErrorAuthFailed
+	case llm.FailoverBilling:
+		return a2a.ErrorBudgetExceeded
+	case llm.FailoverFormat:
+		return a2a.ErrorInternal
+	default:
+		return a2a.ErrorLLMUnavailable
+	}
+}
@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+type stepUpLike struct{}
+
+func (stepUpLike) Error() string            { return "step_up_required" }
+func (stepUpLike) ErrorCode() a2a.ErrorCode { return a2a.ErrorAuthFailed }
+
+func TestClassifyError(t *testing.T) {
+	guardrail := WithErrorCode(a2a.ErrorGuardrailViolation, errors.New("tool_call blocked: pii"))
+	for _, tc := range []struct {
+		name string
+		err  error
+		want a2a.ErrorCode
+	}{
+		{"coded", guardrail, a2a.ErrorGuardrailViolation},
+		{"code inside a tool error wins", &ToolError{Tool: "t", Err: fmt.Errorf("before tool exec hook: %w", guardrail)}, a2a.ErrorGuardrailViolation},
+		{"foreign ErrorCode method", fmt.Errorf("hook: %w", stepUpLike{}), a2a.ErrorAuthFailed},
+		{"plain tool error", &ToolError{Tool: "t", Err: errors.New("after tool exec hook: boom")}, a2a.ErrorToolError},
+		{"failover", &llm.FailoverError{Reason: llm.FailoverRateLimit, Wrapped: errors.New("429")}, a2a.ErrorLLMRateLimited},
+		{"exhausted", &llm.FallbackExhaustedError{Errors: []*llm.FailoverError{
+			{Reason: llm.FailoverRateLimit, Wrapped: errors.New("429")},
+			{Reason: llm.FailoverBilling, Wrapped: errors.New("402")},
+		}}, a2a.ErrorBudgetExceeded},
+		{"unclassified", errors.New("boom"), a2a.ErrorInternal},
+	} {
+		te := ClassifyError(tc.err)
+		if te.Code != tc.want {
+			t.Errorf("%s: code = %s, want %s", tc.name, te.Code, tc.want)
+		}
+		if te.Message != tc.err.Error() {
+			t.Errorf("%s: message = %q, want the error's own", tc.name, te.Message)
+		}
+	}
+	if ClassifyError(nil) != nil {
+		t.Error("nil error should classify to nil")
+	}
+}
+
+func TestLLMErrorCode_RawProviderError(t *testing.T) {
+	err := errors.New("openai error (status 429): slow down")
+	if got := LLMErrorCode(err, "openai", "gpt-4o"); got != a2a.ErrorLLMRateLimited {
+		t.Errorf("got %s, want llm_rate_limited", got)
+	}
+	if got := LLMErrorCode(errors.New("connection reset"), "openai", "gpt-4o"); got != a2a.ErrorLLMUnavailable {
+		t.Errorf("got %s, want llm_unavailable", got)
+	}
+}
@@ -0,0 +1,271 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventTopic names a class of event published on the EventBus.
+type EventTopic string
+
+const (
+	// TopicTaskStarted fires when an invocation starts running a task.
+	TopicTaskStarted EventTopic = "task.started"
+	// TopicTaskFinished fires when it ends; Fields["state"] is the
+	// task's final A2A state.
+	TopicTaskFinished EventTopic = "task.finished"
+	// TopicToolStart and TopicToolEnd bracket a tool call; Hook carries
+	// the tool name, input and (on end) output, error and duration.
+	TopicToolStart EventTopic = "tool.start"
+	TopicToolEnd   EventTopic = "tool.end"
+	// TopicLLMCall fires after each LLM call; Hook carries the response,
+	// provider, model and duration.
+	TopicLLMCall EventTopic = "llm.call"
+	// TopicLoopError fires when the agent loop reports an error.
+	TopicLoopError EventTopic = "loop.error"
+	// TopicScheduleFired and TopicScheduleCompleted bracket a scheduled
+	// run; Fields["schedule_id"] names the schedule and, on completion,
+	// Fields["success"] reports the outcome.
+	TopicScheduleFired     EventTopic = "schedule.fired"
+	TopicScheduleCompleted EventTopic = "schedule.completed"
+	// TopicGuardrailHit fires when a guardrail masks, blocks or warns;
+	// Fields carries gate, decision, guardrail and tool.
+	TopicGuardrailHit EventTopic = "guardrail.hit"
+	// TopicEgressBlocked fires when the egress enforcer or proxy refuses
+	// an outbound connection; Fields carries domain, mode and source.
+	TopicEgressBlocked EventTopic = "egress.blocked"
+)
+
+// Event is one occurrence on the EventBus. Subscribers must treat it as
+// read-only: the same value is delivered to every subscriber.
+type Event struct {
+	Topic         EventTopic
+	Time          time.Time
+	TaskID        string
+	CorrelationID string
+	// Hook is a snapshot of the agent loop's hook context for tool.*,
+	// llm.call and loop.error events; nil otherwise.
+	Hook   *HookContext
+	Fields map[string]any
+}
+
+// EventHandler receives the events of a subscription.
+type EventHandler func(ctx context.Context, e Event)
+
+// DefaultEventQueue is how many undelivered events an asynchronous
+// subscriber may fall behind by before further events are dropped.
+const DefaultEventQueue = 256
+
+// EventBus is the runtime's internal publish/subscribe channel. The
+// subsystems that report what the agent does (task execution, the
+// agent loop, schedules, guardrails) publish to it; the ones that
+// observe (logs, metrics, channel progress, audit) subscribe, so a new
+// integration is a subscriber rather than another call site.
+//
+// Hooks remain the way to intercept: a hook runs in line and can rewrite
+// or veto a call, a subscriber only observes. Publish never blocks on an
+// asynchronous subscriber — each has its own queue and goroutine, which
+// preserves order per subscriber and drops events when the queue is
+// full — so a slow consumer cannot stall the agent loop. Synchronous
+// subscribers run on the publisher's goroutine and are for consumers
+// that must not lose events and are cheap, such as the audit stream.
+//
+// A nil *EventBus is valid: Publish is a no-op.
+type EventBus struct {
+	mu      sync.RWMutex
+	subs    map[int]*subscription
+	next    int
+	closed  bool
+	dropped atomic.Int64
+	wg      sync.WaitGroup
+}
+
+type subscription struct {
+	topics map[EventTopic]bool // nil = every topic
+	fn     EventHandler
+	queue  chan queuedEvent // nil for synchronous subscribers
+	done   chan struct{}
+}
+
+type queuedEvent struct {
+	ctx context.Context
+	e   Event
+}
+
+// NewEventBus creates an EventBus with no subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[int]*subscription)}
+}
+
+// Subscribe registers an asynchronous handler for the given topics (all
+// topics when none are given) and returns a function that removes it.
+// Events still queued when it is removed are discarded.
+func (b *EventBus) Subscribe(fn EventHandler, topics ...EventTopic) func() {
+	sub := newSubscription(fn, topics)
+	sub.queue = make(chan queuedEvent, DefaultEventQueue)
+	sub.done = make(chan struct{})
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case q, ok := <-sub.queue:
+				if !ok {
+					return
+				}
+				sub.fn(q.ctx, q.e)
+			case <-sub.done:
+				return
+			}
+		}
+	}()
+	return b.add(sub)
+}
+
+// SubscribeSync registers a handler that runs on the publisher's
+// goroutine, with the publisher's context, before Publish returns.
+func (b *EventBus) SubscribeSync(fn EventHandler, topics ...EventTopic) func() {
+	return b.add(newSubscription(fn, topics))
+}
+
+func newSubscription(fn EventHandler, topics []EventTopic) *subscription {
+	sub := &subscription{fn: fn}
+	if len(topics) > 0 {
+		sub.topics = make(map[EventTopic]bool, len(topics))
+		for _, t := range topics {
+			sub.topics[t] = true
+		}
+	}
+	return sub
+}
+
+func (b *EventBus) add(sub *subscription) func() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		if sub.queue != nil {
+			close(sub.queue)
+		}
+		return func() {}
+	}
+	id := b.next
+	b.next++
+	b.subs[id] = sub
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			_, live := b.subs[id]
+			delete(b.subs, id)
+			b.mu.Unlock()
+			if live && sub.done != nil {
+				close(sub.done)
+			}
+		})
+	}
+}
+
+// Publish delivers e to every subscriber of its topic. Time defaults to
+// now. Asynchronous subscribers receive the context without its
+// cancellation, since they may run after the publisher has returned.
+func (b *EventBus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	var inline []*subscription
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return
+	}
+	var detached context.Context
+	for _, sub := range b.subs {
+		if sub.topics != nil && !sub.topics[e.Topic] {
+			continue
+		}
+		if sub.queue == nil {
+			inline = append(inline, sub)
+			continue
+		}
+		if detached == nil {
+			detached = context.WithoutCancel(ctx)
+		}
+		select {
+		case sub.queue <- queuedEvent{ctx: detached, e: e}:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+	b.mu.RUnlock()
+	// Outside the lock, so a synchronous handler may publish or
+	// unsubscribe.
+	for _, sub := range inline {
+		sub.fn(ctx, e)
+	}
+}
+
+// Dropped reports how many events asynchronous subscribers have missed
+// because their queue was full.
+func (b *EventBus) Dropped() int64 {
+	if b == nil {
+		return 0
+	}
+	return b.dropped.Load()
+}
+
+// Close stops accepting events, lets asynchronous subscribers drain
+// what is already queued, and waits for them to finish.
+func (b *EventBus) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for id, sub := range b.subs {
+		if sub.queue != nil {
+			close(sub.queue)
+		}
+		delete(b.subs, id)
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// PublishHooks registers hooks that publish the agent loop's tool,
+// LLM and error events. Register it before hooks that rewrite tool
+// input so subscribers see what the model asked for. Each event carries
+// a copy of the hook context, so later hooks' edits do not reach
+// subscribers that run after the loop has moved on.
+func (b *EventBus) PublishHooks(hooks *HookRegistry) {
+	publish := func(topic EventTopic) Hook {
+		return func(ctx context.Context, hctx *HookContext) error {
+			snap := *hctx
+			if hctx.Response != nil {
+				resp := *hctx.Response
+				snap.Response = &resp
+			}
+			b.Publish(ctx, Event{
+				Topic:         topic,
+				TaskID:        hctx.TaskID,
+				CorrelationID: hctx.CorrelationID,
+				Hook:          &snap,
+			})
+			return nil
+		}
+	}
+	hooks.Register(BeforeToolExec, publish(TopicToolStart))
+	hooks.Register(AfterToolExec, publish(TopicToolEnd))
+	hooks.Register(AfterLLMCall, publish(TopicLLMCall))
+	hooks.Register(OnError, publish(TopicLoopError))
+}
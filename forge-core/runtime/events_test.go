@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestEventBus_TopicsAndOrder(t *testing.T) {
+	bus := NewEventBus()
+	var inline, queued []EventTopic
+	bus.SubscribeSync(func(_ context.Context, e Event) { inline = append(inline, e.Topic) }, TopicToolStart, TopicToolEnd)
+	bus.Subscribe(func(_ context.Context, e Event) { queued = append(queued, e.Topic) })
+
+	ctx := context.Background()
+	bus.Publish(ctx, Event{Topic: TopicToolStart})
+	bus.Publish(ctx, Event{Topic: TopicTaskStarted})
+	bus.Publish(ctx, Event{Topic: TopicToolEnd})
+	if len(inline) != 2 || inline[0] != TopicToolStart || inline[1] != TopicToolEnd {
+		t.Errorf("sync subscriber got %v, want [tool.start tool.end] before Publish returned", inline)
+	}
+
+	// Close drains the queue before returning.
+	bus.Close()
+	want := []EventTopic{TopicToolStart, TopicTaskStarted, TopicToolEnd}
+	if len(queued) != len(want) {
+		t.Fatalf("async subscriber got %v, want %v", queued, want)
+	}
+	for i := range want {
+		if queued[i] != want[i] {
+			t.Errorf("async subscriber got %v, want %v in order", queued, want)
+			break
+		}
+	}
+
+	// A closed bus drops publishes and refuses subscribers.
+	bus.Publish(ctx, Event{Topic: TopicToolStart})
+	bus.Subscribe(func(context.Context, Event) { t.Error("delivered after Close") })()
+	if len(inline) != 2 {
+		t.Errorf("published after Close: %v", inline)
+	}
+}
+
+func TestEventBus_DropsWhenFull(t *testing.T) {
+	bus := NewEventBus()
+	release := make(chan struct{})
+	got := 0
+	bus.Subscribe(func(context.Context, Event) {
+		<-release
+		got++
+	})
+	// One event is in the handler, DefaultEventQueue fill the queue, the
+	// rest are dropped rather than blocking the publisher.
+	for range DefaultEventQueue + 10 {
+		bus.Publish(context.Background(), Event{Topic: TopicLLMCall})
+	}
+	close(release)
+	bus.Close()
+	if dropped := bus.Dropped(); dropped < 9 || int(dropped)+got != DefaultEventQueue+10 {
+		t.Errorf("delivered %d, dropped %d of %d", got, dropped, DefaultEventQueue+10)
+	}
+}
+
+func TestEventBus_Unsubscribe(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+	calls := 0
+	cancel := bus.SubscribeSync(func(context.Context, Event) { calls++ })
+	bus.Publish(context.Background(), Event{Topic: TopicGuardrailHit})
+	cancel()
+	cancel() // idempotent
+	bus.Publish(context.Background(), Event{Topic: TopicGuardrailHit})
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	var nilBus *EventBus
+	nilBus.Publish(context.Background(), Event{Topic: TopicGuardrailHit}) // no panic
+}
+
+func TestEventBus_PublishHooks(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+	var events []Event
+	bus.SubscribeSync(func(_ context.Context, e Event) { events = append(events, e) })
+
+	hooks := NewHookRegistry()
+	bus.PublishHooks(hooks)
+	// A later hook rewrites the input; the published snapshot keeps
+	// what the model asked for.
+	hooks.Register(BeforeToolExec, func(_ context.Context, hctx *HookContext) error {
+		hctx.ToolInput = "[redacted]"
+		return nil
+	})
+
+	ctx := context.Background()
+	hctx := &HookContext{ToolName: "http_request", ToolInput: `{"url":"x"}`, TaskID: "t1"}
+	if err := hooks.Fire(ctx, BeforeToolExec, hctx); err != nil {
+		t.Fatal(err)
+	}
+	resp := &llm.ChatResponse{Message: llm.ChatMessage{Content: "hi"}}
+	_ = hooks.Fire(ctx, AfterLLMCall, &HookContext{Response: resp})
+	resp.Message.Content = "rewritten"
+	_ = hooks.Fire(ctx, OnError, &HookContext{Error: errors.New("boom")})
+
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	if e := events[0]; e.Topic != TopicToolStart || e.TaskID != "t1" || e.Hook.ToolInput != `{"url":"x"}` {
+		t.Errorf("tool.start = %+v (hook %+v)", e, e.Hook)
+	}
+	if e := events[1]; e.Topic != TopicLLMCall || e.Hook.Response.Message.Content != "hi" {
+		t.Errorf("llm.call should snapshot the response, got %+v", e.Hook.Response)
+	}
+	if e := events[2]; e.Topic != TopicLoopError || e.Hook.Error == nil {
+		t.Errorf("loop.error = %+v", e)
+	}
+}
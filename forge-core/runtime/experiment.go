@@ -0,0 +1,128 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// ExperimentAssignment is the experiment variant a task runs with. It
+// is recorded in the task's metadata (a2a.MetadataKeyExperiment), on
+// its audit events and in the agent's usage ledger.
+type ExperimentAssignment struct {
+	Experiment string `json:"id"`
+	Variant    string `json:"variant"`
+}
+
+// String is the assignment as "<experiment>/<variant>", the form audit
+// events and usage ledgers carry.
+func (a ExperimentAssignment) String() string {
+	return a.Experiment + "/" + a.Variant
+}
+
+type experimentKey struct{}
+
+// WithExperiment stores the task's experiment variant in the context.
+// A nil assignment leaves the context as it is.
+func WithExperiment(ctx context.Context, a *ExperimentAssignment) context.Context {
+	if a == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, experimentKey{}, *a)
+}
+
+// ExperimentFromContext returns the experiment variant the task runs
+// with, or nil outside any experiment.
+func ExperimentFromContext(ctx context.Context) *ExperimentAssignment {
+	if a, ok := ctx.Value(experimentKey{}).(ExperimentAssignment); ok {
+		return &a
+	}
+	return nil
+}
+
+// AssignExperiment enrolls a task in the first experiment whose when
+// matches its request metadata md and picks its variant: the first
+// variant whose own when matches, else the one the task's bucket falls
+// in. Tasks are bucketed by md[sticky_key], or by taskID without it, so
+// every turn of a task (and every task of a sticky user) gets the same
+// variant while the config is unchanged. It returns nils when no
+// experiment applies.
+func AssignExperiment(exps []types.ExperimentConfig, taskID string, md map[string]any) (*types.ExperimentConfig, *types.ExperimentVariant) {
+	for i := range exps {
+		exp := &exps[i]
+		if !experimentMatch(exp.When).MatchesMetadata(md) {
+			continue
+		}
+		for j := range exp.Variants {
+			if w := exp.Variants[j].When; w != nil && experimentMatch(*w).MatchesMetadata(md) {
+				return exp, &exp.Variants[j]
+			}
+		}
+		key := taskID
+		if exp.StickyKey != "" {
+			if v, ok := md[exp.StickyKey]; ok {
+				key = fmt.Sprint(v)
+			}
+		}
+		if v := bucketVariant(exp, experimentBucket(exp.ID, key)); v != nil {
+			return exp, v
+		}
+	}
+	return nil, nil
+}
+
+// FindExperimentVariant returns the experiment and variant an earlier
+// turn recorded, or nils when the config no longer has them.
+func FindExperimentVariant(exps []types.ExperimentConfig, a ExperimentAssignment) (*types.ExperimentConfig, *types.ExperimentVariant) {
+	for i := range exps {
+		if exps[i].ID != a.Experiment {
+			continue
+		}
+		for j := range exps[i].Variants {
+			if exps[i].Variants[j].ID == a.Variant {
+				return &exps[i], &exps[i].Variants[j]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// bucketVariant returns the variant bucket (0-99) falls in: by the
+// variants' weights, or split evenly between the variants without a
+// when when no variant has a weight.
+func bucketVariant(exp *types.ExperimentConfig, bucket int) *types.ExperimentVariant {
+	var even []*types.ExperimentVariant
+	weighted := false
+	for j := range exp.Variants {
+		v := &exp.Variants[j]
+		switch {
+		case v.Weight > 0:
+			weighted = true
+			if bucket < v.Weight {
+				return v
+			}
+			bucket -= v.Weight
+		case v.When == nil:
+			even = append(even, v)
+		}
+	}
+	if weighted || len(even) == 0 {
+		return nil
+	}
+	return even[bucket%len(even)]
+}
+
+func experimentMatch(m types.ExperimentMatch) *llm.RouteCondition {
+	return &llm.RouteCondition{Metadata: m.Metadata, Tags: m.Tags}
+}
+
+// experimentBucket maps key to one of 100 buckets, salted with the
+// experiment ID so experiments split independently.
+func experimentBucket(experiment, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(experiment + "\x00" + key)) //nolint:errcheck
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestAssignExperiment(t *testing.T) {
+	exps := []types.ExperimentConfig{
+		{
+			ID:   "enterprise",
+			When: types.ExperimentMatch{Metadata: map[string]string{"plan": "enterprise"}},
+			Variants: []types.ExperimentVariant{
+				{ID: "control"},
+				{ID: "opus", Model: &types.ExperimentModel{Name: "claude-opus-4-5"}},
+			},
+		},
+		{
+			ID:        "tone",
+			StickyKey: "user_id",
+			Variants: []types.ExperimentVariant{
+				{ID: "control", Weight: 80},
+				{ID: "terse", Weight: 20, Prompt: "variants/terse"},
+				{ID: "vip", When: &types.ExperimentMatch{Tags: []string{"vip"}}},
+			},
+		},
+	}
+
+	counts := map[string]int{}
+	for i := range 1000 {
+		exp, v := AssignExperiment(exps, fmt.Sprintf("task-%d", i), nil)
+		if exp == nil || exp.ID != "tone" {
+			t.Fatalf("task-%d enrolled in %v", i, exp)
+		}
+		counts[v.ID]++
+	}
+	if counts["vip"] != 0 || counts["terse"] < 150 || counts["terse"] > 250 {
+		t.Errorf("80/20 split = %v", counts)
+	}
+
+	_, v1 := AssignExperiment(exps, "task-a", map[string]any{"user_id": "u1"})
+	for i := range 20 {
+		if _, v := AssignExperiment(exps, fmt.Sprintf("task-%d", i), map[string]any{"user_id": "u1"}); v != v1 {
+			t.Fatalf("sticky user got %s and %s", v1.ID, v.ID)
+		}
+	}
+
+	if _, v := AssignExperiment(exps, "task-1", map[string]any{"tags": []any{"vip"}}); v.ID != "vip" {
+		t.Errorf("vip task got %s", v.ID)
+	}
+	if exp, _ := AssignExperiment(exps, "task-1", map[string]any{"plan": "enterprise"}); exp.ID != "enterprise" {
+		t.Errorf("enterprise task enrolled in %s", exp.ID)
+	}
+	if exp, v := AssignExperiment(nil, "task-1", nil); exp != nil || v != nil {
+		t.Error("enrolled without experiments")
+	}
+
+	if _, v := FindExperimentVariant(exps, ExperimentAssignment{"tone", "terse"}); v == nil || v.Prompt != "variants/terse" {
+		t.Errorf("FindExperimentVariant = %v", v)
+	}
+	if exp, _ := FindExperimentVariant(exps, ExperimentAssignment{"tone", "gone"}); exp != nil {
+		t.Error("found a removed variant")
+	}
+}
+
+func TestExperimentContext(t *testing.T) {
+	ctx := context.Background()
+	if ExperimentFromContext(WithExperiment(ctx, nil)) != nil {
+		t.Error("nil assignment stored")
+	}
+	ctx = WithExperiment(ctx, &ExperimentAssignment{Experiment: "tone", Variant: "terse"})
+	if got := ExperimentFromContext(ctx); got == nil || got.String() != "tone/terse" {
+		t.Errorf("ExperimentFromContext = %v", got)
+	}
+}
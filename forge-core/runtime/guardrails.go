@@ -222,11 +222,12 @@ type GuardrailChecker interface {
 	// CheckStream validates a single chunk emitted by a streaming
 	// LLM call — StreamGate. Returns the (possibly redacted) chunk.
 	//
+	// Callers streaming a response should not call it per token:
+	// StreamGuard / GuardStream buffer the stream into windows, call
+	// CheckStream once per window, and abort the stream on a block.
 	// Forge's current Execute loop does not call provider streaming
 	// (ExecuteStream is a buffered wrapper around non-streaming
-	// Execute), so this is not auto-wired yet. The method is exposed
-	// for callers that consume llm.Client.ChatStream directly and
-	// for future loop work that adds a real per-chunk seam.
+	// Execute), so the loop itself has no stream to guard yet.
 	CheckStream(ctx context.Context, chunk string) (string, error)
 }
 
@@ -28,6 +28,7 @@ type HookContext struct {
 	Messages      []llm.ChatMessage
 	Response      *llm.ChatResponse
 	ToolName      string
+	ToolCallID    string
 	ToolInput     string
 	ToolOutput    string
 	Error         error
@@ -45,6 +46,10 @@ type HookContext struct {
 	// ToolExecDuration is the wall-clock time spent executing the tool.
 	// Populated for AfterToolExec hooks.
 	ToolExecDuration time.Duration
+	// ContextUsage is how full the task's context window is. Populated
+	// for BeforeToolExec and AfterToolExec hooks (before the tool's
+	// result is added) so progress emitters can report it.
+	ContextUsage *ContextUsage
 }
 
 // Hook is a function invoked at a specific point in the agent loop.
@@ -78,11 +83,48 @@ func (r *HookRegistry) Fire(ctx context.Context, point HookPoint, hctx *HookCont
 	return nil
 }
 
+// ProgressPhase is the kind of a ProgressEvent.
+type ProgressPhase string
+
+// Progress event kinds.
+const (
+	// ProgressPlanning is the model's first call of a task run, before
+	// any tool has run.
+	ProgressPlanning ProgressPhase = "planning"
+	// ProgressLLMThinking is a later model call, working from tool
+	// results.
+	ProgressLLMThinking ProgressPhase = "llm_thinking"
+	ProgressToolStart   ProgressPhase = "tool_start"
+	// ProgressToolStream is output or a progress report from a tool
+	// that is still running.
+	ProgressToolStream ProgressPhase = "tool_stream"
+	ProgressToolEnd    ProgressPhase = "tool_end"
+	// ProgressCompaction is the loop freeing context: compacting the
+	// conversation or summarizing a bulky tool result.
+	ProgressCompaction ProgressPhase = "compaction"
+	// ProgressPlan reports a planner-executor task's plan: created, a
+	// step started or failed, or re-planned. The event carries the plan.
+	ProgressPlan ProgressPhase = "plan"
+	// ProgressReflection is the reflection pass reviewing, and possibly
+	// revising, the final answer.
+	ProgressReflection ProgressPhase = "reflection"
+)
+
 // ProgressEvent describes a progress update during task execution.
 type ProgressEvent struct {
-	Phase   string // "tool_start", "tool_end"
+	Phase   ProgressPhase
 	Tool    string
 	Message string
+	// Percent is a running tool's own completion estimate, 0-100, when
+	// it reports one (see ReportProgress).
+	Percent *int
+	// Iteration is the agent-loop iteration the event belongs to,
+	// 1-based, out of at most MaxIterations. Zero outside the loop.
+	Iteration     int
+	MaxIterations int
+	Context       *ContextUsage // context window utilization, when known
+	// Plan is a snapshot of the task's plan on ProgressPlan events.
+	Plan *TaskPlan
 }
 
 // ProgressEmitter is a callback that emits progress events to the client.
@@ -0,0 +1,279 @@
+package runtime
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Ops-log subsystems whose level can be adjusted independently of the
+// process-wide level, so one hot path can be debugged without turning
+// every other subsystem verbose.
+const (
+	LogSubsystemExecutor  = "executor"  // agent loop, LLM calls, compaction
+	LogSubsystemTools     = "tools"     // tool call / result lines
+	LogSubsystemEgress    = "egress"    // outbound-connection decisions
+	LogSubsystemScheduler = "scheduler" // cron fires and backend sync
+	LogSubsystemChannels  = "channels"  // channel adapters (Slack, Telegram)
+)
+
+// LogEventEgressAllowed is the `event` field carried by the per-connection
+// "egress allowed" debug line — the highest-volume entry the runtime
+// writes, and the canonical sampling target.
+const LogEventEgressAllowed = "egress_allowed"
+
+// logSubsystems is the closed set accepted by SetSubsystemLevel.
+var logSubsystems = []string{
+	LogSubsystemChannels,
+	LogSubsystemEgress,
+	LogSubsystemExecutor,
+	LogSubsystemScheduler,
+	LogSubsystemTools,
+}
+
+// samplableLogEvents is the closed set accepted by SetSampling. Sampling
+// is restricted to known high-volume events so it can never silently
+// thin out error or lifecycle lines.
+var samplableLogEvents = []string{
+	LogEventEgressAllowed,
+}
+
+// LogSubsystems returns the subsystem names accepted by
+// LogControls.SetSubsystemLevel, sorted.
+func LogSubsystems() []string {
+	return append([]string(nil), logSubsystems...)
+}
+
+// LogLevel is an ops-log severity. Higher values are more severe.
+type LogLevel int
+
+// Log levels, least to most severe.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the level name as written in the `level` field.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// ParseLogLevel parses a level name (debug, info, warn, error),
+// case-insensitively.
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn, or error", s)
+}
+
+// LogControls holds the runtime-adjustable knobs for ops logging: a
+// process-wide minimum level, per-subsystem level overrides, and 1-in-N
+// sampling for high-volume events. All methods are safe for concurrent
+// use; changes take effect on the next log call.
+//
+// Controls apply to the ops log (stdout) only. Audit events are never
+// filtered or sampled — the audit stream is a complete record by design.
+type LogControls struct {
+	mu       sync.RWMutex
+	level    LogLevel
+	levels   map[string]LogLevel
+	sampling map[string]int
+	counters sync.Map // event → *atomic.Uint64
+}
+
+// NewLogControls returns controls at info level, or debug when verbose,
+// with no overrides and no sampling.
+func NewLogControls(verbose bool) *LogControls {
+	level := LogLevelInfo
+	if verbose {
+		level = LogLevelDebug
+	}
+	return &LogControls{
+		level:    level,
+		levels:   make(map[string]LogLevel),
+		sampling: make(map[string]int),
+	}
+}
+
+// Enabled reports whether an entry at level from subsystem is written.
+// An empty subsystem uses the process-wide level.
+func (c *LogControls) Enabled(subsystem string, level LogLevel) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	floor := c.level
+	if l, ok := c.levels[subsystem]; ok {
+		floor = l
+	}
+	return level >= floor
+}
+
+// SetLevel sets the process-wide minimum level.
+func (c *LogControls) SetLevel(level LogLevel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.level = level
+}
+
+// SetSubsystemLevel overrides the minimum level for one subsystem.
+func (c *LogControls) SetSubsystemLevel(subsystem string, level LogLevel) error {
+	if !slices.Contains(logSubsystems, subsystem) {
+		return fmt.Errorf("unknown log subsystem %q: must be one of %s", subsystem, strings.Join(logSubsystems, ", "))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.levels[subsystem] = level
+	return nil
+}
+
+// ClearSubsystemLevel removes subsystem's override so it follows the
+// process-wide level again.
+func (c *LogControls) ClearSubsystemLevel(subsystem string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.levels, subsystem)
+}
+
+// SetSampling keeps one in every n entries carrying `"event": event`.
+// n <= 1 disables sampling for the event.
+func (c *LogControls) SetSampling(event string, n int) error {
+	if !slices.Contains(samplableLogEvents, event) {
+		return fmt.Errorf("log event %q does not support sampling: must be one of %s", event, strings.Join(samplableLogEvents, ", "))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n <= 1 {
+		delete(c.sampling, event)
+		return nil
+	}
+	c.sampling[event] = n
+	return nil
+}
+
+// sample reports whether the next entry for event is kept, and the
+// sampling rate in force (0 when the event is not sampled). The first
+// entry after sampling is enabled is always kept.
+func (c *LogControls) sample(event string) (keep bool, rate int) {
+	c.mu.RLock()
+	n := c.sampling[event]
+	c.mu.RUnlock()
+	if n <= 1 {
+		return true, 0
+	}
+	v, _ := c.counters.LoadOrStore(event, new(atomic.Uint64))
+	seq := v.(*atomic.Uint64).Add(1) - 1
+	return seq%uint64(n) == 0, n
+}
+
+// LogSettings is the JSON view of LogControls served by the admin
+// logging endpoint.
+type LogSettings struct {
+	Level      string            `json:"level"`
+	Subsystems map[string]string `json:"subsystems"`
+	Sampling   map[string]int    `json:"sampling"`
+}
+
+// Settings returns a snapshot of the current controls.
+func (c *LogControls) Settings() LogSettings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s := LogSettings{
+		Level:      c.level.String(),
+		Subsystems: make(map[string]string, len(c.levels)),
+		Sampling:   make(map[string]int, len(c.sampling)),
+	}
+	for k, v := range c.levels {
+		s.Subsystems[k] = v.String()
+	}
+	for k, v := range c.sampling {
+		s.Sampling[k] = v
+	}
+	return s
+}
+
+// LogSettingsUpdate is a partial change to LogControls. Omitted fields
+// are left as they are. A subsystem mapped to "" clears its override;
+// a sampling rate of 0 or 1 disables sampling for that event.
+type LogSettingsUpdate struct {
+	Level      string            `json:"level,omitempty"`
+	Subsystems map[string]string `json:"subsystems,omitempty"`
+	Sampling   map[string]int    `json:"sampling,omitempty"`
+}
+
+// Apply validates u in full and then applies it, so a request with one
+// bad entry changes nothing.
+func (c *LogControls) Apply(u LogSettingsUpdate) error {
+	var level LogLevel
+	if u.Level != "" {
+		l, err := ParseLogLevel(u.Level)
+		if err != nil {
+			return err
+		}
+		level = l
+	}
+	levels := make(map[string]LogLevel, len(u.Subsystems))
+	for _, name := range slices.Sorted(maps.Keys(u.Subsystems)) {
+		if !slices.Contains(logSubsystems, name) {
+			return fmt.Errorf("unknown log subsystem %q: must be one of %s", name, strings.Join(logSubsystems, ", "))
+		}
+		if u.Subsystems[name] == "" {
+			continue
+		}
+		l, err := ParseLogLevel(u.Subsystems[name])
+		if err != nil {
+			return fmt.Errorf("subsystem %s: %w", name, err)
+		}
+		levels[name] = l
+	}
+	for event, n := range u.Sampling {
+		if !slices.Contains(samplableLogEvents, event) {
+			return fmt.Errorf("log event %q does not support sampling: must be one of %s", event, strings.Join(samplableLogEvents, ", "))
+		}
+		if n < 0 {
+			return fmt.Errorf("sampling rate for %s must be >= 0, got %d", event, n)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if u.Level != "" {
+		c.level = level
+	}
+	for name, raw := range u.Subsystems {
+		if raw == "" {
+			delete(c.levels, name)
+		} else {
+			c.levels[name] = levels[name]
+		}
+	}
+	for event, n := range u.Sampling {
+		if n <= 1 {
+			delete(c.sampling, event)
+		} else {
+			c.sampling[event] = n
+		}
+	}
+	return nil
+}
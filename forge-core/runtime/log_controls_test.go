@@ -0,0 +1,138 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestJSONLogger_SubsystemLevelOverride(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSONLogger(&buf, false)
+	egress := l.Subsystem(LogSubsystemEgress)
+	tools := l.Subsystem(LogSubsystemTools)
+
+	egress.Debug("dropped", nil)
+	if buf.Len() != 0 {
+		t.Fatalf("debug written at info level: %s", buf.String())
+	}
+
+	if err := l.Controls().SetSubsystemLevel(LogSubsystemEgress, LogLevelDebug); err != nil {
+		t.Fatal(err)
+	}
+	egress.Debug("kept", nil)
+	tools.Debug("still dropped", nil)
+	l.Debug("root still dropped", nil)
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %s", len(lines), buf.String())
+	}
+	if lines[0]["msg"] != "kept" || lines[0]["subsystem"] != "egress" || lines[0]["level"] != "debug" {
+		t.Errorf("entry = %v", lines[0])
+	}
+
+	// A quieter override suppresses info for that subsystem only.
+	buf.Reset()
+	if err := l.Controls().SetSubsystemLevel(LogSubsystemTools, LogLevelWarn); err != nil {
+		t.Fatal(err)
+	}
+	tools.Info("quiet", nil)
+	l.Info("root", nil)
+	if lines := decodeLogLines(t, &buf); len(lines) != 1 || lines[0]["msg"] != "root" {
+		t.Errorf("lines = %v", lines)
+	}
+
+	if err := l.Controls().SetSubsystemLevel("bogus", LogLevelDebug); err == nil {
+		t.Error("unknown subsystem accepted")
+	}
+}
+
+func TestJSONLogger_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSONLogger(&buf, true)
+	egress := l.Subsystem(LogSubsystemEgress)
+
+	if err := l.Controls().SetSampling(LogEventEgressAllowed, 10); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 25; i++ {
+		egress.Debug("egress allowed", map[string]any{"event": LogEventEgressAllowed, "domain": "api.example.com"})
+	}
+	// Unsampled events pass through untouched.
+	egress.Info("egress blocked", map[string]any{"event": "egress_blocked"})
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 3 sampled + 1 unsampled", len(lines))
+	}
+	if rate, _ := lines[0]["sample_rate"].(float64); rate != 10 {
+		t.Errorf("sample_rate = %v, want 10", lines[0]["sample_rate"])
+	}
+	if _, ok := lines[3]["sample_rate"]; ok {
+		t.Error("unsampled entry carries sample_rate")
+	}
+
+	if err := l.Controls().SetSampling("tool_exec", 10); err == nil {
+		t.Error("sampling accepted for a non-samplable event")
+	}
+}
+
+func TestLogControls_Apply(t *testing.T) {
+	c := NewLogControls(false)
+	if err := c.Apply(LogSettingsUpdate{
+		Level:      "warn",
+		Subsystems: map[string]string{"egress": "debug", "scheduler": "error"},
+		Sampling:   map[string]int{LogEventEgressAllowed: 50},
+	}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	s := c.Settings()
+	if s.Level != "warn" || s.Subsystems["egress"] != "debug" || s.Subsystems["scheduler"] != "error" || s.Sampling[LogEventEgressAllowed] != 50 {
+		t.Errorf("settings = %+v", s)
+	}
+
+	// One bad entry rejects the whole update.
+	err := c.Apply(LogSettingsUpdate{
+		Level:      "debug",
+		Subsystems: map[string]string{"egress": "info", "nope": "info"},
+	})
+	if err == nil {
+		t.Fatal("Apply accepted an unknown subsystem")
+	}
+	if got := c.Settings(); got.Level != "warn" || got.Subsystems["egress"] != "debug" {
+		t.Errorf("partial apply leaked: %+v", got)
+	}
+
+	// Empty level clears an override; rate 0 disables sampling.
+	if err := c.Apply(LogSettingsUpdate{
+		Subsystems: map[string]string{"egress": ""},
+		Sampling:   map[string]int{LogEventEgressAllowed: 0},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Settings(); len(got.Subsystems) != 1 || len(got.Sampling) != 0 {
+		t.Errorf("settings after clear = %+v", got)
+	}
+
+	if err := c.Apply(LogSettingsUpdate{Level: "loud"}); err == nil {
+		t.Error("invalid level accepted")
+	}
+}
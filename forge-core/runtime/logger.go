@@ -17,33 +17,73 @@ type Logger interface {
 
 // JSONLogger writes structured JSON log entries to an io.Writer.
 type JSONLogger struct {
-	mu      sync.Mutex
-	w       io.Writer
-	verbose bool
+	mu       sync.Mutex
+	w        io.Writer
+	controls *LogControls
 }
 
 // NewJSONLogger creates a JSONLogger writing to w. Debug entries are only
 // emitted when verbose is true.
 func NewJSONLogger(w io.Writer, verbose bool) *JSONLogger {
-	return &JSONLogger{w: w, verbose: verbose}
+	return NewJSONLoggerWithControls(w, NewLogControls(verbose))
 }
 
-func (l *JSONLogger) Info(msg string, fields map[string]any)  { l.log("info", msg, fields) }
-func (l *JSONLogger) Warn(msg string, fields map[string]any)  { l.log("warn", msg, fields) }
-func (l *JSONLogger) Error(msg string, fields map[string]any) { l.log("error", msg, fields) }
+// NewJSONLoggerWithControls creates a JSONLogger whose level, per-subsystem
+// overrides, and sampling are governed by controls. Loggers sharing one
+// LogControls see the same runtime adjustments.
+func NewJSONLoggerWithControls(w io.Writer, controls *LogControls) *JSONLogger {
+	return &JSONLogger{w: w, controls: controls}
+}
+
+// Controls returns the LogControls governing l.
+func (l *JSONLogger) Controls() *LogControls { return l.controls }
+
+// Subsystem returns a Logger that tags every entry with
+// `"subsystem": name` and filters against name's level override.
+func (l *JSONLogger) Subsystem(name string) Logger {
+	return &subsystemLogger{parent: l, subsystem: name}
+}
+
+func (l *JSONLogger) Info(msg string, fields map[string]any) {
+	l.logAt("", LogLevelInfo, msg, fields)
+}
+
+func (l *JSONLogger) Warn(msg string, fields map[string]any) {
+	l.logAt("", LogLevelWarn, msg, fields)
+}
+
+func (l *JSONLogger) Error(msg string, fields map[string]any) {
+	l.logAt("", LogLevelError, msg, fields)
+}
 
 func (l *JSONLogger) Debug(msg string, fields map[string]any) {
-	if !l.verbose {
+	l.logAt("", LogLevelDebug, msg, fields)
+}
+
+// logAt applies the level gate and event sampling for subsystem, then
+// writes the entry.
+func (l *JSONLogger) logAt(subsystem string, level LogLevel, msg string, fields map[string]any) {
+	if !l.controls.Enabled(subsystem, level) {
 		return
 	}
-	l.log("debug", msg, fields)
-}
+	var rate int
+	if event, ok := fields["event"].(string); ok {
+		var keep bool
+		if keep, rate = l.controls.sample(event); !keep {
+			return
+		}
+	}
 
-func (l *JSONLogger) log(level, msg string, fields map[string]any) {
-	entry := make(map[string]any, len(fields)+3)
+	entry := make(map[string]any, len(fields)+5)
 	entry["time"] = time.Now().UTC().Format(time.RFC3339)
-	entry["level"] = level
+	entry["level"] = level.String()
 	entry["msg"] = msg
+	if subsystem != "" {
+		entry["subsystem"] = subsystem
+	}
+	if rate > 1 {
+		entry["sample_rate"] = rate
+	}
 	for k, v := range fields {
 		entry[k] = v
 	}
@@ -54,3 +94,34 @@ func (l *JSONLogger) log(level, msg string, fields map[string]any) {
 	data = append(data, '\n')
 	l.w.Write(data) //nolint:errcheck
 }
+
+// subsystemLogger is the Logger returned by JSONLogger.Subsystem.
+type subsystemLogger struct {
+	parent    *JSONLogger
+	subsystem string
+}
+
+func (s *subsystemLogger) Info(msg string, fields map[string]any) {
+	s.parent.logAt(s.subsystem, LogLevelInfo, msg, fields)
+}
+
+func (s *subsystemLogger) Warn(msg string, fields map[string]any) {
+	s.parent.logAt(s.subsystem, LogLevelWarn, msg, fields)
+}
+
+func (s *subsystemLogger) Error(msg string, fields map[string]any) {
+	s.parent.logAt(s.subsystem, LogLevelError, msg, fields)
+}
+
+func (s *subsystemLogger) Debug(msg string, fields map[string]any) {
+	s.parent.logAt(s.subsystem, LogLevelDebug, msg, fields)
+}
+
+// SubsystemLogger returns l scoped to subsystem when l is a JSONLogger,
+// and l unchanged otherwise (test doubles, nop loggers).
+func SubsystemLogger(l Logger, subsystem string) Logger {
+	if jl, ok := l.(*JSONLogger); ok {
+		return jl.Subsystem(subsystem)
+	}
+	return l
+}
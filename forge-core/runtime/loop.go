@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
@@ -43,6 +44,14 @@ type ToolExecutor interface {
 	ToolDefinitions() []llm.ToolDefinition
 }
 
+// ContextToolDefinitions is implemented by tool executors whose tools
+// depend on the task, like a tools.Registry with origin policies. The
+// loop offers the model ToolDefinitionsFor(ctx) instead of
+// ToolDefinitions().
+type ContextToolDefinitions interface {
+	ToolDefinitionsFor(ctx context.Context) []llm.ToolDefinition
+}
+
 // Pre-hook safety ceiling for deferred tool-result truncation: hooks must
 // never scan unbounded payloads, but the ceiling must be generous enough
 // that real bulky outputs (e.g. kubectl get -o json on a large cluster)
@@ -82,16 +91,23 @@ type LLMExecutor struct {
 	client             llm.Client
 	tools              ToolExecutor
 	hooks              *HookRegistry
+	promptMu           sync.RWMutex // guards systemPrompt, which SetSystemPrompt replaces when the prompt templates change
 	systemPrompt       string
 	maxIter            int
+	repeatLimit        int // stop after one tool call repeats this often (0 = off)
+	oscillationLimit   int // stop after two tool calls alternate this often (0 = off)
 	compactor          *Compactor
 	store              SessionStore
 	logger             Logger
+	modelMu            sync.RWMutex  // guards modelName and provider, which SetModel replaces on a config reload
 	modelName          string        // resolved model name for context budget
 	provider           string        // resolved provider name (anthropic, openai, ollama, custom)
 	charBudget         int           // resolved character budget
 	maxToolResultChars int           // computed from char budget
 	filesDir           string        // directory for file_create output
+	tenantsDir         string        // parent of per-tenant directories ("" = tenants share filesDir)
+	scratchDir         string        // parent of per-task scratch directories ("" = none)
+	keepScratch        bool          // keep scratch directories after Execute
 	sessionMaxAge      time.Duration // max age for session recovery (0 = no limit)
 	workflowPhases     []string      // workflow phases from skills (edit, finalize, query)
 	// deferToolTruncation moves the maxToolResultChars cut to AFTER the
@@ -107,6 +123,20 @@ type LLMExecutor struct {
 	// setup. Zero value (CaptureContent=false) means metadata-only
 	// spans — the default posture.
 	tracingCfg observability.TracingConfig
+	// highWater and summaryClient drive adaptive context shrinking; see
+	// shrinkContext. highWater 0 leaves it off.
+	highWater     float64
+	summaryClient llm.Client
+	reflection    *ReflectionConfig
+	planning      *PlanConfig
+	// outputContracts maps a skill's tool to what the final answer of a
+	// task that called it must look like.
+	outputContracts map[string]*OutputContract
+	runtimeContext  *RuntimeContext
+	// live maps the ID of each task Execute is running to its memory,
+	// so CompactSession can compact a conversation in flight.
+	liveMu sync.Mutex
+	live   map[string]*Memory
 }
 
 // LLMExecutorConfig configures the LLM executor.
@@ -123,8 +153,17 @@ type LLMExecutorConfig struct {
 	Provider       string        // provider name (anthropic, openai, ollama, custom) — for audit attribution
 	CharBudget     int           // explicit char budget override (0 = auto from model)
 	FilesDir       string        // directory for file_create output (default: $TMPDIR/forge-files)
+	TenantsDir     string        // parent of per-tenant directories; a tenant's files go to <TenantsDir>/<tenant>/files
+	ScratchDir     string        // parent of per-task scratch dirs sandboxed tools are confined to ("" = none)
+	KeepScratch    bool          // keep scratch dirs after Execute instead of removing them
 	SessionMaxAge  time.Duration // max idle time before session recovery is skipped (0 = 30m default)
 	WorkflowPhases []string      // workflow phases from skills (edit, finalize, query)
+	// LoopRepeatLimit stops a task that calls the same tool with the same
+	// arguments this many times; LoopOscillationLimit one whose tool
+	// calls alternate between the same two calls and results this many
+	// times. The task fails with a LoopDetectedError. 0 turns a check off.
+	LoopRepeatLimit      int
+	LoopOscillationLimit int
 	// DeferToolResultTruncation applies the tool-result size cap after the
 	// AfterToolExec hooks instead of before, behind a pre-hook safety
 	// ceiling (16x the cap, absolute max 4MB). Enable when a compression
@@ -137,6 +176,27 @@ type LLMExecutorConfig struct {
 	// prompt / completion / tool I/O content on Phase 3 spans
 	// (issue #130). Zero value disables content capture.
 	TracingConfig observability.TracingConfig
+	// ContextHighWater is the context utilization (a fraction of the char
+	// budget) above which the executor summarizes the largest tool
+	// results before the next LLM call, instead of waiting for the budget
+	// to be exceeded and pruning the oldest. 0 disables it.
+	ContextHighWater float64
+	// SummaryClient summarizes tool results for ContextHighWater —
+	// typically a cheaper model. Nil uses Client.
+	SummaryClient llm.Client
+	// Reflection reviews the final answer before it is returned and
+	// revises it once when the review finds problems. Nil disables it.
+	Reflection *ReflectionConfig
+	// Planning runs tasks in planner-executor mode. Nil runs them
+	// directly.
+	Planning *PlanConfig
+	// OutputContracts hold the final answer of a task that called the
+	// keyed tool to that skill's output schema and length cap. Nil
+	// checks nothing.
+	OutputContracts map[string]*OutputContract
+	// RuntimeContext is appended to the system prompt of every task,
+	// rendered as the task starts. Nil adds nothing.
+	RuntimeContext *RuntimeContext
 }
 
 // NewLLMExecutor creates a new LLMExecutor with the given configuration.
@@ -184,6 +244,8 @@ func NewLLMExecutor(cfg LLMExecutorConfig) *LLMExecutor {
 		hooks:               hooks,
 		systemPrompt:        cfg.SystemPrompt,
 		maxIter:             maxIter,
+		repeatLimit:         cfg.LoopRepeatLimit,
+		oscillationLimit:    cfg.LoopOscillationLimit,
 		compactor:           cfg.Compactor,
 		store:               cfg.Store,
 		logger:              logger,
@@ -192,18 +254,43 @@ func NewLLMExecutor(cfg LLMExecutorConfig) *LLMExecutor {
 		charBudget:          budget,
 		maxToolResultChars:  toolLimit,
 		filesDir:            cfg.FilesDir,
+		tenantsDir:          cfg.TenantsDir,
+		scratchDir:          cfg.ScratchDir,
+		keepScratch:         cfg.KeepScratch,
 		sessionMaxAge:       sessionMaxAge,
 		workflowPhases:      cfg.WorkflowPhases,
 		deferToolTruncation: cfg.DeferToolResultTruncation,
 		tracingCfg:          cfg.TracingConfig,
+		highWater:           cfg.ContextHighWater,
+		summaryClient:       cfg.SummaryClient,
+		reflection:          cfg.Reflection,
+		planning:            cfg.Planning,
+		outputContracts:     cfg.OutputContracts,
+		runtimeContext:      cfg.RuntimeContext,
 	}
 }
 
 // Execute processes a message through the LLM agent loop.
 func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (outMsg *a2a.Message, outErr error) {
 	if e.filesDir != "" {
-		ctx = WithFilesDir(ctx, e.filesDir)
+		dir := e.filesDir
+		if tenant := TenantFromContext(ctx); tenant != "" && e.tenantsDir != "" {
+			dir = filepath.Join(e.tenantsDir, tenant, "files")
+		}
+		ctx = WithFilesDir(ctx, dir)
+	}
+	if e.scratchDir != "" && task != nil {
+		dir := filepath.Join(e.scratchDir, tools.ScratchDirName(task.ID))
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("creating task scratch directory: %w", err)
+		}
+		ctx = tools.WithTaskScratch(ctx, dir)
+		if !e.keepScratch {
+			defer func() { _ = os.RemoveAll(dir) }()
+		}
 	}
+	// Every progress event of this run carries its loop iteration.
+	prog, ctx := trackProgress(ctx, e.maxIter)
 
 	// Phase 3 (#104) — open the agent-execution span. Parent (when
 	// present) is the inbound dispatch span set by
@@ -241,14 +328,27 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 	if cid := CorrelationIDFromContext(ctx); cid != "" {
 		span.SetAttributes(attribute.String(observability.AttrForgeCorrelationID, cid))
 	}
-	if e.provider != "" {
-		span.SetAttributes(attribute.String(observability.AttrGenAISystem, e.provider))
+	provider, modelName := e.model()
+	if provider != "" {
+		span.SetAttributes(attribute.String(observability.AttrGenAISystem, provider))
 	}
-	if e.modelName != "" {
-		span.SetAttributes(attribute.String(observability.AttrGenAIRequestModel, e.modelName))
+	if modelName != "" {
+		span.SetAttributes(attribute.String(observability.AttrGenAIRequestModel, modelName))
 	}
 
-	mem := NewMemory(e.systemPrompt, e.charBudget, e.modelName)
+	mem := NewMemory(e.taskSystemPrompt(ctx, msg), e.charBudget, modelName)
+	defer e.trackLive(task.ID, mem)()
+	// Expose the task's cumulative usage ledger and context utilization
+	// to tasks/get however Execute returns.
+	defer func() {
+		if task.Metadata == nil {
+			task.Metadata = map[string]any{}
+		}
+		if u := mem.Usage(); u != nil {
+			task.Metadata["usage"] = u
+		}
+		task.Metadata["context"] = mem.ContextUsage()
+	}()
 
 	// Try to recover session from disk. If found, the disk snapshot
 	// supersedes task.History to avoid duplicating messages.
@@ -299,6 +399,11 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 	// the persisted memory; this is the symmetric guard for the
 	// first-interaction path.
 	if !recovered {
+		// No session (persistence off or first turn): continue the
+		// ledger an earlier turn left on the task.
+		if u, ok := UsageLedgerFromMetadata(task.Metadata["usage"]); ok {
+			mem.SetUsage(u)
+		}
 		historyToLoad := task.History
 		if n := len(historyToLoad); n > 0 && a2aMessagesEqual(historyToLoad[n-1], *msg) {
 			historyToLoad = historyToLoad[:n-1]
@@ -331,10 +436,17 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 
 	// Build tool definitions
 	var toolDefs []llm.ToolDefinition
-	if e.tools != nil {
+	if scoped, ok := e.tools.(ContextToolDefinitions); ok {
+		toolDefs = scoped.ToolDefinitionsFor(ctx)
+	} else if e.tools != nil {
 		toolDefs = e.tools.ToolDefinitions()
 	}
 
+	// Number the sources retrieval tools return so the model can cite
+	// them and the response carries them as structured citations.
+	citer, _ := e.tools.(CitationSource)
+	cites := newCitationTracker(task.History, mem.Messages())
+
 	// Track large tool outputs so they can be included as file parts
 	// in the response (the LLM may truncate them due to output token limits).
 	const largeToolOutputThreshold = 8000
@@ -356,6 +468,7 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 	// missing git ops) and injects proactive nudges. The agent never
 	// sees iteration counts — nudges fire on consecutive read-only iterations.
 	tracker := newWorkflowTracker(e.workflowPhases)
+	loops := newLoopDetector(e.repeatLimit, e.oscillationLimit)
 
 	// Pre-compute available write tools for nudge messages.
 	var availWriteTools []string
@@ -365,25 +478,50 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 		}
 	}
 
+	// Planner-executor mode: plan the request up front, then work
+	// through the steps, each scoped to its tools.
+	var run *planRun
+	if e.planning != nil {
+		var opening string
+		if run, opening = e.startPlan(ctx, mem, ExtractText(msg), toolDefs); run != nil {
+			mem.Append(llm.ChatMessage{Role: llm.RoleUser, Content: opening})
+			defer func() {
+				if task.Metadata == nil {
+					task.Metadata = map[string]any{}
+				}
+				task.Metadata[MetadataKeyPlan] = run.plan
+			}()
+		}
+	}
+
 	// Agent loop
 	for i := 0; i < e.maxIter; i++ {
 		// Record iteration count on the outer span — the closure stamps
 		// the final value before End.
 		finalIter = i + 1
+		prog.setIteration(i + 1)
 		// Honor cancellation at iteration boundary. Returning ctx.Err()
 		// here propagates context.Canceled / DeadlineExceeded up to the
 		// runner, which maps it to TaskStateCanceled +
 		// invocation_cancelled audit. See issue #88 / FWS-4.
 		if err := ctx.Err(); err != nil {
-			return nil, err
+			return nil, e.cancelled(ctx, task.ID, mem, err)
+		}
+
+		// Summarize bulky tool results once past the high-water mark, so
+		// trimming rarely has to prune them blind (best-effort).
+		if e.highWater > 0 {
+			e.shrinkContext(ctx, mem)
 		}
 
 		// Run compaction before LLM call (best-effort).
 		if e.compactor != nil {
-			if _, err := e.compactor.MaybeCompact(task.ID, mem); err != nil {
+			if compacted, err := e.compactor.MaybeCompact(task.ID, mem); err != nil {
 				e.logger.Warn("compaction error", map[string]any{
 					"task_id": task.ID, "error": err.Error(),
 				})
+			} else if compacted {
+				prog.send(ProgressEvent{Phase: ProgressCompaction, Message: "Compacted conversation history", Context: mem.ContextUsage()})
 			}
 		}
 
@@ -398,16 +536,25 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 			return nil, fmt.Errorf("before LLM call hook: %w", err)
 		}
 
+		if prog != nil {
+			ev := ProgressEvent{Phase: ProgressLLMThinking, Message: "Thinking...", Context: mem.ContextUsage()}
+			if i == 0 && run == nil {
+				ev.Phase, ev.Message = ProgressPlanning, "Planning..."
+			}
+			prog.send(ev)
+		}
+
 		// Call LLM
 		req := &llm.ChatRequest{
 			Messages: messages,
-			Tools:    toolDefs,
+			Tools:    run.tools(toolDefs),
 		}
 
 		// Capture wall-clock duration of the provider call so the
 		// AfterLLMCall hook can stamp duration_ms on the llm_call audit
 		// event and X-Forge-Duration-Ms header. See issue #87 / FWS-3.
 		llmStart := time.Now()
+		provider, modelName = e.model()
 		// Phase 3 (#104) — child span around the provider call. The
 		// span carries the same gen_ai.* attributes the audit event
 		// does so a backend can join the two by trace_id without a
@@ -417,8 +564,8 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 		// not part of the LLM call's wall-clock measurement.
 		llmCtx, llmSpan := Tracer().Start(ctx, "llm.completion")
 		llmSpan.SetAttributes(
-			attribute.String(observability.AttrGenAISystem, e.provider),
-			attribute.String(observability.AttrGenAIRequestModel, e.modelName),
+			attribute.String(observability.AttrGenAISystem, provider),
+			attribute.String(observability.AttrGenAIRequestModel, modelName),
 		)
 		// Phase 3.5 (#130) — stamp the structured input messages on the
 		// span when CaptureContent is enabled. Runs through the
@@ -450,18 +597,21 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 			// route to invocation_cancelled instead of state=failed.
 			// See issue #88 / FWS-4.
 			if cerr := ctx.Err(); cerr != nil {
-				return nil, cerr
+				return nil, e.cancelled(ctx, task.ID, mem, cerr)
 			}
 			_ = e.hooks.Fire(ctx, OnError, &HookContext{
 				Error:           err,
 				TaskID:          TaskIDFromContext(ctx),
 				CorrelationID:   CorrelationIDFromContext(ctx),
 				LLMCallDuration: llmDuration,
-				Provider:        e.provider,
-				Model:           e.modelName,
+				Provider:        provider,
+				Model:           modelName,
 			})
-			// Return user-friendly error (raw error is already logged via OnError hook)
-			return nil, fmt.Errorf("something went wrong while processing your request, please try again")
+			// Return user-friendly error (raw error is already logged via
+			// OnError hook), tagged with the provider failure's class so
+			// callers can tell throttling from an outage.
+			return nil, WithErrorCode(LLMErrorCode(err, provider, modelName),
+				fmt.Errorf("something went wrong while processing your request, please try again"))
 		}
 		// Happy-path: stamp usage + finish_reason from the response,
 		// then close the span. Doing this BEFORE the AfterLLMCall hook
@@ -489,6 +639,8 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 		}
 		llmSpan.End()
 
+		e.recordUsage(mem, resp)
+
 		// Fire AfterLLMCall hook
 		if err := e.hooks.Fire(ctx, AfterLLMCall, &HookContext{
 			Messages:        messages,
@@ -496,12 +648,16 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 			TaskID:          TaskIDFromContext(ctx),
 			CorrelationID:   CorrelationIDFromContext(ctx),
 			LLMCallDuration: llmDuration,
-			Provider:        e.provider,
-			Model:           e.modelName,
+			Provider:        provider,
+			Model:           modelName,
 		}); err != nil {
 			return nil, fmt.Errorf("after LLM call hook: %w", err)
 		}
 
+		// Sources the provider grounded the answer in become numbered
+		// inline citations, like those tool calls return.
+		resp.Message = cites.ground(resp.Message)
+
 		// Append assistant message to memory.
 		//
 		// Issue #131 — when the LLM hits finish_reason=length (or otherwise
@@ -534,6 +690,16 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 		// even when tool calls are present, and others return empty/non-standard
 		// values. Only the tool call list determines whether execution continues.
 		if len(resp.Message.ToolCalls) == 0 {
+			// In plan mode a reply without tool calls ends the current
+			// step, not the task.
+			if run.active() {
+				mem.Append(llm.ChatMessage{
+					Role:    llm.RoleUser,
+					Content: e.finishStep(ctx, run, mem, resp.Message.Content, toolDefs),
+				})
+				continue
+			}
+
 			// If the LLM stopped after executing tools, send a continuation
 			// nudge. This catches cases where the LLM reports findings instead
 			// of completing the full workflow (e.g., stops after exploration
@@ -655,6 +821,7 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 				if retryResp, retryErr := e.client.Chat(ctx, retryReq); retryErr == nil && strings.TrimSpace(retryResp.Message.Content) != "" {
 					resp = retryResp
 					mem.Append(resp.Message)
+					e.recordUsage(mem, retryResp)
 					// Fire AfterLLMCall so audit + headers capture the retry's
 					// usage/duration alongside the original turn.
 					_ = e.hooks.Fire(ctx, AfterLLMCall, &HookContext{
@@ -663,25 +830,43 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 						TaskID:          TaskIDFromContext(ctx),
 						CorrelationID:   CorrelationIDFromContext(ctx),
 						LLMCallDuration: time.Since(retryStart),
-						Provider:        e.provider,
-						Model:           e.modelName,
+						Provider:        provider,
+						Model:           modelName,
 					})
 				}
 			}
+			var reflection *ReflectionResult
+			resp.Message, reflection = e.reflect(ctx, mem, ExtractText(msg), resp.Message, toolsUsed, toolDefs)
+			var contractErr error
+			if resp.Message, contractErr = e.enforceOutput(ctx, mem, resp.Message, toolsUsed, toolDefs); contractErr != nil {
+				return nil, e.outputFailed(ctx, task.ID, mem, contractErr)
+			}
 			if strings.TrimSpace(resp.Message.Content) == "" {
 				resp.Message.Content = "I processed your request but wasn't able to produce a response. Please try again."
 			}
 			e.persistSession(task.ID, mem)
-			return e.finalizeResponse(ctx, resp.Message, largeToolOutputs...), nil
+			out := e.finalizeResponse(ctx, resp.Message, largeToolOutputs...)
+			out.Citations = cites.resolve(resp.Message.Content)
+			setReflection(out, reflection)
+			return out, nil
 		}
 
 		// Execute tool calls
 		if e.tools == nil {
+			var reflection *ReflectionResult
+			resp.Message, reflection = e.reflect(ctx, mem, ExtractText(msg), resp.Message, toolsUsed, toolDefs)
+			var contractErr error
+			if resp.Message, contractErr = e.enforceOutput(ctx, mem, resp.Message, toolsUsed, toolDefs); contractErr != nil {
+				return nil, e.outputFailed(ctx, task.ID, mem, contractErr)
+			}
 			if strings.TrimSpace(resp.Message.Content) == "" {
 				resp.Message.Content = "I processed your request but wasn't able to produce a response. Please try again."
 			}
 			e.persistSession(task.ID, mem)
-			return e.finalizeResponse(ctx, resp.Message, largeToolOutputs...), nil
+			out := e.finalizeResponse(ctx, resp.Message, largeToolOutputs...)
+			out.Citations = cites.resolve(resp.Message.Content)
+			setReflection(out, reflection)
+			return out, nil
 		}
 
 		// The LLM made tool calls -- it's making progress. Allow
@@ -689,24 +874,37 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 		stopNudgesSent = 0
 
 		iterResults := make([]toolIterResult, 0, len(resp.Message.ToolCalls))
+		var stuck *LoopDetectedError
 
 		for _, tc := range resp.Message.ToolCalls {
 			// Honor cancellation between tool calls within an iteration —
 			// orchestrators that cancel mid-iteration get fast exit
 			// without burning more LLM/tool spend. See issue #88 / FWS-4.
 			if err := ctx.Err(); err != nil {
-				return nil, err
+				return nil, e.cancelled(ctx, task.ID, mem, err)
+			}
+			// A plan step may only call the tools it was planned with.
+			if !run.allows(tc.Function.Name) {
+				mem.Append(llm.ChatMessage{
+					Role:       llm.RoleTool,
+					Content:    fmt.Sprintf("Error: %s is not one of this plan step's tools.", tc.Function.Name),
+					ToolCallID: tc.ID,
+					Name:       tc.Function.Name,
+				})
+				continue
 			}
 			toolsUsed = append(toolsUsed, tc.Function.Name)
 
 			// Fire BeforeToolExec hook
 			if err := e.hooks.Fire(ctx, BeforeToolExec, &HookContext{
 				ToolName:      tc.Function.Name,
+				ToolCallID:    tc.ID,
 				ToolInput:     tc.Function.Arguments,
 				TaskID:        TaskIDFromContext(ctx),
 				CorrelationID: CorrelationIDFromContext(ctx),
+				ContextUsage:  mem.ContextUsage(),
 			}); err != nil {
-				return nil, fmt.Errorf("before tool exec hook: %w", err)
+				return nil, &ToolError{Tool: tc.Function.Name, Err: fmt.Errorf("before tool exec hook: %w", err)}
 			}
 
 			// Execute tool. Capture wall-clock duration so the
@@ -726,14 +924,29 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 			if e.deferToolTruncation {
 				toolCtx = tools.WithRelaxedLimits(toolCtx)
 			}
+			toolCtx = withProgressTool(toolCtx, tc.Function.Name)
 			if e.tracingCfg.CaptureContent && tc.Function.Arguments != "" {
 				toolSpan.SetAttributes(attribute.String(
 					observability.AttrForgeToolArgs,
 					PrepareSpanContent(tc.Function.Arguments, e.tracingCfg.Redact, DefaultSpanContentCapBytes),
 				))
 			}
-			result, execErr := e.tools.Execute(toolCtx, tc.Function.Name, json.RawMessage(tc.Function.Arguments))
+			// Dry run: record what the call would do and hand the model
+			// that description instead of running the tool.
+			var result string
+			var execErr error
+			if plan := DryRunFromContext(ctx); plan != nil {
+				result = dryRunResult(plan.Record(tc.Function.Name, json.RawMessage(tc.Function.Arguments)))
+			} else {
+				result, execErr = e.tools.Execute(toolCtx, tc.Function.Name, json.RawMessage(tc.Function.Arguments))
+			}
 			toolDuration := time.Since(toolStart)
+			// Cite from the raw result: truncation and compression below
+			// may cut the very fields that name the sources.
+			var toolCites []a2a.Citation
+			if execErr == nil && citer != nil {
+				toolCites = cites.add(citer.Citations(tc.Function.Name, json.RawMessage(tc.Function.Arguments), result))
+			}
 			if execErr != nil {
 				toolSpan.RecordError(execErr)
 				toolSpan.SetStatus(codes.Error, execErr.Error())
@@ -782,15 +995,17 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 			// Fire AfterToolExec hook -- hooks may redact ToolOutput.
 			afterHctx := &HookContext{
 				ToolName:         tc.Function.Name,
+				ToolCallID:       tc.ID,
 				ToolInput:        tc.Function.Arguments,
 				ToolOutput:       result,
 				Error:            execErr,
 				TaskID:           TaskIDFromContext(ctx),
 				CorrelationID:    CorrelationIDFromContext(ctx),
 				ToolExecDuration: toolDuration,
+				ContextUsage:     mem.ContextUsage(),
 			}
 			if err := e.hooks.Fire(ctx, AfterToolExec, afterHctx); err != nil {
-				return nil, fmt.Errorf("after tool exec hook: %w", err)
+				return nil, &ToolError{Tool: tc.Function.Name, Err: fmt.Errorf("after tool exec hook: %w", err)}
 			}
 			result = afterHctx.ToolOutput // allow hooks to redact output
 
@@ -822,6 +1037,16 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 				})
 			}
 
+			// The source list goes after truncation and the hooks, so the
+			// model always sees the numbers it should cite.
+			if len(toolCites) > 0 {
+				result += "\n\n" + citationFooter(toolCites)
+			}
+
+			if stuck == nil {
+				stuck = loops.observe(tc.Function.Name, tc.Function.Arguments, result, i+1)
+			}
+
 			// Append tool result to memory
 			mem.Append(llm.ChatMessage{
 				Role:       llm.RoleTool,
@@ -831,6 +1056,22 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 			})
 		}
 
+		// A stuck loop ends the task once this iteration's calls all have
+		// results, so the persisted session stays well-formed.
+		if stuck != nil {
+			e.logger.Warn("agent loop stopped: no progress", map[string]any{
+				"task_id": TaskIDFromContext(ctx), "kind": stuck.Kind,
+				"tools": stuck.Tools, "count": stuck.Count, "iteration": stuck.Iteration,
+			})
+			_ = e.hooks.Fire(ctx, OnError, &HookContext{
+				Error:         stuck,
+				TaskID:        TaskIDFromContext(ctx),
+				CorrelationID: CorrelationIDFromContext(ctx),
+			})
+			e.persistSession(task.ID, mem)
+			return nil, stuck
+		}
+
 		// Record this iteration's tools for workflow tracking.
 		tracker.recordIteration(iterResults)
 
@@ -846,7 +1087,128 @@ func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Mess
 	}
 
 	e.persistSession(task.ID, mem)
-	return nil, fmt.Errorf("agent loop exceeded maximum iterations (%d)", e.maxIter)
+	return nil, WithErrorCode(a2a.ErrorBudgetExceeded,
+		fmt.Errorf("agent loop exceeded maximum iterations (%d)", e.maxIter))
+}
+
+// ErrNoCompactor is returned by CompactSession when the executor has no
+// Compactor (session persistence is off).
+var ErrNoCompactor = errors.New("compaction unavailable: session persistence is off")
+
+// CompactSession compacts taskID's conversation now, whatever the
+// trigger ratio. A task this executor is running has its live memory
+// compacted, so its next LLM call already sees the summary; otherwise
+// the persisted session is loaded, compacted and saved back. Returns
+// ErrNoSession when there is neither.
+func (e *LLMExecutor) CompactSession(taskID string) (*CompactResult, error) {
+	if e.compactor == nil {
+		return nil, ErrNoCompactor
+	}
+	e.liveMu.Lock()
+	mem := e.live[taskID]
+	e.liveMu.Unlock()
+	if mem != nil {
+		return e.compactor.Compact(taskID, mem)
+	}
+
+	if e.store == nil {
+		return nil, ErrNoSession
+	}
+	saved, err := e.store.Load(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("loading session: %w", err)
+	}
+	if saved == nil {
+		return nil, ErrNoSession
+	}
+	mem = NewMemory(e.basePrompt(context.Background()), e.charBudget, "")
+	mem.LoadFromStore(saved)
+	return e.compactor.Compact(taskID, mem)
+}
+
+// trackLive registers mem as taskID's live memory and returns the func
+// that unregisters it.
+func (e *LLMExecutor) trackLive(taskID string, mem *Memory) func() {
+	e.liveMu.Lock()
+	defer e.liveMu.Unlock()
+	if e.live == nil {
+		e.live = make(map[string]*Memory)
+	}
+	e.live[taskID] = mem
+	return func() {
+		e.liveMu.Lock()
+		defer e.liveMu.Unlock()
+		if e.live[taskID] == mem {
+			delete(e.live, taskID)
+		}
+	}
+}
+
+// SetModel changes the provider and model the executor attributes LLM
+// calls to in audit events, spans and usage. The runner calls it after
+// swapping the model client on a config reload; the character budget
+// stays as resolved at construction.
+func (e *LLMExecutor) SetModel(provider, modelName string) {
+	e.modelMu.Lock()
+	defer e.modelMu.Unlock()
+	e.provider, e.modelName = provider, modelName
+}
+
+func (e *LLMExecutor) model() (provider, modelName string) {
+	e.modelMu.RLock()
+	defer e.modelMu.RUnlock()
+	return e.provider, e.modelName
+}
+
+type systemPromptKey struct{}
+
+// WithSystemPrompt overrides the system prompt of the task run with ctx,
+// e.g. for an experiment variant's prompt. The runtime context is still
+// appended.
+func WithSystemPrompt(ctx context.Context, prompt string) context.Context {
+	return context.WithValue(ctx, systemPromptKey{}, prompt)
+}
+
+// SetSystemPrompt replaces the system prompt of tasks started from now
+// on. The runner calls it when the prompt templates change; a running
+// task keeps the prompt it started with.
+func (e *LLMExecutor) SetSystemPrompt(prompt string) {
+	e.promptMu.Lock()
+	defer e.promptMu.Unlock()
+	e.systemPrompt = prompt
+}
+
+// basePrompt is the system prompt of the task running in ctx: the one
+// WithSystemPrompt set, or else the executor's.
+func (e *LLMExecutor) basePrompt(ctx context.Context) string {
+	if p, ok := ctx.Value(systemPromptKey{}).(string); ok {
+		return p
+	}
+	e.promptMu.RLock()
+	defer e.promptMu.RUnlock()
+	return e.systemPrompt
+}
+
+// recordUsage adds resp's tokens to the task's usage ledger, attributed
+// to the routed model when a model.routes rule served the call.
+func (e *LLMExecutor) recordUsage(mem *Memory, resp *llm.ChatResponse) {
+	provider, model := e.model()
+	if resp.Route != nil {
+		provider, model = resp.Route.Provider, resp.Route.Model
+	}
+	mem.RecordUsage(provider, model, resp.Usage)
+}
+
+// cancelled returns err for an invocation stopped by cancellation. When
+// the runtime cancelled it because a graceful shutdown ran out of
+// drain time, the conversation so far is persisted first so the task
+// resumes from there after the restart; operator cancels keep the
+// session as it stood before the invocation.
+func (e *LLMExecutor) cancelled(ctx context.Context, taskID string, mem *Memory, err error) error {
+	if CancellationReasonFromCause(ctx) == CancelReasonShutdown {
+		e.persistSession(taskID, mem)
+	}
+	return err
 }
 
 // persistSession saves the current memory state to disk (best-effort).
@@ -891,6 +1253,7 @@ func (e *LLMExecutor) persistSession(taskID string, mem *Memory) {
 		TaskID:   taskID,
 		Messages: msgs,
 		Summary:  mem.existingSummary,
+		Usage:    mem.Usage(),
 	}
 
 	if err := e.store.Save(data); err != nil {
@@ -917,8 +1280,9 @@ func (e *LLMExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2
 		resp, err := e.Execute(ctx, task, msg)
 		if err != nil {
 			ch <- &a2a.Message{
-				Role:  a2a.MessageRoleAgent,
-				Parts: []a2a.Part{a2a.NewTextPart("Error: " + err.Error())},
+				Role:     a2a.MessageRoleAgent,
+				Parts:    []a2a.Part{a2a.NewTextPart("Error: " + err.Error())},
+				Metadata: map[string]any{a2a.MetadataKeyError: ClassifyError(err)},
 			}
 			return
 		}
@@ -937,10 +1301,16 @@ func a2aMessageToLLM(msg a2a.Message) llm.ChatMessage {
 		role = llm.RoleAssistant
 	}
 
+	// A file the user sent is shown to the model as a reference it can
+	// read with its tools; the runner saves uploaded content and puts the
+	// path in the URI.
 	var textParts []string
 	for _, p := range msg.Parts {
-		if p.Kind == a2a.PartKindText && p.Text != "" {
+		switch {
+		case p.Kind == a2a.PartKindText && p.Text != "":
 			textParts = append(textParts, p.Text)
+		case p.Kind == a2a.PartKindFile && p.File != nil && p.File.URI != "" && role == llm.RoleUser:
+			textParts = append(textParts, fmt.Sprintf("[attached file %s (%s): %s]", p.File.Name, p.File.MimeType, p.File.URI))
 		}
 	}
 
@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// Kinds of stuck loop a LoopDetectedError reports.
+const (
+	LoopRepeatedCall = "repeated_call"
+	LoopOscillation  = "oscillation"
+)
+
+// loopDetailArgsMax caps the arguments quoted in a LoopDetectedError.
+const loopDetailArgsMax = 500
+
+// LoopDetectedError stops a task whose agent loop has stopped making
+// progress. It is classified as a2a.ErrorLoopDetected and carries what
+// the loop kept doing as the TaskError's details.
+type LoopDetectedError struct {
+	Kind      string   // LoopRepeatedCall or LoopOscillation
+	Tools     []string // the repeated call's tool; the two alternating tools
+	Args      string   // the repeated call's arguments (truncated)
+	Count     int      // repeats, or completed cycles
+	Iteration int      // loop iteration it was detected in, 1-based
+}
+
+func (e *LoopDetectedError) Error() string {
+	if e.Kind == LoopOscillation {
+		return fmt.Sprintf("agent loop stopped: tool calls %s and %s alternated %d times without progress", e.Tools[0], e.Tools[1], e.Count)
+	}
+	return fmt.Sprintf("agent loop stopped: %s was called %d times with the same arguments", e.Tools[0], e.Count)
+}
+
+// ErrorCode implements the interface ClassifyError looks for.
+func (e *LoopDetectedError) ErrorCode() a2a.ErrorCode { return a2a.ErrorLoopDetected }
+
+// ErrorDetails is the diagnostic context ClassifyError puts on the
+// TaskError.
+func (e *LoopDetectedError) ErrorDetails() map[string]any {
+	d := map[string]any{
+		"kind":      e.Kind,
+		"tools":     e.Tools,
+		"count":     e.Count,
+		"iteration": e.Iteration,
+	}
+	if e.Args != "" {
+		d["args"] = e.Args
+	}
+	return d
+}
+
+// loopDetector watches one task's tool calls for a stuck loop: the same
+// call (tool and arguments) made repeatLimit times, or the last steps
+// (call and result) alternating A, B, A, B for oscLimit cycles. A limit
+// of zero or less turns its check off.
+type loopDetector struct {
+	repeatLimit int
+	oscLimit    int
+	calls       map[[32]byte]int
+	steps       [][32]byte
+	stepTools   []string
+}
+
+func newLoopDetector(repeatLimit, oscLimit int) *loopDetector {
+	if repeatLimit <= 0 && oscLimit <= 0 {
+		return nil
+	}
+	return &loopDetector{repeatLimit: repeatLimit, oscLimit: oscLimit, calls: map[[32]byte]int{}}
+}
+
+// observe records a finished tool call and reports a stuck loop, or nil.
+// A nil detector observes nothing.
+func (d *loopDetector) observe(tool, args, result string, iteration int) *LoopDetectedError {
+	if d == nil {
+		return nil
+	}
+	args = canonicalArgs(args)
+	call := sha256.Sum256([]byte(tool + "\x00" + args))
+	if d.repeatLimit > 0 {
+		d.calls[call]++
+		if n := d.calls[call]; n >= d.repeatLimit {
+			if len(args) > loopDetailArgsMax {
+				args = args[:loopDetailArgsMax] + "..."
+			}
+			return &LoopDetectedError{Kind: LoopRepeatedCall, Tools: []string{tool}, Args: args, Count: n, Iteration: iteration}
+		}
+	}
+	if d.oscLimit <= 0 {
+		return nil
+	}
+	d.steps = append(d.steps, sha256.Sum256([]byte(tool+"\x00"+args+"\x00"+result)))
+	d.stepTools = append(d.stepTools, tool)
+	if window := 2 * d.oscLimit; len(d.steps) > window {
+		d.steps = d.steps[len(d.steps)-window:]
+		d.stepTools = d.stepTools[len(d.stepTools)-window:]
+	}
+	if len(d.steps) < 2*d.oscLimit {
+		return nil
+	}
+	n := len(d.steps)
+	if d.steps[n-1] == d.steps[n-2] {
+		return nil // one call repeating is the repeat check's business
+	}
+	for i := 2; i < n; i++ {
+		if d.steps[i] != d.steps[i-2] {
+			return nil
+		}
+	}
+	return &LoopDetectedError{Kind: LoopOscillation, Tools: []string{d.stepTools[n-2], d.stepTools[n-1]}, Count: d.oscLimit, Iteration: iteration}
+}
+
+// canonicalArgs re-encodes JSON arguments with sorted keys and no
+// whitespace, so calls differing only in layout count as the same call.
+func canonicalArgs(args string) string {
+	var v any
+	if err := json.Unmarshal([]byte(args), &v); err != nil {
+		return args
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return args
+	}
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestLoopDetectorRepeatedCall(t *testing.T) {
+	d := newLoopDetector(3, 0)
+	// Key order and whitespace don't make a call different.
+	for i, args := range []string{`{"a":1,"b":2}`, `{ "b": 2, "a": 1 }`} {
+		if err := d.observe("fetch", args, "out", i+1); err != nil {
+			t.Fatalf("call %d: unexpected %v", i+1, err)
+		}
+	}
+	if err := d.observe("fetch", `{"a":2}`, "out", 3); err != nil {
+		t.Fatalf("different args: unexpected %v", err)
+	}
+	err := d.observe("fetch", `{"b":2,"a":1}`, "out", 4)
+	if err == nil || err.Kind != LoopRepeatedCall || err.Count != 3 || err.Args != `{"a":1,"b":2}` || err.Iteration != 4 {
+		t.Fatalf("got %+v, want repeated_call after 3 identical calls", err)
+	}
+}
+
+func TestLoopDetectorOscillation(t *testing.T) {
+	d := newLoopDetector(0, 3)
+	steps := [][2]string{
+		{"file_edit", "x->y"}, {"file_edit", "y->x"},
+		{"file_edit", "x->y"}, {"file_edit", "y->x"},
+		{"file_edit", "x->y"},
+	}
+	for i, s := range steps {
+		if err := d.observe(s[0], s[1], "ok", i+1); err != nil {
+			t.Fatalf("step %d: unexpected %v", i+1, err)
+		}
+	}
+	err := d.observe("file_edit", "y->x", "ok", 6)
+	if err == nil || err.Kind != LoopOscillation || err.Count != 3 {
+		t.Fatalf("got %+v, want oscillation after 3 cycles", err)
+	}
+
+	// The same calls with changing results are progress, not a cycle.
+	d = newLoopDetector(0, 2)
+	for i, out := range []string{"1", "2", "3", "4"} {
+		if err := d.observe([]string{"a", "b"}[i%2], "{}", out, i+1); err != nil {
+			t.Fatalf("step %d: unexpected %v", i+1, err)
+		}
+	}
+}
+
+func TestLoopDetectorOff(t *testing.T) {
+	if d := newLoopDetector(0, -1); d != nil {
+		t.Fatal("expected nil detector with both checks off")
+	}
+	var d *loopDetector
+	if err := d.observe("fetch", "{}", "", 1); err != nil {
+		t.Fatalf("nil detector: %v", err)
+	}
+}
+
+func TestExecutorStopsRepeatedToolCall(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return &llm.ChatResponse{
+				Message: llm.ChatMessage{
+					Role: llm.RoleAssistant,
+					ToolCalls: []llm.ToolCall{{
+						ID: "call", Type: "function",
+						Function: llm.FunctionCall{Name: "status", Arguments: `{"job":"42"}`},
+					}},
+				},
+				FinishReason: "tool_calls",
+			}, nil
+		},
+	}
+	calls := 0
+	toolExec := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, _ json.RawMessage) (string, error) {
+			calls++
+			return "pending", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "status"}}},
+	}
+	var hookErr error
+	hooks := NewHookRegistry()
+	hooks.Register(OnError, func(_ context.Context, hctx *HookContext) error {
+		hookErr = hctx.Error
+		return nil
+	})
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: toolExec, Hooks: hooks, LoopRepeatLimit: 3})
+
+	_, err := executor.Execute(context.Background(), &a2a.Task{ID: "task-1"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("wait for job 42")},
+	})
+	var loop *LoopDetectedError
+	if !errors.As(err, &loop) {
+		t.Fatalf("err = %v, want a LoopDetectedError", err)
+	}
+	if calls != 3 || loop.Iteration != 3 {
+		t.Errorf("stopped after %d calls in iteration %d, want 3 and 3", calls, loop.Iteration)
+	}
+	if hookErr != err {
+		t.Errorf("OnError hook got %v, want the loop error", hookErr)
+	}
+	te := ClassifyError(err)
+	if te.Code != a2a.ErrorLoopDetected || te.Details["kind"] != LoopRepeatedCall || te.Details["args"] != `{"job":"42"}` {
+		t.Errorf("classified as %+v", te)
+	}
+}
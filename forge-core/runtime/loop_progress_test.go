@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestExecutorProgressEvents(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				return &llm.ChatResponse{
+					Message: llm.ChatMessage{
+						Role: llm.RoleAssistant,
+						ToolCalls: []llm.ToolCall{{
+							ID: "call_1", Type: "function",
+							Function: llm.FunctionCall{Name: "fetch", Arguments: `{}`},
+						}},
+					},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	toolExec := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, _ json.RawMessage) (string, error) {
+			ReportProgress(ctx, 150, "halfway and then some")
+			return "ok", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "fetch"}}},
+	}
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: toolExec, MaxIterations: 5})
+
+	var events []ProgressEvent
+	ctx := WithProgressEmitter(context.Background(), func(ev ProgressEvent) {
+		events = append(events, ev)
+	})
+	_, err := executor.Execute(ctx, &a2a.Task{ID: "task-1"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("fetch it")},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	want := []struct {
+		phase ProgressPhase
+		tool  string
+		iter  int
+	}{
+		{ProgressPlanning, "", 1},
+		{ProgressToolStream, "fetch", 1},
+		{ProgressLLMThinking, "", 2},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		ev := events[i]
+		if ev.Phase != w.phase || ev.Tool != w.tool || ev.Iteration != w.iter || ev.MaxIterations != 5 {
+			t.Errorf("events[%d] = %+v, want phase %s tool %q iteration %d/5", i, ev, w.phase, w.tool, w.iter)
+		}
+	}
+	if p := events[1].Percent; p == nil || *p != 100 {
+		t.Errorf("tool_stream percent = %v, want clamped to 100", p)
+	}
+}
+
+func TestReportProgressWithoutEmitter(t *testing.T) {
+	ReportProgress(context.Background(), 50, "no client") // must not panic
+}
@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+func TestExecutorTaskScratch(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		scratchRoot := t.TempDir()
+		var seen string
+		calls := 0
+		client := &mockLLMClient{
+			chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+				calls++
+				if calls == 1 {
+					return &llm.ChatResponse{
+						Message: llm.ChatMessage{
+							Role: llm.RoleAssistant,
+							ToolCalls: []llm.ToolCall{{
+								ID: "call_1", Type: "function",
+								Function: llm.FunctionCall{Name: "file_write", Arguments: `{}`},
+							}},
+						},
+						FinishReason: "tool_calls",
+					}, nil
+				}
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"},
+					FinishReason: "stop",
+				}, nil
+			},
+		}
+		toolExec := &mockToolExecutor{
+			executeFunc: func(ctx context.Context, name string, _ json.RawMessage) (string, error) {
+				seen = tools.TaskScratchFromContext(ctx)
+				if fi, err := os.Stat(seen); err != nil || !fi.IsDir() {
+					t.Errorf("scratch dir %q not created: %v", seen, err)
+				}
+				return "ok", nil
+			},
+			toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "file_write"}}},
+		}
+		executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: toolExec, ScratchDir: scratchRoot, KeepScratch: keep})
+
+		_, err := executor.Execute(context.Background(), &a2a.Task{ID: "../task-1"}, &a2a.Message{
+			Role:  a2a.MessageRoleUser,
+			Parts: []a2a.Part{a2a.NewTextPart("write it")},
+		})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if filepath.Dir(seen) != scratchRoot {
+			t.Fatalf("scratch dir = %q, want one directly under %s", seen, scratchRoot)
+		}
+		if _, err := os.Stat(seen); os.IsNotExist(err) == keep {
+			t.Errorf("keep_scratch=%v: scratch dir exists after Execute = %v", keep, err == nil)
+		}
+	}
+}
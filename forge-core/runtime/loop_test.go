@@ -1354,3 +1354,23 @@ func TestNoStopNudgeForQAConversation(t *testing.T) {
 		t.Errorf("expected 2 LLM calls for Q&A, got %d", callIdx)
 	}
 }
+
+func TestA2AMessageToLLM_AttachedFiles(t *testing.T) {
+	got := a2aMessageToLLM(a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{
+		a2a.NewTextPart("what is this?"),
+		a2a.NewFilePart(a2a.FileContent{Name: "chart.png", MimeType: "image/png", URI: ".forge/files/uploads-t1/chart.png"}),
+		a2a.NewFilePart(a2a.FileContent{Name: "raw.bin", Bytes: []byte{1}}),
+	}})
+	want := "what is this?\n[attached file chart.png (image/png): .forge/files/uploads-t1/chart.png]"
+	if got.Content != want {
+		t.Errorf("content = %q, want %q", got.Content, want)
+	}
+	// The agent's own file parts are its output, not something to read.
+	agent := a2aMessageToLLM(a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{
+		a2a.NewTextPart("report attached"),
+		a2a.NewFilePart(a2a.FileContent{Name: "report.md", URI: "report.md"}),
+	}})
+	if agent.Content != "report attached" {
+		t.Errorf("agent content = %q", agent.Content)
+	}
+}
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"sync"
 
@@ -59,6 +60,20 @@ type Memory struct {
 	messages        []llm.ChatMessage
 	existingSummary string // compacted summary from prior context
 	maxChars        int    // approximate token budget: 1 token ~ 4 chars
+	usage           *UsageLedger
+	shrunk          int // tool results summarized for context space
+}
+
+// ContextUsage reports how full a task's context window is. It is carried
+// on progress events and in task metadata under "context". Tokens are
+// estimated at 4 chars each, with tool results weighted as in trimming.
+type ContextUsage struct {
+	Chars             int     `json:"chars"`
+	BudgetChars       int     `json:"budget_chars"`
+	Tokens            int     `json:"tokens"`
+	BudgetTokens      int     `json:"budget_tokens"`
+	Utilization       float64 `json:"utilization"` // Chars / BudgetChars
+	ShrunkToolResults int     `json:"shrunk_tool_results,omitempty"`
 }
 
 // NewMemory creates a Memory with the given system prompt and character budget.
@@ -140,6 +155,33 @@ func (m *Memory) LoadFromStore(data *SessionData) {
 	defer m.mu.Unlock()
 	m.messages = sanitizeMessages(data.Messages)
 	m.existingSummary = data.Summary
+	m.usage = data.Usage.Clone()
+}
+
+// SetUsage seeds the task's usage ledger (from task metadata when no
+// session was recovered).
+func (m *Memory) SetUsage(l *UsageLedger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = l.Clone()
+}
+
+// RecordUsage adds one LLM call to the task's usage ledger.
+func (m *Memory) RecordUsage(provider, model string, u llm.UsageInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.usage == nil {
+		m.usage = &UsageLedger{}
+	}
+	m.usage.Add(provider, model, u)
+}
+
+// Usage returns a copy of the task's usage ledger, or nil when no LLM
+// call has been recorded.
+func (m *Memory) Usage() *UsageLedger {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage.Clone()
 }
 
 // sanitizeMessages strips the known kinds of corruption from a loaded
@@ -244,6 +286,74 @@ func collapseConsecutiveDuplicates(msgs []llm.ChatMessage) []llm.ChatMessage {
 	return out
 }
 
+// ContextUsage returns the current context window utilization.
+func (m *Memory) ContextUsage() *ContextUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	chars := m.totalChars()
+	u := &ContextUsage{
+		Chars:             chars,
+		BudgetChars:       m.maxChars,
+		Tokens:            chars / charsPerToken,
+		BudgetTokens:      m.maxChars / charsPerToken,
+		ShrunkToolResults: m.shrunk,
+	}
+	if m.maxChars > 0 {
+		u.Utilization = math.Round(float64(chars)/float64(m.maxChars)*1000) / 1000
+	}
+	return u
+}
+
+// toolResultPlaceholderPrefix starts every tool result that pruning or
+// shrinking has already replaced.
+const toolResultPlaceholderPrefix = "[Tool result from "
+
+// largestToolResult returns the index and content of the largest tool
+// result over minChars that has not already been pruned or shrunk.
+func (m *Memory) largestToolResult(minChars int) (int, llm.ChatMessage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	best := -1
+	for i, msg := range m.messages {
+		if msg.Role != llm.RoleTool || len(msg.Content) <= minChars || strings.HasPrefix(msg.Content, toolResultPlaceholderPrefix) {
+			continue
+		}
+		if best < 0 || len(msg.Content) > len(m.messages[best].Content) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return 0, llm.ChatMessage{}, false
+	}
+	return best, m.messages[best], true
+}
+
+// replaceToolResult swaps in content for the tool result at idx, provided
+// it still holds old, and counts it as shrunk.
+func (m *Memory) replaceToolResult(idx int, old, content string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if idx >= len(m.messages) || m.messages[idx].Role != llm.RoleTool || m.messages[idx].Content != old {
+		return false
+	}
+	m.messages[idx].Content = content
+	m.shrunk++
+	return true
+}
+
+// replaceLastAssistant swaps content into the most recent assistant
+// message, as a reflection pass's revision replaces the draft answer.
+func (m *Memory) replaceLastAssistant(content string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].Role == llm.RoleAssistant {
+			m.messages[i].Content = content
+			return
+		}
+	}
+}
+
 // Reset clears the conversation history (keeps the system prompt).
 func (m *Memory) Reset() {
 	m.mu.Lock()
@@ -324,7 +434,7 @@ func (m *Memory) pruneToolResults() {
 		idx := toolIndices[j]
 		name := m.messages[idx].Name
 		origLen := len(m.messages[idx].Content)
-		m.messages[idx].Content = fmt.Sprintf("[Tool result from %s — %d chars, pruned for context space]", name, origLen)
+		m.messages[idx].Content = fmt.Sprintf(toolResultPlaceholderPrefix+"%s — %d chars, pruned for context space]", name, origLen)
 	}
 }
 
@@ -117,7 +117,51 @@ func (c *Compactor) MaybeCompact(taskID string, mem *Memory) (bool, error) {
 		"threshold": threshold,
 		"messages":  len(mem.messages),
 	})
+	removed, err := c.compactLocked(taskID, mem)
+	return removed > 0, err
+}
+
+// CompactResult describes one forced compaction.
+type CompactResult struct {
+	SessionID string `json:"session_id"`
+	// Compacted is false when the conversation was too short to compact;
+	// Summary then holds any summary from an earlier compaction.
+	Compacted   bool   `json:"compacted"`
+	Summary     string `json:"summary"`
+	Removed     int    `json:"removed_messages"`
+	Remaining   int    `json:"remaining_messages"`
+	CharsBefore int    `json:"chars_before"`
+	CharsAfter  int    `json:"chars_after"`
+}
+
+// Compact compacts the oldest 50% of mem's messages into its summary now,
+// whatever the trigger ratio, and returns the resulting summary.
+func (c *Compactor) Compact(taskID string, mem *Memory) (*CompactResult, error) {
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
 
+	res := &CompactResult{SessionID: taskID, CharsBefore: mem.totalChars()}
+	c.logger.Info("compaction requested", map[string]any{
+		"task_id":  taskID,
+		"total":    res.CharsBefore,
+		"messages": len(mem.messages),
+	})
+	removed, err := c.compactLocked(taskID, mem)
+	if err != nil {
+		return nil, err
+	}
+	res.Compacted = removed > 0
+	res.Summary = mem.existingSummary
+	res.Removed = removed
+	res.Remaining = len(mem.messages)
+	res.CharsAfter = mem.totalChars()
+	return res, nil
+}
+
+// compactLocked summarizes the oldest half of the messages after the
+// pinned task request into mem's summary, returning how many messages it
+// removed (0 when there were too few to split). The caller holds mem.mu.
+func (c *Compactor) compactLocked(taskID string, mem *Memory) (int, error) {
 	// Find the first user message — this is the task request that must
 	// survive all compaction cycles so the LLM knows its objective.
 	pinIdx := -1
@@ -135,25 +179,25 @@ func (c *Compactor) MaybeCompact(taskID string, mem *Memory) (bool, error) {
 	}
 	compactable := mem.messages[compactStart:]
 	if len(compactable) < 2 {
-		return false, nil
+		return 0, nil
 	}
 
 	// Take oldest 50% of the compactable range, respecting group boundaries.
 	target := len(compactable) / 2
 	splitIdx := c.findGroupBoundary(compactable, target)
 	if splitIdx <= 0 || splitIdx >= len(compactable) {
-		return false, nil
+		return 0, nil
 	}
 
 	oldMessages := compactable[:splitIdx]
 
 	// Flush key observations to long-term memory before discarding.
-	c.flushToLongTermMemory(oldMessages)
+	c.flushToLongTermMemory(taskID, oldMessages)
 
 	// Summarize the old messages.
 	summary, err := c.summarize(oldMessages, mem.existingSummary)
 	if err != nil {
-		return false, fmt.Errorf("summarization failed: %w", err)
+		return 0, fmt.Errorf("summarization failed: %w", err)
 	}
 
 	// Rebuild messages: pinned prefix + remaining compactable messages.
@@ -176,7 +220,7 @@ func (c *Compactor) MaybeCompact(taskID string, mem *Memory) (bool, error) {
 	// Flush to disk if store is available.
 	c.flushToDisk(taskID, mem)
 
-	return true, nil
+	return splitIdx, nil
 }
 
 // summarize produces a summary of the given messages, incorporating any
@@ -208,6 +252,8 @@ func (c *Compactor) llmSummarize(messages []llm.ChatMessage, existingSummary str
 	sb.WriteString("- Actions taken: what was created, modified, executed, and their outcomes\n")
 	sb.WriteString("- Errors encountered and whether they were resolved\n")
 	sb.WriteString("- What remains to be done\n\n")
+	sb.WriteString("Content shown as " + RedactedPlaceholder + " was removed at the user's request: ")
+	sb.WriteString("keep the marker and never guess or restate what it said.\n\n")
 	sb.WriteString("Format: ## State, ## Findings, ## Progress, ## Remaining\n")
 	sb.WriteString("Output only the summary, no preamble.\n\n")
 
@@ -310,6 +356,7 @@ func (c *Compactor) flushToDisk(taskID string, mem *Memory) {
 		TaskID:   taskID,
 		Messages: mem.messages,
 		Summary:  mem.existingSummary,
+		Usage:    mem.usage.Clone(),
 	}
 
 	if err := c.store.Save(data); err != nil {
@@ -336,14 +383,20 @@ func extractiveLimit(toolName string) int {
 }
 
 // flushToLongTermMemory extracts key observations from messages being
-// compacted and appends them to the long-term daily log.
-func (c *Compactor) flushToLongTermMemory(messages []llm.ChatMessage) {
+// compacted and appends them to the long-term daily log. Redacted
+// messages (see EditSessionMessage) are tombstones and are skipped. The
+// flusher sees the task ID on the context, so a tenant's observations
+// can go to the tenant's memory.
+func (c *Compactor) flushToLongTermMemory(taskID string, messages []llm.ChatMessage) {
 	if c.memoryFlusher == nil {
 		return
 	}
 
 	var observations strings.Builder
 	for _, msg := range messages {
+		if msg.Content == RedactedPlaceholder {
+			continue
+		}
 		switch msg.Role {
 		case llm.RoleTool:
 			// Tool results contain factual observations worth preserving.
@@ -375,7 +428,7 @@ func (c *Compactor) flushToLongTermMemory(messages []llm.ChatMessage) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(WithTaskID(context.Background(), taskID), 5*time.Second)
 	defer cancel()
 
 	if err := c.memoryFlusher.AppendDailyLog(ctx, observations.String()); err != nil {
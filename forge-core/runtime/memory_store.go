@@ -15,11 +15,14 @@ import (
 
 // SessionData holds the persisted state for a single task's conversation.
 type SessionData struct {
-	TaskID    string            `json:"task_id"`
-	Messages  []llm.ChatMessage `json:"messages"`
-	Summary   string            `json:"summary,omitempty"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	TaskID   string            `json:"task_id"`
+	Messages []llm.ChatMessage `json:"messages"`
+	Summary  string            `json:"summary,omitempty"`
+	// Usage is the task's cumulative token and cost ledger, carried
+	// across turns and restarts with the conversation.
+	Usage     *UsageLedger `json:"usage,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
 }
 
 // MemoryStore provides file-backed session persistence.
@@ -0,0 +1,192 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// outputViolationsMax caps the schema errors listed for one answer, so a
+// badly wrong answer doesn't turn the repair prompt into an essay.
+const outputViolationsMax = 10
+
+// OutputContract is what a skill requires of the final answer of a task
+// that called its tool: a JSON Schema the answer must satisfy, a length
+// cap, or both. An answer that breaks a contract gets one repair attempt
+// before the task fails with an OutputContractError.
+type OutputContract struct {
+	MaxLength int // characters; 0 is no cap
+	schema    *gojsonschema.Schema
+}
+
+// NewOutputContract compiles a contract. A nil or empty schema checks
+// only the length.
+func NewOutputContract(schema json.RawMessage, maxLength int) (*OutputContract, error) {
+	c := &OutputContract{MaxLength: maxLength}
+	if len(schema) > 0 {
+		s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+		if err != nil {
+			return nil, fmt.Errorf("compiling output schema: %w", err)
+		}
+		c.schema = s
+	}
+	return c, nil
+}
+
+// check returns how answer breaks the contract, nil when it doesn't.
+func (c *OutputContract) check(answer string) []string {
+	var violations []string
+	if c.MaxLength > 0 {
+		if n := utf8.RuneCountInString(answer); n > c.MaxLength {
+			violations = append(violations, fmt.Sprintf("the answer is %d characters long; the limit is %d", n, c.MaxLength))
+		}
+	}
+	if c.schema == nil {
+		return violations
+	}
+	doc := stripCodeFence(answer)
+	if !json.Valid([]byte(doc)) {
+		return append(violations, "the answer must be a single JSON document, with no text around it")
+	}
+	res, err := c.schema.Validate(gojsonschema.NewStringLoader(doc))
+	if err != nil {
+		return append(violations, "the answer could not be checked against the schema: "+err.Error())
+	}
+	for i, re := range res.Errors() {
+		if i == outputViolationsMax {
+			violations = append(violations, fmt.Sprintf("and %d more schema errors", len(res.Errors())-i))
+			break
+		}
+		violations = append(violations, re.String())
+	}
+	return violations
+}
+
+// stripCodeFence unwraps an answer sent as one fenced code block, the
+// way models often wrap JSON.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") || !strings.HasSuffix(s, "```") || len(s) < 6 {
+		return s
+	}
+	body := strings.TrimSuffix(s, "```")
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		return strings.TrimSpace(body[nl+1:])
+	}
+	return s
+}
+
+// OutputContractError fails a task whose final answer still broke a
+// skill's output contract after the repair attempt. It is classified as
+// a2a.ErrorOutputInvalid.
+type OutputContractError struct {
+	Tools      []string // the tools whose contracts the answer broke
+	Violations []string // how the repaired answer broke them
+}
+
+func (e *OutputContractError) Error() string {
+	return fmt.Sprintf("answer breaks the output contract of %s: %s", strings.Join(e.Tools, ", "), strings.Join(e.Violations, "; "))
+}
+
+// ErrorCode implements the interface ClassifyError looks for.
+func (e *OutputContractError) ErrorCode() a2a.ErrorCode { return a2a.ErrorOutputInvalid }
+
+// ErrorDetails is the diagnostic context ClassifyError puts on the
+// TaskError.
+func (e *OutputContractError) ErrorDetails() map[string]any {
+	return map[string]any{"tools": e.Tools, "violations": e.Violations}
+}
+
+// checkOutput checks answer against the contracts of toolsUsed and
+// returns the tools whose contracts it broke and how.
+func (e *LLMExecutor) checkOutput(answer string, toolsUsed []string) ([]string, []string) {
+	var broken, violations []string
+	for _, tool := range dedup(toolsUsed) {
+		c := e.outputContracts[tool]
+		if c == nil {
+			continue
+		}
+		if v := c.check(answer); len(v) > 0 {
+			broken = append(broken, tool)
+			violations = append(violations, v...)
+		}
+	}
+	slices.Sort(broken)
+	return broken, slices.Compact(violations)
+}
+
+// enforceOutput holds answer, the final answer already appended to mem,
+// to the output contracts of the skills whose tools the task called.
+// An answer that breaks one is sent back to the model once with the
+// violations; the repair replaces the answer in mem. A repair that still
+// breaks a contract fails the task with an OutputContractError.
+func (e *LLMExecutor) enforceOutput(ctx context.Context, mem *Memory, answer llm.ChatMessage, toolsUsed []string, toolDefs []llm.ToolDefinition) (llm.ChatMessage, error) {
+	if len(e.outputContracts) == 0 {
+		return answer, nil
+	}
+	broken, violations := e.checkOutput(answer.Content, toolsUsed)
+	if len(broken) == 0 {
+		return answer, nil
+	}
+	e.logger.Info("answer breaks an output contract, asking for a repair", map[string]any{
+		"task_id": TaskIDFromContext(ctx), "tools": broken, "violations": len(violations),
+	})
+
+	// As with a reflection revision, tool definitions ride along for
+	// providers that reject a tool-call history without them; a repair
+	// that calls a tool instead of answering counts as a failed one.
+	messages := append(mem.Messages(), llm.ChatMessage{
+		Role: llm.RoleUser,
+		Content: "Your answer does not meet the required output format:\n\n- " + strings.Join(violations, "\n- ") +
+			"\n\nReply again with the complete answer in the required format only.",
+	})
+	start := time.Now()
+	resp, err := e.client.Chat(ctx, &llm.ChatRequest{Messages: messages, Tools: toolDefs})
+	if err != nil {
+		return answer, fmt.Errorf("output repair: %w", err)
+	}
+	e.recordUsage(mem, resp)
+	provider, model := e.model()
+	if err := e.hooks.Fire(ctx, AfterLLMCall, &HookContext{
+		Messages:        messages,
+		Response:        resp,
+		TaskID:          TaskIDFromContext(ctx),
+		CorrelationID:   CorrelationIDFromContext(ctx),
+		LLMCallDuration: time.Since(start),
+		Provider:        provider,
+		Model:           model,
+	}); err != nil {
+		return answer, fmt.Errorf("after LLM call hook: %w", err)
+	}
+	if len(resp.Message.ToolCalls) > 0 {
+		return answer, &OutputContractError{Tools: broken, Violations: violations}
+	}
+	if broken, violations = e.checkOutput(resp.Message.Content, toolsUsed); len(broken) > 0 {
+		return answer, &OutputContractError{Tools: broken, Violations: violations}
+	}
+	e.logger.Info("repaired answer meets the output contracts", map[string]any{"task_id": TaskIDFromContext(ctx)})
+	mem.replaceLastAssistant(resp.Message.Content)
+	return resp.Message, nil
+}
+
+// outputFailed ends a task whose answer broke an output contract: the
+// OnError hooks see the failure, and the session is kept so the user
+// can carry on the conversation.
+func (e *LLMExecutor) outputFailed(ctx context.Context, taskID string, mem *Memory, err error) error {
+	e.logger.Warn("answer rejected", map[string]any{"task_id": taskID, "error": err.Error()})
+	_ = e.hooks.Fire(ctx, OnError, &HookContext{
+		Error:         err,
+		TaskID:        TaskIDFromContext(ctx),
+		CorrelationID: CorrelationIDFromContext(ctx),
+	})
+	e.persistSession(taskID, mem)
+	return err
+}
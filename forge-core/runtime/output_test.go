@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+const reportSchema = `{"type":"object","required":["total"],"properties":{"total":{"type":"number"}}}`
+
+// runContract runs a task whose model calls the report tool once and
+// then answers with each of answers in turn, returning the answer and
+// the requests the model saw.
+func runContract(t *testing.T, contract *OutputContract, hooks *HookRegistry, answers ...string) (*a2a.Message, []*llm.ChatRequest, error) {
+	t.Helper()
+	var reqs []*llm.ChatRequest
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		reqs = append(reqs, req)
+		if len(reqs) == 1 {
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{
+				ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "report", Arguments: "{}"},
+			}}}}, nil
+		}
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: answers[len(reqs)-2]}}, nil
+	}}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			return "invoices: 3", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "report"}}},
+	}
+	out, err := NewLLMExecutor(LLMExecutorConfig{
+		Client:          client,
+		Tools:           tools,
+		Hooks:           hooks,
+		OutputContracts: map[string]*OutputContract{"report": contract},
+	}).Execute(context.Background(), &a2a.Task{ID: "t1"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("sum the invoices")},
+	})
+	return out, reqs, err
+}
+
+func mustContract(t *testing.T, schema string, maxLength int) *OutputContract {
+	t.Helper()
+	c, err := NewOutputContract(json.RawMessage(schema), maxLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestOutputContractAcceptsValidAnswer(t *testing.T) {
+	answer := "```json\n{\"total\": 42}\n```"
+	out, reqs, err := runContract(t, mustContract(t, reportSchema, 0), nil, answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ExtractText(out); got != answer || len(reqs) != 2 {
+		t.Errorf("answer = %q after %d model calls; want the answer untouched", got, len(reqs))
+	}
+}
+
+func TestOutputContractRepairsAnswer(t *testing.T) {
+	out, reqs, err := runContract(t, mustContract(t, reportSchema, 0), nil, `{"total": "many"}`, `{"total": 42}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ExtractText(out); got != `{"total": 42}` {
+		t.Errorf("answer = %q, want the repair", got)
+	}
+	if last := reqs[2].Messages[len(reqs[2].Messages)-1]; !strings.Contains(last.Content, "total") {
+		t.Errorf("repair request = %q, want the violation", last.Content)
+	}
+}
+
+func TestOutputContractFailsUnrepairedAnswer(t *testing.T) {
+	hooks := NewHookRegistry()
+	var seen error
+	hooks.Register(OnError, func(ctx context.Context, hc *HookContext) error {
+		seen = hc.Error
+		return nil
+	})
+	_, _, err := runContract(t, mustContract(t, reportSchema, 20), hooks, "The total is 42.", strings.Repeat("42 ", 10))
+
+	var oc *OutputContractError
+	if !errors.As(err, &oc) || len(oc.Tools) != 1 || oc.Tools[0] != "report" {
+		t.Fatalf("err = %v, want an OutputContractError for report", err)
+	}
+	if len(oc.Violations) != 2 {
+		t.Errorf("violations = %q, want the length and the JSON ones", oc.Violations)
+	}
+	if te := ClassifyError(err); te.Code != a2a.ErrorOutputInvalid || te.Details["tools"] == nil {
+		t.Errorf("classified as %+v", te)
+	}
+	if seen != err {
+		t.Errorf("OnError saw %v", seen)
+	}
+}
+
+func TestOutputContractOnlyForSkillTools(t *testing.T) {
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "not JSON"}}, nil
+	}}
+	out, err := NewLLMExecutor(LLMExecutorConfig{
+		Client:          client,
+		OutputContracts: map[string]*OutputContract{"report": mustContract(t, reportSchema, 0)},
+	}).Execute(context.Background(), &a2a.Task{ID: "t1"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("hello")},
+	})
+	if err != nil || ExtractText(out) != "not JSON" {
+		t.Errorf("answer = %v, %v; a run that never called report has no contract", out, err)
+	}
+}
+
+func TestNewOutputContractRejectsBadSchema(t *testing.T) {
+	if _, err := NewOutputContract(json.RawMessage(`{"type": 7}`), 0); err == nil {
+		t.Error("want an error for a schema that doesn't compile")
+	}
+}
@@ -0,0 +1,357 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// PlanConfig turns on planner-executor mode: before the agent loop
+// starts, a planning call breaks the request into steps, each with the
+// tools it may use, and the loop works through them one at a time. A
+// step the agent reports as failed makes the planner re-plan the
+// remaining work.
+type PlanConfig struct {
+	// Client makes the planning calls. Nil uses the executor's client.
+	Client     llm.Client
+	MaxSteps   int // steps per plan (0 = 8)
+	MaxReplans int // re-plans after failed steps per task (0 = 2, negative = none)
+}
+
+// TaskStep statuses.
+const (
+	TaskStepPending    = "pending"
+	TaskStepInProgress = "in_progress"
+	TaskStepDone       = "done"
+	TaskStepFailed     = "failed"
+)
+
+// MetadataKeyPlan is the task metadata key the plan of a task run in
+// planner-executor mode is recorded under, as a *TaskPlan.
+const MetadataKeyPlan = "plan"
+
+// TaskPlan is the step list a planning call produced, with each step's
+// progress.
+type TaskPlan struct {
+	Steps   []TaskStep `json:"steps"`
+	Replans int        `json:"replans,omitempty"`
+}
+
+// TaskStep is one step of a TaskPlan. Tools lists the tools the step may
+// call; empty allows every tool.
+type TaskStep struct {
+	ID     int      `json:"id"`
+	Goal   string   `json:"goal"`
+	Tools  []string `json:"tools,omitempty"`
+	Status string   `json:"status"`
+	// Result is the agent's one-line report on the finished step, or
+	// why it failed.
+	Result string `json:"result,omitempty"`
+}
+
+// clone copies p, so a snapshot sent to progress subscribers doesn't
+// change under them.
+func (p *TaskPlan) clone() *TaskPlan {
+	return &TaskPlan{Replans: p.Replans, Steps: slices.Clone(p.Steps)}
+}
+
+// current returns the step in progress, or nil once none is.
+func (p *TaskPlan) current() *TaskStep {
+	for i := range p.Steps {
+		if p.Steps[i].Status == TaskStepInProgress {
+			return &p.Steps[i]
+		}
+	}
+	return nil
+}
+
+// stepFailedPrefix starts the reply the agent gives for a step it could
+// not complete.
+const stepFailedPrefix = "STEP FAILED:"
+
+const (
+	defaultPlanMaxSteps   = 8
+	defaultPlanMaxReplans = 2
+)
+
+// plannerPrompt is the planning call's system instruction. %d is the
+// step limit.
+const plannerPrompt = `You plan how an AI agent will carry out a user's request. Break the request into at most %d steps the agent will work through in order. Give each step a concrete goal and list the tools it needs, chosen from the available tools; omit "tools" to let a step use any tool. A simple request needs only one step. Reply with JSON only, in this shape:
+{"steps": [{"goal": "...", "tools": ["tool_name"]}]}`
+
+// planRun is one Execute call's progress through its plan.
+type planRun struct {
+	cfg     *PlanConfig
+	plan    *TaskPlan
+	request string
+	// finishing is set once no step is left: the next reply without
+	// tool calls is the final answer.
+	finishing bool
+}
+
+// startPlan plans the request and starts its first step, returning the
+// run and the prompt that opens the step. On failure it logs and returns
+// nil: the task then runs in direct mode.
+func (e *LLMExecutor) startPlan(ctx context.Context, mem *Memory, request string, toolDefs []llm.ToolDefinition) (*planRun, string) {
+	if emit := ProgressEmitterFromContext(ctx); emit != nil {
+		emit(ProgressEvent{Phase: ProgressPlanning, Message: "Planning..."})
+	}
+	steps, err := e.requestPlan(ctx, mem, request, toolDefs, nil, "")
+	if err != nil {
+		e.logger.Warn("planning failed, running the task directly", map[string]any{
+			"task_id": TaskIDFromContext(ctx), "error": err.Error(),
+		})
+		return nil, ""
+	}
+	run := &planRun{cfg: e.planning, plan: &TaskPlan{}, request: request}
+	run.appendSteps(steps)
+	e.logger.Info("task planned", map[string]any{"task_id": TaskIDFromContext(ctx), "steps": len(steps)})
+	return run, run.startNext(ctx)
+}
+
+// appendSteps adds planned steps after the ones already in the plan.
+func (r *planRun) appendSteps(steps []TaskStep) {
+	for _, s := range steps {
+		s.ID = len(r.plan.Steps) + 1
+		s.Status = TaskStepPending
+		r.plan.Steps = append(r.plan.Steps, s)
+	}
+}
+
+// startNext moves the first pending step in progress and returns the
+// prompt that opens it, or, with no step left, the prompt asking for the
+// final answer.
+func (r *planRun) startNext(ctx context.Context) string {
+	for i := range r.plan.Steps {
+		s := &r.plan.Steps[i]
+		if s.Status != TaskStepPending {
+			continue
+		}
+		s.Status = TaskStepInProgress
+		r.emit(ctx, fmt.Sprintf("Step %d of %d: %s", s.ID, len(r.plan.Steps), s.Goal))
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Plan step %d of %d: %s\n\n", s.ID, len(r.plan.Steps), s.Goal)
+		if len(s.Tools) > 0 {
+			fmt.Fprintf(&sb, "Tools for this step: %s.\n", strings.Join(s.Tools, ", "))
+		}
+		sb.WriteString("Work on this step only. When it is done, reply with a one-line result and no tool calls. " +
+			"If it cannot be done, reply with " + stepFailedPrefix + " and the reason.")
+		return sb.String()
+	}
+	r.finishing = true
+	r.emit(ctx, "All steps done")
+	return "All plan steps are finished. Now give your final answer to the user's original request, based on the step results above. Do not mention the plan."
+}
+
+// finishStep records the reply that ended the current step. A failed step is
+// re-planned while re-plans are left; otherwise the run moves on. Returns
+// the prompt for what comes next.
+func (e *LLMExecutor) finishStep(ctx context.Context, r *planRun, mem *Memory, reply string, toolDefs []llm.ToolDefinition) string {
+	s := r.plan.current()
+	if s == nil {
+		return r.startNext(ctx)
+	}
+	reply = strings.TrimSpace(reply)
+	if reason, failed := strings.CutPrefix(reply, stepFailedPrefix); failed {
+		s.Status, s.Result = TaskStepFailed, strings.TrimSpace(reason)
+		if r.plan.Replans < r.maxReplans() {
+			r.emit(ctx, fmt.Sprintf("Step %d failed, re-planning", s.ID))
+			steps, err := e.requestPlan(ctx, mem, r.request, toolDefs, r.plan, s.Result)
+			if err == nil {
+				r.plan.Replans++
+				// The new plan replaces whatever was left of the old one.
+				r.plan.Steps = slices.DeleteFunc(r.plan.Steps, func(st TaskStep) bool { return st.Status == TaskStepPending })
+				r.appendSteps(steps)
+				e.logger.Info("task re-planned", map[string]any{
+					"task_id": TaskIDFromContext(ctx), "failed_step": s.ID, "steps": len(steps),
+				})
+			} else {
+				e.logger.Warn("re-planning failed", map[string]any{"task_id": TaskIDFromContext(ctx), "error": err.Error()})
+			}
+		}
+	} else {
+		s.Status, s.Result = TaskStepDone, truncateRunes(reply, 500)
+	}
+	return r.startNext(ctx)
+}
+
+func (r *planRun) maxReplans() int {
+	switch n := r.cfg.MaxReplans; {
+	case n == 0:
+		return defaultPlanMaxReplans
+	case n < 0:
+		return 0
+	default:
+		return n
+	}
+}
+
+// emit reports the plan's state to the client.
+func (r *planRun) emit(ctx context.Context, message string) {
+	if emit := ProgressEmitterFromContext(ctx); emit != nil {
+		emit(ProgressEvent{Phase: ProgressPlan, Message: message, Plan: r.plan.clone()})
+	}
+}
+
+// active reports whether replies without tool calls end plan steps
+// rather than the task. A nil run is never active.
+func (r *planRun) active() bool { return r != nil && !r.finishing }
+
+// tools narrows toolDefs to the current step's tools.
+func (r *planRun) tools(toolDefs []llm.ToolDefinition) []llm.ToolDefinition {
+	if !r.active() {
+		return toolDefs
+	}
+	s := r.plan.current()
+	if s == nil || len(s.Tools) == 0 {
+		return toolDefs
+	}
+	var out []llm.ToolDefinition
+	for _, td := range toolDefs {
+		if slices.Contains(s.Tools, td.Function.Name) {
+			out = append(out, td)
+		}
+	}
+	return out
+}
+
+// allows reports whether the current step may call tool.
+func (r *planRun) allows(tool string) bool {
+	if !r.active() {
+		return true
+	}
+	s := r.plan.current()
+	return s == nil || len(s.Tools) == 0 || slices.Contains(s.Tools, tool)
+}
+
+// requestPlan makes a planning call. With prior set it re-plans after
+// the failure of one of prior's steps, planning only the remaining work.
+func (e *LLMExecutor) requestPlan(ctx context.Context, mem *Memory, request string, toolDefs []llm.ToolDefinition, prior *TaskPlan, failure string) ([]TaskStep, error) {
+	maxSteps := e.planning.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = defaultPlanMaxSteps
+	}
+	client := e.planning.Client
+	if client == nil {
+		client = e.client
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Agent instructions\n")
+	sb.WriteString(truncateRunes(e.basePrompt(ctx), reflectionInstructionsMax))
+	sb.WriteString("\n\n## Available tools\n")
+	known := make(map[string]bool, len(toolDefs))
+	for _, td := range toolDefs {
+		known[td.Function.Name] = true
+		fmt.Fprintf(&sb, "- %s: %s\n", td.Function.Name, truncateRunes(td.Function.Description, 200))
+	}
+	if earlier := priorTurns(mem); earlier != "" {
+		sb.WriteString("\n## Earlier conversation\n")
+		sb.WriteString(earlier)
+	}
+	sb.WriteString("\n## User request\n")
+	sb.WriteString(request)
+	if prior != nil {
+		sb.WriteString("\n\n## Progress so far\n")
+		for _, s := range prior.Steps {
+			if s.Status == TaskStepDone || s.Status == TaskStepFailed {
+				fmt.Fprintf(&sb, "- [%s] %s: %s\n", s.Status, s.Goal, s.Result)
+			}
+		}
+		fmt.Fprintf(&sb, "\nThe last step failed: %s\nPlan only the remaining work, taking a different approach to the failed step.", failure)
+	}
+
+	messages := []llm.ChatMessage{
+		{Role: llm.RoleSystem, Content: fmt.Sprintf(plannerPrompt, maxSteps)},
+		{Role: llm.RoleUser, Content: sb.String()},
+	}
+	start := time.Now()
+	resp, err := client.Chat(ctx, &llm.ChatRequest{Messages: messages})
+	if err != nil {
+		return nil, err
+	}
+	e.recordUsage(mem, resp)
+	provider, model := e.model()
+	if err := e.hooks.Fire(ctx, AfterLLMCall, &HookContext{
+		Messages:        messages,
+		Response:        resp,
+		TaskID:          TaskIDFromContext(ctx),
+		CorrelationID:   CorrelationIDFromContext(ctx),
+		LLMCallDuration: time.Since(start),
+		Provider:        provider,
+		Model:           model,
+	}); err != nil {
+		return nil, fmt.Errorf("after LLM call hook: %w", err)
+	}
+	return parsePlan(resp.Message.Content, known, maxSteps)
+}
+
+// parsePlan reads a planner reply. Tools the agent doesn't have are
+// dropped from a step; steps past maxSteps are dropped.
+func parsePlan(content string, known map[string]bool, maxSteps int) ([]TaskStep, error) {
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("planner reply is not JSON")
+	}
+	var reply struct {
+		Steps []struct {
+			Goal  string   `json:"goal"`
+			Tools []string `json:"tools"`
+		} `json:"steps"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &reply); err != nil {
+		return nil, fmt.Errorf("parsing plan: %w", err)
+	}
+	var steps []TaskStep
+	for _, s := range reply.Steps {
+		goal := strings.TrimSpace(s.Goal)
+		if goal == "" {
+			continue
+		}
+		step := TaskStep{Goal: goal}
+		for _, t := range s.Tools {
+			if known[t] && !slices.Contains(step.Tools, t) {
+				step.Tools = append(step.Tools, t)
+			}
+		}
+		steps = append(steps, step)
+		if len(steps) == maxSteps {
+			break
+		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("plan has no steps")
+	}
+	return steps, nil
+}
+
+// priorTurnsMax caps the earlier conversation a planning call sees.
+const priorTurnsMax = 4_000
+
+// priorTurns renders the user and assistant text before the current
+// request, newest kept when it is too long.
+func priorTurns(mem *Memory) string {
+	msgs := mem.Messages()
+	// The last message is the request itself.
+	if len(msgs) > 0 {
+		msgs = msgs[:len(msgs)-1]
+	}
+	var turns []string
+	size := 0
+	for i := len(msgs) - 1; i >= 0 && size < priorTurnsMax; i-- {
+		m := msgs[i]
+		if (m.Role != llm.RoleUser && m.Role != llm.RoleAssistant) || strings.TrimSpace(m.Content) == "" {
+			continue
+		}
+		t := fmt.Sprintf("%s: %s\n", m.Role, truncateRunes(m.Content, priorTurnsMax-size))
+		size += len(t)
+		turns = append(turns, t)
+	}
+	slices.Reverse(turns)
+	return strings.Join(turns, "")
+}
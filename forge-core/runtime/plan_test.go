@@ -0,0 +1,183 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestParsePlan(t *testing.T) {
+	known := map[string]bool{"fetch": true, "mail": true}
+	steps, err := parsePlan("Here is the plan:\n```json\n"+
+		`{"steps": [{"goal": "fetch the invoices", "tools": ["fetch", "nope", "fetch"]}, {"goal": " "}, {"goal": "sum them"}, {"goal": "mail it", "tools": ["mail"]}]}`+
+		"\n```", known, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TaskStep{{Goal: "fetch the invoices", Tools: []string{"fetch"}}, {Goal: "sum them"}}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps = %+v, want %+v", steps, want)
+	}
+
+	for _, bad := range []string{"no plan", `{"steps": []}`, `{"steps": [`} {
+		if _, err := parsePlan(bad, known, 8); err == nil {
+			t.Errorf("parsePlan(%q): expected an error", bad)
+		}
+	}
+}
+
+func planTools() *mockToolExecutor {
+	return &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			return name + " ok", nil
+		},
+		toolDefs: []llm.ToolDefinition{
+			{Type: "function", Function: llm.FunctionSchema{Name: "fetch"}},
+			{Type: "function", Function: llm.FunctionSchema{Name: "mail"}},
+		},
+	}
+}
+
+func toolCall(name string) *llm.ChatResponse {
+	return &llm.ChatResponse{Message: llm.ChatMessage{
+		Role:      llm.RoleAssistant,
+		ToolCalls: []llm.ToolCall{{ID: "c-" + name, Type: "function", Function: llm.FunctionCall{Name: name, Arguments: "{}"}}},
+	}}
+}
+
+func reply(text string) *llm.ChatResponse {
+	return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: text}}
+}
+
+func isPlanningCall(req *llm.ChatRequest) bool {
+	return len(req.Messages) > 0 && strings.HasPrefix(req.Messages[0].Content, "You plan how")
+}
+
+func TestPlanModeWorksThroughSteps(t *testing.T) {
+	var stepOneTools []string
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		if isPlanningCall(req) {
+			return reply(`{"steps": [{"goal": "fetch the invoices", "tools": ["fetch"]}, {"goal": "sum them"}]}`), nil
+		}
+		last := req.Messages[len(req.Messages)-1]
+		switch {
+		case strings.HasPrefix(last.Content, "Plan step 1"):
+			for _, td := range req.Tools {
+				stepOneTools = append(stepOneTools, td.Function.Name)
+			}
+			return toolCall("mail"), nil // not a step 1 tool
+		case last.Role == llm.RoleTool && last.Name == "mail":
+			return toolCall("fetch"), nil
+		case last.Role == llm.RoleTool:
+			return reply("fetched 3 invoices"), nil
+		case strings.HasPrefix(last.Content, "Plan step 2"):
+			return reply("total is 42"), nil
+		case strings.HasPrefix(last.Content, "All plan steps"):
+			return reply("The invoices total 42."), nil
+		}
+		t.Errorf("unexpected request ending in %q", last.Content)
+		return reply(""), nil
+	}}
+	tools := planTools()
+	var executed []string
+	exec := tools.executeFunc
+	tools.executeFunc = func(ctx context.Context, name string, args json.RawMessage) (string, error) {
+		executed = append(executed, name)
+		return exec(ctx, name, args)
+	}
+
+	var events []ProgressEvent
+	ctx := WithProgressEmitter(context.Background(), func(ev ProgressEvent) { events = append(events, ev) })
+	task := &a2a.Task{ID: "t1"}
+	out, err := NewLLMExecutor(LLMExecutorConfig{
+		Client:   client,
+		Tools:    tools,
+		Planning: &PlanConfig{},
+	}).Execute(ctx, task, &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("sum the invoices")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ExtractText(out); got != "The invoices total 42." {
+		t.Errorf("answer = %q", got)
+	}
+	if !reflect.DeepEqual(stepOneTools, []string{"fetch"}) || !reflect.DeepEqual(executed, []string{"fetch"}) {
+		t.Errorf("step 1 offered %v and ran %v, want only fetch", stepOneTools, executed)
+	}
+	plan, _ := task.Metadata[MetadataKeyPlan].(*TaskPlan)
+	if plan == nil || len(plan.Steps) != 2 || plan.Steps[0].Status != TaskStepDone || plan.Steps[1].Status != TaskStepDone ||
+		plan.Steps[0].Result != "fetched 3 invoices" {
+		t.Fatalf("plan = %+v", plan)
+	}
+	var planEvents int
+	for _, ev := range events {
+		if ev.Phase == ProgressPlan {
+			planEvents++
+			if ev.Plan == nil {
+				t.Error("plan event without a plan")
+			}
+		}
+	}
+	if planEvents != 3 { // step 1, step 2, done
+		t.Errorf("plan events = %d, want 3", planEvents)
+	}
+}
+
+func TestPlanModeReplansFailedStep(t *testing.T) {
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		if isPlanningCall(req) {
+			if strings.Contains(req.Messages[1].Content, "The last step failed: api down") {
+				return reply(`{"steps": [{"goal": "read the cache"}]}`), nil
+			}
+			return reply(`{"steps": [{"goal": "query the api"}, {"goal": "never reached"}]}`), nil
+		}
+		last := req.Messages[len(req.Messages)-1]
+		switch {
+		case strings.HasPrefix(last.Content, "Plan step 1"):
+			return reply("STEP FAILED: api down"), nil
+		case strings.HasPrefix(last.Content, "Plan step 2"):
+			return reply("cache read"), nil
+		}
+		return reply("done"), nil
+	}}
+	task := &a2a.Task{ID: "t1"}
+	_, err := NewLLMExecutor(LLMExecutorConfig{
+		Client:   client,
+		Tools:    planTools(),
+		Planning: &PlanConfig{},
+	}).Execute(context.Background(), task, &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("get the data")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, _ := task.Metadata[MetadataKeyPlan].(*TaskPlan)
+	want := []TaskStep{
+		{ID: 1, Goal: "query the api", Status: TaskStepFailed, Result: "api down"},
+		{ID: 2, Goal: "read the cache", Status: TaskStepDone, Result: "cache read"},
+	}
+	if plan == nil || plan.Replans != 1 || !reflect.DeepEqual(plan.Steps, want) {
+		t.Fatalf("plan = %+v", plan)
+	}
+}
+
+func TestPlanModeFallsBackWhenPlanningFails(t *testing.T) {
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		if isPlanningCall(req) {
+			return reply("I would rather not."), nil
+		}
+		return reply("direct answer"), nil
+	}}
+	task := &a2a.Task{ID: "t1"}
+	out, err := NewLLMExecutor(LLMExecutorConfig{Client: client, Planning: &PlanConfig{}}).
+		Execute(context.Background(), task, &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ExtractText(out); got != "direct answer" || task.Metadata[MetadataKeyPlan] != nil {
+		t.Errorf("answer = %q, plan = %v; want a direct run", got, task.Metadata[MetadataKeyPlan])
+	}
+}
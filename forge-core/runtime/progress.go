@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// progressTracker stamps the loop iteration onto every progress event
+// emitted while a task runs, whether by hooks, by tools or by the loop
+// itself, so clients can show "step 3" without tracking the loop.
+type progressTracker struct {
+	emit ProgressEmitter
+	max  int
+	iter atomic.Int64
+}
+
+// trackProgress wraps ctx's progress emitter in a tracker. It returns a
+// nil tracker and ctx unchanged when the request has no client to report
+// to; a nil tracker's methods do nothing.
+func trackProgress(ctx context.Context, maxIterations int) (*progressTracker, context.Context) {
+	emit := ProgressEmitterFromContext(ctx)
+	if emit == nil {
+		return nil, ctx
+	}
+	p := &progressTracker{emit: emit, max: maxIterations}
+	return p, WithProgressEmitter(ctx, p.send)
+}
+
+// setIteration records the 1-based iteration the loop is in.
+func (p *progressTracker) setIteration(i int) {
+	if p != nil {
+		p.iter.Store(int64(i))
+	}
+}
+
+// send emits ev, filling in the iteration when it has none.
+func (p *progressTracker) send(ev ProgressEvent) {
+	if p == nil {
+		return
+	}
+	if ev.Iteration == 0 {
+		ev.Iteration = int(p.iter.Load())
+		ev.MaxIterations = p.max
+	}
+	p.emit(ev)
+}
+
+// withProgressTool names tool on the progress events emitted under ctx
+// that name none, so a tool reporting progress needn't know its name.
+func withProgressTool(ctx context.Context, tool string) context.Context {
+	emit := ProgressEmitterFromContext(ctx)
+	if emit == nil {
+		return ctx
+	}
+	return WithProgressEmitter(ctx, func(ev ProgressEvent) {
+		if ev.Tool == "" {
+			ev.Tool = tool
+		}
+		emit(ev)
+	})
+}
+
+// ReportProgress lets a running tool tell the client how far along it
+// is: a tool_stream event carrying percent (clamped to 0-100) and a
+// short message. No-op when the request has no client to report to.
+func ReportProgress(ctx context.Context, percent int, message string) {
+	emit := ProgressEmitterFromContext(ctx)
+	if emit == nil {
+		return
+	}
+	percent = min(max(percent, 0), 100)
+	emit(ProgressEvent{Phase: ProgressToolStream, Message: message, Percent: &percent})
+}
@@ -0,0 +1,354 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// DefaultProviderCaptureCapBytes caps each text field of a provider
+// capture: a message's content, a tool call's arguments. Larger than
+// the audit cap because a capture exists to show what the model saw.
+const DefaultProviderCaptureCapBytes = 64 << 10
+
+// ProviderCaptureConfig configures provider request/response capture.
+type ProviderCaptureConfig struct {
+	// Dir receives one <task>.jsonl file per captured task.
+	Dir string
+	// SamplePercent is the share of tasks whose calls are captured,
+	// 0–100. The choice hashes the task ID, so a task is captured
+	// whole or not at all, on every replica alike.
+	SamplePercent float64
+	// MaxBytes caps each captured text field; <= 0 takes
+	// DefaultProviderCaptureCapBytes.
+	MaxBytes int
+}
+
+// ProviderCapture is one captured provider call: the request the
+// executor sent and what came back, with secrets and PII redacted.
+type ProviderCapture struct {
+	Time          time.Time         `json:"time"`
+	TaskID        string            `json:"task_id,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	Provider      string            `json:"provider"`
+	Model         string            `json:"model,omitempty"`
+	Stream        bool              `json:"stream,omitempty"`
+	DurationMS    int64             `json:"duration_ms"`
+	Request       *llm.ChatRequest  `json:"request"`
+	Response      *llm.ChatResponse `json:"response,omitempty"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// ProviderCapturer records the provider calls of a sample of tasks
+// under a directory. Wrap puts it in front of a provider client.
+type ProviderCapturer struct {
+	dir      string
+	percent  float64
+	maxBytes int
+	onError  func(error)
+	mu       sync.Mutex
+}
+
+// NewProviderCapturer creates cfg.Dir (owner-only: captures hold whole
+// conversations) and returns a capturer writing to it. onError, if
+// set, is told about captures that could not be written; a failed
+// capture never fails the call.
+func NewProviderCapturer(cfg ProviderCaptureConfig, onError func(error)) (*ProviderCapturer, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating capture dir: %w", err)
+	}
+	maxBytes := cfg.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultProviderCaptureCapBytes
+	}
+	return &ProviderCapturer{dir: cfg.Dir, percent: cfg.SamplePercent, maxBytes: maxBytes, onError: onError}, nil
+}
+
+// Wrap returns client with the calls of sampled tasks captured.
+func (c *ProviderCapturer) Wrap(provider string, client llm.Client) llm.Client {
+	return &capturingClient{capturer: c, provider: provider, client: client}
+}
+
+// sampled reports whether ctx's task is captured. Calls outside a task
+// (startup probes, scheduled maintenance) are not.
+func (c *ProviderCapturer) sampled(ctx context.Context) bool {
+	key := TaskIDFromContext(ctx)
+	if key == "" {
+		key = CorrelationIDFromContext(ctx)
+	}
+	if key == "" || c.percent <= 0 {
+		return false
+	}
+	if c.percent >= 100 {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum32()%10000) < c.percent*100
+}
+
+// scrub redacts secrets and PII from s, then caps it.
+func (c *ProviderCapturer) scrub(s string) string {
+	return PrepareCapturedContent(RedactPII(s), true, c.maxBytes)
+}
+
+func (c *ProviderCapturer) scrubMessage(m llm.ChatMessage) llm.ChatMessage {
+	m.Content = c.scrub(m.Content)
+	if len(m.ToolCalls) > 0 {
+		calls := make([]llm.ToolCall, len(m.ToolCalls))
+		for i, tc := range m.ToolCalls {
+			tc.Function.Arguments = c.scrub(tc.Function.Arguments)
+			calls[i] = tc
+		}
+		m.ToolCalls = calls
+	}
+	if len(m.Thinking) > 0 {
+		blocks := make([]llm.ThinkingBlock, len(m.Thinking))
+		for i, b := range m.Thinking {
+			b.Text = c.scrub(b.Text)
+			blocks[i] = b
+		}
+		m.Thinking = blocks
+	}
+	return m
+}
+
+// record writes one capture, appending to its task's file.
+func (c *ProviderCapturer) record(ctx context.Context, provider, model string, stream bool, start time.Time, req *llm.ChatRequest, resp *llm.ChatResponse, callErr error) {
+	rec := ProviderCapture{
+		Time:          start.UTC(),
+		TaskID:        TaskIDFromContext(ctx),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		Provider:      provider,
+		Model:         model,
+		Stream:        stream,
+		DurationMS:    time.Since(start).Milliseconds(),
+	}
+	scrubbed := *req
+	scrubbed.Messages = make([]llm.ChatMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		scrubbed.Messages[i] = c.scrubMessage(m)
+	}
+	rec.Request = &scrubbed
+	if resp != nil {
+		out := *resp
+		out.Message = c.scrubMessage(resp.Message)
+		rec.Response = &out
+	}
+	if callErr != nil {
+		rec.Error = c.scrub(callErr.Error())
+	}
+
+	key := rec.TaskID
+	if key == "" {
+		key = rec.CorrelationID
+	}
+	if err := c.append(key, rec); err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
+
+func (c *ProviderCapturer) append(key string, rec ProviderCapture) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding capture: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, err := os.OpenFile(filepath.Join(c.dir, captureFileName(key)), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening capture file: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// captureFileName is the file of a task's captures: its ID, with
+// characters unsafe in a file name replaced.
+func captureFileName(key string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, key)
+	if len(safe) > 128 {
+		safe = safe[:128]
+	}
+	return strings.TrimLeft(safe, ".") + ".jsonl"
+}
+
+// capturingClient captures a provider client's calls for sampled
+// tasks and passes every other call straight through.
+type capturingClient struct {
+	capturer *ProviderCapturer
+	provider string
+	client   llm.Client
+}
+
+func (cc *capturingClient) ModelID() string { return cc.client.ModelID() }
+
+// model is the model a request goes to: its own, or the client's.
+func (cc *capturingClient) model(req *llm.ChatRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return cc.client.ModelID()
+}
+
+func (cc *capturingClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	if !cc.capturer.sampled(ctx) {
+		return cc.client.Chat(ctx, req)
+	}
+	start := time.Now()
+	resp, err := cc.client.Chat(ctx, req)
+	cc.capturer.record(ctx, cc.provider, cc.model(req), false, start, req, resp, err)
+	return resp, err
+}
+
+// ChatStream forwards the deltas as they come and captures the
+// assembled response once the stream ends.
+func (cc *capturingClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	if !cc.capturer.sampled(ctx) {
+		return cc.client.ChatStream(ctx, req)
+	}
+	start := time.Now()
+	ch, err := cc.client.ChatStream(ctx, req)
+	if err != nil {
+		cc.capturer.record(ctx, cc.provider, cc.model(req), true, start, req, nil, err)
+		return nil, err
+	}
+
+	out := make(chan llm.StreamDelta, cap(ch))
+	go func() {
+		defer close(out)
+		resp := &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant}}
+		for d := range ch {
+			resp.Message.Content += d.Content
+			resp.Message.ToolCalls = append(resp.Message.ToolCalls, d.ToolCalls...)
+			resp.Message.Thinking = append(resp.Message.Thinking, d.Thinking...)
+			resp.Message.Citations = append(resp.Message.Citations, d.Citations...)
+			if d.FinishReason != "" {
+				resp.FinishReason = d.FinishReason
+			}
+			if d.Usage != nil {
+				resp.Usage = *d.Usage
+			}
+			// A consumer that gave up must not strand the provider's
+			// goroutine: keep draining without forwarding.
+			select {
+			case out <- d:
+			case <-ctx.Done():
+			}
+		}
+		cc.capturer.record(ctx, cc.provider, cc.model(req), true, start, req, resp, ctx.Err())
+	}()
+	return out, nil
+}
+
+// ProviderCaptureSummary describes one task's captures.
+type ProviderCaptureSummary struct {
+	TaskID string    `json:"task_id"`
+	Calls  int       `json:"calls"`
+	Errors int       `json:"errors"`
+	Models []string  `json:"models"`
+	Tokens int       `json:"tokens"`
+	First  time.Time `json:"first"`
+	Last   time.Time `json:"last"`
+}
+
+// ListProviderCaptures summarizes the captured tasks under dir, most
+// recent first. A missing dir holds no captures.
+func ListProviderCaptures(dir string) ([]ProviderCaptureSummary, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []ProviderCaptureSummary
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		recs, err := readProviderCaptures(filepath.Join(dir, e.Name()))
+		if err != nil || len(recs) == 0 {
+			continue
+		}
+		s := ProviderCaptureSummary{TaskID: recs[0].TaskID, First: recs[0].Time, Last: recs[0].Time}
+		if s.TaskID == "" {
+			s.TaskID = recs[0].CorrelationID
+		}
+		for _, r := range recs {
+			s.Calls++
+			if r.Error != "" {
+				s.Errors++
+			}
+			model := r.Provider + "/" + r.Model
+			if r.Model == "" {
+				model = r.Provider
+			}
+			if !slices.Contains(s.Models, model) {
+				s.Models = append(s.Models, model)
+			}
+			if r.Response != nil {
+				s.Tokens += r.Response.Usage.TotalTokens
+			}
+			if r.Time.Before(s.First) {
+				s.First = r.Time
+			}
+			if r.Time.After(s.Last) {
+				s.Last = r.Time
+			}
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Last.After(out[j].Last) })
+	return out, nil
+}
+
+// LoadProviderCaptures returns the captured calls of task taskID under
+// dir, in call order.
+func LoadProviderCaptures(dir, taskID string) ([]ProviderCapture, error) {
+	recs, err := readProviderCaptures(filepath.Join(dir, captureFileName(taskID)))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no captures for task %q", taskID)
+	}
+	return recs, err
+}
+
+func readProviderCaptures(path string) ([]ProviderCapture, error) {
+	f, err := os.Open(path) //nolint:gosec // path is under the capture dir
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var recs []ProviderCapture
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var rec ProviderCapture
+		if json.Unmarshal(scanner.Bytes(), &rec) != nil {
+			continue // a line cut short by a crash
+		}
+		recs = append(recs, rec)
+	}
+	return recs, scanner.Err()
+}
@@ -0,0 +1,145 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestProviderCapture_RecordsSampledTasks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "captures")
+	c, err := NewProviderCapturer(ProviderCaptureConfig{Dir: dir, SamplePercent: 100}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	client := c.Wrap("openai", &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		calls++
+		if calls == 2 {
+			return nil, errors.New("rate limited")
+		}
+		return &llm.ChatResponse{
+			Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "Mail jane@example.com", ToolCalls: []llm.ToolCall{
+				{ID: "c1", Function: llm.FunctionCall{Name: "lookup", Arguments: `{"ssn":"123-45-6789"}`}},
+			}},
+			Usage: llm.UsageInfo{TotalTokens: 30},
+		}, nil
+	}})
+
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "my key is sk-abcdefghijklmnopqrstuvwxyz, call 415-555-0100"}}}
+	ctx := WithTaskID(context.Background(), "task/1")
+	if _, err := client.Chat(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Chat(ctx, req); err == nil {
+		t.Fatal("want the provider error passed through")
+	}
+	if _, err := client.Chat(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Messages[0].Content != "my key is sk-abcdefghijklmnopqrstuvwxyz, call 415-555-0100" {
+		t.Error("capture redacted the caller's request")
+	}
+
+	recs, err := LoadProviderCaptures(dir, "task/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("captures = %d, want 2 (the untasked call is not captured)", len(recs))
+	}
+	r := recs[0]
+	if r.Provider != "openai" || r.Model != "test-model" || r.TaskID != "task/1" {
+		t.Errorf("capture = %+v", r)
+	}
+	if got := r.Request.Messages[0].Content; got != "my key is [REDACTED], call [REDACTED]" {
+		t.Errorf("request content = %q", got)
+	}
+	if r.Response.Message.Content != "Mail [REDACTED]" || r.Response.Message.ToolCalls[0].Function.Arguments != `{"ssn":"[REDACTED]"}` {
+		t.Errorf("response = %+v", r.Response.Message)
+	}
+	if recs[1].Error != "rate limited" || recs[1].Response != nil {
+		t.Errorf("failed call = %+v", recs[1])
+	}
+	if info, err := os.Stat(filepath.Join(dir, "task_1.jsonl")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("capture file: %v %v", info, err)
+	}
+
+	sums, err := ListProviderCaptures(dir)
+	if err != nil || len(sums) != 1 {
+		t.Fatalf("summaries = %+v, %v", sums, err)
+	}
+	if s := sums[0]; s.TaskID != "task/1" || s.Calls != 2 || s.Errors != 1 || s.Tokens != 30 || s.Models[0] != "openai/test-model" {
+		t.Errorf("summary = %+v", s)
+	}
+}
+
+func TestProviderCapture_Sampling(t *testing.T) {
+	c, err := NewProviderCapturer(ProviderCaptureConfig{Dir: t.TempDir(), SamplePercent: 25}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		ctx := WithTaskID(context.Background(), fmt.Sprintf("task-%d", i))
+		if c.sampled(ctx) {
+			sampled++
+			if !c.sampled(ctx) {
+				t.Fatal("sampling is not stable per task")
+			}
+		}
+	}
+	if sampled < 180 || sampled > 320 {
+		t.Errorf("sampled %d of 1000 tasks at 25%%", sampled)
+	}
+}
+
+func TestProviderCapture_Stream(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewProviderCapturer(ProviderCaptureConfig{Dir: dir, SamplePercent: 100}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := c.Wrap("anthropic", &streamingMockClient{deltas: []llm.StreamDelta{
+		{Content: "Hel"}, {Content: "lo"}, {FinishReason: "stop", Usage: &llm.UsageInfo{TotalTokens: 7}}, {Done: true},
+	}})
+	ch, err := client.ChatStream(WithTaskID(context.Background(), "t1"), &llm.ChatRequest{Model: "claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var text strings.Builder
+	for d := range ch {
+		text.WriteString(d.Content)
+	}
+	if text.String() != "Hello" {
+		t.Errorf("forwarded %q", text.String())
+	}
+	recs, err := LoadProviderCaptures(dir, "t1")
+	if err != nil || len(recs) != 1 {
+		t.Fatalf("captures = %+v, %v", recs, err)
+	}
+	if r := recs[0]; !r.Stream || r.Model != "claude" || r.Response.Message.Content != "Hello" || r.Response.Usage.TotalTokens != 7 {
+		t.Errorf("capture = %+v", r)
+	}
+}
+
+// streamingMockClient streams a fixed list of deltas.
+type streamingMockClient struct {
+	mockLLMClient
+	deltas []llm.StreamDelta
+}
+
+func (m *streamingMockClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	ch := make(chan llm.StreamDelta, len(m.deltas))
+	for _, d := range m.deltas {
+		ch <- d
+	}
+	close(ch)
+	return ch, nil
+}
@@ -0,0 +1,199 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// RedactedPlaceholder stands in for message content removed by a
+// redaction. The Compactor treats it as a tombstone: it is kept as-is
+// in summaries and never flushed to long-term memory.
+const RedactedPlaceholder = "[redacted]"
+
+// ErrNoSession is returned by EditSessionMessage and CompactSession when
+// the task has no persisted session (persistence disabled, or the
+// session expired).
+var ErrNoSession = errors.New("no persisted session")
+
+// MessageEdit identifies one conversation message and how to change it.
+type MessageEdit struct {
+	// Role and Content select the message. Content is the message's
+	// text as it appears in the A2A task history (see EditTaskHistory);
+	// every session message with the same role and content is edited.
+	Role    string
+	Content string
+	// Delete removes the message. Otherwise its content — or, when
+	// Text is set, every occurrence of Text in it — is replaced with
+	// RedactedPlaceholder.
+	Delete bool
+	// Text, when set, is also scrubbed from every other message, from
+	// tool-call arguments and from the compaction summary, since the
+	// agent may have echoed it.
+	Text string
+}
+
+// Summary outcomes reported in MessageEditResult.Summary.
+const (
+	SummaryUnchanged = "unchanged"
+	SummaryScrubbed  = "scrubbed"
+	SummaryDropped   = "dropped"
+)
+
+// MessageEditResult summarizes what EditSessionMessage changed.
+type MessageEditResult struct {
+	// Matched counts session messages selected by the edit's role and
+	// content. Zero means the message was already compacted away.
+	Matched int
+	// Scrubbed counts other messages Text was removed from.
+	Scrubbed int
+	// Summary is SummaryUnchanged, SummaryScrubbed, or SummaryDropped
+	// when the compaction summary may hold a paraphrase of a compacted
+	// message and had to be discarded.
+	Summary string
+}
+
+// EditSessionMessage applies edit to the persisted session for taskID
+// and saves it back to store.
+//
+// The compaction summary is edited too, so a later compaction cannot
+// resurrect the removed content: occurrences of the message and of
+// Text are scrubbed from it. When a whole message is redacted or
+// deleted but is no longer in the session, it was folded into the
+// summary — possibly paraphrased — so the summary is dropped instead.
+func EditSessionMessage(store SessionStore, taskID string, edit MessageEdit) (*MessageEditResult, error) {
+	if store == nil {
+		return nil, ErrNoSession
+	}
+	saved, err := store.Load(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("loading session: %w", err)
+	}
+	if saved == nil {
+		return nil, ErrNoSession
+	}
+
+	res := &MessageEditResult{Summary: SummaryUnchanged}
+	msgs := make([]llm.ChatMessage, 0, len(saved.Messages))
+	for _, msg := range saved.Messages {
+		if edit.Content != "" && msg.Role == edit.Role && msg.Content == edit.Content {
+			res.Matched++
+			switch {
+			case edit.Delete && len(msg.ToolCalls) > 0:
+				// Tool results reference the call; keep the call and
+				// drop only the text.
+				msg.Content = ""
+			case edit.Delete:
+				continue
+			case edit.Text != "":
+				msg.Content = strings.ReplaceAll(msg.Content, edit.Text, RedactedPlaceholder)
+			default:
+				msg.Content = RedactedPlaceholder
+			}
+		} else if edit.Text != "" && scrubMessage(&msg, edit.Text) {
+			res.Scrubbed++
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if saved.Summary != "" {
+		summary := saved.Summary
+		if edit.Text != "" {
+			summary = strings.ReplaceAll(summary, edit.Text, RedactedPlaceholder)
+		} else if edit.Content != "" {
+			summary = strings.ReplaceAll(summary, edit.Content, RedactedPlaceholder)
+		}
+		switch {
+		case res.Matched == 0 && edit.Text == "":
+			summary = ""
+			res.Summary = SummaryDropped
+		case summary != saved.Summary:
+			res.Summary = SummaryScrubbed
+		}
+		saved.Summary = summary
+	}
+
+	if res.Matched == 0 && res.Scrubbed == 0 && res.Summary == SummaryUnchanged {
+		return res, nil
+	}
+	saved.Messages = msgs
+	if err := store.Save(saved); err != nil {
+		return nil, fmt.Errorf("saving session: %w", err)
+	}
+	return res, nil
+}
+
+// scrubMessage replaces text in msg's content and tool-call arguments
+// and reports whether anything changed. Arguments are JSON, so text is
+// replaced in its JSON-escaped form as well.
+func scrubMessage(msg *llm.ChatMessage, text string) bool {
+	changed := false
+	if strings.Contains(msg.Content, text) {
+		msg.Content = strings.ReplaceAll(msg.Content, text, RedactedPlaceholder)
+		changed = true
+	}
+	if len(msg.ToolCalls) == 0 {
+		return changed
+	}
+	quoted, _ := json.Marshal(text)
+	escaped := strings.Trim(string(quoted), `"`)
+	calls := make([]llm.ToolCall, len(msg.ToolCalls))
+	for i, tc := range msg.ToolCalls {
+		args := strings.ReplaceAll(tc.Function.Arguments, text, RedactedPlaceholder)
+		args = strings.ReplaceAll(args, escaped, RedactedPlaceholder)
+		if args != tc.Function.Arguments {
+			tc.Function.Arguments = args
+			changed = true
+		}
+		calls[i] = tc
+	}
+	msg.ToolCalls = calls
+	return changed
+}
+
+// EditTaskHistory applies a redaction or deletion to history[index] and
+// returns the edited copy of history together with the MessageEdit that
+// selects the same message in the persisted session. When text is set
+// it must occur in the message; it is then also scrubbed from every
+// other message in history.
+func EditTaskHistory(history []a2a.Message, index int, text string, del bool) ([]a2a.Message, MessageEdit, error) {
+	if index < 0 || index >= len(history) {
+		return nil, MessageEdit{}, fmt.Errorf("message index %d out of range (history has %d messages)", index, len(history))
+	}
+	target := a2aMessageToLLM(history[index])
+	if text != "" && !strings.Contains(target.Content, text) {
+		return nil, MessageEdit{}, fmt.Errorf("text not found in message %d", index)
+	}
+	edit := MessageEdit{Role: target.Role, Content: target.Content, Delete: del, Text: text}
+
+	edited := make([]a2a.Message, 0, len(history))
+	for i, msg := range history {
+		switch {
+		case i == index && del:
+			continue
+		case i == index && text == "":
+			msg.Parts = []a2a.Part{a2a.NewTextPart(RedactedPlaceholder)}
+		case text != "":
+			msg.Parts = ScrubParts(msg.Parts, text)
+		}
+		edited = append(edited, msg)
+	}
+	return edited, edit, nil
+}
+
+// ScrubParts returns a copy of parts with every occurrence of text in
+// a text part replaced by RedactedPlaceholder.
+func ScrubParts(parts []a2a.Part, text string) []a2a.Part {
+	out := make([]a2a.Part, len(parts))
+	for i, p := range parts {
+		if p.Kind == a2a.PartKindText {
+			p.Text = strings.ReplaceAll(p.Text, text, RedactedPlaceholder)
+		}
+		out[i] = p
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func newRedactStore(t *testing.T, data *SessionData) SessionStore {
+	t.Helper()
+	store, err := NewMemoryStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(data); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestEditSessionMessage_RedactText(t *testing.T) {
+	store := newRedactStore(t, &SessionData{TaskID: "t1", Summary: "User shared password hunter2.", Messages: []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "log in with password hunter2"},
+		{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+			{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "login", Arguments: `{"password":"hunter2"}`}},
+		}},
+		{Role: llm.RoleTool, ToolCallID: "c1", Content: "ok"},
+		{Role: llm.RoleAssistant, Content: "Logged in."},
+	}})
+
+	res, err := EditSessionMessage(store, "t1", MessageEdit{
+		Role: llm.RoleUser, Content: "log in with password hunter2", Text: "hunter2",
+	})
+	if err != nil {
+		t.Fatalf("EditSessionMessage: %v", err)
+	}
+	if res.Matched != 1 || res.Scrubbed != 1 || res.Summary != SummaryScrubbed {
+		t.Errorf("result = %+v", res)
+	}
+
+	saved, err := store.Load("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.Messages[0].Content; got != "log in with password [redacted]" {
+		t.Errorf("user message = %q", got)
+	}
+	if got := saved.Messages[1].ToolCalls[0].Function.Arguments; strings.Contains(got, "hunter2") {
+		t.Errorf("tool arguments still hold the text: %s", got)
+	}
+	if strings.Contains(saved.Summary, "hunter2") {
+		t.Errorf("summary still holds the text: %q", saved.Summary)
+	}
+}
+
+func TestEditSessionMessage_DeleteCompactedDropsSummary(t *testing.T) {
+	store := newRedactStore(t, &SessionData{TaskID: "t1", Summary: "User asked about their diagnosis.", Messages: []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "first"},
+		{Role: llm.RoleAssistant, Content: "done"},
+	}})
+
+	res, err := EditSessionMessage(store, "t1", MessageEdit{Role: llm.RoleUser, Content: "my diagnosis is ...", Delete: true})
+	if err != nil {
+		t.Fatalf("EditSessionMessage: %v", err)
+	}
+	if res.Matched != 0 || res.Summary != SummaryDropped {
+		t.Errorf("result = %+v, want summary dropped", res)
+	}
+	saved, err := store.Load("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Summary != "" || len(saved.Messages) != 2 {
+		t.Errorf("session = %+v", saved)
+	}
+}
+
+func TestEditSessionMessage_Delete(t *testing.T) {
+	store := newRedactStore(t, &SessionData{TaskID: "t1", Messages: []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "first"},
+		{Role: llm.RoleUser, Content: "oops, wrong window"},
+		{Role: llm.RoleAssistant, Content: "done"},
+	}})
+	res, err := EditSessionMessage(store, "t1", MessageEdit{Role: llm.RoleUser, Content: "oops, wrong window", Delete: true})
+	if err != nil {
+		t.Fatalf("EditSessionMessage: %v", err)
+	}
+	if res.Matched != 1 || res.Summary != SummaryUnchanged {
+		t.Errorf("result = %+v", res)
+	}
+	saved, err := store.Load("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Messages) != 2 || saved.Messages[1].Content != "done" {
+		t.Errorf("messages = %+v", saved.Messages)
+	}
+}
+
+func TestEditSessionMessage_NoSession(t *testing.T) {
+	if _, err := EditSessionMessage(nil, "t1", MessageEdit{}); !errors.Is(err, ErrNoSession) {
+		t.Errorf("err = %v, want ErrNoSession", err)
+	}
+}
+
+func TestEditTaskHistory(t *testing.T) {
+	history := []a2a.Message{
+		{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("token is sk-123")}},
+		{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Saved sk-123.")}},
+	}
+
+	edited, edit, err := EditTaskHistory(history, 0, "sk-123", false)
+	if err != nil {
+		t.Fatalf("EditTaskHistory: %v", err)
+	}
+	if edit.Role != llm.RoleUser || edit.Content != "token is sk-123" || edit.Text != "sk-123" {
+		t.Errorf("edit = %+v", edit)
+	}
+	if edited[0].Parts[0].Text != "token is [redacted]" || edited[1].Parts[0].Text != "Saved [redacted]." {
+		t.Errorf("edited = %+v", edited)
+	}
+	if history[0].Parts[0].Text != "token is sk-123" {
+		t.Error("EditTaskHistory modified its input")
+	}
+
+	edited, _, err = EditTaskHistory(history, 1, "", true)
+	if err != nil {
+		t.Fatalf("EditTaskHistory: %v", err)
+	}
+	if len(edited) != 1 {
+		t.Errorf("edited = %+v, want agent message deleted", edited)
+	}
+
+	if _, _, err := EditTaskHistory(history, 2, "", false); err == nil {
+		t.Error("out-of-range index accepted")
+	}
+	if _, _, err := EditTaskHistory(history, 0, "absent", false); err == nil {
+		t.Error("text missing from the message accepted")
+	}
+}
+
+func TestCompactor_SkipsRedactedInLongTermMemory(t *testing.T) {
+	flusher := &mockMemoryFlusher{}
+	c := NewCompactor(CompactorConfig{MemoryFlusher: flusher})
+	c.flushToLongTermMemory("t1", []llm.ChatMessage{
+		{Role: llm.RoleAssistant, Content: RedactedPlaceholder},
+		{Role: llm.RoleAssistant, Content: "Deployed v2."},
+	})
+	if flusher.observation != "- [decision] Deployed v2.\n" {
+		t.Errorf("flushed = %q", flusher.observation)
+	}
+}
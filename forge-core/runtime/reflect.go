@@ -0,0 +1,176 @@
+package runtime
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// ReflectionConfig turns on a self-critique pass over a task's final
+// answer: a reviewer model checks the draft against the request and the
+// agent's instructions, and when it finds problems the agent revises the
+// answer once with the review in hand.
+type ReflectionConfig struct {
+	// Client is the reviewer, typically a cheaper model. Nil uses the
+	// executor's client.
+	Client llm.Client
+	// Always reviews every final answer. Otherwise only runs that
+	// called one of Tools are reviewed.
+	Always bool
+	Tools  []string
+}
+
+// MetadataKeyReflection is the final message metadata key the outcome of
+// a reflection pass is recorded under, as a ReflectionResult.
+const MetadataKeyReflection = "reflection"
+
+// ReflectionResult is what a reflection pass did to an answer.
+type ReflectionResult struct {
+	// Revised is true when the reviewer found problems and the answer
+	// was rewritten.
+	Revised bool `json:"revised"`
+	// Issues is the reviewer's critique; empty when it approved the draft.
+	Issues string `json:"issues,omitempty"`
+}
+
+// reflectionPrompt is the reviewer's system instruction. It must answer
+// with a bare OK or a list of problems, never a rewrite.
+const reflectionPrompt = `You review an AI agent's draft answer before it is sent to the user. Check it against the user's request and the agent's instructions: does it answer what was asked, follow the required format and constraints, and avoid claims the conversation does not support? Reply with exactly OK if the draft needs no changes. Otherwise list the problems as short bullets, most important first. Do not rewrite the answer.`
+
+// Caps on what the reviewer is shown, so a long system prompt or report
+// doesn't turn the review into the most expensive call of the task.
+const (
+	reflectionInstructionsMax = 8_000
+	reflectionDraftMax        = 20_000
+)
+
+// applies reports whether a run that called toolsUsed gets a review.
+func (c *ReflectionConfig) applies(toolsUsed []string) bool {
+	if c == nil {
+		return false
+	}
+	if c.Always {
+		return true
+	}
+	for _, t := range toolsUsed {
+		if slices.Contains(c.Tools, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// reflect runs the reflection pass over draft, the final answer already
+// appended to mem. When the reviewer finds problems the executor's model
+// revises the answer once, and the revision replaces the draft in mem.
+// Every failure along the way keeps the draft: reflection never fails a
+// task. Returns the answer to send and the outcome, nil when no review
+// ran.
+func (e *LLMExecutor) reflect(ctx context.Context, mem *Memory, request string, draft llm.ChatMessage, toolsUsed []string, toolDefs []llm.ToolDefinition) (llm.ChatMessage, *ReflectionResult) {
+	if !e.reflection.applies(toolsUsed) || strings.TrimSpace(draft.Content) == "" {
+		return draft, nil
+	}
+	if emit := ProgressEmitterFromContext(ctx); emit != nil {
+		emit(ProgressEvent{Phase: ProgressReflection, Message: "Reviewing the answer..."})
+	}
+
+	reviewer := e.reflection.Client
+	if reviewer == nil {
+		reviewer = e.client
+	}
+	var sb strings.Builder
+	sb.WriteString("## Agent instructions\n")
+	sb.WriteString(truncateRunes(e.basePrompt(ctx), reflectionInstructionsMax))
+	sb.WriteString("\n\n## User request\n")
+	sb.WriteString(request)
+	sb.WriteString("\n\n## Draft answer\n")
+	sb.WriteString(truncateRunes(draft.Content, reflectionDraftMax))
+	review, err := reviewer.Chat(ctx, &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: reflectionPrompt},
+			{Role: llm.RoleUser, Content: sb.String()},
+		},
+	})
+	if err != nil {
+		e.logger.Warn("reflection review failed, sending the draft", map[string]any{
+			"task_id": TaskIDFromContext(ctx), "error": err.Error(),
+		})
+		return draft, nil
+	}
+	issues := strings.TrimSpace(review.Message.Content)
+	if issues == "" || strings.EqualFold(strings.Trim(issues, " .*`\n"), "OK") {
+		return draft, &ReflectionResult{}
+	}
+
+	// The revision sees the whole conversation, so it can use what the
+	// tools returned. Tool definitions ride along because some providers
+	// reject a history of tool calls without them; a revision that calls
+	// a tool instead of answering is discarded.
+	messages := append(mem.Messages(), llm.ChatMessage{
+		Role: llm.RoleUser,
+		Content: "A reviewer found these problems with your answer:\n\n" + issues +
+			"\n\nRevise your answer once to fix them. Reply with the complete revised answer only, without mentioning the review.",
+	})
+	start := time.Now()
+	resp, err := e.client.Chat(ctx, &llm.ChatRequest{Messages: messages, Tools: toolDefs})
+	if err != nil || len(resp.Message.ToolCalls) > 0 || strings.TrimSpace(resp.Message.Content) == "" {
+		fields := map[string]any{"task_id": TaskIDFromContext(ctx)}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		e.logger.Warn("reflection revision unusable, sending the draft", fields)
+		return draft, &ReflectionResult{Issues: issues}
+	}
+	e.recordUsage(mem, resp)
+	// The revision is a reply like any other: output guardrails and the
+	// llm_call audit see it. A hook that blocks it keeps the draft.
+	provider, model := e.model()
+	if err := e.hooks.Fire(ctx, AfterLLMCall, &HookContext{
+		Messages:        messages,
+		Response:        resp,
+		TaskID:          TaskIDFromContext(ctx),
+		CorrelationID:   CorrelationIDFromContext(ctx),
+		LLMCallDuration: time.Since(start),
+		Provider:        provider,
+		Model:           model,
+	}); err != nil {
+		e.logger.Warn("reflection revision blocked, sending the draft", map[string]any{
+			"task_id": TaskIDFromContext(ctx), "error": err.Error(),
+		})
+		return draft, &ReflectionResult{Issues: issues}
+	}
+
+	e.logger.Info("reflection revised the answer", map[string]any{"task_id": TaskIDFromContext(ctx)})
+	mem.replaceLastAssistant(resp.Message.Content)
+	return resp.Message, &ReflectionResult{Revised: true, Issues: issues}
+}
+
+// setReflection records a reflection pass's outcome on the final message.
+func setReflection(out *a2a.Message, res *ReflectionResult) {
+	if res == nil {
+		return
+	}
+	if out.Metadata == nil {
+		out.Metadata = map[string]any{}
+	}
+	out.Metadata[MetadataKeyReflection] = res
+}
+
+// truncateRunes cuts s to at most limit bytes without splitting a UTF-8
+// sequence, marking the cut.
+func truncateRunes(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit
+	for cut > 0 && !utf8RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "\n[...]"
+}
+
+func utf8RuneStart(b byte) bool { return b&0xC0 != 0x80 }
@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// reflectionClients returns an agent model that answers "draft" and then
+// "revised", and a reviewer that replies review, counting its calls.
+func reflectionClients(review string, reviews *int) (agent, reviewer *mockLLMClient) {
+	calls := 0
+	agent = &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		calls++
+		if calls == 1 {
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "draft"}}, nil
+		}
+		if last := req.Messages[len(req.Messages)-1]; !strings.Contains(last.Content, "missing the total") {
+			return nil, errors.New("revision request lacks the review")
+		}
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "revised"}}, nil
+	}}
+	reviewer = &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		*reviews++
+		if !strings.Contains(req.Messages[1].Content, "sum the invoices") || !strings.Contains(req.Messages[1].Content, "draft") {
+			return nil, errors.New("review request lacks the task or the draft")
+		}
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: review}}, nil
+	}}
+	return agent, reviewer
+}
+
+func runReflection(t *testing.T, cfg LLMExecutorConfig) *a2a.Message {
+	t.Helper()
+	out, err := NewLLMExecutor(cfg).Execute(context.Background(), &a2a.Task{ID: "t1"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("sum the invoices")},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return out
+}
+
+func TestReflectionRevisesAnswer(t *testing.T) {
+	reviews := 0
+	agent, reviewer := reflectionClients("- missing the total", &reviews)
+	out := runReflection(t, LLMExecutorConfig{
+		Client:     agent,
+		Reflection: &ReflectionConfig{Client: reviewer, Always: true},
+	})
+	if got := ExtractText(out); got != "revised" {
+		t.Errorf("answer = %q, want the revision", got)
+	}
+	res, _ := out.Metadata[MetadataKeyReflection].(*ReflectionResult)
+	if reviews != 1 || res == nil || !res.Revised || res.Issues != "- missing the total" {
+		t.Errorf("reviews = %d, result = %+v", reviews, res)
+	}
+}
+
+func TestReflectionApprovesDraft(t *testing.T) {
+	reviews := 0
+	agent, reviewer := reflectionClients("OK.", &reviews)
+	out := runReflection(t, LLMExecutorConfig{
+		Client:     agent,
+		Reflection: &ReflectionConfig{Client: reviewer, Always: true},
+	})
+	res, _ := out.Metadata[MetadataKeyReflection].(*ReflectionResult)
+	if got := ExtractText(out); got != "draft" || res == nil || res.Revised {
+		t.Errorf("answer = %q, result = %+v; want the approved draft", got, res)
+	}
+}
+
+func TestReflectionOnlyForSkillTools(t *testing.T) {
+	reviews := 0
+	agent, reviewer := reflectionClients("- missing the total", &reviews)
+	out := runReflection(t, LLMExecutorConfig{
+		Client:     agent,
+		Reflection: &ReflectionConfig{Client: reviewer, Tools: []string{"invoice_report"}},
+	})
+	if reviews != 0 || out.Metadata[MetadataKeyReflection] != nil {
+		t.Errorf("reviewed a run that never called invoice_report")
+	}
+}
+
+func TestReflectionRevisionBlockedKeepsDraft(t *testing.T) {
+	reviews := 0
+	agent, reviewer := reflectionClients("- missing the total", &reviews)
+	hooks := NewHookRegistry()
+	hooks.Register(AfterLLMCall, func(ctx context.Context, hc *HookContext) error {
+		if hc.Response.Message.Content == "revised" {
+			return errors.New("blocked")
+		}
+		return nil
+	})
+	out := runReflection(t, LLMExecutorConfig{
+		Client:     agent,
+		Hooks:      hooks,
+		Reflection: &ReflectionConfig{Client: reviewer, Always: true},
+	})
+	res, _ := out.Metadata[MetadataKeyReflection].(*ReflectionResult)
+	if got := ExtractText(out); got != "draft" || res == nil || res.Revised {
+		t.Errorf("answer = %q, result = %+v; want the draft kept", got, res)
+	}
+}
@@ -290,6 +290,7 @@ func cloneSession(d *SessionData) *SessionData {
 		return nil
 	}
 	out := *d
+	out.Usage = d.Usage.Clone()
 	if d.Messages != nil {
 		out.Messages = make([]llm.ChatMessage, len(d.Messages))
 		for i, m := range d.Messages {
@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// RuntimeContext is the block of runtime facts appended to the system
+// prompt of every task (see LLMExecutorConfig.RuntimeContext). It is
+// rendered when the task starts, so the time is current.
+type RuntimeContext struct {
+	Location *time.Location    // zone the time is given in; nil uses time.Local
+	AgentID  string            // agent ID, omitted when empty
+	Version  string            // agent version, omitted when empty
+	Channels []string          // channel adapters the agent runs with
+	Facts    map[string]string // operator facts, listed by key
+	Now      func() time.Time  // clock; nil uses time.Now
+}
+
+// runtimeContextTimeLayout gives the date, weekday, zone abbreviation
+// and UTC offset, e.g. "2026-10-16 14:03 (Friday) CEST, UTC+02:00".
+const runtimeContextTimeLayout = "2006-01-02 15:04 (Monday) MST, UTC-07:00"
+
+// Render returns the block for a task handling msg. The user's language
+// is listed when the channel router detected it.
+func (rc *RuntimeContext) Render(msg *a2a.Message) string {
+	now := time.Now
+	if rc.Now != nil {
+		now = rc.Now
+	}
+	loc := rc.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t := now().In(loc)
+
+	var sb strings.Builder
+	sb.WriteString("## Runtime Context\n\n")
+	sb.WriteString("Facts about this deployment, current as of this task. Use them instead of guessing; resolve relative dates such as \"tomorrow\" against the current time.\n\n")
+	fmt.Fprintf(&sb, "- Current time: %s", t.Format(runtimeContextTimeLayout))
+	if name := loc.String(); name != "Local" && name != "UTC" {
+		fmt.Fprintf(&sb, " (%s)", name)
+	}
+	sb.WriteString("\n")
+	switch {
+	case rc.AgentID != "" && rc.Version != "":
+		fmt.Fprintf(&sb, "- Agent: %s, version %s\n", rc.AgentID, rc.Version)
+	case rc.AgentID != "":
+		fmt.Fprintf(&sb, "- Agent: %s\n", rc.AgentID)
+	}
+	if len(rc.Channels) > 0 {
+		fmt.Fprintf(&sb, "- Channels: %s\n", strings.Join(rc.Channels, ", "))
+	}
+	if msg != nil {
+		if lang, _ := msg.Metadata[a2a.MetadataKeyLanguage].(string); lang != "" {
+			fmt.Fprintf(&sb, "- User's language: %s\n", lang)
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(rc.Facts)) {
+		fmt.Fprintf(&sb, "- %s: %s\n", k, rc.Facts[k])
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// taskSystemPrompt is the system prompt of a task handling msg: the
+// configured one, followed by the runtime context when there is one.
+func (e *LLMExecutor) taskSystemPrompt(ctx context.Context, msg *a2a.Message) string {
+	prompt := e.basePrompt(ctx)
+	if e.runtimeContext == nil {
+		return prompt
+	}
+	block := e.runtimeContext.Render(msg)
+	if prompt == "" {
+		return block
+	}
+	return prompt + "\n\n" + block
+}
@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestRuntimeContextRender(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	rc := &RuntimeContext{
+		Location: berlin,
+		AgentID:  "support-bot",
+		Version:  "1.2.0",
+		Channels: []string{"slack", "telegram"},
+		Facts:    map[string]string{"support_hours": "9-17", "company": "Acme"},
+		Now:      func() time.Time { return time.Date(2026, 10, 16, 12, 3, 0, 0, time.UTC) },
+	}
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Metadata: map[string]any{a2a.MetadataKeyLanguage: "es"}}
+	got := rc.Render(msg)
+	for _, want := range []string{
+		"- Current time: 2026-10-16 14:03 (Friday) CEST, UTC+02:00 (Europe/Berlin)\n",
+		"- Agent: support-bot, version 1.2.0\n",
+		"- Channels: slack, telegram\n",
+		"- User's language: es\n",
+		"- company: Acme\n- support_hours: 9-17",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("block lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains((&RuntimeContext{}).Render(nil), "Agent:") {
+		t.Error("empty agent ID listed")
+	}
+}
+
+func TestRuntimeContextInSystemPrompt(t *testing.T) {
+	var prompts []string
+	client := &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+		prompts = append(prompts, req.Messages[0].Content)
+		return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "ok"}}, nil
+	}}
+	day := 16
+	exec := NewLLMExecutor(LLMExecutorConfig{
+		Client:       client,
+		SystemPrompt: "You are helpful.",
+		RuntimeContext: &RuntimeContext{Location: time.UTC, Now: func() time.Time {
+			return time.Date(2026, 10, day, 9, 0, 0, 0, time.UTC)
+		}},
+	})
+	for range 2 {
+		if _, err := exec.Execute(context.Background(), &a2a.Task{ID: "t1"}, &a2a.Message{
+			Role:  a2a.MessageRoleUser,
+			Parts: []a2a.Part{a2a.NewTextPart("what day is it?")},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		day++
+	}
+	if !strings.HasPrefix(prompts[0], "You are helpful.\n\n## Runtime Context") || !strings.Contains(prompts[0], "2026-10-16 09:00 (Friday)") {
+		t.Errorf("system prompt = %q", prompts[0])
+	}
+	if !strings.Contains(prompts[1], "2026-10-17 09:00 (Saturday)") {
+		t.Errorf("second task's prompt = %q, want the time refreshed", prompts[1])
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/agentspec"
 )
 
@@ -129,7 +130,7 @@ func (s *SkillGuardrailEngine) CheckCommandInput(toolName, toolInput string) err
 				msg = "command blocked by skill guardrail"
 			}
 			if s.enforce {
-				return fmt.Errorf("skill guardrail: %s", msg)
+				return WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("skill guardrail: %s", msg))
 			}
 			s.logger.Warn("skill guardrail command match", map[string]any{
 				"pattern": f.re.String(),
@@ -137,7 +138,7 @@ func (s *SkillGuardrailEngine) CheckCommandInput(toolName, toolInput string) err
 				"target":  matchTarget,
 				"message": msg,
 			})
-			return fmt.Errorf("skill guardrail: %s", msg)
+			return WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("skill guardrail: %s", msg))
 		}
 	}
 
@@ -159,7 +160,7 @@ func (s *SkillGuardrailEngine) CheckCommandOutput(toolName, toolOutput string) (
 	result := applyOutputPolicy(toolOutput, s.denyOutput, s.logger, toolName)
 	switch result.Decision {
 	case DecisionDeny:
-		return "", fmt.Errorf("tool output blocked by skill guardrail")
+		return "", WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("tool output blocked by skill guardrail"))
 	case DecisionModify:
 		return result.Modified, nil
 	default:
@@ -288,7 +289,7 @@ func (s *SkillGuardrailEngine) CheckUserInput(text string) error {
 				"pattern": f.re.String(),
 				"message": msg,
 			})
-			return fmt.Errorf("skill guardrail: %s", msg)
+			return WithErrorCode(a2a.ErrorGuardrailViolation, fmt.Errorf("skill guardrail: %s", msg))
 		}
 	}
 
@@ -0,0 +1,201 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Stream guard defaults, used when StreamGuardConfig leaves a field
+// zero.
+const (
+	DefaultStreamGuardWindow   = 256
+	DefaultStreamGuardHoldback = 64
+	DefaultStreamGuardNotice   = "[Response withheld: it violated a guardrail policy.]"
+)
+
+// StreamGuardConfig configures a StreamGuard.
+type StreamGuardConfig struct {
+	// Window is how many bytes of streamed text are buffered before
+	// they are checked together. Larger windows give the guardrail more
+	// context per check and cost fewer checks; smaller ones release
+	// text sooner.
+	Window int
+	// Holdback is how many bytes of a clean window are kept back and
+	// checked again with the next one, so a pattern split across two
+	// windows (an SSN, a key) is seen whole before any of it is
+	// released. Must be smaller than Window.
+	Holdback int
+	// Notice replaces the streamed output when a check blocks it.
+	Notice string
+}
+
+// StreamGuard runs GuardrailChecker.CheckStream incrementally over a
+// streamed response. Text is released only after the window holding
+// it passed the StreamGate; a masked window is released masked, and a
+// blocked one stops the stream. One guard serves one stream and is not
+// safe for concurrent use.
+type StreamGuard struct {
+	checker  GuardrailChecker
+	window   int
+	holdback int
+	notice   string
+
+	pending string
+	blocked error
+}
+
+// NewStreamGuard returns a guard checking a stream with checker.
+func NewStreamGuard(checker GuardrailChecker, cfg StreamGuardConfig) *StreamGuard {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultStreamGuardWindow
+	}
+	if cfg.Holdback <= 0 || cfg.Holdback >= cfg.Window {
+		cfg.Holdback = min(DefaultStreamGuardHoldback, cfg.Window/4)
+	}
+	if cfg.Notice == "" {
+		cfg.Notice = DefaultStreamGuardNotice
+	}
+	return &StreamGuard{checker: checker, window: cfg.Window, holdback: cfg.Holdback, notice: cfg.Notice}
+}
+
+// Notice is the text that replaces a blocked stream's output.
+func (g *StreamGuard) Notice() string { return g.notice }
+
+// Write buffers chunk and returns the text now safe to release, often
+// none. Once a check blocks the stream, Write returns that error and
+// every later call does too.
+func (g *StreamGuard) Write(ctx context.Context, chunk string) (string, error) {
+	if g.blocked != nil {
+		return "", g.blocked
+	}
+	g.pending += chunk
+	if len(g.pending) < g.window {
+		return "", nil
+	}
+	checked, err := g.check(ctx)
+	if err != nil {
+		return "", err
+	}
+	if checked != g.pending {
+		// Masked. The window goes out as the checker rewrote it, less
+		// the raw tail when the mask left that alone: a second match
+		// split across the windows is then still seen whole.
+		tail := g.pending[holdbackCut(g.pending, g.holdback):]
+		g.pending = ""
+		if strings.HasSuffix(checked, tail) {
+			g.pending = tail
+			checked = checked[:len(checked)-len(tail)]
+		}
+		return checked, nil
+	}
+	cut := holdbackCut(g.pending, g.holdback)
+	out := g.pending[:cut]
+	g.pending = g.pending[cut:]
+	return out, nil
+}
+
+// Flush checks the text still buffered, at the end of the stream, and
+// returns it for release.
+func (g *StreamGuard) Flush(ctx context.Context) (string, error) {
+	if g.blocked != nil {
+		return "", g.blocked
+	}
+	if g.pending == "" {
+		return "", nil
+	}
+	checked, err := g.check(ctx)
+	g.pending = ""
+	return checked, err
+}
+
+func (g *StreamGuard) check(ctx context.Context) (string, error) {
+	checked, err := g.checker.CheckStream(ctx, g.pending)
+	if err != nil {
+		g.blocked = err
+		g.pending = ""
+		return "", err
+	}
+	return checked, nil
+}
+
+// holdbackCut is where a clean window is split: the text before it is
+// released, the rest — at least holdback bytes — kept for the next
+// check. The cut falls on a word boundary when the released part has
+// one, and always on a rune boundary.
+func holdbackCut(s string, holdback int) int {
+	cut := len(s) - holdback
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	if i := strings.LastIndexFunc(s[:cut], unicode.IsSpace); i >= 0 {
+		return i + 1
+	}
+	return cut
+}
+
+// GuardStream passes a provider stream through g. Content is released
+// as its windows pass, and the rest flushed with the finishing delta;
+// tool calls, thinking and usage are forwarded as they come. When a
+// check blocks the stream, one delta with Replace set carries g's notice and
+// finish reason "content_filter", and the rest of the provider stream
+// is drained unseen.
+func GuardStream(ctx context.Context, g *StreamGuard, in <-chan llm.StreamDelta) <-chan llm.StreamDelta {
+	out := make(chan llm.StreamDelta, cap(in))
+	go func() {
+		defer close(out)
+		send := func(d llm.StreamDelta) bool {
+			select {
+			case out <- d:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		block := func() {
+			send(llm.StreamDelta{Content: g.Notice(), Replace: true, FinishReason: "content_filter", Done: true})
+			for range in { //nolint:revive // drain so the provider goroutine can exit
+			}
+		}
+
+		for d := range in {
+			released, err := g.Write(ctx, d.Content)
+			if err != nil {
+				block()
+				return
+			}
+			if d.FinishReason != "" || d.Done {
+				rest, err := g.Flush(ctx)
+				if err != nil {
+					block()
+					return
+				}
+				released += rest
+			}
+			d.Content = released
+			if d.Content == "" && isEmptyDelta(d) {
+				continue
+			}
+			if !send(d) {
+				for range in { //nolint:revive // drain
+				}
+				return
+			}
+		}
+		if rest, err := g.Flush(ctx); err != nil {
+			block()
+		} else if rest != "" {
+			send(llm.StreamDelta{Content: rest})
+		}
+	}()
+	return out
+}
+
+// isEmptyDelta reports whether d carries nothing besides content.
+func isEmptyDelta(d llm.StreamDelta) bool {
+	return len(d.ToolCalls) == 0 && len(d.Thinking) == 0 && len(d.Citations) == 0 &&
+		d.FinishReason == "" && !d.Done && d.Usage == nil
+}
@@ -0,0 +1,124 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// streamChecker masks SSNs and blocks "forbidden" in stream chunks.
+type streamChecker struct {
+	NoopGuardrailChecker
+	checked []string
+}
+
+func (c *streamChecker) CheckStream(_ context.Context, chunk string) (string, error) {
+	c.checked = append(c.checked, chunk)
+	if strings.Contains(chunk, "forbidden") {
+		return "", errors.New("stream blocked: forbidden")
+	}
+	return strings.ReplaceAll(chunk, "123-45-6789", "[SSN]"), nil
+}
+
+func TestStreamGuard_SplitPatternIsMasked(t *testing.T) {
+	checker := &streamChecker{}
+	g := NewStreamGuard(checker, StreamGuardConfig{Window: 24, Holdback: 12})
+
+	var out strings.Builder
+	for _, chunk := range []string{"The customer's SSN is 123-", "45-6789 and the ", "account is active."} {
+		released, err := g.Write(context.Background(), chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.WriteString(released)
+	}
+	rest, err := g.Flush(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.WriteString(rest)
+
+	if got, want := out.String(), "The customer's SSN is [SSN] and the account is active."; got != want {
+		t.Errorf("released %q, want %q", got, want)
+	}
+	if len(checker.checked) < 2 {
+		t.Errorf("checked %q, want one check per window", checker.checked)
+	}
+}
+
+func TestStreamGuard_SecondMatchAfterMask(t *testing.T) {
+	g := NewStreamGuard(&streamChecker{}, StreamGuardConfig{Window: 32, Holdback: 12})
+	var out strings.Builder
+	for _, chunk := range []string{"SSNs: 123-45-6789 and then 123-4", "5-6789 on file."} {
+		released, err := g.Write(context.Background(), chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.WriteString(released)
+	}
+	rest, _ := g.Flush(context.Background())
+	out.WriteString(rest)
+	if got, want := out.String(), "SSNs: [SSN] and then [SSN] on file."; got != want {
+		t.Errorf("released %q, want %q", got, want)
+	}
+}
+
+func TestStreamGuard_HoldsBackUntilWindowFills(t *testing.T) {
+	g := NewStreamGuard(&streamChecker{}, StreamGuardConfig{Window: 64})
+	if released, err := g.Write(context.Background(), "short"); err != nil || released != "" {
+		t.Errorf("Write = (%q, %v), want the chunk held", released, err)
+	}
+	if rest, err := g.Flush(context.Background()); err != nil || rest != "short" {
+		t.Errorf("Flush = (%q, %v)", rest, err)
+	}
+}
+
+func TestGuardStream_BlocksMidStream(t *testing.T) {
+	in := make(chan llm.StreamDelta, 8)
+	for _, c := range []string{"Here is a long harmless opening sentence. ", "Then something forbidden ", "follows here.", ""} {
+		in <- llm.StreamDelta{Content: c}
+	}
+	in <- llm.StreamDelta{FinishReason: "stop", Done: true}
+	close(in)
+
+	g := NewStreamGuard(&streamChecker{}, StreamGuardConfig{Window: 32, Holdback: 8})
+	var deltas []llm.StreamDelta
+	for d := range GuardStream(context.Background(), g, in) {
+		deltas = append(deltas, d)
+	}
+
+	if len(deltas) < 2 {
+		t.Fatalf("deltas = %+v, want released text then the notice", deltas)
+	}
+	if first := deltas[0]; first.Replace || !strings.HasPrefix(first.Content, "Here is a long harmless") {
+		t.Errorf("first delta = %+v", first)
+	}
+	last := deltas[len(deltas)-1]
+	if !last.Replace || last.Content != DefaultStreamGuardNotice || last.FinishReason != "content_filter" || !last.Done {
+		t.Errorf("last delta = %+v", last)
+	}
+	for _, d := range deltas {
+		if strings.Contains(d.Content, "forbidden") {
+			t.Errorf("blocked text was released: %+v", d)
+		}
+	}
+}
+
+func TestGuardStream_ForwardsOtherFields(t *testing.T) {
+	in := make(chan llm.StreamDelta, 4)
+	in <- llm.StreamDelta{Content: "Calling a tool."}
+	in <- llm.StreamDelta{ToolCalls: []llm.ToolCall{{ID: "c1"}}}
+	in <- llm.StreamDelta{FinishReason: "tool_calls", Usage: &llm.UsageInfo{TotalTokens: 9}, Done: true}
+	close(in)
+
+	var deltas []llm.StreamDelta
+	for d := range GuardStream(context.Background(), NewStreamGuard(&streamChecker{}, StreamGuardConfig{}), in) {
+		deltas = append(deltas, d)
+	}
+	if len(deltas) != 2 || len(deltas[0].ToolCalls) != 1 || deltas[1].Content != "Calling a tool." || deltas[1].FinishReason != "tool_calls" {
+		t.Errorf("deltas = %+v", deltas)
+	}
+}
@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+)
+
+// A tenant is a customer of a multi-tenant deployment (forge.yaml
+// `tenants:`). Its callers authenticate with its API keys; the tenant
+// rides the request context from there, and everything the agent keeps
+// for a task is partitioned by it. Task IDs carry the tenant as a
+// prefix, so stores keyed by task ID (sessions, the task store, the
+// compactor) are partitioned without knowing about tenants.
+
+// TenantSeparator joins a tenant to a task or schedule ID. It survives the
+// filename sanitizing of the session store, so a scoped ID can never
+// collide with one a caller outside the tenant could name.
+const TenantSeparator = "."
+
+type tenantKey struct{}
+
+// WithTenant stores the tenant the task runs for in the context. An
+// empty tenant leaves the context as it is.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	if tenant == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant the task runs for, or "" outside
+// any tenant.
+func TenantFromContext(ctx context.Context) string {
+	t, _ := ctx.Value(tenantKey{}).(string)
+	return t
+}
+
+// TenantScopedID scopes a caller-chosen task or schedule ID to tenant. An ID already
+// scoped to the tenant is returned as it is, so the IDs handed back to
+// a tenant can be sent again.
+func TenantScopedID(tenant, id string) string {
+	if tenant == "" || strings.HasPrefix(id, tenant+TenantSeparator) {
+		return id
+	}
+	return tenant + TenantSeparator + id
+}
+
+// TenantFromScopedID returns the tenant a scoped ID belongs to when
+// it is one of tenants, else "".
+func TenantFromScopedID(id string, tenants []string) string {
+	prefix, _, ok := strings.Cut(id, TenantSeparator)
+	if !ok {
+		return ""
+	}
+	for _, t := range tenants {
+		if t == prefix {
+			return t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTenantScopedID(t *testing.T) {
+	tests := []struct{ tenant, id, want string }{
+		{"", "task-1", "task-1"},
+		{"acme", "task-1", "acme.task-1"},
+		{"acme", "acme.task-1", "acme.task-1"},
+		{"acme", "globex.task-1", "acme.globex.task-1"},
+	}
+	for _, tt := range tests {
+		if got := TenantScopedID(tt.tenant, tt.id); got != tt.want {
+			t.Errorf("TenantScopedID(%q, %q) = %q, want %q", tt.tenant, tt.id, got, tt.want)
+		}
+	}
+	tenants := []string{"acme", "globex"}
+	if got := TenantFromScopedID("acme.task-1", tenants); got != "acme" {
+		t.Errorf("TenantFromScopedID = %q, want acme", got)
+	}
+	for _, id := range []string{"task-1", "initech.task-1", "v1.2"} {
+		if got := TenantFromScopedID(id, tenants); got != "" {
+			t.Errorf("TenantFromScopedID(%q) = %q, want none", id, got)
+		}
+	}
+}
+
+func TestEmitFromContext_StampsTenant(t *testing.T) {
+	var buf bytes.Buffer
+	al := NewAuditLogger(&buf)
+	al.EmitFromContext(WithTenant(context.Background(), "acme"), AuditEvent{Event: "test"})
+	al.EmitFromContext(context.Background(), AuditEvent{Event: "test"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[0], `"tenant_id":"acme"`) {
+		t.Errorf("tenant event = %s, want tenant_id", lines[0])
+	}
+	if strings.Contains(lines[1], `"tenant_id"`) {
+		t.Errorf("untenanted event = %s, want no tenant_id", lines[1])
+	}
+}
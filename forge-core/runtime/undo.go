@@ -0,0 +1,140 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// ErrNothingToUndo is returned by UndoLastExchange when the task has no
+// user turn left to roll back (no persisted session, or the session was
+// compacted down to its summary).
+var ErrNothingToUndo = errors.New("undo: no exchange to undo")
+
+// UndoneToolCall describes one tool call removed from the conversation
+// by an undo, paired with the tool result the loop recorded for it.
+// Result is empty when the call never produced a result message (e.g.
+// the invocation was cancelled mid-exchange).
+type UndoneToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+	Result    string
+}
+
+// UndoResult summarizes what UndoLastExchange removed.
+type UndoResult struct {
+	TaskID string
+	// UserMessage is the content of the user turn that opened the
+	// reverted exchange.
+	UserMessage string
+	// RemovedMessages counts every message dropped from the session —
+	// the user turn, assistant turns, tool results, and loop nudges.
+	RemovedMessages int
+	// ToolCalls lists the tool calls the agent made during the
+	// exchange, in call order.
+	ToolCalls []UndoneToolCall
+}
+
+// UndoLastExchange rolls the persisted session for taskID back by one
+// exchange: the most recent user turn and everything the agent loop
+// appended after it. The trimmed session is saved back to store.
+//
+// userContent pins the exchange boundary. The loop appends its own
+// user-role nudges ("You stopped…", "Your response was empty…") inside
+// an exchange, so "last user message" alone would undo only the tail
+// of a nudged turn. Callers pass the text of the last user message in
+// the A2A task history (see UndoTaskHistory), which never contains
+// nudges; when it is empty or no longer present in the session (it may
+// have been compacted away), the last user message is used instead.
+func UndoLastExchange(store SessionStore, taskID, userContent string) (*UndoResult, error) {
+	if store == nil {
+		return nil, ErrNothingToUndo
+	}
+	saved, err := store.Load(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("loading session: %w", err)
+	}
+	if saved == nil {
+		return nil, ErrNothingToUndo
+	}
+
+	cut := -1
+	lastUser := -1
+	for i := len(saved.Messages) - 1; i >= 0; i-- {
+		msg := saved.Messages[i]
+		if msg.Role != llm.RoleUser {
+			continue
+		}
+		if lastUser < 0 {
+			lastUser = i
+		}
+		if userContent != "" && msg.Content == userContent {
+			cut = i
+			break
+		}
+	}
+	if cut < 0 {
+		cut = lastUser
+	}
+	if cut < 0 {
+		return nil, ErrNothingToUndo
+	}
+
+	removed := saved.Messages[cut:]
+	res := &UndoResult{
+		TaskID:          taskID,
+		UserMessage:     removed[0].Content,
+		RemovedMessages: len(removed),
+		ToolCalls:       undoneToolCalls(removed),
+	}
+
+	saved.Messages = append([]llm.ChatMessage(nil), saved.Messages[:cut]...)
+	if err := store.Save(saved); err != nil {
+		return nil, fmt.Errorf("saving session: %w", err)
+	}
+	return res, nil
+}
+
+// undoneToolCalls pairs every assistant tool call in msgs with the
+// tool result message carrying the same ToolCallID.
+func undoneToolCalls(msgs []llm.ChatMessage) []UndoneToolCall {
+	results := make(map[string]string)
+	for _, msg := range msgs {
+		if msg.Role == llm.RoleTool && msg.ToolCallID != "" {
+			results[msg.ToolCallID] = msg.Content
+		}
+	}
+	var calls []UndoneToolCall
+	for _, msg := range msgs {
+		if msg.Role != llm.RoleAssistant {
+			continue
+		}
+		for _, tc := range msg.ToolCalls {
+			calls = append(calls, UndoneToolCall{
+				ID:        tc.ID,
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+				Result:    results[tc.ID],
+			})
+		}
+	}
+	return calls
+}
+
+// UndoTaskHistory drops the most recent user message and every message
+// after it from an A2A task history. It returns the trimmed history and
+// the text of the removed user message — the exchange boundary
+// UndoLastExchange expects — or ok=false when history holds no user
+// message.
+func UndoTaskHistory(history []a2a.Message) (trimmed []a2a.Message, userContent string, ok bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role != a2a.MessageRoleUser {
+			continue
+		}
+		return append([]a2a.Message(nil), history[:i]...), a2aMessageToLLM(history[i]).Content, true
+	}
+	return history, "", false
+}
@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestUndoLastExchange_TrimsNudgedExchange(t *testing.T) {
+	store, err := NewMemoryStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "first"},
+		{Role: llm.RoleAssistant, Content: "done"},
+		{Role: llm.RoleUser, Content: "schedule a report"},
+		{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+			{ID: "tc-1", Type: "function", Function: llm.FunctionCall{Name: "schedule_set", Arguments: `{"cron":"@daily","task":"report"}`}},
+			{ID: "tc-2", Type: "function", Function: llm.FunctionCall{Name: "file_read", Arguments: `{"path":"a"}`}},
+		}},
+		{Role: llm.RoleTool, ToolCallID: "tc-1", Name: "schedule_set", Content: `Created schedule "report-ab12".`},
+		{Role: llm.RoleTool, ToolCallID: "tc-2", Name: "file_read", Content: "1\tdata"},
+		{Role: llm.RoleAssistant, Content: "scheduled"},
+		// Loop nudge inside the same exchange — must not become the boundary.
+		{Role: llm.RoleUser, Content: "You stopped. If the task is complete, summarize what was done."},
+		{Role: llm.RoleAssistant, Content: "summary"},
+	}
+	if err := store.Save(&SessionData{TaskID: "t1", Messages: msgs}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := UndoLastExchange(store, "t1", "schedule a report")
+	if err != nil {
+		t.Fatalf("UndoLastExchange: %v", err)
+	}
+	if res.RemovedMessages != 7 {
+		t.Errorf("RemovedMessages = %d, want 7", res.RemovedMessages)
+	}
+	if res.UserMessage != "schedule a report" {
+		t.Errorf("UserMessage = %q", res.UserMessage)
+	}
+	if len(res.ToolCalls) != 2 {
+		t.Fatalf("ToolCalls = %+v, want 2", res.ToolCalls)
+	}
+	if tc := res.ToolCalls[0]; tc.ID != "tc-1" || tc.Name != "schedule_set" || tc.Result != `Created schedule "report-ab12".` {
+		t.Errorf("ToolCalls[0] = %+v", tc)
+	}
+
+	saved, err := store.Load("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Messages) != 2 || saved.Messages[1].Content != "done" {
+		t.Errorf("persisted messages = %+v, want first exchange only", saved.Messages)
+	}
+}
+
+func TestUndoLastExchange_FallsBackToLastUserMessage(t *testing.T) {
+	store, err := NewMemoryStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "hi"},
+		{Role: llm.RoleAssistant, Content: "hello"},
+	}
+	if err := store.Save(&SessionData{TaskID: "t1", Messages: msgs}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := UndoLastExchange(store, "t1", "")
+	if err != nil {
+		t.Fatalf("UndoLastExchange: %v", err)
+	}
+	if res.RemovedMessages != 2 || len(res.ToolCalls) != 0 {
+		t.Errorf("res = %+v", res)
+	}
+
+	if _, err := UndoLastExchange(store, "t1", ""); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("second undo err = %v, want ErrNothingToUndo", err)
+	}
+	if _, err := UndoLastExchange(store, "missing", ""); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("missing session err = %v, want ErrNothingToUndo", err)
+	}
+	if _, err := UndoLastExchange(nil, "t1", ""); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("nil store err = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestUndoTaskHistory(t *testing.T) {
+	history := []a2a.Message{
+		{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("one")}},
+		{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("1")}},
+		{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("two")}},
+		{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("2")}},
+	}
+	trimmed, content, ok := UndoTaskHistory(history)
+	if !ok || content != "two" || len(trimmed) != 2 {
+		t.Errorf("UndoTaskHistory = (%d msgs, %q, %v)", len(trimmed), content, ok)
+	}
+
+	if _, _, ok := UndoTaskHistory(history[1:2]); ok {
+		t.Error("agent-only history reported an undoable exchange")
+	}
+}
@@ -0,0 +1,178 @@
+package runtime
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// ModelPrice is a model's list price in USD per million tokens.
+type ModelPrice struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// ModelPricing maps model-name prefixes to list prices, used to estimate
+// the cost recorded in a UsageLedger. Prefixes are matched longest-first,
+// as in ModelContextWindows. Models with no entry (local Ollama models,
+// custom deployments) are counted in UnpricedCalls instead of the cost.
+// Estimates use list prices and ignore prompt-cache discounts.
+var ModelPricing = map[string]ModelPrice{
+	"claude-opus-4-5":         {5, 25},
+	"claude-opus-4":           {15, 75},
+	"claude-sonnet-4":         {3, 15},
+	"claude-3-7-sonnet":       {3, 15},
+	"claude-3-5-sonnet":       {3, 15},
+	"claude-haiku-4-5":        {1, 5},
+	"claude-3-5-haiku":        {0.8, 4},
+	"claude-3-haiku":          {0.25, 1.25},
+	"gpt-5":                   {1.25, 10},
+	"gpt-5-mini":              {0.25, 2},
+	"gpt-5-nano":              {0.05, 0.4},
+	"gpt-4.1":                 {2, 8},
+	"gpt-4.1-mini":            {0.4, 1.6},
+	"gpt-4.1-nano":            {0.1, 0.4},
+	"gpt-4o":                  {2.5, 10},
+	"gpt-4o-mini":             {0.15, 0.6},
+	"o3":                      {2, 8},
+	"o4-mini":                 {1.1, 4.4},
+	"gemini-2.5-pro":          {1.25, 10},
+	"gemini-2.5-flash":        {0.3, 2.5},
+	"gemini-2.5-flash-lite":   {0.1, 0.4},
+	"gemini-2.0-flash":        {0.1, 0.4},
+	"mistral-large":           {2, 6},
+	"mistral-medium":          {0.4, 2},
+	"mistral-small":           {0.1, 0.3},
+	"command-r-plus":          {2.5, 10},
+	"command-r":               {0.15, 0.6},
+	"deepseek-chat":           {0.27, 1.1},
+	"deepseek-reasoner":       {0.55, 2.19},
+	"grok-4":                  {3, 15},
+	"grok-3-mini":             {0.3, 0.5},
+	"llama-3.3-70b-versatile": {0.59, 0.79},
+}
+
+// PriceForModel returns the list price for model. Provider prefixes such
+// as "openai/" (OpenRouter) or "us.anthropic." (Bedrock) are ignored.
+func PriceForModel(model string) (ModelPrice, bool) {
+	model = strings.ToLower(model)
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	if i := strings.Index(model, "anthropic."); i >= 0 {
+		model = model[i+len("anthropic."):]
+	}
+	bestPrefix := ""
+	var best ModelPrice
+	for prefix, price := range ModelPricing {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(bestPrefix) {
+			bestPrefix = prefix
+			best = price
+		}
+	}
+	return best, bestPrefix != ""
+}
+
+// ModelUsage is one provider/model's share of a UsageLedger.
+type ModelUsage struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	LLMCalls         int     `json:"llm_calls"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	// Priced is false when the model has no ModelPricing entry; its
+	// cost is then zero rather than estimated.
+	Priced bool `json:"priced"`
+}
+
+// UsageLedger is the running token and cost total of a task (persisted
+// with its session and returned in tasks/get metadata) or of the whole
+// agent (the /info usage section), broken down by provider and model so
+// spend can be attributed for billing.
+type UsageLedger struct {
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	ReasoningTokens  int     `json:"reasoning_tokens,omitempty"`
+	LLMCalls         int     `json:"llm_calls"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	// UnpricedCalls counts calls to models without a known price, which
+	// EstimatedCostUSD leaves out.
+	UnpricedCalls int `json:"unpriced_calls,omitempty"`
+	// Models is the per-provider/model breakdown, in order of first use.
+	Models []ModelUsage `json:"models,omitempty"`
+}
+
+// Add folds one LLM call into the ledger.
+func (l *UsageLedger) Add(provider, model string, u llm.UsageInfo) {
+	total := u.TotalTokens
+	if total == 0 {
+		total = u.InputTokens + u.OutputTokens
+	}
+	price, priced := PriceForModel(model)
+	cost := (float64(u.InputTokens)*price.InputPerMTok + float64(u.OutputTokens)*price.OutputPerMTok) / 1e6
+
+	l.InputTokens += u.InputTokens
+	l.OutputTokens += u.OutputTokens
+	l.TotalTokens += total
+	l.ReasoningTokens += u.ReasoningTokens
+	l.LLMCalls++
+	l.EstimatedCostUSD += cost
+	if !priced {
+		l.UnpricedCalls++
+	}
+
+	m := l.model(provider, model)
+	m.InputTokens += u.InputTokens
+	m.OutputTokens += u.OutputTokens
+	m.TotalTokens += total
+	m.LLMCalls++
+	m.EstimatedCostUSD += cost
+	m.Priced = priced
+}
+
+func (l *UsageLedger) model(provider, model string) *ModelUsage {
+	for i := range l.Models {
+		if l.Models[i].Provider == provider && l.Models[i].Model == model {
+			return &l.Models[i]
+		}
+	}
+	l.Models = append(l.Models, ModelUsage{Provider: provider, Model: model})
+	return &l.Models[len(l.Models)-1]
+}
+
+// Clone returns a deep copy of l, or nil for a nil ledger.
+func (l *UsageLedger) Clone() *UsageLedger {
+	if l == nil {
+		return nil
+	}
+	c := *l
+	c.Models = append([]ModelUsage(nil), l.Models...)
+	return &c
+}
+
+// UsageLedgerFromMetadata reads the ledger a task's "usage" metadata
+// holds: the *UsageLedger an in-memory store keeps, or the JSON object
+// it becomes once the task has round-tripped through a persistent one.
+func UsageLedgerFromMetadata(v any) (*UsageLedger, bool) {
+	switch u := v.(type) {
+	case *UsageLedger:
+		return u, u != nil
+	case UsageLedger:
+		return &u, true
+	case map[string]any:
+		raw, err := json.Marshal(u)
+		if err != nil {
+			return nil, false
+		}
+		var l UsageLedger
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return nil, false
+		}
+		return &l, true
+	}
+	return nil, false
+}
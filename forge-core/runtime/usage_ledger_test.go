@@ -0,0 +1,151 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestPriceForModel(t *testing.T) {
+	tests := []struct {
+		model string
+		want  ModelPrice
+		ok    bool
+	}{
+		{"claude-sonnet-4-20250514", ModelPrice{3, 15}, true},
+		{"us.anthropic.claude-sonnet-4-20250514-v1:0", ModelPrice{3, 15}, true},
+		{"openai/gpt-4o-mini", ModelPrice{0.15, 0.6}, true}, // longest prefix wins over gpt-4o
+		{"gpt-4o", ModelPrice{2.5, 10}, true},
+		{"llama3.1:8b", ModelPrice{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := PriceForModel(tt.model)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("PriceForModel(%q) = %v, %v; want %v, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestUsageLedger_Add(t *testing.T) {
+	var l UsageLedger
+	l.Add("anthropic", "claude-sonnet-4-20250514", llm.UsageInfo{InputTokens: 1_000_000, OutputTokens: 100_000})
+	l.Add("openai", "gpt-4o", llm.UsageInfo{InputTokens: 200_000, OutputTokens: 10_000, TotalTokens: 210_000})
+	l.Add("anthropic", "claude-sonnet-4-20250514", llm.UsageInfo{InputTokens: 1000, OutputTokens: 0})
+	l.Add("ollama", "llama3.1", llm.UsageInfo{InputTokens: 50, OutputTokens: 5})
+
+	if l.LLMCalls != 4 || l.UnpricedCalls != 1 {
+		t.Errorf("calls = %d, unpriced = %d; want 4, 1", l.LLMCalls, l.UnpricedCalls)
+	}
+	if l.TotalTokens != 1_100_000+210_000+1000+55 {
+		t.Errorf("TotalTokens = %d", l.TotalTokens)
+	}
+	// 3 + 1.5 (sonnet) + 0.5 + 0.1 (gpt-4o) + 0.003 (sonnet)
+	if want := 5.103; math.Abs(l.EstimatedCostUSD-want) > 1e-9 {
+		t.Errorf("EstimatedCostUSD = %v, want %v", l.EstimatedCostUSD, want)
+	}
+	if len(l.Models) != 3 {
+		t.Fatalf("Models = %+v, want 3 entries", l.Models)
+	}
+	if m := l.Models[0]; m.Provider != "anthropic" || m.LLMCalls != 2 || m.InputTokens != 1_001_000 || !m.Priced {
+		t.Errorf("Models[0] = %+v", m)
+	}
+	if m := l.Models[2]; m.Priced || m.EstimatedCostUSD != 0 {
+		t.Errorf("Models[2] = %+v, want unpriced", m)
+	}
+
+	c := l.Clone()
+	c.Models[0].LLMCalls = 99
+	if l.Models[0].LLMCalls == 99 {
+		t.Error("Clone shares the Models slice")
+	}
+}
+
+// TestExecute_UsageLedger checks that a task's usage accumulates across
+// turns, is persisted with the session, and is exposed in task metadata
+// for tasks/get.
+func TestExecute_UsageLedger(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(_ context.Context, _ *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "ok"},
+				Usage:        llm.UsageInfo{InputTokens: 100, OutputTokens: 10, TotalTokens: 110},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	store, err := NewMemoryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	exec := NewLLMExecutor(LLMExecutorConfig{
+		Client: client, ModelName: "gpt-4o", Provider: "openai", Store: store,
+	})
+
+	task := &a2a.Task{ID: "task-usage"}
+	for _, text := range []string{"one", "two"} {
+		msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(text)}}
+		if _, err := exec.Execute(context.Background(), task, msg); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+
+	usage, ok := task.Metadata["usage"].(*UsageLedger)
+	if !ok {
+		t.Fatalf("task metadata usage = %#v", task.Metadata["usage"])
+	}
+	if usage.LLMCalls != 2 || usage.TotalTokens != 220 {
+		t.Errorf("usage = %+v, want 2 calls / 220 tokens", usage)
+	}
+	if len(usage.Models) != 1 || usage.Models[0].Provider != "openai" || usage.Models[0].Model != "gpt-4o" {
+		t.Errorf("Models = %+v", usage.Models)
+	}
+
+	saved, err := store.Load(task.ID)
+	if err != nil || saved == nil || saved.Usage == nil {
+		t.Fatalf("Load: err=%v saved=%+v", err, saved)
+	}
+	if saved.Usage.LLMCalls != 2 || saved.Usage.EstimatedCostUSD != usage.EstimatedCostUSD {
+		t.Errorf("persisted usage = %+v, want %+v", saved.Usage, usage)
+	}
+}
+
+func TestLLMExecutor_SetModelAttributesUsage(t *testing.T) {
+	e := NewLLMExecutor(LLMExecutorConfig{Provider: "openai", ModelName: "gpt-4o"})
+	e.SetModel("anthropic", "claude-sonnet-4")
+
+	mem := NewMemory("", 0, "")
+	e.recordUsage(mem, &llm.ChatResponse{Usage: llm.UsageInfo{InputTokens: 10, OutputTokens: 5}})
+	u := mem.Usage()
+	if len(u.Models) != 1 || u.Models[0].Provider != "anthropic" || u.Models[0].Model != "claude-sonnet-4" {
+		t.Errorf("usage models = %+v, want the reloaded model", u.Models)
+	}
+}
+
+func TestUsageLedgerFromMetadata(t *testing.T) {
+	l := &UsageLedger{InputTokens: 10, OutputTokens: 5, TotalTokens: 15, LLMCalls: 1,
+		Models: []ModelUsage{{Provider: "openai", Model: "gpt-4o-mini", TotalTokens: 15, LLMCalls: 1}}}
+	if got, ok := UsageLedgerFromMetadata(l); !ok || got != l {
+		t.Errorf("pointer form = %v, %v", got, ok)
+	}
+
+	// A JSON-backed task store hands the ledger back as a map.
+	raw, _ := json.Marshal(map[string]any{"usage": l})
+	var md map[string]any
+	if err := json.Unmarshal(raw, &md); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := UsageLedgerFromMetadata(md["usage"])
+	if !ok || got.TotalTokens != 15 || got.LLMCalls != 1 || len(got.Models) != 1 || got.Models[0].Model != "gpt-4o-mini" {
+		t.Errorf("map form = %+v, %v", got, ok)
+	}
+
+	for _, v := range []any{nil, "usage", (*UsageLedger)(nil)} {
+		if _, ok := UsageLedgerFromMetadata(v); ok {
+			t.Errorf("UsageLedgerFromMetadata(%#v) = ok", v)
+		}
+	}
+}
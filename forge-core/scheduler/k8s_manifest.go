@@ -4,7 +4,9 @@ import (
 	"crypto/sha1" //nolint:gosec // hash is for name derivation, not security
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 )
 
 // CronJobManifestInput is the data the manifest builders consume. The
@@ -39,6 +41,26 @@ type CronJobManifestInput struct {
 // pull is reproducible.
 const DefaultTriggerImage = "curlimages/curl:8.10.1"
 
+// ConcurrencyPolicy maps a schedule's overlap policy onto the CronJob
+// concurrencyPolicy. Kubernetes has no queueing policy, so "queue"
+// falls back to Forbid — the same skip the file backend's default does.
+func ConcurrencyPolicy(overlap string) string {
+	if overlap == OverlapCancelPrevious {
+		return "Replace"
+	}
+	return "Forbid"
+}
+
+// JobDeadlineSeconds is a schedule's MaxRuntime as the trigger Job's
+// activeDeadlineSeconds, rounded up; 0 when unbounded. Killing the
+// trigger pod drops its request, which cancels the agent's run.
+func JobDeadlineSeconds(maxRuntime time.Duration) int64 {
+	if maxRuntime <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(maxRuntime.Seconds()))
+}
+
 // CronJobName returns the deterministic K8s resource name for a
 // schedule. K8s resource names are constrained to 63 chars with a
 // restricted character set; we hash-suffix when the natural name
@@ -113,6 +135,17 @@ func CronJobYAML(input CronJobManifestInput) string {
 
 	taskText := escapeForSingleQuotedJSON(input.Schedule.Task)
 
+	// CronJob timeZone (batch/v1, Kubernetes 1.27+) is only emitted when
+	// set, so UTC schedules render exactly as before.
+	timeZone := ""
+	if tz := input.Schedule.Timezone; tz != "" {
+		timeZone = fmt.Sprintf("\n  timeZone: %q", tz)
+	}
+	deadline := ""
+	if secs := JobDeadlineSeconds(input.Schedule.MaxRuntime); secs > 0 {
+		deadline = fmt.Sprintf("\n      activeDeadlineSeconds: %d", secs)
+	}
+
 	return fmt.Sprintf(`apiVersion: batch/v1
 kind: CronJob
 metadata:
@@ -123,12 +156,12 @@ metadata:
     forge.schedule.id: %s
     forge.schedule.source: %s
 spec:
-  schedule: %q
-  concurrencyPolicy: Forbid
+  schedule: %q%s
+  concurrencyPolicy: %s
   successfulJobsHistoryLimit: 3
   failedJobsHistoryLimit: 3
   jobTemplate:
-    spec:
+    spec:%s
       template:
         spec:
           restartPolicy: Never
@@ -152,7 +185,9 @@ spec:
 `,
 		name, namespace,
 		input.AgentID, input.Schedule.ID, defaultSource(input.Schedule.Source),
-		input.Schedule.Cron,
+		input.Schedule.Cron, timeZone,
+		ConcurrencyPolicy(input.Schedule.Overlap),
+		deadline,
 		image,
 		authSecret,
 		input.ServiceURL,
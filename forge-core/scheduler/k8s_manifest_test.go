@@ -3,6 +3,7 @@ package scheduler
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCronJobName_FitsK8sLimits(t *testing.T) {
@@ -98,6 +99,42 @@ func TestCronJobYAML_HasRequiredFields(t *testing.T) {
 	}
 }
 
+// TestCronJobYAML_TimeZone verifies a schedule's timezone becomes the
+// CronJob timeZone field and that UTC schedules omit it.
+func TestCronJobYAML_TimeZone(t *testing.T) {
+	in := CronJobManifestInput{
+		AgentID:  "aibuilderdemo",
+		Schedule: Schedule{ID: "standup", Cron: "0 9 * * 1-5", Task: "Post standup"},
+	}
+	if yaml := CronJobYAML(in); strings.Contains(yaml, "timeZone:") {
+		t.Errorf("UTC schedule should omit timeZone:\n%s", yaml)
+	}
+	in.Schedule.Timezone = "America/New_York"
+	if yaml := CronJobYAML(in); !strings.Contains(yaml, "schedule: \"0 9 * * 1-5\"\n  timeZone: \"America/New_York\"\n") {
+		t.Errorf("CronJobYAML missing timeZone:\n%s", yaml)
+	}
+}
+
+// TestCronJobYAML_RunPolicies verifies overlap and max_runtime become
+// the CronJob concurrencyPolicy and the Job's activeDeadlineSeconds.
+func TestCronJobYAML_RunPolicies(t *testing.T) {
+	yaml := CronJobYAML(CronJobManifestInput{
+		AgentID: "aibuilderdemo",
+		Schedule: Schedule{
+			ID: "report", Cron: "@hourly", Task: "Build the report",
+			MaxRuntime: 10 * time.Minute, Overlap: OverlapCancelPrevious,
+		},
+	})
+	for _, sub := range []string{"concurrencyPolicy: Replace", "jobTemplate:\n    spec:\n      activeDeadlineSeconds: 600\n      template:"} {
+		if !strings.Contains(yaml, sub) {
+			t.Errorf("CronJobYAML missing %q\nfull output:\n%s", sub, yaml)
+		}
+	}
+	if ConcurrencyPolicy(OverlapQueue) != "Forbid" || JobDeadlineSeconds(1500*time.Millisecond) != 2 {
+		t.Error("queue should map to Forbid and deadlines round up")
+	}
+}
+
 // TestCronJobYAML_DefaultsAppliedForMissingFields verifies the
 // defaults the manifest builder fills in when caller leaves fields
 // empty: trigger image, auth secret name, namespace, source. Lets
@@ -0,0 +1,563 @@
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NaturalSchedule is a plain-language schedule resolved into a cron
+// expression and the IANA timezone its fields are read in. Warnings
+// flag every guess the parser made (a missing am/pm, an ambiguous zone
+// abbreviation, a default time) so a client can confirm before saving.
+type NaturalSchedule struct {
+	Cron        string   `json:"cron"`
+	Timezone    string   `json:"timezone"`
+	Description string   `json:"description"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// ParseNatural resolves a schedule phrase such as "every weekday at 9am
+// Eastern", "every 15 minutes", "mondays and thursdays at 17:30" or
+// "monthly on the 1st at noon" into a validated cron expression. A cron
+// expression or alias is accepted as is. defaultTZ is the zone used when
+// the phrase names none ("" = UTC).
+func ParseNatural(text, defaultTZ string) (*NaturalSchedule, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("empty schedule")
+	}
+	p := &naturalParser{}
+	if _, err := Parse(text); err == nil {
+		p.cron = text
+		p.desc = "cron " + text
+	} else if err := p.parse(text); err != nil {
+		return nil, err
+	}
+
+	tz := p.tz
+	if tz == "" {
+		tz = defaultTZ
+		if tz == "" {
+			tz = "UTC"
+			if p.wallClock {
+				p.warn("no timezone given; times are UTC")
+			}
+		}
+	}
+	loc, err := LoadTimezone(tz)
+	if err != nil {
+		return nil, err
+	}
+	if p.wallClock && observesDST(loc) {
+		for _, c := range p.times {
+			if c.hour >= 1 && c.hour < 3 {
+				p.warn(fmt.Sprintf("%s falls in the hours daylight-saving changes skip or repeat in %s; the run can be missed or doubled on those days", c, tz))
+				break
+			}
+		}
+	}
+	if _, err := ParseIn(p.cron, tz); err != nil {
+		return nil, fmt.Errorf("resolved to invalid cron %q: %w", p.cron, err)
+	}
+	return &NaturalSchedule{
+		Cron:        p.cron,
+		Timezone:    tz,
+		Description: p.desc + " (" + tz + ")",
+		Warnings:    p.warnings,
+	}, nil
+}
+
+// clock is a wall-clock time of day.
+type clock struct{ hour, minute int }
+
+func (c clock) String() string { return fmt.Sprintf("%02d:%02d", c.hour, c.minute) }
+
+// naturalParser accumulates what a schedule phrase says.
+type naturalParser struct {
+	everyN    int    // interval length; 0 = no interval
+	unit      string // "minute", "hour" or "day"
+	times     []clock
+	dows      []int
+	doms      []int
+	daily     bool
+	weekly    bool
+	monthly   bool
+	tz        string
+	wallClock bool // the schedule fires at times of day, so the zone matters
+
+	cron     string
+	desc     string
+	warnings []string
+}
+
+func (p *naturalParser) warn(msg string) { p.warnings = append(p.warnings, msg) }
+
+// fillerWords carry no scheduling meaning.
+var fillerWords = map[string]bool{
+	"every": true, "each": true, "at": true, "on": true, "the": true, "and": true,
+	"of": true, "in": true, "time": true, "run": true, "runs": true, "from": true,
+}
+
+var dayNames = map[string]int{
+	"sunday": 0, "sun": 0, "monday": 1, "mon": 1, "tuesday": 2, "tue": 2, "tues": 2,
+	"wednesday": 3, "wed": 3, "thursday": 4, "thu": 4, "thur": 4, "thurs": 4,
+	"friday": 5, "fri": 5, "saturday": 6, "sat": 6,
+}
+
+var fullDayNames = [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// zoneAliases maps common zone names and abbreviations to IANA zones,
+// with a warning where the abbreviation is ambiguous or misleading.
+var zoneAliases = map[string]struct{ zone, warning string }{
+	"utc":      {"UTC", ""},
+	"gmt":      {"UTC", ""},
+	"eastern":  {"America/New_York", ""},
+	"et":       {"America/New_York", ""},
+	"est":      {"America/New_York", "EST is read as US Eastern time (America/New_York), which switches to EDT in summer"},
+	"edt":      {"America/New_York", "EDT is read as US Eastern time (America/New_York), which switches to EST in winter"},
+	"central":  {"America/Chicago", ""},
+	"ct":       {"America/Chicago", ""},
+	"cst":      {"America/Chicago", "CST is read as US Central time (America/Chicago); name an IANA zone such as Asia/Shanghai for China Standard Time"},
+	"cdt":      {"America/Chicago", "CDT is read as US Central time (America/Chicago), which switches to CST in winter"},
+	"mountain": {"America/Denver", ""},
+	"mt":       {"America/Denver", ""},
+	"mst":      {"America/Denver", "MST is read as US Mountain time (America/Denver), which observes daylight saving; name America/Phoenix for Arizona"},
+	"mdt":      {"America/Denver", "MDT is read as US Mountain time (America/Denver), which switches to MST in winter"},
+	"pacific":  {"America/Los_Angeles", ""},
+	"pt":       {"America/Los_Angeles", ""},
+	"pst":      {"America/Los_Angeles", "PST is read as US Pacific time (America/Los_Angeles), which switches to PDT in summer"},
+	"pdt":      {"America/Los_Angeles", "PDT is read as US Pacific time (America/Los_Angeles), which switches to PST in winter"},
+	"bst":      {"Europe/London", "BST is read as British time (Europe/London), which switches to GMT in winter"},
+	"cet":      {"Europe/Berlin", "CET is read as Central European time (Europe/Berlin), which switches to CEST in summer"},
+	"ist":      {"Asia/Kolkata", "IST is read as India Standard Time; name an IANA zone such as Europe/Dublin or Asia/Jerusalem for Irish or Israel time"},
+	"jst":      {"Asia/Tokyo", ""},
+}
+
+func (p *naturalParser) parse(text string) error {
+	raw := strings.Fields(strings.NewReplacer(",", " ", ";", " ", "&", " and ").Replace(text))
+	toks := make([]string, len(raw))
+	for i, r := range raw {
+		toks[i] = strings.TrimSuffix(strings.ToLower(r), ".")
+	}
+
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		next := ""
+		if i+1 < len(toks) {
+			next = toks[i+1]
+		}
+		switch {
+		case fillerWords[t]:
+		case strings.Contains(raw[i], "/"):
+			if _, err := LoadTimezone(raw[i]); err != nil {
+				return err
+			}
+			if err := p.setZone(raw[i], ""); err != nil {
+				return err
+			}
+		case zoneAliases[t].zone != "":
+			a := zoneAliases[t]
+			if err := p.setZone(a.zone, a.warning); err != nil {
+				return err
+			}
+		case t == "hourly":
+			p.setInterval(1, "hour")
+		case t == "minute", t == "hour":
+			p.setInterval(1, t)
+		case t == "day", t == "days", t == "daily", t == "everyday":
+			p.daily = true
+		case t == "week", t == "weekly":
+			p.weekly = true
+		case t == "month", t == "monthly":
+			p.monthly = true
+		case t == "weekday", t == "weekdays":
+			p.dows = append(p.dows, 1, 2, 3, 4, 5)
+		case t == "weekend", t == "weekends":
+			p.dows = append(p.dows, 0, 6)
+		case t == "noon":
+			p.times = append(p.times, clock{12, 0})
+		case t == "midnight":
+			p.times = append(p.times, clock{0, 0})
+		case t == "first":
+			p.doms = append(p.doms, 1)
+		case t == "last":
+			return fmt.Errorf("%q: the last day of the month cannot be expressed in cron", text)
+		case isDayName(t) || isDayRange(t):
+			n, err := p.parseDays(toks, i)
+			if err != nil {
+				return err
+			}
+			i += n
+		case isOrdinal(t):
+			d, _ := strconv.Atoi(t[:len(t)-2])
+			if d < 1 || d > 31 {
+				return fmt.Errorf("day of month %q is out of range", raw[i])
+			}
+			p.doms = append(p.doms, d)
+		case startsWithDigit(t):
+			if n, err := strconv.Atoi(t); err == nil && intervalUnit(next) != "" {
+				if n <= 0 {
+					return fmt.Errorf("interval %q must be positive", t+" "+next)
+				}
+				p.setInterval(n, intervalUnit(next))
+				i++
+				continue
+			}
+			if d, err := strconv.Atoi(t); err == nil && i > 0 && toks[i-1] == "the" && next != "am" && next != "pm" {
+				if d < 1 || d > 31 {
+					return fmt.Errorf("day of month %q is out of range", raw[i])
+				}
+				p.doms = append(p.doms, d)
+				continue
+			}
+			suffix := ""
+			if next == "am" || next == "pm" {
+				suffix = next
+				i++
+			}
+			c, err := p.parseClock(t, suffix)
+			if err != nil {
+				return err
+			}
+			p.times = append(p.times, c)
+		default:
+			return fmt.Errorf("could not understand %q in %q", raw[i], text)
+		}
+	}
+	return p.build(text)
+}
+
+func (p *naturalParser) setZone(zone, warning string) error {
+	if p.tz != "" && p.tz != zone {
+		return fmt.Errorf("two timezones given: %s and %s", p.tz, zone)
+	}
+	p.tz = zone
+	if warning != "" {
+		p.warn(warning)
+	}
+	return nil
+}
+
+func (p *naturalParser) setInterval(n int, unit string) {
+	p.everyN, p.unit = n, unit
+}
+
+// intervalUnit normalizes an interval unit word, or returns "".
+func intervalUnit(s string) string {
+	switch s {
+	case "minute", "minutes", "min", "mins", "m":
+		return "minute"
+	case "hour", "hours", "hr", "hrs", "h":
+		return "hour"
+	case "day", "days", "d":
+		return "day"
+	}
+	return ""
+}
+
+func isDayName(s string) bool {
+	if _, ok := dayNames[s]; ok {
+		return true
+	}
+	_, ok := dayNames[strings.TrimSuffix(s, "s")]
+	return ok
+}
+
+func dayNumber(s string) int {
+	if d, ok := dayNames[s]; ok {
+		return d
+	}
+	return dayNames[strings.TrimSuffix(s, "s")]
+}
+
+// isDayRange reports whether s is a hyphenated day range like "mon-fri".
+func isDayRange(s string) bool {
+	from, to, ok := strings.Cut(s, "-")
+	return ok && isDayName(from) && isDayName(to)
+}
+
+// parseDays reads a day name at toks[i], or a range written "mon-fri"
+// or "monday to friday". It returns how many extra tokens it consumed.
+func (p *naturalParser) parseDays(toks []string, i int) (int, error) {
+	from, to, consumed := toks[i], "", 0
+	if a, b, ok := strings.Cut(toks[i], "-"); ok {
+		from, to = a, b
+	} else if i+2 < len(toks) && (toks[i+1] == "to" || toks[i+1] == "through" || toks[i+1] == "thru" || toks[i+1] == "-") && isDayName(toks[i+2]) {
+		to, consumed = toks[i+2], 2
+	}
+	start := dayNumber(from)
+	if to == "" {
+		p.dows = append(p.dows, start)
+		return consumed, nil
+	}
+	end := dayNumber(to)
+	if end < start {
+		return 0, fmt.Errorf("day range %s to %s wraps past Saturday; list the days instead", fullDayNames[start], fullDayNames[end])
+	}
+	for d := start; d <= end; d++ {
+		p.dows = append(p.dows, d)
+	}
+	return consumed, nil
+}
+
+func isOrdinal(s string) bool {
+	if len(s) < 3 || !startsWithDigit(s) {
+		return false
+	}
+	switch s[len(s)-2:] {
+	case "st", "nd", "rd", "th":
+		_, err := strconv.Atoi(s[:len(s)-2])
+		return err == nil
+	}
+	return false
+}
+
+func startsWithDigit(s string) bool { return s != "" && s[0] >= '0' && s[0] <= '9' }
+
+// parseClock reads "9", "9am", "9:30pm" or "17:00", with suffix the am/pm
+// written as a separate word.
+func (p *naturalParser) parseClock(s, suffix string) (clock, error) {
+	orig := strings.TrimSpace(s + " " + suffix)
+	for _, sfx := range []string{"am", "pm"} {
+		if v, ok := strings.CutSuffix(s, sfx); ok {
+			s, suffix = v, sfx
+		}
+	}
+	hs, ms, hasMinutes := strings.Cut(s, ":")
+	h, err := strconv.Atoi(hs)
+	if err != nil {
+		return clock{}, fmt.Errorf("could not read time %q", orig)
+	}
+	m := 0
+	if hasMinutes {
+		if m, err = strconv.Atoi(ms); err != nil || len(ms) != 2 || m > 59 {
+			return clock{}, fmt.Errorf("could not read time %q", orig)
+		}
+	}
+	if suffix != "" {
+		if h < 1 || h > 12 {
+			return clock{}, fmt.Errorf("time %q: hour must be 1-12 with am/pm", orig)
+		}
+		if h == 12 {
+			h = 0
+			if suffix == "am" {
+				p.warn(fmt.Sprintf("%q is read as midnight", orig))
+			}
+		}
+		if suffix == "pm" {
+			h += 12
+		}
+		return clock{h, m}, nil
+	}
+	if h > 23 {
+		return clock{}, fmt.Errorf("time %q: hour must be 0-23", orig)
+	}
+	if h >= 1 && h <= 12 {
+		p.warn(fmt.Sprintf("%q has no am/pm; read as %02d:%02d on the 24-hour clock", orig, h, m))
+	}
+	return clock{h, m}, nil
+}
+
+// build turns the collected parts into p.cron and p.desc.
+func (p *naturalParser) build(text string) error {
+	p.dows = sortedUnique(p.dows)
+	p.doms = sortedUnique(p.doms)
+	if len(p.dows) > 0 && len(p.doms) > 0 {
+		return fmt.Errorf("%q: combining weekdays and days of the month is not supported", text)
+	}
+
+	if p.everyN > 0 && p.unit != "day" {
+		return p.buildInterval(text)
+	}
+
+	dom, dow := "*", "*"
+	var days string
+	switch {
+	case p.everyN > 1: // every N days
+		if len(p.dows) > 0 || len(p.doms) > 0 {
+			return fmt.Errorf("%q: an every-%d-days interval cannot also name days", text, p.everyN)
+		}
+		dom = "*/" + strconv.Itoa(p.everyN)
+		days = fmt.Sprintf("every %d days", p.everyN)
+		p.warn(fmt.Sprintf("every %d days counts from the 1st of each month, so the gap across a month end can be shorter", p.everyN))
+	case len(p.dows) > 0:
+		dow = cronList(p.dows)
+		days = describeDows(p.dows)
+	case len(p.doms) > 0:
+		dom = cronList(p.doms)
+		days = describeDoms(p.doms)
+		if p.doms[len(p.doms)-1] > 28 {
+			p.warn(fmt.Sprintf("months with fewer than %d days are skipped", p.doms[len(p.doms)-1]))
+		}
+	case p.monthly:
+		dom = "1"
+		days = describeDoms([]int{1})
+		p.warn("no day of the month given; runs on the 1st")
+	case p.weekly:
+		dow = "0"
+		days = describeDows([]int{0})
+		p.warn("no weekday given; runs on Sunday")
+	case p.daily || p.everyN == 1 || len(p.times) > 0:
+		days = "every day"
+		if !p.daily && p.everyN == 0 {
+			p.warn("no days given; runs every day")
+		}
+	default:
+		return fmt.Errorf("could not find a schedule in %q; try \"every weekday at 9am\" or \"every 15 minutes\"", text)
+	}
+
+	if len(p.times) == 0 {
+		p.times = []clock{{0, 0}}
+		p.warn("no time of day given; runs at midnight")
+	}
+	slices.SortFunc(p.times, func(a, b clock) int { return (a.hour*60 + a.minute) - (b.hour*60 + b.minute) })
+	p.times = slices.Compact(p.times)
+	hours := make([]int, len(p.times))
+	for i, c := range p.times {
+		if c.minute != p.times[0].minute {
+			return fmt.Errorf("%q: times %s and %s must share the same minute to fit one cron expression", text, p.times[0], c)
+		}
+		hours[i] = c.hour
+	}
+
+	p.wallClock = true
+	p.cron = fmt.Sprintf("%d %s %s * %s", p.times[0].minute, cronList(hours), dom, dow)
+	p.desc = "At " + describeTimes(p.times) + " " + days
+	return nil
+}
+
+// buildInterval handles "every N minutes" and "every N hours", on
+// optional weekdays.
+func (p *naturalParser) buildInterval(text string) error {
+	if len(p.times) > 0 {
+		return fmt.Errorf("%q: an interval cannot also have a time of day", text)
+	}
+	span := map[string]int{"minute": 60, "hour": 24}[p.unit]
+	every := fmt.Sprintf("every %d %ss", p.everyN, p.unit)
+	if p.everyN == 1 {
+		every = "every " + p.unit
+	}
+	dow, days := "*", ""
+	if len(p.dows) > 0 {
+		dow, days = cronList(p.dows), " "+describeDows(p.dows)
+	}
+	if len(p.doms) > 0 {
+		return fmt.Errorf("%q: an interval cannot be limited to days of the month", text)
+	}
+
+	if span%p.everyN != 0 {
+		if dow != "*" {
+			return fmt.Errorf("%q: %s does not divide the %s evenly, so it cannot be limited to weekdays", text, every, map[string]string{"minute": "hour", "hour": "day"}[p.unit])
+		}
+		short := map[string]string{"minute": "m", "hour": "h"}[p.unit]
+		p.cron = fmt.Sprintf("@every %d%s", p.everyN, short)
+		p.desc = "Every " + strings.TrimPrefix(every, "every ") + ", counted from when the schedule is saved"
+		return nil
+	}
+
+	step := "*"
+	if p.everyN > 1 {
+		step = "*/" + strconv.Itoa(p.everyN)
+	}
+	if p.unit == "minute" {
+		p.cron = fmt.Sprintf("%s * * * %s", step, dow)
+	} else {
+		p.cron = fmt.Sprintf("0 %s * * %s", step, dow)
+		// Every N hours fires at local hours 0, N, 2N…; hourly fires
+		// on the hour wherever you are.
+		p.wallClock = p.everyN > 1
+	}
+	p.desc = strings.ToUpper(every[:1]) + every[1:] + days
+	return nil
+}
+
+func sortedUnique(v []int) []int {
+	slices.Sort(v)
+	return slices.Compact(v)
+}
+
+// cronList renders sorted values as a cron list, collapsing runs of
+// three or more into ranges: [1 2 3 4 5] → "1-5".
+func cronList(v []int) string {
+	var parts []string
+	for i := 0; i < len(v); {
+		j := i
+		for j+1 < len(v) && v[j+1] == v[j]+1 {
+			j++
+		}
+		switch {
+		case j-i >= 2:
+			parts = append(parts, fmt.Sprintf("%d-%d", v[i], v[j]))
+		case j > i:
+			parts = append(parts, strconv.Itoa(v[i]), strconv.Itoa(v[j]))
+		default:
+			parts = append(parts, strconv.Itoa(v[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+func describeDows(dows []int) string {
+	switch {
+	case len(dows) == 7:
+		return "every day"
+	case slices.Equal(dows, []int{1, 2, 3, 4, 5}):
+		return "on weekdays"
+	case slices.Equal(dows, []int{0, 6}):
+		return "on weekends"
+	}
+	names := make([]string, len(dows))
+	for i, d := range dows {
+		names[i] = fullDayNames[d]
+	}
+	return "on " + joinAnd(names)
+}
+
+func describeDoms(doms []int) string {
+	days := make([]string, len(doms))
+	for i, d := range doms {
+		days[i] = ordinal(d)
+	}
+	return "on the " + joinAnd(days) + " of each month"
+}
+
+func describeTimes(times []clock) string {
+	s := make([]string, len(times))
+	for i, c := range times {
+		s[i] = c.String()
+	}
+	return joinAnd(s)
+}
+
+func joinAnd(s []string) string {
+	if len(s) == 1 {
+		return s[0]
+	}
+	return strings.Join(s[:len(s)-1], ", ") + " and " + s[len(s)-1]
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// observesDST reports whether loc's UTC offset changes during the year.
+func observesDST(loc *time.Location) bool {
+	year := time.Now().Year()
+	_, jan := time.Date(year, time.January, 1, 12, 0, 0, 0, loc).Zone()
+	_, jul := time.Date(year, time.July, 1, 12, 0, 0, 0, loc).Zone()
+	return jan != jul
+}
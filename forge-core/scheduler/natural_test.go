@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNatural(t *testing.T) {
+	tests := []struct {
+		text, defaultTZ string
+		cron, tz, desc  string
+		warning         string // substring of one warning; "" = none expected
+	}{
+		{"every weekday at 9am Eastern", "", "0 9 * * 1-5", "America/New_York", "At 09:00 on weekdays (America/New_York)", ""},
+		{"every 15 minutes", "", "*/15 * * * *", "UTC", "Every 15 minutes (UTC)", ""},
+		{"every 90 minutes", "", "@every 90m", "UTC", "Every 90 minutes, counted from when the schedule is saved (UTC)", ""},
+		{"hourly", "", "0 * * * *", "UTC", "Every hour (UTC)", ""},
+		{"every 6 hours on weekends", "Europe/Berlin", "0 */6 * * 0,6", "Europe/Berlin", "Every 6 hours on weekends (Europe/Berlin)", ""},
+		{"Mondays and Thursdays at 17:30", "Asia/Tokyo", "30 17 * * 1,4", "Asia/Tokyo", "At 17:30 on Monday and Thursday (Asia/Tokyo)", ""},
+		{"mon-fri at 8:15 am and 5:15pm PST", "", "15 8,17 * * 1-5", "America/Los_Angeles", "At 08:15 and 17:15 on weekdays (America/Los_Angeles)", "PST is read as US Pacific"},
+		{"monday through wednesday at noon", "UTC", "0 12 * * 1-3", "UTC", "At 12:00 on Monday, Tuesday and Wednesday (UTC)", ""},
+		{"monthly on the 1st and 15th at midnight", "UTC", "0 0 1,15 * *", "UTC", "At 00:00 on the 1st and 15th of each month (UTC)", ""},
+		{"every day at 9", "UTC", "0 9 * * *", "UTC", "At 09:00 every day (UTC)", "has no am/pm"},
+		{"every weekday", "UTC", "0 0 * * 1-5", "UTC", "At 00:00 on weekdays (UTC)", "no time of day given"},
+		{"at 7pm", "Europe/London", "0 19 * * *", "Europe/London", "At 19:00 every day (Europe/London)", "no days given"},
+		{"every month", "UTC", "0 0 1 * *", "UTC", "At 00:00 on the 1st of each month (UTC)", "runs on the 1st"},
+		{"on the 31st at 6am", "UTC", "0 6 31 * *", "UTC", "At 06:00 on the 31st of each month (UTC)", "fewer than 31 days"},
+		{"every 2 days at 8am", "UTC", "0 8 */2 * *", "UTC", "At 08:00 every 2 days (UTC)", "counts from the 1st"},
+		{"every day at 2:30am in America/New_York", "", "30 2 * * *", "America/New_York", "At 02:30 every day (America/New_York)", "daylight-saving"},
+		{"every day at 9am", "", "0 9 * * *", "UTC", "At 09:00 every day (UTC)", "no timezone given"},
+		{"0 9 * * 1-5", "America/Chicago", "0 9 * * 1-5", "America/Chicago", "cron 0 9 * * 1-5 (America/Chicago)", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseNatural(tt.text, tt.defaultTZ)
+			if err != nil {
+				t.Fatalf("ParseNatural: %v", err)
+			}
+			if got.Cron != tt.cron || got.Timezone != tt.tz || got.Description != tt.desc {
+				t.Errorf("got %q %q %q, want %q %q %q", got.Cron, got.Timezone, got.Description, tt.cron, tt.tz, tt.desc)
+			}
+			found := tt.warning == ""
+			for _, w := range got.Warnings {
+				found = found || strings.Contains(w, tt.warning)
+			}
+			if !found {
+				t.Errorf("warnings %q lack %q", got.Warnings, tt.warning)
+			}
+			if tt.warning == "" && len(got.Warnings) > 0 {
+				t.Errorf("unexpected warnings %q", got.Warnings)
+			}
+		})
+	}
+}
+
+func TestParseNaturalErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"whenever you like",
+		"on the last day of the month",
+		"every 15 minutes at 9am",
+		"every 7 minutes on weekdays",
+		"mondays and the 1st",
+		"at 9:00 and 17:30",
+		"at 13pm",
+		"every day at 9am Eastern PST",
+		"every day in Mars/Olympus",
+		"friday to monday",
+	} {
+		if got, err := ParseNatural(text, ""); err == nil {
+			t.Errorf("ParseNatural(%q) = %+v, want an error", text, got)
+		}
+	}
+	if _, err := ParseNatural("every day at 9am", "Nowhere/Land"); err == nil {
+		t.Error("an unknown default timezone should fail")
+	}
+}
+
+func TestParseIn(t *testing.T) {
+	ps, err := ParseIn("0 9 * * *", "America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 12:00 UTC on 15 Jan is 07:00 in New York (EST, UTC-5); the next
+	// 09:00 there is 14:00 UTC.
+	next := ps.Next(time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 1, 15, 14, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Next = %v, want %v", next.UTC(), want)
+	}
+	if _, err := ParseIn("0 9 * * *", "Nowhere/Land"); err == nil {
+		t.Error("an unknown timezone should fail")
+	}
+}
@@ -10,6 +10,7 @@ import (
 type Schedule struct {
 	ID            string    `json:"id"`
 	Cron          string    `json:"cron"`
+	Timezone      string    `json:"timezone,omitempty"` // IANA zone the cron fields are read in; default UTC
 	Task          string    `json:"task"`
 	Skill         string    `json:"skill,omitempty"`
 	Channel       string    `json:"channel,omitempty"`        // channel adapter name (e.g. "slack", "telegram")
@@ -18,15 +19,49 @@ type Schedule struct {
 	Enabled       bool      `json:"enabled"`
 	Created       time.Time `json:"created"`
 	LastRun       time.Time `json:"last_run,omitempty"`
-	LastStatus    string    `json:"last_status,omitempty"` // completed, error, running, skipped
+	LastStatus    string    `json:"last_status,omitempty"` // completed, error, timeout, canceled, running, skipped
 	RunCount      int       `json:"run_count"`
+
+	// MaxRuntime bounds a single run; 0 = unbounded. A run over it is
+	// canceled and recorded as "timeout".
+	MaxRuntime time.Duration `json:"max_runtime,omitempty"`
+	// Overlap is what a fire does while the previous run is still
+	// going: OverlapSkip (default), OverlapQueue or OverlapCancelPrevious.
+	Overlap string `json:"overlap,omitempty"`
+	// Workflow runs the named forge.yaml workflow instead of Task.
+	Workflow string `json:"workflow,omitempty"`
+	// Tenant is the tenant that set the schedule; its runs are the
+	// tenant's tasks. Its ID carries the tenant as a prefix.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// Overlap policies for a schedule that comes due while its previous run
+// is still in progress.
+const (
+	// OverlapSkip drops the new fire and records it as "skipped".
+	OverlapSkip = "skip"
+	// OverlapQueue runs the new fire as soon as the current run ends.
+	// At most one fire is queued; further ones are dropped.
+	OverlapQueue = "queue"
+	// OverlapCancelPrevious cancels the current run, recording it as
+	// "canceled", and starts the new one.
+	OverlapCancelPrevious = "cancel-previous"
+)
+
+// ValidOverlap reports whether p is a known overlap policy ("" = skip).
+func ValidOverlap(p string) bool {
+	switch p {
+	case "", OverlapSkip, OverlapQueue, OverlapCancelPrevious:
+		return true
+	}
+	return false
 }
 
 // HistoryEntry records a single execution of a scheduled task.
 type HistoryEntry struct {
 	Timestamp     time.Time `json:"timestamp"`
 	ScheduleID    string    `json:"schedule_id"`
-	Status        string    `json:"status"` // completed, error, skipped
+	Status        string    `json:"status"` // completed, error, timeout, canceled, skipped
 	Duration      string    `json:"duration"`
 	CorrelationID string    `json:"correlation_id"`
 	Error         string    `json:"error,omitempty"`
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -37,15 +38,24 @@ type Scheduler struct {
 	dispatch TaskDispatcher
 	logger   Logger
 	audit    AuditFunc
+	isLeader func() bool // nil: always fire; see SetLeader
 
 	mu      sync.Mutex
-	running map[string]bool           // overlap prevention
+	running map[string]*activeRun     // in-flight runs, for overlap policies
 	parsed  map[string]ParsedSchedule // cache
 
 	stopCh chan struct{}
 	done   chan struct{}
 }
 
+// activeRun is a schedule's in-flight run. Its fields are guarded by
+// Scheduler.mu.
+type activeRun struct {
+	cancel   context.CancelFunc
+	queued   bool // OverlapQueue: run again as soon as this run ends
+	replaced bool // OverlapCancelPrevious: canceled for a newer run
+}
+
 // New creates a new Scheduler.
 func New(store ScheduleStore, dispatch TaskDispatcher, logger Logger, audit AuditFunc) *Scheduler {
 	return &Scheduler{
@@ -53,7 +63,7 @@ func New(store ScheduleStore, dispatch TaskDispatcher, logger Logger, audit Audi
 		dispatch: dispatch,
 		logger:   logger,
 		audit:    audit,
-		running:  make(map[string]bool),
+		running:  make(map[string]*activeRun),
 		parsed:   make(map[string]ParsedSchedule),
 		stopCh:   make(chan struct{}),
 		done:     make(chan struct{}),
@@ -71,6 +81,14 @@ func (s *Scheduler) Stop() {
 	<-s.done
 }
 
+// SetLeader makes the scheduler fire schedules only while isLeader
+// reports true. Replicas of one agent share a leader election so each
+// schedule fires once across the deploy; the others keep ticking and
+// take over when the leader goes away. Call it before Start.
+func (s *Scheduler) SetLeader(isLeader func() bool) {
+	s.isLeader = isLeader
+}
+
 // Reload re-reads the store and recomputes parsed expressions.
 func (s *Scheduler) Reload(ctx context.Context) {
 	schedules, err := s.store.List(ctx)
@@ -87,7 +105,7 @@ func (s *Scheduler) Reload(ctx context.Context) {
 		if !sched.Enabled {
 			continue
 		}
-		ps, parseErr := Parse(sched.Cron)
+		ps, parseErr := ParseIn(sched.Cron, sched.Timezone)
 		if parseErr != nil {
 			s.logger.Warn("scheduler reload: invalid cron expression", map[string]any{
 				"id": sched.ID, "cron": sched.Cron, "error": parseErr.Error(),
@@ -122,6 +140,9 @@ func (s *Scheduler) loop(ctx context.Context) {
 }
 
 func (s *Scheduler) tick(ctx context.Context) {
+	if s.isLeader != nil && !s.isLeader() {
+		return
+	}
 	schedules, err := s.store.List(ctx)
 	if err != nil {
 		s.logger.Warn("scheduler tick: failed to list schedules", map[string]any{"error": err.Error()})
@@ -142,7 +163,7 @@ func (s *Scheduler) tick(ctx context.Context) {
 		if !ok {
 			// Parse on demand if not cached (e.g., newly added).
 			var parseErr error
-			ps, parseErr = Parse(sched.Cron)
+			ps, parseErr = ParseIn(sched.Cron, sched.Timezone)
 			if parseErr != nil {
 				continue
 			}
@@ -160,28 +181,51 @@ func (s *Scheduler) tick(ctx context.Context) {
 			continue // Not due yet.
 		}
 
-		// Check overlap.
-		if s.running[sched.ID] {
-			s.logger.Info("schedule skipped (overlap)", map[string]any{"id": sched.ID})
-			if s.audit != nil {
-				s.audit(AuditScheduleSkip, sched.ID, map[string]any{"reason": "overlap"})
+		// Apply the overlap policy if the previous run is still going.
+		if run := s.running[sched.ID]; run != nil {
+			switch {
+			case sched.Overlap == OverlapQueue && run.queued:
+				continue // already queued; that run covers this fire
+			case sched.Overlap == OverlapQueue:
+				run.queued = true
+				s.logger.Info("schedule queued (overlap)", map[string]any{"id": sched.ID})
+				continue
+			case sched.Overlap == OverlapCancelPrevious:
+				run.replaced = true
+				run.cancel()
+				s.logger.Info("schedule canceling previous run (overlap)", map[string]any{"id": sched.ID})
+			default:
+				s.logger.Info("schedule skipped (overlap)", map[string]any{"id": sched.ID})
+				if s.audit != nil {
+					s.audit(AuditScheduleSkip, sched.ID, map[string]any{"reason": "overlap"})
+				}
+				_ = s.store.RecordRun(ctx, HistoryEntry{
+					Timestamp:  now,
+					ScheduleID: sched.ID,
+					Status:     "skipped",
+				})
+				continue
 			}
-			_ = s.store.RecordRun(ctx, HistoryEntry{
-				Timestamp:  now,
-				ScheduleID: sched.ID,
-				Status:     "skipped",
-			})
-			continue
 		}
 
 		// Fire the schedule.
-		s.running[sched.ID] = true
-		schedCopy := sched
-		go s.fire(ctx, schedCopy, now)
+		s.start(ctx, sched, now)
 	}
 }
 
-func (s *Scheduler) fire(ctx context.Context, sched Schedule, fireTime time.Time) {
+// start registers a run of sched and fires it. Callers hold s.mu.
+func (s *Scheduler) start(ctx context.Context, sched Schedule, fireTime time.Time) {
+	runCtx, cancel := context.WithCancel(ctx)
+	run := &activeRun{cancel: cancel}
+	s.running[sched.ID] = run
+	go s.fire(ctx, runCtx, run, sched, fireTime)
+}
+
+// fire dispatches one run of sched under runCtx, bounded by the
+// schedule's MaxRuntime, and records the outcome. parent is the
+// scheduler's context, used to start a queued follow-up run.
+func (s *Scheduler) fire(parent, ctx context.Context, run *activeRun, sched Schedule, fireTime time.Time) {
+	defer run.cancel()
 	start := time.Now()
 
 	// Open schedule.fire around the dispatch so the runner's
@@ -210,19 +254,17 @@ func (s *Scheduler) fire(ctx context.Context, sched Schedule, fireTime time.Time
 		"task": sched.Task,
 	})
 
-	err := s.dispatch(ctx, sched)
+	status, err := s.dispatchRun(ctx, run, sched)
 	duration := time.Since(start)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 	}
 
-	status := "completed"
 	errStr := ""
 	if err != nil {
-		status = "error"
 		errStr = err.Error()
 		s.logger.Error("scheduled task failed", map[string]any{
-			"id": sched.ID, "error": err.Error(), "duration": duration.String(),
+			"id": sched.ID, "status": status, "error": err.Error(), "duration": duration.String(),
 		})
 	} else {
 		s.logger.Info("scheduled task completed", map[string]any{
@@ -237,7 +279,9 @@ func (s *Scheduler) fire(ctx context.Context, sched Schedule, fireTime time.Time
 		})
 	}
 
-	// Update schedule state.
+	// Update schedule state. The run's context may be canceled by now;
+	// the bookkeeping must land anyway.
+	ctx = context.WithoutCancel(ctx)
 	sched.LastRun = fireTime
 	sched.LastStatus = status
 	sched.RunCount++
@@ -256,8 +300,55 @@ func (s *Scheduler) fire(ctx context.Context, sched Schedule, fireTime time.Time
 		Error:      errStr,
 	})
 
-	// Clear running flag.
+	// Clear the running entry unless a newer run replaced it, and start
+	// the queued run, if any, from the schedule's current state.
 	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[sched.ID] != run {
+		return
+	}
 	delete(s.running, sched.ID)
+	if !run.queued || parent.Err() != nil {
+		return
+	}
+	cur, err := s.store.Get(parent, sched.ID)
+	if err != nil || cur == nil || !cur.Enabled {
+		return
+	}
+	s.start(parent, *cur, time.Now().UTC())
+}
+
+// dispatchRun runs the dispatcher and classifies the outcome:
+// "completed", "error", "timeout" (over MaxRuntime) or "canceled"
+// (replaced by a newer run). A dispatcher that ignores its context is
+// abandoned once the run is over time or canceled, so it cannot hold
+// the schedule's run slot forever.
+func (s *Scheduler) dispatchRun(ctx context.Context, run *activeRun, sched Schedule) (string, error) {
+	if sched.MaxRuntime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sched.MaxRuntime)
+		defer cancel()
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.dispatch(ctx, sched) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err == nil {
+		return "completed", nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "timeout", fmt.Errorf("exceeded max_runtime %s: %w", sched.MaxRuntime, err)
+	}
+	s.mu.Lock()
+	replaced := run.replaced
 	s.mu.Unlock()
+	if replaced {
+		return "canceled", fmt.Errorf("canceled by a newer run (overlap: cancel-previous): %w", err)
+	}
+	return "error", err
 }
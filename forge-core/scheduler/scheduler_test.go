@@ -150,6 +150,51 @@ func TestScheduler_DisabledSchedule(t *testing.T) {
 	}
 }
 
+func TestScheduler_FollowerDoesNotFire(t *testing.T) {
+	store := newMockStore()
+	var fired []string
+	var mu sync.Mutex
+	dispatch := func(_ context.Context, sched Schedule) error {
+		mu.Lock()
+		fired = append(fired, sched.ID)
+		mu.Unlock()
+		return nil
+	}
+
+	store.schedules["due-1"] = Schedule{
+		ID:      "due-1",
+		Cron:    "* * * * *",
+		Task:    "do something",
+		Source:  "llm",
+		Enabled: true,
+		Created: time.Now().UTC().Add(-10 * time.Minute),
+		LastRun: time.Now().UTC().Add(-5 * time.Minute),
+	}
+
+	ctx := context.Background()
+	leader := false
+	sched := New(store, dispatch, &mockLogger{}, nil)
+	sched.SetLeader(func() bool { return leader })
+	sched.Reload(ctx)
+
+	sched.tick(ctx)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if len(fired) != 0 {
+		t.Fatalf("follower fired %v", fired)
+	}
+	mu.Unlock()
+
+	leader = true
+	sched.tick(ctx)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(fired) != 1 {
+		t.Fatalf("leader fired %v, want [due-1]", fired)
+	}
+}
+
 func TestScheduler_OverlapSkip(t *testing.T) {
 	store := newMockStore()
 	blockCh := make(chan struct{})
@@ -209,6 +254,156 @@ func TestScheduler_OverlapSkip(t *testing.T) {
 	}
 }
 
+func TestScheduler_OverlapQueue(t *testing.T) {
+	store := newMockStore()
+	blockCh := make(chan struct{})
+	var fireCount int
+	var mu sync.Mutex
+
+	dispatch := func(_ context.Context, sched Schedule) error {
+		mu.Lock()
+		fireCount++
+		first := fireCount == 1
+		mu.Unlock()
+		if first {
+			<-blockCh
+		}
+		return nil
+	}
+
+	store.schedules["queue-1"] = Schedule{
+		ID:      "queue-1",
+		Cron:    "* * * * *",
+		Task:    "slow task",
+		Source:  "llm",
+		Enabled: true,
+		Overlap: OverlapQueue,
+		Created: time.Now().UTC().Add(-10 * time.Minute),
+		LastRun: time.Now().UTC().Add(-5 * time.Minute),
+	}
+
+	ctx := context.Background()
+	sched := New(store, dispatch, &mockLogger{}, nil)
+	sched.Reload(ctx)
+
+	// First tick fires; the next two queue a single follow-up run.
+	for range 3 {
+		sched.tick(ctx)
+		time.Sleep(50 * time.Millisecond)
+	}
+	mu.Lock()
+	if fireCount != 1 {
+		t.Fatalf("expected 1 fire while blocked, got %d", fireCount)
+	}
+	mu.Unlock()
+
+	// Releasing the first run starts the queued one.
+	close(blockCh)
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if fireCount != 2 {
+		t.Fatalf("expected the queued run to fire, got %d fires", fireCount)
+	}
+	history, _ := store.History(ctx, "queue-1", 10)
+	for _, h := range history {
+		if h.Status != "completed" {
+			t.Errorf("history entry %+v, want only completed runs", h)
+		}
+	}
+}
+
+func TestScheduler_OverlapCancelPrevious(t *testing.T) {
+	store := newMockStore()
+	var fireCount int
+	var mu sync.Mutex
+
+	dispatch := func(ctx context.Context, sched Schedule) error {
+		mu.Lock()
+		fireCount++
+		mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	store.schedules["replace-1"] = Schedule{
+		ID:      "replace-1",
+		Cron:    "* * * * *",
+		Task:    "slow task",
+		Source:  "llm",
+		Enabled: true,
+		Overlap: OverlapCancelPrevious,
+		Created: time.Now().UTC().Add(-10 * time.Minute),
+		LastRun: time.Now().UTC().Add(-5 * time.Minute),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sched := New(store, dispatch, &mockLogger{}, nil)
+	sched.Reload(ctx)
+
+	sched.tick(ctx)
+	time.Sleep(50 * time.Millisecond)
+	sched.tick(ctx)
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	if fireCount != 2 {
+		t.Fatalf("expected the second tick to start a new run, got %d fires", fireCount)
+	}
+	mu.Unlock()
+
+	history, _ := store.History(ctx, "replace-1", 10)
+	if len(history) != 1 || history[0].Status != "canceled" {
+		t.Fatalf("expected one canceled history entry, got %+v", history)
+	}
+	sched.mu.Lock()
+	defer sched.mu.Unlock()
+	if sched.running["replace-1"] == nil {
+		t.Error("the replacement run should still hold the run slot")
+	}
+}
+
+func TestScheduler_MaxRuntime(t *testing.T) {
+	store := newMockStore()
+	// The dispatcher ignores its context; the run is abandoned anyway.
+	dispatch := func(_ context.Context, sched Schedule) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	store.schedules["slow-1"] = Schedule{
+		ID:         "slow-1",
+		Cron:       "* * * * *",
+		Task:       "runaway task",
+		Source:     "llm",
+		Enabled:    true,
+		MaxRuntime: 50 * time.Millisecond,
+		Created:    time.Now().UTC().Add(-10 * time.Minute),
+		LastRun:    time.Now().UTC().Add(-5 * time.Minute),
+	}
+
+	ctx := context.Background()
+	sched := New(store, dispatch, &mockLogger{}, nil)
+	sched.Reload(ctx)
+	sched.tick(ctx)
+	time.Sleep(200 * time.Millisecond)
+
+	history, _ := store.History(ctx, "slow-1", 10)
+	if len(history) != 1 || history[0].Status != "timeout" {
+		t.Fatalf("expected one timeout history entry, got %+v", history)
+	}
+	if got, _ := store.Get(ctx, "slow-1"); got.LastStatus != "timeout" {
+		t.Errorf("LastStatus = %q, want timeout", got.LastStatus)
+	}
+	sched.mu.Lock()
+	defer sched.mu.Unlock()
+	if len(sched.running) != 0 {
+		t.Error("a timed-out run should release its run slot")
+	}
+}
+
 func TestScheduler_Reload(t *testing.T) {
 	store := newMockStore()
 	dispatch := func(_ context.Context, sched Schedule) error { return nil }
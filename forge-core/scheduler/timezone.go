@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	// Embedded zone data: schedules name IANA zones, and slim container
+	// images often ship without /usr/share/zoneinfo.
+	_ "time/tzdata"
+)
+
+// ParseIn parses a cron expression whose fields are read as wall-clock
+// time in the IANA zone tz ("" or "UTC" = UTC). Interval schedules
+// (@every) are unaffected by the zone.
+func ParseIn(expr, tz string) (ParsedSchedule, error) {
+	ps, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	if tz == "" || tz == "UTC" {
+		return ps, nil
+	}
+	loc, err := LoadTimezone(tz)
+	if err != nil {
+		return nil, err
+	}
+	return &zonedSchedule{ParsedSchedule: ps, loc: loc}, nil
+}
+
+// LoadTimezone loads an IANA zone such as "America/New_York".
+func LoadTimezone(tz string) (*time.Location, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use an IANA name such as America/New_York)", tz)
+	}
+	return loc, nil
+}
+
+// zonedSchedule evaluates a schedule in a fixed location.
+type zonedSchedule struct {
+	ParsedSchedule
+	loc *time.Location
+}
+
+// Next returns the next fire time strictly after 'after', matching the
+// cron fields against the wall clock in the schedule's zone.
+func (z *zonedSchedule) Next(after time.Time) time.Time {
+	return z.ParsedSchedule.Next(after.In(z.loc))
+}
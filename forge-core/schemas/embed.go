@@ -4,3 +4,9 @@ import _ "embed"
 
 //go:embed agentspec.v1.0.schema.json
 var AgentSpecV1Schema []byte
+
+// ForgeConfigV1Schema is the JSON Schema for forge.yaml. It carries the
+// per-field descriptions the UI config editor renders as inline docs.
+//
+//go:embed forgeconfig.v1.schema.json
+var ForgeConfigV1Schema []byte
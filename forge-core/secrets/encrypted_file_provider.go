@@ -1,15 +1,16 @@
 package secrets
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
+	"bytes"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 
 	"golang.org/x/crypto/argon2"
@@ -24,23 +25,40 @@ const (
 	argonKeyLen  = 32
 )
 
-// EncryptedFileProvider stores secrets in an AES-256-GCM encrypted JSON file
-// with Argon2id key derivation.
+// EncryptedFileProvider stores secrets in an AES-256-GCM encrypted JSON file.
 //
-// File format: salt(16) || nonce(12) || AES-GCM-ciphertext
-// Plaintext is JSON: {"key": "value", ...}
+// File format (version 2):
+//
+//	"forge-secrets/v2\n" || len(header) uint32 || header JSON || nonce(12) || AES-GCM-ciphertext
+//
+// The secrets are encrypted under a random data key. The header records
+// the key's ID, the encryption context the file is bound to, and the data
+// key wrapped for a passphrase (Argon2id) and/or public-key recipients;
+// it is authenticated as additional data. Plaintext is JSON:
+// {"key": "value", ...}
+//
+// Version 1 files (salt(16) || nonce(12) || AES-GCM-ciphertext, keyed
+// directly by the passphrase) are still read, and are rewritten as
+// version 2 on the next write.
 type EncryptedFileProvider struct {
 	path       string
 	passphrase func() (string, error) // callback to obtain the passphrase
-
-	mu     sync.Mutex
-	cache  map[string]string // in-memory cache after first decrypt
-	loaded bool
+	context    string
+	identities []Identity
+	recipients []Recipient
+
+	mu      sync.Mutex
+	cache   map[string]string // in-memory cache after first decrypt
+	loaded  bool
+	header  *fileHeader // nil until a version 2 file is loaded or written
+	fileKey []byte
+	v1Pass  string // passphrase that opened a version 1 file
 }
 
 // NewEncryptedFileProvider creates a provider that reads/writes an encrypted
 // secrets file at path. The passphrase callback is invoked lazily on first
-// access, keeping the core package free of terminal I/O.
+// access, keeping the core package free of terminal I/O. It is not called
+// when an identity set with SetIdentities opens the file.
 func NewEncryptedFileProvider(path string, passphrase func() (string, error)) *EncryptedFileProvider {
 	return &EncryptedFileProvider{
 		path:       path,
@@ -48,6 +66,31 @@ func NewEncryptedFileProvider(path string, passphrase func() (string, error)) *E
 	}
 }
 
+// SetContext sets the encryption context the file must be bound to, e.g.
+// "agent:support-bot". Opening a file bound to another context fails, so
+// one agent's secrets file cannot be swapped in for another's. A file
+// with no context is bound to this one on its next write.
+func (p *EncryptedFileProvider) SetContext(context string) { p.context = context }
+
+// AgentContext returns the encryption context of an agent's secrets
+// file: "agent:<id>", or "agent:<id>/profile:<profile>" for the file
+// holding one profile's secrets.
+func AgentContext(agentID, profile string) string {
+	if profile == "" {
+		return "agent:" + agentID
+	}
+	return "agent:" + agentID + "/profile:" + profile
+}
+
+// SetIdentities sets the private keys tried, before the passphrase, to
+// open the file.
+func (p *EncryptedFileProvider) SetIdentities(ids ...Identity) { p.identities = ids }
+
+// SetRecipients sets the public keys a new file's data key is wrapped
+// for instead of a passphrase. Existing files keep their wrappings until
+// Rotate.
+func (p *EncryptedFileProvider) SetRecipients(rs ...Recipient) { p.recipients = rs }
+
 func (p *EncryptedFileProvider) Name() string { return "encrypted-file" }
 
 // Get returns the secret for key, decrypting the file on first access.
@@ -142,14 +185,9 @@ func (p *EncryptedFileProvider) ensureLoaded() error {
 		return fmt.Errorf("reading secrets file: %w", err)
 	}
 
-	pass, err := p.passphrase()
-	if err != nil {
-		return fmt.Errorf("obtaining passphrase: %w", err)
-	}
-
-	plaintext, err := decrypt(data, pass)
+	plaintext, err := p.open(data)
 	if err != nil {
-		return fmt.Errorf("decrypting secrets file: %w", err)
+		return err
 	}
 
 	m := make(map[string]string)
@@ -162,12 +200,136 @@ func (p *EncryptedFileProvider) ensureLoaded() error {
 	return nil
 }
 
-// flush encrypts the cache and writes it atomically.
-// Caller must hold p.mu.
-func (p *EncryptedFileProvider) flush() error {
+// open decrypts a version 2 file with the first identity that matches
+// one of its recipients, else the passphrase, or a version 1 file with
+// the passphrase. Caller must hold p.mu.
+func (p *EncryptedFileProvider) open(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, fileMagic) {
+		pass, err := p.getPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := decrypt(data, pass)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting secrets file: %w", err)
+		}
+		p.v1Pass = pass
+		return plaintext, nil
+	}
+
+	hdr, aad, nonce, ciphertext, err := parseFile(data)
+	if err != nil {
+		return nil, err
+	}
+	if hdr.Context != "" && p.context != "" && hdr.Context != p.context {
+		return nil, fmt.Errorf("secrets file %s is bound to %q, not %q", p.path, hdr.Context, p.context)
+	}
+	key, err := p.unwrap(hdr)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := openFile(key, aad, nonce, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secrets file: %w", err)
+	}
+	p.header, p.fileKey = hdr, key
+	return plaintext, nil
+}
+
+// unwrap recovers the data key from the header's stanzas.
+func (p *EncryptedFileProvider) unwrap(hdr *fileHeader) ([]byte, error) {
+	var pass *stanza
+	var recipients []string
+	for _, s := range hdr.Stanzas {
+		if s.Type == stanzaPassphrase {
+			pass = s
+			continue
+		}
+		recipients = append(recipients, s.Recipient)
+		for _, id := range p.identities {
+			key, err := id.unwrap(s)
+			if errors.Is(err, errIdentityMismatch) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unwrapping data key for %s: %w", s.Recipient, err)
+			}
+			return key, nil
+		}
+	}
+	if pass == nil {
+		return nil, fmt.Errorf("no identity matches a recipient of secrets file %s (recipients: %s)",
+			p.path, strings.Join(recipients, ", "))
+	}
+	passphrase, err := p.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := unwrapPassphrase(pass, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secrets file: %w", err)
+	}
+	return key, nil
+}
+
+func (p *EncryptedFileProvider) getPassphrase() (string, error) {
+	if p.passphrase == nil {
+		return "", errors.New("obtaining passphrase: no passphrase configured")
+	}
 	pass, err := p.passphrase()
 	if err != nil {
-		return fmt.Errorf("obtaining passphrase: %w", err)
+		return "", fmt.Errorf("obtaining passphrase: %w", err)
+	}
+	return pass, nil
+}
+
+// Rotate re-encrypts the file under a new data key wrapped for the
+// passphrase, when non-empty, and each recipient, replacing every
+// previous wrapping. It returns the new key ID.
+func (p *EncryptedFileProvider) Rotate(passphrase string, recipients []Recipient) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureLoaded(); err != nil {
+		return "", err
+	}
+	context := p.context
+	if p.header != nil && context == "" {
+		context = p.header.Context
+	}
+	hdr, key, err := newFileHeader(context, passphrase, recipients)
+	if err != nil {
+		return "", err
+	}
+	p.header, p.fileKey = hdr, key
+	if err := p.flush(); err != nil {
+		return "", err
+	}
+	return hdr.KeyID, nil
+}
+
+// flush encrypts the cache and writes it atomically, reusing the file's
+// data key and wrappings. A new file's key is wrapped for the recipients
+// set with SetRecipients, or else the passphrase; a version 1 file's for
+// the passphrase that opened it and those recipients.
+// Caller must hold p.mu.
+func (p *EncryptedFileProvider) flush() error {
+	if p.header == nil {
+		pass := p.v1Pass
+		if pass == "" && len(p.recipients) == 0 {
+			var err error
+			if pass, err = p.getPassphrase(); err != nil {
+				return err
+			}
+		}
+		hdr, key, err := newFileHeader(p.context, pass, p.recipients)
+		if err != nil {
+			return err
+		}
+		p.header, p.fileKey, p.v1Pass = hdr, key, ""
+	}
+	if p.header.Context == "" {
+		p.header.Context = p.context
 	}
 
 	plaintext, err := json.Marshal(p.cache)
@@ -175,7 +337,7 @@ func (p *EncryptedFileProvider) flush() error {
 		return fmt.Errorf("marshalling secrets: %w", err)
 	}
 
-	ciphertext, err := encrypt(plaintext, pass)
+	ciphertext, err := sealFile(p.header, p.fileKey, plaintext)
 	if err != nil {
 		return err
 	}
@@ -223,23 +385,16 @@ func deriveKey(passphrase string, salt []byte) []byte {
 	return argon2.IDKey([]byte(passphrase), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
 }
 
-// encrypt produces: salt(16) || nonce(12) || AES-GCM-ciphertext
+// encrypt produces a version 1 file: salt(16) || nonce(12) || AES-GCM-ciphertext
 func encrypt(plaintext []byte, passphrase string) ([]byte, error) {
 	salt := make([]byte, saltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return nil, fmt.Errorf("generating salt: %w", err)
 	}
 
-	key := deriveKey(passphrase, salt)
-
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, fmt.Errorf("creating cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := newGCM(deriveKey(passphrase, salt))
 	if err != nil {
-		return nil, fmt.Errorf("creating GCM: %w", err)
+		return nil, err
 	}
 
 	nonce := make([]byte, nonceLen)
@@ -257,7 +412,7 @@ func encrypt(plaintext []byte, passphrase string) ([]byte, error) {
 	return result, nil
 }
 
-// decrypt parses: salt(16) || nonce(12) || AES-GCM-ciphertext
+// decrypt parses a version 1 file: salt(16) || nonce(12) || AES-GCM-ciphertext
 func decrypt(data []byte, passphrase string) ([]byte, error) {
 	minLen := saltLen + nonceLen + 1 // at least 1 byte of ciphertext
 	if len(data) < minLen {
@@ -268,16 +423,9 @@ func decrypt(data []byte, passphrase string) ([]byte, error) {
 	nonce := data[saltLen : saltLen+nonceLen]
 	ciphertext := data[saltLen+nonceLen:]
 
-	key := deriveKey(passphrase, salt)
-
-	block, err := aes.NewCipher(key)
+	gcm, err := newGCM(deriveKey(passphrase, salt))
 	if err != nil {
-		return nil, fmt.Errorf("creating cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, fmt.Errorf("creating GCM: %w", err)
+		return nil, err
 	}
 
 	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
@@ -1,8 +1,10 @@
 package secrets
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -246,3 +248,124 @@ func TestEncryptedFileProvider_Name(t *testing.T) {
 		t.Fatalf("expected 'encrypted-file', got %q", p.Name())
 	}
 }
+
+func TestEncryptedFileProvider_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	pass := func() (string, error) { return "old-passphrase", nil }
+
+	p := NewEncryptedFileProvider(path, pass)
+	if err := p.Set("KEY", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	before, err := ReadFileInfo(path)
+	if err != nil {
+		t.Fatalf("ReadFileInfo: %v", err)
+	}
+
+	keyID, err := p.Rotate("new-passphrase", nil)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if keyID == before.KeyID {
+		t.Fatalf("key ID unchanged after rotation: %s", keyID)
+	}
+	after, err := ReadFileInfo(path)
+	if err != nil {
+		t.Fatalf("ReadFileInfo: %v", err)
+	}
+	if after.Version != 2 || after.KeyID != keyID || !after.Passphrase {
+		t.Fatalf("info after rotation = %+v", after)
+	}
+
+	if _, err := NewEncryptedFileProvider(path, pass).Get("KEY"); err == nil {
+		t.Fatal("old passphrase still opens the rotated file")
+	}
+	newPass := func() (string, error) { return "new-passphrase", nil }
+	if v, err := NewEncryptedFileProvider(path, newPass).Get("KEY"); err != nil || v != "value" {
+		t.Fatalf("Get with new passphrase = %q, %v", v, err)
+	}
+
+	if _, err := p.Rotate("", nil); err == nil {
+		t.Fatal("expected an error rotating to no passphrase and no recipients")
+	}
+}
+
+func TestEncryptedFileProvider_Context(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	pass := func() (string, error) { return "test-passphrase", nil }
+
+	p := NewEncryptedFileProvider(path, pass)
+	p.SetContext("agent:billing")
+	if err := p.Set("KEY", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	other := NewEncryptedFileProvider(path, pass)
+	other.SetContext("agent:support")
+	if _, err := other.Get("KEY"); err == nil || !strings.Contains(err.Error(), `bound to "agent:billing"`) {
+		t.Fatalf("Get under another context: err = %v", err)
+	}
+
+	// A provider that expects no context reads any file.
+	if v, err := NewEncryptedFileProvider(path, pass).Get("KEY"); err != nil || v != "value" {
+		t.Fatalf("Get without context = %q, %v", v, err)
+	}
+}
+
+func TestEncryptedFileProvider_TamperedHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	pass := func() (string, error) { return "test-passphrase", nil }
+
+	p := NewEncryptedFileProvider(path, pass)
+	p.SetContext("agent:billing")
+	if err := p.Set("KEY", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// Rebinding the file by editing its cleartext header must fail.
+	data, _ := os.ReadFile(path)
+	data = bytes.Replace(data, []byte("agent:billing"), []byte("agent:support"), 1)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	q := NewEncryptedFileProvider(path, pass)
+	q.SetContext("agent:support")
+	if _, err := q.Get("KEY"); err == nil {
+		t.Fatal("expected an error opening a file with an altered header")
+	}
+}
+
+func TestEncryptedFileProvider_MigratesVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	data, err := encrypt([]byte(`{"KEY":"value"}`), "test-passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := ReadFileInfo(path); err != nil || info.Version != 1 {
+		t.Fatalf("ReadFileInfo = %+v, %v; want version 1", info, err)
+	}
+
+	calls := 0
+	pass := func() (string, error) { calls++; return "test-passphrase", nil }
+	p := NewEncryptedFileProvider(path, pass)
+	if v, err := p.Get("KEY"); err != nil || v != "value" {
+		t.Fatalf("Get = %q, %v", v, err)
+	}
+	if err := p.Set("OTHER", "x"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("passphrase asked %d times, want 1", calls)
+	}
+
+	info, err := ReadFileInfo(path)
+	if err != nil || info.Version != 2 || !info.Passphrase || info.KeyID == "" {
+		t.Fatalf("ReadFileInfo after write = %+v, %v", info, err)
+	}
+	if v, err := NewEncryptedFileProvider(path, pass).Get("KEY"); err != nil || v != "value" {
+		t.Fatalf("Get after migration = %q, %v", v, err)
+	}
+}
@@ -0,0 +1,205 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// fileMagic starts a version 2 secrets file. Version 1 files have no
+// header: they are salt(16) || nonce(12) || AES-GCM-ciphertext under a
+// passphrase-derived key.
+var fileMagic = []byte("forge-secrets/v2\n")
+
+const (
+	fileKeyLen = 32
+	keyIDLen   = 8 // bytes, rendered as 16 hex digits
+	maxHeader  = 1 << 20
+)
+
+// fileHeader is the cleartext header of a version 2 secrets file. The
+// secrets are encrypted under a random data key; the header records the
+// key's ID and every wrapping of it, so rotating or adding a recipient
+// rewrites the header without asking for the data key's other holders.
+type fileHeader struct {
+	KeyID string `json:"key_id"`
+	// Context binds the file to the agent (and profile) it was written
+	// for; a provider expecting another context refuses to open it.
+	Context   string    `json:"context,omitempty"`
+	RotatedAt time.Time `json:"rotated_at"`
+	Stanzas   []*stanza `json:"stanzas"`
+}
+
+// FileInfo describes an encrypted secrets file's key material. It is
+// read from the cleartext header, so no passphrase or identity is needed.
+type FileInfo struct {
+	Version    int       // 1 for legacy passphrase-only files, 2 otherwise
+	KeyID      string    // empty for version 1
+	Context    string    // empty when the file is not bound to an agent
+	RotatedAt  time.Time // zero for version 1
+	Passphrase bool      // whether the passphrase opens the file
+	Recipients []string  // public keys that open the file
+}
+
+// ReadFileInfo returns the key metadata of the secrets file at path.
+func ReadFileInfo(path string) (*FileInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, fileMagic) {
+		return &FileInfo{Version: 1, Passphrase: true}, nil
+	}
+	hdr, _, _, _, err := parseFile(data)
+	if err != nil {
+		return nil, err
+	}
+	info := &FileInfo{Version: 2, KeyID: hdr.KeyID, Context: hdr.Context, RotatedAt: hdr.RotatedAt}
+	for _, s := range hdr.Stanzas {
+		if s.Type == stanzaPassphrase {
+			info.Passphrase = true
+		} else {
+			info.Recipients = append(info.Recipients, s.Recipient)
+		}
+	}
+	return info, nil
+}
+
+// newFileHeader generates a data key and a header wrapping it for the
+// passphrase, when non-empty, and each recipient.
+func newFileHeader(context, passphrase string, recipients []Recipient) (*fileHeader, []byte, error) {
+	if passphrase == "" && len(recipients) == 0 {
+		return nil, nil, errors.New("a passphrase or at least one recipient is required")
+	}
+	key := make([]byte, fileKeyLen)
+	id := make([]byte, keyIDLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, fmt.Errorf("generating data key: %w", err)
+	}
+	if _, err := rand.Read(id); err != nil {
+		return nil, nil, fmt.Errorf("generating key ID: %w", err)
+	}
+	hdr := &fileHeader{
+		KeyID:     hex.EncodeToString(id),
+		Context:   context,
+		RotatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if passphrase != "" {
+		s, err := wrapPassphrase(passphrase, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		hdr.Stanzas = append(hdr.Stanzas, s)
+	}
+	for _, r := range recipients {
+		s, err := r.wrap(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		hdr.Stanzas = append(hdr.Stanzas, s)
+	}
+	return hdr, key, nil
+}
+
+// wrapPassphrase wraps the data key under an Argon2id-derived key. The
+// nonce can be zero: every stanza has a fresh salt.
+func wrapPassphrase(passphrase string, fileKey []byte) (*stanza, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	gcm, err := newGCM(deriveKey(passphrase, salt))
+	if err != nil {
+		return nil, err
+	}
+	body := gcm.Seal(nil, make([]byte, nonceLen), fileKey, nil)
+	return &stanza{Type: stanzaPassphrase, Salt: salt, Body: body}, nil
+}
+
+func unwrapPassphrase(s *stanza, passphrase string) ([]byte, error) {
+	gcm, err := newGCM(deriveKey(passphrase, s.Salt))
+	if err != nil {
+		return nil, err
+	}
+	key, err := gcm.Open(nil, make([]byte, nonceLen), s.Body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed (wrong passphrase?): %w", err)
+	}
+	return key, nil
+}
+
+// sealFile produces: magic || len(header) uint32 || header JSON || nonce(12)
+// || AES-GCM-ciphertext. Everything before the nonce is authenticated as
+// additional data, so the header cannot be altered without detection.
+func sealFile(hdr *fileHeader, fileKey, plaintext []byte) ([]byte, error) {
+	h, err := json.Marshal(hdr)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling header: %w", err)
+	}
+	gcm, err := newGCM(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	out := make([]byte, 0, len(fileMagic)+4+len(h)+nonceLen+len(plaintext)+gcm.Overhead())
+	out = append(out, fileMagic...)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(h)))
+	out = append(out, h...)
+	aad := out
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, aad), nil
+}
+
+// parseFile splits a version 2 file into its header, the authenticated
+// header bytes, nonce and ciphertext.
+func parseFile(data []byte) (hdr *fileHeader, aad, nonce, ciphertext []byte, err error) {
+	rest := data[len(fileMagic):]
+	if len(rest) < 4 {
+		return nil, nil, nil, nil, errors.New("secrets file header truncated")
+	}
+	n := binary.BigEndian.Uint32(rest)
+	if n > maxHeader || int(n) > len(rest)-4-nonceLen {
+		return nil, nil, nil, nil, errors.New("secrets file header truncated")
+	}
+	end := len(fileMagic) + 4 + int(n)
+	hdr = &fileHeader{}
+	if err := json.Unmarshal(data[len(fileMagic)+4:end], hdr); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("parsing secrets file header: %w", err)
+	}
+	return hdr, data[:end], data[end : end+nonceLen], data[end+nonceLen:], nil
+}
+
+func openFile(fileKey, aad, nonce, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed (file corrupted or header altered): %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating GCM: %w", err)
+	}
+	return gcm, nil
+}
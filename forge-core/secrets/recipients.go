@@ -0,0 +1,373 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/ssh"
+)
+
+// Stanza types: the ways a secrets file's data key can be wrapped.
+const (
+	stanzaPassphrase = "passphrase"
+	stanzaX25519     = "x25519"
+	stanzaSSHEd25519 = "ssh-ed25519"
+	stanzaSSHRSA     = "ssh-rsa"
+)
+
+// errIdentityMismatch is returned by Identity.unwrap for a stanza that
+// was not wrapped for it.
+var errIdentityMismatch = errors.New("stanza is not for this identity")
+
+// stanza is one wrapping of a secrets file's data key, recorded in the
+// file header.
+type stanza struct {
+	Type string `json:"type"`
+	// Recipient is who a public-key stanza is for, in the form
+	// ParseRecipient accepts. Empty for the passphrase stanza.
+	Recipient string `json:"recipient,omitempty"`
+	Salt      []byte `json:"salt,omitempty"`      // passphrase: Argon2id salt
+	Ephemeral []byte `json:"ephemeral,omitempty"` // x25519, ssh-ed25519: ephemeral share
+	Body      []byte `json:"body"`
+}
+
+// Recipient is a public key a secrets file's data key is wrapped for,
+// so that whoever holds the matching Identity can open the file without
+// the passphrase. Recipients let a team share one file, each member
+// with their own key.
+type Recipient interface {
+	// String returns the recipient in the form ParseRecipient accepts.
+	String() string
+	wrap(fileKey []byte) (*stanza, error)
+}
+
+// Identity is a private key that opens files wrapped for its Recipient.
+type Identity interface {
+	Recipient() Recipient
+	unwrap(s *stanza) ([]byte, error)
+}
+
+// ParseRecipient parses an age X25519 recipient ("age1…", as printed by
+// age-keygen or `forge secret keygen`) or an SSH public key in
+// authorized_keys form ("ssh-ed25519 AAAA… comment" or "ssh-rsa …").
+func ParseRecipient(s string) (Recipient, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "age1") {
+		hrp, key, err := bech32Decode(s)
+		if err != nil || hrp != "age" || len(key) != curve25519.PointSize {
+			return nil, fmt.Errorf("invalid age recipient %q", s)
+		}
+		return &x25519Recipient{pub: key}, nil
+	}
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(s))
+	if err != nil {
+		return nil, fmt.Errorf("recipient %q is neither an age recipient nor an SSH public key", s)
+	}
+	return sshRecipient(pub)
+}
+
+func sshRecipient(pub ssh.PublicKey) (Recipient, error) {
+	ck, ok := pub.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported SSH key type %s", pub.Type())
+	}
+	switch k := ck.CryptoPublicKey().(type) {
+	case ed25519.PublicKey:
+		u, err := ed25519PublicToX25519(k)
+		if err != nil {
+			return nil, err
+		}
+		return &sshEd25519Recipient{ssh: pub, pub: u}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("ssh-rsa recipient has %d bits, want at least 2048", k.N.BitLen())
+		}
+		return &sshRSARecipient{ssh: pub, pub: k}, nil
+	}
+	return nil, fmt.Errorf("unsupported SSH key type %s (use ssh-ed25519 or ssh-rsa)", pub.Type())
+}
+
+// IdentityEnv names the environment variable holding the path of an
+// identity file, which opens encrypted secrets files wrapped for its
+// recipient without the passphrase.
+const IdentityEnv = "FORGE_SECRETS_IDENTITY"
+
+// ReadIdentityFile parses the identity file at path.
+func ReadIdentityFile(path string) ([]Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading identity file: %w", err)
+	}
+	ids, err := ParseIdentities(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ids, nil
+}
+
+// ParseIdentities parses an identity file: either age X25519 secret keys
+// ("AGE-SECRET-KEY-1…", one per line, # comments allowed) or an
+// unencrypted OpenSSH ed25519 or RSA private key.
+func ParseIdentities(data []byte) ([]Identity, error) {
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		key, err := ssh.ParseRawPrivateKey(data)
+		if err != nil {
+			var missing *ssh.PassphraseMissingError
+			if errors.As(err, &missing) {
+				return nil, errors.New("passphrase-protected SSH keys are not supported; use an age identity or an unencrypted key")
+			}
+			return nil, fmt.Errorf("parsing SSH private key: %w", err)
+		}
+		id, err := sshIdentity(key)
+		if err != nil {
+			return nil, err
+		}
+		return []Identity{id}, nil
+	}
+	var ids []Identity
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hrp, key, err := bech32Decode(line)
+		if err != nil || hrp != "age-secret-key-" || len(key) != curve25519.ScalarSize {
+			return nil, errors.New("identity file holds neither age secret keys nor an SSH private key")
+		}
+		ids = append(ids, newX25519Identity(key))
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("identity file is empty")
+	}
+	return ids, nil
+}
+
+func sshIdentity(key any) (Identity, error) {
+	switch k := key.(type) {
+	case *ed25519.PrivateKey:
+		return sshIdentity(*k)
+	case ed25519.PrivateKey:
+		pub, err := ssh.NewPublicKey(k.Public())
+		if err != nil {
+			return nil, err
+		}
+		r, err := sshRecipient(pub)
+		if err != nil {
+			return nil, err
+		}
+		h := sha512.Sum512(k.Seed())
+		return &sshEd25519Identity{recipient: r.(*sshEd25519Recipient), scalar: h[:curve25519.ScalarSize]}, nil
+	case *rsa.PrivateKey:
+		pub, err := ssh.NewPublicKey(&k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		r, err := sshRecipient(pub)
+		if err != nil {
+			return nil, err
+		}
+		return &sshRSAIdentity{recipient: r.(*sshRSARecipient), key: k}, nil
+	}
+	return nil, fmt.Errorf("unsupported SSH private key type %T (use ed25519 or RSA)", key)
+}
+
+// GenerateX25519Identity returns a new age-compatible identity
+// ("AGE-SECRET-KEY-1…") and its recipient ("age1…").
+func GenerateX25519Identity() (identity, recipient string, err error) {
+	scalar := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(scalar); err != nil {
+		return "", "", fmt.Errorf("generating key: %w", err)
+	}
+	id := newX25519Identity(scalar)
+	identity, err = bech32Encode("AGE-SECRET-KEY-", scalar)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.ToUpper(identity), id.Recipient().String(), nil
+}
+
+// x25519Recipient wraps the data key to an X25519 public key: an
+// ephemeral key agreement, HKDF-SHA256 over the shared secret, and
+// ChaCha20-Poly1305 over the data key.
+type x25519Recipient struct {
+	pub []byte
+}
+
+func (r *x25519Recipient) String() string {
+	s, _ := bech32Encode("age", r.pub)
+	return s
+}
+
+func (r *x25519Recipient) wrap(fileKey []byte) (*stanza, error) {
+	return wrapX25519(stanzaX25519, r.String(), r.pub, fileKey)
+}
+
+type x25519Identity struct {
+	scalar    []byte
+	recipient *x25519Recipient
+}
+
+func newX25519Identity(scalar []byte) *x25519Identity {
+	pub, _ := curve25519.X25519(scalar, curve25519.Basepoint)
+	return &x25519Identity{scalar: scalar, recipient: &x25519Recipient{pub: pub}}
+}
+
+func (i *x25519Identity) Recipient() Recipient { return i.recipient }
+
+func (i *x25519Identity) unwrap(s *stanza) ([]byte, error) {
+	if s.Type != stanzaX25519 || s.Recipient != i.recipient.String() {
+		return nil, errIdentityMismatch
+	}
+	return unwrapX25519(s, i.scalar, i.recipient.pub)
+}
+
+// sshEd25519Recipient wraps like x25519Recipient, to the X25519 form of
+// an Ed25519 SSH key.
+type sshEd25519Recipient struct {
+	ssh ssh.PublicKey
+	pub []byte // X25519 form
+}
+
+func (r *sshEd25519Recipient) String() string { return marshalSSHKey(r.ssh) }
+
+func (r *sshEd25519Recipient) wrap(fileKey []byte) (*stanza, error) {
+	return wrapX25519(stanzaSSHEd25519, r.String(), r.pub, fileKey)
+}
+
+type sshEd25519Identity struct {
+	recipient *sshEd25519Recipient
+	scalar    []byte
+}
+
+func (i *sshEd25519Identity) Recipient() Recipient { return i.recipient }
+
+func (i *sshEd25519Identity) unwrap(s *stanza) ([]byte, error) {
+	if s.Type != stanzaSSHEd25519 || s.Recipient != i.recipient.String() {
+		return nil, errIdentityMismatch
+	}
+	return unwrapX25519(s, i.scalar, i.recipient.pub)
+}
+
+// sshRSARecipient wraps the data key with RSA-OAEP-SHA256.
+type sshRSARecipient struct {
+	ssh ssh.PublicKey
+	pub *rsa.PublicKey
+}
+
+func (r *sshRSARecipient) String() string { return marshalSSHKey(r.ssh) }
+
+func (r *sshRSARecipient) wrap(fileKey []byte) (*stanza, error) {
+	body, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, r.pub, fileKey, []byte(rsaLabel))
+	if err != nil {
+		return nil, fmt.Errorf("wrapping key for %s: %w", r, err)
+	}
+	return &stanza{Type: stanzaSSHRSA, Recipient: r.String(), Body: body}, nil
+}
+
+type sshRSAIdentity struct {
+	recipient *sshRSARecipient
+	key       *rsa.PrivateKey
+}
+
+func (i *sshRSAIdentity) Recipient() Recipient { return i.recipient }
+
+func (i *sshRSAIdentity) unwrap(s *stanza) ([]byte, error) {
+	if s.Type != stanzaSSHRSA || s.Recipient != i.recipient.String() {
+		return nil, errIdentityMismatch
+	}
+	return rsa.DecryptOAEP(sha256.New(), nil, i.key, s.Body, []byte(rsaLabel))
+}
+
+const (
+	x25519Label = "forge-secrets/v2/"
+	rsaLabel    = "forge-secrets/v2/ssh-rsa"
+)
+
+// marshalSSHKey renders pub in authorized_keys form without a comment,
+// so stanzas don't record key owners' email addresses.
+func marshalSSHKey(pub ssh.PublicKey) string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+}
+
+func wrapX25519(typ, recipient string, pub, fileKey []byte) (*stanza, error) {
+	eph := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(eph); err != nil {
+		return nil, fmt.Errorf("generating ephemeral key: %w", err)
+	}
+	ephPub, err := curve25519.X25519(eph, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := curve25519.X25519(eph, pub)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping key for %s: %w", recipient, err)
+	}
+	aead, err := x25519AEAD(typ, shared, ephPub, pub)
+	if err != nil {
+		return nil, err
+	}
+	body := aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), fileKey, nil)
+	return &stanza{Type: typ, Recipient: recipient, Ephemeral: ephPub, Body: body}, nil
+}
+
+func unwrapX25519(s *stanza, scalar, pub []byte) ([]byte, error) {
+	shared, err := curve25519.X25519(scalar, s.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := x25519AEAD(s.Type, shared, s.Ephemeral, pub)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), s.Body, nil)
+}
+
+// x25519AEAD derives the one-use wrapping key for an X25519 stanza. The
+// nonce can be zero: every stanza has a fresh ephemeral key.
+func x25519AEAD(typ string, shared, ephPub, pub []byte) (cipher.AEAD, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	kdf := hkdf.New(sha256.New, shared, slices.Concat(ephPub, pub), []byte(x25519Label+typ))
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.New(key)
+}
+
+// curve25519P is the field prime 2^255 - 19.
+var curve25519P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// ed25519PublicToX25519 maps an Ed25519 public key to the X25519 public
+// key of the same secret (RFC 7748 birational map, u = (1+y)/(1-y)).
+func ed25519PublicToX25519(pub ed25519.PublicKey) ([]byte, error) {
+	le := slices.Clone([]byte(pub))
+	le[31] &= 0x7f
+	slices.Reverse(le)
+	y := new(big.Int).SetBytes(le)
+	one := big.NewInt(1)
+	den := new(big.Int).Sub(one, y)
+	den.Mod(den, curve25519P)
+	if den.Sign() == 0 {
+		return nil, errors.New("invalid ed25519 public key")
+	}
+	u := new(big.Int).Add(one, y)
+	u.Mul(u, new(big.Int).ModInverse(den, curve25519P))
+	u.Mod(u, curve25519P)
+	out := u.FillBytes(make([]byte, curve25519.PointSize))
+	slices.Reverse(out)
+	return out, nil
+}
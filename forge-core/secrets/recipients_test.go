@@ -0,0 +1,156 @@
+package secrets
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// noPassphrase fails the test if the file falls back to the passphrase.
+func noPassphrase(t *testing.T) func() (string, error) {
+	return func() (string, error) {
+		t.Error("passphrase requested although an identity matches")
+		return "", errors.New("no passphrase")
+	}
+}
+
+func sshKeyPair(t *testing.T, key any, pub any) (identity []byte, recipient string) {
+	t.Helper()
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(block), string(ssh.MarshalAuthorizedKey(sshPub))
+}
+
+func TestRecipients_RoundTrip(t *testing.T) {
+	ageID, ageRecipient, err := GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edID, edRecipient := sshKeyPair(t, edKey, edPub)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaID, rsaRecipient := sshKeyPair(t, rsaKey, &rsaKey.PublicKey)
+
+	tests := []struct {
+		name      string
+		identity  []byte
+		recipient string
+	}{
+		{"age", []byte("# created by forge\n" + ageID + "\n"), ageRecipient},
+		{"ssh-ed25519", edID, edRecipient + " alice@example.com"},
+		{"ssh-rsa", rsaID, rsaRecipient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecipient(tt.recipient)
+			if err != nil {
+				t.Fatalf("ParseRecipient: %v", err)
+			}
+			ids, err := ParseIdentities(tt.identity)
+			if err != nil {
+				t.Fatalf("ParseIdentities: %v", err)
+			}
+			if got := ids[0].Recipient().String(); got != r.String() {
+				t.Fatalf("identity's recipient = %q, want %q", got, r.String())
+			}
+			if strings.Contains(r.String(), "alice") {
+				t.Errorf("recipient keeps the key comment: %q", r.String())
+			}
+
+			path := filepath.Join(t.TempDir(), "secrets.enc")
+			p := NewEncryptedFileProvider(path, noPassphrase(t))
+			p.SetRecipients(r)
+			if err := p.Set("KEY", "value"); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+
+			q := NewEncryptedFileProvider(path, noPassphrase(t))
+			q.SetIdentities(ids...)
+			if v, err := q.Get("KEY"); err != nil || v != "value" {
+				t.Fatalf("Get = %q, %v", v, err)
+			}
+			info, err := ReadFileInfo(path)
+			if err != nil || info.Passphrase || len(info.Recipients) != 1 || info.Recipients[0] != r.String() {
+				t.Fatalf("ReadFileInfo = %+v, %v", info, err)
+			}
+		})
+	}
+}
+
+func TestRecipients_TeamSharing(t *testing.T) {
+	aliceID, alice, _ := GenerateX25519Identity()
+	bobID, bob, _ := GenerateX25519Identity()
+	eveID, _, _ := GenerateX25519Identity()
+	ra, _ := ParseRecipient(alice)
+	rb, _ := ParseRecipient(bob)
+
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	pass := func() (string, error) { return "ops-passphrase", nil }
+	p := NewEncryptedFileProvider(path, pass)
+	if err := p.Set("KEY", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := p.Rotate("ops-passphrase", []Recipient{ra, rb}); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	for name, id := range map[string]string{"alice": aliceID, "bob": bobID} {
+		ids, _ := ParseIdentities([]byte(id))
+		q := NewEncryptedFileProvider(path, noPassphrase(t))
+		q.SetIdentities(ids...)
+		if v, err := q.Get("KEY"); err != nil || v != "value" {
+			t.Errorf("%s: Get = %q, %v", name, v, err)
+		}
+	}
+
+	// The passphrase still opens the file; a stranger's key does not.
+	if v, err := NewEncryptedFileProvider(path, pass).Get("KEY"); err != nil || v != "value" {
+		t.Errorf("passphrase: Get = %q, %v", v, err)
+	}
+	if _, err := p.Rotate("", []Recipient{ra}); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	ids, _ := ParseIdentities([]byte(eveID))
+	q := NewEncryptedFileProvider(path, pass)
+	q.SetIdentities(ids...)
+	if _, err := q.Get("KEY"); err == nil || !strings.Contains(err.Error(), "no identity matches") {
+		t.Fatalf("Get with a stranger's identity: err = %v", err)
+	}
+}
+
+func TestParseRecipient_Invalid(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, weakRecipient := sshKeyPair(t, weak, &weak.PublicKey)
+	for _, s := range []string{
+		"",
+		"age1notbech32",
+		"ssh-dss AAAAB3NzaC1kc3MAAACBAP",
+		weakRecipient,
+	} {
+		if _, err := ParseRecipient(s); err == nil {
+			t.Errorf("ParseRecipient(%q) succeeded", s)
+		}
+	}
+}
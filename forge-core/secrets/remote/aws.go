@@ -0,0 +1,75 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/initializ/forge/forge-core/llm/providers"
+)
+
+// awsSource reads an AWS Secrets Manager secret with GetSecretValue,
+// signed with the AWS_* credentials in the environment. The region is
+// the URL's, else AWS_REGION or AWS_DEFAULT_REGION.
+type awsSource struct {
+	raw      string
+	id       string
+	region   string
+	endpoint string // overrides https://secretsmanager.<region>.amazonaws.com/ in tests
+	client   *http.Client
+}
+
+func (s *awsSource) String() string { return s.raw }
+
+func (s *awsSource) Fetch(ctx context.Context) (map[string]string, error) {
+	region := s.region
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region == "" {
+			region = os.Getenv(env)
+		}
+	}
+	if region == "" {
+		return nil, errors.New("aws: no region (add ?region= to the source or set AWS_REGION)")
+	}
+	if _, ok := providers.SigV4CredentialsFromEnv(); !ok {
+		return nil, errors.New("aws: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+	}
+
+	body, _ := json.Marshal(map[string]string{"SecretId": s.id})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("aws: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	client := *s.client
+	client.Transport = &providers.SigV4Transport{
+		Underlying: s.client.Transport,
+		Credentials: func() (providers.SigV4Credentials, error) {
+			creds, _ := providers.SigV4CredentialsFromEnv()
+			return creds, nil
+		},
+		Region:  region,
+		Service: "secretsmanager",
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := getJSON(&client, req, "aws: GetSecretValue "+s.id, &out); err != nil {
+		return nil, err
+	}
+	if out.SecretString == nil {
+		return nil, fmt.Errorf("aws: secret %s has no SecretString (binary secrets are not supported)", s.id)
+	}
+	return parseObject("aws", []byte(*out.SecretString))
+}
@@ -0,0 +1,81 @@
+package remote
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// gcpMetadataToken is the GCE / GKE metadata server's token endpoint
+// for the default service account.
+const gcpMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// gcpSource reads a GCP Secret Manager secret version. The access
+// token is GOOGLE_OAUTH_ACCESS_TOKEN (e.g. from `gcloud auth
+// print-access-token`), else the metadata server's.
+type gcpSource struct {
+	raw      string
+	project  string
+	secret   string
+	version  string
+	endpoint string // overrides https://secretmanager.googleapis.com in tests
+	metadata string // overrides gcpMetadataToken in tests
+	client   *http.Client
+}
+
+func (s *gcpSource) String() string { return s.raw }
+
+func (s *gcpSource) Fetch(ctx context.Context) (map[string]string, error) {
+	token, err := s.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = "https://secretmanager.googleapis.com"
+	}
+	url := fmt.Sprintf("%s/v1/projects/%s/secrets/%s/versions/%s:access", endpoint, s.project, s.secret, s.version)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gcp: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := getJSON(s.client, req, "gcp: access "+s.project+"/"+s.secret, &out); err != nil {
+		return nil, err
+	}
+	payload, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("gcp: decoding payload: %w", err)
+	}
+	return parseObject("gcp", payload)
+}
+
+func (s *gcpSource) token(ctx context.Context) (string, error) {
+	if t := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); t != "" {
+		return t, nil
+	}
+	url := s.metadata
+	if url == "" {
+		url = gcpMetadataToken
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("gcp: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := getJSON(s.client, req, "gcp: metadata server token (GOOGLE_OAUTH_ACCESS_TOKEN not set)", &out); err != nil {
+		return "", err
+	}
+	return out.AccessToken, nil
+}
@@ -0,0 +1,228 @@
+// Package remote pulls secrets from external secret managers —
+// HashiCorp Vault, AWS Secrets Manager and GCP Secret Manager — so
+// `forge secret sync` can copy a defined set of keys into the local
+// encrypted store for offline development.
+//
+// SDK-free, like the STS and Bedrock clients: each manager is one
+// authenticated GET (or signed POST) returning a key-value document.
+//
+// A source URL names the manager and the secret:
+//
+//	vault://<mount>/<path>[?kv=1]        Vault KV v2 (kv=1: KV v1)
+//	aws://<secret-id>[?region=<region>]  AWS Secrets Manager
+//	gcp://<project>/<secret>[?version=N] GCP Secret Manager
+//
+// The secret must hold a JSON object (AWS, GCP) or a KV map (Vault);
+// its fields are the keys that can be synced.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/secrets"
+)
+
+// Source is an external secret manager entry keys are pulled from.
+type Source interface {
+	// Fetch returns the fields of the secret.
+	Fetch(ctx context.Context) (map[string]string, error)
+	// String returns the source URL, for messages.
+	String() string
+}
+
+// Parse parses a source URL. Credentials are read from the environment
+// when the source is fetched, not here, so configs validate anywhere.
+// client may be nil for http.DefaultClient.
+func Parse(raw string, client *http.Client) (Source, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("secrets source %q: %w", raw, err)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	path := strings.Trim(u.Host+u.Path, "/")
+	switch u.Scheme {
+	case "vault":
+		mount, rest, ok := strings.Cut(path, "/")
+		if !ok || mount == "" || rest == "" {
+			return nil, fmt.Errorf("secrets source %q: want vault://<mount>/<path>", raw)
+		}
+		return &vaultSource{raw: raw, mount: mount, path: rest, kv1: u.Query().Get("kv") == "1", client: client}, nil
+	case "aws":
+		if path == "" {
+			return nil, fmt.Errorf("secrets source %q: want aws://<secret-id>", raw)
+		}
+		return &awsSource{raw: raw, id: path, region: u.Query().Get("region"), client: client}, nil
+	case "gcp":
+		project, secret, ok := strings.Cut(path, "/")
+		if !ok || project == "" || secret == "" || strings.Contains(secret, "/") {
+			return nil, fmt.Errorf("secrets source %q: want gcp://<project>/<secret>", raw)
+		}
+		version := u.Query().Get("version")
+		if version == "" {
+			version = "latest"
+		}
+		return &gcpSource{raw: raw, project: project, secret: secret, version: version, client: client}, nil
+	case "":
+		return nil, fmt.Errorf("secrets source %q: missing scheme (vault://, aws:// or gcp://)", raw)
+	}
+	return nil, fmt.Errorf("secrets source %q: unsupported scheme %q (want vault, aws or gcp)", raw, u.Scheme)
+}
+
+// Mapping pairs a local secret key with the source field it is pulled
+// from.
+type Mapping struct {
+	Local  string
+	Remote string
+}
+
+// ParseMappings parses key specs: "KEY" pulls field KEY, and
+// "KEY=field" pulls field into KEY.
+func ParseMappings(specs []string) ([]Mapping, error) {
+	seen := make(map[string]bool, len(specs))
+	out := make([]Mapping, 0, len(specs))
+	for _, s := range specs {
+		local, remote, ok := strings.Cut(s, "=")
+		local, remote = strings.TrimSpace(local), strings.TrimSpace(remote)
+		if !ok {
+			remote = local
+		}
+		if local == "" || remote == "" {
+			return nil, fmt.Errorf("invalid key %q (want KEY or KEY=field)", s)
+		}
+		if seen[local] {
+			return nil, fmt.Errorf("key %q listed twice", local)
+		}
+		seen[local] = true
+		out = append(out, Mapping{Local: local, Remote: remote})
+	}
+	return out, nil
+}
+
+// Status is how a local key compares with its source field.
+type Status string
+
+const (
+	StatusInSync  Status = "in-sync"
+	StatusMissing Status = "missing" // not in the local store yet
+	StatusDrifted Status = "drifted" // local value differs from the source
+	StatusAbsent  Status = "absent"  // the source has no such field
+)
+
+// Change is one key's comparison result.
+type Change struct {
+	Mapping
+	Status Status
+	value  string
+}
+
+// Compare reports, for each mapping, whether local holds the source's
+// value. Values never leave the returned changes except through Apply.
+func Compare(mappings []Mapping, remote map[string]string, local secrets.Provider) ([]Change, error) {
+	changes := make([]Change, 0, len(mappings))
+	for _, m := range mappings {
+		c := Change{Mapping: m}
+		v, ok := remote[m.Remote]
+		if !ok {
+			c.Status = StatusAbsent
+			changes = append(changes, c)
+			continue
+		}
+		c.value = v
+		cur, err := local.Get(m.Local)
+		switch {
+		case secrets.IsNotFound(err):
+			c.Status = StatusMissing
+		case err != nil:
+			return nil, err
+		case cur != v:
+			c.Status = StatusDrifted
+		default:
+			c.Status = StatusInSync
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// Store is the local store keys are synced into;
+// *secrets.EncryptedFileProvider is one.
+type Store interface {
+	secrets.Provider
+	SetBatch(pairs map[string]string) error
+}
+
+// Apply writes the missing and drifted keys to local in one batch and
+// returns how many were written.
+func Apply(changes []Change, local Store) (int, error) {
+	pairs := make(map[string]string)
+	for _, c := range changes {
+		if c.Status == StatusMissing || c.Status == StatusDrifted {
+			pairs[c.Local] = c.value
+		}
+	}
+	if len(pairs) == 0 {
+		return 0, nil
+	}
+	return len(pairs), local.SetBatch(pairs)
+}
+
+// getJSON performs req and decodes a 200 response into out.
+func getJSON(client *http.Client, req *http.Request, what string, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%s: reading response: %w", what, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s: %s", what, resp.Status, truncate(strings.TrimSpace(string(body)), 200))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s: parsing response: %w", what, err)
+	}
+	return nil
+}
+
+// flatten renders a JSON object's fields as strings: strings as-is,
+// anything else as its JSON encoding.
+func flatten(fields map[string]json.RawMessage) map[string]string {
+	out := make(map[string]string, len(fields))
+	for k, raw := range fields {
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			out[k] = s
+		} else {
+			out[k] = string(raw)
+		}
+	}
+	return out
+}
+
+// parseObject decodes a secret payload that must be a JSON object.
+func parseObject(what string, payload []byte) (map[string]string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%s: secret is not a JSON object of key-value pairs", what)
+	}
+	return flatten(fields), nil
+}
+
+// truncate cuts s to at most n runes with an ellipsis suffix.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,177 @@
+package remote
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/secrets"
+)
+
+func TestParse(t *testing.T) {
+	for raw, want := range map[string]string{
+		"vault://secret/billing/prod":         "",
+		"vault://secret":                      "want vault://<mount>/<path>",
+		"aws://prod/billing?region=eu-west-1": "",
+		"aws://":                              "want aws://<secret-id>",
+		"gcp://my-project/billing":            "",
+		"gcp://my-project":                    "want gcp://<project>/<secret>",
+		"s3://bucket/key":                     `unsupported scheme "s3"`,
+		"secret/billing":                      "missing scheme",
+	} {
+		_, err := Parse(raw, nil)
+		if want == "" && err != nil {
+			t.Errorf("Parse(%q): %v", raw, err)
+		}
+		if want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+			t.Errorf("Parse(%q) err = %v, want %q", raw, err, want)
+		}
+	}
+}
+
+func TestParseMappings(t *testing.T) {
+	m, err := ParseMappings([]string{"OPENAI_API_KEY", "STRIPE_KEY = stripe_secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Mapping{{"OPENAI_API_KEY", "OPENAI_API_KEY"}, {"STRIPE_KEY", "stripe_secret"}}
+	if len(m) != 2 || m[0] != want[0] || m[1] != want[1] {
+		t.Errorf("mappings = %v, want %v", m, want)
+	}
+	for _, bad := range [][]string{{"=field"}, {"KEY="}, {"A", "A=b"}} {
+		if _, err := ParseMappings(bad); err == nil {
+			t.Errorf("ParseMappings(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestVaultSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "tok" || r.Header.Get("X-Vault-Namespace") != "team" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/billing":
+			_, _ = io.WriteString(w, `{"data":{"data":{"API_KEY":"v2","PORT":8080},"metadata":{"version":3}}}`)
+		case "/v1/kv/billing":
+			_, _ = io.WriteString(w, `{"data":{"API_KEY":"v1"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("VAULT_ADDR", srv.URL+"/")
+	t.Setenv("VAULT_TOKEN", "tok")
+	t.Setenv("VAULT_NAMESPACE", "team")
+
+	src, _ := Parse("vault://secret/billing", srv.Client())
+	got, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got["API_KEY"] != "v2" || got["PORT"] != "8080" {
+		t.Errorf("KV v2 fields = %v", got)
+	}
+
+	src, _ = Parse("vault://kv/billing?kv=1", srv.Client())
+	if got, err := src.Fetch(context.Background()); err != nil || got["API_KEY"] != "v1" {
+		t.Errorf("KV v1 Fetch = %v, %v", got, err)
+	}
+
+	t.Setenv("VAULT_TOKEN", "wrong")
+	if _, err := src.Fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Fetch with a bad token: err = %v", err)
+	}
+}
+
+func TestAWSSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request") ||
+			string(body) != `{"SecretId":"prod/billing"}` {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, `{"Name":"prod/billing","SecretString":"{\"API_KEY\":\"sk-aws\"}"}`)
+	}))
+	defer srv.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	src, _ := Parse("aws://prod/billing", srv.Client())
+	src.(*awsSource).endpoint = srv.URL
+	got, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got["API_KEY"] != "sk-aws" {
+		t.Errorf("fields = %v", got)
+	}
+}
+
+func TestGCPSource(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte(`{"API_KEY":"sk-gcp"}`))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token" && r.Header.Get("Metadata-Flavor") == "Google":
+			_, _ = io.WriteString(w, `{"access_token":"meta-token","expires_in":3599}`)
+		case r.URL.Path == "/v1/projects/proj/secrets/billing/versions/latest:access" &&
+			r.Header.Get("Authorization") == "Bearer meta-token":
+			_, _ = io.WriteString(w, `{"payload":{"data":"`+payload+`"}}`)
+		default:
+			http.Error(w, "denied", http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+
+	src, _ := Parse("gcp://proj/billing", srv.Client())
+	src.(*gcpSource).endpoint = srv.URL
+	src.(*gcpSource).metadata = srv.URL + "/token"
+	got, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got["API_KEY"] != "sk-gcp" {
+		t.Errorf("fields = %v", got)
+	}
+}
+
+func TestCompareApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	store := secrets.NewEncryptedFileProvider(path, func() (string, error) { return "p", nil })
+	if err := store.SetBatch(map[string]string{"SAME": "1", "OLD": "stale"}); err != nil {
+		t.Fatal(err)
+	}
+	mappings, _ := ParseMappings([]string{"SAME", "OLD", "NEW=new_field", "GONE"})
+	remote := map[string]string{"SAME": "1", "OLD": "fresh", "new_field": "n"}
+
+	changes, err := Compare(mappings, remote, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Status{StatusInSync, StatusDrifted, StatusMissing, StatusAbsent}
+	for i, c := range changes {
+		if c.Status != want[i] {
+			t.Errorf("%s: status = %s, want %s", c.Local, c.Status, want[i])
+		}
+	}
+
+	n, err := Apply(changes, store)
+	if err != nil || n != 2 {
+		t.Fatalf("Apply = %d, %v; want 2 writes", n, err)
+	}
+	for k, v := range map[string]string{"OLD": "fresh", "NEW": "n", "SAME": "1"} {
+		if got, _ := store.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
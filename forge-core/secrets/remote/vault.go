@@ -0,0 +1,61 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// vaultSource reads a Vault KV secret with the token in VAULT_TOKEN
+// from the server at VAULT_ADDR, in VAULT_NAMESPACE when set.
+type vaultSource struct {
+	raw    string
+	mount  string
+	path   string
+	kv1    bool
+	client *http.Client
+}
+
+func (s *vaultSource) String() string { return s.raw }
+
+func (s *vaultSource) Fetch(ctx context.Context) (map[string]string, error) {
+	addr, token := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return nil, errors.New("vault: VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(addr, "/"), s.mount, s.path)
+	if s.kv1 {
+		url = fmt.Sprintf("%s/v1/%s/%s", strings.TrimRight(addr, "/"), s.mount, s.path)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	// KV v2 nests the secret one level deeper than KV v1.
+	var out struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := getJSON(s.client, req, "vault: GET "+s.mount+"/"+s.path, &out); err != nil {
+		return nil, err
+	}
+	data := out.Data
+	if !s.kv1 {
+		var v2 struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return nil, fmt.Errorf("vault: parsing KV v2 response: %w", err)
+		}
+		data = v2.Data
+	}
+	return parseObject("vault", data)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestGenerateAllowlistJSON_DenyAll(t *testing.T) {
@@ -155,3 +157,61 @@ func TestGenerateAllowlistJSON_NilArraysSafeJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateK8sNetworkPolicy_AllowlistRules(t *testing.T) {
+	cfg := &EgressConfig{
+		Profile:             ProfileStandard,
+		Mode:                ModeAllowlist,
+		AllDomains:          []string{"api.example.com"},
+		AllowedPrivateCIDRs: []string{"10.20.0.0/16"},
+		AllowedTCP:          []string{"10.20.1.5:5432", "kafka.internal:9092", "cache.internal:*"},
+	}
+	data, err := GenerateK8sNetworkPolicy("my-agent", cfg)
+	if err != nil {
+		t.Fatalf("GenerateK8sNetworkPolicy: %v", err)
+	}
+	var policy struct {
+		Metadata struct {
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Egress []struct {
+				To []struct {
+					IPBlock struct {
+						CIDR   string   `yaml:"cidr"`
+						Except []string `yaml:"except"`
+					} `yaml:"ipBlock"`
+				} `yaml:"to"`
+				Ports []struct {
+					Protocol string `yaml:"protocol"`
+					Port     int    `yaml:"port"`
+				} `yaml:"ports"`
+			} `yaml:"egress"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		t.Fatalf("policy is not valid YAML: %v\n%s", err, data)
+	}
+	egress := policy.Spec.Egress
+	if len(egress) != 5 {
+		t.Fatalf("got %d egress rules, want 5 (dns, https, cidr, 2 tcp):\n%s", len(egress), data)
+	}
+	if egress[0].Ports[0].Port != 53 {
+		t.Errorf("first rule should allow DNS, got %+v", egress[0])
+	}
+	if https := egress[1].To[0].IPBlock; https.CIDR != "0.0.0.0/0" || len(https.Except) == 0 {
+		t.Errorf("https rule should exclude private ranges, got %+v", https)
+	}
+	if egress[2].To[0].IPBlock.CIDR != "10.20.0.0/16" {
+		t.Errorf("private cidr rule = %+v", egress[2])
+	}
+	if egress[3].To[0].IPBlock.CIDR != "10.20.1.5/32" || egress[3].Ports[0].Port != 5432 {
+		t.Errorf("ip tcp rule = %+v", egress[3])
+	}
+	if len(egress[4].To) != 0 || egress[4].Ports[0].Port != 9092 {
+		t.Errorf("host tcp rule = %+v", egress[4])
+	}
+	if got := policy.Metadata.Annotations["ai.initializ.forge/in-process-only-tcp"]; got != "cache.internal:*" {
+		t.Errorf("in-process-only annotation = %q", got)
+	}
+}
@@ -29,7 +29,11 @@ const dialTimeout = 10 * time.Second
 //     AND the port-aware TCP matcher. A target passes if EITHER allows it.
 //     This means an HTTP-allowed hostname is reachable over CONNECT/SOCKS5
 //     without a redundant `allowed_tcp` entry — the reverse of "allowlist
-//     duplicated across two config keys."
+//     duplicated across two config keys." An allowed target is then
+//     put to the Authorize hook (OPA), which may still deny it. A target
+//     allowed by hostname alone must also pass the EgressConstraints port
+//     check (and the scheme check on the CONNECT path); one allowed by
+//     an explicit `allowed_tcp` entry is not constrained further.
 //  4. Fire the audit hook exactly once with the (host, port) pair and the
 //     decision. Same shape for HTTP and SOCKS5 flows.
 //  5. On allow, dial via `SafeDialer` (SSRF + private-CIDR + strict-IP
@@ -40,7 +44,7 @@ const dialTimeout = 10 * time.Second
 func (p *EgressProxy) ValidateAndDial(ctx context.Context, host, port string) (net.Conn, error) {
 	// SOCKS5 callers record the full host:port in the audit — the whole
 	// point of raw-TCP egress is per-port policy, so per-port audit follows.
-	return p.validateAndDialWithIdentity(ctx, host, port, net.JoinHostPort(host, port), egressIdentity{})
+	return p.validateAndDialWithIdentity(ctx, "", host, port, net.JoinHostPort(host, port), egressIdentity{})
 }
 
 // fireAttemptRaw emits one audit event per dial attempt with the exact
@@ -48,16 +52,16 @@ func (p *EgressProxy) ValidateAndDial(ctx context.Context, host, port string) (n
 // audit shape) or host:port for SOCKS5 (raw-TCP path where port matters).
 // Downstream consumers keyed by hostname keep working; consumers reading
 // SOCKS5 events see the full destination.
-func (p *EgressProxy) fireAttemptRaw(auditDomain string, allowed bool, id egressIdentity) {
+func (p *EgressProxy) fireAttemptRaw(a EgressAttempt, id egressIdentity) {
+	if !a.Allowed {
+		p.meter.blocked(a.Domain)
+	}
 	if p.OnAttempt == nil {
 		return
 	}
-	p.OnAttempt(EgressAttempt{
-		Domain:        auditDomain,
-		Allowed:       allowed,
-		TaskID:        id.taskID,
-		CorrelationID: id.correlationID,
-	})
+	a.TaskID = id.taskID
+	a.CorrelationID = id.correlationID
+	p.OnAttempt(a)
 }
 
 // validateAndDialWithIdentity is the identity-carrying variant of
@@ -65,15 +69,19 @@ func (p *EgressProxy) fireAttemptRaw(auditDomain string, allowed bool, id egress
 // Proxy-Authorization and pass it; SOCKS5 has no channel for identity and
 // uses the bare ValidateAndDial.
 //
+// scheme is "https" on the CONNECT path and empty on the raw-TCP one,
+// which has no scheme to check.
+//
 // The auditDomain parameter controls the string recorded on OnAttempt:
 // callers pass `host` for HTTP (pre-#337 shape, hostname-only — keeps
 // downstream consumers that key by hostname working) or
 // `net.JoinHostPort(host, port)` for the SOCKS5 raw-TCP path (which
 // genuinely needs port granularity to be useful).
-func (p *EgressProxy) validateAndDialWithIdentity(ctx context.Context, host, port, auditDomain string, id egressIdentity) (net.Conn, error) {
+func (p *EgressProxy) validateAndDialWithIdentity(ctx context.Context, scheme, host, port, auditDomain string, id egressIdentity) (net.Conn, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	a := EgressAttempt{Domain: auditDomain, Scheme: scheme, Port: defaultPort(port, scheme)}
 
 	// Localhost fires an "allowed" audit event so downstream audit consumers
 	// see the CONNECT attempt (with task/correlation IDs on the HTTP path).
@@ -83,18 +91,34 @@ func (p *EgressProxy) validateAndDialWithIdentity(ctx context.Context, host, por
 	// `Dialer.DialContext` so a cancelled ctx (agent tool timeout, session
 	// shutdown) cuts the dial the same way it does through SafeDialer.
 	if IsLocalhost(host) {
-		p.fireAttemptRaw(auditDomain, true, id)
+		a.Allowed = true
+		p.fireAttemptRaw(a, id)
 		d := &net.Dialer{Timeout: dialTimeout}
 		return d.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
 	}
 
 	if err := ValidateHostIP(host); err != nil {
-		p.fireAttemptRaw(auditDomain, false, id)
+		p.fireAttemptRaw(a, id)
 		return nil, fmt.Errorf("egress: %w", err)
 	}
 
-	allowed := p.matcher.IsAllowed(host) || (p.tcpMatcher != nil && p.tcpMatcher.IsAllowed(host, port))
-	p.fireAttemptRaw(auditDomain, allowed, id)
+	byTCP := p.tcpMatcher != nil && p.tcpMatcher.IsAllowed(host, port)
+	allowed := p.matcher.IsAllowed(host) || byTCP
+	if allowed {
+		if err := p.authorize(ctx, host, id); err != nil {
+			p.fireAttemptRaw(a, id)
+			return nil, fmt.Errorf("egress: %s: %w", net.JoinHostPort(host, port), err)
+		}
+		if !byTCP {
+			if err := p.activeConstraints().Check(scheme, host, a.Port, ""); err != nil {
+				a.Reason = constraintReason(err)
+				p.fireAttemptRaw(a, id)
+				return nil, fmt.Errorf("egress: %w", err)
+			}
+		}
+	}
+	a.Allowed = allowed
+	p.fireAttemptRaw(a, id)
 	if !allowed {
 		return nil, fmt.Errorf("egress: %s not in allowlist", net.JoinHostPort(host, port))
 	}
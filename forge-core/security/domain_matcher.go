@@ -3,11 +3,15 @@ package security
 import (
 	"net"
 	"strings"
+	"sync"
 )
 
 // DomainMatcher checks hostnames against an exact+wildcard allowlist.
 // It is used by both EgressEnforcer (Go HTTP) and EgressProxy (subprocess HTTP).
+// Update swaps the allowlist in place, so a config reload reaches every
+// holder of the matcher without rebuilding transports.
 type DomainMatcher struct {
+	mu            sync.RWMutex
 	mode          EgressMode
 	allowedHosts  map[string]bool
 	wildcardHosts []string // suffix patterns: ".github.com"
@@ -16,6 +20,14 @@ type DomainMatcher struct {
 // NewDomainMatcher creates a new DomainMatcher for the given mode and domain list.
 // Domains may include wildcard prefixes (e.g. "*.github.com") which match any subdomain.
 func NewDomainMatcher(mode EgressMode, domains []string) *DomainMatcher {
+	m := &DomainMatcher{}
+	m.Update(mode, domains)
+	return m
+}
+
+// Update replaces the matcher's mode and domain list. Requests already
+// past IsAllowed are unaffected; every later check sees the new list.
+func (m *DomainMatcher) Update(mode EgressMode, domains []string) {
 	allowed := make(map[string]bool, len(domains))
 	var wildcards []string
 	for _, d := range domains {
@@ -31,17 +43,19 @@ func NewDomainMatcher(mode EgressMode, domains []string) *DomainMatcher {
 		}
 	}
 
-	return &DomainMatcher{
-		mode:          mode,
-		allowedHosts:  allowed,
-		wildcardHosts: wildcards,
-	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mode = mode
+	m.allowedHosts = allowed
+	m.wildcardHosts = wildcards
 }
 
 // IsAllowed checks if a host is permitted under the current mode.
 // Exact match is checked first, then wildcard suffix, then mode fallback.
 func (m *DomainMatcher) IsAllowed(host string) bool {
 	host = strings.ToLower(host)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	switch m.mode {
 	case ModeDevOpen:
 		return true
@@ -66,6 +80,8 @@ func (m *DomainMatcher) IsAllowed(host string) bool {
 
 // Mode returns the egress mode of this matcher.
 func (m *DomainMatcher) Mode() EgressMode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.mode
 }
 
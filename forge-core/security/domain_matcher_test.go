@@ -84,6 +84,21 @@ func TestDomainMatcherMode(t *testing.T) {
 	}
 }
 
+func TestDomainMatcherUpdate(t *testing.T) {
+	m := NewDomainMatcher(ModeAllowlist, []string{"api.openai.com"})
+	m.Update(ModeAllowlist, []string{"*.github.com"})
+	if m.IsAllowed("api.openai.com") {
+		t.Error("domain dropped by Update still allowed")
+	}
+	if !m.IsAllowed("api.github.com") {
+		t.Error("wildcard added by Update not allowed")
+	}
+	m.Update(ModeDenyAll, nil)
+	if m.Mode() != ModeDenyAll || m.IsAllowed("api.github.com") {
+		t.Errorf("after Update(deny-all): mode %v, api.github.com allowed %v", m.Mode(), m.IsAllowed("api.github.com"))
+	}
+}
+
 func TestIsLocalhostExported(t *testing.T) {
 	tests := []struct {
 		host     string
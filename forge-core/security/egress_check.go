@@ -0,0 +1,144 @@
+package security
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IPResolver is the part of *net.Resolver CheckEgressDomains uses.
+type IPResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// DomainCheck is the live-DNS verdict on one egress allowlist entry.
+type DomainCheck struct {
+	Domain string
+	Addrs  []string
+	// Err is set when the entry cannot work as written: the name does
+	// not resolve (usually a typo).
+	Err error
+	// Warnings flag entries that resolve but are likely to fail at
+	// runtime: a wildcard that misses its apex, an apex whose API host
+	// is not allowed, or a private target the egress enforcer blocks.
+	Warnings []string
+}
+
+// privateNameSuffixes are names that only resolve inside a private
+// network, so a failed lookup from a laptop is expected.
+var privateNameSuffixes = []string{".local", ".localhost", ".internal", ".lan", ".home.arpa", ".svc", ".cluster.local"}
+
+// CheckEgressDomains resolves each allowlist entry and flags entries that
+// will not behave the way their author likely meant. Entries are checked
+// concurrently; results are in input order.
+func CheckEgressDomains(ctx context.Context, r IPResolver, domains []string) []DomainCheck {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	allowed := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		allowed[strings.ToLower(strings.TrimSpace(d))] = true
+	}
+
+	out := make([]DomainCheck, len(domains))
+	var wg sync.WaitGroup
+	for i, d := range domains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i] = checkEgressDomain(ctx, r, strings.ToLower(strings.TrimSpace(d)), allowed)
+			out[i].Domain = d
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+func checkEgressDomain(ctx context.Context, r IPResolver, d string, allowed map[string]bool) DomainCheck {
+	c := DomainCheck{Domain: d}
+	if ip := net.ParseIP(d); ip != nil {
+		c.Addrs = []string{ip.String()}
+		if IsBlockedIP(ip, false, nil) {
+			c.Warnings = append(c.Warnings, "private address: blocked unless egress.allowed_private_cidrs covers it")
+		}
+		return c
+	}
+
+	if apex, ok := strings.CutPrefix(d, "*."); ok {
+		if !allowed[apex] && resolves(ctx, r, apex) {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("%s does not match %s itself; add it if the agent calls it", d, apex))
+		}
+		return c
+	}
+
+	private := d == "localhost" || slices.ContainsFunc(privateNameSuffixes, func(s string) bool { return strings.HasSuffix(d, s) })
+	addrs, err := r.LookupIPAddr(ctx, d)
+	if err != nil {
+		if private {
+			c.Warnings = append(c.Warnings, "private name: resolves only inside its network, and private targets are blocked unless allowed")
+		} else {
+			c.Err = fmt.Errorf("does not resolve: %w", unwrapDNSError(err))
+		}
+		return c
+	}
+	blocked := 0
+	for _, a := range addrs {
+		c.Addrs = append(c.Addrs, a.IP.String())
+		if IsBlockedIP(a.IP, false, nil) {
+			blocked++
+		}
+	}
+	if private || (blocked > 0 && blocked == len(addrs)) {
+		c.Warnings = append(c.Warnings, "resolves to a private address: blocked unless egress.allowed_private_cidrs covers it")
+	}
+
+	// An apex entry (example.com) does not match api.example.com. Flag it
+	// when that API host exists and nothing else allows it.
+	if strings.Count(d, ".") == 1 {
+		api := "api." + d
+		if !allowed[api] && !allowed["*."+d] && resolves(ctx, r, api) {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("apex only: %s exists but is not allowed; add it or *.%s", api, d))
+		}
+	}
+	return c
+}
+
+func resolves(ctx context.Context, r IPResolver, host string) bool {
+	addrs, err := r.LookupIPAddr(ctx, host)
+	return err == nil && len(addrs) > 0
+}
+
+// unwrapDNSError shortens a *net.DNSError to its reason ("no such host").
+func unwrapDNSError(err error) error {
+	var de *net.DNSError
+	if errors.As(err, &de) {
+		return errors.New(de.Err)
+	}
+	return err
+}
+
+// ProbeEgress opens a TLS connection to host:443 (or host, when it has
+// a port) and returns the handshake time. d may be nil for a dialer
+// that verifies the certificate against the system roots.
+func ProbeEgress(ctx context.Context, d *tls.Dialer, host string) (time.Duration, error) {
+	if d == nil {
+		d = &tls.Dialer{}
+	}
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, "443")
+	}
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, err
+	}
+	_ = conn.Close()
+	return time.Since(start), nil
+}
@@ -0,0 +1,101 @@
+package security
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeResolver answers from a fixed table; other names are NXDOMAIN.
+type fakeResolver map[string]string
+
+func (f fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := f[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+func TestCheckEgressDomains(t *testing.T) {
+	r := fakeResolver{
+		"api.openai.com":    "104.18.6.192",
+		"github.com":        "140.82.112.3",
+		"api.github.com":    "140.82.112.6",
+		"slack.com":         "3.64.1.1",
+		"api.slack.com":     "3.64.1.2",
+		"example.com":       "93.184.215.14",
+		"wiki.corp.example": "10.1.2.3",
+		"api.stripe.com":    "54.187.174.169",
+		"stripe.com":        "54.187.174.170",
+		"files.stripe.com":  "54.187.174.171",
+	}
+	domains := []string{
+		"api.openai.com",    // fine
+		"github.com",        // apex, but api.github.com is listed
+		"api.github.com",    // fine
+		"slack.com",         // apex only: api.slack.com exists
+		"*.example.com",     // wildcard misses example.com
+		"wiki.corp.example", // private target
+		"api.opneai.com",    // typo
+		"10.0.0.5",          // private literal
+		"*.stripe.com",      // wildcard, apex allowed below
+		"stripe.com",        // apex, wildcard covers api.stripe.com
+		"db.svc",            // private name, unresolvable here
+	}
+	got := CheckEgressDomains(context.Background(), r, domains)
+	if len(got) != len(domains) {
+		t.Fatalf("got %d results, want %d", len(got), len(domains))
+	}
+
+	want := map[string]string{
+		"api.openai.com":    "",
+		"github.com":        "",
+		"api.github.com":    "",
+		"slack.com":         "apex only: api.slack.com exists",
+		"*.example.com":     "does not match example.com itself",
+		"wiki.corp.example": "resolves to a private address",
+		"10.0.0.5":          "private address",
+		"*.stripe.com":      "",
+		"stripe.com":        "",
+		"db.svc":            "private name",
+	}
+	for _, c := range got {
+		if c.Domain == "api.opneai.com" {
+			if c.Err == nil || !strings.Contains(c.Err.Error(), "does not resolve: no such host") {
+				t.Errorf("typo: Err = %v", c.Err)
+			}
+			continue
+		}
+		if c.Err != nil {
+			t.Errorf("%s: unexpected error %v", c.Domain, c.Err)
+		}
+		w := want[c.Domain]
+		if w == "" && len(c.Warnings) > 0 {
+			t.Errorf("%s: unexpected warnings %v", c.Domain, c.Warnings)
+		}
+		if w != "" && (len(c.Warnings) != 1 || !strings.Contains(c.Warnings[0], w)) {
+			t.Errorf("%s: warnings = %v, want %q", c.Domain, c.Warnings, w)
+		}
+	}
+}
+
+func TestProbeEgress(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	addr := srv.Listener.Addr().String()
+
+	if _, err := ProbeEgress(context.Background(), nil, addr); err == nil {
+		t.Error("expected a certificate error for the self-signed test server")
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	d := &tls.Dialer{Config: &tls.Config{RootCAs: pool, ServerName: "example.com"}}
+	if _, err := ProbeEgress(context.Background(), d, addr); err != nil {
+		t.Errorf("ProbeEgress with the server's root: %v", err)
+	}
+}
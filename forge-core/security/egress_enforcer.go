@@ -19,6 +19,20 @@ type EgressEnforcer struct {
 	AllowPrivateIPs     bool
 	AllowedPrivateCIDRs []*net.IPNet
 	OnAttempt           func(ctx context.Context, domain string, allowed bool)
+
+	// Authorize, when set, is consulted for every allowlisted
+	// non-local domain before OnAttempt fires; a non-nil error blocks
+	// the request. It can only narrow the allowlist, never widen it.
+	Authorize func(ctx context.Context, domain string) error
+
+	// OnViolation, when set, fires in place of OnAttempt when an
+	// allowlisted request breaks the enforcer's constraints, with the
+	// scheme, port, method and reason that blocked it. A response that
+	// outgrows its cap fires it after OnAttempt has already allowed the
+	// request. When nil, those blocks fire OnAttempt with allowed=false.
+	OnViolation func(ctx context.Context, a EgressAttempt)
+
+	constraints *EgressConstraints
 }
 
 // NewEgressEnforcer creates a new EgressEnforcer wrapping the given base transport.
@@ -38,8 +52,21 @@ func NewEgressEnforcer(base http.RoundTripper, mode EgressMode, domains []string
 	}
 }
 
+// Matcher returns the enforcer's domain matcher. Updating it changes
+// what later requests through this enforcer may reach.
+func (e *EgressEnforcer) Matcher() *DomainMatcher {
+	return e.matcher
+}
+
+// SetConstraints installs scheme, port, method and response-size
+// constraints for allowlisted domains. Localhost is exempt. Must be
+// called before the enforcer serves requests.
+func (e *EgressEnforcer) SetConstraints(c *EgressConstraints) {
+	e.constraints = c
+}
+
 // RoundTrip implements http.RoundTripper. It checks the request hostname
-// against the allowlist and fires the OnAttempt callback.
+// against the allowlist and constraints and fires the OnAttempt callback.
 func (e *EgressEnforcer) RoundTrip(req *http.Request) (*http.Response, error) {
 	host := strings.ToLower(req.URL.Hostname())
 
@@ -63,16 +90,66 @@ func (e *EgressEnforcer) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	allowed := e.matcher.IsAllowed(host)
+	var authErr error
+	if allowed && e.Authorize != nil {
+		if authErr = e.Authorize(ctx, host); authErr != nil {
+			allowed = false
+		}
+	}
+
+	attempt := EgressAttempt{
+		Domain: host,
+		Scheme: req.URL.Scheme,
+		Port:   defaultPort(req.URL.Port(), req.URL.Scheme),
+		Method: req.Method,
+	}
+	if allowed && e.matcher.Mode() != ModeDevOpen {
+		if err := e.constraints.Check(attempt.Scheme, host, attempt.Port, attempt.Method); err != nil {
+			attempt.Reason = constraintReason(err)
+			e.violation(ctx, attempt)
+			return nil, fmt.Errorf("egress blocked: %w", err)
+		}
+	}
 
 	if e.OnAttempt != nil {
 		e.OnAttempt(ctx, host, allowed)
 	}
 
+	if authErr != nil {
+		return nil, fmt.Errorf("egress blocked: domain %q: %w", host, authErr)
+	}
 	if !allowed {
 		return nil, fmt.Errorf("egress blocked: domain %q not in allowlist (mode=%s)", host, e.matcher.Mode())
 	}
 
-	return e.base.RoundTrip(req)
+	resp, err := e.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	limit := e.constraints.MaxResponseBytes(host)
+	if limit <= 0 || e.matcher.Mode() == ModeDevOpen {
+		return resp, nil
+	}
+	attempt.Reason = ReasonResponseSize
+	if resp.ContentLength > limit {
+		resp.Body.Close() //nolint:errcheck
+		e.violation(ctx, attempt)
+		return nil, fmt.Errorf("egress blocked: %w", responseTooLarge(host, limit))
+	}
+	resp.Body = cappedBody{
+		cappedReader: &cappedReader{r: resp.Body, host: host, limit: limit, onExceed: func() { e.violation(ctx, attempt) }},
+		Closer:       resp.Body,
+	}
+	return resp, nil
+}
+
+// violation reports a request blocked by the enforcer's constraints.
+func (e *EgressEnforcer) violation(ctx context.Context, a EgressAttempt) {
+	if e.OnViolation != nil {
+		e.OnViolation(ctx, a)
+	} else if e.OnAttempt != nil {
+		e.OnAttempt(ctx, a.Domain, false)
+	}
 }
 
 // WithEgressClient stores an egress-enforced HTTP client in the context.
@@ -2,6 +2,7 @@ package security
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -310,3 +311,34 @@ func TestEgressEnforcerSSRFBypass(t *testing.T) {
 		})
 	}
 }
+
+func TestEgressEnforcerAuthorize(t *testing.T) {
+	var attempts []bool
+	ok := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	enforcer := NewEgressEnforcer(ok, ModeAllowlist, []string{"api.example.com", "ok.example.com"}, false, nil)
+	enforcer.OnAttempt = func(_ context.Context, _ string, allowed bool) { attempts = append(attempts, allowed) }
+	enforcer.Authorize = func(_ context.Context, domain string) error {
+		if domain == "api.example.com" {
+			return errors.New("denied by policy")
+		}
+		return nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	if _, err := enforcer.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "denied by policy") {
+		t.Fatalf("expected policy denial, got %v", err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, "https://ok.example.com/", nil)
+	if _, err := enforcer.RoundTrip(req); err != nil {
+		t.Fatalf("authorized domain: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0] || !attempts[1] {
+		t.Errorf("OnAttempt saw %v, want [false true]", attempts)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
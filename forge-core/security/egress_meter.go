@@ -0,0 +1,244 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// ReasonQuota marks egress blocked because the task behind it used up
+// its EgressTaskQuota.
+const ReasonQuota = "quota"
+
+// taskUsageTTL is how long a task's usage is kept after its last
+// egress. Quotas are per task, and a task that has been quiet this long
+// has almost certainly finished.
+const taskUsageTTL = time.Hour
+
+// EgressTaskQuota bounds the egress of one task through the proxy.
+// Zero fields are unlimited. Only traffic the proxy can attribute to a
+// task (see EgressAttempt) counts against a quota.
+type EgressTaskQuota struct {
+	// MaxBytes caps the bytes a task sends and receives, together.
+	MaxBytes int64 `json:"max_bytes"`
+	// MaxRequests caps the HTTP requests and CONNECT tunnels a task
+	// opens.
+	MaxRequests int64 `json:"max_requests"`
+}
+
+// EgressDomainStats counts the proxy traffic to one domain.
+type EgressDomainStats struct {
+	Requests      int64 `json:"requests"`
+	Blocked       int64 `json:"blocked"`
+	BytesSent     int64 `json:"bytes_sent"`
+	BytesReceived int64 `json:"bytes_received"`
+}
+
+// EgressTaskUsage is one task's use of its quota.
+type EgressTaskUsage struct {
+	Requests int64 `json:"requests"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// EgressProxyStats is a snapshot of an EgressProxy's counters.
+type EgressProxyStats struct {
+	Domains map[string]EgressDomainStats `json:"domains"`
+	// Tasks holds the usage of tasks seen within the last hour.
+	Tasks map[string]EgressTaskUsage `json:"tasks,omitempty"`
+	Quota EgressTaskQuota            `json:"quota"`
+}
+
+// egressMeter keeps the proxy's per-domain counters and per-task quota
+// usage.
+type egressMeter struct {
+	mu      sync.Mutex
+	quota   EgressTaskQuota
+	domains map[string]*EgressDomainStats
+	tasks   map[string]*taskUsage
+	now     func() time.Time
+}
+
+type taskUsage struct {
+	EgressTaskUsage
+	seen time.Time
+}
+
+func newEgressMeter() *egressMeter {
+	return &egressMeter{
+		domains: make(map[string]*EgressDomainStats),
+		tasks:   make(map[string]*taskUsage),
+		now:     time.Now,
+	}
+}
+
+// domain returns domain's counters. The caller holds mu.
+func (m *egressMeter) domain(domain string) *EgressDomainStats {
+	d, ok := m.domains[domain]
+	if !ok {
+		d = &EgressDomainStats{}
+		m.domains[domain] = d
+	}
+	return d
+}
+
+// task returns taskID's usage, or nil for unattributed traffic. The
+// caller holds mu.
+func (m *egressMeter) task(taskID string) *taskUsage {
+	if taskID == "" {
+		return nil
+	}
+	now := m.now()
+	t, ok := m.tasks[taskID]
+	if !ok {
+		for id, u := range m.tasks {
+			if now.Sub(u.seen) > taskUsageTTL {
+				delete(m.tasks, id)
+			}
+		}
+		t = &taskUsage{}
+		m.tasks[taskID] = t
+	}
+	t.seen = now
+	return t
+}
+
+// exhausted reports whether taskID has used up its quota.
+func (m *egressMeter) exhausted(taskID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := m.task(taskID)
+	if t == nil {
+		return false
+	}
+	return (m.quota.MaxRequests > 0 && t.Requests >= m.quota.MaxRequests) ||
+		(m.quota.MaxBytes > 0 && t.Bytes >= m.quota.MaxBytes)
+}
+
+// request counts one allowed request or tunnel.
+func (m *egressMeter) request(taskID, domain string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.domain(domain).Requests++
+	if t := m.task(taskID); t != nil {
+		t.Requests++
+	}
+}
+
+// blocked counts one blocked attempt.
+func (m *egressMeter) blocked(domain string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.domain(domain).Blocked++
+}
+
+// transfer counts bytes moved for taskID to or from domain, and
+// reports false once the task's byte quota is exceeded.
+func (m *egressMeter) transfer(taskID, domain string, sent, received int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := m.domain(domain)
+	d.BytesSent += sent
+	d.BytesReceived += received
+	t := m.task(taskID)
+	if t == nil {
+		return true
+	}
+	t.Bytes += sent + received
+	return m.quota.MaxBytes <= 0 || t.Bytes <= m.quota.MaxBytes
+}
+
+func (m *egressMeter) stats() EgressProxyStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := EgressProxyStats{
+		Domains: make(map[string]EgressDomainStats, len(m.domains)),
+		Quota:   m.quota,
+	}
+	for name, d := range m.domains {
+		s.Domains[name] = *d
+	}
+	now := m.now()
+	for id, t := range m.tasks {
+		if now.Sub(t.seen) > taskUsageTTL {
+			continue
+		}
+		if s.Tasks == nil {
+			s.Tasks = make(map[string]EgressTaskUsage)
+		}
+		s.Tasks[id] = t.EgressTaskUsage
+	}
+	return s
+}
+
+// errQuotaExceeded is wrapped by the error a metered transfer returns
+// once its task's byte quota is used up.
+var errQuotaExceeded = errors.New("egress quota exceeded")
+
+// meter is one metered flow: a task's traffic to one domain. onExceed
+// fires once, when the flow takes the task past its byte quota.
+type meter struct {
+	m        *egressMeter
+	taskID   string
+	domain   string
+	once     sync.Once
+	onExceed func()
+}
+
+func (f *meter) count(sent, received int64) error {
+	if f.m.transfer(f.taskID, f.domain, sent, received) {
+		return nil
+	}
+	if f.onExceed != nil {
+		f.once.Do(f.onExceed)
+	}
+	return fmt.Errorf("egress blocked: task %q: %w (%d bytes)", f.taskID, errQuotaExceeded, f.m.quota.MaxBytes)
+}
+
+// meteredConn counts an upstream conn's traffic: reads are bytes
+// received from the domain, writes bytes sent to it. Both fail once the
+// task's byte quota is exceeded, which ends a relay.
+type meteredConn struct {
+	net.Conn
+	f *meter
+}
+
+func (c *meteredConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if qerr := c.f.count(0, int64(n)); qerr != nil {
+		return n, qerr
+	}
+	return n, err
+}
+
+func (c *meteredConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if qerr := c.f.count(int64(n), 0); qerr != nil {
+		return n, qerr
+	}
+	return n, err
+}
+
+// meteredReader counts bytes read from a plain HTTP body: a request
+// body's as sent to the domain, a response body's as received.
+type meteredReader struct {
+	r    io.Reader
+	f    *meter
+	sent bool
+}
+
+func (r *meteredReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	var qerr error
+	if r.sent {
+		qerr = r.f.count(int64(n), 0)
+	} else {
+		qerr = r.f.count(0, int64(n))
+	}
+	if qerr != nil {
+		return n, qerr
+	}
+	return n, err
+}
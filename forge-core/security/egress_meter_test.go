@@ -0,0 +1,127 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEgressProxyTaskQuota(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("z"), 100)) //nolint:errcheck
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	proxy := NewEgressProxy(NewDomainMatcher(ModeAllowlist, []string{upstreamURL.Hostname()}), false, nil)
+	proxy.SetTaskQuota(EgressTaskQuota{MaxRequests: 2})
+	var mu sync.Mutex
+	var reasons []string
+	proxy.OnAttempt = func(a EgressAttempt) {
+		mu.Lock()
+		defer mu.Unlock()
+		reasons = append(reasons, a.Reason)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	proxyAddr, err := proxy.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer proxy.Stop() //nolint:errcheck
+
+	clientFor := func(task string) *http.Client {
+		b64u := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+		u, _ := url.Parse(proxyAddr)
+		if task != "" {
+			u.User = url.UserPassword(b64u(task), b64u("corr"))
+		}
+		return &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(u)}}
+	}
+	get := func(c *http.Client) int {
+		resp, err := c.Get(upstream.URL + "/")
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		resp.Body.Close()              //nolint:errcheck
+		return resp.StatusCode
+	}
+
+	task := clientFor("task-1")
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := get(task); got != want {
+			t.Errorf("task-1 request %d: status %d, want %d", i, got, want)
+		}
+	}
+	if got := get(clientFor("task-2")); got != http.StatusOK {
+		t.Errorf("task-2 status %d, want its own quota", got)
+	}
+	for range 3 {
+		if got := get(clientFor("")); got != http.StatusOK {
+			t.Errorf("unattributed status %d, want no quota", got)
+		}
+	}
+
+	stats := proxy.Stats()
+	d := stats.Domains[upstreamURL.Hostname()]
+	if d.Requests != 6 || d.Blocked != 1 || d.BytesReceived != 600 {
+		t.Errorf("domain stats = %+v, want 6 requests, 1 blocked, 600 bytes received", d)
+	}
+	if u := stats.Tasks["task-1"]; u.Requests != 2 || u.Bytes != 200 {
+		t.Errorf("task-1 usage = %+v", u)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reasons) < 3 || reasons[2] != ReasonQuota {
+		t.Errorf("reasons = %q, want the third to be quota", reasons)
+	}
+}
+
+func TestEgressMeterByteQuota(t *testing.T) {
+	m := newEgressMeter()
+	m.quota = EgressTaskQuota{MaxBytes: 10}
+	var exceeded int
+	f := &meter{m: m, taskID: "t", domain: "api.example.com", onExceed: func() { exceeded++ }}
+	if err := f.count(6, 0); err != nil {
+		t.Fatalf("under quota: %v", err)
+	}
+	if err := f.count(0, 6); err == nil {
+		t.Fatal("over quota: expected error")
+	}
+	if err := f.count(1, 0); err == nil {
+		t.Fatal("still over quota: expected error")
+	}
+	if exceeded != 1 {
+		t.Errorf("onExceed fired %d times, want 1", exceeded)
+	}
+	if !m.exhausted("t") || m.exhausted("other") || m.exhausted("") {
+		t.Error("exhausted should hold for t only")
+	}
+	if d := m.stats().Domains["api.example.com"]; d.BytesSent != 7 || d.BytesReceived != 6 {
+		t.Errorf("domain stats = %+v", d)
+	}
+}
+
+func TestEgressMeterForgetsIdleTasks(t *testing.T) {
+	now := time.Now()
+	m := newEgressMeter()
+	m.now = func() time.Time { return now }
+	m.request("old", "a.example.com")
+	now = now.Add(2 * taskUsageTTL)
+	m.request("new", "a.example.com")
+	if _, ok := m.tasks["old"]; ok {
+		t.Error("idle task kept past the TTL")
+	}
+	if s := m.stats(); len(s.Tasks) != 1 || s.Domains["a.example.com"].Requests != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+}
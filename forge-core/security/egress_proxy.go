@@ -1,8 +1,10 @@
 package security
 
 import (
+	"bufio"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -34,9 +36,34 @@ type EgressProxy struct {
 	addr          string // "127.0.0.1:<port>" — HTTP listener
 	socksListener net.Listener
 	socksAddr     string // "127.0.0.1:<port>" — SOCKS5 listener (empty when disabled)
+	socketPath    string // Unix socket the HTTP proxy also serves on (empty when disabled)
 	OnAttempt     func(EgressAttempt)
+
+	// Authorize, when set, is consulted for every allowlisted
+	// non-local destination before OnAttempt fires; a non-nil error
+	// blocks it. Same contract as EgressEnforcer.Authorize.
+	Authorize func(ctx context.Context, a EgressAttempt) error
+
+	constraints *EgressConstraints
+	sniPolicy   SNIPolicy
+	meter       *egressMeter
 }
 
+// SNIPolicy selects how the proxy filters CONNECT tunnels by the server
+// name in their TLS ClientHello.
+type SNIPolicy string
+
+const (
+	// SNIAllowlist blocks a tunnel whose ClientHello names a server the
+	// allowlist does not allow. Tunnels without TLS or SNI pass. The
+	// default.
+	SNIAllowlist SNIPolicy = "allowlist"
+	// SNIRequire also blocks tunnels that carry no TLS or no SNI.
+	SNIRequire SNIPolicy = "require"
+	// SNIOff relays tunnels without looking at them.
+	SNIOff SNIPolicy = "off"
+)
+
 // EgressAttempt describes a single egress decision for audit correlation.
 // TaskID and CorrelationID are recovered from the Proxy-Authorization header
 // the subprocess sends (see identityFromRequest) — the caller injects them as
@@ -44,11 +71,20 @@ type EgressProxy struct {
 // credentials on every request and CONNECT. They are empty when the client
 // doesn't send credentials (arbitrary binaries), which degrades gracefully to
 // the pre-#338 behaviour: a domain-only event with no task attribution.
+//
+// Scheme, Port and Method describe the request as far as the path
+// that saw it can tell: CONNECT tunnels carry no method and raw TCP no
+// scheme. Reason is set when EgressConstraints blocked an allowlisted
+// destination (see the Reason constants).
 type EgressAttempt struct {
 	Domain        string
 	Allowed       bool
 	TaskID        string
 	CorrelationID string
+	Scheme        string
+	Port          int
+	Method        string
+	Reason        string
 }
 
 // egressIdentity carries the per-request task/invocation IDs recovered from the
@@ -72,6 +108,8 @@ func NewEgressProxy(matcher *DomainMatcher, allowPrivateIPs bool, allowedPrivate
 		matcher:       matcher,
 		safeDialer:    sd,
 		safeTransport: NewSafeTransport(nil, allowPrivateIPs, allowedPrivateCIDRs),
+		sniPolicy:     SNIAllowlist,
+		meter:         newEgressMeter(),
 	}
 }
 
@@ -82,10 +120,67 @@ func (p *EgressProxy) SetTCPMatcher(m *TCPMatcher) {
 	p.tcpMatcher = m
 }
 
+// SetConstraints installs scheme, port, method and response-size
+// constraints for allowlisted destinations. Plain HTTP requests are
+// checked in full; CONNECT tunnels count as https and are checked on
+// port and relayed size only, since the method travels inside TLS.
+// Raw-TCP destinations allowed by the TCP matcher are not constrained.
+// Must be called before Start.
+func (p *EgressProxy) SetConstraints(c *EgressConstraints) {
+	p.constraints = c
+}
+
+// SetSNIPolicy selects how CONNECT tunnels are filtered by SNI. An
+// empty policy keeps the default, SNIAllowlist. Must be called before
+// Start.
+func (p *EgressProxy) SetSNIPolicy(policy SNIPolicy) {
+	if policy != "" {
+		p.sniPolicy = policy
+	}
+}
+
+// SetTaskQuota bounds the bytes and requests each task may push through
+// the proxy. A task over quota has its new requests refused and its
+// open tunnels cut. Must be called before Start.
+func (p *EgressProxy) SetTaskQuota(q EgressTaskQuota) {
+	p.meter.quota = q
+}
+
+// Stats returns the proxy's per-domain counters and per-task quota
+// usage.
+func (p *EgressProxy) Stats() EgressProxyStats {
+	return p.meter.stats()
+}
+
+// SetUnixSocket makes Start also serve the HTTP proxy on a Unix domain
+// socket at path (see ListenUnix), for sidecars that share a volume with
+// the agent rather than its network namespace. The loopback listener
+// the agent's own subprocesses use is unaffected. Must be called before
+// Start.
+func (p *EgressProxy) SetUnixSocket(path string) {
+	p.socketPath = path
+}
+
+// SocketPath returns the Unix socket the proxy serves on, or empty when
+// SetUnixSocket was not called.
+func (p *EgressProxy) SocketPath() string {
+	return p.socketPath
+}
+
+// activeConstraints returns the constraints to enforce, none in
+// dev-open mode.
+func (p *EgressProxy) activeConstraints() *EgressConstraints {
+	if p.matcher.Mode() == ModeDevOpen {
+		return nil
+	}
+	return p.constraints
+}
+
 // Start binds to 127.0.0.1:0 (random ports) and begins serving.
 // Returns the HTTP proxy URL (e.g., "http://127.0.0.1:54321"). The SOCKS5
 // listener, if TCPMatcher is non-empty, is started at the same time and its
-// URL is available via SOCKSURL().
+// URL is available via SOCKSURL(), as is the Unix socket listener when
+// SetUnixSocket was called.
 func (p *EgressProxy) Start(ctx context.Context) (string, error) {
 	ln, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -105,15 +200,24 @@ func (p *EgressProxy) Start(ctx context.Context) (string, error) {
 
 	go p.srv.Serve(ln) //nolint:errcheck
 
+	if p.socketPath != "" {
+		unixLn, err := ListenUnix(p.socketPath)
+		if err != nil {
+			_ = ln.Close()
+			return "", fmt.Errorf("egress proxy unix listen: %w", err)
+		}
+		go p.srv.Serve(unixLn) //nolint:errcheck
+	}
+
 	// SOCKS5 listener — only when raw-TCP egress is configured. Skipping
 	// this by default means the deploy surface is unchanged for agents that
 	// only need HTTP.
 	if p.tcpMatcher != nil && !p.tcpMatcher.Empty() {
 		socksLn, err := net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
-			// Roll back the HTTP listener so we don't leave the process in a
-			// half-started state.
-			_ = ln.Close()
+			// Roll back the HTTP listeners so we don't leave the process in
+			// a half-started state.
+			_ = p.srv.Close()
 			return "", fmt.Errorf("egress proxy socks5 listen: %w", err)
 		}
 		p.socksListener = socksLn
@@ -181,14 +285,34 @@ func (p *EgressProxy) handleRequest(w http.ResponseWriter, req *http.Request) {
 func (p *EgressProxy) handleHTTP(w http.ResponseWriter, req *http.Request) {
 	host := extractHost(req.URL.Host)
 	id := identityFromRequest(req)
+	attempt := EgressAttempt{
+		Domain: host,
+		Scheme: req.URL.Scheme,
+		Port:   defaultPort(req.URL.Port(), req.URL.Scheme),
+		Method: req.Method,
+	}
 
-	if !p.checkDomain(host, id) {
+	if p.meter.exhausted(id.taskID) {
+		attempt.Reason = ReasonQuota
+		p.fireAttemptRaw(attempt, id)
+		http.Error(w, fmt.Sprintf("egress proxy: task %q is over its egress quota", id.taskID), http.StatusTooManyRequests)
+		return
+	}
+	if !p.check(attempt, id) {
 		http.Error(w, fmt.Sprintf("egress proxy: domain %q blocked", host), http.StatusForbidden)
 		return
 	}
+	p.meter.request(id.taskID, host)
+	quotaHit := attempt
+	quotaHit.Reason = ReasonQuota
+	flow := &meter{m: p.meter, taskID: id.taskID, domain: host, onExceed: func() { p.fireAttemptRaw(quotaHit, id) }}
 
 	// Forward the request
-	outReq, err := http.NewRequestWithContext(req.Context(), req.Method, req.URL.String(), req.Body)
+	var reqBody io.Reader = http.NoBody
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody = &meteredReader{r: req.Body, f: flow, sent: true}
+	}
+	outReq, err := http.NewRequestWithContext(req.Context(), req.Method, req.URL.String(), reqBody)
 	if err != nil {
 		http.Error(w, "egress proxy: failed to create request", http.StatusBadGateway)
 		return
@@ -210,6 +334,17 @@ func (p *EgressProxy) handleHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
+	var body io.Reader = &meteredReader{r: resp.Body, f: flow}
+	if limit := p.activeConstraints().MaxResponseBytes(host); limit > 0 && !IsLocalhost(host) {
+		attempt.Reason = ReasonResponseSize
+		if resp.ContentLength > limit {
+			p.fireAttemptRaw(attempt, id)
+			http.Error(w, "egress proxy: "+responseTooLarge(host, limit).Error(), http.StatusBadGateway)
+			return
+		}
+		body = &cappedReader{r: body, host: host, limit: limit, onExceed: func() { p.fireAttemptRaw(attempt, id) }}
+	}
+
 	// Copy response headers
 	for k, vv := range resp.Header {
 		for _, v := range vv {
@@ -217,7 +352,14 @@ func (p *EgressProxy) handleHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 	w.WriteHeader(resp.StatusCode)
-	io.Copy(w, resp.Body) //nolint:errcheck
+	if _, err := io.Copy(w, body); err != nil {
+		var ce *ConstraintError
+		if errors.As(err, &ce) || errors.Is(err, errQuotaExceeded) {
+			// The status is already out; abort so the client sees a
+			// truncated response rather than a complete short one.
+			panic(http.ErrAbortHandler)
+		}
+	}
 }
 
 // handleConnect handles HTTPS CONNECT tunneling. Delegates policy + dial to
@@ -236,14 +378,31 @@ func (p *EgressProxy) handleConnect(w http.ResponseWriter, req *http.Request) {
 	// events (one from ValidateAndDial, one on failure of the upgrade path)
 	// stay one-per-attempt because the failure path returns before the dial.
 	id := identityFromRequest(req)
+	attempt := EgressAttempt{Domain: host, Scheme: "https", Port: defaultPort(port, "https")}
+
+	if p.meter.exhausted(id.taskID) {
+		attempt.Reason = ReasonQuota
+		p.fireAttemptRaw(attempt, id)
+		http.Error(w, fmt.Sprintf("egress proxy: task %q is over its egress quota", id.taskID), http.StatusTooManyRequests)
+		return
+	}
 
 	// HTTP-CONNECT audits keep the pre-#337 shape (hostname-only) so
 	// downstream consumers that key events by hostname keep working.
-	upstream, err := p.validateAndDialWithIdentity(req.Context(), host, port, host, id)
+	upstream, err := p.validateAndDialWithIdentity(req.Context(), "https", host, port, host, id)
 	if err != nil {
 		http.Error(w, "egress proxy: "+err.Error(), http.StatusForbidden)
 		return
 	}
+	p.meter.request(id.taskID, host)
+	if limit := p.activeConstraints().MaxResponseBytes(host); limit > 0 && !IsLocalhost(host) {
+		a := attempt
+		a.Reason = ReasonResponseSize
+		upstream = &cappedConn{Conn: upstream, r: &cappedReader{r: upstream, host: host, limit: limit, onExceed: func() { p.fireAttemptRaw(a, id) }}}
+	}
+	quotaHit := attempt
+	quotaHit.Reason = ReasonQuota
+	upstream = &meteredConn{Conn: upstream, f: &meter{m: p.meter, taskID: id.taskID, domain: host, onExceed: func() { p.fireAttemptRaw(quotaHit, id) }}}
 
 	// Respond 200 to signal the client that the tunnel is established
 	w.WriteHeader(http.StatusOK)
@@ -254,17 +413,67 @@ func (p *EgressProxy) handleConnect(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, "egress proxy: hijacking not supported", http.StatusInternalServerError)
 		return
 	}
-	clientConn, _, err := hijacker.Hijack()
+	clientConn, rw, err := hijacker.Hijack()
 	if err != nil {
 		upstream.Close() //nolint:errcheck
 		return
 	}
+	if rw.Reader.Buffered() > 0 {
+		// The client sent its first bytes before seeing the 200.
+		clientConn = &bufferedConn{Conn: clientConn, r: rw.Reader}
+	}
 
 	// The hijacked conn is now owned by the relay goroutines — handleConnect
 	// must return so the http.Server can accept the next connection.
-	go relayPair(clientConn, upstream)
+	go p.tunnel(clientConn, upstream, attempt, id)
+}
+
+// tunnel checks a CONNECT tunnel's SNI against the SNI policy, then
+// relays it. A blocked tunnel is closed on both ends and audited with
+// Reason "sni" and the server name as its domain; that event follows
+// the "allowed" one the CONNECT itself produced.
+func (p *EgressProxy) tunnel(client, upstream net.Conn, a EgressAttempt, id egressIdentity) {
+	if p.sniPolicy == SNIOff || p.matcher.Mode() == ModeDevOpen || IsLocalhost(a.Domain) {
+		relayPair(client, upstream)
+		return
+	}
+	serverName, hello, isTLS, err := peekServerName(client)
+	if err != nil {
+		_ = client.Close()
+		_ = upstream.Close()
+		return
+	}
+	blocked := serverName != "" && serverName != a.Domain && !p.matcher.IsAllowed(serverName)
+	if p.sniPolicy == SNIRequire && (!isTLS || serverName == "") {
+		blocked = true
+	}
+	if blocked {
+		if serverName != "" {
+			a.Domain = serverName
+		}
+		a.Reason = ReasonSNI
+		p.fireAttemptRaw(a, id)
+		_ = client.Close()
+		_ = upstream.Close()
+		return
+	}
+	if _, err := upstream.Write(hello); err != nil {
+		_ = client.Close()
+		_ = upstream.Close()
+		return
+	}
+	relayPair(client, upstream)
 }
 
+// bufferedConn reads through r, which holds bytes already taken off the
+// conn, before reading the conn itself.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) { return c.r.Read(p) }
+
 // relayPair blind-relays bytes between two conns in both directions and
 // BLOCKS until both directions finish. Callers that need async behavior
 // (HTTP-CONNECT, where the hijacked conn's lifetime is owned by the spawned
@@ -290,34 +499,53 @@ func relayPair(a, b net.Conn) {
 
 // checkDomain validates a host against the matcher, allowing localhost always.
 func (p *EgressProxy) checkDomain(host string, id egressIdentity) bool {
+	return p.check(EgressAttempt{Domain: host}, id)
+}
+
+// check validates a plain HTTP request against the matcher and the
+// constraints, allowing localhost always, and fires one audit event.
+func (p *EgressProxy) check(a EgressAttempt, id egressIdentity) bool {
 	// Reject non-standard IP formats early
-	if err := ValidateHostIP(host); err != nil {
-		p.fireCallback(host, false, id)
+	if err := ValidateHostIP(a.Domain); err != nil {
+		p.fireAttemptRaw(a, id)
 		return false
 	}
 
 	// Localhost is always allowed
-	if IsLocalhost(host) {
-		p.fireCallback(host, true, id)
+	if IsLocalhost(a.Domain) {
+		a.Allowed = true
+		p.fireAttemptRaw(a, id)
 		return true
 	}
 
-	allowed := p.matcher.IsAllowed(host)
-	p.fireCallback(host, allowed, id)
-	return allowed
+	a.Allowed = p.matcher.IsAllowed(a.Domain) && p.authorize(context.Background(), a.Domain, id) == nil
+	if a.Allowed && a.Scheme != "" {
+		if err := p.activeConstraints().Check(a.Scheme, a.Domain, a.Port, a.Method); err != nil {
+			a.Allowed = false
+			a.Reason = constraintReason(err)
+		}
+	}
+	p.fireAttemptRaw(a, id)
+	return a.Allowed
 }
 
-func (p *EgressProxy) fireCallback(domain string, allowed bool, id egressIdentity) {
-	if p.OnAttempt != nil {
-		p.OnAttempt(EgressAttempt{
-			Domain:        domain,
-			Allowed:       allowed,
-			TaskID:        id.taskID,
-			CorrelationID: id.correlationID,
-		})
+// authorize runs the Authorize hook, if any, for an allowlisted host.
+func (p *EgressProxy) authorize(ctx context.Context, host string, id egressIdentity) error {
+	if p.Authorize == nil {
+		return nil
 	}
+	return p.Authorize(ctx, EgressAttempt{Domain: host, Allowed: true, TaskID: id.taskID, CorrelationID: id.correlationID})
+}
+
+// cappedConn is an upstream tunnel conn whose reads go through a
+// cappedReader, bounding what a CONNECT tunnel relays back.
+type cappedConn struct {
+	net.Conn
+	r *cappedReader
 }
 
+func (c *cappedConn) Read(p []byte) (int, error) { return c.r.Read(p) }
+
 // identityFromRequest recovers the task/invocation IDs the caller stashed in
 // the proxy credentials. HTTP clients that see userinfo in the HTTP_PROXY URL
 // replay it as a "Proxy-Authorization: Basic base64(user:pass)" header on every
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/base64"
+	"errors"
 	"io"
 	"net"
 	"net/http"
@@ -495,3 +496,32 @@ func TestEgressProxyAttributesIdentityCONNECT(t *testing.T) {
 		t.Errorf("CorrelationID = %q, want %q", got.CorrelationID, "corr-9")
 	}
 }
+
+func TestEgressProxyAuthorize(t *testing.T) {
+	matcher := NewDomainMatcher(ModeAllowlist, []string{"api.example.com", "db.example.com"})
+	proxy := NewEgressProxy(matcher, false, nil)
+	var got []EgressAttempt
+	proxy.OnAttempt = func(a EgressAttempt) { got = append(got, a) }
+	proxy.Authorize = func(_ context.Context, a EgressAttempt) error {
+		if a.Domain == "db.example.com" {
+			return errDeniedForTest
+		}
+		return nil
+	}
+
+	id := egressIdentity{taskID: "t1"}
+	if !proxy.checkDomain("api.example.com", id) {
+		t.Error("authorized domain blocked")
+	}
+	if proxy.checkDomain("db.example.com", id) {
+		t.Error("policy-denied domain allowed")
+	}
+	if _, err := proxy.ValidateAndDial(context.Background(), "db.example.com", "5432"); err == nil {
+		t.Error("policy-denied dial allowed")
+	}
+	if len(got) != 3 || !got[0].Allowed || got[1].Allowed || got[2].Allowed || got[1].TaskID != "t1" {
+		t.Errorf("attempts = %+v", got)
+	}
+}
+
+var errDeniedForTest = errors.New("denied by policy")
@@ -0,0 +1,236 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Reasons an EgressConstraints check blocks a request, recorded on
+// EgressAttempt.Reason and in the egress audit event.
+const (
+	ReasonScheme       = "scheme"
+	ReasonPort         = "port"
+	ReasonMethod       = "method"
+	ReasonResponseSize = "response_size"
+)
+
+// ConstraintError is returned when a request to an allowlisted domain
+// breaks one of its constraints.
+type ConstraintError struct {
+	Host   string
+	Reason string
+	Detail string
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("domain %q: %s", e.Host, e.Detail)
+}
+
+// EgressConstraints narrows what an allowlisted domain may be used
+// for: the URL scheme, the port, the HTTP method, and how large a
+// response may be. It is consulted after the DomainMatcher has allowed
+// a host and can only block, never allow. A nil *EgressConstraints
+// imposes nothing.
+type EgressConstraints struct {
+	schemes          map[string]bool
+	maxResponseBytes int64
+	exact            map[string]*egressRule
+	wildcards        []*egressRule // longest suffix first
+}
+
+type egressRule struct {
+	suffix           string // ".github.com" for wildcard rules
+	ports            []int
+	methods          []string // upper case
+	maxResponseBytes int64
+}
+
+// NewEgressConstraints builds constraints from the egress config.
+// Empty schemes means https only. Invalid schemes, ports, methods and
+// sizes are reported together.
+func NewEgressConstraints(schemes []string, maxResponseBytes int64, rules []types.EgressRule) (*EgressConstraints, error) {
+	var errs []error
+	if maxResponseBytes < 0 {
+		errs = append(errs, errors.New("max_response_bytes must not be negative"))
+	}
+	c := &EgressConstraints{
+		schemes:          make(map[string]bool),
+		maxResponseBytes: maxResponseBytes,
+		exact:            make(map[string]*egressRule),
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	for _, s := range schemes {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s != "http" && s != "https" {
+			errs = append(errs, fmt.Errorf("allowed_schemes: %q must be http or https", s))
+			continue
+		}
+		c.schemes[s] = true
+	}
+
+	for i, r := range rules {
+		domain := strings.ToLower(strings.TrimSpace(r.Domain))
+		if domain == "" {
+			errs = append(errs, fmt.Errorf("rules[%d]: domain is required", i))
+			continue
+		}
+		rule := &egressRule{maxResponseBytes: r.MaxResponseBytes}
+		if r.MaxResponseBytes < 0 {
+			errs = append(errs, fmt.Errorf("rules[%d]: max_response_bytes must not be negative", i))
+		}
+		for _, p := range r.Ports {
+			if p < 1 || p > 65535 {
+				errs = append(errs, fmt.Errorf("rules[%d]: port %d out of range 1-65535", i, p))
+			}
+		}
+		rule.ports = r.Ports
+		for _, m := range r.Methods {
+			m = strings.ToUpper(strings.TrimSpace(m))
+			if m == "" || strings.ContainsFunc(m, func(r rune) bool { return r < 'A' || r > 'Z' }) {
+				errs = append(errs, fmt.Errorf("rules[%d]: invalid method %q", i, m))
+				continue
+			}
+			rule.methods = append(rule.methods, m)
+		}
+
+		if strings.HasPrefix(domain, "*.") {
+			rule.suffix = domain[1:]
+			c.wildcards = append(c.wildcards, rule)
+			continue
+		}
+		if _, dup := c.exact[domain]; dup {
+			errs = append(errs, fmt.Errorf("rules[%d]: duplicate rule for %q", i, domain))
+		}
+		c.exact[domain] = rule
+	}
+	slices.SortStableFunc(c.wildcards, func(a, b *egressRule) int {
+		return len(b.suffix) - len(a.suffix)
+	})
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("egress constraints: %w", errors.Join(errs...))
+	}
+	return c, nil
+}
+
+// rule returns the most specific rule for host, or nil.
+func (c *EgressConstraints) rule(host string) *egressRule {
+	if r, ok := c.exact[host]; ok {
+		return r
+	}
+	for _, r := range c.wildcards {
+		if strings.HasSuffix(host, r.suffix) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Check reports whether a request to host may proceed. An empty scheme
+// skips the scheme check and an empty method the method check, for
+// callers that cannot see them (raw TCP, CONNECT tunnels). port is the
+// destination port; callers resolve the scheme's default themselves.
+func (c *EgressConstraints) Check(scheme, host string, port int, method string) error {
+	if c == nil {
+		return nil
+	}
+	host = strings.ToLower(host)
+	if scheme != "" && !c.schemes[strings.ToLower(scheme)] {
+		return &ConstraintError{Host: host, Reason: ReasonScheme, Detail: fmt.Sprintf("scheme %q not allowed", scheme)}
+	}
+	r := c.rule(host)
+	if r == nil {
+		return nil
+	}
+	if len(r.ports) > 0 && !slices.Contains(r.ports, port) {
+		return &ConstraintError{Host: host, Reason: ReasonPort, Detail: fmt.Sprintf("port %d not allowed", port)}
+	}
+	if method != "" && len(r.methods) > 0 && !slices.Contains(r.methods, strings.ToUpper(method)) {
+		return &ConstraintError{Host: host, Reason: ReasonMethod, Detail: fmt.Sprintf("method %s not allowed", method)}
+	}
+	return nil
+}
+
+// MaxResponseBytes returns the response size cap for host, or 0 when
+// responses from it are not capped.
+func (c *EgressConstraints) MaxResponseBytes(host string) int64 {
+	if c == nil {
+		return 0
+	}
+	if r := c.rule(strings.ToLower(host)); r != nil && r.maxResponseBytes > 0 {
+		return r.maxResponseBytes
+	}
+	return c.maxResponseBytes
+}
+
+// constraintReason returns the Reason of a ConstraintError, or "".
+func constraintReason(err error) string {
+	var ce *ConstraintError
+	if errors.As(err, &ce) {
+		return ce.Reason
+	}
+	return ""
+}
+
+// responseTooLarge is the ConstraintError for a response over limit.
+func responseTooLarge(host string, limit int64) *ConstraintError {
+	return &ConstraintError{Host: host, Reason: ReasonResponseSize, Detail: fmt.Sprintf("response exceeds %d bytes", limit)}
+}
+
+// defaultPort parses port, falling back to the scheme's default when
+// it is empty.
+func defaultPort(port, scheme string) int {
+	if n, err := strconv.Atoi(port); err == nil {
+		return n
+	}
+	if strings.EqualFold(scheme, "http") {
+		return 80
+	}
+	return 443
+}
+
+// cappedReader fails a read that would take the total past limit,
+// calling onExceed once when it does.
+type cappedReader struct {
+	r        io.Reader
+	host     string
+	limit    int64
+	n        int64
+	err      error
+	onExceed func()
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.n > c.limit {
+		c.err = c.exceeded()
+		keep := max(int64(n)-(c.n-c.limit), 0)
+		return int(keep), c.err
+	}
+	return n, err
+}
+
+func (c *cappedReader) exceeded() error {
+	if c.onExceed != nil {
+		c.onExceed()
+	}
+	return fmt.Errorf("egress blocked: %w", responseTooLarge(c.host, c.limit))
+}
+
+// cappedBody is a response body read through a cappedReader.
+type cappedBody struct {
+	*cappedReader
+	io.Closer
+}
@@ -0,0 +1,259 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestEgressConstraintsCheck(t *testing.T) {
+	c, err := NewEgressConstraints(nil, 0, []types.EgressRule{
+		{Domain: "api.example.com", Ports: []int{443, 8443}, Methods: []string{"get", "POST"}},
+		{Domain: "*.example.com", Methods: []string{"GET"}},
+		{Domain: "*.files.example.com", Ports: []int{443}},
+	})
+	if err != nil {
+		t.Fatalf("NewEgressConstraints: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		scheme string
+		host   string
+		port   int
+		method string
+		reason string
+	}{
+		{"https default", "https", "other.test", 443, "DELETE", ""},
+		{"http blocked by default", "http", "other.test", 80, "GET", ReasonScheme},
+		{"no scheme skips check", "", "other.test", 80, "GET", ""},
+		{"exact rule port", "https", "api.example.com", 8443, "POST", ""},
+		{"exact rule bad port", "https", "api.example.com", 8080, "GET", ReasonPort},
+		{"exact rule bad method", "https", "api.example.com", 443, "DELETE", ReasonMethod},
+		{"exact beats wildcard", "https", "API.example.com", 443, "post", ""},
+		{"wildcard method", "https", "www.example.com", 443, "PUT", ReasonMethod},
+		{"longest suffix wins", "https", "a.files.example.com", 443, "PUT", ""},
+		{"longest suffix port", "https", "a.files.example.com", 8443, "GET", ReasonPort},
+		{"no method skips check", "https", "www.example.com", 443, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Check(tt.scheme, tt.host, tt.port, tt.method)
+			if got := constraintReason(err); got != tt.reason {
+				t.Errorf("Check = %v, want reason %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestEgressConstraintsNil(t *testing.T) {
+	var c *EgressConstraints
+	if err := c.Check("http", "example.com", 80, "DELETE"); err != nil {
+		t.Errorf("nil constraints blocked: %v", err)
+	}
+	if n := c.MaxResponseBytes("example.com"); n != 0 {
+		t.Errorf("nil constraints cap = %d, want 0", n)
+	}
+}
+
+func TestEgressConstraintsMaxResponseBytes(t *testing.T) {
+	c, err := NewEgressConstraints([]string{"https", "http"}, 1000, []types.EgressRule{
+		{Domain: "big.example.com", MaxResponseBytes: 5000},
+		{Domain: "*.example.com", Ports: []int{443}},
+	})
+	if err != nil {
+		t.Fatalf("NewEgressConstraints: %v", err)
+	}
+	for host, want := range map[string]int64{"big.example.com": 5000, "www.example.com": 1000, "other.test": 1000} {
+		if got := c.MaxResponseBytes(host); got != want {
+			t.Errorf("MaxResponseBytes(%q) = %d, want %d", host, got, want)
+		}
+	}
+	if err := c.Check("http", "other.test", 80, "GET"); err != nil {
+		t.Errorf("http listed in allowed_schemes but blocked: %v", err)
+	}
+}
+
+func TestNewEgressConstraintsInvalid(t *testing.T) {
+	_, err := NewEgressConstraints([]string{"ftp"}, -1, []types.EgressRule{
+		{Ports: []int{443}},
+		{Domain: "a.example.com", Ports: []int{0, 70000}},
+		{Domain: "b.example.com", Methods: []string{"GE T"}},
+		{Domain: "c.example.com"},
+		{Domain: "C.example.com"},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{`"ftp"`, "max_response_bytes", "rules[0]: domain", "port 0", "port 70000", "invalid method", "duplicate rule"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestEgressEnforcerConstraints(t *testing.T) {
+	ok := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	enforcer := NewEgressEnforcer(ok, ModeAllowlist, []string{"api.example.com"}, false, nil)
+	c, err := NewEgressConstraints(nil, 0, []types.EgressRule{{Domain: "api.example.com", Methods: []string{"GET"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	enforcer.SetConstraints(c)
+	var violations []EgressAttempt
+	enforcer.OnViolation = func(_ context.Context, a EgressAttempt) { violations = append(violations, a) }
+	var allowed []bool
+	enforcer.OnAttempt = func(_ context.Context, _ string, ok bool) { allowed = append(allowed, ok) }
+
+	for _, u := range []struct{ method, url string }{
+		{http.MethodGet, "http://api.example.com/"},
+		{http.MethodPost, "https://api.example.com/"},
+	} {
+		req, _ := http.NewRequest(u.method, u.url, nil)
+		if _, err := enforcer.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "egress blocked") {
+			t.Errorf("%s %s: expected block, got %v", u.method, u.url, err)
+		}
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+	if _, err := enforcer.RoundTrip(req); err != nil {
+		t.Fatalf("allowed request: %v", err)
+	}
+
+	if len(violations) != 2 {
+		t.Fatalf("violations = %+v, want 2", violations)
+	}
+	if v := violations[0]; v.Reason != ReasonScheme || v.Scheme != "http" || v.Port != 80 || v.Method != http.MethodGet {
+		t.Errorf("scheme violation = %+v", v)
+	}
+	if v := violations[1]; v.Reason != ReasonMethod || v.Port != 443 || v.Method != http.MethodPost {
+		t.Errorf("method violation = %+v", v)
+	}
+	if len(allowed) != 1 || !allowed[0] {
+		t.Errorf("OnAttempt saw %v, want [true]", allowed)
+	}
+}
+
+func TestEgressEnforcerConstraintsFallBackToOnAttempt(t *testing.T) {
+	enforcer := NewEgressEnforcer(nil, ModeAllowlist, []string{"api.example.com"}, false, nil)
+	c, _ := NewEgressConstraints(nil, 0, nil)
+	enforcer.SetConstraints(c)
+	var allowed []bool
+	enforcer.OnAttempt = func(_ context.Context, _ string, ok bool) { allowed = append(allowed, ok) }
+
+	req, _ := http.NewRequest(http.MethodGet, "http://api.example.com/", nil)
+	if _, err := enforcer.RoundTrip(req); err == nil {
+		t.Fatal("expected http to be blocked")
+	}
+	if len(allowed) != 1 || allowed[0] {
+		t.Errorf("OnAttempt saw %v, want [false]", allowed)
+	}
+}
+
+func TestEgressEnforcerMaxResponseBytes(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), ContentLength: -1}
+		if r.URL.Path == "/sized" {
+			resp.ContentLength = int64(len(body))
+		}
+		return resp, nil
+	})
+	enforcer := NewEgressEnforcer(base, ModeAllowlist, []string{"api.example.com", "big.example.com"}, false, nil)
+	c, _ := NewEgressConstraints(nil, 50, []types.EgressRule{{Domain: "big.example.com", MaxResponseBytes: 1000}})
+	enforcer.SetConstraints(c)
+	var reasons []string
+	enforcer.OnViolation = func(_ context.Context, a EgressAttempt) { reasons = append(reasons, a.Reason) }
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/sized", nil)
+	if _, err := enforcer.RoundTrip(req); err == nil {
+		t.Error("declared oversize response: expected error")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://api.example.com/stream", nil)
+	resp, err := enforcer.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	got, err := io.ReadAll(resp.Body)
+	var ce *ConstraintError
+	if !errors.As(err, &ce) || ce.Reason != ReasonResponseSize {
+		t.Errorf("streamed oversize response: err = %v", err)
+	}
+	if len(got) != 50 {
+		t.Errorf("read %d bytes before the cap, want 50", len(got))
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://big.example.com/sized", nil)
+	resp, err = enforcer.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("per-domain cap: %v", err)
+	}
+	if got, err := io.ReadAll(resp.Body); err != nil || len(got) != len(body) {
+		t.Errorf("per-domain cap: read %d bytes, err %v", len(got), err)
+	}
+
+	if len(reasons) != 2 || reasons[0] != ReasonResponseSize || reasons[1] != ReasonResponseSize {
+		t.Errorf("violations = %v, want two response_size", reasons)
+	}
+}
+
+func TestEgressEnforcerConstraintsDevOpen(t *testing.T) {
+	ok := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	enforcer := NewEgressEnforcer(ok, ModeDevOpen, nil, false, nil)
+	c, _ := NewEgressConstraints(nil, 0, nil)
+	enforcer.SetConstraints(c)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://anything.example.com/", nil)
+	if _, err := enforcer.RoundTrip(req); err != nil {
+		t.Errorf("dev-open should ignore constraints: %v", err)
+	}
+}
+
+func TestEgressProxyConstraints(t *testing.T) {
+	proxy := NewEgressProxy(NewDomainMatcher(ModeAllowlist, []string{"api.example.com"}), false, nil)
+	c, _ := NewEgressConstraints(nil, 0, []types.EgressRule{{Domain: "api.example.com", Ports: []int{443}}})
+	proxy.SetConstraints(c)
+	var attempts []EgressAttempt
+	proxy.OnAttempt = func(a EgressAttempt) { attempts = append(attempts, a) }
+
+	if proxy.check(EgressAttempt{Domain: "api.example.com", Scheme: "http", Port: 80, Method: "GET"}, egressIdentity{}) {
+		t.Error("plain HTTP should be blocked by the default schemes")
+	}
+	if _, err := proxy.validateAndDialWithIdentity(context.Background(), "https", "api.example.com", "8443", "api.example.com", egressIdentity{}); err == nil {
+		t.Error("CONNECT to a port outside the rule should be blocked")
+	}
+
+	if len(attempts) != 2 {
+		t.Fatalf("attempts = %+v, want 2", attempts)
+	}
+	if a := attempts[0]; a.Allowed || a.Reason != ReasonScheme || a.Method != "GET" {
+		t.Errorf("HTTP attempt = %+v", a)
+	}
+	if a := attempts[1]; a.Allowed || a.Reason != ReasonPort || a.Scheme != "https" || a.Port != 8443 {
+		t.Errorf("CONNECT attempt = %+v", a)
+	}
+}
+
+func TestCappedReader(t *testing.T) {
+	var exceeded int
+	r := &cappedReader{r: strings.NewReader(strings.Repeat("y", 64)), host: "api.example.com", limit: 10, onExceed: func() { exceeded++ }}
+	got, err := io.ReadAll(r)
+	if err == nil || len(got) != 10 {
+		t.Errorf("read %d bytes, err %v; want 10 and an error", len(got), err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil {
+		t.Error("read after the cap should keep failing")
+	}
+	if exceeded != 1 {
+		t.Errorf("onExceed fired %d times, want 1", exceeded)
+	}
+}
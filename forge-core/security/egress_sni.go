@@ -0,0 +1,77 @@
+package security
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ReasonSNI marks a CONNECT tunnel blocked because the TLS server name
+// its client asked for is not allowed.
+const ReasonSNI = "sni"
+
+// sniPeekTimeout bounds how long a tunnel may take to send its first
+// TLS record before the proxy gives up on it.
+const sniPeekTimeout = 10 * time.Second
+
+// errSNIPeeked aborts the handshake peekServerName drives once the
+// ClientHello has been parsed.
+var errSNIPeeked = errors.New("sni peeked")
+
+// peekServerName reads the TLS ClientHello a CONNECT client sends first
+// and returns the server name it asks for, without terminating TLS:
+// crypto/tls parses the hello from a conn that records what it reads
+// and refuses writes, and the handshake is abandoned as soon as the
+// hello is seen. The recorded bytes must be replayed upstream before
+// relaying. isTLS is false when the first bytes are not a ClientHello
+// or none arrive within sniPeekTimeout; the server name is empty when
+// the hello carries none.
+func peekServerName(conn net.Conn) (serverName string, hello []byte, isTLS bool, err error) {
+	if err := conn.SetReadDeadline(time.Now().Add(sniPeekTimeout)); err != nil {
+		return "", nil, false, err
+	}
+	defer conn.SetReadDeadline(time.Time{}) //nolint:errcheck
+
+	rec := &recordingConn{Conn: conn}
+	srv := tls.Server(rec, &tls.Config{
+		GetConfigForClient: func(h *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = strings.ToLower(h.ServerName)
+			isTLS = true
+			return nil, errSNIPeeked
+		},
+	})
+	herr := srv.Handshake()
+	hello = rec.buf.Bytes()
+	if isTLS {
+		return serverName, hello, true, nil
+	}
+	if errors.Is(herr, io.EOF) || errors.Is(herr, net.ErrClosed) {
+		// The client left before sending anything worth relaying.
+		return "", hello, false, herr
+	}
+	// Not TLS, or a server-speaks-first protocol that sent nothing
+	// before the deadline: relay what was read as is.
+	return "", hello, false, nil
+}
+
+// recordingConn keeps every byte read through it and fails writes, so
+// a tls.Server reading a ClientHello can neither answer the client nor
+// lose what it consumed.
+type recordingConn struct {
+	net.Conn
+	buf bytes.Buffer
+}
+
+func (c *recordingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.buf.Write(p[:n])
+	return n, err
+}
+
+func (c *recordingConn) Write([]byte) (int, error) {
+	return 0, errSNIPeeked
+}
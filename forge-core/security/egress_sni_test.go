@@ -0,0 +1,108 @@
+package security
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// sendClientHello starts a TLS handshake for serverName on conn. The
+// handshake never completes; the caller closes conn to end it.
+func sendClientHello(conn net.Conn, serverName string) {
+	go tls.Client(conn, &tls.Config{ServerName: serverName, InsecureSkipVerify: true}).Handshake() //nolint:errcheck
+}
+
+func TestPeekServerName(t *testing.T) {
+	client, proxySide := net.Pipe()
+	defer client.Close()    //nolint:errcheck
+	defer proxySide.Close() //nolint:errcheck
+	sendClientHello(client, "API.example.com")
+
+	name, hello, isTLS, err := peekServerName(proxySide)
+	if err != nil || !isTLS {
+		t.Fatalf("peekServerName: isTLS=%v err=%v", isTLS, err)
+	}
+	if name != "api.example.com" {
+		t.Errorf("server name = %q", name)
+	}
+	if len(hello) == 0 || hello[0] != 0x16 {
+		t.Errorf("recorded hello does not start with a handshake record: % x", hello[:min(len(hello), 8)])
+	}
+}
+
+func TestPeekServerNameNotTLS(t *testing.T) {
+	client, proxySide := net.Pipe()
+	defer client.Close()                                         //nolint:errcheck
+	defer proxySide.Close()                                      //nolint:errcheck
+	go client.Write([]byte("GET / HTTP/1.1\r\nHost: x\r\n\r\n")) //nolint:errcheck
+
+	name, hello, isTLS, err := peekServerName(proxySide)
+	if err != nil || isTLS || name != "" {
+		t.Fatalf("peekServerName = %q, isTLS=%v, err=%v", name, isTLS, err)
+	}
+	if !bytes.HasPrefix(hello, []byte("GET ")) {
+		t.Errorf("recorded bytes = %q, want the request start", hello)
+	}
+}
+
+func TestEgressProxyTunnelSNI(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     SNIPolicy
+		serverName string
+		blocked    bool
+	}{
+		{"matching SNI", SNIAllowlist, "api.example.com", false},
+		{"other allowed SNI", SNIAllowlist, "cdn.example.com", false},
+		{"fronted SNI", SNIAllowlist, "evil.test", true},
+		{"no SNI", SNIAllowlist, "", false},
+		{"no SNI required", SNIRequire, "", true},
+		{"off", SNIOff, "evil.test", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := NewEgressProxy(NewDomainMatcher(ModeAllowlist, []string{"api.example.com", "cdn.example.com"}), false, nil)
+			proxy.SetSNIPolicy(tt.policy)
+			var attempts []EgressAttempt
+			proxy.OnAttempt = func(a EgressAttempt) { attempts = append(attempts, a) }
+
+			client, proxyClient := net.Pipe()
+			proxyUpstream, upstream := net.Pipe()
+			defer client.Close()   //nolint:errcheck
+			defer upstream.Close() //nolint:errcheck
+			sendClientHello(client, tt.serverName)
+
+			done := make(chan struct{})
+			go func() {
+				proxy.tunnel(proxyClient, proxyUpstream, EgressAttempt{Domain: "api.example.com", Scheme: "https", Port: 443}, egressIdentity{})
+				close(done)
+			}()
+
+			_ = upstream.SetReadDeadline(time.Now().Add(5 * time.Second))
+			buf := make([]byte, 5)
+			_, err := io.ReadFull(upstream, buf)
+			if tt.blocked {
+				if err == nil {
+					t.Error("upstream received bytes from a blocked tunnel")
+				}
+				<-done
+				if len(attempts) != 1 || attempts[0].Allowed || attempts[0].Reason != ReasonSNI {
+					t.Errorf("attempts = %+v, want one sni block", attempts)
+				}
+				return
+			}
+			if err != nil || buf[0] != 0x16 {
+				t.Fatalf("upstream read %v, % x; want the replayed ClientHello", err, buf)
+			}
+			if len(attempts) != 0 {
+				t.Errorf("attempts = %+v, want none", attempts)
+			}
+			client.Close()   //nolint:errcheck
+			upstream.Close() //nolint:errcheck
+			<-done
+		})
+	}
+}
@@ -57,7 +57,7 @@ func LLMProviderDomains(cfg *types.ForgeConfig) []string {
 	return out
 }
 
-// LLMProviderEnvDomains returns the hostnames extracted from the four
+// LLMProviderEnvDomains returns the hostnames extracted from the
 // canonical SDK base-URL env vars when present in the supplied env
 // map. Used by the runner to auto-merge env-driven LLM provider hosts
 // into the egress allowlist for deployments that haven't yet migrated
@@ -73,7 +73,8 @@ func LLMProviderDomains(cfg *types.ForgeConfig) []string {
 //	provider via OPENAI_BASE_URL only.
 //
 // The env vars consulted are the standard SDK conventions every
-// OpenAI/Anthropic/Ollama/Gemini-compatible provider documents.
+// OpenAI/Anthropic/Ollama/Gemini/Cohere/Mistral-compatible provider
+// documents.
 // Forge does not invent any Forge-specific FORGE_*_BASE_URL variant.
 //
 // Returns nil when none are set. Malformed URLs are silently
@@ -89,6 +90,13 @@ func LLMProviderEnvDomains(envVars map[string]string) []string {
 		"ANTHROPIC_BASE_URL",
 		"OLLAMA_BASE_URL",
 		"GEMINI_BASE_URL",
+		"COHERE_BASE_URL",
+		"MISTRAL_BASE_URL",
+		"GROQ_BASE_URL",
+		"TOGETHER_BASE_URL",
+		"OPENROUTER_BASE_URL",
+		"XAI_BASE_URL",
+		"DEEPSEEK_BASE_URL",
 	} {
 		host := hostFromURL(envVars[key])
 		if host == "" || seen[host] {
@@ -3,6 +3,7 @@ package security
 import (
 	"bytes"
 	"fmt"
+	"net"
 	"strings"
 	"text/template"
 )
@@ -13,9 +14,14 @@ metadata:
   name: {{.AgentID}}-network
   labels:
     app: {{.AgentID}}
-  {{- if .Annotation}}
+  {{- if or .Annotation .HostTCP}}
   annotations:
+    {{- if .Annotation}}
     ai.initializ.forge/allowed-domains: "{{.Annotation}}"
+    {{- end}}
+    {{- if .HostTCP}}
+    ai.initializ.forge/in-process-only-tcp: "{{.HostTCP}}"
+    {{- end}}
   {{- end}}
 spec:
   podSelector:
@@ -27,21 +33,86 @@ spec:
   egress: []
   {{- else}}
   egress:
+    # DNS, so the in-process egress enforcer can resolve allowed hosts.
+    - ports:
+        - protocol: UDP
+          port: 53
+        - protocol: TCP
+          port: 53
+    # HTTP(S).{{if .Annotation}} NetworkPolicy cannot match host names; the
+    # domain allowlist is enforced in-process.{{end}}
+    {{- if .Except}}
+    - to:
+        - ipBlock:
+            cidr: 0.0.0.0/0
+            except:
+              {{- range .Except}}
+              - {{.}}
+              {{- end}}
+      ports:
+    {{- else}}
     - to: []
       ports:
+    {{- end}}
         - protocol: TCP
           port: 443
         - protocol: TCP
           port: 80
+    {{- range .PrivateCIDRs}}
+    # egress.allowed_private_cidrs
+    - to:
+        - ipBlock:
+            cidr: {{.}}
+    {{- end}}
+    {{- range .TCP}}
+    # egress.allowed_tcp {{.Entry}}
+    {{- if .CIDR}}
+    - to:
+        - ipBlock:
+            cidr: {{.CIDR}}
+      {{- if .Port}}
+      ports:
+        - protocol: TCP
+          port: {{.Port}}
+      {{- end}}
+    {{- else}}
+    - ports:
+        - protocol: TCP
+          port: {{.Port}}
+    {{- end}}
+    {{- end}}
   {{- end}}`
 
+// privateEgressRanges are excluded from the HTTP(S) rule unless the
+// egress config allows private IPs: RFC 1918, CGNAT and link-local
+// (which covers cloud metadata endpoints).
+var privateEgressRanges = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "169.254.0.0/16"}
+
 type networkPolicyTemplateData struct {
-	AgentID    string
-	DenyAll    bool
-	Annotation string
+	AgentID      string
+	DenyAll      bool
+	Annotation   string
+	Except       []string
+	PrivateCIDRs []string
+	TCP          []networkPolicyTCPRule
+	HostTCP      string // host:* entries NetworkPolicy cannot express
+}
+
+type networkPolicyTCPRule struct {
+	Entry string
+	CIDR  string // empty: any destination on Port
+	Port  string // empty: any port to CIDR
 }
 
 // GenerateK8sNetworkPolicy produces a K8s NetworkPolicy YAML for the given agent and egress config.
+//
+// Deny-all allows no egress. Otherwise DNS is allowed, HTTP(S) is
+// allowed to public addresses (any address in dev-open mode or with
+// allow_private_ips), and each allowed_private_cidrs / allowed_tcp
+// entry gets its own rule. allowed_tcp entries naming an IP become an
+// ipBlock; those naming a host become a port-only rule, and host:*
+// entries, which no port rule can bound, are left to the in-process
+// enforcer and listed in an annotation.
 func GenerateK8sNetworkPolicy(agentID string, cfg *EgressConfig) ([]byte, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("egress config is nil")
@@ -61,6 +132,33 @@ func GenerateK8sNetworkPolicy(agentID string, cfg *EgressConfig) ([]byte, error)
 		if len(cfg.AllDomains) > 0 {
 			data.Annotation = strings.Join(cfg.AllDomains, ",")
 		}
+		if !cfg.AllowPrivateIPs {
+			data.Except = privateEgressRanges
+		}
+		data.PrivateCIDRs = cfg.AllowedPrivateCIDRs
+		var hostAnyPort []string
+		for _, entry := range cfg.AllowedTCP {
+			host, port, err := parseTCPEntry(entry)
+			if err != nil {
+				return nil, err
+			}
+			rule := networkPolicyTCPRule{Entry: entry, Port: port}
+			if port == "*" {
+				rule.Port = ""
+			}
+			if ip := net.ParseIP(host); ip != nil {
+				bits := 32
+				if ip.To4() == nil {
+					bits = 128
+				}
+				rule.CIDR = fmt.Sprintf("%s/%d", ip, bits)
+			} else if rule.Port == "" {
+				hostAnyPort = append(hostAnyPort, entry)
+				continue
+			}
+			data.TCP = append(data.TCP, rule)
+		}
+		data.HostTCP = strings.Join(hostAnyPort, ",")
 	}
 
 	tmpl, err := template.New("network-policy").Parse(networkPolicyTemplate)
@@ -0,0 +1,254 @@
+// Package opa is the policy-as-code decision point: it asks an Open
+// Policy Agent whether a tool call, an egress attempt or a schedule
+// creation may proceed.
+//
+// The built-in engines (guardrails, egress allowlist, platform policy,
+// step-up) decide from forge.yaml and files shipped with the agent.
+// OPA lets an organization layer its own guardrails on top, written in
+// Rego and owned outside the agent — deny a tool for a tenant, keep a
+// domain off-limits outside business hours, cap schedule frequency.
+// The decision can only narrow what the built-in checks allow: it runs
+// after them and a deny always wins.
+//
+// The client speaks OPA's Data API (POST /v1/data/<decision>), so the
+// same code serves an external OPA server and the local one the
+// runtime starts for Rego bundled with the agent.
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/auth"
+)
+
+// DefaultDecision is the Data API path evaluated when none is configured.
+const DefaultDecision = "forge/decision"
+
+// DefaultTimeout bounds each evaluation when none is configured.
+const DefaultTimeout = 2 * time.Second
+
+// Input kinds.
+const (
+	KindTool     = "tool"
+	KindEgress   = "egress"
+	KindSchedule = "schedule"
+)
+
+// maxArgLen caps each summarized tool argument so a large payload (a
+// file body, a long prompt) never travels to the policy engine.
+const maxArgLen = 256
+
+// Input is the document a policy sees as `input`. Exactly one of Tool,
+// Domain or Schedule is set, matching Kind.
+type Input struct {
+	Kind          string         `json:"kind"`
+	AgentID       string         `json:"agent_id"`
+	TaskID        string         `json:"task_id,omitempty"`
+	CorrelationID string         `json:"correlation_id,omitempty"`
+	Tool          *ToolInput     `json:"tool,omitempty"`
+	Domain        string         `json:"domain,omitempty"`
+	Schedule      *ScheduleInput `json:"schedule,omitempty"`
+	Identity      *Identity      `json:"identity,omitempty"`
+}
+
+// ToolInput describes a tool call. Args is a summary: see SummarizeArgs.
+type ToolInput struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// ScheduleInput describes a schedule being created or updated.
+type ScheduleInput struct {
+	ID            string `json:"id,omitempty"`
+	Cron          string `json:"cron"`
+	Task          string `json:"task"`
+	Skill         string `json:"skill,omitempty"`
+	Channel       string `json:"channel,omitempty"`
+	ChannelTarget string `json:"channel_target,omitempty"`
+}
+
+// Identity is the caller, as verified by the auth middleware. Raw
+// provider claims are deliberately not forwarded.
+type Identity struct {
+	UserID      string   `json:"user_id,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	OrgID       string   `json:"org_id,omitempty"`
+	WorkspaceID string   `json:"workspace_id,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+	Source      string   `json:"source,omitempty"`
+}
+
+// IdentityFrom converts an authenticated identity; nil stays nil.
+func IdentityFrom(id *auth.Identity) *Identity {
+	if id == nil {
+		return nil
+	}
+	return &Identity{
+		UserID:      id.UserID,
+		Email:       id.Email,
+		OrgID:       id.OrgID,
+		WorkspaceID: id.WorkspaceID,
+		Groups:      id.Groups,
+		Source:      id.Source,
+	}
+}
+
+// Decision is a policy's verdict.
+type Decision struct {
+	Allow  bool
+	Reason string
+}
+
+// DeniedError is returned to the caller of a denied action.
+type DeniedError struct {
+	Kind    string
+	Subject string // tool name, domain or schedule ID
+	Reason  string
+}
+
+// ErrorCode classifies a policy denial as a guardrail violation in the
+// a2a error taxonomy.
+func (e *DeniedError) ErrorCode() a2a.ErrorCode { return a2a.ErrorGuardrailViolation }
+
+func (e *DeniedError) Error() string {
+	msg := fmt.Sprintf("denied by policy: %s %s", e.Kind, e.Subject)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// Client evaluates one decision rule on an OPA server.
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient returns a client for the decision rule at decision (a
+// slash- or dot-separated Data API path, DefaultDecision when empty)
+// on the OPA server at baseURL. timeout ≤ 0 uses DefaultTimeout.
+func NewClient(baseURL, decision string, timeout time.Duration) *Client {
+	if decision == "" {
+		decision = DefaultDecision
+	}
+	decision = strings.Trim(strings.ReplaceAll(strings.TrimPrefix(decision, "data."), ".", "/"), "/")
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		endpoint: strings.TrimRight(baseURL, "/") + "/v1/data/" + decision,
+		http:     &http.Client{Timeout: timeout},
+	}
+}
+
+// Endpoint returns the Data API URL the client posts to.
+func (c *Client) Endpoint() string { return c.endpoint }
+
+// Evaluate posts in to the decision rule. The rule may produce a
+// boolean or an object with `allow` (boolean) and an optional `reason`
+// (string). An undefined result — no rule matched and no default —
+// denies, as does a result of any other shape.
+func (c *Client) Evaluate(ctx context.Context, in Input) (Decision, error) {
+	body, err := json.Marshal(map[string]any{"input": in})
+	if err != nil {
+		return Decision{}, fmt.Errorf("marshalling input: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("calling opa: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Decision{}, fmt.Errorf("reading opa response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Decision{}, fmt.Errorf("opa returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return parseResult(data)
+}
+
+func parseResult(data []byte) (Decision, error) {
+	var out struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return Decision{}, fmt.Errorf("parsing opa response: %w", err)
+	}
+	if len(out.Result) == 0 {
+		return Decision{Reason: "policy decision undefined"}, nil
+	}
+	var allow bool
+	if err := json.Unmarshal(out.Result, &allow); err == nil {
+		return Decision{Allow: allow}, nil
+	}
+	var obj struct {
+		Allow  *bool  `json:"allow"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(out.Result, &obj); err != nil || obj.Allow == nil {
+		return Decision{Reason: "policy decision is neither a boolean nor {allow, reason}"}, nil
+	}
+	return Decision{Allow: *obj.Allow, Reason: obj.Reason}, nil
+}
+
+// SummarizeArgs turns a tool call's raw JSON arguments into the
+// summary policies see: top-level keys with scalar values, strings cut
+// to 256 bytes, and nested objects or arrays reduced to their JSON
+// when short and to a "<object|array, N bytes>" marker otherwise.
+// Arguments that are not a JSON object yield nil.
+func SummarizeArgs(raw string) map[string]any {
+	var args map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return nil
+	}
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		var val any
+		if err := json.Unmarshal(v, &val); err != nil {
+			continue
+		}
+		switch tv := val.(type) {
+		case string:
+			out[k] = truncate(tv)
+		case map[string]any, []any:
+			if len(v) <= maxArgLen {
+				out[k] = string(v)
+			} else {
+				kind := "object"
+				if _, ok := tv.([]any); ok {
+					kind = "array"
+				}
+				out[k] = fmt.Sprintf("<%s, %d bytes>", kind, len(v))
+			}
+		default:
+			out[k] = tv
+		}
+	}
+	return out
+}
+
+func truncate(s string) string {
+	if len(s) <= maxArgLen {
+		return s
+	}
+	cut := maxArgLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
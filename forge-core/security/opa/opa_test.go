@@ -0,0 +1,87 @@
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientEvaluate(t *testing.T) {
+	var gotPath string
+	var gotInput Input
+	result := `{"result": {"allow": false, "reason": "tenant may not run cli_execute"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		var body struct {
+			Input Input `json:"input"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotInput = body.Input
+		_, _ = w.Write([]byte(result))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL+"/", "data.acme.forge.allow", 0)
+	in := Input{Kind: KindTool, AgentID: "a", Tool: &ToolInput{Name: "cli_execute"}, Identity: &Identity{OrgID: "acme"}}
+	d, err := c.Evaluate(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if gotPath != "/v1/data/acme/forge/allow" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotInput.Tool == nil || gotInput.Tool.Name != "cli_execute" || gotInput.Identity.OrgID != "acme" {
+		t.Errorf("input = %+v", gotInput)
+	}
+	if d.Allow || d.Reason != "tenant may not run cli_execute" {
+		t.Errorf("decision = %+v", d)
+	}
+
+	result = `{"result": true}`
+	if d, _ := c.Evaluate(context.Background(), in); !d.Allow {
+		t.Error("boolean true result denied")
+	}
+	result = `{}`
+	if d, _ := c.Evaluate(context.Background(), in); d.Allow || d.Reason != "policy decision undefined" {
+		t.Errorf("undefined result = %+v", d)
+	}
+	result = `{"result": "yes"}`
+	if d, _ := c.Evaluate(context.Background(), in); d.Allow {
+		t.Error("malformed result allowed")
+	}
+}
+
+func TestClientEvaluate_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":"internal_error"}`, http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, "", 0).Evaluate(context.Background(), Input{Kind: KindEgress, Domain: "example.com"})
+	if err == nil || !strings.Contains(err.Error(), "opa returned 500") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
+
+func TestSummarizeArgs(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	got := SummarizeArgs(`{"command":"` + long + `","timeout":30,"env":{"A":"1"},"files":[` + strings.Repeat(`"aaaaaaaaaa",`, 30) + `"z"]}`)
+	if s := got["command"].(string); !strings.HasSuffix(s, "…") || len(s) > maxArgLen+len("…") {
+		t.Errorf("command not truncated: %d bytes", len(s))
+	}
+	if got["timeout"] != float64(30) {
+		t.Errorf("timeout = %v", got["timeout"])
+	}
+	if got["env"] != `{"A":"1"}` {
+		t.Errorf("env = %v", got["env"])
+	}
+	if s, _ := got["files"].(string); !strings.HasPrefix(s, "<array, ") {
+		t.Errorf("files = %v", got["files"])
+	}
+	if SummarizeArgs("not json") != nil {
+		t.Error("non-object args should summarize to nil")
+	}
+}
@@ -1,6 +1,9 @@
 package security
 
-import "github.com/initializ/forge/forge-core/types"
+import (
+	"github.com/initializ/forge/forge-core/observability/llmtrace"
+	"github.com/initializ/forge/forge-core/types"
+)
 
 // OTelDomain returns the hostname of the OTLP collector configured in
 // observability.tracing.endpoint, as a single-element slice ready to
@@ -47,3 +50,28 @@ func OTelDomain(cfg types.TracingYAML) []string {
 	}
 	return []string{host}
 }
+
+// LLMTraceDomains returns the hosts of the LLM observability platforms
+// observability.langfuse / langsmith export task traces to: the
+// configured endpoint, else the hosted service. Like OTelDomain it
+// skips disabled exporters and unparseable endpoints. An endpoint set
+// only through LANGFUSE_HOST / LANGSMITH_ENDPOINT is not visible here
+// and must be allowlisted by hand.
+func LLMTraceDomains(cfg types.ObservabilityConfig) []string {
+	var hosts []string
+	add := func(exp types.LLMTraceExportYAML, def string) {
+		if !exp.Enabled {
+			return
+		}
+		endpoint := exp.Endpoint
+		if endpoint == "" {
+			endpoint = def
+		}
+		if host := hostFromURL(endpoint); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	add(cfg.Langfuse, llmtrace.DefaultLangfuseEndpoint)
+	add(cfg.LangSmith, llmtrace.DefaultLangSmithEndpoint)
+	return hosts
+}
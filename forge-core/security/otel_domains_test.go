@@ -103,3 +103,20 @@ func TestOTelDomain_EmptyEndpoint_NoEntry(t *testing.T) {
 		t.Errorf("empty endpoint must produce no entry; got %v", got)
 	}
 }
+
+// TestLLMTraceDomains covers the Langfuse / LangSmith exporters: an
+// enabled exporter contributes its endpoint's host, or the hosted
+// service's without one; a disabled one contributes nothing.
+func TestLLMTraceDomains(t *testing.T) {
+	if got := security.LLMTraceDomains(types.ObservabilityConfig{}); got != nil {
+		t.Errorf("LLMTraceDomains(empty) = %v, want nil", got)
+	}
+	got := security.LLMTraceDomains(types.ObservabilityConfig{
+		Langfuse:  types.LLMTraceExportYAML{Enabled: true, Endpoint: "https://langfuse.internal:3000"},
+		LangSmith: types.LLMTraceExportYAML{Enabled: true},
+	})
+	want := []string{"langfuse.internal", "api.smith.langchain.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LLMTraceDomains = %v, want %v", got, want)
+	}
+}
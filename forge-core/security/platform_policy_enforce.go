@@ -117,7 +117,7 @@ func EnforcePolicy(cfg *types.ForgeConfig, layers []PolicyLayer) []PolicyViolati
 		}
 	}
 
-	// Model: primary + every fallback. Each match attributed to the
+	// Model: primary + every fallback and route. Each match attributed to the
 	// most-restrictive layer (first deny wins).
 	if cfg.Model.Provider != "" {
 		if src := FirstLayerForbiddingModel(layers, cfg.Model.Provider, cfg.Model.Name); src != nil {
@@ -141,6 +141,41 @@ func EnforcePolicy(cfg *types.ForgeConfig, layers []PolicyLayer) []PolicyViolati
 			})
 		}
 	}
+	for i, rt := range cfg.Model.Routes {
+		provider := rt.Provider
+		if provider == "" {
+			provider = cfg.Model.Provider
+		}
+		if src := FirstLayerForbiddingModel(layers, provider, rt.Name); src != nil {
+			violations = append(violations, PolicyViolation{
+				Kind:           ViolationForbiddenModel,
+				OffendingValue: provider + "/" + rt.Name,
+				ForgeYAMLField: fmt.Sprintf("model.routes[%d]", i),
+				Layer:          src.Source,
+				LayerPath:      src.Path,
+			})
+		}
+	}
+	for i, exp := range cfg.Experiments {
+		for j, v := range exp.Variants {
+			if v.Model == nil {
+				continue
+			}
+			provider := v.Model.Provider
+			if provider == "" {
+				provider = cfg.Model.Provider
+			}
+			if src := FirstLayerForbiddingModel(layers, provider, v.Model.Name); src != nil {
+				violations = append(violations, PolicyViolation{
+					Kind:           ViolationForbiddenModel,
+					OffendingValue: provider + "/" + v.Model.Name,
+					ForgeYAMLField: fmt.Sprintf("experiments[%d].variants[%d].model", i, j),
+					Layer:          src.Source,
+					LayerPath:      src.Path,
+				})
+			}
+		}
+	}
 
 	// Size bounds use the MOST RESTRICTIVE non-zero value across all
 	// layers ("most restrictive wins"); the layer whose bound was
@@ -121,6 +121,24 @@ func TestEnforcePolicy_ForbiddenModel_PrimaryAndFallbacks(t *testing.T) {
 	}
 }
 
+func TestEnforcePolicy_ForbiddenModel_Routes(t *testing.T) {
+	// A route is as much a model declaration as a fallback; a route
+	// without a provider inherits the primary's.
+	cfg := &types.ForgeConfig{
+		Model: types.ModelRef{
+			Provider: "anthropic", Name: "claude-sonnet-4-6",
+			Routes: []types.ModelRoute{{Name: "claude-opus-4"}},
+		},
+	}
+	layers := wrap(PlatformPolicy{
+		ForbiddenModels: []ModelMatcher{{Provider: "anthropic", Name: "claude-opus-4"}},
+	})
+	violations := EnforcePolicy(cfg, layers)
+	if len(violations) != 1 || violations[0].ForgeYAMLField != "model.routes[0]" || violations[0].OffendingValue != "anthropic/claude-opus-4" {
+		t.Fatalf("violations = %+v", violations)
+	}
+}
+
 func TestEnforcePolicy_EgressBoundExceeded(t *testing.T) {
 	// Defense against allowlist bloat — a developer pasting 200
 	// third-party domains. Bound check applies to the declared count,
@@ -94,6 +94,11 @@ func (p *EgressProxy) handleSOCKS5(client net.Conn) {
 		return
 	}
 	defer upstream.Close() //nolint:errcheck
+	// SOCKS5 carries no task identity, so the flow is counted against its
+	// destination only, never a task quota.
+	dest := net.JoinHostPort(host, port)
+	p.meter.request("", dest)
+	metered := &meteredConn{Conn: upstream, f: &meter{m: p.meter, domain: dest}}
 
 	// Reply with BND.ADDR = local side of the upstream socket. Clients
 	// generally ignore this but the protocol requires it.
@@ -102,7 +107,7 @@ func (p *EgressProxy) handleSOCKS5(client net.Conn) {
 		return
 	}
 
-	relayPair(client, upstream)
+	relayPair(client, metered)
 }
 
 // socks5Greeting completes the method negotiation: read [ver, nmethods,
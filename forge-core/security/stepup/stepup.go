@@ -28,6 +28,7 @@ import (
 	"fmt"
 	"slices"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/auth"
 )
 
@@ -209,6 +210,10 @@ func (e *RequiredError) Error() string {
 		e.Tool, e.RequiredAcr, e.Reason)
 }
 
+// ErrorCode classifies a step-up requirement as an authentication
+// failure in the a2a error taxonomy: the caller must re-authenticate.
+func (e *RequiredError) ErrorCode() a2a.ErrorCode { return a2a.ErrorAuthFailed }
+
 // AsRequiredError is a convenience for callers that want to check
 // whether an error carries step-up semantics without importing the
 // errors package at every call site. Returns (*RequiredError, true)
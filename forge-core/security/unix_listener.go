@@ -0,0 +1,84 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// maxUnixSocketPath is the longest socket path every supported platform
+// accepts (sun_path is 104 bytes on macOS and the BSDs, 108 on Linux).
+const maxUnixSocketPath = 103
+
+// ListenUnix listens on a Unix domain socket at path that only the
+// current user and group may connect to, so a sidecar sharing the
+// socket's volume needs the agent's uid or gid. A socket left behind by
+// a previous run is removed first; any other file at path is an error
+// rather than being replaced. The socket file is removed when the
+// listener is closed.
+//
+// The socket is bound inside a fresh 0700 directory next to path,
+// restricted to 0660 and only then renamed into place, so no other user
+// can connect while it still has the umask's mode. The process umask is
+// shared by every goroutine and is not changed instead.
+func ListenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("unix socket path is empty")
+	}
+	if len(path) > maxUnixSocketPath {
+		return nil, fmt.Errorf("unix socket path %q is longer than %d bytes", path, maxUnixSocketPath)
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket %s: %w", path, err)
+		}
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Dir(path), ".sock")
+	if err != nil {
+		return nil, fmt.Errorf("creating socket directory for %s: %w", path, err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	bind := filepath.Join(tmp, "s")
+	if len(bind) > maxUnixSocketPath {
+		return nil, fmt.Errorf("unix socket path %q is too long to bind safely; use a path at least %d bytes shorter", path, len(bind)-maxUnixSocketPath)
+	}
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: bind, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// The bound name is gone once renamed; Close removes path instead.
+	ln.SetUnlinkOnClose(false)
+	if err := os.Chmod(bind, 0o660); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("restricting socket %s: %w", path, err)
+	}
+	if err := os.Rename(bind, path); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("placing socket %s: %w", path, err)
+	}
+	return &unixListener{UnixListener: ln, addr: &net.UnixAddr{Name: path, Net: "unix"}}, nil
+}
+
+// unixListener is a listener whose socket was renamed after binding: it
+// reports the final path as its address and removes it on Close.
+type unixListener struct {
+	*net.UnixListener
+	addr *net.UnixAddr
+	once sync.Once
+}
+
+func (l *unixListener) Addr() net.Addr { return l.addr }
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	l.once.Do(func() { _ = os.Remove(l.addr.Name) })
+	return err
+}
@@ -0,0 +1,123 @@
+package security
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// shortTempDir returns a temp dir whose paths fit a socket address;
+// t.TempDir nests too deep on some platforms.
+func shortTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "fsock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) }) //nolint:errcheck
+	return dir
+}
+
+func TestListenUnix(t *testing.T) {
+	dir := shortTempDir(t)
+	path := filepath.Join(dir, "a.sock")
+
+	ln, err := ListenUnix(path)
+	if err != nil {
+		t.Fatalf("ListenUnix: %v", err)
+	}
+	if got := ln.Addr().String(); got != path {
+		t.Errorf("Addr = %q, want %q", got, path)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the socket", len(entries))
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("dialing the renamed socket: %v", err)
+	}
+	conn.Close() //nolint:errcheck
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o660 {
+		t.Errorf("socket mode = %o, want 660", perm)
+	}
+	ln.Close() //nolint:errcheck
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file left after Close: %v", err)
+	}
+}
+
+func TestListenUnixReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "a.sock")
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A crashed process leaves its socket file behind.
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close() //nolint:errcheck
+
+	ln, err := ListenUnix(path)
+	if err != nil {
+		t.Fatalf("ListenUnix over a stale socket: %v", err)
+	}
+	ln.Close() //nolint:errcheck
+}
+
+func TestListenUnixRefusesOtherFiles(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "a.sock")
+	if err := os.WriteFile(path, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ListenUnix(path); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Fatalf("ListenUnix over a regular file: err = %v", err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "keep" {
+		t.Error("regular file was replaced")
+	}
+	if _, err := ListenUnix(strings.Repeat("x", maxUnixSocketPath+1)); err == nil {
+		t.Error("expected an error for an over-long path")
+	}
+}
+
+func TestEgressProxyUnixSocket(t *testing.T) {
+	path := filepath.Join(shortTempDir(t), "egress.sock")
+	proxy := NewEgressProxy(NewDomainMatcher(ModeAllowlist, []string{"allowed.com"}), false, nil)
+	proxy.SetUnixSocket(path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := proxy.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer proxy.Stop() //nolint:errcheck
+	if proxy.SocketPath() != path {
+		t.Errorf("SocketPath = %q, want %q", proxy.SocketPath(), path)
+	}
+
+	// A sidecar reaches the proxy through the socket and is held to the
+	// same allowlist as the loopback listener.
+	client := &http.Client{Transport: &http.Transport{
+		Proxy: http.ProxyURL(&url.URL{Scheme: "http", Host: "egress.sock"}),
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://blocked.com/evil")
+	if err != nil {
+		t.Fatalf("request over the socket: %v", err)
+	}
+	resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", resp.StatusCode)
+	}
+}
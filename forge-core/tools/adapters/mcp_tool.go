@@ -159,6 +159,14 @@ func validateDescriptorName(name string) error {
 // tools.Registry admits "__" in the name.
 func (m *MCPTool) MCPSource() {}
 
+// ReadOnly reports the server's readOnlyHint annotation. Advisory only:
+// conversation undo uses it to skip disavowing calls the server declared
+// side-effect free. Absent annotations mean "may have side effects".
+func (m *MCPTool) ReadOnly() bool {
+	a := m.descriptor.Annotations
+	return a != nil && a.ReadOnlyHint != nil && *a.ReadOnlyHint
+}
+
 // resolveClient selects the Client for this call: the per-subject pool
 // resolver when set (routing to the requesting user's connection), else
 // the fixed client (#317).
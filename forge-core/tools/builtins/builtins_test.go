@@ -574,3 +574,27 @@ func TestAllToolsHaveCategory(t *testing.T) {
 		}
 	}
 }
+
+func TestReadOnlyClassification(t *testing.T) {
+	want := map[string]bool{
+		"http_request":   false,
+		"web_fetch":      true,
+		"json_parse":     true,
+		"csv_parse":      true,
+		"datetime_now":   true,
+		"uuid_generate":  true,
+		"math_calculate": true,
+		"web_search":     true,
+		"file_create":    false,
+	}
+	for _, tool := range All() {
+		ro, ok := want[tool.Name()]
+		if !ok {
+			t.Errorf("tool %q missing from read-only table", tool.Name())
+			continue
+		}
+		if got := tools.IsReadOnly(tool); got != ro {
+			t.Errorf("IsReadOnly(%q) = %v, want %v", tool.Name(), got, ro)
+		}
+	}
+}
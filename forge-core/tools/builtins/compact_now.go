@@ -0,0 +1,70 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// SessionCompactor forces compaction of a task's conversation. The
+// runtime implements it.
+type SessionCompactor interface {
+	// CompactSession compacts sessionID now and returns the summary.
+	// source names the caller ("tool" or "api") for the audit trail.
+	CompactSession(ctx context.Context, sessionID, source string) (*coreruntime.CompactResult, error)
+}
+
+type compactNowTool struct {
+	compactor SessionCompactor
+}
+
+// NewCompactNowTool creates a compact_now tool. It is an admin tool: the
+// runtime registers it only when forge.yaml lists it under tools.
+func NewCompactNowTool(c SessionCompactor) tools.Tool {
+	return &compactNowTool{compactor: c}
+}
+
+func (t *compactNowTool) Name() string             { return "compact_now" }
+func (t *compactNowTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *compactNowTool) Description() string {
+	return "Summarize the older half of a conversation now, freeing context space, and return the summary. " +
+		"Defaults to the current conversation. Only use it when an operator asks."
+}
+
+func (t *compactNowTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"session_id": {"type": "string", "description": "Task ID of the conversation to compact (default: this one)"}
+		}
+	}`)
+}
+
+func (t *compactNowTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input struct {
+		SessionID string `json:"session_id"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &input); err != nil {
+			return "", fmt.Errorf("parsing input: %w", err)
+		}
+	}
+	if input.SessionID == "" {
+		input.SessionID = coreruntime.TaskIDFromContext(ctx)
+	}
+	if input.SessionID == "" {
+		return "", fmt.Errorf("session_id is required outside a task")
+	}
+	res, err := t.compactor.CompactSession(ctx, input.SessionID, "tool")
+	if err != nil {
+		return "", err
+	}
+	if !res.Compacted {
+		return "The conversation is too short to compact.", nil
+	}
+	return fmt.Sprintf("Compacted %d messages into the summary (%d -> %d chars). Summary:\n\n%s",
+		res.Removed, res.CharsBefore, res.CharsAfter, res.Summary), nil
+}
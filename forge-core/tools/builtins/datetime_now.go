@@ -21,6 +21,7 @@ func (t *datetimeNowTool) Description() string {
 	return "Get current date and time in specified format and timezone"
 }
 func (t *datetimeNowTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *datetimeNowTool) ReadOnly() bool           { return true }
 
 func (t *datetimeNowTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -20,6 +20,7 @@ func (t *directoryTreeTool) Description() string {
 	return "Display a tree-formatted directory listing showing the structure of files and directories. Useful for understanding project layout."
 }
 func (t *directoryTreeTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *directoryTreeTool) ReadOnly() bool           { return true }
 
 func (t *directoryTreeTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -46,7 +47,7 @@ func (t *directoryTreeTool) Execute(ctx context.Context, args json.RawMessage) (
 		return "", fmt.Errorf("invalid arguments: %w", err)
 	}
 
-	resolved, err := t.pathValidator.Resolve(input.Path)
+	resolved, err := t.pathValidator.ResolveFor(ctx, input.Path)
 	if err != nil {
 		return "", err
 	}
@@ -65,7 +66,7 @@ func (t *directoryTreeTool) Execute(ctx context.Context, args json.RawMessage) (
 	}
 
 	var sb strings.Builder
-	relRoot, _ := filepath.Rel(t.pathValidator.WorkDir(), resolved)
+	relRoot, _ := filepath.Rel(t.pathValidator.RootFor(ctx), resolved)
 	if relRoot == "." {
 		relRoot = filepath.Base(resolved)
 	}
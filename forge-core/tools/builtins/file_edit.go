@@ -41,7 +41,7 @@ func (t *fileEditTool) InputSchema() json.RawMessage {
 	}`)
 }
 
-func (t *fileEditTool) Execute(_ context.Context, args json.RawMessage) (string, error) {
+func (t *fileEditTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
 	var input struct {
 		Path    string `json:"path"`
 		OldText string `json:"old_text"`
@@ -61,7 +61,7 @@ func (t *fileEditTool) Execute(_ context.Context, args json.RawMessage) (string,
 		return "", fmt.Errorf("old_text and new_text are identical")
 	}
 
-	resolved, err := t.pathValidator.Resolve(input.Path)
+	resolved, err := t.pathValidator.ResolveFor(ctx, input.Path)
 	if err != nil {
 		return "", err
 	}
@@ -64,7 +64,7 @@ type patchOperation struct {
 	NewPath string `json:"new_path"`
 }
 
-func (t *filePatchTool) Execute(_ context.Context, args json.RawMessage) (string, error) {
+func (t *filePatchTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
 	var input struct {
 		Operations []patchOperation `json:"operations"`
 	}
@@ -89,7 +89,7 @@ func (t *filePatchTool) Execute(_ context.Context, args json.RawMessage) (string
 			return "", fmt.Errorf("operation %d: path is required", i)
 		}
 
-		resolved, err := t.pathValidator.Resolve(op.Path)
+		resolved, err := t.pathValidator.ResolveFor(ctx, op.Path)
 		if err != nil {
 			return "", fmt.Errorf("operation %d: %w", i, err)
 		}
@@ -104,7 +104,7 @@ func (t *filePatchTool) Execute(_ context.Context, args json.RawMessage) (string
 			if strings.TrimSpace(op.NewPath) == "" {
 				return "", fmt.Errorf("operation %d: new_path is required for move", i)
 			}
-			newResolved, err := t.pathValidator.Resolve(op.NewPath)
+			newResolved, err := t.pathValidator.ResolveFor(ctx, op.NewPath)
 			if err != nil {
 				return "", fmt.Errorf("operation %d: new_path %w", i, err)
 			}
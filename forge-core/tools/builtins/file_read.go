@@ -20,6 +20,7 @@ func (t *fileReadTool) Description() string {
 	return "Read a file's contents with optional line offset and limit, or list a directory's entries. Returns numbered lines (cat -n style) for files, or a listing with name, type, and size for directories."
 }
 func (t *fileReadTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *fileReadTool) ReadOnly() bool           { return true }
 
 func (t *fileReadTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -52,7 +53,7 @@ func (t *fileReadTool) Execute(ctx context.Context, args json.RawMessage) (strin
 		return "", fmt.Errorf("invalid arguments: %w", err)
 	}
 
-	resolved, err := t.pathValidator.Resolve(input.Path)
+	resolved, err := t.pathValidator.ResolveFor(ctx, input.Path)
 	if err != nil {
 		return "", err
 	}
@@ -127,7 +128,7 @@ func (t *fileReadTool) listDirectory(ctx context.Context, path string) (string,
 		} else if info.Mode()&os.ModeSymlink != 0 {
 			entryType = "link"
 		}
-		relPath, _ := filepath.Rel(t.pathValidator.WorkDir(), filepath.Join(path, entry.Name()))
+		relPath, _ := filepath.Rel(t.pathValidator.RootFor(ctx), filepath.Join(path, entry.Name()))
 		fmt.Fprintf(&sb, "%-6s %10d  %s\n", entryType, info.Size(), relPath)
 	}
 
@@ -38,7 +38,7 @@ func (t *fileWriteTool) InputSchema() json.RawMessage {
 	}`)
 }
 
-func (t *fileWriteTool) Execute(_ context.Context, args json.RawMessage) (string, error) {
+func (t *fileWriteTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
 	var input struct {
 		Path    string `json:"path"`
 		Content string `json:"content"`
@@ -51,7 +51,7 @@ func (t *fileWriteTool) Execute(_ context.Context, args json.RawMessage) (string
 		return "", fmt.Errorf("path is required")
 	}
 
-	resolved, err := t.pathValidator.Resolve(input.Path)
+	resolved, err := t.pathValidator.ResolveFor(ctx, input.Path)
 	if err != nil {
 		return "", err
 	}
@@ -21,6 +21,7 @@ func (t *globSearchTool) Description() string {
 	return "Find files by glob pattern (e.g. '**/*.go', 'src/**/*.ts'). Returns matching file paths sorted by modification time (most recent first)."
 }
 func (t *globSearchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *globSearchTool) ReadOnly() bool           { return true }
 
 func (t *globSearchTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -43,7 +44,7 @@ func (t *globSearchTool) InputSchema() json.RawMessage {
 	}`)
 }
 
-func (t *globSearchTool) Execute(_ context.Context, args json.RawMessage) (string, error) {
+func (t *globSearchTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
 	var input struct {
 		Pattern    string `json:"pattern"`
 		Path       string `json:"path"`
@@ -57,7 +58,7 @@ func (t *globSearchTool) Execute(_ context.Context, args json.RawMessage) (strin
 		return "", fmt.Errorf("pattern is required")
 	}
 
-	searchPath, err := t.pathValidator.Resolve(input.Path)
+	searchPath, err := t.pathValidator.ResolveFor(ctx, input.Path)
 	if err != nil {
 		return "", err
 	}
@@ -99,7 +100,7 @@ func (t *globSearchTool) Execute(_ context.Context, args json.RawMessage) (strin
 			return nil
 		}
 
-		relPath, relErr := filepath.Rel(t.pathValidator.WorkDir(), path)
+		relPath, relErr := filepath.Rel(t.pathValidator.RootFor(ctx), path)
 		if relErr != nil {
 			return nil
 		}
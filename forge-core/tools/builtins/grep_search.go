@@ -35,6 +35,7 @@ func (t *grepSearchTool) Description() string {
 	return "Search file contents using a regex pattern. Uses ripgrep (rg) if available, otherwise falls back to a Go-based search. Returns matches in file:line:content format."
 }
 func (t *grepSearchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *grepSearchTool) ReadOnly() bool           { return true }
 
 func (t *grepSearchTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -86,7 +87,7 @@ func (t *grepSearchTool) Execute(ctx context.Context, args json.RawMessage) (str
 		return "", fmt.Errorf("pattern is required")
 	}
 
-	searchPath, err := t.pathValidator.Resolve(input.Path)
+	searchPath, err := t.pathValidator.ResolveFor(ctx, input.Path)
 	if err != nil {
 		return "", err
 	}
@@ -150,7 +151,7 @@ func (t *grepSearchTool) searchWithRipgrep(ctx context.Context, rgPath, searchPa
 	}
 
 	// Make paths relative to workDir.
-	result = t.relativizePaths(result)
+	result = t.relativizePaths(ctx, result)
 
 	// Enforce total output line limit.
 	lines := strings.Split(result, "\n")
@@ -205,7 +206,7 @@ func (t *grepSearchTool) searchWithGo(ctx context.Context, searchPath, pattern,
 			return nil
 		}
 
-		relPath, _ := filepath.Rel(t.pathValidator.WorkDir(), path)
+		relPath, _ := filepath.Rel(t.pathValidator.RootFor(ctx), path)
 		f, openErr := os.Open(path)
 		if openErr != nil {
 			return nil
@@ -277,8 +278,8 @@ func (t *grepSearchTool) searchWithGo(ctx context.Context, searchPath, pattern,
 	return TruncateOutputCtx(ctx, sb.String()), nil
 }
 
-func (t *grepSearchTool) relativizePaths(output string) string {
-	prefix := t.pathValidator.WorkDir() + string(filepath.Separator)
+func (t *grepSearchTool) relativizePaths(ctx context.Context, output string) string {
+	prefix := t.pathValidator.RootFor(ctx) + string(filepath.Separator)
 	return strings.ReplaceAll(output, prefix, "")
 }
 
@@ -0,0 +1,65 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// HandoffRequest asks for the current conversation to be handed to a
+// human operator.
+type HandoffRequest struct {
+	Reason  string `json:"reason"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// HandoffController hands conversations to the operator channel
+// configured in forge.yaml's handoff block. The runtime implements it.
+type HandoffController interface {
+	// HandOff marks the conversation of the task in ctx human-controlled
+	// and alerts the operators.
+	HandOff(ctx context.Context, req HandoffRequest) error
+}
+
+type handoffTool struct {
+	controller HandoffController
+}
+
+// NewHandoffTool creates a handoff_to_human tool.
+func NewHandoffTool(c HandoffController) tools.Tool {
+	return &handoffTool{controller: c}
+}
+
+func (t *handoffTool) Name() string             { return "handoff_to_human" }
+func (t *handoffTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *handoffTool) Description() string {
+	return "Hand this conversation to a human operator, e.g. when the user asks for a person or the request needs a human decision. " +
+		"Afterwards you no longer answer in this conversation: the user's messages go to the operators until one of them hands it back."
+}
+
+func (t *handoffTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"reason": {"type": "string", "description": "Why a human is needed"},
+			"summary": {"type": "string", "description": "What the user wants and what has been tried, so the operator can pick up without rereading the conversation"}
+		},
+		"required": ["reason"]
+	}`)
+}
+
+func (t *handoffTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input HandoffRequest
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if input.Reason == "" {
+		return "", fmt.Errorf("reason is required")
+	}
+	if err := t.controller.HandOff(ctx, input); err != nil {
+		return "", err
+	}
+	return "The conversation is now with a human operator. Tell the user someone will reply here shortly, and do not try to resolve the request yourself.", nil
+}
@@ -21,6 +21,7 @@ func (t *jsonParseTool) Description() string {
 	return "Parse JSON data and optionally query with dot notation"
 }
 func (t *jsonParseTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *jsonParseTool) ReadOnly() bool           { return true }
 
 func (t *jsonParseTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
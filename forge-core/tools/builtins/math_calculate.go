@@ -21,6 +21,7 @@ type mathCalculateInput struct {
 func (t *mathCalculateTool) Name() string             { return "math_calculate" }
 func (t *mathCalculateTool) Description() string      { return "Evaluate arithmetic expressions safely" }
 func (t *mathCalculateTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *mathCalculateTool) ReadOnly() bool           { return true }
 
 func (t *mathCalculateTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
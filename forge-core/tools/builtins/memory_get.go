@@ -29,18 +29,19 @@ func (t *memoryGetTool) Description() string {
 	return "Read a specific memory file (e.g. MEMORY.md for curated facts, or a daily log like 2026-02-25.md)"
 }
 func (t *memoryGetTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *memoryGetTool) ReadOnly() bool           { return true }
 
 func (t *memoryGetTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
 		"type": "object",
 		"properties": {
-			"path": {"type": "string", "description": "Relative path to the memory file (e.g. MEMORY.md, 2026-02-25.md)"}
+			"path": {"type": "string", "description": "Relative path to the memory file (e.g. MEMORY.md, 2026-02-25.md), or a knowledge base source (kb:docs/setup.md)"}
 		},
 		"required": ["path"]
 	}`)
 }
 
-func (t *memoryGetTool) Execute(_ context.Context, args json.RawMessage) (string, error) {
+func (t *memoryGetTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
 	var input memoryGetInput
 	if err := json.Unmarshal(args, &input); err != nil {
 		return "", fmt.Errorf("parsing input: %w", err)
@@ -50,7 +51,11 @@ func (t *memoryGetTool) Execute(_ context.Context, args json.RawMessage) (string
 		return "", fmt.Errorf("path is required")
 	}
 
-	content, err := t.mgr.GetFile(input.Path)
+	mgr, err := memoryFor(ctx, t.mgr)
+	if err != nil {
+		return "", err
+	}
+	content, err := mgr.GetFile(input.Path)
 	if err != nil {
 		return "", fmt.Errorf("reading memory file: %w", err)
 	}
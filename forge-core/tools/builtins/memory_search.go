@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/memory"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
 	"github.com/initializ/forge/forge-core/tools"
 )
 
@@ -31,13 +34,17 @@ type memorySearchOutput struct {
 	LineStart int     `json:"line_start"`
 	LineEnd   int     `json:"line_end"`
 	Score     float64 `json:"score"`
+	// Citation names the knowledge base document a result came from.
+	Citation string `json:"citation,omitempty"`
 }
 
 func (t *memorySearchTool) Name() string { return "memory_search" }
 func (t *memorySearchTool) Description() string {
-	return "Search long-term agent memory for relevant context from past interactions and curated facts"
+	return "Search long-term agent memory for relevant context from past interactions, curated facts and the knowledge base. " +
+		"Cite knowledge base results by their citation."
 }
 func (t *memorySearchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *memorySearchTool) ReadOnly() bool           { return true }
 
 func (t *memorySearchTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -63,7 +70,11 @@ func (t *memorySearchTool) Execute(ctx context.Context, args json.RawMessage) (s
 		input.MaxResults = 5
 	}
 
-	results, err := t.mgr.Search(ctx, input.Query)
+	mgr, err := memoryFor(ctx, t.mgr)
+	if err != nil {
+		return "", err
+	}
+	results, err := mgr.Search(ctx, input.Query)
 	if err != nil {
 		return "", fmt.Errorf("memory search: %w", err)
 	}
@@ -81,6 +92,7 @@ func (t *memorySearchTool) Execute(ctx context.Context, args json.RawMessage) (s
 			LineStart: r.Chunk.LineStart,
 			LineEnd:   r.Chunk.LineEnd,
 			Score:     r.Score,
+			Citation:  mgr.Citation(r.Chunk),
 		}
 	}
 
@@ -90,3 +102,35 @@ func (t *memorySearchTool) Execute(ctx context.Context, args json.RawMessage) (s
 	}
 	return string(data), nil
 }
+
+// memoryFor returns the memory a task's tools use: the tenant's own
+// when the task runs for one, else the agent's.
+func memoryFor(ctx context.Context, mgr *memory.Manager) (*memory.Manager, error) {
+	if tenant := coreruntime.TenantFromContext(ctx); tenant != "" {
+		return mgr.ForTenant(tenant)
+	}
+	return mgr, nil
+}
+
+// Citations cites each result's source: a knowledge base document by its
+// title, a memory file by its path.
+func (t *memorySearchTool) Citations(_ json.RawMessage, result string) []a2a.Citation {
+	var output []memorySearchOutput
+	if json.Unmarshal([]byte(result), &output) != nil {
+		return nil
+	}
+	cits := make([]a2a.Citation, 0, len(output))
+	for _, o := range output {
+		title := t.mgr.KBTitle(o.Source)
+		if title == "" {
+			title = strings.TrimPrefix(o.Source, memory.KBSourcePrefix)
+		}
+		cits = append(cits, a2a.Citation{
+			Title:    title,
+			Source:   o.Source,
+			Location: fmt.Sprintf("lines %d-%d", o.LineStart+1, o.LineEnd+1),
+			Tool:     t.Name(),
+		})
+	}
+	return cits
+}
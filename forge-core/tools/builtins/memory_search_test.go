@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/initializ/forge/forge-core/memory"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
 )
 
 func TestMemorySearchTool(t *testing.T) {
@@ -50,6 +52,10 @@ func TestMemorySearchTool(t *testing.T) {
 	if !strings.Contains(result, "Go over Python") {
 		t.Errorf("expected result to contain memory content, got: %s", result)
 	}
+	cits := tool.(tools.Citer).Citations(args, result)
+	if len(cits) != 1 || cits[0].Title != "MEMORY.md" || cits[0].Source != "MEMORY.md" || cits[0].Location != "lines 1-1" {
+		t.Errorf("citations = %+v", cits)
+	}
 
 	// Test empty query.
 	args, _ = json.Marshal(map[string]any{"query": ""})
@@ -58,3 +64,29 @@ func TestMemorySearchTool(t *testing.T) {
 		t.Error("expected error for empty query")
 	}
 }
+
+func TestMemoryToolsPartitionTenants(t *testing.T) {
+	mgr, err := memory.NewManager(memory.ManagerConfig{MemoryDir: filepath.Join(t.TempDir(), "memory")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Close() //nolint:errcheck
+
+	acme := coreruntime.WithTenant(context.Background(), "acme")
+	globex := coreruntime.WithTenant(context.Background(), "globex")
+	write, search := NewMemoryWriteTool(mgr), NewMemorySearchTool(mgr)
+	if _, err := write.Execute(acme, json.RawMessage(`{"content":"The staging database is pg-acme-7."}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	query := json.RawMessage(`{"query":"staging database"}`)
+	for name, ctx := range map[string]context.Context{"acme": acme, "globex": globex, "the agent": context.Background()} {
+		result, err := search.Execute(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found := strings.Contains(result, "pg-acme-7"); found != (name == "acme") {
+			t.Errorf("search as %s = %s", name, result)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/initializ/forge/forge-core/memory"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+type memoryWriteTool struct {
+	mgr *memory.Manager
+}
+
+// NewMemoryWriteTool creates a memory_write tool backed by a Manager.
+// Like memory_search, it is registered conditionally (not via All()).
+func NewMemoryWriteTool(mgr *memory.Manager) tools.Tool {
+	return &memoryWriteTool{mgr: mgr}
+}
+
+type memoryWriteInput struct {
+	Content    string   `json:"content"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Confidence float64  `json:"confidence,omitempty"`
+	Importance float64  `json:"importance,omitempty"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+}
+
+func (t *memoryWriteTool) Name() string { return "memory_write" }
+func (t *memoryWriteTool) Description() string {
+	return "Save a fact to long-term memory so later conversations can find it with memory_search. " +
+		"Save durable, reusable facts (preferences, decisions, environment details), one per call, stated on their own. " +
+		"Set expires_at for facts that stop being true."
+}
+func (t *memoryWriteTool) Category() tools.Category { return tools.CategoryBuiltin }
+
+func (t *memoryWriteTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"content": {"type": "string", "description": "The fact to remember, self-contained (max 2000 chars)"},
+			"namespace": {"type": "string", "description": "Namespace to file it under, e.g. user-prefs or infra (default: default)"},
+			"tags": {"type": "array", "items": {"type": "string"}, "description": "Keywords to find it by"},
+			"confidence": {"type": "number", "minimum": 0, "maximum": 1, "description": "How sure you are the fact is correct"},
+			"importance": {"type": "number", "minimum": 0, "maximum": 1, "description": "How much it matters to keep the fact; important facts are archived later (default: 0.5)"},
+			"expires_at": {"type": "string", "description": "When the fact stops being true: RFC 3339 time or YYYY-MM-DD (default: never)"}
+		},
+		"required": ["content"]
+	}`)
+}
+
+func (t *memoryWriteTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input memoryWriteInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	req := memory.WriteRequest{
+		Namespace:  input.Namespace,
+		Content:    input.Content,
+		Tags:       input.Tags,
+		SourceTask: coreruntime.TaskIDFromContext(ctx),
+		Confidence: input.Confidence,
+		Importance: input.Importance,
+	}
+	if input.ExpiresAt != "" {
+		exp, err := parseExpiry(input.ExpiresAt)
+		if err != nil {
+			return "", err
+		}
+		req.ExpiresAt = &exp
+	}
+
+	mgr, err := memoryFor(ctx, t.mgr)
+	if err != nil {
+		return "", err
+	}
+	res, err := mgr.Write(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("memory write: %w", err)
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("marshalling result: %w", err)
+	}
+	return string(data), nil
+}
+
+// parseExpiry accepts an RFC 3339 time or a date, which expires at the
+// start of that day (UTC).
+func parseExpiry(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("expires_at %q: want an RFC 3339 time or YYYY-MM-DD", s)
+}
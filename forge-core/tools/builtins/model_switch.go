@@ -0,0 +1,92 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// ModelSwitchRequest asks the runtime to serve a different model.
+// Empty Provider or Model keeps the current one. Nil Fallbacks keeps
+// the current fallbacks; an empty list drops them.
+type ModelSwitchRequest struct {
+	Provider  string                `json:"provider,omitempty"`
+	Model     string                `json:"model,omitempty"`
+	Fallbacks []ModelSwitchFallback `json:"fallbacks,omitempty"`
+	Reason    string                `json:"reason,omitempty"`
+}
+
+// ModelSwitchFallback is one fallback candidate in a ModelSwitchRequest.
+type ModelSwitchFallback struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// ModelSwitcher swaps the running agent's LLM client. The runtime
+// implements it.
+type ModelSwitcher interface {
+	// SwitchModel validates and applies req, returning the
+	// provider/model now serving. source names the caller ("tool" or
+	// "api") for the audit trail.
+	SwitchModel(ctx context.Context, req ModelSwitchRequest, source string) (string, error)
+	// CurrentModel returns the provider/model serving now.
+	CurrentModel() string
+}
+
+type modelSwitchTool struct {
+	switcher ModelSwitcher
+}
+
+// NewModelSwitchTool creates a model_switch tool. It is an admin tool:
+// the runtime registers it only when forge.yaml lists it under tools.
+func NewModelSwitchTool(s ModelSwitcher) tools.Tool {
+	return &modelSwitchTool{switcher: s}
+}
+
+func (t *modelSwitchTool) Name() string             { return "model_switch" }
+func (t *modelSwitchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *modelSwitchTool) Description() string {
+	return "Switch the LLM provider and model this agent runs on, without a restart. " +
+		"Only use it when an operator asks, e.g. to move off a provider during an incident. " +
+		"Currently serving: " + t.switcher.CurrentModel() + "."
+}
+
+func (t *modelSwitchTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"provider": {"type": "string", "description": "LLM provider, e.g. openai or anthropic (default: keep the current one)"},
+			"model": {"type": "string", "description": "Model name (default: keep the current one, or the provider's default when the provider changes)"},
+			"fallbacks": {
+				"type": "array",
+				"description": "Fallback candidates replacing the current ones; an empty list removes them",
+				"items": {
+					"type": "object",
+					"properties": {
+						"provider": {"type": "string"},
+						"model": {"type": "string"}
+					},
+					"required": ["provider"]
+				}
+			},
+			"reason": {"type": "string", "description": "Why the model is being switched, for the audit trail"}
+		}
+	}`)
+}
+
+func (t *modelSwitchTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input ModelSwitchRequest
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if input.Provider == "" && input.Model == "" && input.Fallbacks == nil {
+		return "", fmt.Errorf("provider, model or fallbacks is required")
+	}
+	model, err := t.switcher.SwitchModel(ctx, input, "tool")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Switched to %s. The change applies from the next LLM call and lasts until the agent restarts or reloads its config.", model), nil
+}
@@ -0,0 +1,79 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// NotifyRequest is a proactive message for a configured notify target.
+type NotifyRequest struct {
+	Target   string            `json:"target"`
+	Message  string            `json:"message"`
+	Title    string            `json:"title,omitempty"`
+	Severity string            `json:"severity,omitempty"` // info, warning or critical
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+// Notifier delivers NotifyRequests to the channel targets declared in
+// forge.yaml's notify block. The runtime implements it.
+type Notifier interface {
+	// Notify renders and sends req. source names the caller ("tool",
+	// "api" or "alert") for the audit trail.
+	Notify(ctx context.Context, req NotifyRequest, source string) error
+	// Targets returns the configured target names.
+	Targets() []string
+}
+
+type notifyTool struct {
+	notifier Notifier
+}
+
+// NewNotifyTool creates a notify tool for sending proactive messages.
+func NewNotifyTool(n Notifier) tools.Tool {
+	return &notifyTool{notifier: n}
+}
+
+func (t *notifyTool) Name() string             { return "notify" }
+func (t *notifyTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *notifyTool) Description() string {
+	targets := t.notifier.Targets()
+	sort.Strings(targets)
+	return "Send a message to a configured channel target without being asked, e.g. to alert on-call about a problem found while monitoring. " +
+		"Available targets: " + strings.Join(targets, ", ") + ". Notifications are rate limited per target."
+}
+
+func (t *notifyTool) InputSchema() json.RawMessage {
+	targets := t.notifier.Targets()
+	sort.Strings(targets)
+	enum, _ := json.Marshal(targets)
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {
+			"target": {"type": "string", "enum": %s, "description": "The notify target to message"},
+			"message": {"type": "string", "description": "The message body (markdown)"},
+			"title": {"type": "string", "description": "Optional short headline"},
+			"severity": {"type": "string", "enum": ["info", "warning", "critical"], "description": "Optional severity (default info)"},
+			"fields": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Optional key/value details for the target's template"}
+		},
+		"required": ["target", "message"]
+	}`, enum))
+}
+
+func (t *notifyTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input NotifyRequest
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if input.Target == "" || input.Message == "" {
+		return "", fmt.Errorf("target and message are required")
+	}
+	if err := t.notifier.Notify(ctx, input, "tool"); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Notification sent to %q.", input.Target), nil
+}
@@ -1,14 +1,17 @@
 package builtins
 
 import (
-	"fmt"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/initializ/forge/forge-core/tools"
 )
 
 // PathValidator provides path confinement to a working directory.
-// All resolved paths are guaranteed to be within workDir.
+// All resolved paths are guaranteed to be within workDir — or, for a tool
+// running sandboxed, within the task scratch directory (see ResolveFor).
 type PathValidator struct {
 	workDir string // absolute path
 }
@@ -23,37 +26,55 @@ func NewPathValidator(workDir string) *PathValidator {
 }
 
 // Resolve converts a relative or absolute path to an absolute path within workDir.
-// It returns an error if the resolved path escapes the working directory.
+// It returns a *tools.PathViolation if the resolved path escapes the working
+// directory, directly or through a symlink.
 func (v *PathValidator) Resolve(path string) (string, error) {
 	if strings.TrimSpace(path) == "" {
 		return v.workDir, nil
 	}
 
-	var resolved string
-	if filepath.IsAbs(path) {
-		resolved = filepath.Clean(path)
-	} else {
-		resolved = filepath.Clean(filepath.Join(v.workDir, path))
+	if !filepath.IsAbs(path) {
+		resolved := filepath.Clean(filepath.Join(v.workDir, path))
 		// If the path doesn't exist but workspace/<path> does, use that.
 		// This handles the common case where the LLM passes "myrepo" instead
 		// of "workspace/myrepo" for cloned repositories.
 		if _, err := os.Stat(resolved); os.IsNotExist(err) {
 			wsPath := filepath.Clean(filepath.Join(v.workDir, "workspace", path))
 			if _, wsErr := os.Stat(wsPath); wsErr == nil {
-				resolved = wsPath
+				path = filepath.Join("workspace", path)
 			}
 		}
 	}
 
-	// Ensure the resolved path is within workDir.
-	if resolved != v.workDir && !strings.HasPrefix(resolved, v.workDir+string(filepath.Separator)) {
-		return "", fmt.Errorf("path %q resolves outside the working directory", path)
-	}
+	return tools.ConfinePath(v.workDir, path, "working directory")
+}
 
-	return resolved, nil
+// ResolveFor is Resolve for a tool call: when the tool runs sandboxed,
+// paths resolve against, and must stay within, the task scratch directory.
+func (v *PathValidator) ResolveFor(ctx context.Context, path string) (string, error) {
+	root := tools.SandboxRootFromContext(ctx)
+	if root == "" {
+		return v.Resolve(path)
+	}
+	if strings.TrimSpace(path) == "" {
+		path = "."
+	}
+	return tools.ConfinePath(root, path, "task sandbox")
 }
 
 // WorkDir returns the absolute working directory.
 func (v *PathValidator) WorkDir() string {
 	return v.workDir
 }
+
+// RootFor returns the directory a tool call's paths are relative to: the
+// task scratch directory for a sandboxed tool, otherwise WorkDir.
+func (v *PathValidator) RootFor(ctx context.Context) string {
+	if root := tools.SandboxRootFromContext(ctx); root != "" {
+		if abs, err := filepath.Abs(root); err == nil {
+			return abs
+		}
+		return root
+	}
+	return v.workDir
+}
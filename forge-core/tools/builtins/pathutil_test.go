@@ -1,9 +1,14 @@
 package builtins
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/initializ/forge/forge-core/tools"
 )
 
 func TestPathValidator_Resolve(t *testing.T) {
@@ -103,3 +108,37 @@ func TestPathValidator_Resolve(t *testing.T) {
 		})
 	}
 }
+
+func TestPathValidator_ResolveForSandbox(t *testing.T) {
+	workDir := t.TempDir()
+	scratch := t.TempDir()
+	pv := NewPathValidator(workDir)
+	ctx := tools.WithSandboxRoot(context.Background(), scratch)
+
+	got, err := pv.ResolveFor(ctx, "out/report.md")
+	if err != nil || got != filepath.Join(scratch, "out", "report.md") {
+		t.Errorf("ResolveFor(out/report.md) = %q, %v; want it under the sandbox", got, err)
+	}
+	if pv.RootFor(ctx) != scratch {
+		t.Errorf("RootFor = %q, want %q", pv.RootFor(ctx), scratch)
+	}
+
+	// The agent working directory is outside the sandbox.
+	_, err = pv.ResolveFor(ctx, filepath.Join(workDir, "go.mod"))
+	var violation *tools.PathViolation
+	if !errors.As(err, &violation) || !strings.Contains(err.Error(), "outside the task sandbox") {
+		t.Errorf("ResolveFor(workDir file) error = %v, want a task sandbox violation", err)
+	}
+}
+
+func TestPathValidator_SymlinkEscape(t *testing.T) {
+	workDir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(workDir, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	_, err := NewPathValidator(workDir).Resolve("link/secret.txt")
+	if err == nil || !strings.Contains(err.Error(), "outside the working directory") {
+		t.Errorf("Resolve through an escaping symlink: err = %v", err)
+	}
+}
@@ -28,6 +28,7 @@ func NewReadSkillTool(workDir string) *ReadSkillTool {
 
 func (t *ReadSkillTool) Name() string             { return "read_skill" }
 func (t *ReadSkillTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *ReadSkillTool) ReadOnly() bool           { return true }
 
 func (t *ReadSkillTool) Description() string {
 	return "Read the full instructions for an available skill. " +
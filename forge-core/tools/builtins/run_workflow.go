@@ -0,0 +1,87 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/workflow"
+)
+
+// WorkflowRunner runs the workflows declared in forge.yaml.
+// *workflow.Engine implements it.
+type WorkflowRunner interface {
+	Workflows() []types.WorkflowConfig
+	Run(ctx context.Context, id string, opts workflow.RunOptions) (*workflow.Run, error)
+}
+
+type runWorkflowTool struct {
+	runner WorkflowRunner
+}
+
+// NewRunWorkflowTool creates a run_workflow tool. The runtime registers it
+// when forge.yaml declares workflows.
+func NewRunWorkflowTool(r WorkflowRunner) tools.Tool {
+	return &runWorkflowTool{runner: r}
+}
+
+func (t *runWorkflowTool) Name() string             { return workflow.RunWorkflowTool }
+func (t *runWorkflowTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *runWorkflowTool) Description() string {
+	var sb strings.Builder
+	sb.WriteString("Run a predefined workflow: a fixed sequence of steps that runs the same way every time. " +
+		"Prefer it over doing the same steps yourself. Returns the run's output and each step's result. Available workflows:")
+	for _, wf := range t.runner.Workflows() {
+		sb.WriteString("\n- " + wf.ID)
+		if len(wf.Inputs) > 0 {
+			sb.WriteString(" (inputs: " + strings.Join(wf.Inputs, ", ") + ")")
+		}
+		if wf.Description != "" {
+			sb.WriteString(": " + wf.Description)
+		}
+	}
+	return sb.String()
+}
+
+func (t *runWorkflowTool) InputSchema() json.RawMessage {
+	ids := []string{}
+	for _, wf := range t.runner.Workflows() {
+		ids = append(ids, wf.ID)
+	}
+	enum, _ := json.Marshal(ids)
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {
+			"workflow": {"type": "string", "enum": %s, "description": "The workflow to run"},
+			"inputs": {"type": "object", "description": "The workflow's inputs by name"}
+		},
+		"required": ["workflow"]
+	}`, enum))
+}
+
+func (t *runWorkflowTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input struct {
+		Workflow string         `json:"workflow"`
+		Inputs   map[string]any `json:"inputs"`
+	}
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if input.Workflow == "" {
+		return "", fmt.Errorf("workflow is required")
+	}
+	run, err := t.runner.Run(ctx, input.Workflow, workflow.RunOptions{Inputs: input.Inputs, Trigger: "tool"})
+	if run == nil {
+		return "", err
+	}
+	// A failed run is still a result: the steps show the model what
+	// went wrong.
+	out, err := json.Marshal(run)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
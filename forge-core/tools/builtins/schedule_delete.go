@@ -49,18 +49,18 @@ func (t *scheduleDeleteTool) Execute(ctx context.Context, args json.RawMessage)
 		return "", fmt.Errorf("id is required")
 	}
 
-	existing, err := t.store.Get(ctx, input.ID)
+	existing, err := t.store.Get(ctx, scheduleID(ctx, input.ID))
 	if err != nil {
 		return "", fmt.Errorf("looking up schedule: %w", err)
 	}
-	if existing == nil {
+	if existing == nil || !ownSchedule(ctx, existing.ID) {
 		return "", fmt.Errorf("schedule %q not found", input.ID)
 	}
 	if existing.Source == "yaml" {
 		return "", fmt.Errorf("cannot delete schedule %q: it is defined in forge.yaml (source: yaml). Remove it from forge.yaml instead", input.ID)
 	}
 
-	if err := t.store.Delete(ctx, input.ID); err != nil {
+	if err := t.store.Delete(ctx, existing.ID); err != nil {
 		return "", fmt.Errorf("deleting schedule: %w", err)
 	}
 
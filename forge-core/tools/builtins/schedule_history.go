@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -27,6 +28,7 @@ type scheduleHistoryInput struct {
 
 func (t *scheduleHistoryTool) Name() string             { return "schedule_history" }
 func (t *scheduleHistoryTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *scheduleHistoryTool) ReadOnly() bool           { return true }
 func (t *scheduleHistoryTool) Description() string {
 	return "View execution history for scheduled tasks. Optionally filter by schedule ID."
 }
@@ -57,10 +59,22 @@ func (t *scheduleHistoryTool) Execute(ctx context.Context, args json.RawMessage)
 		limit = 50
 	}
 
-	history, err := t.store.History(ctx, input.ScheduleID, limit)
+	// Other tenants' runs are filtered out after the read, so read them
+	// all when no one schedule is asked for.
+	var history []scheduler.HistoryEntry
+	var err error
+	if input.ScheduleID != "" {
+		history, err = t.store.History(ctx, scheduleID(ctx, input.ScheduleID), limit)
+	} else {
+		history, err = t.store.History(ctx, "", 0)
+	}
 	if err != nil {
 		return "", fmt.Errorf("reading history: %w", err)
 	}
+	history = slices.DeleteFunc(history, func(h scheduler.HistoryEntry) bool { return !ownSchedule(ctx, h.ScheduleID) })
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
 
 	if len(history) == 0 {
 		return "No execution history found.", nil
@@ -77,7 +91,7 @@ func (t *scheduleHistoryTool) Execute(ctx context.Context, args json.RawMessage)
 		}
 		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
 			h.Timestamp.Format(time.RFC3339),
-			h.ScheduleID,
+			displayScheduleID(ctx, h.ScheduleID),
 			h.Status,
 			h.Duration,
 			h.CorrelationID,
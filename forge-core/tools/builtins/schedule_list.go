@@ -26,6 +26,7 @@ type scheduleListInput struct {
 
 func (t *scheduleListTool) Name() string             { return "schedule_list" }
 func (t *scheduleListTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *scheduleListTool) ReadOnly() bool           { return true }
 func (t *scheduleListTool) Description() string {
 	return "List all scheduled tasks with their cron expressions, status, and next fire time."
 }
@@ -62,13 +63,13 @@ func (t *scheduleListTool) Execute(ctx context.Context, args json.RawMessage) (s
 	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
 
 	for _, sched := range schedules {
-		if input.EnabledOnly != nil && *input.EnabledOnly && !sched.Enabled {
+		if input.EnabledOnly != nil && *input.EnabledOnly && !sched.Enabled || !ownSchedule(ctx, sched.ID) {
 			continue
 		}
 
 		nextFire := "N/A"
 		if sched.Enabled {
-			parsed, parseErr := scheduler.Parse(sched.Cron)
+			parsed, parseErr := scheduler.ParseIn(sched.Cron, sched.Timezone)
 			if parseErr == nil {
 				ref := sched.LastRun
 				if ref.IsZero() {
@@ -87,8 +88,13 @@ func (t *scheduleListTool) Execute(ctx context.Context, args json.RawMessage) (s
 			task = task[:57] + "..."
 		}
 
+		cron := sched.Cron
+		if sched.Timezone != "" {
+			cron += " (" + sched.Timezone + ")"
+		}
+
 		fmt.Fprintf(&b, "| %s | %s | %s | %t | %s | %s |\n",
-			sched.ID, sched.Cron, sched.Source, sched.Enabled, nextFire, task)
+			displayScheduleID(ctx, sched.ID), cron, sched.Source, sched.Enabled, nextFire, task)
 	}
 
 	return b.String(), nil
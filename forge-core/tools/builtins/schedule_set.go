@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
 	"github.com/initializ/forge/forge-core/scheduler"
 	"github.com/initializ/forge/forge-core/tools"
 )
@@ -32,17 +33,20 @@ func NewScheduleSetTool(store scheduler.ScheduleStore, reloader ScheduleReloader
 type scheduleSetInput struct {
 	ID            string `json:"id"`
 	Cron          string `json:"cron"`
+	Timezone      string `json:"timezone"`
 	Task          string `json:"task"`
 	Skill         string `json:"skill"`
 	Channel       string `json:"channel"`
 	ChannelTarget string `json:"channel_target"`
 	Enabled       *bool  `json:"enabled"`
+	MaxRuntime    string `json:"max_runtime"`
+	Overlap       string `json:"overlap"`
 }
 
 func (t *scheduleSetTool) Name() string             { return "schedule_set" }
 func (t *scheduleSetTool) Category() tools.Category { return tools.CategoryBuiltin }
 func (t *scheduleSetTool) Description() string {
-	return "Create or update a recurring scheduled task. Supports standard 5-field cron expressions (e.g. '*/15 * * * *'), aliases (@hourly, @daily, @weekly, @monthly), and intervals (@every 5m)."
+	return "Create or update a recurring scheduled task. Supports standard 5-field cron expressions (e.g. '*/15 * * * *'), aliases (@hourly, @daily, @weekly, @monthly), and intervals (@every 5m), read in UTC unless a timezone is given."
 }
 
 func (t *scheduleSetTool) InputSchema() json.RawMessage {
@@ -51,11 +55,14 @@ func (t *scheduleSetTool) InputSchema() json.RawMessage {
 		"properties": {
 			"id": {"type": "string", "description": "Schedule ID (auto-generated from task if omitted). Must be kebab-case."},
 			"cron": {"type": "string", "description": "Cron expression: 5-field (min hour dom mon dow), @hourly/@daily/@weekly/@monthly, or @every <duration>"},
+			"timezone": {"type": "string", "description": "IANA timezone the cron fields are read in, e.g. America/New_York (default: UTC)"},
 			"task": {"type": "string", "description": "The task description to execute on each trigger"},
 			"skill": {"type": "string", "description": "Optional skill name to invoke"},
 			"channel": {"type": "string", "description": "Channel adapter to send results to (e.g. slack, telegram). Required for schedule results to be delivered to a channel."},
 			"channel_target": {"type": "string", "description": "Destination ID for the channel (Slack channel ID, Telegram chat ID). Required when channel is set."},
-			"enabled": {"type": "boolean", "description": "Whether the schedule is active (default: true)"}
+			"enabled": {"type": "boolean", "description": "Whether the schedule is active (default: true)"},
+			"max_runtime": {"type": "string", "description": "Cancel a run after this long, e.g. 10m (default: unbounded)"},
+			"overlap": {"type": "string", "enum": ["skip", "queue", "cancel-previous"], "description": "What to do when the task comes due while its previous run is still going: skip the new run (default), queue it to run next, or cancel the previous run"}
 		},
 		"required": ["cron", "task"]
 	}`)
@@ -76,12 +83,23 @@ func (t *scheduleSetTool) Execute(ctx context.Context, args json.RawMessage) (st
 		return "", fmt.Errorf("task is required")
 	}
 
-	// Validate cron expression.
-	parsed, err := scheduler.Parse(input.Cron)
+	// Validate cron expression and timezone.
+	parsed, err := scheduler.ParseIn(input.Cron, input.Timezone)
 	if err != nil {
 		return "", fmt.Errorf("invalid cron expression: %w", err)
 	}
 
+	var maxRuntime time.Duration
+	if input.MaxRuntime != "" {
+		maxRuntime, err = time.ParseDuration(input.MaxRuntime)
+		if err != nil || maxRuntime <= 0 {
+			return "", fmt.Errorf("invalid max_runtime %q: use a positive duration such as 10m", input.MaxRuntime)
+		}
+	}
+	if !scheduler.ValidOverlap(input.Overlap) {
+		return "", fmt.Errorf("invalid overlap %q: must be skip, queue or cancel-previous", input.Overlap)
+	}
+
 	// Auto-generate ID if not provided.
 	id := input.ID
 	if id == "" {
@@ -90,9 +108,10 @@ func (t *scheduleSetTool) Execute(ctx context.Context, args json.RawMessage) (st
 	if !kebabPattern.MatchString(id) {
 		return "", fmt.Errorf("schedule ID %q must be kebab-case (lowercase letters, numbers, hyphens)", id)
 	}
+	tenant := coreruntime.TenantFromContext(ctx)
 
 	// Check if modifying a yaml-sourced schedule.
-	existing, err := t.store.Get(ctx, id)
+	existing, err := t.store.Get(ctx, coreruntime.TenantScopedID(tenant, id))
 	if err != nil {
 		return "", fmt.Errorf("checking existing schedule: %w", err)
 	}
@@ -109,8 +128,9 @@ func (t *scheduleSetTool) Execute(ctx context.Context, args json.RawMessage) (st
 
 	now := time.Now().UTC()
 	sched := scheduler.Schedule{
-		ID:            id,
+		ID:            coreruntime.TenantScopedID(tenant, id),
 		Cron:          input.Cron,
+		Timezone:      input.Timezone,
 		Task:          input.Task,
 		Skill:         input.Skill,
 		Channel:       input.Channel,
@@ -118,6 +138,9 @@ func (t *scheduleSetTool) Execute(ctx context.Context, args json.RawMessage) (st
 		Source:        "llm",
 		Enabled:       enabled,
 		Created:       now,
+		MaxRuntime:    maxRuntime,
+		Overlap:       input.Overlap,
+		Tenant:        tenant,
 	}
 
 	// Preserve fields from existing schedule.
@@ -131,6 +154,12 @@ func (t *scheduleSetTool) Execute(ctx context.Context, args json.RawMessage) (st
 			sched.Channel = existing.Channel
 			sched.ChannelTarget = existing.ChannelTarget
 		}
+		if input.MaxRuntime == "" {
+			sched.MaxRuntime = existing.MaxRuntime
+		}
+		if input.Overlap == "" {
+			sched.Overlap = existing.Overlap
+		}
 	}
 
 	if err := t.store.Set(ctx, sched); err != nil {
@@ -146,8 +175,70 @@ func (t *scheduleSetTool) Execute(ctx context.Context, args json.RawMessage) (st
 		action = "Updated"
 	}
 
+	cron := input.Cron
+	if input.Timezone != "" {
+		cron += " (" + input.Timezone + ")"
+	}
 	return fmt.Sprintf("%s schedule %q.\nCron: %s\nTask: %s\nNext fire: %s",
-		action, id, input.Cron, input.Task, next.Format(time.RFC3339)), nil
+		action, id, cron, input.Task, next.Format(time.RFC3339)), nil
+}
+
+// Compensate reverses a schedule_set call that created a new schedule
+// by deleting it again. Calls that updated an existing schedule are not
+// reversed — the previous definition is not retained, so there is
+// nothing safe to restore — and report an empty description.
+func (t *scheduleSetTool) Compensate(ctx context.Context, args json.RawMessage, result string) (string, error) {
+	if !strings.HasPrefix(result, "Created schedule ") {
+		return "", nil
+	}
+	var input scheduleSetInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	id := input.ID
+	if id == "" {
+		id = slugify(input.Task)
+	}
+
+	existing, err := t.store.Get(ctx, scheduleID(ctx, id))
+	if err != nil {
+		return "", fmt.Errorf("looking up schedule: %w", err)
+	}
+	if existing == nil || existing.Source == "yaml" {
+		return "", nil
+	}
+	if err := t.store.Delete(ctx, existing.ID); err != nil {
+		return "", fmt.Errorf("deleting schedule: %w", err)
+	}
+	t.reloader.Reload(ctx)
+
+	return fmt.Sprintf("Deleted schedule %q.", id), nil
+}
+
+// A task run for a tenant sees only the tenant's schedules, under the
+// IDs it gave them; the store keys them by the tenant-scoped ID.
+
+// scheduleID is the store ID of the schedule a task calls id.
+func scheduleID(ctx context.Context, id string) string {
+	return coreruntime.TenantScopedID(coreruntime.TenantFromContext(ctx), id)
+}
+
+// ownSchedule reports whether the task's tenant, or its lack of one,
+// owns the schedule with store ID id. Untenanted IDs are kebab-case, so
+// only tenant-scoped ones carry the separator.
+func ownSchedule(ctx context.Context, id string) bool {
+	if tenant := coreruntime.TenantFromContext(ctx); tenant != "" {
+		return strings.HasPrefix(id, tenant+coreruntime.TenantSeparator)
+	}
+	return !strings.Contains(id, coreruntime.TenantSeparator)
+}
+
+// displayScheduleID is the ID the task knows the schedule by.
+func displayScheduleID(ctx context.Context, id string) string {
+	if tenant := coreruntime.TenantFromContext(ctx); tenant != "" {
+		return strings.TrimPrefix(id, tenant+coreruntime.TenantSeparator)
+	}
+	return id
 }
 
 // slugify converts a task description into a kebab-case ID.
@@ -13,6 +13,7 @@ import (
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/charset"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/credentials"
 	"github.com/initializ/forge/forge-core/security"
 	"github.com/initializ/forge/forge-core/tools"
@@ -67,6 +68,7 @@ func (t *webFetchTool) Description() string {
 		"non-GET method, use http_request instead; for finding pages, use web_search."
 }
 func (t *webFetchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *webFetchTool) ReadOnly() bool           { return true }
 
 func (t *webFetchTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -201,6 +203,23 @@ func (t *webFetchTool) Execute(ctx context.Context, args json.RawMessage) (strin
 	return string(data), nil
 }
 
+// Citations cites the fetched page by its final URL, titled with the
+// heading extraction put first (HTML pages only).
+func (t *webFetchTool) Citations(_ json.RawMessage, result string) []a2a.Citation {
+	var out struct {
+		URL     string `json:"url"`
+		Content string `json:"content"`
+	}
+	if json.Unmarshal([]byte(result), &out) != nil || out.URL == "" {
+		return nil
+	}
+	title := ""
+	if first, _, _ := strings.Cut(out.Content, "\n"); strings.HasPrefix(first, "# ") {
+		title = strings.TrimSpace(strings.TrimPrefix(first, "# "))
+	}
+	return []a2a.Citation{{Title: title, URL: out.URL, Tool: t.Name()}}
+}
+
 // contentKind distinguishes HTML (needs extraction) from already-textual
 // payloads (returned as-is).
 type contentKind int
@@ -328,6 +347,12 @@ func extractReadableText(htmlContent string) string {
 	return tidyText(b.String())
 }
 
+// ExtractReadableText is the HTML-to-text conversion web_fetch applies,
+// exported for the knowledge base's HTML ingestion.
+func ExtractReadableText(htmlContent string) string {
+	return extractReadableText(htmlContent)
+}
+
 func isHeading(tag string) bool {
 	return len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6'
 }
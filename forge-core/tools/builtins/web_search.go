@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/tools"
 )
 
@@ -17,6 +18,7 @@ func (t *webSearchTool) Description() string {
 		"For in-depth research or comprehensive reports, prefer the tavily_research tool if available."
 }
 func (t *webSearchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *webSearchTool) ReadOnly() bool           { return true }
 
 func (t *webSearchTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -67,6 +69,33 @@ func (t *webSearchTool) Execute(ctx context.Context, args json.RawMessage) (stri
 	return provider.search(ctx, input.Query, opts)
 }
 
+// Citations cites each search hit. Tavily returns titled results;
+// Perplexity returns a bare list of source URLs.
+func (t *webSearchTool) Citations(_ json.RawMessage, result string) []a2a.Citation {
+	var out struct {
+		Results []struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+		} `json:"results"`
+		Citations []string `json:"citations"`
+	}
+	if json.Unmarshal([]byte(result), &out) != nil {
+		return nil
+	}
+	var cits []a2a.Citation
+	for _, r := range out.Results {
+		if r.URL != "" {
+			cits = append(cits, a2a.Citation{Title: r.Title, URL: r.URL, Tool: t.Name()})
+		}
+	}
+	for _, u := range out.Citations {
+		if u != "" {
+			cits = append(cits, a2a.Citation{URL: u, Tool: t.Name()})
+		}
+	}
+	return cits
+}
+
 // resolveWebSearchProvider selects the web search provider based on environment.
 // Priority: WEB_SEARCH_PROVIDER env > auto-detect (Tavily first, then Perplexity).
 func resolveWebSearchProvider() (webSearchProvider, error) {
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Task origins: where the task a tool call runs for came from.
+const (
+	// OriginChannel is a message from a channel adapter (Slack,
+	// Telegram, Teams, ...) or the public web chat widget.
+	OriginChannel = "channel"
+	// OriginREST is a call to the REST endpoints (POST /tasks/send,
+	// /tasks/sendSubscribe).
+	OriginREST = "rest"
+	// OriginA2A is a JSON-RPC call to the A2A endpoint.
+	OriginA2A = "a2a"
+	// OriginSchedule is a cron schedule firing.
+	OriginSchedule = "schedule"
+	// OriginUI is the forge ui dashboard chat.
+	OriginUI = "ui"
+	// OriginWebhook is a signed webhook trigger (forge.yaml triggers).
+	OriginWebhook = "webhook"
+)
+
+// Origins lists every task origin.
+var Origins = []string{OriginChannel, OriginREST, OriginA2A, OriginSchedule, OriginUI, OriginWebhook}
+
+type originKey struct{}
+
+// WithOrigin records the task origin on ctx. The registry applies the
+// origin's policy (see SetOriginPolicies) to every tool call under ctx.
+func WithOrigin(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// OriginFromContext returns the task origin, or "" when none was set.
+func OriginFromContext(ctx context.Context) string {
+	o, _ := ctx.Value(originKey{}).(string)
+	return o
+}
+
+// OriginPolicy limits the tools a task from one origin may call. Entries
+// are tool names or path.Match patterns ("github__*"). An empty Allow
+// allows every tool; Deny wins over Allow.
+type OriginPolicy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Permits reports whether the policy lets tasks call tool.
+func (p OriginPolicy) Permits(tool string) bool {
+	if matchAny(p.Deny, tool) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchAny(p.Allow, tool)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetOriginPolicies installs per-origin tool policies, keyed by origin.
+// Tasks from an origin without a policy, or without an origin, may call
+// every tool.
+func (r *Registry) SetOriginPolicies(policies map[string]OriginPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.origins = policies
+}
+
+// checkOrigin returns an error when ctx's origin may not call name.
+// Callers hold r.mu.
+func (r *Registry) checkOrigin(ctx context.Context, name string) error {
+	origin := OriginFromContext(ctx)
+	p, ok := r.origins[origin]
+	if !ok || p.Permits(name) {
+		return nil
+	}
+	return fmt.Errorf("tool %q is not available to tasks from origin %q", name, origin)
+}
+
+// ToolDefinitionsFor returns the definitions of the tools ctx's origin
+// may call, so the model is not offered tools it would be refused.
+func (r *Registry) ToolDefinitionsFor(ctx context.Context) []llm.ToolDefinition {
+	r.mu.RLock()
+	p, ok := r.origins[OriginFromContext(ctx)]
+	r.mu.RUnlock()
+	defs := r.ToolDefinitions()
+	if !ok {
+		return defs
+	}
+	kept := defs[:0]
+	for _, d := range defs {
+		if p.Permits(d.Function.Name) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// ValidateOriginPolicies checks that every key is a known origin and
+// every pattern is well-formed.
+func ValidateOriginPolicies(policies map[string]OriginPolicy) error {
+	keys := make([]string, 0, len(policies))
+	for k := range policies {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, origin := range keys {
+		known := false
+		for _, o := range Origins {
+			known = known || o == origin
+		}
+		if !known {
+			return fmt.Errorf("tool_policies: unknown origin %q (want one of %v)", origin, Origins)
+		}
+		p := policies[origin]
+		for _, pat := range append(append([]string(nil), p.Allow...), p.Deny...) {
+			if _, err := path.Match(pat, ""); err != nil || pat == "" {
+				return fmt.Errorf("tool_policies.%s: invalid tool pattern %q", origin, pat)
+			}
+		}
+	}
+	return nil
+}
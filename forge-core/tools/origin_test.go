@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOriginPolicies(t *testing.T) {
+	reg := NewRegistry()
+	for _, name := range []string{"cli_execute", "file_read", "github_create_issue", "github_get_issue", "web_search"} {
+		if err := reg.Register(&stubTool{name: name, category: CategoryBuiltin}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg.SetOriginPolicies(map[string]OriginPolicy{
+		OriginChannel:  {Allow: []string{"web_search", "file_read", "github_*"}, Deny: []string{"github_create_*"}},
+		OriginSchedule: {Deny: []string{"cli_execute"}},
+	})
+
+	offered := func(ctx context.Context) string {
+		var names []string
+		for _, d := range reg.ToolDefinitionsFor(ctx) {
+			names = append(names, d.Function.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	channel := WithOrigin(context.Background(), OriginChannel)
+	if got, want := offered(channel), "file_read,github_get_issue,web_search"; got != want {
+		t.Errorf("channel tools = %s, want %s", got, want)
+	}
+	if got, want := offered(WithOrigin(context.Background(), OriginSchedule)), "file_read,github_create_issue,github_get_issue,web_search"; got != want {
+		t.Errorf("schedule tools = %s, want %s", got, want)
+	}
+	if got := offered(context.Background()); strings.Count(got, ",") != 4 {
+		t.Errorf("untagged tools = %s, want all five", got)
+	}
+
+	if _, err := reg.Execute(channel, "cli_execute", json.RawMessage(`{}`)); err == nil || !strings.Contains(err.Error(), `origin "channel"`) {
+		t.Errorf("channel cli_execute err = %v", err)
+	}
+	if _, err := reg.Execute(channel, "web_search", json.RawMessage(`{}`)); err != nil {
+		t.Errorf("channel web_search: %v", err)
+	}
+	if _, err := reg.Filter([]string{"cli_execute"}).Execute(channel, "cli_execute", json.RawMessage(`{}`)); err == nil {
+		t.Error("Filter dropped the origin policies")
+	}
+}
+
+func TestValidateOriginPolicies(t *testing.T) {
+	if err := ValidateOriginPolicies(map[string]OriginPolicy{OriginUI: {Allow: []string{"*"}}}); err != nil {
+		t.Errorf("valid policies: %v", err)
+	}
+	if err := ValidateOriginPolicies(map[string]OriginPolicy{"slack": {}}); err == nil {
+		t.Error("unknown origin accepted")
+	}
+	if err := ValidateOriginPolicies(map[string]OriginPolicy{OriginREST: {Deny: []string{"file_["}}}); err == nil {
+		t.Error("malformed pattern accepted")
+	}
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ApplyReadOnly restricts reg for an agent in `mode: read-only`:
+//
+//   - builtins that don't declare themselves ReadOnly are removed
+//     (file_write, file_edit, schedule_set, memory_write, ...);
+//   - http_request may only GET;
+//   - cli_execute runs only command lines that start with one of
+//     safeCommands ("kubectl get", "git log"), and is removed when
+//     there are none.
+//
+// Adapter, custom and MCP tools are left alone. ApplyReadOnly returns
+// the names of the tools it removed, sorted.
+func ApplyReadOnly(reg *Registry, safeCommands []string) []string {
+	var safe [][]string
+	for _, s := range safeCommands {
+		if f := strings.Fields(s); len(f) > 0 {
+			safe = append(safe, f)
+		}
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	var removed []string
+	for name, t := range reg.tools {
+		switch {
+		case name == "http_request":
+			reg.tools[name] = &readOnlyHTTPTool{Tool: t}
+		case name == "cli_execute" && len(safe) > 0:
+			reg.tools[name] = &readOnlyCLITool{Tool: t, safe: safe}
+		case t.Category() == CategoryBuiltin && !IsReadOnly(t):
+			delete(reg.tools, name)
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// readOnlyHTTPTool limits http_request to GET.
+type readOnlyHTTPTool struct{ Tool }
+
+func (t *readOnlyHTTPTool) Description() string {
+	return "Make HTTP GET requests (the agent is read-only)"
+}
+
+func (t *readOnlyHTTPTool) InputSchema() json.RawMessage {
+	var schema map[string]any
+	if err := json.Unmarshal(t.Tool.InputSchema(), &schema); err != nil {
+		return t.Tool.InputSchema()
+	}
+	if props, ok := schema["properties"].(map[string]any); ok {
+		if method, ok := props["method"].(map[string]any); ok {
+			method["enum"] = []string{"GET"}
+		}
+	}
+	out, err := json.Marshal(schema)
+	if err != nil {
+		return t.Tool.InputSchema()
+	}
+	return out
+}
+
+func (t *readOnlyHTTPTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var in struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(args, &in); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if !strings.EqualFold(in.Method, "GET") {
+		return "", fmt.Errorf("read-only mode: http_request may only GET, not %s", strings.ToUpper(in.Method))
+	}
+	return t.Tool.Execute(ctx, args)
+}
+
+func (t *readOnlyHTTPTool) ReadOnly() bool { return true }
+
+// readOnlyCLITool lets cli_execute run only allow-listed safe commands.
+type readOnlyCLITool struct {
+	Tool
+	safe [][]string
+}
+
+func (t *readOnlyCLITool) Description() string {
+	cmds := make([]string, len(t.safe))
+	for i, s := range t.safe {
+		cmds[i] = strings.Join(s, " ")
+	}
+	return t.Tool.Description() + " The agent is read-only: only commands starting with " +
+		strings.Join(cmds, ", ") + " are allowed."
+}
+
+func (t *readOnlyCLITool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var in struct {
+		Binary string   `json:"binary"`
+		Args   []string `json:"args"`
+	}
+	if err := json.Unmarshal(args, &in); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	words := append([]string{in.Binary}, in.Args...)
+	for _, s := range t.safe {
+		if hasPrefixWords(words, s) {
+			return t.Tool.Execute(ctx, args)
+		}
+	}
+	return "", fmt.Errorf("read-only mode: %q is not one of the safe commands in read_only.safe_commands", strings.Join(words, " "))
+}
+
+func (t *readOnlyCLITool) ReadOnly() bool { return true }
+
+// hasPrefixWords reports whether words starts with prefix.
+func hasPrefixWords(words, prefix []string) bool {
+	if len(words) < len(prefix) {
+		return false
+	}
+	for i, p := range prefix {
+		if words[i] != p {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// stubTool is a minimal Tool for registry tests.
+type stubTool struct {
+	name     string
+	category Category
+	readOnly bool
+	schema   string
+	calls    int
+	exec     func(context.Context, json.RawMessage) (string, error)
+}
+
+func (s *stubTool) Name() string        { return s.name }
+func (s *stubTool) Description() string { return s.name }
+func (s *stubTool) Category() Category  { return s.category }
+func (s *stubTool) InputSchema() json.RawMessage {
+	if s.schema == "" {
+		return json.RawMessage(`{"type":"object"}`)
+	}
+	return json.RawMessage(s.schema)
+}
+func (s *stubTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	s.calls++
+	if s.exec != nil {
+		return s.exec(ctx, args)
+	}
+	return "ok", nil
+}
+func (s *stubTool) ReadOnly() bool { return s.readOnly }
+
+func TestApplyReadOnly(t *testing.T) {
+	reg := NewRegistry()
+	httpTool := &stubTool{name: "http_request", category: CategoryBuiltin,
+		schema: `{"type":"object","properties":{"method":{"type":"string","enum":["GET","POST","PUT","DELETE"]}}}`}
+	cliTool := &stubTool{name: "cli_execute", category: CategoryBuiltin}
+	for _, tool := range []Tool{
+		httpTool, cliTool,
+		&stubTool{name: "file_write", category: CategoryBuiltin},
+		&stubTool{name: "schedule_set", category: CategoryBuiltin},
+		&stubTool{name: "file_read", category: CategoryBuiltin, readOnly: true},
+		&stubTool{name: "github_api", category: CategoryAdapter},
+	} {
+		if err := reg.Register(tool); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed := ApplyReadOnly(reg, []string{"kubectl get", " git  log "})
+	if want := []string{"file_write", "schedule_set"}; !slices.Equal(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	if want := []string{"cli_execute", "file_read", "github_api", "http_request"}; !slices.Equal(reg.List(), want) {
+		t.Errorf("tools = %v, want %v", reg.List(), want)
+	}
+
+	ctx := context.Background()
+	if !strings.Contains(string(reg.Get("http_request").InputSchema()), `"enum":["GET"]`) {
+		t.Errorf("http_request schema = %s", reg.Get("http_request").InputSchema())
+	}
+	if _, err := reg.Execute(ctx, "http_request", json.RawMessage(`{"method":"DELETE","url":"https://x"}`)); err == nil {
+		t.Error("DELETE allowed")
+	}
+	if _, err := reg.Execute(ctx, "http_request", json.RawMessage(`{"method":"get","url":"https://x"}`)); err != nil {
+		t.Errorf("GET: %v", err)
+	}
+
+	for _, tc := range []struct {
+		args string
+		ok   bool
+	}{
+		{`{"binary":"kubectl","args":["get","pods"]}`, true},
+		{`{"binary":"git","args":["log","-n","5"]}`, true},
+		{`{"binary":"kubectl","args":["delete","pod","web"]}`, false},
+		{`{"binary":"kubectl"}`, false},
+	} {
+		_, err := reg.Execute(ctx, "cli_execute", json.RawMessage(tc.args))
+		if (err == nil) != tc.ok {
+			t.Errorf("cli_execute %s: err = %v, want ok=%v", tc.args, err, tc.ok)
+		}
+	}
+	if httpTool.calls != 1 || cliTool.calls != 2 {
+		t.Errorf("underlying calls: http %d, cli %d", httpTool.calls, cliTool.calls)
+	}
+}
+
+func TestApplyReadOnly_NoSafeCommandsRemovesCLI(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(&stubTool{name: "cli_execute", category: CategoryBuiltin}); err != nil {
+		t.Fatal(err)
+	}
+	if removed := ApplyReadOnly(reg, nil); !slices.Equal(removed, []string{"cli_execute"}) {
+		t.Errorf("removed = %v", removed)
+	}
+}
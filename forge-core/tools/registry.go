@@ -8,14 +8,19 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/llm"
 )
 
 // Registry is a thread-safe tool registry. It implements engine.ToolExecutor
 // via Go structural typing -- no direct import of the engine package is needed.
 type Registry struct {
-	mu    sync.RWMutex
-	tools map[string]Tool
+	mu      sync.RWMutex
+	tools   map[string]Tool
+	origins map[string]OriginPolicy // see SetOriginPolicies
+	// sandboxed names the tools confined to the task scratch directory;
+	// see SetSandbox.
+	sandboxed []string
 }
 
 // NewRegistry creates an empty tool registry.
@@ -83,14 +88,33 @@ func (r *Registry) List() []string {
 func (r *Registry) Execute(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
 	r.mu.RLock()
 	t, ok := r.tools[name]
+	originErr := r.checkOrigin(ctx, name)
+	ctx = r.sandboxContext(ctx, name)
 	r.mu.RUnlock()
 
 	if !ok {
 		return "", fmt.Errorf("unknown tool: %q", name)
 	}
+	if originErr != nil {
+		return "", originErr
+	}
 	return t.Execute(ctx, arguments)
 }
 
+// Citations returns the sources a call to the named tool cited, or nil
+// when the tool does not implement Citer.
+func (r *Registry) Citations(name string, arguments json.RawMessage, result string) []a2a.Citation {
+	r.mu.RLock()
+	t, ok := r.tools[name]
+	r.mu.RUnlock()
+
+	c, isCiter := t.(Citer)
+	if !ok || !isCiter {
+		return nil
+	}
+	return c.Citations(arguments, result)
+}
+
 // Filter returns a new Registry containing only tools whose names are in the allowed list.
 // This is useful for Command to restrict which tools are available at runtime.
 func (r *Registry) Filter(allowed []string) *Registry {
@@ -102,6 +126,8 @@ func (r *Registry) Filter(allowed []string) *Registry {
 	filtered := NewRegistry()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	filtered.origins = r.origins
+	filtered.sandboxed = r.sandboxed
 
 	for name, tool := range r.tools {
 		if allowSet[name] {
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathViolation is the error a tool returns when a path it was given
+// resolves, lexically or through a symlink, outside the directory it is
+// confined to. The runtime audits it as a sandbox_violation.
+type PathViolation struct {
+	Path  string // the path as the tool was given it
+	Root  string // the directory it had to stay in
+	Scope string // what Root is, for the message: "working directory", "task sandbox"
+}
+
+func (e *PathViolation) Error() string {
+	scope := e.Scope
+	if scope == "" {
+		scope = "working directory"
+	}
+	return fmt.Sprintf("path %q resolves outside the %s", e.Path, scope)
+}
+
+type taskScratchKey struct{}
+type sandboxRootKey struct{}
+
+// WithTaskScratch records the current task's scratch directory on ctx.
+// The registry confines sandboxed tools (see SetSandbox) to it.
+func WithTaskScratch(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, taskScratchKey{}, dir)
+}
+
+// TaskScratchFromContext returns the task's scratch directory, or "" when
+// the task has none.
+func TaskScratchFromContext(ctx context.Context) string {
+	d, _ := ctx.Value(taskScratchKey{}).(string)
+	return d
+}
+
+// WithSandboxRoot confines the tool executing under ctx to dir: file
+// tools resolve paths inside it and subprocess tools start in it.
+func WithSandboxRoot(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, sandboxRootKey{}, dir)
+}
+
+// SandboxRootFromContext returns the directory the executing tool is
+// confined to, or "" when it runs unsandboxed from the agent WorkDir.
+func SandboxRootFromContext(ctx context.Context) string {
+	d, _ := ctx.Value(sandboxRootKey{}).(string)
+	return d
+}
+
+// SetSandbox names the tools (names or path.Match patterns) that run
+// confined to the task's scratch directory. Tasks without a scratch
+// directory run every tool unconfined.
+func (r *Registry) SetSandbox(patterns []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sandboxed = patterns
+}
+
+// sandboxContext confines ctx to the task scratch directory when name is
+// sandboxed. Callers hold r.mu.
+func (r *Registry) sandboxContext(ctx context.Context, name string) context.Context {
+	if dir := TaskScratchFromContext(ctx); dir != "" && matchAny(r.sandboxed, name) {
+		return WithSandboxRoot(ctx, dir)
+	}
+	return ctx
+}
+
+// ScratchDirName turns a task ID into a directory name. Task IDs are
+// client-supplied, so the name keeps only filename-safe characters and
+// adds a hash of the full ID to keep distinct IDs apart.
+func ScratchDirName(taskID string) string {
+	sum := sha256.Sum256([]byte(taskID))
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, taskID)
+	if len(safe) > 48 {
+		safe = safe[:48]
+	}
+	if safe == "" {
+		safe = "task"
+	}
+	return safe + "-" + hex.EncodeToString(sum[:4])
+}
+
+// ConfinePath resolves p (relative paths against root) and returns it only
+// when it stays inside root, after following any symlinks in the part of
+// the path that exists — a link inside root pointing out of it is a
+// violation, as it would be under chroot. Violations are *PathViolation
+// errors naming scope.
+func ConfinePath(root, p, scope string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	resolved := p
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(absRoot, resolved)
+	}
+	resolved = filepath.Clean(resolved)
+	violation := &PathViolation{Path: p, Root: absRoot, Scope: scope}
+	if !within(resolved, absRoot) {
+		return "", violation
+	}
+
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return resolved, nil // root doesn't exist yet: nothing under it can be a link
+	}
+	if real, err := evalExisting(resolved, 0); err != nil || !within(real, realRoot) {
+		return "", violation
+	}
+	return resolved, nil
+}
+
+// maxLinkHops bounds symlink chains, as the kernel's ELOOP does.
+const maxLinkHops = 40
+
+// evalExisting follows symlinks in the longest existing prefix of p and
+// returns that prefix's real path joined with the rest. A dangling link is
+// followed to its target too: writing through it would create the target.
+func evalExisting(p string, hops int) (string, error) {
+	rest := ""
+	for cur := p; ; {
+		if real, err := filepath.EvalSymlinks(cur); err == nil {
+			return filepath.Join(real, rest), nil
+		}
+		if fi, err := os.Lstat(cur); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(cur)
+			if err != nil || hops >= maxLinkHops {
+				return "", fmt.Errorf("cannot resolve symlink %q", cur)
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(cur), target)
+			}
+			return evalExisting(filepath.Join(target, rest), hops+1)
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return p, nil
+		}
+		rest = filepath.Join(filepath.Base(cur), rest)
+		cur = parent
+	}
+}
+
+// within reports whether p is dir or lies under it.
+func within(p, dir string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfinePath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "missing"), filepath.Join(root, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sub", filepath.Join(root, "inner")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path string
+		ok   bool
+	}{
+		{"notes.txt", true},
+		{"sub/new/file.txt", true},
+		{"inner/file.txt", true},
+		{filepath.Join(root, "sub"), true},
+		{"../x", false},
+		{outside, false},
+		{"escape/secret", false},
+		{"escape", false},
+		{"dangling", false},
+	} {
+		got, err := ConfinePath(root, tc.path, "task sandbox")
+		if tc.ok {
+			if err != nil {
+				t.Errorf("ConfinePath(%q): %v", tc.path, err)
+			} else if !strings.HasPrefix(got, root) {
+				t.Errorf("ConfinePath(%q) = %q, want a path under %s", tc.path, got, root)
+			}
+			continue
+		}
+		var pv *PathViolation
+		if !errors.As(err, &pv) || pv.Path != tc.path || pv.Scope != "task sandbox" {
+			t.Errorf("ConfinePath(%q) = %q, %v; want a PathViolation", tc.path, got, err)
+		}
+	}
+}
+
+func TestScratchDirName(t *testing.T) {
+	a, b := ScratchDirName("../../etc"), ScratchDirName("__/__/etc")
+	if strings.ContainsAny(a, `/\.`) {
+		t.Errorf("ScratchDirName kept path characters: %q", a)
+	}
+	if a == b {
+		t.Errorf("distinct task IDs share a scratch dir name %q", a)
+	}
+	if ScratchDirName("task-1") != ScratchDirName("task-1") {
+		t.Error("ScratchDirName is not stable")
+	}
+}
+
+func TestRegistrySandbox(t *testing.T) {
+	var roots = map[string]string{}
+	reg := NewRegistry()
+	for _, name := range []string{"file_write", "web_search"} {
+		name := name
+		_ = reg.Register(&stubTool{name: name, category: CategoryBuiltin, exec: func(ctx context.Context, _ json.RawMessage) (string, error) {
+			roots[name] = SandboxRootFromContext(ctx)
+			return "", nil
+		}})
+	}
+	reg.SetSandbox([]string{"file_*"})
+
+	ctx := WithTaskScratch(context.Background(), "/scratch/task-1")
+	for _, name := range []string{"file_write", "web_search"} {
+		if _, err := reg.Execute(ctx, name, json.RawMessage(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if roots["file_write"] != "/scratch/task-1" || roots["web_search"] != "" {
+		t.Errorf("sandbox roots = %v, want only file_write confined", roots)
+	}
+
+	// Without a task scratch directory nothing is confined.
+	_, _ = reg.Execute(context.Background(), "file_write", json.RawMessage(`{}`))
+	if roots["file_write"] != "" {
+		t.Errorf("file_write confined to %q without a scratch directory", roots["file_write"])
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/llm"
 )
 
@@ -48,6 +49,49 @@ type MCPSource interface {
 	MCPSource() // marker — body is empty
 }
 
+// ReadOnly is an optional interface a tool implements to declare that
+// Execute has no side effects outside the conversation — it reads
+// files, fetches URLs, or computes values, but never creates, edits,
+// or deletes anything. Conversation undo (tasks/undo) uses it to decide
+// which tool calls from a reverted exchange must be marked "disavowed"
+// in the audit trail: every call to a tool that does NOT report
+// ReadOnly() == true is treated as side-effecting.
+type ReadOnly interface {
+	Tool
+	ReadOnly() bool
+}
+
+// IsReadOnly reports whether t declares itself side-effect free.
+// Tools that do not implement ReadOnly are conservatively assumed to
+// have side effects.
+func IsReadOnly(t Tool) bool {
+	ro, ok := t.(ReadOnly)
+	return ok && ro.ReadOnly()
+}
+
+// Compensator is an optional interface for side-effecting tools that
+// know how to reverse one of their own calls — e.g. deleting the
+// schedule a schedule_set call just created. Undo invokes Compensate
+// with the original arguments and result when the caller opts in.
+// It returns a short human-readable description of what was reverted;
+// an empty string with a nil error means there was nothing to reverse
+// (for example the call updated an existing resource rather than
+// creating one).
+type Compensator interface {
+	Tool
+	Compensate(ctx context.Context, args json.RawMessage, result string) (string, error)
+}
+
+// Citer is an optional interface for retrieval tools whose results name
+// their sources — search hits, fetched pages, knowledge base chunks. The
+// executor calls Citations with a successful call's arguments and raw
+// result, numbers the sources, and lets the model cite them by number.
+// Citation IDs are left zero; the executor assigns them.
+type Citer interface {
+	Tool
+	Citations(args json.RawMessage, result string) []a2a.Citation
+}
+
 // ToLLMDefinition converts a Tool to an llm.ToolDefinition for use with LLM APIs.
 func ToLLMDefinition(t Tool) llm.ToolDefinition {
 	return llm.ToolDefinition{
@@ -4,7 +4,9 @@ package types
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/initializ/forge/forge-core/credentials"
@@ -13,28 +15,94 @@ import (
 
 // ForgeConfig represents the top-level forge.yaml configuration.
 type ForgeConfig struct {
-	AgentID        string              `yaml:"agent_id"`
-	Version        string              `yaml:"version"`
-	Framework      string              `yaml:"framework"`
-	Entrypoint     string              `yaml:"entrypoint"`
-	Model          ModelRef            `yaml:"model,omitempty"`
-	Tools          []ToolRef           `yaml:"tools,omitempty"`
-	BuiltinTools   []string            `yaml:"builtin_tools,omitempty"`
-	Channels       []string            `yaml:"channels,omitempty"`
-	Registry       string              `yaml:"registry,omitempty"`
-	Egress         EgressRef           `yaml:"egress,omitempty"`
-	Skills         SkillsRef           `yaml:"skills,omitempty"`
-	Memory         MemoryConfig        `yaml:"memory,omitempty"`
-	Compression    CompressionConfig   `yaml:"compression,omitempty"`
-	Secrets        SecretsConfig       `yaml:"secrets,omitempty"`
-	Auth           AuthConfig          `yaml:"auth,omitempty"`
-	MCP            MCPConfig           `yaml:"mcp,omitempty"`
-	Platform       *PlatformConfig     `yaml:"platform,omitempty"`
-	Schedules      []ScheduleConfig    `yaml:"schedules,omitempty"`
-	Scheduler      SchedulerConfig     `yaml:"scheduler,omitempty"`
-	CORSOrigins    []string            `yaml:"cors_origins,omitempty"`
-	Package        PackageConfig       `yaml:"package,omitempty"`
-	GuardrailsPath string              `yaml:"guardrails_path,omitempty"` // path to guardrails.json (default: "guardrails.json")
+	// SchemaVersion is the forge.yaml layout version the file was
+	// written for. Zero (absent) means the file predates versioning;
+	// see MigrateForgeConfig.
+	SchemaVersion     int                     `yaml:"schema_version,omitempty"`
+	AgentID           string                  `yaml:"agent_id"`
+	Version           string                  `yaml:"version"`
+	Framework         string                  `yaml:"framework"`
+	Entrypoint        string                  `yaml:"entrypoint"`
+	Model             ModelRef                `yaml:"model,omitempty"`
+	Tools             []ToolRef               `yaml:"tools,omitempty"`
+	BuiltinTools      []string                `yaml:"builtin_tools,omitempty"`
+	Channels          []string                `yaml:"channels,omitempty"`
+	ChannelMiddleware ChannelMiddlewareConfig `yaml:"channel_middleware,omitempty"`
+	Voice             VoiceConfig             `yaml:"voice,omitempty"`
+	Attachments       AttachmentsConfig       `yaml:"attachments,omitempty"`
+	Localization      LocalizationConfig      `yaml:"localization,omitempty"`
+	Notify            NotifyConfig            `yaml:"notify,omitempty"`
+	Handoff           HandoffConfig           `yaml:"handoff,omitempty"`
+	Alerts            AlertsConfig            `yaml:"alerts,omitempty"`
+	Registry          string                  `yaml:"registry,omitempty"`
+	Egress            EgressRef               `yaml:"egress,omitempty"`
+	Skills            SkillsRef               `yaml:"skills,omitempty"`
+	Memory            MemoryConfig            `yaml:"memory,omitempty"`
+	KB                KBConfig                `yaml:"kb,omitempty"`
+	Compression       CompressionConfig       `yaml:"compression,omitempty"`
+	Secrets           SecretsConfig           `yaml:"secrets,omitempty"`
+	Auth              AuthConfig              `yaml:"auth,omitempty"`
+	MCP               MCPConfig               `yaml:"mcp,omitempty"`
+	Platform          *PlatformConfig         `yaml:"platform,omitempty"`
+	Schedules         []ScheduleConfig        `yaml:"schedules,omitempty"`
+	Triggers          []TriggerConfig         `yaml:"triggers,omitempty"`
+	Scheduler         SchedulerConfig         `yaml:"scheduler,omitempty"`
+	Cluster           ClusterConfig           `yaml:"cluster,omitempty"`
+	CORSOrigins       []string                `yaml:"cors_origins,omitempty"`
+	Package           PackageConfig           `yaml:"package,omitempty"`
+	GuardrailsPath    string                  `yaml:"guardrails_path,omitempty"` // path to guardrails.json (default: "guardrails.json")
+	// EnforceGuardrails sets whether guardrail violations block (true)
+	// or are only logged (false) when `forge run` is not given
+	// --enforce-guardrails / --no-guardrails. Nil keeps the flag default
+	// (enforce). Mostly useful per profile.
+	EnforceGuardrails *bool `yaml:"enforce_guardrails,omitempty"`
+	// Mode is the agent's enforcement profile. ModeReadOnly strips the
+	// agent's builtin tools down to ones without side effects; see
+	// ReadOnlyConfig. Empty means no restriction.
+	Mode     string         `yaml:"mode,omitempty"`
+	ReadOnly ReadOnlyConfig `yaml:"read_only,omitempty"`
+	// ToolPolicies narrows the tools a task may call by where it came
+	// from, keyed by origin: channel, rest, a2a, schedule or ui.
+	ToolPolicies map[string]ToolPolicy `yaml:"tool_policies,omitempty"`
+	// Sandbox confines chosen tools to a per-task scratch directory.
+	Sandbox SandboxConfig `yaml:"sandbox,omitempty"`
+	// Retention bounds the artifacts the agent keeps under .forge/.
+	Retention RetentionConfig `yaml:"retention,omitempty"`
+	// Loop bounds the agent loop of each task.
+	Loop LoopConfig `yaml:"loop,omitempty"`
+	// Reflection reviews final answers before they are returned.
+	Reflection ReflectionConfig `yaml:"reflection,omitempty"`
+	// Executor selects how tasks are run: directly, or planned first.
+	Executor ExecutorConfig `yaml:"executor,omitempty"`
+	// RuntimeContext tells the model the current time, the agent and
+	// its channels, and operator facts, in every task's system prompt.
+	RuntimeContext RuntimeContextConfig `yaml:"runtime_context,omitempty"`
+	// Experiments split tasks between prompt and model variants and
+	// record how each variant does, for `forge experiments report`.
+	Experiments []ExperimentConfig `yaml:"experiments,omitempty"`
+	// Workflows declares deterministic step pipelines, run by schedules,
+	// webhook triggers or the run_workflow tool.
+	Workflows []WorkflowConfig `yaml:"workflows,omitempty"`
+	// Tenants partitions one deployment between customers: each tenant's
+	// API keys authenticate its callers, and their sessions, memory,
+	// files, schedules, rate limits and usage are kept apart.
+	Tenants []TenantConfig `yaml:"tenants,omitempty"`
+	// Profiles holds named environment overlays (dev, staging, prod)
+	// selected with `forge run --profile` or FORGE_PROFILE; see
+	// ApplyProfile.
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty"`
+	// ActiveProfile is the profile ApplyProfile merged in; empty when
+	// none was selected.
+	ActiveProfile string `yaml:"-"`
+	// Extends names an organization base config — a local path or an
+	// https:// URL — whose settings this file inherits and may only
+	// tighten; see Inherit. A URL must be pinned by ExtendsSHA256, the
+	// hex SHA-256 of the base file.
+	Extends       string `yaml:"extends,omitempty"`
+	ExtendsSHA256 string `yaml:"extends_sha256,omitempty"`
+	// BaseGuardrails is the base config's guardrails overlay, merged
+	// most-restrictive onto the agent's guardrails.json at startup.
+	BaseGuardrails map[string]any      `yaml:"-"`
 	Server         ServerConfig        `yaml:"server,omitempty"`
 	Observability  ObservabilityConfig `yaml:"observability,omitempty"`
 	Security       SecurityConfig      `yaml:"security,omitempty"`
@@ -113,6 +181,18 @@ type WorkflowPropagationConfig struct {
 // not per-agent configuration.
 type AuditConfig struct {
 	Capture AuditCaptureConfig `yaml:"capture,omitempty"`
+	Export  AuditExportYAML    `yaml:"export,omitempty"`
+}
+
+// AuditExportYAML is the forge.yaml form of the audit export sinks.
+// The --audit-socket / --audit-http-endpoint flags and the
+// FORGE_AUDIT_SOCKET / FORGE_AUDIT_HTTP_ENDPOINT env vars take
+// precedence; the block is mostly set by an organization base config
+// (see ForgeConfig.Extends) so every agent ships its audit trail to the
+// same sink.
+type AuditExportYAML struct {
+	Socket       string `yaml:"socket,omitempty"`
+	HTTPEndpoint string `yaml:"http_endpoint,omitempty"`
 }
 
 // AuditCaptureConfig is the forge.yaml-facing payload-capture
@@ -204,6 +284,19 @@ type SecurityConfig struct {
 	// Opt-in; requires a decision to arrive at
 	// `POST /tasks/{id}/decisions` before the tool call proceeds.
 	Defer DeferConfig `yaml:"defer,omitempty"`
+
+	// OPA sends tool calls, egress attempts and schedule creation to
+	// an Open Policy Agent for an allow/deny decision on top of the
+	// built-in checks. Off unless url or policy_path is set.
+	OPA OPAConfig `yaml:"opa,omitempty"`
+
+	// BuildVerification is what `forge run` does when the build output
+	// fails verification against checksums.json, the SBOM and the
+	// provenance attestation: "warn" (default) logs and starts anyway;
+	// "enforce" refuses to start, and also requires all three to be
+	// present and checksums.json to be signed by a trusted key.
+	// FORGE_BUILD_VERIFICATION overrides it.
+	BuildVerification string `yaml:"build_verification,omitempty"`
 }
 
 // IntentDriftConfig is the forge.yaml-facing block for R7 drift
@@ -392,6 +485,67 @@ func (c DeferConfig) Validate() error {
 	return nil
 }
 
+// OPA decision points, as listed in OPAConfig.Points.
+const (
+	OPAPointTool     = "tool"
+	OPAPointEgress   = "egress"
+	OPAPointSchedule = "schedule"
+)
+
+// OPAConfig configures the policy-as-code decision point. The runtime
+// POSTs an input document describing the action to OPA's Data API at
+// Decision and proceeds only when the result allows it. Exactly one of
+// URL (an external OPA server; FORGE_OPA_URL overrides it) or
+// PolicyPath (Rego shipped with the agent, served by a local
+// `opa run --server`) selects where policies live.
+type OPAConfig struct {
+	// URL is the base URL of an external OPA server, e.g.
+	// http://opa.policy.svc:8181.
+	URL string `yaml:"url,omitempty"`
+
+	// PolicyPath is a .rego file or a directory of them, relative to
+	// forge.yaml. Requires the opa binary on PATH.
+	PolicyPath string `yaml:"policy_path,omitempty"`
+
+	// Decision is the Data API path of the rule to evaluate. Empty →
+	// "forge/decision" (data.forge.decision).
+	Decision string `yaml:"decision,omitempty"`
+
+	// Timeout bounds each evaluation. Zero → 2 seconds.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// FailOpen allows the action when OPA is unreachable or errors.
+	// Default false: evaluation failures deny.
+	FailOpen bool `yaml:"fail_open,omitempty"`
+
+	// Points selects which actions are sent to OPA: "tool", "egress",
+	// "schedule". Empty → all three.
+	Points []string `yaml:"points,omitempty"`
+}
+
+// Enabled reports whether a policy source is configured.
+func (c OPAConfig) Enabled() bool {
+	return c.URL != "" || c.PolicyPath != ""
+}
+
+// Validate rejects configs that would misroute decisions at runtime.
+func (c OPAConfig) Validate() error {
+	if c.URL != "" && c.PolicyPath != "" {
+		return fmt.Errorf("security.opa: set url or policy_path, not both")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("security.opa: timeout must not be negative")
+	}
+	for _, p := range c.Points {
+		switch p {
+		case OPAPointTool, OPAPointEgress, OPAPointSchedule:
+		default:
+			return fmt.Errorf("security.opa: unknown point %q (want tool, egress or schedule)", p)
+		}
+	}
+	return nil
+}
+
 // DeferToolConfig configures deferral for one tool.
 type DeferToolConfig struct {
 	// To identifies the decision target (channel, human, external
@@ -417,12 +571,54 @@ type DeferToolConfig struct {
 	Approvers []string `yaml:"approvers,omitempty"`
 }
 
-// ObservabilityConfig groups telemetry-related sub-blocks. Today it
-// carries only `tracing:` (OTel Tracing v1, issue #108). Future
-// metrics / logs configuration belongs here too so operators have a
-// single observability stanza in forge.yaml.
+// ObservabilityConfig groups telemetry-related sub-blocks: `tracing:`
+// (OTel Tracing v1, issue #108) and the LLM observability platforms
+// task traces are exported to. Future metrics / logs configuration
+// belongs here too so operators have a single observability stanza in
+// forge.yaml.
 type ObservabilityConfig struct {
-	Tracing TracingYAML `yaml:"tracing,omitempty"`
+	Tracing   TracingYAML         `yaml:"tracing,omitempty"`
+	Langfuse  LLMTraceExportYAML  `yaml:"langfuse,omitempty"`
+	LangSmith LLMTraceExportYAML  `yaml:"langsmith,omitempty"`
+	Captures  ProviderCaptureYAML `yaml:"captures,omitempty"`
+}
+
+// ProviderCaptureYAML configures capturing the full requests sent to
+// LLM providers and their responses, for debugging model behavior.
+// Captures are written to .forge/captures, one file per task, with
+// secrets and PII redacted; `forge logs --captures` reads them.
+type ProviderCaptureYAML struct {
+	// Enabled turns capture on. Default false.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// SamplePercent is the share of tasks captured, 0–100. A sampled
+	// task has every provider call captured. Default 100.
+	SamplePercent float64 `yaml:"sample_percent,omitempty"`
+	// MaxBytes caps each captured message or tool-call argument.
+	// Default 64 KiB.
+	MaxBytes int `yaml:"max_bytes,omitempty"`
+}
+
+// LLMTraceExportYAML configures exporting task traces — LLM calls, tool
+// calls, token usage and the final output — to Langfuse or LangSmith.
+// Each invocation becomes one trace, identified by its correlation ID;
+// the task ID groups a conversation's traces. API keys come from the
+// environment (LANGFUSE_PUBLIC_KEY + LANGFUSE_SECRET_KEY,
+// LANGSMITH_API_KEY), never from forge.yaml.
+type LLMTraceExportYAML struct {
+	// Enabled turns the exporter on. Default false.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Endpoint is the platform's API base URL, for self-hosted
+	// deployments. Default: LANGFUSE_HOST / LANGSMITH_ENDPOINT, then
+	// the hosted service.
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// Project is the LangSmith project runs are filed under. Default:
+	// LANGSMITH_PROJECT, then agent_id. Ignored by Langfuse.
+	Project string `yaml:"project,omitempty"`
+	// CaptureContent sends prompts, completions, tool input and output
+	// and the task's final output, with secrets redacted. Default
+	// false: names, timings, models and token counts only, matching
+	// the audit and tracing posture.
+	CaptureContent bool `yaml:"capture_content,omitempty"`
 }
 
 // TracingYAML is the yaml-facing tracing configuration. It maps onto
@@ -490,11 +686,12 @@ type TracingYAML struct {
 }
 
 // ServerConfig groups A2A-server-side knobs that don't fit elsewhere
-// in the schema. Today it carries only the rate-limit sub-block; new
-// server-level concerns (TLS, request-size limits) belong here.
+// in the schema: the rate-limit and request-limit sub-blocks, and the
+// public URL. New server-level concerns (TLS) belong here.
 // See issue #110 / FWS-10.
 type ServerConfig struct {
-	RateLimit RateLimitYAML `yaml:"rate_limit,omitempty"`
+	RateLimit RateLimitYAML     `yaml:"rate_limit,omitempty"`
+	Limits    RequestLimitsYAML `yaml:"limits,omitempty"`
 
 	// PublicURL is the agent's externally-reachable base URL (scheme +
 	// host, no trailing slash), e.g. "https://agent.example.com". It is
@@ -521,6 +718,606 @@ type RateLimitYAML struct {
 	CancelExempt *bool   `yaml:"cancel_exempt,omitempty"` // pointer so "explicitly false" can override the true default
 }
 
+// RequestLimitsYAML mirrors the runtime server.RequestLimits for the
+// same reason RateLimitYAML exists. Zero values keep the runtime
+// default; Endpoints entries overlay the built-in per-route caps.
+type RequestLimitsYAML struct {
+	MaxBodyBytes       int64            `yaml:"max_body_bytes,omitempty"`
+	Endpoints          map[string]int64 `yaml:"endpoints,omitempty"` // route pattern ("POST /tasks/send"; "POST /" is JSON-RPC) → max body bytes
+	MaxMessageParts    int              `yaml:"max_message_parts,omitempty"`
+	MaxHistoryMessages int              `yaml:"max_history_messages,omitempty"`
+	MaxSSEEventBytes   int              `yaml:"max_sse_event_bytes,omitempty"`
+	MaxBatchRequests   int              `yaml:"max_batch_requests,omitempty"`
+	BatchConcurrency   int              `yaml:"batch_concurrency,omitempty"`
+}
+
+// Validate rejects negative limits and malformed endpoint patterns.
+func (l RequestLimitsYAML) Validate() error {
+	if l.MaxBodyBytes < 0 || l.MaxMessageParts < 0 || l.MaxHistoryMessages < 0 || l.MaxSSEEventBytes < 0 ||
+		l.MaxBatchRequests < 0 || l.BatchConcurrency < 0 {
+		return fmt.Errorf("server.limits: limits must not be negative")
+	}
+	for pattern, n := range l.Endpoints {
+		method, path, ok := strings.Cut(pattern, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("server.limits.endpoints: %q is not a route pattern like \"POST /tasks/send\"", pattern)
+		}
+		if n <= 0 {
+			return fmt.Errorf("server.limits.endpoints[%q]: must be positive", pattern)
+		}
+	}
+	return nil
+}
+
+// ChannelMiddlewareConfig is the inbound policy every channel adapter's
+// messages pass before they reach the agent. Zero values keep the
+// defaults: duplicate detection over 10 minutes, a 64 KiB message cap,
+// no rate limit, every sender admitted and conversations idle for 24
+// hours starting a new session.
+type ChannelMiddlewareConfig struct {
+	RateLimit       ChannelRateLimitYAML `yaml:"rate_limit,omitempty"`
+	DedupeWindow    time.Duration        `yaml:"dedupe_window,omitempty"`     // how long an update ID is remembered to drop redeliveries
+	MaxMessageBytes int                  `yaml:"max_message_bytes,omitempty"` // inbound message text cap
+	SessionTTL      time.Duration        `yaml:"session_ttl,omitempty"`       // idle time after which a conversation starts a new agent session
+	// AllowUsers, when set, admits only the senders it lists; DenyUsers
+	// drops the senders it lists. Entries are "<adapter>:<user id>",
+	// "<adapter>:*" for every sender on an adapter, or an email address,
+	// matched against the sender's resolved email.
+	AllowUsers []string `yaml:"allow_users,omitempty"`
+	DenyUsers  []string `yaml:"deny_users,omitempty"`
+}
+
+// ChannelRateLimitYAML caps the messages each sender may send. Zero
+// PerMinute means unlimited; Burst defaults to PerMinute.
+type ChannelRateLimitYAML struct {
+	PerMinute int `yaml:"per_minute,omitempty"`
+	Burst     int `yaml:"burst,omitempty"`
+}
+
+// Validate rejects negative limits and malformed sender entries.
+func (c ChannelMiddlewareConfig) Validate() error {
+	if c.RateLimit.PerMinute < 0 || c.RateLimit.Burst < 0 || c.DedupeWindow < 0 || c.MaxMessageBytes < 0 || c.SessionTTL < 0 {
+		return fmt.Errorf("channel_middleware: limits must not be negative")
+	}
+	for _, list := range []struct {
+		field   string
+		entries []string
+	}{{"allow_users", c.AllowUsers}, {"deny_users", c.DenyUsers}} {
+		for _, e := range list.entries {
+			adapter, user, ok := strings.Cut(e, ":")
+			if !ok && strings.Contains(e, "@") {
+				continue // an email address
+			}
+			if !ok || adapter == "" || user == "" {
+				return fmt.Errorf("channel_middleware.%s: %q must be <adapter>:<user id>, <adapter>:* or an email address", list.field, e)
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultAttachmentMaxBytes is the largest channel attachment passed to
+// the agent by default. Base64 inflates it by a third on the way, so it
+// stays clear of the A2A server's 2 MiB default body cap.
+const DefaultAttachmentMaxBytes = 1 << 20
+
+// AttachmentsConfig lets files and images users share on channel
+// adapters reach the agent. Off by default: attachments are dropped.
+type AttachmentsConfig struct {
+	Enabled  bool  `yaml:"enabled,omitempty"`
+	MaxBytes int64 `yaml:"max_bytes,omitempty"` // per file; default DefaultAttachmentMaxBytes
+	// AllowedTypes lists the MIME types accepted, exactly ("application/pdf")
+	// or by family ("image/*"). Empty accepts any type.
+	AllowedTypes []string `yaml:"allowed_types,omitempty"`
+	// ScanCommand, when set, is run for every attachment with the file on
+	// stdin, e.g. ["clamdscan", "--no-summary", "-"]. Exit status 0 passes
+	// the file; anything else rejects it.
+	ScanCommand []string `yaml:"scan_command,omitempty"`
+}
+
+// Validate rejects a negative size cap and malformed MIME types.
+func (c AttachmentsConfig) Validate() error {
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("attachments.max_bytes must not be negative")
+	}
+	for _, t := range c.AllowedTypes {
+		family, sub, ok := strings.Cut(t, "/")
+		if !ok || family == "" || family == "*" || sub == "" {
+			return fmt.Errorf("attachments.allowed_types: %q must be a MIME type such as application/pdf or image/*", t)
+		}
+	}
+	return nil
+}
+
+// LocalizationConfig sets the language of the messages the agent writes
+// itself rather than its model: channel error replies, guardrail
+// notices, schedule confirmations. Empty keeps them in English.
+type LocalizationConfig struct {
+	// DefaultLanguage is the ISO 639-1 code used when detection is off
+	// or can't tell. Default "en".
+	DefaultLanguage string `yaml:"default_language,omitempty"`
+	// Detect answers each user in the language detected on their message.
+	Detect bool `yaml:"detect,omitempty"`
+	// CatalogDir, relative to forge.yaml, holds <lang>.yaml catalogs that
+	// add languages or override built-in strings.
+	CatalogDir string `yaml:"catalog_dir,omitempty"`
+}
+
+// languageCodeRe matches an ISO 639-1 code with an optional region.
+var languageCodeRe = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+// Validate checks the default language is a language code.
+func (c LocalizationConfig) Validate() error {
+	if c.DefaultLanguage != "" && !languageCodeRe.MatchString(c.DefaultLanguage) {
+		return fmt.Errorf("localization.default_language: %q must be an ISO 639-1 code such as es or pt-BR", c.DefaultLanguage)
+	}
+	return nil
+}
+
+// VoiceConfig turns voice messages on channel adapters into text for
+// the agent, and optionally speaks the agent's reply back. Empty
+// disables voice; voice messages then arrive without text.
+type VoiceConfig struct {
+	STT VoiceSTTConfig `yaml:"stt,omitempty"`
+	TTS VoiceTTSConfig `yaml:"tts,omitempty"`
+}
+
+// VoiceSTTConfig selects the speech-to-text provider voice messages
+// are transcribed with.
+type VoiceSTTConfig struct {
+	// Provider is "openai" (the Whisper API) or "local" (a self-hosted
+	// server speaking the OpenAI audio API, such as speaches or LocalAI).
+	Provider string `yaml:"provider,omitempty"`
+	Model    string `yaml:"model,omitempty"`    // default whisper-1 (openai)
+	BaseURL  string `yaml:"base_url,omitempty"` // overrides the provider's endpoint
+	// APIKeyEnv names the env var to source the API key from. Defaults
+	// to OPENAI_API_KEY for openai; none for local.
+	APIKeyEnv string `yaml:"api_key_env,omitempty"`
+	Language  string `yaml:"language,omitempty"` // ISO-639-1 hint, e.g. "en"; detected when empty
+}
+
+// VoiceTTSConfig selects the text-to-speech provider replies to voice
+// messages are spoken with. Empty replies in text only.
+type VoiceTTSConfig struct {
+	Provider  string `yaml:"provider,omitempty"` // "openai" or "local", as for STT
+	Model     string `yaml:"model,omitempty"`    // default tts-1 (openai)
+	BaseURL   string `yaml:"base_url,omitempty"`
+	APIKeyEnv string `yaml:"api_key_env,omitempty"`
+	Voice     string `yaml:"voice,omitempty"` // provider voice name; default alloy (openai)
+	// MaxChars caps the reply length that is spoken; longer replies are
+	// sent as text only. Default 1000.
+	MaxChars int `yaml:"max_chars,omitempty"`
+}
+
+// Validate rejects unknown providers, and TTS configured without STT
+// (only replies to voice messages are spoken).
+func (c VoiceConfig) Validate() error {
+	for _, p := range []struct{ field, provider string }{{"stt", c.STT.Provider}, {"tts", c.TTS.Provider}} {
+		switch p.provider {
+		case "", "openai", "local":
+		default:
+			return fmt.Errorf("voice.%s.provider: %q must be openai or local", p.field, p.provider)
+		}
+	}
+	if c.TTS.Provider != "" && c.STT.Provider == "" {
+		return fmt.Errorf("voice.tts requires voice.stt: only replies to voice messages are spoken")
+	}
+	if c.TTS.MaxChars < 0 {
+		return fmt.Errorf("voice.tts.max_chars must not be negative")
+	}
+	return nil
+}
+
+// NotifyConfig declares the channel targets the agent may message
+// unprompted, through the notify tool and POST /notify. No targets
+// disables both.
+type NotifyConfig struct {
+	// Targets maps a name the agent and API callers use (e.g. "oncall")
+	// to a channel destination.
+	Targets map[string]NotifyTarget `yaml:"targets,omitempty"`
+	// RateLimit caps the notifications sent to each target. Default 10
+	// per minute, bursts of 10.
+	RateLimit ChannelRateLimitYAML `yaml:"rate_limit,omitempty"`
+}
+
+// NotifyTarget is one destination for proactive messages.
+type NotifyTarget struct {
+	Channel string `yaml:"channel"` // adapter name: slack, telegram, msteams
+	Target  string `yaml:"target"`  // the adapter's chat or channel ID
+	// Template is a Go text/template rendering the message, over
+	// .Title, .Message, .Severity, .Fields, .Target, .Agent and .Time.
+	// Default: the title in bold above the message.
+	Template string `yaml:"template,omitempty"`
+}
+
+// Validate rejects targets without a channel or destination, templates
+// that do not parse, and negative limits.
+func (c NotifyConfig) Validate() error {
+	if c.RateLimit.PerMinute < 0 || c.RateLimit.Burst < 0 {
+		return fmt.Errorf("notify.rate_limit: limits must not be negative")
+	}
+	for name, t := range c.Targets {
+		if name == "" || strings.ContainsAny(name, " \t\n") {
+			return fmt.Errorf("notify.targets: %q is not a valid target name", name)
+		}
+		if t.Channel == "" || t.Target == "" {
+			return fmt.Errorf("notify.targets.%s: channel and target are required", name)
+		}
+		if t.Template != "" {
+			if _, err := template.New(name).Parse(t.Template); err != nil {
+				return fmt.Errorf("notify.targets.%s.template: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AlertsConfig declares thresholds on the agent's usage and health,
+// checked by a background monitor that notifies a notify target or a
+// webhook when one is breached.
+type AlertsConfig struct {
+	// Interval is how often the rules are evaluated. Default 1m.
+	Interval time.Duration `yaml:"interval,omitempty"`
+	Rules    []AlertRule   `yaml:"rules,omitempty"`
+}
+
+// AlertRule fires when Metric rises above Threshold.
+type AlertRule struct {
+	ID        string  `yaml:"id"`
+	Metric    string  `yaml:"metric"` // daily_tokens | daily_cost_usd | task_failure_rate | p95_latency_seconds
+	Threshold float64 `yaml:"threshold"`
+	// Window is the trailing period task_failure_rate and
+	// p95_latency_seconds are computed over. Default 1h. The daily_*
+	// metrics reset at midnight UTC.
+	Window time.Duration `yaml:"window,omitempty"`
+	// MinTasks is how many finished tasks the window needs before the
+	// task metrics are evaluated, so one failure is not a 100% rate.
+	// Default 5.
+	MinTasks int `yaml:"min_tasks,omitempty"`
+	// Notify names a notify.targets entry to message.
+	Notify string `yaml:"notify,omitempty"`
+	// WebhookEnv names the environment variable holding a URL the
+	// alert is POSTed to as JSON.
+	WebhookEnv string `yaml:"webhook_env,omitempty"`
+	// Cooldown is the least time between repeats of a firing alert.
+	// Default 1h.
+	Cooldown time.Duration `yaml:"cooldown,omitempty"`
+}
+
+// Alert metrics.
+const (
+	AlertMetricDailyTokens     = "daily_tokens"
+	AlertMetricDailyCost       = "daily_cost_usd"
+	AlertMetricTaskFailureRate = "task_failure_rate"
+	AlertMetricP95Latency      = "p95_latency_seconds"
+)
+
+// Default alert settings.
+const (
+	DefaultAlertInterval = time.Minute
+	DefaultAlertWindow   = time.Hour
+	DefaultAlertMinTasks = 5
+	DefaultAlertCooldown = time.Hour
+)
+
+// Validate rejects rules without an ID, a known metric or a
+// destination, duplicate IDs, and negative settings. Whether a Notify
+// target exists is checked against notify.targets by the caller.
+func (c AlertsConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("alerts.interval must not be negative")
+	}
+	seen := make(map[string]bool, len(c.Rules))
+	for i, rule := range c.Rules {
+		if rule.ID == "" {
+			return fmt.Errorf("alerts.rules[%d]: id is required", i)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("alerts.rules[%d]: duplicate id %q", i, rule.ID)
+		}
+		seen[rule.ID] = true
+		switch rule.Metric {
+		case AlertMetricDailyTokens, AlertMetricDailyCost, AlertMetricTaskFailureRate, AlertMetricP95Latency:
+		default:
+			return fmt.Errorf("alerts.rules.%s: metric %q must be one of %s, %s, %s, %s", rule.ID, rule.Metric,
+				AlertMetricDailyTokens, AlertMetricDailyCost, AlertMetricTaskFailureRate, AlertMetricP95Latency)
+		}
+		if rule.Threshold <= 0 {
+			return fmt.Errorf("alerts.rules.%s: threshold must be positive", rule.ID)
+		}
+		if rule.Metric == AlertMetricTaskFailureRate && rule.Threshold > 1 {
+			return fmt.Errorf("alerts.rules.%s: task_failure_rate threshold is a fraction between 0 and 1", rule.ID)
+		}
+		if rule.Window < 0 || rule.MinTasks < 0 || rule.Cooldown < 0 {
+			return fmt.Errorf("alerts.rules.%s: window, min_tasks and cooldown must not be negative", rule.ID)
+		}
+		if rule.Notify == "" && rule.WebhookEnv == "" {
+			return fmt.Errorf("alerts.rules.%s: notify or webhook_env is required", rule.ID)
+		}
+	}
+	return nil
+}
+
+// HandoffConfig enables the handoff_to_human tool: the agent hands a
+// channel conversation to a human operator, whose channel then receives
+// the conversation's messages instead of the LLM until someone sends
+// /resume-bot. No operator channel disables the tool.
+type HandoffConfig struct {
+	Channel string `yaml:"channel,omitempty"` // operator channel's adapter: slack, telegram, msteams
+	Target  string `yaml:"target,omitempty"`  // the adapter's chat or channel ID
+}
+
+// Enabled reports whether an operator channel is configured.
+func (c HandoffConfig) Enabled() bool {
+	return c.Channel != "" || c.Target != ""
+}
+
+// Validate requires channel and target together.
+func (c HandoffConfig) Validate() error {
+	if c.Enabled() && (c.Channel == "" || c.Target == "") {
+		return fmt.Errorf("handoff: channel and target are required together")
+	}
+	return nil
+}
+
+// ModeReadOnly is the `mode: read-only` enforcement profile.
+const ModeReadOnly = "read-only"
+
+// ReadOnlyConfig tunes `mode: read-only`. In that mode, builtins that
+// don't declare themselves read-only are removed, http_request may only
+// GET, and cli_execute runs only the SafeCommands. A safe command is the
+// leading words a command line must start with ("kubectl get", "git
+// log"); with none listed, cli_execute is removed too.
+type ReadOnlyConfig struct {
+	SafeCommands []string `yaml:"safe_commands,omitempty"`
+}
+
+// ToolPolicy is one origin's tool_policies entry. Entries are tool
+// names or glob patterns; an empty Allow allows every tool and Deny
+// wins over Allow.
+type ToolPolicy struct {
+	Allow []string `yaml:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty"`
+}
+
+// SandboxConfig gives each task a scratch directory under
+// .forge/scratch/ and confines the listed tools to it: file tools resolve
+// paths inside it, and subprocess tools (cli_execute, custom tools, skill
+// scripts) start in it with HOME and TMPDIR pointing at it. Tools are
+// names or glob patterns; with none listed, there are no scratch
+// directories.
+type SandboxConfig struct {
+	Tools []string `yaml:"tools,omitempty"`
+	// KeepScratch keeps a task's scratch directory after the task ends
+	// instead of removing it.
+	KeepScratch bool `yaml:"keep_scratch,omitempty"`
+}
+
+// RetentionConfig bounds the artifacts the agent accumulates on disk:
+// session files, files tools create (file_create output, cli_execute
+// spill files) and task scratch directories. The running agent collects
+// at startup and on GCSchedule; `forge clean` collects on demand.
+// Long-term memory has its own memory.retention.
+type RetentionConfig struct {
+	GCSchedule string          `yaml:"gc_schedule,omitempty"` // cron; default: @hourly
+	Sessions   RetentionPolicy `yaml:"sessions,omitempty"`
+	Files      RetentionPolicy `yaml:"files,omitempty"`
+	Scratch    RetentionPolicy `yaml:"scratch,omitempty"`
+	Captures   RetentionPolicy `yaml:"captures,omitempty"`
+}
+
+// RetentionPolicy limits one artifact category. Zero takes the category
+// default; a negative value removes the limit.
+type RetentionPolicy struct {
+	MaxAge   time.Duration `yaml:"max_age,omitempty"`   // remove entries not modified for this long
+	MaxBytes int64         `yaml:"max_bytes,omitempty"` // then remove the oldest until the category fits
+}
+
+// Default retention settings.
+const (
+	DefaultRetentionGCSchedule = "@hourly"
+	DefaultSessionMaxAge       = 7 * 24 * time.Hour
+	DefaultFilesMaxAge         = 7 * 24 * time.Hour
+	DefaultFilesMaxBytes       = 1 << 30 // 1 GiB
+	DefaultScratchMaxAge       = 24 * time.Hour
+	DefaultScratchMaxBytes     = 1 << 30 // 1 GiB
+	DefaultCapturesMaxAge      = 3 * 24 * time.Hour
+	DefaultCapturesMaxBytes    = 256 << 20 // 256 MiB
+)
+
+// LoopConfig bounds the agent loop of one task: how many model calls it
+// may make, and when it is stuck. A stuck task calls the same tool with
+// the same arguments RepeatLimit times, or cycles between the same two
+// tool calls and results OscillationLimit times. Zero takes the
+// default; a negative limit turns that check off.
+type LoopConfig struct {
+	MaxIterations    int `yaml:"max_iterations,omitempty"`
+	RepeatLimit      int `yaml:"repeat_limit,omitempty"`
+	OscillationLimit int `yaml:"oscillation_limit,omitempty"`
+}
+
+// Default loop limits.
+const (
+	DefaultLoopMaxIterations    = 100
+	DefaultLoopRepeatLimit      = 5
+	DefaultLoopOscillationLimit = 3
+)
+
+// ReflectionConfig turns on a self-critique pass: before a task's final
+// answer is returned, a reviewer model checks it against the request and
+// the agent's instructions, and the agent revises it once when the review
+// finds problems. Enabled reviews every task; without it only tasks that
+// called a tool of a skill declaring `reflection: true` are reviewed.
+// Model names the reviewer on the primary provider; empty uses the first
+// fallback model, or the primary model when there is none.
+type ReflectionConfig struct {
+	Enabled bool   `yaml:"enabled,omitempty"`
+	Model   string `yaml:"model,omitempty"`
+}
+
+// Executor modes.
+const (
+	ExecutorModeDirect = "direct"
+	ExecutorModePlan   = "plan"
+)
+
+// ExecutorConfig selects the executor mode. In plan mode a planning call
+// breaks each request into at most MaxSteps steps, each scoped to the
+// tools it needs, and the agent works through them in order; a step it
+// cannot complete is re-planned, at most MaxReplans times per task. Zero
+// takes the defaults (8 steps, 2 re-plans); a negative MaxReplans turns
+// re-planning off.
+type ExecutorConfig struct {
+	Mode       string `yaml:"mode,omitempty"`
+	MaxSteps   int    `yaml:"max_steps,omitempty"`
+	MaxReplans int    `yaml:"max_replans,omitempty"`
+}
+
+// RuntimeContextConfig appends a block of runtime facts to the system
+// prompt, rebuilt for every task: the current date and time in Timezone,
+// the agent's ID and version, the channels it runs on, the language of
+// the message, and Facts. Models otherwise guess the date, which breaks
+// anything relative to "today".
+type RuntimeContextConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Timezone is an IANA zone name such as Europe/Berlin. Empty uses
+	// the host's zone.
+	Timezone string `yaml:"timezone,omitempty"`
+	// Facts are operator-defined facts, listed by key.
+	Facts map[string]string `yaml:"facts,omitempty"`
+}
+
+// ExperimentConfig is one forge.yaml experiments entry: an A/B test
+// between variants of the system prompt and model. A task is enrolled
+// in the first experiment whose When matches it and stays in one
+// variant for every turn.
+type ExperimentConfig struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description,omitempty"`
+	// When limits the experiment to tasks whose request metadata
+	// matches; empty enrolls every task.
+	When ExperimentMatch `yaml:"when,omitempty"`
+	// StickyKey is the request metadata key tasks are bucketed by, such
+	// as user_id, so one user always sees one variant. Tasks without it
+	// are bucketed by task ID.
+	StickyKey string              `yaml:"sticky_key,omitempty"`
+	Variants  []ExperimentVariant `yaml:"variants"`
+}
+
+// ExperimentMatch selects tasks by their A2A request metadata.
+type ExperimentMatch struct {
+	// Metadata entries must equal the request metadata's.
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+	// Tags must all be present in the request's "tags" metadata.
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// ExperimentVariant is one arm of an experiment. A variant that sets
+// neither Prompt nor Model is the control: the agent as configured.
+type ExperimentVariant struct {
+	ID string `yaml:"id"`
+	// Weight is the percentage of the experiment's tasks the variant
+	// gets. Weights must add up to 100; all zero splits evenly.
+	Weight int `yaml:"weight,omitempty"`
+	// When assigns tasks whose metadata matches to this variant,
+	// whatever their bucket. A variant with When and no Weight gets
+	// only those tasks.
+	When *ExperimentMatch `yaml:"when,omitempty"`
+	// Prompt names the template under prompts/ that renders the
+	// variant's system prompt in place of system.tmpl.
+	Prompt string `yaml:"prompt,omitempty"`
+	// Model sends the variant's LLM calls to another model.
+	Model *ExperimentModel `yaml:"model,omitempty"`
+}
+
+// ExperimentModel is a variant's model, resolved like a model.routes
+// entry: provider defaults to model.provider.
+type ExperimentModel struct {
+	Provider        string `yaml:"provider,omitempty"`
+	Name            string `yaml:"name"`
+	ReasoningEffort string `yaml:"reasoning_effort,omitempty"`
+}
+
+// WorkflowConfig declares a named pipeline of steps run in order, with
+// no model deciding what happens next. Step arguments, prompts,
+// conditions and Output are Go text/templates over .Inputs (the run's
+// inputs) and .Steps (earlier steps' results by ID: .Output, .JSON — the
+// output decoded as JSON, nil when it is not — and .Status).
+type WorkflowConfig struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description,omitempty"`
+	// Inputs names the inputs a run expects; run_workflow callers are
+	// told about them and a run missing one fails before any step.
+	Inputs []string       `yaml:"inputs,omitempty"`
+	Steps  []WorkflowStep `yaml:"steps"`
+	// Output renders the run's result. Default: the last step's output.
+	Output string `yaml:"output,omitempty"`
+	// Timeout cancels a run after this long; 0 = unbounded.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// WorkflowStep is one step of a workflow. It sets exactly one of Tool,
+// Prompt or Parallel.
+type WorkflowStep struct {
+	ID string `yaml:"id"`
+	// If skips the step unless it renders to something other than "",
+	// "false", "0" or "no".
+	If string `yaml:"if,omitempty"`
+	// Tool calls a registered tool with Args, whose string values are
+	// rendered as templates.
+	Tool string         `yaml:"tool,omitempty"`
+	Args map[string]any `yaml:"args,omitempty"`
+	// Prompt sends the rendered prompt to the model in a single call,
+	// without tools.
+	Prompt string `yaml:"prompt,omitempty"`
+	// Parallel runs its steps concurrently (fan-out); the step's output
+	// is a JSON object of their outputs by ID. Parallel steps cannot
+	// nest.
+	Parallel []WorkflowStep `yaml:"parallel,omitempty"`
+	// OnError is fail (default: the run stops) or continue.
+	OnError string `yaml:"on_error,omitempty"`
+}
+
+// Workflow step error policies.
+const (
+	WorkflowOnErrorFail     = "fail"
+	WorkflowOnErrorContinue = "continue"
+)
+
+// Workflow returns the declared workflow with the given ID, or nil.
+func (c *ForgeConfig) Workflow(id string) *WorkflowConfig {
+	for i := range c.Workflows {
+		if c.Workflows[i].ID == id {
+			return &c.Workflows[i]
+		}
+	}
+	return nil
+}
+
+// TenantConfig declares one tenant of a multi-tenant deployment. A
+// caller presenting one of its API keys as a bearer token acts as the
+// tenant.
+type TenantConfig struct {
+	ID string `yaml:"id"` // kebab-case; namespaces the tenant's data
+	// APIKeysEnv names the environment variables holding the tenant's
+	// API keys. Several let a key be rotated without downtime.
+	APIKeysEnv []string `yaml:"api_keys_env"`
+	// RateLimit overrides server.rate_limit for the tenant's requests.
+	RateLimit TenantRateLimit `yaml:"rate_limit,omitempty"`
+}
+
+// TenantRateLimit is a tenant's request budget. Zero values keep the
+// server.rate_limit ones.
+type TenantRateLimit struct {
+	RPS   float64 `yaml:"rps,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
+}
+
+// IsReadOnly reports whether the config selects `mode: read-only`.
+func (c *ForgeConfig) IsReadOnly() bool {
+	return c.Mode == ModeReadOnly
+}
+
 // MCPConfig declares Model Context Protocol servers for the agent.
 //
 // Phase 1 (v0.12.0): HTTP transport only. Stdio servers are on the
@@ -742,10 +1539,60 @@ type AuthProvider struct {
 type ScheduleConfig struct {
 	ID            string `yaml:"id"`
 	Cron          string `yaml:"cron"`
+	Timezone      string `yaml:"timezone,omitempty"` // IANA zone the cron fields are read in (default UTC)
 	Task          string `yaml:"task"`
 	Skill         string `yaml:"skill,omitempty"`
 	Channel       string `yaml:"channel,omitempty"`        // channel adapter name (e.g. "slack", "telegram")
 	ChannelTarget string `yaml:"channel_target,omitempty"` // destination ID (channel ID, chat ID)
+
+	MaxRuntime time.Duration `yaml:"max_runtime,omitempty"` // cancel a run after this long; 0 = unbounded
+	Overlap    string        `yaml:"overlap,omitempty"`     // skip (default) | queue | cancel-previous
+
+	// Workflow runs the named forge.yaml workflow instead of Task. A
+	// schedule sets exactly one of the two.
+	Workflow string `yaml:"workflow,omitempty"`
+}
+
+// TriggerConfig declares an inbound webhook that starts a task: a POST
+// to Path, signed with HMAC-SHA256 over the raw body, runs Task
+// rendered against the payload.
+type TriggerConfig struct {
+	ID string `yaml:"id"`
+	// Path is the route the webhook posts to. It must sit under
+	// /hooks/. Default: /hooks/<id>.
+	Path string `yaml:"path,omitempty"`
+	// SecretEnv names the environment variable holding the shared HMAC
+	// secret. Requests without a valid signature are rejected.
+	SecretEnv string `yaml:"secret_env"`
+	// SignatureHeader carries the hex HMAC-SHA256 of the body, with or
+	// without a "sha256=" prefix. Default: X-Hub-Signature-256 (GitHub).
+	SignatureHeader string `yaml:"signature_header,omitempty"`
+	// Task is a Go text/template rendering the task message, over
+	// .Payload (the decoded JSON body), .Body (the raw body), .Headers
+	// and .Trigger. Default: the trigger ID above the raw body.
+	Task          string `yaml:"task,omitempty"`
+	Skill         string `yaml:"skill,omitempty"`
+	Channel       string `yaml:"channel,omitempty"`        // channel adapter the result is delivered to
+	ChannelTarget string `yaml:"channel_target,omitempty"` // destination ID (channel ID, chat ID)
+
+	// Workflow runs the named forge.yaml workflow instead of a task. Its
+	// inputs are the request's payload, body, headers and trigger ID;
+	// Task is not used.
+	Workflow string `yaml:"workflow,omitempty"`
+}
+
+// Default webhook trigger settings.
+const (
+	TriggerPathPrefix             = "/hooks/"
+	DefaultTriggerSignatureHeader = "X-Hub-Signature-256"
+)
+
+// Route returns the trigger's webhook path, defaulted from its ID.
+func (t TriggerConfig) Route() string {
+	if t.Path != "" {
+		return t.Path
+	}
+	return TriggerPathPrefix + t.ID
 }
 
 // SchedulerConfig selects the scheduler backend and tunes its
@@ -799,10 +1646,40 @@ type K8sSchedulerConfig struct {
 	AuthSecretName string `yaml:"auth_secret_name,omitempty"`
 }
 
+// ClusterConfig coordinates the replicas of a multi-replica deploy
+// (several `forge serve` processes running the same agent). Unset, each
+// replica runs alone: every one fires every file-backend schedule and a
+// task's session can be written by two replicas at once.
+type ClusterConfig struct {
+	// LockURL is the shared lock backend. With it, one replica is elected
+	// to run the file-backend scheduler and a task runs on one replica at
+	// a time. "redis://[:password@]host:port[/db]", "rediss://…" for TLS,
+	// or "memory://" (one process; tests only). Env override:
+	// FORGE_CLUSTER_LOCK_URL.
+	LockURL string `yaml:"lock_url,omitempty"`
+
+	// LockTTL is how long a lock outlives the replica holding it, and so
+	// how long failover takes after a crash (Go duration, e.g. "15s").
+	LockTTL string `yaml:"lock_ttl,omitempty"` // default: 15s
+}
+
 // SecretsConfig configures secret management providers.
 type SecretsConfig struct {
 	Providers []string `yaml:"providers,omitempty"` // e.g. ["env"], ["encrypted-file","env"]
 	Path      string   `yaml:"path,omitempty"`      // encrypted file path, default ~/.forge/secrets.enc
+	// Recipients are public keys ("age1…" or SSH authorized_keys lines)
+	// new and rotated encrypted files are wrapped for, so team members
+	// open them with their own key instead of a shared passphrase.
+	Recipients []string `yaml:"recipients,omitempty"`
+	// Sync is the external secret manager `forge secret sync` pulls
+	// keys from into the local encrypted store.
+	Sync *SecretsSyncConfig `yaml:"sync,omitempty"`
+}
+
+// SecretsSyncConfig configures `forge secret sync`.
+type SecretsSyncConfig struct {
+	From string   `yaml:"from"`           // vault://<mount>/<path>, aws://<secret-id>, gcp://<project>/<secret>
+	Keys []string `yaml:"keys,omitempty"` // "KEY", or "KEY=field" to pull a differently named field
 }
 
 // MemoryConfig configures agent memory persistence and compaction.
@@ -812,6 +1689,10 @@ type MemoryConfig struct {
 	SessionMaxAge string  `yaml:"session_max_age,omitempty"` // e.g. "30m", "1h" (default: 30m)
 	TriggerRatio  float64 `yaml:"trigger_ratio,omitempty"`
 	CharBudget    int     `yaml:"char_budget,omitempty"`
+	// HighWaterMark is the context utilization (fraction of char_budget)
+	// above which the largest tool results are summarized by the first
+	// fallback model, or the primary without one. 0 (default) = off.
+	HighWaterMark float64 `yaml:"high_water_mark,omitempty"`
 
 	// SessionStore selects the session-memory backend (issue #243):
 	//   "file"   (default) — local .forge/sessions/*.json; single-pod / dev.
@@ -825,13 +1706,50 @@ type MemoryConfig struct {
 	SessionStoreURL string `yaml:"session_store_url,omitempty"`
 
 	// Long-term memory (persistent cross-session knowledge).
-	LongTerm          *bool   `yaml:"long_term,omitempty"`            // default: false
-	MemoryDir         string  `yaml:"memory_dir,omitempty"`           // default: .forge/memory
-	EmbeddingProvider string  `yaml:"embedding_provider,omitempty"`   // auto-detect from LLM
-	EmbeddingModel    string  `yaml:"embedding_model,omitempty"`      // provider default
-	VectorWeight      float64 `yaml:"vector_weight,omitempty"`        // default: 0.7
-	KeywordWeight     float64 `yaml:"keyword_weight,omitempty"`       // default: 0.3
-	DecayHalfLifeDays int     `yaml:"decay_half_life_days,omitempty"` // default: 7
+	LongTerm          *bool         `yaml:"long_term,omitempty"`            // default: false
+	MemoryDir         string        `yaml:"memory_dir,omitempty"`           // default: .forge/memory
+	EmbeddingProvider string        `yaml:"embedding_provider,omitempty"`   // auto-detect from LLM
+	EmbeddingModel    string        `yaml:"embedding_model,omitempty"`      // provider default
+	EmbeddingBaseURL  string        `yaml:"embedding_base_url,omitempty"`   // provider default; local: http://localhost:8080/v1
+	EmbeddingDims     int           `yaml:"embedding_dims,omitempty"`       // provider default
+	RerankProvider    string        `yaml:"rerank_provider,omitempty"`      // local, cohere, voyage, llm; default: no reranking
+	RerankModel       string        `yaml:"rerank_model,omitempty"`         // provider default
+	RerankBaseURL     string        `yaml:"rerank_base_url,omitempty"`      // full rerank endpoint; local: http://localhost:8080/v1/rerank
+	RerankBudget      time.Duration `yaml:"rerank_budget,omitempty"`        // reranker time limit; default: 3s
+	QueryRewrite      bool          `yaml:"query_rewrite,omitempty"`        // HyDE query rewrite before vector search; default: false
+	RewriteBudget     time.Duration `yaml:"rewrite_budget,omitempty"`       // query rewrite time limit; default: 2s
+	VectorWeight      float64       `yaml:"vector_weight,omitempty"`        // default: 0.7
+	KeywordWeight     float64       `yaml:"keyword_weight,omitempty"`       // default: 0.3
+	DecayHalfLifeDays int           `yaml:"decay_half_life_days,omitempty"` // default: 7
+
+	// WriteQuota caps the live notes memory_write keeps per namespace
+	// (default: 100); WriteQuotas overrides it for named namespaces.
+	WriteQuota  int            `yaml:"write_quota,omitempty"`
+	WriteQuotas map[string]int `yaml:"write_quotas,omitempty"`
+
+	// Retention decides which long-term memories `forge memory gc` and
+	// scheduled GC archive.
+	Retention MemoryRetentionConfig `yaml:"retention,omitempty"`
+}
+
+// MemoryRetentionConfig configures long-term memory GC. A memory's
+// retention score halves every half_life_days × 2 × importance of age
+// (notes carry their importance, daily logs count as 0.5); memories
+// scoring under archive_below are moved to the compressed archive.
+type MemoryRetentionConfig struct {
+	HalfLifeDays int     `yaml:"half_life_days,omitempty"` // default: 30
+	ArchiveBelow float64 `yaml:"archive_below,omitempty"`  // default: 0.1
+	MaxNotes     int     `yaml:"max_notes,omitempty"`      // per namespace; default: no cap
+	GCSchedule   string  `yaml:"gc_schedule,omitempty"`    // cron, e.g. "@daily"; default: off
+}
+
+// KBConfig configures the knowledge base: local documents (markdown,
+// text, HTML, PDF) synced into a read-only partition of long-term memory
+// and searched with memory_search, which cites them. Setting paths turns
+// long-term memory on; the agent re-syncs them at startup.
+type KBConfig struct {
+	Paths   []string `yaml:"paths,omitempty"`   // files or directories, relative to the agent directory
+	Exclude []string `yaml:"exclude,omitempty"` // globs matched against each document's relative path
 }
 
 // CompressionConfig configures reversible context compression (ctxzip).
@@ -884,6 +1802,53 @@ type EgressRef struct {
 	// governed by AllowedDomains — this list is only consulted for the
 	// raw-TCP path. When empty, no SOCKS5 listener is bound.
 	AllowedTCP []string `yaml:"allowed_tcp,omitempty"`
+	// AllowedSchemes lists the URL schemes HTTP egress may use. Empty
+	// means https only; add "http" to permit plaintext. Localhost and
+	// dev-open mode are exempt.
+	AllowedSchemes []string `yaml:"allowed_schemes,omitempty"`
+	// MaxResponseBytes caps the body of every egress response (and the
+	// bytes relayed back through a CONNECT tunnel). Zero means no cap.
+	MaxResponseBytes int64 `yaml:"max_response_bytes,omitempty"`
+	// Rules narrow what an allowed domain may be used for. A rule never
+	// allows a domain on its own; AllowedDomains still decides that.
+	Rules []EgressRule `yaml:"rules,omitempty"`
+	// Proxy tunes the subprocess egress proxy.
+	Proxy *EgressProxyRef `yaml:"proxy,omitempty"`
+}
+
+// EgressProxyRef tunes the subprocess egress proxy.
+type EgressProxyRef struct {
+	// SNI filters CONNECT tunnels by the server name in their TLS
+	// ClientHello: "allowlist" (default) blocks names the allowlist does
+	// not allow, "require" also blocks tunnels without one, "off" skips
+	// the check.
+	SNI string `yaml:"sni,omitempty"`
+	// TaskMaxBytes caps the bytes one task may send and receive through
+	// the proxy. Zero means no cap.
+	TaskMaxBytes int64 `yaml:"task_max_bytes,omitempty"`
+	// TaskMaxRequests caps the requests and tunnels one task may open
+	// through the proxy. Zero means no cap.
+	TaskMaxRequests int64 `yaml:"task_max_requests,omitempty"`
+	// Socket is an absolute path at which the proxy also listens on a
+	// Unix domain socket, for sidecars that share a volume rather than
+	// the agent's network namespace. Empty keeps it loopback-only.
+	Socket string `yaml:"socket,omitempty"`
+}
+
+// EgressRule constrains egress to one domain. Domain takes the same
+// exact or `*.suffix` forms as AllowedDomains; when several rules match
+// a host, the exact one wins, then the longest suffix.
+type EgressRule struct {
+	Domain string `yaml:"domain"`
+	// Ports the domain may be reached on. Empty allows any port.
+	Ports []int `yaml:"ports,omitempty"`
+	// Methods lists the HTTP methods allowed, case-insensitively. Empty
+	// allows any. Not enforced inside CONNECT tunnels, where the proxy
+	// cannot see the request.
+	Methods []string `yaml:"methods,omitempty"`
+	// MaxResponseBytes overrides egress.max_response_bytes for the
+	// domain.
+	MaxResponseBytes int64 `yaml:"max_response_bytes,omitempty"`
 }
 
 // SkillsRef references a skills definition file.
@@ -950,9 +1915,149 @@ type ModelRef struct {
 	// "x-gateway-key". Ignored for every other AuthScheme.
 	AuthHeaderName string `yaml:"auth_header_name,omitempty"`
 
+	// ReasoningEffort ("minimal", "low", "medium", "high") asks a
+	// reasoning model to think less or more before answering. Sent only
+	// to providers that accept the value (openai, gemini, xai); dropped
+	// elsewhere, and `forge validate` warns about the mismatch. Empty
+	// leaves the provider default.
+	ReasoningEffort string `yaml:"reasoning_effort,omitempty"`
+
+	// ThinkingBudget turns on extended thinking for an anthropic
+	// primary model: the most tokens it may think for per call, at
+	// least 1024. Thinking blocks are kept with the conversation and
+	// sent back with tool results, as the API requires. 0 (default)
+	// leaves thinking off. Routes and fallbacks do not inherit it.
+	ThinkingBudget int `yaml:"thinking_budget,omitempty"`
+
+	// KeepAlive is how long Ollama keeps the model loaded after each
+	// call: a duration ("30m") or seconds ("-1" = indefinitely, "0" =
+	// unload immediately). Applies to every ollama client in the chain,
+	// primary or fallback; OLLAMA_KEEP_ALIVE overrides it. Empty leaves
+	// the daemon default (5m).
+	KeepAlive string `yaml:"keep_alive,omitempty"`
+
+	// Gemini holds options of the native Gemini API, applied to every
+	// gemini client in the chain: primary, fallbacks and routes.
+	Gemini *GeminiOptions `yaml:"gemini,omitempty"`
+
 	Version        string          `yaml:"version,omitempty"`
 	OrganizationID string          `yaml:"organization_id,omitempty"`
 	Fallbacks      []ModelFallback `yaml:"fallbacks,omitempty"`
+
+	// Routes send matching LLM calls to a different model. They are
+	// checked in order before every call; the first match wins, and a
+	// call no route matches goes to the primary model and its
+	// fallbacks. A routed call that fails with a retriable error is
+	// retried on that primary chain.
+	Routes []ModelRoute `yaml:"routes,omitempty"`
+
+	// ResponseCache answers repeated idempotent LLM requests from a
+	// local cache — for eval runs, replays and CI. Off by default; the
+	// FORGE_LLM_CACHE* env vars override it per environment.
+	ResponseCache *ResponseCacheConfig `yaml:"response_cache,omitempty"`
+
+	// HTTP tunes the connection pool, proxy, TLS and timeouts of every
+	// provider client.
+	HTTP *ModelHTTPConfig `yaml:"http,omitempty"`
+}
+
+// ModelHTTPConfig is the model.http block. Every provider client —
+// primary, fallbacks and routes — shares one connection pool, so
+// concurrent tasks reuse warm connections instead of paying a TCP and
+// TLS handshake per call.
+type ModelHTTPConfig struct {
+	// MaxIdleConns bounds idle connections across hosts (default 100);
+	// MaxIdleConnsPerHost per provider host (default 32).
+	MaxIdleConns        int `yaml:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host,omitempty"`
+	// MaxConnsPerHost caps connections per host; 0 (default) is no cap.
+	MaxConnsPerHost int `yaml:"max_conns_per_host,omitempty"`
+	// IdleConnTimeout closes connections idle for longer. Default 90s.
+	IdleConnTimeout time.Duration `yaml:"idle_conn_timeout,omitempty"`
+	// HTTP2 negotiates HTTP/2 with providers that offer it. Default
+	// true; false pins HTTP/1.1 for gateways that mishandle HTTP/2.
+	HTTP2 *bool `yaml:"http2,omitempty"`
+	// Proxy sends provider traffic through an HTTP(S) proxy. Empty
+	// honors HTTPS_PROXY / NO_PROXY.
+	Proxy string          `yaml:"proxy,omitempty"`
+	TLS   *ModelTLSConfig `yaml:"tls,omitempty"`
+	// Timeout bounds each provider call, streaming included. Default
+	// 120s (300s for ollama).
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Timeouts overrides Timeout per provider, e.g. {ollama: 10m}.
+	Timeouts map[string]time.Duration `yaml:"timeouts,omitempty"`
+}
+
+// ModelTLSConfig is the model.http.tls block.
+type ModelTLSConfig struct {
+	// CAFile is a PEM bundle trusted in addition to the system roots,
+	// for gateways behind a private CA. Relative to the project
+	// directory.
+	CAFile string `yaml:"ca_file,omitempty"`
+	// MinVersion is "1.2" (default) or "1.3".
+	MinVersion string `yaml:"min_version,omitempty"`
+	// InsecureSkipVerify turns off certificate verification. Local
+	// gateways with self-signed certificates only.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify,omitempty"`
+}
+
+// GeminiOptions is the model.gemini block.
+type GeminiOptions struct {
+	// Grounding lets the model search Google to ground its answers;
+	// the sources it used come back as the response's citations.
+	Grounding bool `yaml:"grounding,omitempty"`
+
+	// SafetySettings sets the block threshold per harm category
+	// (harassment, hate_speech, sexually_explicit, dangerous_content,
+	// civic_integrity): block_none, block_only_high,
+	// block_medium_and_above, block_low_and_above or off. Categories
+	// left out keep Gemini's default.
+	SafetySettings map[string]string `yaml:"safety_settings,omitempty"`
+}
+
+// ResponseCacheConfig is the model.response_cache block. Only requests
+// that do not ask for sampling (temperature unset or 0) are cached,
+// keyed by provider, model, messages, tools and max tokens.
+type ResponseCacheConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// TTL is how long a stored response is served. Default 1h.
+	TTL time.Duration `yaml:"ttl,omitempty"`
+	// MaxEntries bounds the in-memory cache (LRU). Default 1000.
+	MaxEntries int `yaml:"max_entries,omitempty"`
+	// Dir persists responses across runs, relative to the project
+	// directory. Empty keeps the cache in memory only.
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// ModelRoute is one model.routes rule.
+type ModelRoute struct {
+	// ID names the rule in audit events (llm_call `route`). Defaults to
+	// "routes[<index>]".
+	ID   string         `yaml:"id,omitempty"`
+	When RouteCondition `yaml:"when"`
+
+	// Provider defaults to model.provider; a route on the primary's
+	// provider reuses its base URL and auth settings.
+	Provider        string `yaml:"provider,omitempty"`
+	Name            string `yaml:"name"`
+	ReasoningEffort string `yaml:"reasoning_effort,omitempty"`
+}
+
+// RouteCondition is a model.routes[].when block. Every set field must
+// match; an empty block matches every call.
+type RouteCondition struct {
+	// Metadata entries must equal the task's A2A request metadata.
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+	// Tags must all be present in the request's "tags" metadata.
+	Tags []string `yaml:"tags,omitempty"`
+	// MinInputTokens / MaxInputTokens bound the estimated prompt size.
+	MinInputTokens int `yaml:"min_input_tokens,omitempty"`
+	MaxInputTokens int `yaml:"max_input_tokens,omitempty"`
+	// Tools requires the call to offer tools (true) or none (false).
+	Tools *bool `yaml:"tools,omitempty"`
+	// ToolResults requires the call to follow tool results (true) or a
+	// user message (false).
+	ToolResults *bool `yaml:"tool_results,omitempty"`
 }
 
 // ModelFallback identifies an alternative LLM provider for fallback.
@@ -967,6 +2072,11 @@ type ModelFallback struct {
 
 	OrganizationID string `yaml:"organization_id,omitempty"`
 
+	// ReasoningEffort — same semantics as ModelRef.ReasoningEffort,
+	// applied to this fallback only so a chain can pair a
+	// high-effort primary with a cheaper fallback.
+	ReasoningEffort string `yaml:"reasoning_effort,omitempty"`
+
 	// NOTE: AuthScheme / AuthHeaderName / AWSRegion are intentionally
 	// absent — auth_scheme (#202 aws_sigv4, #302 apikey_header) applies to
 	// the PRIMARY model only. A fallback routed through the same gateway
@@ -1014,6 +2124,12 @@ func ParseForgeConfig(data []byte) (*ForgeConfig, error) {
 	// values, and the egress allowlist is computed from real hosts.
 	expandMCPEnv(&cfg)
 
+	if cfg.SchemaVersion > ForgeConfigSchemaVersion {
+		return nil, fmt.Errorf("forge config: schema_version %d is newer than this forge supports (%d); upgrade forge", cfg.SchemaVersion, ForgeConfigSchemaVersion)
+	}
+	if cfg.SchemaVersion < 0 {
+		return nil, fmt.Errorf("forge config: schema_version must not be negative")
+	}
 	if cfg.AgentID == "" {
 		return nil, fmt.Errorf("forge config: agent_id is required")
 	}
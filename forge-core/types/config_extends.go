@@ -0,0 +1,216 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BaseConfig is an organization base config, the file a forge.yaml
+// names in extends. Platform teams use it to set the egress, guardrail,
+// audit and secrets policy every agent inherits. Only those sections
+// may appear in it.
+type BaseConfig struct {
+	Egress            EgressRef `yaml:"egress,omitempty"`
+	EnforceGuardrails *bool     `yaml:"enforce_guardrails,omitempty"`
+	// Guardrails uses the guardrails.json schema (camelCase keys) and is
+	// merged most-restrictive onto the agent's guardrails.
+	Guardrails map[string]any `yaml:"guardrails,omitempty"`
+	Audit      AuditConfig    `yaml:"audit,omitempty"`
+	Secrets    SecretsConfig  `yaml:"secrets,omitempty"`
+}
+
+// ParseBaseConfig parses a base config. Keys outside the inheritable
+// sections, and misspelled keys inside them, are errors: a base config
+// that silently dropped a mandate would leave agents looser than the
+// platform team intended.
+func ParseBaseConfig(data []byte) (*BaseConfig, error) {
+	var base BaseConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&base); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing base config (only egress, enforce_guardrails, guardrails, audit and secrets are inheritable): %w", err)
+	}
+	return &base, nil
+}
+
+// egressModeRank and egressProfileRank order egress settings from
+// strictest to loosest.
+var (
+	egressModeRank    = map[string]int{"deny-all": 0, "allowlist": 1, "dev-open": 2}
+	egressProfileRank = map[string]int{"strict": 0, "standard": 1, "permissive": 2}
+)
+
+// Inherit merges base into the config. Settings the config leaves unset
+// take the base's value; settings it does set must be at least as strict
+// as the base's:
+//
+//   - egress mode and profile may not be looser; allowed_domains,
+//     capabilities, allowed_tcp and allowed_private_cidrs set in the base
+//     bound the config's lists; allow_private_ips may not be turned on
+//     when the base turns it off
+//   - enforce_guardrails may not be turned off when the base turns it on
+//   - audit.export sinks may not be replaced, and audit.capture.redact
+//     may not be turned off
+//   - secrets providers must be among the base's
+//
+// Every loosening is reported in one error, and the config is left
+// partly merged; callers should discard it.
+func (c *ForgeConfig) Inherit(base *BaseConfig) error {
+	var loose []string
+	loosen := func(format string, args ...any) {
+		loose = append(loose, fmt.Sprintf(format, args...))
+	}
+
+	e, b := &c.Egress, base.Egress
+	if b.Mode != "" {
+		if e.Mode == "" {
+			e.Mode = b.Mode
+		} else if egressModeRank[e.Mode] > egressModeRank[b.Mode] {
+			loosen("egress.mode %q is looser than the base's %q", e.Mode, b.Mode)
+		}
+	}
+	if b.Profile != "" {
+		if e.Profile == "" {
+			e.Profile = b.Profile
+		} else if egressProfileRank[e.Profile] > egressProfileRank[b.Profile] {
+			loosen("egress.profile %q is looser than the base's %q", e.Profile, b.Profile)
+		}
+	}
+	inheritBounded(&e.AllowedDomains, b.AllowedDomains, domainCovered, "egress.allowed_domains", loosen)
+	if len(b.AllowedDomains) > 0 || len(b.Capabilities) > 0 {
+		// Capabilities add domains, so a base domain list bounds them too.
+		inheritBounded(&e.Capabilities, b.Capabilities, slices.Contains[[]string], "egress.capabilities", loosen)
+	}
+	inheritBounded(&e.AllowedTCP, b.AllowedTCP, slices.Contains[[]string], "egress.allowed_tcp", loosen)
+	inheritBounded(&e.AllowedPrivateCIDRs, b.AllowedPrivateCIDRs, cidrCovered, "egress.allowed_private_cidrs", loosen)
+	if b.AllowPrivateIPs != nil {
+		if e.AllowPrivateIPs == nil {
+			v := *b.AllowPrivateIPs
+			e.AllowPrivateIPs = &v
+		} else if *e.AllowPrivateIPs && !*b.AllowPrivateIPs {
+			loosen("egress.allow_private_ips may not be enabled; the base disables it")
+		}
+	}
+
+	if b := base.EnforceGuardrails; b != nil {
+		if c.EnforceGuardrails == nil {
+			v := *b
+			c.EnforceGuardrails = &v
+		} else if *b && !*c.EnforceGuardrails {
+			loosen("enforce_guardrails may not be disabled; the base enforces guardrails")
+		}
+	}
+	c.BaseGuardrails = base.Guardrails
+
+	ex, bx := &c.Audit.Export, base.Audit.Export
+	for _, f := range []struct {
+		key        string
+		own, based *string
+	}{
+		{"socket", &ex.Socket, &bx.Socket},
+		{"http_endpoint", &ex.HTTPEndpoint, &bx.HTTPEndpoint},
+	} {
+		if *f.based == "" {
+			continue
+		}
+		if *f.own == "" {
+			*f.own = *f.based
+		} else if *f.own != *f.based {
+			loosen("audit.export.%s %q may not replace the base's %q", f.key, *f.own, *f.based)
+		}
+	}
+	cp, bp := &c.Audit.Capture, base.Audit.Capture
+	for _, f := range []struct{ own, based **bool }{
+		{&cp.ToolArgs, &bp.ToolArgs},
+		{&cp.ToolResult, &bp.ToolResult},
+		{&cp.LLMMessages, &bp.LLMMessages},
+		{&cp.LLMResponse, &bp.LLMResponse},
+		{&cp.Redact, &bp.Redact},
+	} {
+		if *f.own == nil && *f.based != nil {
+			v := **f.based
+			*f.own = &v
+		}
+	}
+	if bp.Redact != nil && *bp.Redact && !*cp.Redact {
+		loosen("audit.capture.redact may not be disabled; the base redacts captured payloads")
+	}
+	if cp.MaxBytes == 0 {
+		cp.MaxBytes = bp.MaxBytes
+	}
+
+	inheritBounded(&c.Secrets.Providers, base.Secrets.Providers, slices.Contains[[]string], "secrets.providers", loosen)
+	if c.Secrets.Path == "" {
+		c.Secrets.Path = base.Secrets.Path
+	}
+	if len(c.Secrets.Recipients) == 0 {
+		c.Secrets.Recipients = base.Secrets.Recipients
+	}
+
+	if len(loose) > 0 {
+		return fmt.Errorf("forge config loosens its base config %s:\n  %s", c.Extends, strings.Join(loose, "\n  "))
+	}
+	return nil
+}
+
+// inheritBounded applies a base list that is both the default and the
+// bound for the config's own list: an unset list takes the base's, and
+// every entry of a set one must be covered by it.
+func inheritBounded(own *[]string, base []string, covered func([]string, string) bool, key string, loosen func(string, ...any)) {
+	if len(base) == 0 {
+		return
+	}
+	if len(*own) == 0 {
+		*own = slices.Clone(base)
+		return
+	}
+	for _, v := range *own {
+		if !covered(base, v) {
+			loosen("%s entry %q is not allowed by the base", key, v)
+		}
+	}
+}
+
+// domainCovered reports whether domain (possibly a *.wildcard) is within
+// the allowlist, using DomainMatcher's exact and *.suffix rules.
+func domainCovered(allowed []string, domain string) bool {
+	domain = strings.ToLower(domain)
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == domain {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(a, "*"); ok && strings.HasPrefix(suffix, ".") &&
+			strings.HasSuffix(strings.TrimPrefix(domain, "*"), suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cidrCovered reports whether cidr lies entirely inside one of allowed.
+func cidrCovered(allowed []string, cidr string) bool {
+	_, inner, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	innerOnes, _ := inner.Mask.Size()
+	for _, a := range allowed {
+		_, outer, err := net.ParseCIDR(a)
+		if err != nil {
+			continue
+		}
+		outerOnes, _ := outer.Mask.Size()
+		if outer.Contains(inner.IP) && outerOnes <= innerOnes && len(outer.IP) == len(inner.IP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+const baseYAML = `
+egress:
+  mode: allowlist
+  profile: standard
+  allowed_domains: [api.openai.com, "*.example.com"]
+  allowed_private_cidrs: [10.0.0.0/8]
+enforce_guardrails: true
+guardrails:
+  security:
+    commandInjection: {enabled: true, action: block}
+audit:
+  export:
+    socket: /var/run/forge/audit.sock
+  capture:
+    redact: true
+secrets:
+  providers: [encrypted-file, env]
+`
+
+func TestInherit_FillsUnsetFromBase(t *testing.T) {
+	base, err := ParseBaseConfig([]byte(baseYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ForgeConfig{Extends: "org-base.yaml"}
+	if err := cfg.Inherit(base); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Egress.Mode != "allowlist" || cfg.Egress.Profile != "standard" || len(cfg.Egress.AllowedDomains) != 2 {
+		t.Errorf("egress = %+v", cfg.Egress)
+	}
+	if cfg.EnforceGuardrails == nil || !*cfg.EnforceGuardrails || cfg.BaseGuardrails["security"] == nil {
+		t.Errorf("guardrails = %v, %v", cfg.EnforceGuardrails, cfg.BaseGuardrails)
+	}
+	if cfg.Audit.Export.Socket != "/var/run/forge/audit.sock" || cfg.Audit.Capture.Redact == nil || !*cfg.Audit.Capture.Redact {
+		t.Errorf("audit = %+v", cfg.Audit)
+	}
+	if strings.Join(cfg.Secrets.Providers, ",") != "encrypted-file,env" {
+		t.Errorf("secrets = %+v", cfg.Secrets)
+	}
+}
+
+func TestInherit_AllowsTightening(t *testing.T) {
+	base, _ := ParseBaseConfig([]byte(baseYAML))
+	cfg, err := ParseForgeConfig([]byte(`
+agent_id: a
+version: 0.1.0
+framework: forge
+egress:
+  mode: deny-all
+  profile: strict
+  allowed_domains: [api.openai.com, "*.eu.example.com", docs.example.com]
+  allowed_private_cidrs: [10.20.0.0/16]
+secrets:
+  providers: [encrypted-file]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Inherit(base); err != nil {
+		t.Errorf("tightening rejected: %v", err)
+	}
+}
+
+func TestInherit_RejectsLoosening(t *testing.T) {
+	base, _ := ParseBaseConfig([]byte(baseYAML))
+	off := false
+	cfg := &ForgeConfig{
+		Extends: "org-base.yaml",
+		Egress: EgressRef{
+			Mode:                "dev-open",
+			Profile:             "permissive",
+			AllowedDomains:      []string{"example.com", "evil.io"},
+			AllowedPrivateCIDRs: []string{"10.0.0.0/7"},
+		},
+		EnforceGuardrails: &off,
+		Audit: AuditConfig{
+			Export:  AuditExportYAML{Socket: "/tmp/mine.sock"},
+			Capture: AuditCaptureConfig{Redact: &off},
+		},
+		Secrets: SecretsConfig{Providers: []string{"vault"}},
+	}
+	err := cfg.Inherit(base)
+	if err == nil {
+		t.Fatal("expected loosening to be rejected")
+	}
+	for _, want := range []string{
+		"egress.mode", "egress.profile", `"example.com"`, `"evil.io"`, `"10.0.0.0/7"`,
+		"enforce_guardrails", "audit.export.socket", "audit.capture.redact", `"vault"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s:\n%v", want, err)
+		}
+	}
+}
+
+func TestParseBaseConfig_RejectsNonInheritableKeys(t *testing.T) {
+	for _, in := range []string{"model:\n  name: gpt-4o\n", "egress:\n  mdoe: allowlist\n"} {
+		if _, err := ParseBaseConfig([]byte(in)); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+	if _, err := ParseBaseConfig(nil); err != nil {
+		t.Errorf("empty base config: %v", err)
+	}
+}
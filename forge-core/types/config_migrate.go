@@ -0,0 +1,136 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ForgeConfigSchemaVersion is the forge.yaml layout version this build
+// reads and writes, matching the maximum schema_version accepted by
+// forgeconfig.v1.schema.json. Files without schema_version predate
+// versioning and are version 0.
+const ForgeConfigSchemaVersion = 1
+
+// forgeConfigMigrations[i] rewrites a version-i document, in place, into
+// the version-i+1 layout and reports whether it changed anything.
+// MigrateForgeConfig stamps the new version itself, so a step only moves
+// or rewrites keys.
+var forgeConfigMigrations = []func(root *yaml.Node) (bool, error){
+	// 0 → 1: the unversioned layout is the version-1 layout; only the
+	// version is recorded.
+	func(*yaml.Node) (bool, error) { return false, nil },
+}
+
+// MigrateForgeConfig upgrades raw forge.yaml bytes to
+// ForgeConfigSchemaVersion, returning the upgraded document and the
+// version it started from. A current file is returned unchanged. When
+// the steps only need the version stamped, the file is edited in place
+// and otherwise kept byte for byte; a step that rewrites keys re-encodes
+// the document, which keeps comments and key order but normalises
+// indentation to two spaces and drops blank lines.
+func MigrateForgeConfig(data []byte) ([]byte, int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("parsing forge config: %w", err)
+	}
+	if doc.Kind == 0 {
+		// Empty file: nothing to migrate; ParseForgeConfig reports the
+		// missing required fields.
+		return data, ForgeConfigSchemaVersion, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, 0, fmt.Errorf("forge config: top level must be a mapping")
+	}
+
+	from := 0
+	if v := mappingValue(root, "schema_version"); v != nil {
+		n, err := strconv.Atoi(v.Value)
+		if v.Kind != yaml.ScalarNode || err != nil {
+			return nil, 0, fmt.Errorf("forge config: line %d: schema_version must be an integer", v.Line)
+		}
+		from = n
+	}
+	if from >= ForgeConfigSchemaVersion || from < 0 {
+		// Current, or out of range — ParseForgeConfig rejects the latter.
+		return data, from, nil
+	}
+
+	rewritten := false
+	for v := from; v < ForgeConfigSchemaVersion; v++ {
+		changed, err := forgeConfigMigrations[v](root)
+		if err != nil {
+			return nil, from, fmt.Errorf("migrating forge config from schema version %d: %w", v, err)
+		}
+		rewritten = rewritten || changed
+	}
+	if !rewritten && len(root.Content) > 0 && root.Style&yaml.FlowStyle == 0 {
+		return stampSchemaVersion(data, root), from, nil
+	}
+	setSchemaVersion(root, ForgeConfigSchemaVersion)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, from, fmt.Errorf("encoding migrated forge config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, from, fmt.Errorf("encoding migrated forge config: %w", err)
+	}
+	return buf.Bytes(), from, nil
+}
+
+// mappingValue returns the value node for key in mapping node m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setSchemaVersion sets schema_version on the root mapping, adding it as
+// the first key when absent.
+func setSchemaVersion(root *yaml.Node, v int) {
+	val := strconv.Itoa(v)
+	if n := mappingValue(root, "schema_version"); n != nil {
+		n.Kind, n.Tag, n.Value, n.Style = yaml.ScalarNode, "!!int", val, 0
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schema_version"}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: val}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// stampSchemaVersion edits the schema_version line of data to the
+// current version, or inserts one above the first top-level key and its
+// comment, leaving the rest of the file untouched.
+func stampSchemaVersion(data []byte, root *yaml.Node) []byte {
+	val := strconv.Itoa(ForgeConfigSchemaVersion)
+	lines := strings.SplitAfter(string(data), "\n")
+	if n := mappingValue(root, "schema_version"); n != nil {
+		line := lines[n.Line-1]
+		end := n.Column - 1 + len(n.Value)
+		if n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			end += 2
+		}
+		lines[n.Line-1] = line[:n.Column-1] + val + line[end:]
+		return []byte(strings.Join(lines, ""))
+	}
+	first := root.Content[0]
+	at := first.Line - 1
+	stamp := "schema_version: " + val + "\n"
+	if first.HeadComment != "" {
+		at -= strings.Count(first.HeadComment, "\n") + 1
+		stamp += "\n"
+	}
+	lines = slices.Insert(lines, at, stamp)
+	return []byte(strings.Join(lines, ""))
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrateForgeConfig_StampsUnversionedFile(t *testing.T) {
+	in := `# My agent.
+
+# The agent's ID.
+agent_id: a # inline
+version: 0.1.0
+
+framework: forge
+`
+	out, from, err := MigrateForgeConfig([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# My agent.
+
+schema_version: 1
+
+# The agent's ID.
+agent_id: a # inline
+version: 0.1.0
+
+framework: forge
+`
+	if from != 0 || string(out) != want {
+		t.Errorf("from %d, got:\n%s", from, out)
+	}
+	cfg, err := ParseForgeConfig(out)
+	if err != nil || cfg.SchemaVersion != ForgeConfigSchemaVersion {
+		t.Errorf("parse migrated = %+v, %v", cfg, err)
+	}
+
+	// Already current: returned as is.
+	again, from, err := MigrateForgeConfig(out)
+	if err != nil || from != ForgeConfigSchemaVersion || string(again) != string(out) {
+		t.Errorf("second migration = %q, %d, %v", again, from, err)
+	}
+}
+
+func TestMigrateForgeConfig_ExplicitVersionZero(t *testing.T) {
+	out, from, err := MigrateForgeConfig([]byte("agent_id: a\nschema_version: \"0\"  # old\nversion: 0.1.0\n"))
+	if err != nil || from != 0 || string(out) != "agent_id: a\nschema_version: 1  # old\nversion: 0.1.0\n" {
+		t.Errorf("got %q, %d, %v", out, from, err)
+	}
+}
+
+func TestMigrateForgeConfig_Errors(t *testing.T) {
+	for _, in := range []string{"- a\n- b\n", "schema_version: one\n", "agent_id: [x\n"} {
+		if _, _, err := MigrateForgeConfig([]byte(in)); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+	_, err := ParseForgeConfig([]byte("schema_version: 99\nagent_id: a\nversion: 1\nframework: forge\n"))
+	if err == nil || !strings.Contains(err.Error(), "upgrade forge") {
+		t.Errorf("newer schema_version error = %v", err)
+	}
+}
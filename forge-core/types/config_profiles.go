@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnv names the environment variable selecting the forge.yaml
+// profile to apply, the env form of `forge run --profile`.
+const ProfileEnv = "FORGE_PROFILE"
+
+// ProfileConfig is one named environment overlay under forge.yaml's
+// profiles: block. Each key mirrors the top-level key it overrides:
+//
+//	profiles:
+//	  prod:
+//	    model: {name: gpt-4o}
+//	    egress: {mode: allowlist}
+//	    enforce_guardrails: true
+//	    secrets: {providers: [env]}
+//
+// Sections are kept as raw YAML until applied so the merge can tell
+// which keys the profile sets.
+type ProfileConfig struct {
+	Model             yaml.Node `yaml:"model,omitempty"`
+	Egress            yaml.Node `yaml:"egress,omitempty"`
+	EnforceGuardrails *bool     `yaml:"enforce_guardrails,omitempty"`
+	Secrets           yaml.Node `yaml:"secrets,omitempty"`
+}
+
+// ApplyProfile merges the named profile onto the config. Keys the
+// profile sets replace the base values — lists wholesale — and keys it
+// omits keep them. An empty name applies nothing; a name forge.yaml
+// doesn't define is an error.
+func (c *ForgeConfig) ApplyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		if len(names) == 0 {
+			return fmt.Errorf("forge config: profile %q is not defined (forge.yaml has no profiles)", name)
+		}
+		return fmt.Errorf("forge config: profile %q is not defined (have: %s)", name, strings.Join(names, ", "))
+	}
+	sections := []struct {
+		key  string
+		node *yaml.Node
+		into any
+	}{
+		{"model", &p.Model, &c.Model},
+		{"egress", &p.Egress, &c.Egress},
+		{"secrets", &p.Secrets, &c.Secrets},
+	}
+	for _, s := range sections {
+		if s.node.Kind == 0 {
+			continue
+		}
+		if err := s.node.Decode(s.into); err != nil {
+			return fmt.Errorf("forge config: profiles.%s.%s: %w", name, s.key, err)
+		}
+	}
+	if p.EnforceGuardrails != nil {
+		v := *p.EnforceGuardrails
+		c.EnforceGuardrails = &v
+	}
+	c.ActiveProfile = name
+	return nil
+}
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+const profilesYAML = `
+agent_id: a
+version: 0.1.0
+framework: forge
+model:
+  provider: openai
+  name: gpt-4o-mini
+  fallbacks:
+    - provider: anthropic
+      name: claude-haiku
+egress:
+  mode: dev-open
+  allowed_domains: [api.openai.com]
+secrets:
+  providers: [env]
+profiles:
+  prod:
+    model:
+      name: gpt-4o
+      fallbacks: []
+    egress:
+      mode: allowlist
+    enforce_guardrails: true
+    secrets:
+      providers: [encrypted-file, env]
+  dev:
+    enforce_guardrails: false
+`
+
+func TestApplyProfile_MergesOverlay(t *testing.T) {
+	cfg, err := ParseForgeConfig([]byte(profilesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.ApplyProfile("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Model.Provider != "openai" || cfg.Model.Name != "gpt-4o" || len(cfg.Model.Fallbacks) != 0 {
+		t.Errorf("model = %+v", cfg.Model)
+	}
+	if cfg.Egress.Mode != "allowlist" || len(cfg.Egress.AllowedDomains) != 1 {
+		t.Errorf("egress = %+v", cfg.Egress)
+	}
+	if cfg.EnforceGuardrails == nil || !*cfg.EnforceGuardrails || cfg.ActiveProfile != "prod" {
+		t.Errorf("enforce_guardrails = %v, active = %q", cfg.EnforceGuardrails, cfg.ActiveProfile)
+	}
+	if strings.Join(cfg.Secrets.Providers, ",") != "encrypted-file,env" {
+		t.Errorf("secrets = %+v", cfg.Secrets)
+	}
+}
+
+func TestApplyProfile_NoneAndUnknown(t *testing.T) {
+	cfg, err := ParseForgeConfig([]byte(profilesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.ApplyProfile(""); err != nil || cfg.Model.Name != "gpt-4o-mini" || cfg.ActiveProfile != "" {
+		t.Errorf("empty profile changed config: %v", err)
+	}
+	if err := cfg.ApplyProfile("staging"); err == nil || !strings.Contains(err.Error(), "have: dev, prod") {
+		t.Errorf("unknown profile error = %v", err)
+	}
+	if err := cfg.ApplyProfile("dev"); err != nil || cfg.Model.Name != "gpt-4o-mini" || *cfg.EnforceGuardrails {
+		t.Errorf("dev profile = %+v, %v", cfg, err)
+	}
+}
@@ -0,0 +1,168 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkspaceFile is the default name of the workspace config `forge run
+// --all` reads.
+const WorkspaceFile = "forge-workspace.yaml"
+
+// WorkspaceBasePort is the first port handed to workspace agents that
+// don't set one.
+const WorkspaceBasePort = 8080
+
+// WorkspaceConfig is a forge-workspace.yaml: several agent directories
+// run together, each on its own port.
+//
+//	env:
+//	  LOG_LEVEL: debug
+//	agents:
+//	  - name: researcher
+//	    dir: ./researcher
+//	    port: 8081
+//	  - name: writer
+//	    dir: ./writer
+//	    depends_on: [researcher]
+//	    env:
+//	      MODEL_NAME: gpt-4o
+type WorkspaceConfig struct {
+	// Env is set for every agent; an agent's own env wins over it.
+	Env    map[string]string `yaml:"env,omitempty"`
+	Agents []WorkspaceAgent  `yaml:"agents"`
+}
+
+// WorkspaceAgent is one agent in a workspace.
+type WorkspaceAgent struct {
+	// Name labels the agent's log lines and is what depends_on refers
+	// to. Defaults to the base name of Dir.
+	Name string `yaml:"name,omitempty"`
+	// Dir is the agent directory (holding its forge.yaml), relative to
+	// the workspace file.
+	Dir string `yaml:"dir"`
+	// Port is the agent's A2A port. Zero takes the next free one from
+	// WorkspaceBasePort in declaration order.
+	Port int               `yaml:"port,omitempty"`
+	Env  map[string]string `yaml:"env,omitempty"`
+	// DependsOn names agents that must be healthy before this one
+	// starts.
+	DependsOn []string `yaml:"depends_on,omitempty"`
+}
+
+var workspaceAgentName = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
+// ParseWorkspaceConfig parses and validates a forge-workspace.yaml,
+// filling in default names and ports. Misspelled keys are errors.
+func ParseWorkspaceConfig(data []byte) (*WorkspaceConfig, error) {
+	var ws WorkspaceConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&ws); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing workspace config: %w", err)
+	}
+	if len(ws.Agents) == 0 {
+		return nil, fmt.Errorf("workspace config: agents is required")
+	}
+
+	names := make(map[string]bool, len(ws.Agents))
+	ports := make(map[int]string, len(ws.Agents))
+	for i := range ws.Agents {
+		a := &ws.Agents[i]
+		if a.Dir == "" {
+			return nil, fmt.Errorf("workspace config: agents[%d]: dir is required", i)
+		}
+		if a.Name == "" {
+			a.Name = strings.ToLower(filepath.Base(filepath.Clean(a.Dir)))
+		}
+		if !workspaceAgentName.MatchString(a.Name) {
+			return nil, fmt.Errorf("workspace config: agent name %q must be lowercase letters, digits, '-' and '_'", a.Name)
+		}
+		if names[a.Name] {
+			return nil, fmt.Errorf("workspace config: agent name %q is used twice", a.Name)
+		}
+		names[a.Name] = true
+		if a.Port < 0 || a.Port > 65535 {
+			return nil, fmt.Errorf("workspace config: agent %q: port %d is out of range", a.Name, a.Port)
+		}
+		if a.Port != 0 {
+			if other, ok := ports[a.Port]; ok {
+				return nil, fmt.Errorf("workspace config: agents %q and %q both use port %d", other, a.Name, a.Port)
+			}
+			ports[a.Port] = a.Name
+		}
+	}
+	next := WorkspaceBasePort
+	for i := range ws.Agents {
+		a := &ws.Agents[i]
+		if a.Port != 0 {
+			continue
+		}
+		for ports[next] != "" {
+			next++
+		}
+		a.Port = next
+		ports[next] = a.Name
+	}
+
+	for _, a := range ws.Agents {
+		for _, d := range a.DependsOn {
+			if !names[d] {
+				return nil, fmt.Errorf("workspace config: agent %q depends on unknown agent %q", a.Name, d)
+			}
+		}
+	}
+	if _, err := ws.StartOrder(); err != nil {
+		return nil, err
+	}
+	return &ws, nil
+}
+
+// StartOrder returns the agents ordered so each comes after everything
+// it depends on, keeping declaration order otherwise. A dependency
+// cycle is an error.
+func (ws *WorkspaceConfig) StartOrder() ([]WorkspaceAgent, error) {
+	byName := make(map[string]WorkspaceAgent, len(ws.Agents))
+	for _, a := range ws.Agents {
+		byName[a.Name] = a
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(ws.Agents))
+	order := make([]WorkspaceAgent, 0, len(ws.Agents))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			cycle := append(path[slices.Index(path, name):], name)
+			return fmt.Errorf("workspace config: dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+		state[name] = visiting
+		for _, d := range byName[name].DependsOn {
+			if err := visit(d, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		order = append(order, byName[name])
+		return nil
+	}
+	for _, a := range ws.Agents {
+		if err := visit(a.Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWorkspaceConfig_DefaultsAndOrder(t *testing.T) {
+	ws, err := ParseWorkspaceConfig([]byte(`
+env:
+  LOG_LEVEL: debug
+agents:
+  - dir: ./Writer
+    depends_on: [researcher]
+  - name: researcher
+    dir: ./agents/research
+    port: 8080
+    depends_on: [search]
+  - name: search
+    dir: ./search
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	for _, a := range ws.Agents {
+		got[a.Name] = a.Port
+	}
+	// writer takes the first port not claimed explicitly; search the next.
+	want := map[string]int{"writer": 8081, "researcher": 8080, "search": 8082}
+	for name, port := range want {
+		if got[name] != port {
+			t.Errorf("agent %q port = %d, want %d (all: %v)", name, got[name], port, got)
+		}
+	}
+
+	order, err := ws.StartOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range order {
+		names = append(names, a.Name)
+	}
+	if strings.Join(names, ",") != "search,researcher,writer" {
+		t.Errorf("start order = %v", names)
+	}
+}
+
+func TestParseWorkspaceConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name, yaml, want string
+	}{
+		{"empty", ``, "agents is required"},
+		{"no dir", "agents:\n  - name: a\n", "dir is required"},
+		{"typo", "agents:\n  - dir: a\n    dependson: [b]\n", "dependson"},
+		{"duplicate", "agents:\n  - dir: x/a\n  - dir: y/a\n", `"a" is used twice`},
+		{"bad name", "agents:\n  - name: My Agent\n    dir: a\n", "must be lowercase"},
+		{"port clash", "agents:\n  - {dir: a, port: 9000}\n  - {dir: b, port: 9000}\n", "both use port 9000"},
+		{"port range", "agents:\n  - {dir: a, port: 70000}\n", "out of range"},
+		{"unknown dep", "agents:\n  - {dir: a, depends_on: [b]}\n", `unknown agent "b"`},
+		{"cycle", "agents:\n  - {dir: a, depends_on: [b]}\n  - {dir: b, depends_on: [c]}\n  - {dir: c, depends_on: [b]}\n", "cycle: b -> c -> b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWorkspaceConfig([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
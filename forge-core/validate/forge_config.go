@@ -2,12 +2,23 @@ package validate
 
 import (
 	"fmt"
+	"maps"
+	"net/url"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
 	"github.com/initializ/forge/forge-core/scheduler"
+	"github.com/initializ/forge/forge-core/secrets"
+	"github.com/initializ/forge/forge-core/secrets/remote"
+	"github.com/initializ/forge/forge-core/tools"
 	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/workflow"
 )
 
 var kebabCasePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
@@ -39,11 +50,13 @@ var (
 	agentIDPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
 	semverPattern  = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
 
-	knownFrameworks      = map[string]bool{"forge": true, "crewai": true, "langchain": true, "custom": true}
-	knownEgressProfiles  = map[string]bool{"strict": true, "standard": true, "permissive": true}
-	knownEgressModes     = map[string]bool{"deny-all": true, "allowlist": true, "dev-open": true}
-	knownSecretProviders = map[string]bool{"env": true, "encrypted-file": true}
-	knownGuardrailTypes  = map[string]bool{
+	knownFrameworks        = map[string]bool{"forge": true, "crewai": true, "langchain": true, "custom": true}
+	knownEgressProfiles    = map[string]bool{"strict": true, "standard": true, "permissive": true}
+	knownEgressModes       = map[string]bool{"deny-all": true, "allowlist": true, "dev-open": true}
+	knownEgressSNIPolicies = map[string]bool{"allowlist": true, "require": true, "off": true}
+	knownSecretProviders   = map[string]bool{"env": true, "encrypted-file": true}
+	knownReasoningEfforts  = map[string]bool{"": true, "minimal": true, "low": true, "medium": true, "high": true}
+	knownGuardrailTypes    = map[string]bool{
 		"no_pii":                   true,
 		"jailbreak_protection":     true,
 		"tool_scope_enforcement":   true,
@@ -52,6 +65,231 @@ var (
 	}
 )
 
+// validateReasoningEffort rejects an unknown reasoning_effort and warns
+// when provider would drop the value rather than send it.
+func validateReasoningEffort(r *ValidationResult, path, provider, effort string) {
+	if !knownReasoningEfforts[effort] {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s.reasoning_effort %q must be one of: minimal, low, medium, high", path, effort))
+		return
+	}
+	if effort == "" || provider == "" {
+		return
+	}
+	if supported := providers.ReasoningEfforts(provider); !slices.Contains(supported, effort) {
+		if len(supported) == 0 {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s.reasoning_effort is set but provider %q does not support it; it will be ignored", path, provider))
+		} else {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s.reasoning_effort %q is not supported by provider %q (supported: %s); it will be ignored", path, effort, provider, strings.Join(supported, ", ")))
+		}
+	}
+}
+
+// usesOllama reports whether m or any of its fallbacks is ollama.
+func usesOllama(m types.ModelRef) bool {
+	if m.Provider == "ollama" {
+		return true
+	}
+	for _, fb := range m.Fallbacks {
+		if fb.Provider == "ollama" {
+			return true
+		}
+	}
+	return false
+}
+
+// usesGemini reports whether m, a fallback or a route is gemini.
+func usesGemini(m types.ModelRef) bool {
+	if m.Provider == "gemini" {
+		return true
+	}
+	for _, fb := range m.Fallbacks {
+		if fb.Provider == "gemini" {
+			return true
+		}
+	}
+	for _, rt := range m.Routes {
+		if rt.Provider == "gemini" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateModelHTTP checks model.http.
+// validateEgressConstraints checks egress.allowed_schemes,
+// max_response_bytes, rules and proxy.
+func validateEgressConstraints(r *ValidationResult, e types.EgressRef) {
+	for _, s := range e.AllowedSchemes {
+		switch strings.ToLower(s) {
+		case "https":
+		case "http":
+			r.Warnings = append(r.Warnings, "egress.allowed_schemes includes http: plaintext egress can be read and altered in transit")
+		default:
+			r.Errors = append(r.Errors, fmt.Sprintf("egress.allowed_schemes: %q must be http or https", s))
+		}
+	}
+	if e.MaxResponseBytes < 0 {
+		r.Errors = append(r.Errors, "egress.max_response_bytes must not be negative")
+	}
+	seen := make(map[string]bool, len(e.Rules))
+	for i, rule := range e.Rules {
+		domain := strings.ToLower(strings.TrimSpace(rule.Domain))
+		switch {
+		case domain == "":
+			r.Errors = append(r.Errors, fmt.Sprintf("egress.rules[%d]: domain is required", i))
+		case seen[domain]:
+			r.Errors = append(r.Errors, fmt.Sprintf("egress.rules[%d]: duplicate rule for %q", i, domain))
+		}
+		seen[domain] = true
+		for _, p := range rule.Ports {
+			if p < 1 || p > 65535 {
+				r.Errors = append(r.Errors, fmt.Sprintf("egress.rules[%d]: port %d out of range 1-65535", i, p))
+			}
+		}
+		for _, m := range rule.Methods {
+			if m == "" || strings.ContainsFunc(strings.ToUpper(m), func(c rune) bool { return c < 'A' || c > 'Z' }) {
+				r.Errors = append(r.Errors, fmt.Sprintf("egress.rules[%d]: invalid method %q", i, m))
+			}
+		}
+		if rule.MaxResponseBytes < 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("egress.rules[%d].max_response_bytes must not be negative", i))
+		}
+	}
+	if p := e.Proxy; p != nil {
+		if p.SNI != "" && !knownEgressSNIPolicies[p.SNI] {
+			r.Errors = append(r.Errors, fmt.Sprintf("egress.proxy.sni %q must be one of: allowlist, require, off", p.SNI))
+		}
+		if p.TaskMaxBytes < 0 || p.TaskMaxRequests < 0 {
+			r.Errors = append(r.Errors, "egress.proxy: task quotas must not be negative")
+		}
+		if p.Socket != "" && !path.IsAbs(p.Socket) {
+			r.Errors = append(r.Errors, fmt.Sprintf("egress.proxy.socket %q must be an absolute path", p.Socket))
+		}
+	}
+}
+
+func validateModelHTTP(r *ValidationResult, h *types.ModelHTTPConfig) {
+	if h == nil {
+		return
+	}
+	if h.MaxIdleConns < 0 || h.MaxIdleConnsPerHost < 0 || h.MaxConnsPerHost < 0 {
+		r.Errors = append(r.Errors, "model.http: connection limits must not be negative")
+	}
+	if h.IdleConnTimeout < 0 || h.Timeout < 0 {
+		r.Errors = append(r.Errors, "model.http: durations must not be negative")
+	}
+	if h.Proxy != "" {
+		if u, err := url.Parse(h.Proxy); err != nil || u.Host == "" {
+			r.Errors = append(r.Errors, fmt.Sprintf("model.http.proxy %q is not a valid URL", h.Proxy))
+		}
+	}
+	if t := h.TLS; t != nil {
+		if t.MinVersion != "" && t.MinVersion != "1.2" && t.MinVersion != "1.3" {
+			r.Errors = append(r.Errors, fmt.Sprintf("model.http.tls.min_version %q must be 1.2 or 1.3", t.MinVersion))
+		}
+		if t.InsecureSkipVerify {
+			r.Warnings = append(r.Warnings, "model.http.tls.insecure_skip_verify disables certificate verification for every provider call")
+		}
+	}
+	for _, provider := range slices.Sorted(maps.Keys(h.Timeouts)) {
+		if !providers.IsSupported(provider) {
+			r.Errors = append(r.Errors, fmt.Sprintf("model.http.timeouts: unknown provider %q", provider))
+		} else if h.Timeouts[provider] < 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("model.http.timeouts.%s must not be negative", provider))
+		}
+	}
+}
+
+// validateModelRoutes checks model.routes. An empty `when` is legal but
+// shadows every later route and the primary model, so it only warns.
+func validateModelRoutes(r *ValidationResult, m types.ModelRef) {
+	seen := map[string]bool{}
+	for i, rt := range m.Routes {
+		path := fmt.Sprintf("model.routes[%d]", i)
+		if rt.ID != "" {
+			if seen[rt.ID] {
+				r.Errors = append(r.Errors, fmt.Sprintf("%s: duplicate id %q", path, rt.ID))
+			}
+			seen[rt.ID] = true
+		}
+		provider := rt.Provider
+		if provider == "" {
+			provider = m.Provider
+		}
+		if rt.Name == "" && provider == m.Provider {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.name is required when the route uses the primary provider", path))
+		}
+		w := rt.When
+		if w.MinInputTokens < 0 || w.MaxInputTokens < 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.when: token bounds must not be negative", path))
+		} else if w.MaxInputTokens > 0 && w.MinInputTokens > w.MaxInputTokens {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.when: min_input_tokens (%d) exceeds max_input_tokens (%d)", path, w.MinInputTokens, w.MaxInputTokens))
+		}
+		if len(w.Metadata) == 0 && len(w.Tags) == 0 && w.MinInputTokens == 0 && w.MaxInputTokens == 0 && w.Tools == nil && w.ToolResults == nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s.when is empty and matches every call; later routes and the primary model only serve as its fallback", path))
+		}
+		validateReasoningEffort(r, path, provider, rt.ReasoningEffort)
+	}
+}
+
+// validateExperiments checks experiments. IDs end up joined as
+// "<experiment>/<variant>" in audit events, so neither may contain "/".
+func validateExperiments(r *ValidationResult, cfg *types.ForgeConfig) {
+	seen := map[string]bool{}
+	for i, exp := range cfg.Experiments {
+		path := fmt.Sprintf("experiments[%d]", i)
+		switch {
+		case exp.ID == "":
+			r.Errors = append(r.Errors, path+".id is required")
+		case strings.Contains(exp.ID, "/"):
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.id %q must not contain \"/\"", path, exp.ID))
+		case seen[exp.ID]:
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: duplicate id %q", path, exp.ID))
+		}
+		seen[exp.ID] = true
+		if len(exp.Variants) < 2 {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.variants: an experiment needs at least two variants", path))
+		}
+		variants := map[string]bool{}
+		weights, bucketed := 0, false
+		for j, v := range exp.Variants {
+			vpath := fmt.Sprintf("%s.variants[%d]", path, j)
+			switch {
+			case v.ID == "":
+				r.Errors = append(r.Errors, vpath+".id is required")
+			case strings.Contains(v.ID, "/"):
+				r.Errors = append(r.Errors, fmt.Sprintf("%s.id %q must not contain \"/\"", vpath, v.ID))
+			case variants[v.ID]:
+				r.Errors = append(r.Errors, fmt.Sprintf("%s: duplicate id %q", vpath, v.ID))
+			}
+			variants[v.ID] = true
+			if v.Weight < 0 {
+				r.Errors = append(r.Errors, fmt.Sprintf("%s.weight must not be negative, got %d", vpath, v.Weight))
+			}
+			weights += max(v.Weight, 0)
+			if v.When == nil {
+				bucketed = true
+			}
+			if v.Model != nil {
+				provider := v.Model.Provider
+				if provider == "" {
+					provider = cfg.Model.Provider
+				}
+				if v.Model.Name == "" && provider == cfg.Model.Provider {
+					r.Errors = append(r.Errors, fmt.Sprintf("%s.model.name is required when the variant uses the primary provider", vpath))
+				}
+				validateReasoningEffort(r, vpath+".model", provider, v.Model.ReasoningEffort)
+			}
+		}
+		if weights > 0 && weights != 100 {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.variants: weights add up to %d, want 100", path, weights))
+		}
+		if weights == 0 && !bucketed && len(exp.Variants) > 0 {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: every variant has a when and none a weight; tasks matching no variant's when are not enrolled", path))
+		}
+	}
+}
+
 // ValidationResult holds errors and warnings from config validation.
 type ValidationResult struct {
 	Errors   []string
@@ -122,6 +360,43 @@ func ValidateForgeConfig(cfg *types.ForgeConfig) *ValidationResult {
 		r.Warnings = append(r.Warnings, `model.auth_header_name is set but auth_scheme is not "apikey_header" / "apikey_header_only"; it will be ignored`)
 	}
 
+	validateReasoningEffort(r, "model", cfg.Model.Provider, cfg.Model.ReasoningEffort)
+	switch tb := cfg.Model.ThinkingBudget; {
+	case tb < 0:
+		r.Errors = append(r.Errors, "model.thinking_budget must not be negative")
+	case tb > 0 && tb < 1024:
+		r.Errors = append(r.Errors, fmt.Sprintf("model.thinking_budget %d is below Anthropic's minimum of 1024 tokens", tb))
+	case tb > 0 && cfg.Model.Provider != "" && cfg.Model.Provider != "anthropic":
+		r.Warnings = append(r.Warnings, fmt.Sprintf("model.thinking_budget is set but provider %q does not support extended thinking; it will be ignored", cfg.Model.Provider))
+	}
+	if err := providers.ValidateOllamaKeepAlive(cfg.Model.KeepAlive); err != nil {
+		r.Errors = append(r.Errors, "model."+err.Error())
+	} else if cfg.Model.KeepAlive != "" && !usesOllama(cfg.Model) {
+		r.Warnings = append(r.Warnings, "model.keep_alive is set but neither the provider nor any fallback is ollama; it will be ignored")
+	}
+	for i, fb := range cfg.Model.Fallbacks {
+		validateReasoningEffort(r, fmt.Sprintf("model.fallbacks[%d]", i), fb.Provider, fb.ReasoningEffort)
+	}
+	if g := cfg.Model.Gemini; g != nil {
+		if err := providers.ValidateGeminiSafetySettings(g.SafetySettings); err != nil {
+			r.Errors = append(r.Errors, "model.gemini.safety_settings: "+err.Error())
+		} else if !usesGemini(cfg.Model) {
+			r.Warnings = append(r.Warnings, "model.gemini is set but no model in the chain is gemini; it will be ignored")
+		}
+	}
+	validateModelRoutes(r, cfg.Model)
+	validateExperiments(r, cfg)
+	if rc := cfg.Model.ResponseCache; rc != nil {
+		if rc.TTL < 0 {
+			r.Errors = append(r.Errors, "model.response_cache.ttl must not be negative")
+		}
+		if rc.MaxEntries < 0 {
+			r.Errors = append(r.Errors, "model.response_cache.max_entries must not be negative")
+		}
+	}
+
+	validateModelHTTP(r, cfg.Model.HTTP)
+
 	if cfg.Framework != "" && !knownFrameworks[cfg.Framework] {
 		r.Warnings = append(r.Warnings, fmt.Sprintf("unknown framework %q (known: forge, crewai, langchain)", cfg.Framework))
 	}
@@ -136,6 +411,7 @@ func ValidateForgeConfig(cfg *types.ForgeConfig) *ValidationResult {
 	if cfg.Egress.Mode == "dev-open" {
 		r.Warnings = append(r.Warnings, "egress mode 'dev-open' is not recommended for production")
 	}
+	validateEgressConstraints(r, cfg.Egress)
 
 	// Validate secrets config
 	for _, p := range cfg.Secrets.Providers {
@@ -143,6 +419,21 @@ func ValidateForgeConfig(cfg *types.ForgeConfig) *ValidationResult {
 			r.Warnings = append(r.Warnings, fmt.Sprintf("unknown secret provider %q (known: env, encrypted-file)", p))
 		}
 	}
+	for i, rcpt := range cfg.Secrets.Recipients {
+		if _, err := secrets.ParseRecipient(rcpt); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("secrets.recipients[%d]: %v", i, err))
+		}
+	}
+	if sc := cfg.Secrets.Sync; sc != nil {
+		if sc.From == "" {
+			r.Errors = append(r.Errors, "secrets.sync.from is required")
+		} else if _, err := remote.Parse(sc.From, nil); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("secrets.sync.from: %v", err))
+		}
+		if _, err := remote.ParseMappings(sc.Keys); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("secrets.sync.keys: %v", err))
+		}
+	}
 
 	// Validate schedules config
 	seenScheduleIDs := make(map[string]bool, len(cfg.Schedules))
@@ -162,19 +453,242 @@ func ValidateForgeConfig(cfg *types.ForgeConfig) *ValidationResult {
 		} else if _, err := scheduler.Parse(s.Cron); err != nil {
 			r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: invalid cron %q: %s", i, s.Cron, err))
 		}
+		if s.Timezone != "" {
+			if _, err := scheduler.LoadTimezone(s.Timezone); err != nil {
+				r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: %s", i, err))
+			}
+		}
+		if s.MaxRuntime < 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: max_runtime must not be negative", i))
+		}
+		if !scheduler.ValidOverlap(s.Overlap) {
+			r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: overlap %q must be one of skip, queue, cancel-previous", i, s.Overlap))
+		}
 
-		if s.Task == "" {
+		switch {
+		case s.Task == "" && s.Workflow == "":
 			r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: task is required", i))
+		case s.Task != "" && s.Workflow != "":
+			r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: set task or workflow, not both", i))
+		case s.Workflow != "":
+			if wf := cfg.Workflow(s.Workflow); wf == nil {
+				r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: unknown workflow %q", i, s.Workflow))
+			} else if len(wf.Inputs) > 0 {
+				r.Errors = append(r.Errors, fmt.Sprintf("schedules[%d]: workflow %q needs inputs %v, which a schedule cannot give", i, s.Workflow, wf.Inputs))
+			}
+		}
+	}
+
+	validateTriggers(cfg.Triggers, r)
+	for i, t := range cfg.Triggers {
+		if t.Workflow == "" {
+			continue
+		}
+		if wf := cfg.Workflow(t.Workflow); wf == nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: unknown workflow %q", i, t.Workflow))
+		} else {
+			for _, in := range wf.Inputs {
+				if !slices.Contains(triggerWorkflowInputs, in) {
+					r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: workflow %q needs input %q; a trigger gives %s", i, t.Workflow, in, strings.Join(triggerWorkflowInputs, ", ")))
+				}
+			}
+		}
+		if t.Task != "" {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: set task or workflow, not both", i))
+		}
+	}
+	validateWorkflows(cfg.Workflows, r)
+	validateTenants(cfg.Tenants, r)
+	validateLLMTraceExport("observability.langfuse", cfg.Observability.Langfuse, r)
+	validateLLMTraceExport("observability.langsmith", cfg.Observability.LangSmith, r)
+	if c := cfg.Observability.Captures; c.SamplePercent < 0 || c.SamplePercent > 100 {
+		r.Errors = append(r.Errors, fmt.Sprintf("observability.captures.sample_percent %g must be between 0 and 100", c.SamplePercent))
+	}
+	if cfg.Observability.Captures.MaxBytes < 0 {
+		r.Errors = append(r.Errors, "observability.captures.max_bytes must not be negative")
+	}
+
+	if c := cfg.Memory.Retention.GCSchedule; c != "" {
+		if _, err := scheduler.Parse(c); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("memory.retention.gc_schedule: invalid cron %q: %s", c, err))
 		}
 	}
 
+	validateClusterConfig(cfg, r)
+	if v := cfg.Security.BuildVerification; v != "" && v != "warn" && v != "enforce" {
+		r.Errors = append(r.Errors, fmt.Sprintf("security.build_verification %q must be one of: warn, enforce", v))
+	}
+	if err := cfg.Security.OPA.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if err := cfg.Server.Limits.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if err := cfg.ChannelMiddleware.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if err := cfg.Voice.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if err := cfg.Attachments.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if err := cfg.Localization.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if err := cfg.Notify.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if err := cfg.Handoff.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	if cfg.Mode != "" && !cfg.IsReadOnly() {
+		r.Errors = append(r.Errors, fmt.Sprintf("mode %q must be %q or unset", cfg.Mode, types.ModeReadOnly))
+	}
+	for i, s := range cfg.ReadOnly.SafeCommands {
+		if strings.TrimSpace(s) == "" {
+			r.Errors = append(r.Errors, fmt.Sprintf("read_only.safe_commands[%d] is empty", i))
+		}
+	}
+	if len(cfg.ToolPolicies) > 0 {
+		policies := make(map[string]tools.OriginPolicy, len(cfg.ToolPolicies))
+		for origin, p := range cfg.ToolPolicies {
+			policies[origin] = tools.OriginPolicy{Allow: p.Allow, Deny: p.Deny}
+		}
+		if err := tools.ValidateOriginPolicies(policies); err != nil {
+			r.Errors = append(r.Errors, err.Error())
+		}
+	}
+	for _, pat := range cfg.Sandbox.Tools {
+		if _, err := path.Match(pat, ""); err != nil || pat == "" {
+			r.Errors = append(r.Errors, fmt.Sprintf("sandbox.tools: invalid tool pattern %q", pat))
+		}
+	}
+	if cfg.Sandbox.KeepScratch && len(cfg.Sandbox.Tools) == 0 {
+		r.Warnings = append(r.Warnings, "sandbox.keep_scratch is set but sandbox.tools is empty")
+	}
+	if c := cfg.Retention.GCSchedule; c != "" {
+		if _, err := scheduler.Parse(c); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("retention.gc_schedule: invalid cron %q: %s", c, err))
+		}
+	}
+	if s := cfg.Retention.Sessions; (s.MaxAge != 0 || s.MaxBytes != 0) && cfg.Memory.SessionStore != "" && cfg.Memory.SessionStore != "file" {
+		r.Warnings = append(r.Warnings, "retention.sessions only applies to the file session store; memory.session_store is "+cfg.Memory.SessionStore)
+	}
+	if cfg.Loop.MaxIterations < 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("loop.max_iterations must not be negative, got %d", cfg.Loop.MaxIterations))
+	}
+	if cfg.Loop.RepeatLimit == 1 || cfg.Loop.OscillationLimit == 1 {
+		r.Errors = append(r.Errors, "loop.repeat_limit and loop.oscillation_limit must be at least 2: a limit of 1 stops every task at its first tool call")
+	}
+	switch cfg.Executor.Mode {
+	case "", types.ExecutorModeDirect, types.ExecutorModePlan:
+	default:
+		r.Errors = append(r.Errors, fmt.Sprintf("executor.mode must be %q or %q, got %q", types.ExecutorModeDirect, types.ExecutorModePlan, cfg.Executor.Mode))
+	}
+	if cfg.Executor.MaxSteps < 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("executor.max_steps must not be negative, got %d", cfg.Executor.MaxSteps))
+	}
+	if tz := cfg.RuntimeContext.Timezone; tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("runtime_context.timezone %q is not a known time zone", tz))
+		}
+	}
+	for k := range cfg.RuntimeContext.Facts {
+		if strings.TrimSpace(k) == "" || strings.ContainsAny(k, "\r\n") {
+			r.Errors = append(r.Errors, fmt.Sprintf("runtime_context.facts: key %q must be a non-empty single line", k))
+		}
+	}
+	if len(cfg.RuntimeContext.Facts) > 0 && !cfg.RuntimeContext.Enabled {
+		r.Warnings = append(r.Warnings, "runtime_context.facts is set but runtime_context.enabled is false")
+	}
+	if len(cfg.ReadOnly.SafeCommands) > 0 && !cfg.IsReadOnly() {
+		r.Warnings = append(r.Warnings, "read_only.safe_commands is set but mode is not read-only")
+	}
+	if err := cfg.Alerts.Validate(); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+	for _, rule := range cfg.Alerts.Rules {
+		if _, ok := cfg.Notify.Targets[rule.Notify]; rule.Notify != "" && !ok {
+			r.Errors = append(r.Errors, fmt.Sprintf("alerts.rules.%s: notify target %q is not declared in notify.targets", rule.ID, rule.Notify))
+		}
+	}
 	ValidateAuthConfig(cfg.Auth, r)
 	ValidateMCPConfig(cfg.MCP, r)
 	validateStandaloneDelegatedConsent(cfg, r)
+	validateProfiles(cfg, r)
 
 	return r
 }
 
+// validateProfiles checks each profiles entry by merging it onto a copy
+// of the config and re-checking the sections it may override.
+func validateProfiles(cfg *types.ForgeConfig, r *ValidationResult) {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if !kebabCasePattern.MatchString(name) {
+			r.Errors = append(r.Errors, fmt.Sprintf("profiles: name %q must be kebab-case (e.g. prod, eu-staging)", name))
+			continue
+		}
+		applied := *cfg
+		if rc := cfg.Model.ResponseCache; rc != nil {
+			// The merge decodes into the pointee; keep cfg's untouched.
+			c := *rc
+			applied.Model.ResponseCache = &c
+		}
+		if h := cfg.Model.HTTP; h != nil {
+			c := *h
+			applied.Model.HTTP = &c
+		}
+		if err := applied.ApplyProfile(name); err != nil {
+			r.Errors = append(r.Errors, err.Error())
+			continue
+		}
+		if applied.Egress.Profile != "" && !knownEgressProfiles[applied.Egress.Profile] {
+			r.Errors = append(r.Errors, fmt.Sprintf("profiles.%s: egress.profile %q must be one of: strict, standard, permissive", name, applied.Egress.Profile))
+		}
+		if applied.Egress.Mode != "" && !knownEgressModes[applied.Egress.Mode] {
+			r.Errors = append(r.Errors, fmt.Sprintf("profiles.%s: egress.mode %q must be one of: deny-all, allowlist, dev-open", name, applied.Egress.Mode))
+		}
+		for _, p := range applied.Secrets.Providers {
+			if !knownSecretProviders[p] {
+				r.Warnings = append(r.Warnings, fmt.Sprintf("profiles.%s: unknown secret provider %q (known: env, encrypted-file)", name, p))
+			}
+		}
+		if applied.Model.Provider != "" && applied.Model.Name == "" {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("profiles.%s: model.provider is set but model.name is empty", name))
+		}
+	}
+}
+
+// validateClusterConfig checks the cluster block. The lock URL is only
+// checked for a known scheme; reachability is a startup concern.
+func validateClusterConfig(cfg *types.ForgeConfig, r *ValidationResult) {
+	c := cfg.Cluster
+	if c.LockURL != "" {
+		u, err := url.Parse(c.LockURL)
+		switch {
+		case err != nil:
+			r.Errors = append(r.Errors, fmt.Sprintf("cluster.lock_url: %s", err))
+		case u.Scheme != "redis" && u.Scheme != "rediss" && u.Scheme != "memory":
+			r.Errors = append(r.Errors, fmt.Sprintf("cluster.lock_url scheme %q must be one of: redis, rediss, memory", u.Scheme))
+		case u.Scheme == "memory":
+			r.Warnings = append(r.Warnings, "cluster.lock_url memory:// only coordinates within one process; use redis:// across replicas")
+		}
+	}
+	if c.LockTTL != "" {
+		if d, err := time.ParseDuration(c.LockTTL); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("cluster.lock_ttl %q is not a valid duration", c.LockTTL))
+		} else if d < 3*time.Second {
+			r.Errors = append(r.Errors, fmt.Sprintf("cluster.lock_ttl %q must be at least 3s", c.LockTTL))
+		}
+	}
+}
+
 // validateStandaloneDelegatedConsent enforces the standalone (#332) rules for
 // auth.type=user servers that have NO platform block: without a platform token
 // endpoint, Forge runs the per-user OAuth itself, which needs explicit
@@ -199,3 +713,194 @@ func validateStandaloneDelegatedConsent(cfg *types.ForgeConfig, r *ValidationRes
 		}
 	}
 }
+
+// triggerPathPattern restricts webhook routes to plain path segments,
+// so a trigger cannot register a wildcard or method-qualified pattern.
+var triggerPathPattern = regexp.MustCompile(`^/hooks(/[A-Za-z0-9._-]+)+$`)
+
+// validateTriggers checks the webhook triggers: unique kebab-case IDs,
+// distinct routes under /hooks/, a secret to verify signatures with,
+// and task templates that parse.
+// triggerWorkflowInputs are the inputs a webhook trigger gives the
+// workflow it runs.
+var triggerWorkflowInputs = []string{"trigger", "payload", "body", "headers"}
+
+// validateWorkflows checks the declared workflows: kebab-case unique IDs,
+// steps that each do exactly one thing, and templates that parse.
+func validateWorkflows(workflows []types.WorkflowConfig, r *ValidationResult) {
+	seenIDs := make(map[string]bool, len(workflows))
+	for i, wf := range workflows {
+		where := fmt.Sprintf("workflows[%d]", i)
+		if wf.ID == "" {
+			r.Errors = append(r.Errors, where+": id is required")
+		} else if !kebabCasePattern.MatchString(wf.ID) {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: id %q must be kebab-case", where, wf.ID))
+		} else if seenIDs[wf.ID] {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: duplicate id %q", where, wf.ID))
+		}
+		seenIDs[wf.ID] = true
+
+		if len(wf.Steps) == 0 {
+			r.Errors = append(r.Errors, where+": steps is required")
+		}
+		if wf.Timeout < 0 {
+			r.Errors = append(r.Errors, where+": timeout must not be negative")
+		}
+		seenSteps := map[string]bool{}
+		for j, step := range wf.Steps {
+			validateWorkflowStep(fmt.Sprintf("%s.steps[%d]", where, j), step, true, seenSteps, r)
+		}
+		if wf.Output != "" {
+			if err := workflow.Parse("output", wf.Output); err != nil {
+				r.Errors = append(r.Errors, fmt.Sprintf("%s.output: %s", where, err))
+			}
+		}
+	}
+}
+
+// validateLLMTraceExport checks a Langfuse / LangSmith exporter block:
+// the endpoint, when set, must be an http(s) URL. project only means
+// something to LangSmith.
+func validateLLMTraceExport(where string, c types.LLMTraceExportYAML, r *ValidationResult) {
+	if c.Endpoint != "" {
+		if u, err := url.Parse(c.Endpoint); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.endpoint %q must be an http or https URL", where, c.Endpoint))
+		}
+	}
+	if c.Project != "" && where == "observability.langfuse" {
+		r.Warnings = append(r.Warnings, where+".project is ignored: a Langfuse project is selected by its API keys")
+	}
+}
+
+// validateTenants checks the declared tenants: kebab-case unique IDs,
+// at least one API key each, and no key variable shared between two
+// tenants, which would let one act as the other.
+func validateTenants(tenants []types.TenantConfig, r *ValidationResult) {
+	seenIDs := make(map[string]bool, len(tenants))
+	keyOwner := map[string]string{}
+	for i, t := range tenants {
+		where := fmt.Sprintf("tenants[%d]", i)
+		if t.ID == "" {
+			r.Errors = append(r.Errors, where+": id is required")
+		} else if !kebabCasePattern.MatchString(t.ID) {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: id %q must be kebab-case", where, t.ID))
+		} else if seenIDs[t.ID] {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: duplicate id %q", where, t.ID))
+		}
+		seenIDs[t.ID] = true
+
+		if len(t.APIKeysEnv) == 0 {
+			r.Errors = append(r.Errors, where+": api_keys_env is required")
+		}
+		for _, env := range t.APIKeysEnv {
+			if owner, ok := keyOwner[env]; ok && owner != t.ID {
+				r.Errors = append(r.Errors, fmt.Sprintf("%s: api key %s is also tenant %q's", where, env, owner))
+			}
+			keyOwner[env] = t.ID
+		}
+		if t.RateLimit.RPS < 0 || t.RateLimit.Burst < 0 {
+			r.Errors = append(r.Errors, where+": rate_limit must not be negative")
+		}
+	}
+}
+
+func validateWorkflowStep(where string, step types.WorkflowStep, top bool, seen map[string]bool, r *ValidationResult) {
+	if step.ID == "" {
+		r.Errors = append(r.Errors, where+": id is required")
+	} else if seen[step.ID] {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: duplicate step id %q", where, step.ID))
+	}
+	seen[step.ID] = true
+
+	kinds := 0
+	for _, set := range []bool{step.Tool != "", step.Prompt != "", len(step.Parallel) > 0} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		r.Errors = append(r.Errors, where+": set exactly one of tool, prompt or parallel")
+	}
+	if step.Tool == workflow.RunWorkflowTool {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: a workflow cannot call %s", where, workflow.RunWorkflowTool))
+	}
+	if len(step.Args) > 0 && step.Tool == "" {
+		r.Errors = append(r.Errors, where+": args needs a tool")
+	}
+	if step.OnError != "" && step.OnError != types.WorkflowOnErrorFail && step.OnError != types.WorkflowOnErrorContinue {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: on_error %q must be one of fail, continue", where, step.OnError))
+	}
+
+	templates := map[string]string{"if": step.If, "prompt": step.Prompt}
+	collectArgTemplates("args", step.Args, templates)
+	for field, src := range templates {
+		if src == "" {
+			continue
+		}
+		if err := workflow.Parse(field, src); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s.%s: %s", where, field, err))
+		}
+	}
+
+	if len(step.Parallel) > 0 && !top {
+		r.Errors = append(r.Errors, where+": parallel steps cannot nest")
+		return
+	}
+	for k, sub := range step.Parallel {
+		validateWorkflowStep(fmt.Sprintf("%s.parallel[%d]", where, k), sub, false, seen, r)
+	}
+}
+
+// collectArgTemplates gathers the string values of a step's arguments,
+// keyed by their path, walking nested maps and lists.
+func collectArgTemplates(path string, v any, out map[string]string) {
+	switch v := v.(type) {
+	case string:
+		out[path] = v
+	case map[string]any:
+		for k, item := range v {
+			collectArgTemplates(path+"."+k, item, out)
+		}
+	case []any:
+		for i, item := range v {
+			collectArgTemplates(fmt.Sprintf("%s[%d]", path, i), item, out)
+		}
+	}
+}
+
+func validateTriggers(triggers []types.TriggerConfig, r *ValidationResult) {
+	seenIDs := make(map[string]bool, len(triggers))
+	seenRoutes := make(map[string]bool, len(triggers))
+	for i, t := range triggers {
+		if t.ID == "" {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: id is required", i))
+			continue
+		}
+		if !kebabCasePattern.MatchString(t.ID) {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: id %q must be kebab-case", i, t.ID))
+		} else if seenIDs[t.ID] {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: duplicate id %q", i, t.ID))
+		}
+		seenIDs[t.ID] = true
+
+		route := t.Route()
+		if !triggerPathPattern.MatchString(route) {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: path %q must be under %s", i, route, types.TriggerPathPrefix))
+		} else if seenRoutes[route] {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: path %q is used by another trigger", i, route))
+		}
+		seenRoutes[route] = true
+
+		if t.SecretEnv == "" {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: secret_env is required to verify webhook signatures", i))
+		}
+		if t.Task != "" {
+			if _, err := template.New(t.ID).Parse(t.Task); err != nil {
+				r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d].task: %s", i, err))
+			}
+		}
+		if (t.Channel == "") != (t.ChannelTarget == "") {
+			r.Errors = append(r.Errors, fmt.Sprintf("triggers[%d]: channel and channel_target must be set together", i))
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/schemas"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	compiledForgeConfigSchema *gojsonschema.Schema
+	forgeConfigSchemaOnce     sync.Once
+	forgeConfigSchemaErr      error
+)
+
+func getForgeConfigSchema() (*gojsonschema.Schema, error) {
+	forgeConfigSchemaOnce.Do(func() {
+		loader := gojsonschema.NewBytesLoader(schemas.ForgeConfigV1Schema)
+		compiledForgeConfigSchema, forgeConfigSchemaErr = gojsonschema.NewSchema(loader)
+	})
+	return compiledForgeConfigSchema, forgeConfigSchemaErr
+}
+
+// schemaIndexPattern matches the ".N" array segments gojsonschema emits in
+// field paths so they can be rendered in the "[N]" style the semantic
+// validator uses (mcp.servers[0].url).
+var schemaIndexPattern = regexp.MustCompile(`\.(\d+)`)
+
+// SchemaIssue is one forge.yaml schema violation.
+type SchemaIssue struct {
+	// Field is the path of the offending value (mcp.servers[0].url), or
+	// "(root)" for the document itself.
+	Field string
+	// Line and Column locate the value (for an unknown field, its key)
+	// in the file, 1-based; zero when the position is not known.
+	Line, Column int
+	Message      string
+	// Unknown marks a key the schema does not define. Every other issue
+	// is a type, enum or range violation.
+	Unknown bool
+}
+
+// String renders the issue as "field: message"; unknown top-level keys
+// have no field prefix.
+func (i SchemaIssue) String() string {
+	if i.Unknown && i.Field == "(root)" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// CheckForgeConfigSchema validates raw forge.yaml bytes against the
+// ForgeConfig JSON Schema and returns every violation with its position
+// in the file. The error is non-nil only when the YAML cannot be parsed
+// or the schema fails to compile.
+func CheckForgeConfigSchema(data []byte) ([]SchemaIssue, error) {
+	schema, err := getForgeConfigSchema()
+	if err != nil {
+		return nil, fmt.Errorf("compiling forge config schema: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("parsing forge config: %w", err)
+	}
+	var doc any
+	if err := node.Decode(&doc); err != nil && node.Kind != 0 {
+		return nil, fmt.Errorf("parsing forge config: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	jsonData, err := json.Marshal(normalizeYAML(doc))
+	if err != nil {
+		return nil, fmt.Errorf("converting forge config to json: %w", err)
+	}
+
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("validating forge config: %w", err)
+	}
+
+	issues := make([]SchemaIssue, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		issue := SchemaIssue{
+			Field:   schemaIndexPattern.ReplaceAllString(e.Field(), "[$1]"),
+			Message: e.Description(),
+		}
+		path := e.Field()
+		if e.Type() == "additional_property_not_allowed" {
+			prop, _ := e.Details()["property"].(string)
+			issue.Unknown = true
+			issue.Message = fmt.Sprintf("unknown field %q", prop)
+			path += "." + prop
+		}
+		if n := yamlNodeAt(&node, path, issue.Unknown); n != nil {
+			issue.Line, issue.Column = n.Line, n.Column
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// ValidateForgeConfigSchema validates raw forge.yaml bytes against the
+// ForgeConfig JSON Schema. It complements ValidateForgeConfig: the typed
+// parser silently drops keys it does not know, so a misspelled field
+// (`egres:`) or a value out of range would otherwise go unnoticed.
+//
+// Unknown keys are reported as warnings — the agent still runs with them
+// — while type and range violations are errors. Each message ends with
+// the line and column of the offending value. The returned error is
+// non-nil only when the YAML cannot be parsed or the schema fails to
+// compile.
+func ValidateForgeConfigSchema(data []byte) (*ValidationResult, error) {
+	issues, err := CheckForgeConfigSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	r := &ValidationResult{}
+	for _, i := range issues {
+		msg := i.String()
+		if i.Line > 0 {
+			msg += fmt.Sprintf(" (line %d, column %d)", i.Line, i.Column)
+		}
+		if i.Unknown {
+			r.Warnings = append(r.Warnings, msg)
+		} else {
+			r.Errors = append(r.Errors, msg)
+		}
+	}
+	return r, nil
+}
+
+// yamlNodeAt resolves a gojsonschema field path ("(root)",
+// "mcp.servers.0.url") against the parsed document. With key set it
+// returns the final mapping key instead of its value. A path that runs
+// out resolves to the deepest node reached.
+func yamlNodeAt(doc *yaml.Node, path string, key bool) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	n := doc.Content[0]
+	segs := strings.Split(strings.TrimPrefix(strings.TrimPrefix(path, "(root)"), "."), ".")
+	if path == "(root)" {
+		segs = nil
+	}
+	for i, seg := range segs {
+		switch n.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for j := 0; j+1 < len(n.Content); j += 2 {
+				if n.Content[j].Value == seg {
+					next = n.Content[j+1]
+					if key && i == len(segs)-1 {
+						next = n.Content[j]
+					}
+					break
+				}
+			}
+			if next == nil {
+				return n
+			}
+			n = next
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(n.Content) {
+				return n
+			}
+			n = n.Content[idx]
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// normalizeYAML converts the map[interface{}]interface{} nodes yaml.v3
+// produces for non-string keys into map[string]any so the document can
+// be marshalled to JSON.
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			t[k] = normalizeYAML(val)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(val)
+		}
+		return m
+	case []any:
+		for i, val := range t {
+			t[i] = normalizeYAML(val)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,205 @@
+package validate
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/schemas"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestValidateForgeConfigSchema_Valid(t *testing.T) {
+	yml := `
+agent_id: test-agent
+version: 0.1.0
+framework: forge
+model:
+  provider: openai
+  name: gpt-4o
+egress:
+  mode: allowlist
+  allowed_domains: [api.openai.com]
+memory:
+  trigger_ratio: 0.6
+mcp:
+  servers:
+    - name: jira
+      url: https://mcp.example.com
+      timeout: 30s
+`
+	r, err := ValidateForgeConfigSchema([]byte(yml))
+	if err != nil {
+		t.Fatalf("ValidateForgeConfigSchema: %v", err)
+	}
+	if len(r.Errors) > 0 || len(r.Warnings) > 0 {
+		t.Errorf("expected clean result, got errors=%v warnings=%v", r.Errors, r.Warnings)
+	}
+}
+
+func TestValidateForgeConfigSchema_UnknownTopLevelFieldWarns(t *testing.T) {
+	r, err := ValidateForgeConfigSchema([]byte("agent_id: a\nversion: 0.1.0\negres:\n  mode: allowlist\n"))
+	if err != nil {
+		t.Fatalf("ValidateForgeConfigSchema: %v", err)
+	}
+	if len(r.Errors) != 0 {
+		t.Errorf("unknown field must not be an error, got %v", r.Errors)
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], `"egres"`) {
+		t.Errorf("expected unknown-field warning for egres, got %v", r.Warnings)
+	}
+}
+
+func TestValidateForgeConfigSchema_TypeAndRangeErrors(t *testing.T) {
+	yml := `
+agent_id: a
+version: 0.1.0
+builtin_tools: web_search
+memory:
+  trigger_ratio: 1.5
+mcp:
+  servers:
+    - name: jira
+      required: "yes"
+`
+	r, err := ValidateForgeConfigSchema([]byte(yml))
+	if err != nil {
+		t.Fatalf("ValidateForgeConfigSchema: %v", err)
+	}
+	want := []string{"builtin_tools", "memory.trigger_ratio", "mcp.servers[0].required"}
+	for _, w := range want {
+		found := false
+		for _, e := range r.Errors {
+			if strings.HasPrefix(e, w+":") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected error for %s, got %v", w, r.Errors)
+		}
+	}
+}
+
+func TestValidateForgeConfigSchema_EmptyDocument(t *testing.T) {
+	r, err := ValidateForgeConfigSchema(nil)
+	if err != nil {
+		t.Fatalf("ValidateForgeConfigSchema: %v", err)
+	}
+	if !r.IsValid() {
+		t.Errorf("empty document should pass the schema, got %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfigSchema_MalformedYAML(t *testing.T) {
+	if _, err := ValidateForgeConfigSchema([]byte("agent_id: [unterminated")); err == nil {
+		t.Error("expected parse error for malformed yaml")
+	}
+}
+
+// TestForgeConfigSchema_CoversTopLevelFields keeps the schema in lockstep
+// with types.ForgeConfig: a field added to the struct but not the schema
+// would surface as a spurious "unknown field" warning in the UI editor.
+func TestForgeConfigSchema_CoversTopLevelFields(t *testing.T) {
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(schemas.ForgeConfigV1Schema, &schema); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+
+	typ := reflect.TypeOf(types.ForgeConfig{})
+	for i := 0; i < typ.NumField(); i++ {
+		tag := strings.Split(typ.Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if _, ok := schema.Properties[tag]; !ok {
+			t.Errorf("forge.yaml field %q (ForgeConfig.%s) missing from forgeconfig.v1.schema.json", tag, typ.Field(i).Name)
+		}
+	}
+}
+
+func TestCheckForgeConfigSchema_Positions(t *testing.T) {
+	yml := `agent_id: a
+version: 0.1.0
+egres:
+  mode: allowlist
+egress:
+  mode: sometimes
+mcp:
+  servers:
+    - name: jira
+      required: "yes"
+`
+	issues, err := CheckForgeConfigSchema([]byte(yml))
+	if err != nil {
+		t.Fatalf("CheckForgeConfigSchema: %v", err)
+	}
+	want := map[string][2]int{
+		`unknown field "egres"`:   {3, 1},
+		"egress.mode":             {6, 9},
+		"mcp.servers[0].required": {10, 17},
+	}
+	for _, i := range issues {
+		key := i.Field
+		if i.Unknown {
+			key = i.String()
+		}
+		if pos, ok := want[key]; ok {
+			if i.Line != pos[0] || i.Column != pos[1] {
+				t.Errorf("%s at %d:%d, want %d:%d", key, i.Line, i.Column, pos[0], pos[1])
+			}
+			delete(want, key)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("missing issues %v in %+v", want, issues)
+	}
+}
+
+func TestForgeConfigSchema_VersionMatchesTypes(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			SchemaVersion struct {
+				Maximum int `json:"maximum"`
+			} `json:"schema_version"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schemas.ForgeConfigV1Schema, &schema); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+	if schema.Properties.SchemaVersion.Maximum != types.ForgeConfigSchemaVersion {
+		t.Errorf("schema allows schema_version up to %d, types.ForgeConfigSchemaVersion is %d",
+			schema.Properties.SchemaVersion.Maximum, types.ForgeConfigSchemaVersion)
+	}
+}
+
+func TestValidateForgeConfigSchema_Profiles(t *testing.T) {
+	r, err := ValidateForgeConfigSchema([]byte(`
+agent_id: a
+version: 0.1.0
+profiles:
+  prod:
+    model: {name: gpt-4o}
+    egress: {mode: bogus}
+    enforce_guardrails: "yes"
+    memory: {}
+`))
+	if err != nil {
+		t.Fatalf("ValidateForgeConfigSchema: %v", err)
+	}
+	for _, w := range []string{"profiles.prod.egress.mode:", "profiles.prod.enforce_guardrails:"} {
+		found := false
+		for _, e := range r.Errors {
+			found = found || strings.HasPrefix(e, w)
+		}
+		if !found {
+			t.Errorf("expected error for %s, got %v", w, r.Errors)
+		}
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], `"memory"`) {
+		t.Errorf("expected unknown-field warning for profiles.prod.memory, got %v", r.Warnings)
+	}
+}
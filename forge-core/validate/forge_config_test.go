@@ -3,6 +3,7 @@ package validate
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/initializ/forge/forge-core/types"
 )
@@ -191,6 +192,179 @@ func TestValidateForgeConfig_UnknownFramework(t *testing.T) {
 	}
 }
 
+func TestValidateForgeConfig_Mode(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mode = types.ModeReadOnly
+	cfg.ReadOnly.SafeCommands = []string{"kubectl get"}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() || len(r.Warnings) != 0 {
+		t.Fatalf("read-only: errors %v, warnings %v", r.Errors, r.Warnings)
+	}
+
+	cfg.Mode = "readonly"
+	cfg.ReadOnly.SafeCommands = []string{"  "}
+	r := ValidateForgeConfig(cfg)
+	if !hasSubstr(r.Errors, `mode "readonly"`) || !hasSubstr(r.Errors, "read_only.safe_commands[0] is empty") {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	if !hasSubstr(r.Warnings, "mode is not read-only") {
+		t.Errorf("warnings = %v", r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_ToolPolicies(t *testing.T) {
+	cfg := validConfig()
+	cfg.ToolPolicies = map[string]types.ToolPolicy{"channel": {Allow: []string{"web_search"}}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("errors: %v", r.Errors)
+	}
+	cfg.ToolPolicies = map[string]types.ToolPolicy{"slack": {Deny: []string{"cli_execute"}}}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, `unknown origin "slack"`) {
+		t.Errorf("errors = %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_Sandbox(t *testing.T) {
+	cfg := validConfig()
+	cfg.Sandbox = types.SandboxConfig{Tools: []string{"file_*", "[bad"}}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, `sandbox.tools: invalid tool pattern "[bad"`) {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Sandbox = types.SandboxConfig{KeepScratch: true}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Warnings, "sandbox.tools is empty") {
+		t.Errorf("warnings = %v", r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_Retention(t *testing.T) {
+	cfg := validConfig()
+	cfg.Retention = types.RetentionConfig{GCSchedule: "every hour"}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, `retention.gc_schedule: invalid cron "every hour"`) {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Retention = types.RetentionConfig{Sessions: types.RetentionPolicy{MaxAge: time.Hour}}
+	cfg.Memory.SessionStore = "remote"
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Warnings, "retention.sessions only applies to the file session store") {
+		t.Errorf("warnings = %v", r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_Loop(t *testing.T) {
+	cfg := validConfig()
+	cfg.Loop = types.LoopConfig{MaxIterations: -1}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, "loop.max_iterations must not be negative") {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Loop = types.LoopConfig{RepeatLimit: 1}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, "must be at least 2") {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Loop = types.LoopConfig{MaxIterations: 20, RepeatLimit: -1, OscillationLimit: 4}
+	if r := ValidateForgeConfig(cfg); len(r.Errors) != 0 {
+		t.Errorf("errors = %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_Executor(t *testing.T) {
+	cfg := validConfig()
+	cfg.Executor = types.ExecutorConfig{Mode: "planned"}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, `executor.mode must be "direct" or "plan"`) {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Executor = types.ExecutorConfig{Mode: types.ExecutorModePlan, MaxSteps: 5, MaxReplans: -1}
+	if r := ValidateForgeConfig(cfg); len(r.Errors) != 0 {
+		t.Errorf("errors = %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_Workflows(t *testing.T) {
+	cfg := validConfig()
+	cfg.Workflows = []types.WorkflowConfig{
+		{ID: "digest", Steps: []types.WorkflowStep{
+			{ID: "fetch", Tool: "http_request", Args: map[string]any{"url": "{{.Inputs.url}}"}},
+			{ID: "fan", Parallel: []types.WorkflowStep{{ID: "a", Prompt: "{{json .Steps.fetch.JSON}}"}}},
+		}},
+		{ID: "Bad_ID", Steps: []types.WorkflowStep{
+			{ID: "both", Tool: "x", Prompt: "y"},
+			{ID: "both", Tool: "run_workflow", OnError: "retry"},
+			{ID: "tmpl", Prompt: "{{.Inputs.x"},
+			{ID: "nest", Parallel: []types.WorkflowStep{{ID: "inner", Parallel: []types.WorkflowStep{{ID: "z", Tool: "x"}}}}},
+		}},
+	}
+	cfg.Schedules = []types.ScheduleConfig{
+		{ID: "nightly", Cron: "@daily", Workflow: "digest"},
+		{ID: "missing", Cron: "@daily", Workflow: "nope"},
+		{ID: "both", Cron: "@daily", Workflow: "digest", Task: "hi"},
+	}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{
+		`workflows[1]: id "Bad_ID" must be kebab-case`,
+		"workflows[1].steps[0]: set exactly one of tool, prompt or parallel",
+		`workflows[1].steps[1]: duplicate step id "both"`,
+		"workflows[1].steps[1]: a workflow cannot call run_workflow",
+		`workflows[1].steps[1]: on_error "retry" must be one of fail, continue`,
+		"workflows[1].steps[2].prompt:",
+		"workflows[1].steps[3].parallel[0]: parallel steps cannot nest",
+		`schedules[1]: unknown workflow "nope"`,
+		"schedules[2]: set task or workflow, not both",
+	} {
+		if !hasSubstr(r.Errors, want) {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+	if hasSubstr(r.Errors, "workflows[0]") || hasSubstr(r.Errors, "schedules[0]") {
+		t.Errorf("valid workflow or schedule rejected: %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_Tenants(t *testing.T) {
+	cfg := validConfig()
+	cfg.Tenants = []types.TenantConfig{
+		{ID: "acme", APIKeysEnv: []string{"ACME_KEY", "ACME_KEY_NEXT"}, RateLimit: types.TenantRateLimit{RPS: 5}},
+		{ID: "Globex"},
+		{ID: "acme", APIKeysEnv: []string{"ACME_KEY"}, RateLimit: types.TenantRateLimit{Burst: -1}},
+	}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{
+		`tenants[1]: id "Globex" must be kebab-case`,
+		"tenants[1]: api_keys_env is required",
+		`tenants[2]: duplicate id "acme"`,
+		"tenants[2]: rate_limit must not be negative",
+	} {
+		if !hasSubstr(r.Errors, want) {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+	if hasSubstr(r.Errors, "tenants[0]") {
+		t.Errorf("valid tenant rejected: %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_ThinkingBudget(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.Provider = "anthropic"
+	cfg.Model.ThinkingBudget = 512
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, "below Anthropic's minimum") {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Model.Provider, cfg.Model.ThinkingBudget = "openai", 4096
+	if r := ValidateForgeConfig(cfg); !r.IsValid() || !hasSubstr(r.Warnings, "model.thinking_budget is set but provider \"openai\"") {
+		t.Errorf("errors = %v, warnings = %v", r.Errors, r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_LLMTraceExport(t *testing.T) {
+	cfg := validConfig()
+	cfg.Observability.Langfuse = types.LLMTraceExportYAML{Enabled: true, Endpoint: "https://langfuse.internal", Project: "support"}
+	cfg.Observability.LangSmith = types.LLMTraceExportYAML{Enabled: true, Endpoint: "api.smith.langchain.com"}
+	r := ValidateForgeConfig(cfg)
+	if !hasSubstr(r.Errors, `observability.langsmith.endpoint "api.smith.langchain.com" must be an http or https URL`) || len(r.Errors) != 1 {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	if !hasSubstr(r.Warnings, "observability.langfuse.project is ignored") {
+		t.Errorf("warnings = %v", r.Warnings)
+	}
+}
+
 func TestValidateForgeConfig_OrgIDOnNonOpenAI(t *testing.T) {
 	cfg := validConfig()
 	cfg.Model.Provider = "anthropic"
@@ -225,3 +399,503 @@ func TestValidateForgeConfig_OrgIDOnOpenAI(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateForgeConfig_ReasoningEffort(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.Provider = "xai"
+	cfg.Model.Name = "grok-3-mini"
+	cfg.Model.ReasoningEffort = "high"
+	cfg.Model.Fallbacks = []types.ModelFallback{
+		{Provider: "deepseek", Name: "deepseek-reasoner", ReasoningEffort: "high"},
+		{Provider: "xai", ReasoningEffort: "medium"},
+	}
+	r := ValidateForgeConfig(cfg)
+	if !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	joined := strings.Join(r.Warnings, "\n")
+	if strings.Contains(joined, "model.reasoning_effort") {
+		t.Errorf("unexpected warning for a supported effort: %v", r.Warnings)
+	}
+	if !strings.Contains(joined, `model.fallbacks[0].reasoning_effort is set but provider "deepseek" does not support it`) {
+		t.Errorf("missing deepseek warning: %v", r.Warnings)
+	}
+	if !strings.Contains(joined, `model.fallbacks[1].reasoning_effort "medium" is not supported by provider "xai" (supported: low, high)`) {
+		t.Errorf("missing unsupported-value warning: %v", r.Warnings)
+	}
+
+	cfg.Model.ReasoningEffort = "extreme"
+	r = ValidateForgeConfig(cfg)
+	if r.IsValid() {
+		t.Error("expected error for unknown reasoning_effort")
+	}
+}
+
+func TestValidateForgeConfig_ModelRoutes(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.Provider = "openai"
+	cfg.Model.Name = "gpt-4o"
+	cfg.Model.Routes = []types.ModelRoute{
+		{ID: "cheap", When: types.RouteCondition{Tags: []string{"cheap"}}, Name: "gpt-4o-mini"},
+		{When: types.RouteCondition{MinInputTokens: 50000}, Provider: "gemini"},
+	}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() || len(r.Warnings) != 0 {
+		t.Errorf("valid routes: errors %v, warnings %v", r.Errors, r.Warnings)
+	}
+
+	cfg.Model.Routes = []types.ModelRoute{
+		{ID: "a", When: types.RouteCondition{MaxInputTokens: 10}},
+		{ID: "a", When: types.RouteCondition{MinInputTokens: 500, MaxInputTokens: 100}, Name: "gpt-4o-mini"},
+	}
+	r := ValidateForgeConfig(cfg)
+	if len(r.Errors) != 3 {
+		t.Errorf("expected missing name, duplicate id and inverted bounds, got %v", r.Errors)
+	}
+
+	cfg.Model.Routes = []types.ModelRoute{{Name: "gpt-4o-mini"}}
+	r = ValidateForgeConfig(cfg)
+	if !r.IsValid() || len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "matches every call") {
+		t.Errorf("empty when: errors %v, warnings %v", r.Errors, r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_ResponseCache(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.ResponseCache = &types.ResponseCacheConfig{Enabled: true, TTL: 24 * time.Hour, Dir: ".forge/llm-cache"}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid cache: %v", r.Errors)
+	}
+	cfg.Model.ResponseCache = &types.ResponseCacheConfig{Enabled: true, TTL: -time.Minute, MaxEntries: -1}
+	if r := ValidateForgeConfig(cfg); len(r.Errors) != 2 {
+		t.Errorf("expected ttl and max_entries errors, got %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_OllamaKeepAlive(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.Provider = "ollama"
+	cfg.Model.Name = "llama3.2"
+	for _, v := range []string{"30m", "-1", "0", "3600"} {
+		cfg.Model.KeepAlive = v
+		if r := ValidateForgeConfig(cfg); !r.IsValid() || len(r.Warnings) != 0 {
+			t.Errorf("keep_alive %q: errors %v, warnings %v", v, r.Errors, r.Warnings)
+		}
+	}
+
+	cfg.Model.KeepAlive = "forever"
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("expected error for unparsable keep_alive")
+	}
+
+	cfg.Model.Provider = "openai"
+	cfg.Model.KeepAlive = "10m"
+	r := ValidateForgeConfig(cfg)
+	if !r.IsValid() || len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "keep_alive") {
+		t.Errorf("non-ollama keep_alive: errors %v, warnings %v", r.Errors, r.Warnings)
+	}
+	cfg.Model.Fallbacks = []types.ModelFallback{{Provider: "ollama"}}
+	if r := ValidateForgeConfig(cfg); len(r.Warnings) != 0 {
+		t.Errorf("ollama fallback should silence the warning: %v", r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_Cluster(t *testing.T) {
+	cfg := validConfig()
+	cfg.Cluster = types.ClusterConfig{LockURL: "rediss://:pw@redis:6380/1", LockTTL: "20s"}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() || len(r.Warnings) != 0 {
+		t.Errorf("valid cluster: errors %v, warnings %v", r.Errors, r.Warnings)
+	}
+	cfg.Cluster = types.ClusterConfig{LockURL: "postgres://db/forge", LockTTL: "1s"}
+	if r := ValidateForgeConfig(cfg); len(r.Errors) != 2 {
+		t.Errorf("expected lock_url and lock_ttl errors, got %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_BuildVerification(t *testing.T) {
+	cfg := validConfig()
+	cfg.Security.BuildVerification = "enforce"
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("enforce rejected: %v", r.Errors)
+	}
+	cfg.Security.BuildVerification = "strict"
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("build_verification \"strict\" accepted")
+	}
+}
+
+func TestValidateForgeConfig_OPA(t *testing.T) {
+	cfg := validConfig()
+	cfg.Security.OPA = types.OPAConfig{URL: "http://opa:8181", Points: []string{"tool", "egress"}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid opa config rejected: %v", r.Errors)
+	}
+	cfg.Security.OPA.PolicyPath = "policies"
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("url together with policy_path accepted")
+	}
+	cfg.Security.OPA = types.OPAConfig{PolicyPath: "policies", Points: []string{"llm"}}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("unknown point accepted")
+	}
+}
+
+func TestValidateForgeConfig_ServerLimits(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Limits = types.RequestLimitsYAML{MaxBodyBytes: 4 << 20, Endpoints: map[string]int64{"POST /tasks/send": 8 << 20}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid limits rejected: %v", r.Errors)
+	}
+	cfg.Server.Limits.Endpoints = map[string]int64{"/tasks/send": 1024}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("endpoint without a method accepted")
+	}
+	cfg.Server.Limits = types.RequestLimitsYAML{MaxMessageParts: -1}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("negative limit accepted")
+	}
+}
+
+func TestValidateForgeConfig_Triggers(t *testing.T) {
+	cfg := validConfig()
+	cfg.Triggers = []types.TriggerConfig{
+		{ID: "github-push", SecretEnv: "GITHUB_WEBHOOK_SECRET", Task: "Review {{.Payload.head_commit.id}}"},
+		{ID: "alerts", Path: "/hooks/grafana/alerts", SecretEnv: "GRAFANA_SECRET", Channel: "slack", ChannelTarget: "C0123"},
+	}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid triggers rejected: %v", r.Errors)
+	}
+	cfg.Triggers = []types.TriggerConfig{
+		{ID: "a", Path: "/tasks/send", SecretEnv: "S"},
+		{ID: "b", SecretEnv: "", Task: "{{.Payload"},
+		{ID: "c", Path: "/hooks/b", SecretEnv: "S", Channel: "slack"},
+	}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{`path "/tasks/send" must be under /hooks/`, "secret_env is required", "triggers[1].task", `path "/hooks/b" is used`, "must be set together"} {
+		if !hasSubstr(r.Errors, want) {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+}
+
+func TestValidateForgeConfig_Alerts(t *testing.T) {
+	cfg := validConfig()
+	cfg.Notify.Targets = map[string]types.NotifyTarget{"oncall": {Channel: "slack", Target: "C0123"}}
+	cfg.Alerts = types.AlertsConfig{Rules: []types.AlertRule{
+		{ID: "spend", Metric: types.AlertMetricDailyCost, Threshold: 25, Notify: "oncall"},
+		{ID: "failures", Metric: types.AlertMetricTaskFailureRate, Threshold: 0.2, Window: time.Hour, WebhookEnv: "ALERT_WEBHOOK_URL"},
+	}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid alerts rejected: %v", r.Errors)
+	}
+
+	for rule, want := range map[types.AlertRule]string{
+		{ID: "a", Metric: "tokens", Threshold: 1, Notify: "oncall"}:                                   `metric "tokens" must be one of`,
+		{ID: "a", Metric: types.AlertMetricTaskFailureRate, Threshold: 20, Notify: "oncall"}:          "is a fraction",
+		{ID: "a", Metric: types.AlertMetricDailyTokens, Threshold: 1}:                                 "notify or webhook_env is required",
+		{ID: "a", Metric: types.AlertMetricDailyTokens, Threshold: 1, Notify: "pager"}:                `notify target "pager" is not declared`,
+		{ID: "a", Metric: types.AlertMetricP95Latency, Threshold: 30, Cooldown: -1, Notify: "oncall"}: "must not be negative",
+	} {
+		cfg.Alerts.Rules = []types.AlertRule{rule}
+		if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, want) {
+			t.Errorf("rule %+v: missing error %q in %v", rule, want, r.Errors)
+		}
+	}
+}
+
+func TestValidateForgeConfig_ChannelMiddleware(t *testing.T) {
+	cfg := validConfig()
+	cfg.ChannelMiddleware = types.ChannelMiddlewareConfig{
+		RateLimit:  types.ChannelRateLimitYAML{PerMinute: 10},
+		AllowUsers: []string{"slack:U0123", "telegram:*", "alice@example.com"},
+		DenyUsers:  []string{"telegram:666"},
+	}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid channel policy rejected: %v", r.Errors)
+	}
+	cfg.ChannelMiddleware.DenyUsers = []string{"U0123"}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("sender without an adapter accepted")
+	}
+	cfg.ChannelMiddleware = types.ChannelMiddlewareConfig{MaxMessageBytes: -1}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("negative limit accepted")
+	}
+}
+
+func TestValidateForgeConfig_Notify(t *testing.T) {
+	cfg := validConfig()
+	cfg.Notify = types.NotifyConfig{Targets: map[string]types.NotifyTarget{
+		"oncall": {Channel: "slack", Target: "C0123", Template: "{{.Title}}: {{.Message}}"},
+	}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid notify config rejected: %v", r.Errors)
+	}
+	cfg.Notify.Targets["oncall"] = types.NotifyTarget{Channel: "slack", Target: "C0123", Template: "{{.Title"}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("unparseable template accepted")
+	}
+	cfg.Notify.Targets["oncall"] = types.NotifyTarget{Channel: "slack"}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("target without a destination accepted")
+	}
+}
+
+func TestValidateForgeConfig_Handoff(t *testing.T) {
+	cfg := validConfig()
+	cfg.Handoff = types.HandoffConfig{Channel: "slack", Target: "C0SUPPORT"}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid handoff config rejected: %v", r.Errors)
+	}
+	cfg.Handoff = types.HandoffConfig{Channel: "slack"}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("handoff without a target accepted")
+	}
+}
+
+func TestValidateForgeConfig_Voice(t *testing.T) {
+	cfg := validConfig()
+	cfg.Voice = types.VoiceConfig{
+		STT: types.VoiceSTTConfig{Provider: "local", BaseURL: "http://localhost:8000/v1"},
+		TTS: types.VoiceTTSConfig{Provider: "openai", Voice: "nova"},
+	}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid voice config rejected: %v", r.Errors)
+	}
+	cfg.Voice.STT.Provider = "deepgram"
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("unknown stt provider accepted")
+	}
+	cfg.Voice = types.VoiceConfig{TTS: types.VoiceTTSConfig{Provider: "openai"}}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("tts without stt accepted")
+	}
+}
+
+func TestValidateForgeConfig_Attachments(t *testing.T) {
+	cfg := validConfig()
+	cfg.Attachments = types.AttachmentsConfig{Enabled: true, AllowedTypes: []string{"image/*", "application/pdf"}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid attachments config rejected: %v", r.Errors)
+	}
+	cfg.Attachments.AllowedTypes = []string{"pdf"}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("malformed MIME type accepted")
+	}
+	cfg.Attachments = types.AttachmentsConfig{MaxBytes: -1}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("negative max_bytes accepted")
+	}
+}
+
+func TestValidateForgeConfig_RuntimeContext(t *testing.T) {
+	cfg := validConfig()
+	cfg.RuntimeContext = types.RuntimeContextConfig{Enabled: true, Timezone: "Europe/Berlin", Facts: map[string]string{"company": "Acme"}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid runtime_context rejected: %v", r.Errors)
+	}
+	cfg.RuntimeContext.Timezone = "Mars/Olympus"
+	cfg.RuntimeContext.Facts["bad\nkey"] = "x"
+	if r := ValidateForgeConfig(cfg); len(r.Errors) != 2 {
+		t.Errorf("errors = %v, want the timezone and the fact key", r.Errors)
+	}
+	cfg.RuntimeContext = types.RuntimeContextConfig{Facts: map[string]string{"company": "Acme"}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() || len(r.Warnings) == 0 {
+		t.Errorf("facts without enabled: errors %v, warnings %v; want a warning", r.Errors, r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_Experiments(t *testing.T) {
+	cfg := validConfig()
+	cfg.Experiments = []types.ExperimentConfig{{
+		ID: "tone",
+		Variants: []types.ExperimentVariant{
+			{ID: "control", Weight: 50},
+			{ID: "terse", Weight: 50, Prompt: "variants/terse", Model: &types.ExperimentModel{Name: "gpt-4o-mini"}},
+			{ID: "vip", When: &types.ExperimentMatch{Tags: []string{"vip"}}},
+		},
+	}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("valid experiment rejected: %v", r.Errors)
+	}
+	cfg.Experiments[0].Variants[1] = types.ExperimentVariant{ID: "control", Weight: 40, Model: &types.ExperimentModel{}}
+	cfg.Experiments = append(cfg.Experiments, types.ExperimentConfig{ID: "a/b", Variants: []types.ExperimentVariant{{ID: "only"}}})
+	r := ValidateForgeConfig(cfg)
+	// duplicate variant, model without a name, weights != 100, "/" in
+	// the id, a single variant
+	if len(r.Errors) != 5 {
+		t.Errorf("errors = %v, want 5", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_Localization(t *testing.T) {
+	cfg := validConfig()
+	for _, lang := range []string{"es", "pt-BR"} {
+		cfg.Localization = types.LocalizationConfig{DefaultLanguage: lang, Detect: true}
+		if r := ValidateForgeConfig(cfg); !r.IsValid() {
+			t.Errorf("default_language %q rejected: %v", lang, r.Errors)
+		}
+	}
+	cfg.Localization.DefaultLanguage = "Spanish"
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("default_language that isn't a language code accepted")
+	}
+}
+
+func TestValidateForgeConfig_Profiles(t *testing.T) {
+	cfg, err := types.ParseForgeConfig([]byte(`
+agent_id: a
+version: 0.1.0
+framework: forge
+egress:
+  mode: allowlist
+profiles:
+  prod:
+    egress:
+      mode: wide-open
+  Dev:
+    enforce_guardrails: false
+  staging:
+    model:
+      name: [not, a, string]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{`profiles.prod: egress.mode "wide-open"`, `name "Dev" must be kebab-case`, "profiles.staging.model"} {
+		found := false
+		for _, e := range r.Errors {
+			found = found || strings.Contains(e, want)
+		}
+		if !found {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+	if cfg.Egress.Mode != "allowlist" {
+		t.Errorf("validation modified the config: egress.mode = %q", cfg.Egress.Mode)
+	}
+}
+
+func TestValidateForgeConfig_Gemini(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.Provider = "gemini"
+	cfg.Model.Gemini = &types.GeminiOptions{Grounding: true, SafetySettings: map[string]string{"harassment": "block_most"}}
+	if r := ValidateForgeConfig(cfg); !hasSubstr(r.Errors, `model.gemini.safety_settings: harassment: unknown threshold "block_most"`) {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Model.Provider = "openai"
+	cfg.Model.Gemini.SafetySettings = map[string]string{"harassment": "block_only_high"}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() || !hasSubstr(r.Warnings, "model.gemini is set but no model in the chain is gemini") {
+		t.Errorf("errors = %v, warnings = %v", r.Errors, r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_ProviderCaptures(t *testing.T) {
+	cfg := validConfig()
+	cfg.Observability.Captures = types.ProviderCaptureYAML{Enabled: true, SamplePercent: 150, MaxBytes: -1}
+	r := ValidateForgeConfig(cfg)
+	if !hasSubstr(r.Errors, "observability.captures.sample_percent 150 must be between 0 and 100") || !hasSubstr(r.Errors, "observability.captures.max_bytes must not be negative") {
+		t.Errorf("errors = %v", r.Errors)
+	}
+	cfg.Observability.Captures = types.ProviderCaptureYAML{Enabled: true, SamplePercent: 5}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("errors = %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_ModelHTTP(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.HTTP = &types.ModelHTTPConfig{
+		MaxConnsPerHost: -1,
+		Proxy:           "proxy:3128",
+		TLS:             &types.ModelTLSConfig{MinVersion: "1.0", InsecureSkipVerify: true},
+		Timeouts:        map[string]time.Duration{"ollama": 10 * time.Minute, "openia": time.Minute},
+	}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{
+		"model.http: connection limits must not be negative",
+		`model.http.proxy "proxy:3128" is not a valid URL`,
+		`model.http.tls.min_version "1.0" must be 1.2 or 1.3`,
+		`model.http.timeouts: unknown provider "openia"`,
+	} {
+		if !hasSubstr(r.Errors, want) {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+	if len(r.Errors) != 4 || !hasSubstr(r.Warnings, "insecure_skip_verify") {
+		t.Errorf("errors = %v, warnings = %v", r.Errors, r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_EgressConstraints(t *testing.T) {
+	cfg := validConfig()
+	cfg.Egress.AllowedSchemes = []string{"https", "http", "ftp"}
+	cfg.Egress.MaxResponseBytes = -1
+	cfg.Egress.Rules = []types.EgressRule{
+		{Domain: "api.example.com", Ports: []int{443, 0}, Methods: []string{"get", "GE T"}},
+		{Domain: "API.example.com"},
+		{Ports: []int{443}},
+	}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{
+		`egress.allowed_schemes: "ftp" must be http or https`,
+		"egress.max_response_bytes must not be negative",
+		"egress.rules[0]: port 0 out of range",
+		`egress.rules[0]: invalid method "GE T"`,
+		`egress.rules[1]: duplicate rule for "api.example.com"`,
+		"egress.rules[2]: domain is required",
+	} {
+		if !hasSubstr(r.Errors, want) {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+	if len(r.Errors) != 6 || !hasSubstr(r.Warnings, "includes http") {
+		t.Errorf("errors = %v, warnings = %v", r.Errors, r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_EgressProxy(t *testing.T) {
+	cfg := validConfig()
+	cfg.Egress.Proxy = &types.EgressProxyRef{SNI: "strict", TaskMaxBytes: -1, Socket: "run/egress.sock"}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{
+		`egress.proxy.sni "strict" must be one of`,
+		"egress.proxy: task quotas must not be negative",
+		`egress.proxy.socket "run/egress.sock" must be an absolute path`,
+	} {
+		if !hasSubstr(r.Errors, want) {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+}
+
+func TestValidateForgeConfig_SecretsRecipients(t *testing.T) {
+	cfg := validConfig()
+	cfg.Secrets.Recipients = []string{
+		"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p",
+		"not-a-key",
+	}
+	r := ValidateForgeConfig(cfg)
+	if len(r.Errors) != 1 || !hasSubstr(r.Errors, "secrets.recipients[1]:") {
+		t.Errorf("errors = %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_SecretsSync(t *testing.T) {
+	cfg := validConfig()
+	cfg.Secrets.Sync = &types.SecretsSyncConfig{From: "s3://bucket/key", Keys: []string{"A", "A=b"}}
+	r := ValidateForgeConfig(cfg)
+	for _, want := range []string{
+		`secrets.sync.from: secrets source "s3://bucket/key": unsupported scheme "s3"`,
+		`secrets.sync.keys: key "A" listed twice`,
+	} {
+		if !hasSubstr(r.Errors, want) {
+			t.Errorf("missing error %q in %v", want, r.Errors)
+		}
+	}
+
+	cfg.Secrets.Sync = &types.SecretsSyncConfig{From: "vault://secret/billing", Keys: []string{"OPENAI_API_KEY"}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("errors = %v", r.Errors)
+	}
+}
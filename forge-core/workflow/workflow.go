@@ -0,0 +1,472 @@
+// Package workflow runs the deterministic step pipelines forge.yaml
+// declares under `workflows:`. A run executes its steps in order — tool
+// calls, single model prompts and parallel fan-outs, each optionally
+// gated by a condition — with no model deciding what happens next, and
+// audits every step.
+//
+// Step arguments, prompts, conditions and the workflow output are Go
+// text/templates over the run's inputs and the results of earlier steps:
+//
+//	{{.Inputs.repo}}                 an input
+//	{{.Steps.fetch.Output}}          a step's output
+//	{{.Steps.fetch.JSON.count}}      a field of a step's JSON output
+//	{{.Steps.fetch.Status}}          ok, skipped or failed
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// Run statuses.
+const (
+	RunCompleted = "completed"
+	RunFailed    = "failed"
+)
+
+// Step statuses.
+const (
+	StepOK      = "ok"
+	StepSkipped = "skipped"
+	StepFailed  = "failed"
+)
+
+// Step kinds.
+const (
+	KindTool     = "tool"
+	KindPrompt   = "prompt"
+	KindParallel = "parallel"
+)
+
+// RunWorkflowTool is the name of the tool that starts a workflow. A
+// workflow may not call it: runs cannot nest.
+const RunWorkflowTool = "run_workflow"
+
+// ToolRunner calls a registered tool with JSON arguments. The runtime's
+// runner fires the tool hooks around the call, so guardrails and the
+// tool_exec audit see workflow steps like any other call.
+type ToolRunner func(ctx context.Context, name string, args json.RawMessage) (string, error)
+
+// Config configures an Engine.
+type Config struct {
+	Workflows []types.WorkflowConfig
+	Tools     ToolRunner
+	// Client answers prompt steps. Nil fails them.
+	Client llm.Client
+	// Audit receives a workflow_step event per step and a workflow_run
+	// event per run. Nil disables auditing.
+	Audit *coreruntime.AuditLogger
+}
+
+// Engine runs declared workflows. It is safe for concurrent use.
+type Engine struct {
+	workflows map[string]types.WorkflowConfig
+	tools     ToolRunner
+	client    llm.Client
+	audit     *coreruntime.AuditLogger
+}
+
+// New returns an engine for cfg.Workflows. The workflows are assumed
+// valid; validate.ValidateForgeConfig checks them.
+func New(cfg Config) *Engine {
+	e := &Engine{
+		workflows: make(map[string]types.WorkflowConfig, len(cfg.Workflows)),
+		tools:     cfg.Tools,
+		client:    cfg.Client,
+		audit:     cfg.Audit,
+	}
+	for _, wf := range cfg.Workflows {
+		e.workflows[wf.ID] = wf
+	}
+	return e
+}
+
+// Workflows returns the declared workflows sorted by ID.
+func (e *Engine) Workflows() []types.WorkflowConfig {
+	out := make([]types.WorkflowConfig, 0, len(e.workflows))
+	for _, wf := range e.workflows {
+		out = append(out, wf)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Run is the record of one workflow run.
+type Run struct {
+	ID         string       `json:"id"`
+	Workflow   string       `json:"workflow"`
+	Trigger    string       `json:"trigger,omitempty"`
+	Status     string       `json:"status"`
+	Output     string       `json:"output,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Steps      []StepResult `json:"steps"`
+	DurationMs int64        `json:"duration_ms"`
+}
+
+// StepResult is what one step did.
+type StepResult struct {
+	ID         string       `json:"id"`
+	Kind       string       `json:"kind"`
+	Tool       string       `json:"tool,omitempty"`
+	Status     string       `json:"status"`
+	Output     string       `json:"output,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	DurationMs int64        `json:"duration_ms"`
+	Parallel   []StepResult `json:"parallel,omitempty"`
+}
+
+// stepData is a finished step as templates see it.
+type stepData struct {
+	Output string
+	JSON   any
+	Status string
+}
+
+// templateData is what step templates render against.
+type templateData struct {
+	Inputs map[string]any
+	Steps  map[string]stepData
+}
+
+// StepError reports the step that failed a run.
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e *StepError) Error() string { return fmt.Sprintf("step %s: %v", e.Step, e.Err) }
+func (e *StepError) Unwrap() error { return e.Err }
+
+// RunOptions parameterizes one run.
+type RunOptions struct {
+	// ID names the run. Empty mints one with NewRunID.
+	ID     string
+	Inputs map[string]any
+	// Trigger records what started the run for the audit trail:
+	// "schedule:<id>", "webhook:<id>" or "tool".
+	Trigger string
+}
+
+// NewRunID returns a fresh run ID for workflow id.
+func NewRunID(id string) string {
+	return fmt.Sprintf("wf-%s-%s", id, coreruntime.GenerateID())
+}
+
+// Run executes workflow id. The run ID is the task ID its tool calls
+// and audit events carry. Run returns the run record, also when a step
+// failed the run, together with the failure; an unknown workflow or a
+// missing input fails before any step and returns a nil run.
+func (e *Engine) Run(ctx context.Context, id string, opts RunOptions) (*Run, error) {
+	wf, ok := e.workflows[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown workflow %q", id)
+	}
+	inputs := opts.Inputs
+	for _, name := range wf.Inputs {
+		if _, ok := inputs[name]; !ok {
+			return nil, fmt.Errorf("workflow %q: missing input %q", id, name)
+		}
+	}
+	if inputs == nil {
+		inputs = map[string]any{}
+	}
+	if wf.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wf.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	run := &Run{
+		ID:       opts.ID,
+		Workflow: wf.ID,
+		Trigger:  opts.Trigger,
+		Status:   RunCompleted,
+	}
+	if run.ID == "" {
+		run.ID = NewRunID(wf.ID)
+	}
+	ctx = coreruntime.WithTaskID(ctx, run.ID)
+	data := &templateData{Inputs: inputs, Steps: map[string]stepData{}}
+	var runErr error
+	for _, step := range wf.Steps {
+		res := e.runStep(ctx, run, step, "", data)
+		run.Steps = append(run.Steps, res)
+		data.Steps[step.ID] = newStepData(res)
+		if res.Status == StepFailed && step.OnError != types.WorkflowOnErrorContinue {
+			runErr = &StepError{Step: step.ID, Err: errors.New(res.Error)}
+			break
+		}
+	}
+	if runErr == nil {
+		run.Output, runErr = runOutput(wf, run, data)
+	}
+	if runErr != nil {
+		run.Status = RunFailed
+		run.Error = runErr.Error()
+	}
+	run.DurationMs = time.Since(start).Milliseconds()
+
+	fields := map[string]any{
+		"workflow":    wf.ID,
+		"trigger":     run.Trigger,
+		"status":      run.Status,
+		"steps":       len(run.Steps),
+		"duration_ms": run.DurationMs,
+	}
+	if run.Error != "" {
+		fields["error"] = run.Error
+	}
+	e.emit(ctx, coreruntime.AuditWorkflowRun, run.ID, fields)
+	return run, runErr
+}
+
+// runOutput renders the workflow's output, or returns the last step's.
+func runOutput(wf types.WorkflowConfig, run *Run, data *templateData) (string, error) {
+	if wf.Output == "" {
+		if n := len(run.Steps); n > 0 {
+			return run.Steps[n-1].Output, nil
+		}
+		return "", nil
+	}
+	out, err := render("output", wf.Output, data)
+	if err != nil {
+		return "", fmt.Errorf("rendering output: %w", err)
+	}
+	return out, nil
+}
+
+// runStep runs one step against the results so far. prefix names the
+// parallel step a fanned-out step belongs to.
+func (e *Engine) runStep(ctx context.Context, run *Run, step types.WorkflowStep, prefix string, data *templateData) StepResult {
+	start := time.Now()
+	res := StepResult{ID: step.ID, Kind: stepKind(step), Tool: step.Tool, Status: StepOK}
+	if err := ctx.Err(); err != nil {
+		res.Status, res.Error = StepFailed, err.Error()
+	} else if ok, err := condition(step, data); err != nil {
+		res.Status, res.Error = StepFailed, err.Error()
+	} else if !ok {
+		res.Status = StepSkipped
+	} else {
+		var err error
+		switch res.Kind {
+		case KindTool:
+			res.Output, err = e.runTool(ctx, step, data)
+		case KindPrompt:
+			res.Output, err = e.runPrompt(ctx, step, data)
+		case KindParallel:
+			res.Parallel, res.Output, err = e.runParallel(ctx, run, step, data)
+		}
+		if err != nil {
+			res.Status, res.Error = StepFailed, err.Error()
+		}
+	}
+	res.DurationMs = time.Since(start).Milliseconds()
+
+	fields := map[string]any{
+		"workflow":    run.Workflow,
+		"step":        prefix + step.ID,
+		"kind":        res.Kind,
+		"status":      res.Status,
+		"duration_ms": res.DurationMs,
+	}
+	if res.Tool != "" {
+		fields["tool"] = res.Tool
+	}
+	if res.Error != "" {
+		fields["error"] = res.Error
+	}
+	e.emit(ctx, coreruntime.AuditWorkflowStep, run.ID, fields)
+	return res
+}
+
+func stepKind(step types.WorkflowStep) string {
+	switch {
+	case step.Tool != "":
+		return KindTool
+	case step.Prompt != "":
+		return KindPrompt
+	default:
+		return KindParallel
+	}
+}
+
+// condition reports whether a step runs: it has no condition, or the
+// condition renders to something other than a false-like value.
+func condition(step types.WorkflowStep, data *templateData) (bool, error) {
+	if step.If == "" {
+		return true, nil
+	}
+	out, err := render(step.ID+".if", step.If, data)
+	if err != nil {
+		return false, fmt.Errorf("evaluating if: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(out)) {
+	case "", "false", "0", "no":
+		return false, nil
+	}
+	return true, nil
+}
+
+func (e *Engine) runTool(ctx context.Context, step types.WorkflowStep, data *templateData) (string, error) {
+	if e.tools == nil {
+		return "", errors.New("no tool runner configured")
+	}
+	args, err := renderArgs(step.ID, step.Args, data)
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("encoding args: %w", err)
+	}
+	return e.tools(ctx, step.Tool, raw)
+}
+
+func (e *Engine) runPrompt(ctx context.Context, step types.WorkflowStep, data *templateData) (string, error) {
+	if e.client == nil {
+		return "", errors.New("no model configured")
+	}
+	prompt, err := render(step.ID+".prompt", step.Prompt, data)
+	if err != nil {
+		return "", fmt.Errorf("rendering prompt: %w", err)
+	}
+	resp, err := e.client.Chat(ctx, &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(resp.Message.Content), nil
+}
+
+// runParallel runs step's sub-steps concurrently. Each sees the results
+// of the steps before the parallel step, not of its siblings. The output
+// is a JSON object of the sub-steps' outputs by ID; a failed sub-step
+// fails the parallel step unless it continues on error.
+func (e *Engine) runParallel(ctx context.Context, run *Run, step types.WorkflowStep, data *templateData) ([]StepResult, string, error) {
+	results := make([]StepResult, len(step.Parallel))
+	var wg sync.WaitGroup
+	for i, sub := range step.Parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = e.runStep(ctx, run, sub, step.ID+".", data)
+		}()
+	}
+	wg.Wait()
+
+	outputs := make(map[string]string, len(results))
+	var failed []string
+	for i, res := range results {
+		outputs[res.ID] = res.Output
+		if res.Status == StepFailed && step.Parallel[i].OnError != types.WorkflowOnErrorContinue {
+			failed = append(failed, res.ID+": "+res.Error)
+		}
+	}
+	out, err := json.Marshal(outputs)
+	if err != nil {
+		return results, "", err
+	}
+	if len(failed) > 0 {
+		return results, string(out), errors.New(strings.Join(failed, "; "))
+	}
+	return results, string(out), nil
+}
+
+func newStepData(res StepResult) stepData {
+	d := stepData{Output: res.Output, Status: res.Status}
+	var v any
+	if json.Unmarshal([]byte(res.Output), &v) == nil {
+		d.JSON = v
+	}
+	return d
+}
+
+func (e *Engine) emit(ctx context.Context, event, runID string, fields map[string]any) {
+	if e.audit == nil {
+		return
+	}
+	e.audit.EmitFromContext(ctx, coreruntime.AuditEvent{Event: event, TaskID: runID, Fields: fields})
+}
+
+// renderArgs renders the string values of a step's arguments, walking
+// nested maps and lists.
+func renderArgs(stepID string, args map[string]any, data *templateData) (map[string]any, error) {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		r, err := renderValue(stepID+".args."+k, v, data)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = r
+	}
+	return out, nil
+}
+
+func renderValue(name string, v any, data *templateData) (any, error) {
+	switch v := v.(type) {
+	case string:
+		out, err := render(name, v, data)
+		if err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", name, err)
+		}
+		return out, nil
+	case map[string]any:
+		return renderArgs(name, v, data)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			r, err := renderValue(fmt.Sprintf("%s[%d]", name, i), item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// funcs are the helpers workflow templates may call.
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"contains": strings.Contains,
+	"lower":    strings.ToLower,
+	"trim":     strings.TrimSpace,
+}
+
+// Parse parses a workflow template, for validation.
+func Parse(name, src string) error {
+	_, err := template.New(name).Funcs(funcs).Parse(src)
+	return err
+}
+
+func render(name, src string, data *templateData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Funcs(funcs).Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	// A missing key of a map[string]any prints as "<no value>" even
+	// with missingkey=zero; an absent value renders empty here.
+	return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+}
@@ -0,0 +1,167 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+type echoClient struct{}
+
+func (echoClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "summary of " + req.Messages[0].Content}}, nil
+}
+
+func (echoClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (echoClient) ModelID() string { return "test-model" }
+
+// recordingTools answers each tool with its name and arguments and
+// fails the tool named "broken".
+type recordingTools struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordingTools) run(ctx context.Context, name string, args json.RawMessage) (string, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, name+" "+string(args))
+	r.mu.Unlock()
+	switch name {
+	case "broken":
+		return "", errors.New("tool exploded")
+	case "count":
+		return `{"count": 3}`, nil
+	}
+	return name + " ran", nil
+}
+
+func TestRunToolsPromptsAndConditions(t *testing.T) {
+	tools := &recordingTools{}
+	var audit bytes.Buffer
+	e := New(Config{
+		Workflows: []types.WorkflowConfig{{
+			ID:     "triage",
+			Inputs: []string{"repo"},
+			Steps: []types.WorkflowStep{
+				{ID: "issues", Tool: "count", Args: map[string]any{"repo": "{{.Inputs.repo}}", "labels": []any{"bug", 7}}},
+				{ID: "page", If: `{{if gt .Steps.issues.JSON.count 5.0}}yes{{end}}`, Tool: "pager"},
+				{ID: "digest", Prompt: "{{.Steps.issues.JSON.count}} open issues in {{.Inputs.repo}}"},
+			},
+			Output: "{{.Steps.digest.Output}} (paged: {{.Steps.page.Status}})",
+		}},
+		Tools:  tools.run,
+		Client: echoClient{},
+		Audit:  coreruntime.NewAuditLogger(&audit),
+	})
+
+	run, err := e.Run(context.Background(), "triage", RunOptions{Inputs: map[string]any{"repo": "forge"}, Trigger: "tool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "summary of 3 open issues in forge (paged: skipped)"; run.Output != want {
+		t.Errorf("output = %q, want %q", run.Output, want)
+	}
+	if len(tools.calls) != 1 || tools.calls[0] != `count {"labels":["bug",7],"repo":"forge"}` {
+		t.Errorf("tool calls = %v", tools.calls)
+	}
+	if run.Status != RunCompleted || len(run.Steps) != 3 || run.Steps[1].Status != StepSkipped {
+		t.Errorf("run = %+v", run)
+	}
+	if got := strings.Count(audit.String(), `"event":"workflow_step"`); got != 3 {
+		t.Errorf("workflow_step events = %d, want 3", got)
+	}
+	if !strings.Contains(audit.String(), `"event":"workflow_run"`) {
+		t.Error("no workflow_run event")
+	}
+}
+
+func TestRunStopsOnFailureUnlessContinued(t *testing.T) {
+	tools := &recordingTools{}
+	e := New(Config{
+		Workflows: []types.WorkflowConfig{
+			{ID: "strict", Steps: []types.WorkflowStep{{ID: "a", Tool: "broken"}, {ID: "b", Tool: "after"}}},
+			{ID: "lenient", Steps: []types.WorkflowStep{
+				{ID: "a", Tool: "broken", OnError: types.WorkflowOnErrorContinue},
+				{ID: "b", Tool: "after", If: `{{eq .Steps.a.Status "failed"}}`},
+			}},
+		},
+		Tools: tools.run,
+	})
+
+	run, err := e.Run(context.Background(), "strict", RunOptions{Trigger: "tool"})
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "a" || run.Status != RunFailed || len(run.Steps) != 1 {
+		t.Fatalf("strict: run = %+v, err = %v", run, err)
+	}
+
+	run, err = e.Run(context.Background(), "lenient", RunOptions{Trigger: "tool"})
+	if err != nil || run.Output != "after ran" {
+		t.Fatalf("lenient: run = %+v, err = %v", run, err)
+	}
+}
+
+func TestRunParallel(t *testing.T) {
+	tools := &recordingTools{}
+	e := New(Config{
+		Workflows: []types.WorkflowConfig{{ID: "fan", Steps: []types.WorkflowStep{
+			{ID: "all", Parallel: []types.WorkflowStep{
+				{ID: "x", Tool: "left"},
+				{ID: "y", Tool: "right"},
+				{ID: "z", Tool: "broken", OnError: types.WorkflowOnErrorContinue},
+			}},
+			{ID: "join", Tool: "merge", Args: map[string]any{"left": "{{.Steps.all.JSON.x}}"}},
+		}}},
+		Tools: tools.run,
+	})
+
+	run, err := e.Run(context.Background(), "fan", RunOptions{Trigger: "tool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outputs map[string]string
+	if err := json.Unmarshal([]byte(run.Steps[0].Output), &outputs); err != nil {
+		t.Fatal(err)
+	}
+	if outputs["x"] != "left ran" || outputs["y"] != "right ran" || len(run.Steps[0].Parallel) != 3 {
+		t.Errorf("parallel step = %+v", run.Steps[0])
+	}
+	if last := tools.calls[len(tools.calls)-1]; last != `merge {"left":"left ran"}` {
+		t.Errorf("join call = %s", last)
+	}
+}
+
+func TestRunRejectsUnknownWorkflowAndMissingInputs(t *testing.T) {
+	e := New(Config{Workflows: []types.WorkflowConfig{
+		{ID: "needs", Inputs: []string{"repo"}, Steps: []types.WorkflowStep{{ID: "a", Tool: "x"}}},
+	}})
+	for _, id := range []string{"nope", "needs"} {
+		if run, err := e.Run(context.Background(), id, RunOptions{Trigger: "tool"}); err == nil || run != nil {
+			t.Errorf("Run(%s) = %+v, %v; want an error", id, run, err)
+		}
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	e := New(Config{
+		Workflows: []types.WorkflowConfig{{ID: "slow", Timeout: 1, Steps: []types.WorkflowStep{{ID: "a", Tool: "wait"}}}},
+		Tools: func(ctx context.Context, name string, args json.RawMessage) (string, error) {
+			<-ctx.Done()
+			return "", fmt.Errorf("wait: %w", ctx.Err())
+		},
+	})
+	if _, err := e.Run(context.Background(), "slow", RunOptions{Trigger: "tool"}); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("err = %v, want a deadline", err)
+	}
+}
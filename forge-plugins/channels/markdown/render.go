@@ -0,0 +1,257 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format is an outbound format profile: how an adapter renders the
+// agent's markdown before sending it. Each adapter accepts the subset
+// it can deliver through its `format` setting.
+type Format string
+
+const (
+	// FormatMrkdwn is Slack mrkdwn text (the Slack default).
+	FormatMrkdwn Format = "mrkdwn"
+	// FormatBlocks is Slack Block Kit: header, divider and mrkdwn
+	// section blocks.
+	FormatBlocks Format = "blocks"
+	// FormatHTML is the Telegram HTML subset (the Telegram default).
+	FormatHTML Format = "html"
+	// FormatPlain strips all markup; for channels or clients that show
+	// markup characters literally.
+	FormatPlain Format = "plain"
+)
+
+// Slack Block Kit limits.
+const (
+	slackHeaderLimit  = 150
+	slackSectionLimit = 3000
+	// SlackMaxBlocks is the most blocks one chat.postMessage accepts.
+	SlackMaxBlocks = 50
+)
+
+// ResolveFormat validates an adapter's `format` setting against the
+// formats the adapter supports. An empty setting selects supported[0],
+// the adapter's default.
+func ResolveFormat(channel, setting string, supported ...Format) (Format, error) {
+	if setting == "" {
+		return supported[0], nil
+	}
+	names := make([]string, len(supported))
+	for i, f := range supported {
+		if Format(setting) == f {
+			return f, nil
+		}
+		names[i] = "'" + string(f) + "'"
+	}
+	return "", fmt.Errorf("%s: format must be one of %s, got %q", channel, strings.Join(names, ", "), setting)
+}
+
+// Render converts markdown to the given format. Tables, which neither
+// Slack nor Telegram renders, become aligned monospace blocks first.
+// FormatBlocks renders as FormatMrkdwn here — the text a Block Kit
+// message carries as its notification fallback; see ToSlackBlocks.
+func Render(text string, f Format) string {
+	switch f {
+	case FormatMrkdwn, FormatBlocks:
+		return ToSlackMrkdwn(tablesToCode(text, true))
+	case FormatHTML:
+		return ToTelegramHTML(tablesToCode(text, true))
+	case FormatPlain:
+		return ToPlain(text)
+	}
+	return text
+}
+
+// ToPlain strips markdown to plain text: headings and emphasis lose
+// their markers, bullets become "•", links become "text (url)", fenced
+// code keeps its content without the fences and tables are aligned.
+func ToPlain(text string) string {
+	lines := strings.Split(tablesToCode(text, false), "\n")
+	var result []string
+	inCodeBlock := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			result = append(result, line)
+			continue
+		}
+		switch {
+		case headerRe.MatchString(line):
+			line = headerRe.FindStringSubmatch(line)[2]
+		case blockquoteRe.MatchString(line):
+			line = blockquoteRe.FindStringSubmatch(line)[1]
+		case bulletRe.MatchString(line):
+			line = "• " + bulletRe.FindStringSubmatch(line)[1]
+		}
+		result = append(result, stripInline(line))
+	}
+	return strings.Join(result, "\n")
+}
+
+// stripInline removes inline emphasis and code markers from a line.
+func stripInline(line string) string {
+	line = inlineCodeRe.ReplaceAllString(line, "$1")
+	line = boldRe.ReplaceAllString(line, "$1")
+	line = strikethroughRe.ReplaceAllString(line, "$1")
+	line = italicRe.ReplaceAllString(line, "$1")
+	return linkRe.ReplaceAllString(line, "$1 ($2)")
+}
+
+var ruleRe = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+
+// ToSlackBlocks converts markdown to Slack Block Kit blocks. Headings
+// become header blocks, horizontal rules become dividers and everything
+// between them becomes mrkdwn section blocks, split to stay within the
+// section text limit. Callers post at most SlackMaxBlocks per message.
+func ToSlackBlocks(text string) []map[string]any {
+	var blocks []map[string]any
+	var para []string
+	flush := func() {
+		body := strings.TrimSpace(strings.Join(para, "\n"))
+		para = nil
+		if body == "" {
+			return
+		}
+		for _, chunk := range SplitMessage(ToSlackMrkdwn(body), slackSectionLimit) {
+			blocks = append(blocks, map[string]any{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": chunk},
+			})
+		}
+	}
+
+	inCodeBlock := false
+	for _, line := range strings.Split(tablesToCode(text, true), "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+			para = append(para, line)
+			continue
+		}
+		if inCodeBlock {
+			para = append(para, line)
+			continue
+		}
+		if m := headerRe.FindStringSubmatch(line); m != nil {
+			flush()
+			blocks = append(blocks, map[string]any{
+				"type": "header",
+				"text": map[string]any{"type": "plain_text", "text": truncateRunes(stripInline(m[2]), slackHeaderLimit)},
+			})
+			continue
+		}
+		if ruleRe.MatchString(line) {
+			flush()
+			blocks = append(blocks, map[string]any{"type": "divider"})
+			continue
+		}
+		para = append(para, line)
+	}
+	flush()
+	return blocks
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with "…".
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
+
+var tableSepRe = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+
+// tablesToCode rewrites markdown tables outside fenced code as aligned
+// monospace text, wrapped in a fence when fence is set so the target
+// format shows it in a fixed-width font. A table is a row containing
+// "|" immediately followed by a separator row ("|---|---|").
+func tablesToCode(text string, fence bool) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	inCodeBlock := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+		}
+		if inCodeBlock || !strings.Contains(line, "|") || i+1 >= len(lines) || !tableSepRe.MatchString(lines[i+1]) || !strings.Contains(lines[i+1], "|") {
+			out = append(out, line)
+			continue
+		}
+		rows := [][]string{tableCells(line)}
+		j := i + 2
+		for ; j < len(lines) && strings.Contains(lines[j], "|") && strings.TrimSpace(lines[j]) != ""; j++ {
+			rows = append(rows, tableCells(lines[j]))
+		}
+		if fence {
+			out = append(out, "```")
+		}
+		out = append(out, alignRows(rows)...)
+		if fence {
+			out = append(out, "```")
+		}
+		i = j - 1
+	}
+	return strings.Join(out, "\n")
+}
+
+// tableCells splits a markdown table row into trimmed, unmarked cells.
+func tableCells(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+	cells := strings.Split(row, "|")
+	for i, c := range cells {
+		cells[i] = stripInline(strings.TrimSpace(c))
+	}
+	return cells
+}
+
+// alignRows pads cells to their column width. The first row is the
+// header and is underlined.
+func alignRows(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for c, cell := range row {
+			if c >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[c] = max(widths[c], utf8.RuneCountInString(cell))
+		}
+	}
+	format := func(row []string) string {
+		var b strings.Builder
+		for c, w := range widths {
+			cell := ""
+			if c < len(row) {
+				cell = row[c]
+			}
+			if c > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(cell)
+			if c < len(widths)-1 {
+				b.WriteString(strings.Repeat(" ", w-utf8.RuneCountInString(cell)))
+			}
+		}
+		return strings.TrimRight(b.String(), " ")
+	}
+
+	out := []string{format(rows[0])}
+	rule := make([]string, len(widths))
+	for c, w := range widths {
+		rule[c] = strings.Repeat("-", w)
+	}
+	out = append(out, strings.Join(rule, "  "))
+	for _, row := range rows[1:] {
+		out = append(out, format(row))
+	}
+	return out
+}
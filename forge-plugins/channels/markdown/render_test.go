@@ -0,0 +1,86 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleTable = "| Name | Status |\n|------|:------:|\n| api | **up** |\n| worker-east | down |"
+
+func TestResolveFormat(t *testing.T) {
+	f, err := ResolveFormat("slack", "", FormatMrkdwn, FormatBlocks)
+	if err != nil || f != FormatMrkdwn {
+		t.Errorf("empty setting = %q, %v; want default mrkdwn", f, err)
+	}
+	f, err = ResolveFormat("slack", "blocks", FormatMrkdwn, FormatBlocks)
+	if err != nil || f != FormatBlocks {
+		t.Errorf("blocks = %q, %v; want blocks", f, err)
+	}
+	_, err = ResolveFormat("telegram", "blocks", FormatHTML, FormatPlain)
+	if err == nil || !strings.Contains(err.Error(), "'html', 'plain'") {
+		t.Errorf("unsupported format error = %v, want the supported list", err)
+	}
+}
+
+func TestTablesToCode_Aligns(t *testing.T) {
+	got := tablesToCode(sampleTable, true)
+	want := "```\nName         Status\n-----------  ------\napi          up\nworker-east  down\n```"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTablesToCode_IgnoresFencedAndPipes(t *testing.T) {
+	in := "```\n| a | b |\n|---|---|\n```\nuse a | b pipe\n---"
+	if got := tablesToCode(in, true); got != in {
+		t.Errorf("got %q, want input unchanged", got)
+	}
+}
+
+func TestRender_TelegramTable(t *testing.T) {
+	got := Render(sampleTable, FormatHTML)
+	if !strings.HasPrefix(got, "<pre><code>Name") {
+		t.Errorf("table should render as a preformatted block, got %q", got)
+	}
+}
+
+func TestToPlain(t *testing.T) {
+	in := "# Title\n\n- **bold** and *italic*\n> quoted `code`\n[docs](https://x.test)\n```go\nx := *p\n```"
+	want := "Title\n\n• bold and italic\nquoted code\ndocs (https://x.test)\nx := *p"
+	if got := ToPlain(in); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToSlackBlocks(t *testing.T) {
+	in := "# Deploy **report**\n\nAll services up.\n\n---\n\n" + sampleTable
+	blocks := ToSlackBlocks(in)
+	var types []string
+	for _, b := range blocks {
+		types = append(types, b["type"].(string))
+	}
+	if got := strings.Join(types, ","); got != "header,section,divider,section" {
+		t.Fatalf("block types = %s", got)
+	}
+	header := blocks[0]["text"].(map[string]any)
+	if header["type"] != "plain_text" || header["text"] != "Deploy report" {
+		t.Errorf("header text = %v", header)
+	}
+	table := blocks[3]["text"].(map[string]any)["text"].(string)
+	if !strings.HasPrefix(table, "```\nName") {
+		t.Errorf("table section should be a code block, got %q", table)
+	}
+}
+
+func TestToSlackBlocks_SplitsLongSections(t *testing.T) {
+	long := strings.Repeat("word ", 700) + "\n\n" + strings.Repeat("more ", 700)
+	blocks := ToSlackBlocks(long)
+	if len(blocks) < 2 {
+		t.Fatalf("got %d blocks, want the section split", len(blocks))
+	}
+	for _, b := range blocks {
+		if n := len(b["text"].(map[string]any)["text"].(string)); n > slackSectionLimit {
+			t.Errorf("section length %d exceeds %d", n, slackSectionLimit)
+		}
+	}
+}
@@ -628,6 +628,9 @@ func (p *Plugin) dispatch(ctx context.Context, msg *ChatMessage, chatTypeHint st
 		defer finish(&handlerErr)
 
 		resp, herr := handler(spanCtx, event)
+		if channels.IsDropped(herr) {
+			return // a retry of a message already being answered, or dropped by policy
+		}
 		if herr != nil {
 			handlerErr = herr
 			log.Printf("[msteams] handler error: %v", herr)
@@ -791,8 +794,8 @@ func parseAllowBotIDs(s string) map[string]bool {
 	return out
 }
 
-// extractText pulls the text content out of an A2A message, mirroring the
-// pattern used by Slack and Telegram.
+// extractText pulls the text content out of an A2A message, with footnotes
+// for any sources it cites, mirroring the pattern used by Slack and Telegram.
 func extractText(msg *a2a.Message) string {
 	if msg == nil {
 		return "(no response)"
@@ -806,6 +809,9 @@ func extractText(msg *a2a.Message) string {
 	if len(parts) == 0 {
 		return "(no text response)"
 	}
+	if notes := channels.CitationFootnotes(msg.Citations); notes != "" {
+		parts = append(parts, "\n"+notes)
+	}
 	return strings.Join(parts, "\n")
 }
 
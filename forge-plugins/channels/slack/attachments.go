@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// slackFileHost serves the files shared in Slack messages.
+const slackFileHost = "files.slack.com"
+
+// slackFile is the part of a shared file's object needed to download it.
+type slackFile struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	MimeType           string `json:"mimetype"`
+	Size               int64  `json:"size"`
+	URLPrivateDownload string `json:"url_private_download"`
+}
+
+// fileAttachments describes the files a message shares. Their content is
+// fetched by FetchAttachment.
+func fileAttachments(files []slackFile) []channels.Attachment {
+	var atts []channels.Attachment
+	for _, f := range files {
+		if f.URLPrivateDownload == "" {
+			continue // a file the bot can't download, e.g. an external one
+		}
+		atts = append(atts, channels.Attachment{
+			Name: f.Name, MimeType: f.MimeType, URL: f.URLPrivateDownload, ID: f.ID, Size: f.Size,
+		})
+	}
+	return atts
+}
+
+// FetchAttachment implements channels.AttachmentFetcher. The bot token
+// is only ever sent to Slack's file host: a download link pointing
+// anywhere else is refused. Needs the files:read scope.
+func (p *Plugin) FetchAttachment(ctx context.Context, a channels.Attachment, maxBytes int64) ([]byte, error) {
+	u, err := url.Parse(a.URL)
+	if err != nil || u.Scheme != "https" || u.Host != p.fileHost {
+		return nil, fmt.Errorf("slack attachment %q: download link is not on %s", a.Name, p.fileHost)
+	}
+	if a.Size > maxBytes {
+		return nil, fmt.Errorf("slack attachment %q is %d bytes, over the %d byte limit", a.Name, a.Size, maxBytes)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.botToken)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading slack file: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading slack file: status %d", resp.StatusCode)
+	}
+	// Without files:read Slack answers with its sign-in page.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") && !strings.HasPrefix(a.MimeType, "text/html") {
+		return nil, fmt.Errorf("downloading slack file: got a web page; does the app have the files:read scope?")
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err == nil && int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("slack attachment %q is over the %d byte limit", a.Name, maxBytes)
+	}
+	return data, err
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+func TestNormalizeEvent_Files(t *testing.T) {
+	raw := `{
+		"event": {
+			"type": "message",
+			"subtype": "file_share",
+			"channel": "C0123456",
+			"user": "U789",
+			"text": "can you read this?",
+			"ts": "1234567890.123456",
+			"files": [
+				{"id": "F1", "name": "q3.pdf", "mimetype": "application/pdf", "size": 2048,
+				 "url_private_download": "https://files.slack.com/files-pri/T1-F1/download/q3.pdf"},
+				{"id": "F2", "name": "gdoc", "mimetype": "application/vnd.google-apps.document"}
+			]
+		}
+	}`
+	event, err := New().NormalizeEvent([]byte(raw))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	want := channels.Attachment{Name: "q3.pdf", MimeType: "application/pdf", ID: "F1", Size: 2048,
+		URL: "https://files.slack.com/files-pri/T1-F1/download/q3.pdf"}
+	if len(event.Attachments) != 1 || event.Attachments[0].Name != want.Name || event.Attachments[0].URL != want.URL {
+		t.Errorf("Attachments = %+v, want only the downloadable file", event.Attachments)
+	}
+}
+
+func TestFetchAttachment(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("%PDF-1.7"))
+	}))
+	defer srv.Close()
+	host, _ := url.Parse(srv.URL)
+	p := New()
+	p.botToken = "xoxb-test"
+	p.client = srv.Client()
+	p.fileHost = host.Host
+
+	att := channels.Attachment{Name: "q3.pdf", MimeType: "application/pdf", URL: srv.URL + "/files-pri/T1-F1/download/q3.pdf"}
+	data, err := p.FetchAttachment(context.Background(), att, 1<<20)
+	if err != nil || string(data) != "%PDF-1.7" {
+		t.Errorf("FetchAttachment = %q, %v", data, err)
+	}
+	if _, err := p.FetchAttachment(context.Background(), att, 4); err == nil {
+		t.Error("a file over maxBytes should not be fetched")
+	}
+	att.URL = "https://evil.example.com/q3.pdf"
+	if _, err := p.FetchAttachment(context.Background(), att, 1<<20); err == nil {
+		t.Error("the bot token must not be sent off Slack's file host")
+	}
+}
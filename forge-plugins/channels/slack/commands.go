@@ -0,0 +1,117 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// Slash commands arrive over Socket Mode as `slash_commands` envelopes
+// (already acked by readLoop). A command mapped in the `commands` setting
+// asks the agent to use that skill. The bot posts the invocation in the
+// channel and threads the reply under it, so the thread becomes the
+// command's session: follow-ups there continue the same conversation.
+
+// slashCommand is the slash_commands payload fields the adapter reads.
+type slashCommand struct {
+	Command     string `json:"command"`
+	Text        string `json:"text"`
+	UserID      string `json:"user_id"`
+	ChannelID   string `json:"channel_id"`
+	TriggerID   string `json:"trigger_id"`
+	ResponseURL string `json:"response_url"`
+}
+
+// parseCommands parses the `commands` setting: comma-separated
+// /command=skill pairs, e.g. "/triage=k8s-incident-triage,/report=pod-report".
+func parseCommands(raw string) (map[string]string, error) {
+	commands := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		cmd, skill, ok := strings.Cut(pair, "=")
+		cmd, skill = strings.TrimSpace(cmd), strings.TrimSpace(skill)
+		if !ok || !strings.HasPrefix(cmd, "/") || len(cmd) < 2 || skill == "" {
+			return nil, fmt.Errorf("slack: commands entry %q must be /command=skill", pair)
+		}
+		commands[cmd] = skill
+	}
+	return commands, nil
+}
+
+// commandMessage is the message the agent receives for a slash command.
+// An unmapped command is forwarded as typed.
+func commandMessage(cmd slashCommand, skill string) string {
+	text := strings.TrimSpace(cmd.Text)
+	if skill == "" {
+		return strings.TrimSpace(cmd.Command + " " + text)
+	}
+	msg := fmt.Sprintf("Use the %s skill (load it with read_skill)", skill)
+	if text == "" {
+		return msg + "."
+	}
+	return msg + " for this request:\n" + text
+}
+
+// handleSlashCommand turns a slash command into a channel event and
+// dispatches it like a message.
+func (p *Plugin) handleSlashCommand(ctx context.Context, payload []byte, handler channels.EventHandler) error {
+	var cmd slashCommand
+	if err := json.Unmarshal(payload, &cmd); err != nil {
+		return fmt.Errorf("parsing slash command: %w", err)
+	}
+	if cmd.Command == "" || cmd.ChannelID == "" || cmd.UserID == "" {
+		return fmt.Errorf("slash command missing command, channel_id or user_id")
+	}
+
+	// The visible invocation is the thread root: the reply, the thinking
+	// message and the reactions all attach to it.
+	rootTS, err := p.postMessageTS(map[string]any{
+		"channel": cmd.ChannelID,
+		"text":    fmt.Sprintf("<@%s> ran `%s`", cmd.UserID, strings.TrimSpace(cmd.Command+" "+cmd.Text)),
+	})
+	if err != nil {
+		p.respondEphemeral(cmd.ResponseURL, "I can't post in this conversation. Invite me to the channel and try again.")
+		return fmt.Errorf("posting %s invocation: %w", cmd.Command, err)
+	}
+
+	event := &channels.ChannelEvent{
+		Channel:     "slack",
+		WorkspaceID: cmd.ChannelID,
+		UserID:      cmd.UserID,
+		ThreadID:    rootTS,
+		MessageID:   rootTS,
+		EventID:     cmd.TriggerID,
+		Message:     commandMessage(cmd, p.commands[cmd.Command]),
+		Raw:         payload,
+	}
+	p.attributeSender(ctx, event)
+	go p.dispatch(ctx, event, handler)
+	return nil
+}
+
+// respondEphemeral shows text only to the user who ran the command, via
+// the command's response_url. Best-effort.
+func (p *Plugin) respondEphemeral(responseURL, text string) {
+	if responseURL == "" {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{"response_type": "ephemeral", "text": text})
+	req, err := http.NewRequest(http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return
+	}
+	_ = resp.Body.Close()
+}
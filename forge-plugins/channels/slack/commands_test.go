@@ -0,0 +1,75 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+func TestParseCommands(t *testing.T) {
+	got, err := parseCommands(" /triage=k8s-incident-triage, /report = pod-report ,")
+	if err != nil {
+		t.Fatalf("parseCommands() error: %v", err)
+	}
+	if len(got) != 2 || got["/triage"] != "k8s-incident-triage" || got["/report"] != "pod-report" {
+		t.Errorf("parseCommands() = %v", got)
+	}
+	for _, bad := range []string{"triage=x", "/triage", "/triage=", "/=x"} {
+		if _, err := parseCommands(bad); err == nil {
+			t.Errorf("parseCommands(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCommandMessage(t *testing.T) {
+	cmd := slashCommand{Command: "/triage", Text: " pod api-7 crashlooping "}
+	if got := commandMessage(cmd, "k8s-incident-triage"); got != "Use the k8s-incident-triage skill (load it with read_skill) for this request:\npod api-7 crashlooping" {
+		t.Errorf("mapped = %q", got)
+	}
+	if got := commandMessage(slashCommand{Command: "/triage"}, "k8s-incident-triage"); !strings.HasSuffix(got, "read_skill).") {
+		t.Errorf("mapped without text = %q", got)
+	}
+	if got := commandMessage(cmd, ""); got != "/triage pod api-7 crashlooping" {
+		t.Errorf("unmapped = %q", got)
+	}
+}
+
+// TestHandleSlashCommand checks the invocation becomes the thread root
+// and the event carries its ts as the session thread.
+func TestHandleSlashCommand(t *testing.T) {
+	f := newFakeSlack(t)
+	p := f.plugin()
+	p.thinking = false
+	p.commands = map[string]string{"/triage": "k8s-incident-triage"}
+
+	got := make(chan *channels.ChannelEvent, 1)
+	handler := func(ctx context.Context, ev *channels.ChannelEvent) (*a2a.Message, error) {
+		got <- ev
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("triaged")}}, nil
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"command": "/triage", "text": "api-7", "user_id": "U1", "channel_id": "C1", "trigger_id": "trig-1",
+	})
+	if err := p.handleSlashCommand(context.Background(), payload, handler); err != nil {
+		t.Fatalf("handleSlashCommand() error: %v", err)
+	}
+
+	ev := <-got
+	if ev.ThreadID != "1700000000.000200" || ev.MessageID != ev.ThreadID {
+		t.Errorf("event thread = %q / %q, want the posted root ts", ev.ThreadID, ev.MessageID)
+	}
+	if ev.EventID != "trig-1" || !strings.Contains(ev.Message, "k8s-incident-triage") {
+		t.Errorf("event = %+v", ev)
+	}
+	if root := f.find("chat.postMessage", "text", "ran `/triage api-7`"); len(root) != 1 || root[0].payload["channel"] != "C1" {
+		t.Errorf("invocation root not posted: %+v", root)
+	}
+	f.waitFor(t, "chat.postMessage", "text", "triaged")
+	if reply := f.find("chat.postMessage", "text", "triaged"); reply[0].payload["thread_ts"] != "1700000000.000200" {
+		t.Errorf("reply thread_ts = %v, want the root", reply[0].payload["thread_ts"])
+	}
+}
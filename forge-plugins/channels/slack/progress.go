@@ -0,0 +1,222 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// Processing feedback for an inbound message (see dispatch): reactions on
+// the user's message, and a thinking message in the reply thread that
+// follows the task's tool progress and is deleted once the reply is
+// posted. Progress comes from the runtime when the adapter runs in-process
+// (`forge run --with`); without it the thinking message only carries the
+// long-running interim text.
+var _ channels.ProgressAware = (*Plugin)(nil)
+
+// SetProgressSubscriber wires the runtime's task progress feed. Called
+// once by the runtime at startup.
+func (p *Plugin) SetProgressSubscriber(s channels.ProgressSubscriber) {
+	p.progressSubscriber = s
+}
+
+// Default reactions; each can be changed or turned off ("none") in the
+// adapter settings.
+const (
+	defaultAckReaction   = "eyes"
+	defaultDoneReaction  = "white_check_mark"
+	defaultErrorReaction = "x"
+)
+
+// reactionSetting reads a reaction setting: empty keeps def, "none"
+// turns the reaction off, surrounding colons (":eyes:") are dropped.
+func reactionSetting(raw, def string) string {
+	raw = strings.Trim(strings.TrimSpace(raw), ":")
+	switch raw {
+	case "":
+		return def
+	case "none":
+		return ""
+	}
+	return raw
+}
+
+// react adds emoji to the event's message. Best-effort; no-op when the
+// reaction is turned off or the event has no message to react to.
+func (p *Plugin) react(event *channels.ChannelEvent, emoji string) {
+	if emoji != "" && event.MessageID != "" {
+		_ = p.addReaction(event.WorkspaceID, event.MessageID, emoji)
+	}
+}
+
+func (p *Plugin) unreact(event *channels.ChannelEvent, emoji string) {
+	if emoji != "" && event.MessageID != "" {
+		_ = p.removeReaction(event.WorkspaceID, event.MessageID, emoji)
+	}
+}
+
+// replyThreadTS is the thread an event's replies go to.
+func replyThreadTS(event *channels.ChannelEvent) string {
+	if event.ThreadID != "" {
+		return event.ThreadID
+	}
+	return event.MessageID
+}
+
+// progressText is the thinking-message text for a progress update.
+func progressText(u channels.ProgressUpdate) string {
+	if u.Phase == "tool_end" {
+		return fmt.Sprintf("_Working… finished `%s`_", u.Tool)
+	}
+	return fmt.Sprintf("_Working… running `%s`_", u.Tool)
+}
+
+// thinkingMessage is the transient status message posted in the reply
+// thread while the agent works. The first update posts it, later ones
+// edit it in place and clear deletes it. Updates never block the caller —
+// progress arrives on the runtime's tool-hook goroutine — so a single
+// worker posts the latest text.
+type thinkingMessage struct {
+	p        *Plugin
+	channel  string
+	threadTS string
+
+	mu      sync.Mutex
+	ts      string // posted message; empty until the first post completes
+	pending string // latest text
+	shown   string // text last sent to Slack
+	running bool   // worker active
+	cleared bool
+}
+
+// newThinkingMessage returns the event's thinking message, or nil when
+// thinking messages are turned off.
+func (p *Plugin) newThinkingMessage(event *channels.ChannelEvent) *thinkingMessage {
+	if !p.thinking {
+		return nil
+	}
+	return &thinkingMessage{p: p, channel: event.WorkspaceID, threadTS: replyThreadTS(event)}
+}
+
+// update sets the message text. No-op after clear.
+func (t *thinkingMessage) update(text string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = text
+	if t.running || t.cleared {
+		return
+	}
+	t.running = true
+	go t.flush()
+}
+
+func (t *thinkingMessage) flush() {
+	for {
+		t.mu.Lock()
+		text, ts := t.pending, t.ts
+		if t.cleared || text == t.shown {
+			t.running = false
+			t.mu.Unlock()
+			return
+		}
+		t.shown = text
+		t.mu.Unlock()
+
+		if ts != "" {
+			_ = t.p.callAPI("chat.update", map[string]any{"channel": t.channel, "ts": ts, "text": text}, nil)
+			continue
+		}
+		payload := map[string]any{"channel": t.channel, "text": text, "mrkdwn": true}
+		if t.threadTS != "" {
+			payload["thread_ts"] = t.threadTS
+		}
+		posted, err := t.p.postMessageTS(payload)
+		if err != nil {
+			t.p.logWarn("posting thinking message failed", map[string]any{"error": err.Error()})
+		}
+		t.mu.Lock()
+		t.ts = posted
+		cleared := t.cleared
+		t.mu.Unlock()
+		if cleared && posted != "" {
+			// clear ran while the post was in flight.
+			t.p.deleteMessage(t.channel, posted)
+		}
+	}
+}
+
+// clear deletes the message; later updates are ignored.
+func (t *thinkingMessage) clear() {
+	t.mu.Lock()
+	t.cleared = true
+	ts := t.ts
+	t.ts = ""
+	t.mu.Unlock()
+	if ts != "" {
+		t.p.deleteMessage(t.channel, ts)
+	}
+}
+
+// deleteMessage deletes one of the bot's messages. Best-effort.
+func (p *Plugin) deleteMessage(channel, ts string) {
+	_ = p.callAPI("chat.delete", map[string]any{"channel": channel, "ts": ts}, nil)
+}
+
+// postMessageTS posts via chat.postMessage and returns the new message's
+// ts. Unlike postMessage it fails on an `"ok": false` response.
+func (p *Plugin) postMessageTS(payload map[string]any) (string, error) {
+	var out struct {
+		TS string `json:"ts"`
+	}
+	if err := p.callAPI("chat.postMessage", payload, &out); err != nil {
+		return "", err
+	}
+	return out.TS, nil
+}
+
+// callAPI POSTs payload as JSON to a Web API method and decodes the
+// response into out (when non-nil). An `"ok": false` response is an error
+// carrying Slack's error code.
+func (p *Plugin) callAPI(method string, payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling %s: %w", method, err)
+	}
+	req, err := http.NewRequest(http.MethodPost, p.apiBase+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.botToken)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("%s: decoding response (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return fmt.Errorf("%s: decoding response: %w", method, err)
+	}
+	if !status.OK {
+		return fmt.Errorf("%s: %s", method, status.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return fmt.Errorf("%s: decoding response: %w", method, err)
+		}
+	}
+	return nil
+}
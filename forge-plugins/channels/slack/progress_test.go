@@ -0,0 +1,181 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+type slackCall struct {
+	method  string
+	payload map[string]any
+}
+
+// fakeSlack records Web API calls. chat.postMessage returns a ts.
+type fakeSlack struct {
+	mu    sync.Mutex
+	calls []slackCall
+	srv   *httptest.Server
+}
+
+func newFakeSlack(t *testing.T) *fakeSlack {
+	t.Helper()
+	f := &fakeSlack{}
+	f.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		method := strings.TrimPrefix(r.URL.Path, "/")
+		f.mu.Lock()
+		f.calls = append(f.calls, slackCall{method: method, payload: payload})
+		f.mu.Unlock()
+		if method == "chat.postMessage" {
+			w.Write([]byte(`{"ok":true,"ts":"1700000000.000200"}`)) //nolint:errcheck
+			return
+		}
+		w.Write([]byte(`{"ok":true}`)) //nolint:errcheck
+	}))
+	t.Cleanup(f.srv.Close)
+	return f
+}
+
+func (f *fakeSlack) plugin() *Plugin {
+	p := New()
+	p.botToken = "xoxb-test"
+	p.apiBase = f.srv.URL
+	return p
+}
+
+// find returns the calls to method whose payload field key contains want.
+func (f *fakeSlack) find(method, key, want string) []slackCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []slackCall
+	for _, c := range f.calls {
+		if v, _ := c.payload[key].(string); c.method == method && strings.Contains(v, want) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (f *fakeSlack) waitFor(t *testing.T, method, key, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(f.find(method, key, want)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("no %s call with %s containing %q; calls: %+v", method, key, want, f.calls)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReactionSetting(t *testing.T) {
+	for raw, want := range map[string]string{
+		"":           "eyes",
+		"none":       "",
+		":rocket:":   "rocket",
+		" hourglass": "hourglass",
+	} {
+		if got := reactionSetting(raw, "eyes"); got != want {
+			t.Errorf("reactionSetting(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestInit_ProcessingSettings(t *testing.T) {
+	p := New()
+	err := p.Init(channels.ChannelConfig{
+		Adapter: "slack",
+		Settings: map[string]string{
+			"app_token":        "xapp-test",
+			"bot_token":        "xoxb-test",
+			"done_reaction":    "none",
+			"thinking_message": "false",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Init() unexpected error: %v", err)
+	}
+	if p.ackReaction != "eyes" || p.doneReaction != "" || p.errorReaction != "x" {
+		t.Errorf("reactions = %q/%q/%q", p.ackReaction, p.doneReaction, p.errorReaction)
+	}
+	if p.thinking {
+		t.Error("thinking_message: false should turn the thinking message off")
+	}
+}
+
+// TestDispatch_ProgressAndReactions walks one message through dispatch:
+// ack reaction, a thinking message that follows tool progress and is
+// deleted, the threaded reply and the done reaction.
+func TestDispatch_ProgressAndReactions(t *testing.T) {
+	f := newFakeSlack(t)
+	p := f.plugin()
+
+	var subscribed string
+	var progress func(channels.ProgressUpdate)
+	p.SetProgressSubscriber(func(taskID string, fn func(channels.ProgressUpdate)) func() {
+		subscribed, progress = taskID, fn
+		return func() {}
+	})
+
+	event := &channels.ChannelEvent{Channel: "slack", WorkspaceID: "C1", UserID: "U1", ThreadID: "1700000000.000100", MessageID: "1700000000.000100"}
+	handler := func(ctx context.Context, ev *channels.ChannelEvent) (*a2a.Message, error) {
+		progress(channels.ProgressUpdate{Phase: "tool_start", Tool: "web_search"})
+		f.waitFor(t, "chat.postMessage", "text", "running `web_search`")
+		progress(channels.ProgressUpdate{Phase: "tool_end", Tool: "web_search"})
+		f.waitFor(t, "chat.update", "text", "finished `web_search`")
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("the answer")}}, nil
+	}
+	p.dispatch(context.Background(), event, handler)
+
+	if subscribed != "slack-C1-1700000000.000100" {
+		t.Errorf("subscribed to %q, want the event's session task ID", subscribed)
+	}
+	thinking := f.find("chat.postMessage", "text", "running")
+	if len(thinking) != 1 || thinking[0].payload["thread_ts"] != "1700000000.000100" {
+		t.Errorf("thinking message should be posted once in the thread: %+v", thinking)
+	}
+	if len(f.find("chat.delete", "ts", "1700000000.000200")) != 1 {
+		t.Error("thinking message should be deleted when the reply is ready")
+	}
+	if len(f.find("chat.postMessage", "text", "the answer")) != 1 {
+		t.Error("reply not posted")
+	}
+	if len(f.find("reactions.add", "name", "eyes")) != 1 || len(f.find("reactions.remove", "name", "eyes")) != 1 {
+		t.Error("ack reaction should be added then removed")
+	}
+	if len(f.find("reactions.add", "name", "white_check_mark")) != 1 {
+		t.Error("done reaction missing")
+	}
+}
+
+func TestDispatch_FailedTaskReaction(t *testing.T) {
+	f := newFakeSlack(t)
+	p := f.plugin()
+	p.thinking = false
+
+	event := &channels.ChannelEvent{Channel: "slack", WorkspaceID: "C1", MessageID: "1700000000.000100"}
+	handler := func(ctx context.Context, ev *channels.ChannelEvent) (*a2a.Message, error) {
+		return &a2a.Message{
+			Role:     a2a.MessageRoleAgent,
+			Parts:    []a2a.Part{a2a.NewTextPart("Something went wrong.")},
+			Metadata: map[string]any{a2a.MetadataKeyError: a2a.NewTaskError(a2a.ErrorInternal, "boom")},
+		}, nil
+	}
+	p.dispatch(context.Background(), event, handler)
+
+	if len(f.find("reactions.add", "name", "x")) != 1 {
+		t.Error("a failed task's fallback reply should get the error reaction")
+	}
+	if len(f.find("reactions.add", "name", "white_check_mark")) != 0 {
+		t.Error("a failed task must not get the done reaction")
+	}
+}
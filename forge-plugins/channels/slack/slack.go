@@ -27,20 +27,34 @@ const slackAPIBase = "https://slack.com/api"
 // "Researching..." message for slow handler responses.
 const longRunningThreshold = 15 * time.Second
 
+const interimText = "Researching, I'll post the result shortly..."
+
 // Plugin implements channels.ChannelPlugin for Slack using Socket Mode.
 type Plugin struct {
 	appToken    string
 	botToken    string
-	botUserID   string          // resolved at startup via auth.test
-	ownBotID    string          // resolved at startup via auth.test; used as the self-loop guard
-	allowBotIDs map[string]bool // bot_ids whose @mentions are admitted; default empty (no other bots admitted)
-	wsConn      *websocket.Conn
-	connMu      sync.Mutex
-	stopCh      chan struct{}
-	client      *http.Client
-	apiBase     string // overridable for tests
-	dedupMu     sync.Mutex
-	dedupCache  map[string]time.Time
+	botUserID   string            // resolved at startup via auth.test
+	ownBotID    string            // resolved at startup via auth.test; used as the self-loop guard
+	allowBotIDs map[string]bool   // bot_ids whose @mentions are admitted; default empty (no other bots admitted)
+	format      markdown.Format   // outbound format profile: mrkdwn (default), blocks or plain
+	commands    map[string]string // slash command → skill name (commands setting)
+
+	// Processing feedback (progress.go): reactions on the inbound message
+	// ("" = off) and the thinking message, which follows the task's tool
+	// progress when the runtime wires progressSubscriber.
+	ackReaction        string
+	doneReaction       string
+	errorReaction      string
+	thinking           bool
+	progressSubscriber channels.ProgressSubscriber
+	wsConn             *websocket.Conn
+	connMu             sync.Mutex
+	stopCh             chan struct{}
+	client             *http.Client
+	apiBase            string // overridable for tests
+	fileHost           string // the host file downloads must be on; overridable for tests
+	dedupMu            sync.Mutex
+	dedupCache         map[string]time.Time
 
 	// approvalResolver is wired by the runtime (SetApprovalResolver) so an
 	// interactive DEFER approval click resolves the deferred task (#310).
@@ -89,6 +103,12 @@ func New() *Plugin {
 	return &Plugin{
 		client:         &http.Client{Timeout: 30 * time.Second},
 		apiBase:        slackAPIBase,
+		fileHost:       slackFileHost,
+		format:         markdown.FormatMrkdwn,
+		ackReaction:    defaultAckReaction,
+		doneReaction:   defaultDoneReaction,
+		errorReaction:  defaultErrorReaction,
+		thinking:       true,
 		dedupCache:     make(map[string]time.Time),
 		chanIDCache:    make(map[string]string),
 		userEmailCache: make(map[string]string),
@@ -115,6 +135,20 @@ func (p *Plugin) Init(cfg channels.ChannelConfig) error {
 	// dropped regardless of this list (see ownBotID guard in Start).
 	p.allowBotIDs = parseAllowBotIDs(settings["allow_bot_ids"])
 
+	format, err := markdown.ResolveFormat("slack", settings["format"], markdown.FormatMrkdwn, markdown.FormatBlocks, markdown.FormatPlain)
+	if err != nil {
+		return err
+	}
+	p.format = format
+
+	if p.commands, err = parseCommands(settings["commands"]); err != nil {
+		return err
+	}
+	p.ackReaction = reactionSetting(settings["ack_reaction"], defaultAckReaction)
+	p.doneReaction = reactionSetting(settings["done_reaction"], defaultDoneReaction)
+	p.errorReaction = reactionSetting(settings["error_reaction"], defaultErrorReaction)
+	p.thinking = settings["thinking_message"] != "false"
+
 	return nil
 }
 
@@ -415,6 +449,13 @@ func (p *Plugin) readLoop(ctx context.Context, conn *websocket.Conn, handler cha
 			continue
 		}
 
+		if envelope.Type == "slash_commands" {
+			if err := p.handleSlashCommand(ctx, envelope.Payload, handler); err != nil {
+				p.logWarn("slash command handling failed", map[string]any{"error": err.Error()})
+			}
+			continue
+		}
+
 		if envelope.Type != "events_api" {
 			continue
 		}
@@ -435,8 +476,9 @@ func (p *Plugin) readLoop(ctx context.Context, conn *websocket.Conn, handler cha
 		}
 
 		// Skip message subtypes (message_deleted, message_changed,
-		// channel_join, etc.) — only process plain user messages.
-		if payload.Event.SubType != "" {
+		// channel_join, etc.) — only process plain user messages and
+		// messages sharing files.
+		if payload.Event.SubType != "" && payload.Event.SubType != "file_share" {
 			continue
 		}
 
@@ -475,72 +517,102 @@ func (p *Plugin) readLoop(ctx context.Context, conn *websocket.Conn, handler cha
 			event.Message = stripBotMention(event.Message, p.botUserID)
 		}
 
-		// Attribute the task to the SENDER (§19 P3): resolve their email
-		// (users.info, #313 cache) so the runtime executes the task as that
-		// user — delegated (auth.type=user) MCP tools key consent + per-user
-		// tokens on this subject. Best-effort: unresolved leaves the loopback
-		// identity, and delegated tools then can't identify the consenting
-		// user (field-hit 2026-07-21: consent DM addressed to
-		// "forge-internal").
-		if email, eErr := p.resolveUserEmail(ctx, event.UserID); eErr == nil {
-			event.UserEmail = email
-		} else {
-			fmt.Printf("  slack: sender email unresolved for %s: %v — delegated tools won't know the user\n", event.UserID, eErr)
-		}
-
-		go func() {
-			// Open channel.slack.deliver around the full per-message
-			// pipeline (parse + thread context fetch + internal A2A
-			// POST) so operators can see "how long did Slack→agent
-			// take?" from the flame graph. The router injects the
-			// traceparent on the internal POST so the agent's
-			// a2a.tasks/send span nests under this one. Issue #187.
-			spanCtx, _, finish := channels.StartDeliverSpan(ctx, "slack", event)
-			var handlerErr error
-			defer finish(&handlerErr)
-
-			// Add :eyes: reaction to indicate we received the message.
-			_ = p.addReaction(event.WorkspaceID, event.MessageID, "eyes")
-
-			// If the handler takes longer than 15s, send an interim message.
-			done := make(chan struct{})
-			go func() {
-				select {
-				case <-time.After(longRunningThreshold):
-					threadTS := event.ThreadID
-					if threadTS == "" {
-						threadTS = event.MessageID
-					}
-					payload := map[string]any{
-						"channel": event.WorkspaceID,
-						"text":    "Researching, I'll post the result shortly...",
-						"mrkdwn":  true,
-					}
-					if threadTS != "" {
-						payload["thread_ts"] = threadTS
-					}
-					_ = p.postMessage(payload)
-				case <-done:
-				}
-			}()
-
-			resp, err := handler(spanCtx, event)
-			close(done)
+		p.attributeSender(ctx, event)
+		go p.dispatch(ctx, event, handler)
+	}
+}
 
-			// Remove the :eyes: reaction.
-			_ = p.removeReaction(event.WorkspaceID, event.MessageID, "eyes")
+// attributeSender attributes the task to the SENDER (§19 P3): resolve
+// their email (users.info, #313 cache) so the runtime executes the task as
+// that user — delegated (auth.type=user) MCP tools key consent + per-user
+// tokens on this subject. Best-effort: unresolved leaves the loopback
+// identity, and delegated tools then can't identify the consenting user
+// (field-hit 2026-07-21: consent DM addressed to "forge-internal").
+func (p *Plugin) attributeSender(ctx context.Context, event *channels.ChannelEvent) {
+	if email, eErr := p.resolveUserEmail(ctx, event.UserID); eErr == nil {
+		event.UserEmail = email
+	} else {
+		fmt.Printf("  slack: sender email unresolved for %s: %v — delegated tools won't know the user\n", event.UserID, eErr)
+	}
+}
 
-			if err != nil {
-				handlerErr = err
-				fmt.Printf("slack: handler error: %v\n", err)
+// dispatch runs one inbound message through the handler and posts the
+// reply, with processing feedback around it: the acknowledgement
+// reaction, the thinking message (or, with it disabled, the long-running
+// interim message) and the done / error reaction.
+func (p *Plugin) dispatch(ctx context.Context, event *channels.ChannelEvent, handler channels.EventHandler) {
+	// Open channel.slack.deliver around the full per-message
+	// pipeline (parse + thread context fetch + internal A2A
+	// POST) so operators can see "how long did Slack→agent
+	// take?" from the flame graph. The router injects the
+	// traceparent on the internal POST so the agent's
+	// a2a.tasks/send span nests under this one. Issue #187.
+	spanCtx, _, finish := channels.StartDeliverSpan(ctx, "slack", event)
+	var handlerErr error
+	defer finish(&handlerErr)
+
+	// Acknowledge receipt (default :eyes:).
+	p.react(event, p.ackReaction)
+
+	thinking := p.newThinkingMessage(event)
+	stopProgress := func() {}
+	if thinking != nil && p.progressSubscriber != nil {
+		stopProgress = p.progressSubscriber(channels.SessionTaskID(event), func(u channels.ProgressUpdate) {
+			thinking.update(progressText(u))
+		})
+	}
+
+	// If the handler takes longer than 15s, tell the user so.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-time.After(longRunningThreshold):
+			if thinking != nil {
+				thinking.update(interimText)
 				return
 			}
-			if sendErr := p.SendResponse(event, resp); sendErr != nil {
-				handlerErr = sendErr
-				fmt.Printf("slack: send response error: %v\n", sendErr)
+			payload := map[string]any{
+				"channel": event.WorkspaceID,
+				"text":    interimText,
+				"mrkdwn":  true,
+			}
+			if ts := replyThreadTS(event); ts != "" {
+				payload["thread_ts"] = ts
 			}
-		}()
+			_ = p.postMessage(payload)
+		case <-done:
+		}
+	}()
+
+	resp, err := handler(spanCtx, event)
+	close(done)
+	stopProgress()
+	if thinking != nil {
+		thinking.clear()
+	}
+	p.unreact(event, p.ackReaction)
+
+	if channels.IsDropped(err) {
+		return // a retry of a message already being answered, or dropped by policy
+	}
+	if err != nil {
+		handlerErr = err
+		p.react(event, p.errorReaction)
+		fmt.Printf("slack: handler error: %v\n", err)
+		return
 	}
+	if sendErr := p.SendResponse(event, resp); sendErr != nil {
+		handlerErr = sendErr
+		p.react(event, p.errorReaction)
+		fmt.Printf("slack: send response error: %v\n", sendErr)
+		return
+	}
+	// A fallback reply for a failed task still reads as a failure.
+	if a2a.TaskErrorFromMessage(resp) != nil {
+		p.react(event, p.errorReaction)
+		return
+	}
+	p.react(event, p.doneReaction)
 }
 
 func (p *Plugin) Stop() error {
@@ -606,7 +678,9 @@ func (p *Plugin) NormalizeEvent(raw []byte) (*channels.ChannelEvent, error) {
 		UserID:      payload.Event.User,
 		ThreadID:    threadID,
 		MessageID:   messageID,
+		EventID:     payload.EventID,
 		Message:     payload.Event.Text,
+		Attachments: fileAttachments(payload.Event.Files),
 		Raw:         raw,
 	}, nil
 }
@@ -628,11 +702,6 @@ func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Messag
 			fileName = "research-report.md"
 		}
 
-		threadTS := event.ThreadID
-		if threadTS == "" {
-			threadTS = event.MessageID
-		}
-
 		if err := p.uploadFile(event, fileName, fileContent); err != nil {
 			fmt.Printf("slack: file upload failed: %v (falling back to chunked messages)\n", err)
 			// Fall back: send the file content as chunked messages.
@@ -648,17 +717,7 @@ func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Messag
 				summary, _ = markdown.SplitSummaryAndReport(summary)
 			}
 		}
-		summaryText := summary + "\n\n_Full report attached as file above._"
-		summaryMrkdwn := markdown.ToSlackMrkdwn(summaryText)
-		payload := map[string]any{
-			"channel": event.WorkspaceID,
-			"text":    summaryMrkdwn,
-			"mrkdwn":  true,
-		}
-		if threadTS != "" {
-			payload["thread_ts"] = threadTS
-		}
-		return p.postMessage(payload)
+		return p.sendChunked(event, summary+"\n\n*Full report attached as file above.*")
 	}
 
 	// No file parts — use text-based logic.
@@ -669,38 +728,23 @@ func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Messag
 			summary, report = markdown.SplitSummaryAndReport(text)
 		}
 
-		threadTS := event.ThreadID
-		if threadTS == "" {
-			threadTS = event.MessageID
-		}
-
 		if err := p.uploadFile(event, "research-report.md", report); err != nil {
 			fmt.Printf("slack: file upload failed: %v (falling back to chunked messages)\n", err)
 			return p.sendChunked(event, text)
 		}
 
-		summaryText := summary + "\n\n_Full report attached as file above._"
-		summaryMrkdwn := markdown.ToSlackMrkdwn(summaryText)
-		payload := map[string]any{
-			"channel": event.WorkspaceID,
-			"text":    summaryMrkdwn,
-			"mrkdwn":  true,
-		}
-		if threadTS != "" {
-			payload["thread_ts"] = threadTS
-		}
-		return p.postMessage(payload)
+		return p.sendChunked(event, summary+"\n\n*Full report attached as file above.*")
 	}
 
-	mrkdwn := markdown.ToSlackMrkdwn(text)
-	chunks := markdown.SplitMessage(mrkdwn, 4000)
+	return p.sendChunked(event, text)
+}
 
-	for i, chunk := range chunks {
-		payload := map[string]any{
-			"channel": event.WorkspaceID,
-			"text":    chunk,
-			"mrkdwn":  true,
-		}
+// sendChunked sends text in the configured format profile as one or more
+// messages, threading the first under the triggering message. Also the
+// fallback for a failed file upload.
+func (p *Plugin) sendChunked(event *channels.ChannelEvent, text string) error {
+	for i, payload := range p.messagePayloads(text) {
+		payload["channel"] = event.WorkspaceID
 		if i == 0 {
 			if event.ThreadID != "" {
 				payload["thread_ts"] = event.ThreadID
@@ -715,28 +759,55 @@ func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Messag
 	return nil
 }
 
-// sendChunked sends text as chunked messages (fallback for failed file upload).
-func (p *Plugin) sendChunked(event *channels.ChannelEvent, text string) error {
-	mrkdwn := markdown.ToSlackMrkdwn(text)
-	chunks := markdown.SplitMessage(mrkdwn, 4000)
-	for i, chunk := range chunks {
-		payload := map[string]any{
-			"channel": event.WorkspaceID,
-			"text":    chunk,
-			"mrkdwn":  true,
+// messagePayloads renders text as chat.postMessage payloads (without
+// channel or thread) in the configured format profile: mrkdwn text,
+// Block Kit blocks with a plain-text notification fallback, or plain
+// text with mrkdwn parsing off.
+func (p *Plugin) messagePayloads(text string) []map[string]any {
+	var payloads []map[string]any
+	switch p.format {
+	case markdown.FormatBlocks:
+		blocks := markdown.ToSlackBlocks(text)
+		fallback := notificationText(text)
+		for len(blocks) > 0 {
+			n := min(len(blocks), markdown.SlackMaxBlocks)
+			payloads = append(payloads, map[string]any{
+				"text":   fallback,
+				"blocks": blocks[:n],
+			})
+			blocks = blocks[n:]
 		}
-		if i == 0 {
-			if event.ThreadID != "" {
-				payload["thread_ts"] = event.ThreadID
-			} else if event.MessageID != "" {
-				payload["thread_ts"] = event.MessageID
-			}
+		if len(payloads) > 0 {
+			return payloads
 		}
-		if err := p.postMessage(payload); err != nil {
-			return err
+	case markdown.FormatPlain:
+		for _, chunk := range markdown.SplitMessage(markdown.ToPlain(text), 4000) {
+			payloads = append(payloads, map[string]any{
+				"text":   chunk,
+				"mrkdwn": false,
+			})
 		}
+		return payloads
 	}
-	return nil
+	for _, chunk := range markdown.SplitMessage(markdown.Render(text, markdown.FormatMrkdwn), 4000) {
+		payloads = append(payloads, map[string]any{
+			"text":   chunk,
+			"mrkdwn": true,
+		})
+	}
+	return payloads
+}
+
+// notificationText is the top-level text of a Block Kit message, shown in
+// notifications and by clients that cannot render blocks: the first
+// non-empty line of the plain rendering.
+func notificationText(text string) string {
+	for _, line := range strings.Split(markdown.ToPlain(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return text
 }
 
 // uploadFile uploads content as a file to a Slack channel using the
@@ -995,7 +1066,8 @@ func unwrapJSONContent(text string) string {
 	return text
 }
 
-// extractText concatenates all text parts from an A2A message.
+// extractText concatenates all text parts from an A2A message, followed by
+// footnotes for any sources it cites.
 func extractText(msg *a2a.Message) string {
 	if msg == nil {
 		return "(no response)"
@@ -1012,6 +1084,9 @@ func extractText(msg *a2a.Message) string {
 	if text == "" {
 		text = "(no text response)"
 	}
+	if notes := channels.CitationFootnotes(msg.Citations); notes != "" {
+		text += "\n\n" + notes
+	}
 	return text
 }
 
@@ -1048,18 +1123,20 @@ type socketEnvelope struct {
 
 // slackEventPayload represents the outer Slack event callback structure.
 type slackEventPayload struct {
-	TeamID string     `json:"team_id"`
-	Event  slackEvent `json:"event"`
+	TeamID  string     `json:"team_id"`
+	EventID string     `json:"event_id"`
+	Event   slackEvent `json:"event"`
 }
 
 // slackEvent represents the inner event fields we care about.
 type slackEvent struct {
-	Type     string `json:"type"`
-	SubType  string `json:"subtype"`
-	Channel  string `json:"channel"`
-	User     string `json:"user"`
-	Text     string `json:"text"`
-	TS       string `json:"ts"`
-	ThreadTS string `json:"thread_ts"`
-	BotID    string `json:"bot_id"`
+	Type     string      `json:"type"`
+	SubType  string      `json:"subtype"`
+	Channel  string      `json:"channel"`
+	User     string      `json:"user"`
+	Text     string      `json:"text"`
+	TS       string      `json:"ts"`
+	ThreadTS string      `json:"thread_ts"`
+	BotID    string      `json:"bot_id"`
+	Files    []slackFile `json:"files,omitempty"`
 }
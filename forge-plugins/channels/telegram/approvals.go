@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// Interactive DEFER approvals: the bot posts the request with Approve and
+// Reject buttons to the target chat, and a press resolves the deferred
+// task via the wired resolver. Telegram has no email for the approver, so
+// a configured approver allowlist denies Telegram decisions (fail closed).
+var _ channels.ApprovalDeliverer = (*Plugin)(nil)
+
+// SetApprovalResolver wires the callback invoked when an approver presses
+// a button. Called once by the runtime at startup.
+func (p *Plugin) SetApprovalResolver(r channels.ApprovalResolver) {
+	p.approvalResolver = r
+}
+
+// DeliverApproval posts the approval request to req.Target, a chat ID or
+// a public "@channelname".
+func (p *Plugin) DeliverApproval(ctx context.Context, req channels.ApprovalRequest) error {
+	if req.Target == "" {
+		return fmt.Errorf("telegram DeliverApproval: empty target chat")
+	}
+	approve, ok := callbackButton("✅ Approve", verbApprove, req.TaskID)
+	reject, _ := callbackButton("⛔ Reject", verbReject, req.TaskID)
+	if !ok {
+		return fmt.Errorf("telegram DeliverApproval: task id %q too long for a button", req.TaskID)
+	}
+	return p.sendMessage(map[string]any{
+		"chat_id":      req.Target,
+		"text":         approvalText(req),
+		"parse_mode":   "HTML",
+		"reply_markup": inlineKeyboardMarkup{InlineKeyboard: [][]inlineKeyboardButton{{approve, reject}}},
+	})
+}
+
+// approvalText is the approval request message, in Telegram HTML.
+func approvalText(req channels.ApprovalRequest) string {
+	text := fmt.Sprintf("<b>Approval required</b> for <code>%s</code>", html.EscapeString(req.Tool))
+	if req.Context != "" {
+		text += "\n" + html.EscapeString(req.Context)
+	}
+	if req.Timeout > 0 {
+		text += fmt.Sprintf("\n<i>Auto-denies in %s.</i>", req.Timeout.Round(time.Second))
+	}
+	return text
+}
+
+// handleApprovalCallback records an Approve or Reject press and replaces
+// the buttons with the outcome. A decision the runtime refuses (already
+// resolved, timed out, approver not allowed) is shown as an alert and the
+// buttons stay.
+func (p *Plugin) handleApprovalCallback(ctx context.Context, cq *telegramCallbackQuery, verb, taskID string) (string, bool) {
+	if p.approvalResolver == nil {
+		return "Approvals are not enabled for this agent.", true
+	}
+	dec := channels.ApprovalDecision{
+		TaskID:   taskID,
+		Decision: "approve",
+		Approver: actorName(cq.From),
+	}
+	outcome := "✅ Approved by " + dec.Approver
+	if verb == verbReject {
+		dec.Decision = "reject"
+		outcome = "⛔ Rejected by " + dec.Approver
+	}
+	if err := p.approvalResolver(ctx, dec); err != nil {
+		return fmt.Sprintf("Could not record %s: %v", dec.Decision, err), true
+	}
+	p.finishKeyboardMessage(cq.Message, outcome)
+	return "Recorded.", false
+}
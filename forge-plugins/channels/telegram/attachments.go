@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// telegramPhotoSize is one of the sizes Telegram offers a photo in.
+type telegramPhotoSize struct {
+	FileID   string `json:"file_id"`
+	FileSize int64  `json:"file_size,omitempty"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// fileAttachments describes a message's photo, in its largest size,
+// and document. Their content is fetched by FetchAttachment.
+func fileAttachments(msg *telegramMessage) []channels.Attachment {
+	var atts []channels.Attachment
+	if len(msg.Photo) > 0 {
+		largest := msg.Photo[0]
+		for _, s := range msg.Photo[1:] {
+			if s.Width*s.Height > largest.Width*largest.Height {
+				largest = s
+			}
+		}
+		atts = append(atts, channels.Attachment{
+			Name: "photo.jpg", MimeType: "image/jpeg", ID: largest.FileID, Size: largest.FileSize,
+		})
+	}
+	if d := msg.Document; d != nil {
+		att := channels.Attachment{Name: d.FileName, MimeType: d.MimeType, ID: d.FileID, Size: d.FileSize}
+		if att.Name == "" {
+			att.Name = "document"
+		}
+		if att.MimeType == "" {
+			att.MimeType = "application/octet-stream"
+		}
+		atts = append(atts, att)
+	}
+	return atts
+}
+
+// FetchAttachment implements channels.AttachmentFetcher. Files are
+// downloaded through the Bot API, so only from Telegram.
+func (p *Plugin) FetchAttachment(ctx context.Context, a channels.Attachment, maxBytes int64) ([]byte, error) {
+	if a.ID == "" {
+		return nil, fmt.Errorf("telegram attachment %q has no file ID", a.Name)
+	}
+	return p.downloadFile(ctx, a.ID, min(maxBytes, maxDownloadBytes))
+}
@@ -0,0 +1,68 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const photoUpdate = `{
+	"update_id": 102,
+	"message": {
+		"message_id": 44,
+		"from": {"id": 12345},
+		"chat": {"id": 67890},
+		"caption": "what's wrong here?",
+		"photo": [
+			{"file_id": "AgAD-small", "width": 90, "height": 60, "file_size": 1200},
+			{"file_id": "AgAD-large", "width": 1280, "height": 853, "file_size": 5}
+		]
+	}
+}`
+
+func TestNormalizeEvent_Photo(t *testing.T) {
+	event, err := New().NormalizeEvent([]byte(photoUpdate))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.Message != "what's wrong here?" {
+		t.Errorf("Message = %q, want the caption", event.Message)
+	}
+	if len(event.Attachments) != 1 || event.Attachments[0].ID != "AgAD-large" || event.Attachments[0].MimeType != "image/jpeg" {
+		t.Errorf("Attachments = %+v, want the largest photo size", event.Attachments)
+	}
+
+	doc, _ := New().NormalizeEvent([]byte(`{"update_id":1,"message":{"message_id":1,"from":{"id":1},"chat":{"id":1},
+		"document":{"file_id":"BQAD-doc","file_name":"q3.pdf","mime_type":"application/pdf","file_size":2048}}}`))
+	if len(doc.Attachments) != 1 || doc.Attachments[0].Name != "q3.pdf" || doc.Attachments[0].Size != 2048 {
+		t.Errorf("document Attachments = %+v", doc.Attachments)
+	}
+}
+
+func TestFetchAttachment(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest-token/getFile":
+			fmt.Fprint(w, `{"ok":true,"result":{"file_path":"photos/file_1.jpg","file_size":5}}`)
+		case "/file/bottest-token/photos/file_1.jpg":
+			fmt.Fprint(w, "\xff\xd8jpg")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+
+	event, _ := p.NormalizeEvent([]byte(photoUpdate))
+	data, err := p.FetchAttachment(context.Background(), event.Attachments[0], 1<<20)
+	if err != nil || string(data) != "\xff\xd8jpg" {
+		t.Errorf("FetchAttachment = %q, %v", data, err)
+	}
+	if _, err := p.FetchAttachment(context.Background(), event.Attachments[0], 4); err == nil {
+		t.Error("a file over maxBytes should not be fetched")
+	}
+}
@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Inline keyboards let operators act on approvals and schedules with a tap
+// instead of a typed command. A button press arrives as a callback_query
+// update — over polling and the webhook alike — whose data is
+// "<verb>:<id>", the id being the task or schedule the button acts on.
+
+const (
+	// maxCallbackData is Telegram's limit on a button's callback_data.
+	maxCallbackData = 64
+	// callbackTimeout bounds acting on one button press.
+	callbackTimeout = 30 * time.Second
+)
+
+// Callback verbs.
+const (
+	verbApprove       = "ap"
+	verbReject        = "rj"
+	verbPause         = "sp"
+	verbResume        = "sr"
+	verbDelete        = "sd" // asks for confirmation
+	verbConfirmDelete = "sx"
+)
+
+// callbackButton builds a button whose press sends verb and id back. ok is
+// false when the data would exceed Telegram's limit.
+func callbackButton(text, verb, id string) (inlineKeyboardButton, bool) {
+	data := verb + ":" + id
+	return inlineKeyboardButton{Text: text, CallbackData: data}, len(data) <= maxCallbackData
+}
+
+// parseCallbackData splits a button's callback_data into verb and id.
+func parseCallbackData(data string) (verb, id string, ok bool) {
+	verb, id, ok = strings.Cut(data, ":")
+	return verb, id, ok && id != ""
+}
+
+// actorName identifies who pressed a button: the @username, or the
+// numeric user id for users without one.
+func actorName(u telegramUser) string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	return strconv.FormatInt(u.ID, 10)
+}
+
+// handleCallback acts on a button press. Telegram shows a spinner on the
+// button until the query is answered, so every press is answered — with a
+// short notice, or an alert when the action failed.
+func (p *Plugin) handleCallback(cq *telegramCallbackQuery) {
+	ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+	defer cancel()
+
+	var notice string
+	var alert bool
+	verb, id, ok := parseCallbackData(cq.Data)
+	switch {
+	case !ok || cq.Message == nil:
+	case verb == verbApprove || verb == verbReject:
+		notice, alert = p.handleApprovalCallback(ctx, cq, verb, id)
+	case verb == verbPause || verb == verbResume || verb == verbDelete || verb == verbConfirmDelete:
+		notice, alert = p.handleScheduleCallback(ctx, cq, verb, id)
+	}
+	p.answerCallback(cq.ID, notice, alert)
+}
+
+// answerCallback answers a callback query. Best-effort.
+func (p *Plugin) answerCallback(queryID, text string, alert bool) {
+	payload := map[string]any{"callback_query_id": queryID}
+	if text != "" {
+		payload["text"] = text
+		payload["show_alert"] = alert
+	}
+	_ = p.callAPI("answerCallbackQuery", payload)
+}
+
+// finishKeyboardMessage replaces a keyboard message's text with its
+// original text plus an outcome line, which also removes the keyboard.
+// Best-effort — the action is already recorded.
+func (p *Plugin) finishKeyboardMessage(msg *telegramMessage, outcome string) {
+	_ = p.callAPI("editMessageText", map[string]any{
+		"chat_id":    msg.Chat.ID,
+		"message_id": msg.MessageID,
+		"text":       strings.TrimSpace(msg.Text) + "\n\n" + outcome,
+	})
+}
+
+// replaceKeyboard swaps a message's keyboard.
+func (p *Plugin) replaceKeyboard(msg *telegramMessage, markup *inlineKeyboardMarkup) error {
+	return p.callAPI("editMessageReplyMarkup", map[string]any{
+		"chat_id":      msg.Chat.ID,
+		"message_id":   msg.MessageID,
+		"reply_markup": markup,
+	})
+}
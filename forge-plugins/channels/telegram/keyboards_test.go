@@ -0,0 +1,284 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// apiCall is one recorded Bot API call.
+type apiCall struct {
+	Method  string
+	Payload map[string]any
+}
+
+// fakeBotAPI records Bot API calls and answers each with ok.
+type fakeBotAPI struct {
+	mu    sync.Mutex
+	calls []apiCall
+}
+
+func newFakeBotAPI(t *testing.T) (*fakeBotAPI, *Plugin) {
+	t.Helper()
+	f := &fakeBotAPI{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		f.mu.Lock()
+		f.calls = append(f.calls, apiCall{Method: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], Payload: payload})
+		f.mu.Unlock()
+		fmt.Fprint(w, `{"ok":true,"result":{}}`)
+	}))
+	t.Cleanup(srv.Close)
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+	return f, p
+}
+
+func (f *fakeBotAPI) methods() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, c := range f.calls {
+		out = append(out, c.Method)
+	}
+	return out
+}
+
+func (f *fakeBotAPI) last(method string) map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.calls) - 1; i >= 0; i-- {
+		if f.calls[i].Method == method {
+			return f.calls[i].Payload
+		}
+	}
+	return nil
+}
+
+// buttonData returns the callback_data of a payload's keyboard buttons.
+func buttonData(payload map[string]any) []string {
+	markup, _ := payload["reply_markup"].(map[string]any)
+	rows, _ := markup["inline_keyboard"].([]any)
+	var out []string
+	for _, row := range rows {
+		for _, b := range row.([]any) {
+			out = append(out, b.(map[string]any)["callback_data"].(string))
+		}
+	}
+	return out
+}
+
+func keyboardMessage(data ...string) *telegramMessage {
+	markup := &inlineKeyboardMarkup{InlineKeyboard: [][]inlineKeyboardButton{{}}}
+	for _, d := range data {
+		markup.InlineKeyboard[0] = append(markup.InlineKeyboard[0], inlineKeyboardButton{Text: d, CallbackData: d})
+	}
+	return &telegramMessage{MessageID: 7, Chat: telegramChat{ID: 42}, Text: "Approval required for deploy", ReplyMarkup: markup}
+}
+
+func TestDeliverApproval(t *testing.T) {
+	f, p := newFakeBotAPI(t)
+	err := p.DeliverApproval(context.Background(), channels.ApprovalRequest{
+		TaskID: "task-1", Tool: "deploy", Context: "ship <v2>", Timeout: 5 * time.Minute, Target: "42",
+	})
+	if err != nil {
+		t.Fatalf("DeliverApproval: %v", err)
+	}
+	sent := f.last("sendMessage")
+	if sent["chat_id"] != "42" || sent["parse_mode"] != "HTML" {
+		t.Errorf("payload = %v", sent)
+	}
+	if text := sent["text"].(string); !strings.Contains(text, "<code>deploy</code>") || !strings.Contains(text, "ship &lt;v2&gt;") || !strings.Contains(text, "Auto-denies in 5m0s") {
+		t.Errorf("text = %q", text)
+	}
+	if got := strings.Join(buttonData(sent), ","); got != "ap:task-1,rj:task-1" {
+		t.Errorf("buttons = %s", got)
+	}
+
+	if err := p.DeliverApproval(context.Background(), channels.ApprovalRequest{TaskID: strings.Repeat("x", 80), Target: "42"}); err == nil {
+		t.Error("expected an error for a task id too long for callback_data")
+	}
+}
+
+func TestApprovalCallback(t *testing.T) {
+	f, p := newFakeBotAPI(t)
+	var got []channels.ApprovalDecision
+	var resolveErr error
+	p.SetApprovalResolver(func(_ context.Context, d channels.ApprovalDecision) error {
+		got = append(got, d)
+		return resolveErr
+	})
+
+	p.handleCallback(&telegramCallbackQuery{
+		ID: "q1", From: telegramUser{ID: 5, Username: "oncall"}, Data: "rj:task-1", Message: keyboardMessage("ap:task-1", "rj:task-1"),
+	})
+	if len(got) != 1 || got[0].TaskID != "task-1" || got[0].Decision != "reject" || got[0].Approver != "@oncall" {
+		t.Fatalf("decisions = %+v", got)
+	}
+	if edit := f.last("editMessageText"); edit == nil || !strings.HasSuffix(edit["text"].(string), "⛔ Rejected by @oncall") {
+		t.Errorf("edit = %v", edit)
+	}
+	if ans := f.last("answerCallbackQuery"); ans["callback_query_id"] != "q1" || ans["show_alert"] != false {
+		t.Errorf("answer = %v", ans)
+	}
+
+	// A refused decision is an alert; the buttons stay.
+	f.calls = nil
+	resolveErr = fmt.Errorf("deferral already resolved")
+	p.handleCallback(&telegramCallbackQuery{ID: "q2", From: telegramUser{ID: 5}, Data: "ap:task-1", Message: keyboardMessage("ap:task-1", "rj:task-1")})
+	if got[1].Approver != "5" {
+		t.Errorf("approver without username = %q, want the user id", got[1].Approver)
+	}
+	if methods := strings.Join(f.methods(), ","); methods != "answerCallbackQuery" {
+		t.Errorf("calls = %s, want only the answer", methods)
+	}
+	if ans := f.last("answerCallbackQuery"); ans["show_alert"] != true || !strings.Contains(ans["text"].(string), "already resolved") {
+		t.Errorf("answer = %v", ans)
+	}
+}
+
+func TestDeliverSchedule(t *testing.T) {
+	f, p := newFakeBotAPI(t)
+	ctx := context.Background()
+
+	if err := p.DeliverSchedule(ctx, channels.ScheduleNotice{ScheduleID: "daily-report", Cron: "@daily", Task: "Summarize <alerts>", Enabled: true, Target: "42"}); err != nil {
+		t.Fatalf("confirmation: %v", err)
+	}
+	sent := f.last("sendMessage")
+	if text := sent["text"].(string); !strings.Contains(text, "<code>daily-report</code>") || !strings.Contains(text, "Summarize &lt;alerts&gt;") {
+		t.Errorf("confirmation text = %q", text)
+	}
+	if got := strings.Join(buttonData(sent), ","); got != "sp:daily-report,sd:daily-report" {
+		t.Errorf("buttons = %s", got)
+	}
+
+	// A run result is the reply, then the controls line.
+	f.calls = nil
+	resp := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("All quiet.")}}
+	if err := p.DeliverSchedule(ctx, channels.ScheduleNotice{ScheduleID: "daily-report", Cron: "@daily", Target: "42", Response: resp}); err != nil {
+		t.Fatalf("run result: %v", err)
+	}
+	f.mu.Lock()
+	calls := f.calls
+	f.mu.Unlock()
+	if len(calls) != 2 || calls[0].Payload["text"] != "All quiet." || buttonData(calls[0].Payload) != nil {
+		t.Fatalf("calls = %+v", calls)
+	}
+	if got := strings.Join(buttonData(calls[1].Payload), ","); got != "sr:daily-report,sd:daily-report" {
+		t.Errorf("paused schedule buttons = %s", got)
+	}
+}
+
+func TestDeliverSchedule_Localized(t *testing.T) {
+	f, p := newFakeBotAPI(t)
+	l, err := i18n.New(types.LocalizationConfig{Detect: true}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetLocalizer(l)
+	if err := p.DeliverSchedule(context.Background(), channels.ScheduleNotice{ScheduleID: "resumen", Cron: "@daily", Task: "Resumir las alertas", Target: "42", Language: "es"}); err != nil {
+		t.Fatal(err)
+	}
+	sent := f.last("sendMessage")
+	if text := sent["text"].(string); !strings.HasPrefix(text, "<b>Programación creada:</b>") || !strings.HasSuffix(text, "<i>En pausa.</i>") {
+		t.Errorf("confirmation text = %q", text)
+	}
+	kb := sent["reply_markup"].(map[string]any)["inline_keyboard"].([]any)[0].([]any)
+	if label := kb[0].(map[string]any)["text"]; label != "▶️ Reanudar" {
+		t.Errorf("resume button = %q", label)
+	}
+
+	// A press answers in the language of the notice it is on.
+	p.SetScheduleManager(func(context.Context, channels.ScheduleAction) error { return nil })
+	msg := keyboardMessage("sr:resumen", "sd:resumen")
+	msg.Text = "Programación creada: resumen @daily — Resumir las alertas del día para el equipo"
+	p.handleCallback(&telegramCallbackQuery{ID: "q", From: telegramUser{ID: 5}, Data: "sr:resumen", Message: msg})
+	if ans := f.last("answerCallbackQuery"); ans["text"] != "Programación reanudada." {
+		t.Errorf("answer = %v", ans)
+	}
+}
+
+func TestScheduleCallback(t *testing.T) {
+	f, p := newFakeBotAPI(t)
+	var actions []string
+	p.SetScheduleManager(func(_ context.Context, a channels.ScheduleAction) error {
+		actions = append(actions, a.Action+":"+a.ScheduleID+":"+a.Actor)
+		if a.ScheduleID == "declared" {
+			return fmt.Errorf("schedule %q is defined in forge.yaml", a.ScheduleID)
+		}
+		return nil
+	})
+	press := func(data string, msg *telegramMessage) {
+		p.handleCallback(&telegramCallbackQuery{ID: "q", From: telegramUser{ID: 5, Username: "ops"}, Data: data, Message: msg})
+	}
+
+	press("sp:daily", keyboardMessage("sp:daily", "sd:daily"))
+	if got := strings.Join(buttonData(f.last("editMessageReplyMarkup")), ","); got != "sr:daily,sd:daily" {
+		t.Errorf("after pause = %s", got)
+	}
+
+	// Delete asks first, keeping the schedule's state; only the second tap deletes.
+	press("sd:daily", keyboardMessage("sr:daily", "sd:daily"))
+	if got := strings.Join(buttonData(f.last("editMessageReplyMarkup")), ","); got != "sr:daily,sx:daily" {
+		t.Errorf("delete confirmation = %s", got)
+	}
+	press("sx:daily", keyboardMessage("sr:daily", "sx:daily"))
+	if edit := f.last("editMessageText"); edit == nil || !strings.Contains(edit["text"].(string), "deleted by @ops") {
+		t.Errorf("edit = %v", edit)
+	}
+
+	press("sr:declared", keyboardMessage("sr:declared", "sd:declared"))
+	if ans := f.last("answerCallbackQuery"); ans["show_alert"] != true || !strings.Contains(ans["text"].(string), "forge.yaml") {
+		t.Errorf("answer = %v", ans)
+	}
+
+	if got := strings.Join(actions, ","); got != "pause:daily:@ops,delete:daily:@ops,resume:declared:@ops" {
+		t.Errorf("actions = %s", got)
+	}
+}
+
+func TestWebhookHandler_CallbackQuery(t *testing.T) {
+	_, p := newFakeBotAPI(t)
+	resolved := make(chan channels.ApprovalDecision, 1)
+	p.SetApprovalResolver(func(_ context.Context, d channels.ApprovalDecision) error {
+		resolved <- d
+		return nil
+	})
+	handler := p.makeWebhookHandler(func(context.Context, *channels.ChannelEvent) (*a2a.Message, error) {
+		t.Error("a callback query must not reach the message handler")
+		return nil, nil
+	})
+
+	body, _ := json.Marshal(telegramUpdate{UpdateID: 9, CallbackQuery: &telegramCallbackQuery{
+		ID: "q", From: telegramUser{ID: 5}, Data: "ap:task-1", Message: keyboardMessage("ap:task-1", "rj:task-1"),
+	}})
+	req := httptest.NewRequest(http.MethodPost, defaultWebhookPath, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	select {
+	case d := <-resolved:
+		if d.Decision != "approve" || d.TaskID != "task-1" {
+			t.Errorf("decision = %+v", d)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback query was not resolved")
+	}
+}
@@ -0,0 +1,136 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
+)
+
+// Schedule controls: the confirmation of a schedule the agent sets for
+// this chat, and each run's result, carry Pause/Resume and Delete
+// buttons. Delete asks for a second tap before the schedule is removed.
+// They are written from the localizer's catalogs.
+var (
+	_ channels.ScheduleDeliverer = (*Plugin)(nil)
+	_ channels.Localized         = (*Plugin)(nil)
+)
+
+// SetScheduleManager wires the callback invoked when a user presses a
+// schedule button. Called once by the runtime at startup.
+func (p *Plugin) SetScheduleManager(m channels.ScheduleManager) {
+	p.scheduleManager = m
+}
+
+// SetLocalizer wires the catalogs schedule notices and their controls
+// are written from. Called once by the runtime at startup.
+func (p *Plugin) SetLocalizer(l *i18n.Localizer) {
+	p.localizer = l
+}
+
+// DeliverSchedule posts a schedule notice to its chat. A run's result is
+// sent like any reply, followed by a short line carrying the controls.
+func (p *Plugin) DeliverSchedule(ctx context.Context, n channels.ScheduleNotice) error {
+	if n.Target == "" {
+		return fmt.Errorf("telegram DeliverSchedule: empty target chat")
+	}
+	text := p.scheduleConfirmationText(n)
+	if n.Response != nil {
+		event := &channels.ChannelEvent{Channel: "telegram", WorkspaceID: n.Target}
+		if err := p.SendResponse(event, n.Response); err != nil {
+			return err
+		}
+		text = fmt.Sprintf("<i>%s</i> <code>%s</code> · <code>%s</code>", html.EscapeString(p.localizer.Text(n.Language, "schedule.run")),
+			html.EscapeString(n.ScheduleID), html.EscapeString(n.Cron))
+	}
+	payload := map[string]any{
+		"chat_id":    n.Target,
+		"text":       text,
+		"parse_mode": "HTML",
+	}
+	if markup := p.scheduleKeyboard(n.Language, n.ScheduleID, n.Enabled, false); markup != nil {
+		payload["reply_markup"] = markup
+	}
+	return p.sendMessage(payload)
+}
+
+// scheduleConfirmationText is the confirmation of a schedule, in
+// Telegram HTML.
+func (p *Plugin) scheduleConfirmationText(n channels.ScheduleNotice) string {
+	text := fmt.Sprintf("<b>%s</b> <code>%s</code>\n<code>%s</code> — %s", html.EscapeString(p.localizer.Text(n.Language, "schedule.set")),
+		html.EscapeString(n.ScheduleID), html.EscapeString(n.Cron), html.EscapeString(n.Task))
+	if !n.Enabled {
+		text += "\n<i>" + html.EscapeString(p.localizer.Text(n.Language, "schedule.paused")) + "</i>"
+	}
+	return text
+}
+
+// scheduleKeyboard is a schedule's controls, labelled in lang: Pause or
+// Resume, depending on whether it is enabled, and Delete — or its
+// confirmation. nil when the schedule ID is too long for a button.
+func (p *Plugin) scheduleKeyboard(lang, id string, enabled, confirmDelete bool) *inlineKeyboardMarkup {
+	l := p.localizer
+	toggle, ok := callbackButton("⏸ "+l.Text(lang, "schedule.button.pause"), verbPause, id)
+	if !enabled {
+		toggle, ok = callbackButton("▶️ "+l.Text(lang, "schedule.button.resume"), verbResume, id)
+	}
+	del, _ := callbackButton("🗑 "+l.Text(lang, "schedule.button.delete"), verbDelete, id)
+	if confirmDelete {
+		del, _ = callbackButton("⚠️ "+l.Text(lang, "schedule.button.confirm_delete"), verbConfirmDelete, id)
+	}
+	if !ok {
+		return nil
+	}
+	return &inlineKeyboardMarkup{InlineKeyboard: [][]inlineKeyboardButton{{toggle, del}}}
+}
+
+// keyboardEnabled reports whether a schedule keyboard shows the schedule
+// as enabled (it offers Pause).
+func keyboardEnabled(markup *inlineKeyboardMarkup) bool {
+	if markup == nil {
+		return false
+	}
+	for _, row := range markup.InlineKeyboard {
+		for _, b := range row {
+			if strings.HasPrefix(b.CallbackData, verbPause+":") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleScheduleCallback applies a schedule button press and updates the
+// keyboard to the schedule's new state. Replies are in the language of
+// the notice the button is on.
+func (p *Plugin) handleScheduleCallback(ctx context.Context, cq *telegramCallbackQuery, verb, id string) (string, bool) {
+	msg := cq.Message
+	l := p.localizer
+	lang := l.Language(msg.Text)
+	if p.scheduleManager == nil {
+		return l.Text(lang, "schedule.controls_disabled"), true
+	}
+	if verb == verbDelete {
+		_ = p.replaceKeyboard(msg, p.scheduleKeyboard(lang, id, keyboardEnabled(msg.ReplyMarkup), true))
+		return l.Text(lang, "schedule.tap_again"), false
+	}
+
+	action := map[string]string{verbPause: "pause", verbResume: "resume", verbConfirmDelete: "delete"}[verb]
+	if err := p.scheduleManager(ctx, channels.ScheduleAction{ScheduleID: id, Action: action, Actor: actorName(cq.From)}); err != nil {
+		return l.Text(lang, "schedule."+action+"_failed", err), true
+	}
+	switch verb {
+	case verbConfirmDelete:
+		p.finishKeyboardMessage(msg, "🗑 "+l.Text(lang, "schedule.deleted_by", id, actorName(cq.From)))
+		return l.Text(lang, "schedule.deleted"), false
+	case verbPause:
+		_ = p.replaceKeyboard(msg, p.scheduleKeyboard(lang, id, false, false))
+		return l.Text(lang, "schedule.paused_ack"), false
+	default:
+		_ = p.replaceKeyboard(msg, p.scheduleKeyboard(lang, id, true, false))
+		return l.Text(lang, "schedule.resumed_ack"), false
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/i18n"
 	"github.com/initializ/forge/forge-plugins/channels/markdown"
 )
 
@@ -36,11 +37,16 @@ type Plugin struct {
 	mode          string // "polling" or "webhook"
 	webhookPort   int
 	webhookPath   string
-	webhookSecret string // random secret for webhook auth
+	webhookSecret string          // random secret for webhook auth
+	format        markdown.Format // outbound format profile: html (default) or plain
 	srv           *http.Server
 	client        *http.Client
 	apiBase       string // overridable for tests
 	stopCh        chan struct{}
+
+	approvalResolver channels.ApprovalResolver // wired by the runtime; resolves approval button presses
+	scheduleManager  channels.ScheduleManager  // wired by the runtime; applies schedule button presses
+	localizer        *i18n.Localizer           // wired by the runtime; schedule notices' catalogs (English when nil)
 }
 
 // New creates an uninitialised Telegram plugin.
@@ -48,6 +54,7 @@ func New() *Plugin {
 	return &Plugin{
 		client:  &http.Client{Timeout: 60 * time.Second},
 		apiBase: telegramAPIBase,
+		format:  markdown.FormatHTML,
 		stopCh:  make(chan struct{}),
 	}
 }
@@ -70,6 +77,12 @@ func (p *Plugin) Init(cfg channels.ChannelConfig) error {
 		return fmt.Errorf("telegram: mode must be 'polling' or 'webhook', got %q", p.mode)
 	}
 
+	format, err := markdown.ResolveFormat("telegram", settings["format"], markdown.FormatHTML, markdown.FormatPlain)
+	if err != nil {
+		return err
+	}
+	p.format = format
+
 	p.webhookPort = cfg.WebhookPort
 	if p.webhookPort == 0 {
 		p.webhookPort = defaultWebhookPort
@@ -160,6 +173,14 @@ func (p *Plugin) makeWebhookHandler(handler channels.EventHandler) http.HandlerF
 			return
 		}
 
+		// Inline keyboard button presses arrive as callback queries.
+		var update telegramUpdate
+		if json.Unmarshal(body, &update) == nil && update.CallbackQuery != nil {
+			w.WriteHeader(http.StatusOK)
+			go p.handleCallback(update.CallbackQuery)
+			return
+		}
+
 		event, err := p.NormalizeEvent(body)
 		if err != nil {
 			http.Error(w, "invalid update", http.StatusBadRequest)
@@ -202,6 +223,10 @@ func (p *Plugin) startPolling(ctx context.Context, handler channels.EventHandler
 				offset = update.UpdateID + 1
 			}
 
+			if update.CallbackQuery != nil {
+				go p.handleCallback(update.CallbackQuery)
+				continue
+			}
 			if update.Message == nil {
 				continue
 			}
@@ -231,6 +256,13 @@ func (p *Plugin) handleEvent(event *channels.ChannelEvent, handler channels.Even
 		var handlerErr error
 		defer finish(&handlerErr)
 
+		if err := p.fetchAudio(spanCtx, event); err != nil {
+			handlerErr = err
+			log.Printf("[telegram] fetching voice message: %v", err)
+			_ = p.sendChunked(event, "I couldn't download your voice message. Please try again, or type it instead.")
+			return
+		}
+
 		stopTyping := p.startTypingIndicator(spanCtx, event.WorkspaceID)
 
 		// Send an interim message if the task takes longer than the threshold.
@@ -251,6 +283,9 @@ func (p *Plugin) handleEvent(event *channels.ChannelEvent, handler channels.Even
 		close(done)
 		stopTyping()
 
+		if channels.IsDropped(err) {
+			return // a retry of a message already being answered, or dropped by policy
+		}
 		if err != nil {
 			handlerErr = err
 			fmt.Printf("telegram: handler error: %v\n", err)
@@ -312,23 +347,45 @@ func (p *Plugin) NormalizeEvent(raw []byte) (*channels.ChannelEvent, error) {
 		threadID = strconv.FormatInt(update.Message.ReplyToMessage.MessageID, 10)
 	}
 
-	return &channels.ChannelEvent{
+	event := &channels.ChannelEvent{
 		Channel:     "telegram",
 		WorkspaceID: strconv.FormatInt(update.Message.Chat.ID, 10),
 		UserID:      strconv.FormatInt(update.Message.From.ID, 10),
 		ThreadID:    threadID,
 		MessageID:   strconv.FormatInt(update.Message.MessageID, 10),
+		EventID:     strconv.FormatInt(update.UpdateID, 10),
 		Message:     update.Message.Text,
 		Raw:         raw,
-	}, nil
+	}
+	// A voice note or audio file arrives without text (a caption at
+	// most); its content is downloaded by fetchAudio.
+	if att, _, ok := audioAttachment(update.Message); ok {
+		event.Message = update.Message.Caption
+		event.Attachments = []channels.Attachment{att}
+	}
+	// So does a photo or document; the router fetches it through
+	// FetchAttachment when forge.yaml admits attachments.
+	if atts := fileAttachments(update.Message); len(atts) > 0 {
+		event.Message = update.Message.Caption
+		event.Attachments = append(event.Attachments, atts...)
+	}
+	return event, nil
 }
 
 // SendResponse sends a text message back to the Telegram chat.
 // If the runtime attached file parts (large tool outputs), those are used
 // for the document upload since the LLM text may be truncated.
 // For large responses (>4096 chars), sends a summary message and uploads
-// the full report as a document.
+// the full report as a document. A spoken reply to a voice message
+// follows the text as a voice note.
 func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Message) error {
+	if audio := extractAudio(response); audio != nil {
+		defer func() {
+			if err := p.sendAudio(event, audio); err != nil {
+				log.Printf("[telegram] sending voice reply failed: %v", err)
+			}
+		}()
+	}
 	text := extractText(response)
 	fileContent, fileName := extractLargestFile(response)
 	log.Printf("[telegram] SendResponse: text length=%d chars, file part=%d chars", len(text), len(fileContent))
@@ -366,19 +423,8 @@ func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Messag
 			}
 		}
 		summaryText := summary + "\n\nFull report attached as file above."
-		summaryHTML := markdown.ToTelegramHTML(summaryText)
-		payload := map[string]any{
-			"chat_id":    event.WorkspaceID,
-			"text":       summaryHTML,
-			"parse_mode": "HTML",
-		}
-		if event.MessageID != "" {
-			payload["reply_to_message_id"] = event.MessageID
-		}
-		if err := p.sendMessage(payload); err != nil {
-			delete(payload, "parse_mode")
-			payload["text"] = summaryText
-			_ = p.sendMessage(payload)
+		if err := p.sendChunked(event, summaryText); err != nil {
+			log.Printf("[telegram] sending summary failed: %v", err)
 		}
 		return nil
 	}
@@ -407,62 +453,37 @@ func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Messag
 		}
 
 		summaryText := summary + "\n\nFull report attached as file above."
-		summaryHTML := markdown.ToTelegramHTML(summaryText)
-		payload := map[string]any{
-			"chat_id":    event.WorkspaceID,
-			"text":       summaryHTML,
-			"parse_mode": "HTML",
-		}
-		if event.MessageID != "" {
-			payload["reply_to_message_id"] = event.MessageID
-		}
-		if err := p.sendMessage(payload); err != nil {
-			delete(payload, "parse_mode")
-			payload["text"] = summaryText
-			_ = p.sendMessage(payload)
+		if err := p.sendChunked(event, summaryText); err != nil {
+			log.Printf("[telegram] sending summary failed: %v", err)
 		}
 		return nil
 	}
 
-	html := markdown.ToTelegramHTML(text)
-	chunks := markdown.SplitMessage(html, 4096)
-
-	for i, chunk := range chunks {
-		payload := map[string]any{
-			"chat_id":    event.WorkspaceID,
-			"text":       chunk,
-			"parse_mode": "HTML",
-		}
-		if i == 0 && event.MessageID != "" {
-			payload["reply_to_message_id"] = event.MessageID
-		}
-		if err := p.sendMessage(payload); err != nil {
-			delete(payload, "parse_mode")
-			payload["text"] = text
-			if fbErr := p.sendMessage(payload); fbErr != nil {
-				return fbErr
-			}
-		}
-	}
-	return nil
+	return p.sendChunked(event, text)
 }
 
-// sendChunked sends text as chunked messages (fallback for failed document upload).
+// sendChunked sends text in the configured format profile as one or more
+// messages, replying to the triggering message with the first. A chunk
+// Telegram rejects as malformed HTML is resent without parse_mode. Also
+// the fallback for a failed document upload.
 func (p *Plugin) sendChunked(event *channels.ChannelEvent, text string) error {
-	html := markdown.ToTelegramHTML(text)
-	chunks := markdown.SplitMessage(html, 4096)
+	chunks := markdown.SplitMessage(markdown.Render(text, p.format), 4096)
 	for i, chunk := range chunks {
 		payload := map[string]any{
-			"chat_id":    event.WorkspaceID,
-			"text":       chunk,
-			"parse_mode": "HTML",
+			"chat_id": event.WorkspaceID,
+			"text":    chunk,
+		}
+		if p.format == markdown.FormatHTML {
+			payload["parse_mode"] = "HTML"
 		}
 		if i == 0 && event.MessageID != "" {
 			payload["reply_to_message_id"] = event.MessageID
 		}
 		if err := p.sendMessage(payload); err != nil {
+			if _, html := payload["parse_mode"]; !html {
+				return err
+			}
 			delete(payload, "parse_mode")
-			payload["text"] = chunk
 			if fbErr := p.sendMessage(payload); fbErr != nil {
 				return fbErr
 			}
@@ -473,6 +494,12 @@ func (p *Plugin) sendChunked(event *channels.ChannelEvent, text string) error {
 
 // sendDocument uploads content as a document to a Telegram chat.
 func (p *Plugin) sendDocument(event *channels.ChannelEvent, filename, content string) error {
+	return p.uploadFile(event, "sendDocument", "document", filename, []byte(content))
+}
+
+// uploadFile posts data as the field of a multipart Bot API method
+// (sendDocument, sendVoice, sendAudio), replying to the event.
+func (p *Plugin) uploadFile(event *channels.ChannelEvent, method, field, filename string, data []byte) error {
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
 
@@ -488,35 +515,34 @@ func (p *Plugin) sendDocument(event *channels.ChannelEvent, filename, content st
 		}
 	}
 
-	// document file field
-	part, err := writer.CreateFormFile("document", filename)
+	part, err := writer.CreateFormFile(field, filename)
 	if err != nil {
 		return fmt.Errorf("creating form file: %w", err)
 	}
-	if _, err := part.Write([]byte(content)); err != nil {
-		return fmt.Errorf("writing document content: %w", err)
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("writing %s content: %w", field, err)
 	}
 
 	if err := writer.Close(); err != nil {
 		return fmt.Errorf("closing multipart writer: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/bot%s/sendDocument", p.apiBase, p.botToken)
+	url := fmt.Sprintf("%s/bot%s/%s", p.apiBase, p.botToken, method)
 	req, err := http.NewRequest(http.MethodPost, url, &buf)
 	if err != nil {
-		return fmt.Errorf("creating sendDocument request: %w", err)
+		return fmt.Errorf("creating %s request: %w", method, err)
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("sending document to telegram: %w", err)
+		return fmt.Errorf("telegram %s: %w", method, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("telegram sendDocument error %d: %s", resp.StatusCode, string(respBody))
+		return fmt.Errorf("telegram %s error %d: %s", method, resp.StatusCode, string(respBody))
 	}
 
 	return nil
@@ -585,12 +611,17 @@ func (p *Plugin) startTypingIndicator(ctx context.Context, chatID string) (stop
 
 // sendMessage posts a JSON payload to the Telegram sendMessage API.
 func (p *Plugin) sendMessage(payload map[string]any) error {
+	return p.callAPI("sendMessage", payload)
+}
+
+// callAPI posts a JSON payload to a Telegram Bot API method.
+func (p *Plugin) callAPI(method string, payload map[string]any) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshalling telegram response: %w", err)
+		return fmt.Errorf("marshalling telegram %s: %w", method, err)
 	}
 
-	url := fmt.Sprintf("%s/bot%s/sendMessage", p.apiBase, p.botToken)
+	url := fmt.Sprintf("%s/bot%s/%s", p.apiBase, p.botToken, method)
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("creating telegram request: %w", err)
@@ -611,7 +642,8 @@ func (p *Plugin) sendMessage(payload map[string]any) error {
 	return nil
 }
 
-// extractText concatenates all text parts from an A2A message.
+// extractText concatenates all text parts from an A2A message, followed by
+// footnotes for any sources it cites.
 func extractText(msg *a2a.Message) string {
 	if msg == nil {
 		return "(no response)"
@@ -628,19 +660,23 @@ func extractText(msg *a2a.Message) string {
 	if text == "" {
 		text = "(no text response)"
 	}
+	if notes := channels.CitationFootnotes(msg.Citations); notes != "" {
+		text += "\n\n" + notes
+	}
 	return text
 }
 
 // extractLargestFile returns the content and filename of the largest file part
 // in the message, or empty strings if no file parts exist.
 // The runtime attaches large tool outputs as file parts so they aren't
-// truncated by LLM output token limits.
+// truncated by LLM output token limits. Audio parts (spoken replies)
+// are not reports and are skipped.
 func extractLargestFile(msg *a2a.Message) (content, filename string) {
 	if msg == nil {
 		return "", ""
 	}
 	for _, p := range msg.Parts {
-		if p.Kind == a2a.PartKindFile && p.File != nil && len(p.File.Bytes) > len(content) {
+		if p.Kind == a2a.PartKindFile && p.File != nil && !isAudioFile(p.File) && len(p.File.Bytes) > len(content) {
 			content = string(p.File.Bytes)
 			filename = p.File.Name
 		}
@@ -651,20 +687,54 @@ func extractLargestFile(msg *a2a.Message) (content, filename string) {
 // Telegram API types (minimal, for parsing).
 
 type telegramUpdate struct {
-	UpdateID int64            `json:"update_id"`
-	Message  *telegramMessage `json:"message,omitempty"`
+	UpdateID      int64                  `json:"update_id"`
+	Message       *telegramMessage       `json:"message,omitempty"`
+	CallbackQuery *telegramCallbackQuery `json:"callback_query,omitempty"`
 }
 
 type telegramMessage struct {
-	MessageID      int64            `json:"message_id"`
-	From           telegramUser     `json:"from"`
-	Chat           telegramChat     `json:"chat"`
-	Text           string           `json:"text"`
-	ReplyToMessage *telegramMessage `json:"reply_to_message,omitempty"`
+	MessageID      int64                 `json:"message_id"`
+	From           telegramUser          `json:"from"`
+	Chat           telegramChat          `json:"chat"`
+	Text           string                `json:"text"`
+	Caption        string                `json:"caption,omitempty"`
+	Voice          *telegramFile         `json:"voice,omitempty"`
+	Audio          *telegramFile         `json:"audio,omitempty"`
+	Document       *telegramFile         `json:"document,omitempty"`
+	Photo          []telegramPhotoSize   `json:"photo,omitempty"`
+	ReplyToMessage *telegramMessage      `json:"reply_to_message,omitempty"`
+	ReplyMarkup    *inlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
 type telegramUser struct {
-	ID int64 `json:"id"`
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+// telegramCallbackQuery is an inline keyboard button press.
+type telegramCallbackQuery struct {
+	ID      string           `json:"id"`
+	From    telegramUser     `json:"from"`
+	Message *telegramMessage `json:"message,omitempty"` // the message carrying the keyboard
+	Data    string           `json:"data"`
+}
+
+type inlineKeyboardMarkup struct {
+	InlineKeyboard [][]inlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type inlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// telegramFile is the part of a Voice or Audio object needed to
+// download it.
+type telegramFile struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	FileSize int64  `json:"file_size,omitempty"`
 }
 
 type telegramChat struct {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-plugins/channels/markdown"
 )
 
 func TestNormalizeEvent(t *testing.T) {
@@ -47,6 +48,9 @@ func TestNormalizeEvent(t *testing.T) {
 	if event.MessageID != "42" {
 		t.Errorf("MessageID = %q, want 42", event.MessageID)
 	}
+	if event.EventID != "100" {
+		t.Errorf("EventID = %q, want 100", event.EventID)
+	}
 	if event.Message != "hello bot" {
 		t.Errorf("Message = %q, want 'hello bot'", event.Message)
 	}
@@ -614,3 +618,48 @@ func TestInit_InvalidMode(t *testing.T) {
 		t.Fatal("expected error for invalid mode")
 	}
 }
+
+func TestInit_InvalidFormat(t *testing.T) {
+	p := New()
+	err := p.Init(channels.ChannelConfig{
+		Adapter: "telegram",
+		Settings: map[string]string{
+			"bot_token": "test-token",
+			"format":    "blocks",
+		},
+	})
+	if err == nil {
+		t.Fatal("expected error for a format Telegram cannot send")
+	}
+}
+
+func TestSendResponse_PlainFormat(t *testing.T) {
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload) //nolint:errcheck
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+	p.format = markdown.FormatPlain
+
+	event := &channels.ChannelEvent{WorkspaceID: "67890", MessageID: "42"}
+	msg := &a2a.Message{
+		Role:  a2a.MessageRoleAgent,
+		Parts: []a2a.Part{a2a.NewTextPart("## Result\n\nsee [docs](https://example.com)")},
+	}
+	if err := p.SendResponse(event, msg); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+	if _, ok := payload["parse_mode"]; ok {
+		t.Errorf("parse_mode = %v, want none for plain format", payload["parse_mode"])
+	}
+	if want := "Result\n\nsee docs (https://example.com)"; payload["text"] != want {
+		t.Errorf("text = %q, want %q", payload["text"], want)
+	}
+}
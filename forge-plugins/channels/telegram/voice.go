@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// maxDownloadBytes is the largest file the Bot API lets a bot download.
+const maxDownloadBytes = 20 << 20
+
+// audioAttachment describes a message's voice note or audio file for
+// the event, or returns ok=false when it has neither. The content is
+// fetched later by fetchAudio.
+func audioAttachment(msg *telegramMessage) (att channels.Attachment, fileID string, ok bool) {
+	switch {
+	case msg.Voice != nil:
+		att = channels.Attachment{Name: "voice.ogg", MimeType: msg.Voice.MimeType}
+		fileID = msg.Voice.FileID
+	case msg.Audio != nil:
+		att = channels.Attachment{Name: msg.Audio.FileName, MimeType: msg.Audio.MimeType}
+		fileID = msg.Audio.FileID
+	default:
+		return att, "", false
+	}
+	if att.MimeType == "" {
+		att.MimeType = "audio/ogg"
+	}
+	if att.Name == "" {
+		att.Name = "audio.ogg"
+	}
+	return att, fileID, true
+}
+
+// fetchAudio downloads the voice note or audio file of the message in
+// event.Raw into its attachment, so the router can transcribe it.
+func (p *Plugin) fetchAudio(ctx context.Context, event *channels.ChannelEvent) error {
+	var update telegramUpdate
+	if err := json.Unmarshal(event.Raw, &update); err != nil || update.Message == nil {
+		return nil
+	}
+	_, fileID, ok := audioAttachment(update.Message)
+	if !ok {
+		return nil
+	}
+	data, err := p.downloadFile(ctx, fileID, maxDownloadBytes)
+	if err != nil {
+		return err
+	}
+	for i := range event.Attachments {
+		if event.Attachments[i].IsAudio() {
+			event.Attachments[i].Data = data
+			break
+		}
+	}
+	return nil
+}
+
+// downloadFile resolves fileID with getFile and downloads the file,
+// failing when it is larger than limit.
+func (p *Plugin) downloadFile(ctx context.Context, fileID string, limit int64) ([]byte, error) {
+	getFile := fmt.Sprintf("%s/bot%s/getFile?file_id=%s", p.apiBase, p.botToken, url.QueryEscape(fileID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, getFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("telegram getFile: %w", err)
+	}
+	var result struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			FilePath string `json:"file_path"`
+			FileSize int64  `json:"file_size"`
+		} `json:"result"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	_ = resp.Body.Close()
+	if err != nil || !result.OK || result.Result.FilePath == "" {
+		return nil, fmt.Errorf("telegram getFile failed for %s", fileID)
+	}
+	if result.Result.FileSize > limit {
+		return nil, fmt.Errorf("telegram file is %d bytes, over the %d byte download limit", result.Result.FileSize, limit)
+	}
+
+	fileURL := fmt.Sprintf("%s/file/bot%s/%s", p.apiBase, p.botToken, result.Result.FilePath)
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err = p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading telegram file: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading telegram file: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err == nil && int64(len(data)) > limit {
+		return nil, fmt.Errorf("telegram file is over the %d byte download limit", limit)
+	}
+	return data, err
+}
+
+// extractAudio returns the first audio file part of msg, the spoken
+// reply to a voice message, or nil.
+func extractAudio(msg *a2a.Message) *a2a.FileContent {
+	if msg == nil {
+		return nil
+	}
+	for _, p := range msg.Parts {
+		if p.Kind == a2a.PartKindFile && p.File != nil && isAudioFile(p.File) && len(p.File.Bytes) > 0 {
+			return p.File
+		}
+	}
+	return nil
+}
+
+func isAudioFile(f *a2a.FileContent) bool {
+	return strings.HasPrefix(f.MimeType, "audio/")
+}
+
+// sendAudio sends a spoken reply: Ogg Opus as a voice note, anything
+// else as an audio file.
+func (p *Plugin) sendAudio(event *channels.ChannelEvent, audio *a2a.FileContent) error {
+	if audio.MimeType == "audio/ogg" {
+		return p.uploadFile(event, "sendVoice", "voice", audio.Name, audio.Bytes)
+	}
+	return p.uploadFile(event, "sendAudio", "audio", audio.Name, audio.Bytes)
+}
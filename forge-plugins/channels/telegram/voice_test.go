@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+const voiceUpdate = `{
+	"update_id": 101,
+	"message": {
+		"message_id": 43,
+		"from": {"id": 12345},
+		"chat": {"id": 67890},
+		"caption": "re: standup",
+		"voice": {"file_id": "AwAD-voice", "duration": 3, "mime_type": "audio/ogg", "file_size": 9}
+	}
+}`
+
+func TestNormalizeEvent_Voice(t *testing.T) {
+	event, err := New().NormalizeEvent([]byte(voiceUpdate))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.Message != "re: standup" {
+		t.Errorf("Message = %q, want the caption", event.Message)
+	}
+	if len(event.Attachments) != 1 || !event.Attachments[0].IsAudio() || event.Attachments[0].Name != "voice.ogg" {
+		t.Errorf("Attachments = %+v", event.Attachments)
+	}
+}
+
+func TestFetchAudio(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottest-token/getFile":
+			if r.URL.Query().Get("file_id") != "AwAD-voice" {
+				t.Errorf("file_id = %q", r.URL.Query().Get("file_id"))
+			}
+			fmt.Fprint(w, `{"ok":true,"result":{"file_path":"voice/file_7.oga","file_size":9}}`)
+		case "/file/bottest-token/voice/file_7.oga":
+			fmt.Fprint(w, "OggS-data")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+
+	event, _ := p.NormalizeEvent([]byte(voiceUpdate))
+	if err := p.fetchAudio(context.Background(), event); err != nil {
+		t.Fatalf("fetchAudio: %v", err)
+	}
+	if got := string(event.Attachments[0].Data); got != "OggS-data" {
+		t.Errorf("audio = %q", got)
+	}
+
+	// A text message has nothing to fetch.
+	text, _ := p.NormalizeEvent([]byte(`{"update_id":1,"message":{"message_id":1,"from":{"id":1},"chat":{"id":1},"text":"hi"}}`))
+	if err := p.fetchAudio(context.Background(), text); err != nil || len(text.Attachments) != 0 {
+		t.Errorf("text message: err = %v, attachments = %+v", err, text.Attachments)
+	}
+}
+
+func TestSendResponse_VoiceReply(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if method == "sendVoice" {
+			file, hdr, err := r.FormFile("voice")
+			if err != nil {
+				t.Errorf("voice field: %v", err)
+			} else if data, _ := io.ReadAll(file); hdr.Filename != "reply.ogg" || string(data) != "OggS-reply" {
+				t.Errorf("voice = %s %q", hdr.Filename, data)
+			}
+		}
+		mu.Lock()
+		calls = append(calls, method)
+		mu.Unlock()
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+
+	resp := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{
+		a2a.NewTextPart("Standup moved to 10:30."),
+		a2a.NewFilePart(a2a.FileContent{Name: "reply.ogg", MimeType: "audio/ogg", Bytes: []byte("OggS-reply")}),
+	}}
+	if err := p.SendResponse(&channels.ChannelEvent{WorkspaceID: "67890", MessageID: "43"}, resp); err != nil {
+		t.Fatalf("SendResponse: %v", err)
+	}
+	// The audio part is not mistaken for a report document.
+	if got := strings.Join(calls, ","); got != "sendMessage,sendVoice" {
+		t.Errorf("calls = %s, want the text then the voice note", got)
+	}
+}
@@ -31,6 +31,12 @@ var trustedDomains = map[string]bool{
 	"api.anthropic.com": true,
 	"api.together.ai":   true,
 	"api.cohere.com":    true,
+	"api.mistral.ai":    true,
+	"api.groq.com":      true,
+	"api.together.xyz":  true,
+	"openrouter.ai":     true,
+	"api.x.ai":          true,
+	"api.deepseek.com":  true,
 	"api.tavily.com":    true,
 	// Channels.
 	"api.slack.com":    true,
@@ -85,6 +85,23 @@ type ForgeSkillMeta struct {
 	WorkflowPhase string                `yaml:"workflow_phase,omitempty" json:"workflow_phase,omitempty"`
 	Guardrails    *SkillGuardrailConfig `yaml:"guardrails,omitempty" json:"guardrails,omitempty"`
 	TrustHints    *TrustHints           `yaml:"trust_hints,omitempty" json:"trust_hints,omitempty"`
+	// Reflection asks for a review of the final answer of any task that
+	// called one of the skill's tools; see forge.yaml `reflection`.
+	Reflection bool `yaml:"reflection,omitempty" json:"reflection,omitempty"`
+	// Output constrains the final answer of any task that called one of
+	// the skill's tools.
+	Output *SkillOutputContract `yaml:"output,omitempty" json:"output,omitempty"`
+}
+
+// SkillOutputContract is the shape a skill requires of the final answer
+// of a task that used it. An answer that breaks it gets one repair
+// attempt; if the repair breaks it too, the task fails.
+type SkillOutputContract struct {
+	// Schema is a JSON Schema the answer must validate against. The
+	// answer must then be a JSON document.
+	Schema map[string]any `yaml:"schema,omitempty" json:"schema,omitempty"`
+	// MaxLength caps the answer's length in characters. 0 is no cap.
+	MaxLength int `yaml:"max_length,omitempty" json:"max_length,omitempty"`
 }
 
 // TrustHints are the skill author's self-declared behavior hints, checked for
@@ -260,6 +277,10 @@ type AggregatedRequirements struct {
 	WorkflowPhases  []string              // union of workflow_phase values across skills, deduplicated, sorted
 	SkillGuardrails *SkillGuardrailConfig // aggregated guardrails from all skills
 	Capabilities    []string              // union of requires.capabilities across skills, deduplicated, sorted
+	ReflectionTools []string              // tools of skills declaring reflection: true, deduplicated, sorted
+	// OutputContracts maps the tool of each skill declaring an output
+	// contract to that contract.
+	OutputContracts map[string]*SkillOutputContract
 }
 
 // DerivedCLIConfig holds auto-derived cli_execute configuration from skill requirements.
@@ -270,6 +291,10 @@ type DerivedCLIConfig struct {
 	DeniedTools     []string // tools to remove from registry before LLM execution
 	EgressDomains   []string // additional egress domains from skills
 	WorkflowPhases  []string // workflow phases from skills (edit, finalize, query)
+	ReflectionTools []string // tools whose tasks get a reflection pass over the final answer
+	// OutputContracts constrain the final answer of tasks that called
+	// the keyed tool.
+	OutputContracts map[string]*SkillOutputContract
 }
 
 // DerivedBrowserConfig signals that at least one active skill declared the
@@ -62,6 +62,8 @@ func DeriveCLIConfig(reqs *contract.AggregatedRequirements) *contract.DerivedCLI
 		DeniedTools:     reqs.DeniedTools,    // already sorted from AggregateRequirements
 		EgressDomains:   reqs.EgressDomains,  // already sorted from AggregateRequirements
 		WorkflowPhases:  reqs.WorkflowPhases, // already sorted from AggregateRequirements
+		ReflectionTools: reqs.ReflectionTools,
+		OutputContracts: reqs.OutputContracts,
 	}
 }
 
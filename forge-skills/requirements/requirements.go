@@ -22,6 +22,8 @@ func AggregateRequirements(entries []contract.SkillEntry) *contract.AggregatedRe
 	egressSet := make(map[string]bool)
 	phaseSet := make(map[string]bool)
 	capSet := make(map[string]bool)
+	reflectSet := make(map[string]bool)
+	outputContracts := make(map[string]*contract.SkillOutputContract)
 	var oneOfGroups [][]string
 
 	var denyCommands []contract.SkillCommandFilter
@@ -62,6 +64,18 @@ func AggregateRequirements(entries []contract.SkillEntry) *contract.AggregatedRe
 						phaseSet[s] = true
 					}
 				}
+				if on, _ := forgeMap["reflection"].(bool); on && e.Name != "" {
+					reflectSet[e.Name] = true
+				}
+				if raw, ok := forgeMap["output"]; ok && e.Name != "" {
+					data, err := yaml.Marshal(raw)
+					if err == nil {
+						var oc contract.SkillOutputContract
+						if err := yaml.Unmarshal(data, &oc); err == nil && (len(oc.Schema) > 0 || oc.MaxLength > 0) {
+							outputContracts[e.Name] = &oc
+						}
+					}
+				}
 				if raw, ok := forgeMap["guardrails"]; ok {
 					// Re-marshal to yaml, unmarshal into SkillGuardrailConfig
 					data, err := yaml.Marshal(raw)
@@ -158,6 +172,10 @@ func AggregateRequirements(entries []contract.SkillEntry) *contract.AggregatedRe
 		EgressDomains:   sortedKeys(egressSet),
 		WorkflowPhases:  sortedKeys(phaseSet),
 		Capabilities:    sortedKeys(capSet),
+		ReflectionTools: sortedKeys(reflectSet),
+	}
+	if len(outputContracts) > 0 {
+		agg.OutputContracts = outputContracts
 	}
 	agg.EnvRequired = sortedKeys(reqSet)
 	agg.EnvOptional = sortedKeys(optSet)
@@ -209,6 +209,52 @@ func TestAggregate_WorkflowPhaseCollected(t *testing.T) {
 	}
 }
 
+func TestAggregate_ReflectionTools(t *testing.T) {
+	meta := func(on any) *contract.SkillMetadata {
+		return &contract.SkillMetadata{Metadata: map[string]map[string]any{"forge": {"reflection": on}}}
+	}
+	entries := []contract.SkillEntry{
+		{Name: "write_report", Metadata: meta(true)},
+		{Name: "draft_email", Metadata: meta(true)},
+		{Name: "lookup", Metadata: meta(false)},
+		{Name: "search"},
+	}
+
+	reqs := AggregateRequirements(entries)
+	if want := []string{"draft_email", "write_report"}; !reflect.DeepEqual(reqs.ReflectionTools, want) {
+		t.Errorf("ReflectionTools = %v, want %v", reqs.ReflectionTools, want)
+	}
+	if cfg := DeriveCLIConfig(reqs); !reflect.DeepEqual(cfg.ReflectionTools, reqs.ReflectionTools) {
+		t.Errorf("derived ReflectionTools = %v, want %v", cfg.ReflectionTools, reqs.ReflectionTools)
+	}
+}
+
+func TestAggregate_OutputContracts(t *testing.T) {
+	entries := []contract.SkillEntry{
+		{Name: "invoice_report", Metadata: &contract.SkillMetadata{Metadata: map[string]map[string]any{"forge": {"output": map[string]any{
+			"schema":     map[string]any{"type": "object", "required": []any{"total"}},
+			"max_length": 2000,
+		}}}}},
+		{Name: "tweet", Metadata: &contract.SkillMetadata{Metadata: map[string]map[string]any{"forge": {"output": map[string]any{"max_length": 280}}}}},
+		{Name: "search", Metadata: &contract.SkillMetadata{Metadata: map[string]map[string]any{"forge": {"output": map[string]any{}}}}},
+	}
+
+	reqs := AggregateRequirements(entries)
+	if len(reqs.OutputContracts) != 2 {
+		t.Fatalf("OutputContracts = %v, want invoice_report and tweet", reqs.OutputContracts)
+	}
+	report := reqs.OutputContracts["invoice_report"]
+	if report == nil || report.MaxLength != 2000 || report.Schema["type"] != "object" {
+		t.Errorf("invoice_report contract = %+v", report)
+	}
+	if tweet := reqs.OutputContracts["tweet"]; tweet == nil || tweet.MaxLength != 280 || tweet.Schema != nil {
+		t.Errorf("tweet contract = %+v", tweet)
+	}
+	if cfg := DeriveCLIConfig(reqs); !reflect.DeepEqual(cfg.OutputContracts, reqs.OutputContracts) {
+		t.Errorf("derived OutputContracts = %v, want %v", cfg.OutputContracts, reqs.OutputContracts)
+	}
+}
+
 func TestAggregate_NoRequirements(t *testing.T) {
 	entries := []contract.SkillEntry{
 		{Name: "a"},
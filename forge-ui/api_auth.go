@@ -0,0 +1,59 @@
+package forgeui
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// apiTokenCookie carries the API token for the browser dashboard, which
+// cannot add an Authorization header to its fetch and EventSource calls.
+const apiTokenCookie = "forge_ui_token"
+
+// apiAuthMiddleware requires token on every /api/ route except
+// /api/health when token is set: as a bearer token (`forge agents`, CI)
+// or as the cookie a browser gets by opening /?token=<token> once. The
+// static dashboard assets stay public; they hold no workspace data.
+func apiAuthMiddleware(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" && r.URL.Query().Has("token") {
+			if !tokenMatches(token, r.URL.Query().Get("token")) {
+				writeError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     apiTokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+				SameSite: http.SameSiteStrictMode,
+			})
+			// Drop the token from the address bar and history.
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/api/") || r.URL.Path == "/api/health" || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			if c, err := r.Cookie(apiTokenCookie); err == nil {
+				got = c.Value
+			}
+		}
+		if !tokenMatches(token, got) {
+			writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func tokenMatches(want, got string) bool {
+	return got != "" && subtle.ConstantTimeCompare([]byte(want), []byte(got)) == 1
+}
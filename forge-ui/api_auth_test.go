@@ -0,0 +1,60 @@
+package forgeui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIAuthMiddleware(t *testing.T) {
+	h := apiAuthMiddleware("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		cookie string
+		want   int
+	}{
+		{"no token", "/api/agents", "", "", http.StatusUnauthorized},
+		{"wrong bearer", "/api/agents", "Bearer nope", "", http.StatusUnauthorized},
+		{"bearer", "/api/agents", "Bearer secret", "", http.StatusOK},
+		{"cookie", "/api/agents", "", "secret", http.StatusOK},
+		{"health is public", "/api/health", "", "", http.StatusOK},
+		{"static is public", "/app.js", "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: apiTokenCookie, Value: tt.cookie})
+			}
+			if got := serve(req).Code; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Opening /?token= sets the cookie and redirects to a clean URL.
+	w := serve(httptest.NewRequest(http.MethodGet, "/?token=secret", nil))
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/" {
+		t.Fatalf("bootstrap: status = %d, location = %q", w.Code, w.Header().Get("Location"))
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != apiTokenCookie || cookies[0].Value != "secret" || !cookies[0].HttpOnly {
+		t.Errorf("bootstrap cookies = %v", cookies)
+	}
+	if w := serve(httptest.NewRequest(http.MethodGet, "/?token=nope", nil)); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad bootstrap token: status = %d", w.Code)
+	}
+}
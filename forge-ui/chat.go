@@ -1,11 +1,8 @@
 package forgeui
 
 import (
-	"bufio"
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -13,7 +10,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/auth"
+	"github.com/initializ/forge/forge-core/tools"
 )
 
 // handleChat proxies a chat message to a running agent via A2A JSON-RPC
@@ -35,6 +34,20 @@ func (s *UIServer) handleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Generate session ID if not provided.
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = fmt.Sprintf("%s-%d", agentID, time.Now().UnixNano())
+	}
+	s.proxyChat(w, r, agentID, sessionID, req.Message, tools.OriginUI)
+}
+
+// proxyChat sends message to the running agent agentID under sessionID
+// via A2A tasks/sendSubscribe (a2a.Client) and relays the SSE stream to
+// w, ending with a done event carrying the session ID. Shared by the
+// dashboard chat and the webchat widget; origin tags the task for the
+// agent's tool_policies.
+func (s *UIServer) proxyChat(w http.ResponseWriter, r *http.Request, agentID, sessionID, message, origin string) {
 	agents, err := s.scanner.Scan()
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
@@ -45,63 +58,25 @@ func (s *UIServer) handleChat(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "agent is not running")
 		return
 	}
-	port := agent.Port
 
-	// Generate session ID if not provided.
-	sessionID := req.SessionID
-	if sessionID == "" {
-		sessionID = fmt.Sprintf("%s-%d", agentID, time.Now().UnixNano())
-	}
-
-	// Build A2A JSON-RPC request for tasks/sendSubscribe.
-	rpcBody, err := json.Marshal(map[string]any{
-		"jsonrpc": "2.0",
-		"id":      1,
-		"method":  "tasks/sendSubscribe",
-		"params": map[string]any{
-			"id": sessionID,
-			"message": map[string]any{
-				"role": "user",
-				"parts": []map[string]any{
-					{"kind": "text", "text": req.Message},
-				},
-			},
+	client := a2a.NewClient(a2a.ClientConfig{
+		BaseURL: fmt.Sprintf("http://127.0.0.1:%d", agent.Port),
+		Token:   s.loadAgentToken(agentID),
+		// coreruntime.HeaderForgeOrigin
+		Header: http.Header{"X-Forge-Origin": []string{origin}},
+	})
+	stream, err := client.SendSubscribe(r.Context(), a2a.SendTaskParams{
+		ID: sessionID,
+		Message: a2a.Message{
+			Role:  a2a.MessageRoleUser,
+			Parts: []a2a.Part{a2a.NewTextPart(message)},
 		},
 	})
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to build request")
-		return
-	}
-
-	// POST to the agent's A2A endpoint.
-	client := &http.Client{Timeout: 0}
-	agentURL := fmt.Sprintf("http://127.0.0.1:%d/", port)
-	agentReq, err := http.NewRequestWithContext(r.Context(), http.MethodPost, agentURL, bytes.NewReader(rpcBody))
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to create agent request")
-		return
-	}
-	agentReq.Header.Set("Content-Type", "application/json")
-
-	// Load and set auth token from the agent directory.
-	if token := s.loadAgentToken(agentID); token != "" {
-		agentReq.Header.Set("Authorization", "Bearer "+token)
-	}
-
-	agentResp, err := client.Do(agentReq)
 	if err != nil {
 		writeError(w, http.StatusBadGateway, "failed to reach agent: "+err.Error())
 		return
 	}
-	defer func() { _ = agentResp.Body.Close() }()
-
-	// If the agent didn't return SSE, relay the error.
-	ct := agentResp.Header.Get("Content-Type")
-	if !strings.Contains(ct, "text/event-stream") {
-		body, _ := io.ReadAll(agentResp.Body)
-		writeError(w, http.StatusBadGateway, "agent returned non-SSE response: "+string(body))
-		return
-	}
+	defer func() { _ = stream.Close() }()
 
 	// Set SSE headers for the browser.
 	flusher, ok := w.(http.Flusher)
@@ -115,35 +90,16 @@ func (s *UIServer) handleChat(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	flusher.Flush()
 
-	// Parse agent SSE and re-emit to browser.
-	scanner := bufio.NewScanner(agentResp.Body)
-	var eventType string
-	var dataLines []string
-
-	for scanner.Scan() {
-		// Check if client disconnected.
-		select {
-		case <-r.Context().Done():
-			return
-		default:
-		}
-
-		line := scanner.Text()
-
-		if after, found := strings.CutPrefix(line, "event:"); found {
-			eventType = strings.TrimSpace(after)
-		} else if after, found := strings.CutPrefix(line, "data:"); found {
-			dataLines = append(dataLines, after)
-		} else if line == "" && eventType != "" {
-			// Blank line = end of SSE frame. Re-emit to browser.
-			data := strings.TrimSpace(strings.Join(dataLines, "\n"))
-			if data != "" {
-				_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
-				flusher.Flush()
-			}
-			eventType = ""
-			dataLines = nil
+	// Re-emit the agent's events to the browser until the agent closes
+	// the stream or the browser disconnects (which cancels the request
+	// and ends the stream).
+	for {
+		ev, err := stream.Next()
+		if err != nil {
+			break
 		}
+		_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Event, ev.Data)
+		flusher.Flush()
 	}
 
 	// Send final done event with session_id.
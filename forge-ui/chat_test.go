@@ -257,10 +257,16 @@ func TestHandleChatProxy(t *testing.T) {
 				"message": map[string]any{
 					"role": "agent",
 					"parts": []map[string]any{
-						{"kind": "data", "data": map[string]any{"name": "web_search", "phase": "start"}},
+						{"kind": "text", "text": "Executing web_search..."},
 					},
 				},
 			},
+			"metadata": map[string]any{
+				"progress_phase":          "tool_start",
+				"progress_tool":           "web_search",
+				"progress_iteration":      1,
+				"progress_max_iterations": 10,
+			},
 		})
 		_, _ = fmt.Fprintf(w, "event: progress\ndata: %s\n\n", progressData)
 		flusher.Flush()
@@ -2,10 +2,16 @@ package forgeui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 )
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
@@ -134,6 +140,76 @@ func (s *UIServer) handleStopAgent(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "stopped", "agent_id": id})
 }
 
+// maxLogTail bounds how much of serve.log handleAgentLogs reads.
+const maxLogTail = 1 << 20
+
+// handleAgentLogs returns the last ?lines= lines (default 100, max 5000)
+// of the agent's .forge/serve.log.
+func (s *UIServer) handleAgentLogs(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	n := 100
+	if v := r.URL.Query().Get("lines"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, "lines must be a positive integer")
+			return
+		}
+		n = min(parsed, 5000)
+	}
+
+	agents, err := s.scanner.Scan()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	agent, ok := agents[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "agent not found")
+		return
+	}
+
+	lines, err := tailFile(filepath.Join(agent.Directory, ".forge", "serve.log"), n)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"agent_id": id, "lines": lines})
+}
+
+// tailFile returns up to the last n lines of path, reading at most
+// maxLogTail bytes from its end.
+func tailFile(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	off := max(st.Size()-maxLogTail, 0)
+	data := make([]byte, st.Size()-off)
+	if _, err := f.ReadAt(data, off); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(text, "\n")
+	if off > 0 {
+		lines = lines[1:] // the first line is likely cut off
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
 // handleRescan forces a workspace re-scan and returns the updated agent list.
 func (s *UIServer) handleRescan(w http.ResponseWriter, r *http.Request) {
 	agents, err := s.scanner.Scan()
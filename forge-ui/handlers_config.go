@@ -0,0 +1,117 @@
+package forgeui
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/initializ/forge/forge-core/schemas"
+)
+
+// handleGetConfigSchema serves the forge.yaml JSON Schema. The config
+// editor feeds it to Monaco for hover docs and completion, and renders the
+// per-field descriptions in its docs panel.
+func (s *UIServer) handleGetConfigSchema(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(schemas.ForgeConfigV1Schema)
+}
+
+// handleDiffConfig returns a unified diff between the on-disk forge.yaml
+// and the proposed content, together with its validation result. The
+// returned BaseSHA256 is echoed back on apply so the save can detect a
+// concurrent edit.
+func (s *UIServer) handleDiffConfig(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "agent id is required")
+		return
+	}
+
+	var req ConfigUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	agents, err := s.scanner.Scan()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	agent, ok := agents[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "agent not found")
+		return
+	}
+
+	current, err := os.ReadFile(filepath.Join(agent.Directory, "forge.yaml"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "reading config: "+err.Error())
+		return
+	}
+
+	diff := unifiedDiff("forge.yaml", string(current), req.Content)
+	writeJSON(w, http.StatusOK, ConfigDiffResponse{
+		ConfigValidateResponse: validateConfigContent(req.Content),
+		Diff:                   diff,
+		Changed:                diff != "",
+		BaseSHA256:             contentSHA256(current),
+	})
+}
+
+// contentSHA256 returns the hex SHA-256 of data.
+func contentSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFileAtomic writes data to a temp file in path's directory and
+// renames it into place. The agent's file watcher polls modification
+// times, so a half-written forge.yaml must never be visible to it.
+//
+// A symlinked path is written through to the file it points at, and an
+// existing file keeps its permission bits (a forge.yaml naming secrets
+// may be 0600); perm applies only to a new file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("resolving %s: %w", path, err)
+	}
+	if fi, err := os.Stat(path); err == nil {
+		perm = fi.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp.*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op once rename succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("renaming to %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package forgeui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHandleGetConfigSchema(t *testing.T) {
+	srv, _ := setupTestServerWithCreate(t)
+
+	w := httptest.NewRecorder()
+	srv.handleGetConfigSchema(w, httptest.NewRequest(http.MethodGet, "/api/config/schema", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var schema struct {
+		Title      string                    `json:"title"`
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&schema); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if schema.Title != "ForgeConfig" {
+		t.Errorf("title = %q, want ForgeConfig", schema.Title)
+	}
+	if desc, _ := schema.Properties["egress"]["description"].(string); desc == "" {
+		t.Error("expected egress to carry an inline description")
+	}
+}
+
+func TestHandleDiffConfig(t *testing.T) {
+	srv, _ := setupTestServerWithCreate(t)
+
+	newContent := `agent_id: test-agent
+version: 0.1.0
+framework: forge
+model:
+  provider: anthropic
+  name: gpt-4o
+`
+	body, _ := json.Marshal(ConfigUpdateRequest{Content: newContent})
+	req := httptest.NewRequest(http.MethodPost, "/api/agents/test-agent/config/diff", bytes.NewReader(body))
+	req.SetPathValue("id", "test-agent")
+	w := httptest.NewRecorder()
+	srv.handleDiffConfig(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp ConfigDiffResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if !resp.Changed || !resp.Valid {
+		t.Fatalf("expected a valid change, got %+v", resp)
+	}
+	if !strings.Contains(resp.Diff, "-  provider: openai\n+  provider: anthropic\n") {
+		t.Errorf("diff missing provider change:\n%s", resp.Diff)
+	}
+	if resp.BaseSHA256 == "" {
+		t.Error("expected base_sha256")
+	}
+}
+
+func TestHandleDiffConfig_UnknownFieldWarns(t *testing.T) {
+	srv, _ := setupTestServerWithCreate(t)
+
+	body, _ := json.Marshal(ConfigUpdateRequest{Content: "agent_id: test-agent\nversion: 0.1.0\nframework: forge\nchanels: [slack]\n"})
+	req := httptest.NewRequest(http.MethodPost, "/api/agents/test-agent/config/diff", bytes.NewReader(body))
+	req.SetPathValue("id", "test-agent")
+	w := httptest.NewRecorder()
+	srv.handleDiffConfig(w, req)
+
+	var resp ConfigDiffResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if !resp.Valid {
+		t.Errorf("unknown field must not invalidate config, got errors: %v", resp.Errors)
+	}
+	found := false
+	for _, warn := range resp.Warnings {
+		if strings.Contains(warn, `"chanels"`) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected unknown-field warning, got %v", resp.Warnings)
+	}
+}
+
+func TestHandleUpdateConfig_BaseHashConflict(t *testing.T) {
+	srv, root := setupTestServerWithCreate(t)
+	configPath := filepath.Join(root, "test-agent", "forge.yaml")
+
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := contentSHA256(original)
+
+	// Someone edits the file after the diff was previewed.
+	writeFile(t, configPath, string(original)+"channels: [slack]\n")
+
+	body, _ := json.Marshal(ConfigUpdateRequest{
+		Content:    "agent_id: test-agent\nversion: 0.2.0\nframework: forge\n",
+		BaseSHA256: base,
+	})
+	req := httptest.NewRequest(http.MethodPut, "/api/agents/test-agent/config", bytes.NewReader(body))
+	req.SetPathValue("id", "test-agent")
+	w := httptest.NewRecorder()
+	srv.handleUpdateConfig(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "channels: [slack]") {
+		t.Error("concurrent edit was overwritten")
+	}
+}
+
+func TestHandleUpdateConfig_ApplyResponse(t *testing.T) {
+	srv, root := setupTestServerWithCreate(t)
+	configPath := filepath.Join(root, "test-agent", "forge.yaml")
+
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(ConfigUpdateRequest{
+		Content:    "agent_id: test-agent\nversion: 0.2.0\nframework: forge\n",
+		BaseSHA256: contentSHA256(original),
+	})
+	req := httptest.NewRequest(http.MethodPut, "/api/agents/test-agent/config", bytes.NewReader(body))
+	req.SetPathValue("id", "test-agent")
+	w := httptest.NewRecorder()
+	srv.handleUpdateConfig(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp ConfigApplyResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if !resp.Applied {
+		t.Error("expected applied = true")
+	}
+	if resp.ReloadPending {
+		t.Error("stopped agent must not report a pending reload")
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(configPath))
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp.") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestHandleUpdateConfig_KeepsModeAndSymlink(t *testing.T) {
+	srv, root := setupTestServerWithCreate(t)
+	dir := filepath.Join(root, "test-agent")
+	configPath := filepath.Join(dir, "forge.yaml")
+
+	// forge.yaml is a 0600 file kept elsewhere and linked into the agent.
+	real := filepath.Join(t.TempDir(), "forge.yaml")
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(real, original, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "agent_id: test-agent\nversion: 0.2.0\nframework: forge\n"
+	body, _ := json.Marshal(ConfigUpdateRequest{Content: content})
+	req := httptest.NewRequest(http.MethodPut, "/api/agents/test-agent/config", bytes.NewReader(body))
+	req.SetPathValue("id", "test-agent")
+	w := httptest.NewRecorder()
+	srv.handleUpdateConfig(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	if fi, err := os.Lstat(configPath); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("forge.yaml is no longer a symlink: %v", err)
+	}
+	fi, err := os.Stat(real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %o, want 600", perm)
+	}
+	if data, _ := os.ReadFile(real); string(data) != content {
+		t.Errorf("target content = %q", data)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	if got := unifiedDiff("f", "a\nb\n", "a\nb\n"); got != "" {
+		t.Errorf("identical input should produce empty diff, got %q", got)
+	}
+
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n20\n"
+	updated := strings.Replace(old, "2\n", "two\n", 1)
+	updated = strings.Replace(updated, "19\n", "nineteen\n", 1)
+
+	got := unifiedDiff("f", old, updated)
+	want := `--- a/f
++++ b/f
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -16,5 +16,5 @@
+ 16
+ 17
+ 18
+-19
++nineteen
+ 20
+`
+	if got != want {
+		t.Errorf("unifiedDiff mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
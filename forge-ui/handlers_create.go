@@ -22,7 +22,7 @@ import (
 // per-provider model lists, and web search provider options.
 func (s *UIServer) handleGetWizardMeta(w http.ResponseWriter, _ *http.Request) {
 	meta := WizardMetadata{
-		Providers:  []string{"openai", "anthropic", "gemini", "ollama", "custom"},
+		Providers:  []string{"openai", "anthropic", "gemini", "mistral", "cohere", "groq", "together", "openrouter", "xai", "deepseek", "ollama", "custom"},
 		Frameworks: []string{"forge", "crewai", "langchain"},
 		Channels:   []string{"slack", "telegram"},
 	}
@@ -64,6 +64,70 @@ func (s *UIServer) handleGetWizardMeta(w http.ResponseWriter, _ *http.Request) {
 				{DisplayName: "Gemini 2.5 Pro", ModelID: "gemini-2.5-pro"},
 			},
 		},
+		"mistral": {
+			Default:  "mistral-large-latest",
+			NeedsKey: true,
+			APIKey: []ModelOption{
+				{DisplayName: "Mistral Large", ModelID: "mistral-large-latest"},
+				{DisplayName: "Mistral Medium", ModelID: "mistral-medium-latest"},
+				{DisplayName: "Mistral Small", ModelID: "mistral-small-latest"},
+			},
+		},
+		"cohere": {
+			Default:  "command-r-plus",
+			NeedsKey: true,
+			APIKey: []ModelOption{
+				{DisplayName: "Command R+", ModelID: "command-r-plus"},
+				{DisplayName: "Command R", ModelID: "command-r"},
+			},
+		},
+		"groq": {
+			Default:  "llama-3.3-70b-versatile",
+			NeedsKey: true,
+			APIKey: []ModelOption{
+				{DisplayName: "Llama 3.3 70B Versatile", ModelID: "llama-3.3-70b-versatile"},
+				{DisplayName: "Llama 3.1 8B Instant", ModelID: "llama-3.1-8b-instant"},
+				{DisplayName: "GPT-OSS 120B", ModelID: "openai/gpt-oss-120b"},
+				{DisplayName: "Qwen3 32B", ModelID: "qwen/qwen3-32b"},
+			},
+		},
+		"together": {
+			Default:  "meta-llama/Llama-3.3-70B-Instruct-Turbo",
+			NeedsKey: true,
+			APIKey: []ModelOption{
+				{DisplayName: "Llama 3.3 70B Instruct Turbo", ModelID: "meta-llama/Llama-3.3-70B-Instruct-Turbo"},
+				{DisplayName: "Qwen 2.5 72B Instruct Turbo", ModelID: "Qwen/Qwen2.5-72B-Instruct-Turbo"},
+				{DisplayName: "DeepSeek V3", ModelID: "deepseek-ai/DeepSeek-V3"},
+				{DisplayName: "Kimi K2 Instruct", ModelID: "moonshotai/Kimi-K2-Instruct"},
+			},
+		},
+		"openrouter": {
+			Default:  "openai/gpt-4o",
+			NeedsKey: true,
+			APIKey: []ModelOption{
+				{DisplayName: "GPT-4o", ModelID: "openai/gpt-4o"},
+				{DisplayName: "Claude Sonnet 4", ModelID: "anthropic/claude-sonnet-4"},
+				{DisplayName: "Gemini 2.5 Flash", ModelID: "google/gemini-2.5-flash"},
+				{DisplayName: "Llama 3.3 70B Instruct", ModelID: "meta-llama/llama-3.3-70b-instruct"},
+			},
+		},
+		"xai": {
+			Default:  "grok-4",
+			NeedsKey: true,
+			APIKey: []ModelOption{
+				{DisplayName: "Grok 4", ModelID: "grok-4"},
+				{DisplayName: "Grok 3", ModelID: "grok-3"},
+				{DisplayName: "Grok 3 Mini", ModelID: "grok-3-mini"},
+			},
+		},
+		"deepseek": {
+			Default:  "deepseek-chat",
+			NeedsKey: true,
+			APIKey: []ModelOption{
+				{DisplayName: "DeepSeek Chat (V3)", ModelID: "deepseek-chat"},
+				{DisplayName: "DeepSeek Reasoner (R1)", ModelID: "deepseek-reasoner"},
+			},
+		},
 		"ollama": {
 			Default:  "llama3",
 			NeedsKey: false,
@@ -343,12 +407,27 @@ func (s *UIServer) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	configPath := filepath.Join(agent.Directory, "forge.yaml")
-	if err := os.WriteFile(configPath, []byte(req.Content), 0o644); err != nil {
+	if req.BaseSHA256 != "" {
+		current, err := os.ReadFile(configPath)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "reading config: "+err.Error())
+			return
+		}
+		if contentSHA256(current) != req.BaseSHA256 {
+			writeError(w, http.StatusConflict, "forge.yaml changed on disk since the diff was previewed; reload and re-apply")
+			return
+		}
+	}
+	if err := writeFileAtomic(configPath, []byte(req.Content), 0o644); err != nil {
 		writeError(w, http.StatusInternalServerError, "writing config: "+err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	writeJSON(w, http.StatusOK, ConfigApplyResponse{
+		ConfigValidateResponse: resp,
+		Applied:                true,
+		ReloadPending:          agent.Status == StateRunning,
+	})
 }
 
 // handleValidateConfig validates forge.yaml content without saving.
@@ -441,6 +520,14 @@ func validateConfigContent(content string) ConfigValidateResponse {
 	}
 
 	result := validate.ValidateForgeConfig(cfg)
+
+	// Schema pass: catches unknown keys and type / range mistakes the
+	// typed parser silently accepts.
+	if sr, err := validate.ValidateForgeConfigSchema([]byte(content)); err == nil {
+		result.Errors = append(result.Errors, sr.Errors...)
+		result.Warnings = append(result.Warnings, sr.Warnings...)
+	}
+
 	return ConfigValidateResponse{
 		Valid:    result.IsValid(),
 		Errors:   result.Errors,
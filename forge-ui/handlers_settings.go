@@ -40,7 +40,7 @@ func (s *UIServer) handleGetSkillBuilderSettings(w http.ResponseWriter, _ *http.
 		"has_key":     llm.HasCredentials(),
 		"source":      llm.Source,
 		"warning":     llm.Warning,
-		"providers":   []string{"openai", "anthropic", "gemini", "ollama"},
+		"providers":   []string{"openai", "anthropic", "gemini", "mistral", "cohere", "groq", "together", "openrouter", "xai", "deepseek", "ollama"},
 	})
 }
 
@@ -117,6 +117,20 @@ func defaultAPIKeyEnv(provider string) string {
 		return "ANTHROPIC_API_KEY"
 	case "gemini":
 		return "GEMINI_API_KEY"
+	case "mistral":
+		return "MISTRAL_API_KEY"
+	case "cohere":
+		return "COHERE_API_KEY"
+	case "groq":
+		return "GROQ_API_KEY"
+	case "together":
+		return "TOGETHER_API_KEY"
+	case "openrouter":
+		return "OPENROUTER_API_KEY"
+	case "xai":
+		return "XAI_API_KEY"
+	case "deepseek":
+		return "DEEPSEEK_API_KEY"
 	}
 	return ""
 }
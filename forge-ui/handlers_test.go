@@ -2,10 +2,13 @@ package forgeui
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -152,3 +155,41 @@ model:
 		t.Fatalf("expected 2 agents after rescan, got %d", len(agents))
 	}
 }
+
+func TestHandleAgentLogs(t *testing.T) {
+	srv, root := setupTestServer(t)
+	var log strings.Builder
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(&log, "line %d\n", i)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "test-agent", ".forge"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "test-agent", ".forge", "serve.log"), log.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/agents/test-agent/logs?lines=2", nil)
+	req.SetPathValue("id", "test-agent")
+	w := httptest.NewRecorder()
+	srv.handleAgentLogs(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var resp struct {
+		Lines []string `json:"lines"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if want := []string{"line 4", "line 5"}; !slices.Equal(resp.Lines, want) {
+		t.Errorf("lines = %q, want %q", resp.Lines, want)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/agents/test-agent/logs?lines=zero", nil)
+	req.SetPathValue("id", "test-agent")
+	w = httptest.NewRecorder()
+	srv.handleAgentLogs(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad lines: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
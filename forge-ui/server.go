@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/initializ/forge/forge-ui/static"
@@ -17,6 +19,8 @@ import (
 // UIServerConfig configures the UI dashboard server.
 type UIServerConfig struct {
 	Port          int             // default: 4200
+	Host          string          // bind address (default: 127.0.0.1)
+	APIToken      string          // when set, required on /api/ routes; see apiAuthMiddleware
 	WorkDir       string          // workspace root to scan for agents
 	ExePath       string          // path to forge binary for exec
 	Version       string          // forge version string
@@ -36,6 +40,7 @@ type UIServer struct {
 	broker        *SSEBroker
 	srv           *http.Server
 	updateChecker *updateInfo
+	widgetMu      sync.Mutex // serialises widget token file updates
 }
 
 // NewUIServer creates a UIServer with the given configuration.
@@ -43,6 +48,9 @@ func NewUIServer(cfg UIServerConfig) *UIServer {
 	if cfg.Port == 0 {
 		cfg.Port = 4200
 	}
+	if cfg.Host == "" {
+		cfg.Host = "127.0.0.1"
+	}
 	if cfg.AgentPort == 0 {
 		cfg.AgentPort = 9100
 	}
@@ -77,6 +85,7 @@ func (s *UIServer) Start(ctx context.Context) error {
 	mux.HandleFunc("GET /api/agents/{id}", s.handleGetAgent)
 	mux.HandleFunc("POST /api/agents/{id}/start", s.handleStartAgent)
 	mux.HandleFunc("POST /api/agents/{id}/stop", s.handleStopAgent)
+	mux.HandleFunc("GET /api/agents/{id}/logs", s.handleAgentLogs)
 	mux.HandleFunc("POST /api/agents/{id}/chat", s.handleChat)
 	mux.HandleFunc("GET /api/agents/{id}/sessions", s.handleListSessions)
 	mux.HandleFunc("GET /api/agents/{id}/sessions/{sid}", s.handleGetSession)
@@ -87,6 +96,8 @@ func (s *UIServer) Start(ctx context.Context) error {
 	mux.HandleFunc("GET /api/agents/{id}/config", s.handleGetConfig)
 	mux.HandleFunc("PUT /api/agents/{id}/config", s.handleUpdateConfig)
 	mux.HandleFunc("POST /api/agents/{id}/config/validate", s.handleValidateConfig)
+	mux.HandleFunc("POST /api/agents/{id}/config/diff", s.handleDiffConfig)
+	mux.HandleFunc("GET /api/config/schema", s.handleGetConfigSchema)
 	// User-policy surface (issue #90 / FWS-6 three-layer). Read /
 	// write the user-level ~/.forge/policy.yaml that bounds every
 	// agent the user runs locally. System and workspace layers are
@@ -122,6 +133,15 @@ func (s *UIServer) Start(ctx context.Context) error {
 	mux.HandleFunc("GET /api/settings/skill-builder", s.handleGetSkillBuilderSettings)
 	mux.HandleFunc("PUT /api/settings/skill-builder", s.handlePutSkillBuilderSettings)
 
+	// Webchat widget: the embeddable bundle, its CORS-scoped chat API,
+	// and token management for the dashboard.
+	mux.HandleFunc("GET /widget.js", s.handleWidgetScript)
+	mux.HandleFunc("OPTIONS /widget/chat", s.handleWidgetPreflight)
+	mux.HandleFunc("POST /widget/chat", s.handleWidgetChat)
+	mux.HandleFunc("GET /api/agents/{id}/widget-tokens", s.handleListWidgetTokens)
+	mux.HandleFunc("POST /api/agents/{id}/widget-tokens", s.handleCreateWidgetToken)
+	mux.HandleFunc("DELETE /api/agents/{id}/widget-tokens/{tid}", s.handleDeleteWidgetToken)
+
 	// Static file serving with SPA fallback. The embedded FS is rooted
 	// directly at the static assets — no "dist/" subdirectory (review #8
 	// removed the misleading dist/ naming).
@@ -147,10 +167,10 @@ func (s *UIServer) Start(ctx context.Context) error {
 		fileServer.ServeHTTP(w, r)
 	})
 
-	addr := fmt.Sprintf("127.0.0.1:%d", s.cfg.Port)
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
 	s.srv = &http.Server{
 		Addr:         addr,
-		Handler:      corsMiddleware(mux),
+		Handler:      corsMiddleware(apiAuthMiddleware(s.cfg.APIToken, mux)),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 0, // disabled for SSE
 	}
@@ -165,12 +185,19 @@ func (s *UIServer) Start(ctx context.Context) error {
 	fmt.Printf("  ─────────────────────────────────\n")
 	fmt.Printf("  URL:       http://%s\n", addr)
 	fmt.Printf("  Workspace: %s\n", s.cfg.WorkDir)
+	if s.cfg.APIToken != "" {
+		fmt.Printf("  Auth:      API token required (open /?token=<token> in a browser)\n")
+	}
 	fmt.Printf("  ─────────────────────────────────\n\n")
 
 	if s.cfg.OpenBrowser {
 		go func() {
 			time.Sleep(500 * time.Millisecond)
-			openBrowser(fmt.Sprintf("http://%s", addr))
+			url := fmt.Sprintf("http://%s", addr)
+			if s.cfg.APIToken != "" {
+				url += "/?token=" + s.cfg.APIToken
+			}
+			openBrowser(url)
 		}()
 	}
 
@@ -190,12 +217,17 @@ func (s *UIServer) Start(ctx context.Context) error {
 	return nil
 }
 
-// corsMiddleware adds CORS headers.
+// corsMiddleware adds CORS headers. The widget chat API sets its own,
+// scoped to each token's origins.
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/widget/") {
+			next.ServeHTTP(w, r)
+			return
+		}
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
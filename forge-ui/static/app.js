@@ -98,17 +98,33 @@ async function fetchConfig(agentId) {
   return res.text();
 }
 
-async function saveConfig(agentId, content) {
+async function saveConfig(agentId, content, baseSha256) {
   const res = await fetch(`/api/agents/${agentId}/config`, {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify({ content }),
+    body: JSON.stringify({ content, base_sha256: baseSha256 || undefined }),
   });
   const data = await res.json();
-  if (!res.ok && !data.errors) throw new Error(`Failed to save config: ${res.status}`);
+  if (!res.ok && !data.errors) throw new Error(data.error || `Failed to save config: ${res.status}`);
   return data;
 }
 
+async function diffConfig(agentId, content) {
+  const res = await fetch(`/api/agents/${agentId}/config/diff`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ content }),
+  });
+  if (!res.ok) throw new Error(`Failed to diff config: ${res.status}`);
+  return res.json();
+}
+
+async function fetchConfigSchema() {
+  const res = await fetch('/api/config/schema');
+  if (!res.ok) throw new Error(`Failed to fetch config schema: ${res.status}`);
+  return res.json();
+}
+
 async function validateConfig(agentId, content) {
   const res = await fetch(`/api/agents/${agentId}/config/validate`, {
     method: 'POST',
@@ -557,7 +573,9 @@ function useChatStream(agentId) {
       const decoder = new TextDecoder();
       let buffer = '';
       let currentTools = [];
+      let activity = null;
       let agentText = '';
+      let citations = [];
       let receivedSessionId = sessionId;
 
       while (true) {
@@ -608,27 +626,35 @@ function useChatStream(agentId) {
                 return [...prev, { role: 'agent', content: agentText, tools: [...currentTools], isStreaming: true }];
               });
             } else if (eventType === 'progress') {
-              // Tool execution progress
+              // Typed progress: the phase, tool, percent and loop
+              // iteration ride in metadata, the message in a text part.
               const status = parsed.status || parsed;
+              const meta = parsed.metadata || {};
+              let text = '';
               if (status.message && status.message.parts) {
                 for (const part of status.message.parts) {
-                  if (part.kind === 'data' && part.data) {
-                    const toolInfo = part.data;
-                    currentTools = [...currentTools.filter(t =>
-                      !(t.name === toolInfo.name && t.phase === 'start')
-                    ), toolInfo];
-                  }
+                  if (part.kind === 'text' && part.text) text = part.text;
                 }
               }
+              currentTools = applyToolProgress(currentTools, meta, text);
+              if (meta.progress_iteration) {
+                activity = { ...activity, iteration: meta.progress_iteration, maxIterations: meta.progress_max_iterations };
+              }
+              if (['planning', 'llm_thinking', 'compaction', 'reflection', 'plan'].includes(meta.progress_phase)) {
+                activity = { ...activity, phase: meta.progress_phase, message: text };
+              }
+              if (meta.plan) {
+                activity = { ...activity, plan: meta.plan };
+              }
               // Update messages in real-time to show tool progress
               setMessages(prev => {
                 const last = prev[prev.length - 1];
                 if (last && last.role === 'agent' && last.isStreaming) {
                   const updated = [...prev];
-                  updated[updated.length - 1] = { ...last, content: agentText, tools: [...currentTools] };
+                  updated[updated.length - 1] = { ...last, content: agentText, tools: [...currentTools], activity };
                   return updated;
                 }
-                return [...prev, { role: 'agent', content: agentText, tools: [...currentTools], isStreaming: true }];
+                return [...prev, { role: 'agent', content: agentText, tools: [...currentTools], activity, isStreaming: true }];
               });
             } else if (eventType === 'result') {
               // Final result
@@ -641,15 +667,19 @@ function useChatStream(agentId) {
                   }
                 }
               }
+              // Sources the response cites as [n]
+              if (status.message && status.message.citations) {
+                citations = status.message.citations;
+              }
               // Show final text immediately
               setMessages(prev => {
                 const last = prev[prev.length - 1];
                 if (last && last.role === 'agent' && last.isStreaming) {
                   const updated = [...prev];
-                  updated[updated.length - 1] = { ...last, content: agentText, tools: [...currentTools] };
+                  updated[updated.length - 1] = { ...last, content: agentText, tools: [...currentTools], citations, activity: null };
                   return updated;
                 }
-                return [...prev, { role: 'agent', content: agentText, tools: [...currentTools], isStreaming: true }];
+                return [...prev, { role: 'agent', content: agentText, tools: [...currentTools], citations, isStreaming: true }];
               });
             } else if (eventType === 'done') {
               if (parsed.session_id) {
@@ -665,7 +695,7 @@ function useChatStream(agentId) {
         setMessages(prev => {
           // Remove streaming placeholder if present
           const filtered = prev.filter(m => !m.isStreaming);
-          return [...filtered, { role: 'agent', content: agentText, tools: currentTools }];
+          return [...filtered, { role: 'agent', content: agentText, tools: currentTools, citations }];
         });
       }
 
@@ -968,26 +998,95 @@ function Dashboard({ agents, onStart, onStop, onRescan, onChannelsChanged, loadi
 
 // ── Tool Card Component ──────────────────────────────────────
 
+// Streamed tool output kept per card; older output scrolls off.
+const TOOL_OUTPUT_MAX_CHARS = 8000;
+
+// applyToolProgress folds one tool_start / tool_stream / tool_end
+// progress event into the tool cards. A tool_stream event carrying a
+// percent updates the card's bar; one without is streamed output.
+function applyToolProgress(tools, meta, text) {
+  const name = meta.progress_tool;
+  if (!name) return tools;
+  const running = tools.findIndex(t => t.name === name && t.phase === 'start');
+  switch (meta.progress_phase) {
+    case 'tool_start':
+      return [...tools, { name, phase: 'start', message: text, output: '' }];
+    case 'tool_stream': {
+      if (running < 0) return tools;
+      const t = { ...tools[running] };
+      if (meta.progress_percent != null) {
+        t.percent = meta.progress_percent;
+        t.message = text;
+      } else {
+        t.output = (t.output + text).slice(-TOOL_OUTPUT_MAX_CHARS);
+      }
+      return tools.map((x, i) => (i === running ? t : x));
+    }
+    case 'tool_end': {
+      const done = { ...(running >= 0 ? tools[running] : { name, output: '' }), phase: 'end', message: text };
+      if (running < 0) return [...tools, done];
+      return tools.map((x, i) => (i === running ? done : x));
+    }
+  }
+  return tools;
+}
+
 function ToolCard({ tool }) {
   const [expanded, setExpanded] = useState(false);
   const phase = tool.phase || 'unknown';
   const phaseClass = phase === 'end' ? 'tool-done' : 'tool-running';
+  const running = phase !== 'end';
+  const status = !running ? 'completed' : tool.percent != null ? `${tool.percent}%` : 'running...';
+  const body = [tool.message, tool.output].filter(Boolean).join('\n');
 
   return html`
     <div class="chat-tool-card ${phaseClass}" onClick=${() => setExpanded(!expanded)}>
       <div class="chat-tool-header">
         <span class="chat-tool-icon">${phase === 'end' ? '\u2713' : '\u25B6'}</span>
         <span class="chat-tool-name">${tool.name || 'tool'}</span>
-        <span class="chat-tool-phase">${phase === 'end' ? 'completed' : 'running...'}</span>
+        <span class="chat-tool-phase">${status}</span>
         <span class="chat-tool-chevron ${expanded ? 'expanded' : ''}">\u25B8</span>
       </div>
-      ${expanded && tool.message && html`
-        <div class="chat-tool-body">${tool.message}</div>
+      ${running && tool.percent != null && html`
+        <div class="chat-tool-progress"><div class="chat-tool-progress-bar" style=${{ width: `${tool.percent}%` }} /></div>
       `}
+      ${expanded && body && html`
+        <div class="chat-tool-body">${body}</div>
+      `}
+    </div>
+  `;
+}
+
+// ActivityLine shows what the agent loop is doing between tools:
+// planning, thinking over tool results, or compacting context.
+function ActivityLine({ activity }) {
+  const step = activity.iteration
+    ? `Step ${activity.iteration}${activity.maxIterations ? ` of ${activity.maxIterations}` : ''}`
+    : '';
+  return html`
+    <div class="chat-activity">
+      ${step && html`<span class="chat-activity-step">${step}</span>`}
+      <span>${activity.message}</span>
     </div>
   `;
 }
 
+const PLAN_STEP_MARKS = { pending: '○', in_progress: '◐', done: '●', failed: '✕' };
+
+// PlanList shows a planner-executor task's steps and their status.
+function PlanList({ plan }) {
+  return html`
+    <ol class="chat-plan">
+      ${plan.steps.map(s => html`
+        <li key=${s.id} class="chat-plan-step ${s.status}" title=${s.result || ''}>
+          <span class="chat-plan-mark">${PLAN_STEP_MARKS[s.status] || '○'}</span>
+          <span>${s.goal}</span>
+        </li>
+      `)}
+    </ol>
+  `;
+}
+
 // ── Message Bubble Component ─────────────────────────────────
 
 function MessageBubble({ message }) {
@@ -1010,6 +1109,12 @@ function MessageBubble({ message }) {
   // Agent message
   return html`
     <div class="chat-bubble agent">
+      ${message.isStreaming && message.activity && message.activity.plan && html`
+        <${PlanList} plan=${message.activity.plan} />
+      `}
+      ${message.isStreaming && message.activity && message.activity.message && html`
+        <${ActivityLine} activity=${message.activity} />
+      `}
       ${message.tools && message.tools.length > 0 && html`
         <div class="chat-tools">
           ${message.tools.map((t, i) => html`<${ToolCard} key=${i} tool=${t} />`)}
@@ -1021,10 +1126,31 @@ function MessageBubble({ message }) {
       ${message.isStreaming && !message.content && html`
         <div class="chat-bubble-content"><span class="typing-indicator" /></div>
       `}
+      ${message.citations && message.citations.length > 0 && html`
+        <ol class="chat-citations">
+          ${message.citations.map(c => html`<${CitationItem} key=${c.id} citation=${c} />`)}
+        </ol>
+      `}
     </div>
   `;
 }
 
+// CitationItem renders one source the agent cited: web pages as links,
+// knowledge base documents and memory files with their location.
+function CitationItem({ citation }) {
+  const title = citation.title || citation.url || citation.source;
+  const source = citation.source !== title ? citation.source : '';
+  const where = citation.url ? '' : [source, citation.location].filter(Boolean).join(', ');
+  return html`
+    <li class="chat-citation" value=${citation.id}>
+      ${citation.url
+        ? html`<a href=${citation.url} target="_blank" rel="noopener noreferrer">${title}</a>`
+        : html`<span>${title}</span>`}
+      ${where && html`<span class="chat-citation-where">${where}</span>`}
+    </li>
+  `;
+}
+
 // ── Chat Page Component ──────────────────────────────────────
 
 function ChatPage({ agentId, agents }) {
@@ -1288,6 +1414,13 @@ const PROVIDER_KEY_INFO = {
   openai:    { label: 'OpenAI API Key',    placeholder: 'sk-...', envVar: 'OPENAI_API_KEY' },
   anthropic: { label: 'Anthropic API Key', placeholder: 'sk-ant-...', envVar: 'ANTHROPIC_API_KEY' },
   gemini:    { label: 'Gemini API Key',    placeholder: 'AI...', envVar: 'GEMINI_API_KEY' },
+  mistral:   { label: 'Mistral API Key',   placeholder: 'your-mistral-key', envVar: 'MISTRAL_API_KEY' },
+  cohere:    { label: 'Cohere API Key',    placeholder: 'your-cohere-key', envVar: 'COHERE_API_KEY' },
+  groq:      { label: 'Groq API Key',      placeholder: 'gsk_...', envVar: 'GROQ_API_KEY' },
+  together:  { label: 'Together AI API Key', placeholder: 'your-together-key', envVar: 'TOGETHER_API_KEY' },
+  openrouter: { label: 'OpenRouter API Key', placeholder: 'sk-or-...', envVar: 'OPENROUTER_API_KEY' },
+  xai:       { label: 'xAI API Key',       placeholder: 'xai-...', envVar: 'XAI_API_KEY' },
+  deepseek:  { label: 'DeepSeek API Key',  placeholder: 'sk-...', envVar: 'DEEPSEEK_API_KEY' },
   custom:    { label: 'API Key / Auth Token', placeholder: 'your-api-key', envVar: 'MODEL_API_KEY' },
 };
 
@@ -1305,6 +1438,9 @@ const CHANNEL_TOKEN_FIELDS = {
 // Fallback provider env var keys
 const FALLBACK_KEY_MAP = {
   openai: 'OPENAI_API_KEY', anthropic: 'ANTHROPIC_API_KEY', gemini: 'GEMINI_API_KEY',
+  mistral: 'MISTRAL_API_KEY', cohere: 'COHERE_API_KEY', groq: 'GROQ_API_KEY',
+  together: 'TOGETHER_API_KEY', openrouter: 'OPENROUTER_API_KEY', xai: 'XAI_API_KEY',
+  deepseek: 'DEEPSEEK_API_KEY',
 };
 
 function slugify(name) {
@@ -1523,6 +1659,13 @@ function CreatePage() {
           openai: 'GPT 5.3 Codex, GPT 5.2, GPT 5 Mini',
           anthropic: 'Claude Sonnet, Haiku, Opus',
           gemini: 'Gemini 2.5 Flash, Pro',
+          mistral: 'Mistral Large, Medium, Small',
+          cohere: 'Command R+, Command R',
+          groq: 'Llama 3.3 70B, Llama 3.1 8B',
+          together: 'Llama 3.3 70B, Qwen 2.5, DeepSeek V3',
+          openrouter: 'Hundreds of models behind one key',
+          xai: 'Grok 4, Grok 3 Mini',
+          deepseek: 'DeepSeek V3 chat, R1 reasoner',
           ollama: 'Run models locally, no API key needed',
           custom: 'Any OpenAI-compatible endpoint',
         };
@@ -1818,7 +1961,10 @@ function CreatePage() {
           }));
         };
         const fbDescriptions = {
-          openai: 'GPT models', anthropic: 'Claude models', gemini: 'Gemini models', ollama: 'Local models (no key needed)',
+          openai: 'GPT models', anthropic: 'Claude models', gemini: 'Gemini models',
+          mistral: 'Mistral models', cohere: 'Command R models', groq: 'Groq-hosted models',
+          together: 'Together-hosted models', openrouter: 'OpenRouter models', xai: 'Grok models',
+          deepseek: 'DeepSeek models', ollama: 'Local models (no key needed)',
         };
         return html`
           <div class="wizard-step">
@@ -2134,14 +2280,99 @@ function CreatePage() {
 
 // ── Config Editor Page ───────────────────────────────────────
 
+// yamlKeyPathAt returns the chain of mapping keys leading to the key on
+// lineIndex (0-based), e.g. ['mcp', 'servers', 'url']. List items are
+// transparent — the schema walk below steps into `items` on its own. The
+// editor only needs this for hover docs, so a light indentation scan is
+// enough; it doesn't try to be a YAML parser.
+function yamlKeyPathAt(lines, lineIndex) {
+  const keyRe = /^(\s*)(?:-\s+)?([A-Za-z0-9_.-]+)\s*:/;
+  const m = keyRe.exec(lines[lineIndex] || '');
+  if (!m) return [];
+  const path = [m[2]];
+  let indent = m[1].length;
+  // YAML allows list items at the same indent as their parent key
+  // ("servers:\n- name: x"), so after a dash the parent may be level.
+  let inItem = lines[lineIndex].slice(indent).startsWith('-');
+  for (let i = lineIndex - 1; i >= 0 && (indent > 0 || inItem); i--) {
+    const pm = keyRe.exec(lines[i]);
+    if (!pm) continue;
+    const pIndent = pm[1].length;
+    const isItem = lines[i].slice(pIndent).startsWith('-');
+    if (pIndent < indent || (inItem && !isItem && pIndent === indent)) {
+      if (isItem) {
+        // First key of the enclosing list item; keep climbing from the dash.
+        indent = pIndent;
+        inItem = true;
+        continue;
+      }
+      path.unshift(pm[2]);
+      indent = pIndent;
+      inItem = false;
+    }
+  }
+  return path;
+}
+
+// schemaNodeFor walks the forge.yaml JSON Schema along a key path,
+// stepping through array `items` transparently.
+function schemaNodeFor(schema, path) {
+  let node = schema;
+  for (const key of path) {
+    while (node && node.type === 'array' && node.items) node = node.items;
+    node = node && node.properties ? node.properties[key] : null;
+    if (!node) return null;
+  }
+  return node;
+}
+
+function ConfigDiffModal({ diff, validation, applying, onApply, onClose }) {
+  const lines = (diff.diff || '').split('\n').filter(l => l !== '');
+  const lineClass = (l) => {
+    if (l.startsWith('+++') || l.startsWith('---')) return 'config-diff-file';
+    if (l.startsWith('@@')) return 'config-diff-hunk';
+    if (l.startsWith('+')) return 'config-diff-add';
+    if (l.startsWith('-')) return 'config-diff-del';
+    return '';
+  };
+  return html`
+    <div class="modal-overlay" onClick=${onClose}>
+      <div class="modal config-diff-modal" onClick=${(e) => e.stopPropagation()}>
+        <div class="modal-header">
+          <div class="modal-title">Review changes</div>
+          <div class="modal-subtitle">forge.yaml \u2014 applying writes the file; a running agent reloads it via its file watcher.</div>
+        </div>
+        <pre class="config-diff">${lines.map(l => html`<div class=${lineClass(l)}>${l}</div>`)}</pre>
+        ${((validation.errors || []).length > 0 || (validation.warnings || []).length > 0) && html`
+          <div class="config-validation">
+            ${(validation.errors || []).map(e => html`<div class="validation-error">\u2717 ${e}</div>`)}
+            ${(validation.warnings || []).map(w => html`<div class="validation-warning">\u26A0 ${w}</div>`)}
+          </div>
+        `}
+        <div class="modal-actions">
+          <button class="btn btn-ghost" onClick=${onClose}>Cancel</button>
+          <button class="btn btn-primary" onClick=${onApply} disabled=${!validation.valid || applying}>
+            ${applying ? html`<span class="spinner" />` : 'Apply'}
+          </button>
+        </div>
+      </div>
+    </div>
+  `;
+}
+
 function ConfigPage({ agentId }) {
   const [content, setContent] = useState(null);
   const [originalContent, setOriginalContent] = useState(null);
   const [monacoLoaded, setMonacoLoaded] = useState(false);
   const [validation, setValidation] = useState(null);
   const [saving, setSaving] = useState(false);
+  const [schema, setSchema] = useState(null);
+  const [fieldDoc, setFieldDoc] = useState(null); // { path, node }
+  const [pendingDiff, setPendingDiff] = useState(null);
+  const [applyStatus, setApplyStatus] = useState(null);
   const editorRef = useRef(null);
   const containerRef = useRef(null);
+  const schemaRef = useRef(null);
 
   // Load config
   useEffect(() => {
@@ -2151,6 +2382,14 @@ function ConfigPage({ agentId }) {
     }).catch(err => setValidation({ errors: [err.message] }));
   }, [agentId]);
 
+  // Load schema (inline docs)
+  useEffect(() => {
+    fetchConfigSchema().then(s => {
+      schemaRef.current = s;
+      setSchema(s);
+    }).catch(() => { /* docs are best-effort */ });
+  }, []);
+
   // Load Monaco
   useEffect(() => {
     loadMonaco().then(m => {
@@ -2175,31 +2414,69 @@ function ConfigPage({ agentId }) {
     editor.onDidChangeModelContent(() => {
       setContent(editor.getValue());
     });
-    // Cmd/Ctrl+S to save
+    // Track the field under the cursor for the docs panel.
+    editor.onDidChangeCursorPosition((e) => {
+      const s = schemaRef.current;
+      if (!s) return;
+      const path = yamlKeyPathAt(editor.getModel().getLinesContent(), e.position.lineNumber - 1);
+      const node = path.length ? schemaNodeFor(s, path) : null;
+      setFieldDoc(node ? { path, node } : null);
+    });
+    // Hover docs from the schema descriptions.
+    const hover = window.monaco.languages.registerHoverProvider('yaml', {
+      provideHover: (model, position) => {
+        const s = schemaRef.current;
+        if (!s || model !== editor.getModel()) return null;
+        const path = yamlKeyPathAt(model.getLinesContent(), position.lineNumber - 1);
+        const node = path.length ? schemaNodeFor(s, path) : null;
+        if (!node || !node.description) return null;
+        const contents = [{ value: `**${path.join('.')}**` }, { value: node.description }];
+        if (node.enum) contents.push({ value: 'Values: ' + node.enum.filter(Boolean).map(v => '`' + v + '`').join(', ') });
+        return { contents };
+      },
+    });
+    // Cmd/Ctrl+S to review and apply
     editor.addCommand(window.monaco.KeyMod.CtrlCmd | window.monaco.KeyCode.KeyS, () => {
-      handleSave();
+      handleReview();
     });
     editorRef.current = editor;
-    return () => { editor.dispose(); editorRef.current = null; };
+    return () => { hover.dispose(); editor.dispose(); editorRef.current = null; };
   }, [monacoLoaded, content === null]);
 
   const isDirty = content !== null && content !== originalContent;
 
-  const handleSave = useCallback(async () => {
+  const handleReview = useCallback(async () => {
     if (!content) return;
+    setApplyStatus(null);
+    try {
+      const result = await diffConfig(agentId, content);
+      setValidation(result);
+      if (result.changed) setPendingDiff(result);
+    } catch (err) {
+      setValidation({ errors: [err.message] });
+    }
+  }, [agentId, content]);
+
+  const handleApply = useCallback(async () => {
+    if (!pendingDiff) return;
     setSaving(true);
     try {
-      const result = await saveConfig(agentId, content);
+      const result = await saveConfig(agentId, content, pendingDiff.base_sha256);
       setValidation(result);
-      if (result.valid) {
+      if (result.applied) {
         setOriginalContent(content);
+        setApplyStatus(result.reload_pending
+          ? 'Applied \u2014 the running agent will reload forge.yaml within a few seconds.'
+          : 'Applied \u2014 changes take effect the next time the agent starts.');
       }
+      setPendingDiff(null);
     } catch (err) {
       setValidation({ errors: [err.message] });
+      setPendingDiff(null);
     } finally {
       setSaving(false);
     }
-  }, [agentId, content]);
+  }, [agentId, content, pendingDiff]);
 
   const handleValidate = useCallback(async () => {
     if (!content) return;
@@ -2234,6 +2511,40 @@ function ConfigPage({ agentId }) {
     return html`<div ref=${containerRef} class="config-editor" />`;
   };
 
+  // Docs panel: the field under the cursor, or the top-level field index.
+  const renderDocs = () => {
+    if (!schema) return null;
+    if (fieldDoc) {
+      const { path, node } = fieldDoc;
+      const children = node.properties || (node.items && node.items.properties);
+      return html`
+        <aside class="config-docs">
+          <div class="config-docs-title">${path.join('.')}</div>
+          ${node.type && html`<div class="config-docs-type">${node.type}</div>`}
+          <div class="config-docs-desc">${node.description || 'No description.'}</div>
+          ${node.enum && html`<div class="config-docs-desc">Values: ${node.enum.filter(Boolean).join(', ')}</div>`}
+          ${children && html`
+            <div class="config-docs-fields">
+              ${Object.entries(children).map(([k, v]) => html`
+                <div class="config-docs-field"><code>${k}</code> ${v.description || ''}</div>
+              `)}
+            </div>
+          `}
+        </aside>
+      `;
+    }
+    return html`
+      <aside class="config-docs">
+        <div class="config-docs-title">forge.yaml fields</div>
+        <div class="config-docs-fields">
+          ${Object.entries(schema.properties || {}).map(([k, v]) => html`
+            <div class="config-docs-field"><code>${k}</code> ${v.description || ''}</div>
+          `)}
+        </div>
+      </aside>
+    `;
+  };
+
   return html`
     <main class="main config-layout">
       <div class="config-header">
@@ -2244,20 +2555,32 @@ function ConfigPage({ agentId }) {
         </div>
         <div class="config-actions">
           <button class="btn btn-ghost btn-sm" onClick=${handleValidate}>Validate</button>
-          <button class="btn btn-primary btn-sm" onClick=${handleSave} disabled=${!isDirty || saving}>
-            ${saving ? html`<span class="spinner" />` : 'Save'}
+          <button class="btn btn-primary btn-sm" onClick=${handleReview} disabled=${!isDirty || saving}>
+            Review & Apply
           </button>
           <button class="btn btn-ghost btn-sm" onClick=${handleRestart}>Restart Agent</button>
         </div>
       </div>
-      ${renderEditor()}
-      ${validation && html`
+      <div class="config-body">
+        ${renderEditor()}
+        ${renderDocs()}
+      </div>
+      ${(validation || applyStatus) && html`
         <div class="config-validation">
-          ${(validation.errors || []).map(e => html`<div class="validation-error">\u2717 ${e}</div>`)}
-          ${(validation.warnings || []).map(w => html`<div class="validation-warning">\u26A0 ${w}</div>`)}
-          ${validation.valid && html`<div class="validation-ok">\u2713 Configuration is valid</div>`}
+          ${applyStatus && html`<div class="validation-ok">\u2713 ${applyStatus}</div>`}
+          ${validation && (validation.errors || []).map(e => html`<div class="validation-error">\u2717 ${e}</div>`)}
+          ${validation && (validation.warnings || []).map(w => html`<div class="validation-warning">\u26A0 ${w}</div>`)}
+          ${validation && validation.valid && !applyStatus && html`<div class="validation-ok">\u2713 Configuration is valid</div>`}
         </div>
       `}
+      ${pendingDiff && html`
+        <${ConfigDiffModal}
+          diff=${pendingDiff}
+          validation=${pendingDiff}
+          applying=${saving}
+          onApply=${handleApply}
+          onClose=${() => setPendingDiff(null)} />
+      `}
     </main>
   `;
 }
@@ -2463,6 +2786,13 @@ function SkillBuilderSettingsModal({ initial, onClose, onSaved }) {
             <option value="openai">openai</option>
             <option value="anthropic">anthropic</option>
             <option value="gemini">gemini</option>
+            <option value="mistral">mistral</option>
+            <option value="cohere">cohere</option>
+            <option value="groq">groq</option>
+            <option value="together">together</option>
+            <option value="openrouter">openrouter</option>
+            <option value="xai">xai</option>
+            <option value="deepseek">deepseek</option>
             <option value="ollama">ollama</option>
           </select>
 
@@ -3,8 +3,9 @@ package static
 import "embed"
 
 // FS exposes the agent dashboard's bundled static assets (index.html,
-// app.js, style.css, and the Monaco editor under monaco/) as an
-// embed.FS so they ship inside the forge binary.
+// app.js, style.css, the Monaco editor under monaco/, and the
+// embeddable webchat widget.js) as an embed.FS so they ship inside the
+// forge binary.
 //
 // Files are listed explicitly here rather than embedding the whole
 // directory so that this file itself (embed.go) is NOT served as an
@@ -16,5 +17,5 @@ import "embed"
 // to be called "dist/" but that naming was misleading (review #8); it
 // signaled "build artifact" when in fact the directory is the source.
 //
-//go:embed app.js style.css index.html monaco
+//go:embed app.js style.css index.html monaco widget.js
 var FS embed.FS
@@ -0,0 +1,148 @@
+// Forge webchat widget. Embed a running agent in any web page:
+//
+//   <script src="http://localhost:4200/widget.js"
+//           data-token="fwt_..." data-title="Support" async></script>
+//
+// The token (created in the dashboard API, POST /api/agents/{id}/widget-tokens)
+// selects the agent and the page origins allowed to use it. The widget
+// renders in a shadow root so the host page's styles don't leak in, and
+// keeps its session in sessionStorage so a reload continues the chat.
+//
+// Hand-edited, no build step — like app.js.
+(function () {
+  'use strict';
+
+  const script = document.currentScript;
+  if (!script || !script.dataset.token) {
+    console.error('forge widget: data-token is required');
+    return;
+  }
+  const token = script.dataset.token;
+  const title = script.dataset.title || 'Chat';
+  const position = script.dataset.position === 'left' ? 'left' : 'right';
+  const base = new URL(script.src).origin;
+  const storageKey = 'forge-widget:' + token.slice(0, 12);
+
+  const host = document.createElement('div');
+  const root = host.attachShadow({ mode: 'open' });
+  root.innerHTML = `
+<style>
+  :host { all: initial; }
+  .fw { position: fixed; bottom: 20px; ${position}: 20px; z-index: 2147483000;
+        font: 14px/1.4 system-ui, -apple-system, sans-serif; color: #1f2328; }
+  .fw-toggle { width: 52px; height: 52px; border-radius: 50%; border: 0; cursor: pointer;
+               background: #0969da; color: #fff; font-size: 22px; box-shadow: 0 4px 12px rgba(0,0,0,.2); }
+  .fw-panel { display: none; flex-direction: column; width: 340px; height: 460px; margin-bottom: 12px;
+              background: #fff; border: 1px solid #d0d7de; border-radius: 12px; overflow: hidden;
+              box-shadow: 0 8px 24px rgba(0,0,0,.18); }
+  .fw.open .fw-panel { display: flex; }
+  .fw-head { padding: 10px 14px; background: #0969da; color: #fff; font-weight: 600; }
+  .fw-log { flex: 1; overflow-y: auto; padding: 12px; display: flex; flex-direction: column; gap: 8px; }
+  .fw-msg { max-width: 85%; padding: 8px 10px; border-radius: 10px; white-space: pre-wrap; word-wrap: break-word; }
+  .fw-user { align-self: flex-end; background: #0969da; color: #fff; }
+  .fw-agent { align-self: flex-start; background: #f6f8fa; border: 1px solid #d0d7de; }
+  .fw-error { align-self: center; color: #cf222e; font-size: 12px; }
+  .fw-form { display: flex; border-top: 1px solid #d0d7de; }
+  .fw-input { flex: 1; border: 0; padding: 10px 12px; font: inherit; outline: none; }
+  .fw-send { border: 0; background: none; color: #0969da; font-weight: 600; padding: 0 14px; cursor: pointer; }
+  .fw-send:disabled { color: #8c959f; cursor: default; }
+</style>
+<div class="fw">
+  <div class="fw-panel" role="dialog">
+    <div class="fw-head"></div>
+    <div class="fw-log" aria-live="polite"></div>
+    <form class="fw-form">
+      <input class="fw-input" placeholder="Type a message..." autocomplete="off">
+      <button class="fw-send" type="submit">Send</button>
+    </form>
+  </div>
+  <button class="fw-toggle" aria-label="Open chat">💬</button>
+</div>`;
+
+  const wrap = root.querySelector('.fw');
+  const log = root.querySelector('.fw-log');
+  const form = root.querySelector('.fw-form');
+  const input = root.querySelector('.fw-input');
+  const send = root.querySelector('.fw-send');
+  root.querySelector('.fw-head').textContent = title;
+  root.querySelector('.fw-toggle').addEventListener('click', () => {
+    wrap.classList.toggle('open');
+    if (wrap.classList.contains('open')) input.focus();
+  });
+
+  function append(role, text) {
+    const el = document.createElement('div');
+    el.className = 'fw-msg fw-' + role;
+    el.textContent = text;
+    log.appendChild(el);
+    log.scrollTop = log.scrollHeight;
+    return el;
+  }
+
+  // textOf returns the text parts of a task's status message.
+  function textOf(task) {
+    const status = (task && task.status) || task || {};
+    const parts = (status.message && status.message.parts) || [];
+    return parts.filter(p => p.kind === 'text' && p.text).map(p => p.text).join('\n');
+  }
+
+  async function chat(text) {
+    const res = await fetch(base + '/widget/chat', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json', 'Authorization': 'Bearer ' + token },
+      body: JSON.stringify({ message: text, session_id: sessionStorage.getItem(storageKey) || undefined }),
+    });
+    if (!res.ok) {
+      const body = await res.json().catch(() => ({}));
+      throw new Error(body.error || 'HTTP ' + res.status);
+    }
+    const reply = append('agent', '…');
+    const reader = res.body.getReader();
+    const decoder = new TextDecoder();
+    let buffer = '';
+    for (;;) {
+      const { done, value } = await reader.read();
+      if (done) break;
+      buffer += decoder.decode(value, { stream: true });
+      const frames = buffer.split('\n\n');
+      buffer = frames.pop();
+      for (const frame of frames) {
+        let type = '', data = '';
+        for (const line of frame.split('\n')) {
+          if (line.startsWith('event:')) type = line.slice(6).trim();
+          else if (line.startsWith('data:')) data = line.slice(5).trim();
+        }
+        if (!type || !data) continue;
+        let parsed;
+        try { parsed = JSON.parse(data); } catch { continue; }
+        if (type === 'status' || type === 'result') {
+          const t = textOf(parsed);
+          if (t) reply.textContent = t;
+        } else if (type === 'done' && parsed.session_id) {
+          sessionStorage.setItem(storageKey, parsed.session_id);
+        }
+        log.scrollTop = log.scrollHeight;
+      }
+    }
+    if (reply.textContent === '…') reply.textContent = '(no response)';
+  }
+
+  form.addEventListener('submit', async (e) => {
+    e.preventDefault();
+    const text = input.value.trim();
+    if (!text || send.disabled) return;
+    input.value = '';
+    append('user', text);
+    send.disabled = true;
+    try {
+      await chat(text);
+    } catch (err) {
+      append('error', 'Could not reach the agent: ' + err.message);
+    } finally {
+      send.disabled = false;
+      input.focus();
+    }
+  });
+
+  document.body.appendChild(host);
+})();
@@ -279,8 +279,14 @@ type CreateAgentResponse struct {
 }
 
 // ConfigUpdateRequest is the PUT body for saving forge.yaml.
+//
+// BaseSHA256 is the hash of the on-disk content the editor's diff preview
+// was computed against (ConfigDiffResponse.BaseSHA256). When set, the save
+// is rejected with 409 if forge.yaml changed underneath the editor, so an
+// apply never silently clobbers a concurrent edit.
 type ConfigUpdateRequest struct {
-	Content string `json:"content"`
+	Content    string `json:"content"`
+	BaseSHA256 string `json:"base_sha256,omitempty"`
 }
 
 // ConfigValidateResponse returned from validate/save endpoints.
@@ -290,6 +296,23 @@ type ConfigValidateResponse struct {
 	Warnings []string `json:"warnings,omitempty"`
 }
 
+// ConfigDiffResponse is the diff preview shown before applying an edit.
+type ConfigDiffResponse struct {
+	ConfigValidateResponse
+	Diff       string `json:"diff"`
+	Changed    bool   `json:"changed"`
+	BaseSHA256 string `json:"base_sha256"`
+}
+
+// ConfigApplyResponse is returned from a successful save. ReloadPending
+// is true when the agent is running: its file watcher picks up the new
+// forge.yaml on the next poll and reloads without a restart.
+type ConfigApplyResponse struct {
+	ConfigValidateResponse
+	Applied       bool `json:"applied"`
+	ReloadPending bool `json:"reload_pending"`
+}
+
 // SkillBrowserEntry describes a registry skill for the API.
 type SkillBrowserEntry struct {
 	Name          string   `json:"name"`
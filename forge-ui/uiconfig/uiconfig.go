@@ -314,6 +314,20 @@ func defaultAPIKeyEnv(provider string) string {
 		return "ANTHROPIC_API_KEY"
 	case "gemini":
 		return "GEMINI_API_KEY"
+	case "mistral":
+		return "MISTRAL_API_KEY"
+	case "cohere":
+		return "COHERE_API_KEY"
+	case "groq":
+		return "GROQ_API_KEY"
+	case "together":
+		return "TOGETHER_API_KEY"
+	case "openrouter":
+		return "OPENROUTER_API_KEY"
+	case "xai":
+		return "XAI_API_KEY"
+	case "deepseek":
+		return "DEEPSEEK_API_KEY"
 	}
 	return ""
 }
@@ -326,11 +340,11 @@ func ValidateSkillBuilderConfig(cfg SkillBuilderConfig) error {
 
 func validateSkillBuilderConfig(cfg SkillBuilderConfig) error {
 	switch cfg.Provider {
-	case "openai", "anthropic", "gemini", "ollama":
+	case "openai", "anthropic", "gemini", "mistral", "cohere", "groq", "together", "openrouter", "xai", "deepseek", "ollama":
 	case "":
 		return fmt.Errorf("provider is required")
 	default:
-		return fmt.Errorf("unknown provider %q (must be openai, anthropic, gemini, or ollama)", cfg.Provider)
+		return fmt.Errorf("unknown provider %q (must be openai, anthropic, gemini, mistral, cohere, groq, together, openrouter, xai, deepseek, or ollama)", cfg.Provider)
 	}
 	if cfg.Model == "" {
 		return fmt.Errorf("model is required")
@@ -0,0 +1,325 @@
+package forgeui
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-ui/static"
+	"github.com/initializ/forge/forge-ui/uiconfig"
+)
+
+// Webchat widget: GET /widget.js is a self-contained chat bubble any
+// web page can embed, and POST /widget/chat is its chat API, proxied to
+// one agent. Each widget token names the agent and the page origins
+// allowed to use it; the chat API answers CORS only for those origins,
+// unlike the dashboard API's wildcard.
+
+// widgetTokensFile holds the widget tokens, under the workspace's
+// .forge directory next to ui.yaml. Only token hashes are stored.
+const widgetTokensFile = "widget-tokens.json"
+
+// widgetTokenPrefix marks widget tokens so they are recognisable in
+// page source and logs.
+const widgetTokenPrefix = "fwt_"
+
+// maxWidgetChatBytes caps a widget chat request body.
+const maxWidgetChatBytes = 64 << 10
+
+// WidgetToken is a chat API token for the webchat widget. Hash is the
+// SHA-256 of the secret; the secret itself is returned once, at
+// creation.
+type WidgetToken struct {
+	ID        string    `json:"id"`
+	AgentID   string    `json:"agent_id"`
+	Origins   []string  `json:"origins"`
+	Hash      string    `json:"hash,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWidgetTokenRequest is the POST body for creating a widget token.
+type CreateWidgetTokenRequest struct {
+	Origins []string `json:"origins"`
+}
+
+func (s *UIServer) widgetTokensPath() string {
+	return filepath.Join(s.cfg.WorkDir, uiconfig.WorkspaceConfigDir, widgetTokensFile)
+}
+
+// loadWidgetTokens reads the token file; a missing file is no tokens.
+func (s *UIServer) loadWidgetTokens() ([]WidgetToken, error) {
+	raw, err := os.ReadFile(s.widgetTokensPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading widget tokens: %w", err)
+	}
+	var tokens []WidgetToken
+	if err := json.Unmarshal(raw, &tokens); err != nil {
+		return nil, fmt.Errorf("parsing widget tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// saveWidgetTokens writes the token file atomically, readable only by
+// the owning user.
+func (s *UIServer) saveWidgetTokens(tokens []WidgetToken) error {
+	path := s.widgetTokensPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	raw, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("writing widget tokens: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing widget tokens: %w", err)
+	}
+	return nil
+}
+
+func hashWidgetSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookupWidgetToken returns the token whose secret is presented, or nil.
+func (s *UIServer) lookupWidgetToken(secret string) *WidgetToken {
+	if !strings.HasPrefix(secret, widgetTokenPrefix) {
+		return nil
+	}
+	tokens, err := s.loadWidgetTokens()
+	if err != nil {
+		return nil
+	}
+	hash := hashWidgetSecret(secret)
+	for i := range tokens {
+		if subtle.ConstantTimeCompare([]byte(tokens[i].Hash), []byte(hash)) == 1 {
+			return &tokens[i]
+		}
+	}
+	return nil
+}
+
+// normalizeOrigin validates a page origin ("https://intranet.example.com",
+// with an optional port) and returns it in the form browsers send.
+func normalizeOrigin(origin string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", fmt.Errorf("origin %q must be a scheme and host, like https://intranet.example.com", origin)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
+
+// handleListWidgetTokens lists an agent's widget tokens, without
+// secrets.
+func (s *UIServer) handleListWidgetTokens(w http.ResponseWriter, r *http.Request) {
+	agentID := r.PathValue("id")
+	s.widgetMu.Lock()
+	tokens, err := s.loadWidgetTokens()
+	s.widgetMu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := []WidgetToken{}
+	for _, t := range tokens {
+		if t.AgentID == agentID {
+			t.Hash = ""
+			out = append(out, t)
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleCreateWidgetToken creates a widget token for an agent. The
+// response carries the secret, which is not stored and cannot be shown
+// again, and an embed snippet.
+func (s *UIServer) handleCreateWidgetToken(w http.ResponseWriter, r *http.Request) {
+	agentID := r.PathValue("id")
+	agents, err := s.scanner.Scan()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, ok := agents[agentID]; !ok {
+		writeError(w, http.StatusNotFound, "agent not found")
+		return
+	}
+
+	var req CreateWidgetTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Origins) == 0 {
+		writeError(w, http.StatusBadRequest, "at least one origin is required")
+		return
+	}
+	origins := make([]string, 0, len(req.Origins))
+	for _, o := range req.Origins {
+		n, err := normalizeOrigin(o)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !slices.Contains(origins, n) {
+			origins = append(origins, n)
+		}
+	}
+
+	id, key := make([]byte, 4), make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		writeError(w, http.StatusInternalServerError, "generating token")
+		return
+	}
+	if _, err := rand.Read(key); err != nil {
+		writeError(w, http.StatusInternalServerError, "generating token")
+		return
+	}
+	secret := widgetTokenPrefix + hex.EncodeToString(key)
+	tok := WidgetToken{
+		ID:        hex.EncodeToString(id),
+		AgentID:   agentID,
+		Origins:   origins,
+		Hash:      hashWidgetSecret(secret),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.widgetMu.Lock()
+	tokens, err := s.loadWidgetTokens()
+	if err == nil {
+		err = s.saveWidgetTokens(append(tokens, tok))
+	}
+	s.widgetMu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tok.Hash = ""
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"token":   tok,
+		"secret":  secret,
+		"snippet": fmt.Sprintf(`<script src="http://127.0.0.1:%d/widget.js" data-token="%s" async></script>`, s.cfg.Port, secret),
+	})
+}
+
+// handleDeleteWidgetToken revokes a widget token.
+func (s *UIServer) handleDeleteWidgetToken(w http.ResponseWriter, r *http.Request) {
+	agentID, tokenID := r.PathValue("id"), r.PathValue("tid")
+	s.widgetMu.Lock()
+	defer s.widgetMu.Unlock()
+	tokens, err := s.loadWidgetTokens()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	kept := slices.DeleteFunc(slices.Clone(tokens), func(t WidgetToken) bool {
+		return t.ID == tokenID && t.AgentID == agentID
+	})
+	if len(kept) == len(tokens) {
+		writeError(w, http.StatusNotFound, "widget token not found")
+		return
+	}
+	if err := s.saveWidgetTokens(kept); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// handleWidgetScript serves the widget bundle.
+func (s *UIServer) handleWidgetScript(w http.ResponseWriter, _ *http.Request) {
+	js, err := fs.ReadFile(static.FS, "widget.js")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "widget bundle missing")
+		return
+	}
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_, _ = w.Write(js)
+}
+
+// allowWidgetOrigin sets the CORS headers admitting origin.
+func allowWidgetOrigin(w http.ResponseWriter, origin string) {
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Vary", "Origin")
+}
+
+// handleWidgetPreflight answers CORS preflights for the widget chat
+// API. A preflight carries no token, so it admits any origin some
+// widget token allows; the request itself is checked against its own
+// token's origins.
+func (s *UIServer) handleWidgetPreflight(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	tokens, _ := s.loadWidgetTokens()
+	for _, t := range tokens {
+		if slices.Contains(t.Origins, strings.ToLower(origin)) {
+			allowWidgetOrigin(w, origin)
+			w.Header().Set("Access-Control-Allow-Methods", "POST")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusForbidden)
+}
+
+// handleWidgetChat is the widget's chat API: the bearer widget token
+// selects the agent, the page's Origin must be one the token allows,
+// and sessions are confined to ones the token started.
+func (s *UIServer) handleWidgetChat(w http.ResponseWriter, r *http.Request) {
+	secret, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	tok := s.lookupWidgetToken(secret)
+	if tok == nil {
+		writeError(w, http.StatusUnauthorized, "invalid widget token")
+		return
+	}
+	origin := r.Header.Get("Origin")
+	if !slices.Contains(tok.Origins, strings.ToLower(origin)) {
+		writeError(w, http.StatusForbidden, "origin not allowed for this widget token")
+		return
+	}
+	allowWidgetOrigin(w, origin)
+
+	var req ChatRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWidgetChatBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Message == "" {
+		writeError(w, http.StatusBadRequest, "message is required")
+		return
+	}
+	// A page may only continue a session its token started, never the
+	// dashboard's or another widget's.
+	prefix := "widget-" + tok.ID + "-"
+	sessionID := req.SessionID
+	if !strings.HasPrefix(sessionID, prefix) {
+		sessionID = fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
+	}
+	// The widget is public, so its tasks get the channel origin.
+	s.proxyChat(w, r, tok.AgentID, sessionID, req.Message, tools.OriginChannel)
+}
@@ -0,0 +1,143 @@
+package forgeui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// createWidgetToken creates a token for agentID through the handler and
+// returns its ID and secret.
+func createWidgetToken(t *testing.T, s *UIServer, agentID string, origins ...string) (string, string) {
+	t.Helper()
+	body, _ := json.Marshal(CreateWidgetTokenRequest{Origins: origins})
+	req := httptest.NewRequest(http.MethodPost, "/api/agents/"+agentID+"/widget-tokens", strings.NewReader(string(body)))
+	req.SetPathValue("id", agentID)
+	rec := httptest.NewRecorder()
+	s.handleCreateWidgetToken(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Token   WidgetToken `json:"token"`
+		Secret  string      `json:"secret"`
+		Snippet string      `json:"snippet"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Token.Hash != "" || !strings.Contains(resp.Snippet, resp.Secret) {
+		t.Errorf("create response = %s", rec.Body.String())
+	}
+	return resp.Token.ID, resp.Secret
+}
+
+func TestWidgetTokens_CreateListDelete(t *testing.T) {
+	s, dir := newTestServer(t)
+	createTestAgent(t, dir, "support-bot")
+
+	id, secret := createWidgetToken(t, s, "support-bot", "https://Intranet.example.com", "https://intranet.example.com/")
+	if tok := s.lookupWidgetToken(secret); tok == nil || tok.ID != id || len(tok.Origins) != 1 || tok.Origins[0] != "https://intranet.example.com" {
+		t.Fatalf("lookup = %+v", tok)
+	}
+	if s.lookupWidgetToken(secret+"x") != nil {
+		t.Error("wrong secret accepted")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/agents/support-bot/widget-tokens", nil)
+	req.SetPathValue("id", "support-bot")
+	rec := httptest.NewRecorder()
+	s.handleListWidgetTokens(rec, req)
+	if strings.Contains(rec.Body.String(), "hash") || !strings.Contains(rec.Body.String(), id) {
+		t.Errorf("list = %s", rec.Body.String())
+	}
+
+	for _, want := range []int{http.StatusOK, http.StatusNotFound} {
+		req = httptest.NewRequest(http.MethodDelete, "/api/agents/support-bot/widget-tokens/"+id, nil)
+		req.SetPathValue("id", "support-bot")
+		req.SetPathValue("tid", id)
+		rec = httptest.NewRecorder()
+		s.handleDeleteWidgetToken(rec, req)
+		if rec.Code != want {
+			t.Errorf("delete: %d, want %d", rec.Code, want)
+		}
+	}
+	if s.lookupWidgetToken(secret) != nil {
+		t.Error("revoked token still accepted")
+	}
+}
+
+func TestWidgetTokens_RejectsBadOrigins(t *testing.T) {
+	s, dir := newTestServer(t)
+	createTestAgent(t, dir, "support-bot")
+	for _, body := range []string{`{"origins":[]}`, `{"origins":["*"]}`, `{"origins":["https://x.example.com/app"]}`, `{"origins":["ftp://x.example.com"]}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/agents/support-bot/widget-tokens", strings.NewReader(body))
+		req.SetPathValue("id", "support-bot")
+		rec := httptest.NewRecorder()
+		s.handleCreateWidgetToken(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestWidgetChat_OriginScoping(t *testing.T) {
+	s, dir := newTestServer(t)
+	createTestAgent(t, dir, "support-bot")
+	_, secret := createWidgetToken(t, s, "support-bot", "https://intranet.example.com")
+	handler := corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			s.handleWidgetPreflight(w, r)
+			return
+		}
+		s.handleWidgetChat(w, r)
+	}))
+
+	chat := func(origin, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/widget/chat", strings.NewReader(`{"message":"hi"}`))
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := chat("https://intranet.example.com", "fwt_nope"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad token: %d", rec.Code)
+	}
+	if rec := chat("https://evil.example.com", secret); rec.Code != http.StatusForbidden || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("foreign origin: %d, ACAO %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	// The allowed origin gets through to the agent proxy (not running
+	// here) with CORS scoped to it.
+	rec := chat("https://intranet.example.com", secret)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "not running") {
+		t.Errorf("allowed origin: %d %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://intranet.example.com" {
+		t.Errorf("ACAO = %q", got)
+	}
+
+	for origin, want := range map[string]int{"https://intranet.example.com": http.StatusNoContent, "https://evil.example.com": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodOptions, "/widget/chat", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("preflight from %s: %d, want %d", origin, rec.Code, want)
+		}
+	}
+}
+
+func TestWidgetScript(t *testing.T) {
+	s, _ := newTestServer(t)
+	rec := httptest.NewRecorder()
+	s.handleWidgetScript(rec, httptest.NewRequest(http.MethodGet, "/widget.js", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/javascript") {
+		t.Fatalf("widget.js: %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "/widget/chat") {
+		t.Error("bundle does not call the widget chat API")
+	}
+}